@@ -0,0 +1,706 @@
+package bridge
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// CalDAV defaults.
+const (
+	DefaultCalDAVPast     = 30 * 24 * time.Hour
+	DefaultCalDAVFuture   = 365 * 24 * time.Hour
+	DefaultCalDAVCacheTTL = time.Minute
+
+	maxReportBody = 1 << 20
+)
+
+// CalDAV URL layout.
+const (
+	davPrincipalPath = "/dav/principal/"
+	davHomePath      = "/dav/calendars/"
+)
+
+// CalendarSource lists the calendars the bridge can expose.
+type CalendarSource interface {
+	List(ctx context.Context) ([]*calendar.Calendar, error)
+}
+
+// EventSource lists expanded events of a calendar within a time range.
+type EventSource interface {
+	List(ctx context.Context, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error)
+}
+
+// CalDAVConfig configures a CalDAV bridge.
+type CalDAVConfig struct {
+	// Username and Password are the HTTP Basic credentials clients use.
+	Username string
+	Password string
+	// CalendarIDs restricts the bridge to these calendars. Empty exposes
+	// every calendar the account can read.
+	CalendarIDs []string
+	// Past and Future bound the window of events served around now.
+	Past   time.Duration
+	Future time.Duration
+	// CacheTTL is how long fetched events are reused before refetching.
+	CacheTTL time.Duration
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+	// Logf receives diagnostic messages; nil disables logging.
+	Logf func(format string, args ...any)
+	// CacheLookup is told whether each lookup of calendars or events hit
+	// the cache. It may be nil.
+	CacheLookup func(hit bool)
+}
+
+// CalDAVServer is a read-only CalDAV server backed by Google Calendar.
+// It implements http.Handler.
+type CalDAVServer struct {
+	calendars CalendarSource
+	events    EventSource
+	cfg       CalDAVConfig
+
+	mu        sync.Mutex
+	calCache  []*calendar.Calendar
+	calFetch  time.Time
+	evCache   map[string]*eventSnapshot
+	fetchLock sync.Mutex
+}
+
+// eventSnapshot holds the events of one calendar fetched at a point in time.
+type eventSnapshot struct {
+	fetched time.Time
+	events  []*calendar.Event
+	byID    map[string]*calendar.Event
+	ctag    string
+}
+
+// NewCalDAVServer creates a CalDAV server over the given sources.
+func NewCalDAVServer(calendars CalendarSource, events EventSource, cfg CalDAVConfig) *CalDAVServer {
+	if cfg.Past <= 0 {
+		cfg.Past = DefaultCalDAVPast
+	}
+	if cfg.Future <= 0 {
+		cfg.Future = DefaultCalDAVFuture
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCalDAVCacheTTL
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &CalDAVServer{
+		calendars: calendars,
+		events:    events,
+		cfg:       cfg,
+		evCache:   make(map[string]*eventSnapshot),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *CalDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.logf("%s %s", r.Method, r.URL.Path)
+
+	if r.URL.Path == "/.well-known/caldav" {
+		http.Redirect(w, r, davPrincipalPath, http.StatusMovedPermanently)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="goog"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, HEAD, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+	case "PROPFIND":
+		s.handlePropfind(w, r)
+	case "REPORT":
+		s.handleReport(w, r)
+	case http.MethodGet, http.MethodHead:
+		s.handleGet(w, r)
+	case http.MethodPut, http.MethodDelete, "MKCALENDAR", "MKCOL", "PROPPATCH", "MOVE", "COPY", http.MethodPost:
+		http.Error(w, "the bridge is read-only", http.StatusForbidden)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized checks HTTP Basic credentials.
+func (s *CalDAVServer) authorized(r *http.Request) bool {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	userOK := strings.EqualFold(user, s.cfg.Username)
+	passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(s.cfg.Password)) == 1
+	return userOK && passOK
+}
+
+// davTarget identifies the resource a request path refers to.
+type davTarget struct {
+	kind       string // "principal", "home", "calendar" or "event"
+	calendarID string
+	eventID    string
+}
+
+// resolvePath maps a request path onto a resource.
+func resolvePath(path string) (davTarget, bool) {
+	switch path {
+	case "/", "/dav", "/dav/", davPrincipalPath, strings.TrimSuffix(davPrincipalPath, "/"):
+		return davTarget{kind: "principal"}, true
+	case davHomePath, strings.TrimSuffix(davHomePath, "/"):
+		return davTarget{kind: "home"}, true
+	}
+	if !strings.HasPrefix(path, davHomePath) {
+		return davTarget{}, false
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(path, davHomePath), "/"), "/")
+	calID, err := url.PathUnescape(parts[0])
+	if err != nil || calID == "" {
+		return davTarget{}, false
+	}
+	switch len(parts) {
+	case 1:
+		return davTarget{kind: "calendar", calendarID: calID}, true
+	case 2:
+		name, ok := strings.CutSuffix(parts[1], ".ics")
+		if !ok {
+			return davTarget{}, false
+		}
+		eventID, err := url.PathUnescape(name)
+		if err != nil || eventID == "" {
+			return davTarget{}, false
+		}
+		return davTarget{kind: "event", calendarID: calID, eventID: eventID}, true
+	}
+	return davTarget{}, false
+}
+
+// calendarHref returns the collection URL of a calendar.
+func calendarHref(calendarID string) string {
+	return davHomePath + url.PathEscape(calendarID) + "/"
+}
+
+// eventHref returns the resource URL of an event.
+func eventHref(calendarID, eventID string) string {
+	return calendarHref(calendarID) + url.PathEscape(eventID) + ".ics"
+}
+
+// eventETag returns a strong entity tag for an event.
+func eventETag(event *calendar.Event) string {
+	sum := sha256.Sum256([]byte(event.ID + "\x00" + event.Updated.UTC().Format(time.RFC3339Nano)))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// handlePropfind serves PROPFIND for all resource kinds.
+func (s *CalDAVServer) handlePropfind(w http.ResponseWriter, r *http.Request) {
+	// The request body only selects properties; every known property is
+	// returned, which clients accept.
+	_, _ = io.Copy(io.Discard, io.LimitReader(r.Body, maxReportBody))
+
+	target, ok := resolvePath(r.URL.EscapedPath())
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	depth := r.Header.Get("Depth")
+	children := depth != "0"
+
+	ms := newMultistatus()
+	ctx := r.Context()
+
+	switch target.kind {
+	case "principal":
+		ms.response(r.URL.Path, s.principalProps())
+	case "home":
+		ms.response(davHomePath, homeProps())
+		if children {
+			cals, err := s.listCalendars(ctx)
+			if err != nil {
+				s.fail(w, err)
+				return
+			}
+			for _, cal := range cals {
+				snap, err := s.snapshot(ctx, cal.ID)
+				if err != nil {
+					s.fail(w, err)
+					return
+				}
+				ms.response(calendarHref(cal.ID), calendarProps(cal, snap.ctag))
+			}
+		}
+	case "calendar":
+		cal, err := s.findCalendar(ctx, target.calendarID)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		if cal == nil {
+			http.NotFound(w, r)
+			return
+		}
+		snap, err := s.snapshot(ctx, cal.ID)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		ms.response(calendarHref(cal.ID), calendarProps(cal, snap.ctag))
+		if children {
+			for _, ev := range snap.events {
+				ms.response(eventHref(cal.ID, ev.ID), eventProps(ev, ""))
+			}
+		}
+	case "event":
+		ev, err := s.findEvent(ctx, target)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		if ev == nil {
+			http.NotFound(w, r)
+			return
+		}
+		ms.response(eventHref(target.calendarID, ev.ID), eventProps(ev, ""))
+	}
+
+	ms.write(w)
+}
+
+// handleReport serves calendar-query and calendar-multiget reports.
+func (s *CalDAVServer) handleReport(w http.ResponseWriter, r *http.Request) {
+	target, ok := resolvePath(r.URL.EscapedPath())
+	if !ok || target.kind != "calendar" {
+		http.Error(w, "reports are supported on calendar collections only", http.StatusForbidden)
+		return
+	}
+	report, err := parseReport(io.LimitReader(r.Body, maxReportBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	cal, err := s.findCalendar(ctx, target.calendarID)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if cal == nil {
+		http.NotFound(w, r)
+		return
+	}
+	snap, err := s.snapshot(ctx, cal.ID)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	now := s.cfg.Now()
+	ms := newMultistatus()
+	switch report.name {
+	case "calendar-query":
+		for _, ev := range snap.events {
+			if report.overlaps(ev) {
+				ms.response(eventHref(cal.ID, ev.ID), eventProps(ev, renderICalendar(ev, now)))
+			}
+		}
+	case "calendar-multiget":
+		for _, href := range report.hrefs {
+			t, ok := resolvePath(hrefPath(href))
+			var ev *calendar.Event
+			if ok && t.kind == "event" && t.calendarID == cal.ID {
+				ev = snap.byID[t.eventID]
+			}
+			if ev == nil {
+				ms.status(href, http.StatusNotFound)
+				continue
+			}
+			ms.response(eventHref(cal.ID, ev.ID), eventProps(ev, renderICalendar(ev, now)))
+		}
+	default:
+		http.Error(w, "unsupported report "+report.name, http.StatusForbidden)
+		return
+	}
+
+	ms.write(w)
+}
+
+// handleGet serves the iCalendar body of an event.
+func (s *CalDAVServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	target, ok := resolvePath(r.URL.EscapedPath())
+	if !ok || target.kind != "event" {
+		http.Error(w, "only event resources can be fetched", http.StatusNotFound)
+		return
+	}
+	ev, err := s.findEvent(r.Context(), target)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if ev == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	body := renderICalendar(ev, s.cfg.Now())
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", eventETag(ev))
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+// fail reports an upstream error to the client.
+func (s *CalDAVServer) fail(w http.ResponseWriter, err error) {
+	s.logf("upstream error: %v", err)
+	http.Error(w, "failed to load calendar data", http.StatusBadGateway)
+}
+
+func (s *CalDAVServer) logf(format string, args ...any) {
+	if s.cfg.Logf != nil {
+		s.cfg.Logf(format, args...)
+	}
+}
+
+// listCalendars returns the exposed calendars, cached for CacheTTL.
+func (s *CalDAVServer) listCalendars(ctx context.Context) ([]*calendar.Calendar, error) {
+	s.mu.Lock()
+	cals, fresh := s.calCache, s.calCache != nil && s.cfg.Now().Sub(s.calFetch) < s.cfg.CacheTTL
+	s.mu.Unlock()
+	s.cacheLookup(fresh)
+	if fresh {
+		return cals, nil
+	}
+
+	all, err := s.calendars.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(s.cfg.CalendarIDs))
+	for _, id := range s.cfg.CalendarIDs {
+		wanted[id] = true
+	}
+	cals = make([]*calendar.Calendar, 0, len(all))
+	for _, cal := range all {
+		if len(wanted) > 0 {
+			if wanted[cal.ID] || (cal.Primary && wanted["primary"]) {
+				cals = append(cals, cal)
+			}
+			continue
+		}
+		if cal.AccessRole != calendar.AccessRoleFreeBusyReader {
+			cals = append(cals, cal)
+		}
+	}
+	sort.SliceStable(cals, func(i, j int) bool { return cals[i].Primary && !cals[j].Primary })
+
+	s.mu.Lock()
+	s.calCache, s.calFetch = cals, s.cfg.Now()
+	s.mu.Unlock()
+	return cals, nil
+}
+
+// findCalendar returns the exposed calendar with the given ID, or nil.
+func (s *CalDAVServer) findCalendar(ctx context.Context, id string) (*calendar.Calendar, error) {
+	cals, err := s.listCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cal := range cals {
+		if cal.ID == id {
+			return cal, nil
+		}
+	}
+	return nil, nil
+}
+
+// findEvent returns the event a target refers to, or nil.
+func (s *CalDAVServer) findEvent(ctx context.Context, target davTarget) (*calendar.Event, error) {
+	cal, err := s.findCalendar(ctx, target.calendarID)
+	if err != nil || cal == nil {
+		return nil, err
+	}
+	snap, err := s.snapshot(ctx, cal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return snap.byID[target.eventID], nil
+}
+
+// snapshot returns the cached events of a calendar, refetching them once
+// CacheTTL has passed.
+func (s *CalDAVServer) snapshot(ctx context.Context, calendarID string) (*eventSnapshot, error) {
+	// Serialize fetches so concurrent client requests share one refresh
+	s.fetchLock.Lock()
+	defer s.fetchLock.Unlock()
+
+	now := s.cfg.Now()
+	s.mu.Lock()
+	snap := s.evCache[calendarID]
+	s.mu.Unlock()
+	fresh := snap != nil && now.Sub(snap.fetched) < s.cfg.CacheTTL
+	s.cacheLookup(fresh)
+	if fresh {
+		return snap, nil
+	}
+
+	events, err := s.events.List(ctx, calendarID, now.Add(-s.cfg.Past), now.Add(s.cfg.Future))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events of %s: %w", calendarID, err)
+	}
+
+	snap = &eventSnapshot{fetched: now, byID: make(map[string]*calendar.Event, len(events))}
+	hash := sha256.New()
+	for _, ev := range events {
+		if ev == nil || ev.ID == "" || ev.Status == calendar.StatusCancelled {
+			continue
+		}
+		snap.events = append(snap.events, ev)
+		snap.byID[ev.ID] = ev
+		fmt.Fprintf(hash, "%s\x00%s\x00", ev.ID, ev.Updated.UTC().Format(time.RFC3339Nano))
+	}
+	snap.ctag = `"` + hex.EncodeToString(hash.Sum(nil)[:8]) + `"`
+
+	s.mu.Lock()
+	s.evCache[calendarID] = snap
+	s.mu.Unlock()
+	return snap, nil
+}
+
+// cacheLookup reports a cache lookup to CacheLookup, if set.
+func (s *CalDAVServer) cacheLookup(hit bool) {
+	if s.cfg.CacheLookup != nil {
+		s.cfg.CacheLookup(hit)
+	}
+}
+
+// principalProps returns the properties of the principal resource.
+func (s *CalDAVServer) principalProps() []davProp {
+	props := []davProp{
+		{name: "D:resourcetype", inner: "<D:principal/>"},
+		{name: "D:displayname", text: s.cfg.Username},
+		{name: "D:current-user-principal", inner: hrefXML(davPrincipalPath)},
+		{name: "D:principal-URL", inner: hrefXML(davPrincipalPath)},
+		{name: "C:calendar-home-set", inner: hrefXML(davHomePath)},
+	}
+	if s.cfg.Username != "" {
+		props = append(props, davProp{name: "C:calendar-user-address-set", inner: hrefXML("mailto:" + s.cfg.Username)})
+	}
+	return props
+}
+
+// homeProps returns the properties of the calendar home collection.
+func homeProps() []davProp {
+	return []davProp{
+		{name: "D:resourcetype", inner: "<D:collection/>"},
+		{name: "D:displayname", text: "Calendars"},
+		{name: "D:current-user-principal", inner: hrefXML(davPrincipalPath)},
+	}
+}
+
+// calendarProps returns the properties of a calendar collection.
+func calendarProps(cal *calendar.Calendar, ctag string) []davProp {
+	props := []davProp{
+		{name: "D:resourcetype", inner: "<D:collection/><C:calendar/>"},
+		{name: "D:displayname", text: cal.Title},
+		{name: "D:current-user-principal", inner: hrefXML(davPrincipalPath)},
+		{name: "D:current-user-privilege-set", inner: "<D:privilege><D:read/></D:privilege>"},
+		{name: "D:supported-report-set", inner: "<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>" +
+			"<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>"},
+		{name: "C:supported-calendar-component-set", inner: `<C:comp name="VEVENT"/>`},
+		{name: "CS:getctag", text: ctag},
+	}
+	if cal.Description != "" {
+		props = append(props, davProp{name: "C:calendar-description", text: cal.Description})
+	}
+	if cal.TimeZone != "" {
+		props = append(props, davProp{name: "C:calendar-timezone", text: cal.TimeZone})
+	}
+	return props
+}
+
+// eventProps returns the properties of an event resource. calendarData is
+// included when non-empty.
+func eventProps(ev *calendar.Event, calendarData string) []davProp {
+	props := []davProp{
+		{name: "D:resourcetype"},
+		{name: "D:getetag", text: eventETag(ev)},
+		{name: "D:getcontenttype", text: "text/calendar; charset=utf-8; component=vevent"},
+	}
+	if !ev.Updated.IsZero() {
+		props = append(props, davProp{name: "D:getlastmodified", text: ev.Updated.UTC().Format(http.TimeFormat)})
+	}
+	if calendarData != "" {
+		props = append(props, davProp{name: "C:calendar-data", text: calendarData})
+	}
+	return props
+}
+
+// davProp is a single property of a multistatus response. Either text
+// (escaped) or inner (raw XML) is used as the element content.
+type davProp struct {
+	name  string
+	text  string
+	inner string
+}
+
+// multistatus builds a WebDAV multistatus document.
+type multistatus struct {
+	b strings.Builder
+}
+
+func newMultistatus() *multistatus {
+	ms := &multistatus{}
+	ms.b.WriteString(xml.Header)
+	ms.b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`)
+	return ms
+}
+
+// response adds a resource with its properties.
+func (ms *multistatus) response(href string, props []davProp) {
+	ms.b.WriteString("<D:response>")
+	ms.b.WriteString(hrefXML(href))
+	ms.b.WriteString("<D:propstat><D:prop>")
+	for _, p := range props {
+		ms.b.WriteString("<" + p.name + ">")
+		if p.inner != "" {
+			ms.b.WriteString(p.inner)
+		} else {
+			_ = xml.EscapeText(&ms.b, []byte(p.text))
+		}
+		ms.b.WriteString("</" + p.name + ">")
+	}
+	ms.b.WriteString("</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat></D:response>")
+}
+
+// status adds a resource that only carries a status code.
+func (ms *multistatus) status(href string, code int) {
+	ms.b.WriteString("<D:response>")
+	ms.b.WriteString(hrefXML(href))
+	fmt.Fprintf(&ms.b, "<D:status>HTTP/1.1 %d %s</D:status></D:response>", code, http.StatusText(code))
+}
+
+func (ms *multistatus) write(w http.ResponseWriter) {
+	ms.b.WriteString("</D:multistatus>")
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, ms.b.String())
+}
+
+// hrefXML returns an escaped D:href element.
+func hrefXML(href string) string {
+	var b strings.Builder
+	b.WriteString("<D:href>")
+	_ = xml.EscapeText(&b, []byte(href))
+	b.WriteString("</D:href>")
+	return b.String()
+}
+
+// hrefPath returns the path of an href, which may be an absolute URL.
+func hrefPath(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	return u.EscapedPath()
+}
+
+// davReport is a parsed REPORT request body.
+type davReport struct {
+	name  string
+	hrefs []string
+	start time.Time
+	end   time.Time
+}
+
+// parseReport extracts the report name, hrefs and time range from a
+// REPORT body. Filters other than time-range are ignored, so clients may
+// receive more events than they asked for.
+func parseReport(r io.Reader) (*davReport, error) {
+	report := &davReport{}
+	dec := xml.NewDecoder(r)
+	inHref := false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid report body: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if report.name == "" {
+				report.name = t.Name.Local
+			}
+			switch t.Name.Local {
+			case "href":
+				inHref = true
+			case "time-range":
+				for _, attr := range t.Attr {
+					ts, err := time.Parse(icalUTCLayout, attr.Value)
+					if err != nil {
+						return nil, fmt.Errorf("invalid time-range %s %q", attr.Name.Local, attr.Value)
+					}
+					switch attr.Name.Local {
+					case "start":
+						report.start = ts
+					case "end":
+						report.end = ts
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "href" {
+				inHref = false
+			}
+		case xml.CharData:
+			if inHref {
+				report.hrefs = append(report.hrefs, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+
+	if report.name == "" {
+		return nil, fmt.Errorf("empty report body")
+	}
+	return report, nil
+}
+
+// overlaps reports whether an event intersects the report's time range,
+// following RFC 4791 section 9.9.
+func (r *davReport) overlaps(ev *calendar.Event) bool {
+	if !r.end.IsZero() && !ev.Start.Before(r.end) {
+		return false
+	}
+	if r.start.IsZero() {
+		return true
+	}
+	if ev.End.After(ev.Start) {
+		return ev.End.After(r.start)
+	}
+	// Zero-length events match when they start inside the range
+	return !ev.Start.Before(r.start)
+}
@@ -0,0 +1,357 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// fakeCalendarSource returns a fixed calendar list.
+type fakeCalendarSource struct {
+	Calendars []*calendar.Calendar
+	Err       error
+}
+
+func (f *fakeCalendarSource) List(ctx context.Context) ([]*calendar.Calendar, error) {
+	return f.Calendars, f.Err
+}
+
+// fakeEventSource returns events by calendar ID and records calls.
+type fakeEventSource struct {
+	Events  map[string][]*calendar.Event
+	Err     error
+	Calls   int
+	TimeMin time.Time
+	TimeMax time.Time
+}
+
+func (f *fakeEventSource) List(ctx context.Context, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	f.Calls++
+	f.TimeMin, f.TimeMax = timeMin, timeMax
+	return f.Events[calendarID], f.Err
+}
+
+var caldavNow = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestCalDAV returns a server with a primary, a shared and a
+// free/busy-only calendar.
+func newTestCalDAV(t *testing.T, cfg CalDAVConfig) (*CalDAVServer, *fakeEventSource) {
+	t.Helper()
+
+	cals := &fakeCalendarSource{Calendars: []*calendar.Calendar{
+		{ID: "team@group.calendar.google.com", Title: "Team", AccessRole: calendar.AccessRoleReader},
+		{ID: "me@example.com", Title: "Me", Description: "Personal", Primary: true, AccessRole: calendar.AccessRoleOwner},
+		{ID: "busy@example.com", Title: "Busy", AccessRole: calendar.AccessRoleFreeBusyReader},
+	}}
+	events := &fakeEventSource{Events: map[string][]*calendar.Event{
+		"me@example.com": {
+			{ID: "e1", Title: "Standup", Start: caldavNow.Add(24 * time.Hour), End: caldavNow.Add(25 * time.Hour), Updated: caldavNow},
+			{ID: "e2", Title: "Offsite", Start: caldavNow.Add(30 * 24 * time.Hour), End: caldavNow.Add(31 * 24 * time.Hour)},
+			{ID: "e3", Title: "Gone", Start: caldavNow, End: caldavNow.Add(time.Hour), Status: calendar.StatusCancelled},
+		},
+	}}
+
+	cfg.Username, cfg.Password = "me@example.com", "secret"
+	cfg.Now = func() time.Time { return caldavNow }
+	return NewCalDAVServer(cals, events, cfg), events
+}
+
+// davRequest sends an authenticated request to the handler.
+func davRequest(t *testing.T, h http.Handler, method, path, depth, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetBasicAuth("me@example.com", "secret")
+	if depth != "" {
+		req.Header.Set("Depth", depth)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCalDAV_Auth(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest("PROPFIND", davPrincipalPath, nil))
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("expected 401 with challenge, got %d", rec.Code)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		req := httptest.NewRequest("PROPFIND", davPrincipalPath, nil)
+		req.SetBasicAuth("me@example.com", "nope")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("well-known redirect needs no credentials", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest("PROPFIND", "/.well-known/caldav", nil))
+		if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != davPrincipalPath {
+			t.Errorf("expected redirect to principal, got %d %q", rec.Code, rec.Header().Get("Location"))
+		}
+	})
+}
+
+func TestCalDAV_Options(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	rec := davRequest(t, srv, http.MethodOptions, "/", "", "")
+	if !strings.Contains(rec.Header().Get("DAV"), "calendar-access") {
+		t.Errorf("DAV header = %q", rec.Header().Get("DAV"))
+	}
+}
+
+func TestCalDAV_PropfindDiscovery(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	rec := davRequest(t, srv, "PROPFIND", davPrincipalPath, "0", "")
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("expected 207, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"<C:calendar-home-set><D:href>/dav/calendars/</D:href>", "mailto:me@example.com"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("principal response missing %q\n%s", want, body)
+		}
+	}
+
+	rec = davRequest(t, srv, "PROPFIND", davHomePath, "1", "")
+	body = rec.Body.String()
+	if !strings.Contains(body, "/dav/calendars/me@example.com/") || !strings.Contains(body, "/dav/calendars/team@group.calendar.google.com/") {
+		t.Errorf("home listing missing calendars\n%s", body)
+	}
+	if strings.Contains(body, "busy@example.com") {
+		t.Error("free/busy-only calendar should be hidden")
+	}
+	if strings.Index(body, "me@example.com/") > strings.Index(body, "team@group") {
+		t.Error("primary calendar should be listed first")
+	}
+	if !strings.Contains(body, "<C:calendar/>") || !strings.Contains(body, "<CS:getctag>") {
+		t.Errorf("calendar properties missing\n%s", body)
+	}
+}
+
+func TestCalDAV_PropfindCalendar(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	rec := davRequest(t, srv, "PROPFIND", "/dav/calendars/me@example.com/", "1", "")
+	body := rec.Body.String()
+	for _, want := range []string{"<D:displayname>Me</D:displayname>", "Personal", "/e1.ics", "/e2.ics", "<D:getetag>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("calendar listing missing %q\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "e3.ics") {
+		t.Error("cancelled event should be hidden")
+	}
+
+	rec = davRequest(t, srv, "PROPFIND", "/dav/calendars/me@example.com/", "0", "")
+	if strings.Contains(rec.Body.String(), "e1.ics") {
+		t.Error("Depth 0 should not list events")
+	}
+
+	rec = davRequest(t, srv, "PROPFIND", "/dav/calendars/unknown/", "0", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown calendar, got %d", rec.Code)
+	}
+}
+
+func TestCalDAV_CalendarFilter(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{CalendarIDs: []string{"primary"}})
+
+	body := davRequest(t, srv, "PROPFIND", davHomePath, "1", "").Body.String()
+	if !strings.Contains(body, "me@example.com/") || strings.Contains(body, "team@group") {
+		t.Errorf("expected only the primary calendar\n%s", body)
+	}
+}
+
+func TestCalDAV_ReportCalendarQuery(t *testing.T) {
+	srv, events := newTestCalDAV(t, CalDAVConfig{Past: 7 * 24 * time.Hour, Future: 60 * 24 * time.Hour})
+
+	query := `<?xml version="1.0"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT">
+    <C:time-range start="20260501T000000Z" end="20260510T000000Z"/>
+  </C:comp-filter></C:comp-filter></C:filter>
+</C:calendar-query>`
+
+	rec := davRequest(t, srv, "REPORT", "/dav/calendars/me@example.com/", "1", query)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("expected 207, got %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "e1.ics") || !strings.Contains(body, "SUMMARY:Standup") {
+		t.Errorf("expected Standup in range\n%s", body)
+	}
+	if strings.Contains(body, "e2.ics") {
+		t.Error("Offsite is outside the requested range")
+	}
+
+	if !events.TimeMin.Equal(caldavNow.Add(-7*24*time.Hour)) || !events.TimeMax.Equal(caldavNow.Add(60*24*time.Hour)) {
+		t.Errorf("fetch window = %v - %v", events.TimeMin, events.TimeMax)
+	}
+}
+
+func TestCalDAV_ReportMultiget(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	query := `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <D:href>/dav/calendars/me%40example.com/e2.ics</D:href>
+  <D:href>http://127.0.0.1:5232/dav/calendars/me@example.com/missing.ics</D:href>
+</C:calendar-multiget>`
+
+	body := davRequest(t, srv, "REPORT", "/dav/calendars/me@example.com/", "", query).Body.String()
+	if !strings.Contains(body, "SUMMARY:Offsite") {
+		t.Errorf("expected Offsite calendar data\n%s", body)
+	}
+	if !strings.Contains(body, "missing.ics</D:href><D:status>HTTP/1.1 404 Not Found") {
+		t.Errorf("expected 404 for unknown href\n%s", body)
+	}
+}
+
+func TestCalDAV_ReportErrors(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	if rec := davRequest(t, srv, "REPORT", "/dav/calendars/me@example.com/", "", "<broken"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed body, got %d", rec.Code)
+	}
+	if rec := davRequest(t, srv, "REPORT", "/dav/calendars/me@example.com/", "", `<D:sync-collection xmlns:D="DAV:"/>`); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for unsupported report, got %d", rec.Code)
+	}
+	if rec := davRequest(t, srv, "REPORT", davHomePath, "", `<C:calendar-query xmlns:C="urn:ietf:params:xml:ns:caldav"/>`); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for report on home, got %d", rec.Code)
+	}
+}
+
+func TestCalDAV_Get(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	rec := davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e1.ics", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") || rec.Header().Get("ETag") == "" {
+		t.Errorf("unexpected headers %v", rec.Header())
+	}
+	if !strings.Contains(rec.Body.String(), "UID:e1") {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+
+	rec = davRequest(t, srv, http.MethodHead, "/dav/calendars/me@example.com/e1.ics", "", "")
+	if rec.Body.Len() != 0 {
+		t.Error("HEAD should not return a body")
+	}
+
+	rec = davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e3.ics", "", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for cancelled event, got %d", rec.Code)
+	}
+}
+
+func TestCalDAV_ReadOnly(t *testing.T) {
+	srv, _ := newTestCalDAV(t, CalDAVConfig{})
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete, "MKCALENDAR", "PROPPATCH"} {
+		rec := davRequest(t, srv, method, "/dav/calendars/me@example.com/e1.ics", "", "BEGIN:VCALENDAR")
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", method, rec.Code)
+		}
+	}
+}
+
+func TestCalDAV_Cache(t *testing.T) {
+	srv, events := newTestCalDAV(t, CalDAVConfig{CacheTTL: time.Minute})
+	now := caldavNow
+	srv.cfg.Now = func() time.Time { return now }
+
+	davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e1.ics", "", "")
+	davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e2.ics", "", "")
+	if events.Calls != 1 {
+		t.Errorf("expected one fetch within TTL, got %d", events.Calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e1.ics", "", "")
+	if events.Calls != 2 {
+		t.Errorf("expected refetch after TTL, got %d", events.Calls)
+	}
+}
+
+func TestCalDAV_CacheLookup(t *testing.T) {
+	var hits, misses int
+	srv, _ := newTestCalDAV(t, CalDAVConfig{CacheTTL: time.Minute, CacheLookup: func(hit bool) {
+		if hit {
+			hits++
+		} else {
+			misses++
+		}
+	}})
+
+	davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e1.ics", "", "")
+	davRequest(t, srv, http.MethodGet, "/dav/calendars/me@example.com/e2.ics", "", "")
+	if misses != 2 {
+		t.Errorf("expected misses for the calendar list and events, got %d", misses)
+	}
+	if hits < 2 {
+		t.Errorf("expected the second request to hit the cache, got %d hits", hits)
+	}
+}
+
+func TestCalDAV_UpstreamError(t *testing.T) {
+	srv, events := newTestCalDAV(t, CalDAVConfig{})
+	events.Err = errors.New("quota exceeded")
+
+	rec := davRequest(t, srv, "PROPFIND", "/dav/calendars/me@example.com/", "1", "")
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("expected 502, got %d", rec.Code)
+	}
+	if body, _ := io.ReadAll(rec.Body); strings.Contains(string(body), "quota") {
+		t.Error("upstream error details should not leak to clients")
+	}
+}
+
+func TestDavReport_Overlaps(t *testing.T) {
+	r := &davReport{
+		start: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+		end:   time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC),
+	}
+	at := func(day, hour int) time.Time { return time.Date(2026, 5, day, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"inside", at(1, 9), at(1, 10), true},
+		{"spans start", at(0, 22), at(1, 1), true},
+		{"ends at start", at(0, 22), at(1, 0), false},
+		{"starts at end", at(2, 0), at(2, 1), false},
+		{"before", at(0, 1), at(0, 2), false},
+		{"zero length inside", at(1, 5), at(1, 5), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.overlaps(&calendar.Event{Start: tt.start, End: tt.end}); got != tt.want {
+				t.Errorf("overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package bridge
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// iCalendar constants.
+const (
+	icalProdID        = "-//go-goog-cli//goog bridge//EN"
+	icalUTCLayout     = "20060102T150405Z"
+	icalDateLayout    = "20060102"
+	icalMaxLineOctets = 75
+)
+
+// icalPartStat maps Google response statuses to iCalendar PARTSTAT values.
+var icalPartStat = map[string]string{
+	calendar.ResponseAccepted:    "ACCEPTED",
+	calendar.ResponseDeclined:    "DECLINED",
+	calendar.ResponseTentative:   "TENTATIVE",
+	calendar.ResponseNeedsAction: "NEEDS-ACTION",
+}
+
+// renderICalendar renders a single event as a VCALENDAR object.
+func renderICalendar(event *calendar.Event, now time.Time) string {
+	var w icalWriter
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + icalProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + icalEscape(event.ID))
+
+	stamp := event.Updated
+	if stamp.IsZero() {
+		stamp = now
+	}
+	w.line("DTSTAMP:" + stamp.UTC().Format(icalUTCLayout))
+
+	if event.AllDay {
+		w.line("DTSTART;VALUE=DATE:" + event.Start.Format(icalDateLayout))
+		if !event.End.IsZero() {
+			w.line("DTEND;VALUE=DATE:" + event.End.Format(icalDateLayout))
+		}
+	} else {
+		w.line("DTSTART:" + event.Start.UTC().Format(icalUTCLayout))
+		if !event.End.IsZero() {
+			w.line("DTEND:" + event.End.UTC().Format(icalUTCLayout))
+		}
+	}
+
+	w.line("SUMMARY:" + icalEscape(event.Title))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + icalEscape(event.Description))
+	}
+	if event.Location != "" {
+		w.line("LOCATION:" + icalEscape(event.Location))
+	}
+	if event.Status != "" {
+		w.line("STATUS:" + strings.ToUpper(event.Status))
+	}
+	if event.Visibility == calendar.VisibilityPrivate {
+		w.line("CLASS:PRIVATE")
+	}
+	if event.Organizer != nil && event.Organizer.Email != "" {
+		w.line("ORGANIZER" + icalCommonName(event.Organizer.DisplayName) + ":mailto:" + event.Organizer.Email)
+	}
+	for _, a := range event.Attendees {
+		if a.Email == "" {
+			continue
+		}
+		params := icalCommonName(a.DisplayName)
+		if stat, ok := icalPartStat[a.ResponseStatus]; ok {
+			params += ";PARTSTAT=" + stat
+		}
+		if a.Optional {
+			params += ";ROLE=OPT-PARTICIPANT"
+		} else {
+			params += ";ROLE=REQ-PARTICIPANT"
+		}
+		w.line("ATTENDEE" + params + ":mailto:" + a.Email)
+	}
+	if event.HTMLLink != "" {
+		w.line("URL:" + event.HTMLLink)
+	}
+	if !event.Created.IsZero() {
+		w.line("CREATED:" + event.Created.UTC().Format(icalUTCLayout))
+	}
+	if !event.Updated.IsZero() {
+		w.line("LAST-MODIFIED:" + event.Updated.UTC().Format(icalUTCLayout))
+	}
+
+	w.line("END:VEVENT")
+	w.line("END:VCALENDAR")
+	return w.String()
+}
+
+// icalCommonName returns a CN parameter for a display name, if any.
+func icalCommonName(name string) string {
+	if name == "" {
+		return ""
+	}
+	// Parameter values cannot contain DQUOTE; quote to allow ; : and ,
+	return `;CN="` + strings.ReplaceAll(name, `"`, "'") + `"`
+}
+
+// icalEscape escapes a TEXT value.
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// icalWriter writes CRLF-terminated content lines folded at 75 octets.
+type icalWriter struct {
+	strings.Builder
+}
+
+func (w *icalWriter) line(s string) {
+	for len(s) > icalMaxLineOctets {
+		cut := icalMaxLineOctets
+		// Do not split a multi-byte character
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+func TestRenderICalendar(t *testing.T) {
+	loc := time.FixedZone("EST", -5*3600)
+	event := &calendar.Event{
+		ID:          "evt1",
+		Title:       "Planning; Q3, draft",
+		Description: "Line one\nLine two",
+		Location:    "Room 4",
+		Start:       time.Date(2026, 3, 2, 9, 0, 0, 0, loc),
+		End:         time.Date(2026, 3, 2, 10, 0, 0, 0, loc),
+		Status:      calendar.StatusConfirmed,
+		Visibility:  calendar.VisibilityPrivate,
+		Organizer:   &calendar.Attendee{Email: "boss@example.com", DisplayName: "The Boss"},
+		Attendees: []*calendar.Attendee{
+			{Email: "me@example.com", ResponseStatus: calendar.ResponseAccepted},
+			{Email: "opt@example.com", ResponseStatus: calendar.ResponseNeedsAction, Optional: true},
+		},
+		Updated: time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	got := renderICalendar(event, time.Now())
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:evt1\r\n",
+		"DTSTAMP:20260201T120000Z\r\n",
+		"DTSTART:20260302T140000Z\r\n",
+		"DTEND:20260302T150000Z\r\n",
+		`SUMMARY:Planning\; Q3\, draft` + "\r\n",
+		`DESCRIPTION:Line one\nLine two` + "\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"CLASS:PRIVATE\r\n",
+		`ORGANIZER;CN="The Boss":mailto:boss@example.com` + "\r\n",
+		"ATTENDEE;PARTSTAT=ACCEPTED;ROLE=REQ-PARTICIPANT:mailto:me@example.com\r\n",
+		"ATTENDEE;PARTSTAT=NEEDS-ACTION;ROLE=OPT-PARTICIPANT:mailto:opt@example.com\r\n",
+		"LAST-MODIFIED:20260201T120000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderICalendar_AllDay(t *testing.T) {
+	event := &calendar.Event{
+		ID:     "evt2",
+		Title:  "Holiday",
+		Start:  time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2026, 7, 5, 0, 0, 0, 0, time.UTC),
+		AllDay: true,
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	got := renderICalendar(event, now)
+
+	for _, want := range []string{"DTSTART;VALUE=DATE:20260704\r\n", "DTEND;VALUE=DATE:20260705\r\n", "DTSTAMP:20260101T000000Z\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "CLASS:") || strings.Contains(got, "LAST-MODIFIED") {
+		t.Errorf("unexpected optional properties\n%s", got)
+	}
+}
+
+func TestICalWriter_Folding(t *testing.T) {
+	var w icalWriter
+	w.line("SUMMARY:" + strings.Repeat("é", 60))
+
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected folded output, got %q", w.String())
+	}
+	for i, line := range lines {
+		if len(line) > icalMaxLineOctets+1 {
+			t.Errorf("line %d is %d octets", i, len(line))
+		}
+		if i > 0 && !strings.HasPrefix(line, " ") {
+			t.Errorf("continuation line %d does not start with a space", i)
+		}
+		if !strings.HasPrefix(strings.TrimPrefix(line, " "), "SUMMARY") && !strings.HasPrefix(strings.TrimPrefix(line, " "), "é") {
+			t.Errorf("line %d splits a character: %q", i, line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(w.String(), "\r\n ", "")
+	if unfolded != "SUMMARY:"+strings.Repeat("é", 60)+"\r\n" {
+		t.Errorf("unfolded = %q", unfolded)
+	}
+}
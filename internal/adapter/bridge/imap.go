@@ -0,0 +1,999 @@
+// Package bridge provides local protocol servers that expose Google data
+// to third-party clients using goog's authentication.
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Default IMAP bridge limits.
+const (
+	DefaultMaxMessages   = 500
+	DefaultCacheBytes    = 64 << 20
+	imapListPageSize     = 100
+	imapHierarchyDelim   = "/"
+	imapCapabilities     = "IMAP4rev1 LITERAL+ UNSELECT SPECIAL-USE"
+	imapReadOnlyResponse = "[CANNOT] The bridge is read-only"
+)
+
+// MessageSource provides the messages served by the IMAP bridge.
+type MessageSource interface {
+	List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error)
+	GetRaw(ctx context.Context, id string) ([]byte, error)
+}
+
+// LabelSource provides the labels exposed as IMAP mailboxes.
+type LabelSource interface {
+	List(ctx context.Context) ([]*mail.Label, error)
+}
+
+// IMAPConfig configures an IMAP bridge.
+type IMAPConfig struct {
+	// Username and Password are the credentials clients must LOGIN with.
+	Username string
+	Password string
+	// MaxMessages caps the number of most recent messages per mailbox.
+	MaxMessages int
+	// CacheBytes bounds the in-memory cache of raw messages.
+	CacheBytes int
+	// Logf receives connection errors. It may be nil.
+	Logf func(format string, args ...any)
+	// CacheLookup is told whether each raw message lookup hit the cache.
+	// It may be nil.
+	CacheLookup func(hit bool)
+}
+
+// systemMailboxes maps Gmail system labels to IMAP mailbox names and
+// special-use attributes. System labels not listed are not exposed.
+var systemMailboxes = map[string]struct{ Name, Attr string }{
+	"INBOX":     {"INBOX", ""},
+	"SENT":      {"Sent", `\Sent`},
+	"DRAFT":     {"Drafts", `\Drafts`},
+	"SPAM":      {"Spam", `\Junk`},
+	"TRASH":     {"Trash", `\Trash`},
+	"STARRED":   {"Starred", `\Flagged`},
+	"IMPORTANT": {"Important", ""},
+}
+
+// mailbox is an IMAP view of a Gmail label.
+type mailbox struct {
+	Name    string
+	LabelID string
+	Attr    string
+}
+
+// mailboxUIDs assigns stable UIDs to message IDs for one mailbox.
+type mailboxUIDs struct {
+	validity uint32
+	next     uint32
+	byID     map[string]uint32
+}
+
+// imapMessage is a message in a selected mailbox snapshot.
+type imapMessage struct {
+	UID uint32
+	Msg *mail.Message
+}
+
+// IMAPServer is a minimal read-only IMAP4rev1 server backed by Gmail.
+type IMAPServer struct {
+	messages MessageSource
+	labels   LabelSource
+	cfg      IMAPConfig
+	started  uint32
+
+	mu    sync.Mutex
+	uids  map[string]*mailboxUIDs
+	cache *rawCache
+	conns map[net.Conn]struct{}
+}
+
+// NewIMAPServer creates an IMAP bridge over the given sources.
+func NewIMAPServer(messages MessageSource, labels LabelSource, cfg IMAPConfig) *IMAPServer {
+	if cfg.MaxMessages <= 0 {
+		cfg.MaxMessages = DefaultMaxMessages
+	}
+	if cfg.CacheBytes <= 0 {
+		cfg.CacheBytes = DefaultCacheBytes
+	}
+	return &IMAPServer{
+		messages: messages,
+		labels:   labels,
+		cfg:      cfg,
+		started:  uint32(time.Now().Unix()),
+		uids:     make(map[string]*mailboxUIDs),
+		cache:    newRawCache(cfg.CacheBytes),
+		conns:    make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve accepts connections on ln until ctx is cancelled.
+func (s *IMAPServer) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+		s.mu.Lock()
+		for c := range s.conns {
+			_ = c.Close()
+		}
+		s.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				_ = conn.Close()
+			}()
+			if err := s.serveConn(ctx, conn); err != nil && ctx.Err() == nil {
+				s.logf("imap: %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+func (s *IMAPServer) logf(format string, args ...any) {
+	if s.cfg.Logf != nil {
+		s.cfg.Logf(format, args...)
+	}
+}
+
+// imapSession is the state of one client connection.
+type imapSession struct {
+	server        *IMAPServer
+	ctx           context.Context
+	w             *bufio.Writer
+	authenticated bool
+	selected      *mailbox
+	msgs          []imapMessage
+}
+
+// serveConn runs the command loop for one connection.
+func (s *IMAPServer) serveConn(ctx context.Context, conn net.Conn) error {
+	sess := &imapSession{server: s, ctx: ctx, w: bufio.NewWriter(conn)}
+	r := bufio.NewReader(conn)
+
+	sess.untagged("OK [CAPABILITY " + imapCapabilities + "] goog IMAP bridge ready")
+	if err := sess.w.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		line, err := readCommandLine(r, sess.w)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		cmd, err := parseCommand(line)
+		if err != nil {
+			tag, _, _ := strings.Cut(line, " ")
+			if tag == "" {
+				tag = "*"
+			}
+			sess.tagged(tag, "BAD", "Invalid command syntax")
+		} else if done := sess.handle(cmd); done {
+			return sess.w.Flush()
+		}
+		if err := sess.w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func (sess *imapSession) untagged(text string) {
+	_, _ = sess.w.WriteString("* " + text + "\r\n")
+}
+
+func (sess *imapSession) tagged(tag, status, text string) {
+	_, _ = sess.w.WriteString(tag + " " + status + " " + text + "\r\n")
+}
+
+// handle dispatches one command and reports whether the connection ends.
+func (sess *imapSession) handle(cmd *imapCommand) bool {
+	switch cmd.Name {
+	case "CAPABILITY":
+		sess.untagged("CAPABILITY " + imapCapabilities)
+		sess.tagged(cmd.Tag, "OK", "CAPABILITY completed")
+		return false
+	case "NOOP", "CHECK":
+		sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+		return false
+	case "LOGOUT":
+		sess.untagged("BYE goog IMAP bridge closing connection")
+		sess.tagged(cmd.Tag, "OK", "LOGOUT completed")
+		return true
+	case "LOGIN":
+		sess.handleLogin(cmd)
+		return false
+	}
+
+	if !sess.authenticated {
+		sess.tagged(cmd.Tag, "NO", "Not authenticated")
+		return false
+	}
+
+	switch cmd.Name {
+	case "LIST", "LSUB":
+		sess.handleList(cmd)
+	case "STATUS":
+		sess.handleStatus(cmd)
+	case "SELECT", "EXAMINE":
+		sess.handleSelect(cmd)
+	case "CLOSE", "UNSELECT":
+		if sess.selected == nil {
+			sess.tagged(cmd.Tag, "NO", "No mailbox selected")
+			return false
+		}
+		sess.selected, sess.msgs = nil, nil
+		sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+	case "FETCH", "UID FETCH":
+		sess.handleFetch(cmd, cmd.Name == "UID FETCH")
+	case "SEARCH", "UID SEARCH":
+		sess.handleSearch(cmd, cmd.Name == "UID SEARCH")
+	case "STORE", "UID STORE", "COPY", "UID COPY", "MOVE", "UID MOVE", "EXPUNGE", "UID EXPUNGE",
+		"APPEND", "CREATE", "DELETE", "RENAME", "SUBSCRIBE", "UNSUBSCRIBE":
+		sess.tagged(cmd.Tag, "NO", imapReadOnlyResponse)
+	default:
+		sess.tagged(cmd.Tag, "BAD", "Unsupported command "+cmd.Name)
+	}
+	return false
+}
+
+// handleLogin checks the client credentials.
+func (sess *imapSession) handleLogin(cmd *imapCommand) {
+	if sess.authenticated {
+		sess.tagged(cmd.Tag, "BAD", "Already authenticated")
+		return
+	}
+	if len(cmd.Args) != 2 {
+		sess.tagged(cmd.Tag, "BAD", "LOGIN requires username and password")
+		return
+	}
+	user, ok1 := cmd.Args[0].(string)
+	pass, ok2 := cmd.Args[1].(string)
+	if !ok1 || !ok2 {
+		sess.tagged(cmd.Tag, "BAD", "LOGIN requires username and password")
+		return
+	}
+
+	cfg := sess.server.cfg
+	userOK := subtle.ConstantTimeCompare([]byte(strings.ToLower(user)), []byte(strings.ToLower(cfg.Username))) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(cfg.Password)) == 1
+	if !userOK || !passOK {
+		sess.tagged(cmd.Tag, "NO", "[AUTHENTICATIONFAILED] Invalid credentials")
+		return
+	}
+
+	sess.authenticated = true
+	sess.tagged(cmd.Tag, "OK", "[CAPABILITY "+imapCapabilities+"] LOGIN completed")
+}
+
+// mailboxes returns the mailboxes exposed by the bridge, sorted by name.
+func (s *IMAPServer) mailboxes(ctx context.Context) ([]mailbox, error) {
+	labels, err := s.labels.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	boxes := []mailbox{{Name: "INBOX", LabelID: "INBOX"}}
+	for _, label := range labels {
+		if label.IsSystemLabel() {
+			sys, ok := systemMailboxes[label.ID]
+			if !ok || label.ID == "INBOX" {
+				continue
+			}
+			boxes = append(boxes, mailbox{Name: sys.Name, LabelID: label.ID, Attr: sys.Attr})
+			continue
+		}
+		boxes = append(boxes, mailbox{Name: label.Name, LabelID: label.ID})
+	}
+	sort.Slice(boxes[1:], func(i, j int) bool { return boxes[i+1].Name < boxes[j+1].Name })
+	return boxes, nil
+}
+
+// findMailbox resolves a mailbox by name. INBOX is case-insensitive.
+func (s *IMAPServer) findMailbox(ctx context.Context, name string) (*mailbox, error) {
+	boxes, err := s.mailboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range boxes {
+		if boxes[i].Name == name || (boxes[i].LabelID == "INBOX" && strings.EqualFold(name, "INBOX")) {
+			return &boxes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// handleList answers LIST and LSUB with mailboxes matching the pattern.
+func (sess *imapSession) handleList(cmd *imapCommand) {
+	if len(cmd.Args) != 2 {
+		sess.tagged(cmd.Tag, "BAD", cmd.Name+" requires reference and pattern")
+		return
+	}
+	ref, ok1 := cmd.Args[0].(string)
+	pattern, ok2 := cmd.Args[1].(string)
+	if !ok1 || !ok2 {
+		sess.tagged(cmd.Tag, "BAD", cmd.Name+" requires reference and pattern")
+		return
+	}
+
+	if pattern == "" {
+		sess.untagged(cmd.Name + ` (\Noselect) "` + imapHierarchyDelim + `" ""`)
+		sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+		return
+	}
+
+	boxes, err := sess.server.mailboxes(sess.ctx)
+	if err != nil {
+		sess.tagged(cmd.Tag, "NO", err.Error())
+		return
+	}
+	for _, box := range boxes {
+		if !matchMailbox(ref+pattern, box.Name) {
+			continue
+		}
+		attrs := []string{}
+		if box.Attr != "" {
+			attrs = append(attrs, box.Attr)
+		}
+		sess.untagged(fmt.Sprintf("%s (%s) \"%s\" %s", cmd.Name, strings.Join(attrs, " "), imapHierarchyDelim, imapAString(box.Name)))
+	}
+	sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+}
+
+// matchMailbox matches a LIST pattern where "*" matches anything and "%"
+// matches anything except the hierarchy delimiter.
+func matchMailbox(pattern, name string) bool {
+	if strings.EqualFold(pattern, "INBOX") && strings.EqualFold(name, "INBOX") {
+		return true
+	}
+	return matchWildcard(pattern, name)
+}
+
+func matchWildcard(pattern, name string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(name); i >= 0; i-- {
+				if matchWildcard(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case '%':
+			for i := 0; i <= len(name); i++ {
+				if i > 0 && name[i-1] == imapHierarchyDelim[0] {
+					break
+				}
+				if matchWildcard(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		default:
+			if len(name) == 0 || name[0] != pattern[0] {
+				return false
+			}
+			pattern, name = pattern[1:], name[1:]
+		}
+	}
+	return len(name) == 0
+}
+
+// snapshot loads the most recent messages of a mailbox, oldest first, and
+// assigns UIDs to messages that have not been seen before.
+func (s *IMAPServer) snapshot(ctx context.Context, box *mailbox) ([]imapMessage, uint32, uint32, error) {
+	var msgs []*mail.Message
+	opts := mail.ListOptions{LabelIDs: []string{box.LabelID}, MaxResults: imapListPageSize}
+	for len(msgs) < s.cfg.MaxMessages {
+		if remaining := s.cfg.MaxMessages - len(msgs); remaining < opts.MaxResults {
+			opts.MaxResults = remaining
+		}
+		result, err := s.messages.List(ctx, opts)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to list messages: %w", err)
+		}
+		msgs = append(msgs, result.Items...)
+		if result.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = result.NextPageToken
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Date.Before(msgs[j].Date) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uids, ok := s.uids[box.LabelID]
+	if !ok {
+		uids = &mailboxUIDs{validity: s.started, next: 1, byID: make(map[string]uint32)}
+		s.uids[box.LabelID] = uids
+	}
+
+	out := make([]imapMessage, 0, len(msgs))
+	for _, m := range msgs {
+		uid, ok := uids.byID[m.ID]
+		if !ok {
+			uid = uids.next
+			uids.byID[m.ID] = uid
+			uids.next++
+		}
+		out = append(out, imapMessage{UID: uid, Msg: m})
+	}
+	// Sequence numbers must follow UID order
+	sort.Slice(out, func(i, j int) bool { return out[i].UID < out[j].UID })
+	return out, uids.validity, uids.next, nil
+}
+
+// mailboxArg returns the mailbox named by the first argument.
+func (sess *imapSession) mailboxArg(cmd *imapCommand) (*mailbox, bool) {
+	if len(cmd.Args) == 0 {
+		sess.tagged(cmd.Tag, "BAD", cmd.Name+" requires a mailbox name")
+		return nil, false
+	}
+	name, ok := cmd.Args[0].(string)
+	if !ok {
+		sess.tagged(cmd.Tag, "BAD", cmd.Name+" requires a mailbox name")
+		return nil, false
+	}
+	box, err := sess.server.findMailbox(sess.ctx, name)
+	if err != nil {
+		sess.tagged(cmd.Tag, "NO", err.Error())
+		return nil, false
+	}
+	if box == nil {
+		sess.tagged(cmd.Tag, "NO", "[NONEXISTENT] No such mailbox")
+		return nil, false
+	}
+	return box, true
+}
+
+// handleSelect opens a mailbox read-only. SELECT and EXAMINE behave alike.
+func (sess *imapSession) handleSelect(cmd *imapCommand) {
+	sess.selected, sess.msgs = nil, nil
+
+	box, ok := sess.mailboxArg(cmd)
+	if !ok {
+		return
+	}
+	msgs, validity, next, err := sess.server.snapshot(sess.ctx, box)
+	if err != nil {
+		sess.tagged(cmd.Tag, "NO", err.Error())
+		return
+	}
+	sess.selected, sess.msgs = box, msgs
+
+	sess.untagged(`FLAGS (\Seen \Flagged \Draft)`)
+	sess.untagged(`OK [PERMANENTFLAGS ()] No permanent flags permitted`)
+	sess.untagged(fmt.Sprintf("%d EXISTS", len(msgs)))
+	sess.untagged("0 RECENT")
+	if unseen := firstUnseen(msgs); unseen > 0 {
+		sess.untagged(fmt.Sprintf("OK [UNSEEN %d] First unseen message", unseen))
+	}
+	sess.untagged(fmt.Sprintf("OK [UIDVALIDITY %d] UIDs valid", validity))
+	sess.untagged(fmt.Sprintf("OK [UIDNEXT %d] Predicted next UID", next))
+	sess.tagged(cmd.Tag, "OK", "[READ-ONLY] "+cmd.Name+" completed")
+}
+
+// firstUnseen returns the sequence number of the first unread message.
+func firstUnseen(msgs []imapMessage) int {
+	for i, m := range msgs {
+		if !m.Msg.IsRead {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// handleStatus reports mailbox counters.
+func (sess *imapSession) handleStatus(cmd *imapCommand) {
+	box, ok := sess.mailboxArg(cmd)
+	if !ok {
+		return
+	}
+	if len(cmd.Args) != 2 {
+		sess.tagged(cmd.Tag, "BAD", "STATUS requires a list of items")
+		return
+	}
+	items, ok := cmd.Args[1].(imapList)
+	if !ok {
+		sess.tagged(cmd.Tag, "BAD", "STATUS requires a list of items")
+		return
+	}
+
+	msgs, validity, next, err := sess.server.snapshot(sess.ctx, box)
+	if err != nil {
+		sess.tagged(cmd.Tag, "NO", err.Error())
+		return
+	}
+
+	var out []string
+	for _, item := range items {
+		name, _ := item.(string)
+		switch name = strings.ToUpper(name); name {
+		case "MESSAGES":
+			out = append(out, name, strconv.Itoa(len(msgs)))
+		case "RECENT":
+			out = append(out, name, "0")
+		case "UIDNEXT":
+			out = append(out, name, strconv.FormatUint(uint64(next), 10))
+		case "UIDVALIDITY":
+			out = append(out, name, strconv.FormatUint(uint64(validity), 10))
+		case "UNSEEN":
+			unseen := 0
+			for _, m := range msgs {
+				if !m.Msg.IsRead {
+					unseen++
+				}
+			}
+			out = append(out, name, strconv.Itoa(unseen))
+		default:
+			sess.tagged(cmd.Tag, "BAD", "Unknown STATUS item "+name)
+			return
+		}
+	}
+	sess.untagged(fmt.Sprintf("STATUS %s (%s)", imapAString(box.Name), strings.Join(out, " ")))
+	sess.tagged(cmd.Tag, "OK", "STATUS completed")
+}
+
+// resolveSet returns the indexes of messages matched by a sequence set.
+func (sess *imapSession) resolveSet(set seqSet, uid bool) []int {
+	var idx []int
+	if len(sess.msgs) == 0 {
+		return idx
+	}
+	maxSeq := uint32(len(sess.msgs))
+	maxUID := sess.msgs[len(sess.msgs)-1].UID
+	for i, m := range sess.msgs {
+		if uid {
+			if set.Contains(m.UID, maxUID) {
+				idx = append(idx, i)
+			}
+		} else if set.Contains(uint32(i+1), maxSeq) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// flags returns the IMAP flags of a message.
+func flags(m *mail.Message) string {
+	var f []string
+	if m.IsRead {
+		f = append(f, `\Seen`)
+	}
+	if m.IsStarred {
+		f = append(f, `\Flagged`)
+	}
+	for _, l := range m.Labels {
+		if l == "DRAFT" {
+			f = append(f, `\Draft`)
+		}
+	}
+	return "(" + strings.Join(f, " ") + ")"
+}
+
+// fetchMacros expands the FETCH macros.
+var fetchMacros = map[string][]string{
+	"ALL":  {"FLAGS", "INTERNALDATE", "RFC822.SIZE", "ENVELOPE"},
+	"FAST": {"FLAGS", "INTERNALDATE", "RFC822.SIZE"},
+	"FULL": {"FLAGS", "INTERNALDATE", "RFC822.SIZE", "ENVELOPE", "BODY"},
+}
+
+// handleFetch answers FETCH and UID FETCH.
+func (sess *imapSession) handleFetch(cmd *imapCommand, uid bool) {
+	if sess.selected == nil {
+		sess.tagged(cmd.Tag, "NO", "No mailbox selected")
+		return
+	}
+	if len(cmd.Args) != 2 {
+		sess.tagged(cmd.Tag, "BAD", "FETCH requires a sequence set and items")
+		return
+	}
+	setArg, _ := cmd.Args[0].(string)
+	set, err := parseSeqSet(setArg)
+	if err != nil {
+		sess.tagged(cmd.Tag, "BAD", "Invalid sequence set")
+		return
+	}
+
+	var items []string
+	switch arg := cmd.Args[1].(type) {
+	case string:
+		if macro, ok := fetchMacros[strings.ToUpper(arg)]; ok {
+			items = macro
+		} else {
+			items = []string{arg}
+		}
+	case imapList:
+		for _, a := range arg {
+			s, ok := a.(string)
+			if !ok {
+				sess.tagged(cmd.Tag, "BAD", "Invalid fetch item")
+				return
+			}
+			items = append(items, s)
+		}
+	}
+	if uid && !containsFold(items, "UID") {
+		items = append([]string{"UID"}, items...)
+	}
+
+	// Validate before producing any output
+	sections := make(map[int]*fetchSection)
+	for i, item := range items {
+		upper := strings.ToUpper(item)
+		switch {
+		case strings.HasPrefix(upper, "BODY[") || strings.HasPrefix(upper, "BODY.PEEK["):
+			sec, err := parseFetchSection(item)
+			if err != nil {
+				sess.tagged(cmd.Tag, "BAD", "Invalid fetch item "+item)
+				return
+			}
+			sections[i] = sec
+		case isSimpleFetchItem(upper):
+		default:
+			sess.tagged(cmd.Tag, "BAD", "Unsupported fetch item "+item)
+			return
+		}
+	}
+
+	for _, i := range sess.resolveSet(set, uid) {
+		line, err := sess.fetchLine(i, items, sections)
+		if err != nil {
+			sess.tagged(cmd.Tag, "NO", err.Error())
+			return
+		}
+		_, _ = sess.w.WriteString(line)
+	}
+	sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+}
+
+// isSimpleFetchItem reports whether item is a supported non-section item.
+func isSimpleFetchItem(item string) bool {
+	switch item {
+	case "UID", "FLAGS", "INTERNALDATE", "RFC822.SIZE", "ENVELOPE", "BODYSTRUCTURE", "BODY",
+		"RFC822", "RFC822.HEADER", "RFC822.TEXT":
+		return true
+	}
+	return false
+}
+
+// fetchLine renders the FETCH response for the message at index i.
+func (sess *imapSession) fetchLine(i int, items []string, sections map[int]*fetchSection) (string, error) {
+	m := sess.msgs[i]
+
+	var raw []byte
+	var root *mimePart
+	load := func() error {
+		if raw != nil {
+			return nil
+		}
+		var err error
+		if raw, err = sess.server.raw(sess.ctx, m.Msg.ID); err != nil {
+			return err
+		}
+		root = parseMimePart(raw, "text/plain")
+		return nil
+	}
+
+	var parts []string
+	for idx, item := range items {
+		upper := strings.ToUpper(item)
+		if sec, ok := sections[idx]; ok {
+			if err := load(); err != nil {
+				return "", err
+			}
+			data := sec.extract(raw, root)
+			value := "NIL"
+			if data != nil {
+				value = imapLiteral(data)
+			}
+			parts = append(parts, sec.responseName()+" "+value)
+			continue
+		}
+
+		switch upper {
+		case "UID":
+			parts = append(parts, "UID "+strconv.FormatUint(uint64(m.UID), 10))
+		case "FLAGS":
+			parts = append(parts, "FLAGS "+flags(m.Msg))
+		case "INTERNALDATE":
+			parts = append(parts, "INTERNALDATE "+formatInternalDate(m.Msg.Date))
+		default:
+			if err := load(); err != nil {
+				return "", err
+			}
+			switch upper {
+			case "RFC822.SIZE":
+				parts = append(parts, "RFC822.SIZE "+strconv.Itoa(len(raw)))
+			case "ENVELOPE":
+				parts = append(parts, "ENVELOPE "+envelope(root.Fields))
+			case "BODYSTRUCTURE", "BODY":
+				parts = append(parts, upper+" "+bodyStructure(root))
+			case "RFC822":
+				parts = append(parts, "RFC822 "+imapLiteral(raw))
+			case "RFC822.HEADER":
+				parts = append(parts, "RFC822.HEADER "+imapLiteral(root.Header))
+			case "RFC822.TEXT":
+				parts = append(parts, "RFC822.TEXT "+imapLiteral(root.Body))
+			}
+		}
+	}
+	return fmt.Sprintf("* %d FETCH (%s)\r\n", i+1, strings.Join(parts, " ")), nil
+}
+
+// handleSearch answers SEARCH and UID SEARCH for flag, date and set keys.
+func (sess *imapSession) handleSearch(cmd *imapCommand, uid bool) {
+	if sess.selected == nil {
+		sess.tagged(cmd.Tag, "NO", "No mailbox selected")
+		return
+	}
+
+	args := cmd.Args
+	if len(args) >= 2 {
+		if key, _ := args[0].(string); strings.EqualFold(key, "CHARSET") {
+			args = args[2:]
+		}
+	}
+
+	match, err := sess.searchFilter(args)
+	if err != nil {
+		sess.tagged(cmd.Tag, "NO", "[CANNOT] "+err.Error())
+		return
+	}
+
+	var hits []string
+	for i, m := range sess.msgs {
+		if !match(i, m) {
+			continue
+		}
+		if uid {
+			hits = append(hits, strconv.FormatUint(uint64(m.UID), 10))
+		} else {
+			hits = append(hits, strconv.Itoa(i+1))
+		}
+	}
+	if len(hits) > 0 {
+		sess.untagged("SEARCH " + strings.Join(hits, " "))
+	} else {
+		sess.untagged("SEARCH")
+	}
+	sess.tagged(cmd.Tag, "OK", cmd.Name+" completed")
+}
+
+// searchMatcher reports whether the message at index i matches.
+type searchMatcher func(i int, m imapMessage) bool
+
+// searchFilter builds a matcher that ANDs all search keys in args.
+func (sess *imapSession) searchFilter(args imapList) (searchMatcher, error) {
+	var matchers []searchMatcher
+	for i := 0; i < len(args); i++ {
+		if sub, ok := args[i].(imapList); ok {
+			m, err := sess.searchFilter(sub)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, m)
+			continue
+		}
+
+		key, _ := args[i].(string)
+		upper := strings.ToUpper(key)
+		nextArg := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s requires an argument", upper)
+			}
+			i++
+			s, ok := args[i].(string)
+			if !ok {
+				return "", fmt.Errorf("%s requires an argument", upper)
+			}
+			return s, nil
+		}
+
+		switch upper {
+		case "ALL":
+			matchers = append(matchers, func(int, imapMessage) bool { return true })
+		case "SEEN":
+			matchers = append(matchers, func(_ int, m imapMessage) bool { return m.Msg.IsRead })
+		case "UNSEEN":
+			matchers = append(matchers, func(_ int, m imapMessage) bool { return !m.Msg.IsRead })
+		case "FLAGGED":
+			matchers = append(matchers, func(_ int, m imapMessage) bool { return m.Msg.IsStarred })
+		case "UNFLAGGED":
+			matchers = append(matchers, func(_ int, m imapMessage) bool { return !m.Msg.IsStarred })
+		case "NEW", "RECENT":
+			matchers = append(matchers, func(int, imapMessage) bool { return false })
+		case "OLD":
+			matchers = append(matchers, func(int, imapMessage) bool { return true })
+		case "DELETED":
+			matchers = append(matchers, func(int, imapMessage) bool { return false })
+		case "UNDELETED":
+			matchers = append(matchers, func(int, imapMessage) bool { return true })
+		case "SINCE", "BEFORE", "ON":
+			value, err := nextArg()
+			if err != nil {
+				return nil, err
+			}
+			day, err := time.Parse("2-Jan-2006", value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date %q", value)
+			}
+			matchers = append(matchers, dateMatcher(upper, day))
+		case "UID":
+			value, err := nextArg()
+			if err != nil {
+				return nil, err
+			}
+			set, err := parseSeqSet(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UID set %q", value)
+			}
+			maxUID := sess.maxUID()
+			matchers = append(matchers, func(_ int, m imapMessage) bool { return set.Contains(m.UID, maxUID) })
+		default:
+			set, err := parseSeqSet(key)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported search key %s", upper)
+			}
+			maxSeq := uint32(len(sess.msgs))
+			matchers = append(matchers, func(i int, _ imapMessage) bool { return set.Contains(uint32(i+1), maxSeq) })
+		}
+	}
+
+	return func(i int, m imapMessage) bool {
+		for _, match := range matchers {
+			if !match(i, m) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// dateMatcher compares the message date, in its own zone, to a day.
+func dateMatcher(key string, day time.Time) searchMatcher {
+	return func(_ int, m imapMessage) bool {
+		y, mo, d := m.Msg.Date.Date()
+		msgDay := time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+		switch key {
+		case "SINCE":
+			return !msgDay.Before(day)
+		case "BEFORE":
+			return msgDay.Before(day)
+		default:
+			return msgDay.Equal(day)
+		}
+	}
+}
+
+func (sess *imapSession) maxUID() uint32 {
+	if len(sess.msgs) == 0 {
+		return 0
+	}
+	return sess.msgs[len(sess.msgs)-1].UID
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// raw returns the raw RFC 822 bytes of a message with CRLF line endings,
+// using the in-memory cache.
+func (s *IMAPServer) raw(ctx context.Context, id string) ([]byte, error) {
+	data, ok := s.cache.get(id)
+	if s.cfg.CacheLookup != nil {
+		s.cfg.CacheLookup(ok)
+	}
+	if ok {
+		return data, nil
+	}
+	data, err := s.messages.GetRaw(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message %s: %w", id, err)
+	}
+	data = normalizeCRLF(data)
+	s.cache.put(id, data)
+	return data, nil
+}
+
+// normalizeCRLF converts bare LF line endings to CRLF.
+func normalizeCRLF(data []byte) []byte {
+	if !bytes.Contains(data, []byte("\n")) {
+		return data
+	}
+	var out bytes.Buffer
+	out.Grow(len(data) + len(data)/40)
+	for i, c := range data {
+		if c == '\n' && (i == 0 || data[i-1] != '\r') {
+			out.WriteByte('\r')
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
+
+// rawCache is a FIFO cache of raw messages bounded by total size.
+type rawCache struct {
+	mu    sync.Mutex
+	limit int
+	size  int
+	order []string
+	data  map[string][]byte
+}
+
+func newRawCache(limit int) *rawCache {
+	return &rawCache{limit: limit, data: make(map[string][]byte)}
+}
+
+func (c *rawCache) get(id string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.data[id]
+	return data, ok
+}
+
+func (c *rawCache) put(id string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.data[id]; ok || len(data) > c.limit {
+		return
+	}
+	for c.size+len(data) > c.limit && len(c.order) > 0 {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.size -= len(c.data[oldest])
+		delete(c.data, oldest)
+	}
+	c.data[id] = data
+	c.order = append(c.order, id)
+	c.size += len(data)
+}
@@ -0,0 +1,472 @@
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// imapDateTimeLayout is the INTERNALDATE format.
+const imapDateTimeLayout = "02-Jan-2006 15:04:05 -0700"
+
+// mimePart is a node of a parsed MIME message. Header and Body are the
+// raw bytes of the part; Header includes the blank separator line.
+type mimePart struct {
+	Header  []byte
+	Body    []byte
+	Fields  textproto.MIMEHeader
+	Type    string
+	SubType string
+	Params  map[string]string
+	Parts   []*mimePart
+	Message *mimePart // encapsulated message for message/rfc822
+}
+
+// parseMimePart parses raw message or part bytes into a MIME tree.
+func parseMimePart(raw []byte, defaultType string) *mimePart {
+	header, body := splitHeader(raw)
+	part := &mimePart{Header: header, Body: body, Fields: parseHeaderFields(header)}
+
+	mediaType, params, err := mime.ParseMediaType(part.Fields.Get("Content-Type"))
+	if err != nil || !strings.Contains(mediaType, "/") {
+		mediaType, params = defaultType, nil
+	}
+	part.Type, part.SubType, _ = strings.Cut(strings.ToLower(mediaType), "/")
+	part.Params = params
+	if part.Type == "text" && part.Params["charset"] == "" {
+		if part.Params == nil {
+			part.Params = make(map[string]string)
+		}
+		part.Params["charset"] = "us-ascii"
+	}
+
+	switch {
+	case part.Type == "multipart" && params["boundary"] != "":
+		childType := "text/plain"
+		if part.SubType == "digest" {
+			childType = "message/rfc822"
+		}
+		for _, childRaw := range splitMultipart(body, params["boundary"]) {
+			part.Parts = append(part.Parts, parseMimePart(childRaw, childType))
+		}
+	case part.Type == "message" && part.SubType == "rfc822":
+		part.Message = parseMimePart(body, "text/plain")
+	}
+	return part
+}
+
+// splitHeader splits raw bytes after the blank line ending the header.
+func splitHeader(raw []byte) (header, body []byte) {
+	if bytes.HasPrefix(raw, []byte("\r\n")) {
+		return raw[:2], raw[2:]
+	}
+	if i := bytes.Index(raw, []byte("\r\n\r\n")); i >= 0 {
+		return raw[:i+4], raw[i+4:]
+	}
+	if i := bytes.Index(raw, []byte("\n\n")); i >= 0 {
+		return raw[:i+2], raw[i+2:]
+	}
+	return raw, nil
+}
+
+// parseHeaderFields parses a raw header block, ignoring malformed lines.
+func parseHeaderFields(header []byte) textproto.MIMEHeader {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(header)))
+	fields, _ := r.ReadMIMEHeader()
+	if fields == nil {
+		fields = make(textproto.MIMEHeader)
+	}
+	return fields
+}
+
+// splitMultipart returns the raw body parts of a multipart body. The line
+// break preceding each delimiter belongs to the delimiter.
+func splitMultipart(body []byte, boundary string) [][]byte {
+	delim := []byte("--" + boundary)
+	var parts [][]byte
+
+	idx := indexDelimiter(body, delim, 0)
+	for idx >= 0 {
+		if bytes.HasPrefix(body[idx+len(delim):], []byte("--")) {
+			break // closing delimiter
+		}
+		nl := bytes.IndexByte(body[idx:], '\n')
+		if nl < 0 {
+			break
+		}
+		start := idx + nl + 1
+		next := indexDelimiter(body, delim, start)
+		if next < 0 {
+			parts = append(parts, body[start:])
+			break
+		}
+		end := next
+		if end > start && body[end-1] == '\n' {
+			end--
+		}
+		if end > start && body[end-1] == '\r' {
+			end--
+		}
+		parts = append(parts, body[start:end])
+		idx = next
+	}
+	return parts
+}
+
+// indexDelimiter finds the next delimiter at the start of a line.
+func indexDelimiter(body, delim []byte, from int) int {
+	for from <= len(body) {
+		i := bytes.Index(body[from:], delim)
+		if i < 0 {
+			return -1
+		}
+		pos := from + i
+		if pos == 0 || body[pos-1] == '\n' {
+			return pos
+		}
+		from = pos + 1
+	}
+	return -1
+}
+
+// child returns the nth (1-based) sub-part used for section numbering.
+func (p *mimePart) child(n int) *mimePart {
+	if len(p.Parts) > 0 {
+		if n < 1 || n > len(p.Parts) {
+			return nil
+		}
+		return p.Parts[n-1]
+	}
+	if p.Message != nil {
+		return p.Message.child(n)
+	}
+	if n == 1 {
+		return p
+	}
+	return nil
+}
+
+// headerFields returns the header lines whose names are (or, with not set,
+// are not) in names, followed by the blank separator line.
+func headerFields(header []byte, names []string, not bool) []byte {
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[strings.ToLower(n)] = true
+	}
+
+	var out bytes.Buffer
+	keep := false
+	for _, line := range bytes.SplitAfter(header, []byte("\n")) {
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if len(trimmed) == 0 {
+			break
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			name, _, _ := bytes.Cut(trimmed, []byte(":"))
+			keep = want[strings.ToLower(strings.TrimSpace(string(name)))] != not
+		}
+		if keep {
+			out.Write(trimmed)
+			out.WriteString("\r\n")
+		}
+	}
+	out.WriteString("\r\n")
+	return out.Bytes()
+}
+
+// fetchSection is a parsed BODY[...]<...> fetch item.
+type fetchSection struct {
+	Spec    string // section as requested, used in the response
+	Path    []int
+	Text    string // "", HEADER, HEADER.FIELDS, HEADER.FIELDS.NOT, TEXT, MIME
+	Fields  []string
+	Partial bool
+	Offset  int
+	Count   int
+}
+
+// parseFetchSection parses the part of a BODY fetch item starting at "[".
+func parseFetchSection(item string) (*fetchSection, error) {
+	open := strings.IndexByte(item, '[')
+	closeIdx := strings.LastIndexByte(item, ']')
+	if open < 0 || closeIdx < open {
+		return nil, errBadSyntax
+	}
+	sec := &fetchSection{Spec: item[open+1 : closeIdx]}
+
+	if rest := item[closeIdx+1:]; rest != "" {
+		if !strings.HasPrefix(rest, "<") || !strings.HasSuffix(rest, ">") {
+			return nil, errBadSyntax
+		}
+		offset, count, ok := strings.Cut(rest[1:len(rest)-1], ".")
+		var err1, err2 error
+		sec.Offset, err1 = strconv.Atoi(offset)
+		if ok {
+			sec.Count, err2 = strconv.Atoi(count)
+		}
+		if !ok || err1 != nil || err2 != nil || sec.Offset < 0 || sec.Count < 0 {
+			return nil, errBadSyntax
+		}
+		sec.Partial = true
+	}
+
+	spec := sec.Spec
+	if fieldsStart := strings.IndexByte(spec, '('); fieldsStart >= 0 {
+		p := &argParser{s: spec[fieldsStart:]}
+		args, err := p.parseList(false)
+		if err != nil || len(args) != 1 {
+			return nil, errBadSyntax
+		}
+		list, ok := args[0].(imapList)
+		if !ok {
+			return nil, errBadSyntax
+		}
+		for _, f := range list {
+			name, ok := f.(string)
+			if !ok {
+				return nil, errBadSyntax
+			}
+			sec.Fields = append(sec.Fields, name)
+		}
+		spec = strings.TrimSpace(spec[:fieldsStart])
+	}
+
+	for spec != "" {
+		head, tail, _ := strings.Cut(spec, ".")
+		n, err := strconv.Atoi(head)
+		if err != nil {
+			break
+		}
+		if n < 1 {
+			return nil, errBadSyntax
+		}
+		sec.Path = append(sec.Path, n)
+		spec = tail
+	}
+
+	sec.Text = strings.ToUpper(spec)
+	switch sec.Text {
+	case "", "HEADER", "TEXT":
+	case "MIME":
+		if len(sec.Path) == 0 {
+			return nil, errBadSyntax
+		}
+	case "HEADER.FIELDS", "HEADER.FIELDS.NOT":
+		if len(sec.Fields) == 0 {
+			return nil, errBadSyntax
+		}
+	default:
+		return nil, errBadSyntax
+	}
+	return sec, nil
+}
+
+// extract returns the bytes a section refers to, or nil if the section
+// does not exist in the message.
+func (sec *fetchSection) extract(raw []byte, root *mimePart) []byte {
+	part := root
+	for _, n := range sec.Path {
+		if part = part.child(n); part == nil {
+			return nil
+		}
+	}
+
+	var data []byte
+	switch sec.Text {
+	case "":
+		if len(sec.Path) == 0 {
+			data = raw
+		} else {
+			data = part.Body
+		}
+	case "MIME":
+		data = part.Header
+	default:
+		msg := part
+		if len(sec.Path) > 0 {
+			if part.Message == nil {
+				return nil
+			}
+			msg = part.Message
+		}
+		switch sec.Text {
+		case "HEADER":
+			data = msg.Header
+		case "TEXT":
+			data = msg.Body
+		case "HEADER.FIELDS":
+			data = headerFields(msg.Header, sec.Fields, false)
+		case "HEADER.FIELDS.NOT":
+			data = headerFields(msg.Header, sec.Fields, true)
+		}
+	}
+
+	if sec.Partial {
+		if sec.Offset >= len(data) {
+			return []byte{}
+		}
+		data = data[sec.Offset:]
+		if sec.Count < len(data) {
+			data = data[:sec.Count]
+		}
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return data
+}
+
+// responseName returns the data item name used in the FETCH response.
+func (sec *fetchSection) responseName() string {
+	name := "BODY[" + sec.Spec + "]"
+	if sec.Partial {
+		name += "<" + strconv.Itoa(sec.Offset) + ">"
+	}
+	return name
+}
+
+// imapString formats s as an IMAP quoted string, literal, or NIL.
+func imapString(s string) string {
+	if s == "" {
+		return "NIL"
+	}
+	return imapAString(s)
+}
+
+// imapAString formats s as a quoted string or literal, never NIL.
+func imapAString(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '\r' || c == '\n' || c >= 0x80 || c == 0 {
+			return imapLiteral([]byte(s))
+		}
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// imapLiteral formats data as a synchronizing literal.
+func imapLiteral(data []byte) string {
+	return "{" + strconv.Itoa(len(data)) + "}\r\n" + string(data)
+}
+
+// encodeHeaderText re-encodes decoded header text for the wire.
+func encodeHeaderText(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return mime.QEncoding.Encode("utf-8", s)
+		}
+	}
+	return s
+}
+
+// envelope renders the ENVELOPE structure of a message header.
+func envelope(fields textproto.MIMEHeader) string {
+	from := addressList(fields.Get("From"))
+	sender := addressList(fields.Get("Sender"))
+	if sender == "NIL" {
+		sender = from
+	}
+	replyTo := addressList(fields.Get("Reply-To"))
+	if replyTo == "NIL" {
+		replyTo = from
+	}
+
+	items := []string{
+		imapString(fields.Get("Date")),
+		imapString(fields.Get("Subject")),
+		from,
+		sender,
+		replyTo,
+		addressList(fields.Get("To")),
+		addressList(fields.Get("Cc")),
+		addressList(fields.Get("Bcc")),
+		imapString(fields.Get("In-Reply-To")),
+		imapString(fields.Get("Message-Id")),
+	}
+	return "(" + strings.Join(items, " ") + ")"
+}
+
+// addressList renders an address header as an IMAP address list.
+func addressList(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "NIL"
+	}
+	addrs, err := mail.ParseAddressList(value)
+	if err != nil || len(addrs) == 0 {
+		return "NIL"
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for _, addr := range addrs {
+		local, host, _ := strings.Cut(addr.Address, "@")
+		fmt.Fprintf(&sb, "(%s NIL %s %s)",
+			imapString(encodeHeaderText(addr.Name)), imapString(local), imapString(host))
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// bodyStructure renders the BODYSTRUCTURE of a part.
+func bodyStructure(p *mimePart) string {
+	if len(p.Parts) > 0 {
+		var sb strings.Builder
+		sb.WriteByte('(')
+		for _, child := range p.Parts {
+			sb.WriteString(bodyStructure(child))
+		}
+		sb.WriteString(" " + imapString(strings.ToUpper(p.SubType)))
+		sb.WriteByte(')')
+		return sb.String()
+	}
+
+	encoding := p.Fields.Get("Content-Transfer-Encoding")
+	if encoding == "" {
+		encoding = "7BIT"
+	}
+	items := []string{
+		imapString(strings.ToUpper(p.Type)),
+		imapString(strings.ToUpper(p.SubType)),
+		paramList(p.Params),
+		imapString(p.Fields.Get("Content-Id")),
+		imapString(p.Fields.Get("Content-Description")),
+		imapString(strings.ToUpper(encoding)),
+		strconv.Itoa(len(p.Body)),
+	}
+	switch {
+	case p.Message != nil:
+		items = append(items, envelope(p.Message.Fields), bodyStructure(p.Message), strconv.Itoa(bytes.Count(p.Body, []byte("\n"))))
+	case p.Type == "text":
+		items = append(items, strconv.Itoa(bytes.Count(p.Body, []byte("\n"))))
+	}
+	return "(" + strings.Join(items, " ") + ")"
+}
+
+// paramList renders body parameters as an IMAP list, sorted by name.
+func paramList(params map[string]string) string {
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return "NIL"
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		items = append(items, imapString(strings.ToUpper(k)), imapString(encodeHeaderText(params[k])))
+	}
+	return "(" + strings.Join(items, " ") + ")"
+}
+
+// formatInternalDate formats t as an INTERNALDATE value.
+func formatInternalDate(t time.Time) string {
+	return `"` + t.Format(imapDateTimeLayout) + `"`
+}
@@ -0,0 +1,177 @@
+package bridge
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMultipartRaw is a multipart/mixed message with a text part and an
+// attached message.
+const testMultipartRaw = "From: Alice Example <alice@example.com>\r\n" +
+	"To: bob@example.com, \"Carol\" <carol@example.com>\r\n" +
+	"Subject: Quarterly report\r\n" +
+	"Date: Mon, 1 Apr 2024 10:00:00 +0000\r\n" +
+	"Message-ID: <m1@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"XYZ\"\r\n" +
+	"\r\n" +
+	"preamble\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Hello Bob,\r\nsee attached.\r\n" +
+	"--XYZ\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"From: carol@example.com\r\n" +
+	"Subject: Inner\r\n" +
+	"\r\n" +
+	"inner body\r\n" +
+	"--XYZ--\r\n"
+
+func TestParseMimePart_Multipart(t *testing.T) {
+	root := parseMimePart([]byte(testMultipartRaw), "text/plain")
+
+	if root.Type != "multipart" || root.SubType != "mixed" || len(root.Parts) != 2 {
+		t.Fatalf("unexpected root: %s/%s with %d parts", root.Type, root.SubType, len(root.Parts))
+	}
+	if got := string(root.Parts[0].Body); got != "Hello Bob,\r\nsee attached." {
+		t.Errorf("part 1 body = %q", got)
+	}
+	inner := root.Parts[1].Message
+	if inner == nil || inner.Fields.Get("Subject") != "Inner" || string(inner.Body) != "inner body" {
+		t.Errorf("unexpected encapsulated message: %+v", inner)
+	}
+}
+
+func TestFetchSection_Extract(t *testing.T) {
+	raw := []byte(testMultipartRaw)
+	root := parseMimePart(raw, "text/plain")
+
+	tests := []struct {
+		item string
+		want string
+	}{
+		{"BODY[]", testMultipartRaw},
+		{"BODY.PEEK[HEADER.FIELDS (Subject FROM)]", "From: Alice Example <alice@example.com>\r\nSubject: Quarterly report\r\n\r\n"},
+		{"BODY[1]", "Hello Bob,\r\nsee attached."},
+		{"BODY[1.MIME]", "Content-Type: text/plain; charset=utf-8\r\n\r\n"},
+		{"BODY[2.HEADER]", "From: carol@example.com\r\nSubject: Inner\r\n\r\n"},
+		{"BODY[2.TEXT]", "inner body"},
+		{"BODY[2.1]", "inner body"},
+		{"BODY[]<6.5>", "Alice"},
+		{"BODY[1]<100.10>", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			sec, err := parseFetchSection(tt.item)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := string(sec.extract(raw, root)); got != tt.want {
+				t.Errorf("extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	sec, _ := parseFetchSection("BODY[3]")
+	if sec.extract(raw, root) != nil {
+		t.Error("expected nil for a missing part")
+	}
+}
+
+func TestFetchSection_HeaderFieldsNot(t *testing.T) {
+	header := []byte("Subject: Hi\r\nReceived: from a\r\n\tby b\r\nFrom: x@example.com\r\n\r\n")
+	got := string(headerFields(header, []string{"received"}, true))
+	if got != "Subject: Hi\r\nFrom: x@example.com\r\n\r\n" {
+		t.Errorf("headerFields() = %q", got)
+	}
+	got = string(headerFields(header, []string{"RECEIVED"}, false))
+	if got != "Received: from a\r\n\tby b\r\n\r\n" {
+		t.Errorf("headerFields() with continuation = %q", got)
+	}
+}
+
+func TestParseFetchSection_Errors(t *testing.T) {
+	for _, item := range []string{"BODY[", "BODY[FOO]", "BODY[MIME]", "BODY[HEADER.FIELDS]", "BODY[]<1>", "BODY[]x", "BODY[0]"} {
+		if _, err := parseFetchSection(item); err == nil {
+			t.Errorf("parseFetchSection(%q) expected error", item)
+		}
+	}
+}
+
+func TestFetchSection_ResponseName(t *testing.T) {
+	sec, _ := parseFetchSection("BODY.PEEK[HEADER.FIELDS (FROM)]<0.10>")
+	if got := sec.responseName(); got != "BODY[HEADER.FIELDS (FROM)]<0>" {
+		t.Errorf("responseName() = %q", got)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	root := parseMimePart([]byte(testMultipartRaw), "text/plain")
+	got := envelope(root.Fields)
+
+	want := `("Mon, 1 Apr 2024 10:00:00 +0000" "Quarterly report" ` +
+		`(("Alice Example" NIL "alice" "example.com")) ` +
+		`(("Alice Example" NIL "alice" "example.com")) ` +
+		`(("Alice Example" NIL "alice" "example.com")) ` +
+		`((NIL NIL "bob" "example.com")("Carol" NIL "carol" "example.com")) ` +
+		`NIL NIL NIL "<m1@example.com>")`
+	if got != want {
+		t.Errorf("envelope() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAddressList_EncodesNonASCII(t *testing.T) {
+	got := addressList("=?utf-8?q?Ren=C3=A9?= <rene@example.com>")
+	if !strings.Contains(got, `"=?utf-8?q?Ren=C3=A9?="`) {
+		t.Errorf("addressList() = %s, want encoded name", got)
+	}
+	if addressList("not an address") != "NIL" {
+		t.Error("expected NIL for an unparseable address")
+	}
+}
+
+func TestBodyStructure(t *testing.T) {
+	root := parseMimePart([]byte(testMultipartRaw), "text/plain")
+	got := bodyStructure(root)
+
+	for _, want := range []string{
+		`(("TEXT" "PLAIN" ("CHARSET" "utf-8") NIL NIL "7BIT" 25 1)`,
+		`("MESSAGE" "RFC822" NIL NIL NIL "7BIT" 53 (NIL "Inner" ((NIL NIL "carol" "example.com"))`,
+		`"MIXED")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("bodyStructure() = %s\nmissing %s", got, want)
+		}
+	}
+
+	single := bodyStructure(parseMimePart([]byte("Subject: x\r\n\r\nhi\r\n"), "text/plain"))
+	if single != `("TEXT" "PLAIN" ("CHARSET" "us-ascii") NIL NIL "7BIT" 4 1)` {
+		t.Errorf("single-part bodyStructure() = %s", single)
+	}
+}
+
+func TestImapString(t *testing.T) {
+	tests := map[string]string{
+		"":          "NIL",
+		"plain":     `"plain"`,
+		`say "hi"\`: `"say \"hi\"\\"`,
+		"two\nline": "{8}\r\ntwo\nline",
+		"café":      "{5}\r\ncafé",
+	}
+	for in, want := range tests {
+		if got := imapString(in); got != want {
+			t.Errorf("imapString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormatInternalDate(t *testing.T) {
+	d := time.Date(2024, 4, 1, 9, 5, 0, 0, time.FixedZone("", -5*3600))
+	if got := formatInternalDate(d); got != `"01-Apr-2024 09:05:00 -0500"` {
+		t.Errorf("formatInternalDate() = %s", got)
+	}
+}
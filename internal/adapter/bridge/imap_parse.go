@@ -0,0 +1,304 @@
+package bridge
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLiteralSize bounds client literals; the bridge only accepts small
+// arguments such as passwords and mailbox names.
+const maxLiteralSize = 64 * 1024
+
+// errBadSyntax is returned for malformed client commands.
+var errBadSyntax = errors.New("syntax error")
+
+// imapList is a parenthesized list of arguments. Elements are either
+// strings (atoms, quoted strings, literals) or nested imapLists.
+type imapList []any
+
+// imapCommand is a parsed client command.
+type imapCommand struct {
+	Tag  string
+	Name string
+	Args imapList
+}
+
+// readCommandLine reads one client command, including any literals it
+// carries. Synchronizing literals are acknowledged with a continuation
+// request written to w.
+func readCommandLine(r *bufio.Reader, w *bufio.Writer) (string, error) {
+	var sb strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(line)
+
+		trimmed := strings.TrimRight(line, "\r\n")
+		size, nonSync, ok := trailingLiteral(trimmed)
+		if !ok {
+			return strings.TrimRight(sb.String(), "\r\n"), nil
+		}
+		if size > maxLiteralSize {
+			return "", fmt.Errorf("literal of %d bytes exceeds limit", size)
+		}
+		if !nonSync {
+			if _, err := w.WriteString("+ Ready for literal data\r\n"); err != nil {
+				return "", err
+			}
+			if err := w.Flush(); err != nil {
+				return "", err
+			}
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		sb.Write(buf)
+	}
+}
+
+// trailingLiteral reports whether line ends with a literal marker {n} or {n+}.
+func trailingLiteral(line string) (size int, nonSync bool, ok bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false, false
+	}
+	open := strings.LastIndexByte(line, '{')
+	if open < 0 {
+		return 0, false, false
+	}
+	num := line[open+1 : len(line)-1]
+	if strings.HasSuffix(num, "+") {
+		nonSync = true
+		num = strings.TrimSuffix(num, "+")
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return 0, false, false
+	}
+	return n, nonSync, true
+}
+
+// parseCommand parses a command line into tag, name and arguments. The
+// UID prefix is folded into the name, e.g. "UID FETCH".
+func parseCommand(line string) (*imapCommand, error) {
+	p := &argParser{s: line}
+	args, err := p.parseList(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < 2 {
+		return nil, errBadSyntax
+	}
+	tag, ok1 := args[0].(string)
+	name, ok2 := args[1].(string)
+	if !ok1 || !ok2 || tag == "" {
+		return nil, errBadSyntax
+	}
+
+	cmd := &imapCommand{Tag: tag, Name: strings.ToUpper(name), Args: args[2:]}
+	if cmd.Name == "UID" {
+		if len(cmd.Args) == 0 {
+			return nil, errBadSyntax
+		}
+		sub, ok := cmd.Args[0].(string)
+		if !ok {
+			return nil, errBadSyntax
+		}
+		cmd.Name = "UID " + strings.ToUpper(sub)
+		cmd.Args = cmd.Args[1:]
+	}
+	return cmd, nil
+}
+
+// argParser tokenizes IMAP arguments.
+type argParser struct {
+	s   string
+	pos int
+}
+
+// parseList parses arguments until the end of input or, when nested, a
+// closing parenthesis.
+func (p *argParser) parseList(nested bool) (imapList, error) {
+	var list imapList
+	for {
+		for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+			p.pos++
+		}
+		if p.pos >= len(p.s) {
+			if nested {
+				return nil, errBadSyntax
+			}
+			return list, nil
+		}
+
+		switch c := p.s[p.pos]; c {
+		case ')':
+			if !nested {
+				return nil, errBadSyntax
+			}
+			p.pos++
+			return list, nil
+		case '(':
+			p.pos++
+			sub, err := p.parseList(true)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, sub)
+		case '"':
+			str, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, str)
+		case '{':
+			str, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, str)
+		default:
+			list = append(list, p.parseAtom())
+		}
+	}
+}
+
+// parseQuoted parses a quoted string with backslash escapes.
+func (p *argParser) parseQuoted() (string, error) {
+	var sb strings.Builder
+	p.pos++ // opening quote
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '\\':
+			if p.pos >= len(p.s) {
+				return "", errBadSyntax
+			}
+			sb.WriteByte(p.s[p.pos])
+			p.pos++
+		case '"':
+			return sb.String(), nil
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", errBadSyntax
+}
+
+// parseLiteral parses {n}CRLF followed by n bytes.
+func (p *argParser) parseLiteral() (string, error) {
+	end := strings.IndexByte(p.s[p.pos:], '}')
+	if end < 0 {
+		return "", errBadSyntax
+	}
+	num := strings.TrimSuffix(p.s[p.pos+1:p.pos+end], "+")
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return "", errBadSyntax
+	}
+	p.pos += end + 1
+	if strings.HasPrefix(p.s[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if strings.HasPrefix(p.s[p.pos:], "\n") {
+		p.pos++
+	}
+	if p.pos+n > len(p.s) {
+		return "", errBadSyntax
+	}
+	str := p.s[p.pos : p.pos+n]
+	p.pos += n
+	return str, nil
+}
+
+// parseAtom parses an atom. Bracketed sections are kept whole so that
+// fetch items such as BODY[HEADER.FIELDS (FROM TO)]<0.100> form one atom.
+func (p *argParser) parseAtom() string {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if depth == 0 && (c == ' ' || c == '(' || c == ')' || c == '"') {
+			break
+		}
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// seqRange is an inclusive range in a sequence set. Zero stands for "*".
+type seqRange struct {
+	Start, Stop uint32
+}
+
+// seqSet is a parsed IMAP sequence set such as "1:3,7,10:*".
+type seqSet []seqRange
+
+// parseSeqSet parses a sequence set.
+func parseSeqSet(s string) (seqSet, error) {
+	if s == "" {
+		return nil, errBadSyntax
+	}
+	var set seqSet
+	for _, part := range strings.Split(s, ",") {
+		lo, hi, isRange := strings.Cut(part, ":")
+		start, err := parseSeqNumber(lo)
+		if err != nil {
+			return nil, err
+		}
+		stop := start
+		if isRange {
+			if stop, err = parseSeqNumber(hi); err != nil {
+				return nil, err
+			}
+		}
+		set = append(set, seqRange{Start: start, Stop: stop})
+	}
+	return set, nil
+}
+
+// parseSeqNumber parses a non-zero number or "*" (returned as 0).
+func parseSeqNumber(s string) (uint32, error) {
+	if s == "*" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || n == 0 {
+		return 0, errBadSyntax
+	}
+	return uint32(n), nil
+}
+
+// Contains reports whether n is in the set, with "*" standing for max.
+func (s seqSet) Contains(n, max uint32) bool {
+	for _, r := range s {
+		start, stop := r.Start, r.Stop
+		if start == 0 {
+			start = max
+		}
+		if stop == 0 {
+			stop = max
+		}
+		if start > stop {
+			start, stop = stop, start
+		}
+		if n >= start && n <= stop {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line     string
+		wantName string
+		wantArgs imapList
+	}{
+		{`a1 CAPABILITY`, "CAPABILITY", imapList{}},
+		{`a2 login me@example.com "pa ss\"word"`, "LOGIN", imapList{"me@example.com", `pa ss"word`}},
+		{`a3 LIST "" "*"`, "LIST", imapList{"", "*"}},
+		{`a4 STATUS INBOX (MESSAGES UNSEEN)`, "STATUS", imapList{"INBOX", imapList{"MESSAGES", "UNSEEN"}}},
+		{
+			`a5 UID fetch 1:* (UID BODY.PEEK[HEADER.FIELDS (FROM TO)]<0.100>)`,
+			"UID FETCH",
+			imapList{"1:*", imapList{"UID", "BODY.PEEK[HEADER.FIELDS (FROM TO)]<0.100>"}},
+		},
+		{"a6 LOGIN {2}\r\nme {4+}\r\npass", "LOGIN", imapList{"me", "pass"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			cmd, err := parseCommand(tt.line)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cmd.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", cmd.Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+				t.Errorf("Args = %#v, want %#v", cmd.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseCommand_Errors(t *testing.T) {
+	for _, line := range []string{`a1`, `a1 LIST ("x"`, `a1 LOGIN "unterminated`, `a1 UID`, `a1 X {10}` + "\r\nshort"} {
+		if _, err := parseCommand(line); err == nil {
+			t.Errorf("parseCommand(%q) expected error", line)
+		}
+	}
+}
+
+func TestReadCommandLine_Literals(t *testing.T) {
+	in := "a1 LOGIN {2}\r\nme {4}\r\npass\r\na2 NOOP\r\n"
+	r := bufio.NewReader(strings.NewReader(in))
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+
+	line, err := readCommandLine(r, w)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line != "a1 LOGIN {2}\r\nme {4}\r\npass" {
+		t.Errorf("line = %q", line)
+	}
+	if strings.Count(out.String(), "+ Ready") != 2 {
+		t.Errorf("expected two continuation requests, got %q", out.String())
+	}
+
+	line, err = readCommandLine(r, w)
+	if err != nil || line != "a2 NOOP" {
+		t.Errorf("second line = %q, %v", line, err)
+	}
+}
+
+func TestReadCommandLine_LiteralTooLarge(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a1 LOGIN {999999}\r\n"))
+	if _, err := readCommandLine(r, bufio.NewWriter(&bytes.Buffer{})); err == nil {
+		t.Error("expected error for oversized literal")
+	}
+}
+
+func TestSeqSet(t *testing.T) {
+	set, err := parseSeqSet("1:3,7,10:*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for n, want := range map[uint32]bool{1: true, 3: true, 4: false, 7: true, 9: false, 10: true, 12: true} {
+		if got := set.Contains(n, 12); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", n, got, want)
+		}
+	}
+
+	// "*:5" is the same range as "5:*"
+	rev, _ := parseSeqSet("*:5")
+	if !rev.Contains(6, 8) || rev.Contains(4, 8) {
+		t.Error("expected reversed range to be normalized")
+	}
+
+	for _, bad := range []string{"", "0", "a:3", "1:"} {
+		if _, err := parseSeqSet(bad); err == nil {
+			t.Errorf("parseSeqSet(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// fakeMessageSource serves messages by label with paging.
+type fakeMessageSource struct {
+	Messages map[string][]*mail.Message // by label ID, newest first
+	Raw      map[string][]byte
+	RawCalls int
+	PageSize int
+}
+
+func (f *fakeMessageSource) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	all := f.Messages[opts.LabelIDs[0]]
+	start := 0
+	if opts.PageToken != "" {
+		_, _ = fmt.Sscanf(opts.PageToken, "%d", &start)
+	}
+	size := opts.MaxResults
+	if f.PageSize > 0 && f.PageSize < size {
+		size = f.PageSize
+	}
+	end := start + size
+	if end > len(all) {
+		end = len(all)
+	}
+	result := &mail.ListResult[*mail.Message]{Items: all[start:end]}
+	if end < len(all) {
+		result.NextPageToken = fmt.Sprint(end)
+	}
+	return result, nil
+}
+
+func (f *fakeMessageSource) GetRaw(ctx context.Context, id string) ([]byte, error) {
+	f.RawCalls++
+	data, ok := f.Raw[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+// fakeLabelSource returns a fixed label list.
+type fakeLabelSource struct {
+	Labels []*mail.Label
+	Err    error
+}
+
+func (f *fakeLabelSource) List(ctx context.Context) ([]*mail.Label, error) {
+	return f.Labels, f.Err
+}
+
+// imapTestClient drives a bridge connection line by line.
+type imapTestClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+	n    int
+}
+
+// startTestServer serves a bridge on a loopback port and returns a client
+// that has read the greeting.
+func startTestServer(t *testing.T, src *fakeMessageSource, cfg IMAPConfig) *imapTestClient {
+	t.Helper()
+
+	labels := &fakeLabelSource{Labels: []*mail.Label{
+		mail.NewSystemLabel("INBOX", "INBOX"),
+		mail.NewSystemLabel("SENT", "SENT"),
+		mail.NewSystemLabel("CATEGORY_SOCIAL", "CATEGORY_SOCIAL"),
+		mail.NewLabel("Label_1", "Work"),
+		mail.NewLabel("Label_2", "Work/Projects"),
+	}}
+	if cfg.Username == "" {
+		cfg.Username, cfg.Password = "me@example.com", "secret"
+	}
+	server := NewIMAPServer(src, labels, cfg)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, ln) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		_ = conn.Close()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Serve() returned error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("Serve() did not stop")
+		}
+	})
+
+	c := &imapTestClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+	if greeting := c.readLine(); !strings.HasPrefix(greeting, "* OK") {
+		t.Fatalf("unexpected greeting: %q", greeting)
+	}
+	return c
+}
+
+func (c *imapTestClient) readLine() string {
+	c.t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatalf("failed to read response: %v", err)
+	}
+	return line
+}
+
+// do sends a command and returns everything up to and including the
+// tagged completion line, with literals inlined.
+func (c *imapTestClient) do(command string) string {
+	c.t.Helper()
+	c.n++
+	tag := fmt.Sprintf("t%d", c.n)
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		c.t.Fatalf("failed to send: %v", err)
+	}
+
+	var sb strings.Builder
+	for {
+		line := c.readLine()
+		sb.WriteString(line)
+		if size, _, ok := trailingLiteral(strings.TrimRight(line, "\r\n")); ok {
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(c.r, buf); err != nil {
+				c.t.Fatalf("failed to read literal: %v", err)
+			}
+			sb.Write(buf)
+			continue
+		}
+		if strings.HasPrefix(line, tag+" ") {
+			return sb.String()
+		}
+	}
+}
+
+func (c *imapTestClient) login() {
+	c.t.Helper()
+	if resp := c.do(`LOGIN me@example.com "secret"`); !strings.Contains(resp, "OK") {
+		c.t.Fatalf("login failed: %s", resp)
+	}
+}
+
+// testSource returns three inbox messages (newest first) with raw bodies.
+func testSource() *fakeMessageSource {
+	base := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	m1 := &mail.Message{ID: "a1", Subject: "First", Date: base, IsRead: true}
+	m2 := &mail.Message{ID: "b2", Subject: "Second", Date: base.Add(time.Hour), IsStarred: true}
+	m3 := &mail.Message{ID: "c3", Subject: "Third", Date: base.Add(48 * time.Hour)}
+
+	raw := func(subject string) []byte {
+		return []byte("From: Alice <alice@example.com>\nSubject: " + subject + "\n\nbody of " + subject + "\n")
+	}
+	return &fakeMessageSource{
+		Messages: map[string][]*mail.Message{
+			"INBOX":   {m3, m2, m1},
+			"Label_1": {m2},
+		},
+		Raw: map[string][]byte{"a1": raw("First"), "b2": raw("Second"), "c3": raw("Third")},
+	}
+}
+
+func TestIMAPServer_LoginRequired(t *testing.T) {
+	c := startTestServer(t, testSource(), IMAPConfig{})
+
+	if resp := c.do("CAPABILITY"); !strings.Contains(resp, "IMAP4rev1") {
+		t.Errorf("unexpected CAPABILITY: %s", resp)
+	}
+	if resp := c.do("SELECT INBOX"); !strings.Contains(resp, "NO Not authenticated") {
+		t.Errorf("expected SELECT to require login, got %s", resp)
+	}
+	if resp := c.do("LOGIN me@example.com wrong"); !strings.Contains(resp, "NO [AUTHENTICATIONFAILED]") {
+		t.Errorf("expected bad password to fail, got %s", resp)
+	}
+	if resp := c.do("LOGIN ME@example.com secret"); !strings.Contains(resp, "OK") {
+		t.Errorf("expected case-insensitive username, got %s", resp)
+	}
+	if resp := c.do("LOGOUT"); !strings.Contains(resp, "* BYE") {
+		t.Errorf("unexpected LOGOUT: %s", resp)
+	}
+}
+
+func TestIMAPServer_List(t *testing.T) {
+	c := startTestServer(t, testSource(), IMAPConfig{})
+	c.login()
+
+	resp := c.do(`LIST "" "*"`)
+	for _, want := range []string{
+		`* LIST () "/" "INBOX"`,
+		`* LIST (\Sent) "/" "Sent"`,
+		`* LIST () "/" "Work"`,
+		`* LIST () "/" "Work/Projects"`,
+	} {
+		if !strings.Contains(resp, want) {
+			t.Errorf("expected %q in:\n%s", want, resp)
+		}
+	}
+	if strings.Contains(resp, "CATEGORY_SOCIAL") {
+		t.Error("expected category labels to be hidden")
+	}
+
+	resp = c.do(`LIST "" "%"`)
+	if strings.Contains(resp, "Work/Projects") || !strings.Contains(resp, `"Work"`) {
+		t.Errorf("expected %% not to cross the delimiter:\n%s", resp)
+	}
+
+	resp = c.do(`LIST "" ""`)
+	if !strings.Contains(resp, `(\Noselect) "/" ""`) {
+		t.Errorf("expected delimiter response, got %s", resp)
+	}
+}
+
+func TestIMAPServer_SelectAndFetch(t *testing.T) {
+	src := testSource()
+	c := startTestServer(t, src, IMAPConfig{})
+	c.login()
+
+	resp := c.do("SELECT INBOX")
+	for _, want := range []string{"* 3 EXISTS", "[UNSEEN 2]", "[UIDNEXT 4]", "OK [READ-ONLY]"} {
+		if !strings.Contains(resp, want) {
+			t.Errorf("expected %q in:\n%s", want, resp)
+		}
+	}
+
+	resp = c.do("FETCH 1:* (UID FLAGS INTERNALDATE)")
+	for _, want := range []string{
+		`* 1 FETCH (UID 1 FLAGS (\Seen) INTERNALDATE "01-Apr-2024 10:00:00 +0000")`,
+		`* 2 FETCH (UID 2 FLAGS (\Flagged)`,
+		`* 3 FETCH (UID 3 FLAGS ()`,
+	} {
+		if !strings.Contains(resp, want) {
+			t.Errorf("expected %q in:\n%s", want, resp)
+		}
+	}
+	if src.RawCalls != 0 {
+		t.Errorf("expected no raw downloads for metadata, got %d", src.RawCalls)
+	}
+
+	resp = c.do("UID FETCH 3 (BODY.PEEK[HEADER.FIELDS (SUBJECT)] RFC822.SIZE)")
+	if !strings.Contains(resp, "* 3 FETCH (UID 3 BODY[HEADER.FIELDS (SUBJECT)] {18}\r\nSubject: Third\r\n\r\n RFC822.SIZE 66)") {
+		t.Errorf("unexpected UID FETCH:\n%q", resp)
+	}
+
+	resp = c.do("FETCH 3 BODY[TEXT]")
+	if !strings.Contains(resp, "BODY[TEXT] {15}\r\nbody of Third\r\n)") {
+		t.Errorf("unexpected body fetch:\n%q", resp)
+	}
+	if src.RawCalls != 1 {
+		t.Errorf("expected raw message to be cached, got %d downloads", src.RawCalls)
+	}
+
+	if resp := c.do("FETCH 1 ENVELOPE"); !strings.Contains(resp, `ENVELOPE (NIL "First" (("Alice" NIL "alice" "example.com"))`) {
+		t.Errorf("unexpected ENVELOPE:\n%s", resp)
+	}
+	if resp := c.do("FETCH 1 (X-GM-MSGID)"); !strings.Contains(resp, "BAD Unsupported fetch item") {
+		t.Errorf("expected unsupported item to be rejected, got %s", resp)
+	}
+}
+
+func TestIMAPServer_UIDsStableAcrossSelects(t *testing.T) {
+	src := testSource()
+	c := startTestServer(t, src, IMAPConfig{})
+	c.login()
+
+	c.do("SELECT INBOX")
+	first := c.do("UID SEARCH ALL")
+
+	// A new message arrives and gets the next UID
+	m4 := &mail.Message{ID: "d4", Subject: "Fourth", Date: time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)}
+	src.Messages["INBOX"] = append([]*mail.Message{m4}, src.Messages["INBOX"]...)
+
+	c.do("SELECT INBOX")
+	second := c.do("UID SEARCH ALL")
+
+	if !strings.Contains(first, "* SEARCH 1 2 3\r\n") || !strings.Contains(second, "* SEARCH 1 2 3 4\r\n") {
+		t.Errorf("unexpected UIDs:\n%s\n%s", first, second)
+	}
+}
+
+func TestIMAPServer_Search(t *testing.T) {
+	c := startTestServer(t, testSource(), IMAPConfig{})
+	c.login()
+	c.do("EXAMINE INBOX")
+
+	tests := map[string]string{
+		"SEARCH UNSEEN":                    "* SEARCH 2 3\r\n",
+		"SEARCH FLAGGED":                   "* SEARCH 2\r\n",
+		"SEARCH SINCE 2-Apr-2024":          "* SEARCH 3\r\n",
+		"SEARCH BEFORE 2-Apr-2024 UNSEEN":  "* SEARCH 2\r\n",
+		"SEARCH 2:*":                       "* SEARCH 2 3\r\n",
+		"UID SEARCH UID 1,3":               "* SEARCH 1 3\r\n",
+		"SEARCH CHARSET UTF-8 (SEEN)":      "* SEARCH 1\r\n",
+		"SEARCH DELETED":                   "* SEARCH\r\n",
+		`SEARCH SUBJECT "Third"`:           "NO [CANNOT]",
+		"SEARCH SINCE yesterday":           "NO [CANNOT]",
+		"SEARCH ON 01-Apr-2024 NOT-A-FLAG": "NO [CANNOT]",
+	}
+	for command, want := range tests {
+		if resp := c.do(command); !strings.Contains(resp, want) {
+			t.Errorf("%s: expected %q in %q", command, want, resp)
+		}
+	}
+}
+
+func TestIMAPServer_ReadOnly(t *testing.T) {
+	c := startTestServer(t, testSource(), IMAPConfig{})
+	c.login()
+	c.do("SELECT INBOX")
+
+	for _, command := range []string{`STORE 1 +FLAGS (\Seen)`, "UID COPY 1 Work", "EXPUNGE", "CREATE Foo", `APPEND INBOX "x"`} {
+		if resp := c.do(command); !strings.Contains(resp, "NO [CANNOT]") {
+			t.Errorf("%s: expected read-only rejection, got %s", command, resp)
+		}
+	}
+}
+
+func TestIMAPServer_StatusAndMaxMessages(t *testing.T) {
+	src := testSource()
+	src.PageSize = 1
+	c := startTestServer(t, src, IMAPConfig{MaxMessages: 2})
+	c.login()
+
+	resp := c.do("STATUS INBOX (MESSAGES UNSEEN UIDNEXT)")
+	if !strings.Contains(resp, `* STATUS "INBOX" (MESSAGES 2 UNSEEN 2 UIDNEXT 3)`) {
+		t.Errorf("unexpected STATUS:\n%s", resp)
+	}
+	if resp := c.do("STATUS Nope (MESSAGES)"); !strings.Contains(resp, "NO [NONEXISTENT]") {
+		t.Errorf("expected missing mailbox error, got %s", resp)
+	}
+
+	c.do("SELECT Work")
+	if resp := c.do("FETCH 1 (FLAGS)"); !strings.Contains(resp, `* 1 FETCH (FLAGS (\Flagged))`) {
+		t.Errorf("unexpected FETCH in label mailbox:\n%s", resp)
+	}
+	if resp := c.do("CLOSE"); !strings.Contains(resp, "OK CLOSE completed") {
+		t.Errorf("unexpected CLOSE: %s", resp)
+	}
+	if resp := c.do("FETCH 1 (FLAGS)"); !strings.Contains(resp, "NO No mailbox selected") {
+		t.Errorf("expected FETCH without mailbox to fail, got %s", resp)
+	}
+}
+
+func TestIMAPServer_LabelError(t *testing.T) {
+	server := NewIMAPServer(testSource(), &fakeLabelSource{Err: errors.New("boom")}, IMAPConfig{})
+	if _, err := server.mailboxes(context.Background()); err == nil || !strings.Contains(err.Error(), "failed to list labels") {
+		t.Errorf("expected label error, got %v", err)
+	}
+}
+
+func TestNormalizeCRLF(t *testing.T) {
+	if got := string(normalizeCRLF([]byte("a\nb\r\nc\n"))); got != "a\r\nb\r\nc\r\n" {
+		t.Errorf("normalizeCRLF() = %q", got)
+	}
+}
+
+func TestRawCache_Evicts(t *testing.T) {
+	c := newRawCache(10)
+	c.put("a", []byte("12345"))
+	c.put("b", []byte("12345"))
+	c.put("c", []byte("123"))
+	c.put("huge", []byte("12345678901"))
+
+	if _, ok := c.get("a"); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := c.get(id); !ok {
+			t.Errorf("expected %s to be cached", id)
+		}
+	}
+	if _, ok := c.get("huge"); ok {
+		t.Error("expected entries larger than the limit not to be cached")
+	}
+}
@@ -3,12 +3,16 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"text/tabwriter"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
 	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
 )
 
@@ -58,9 +62,16 @@ var accountRemoveCmd = &cobra.Command{
 	Short: "Remove a Google account",
 	Long: `Remove a Google account.
 
-This removes the account configuration and deletes the
-stored credentials from the keyring.`,
-	Example: `  goog account remove work`,
+This revokes the account's OAuth token at Google, so goog loses
+access to the account, deletes every keyring entry stored for it,
+and removes it from the configuration. What was cleaned up is
+reported. If the token cannot be revoked (for example when offline),
+the local cleanup still happens and a warning says so.`,
+	Example: `  # Remove an account and revoke its access
+  goog account remove work
+
+  # Remove it locally without contacting Google
+  goog account remove work --no-revoke`,
 	Aliases: []string{"rm", "delete"},
 	Args:    cobra.ExactArgs(1),
 	RunE:    runAccountRemove,
@@ -68,14 +79,23 @@ stored credentials from the keyring.`,
 
 // accountSwitchCmd switches the default account.
 var accountSwitchCmd = &cobra.Command{
-	Use:   "switch <alias>",
+	Use:   "switch [alias]",
 	Short: "Set the default account",
 	Long: `Set the default Google account.
 
-The default account is used when no --account flag is specified.`,
-	Example: `  goog account switch personal`,
+The default account is used when no --account flag is specified.
+Without an alias, the configured accounts are listed with their email
+and when they were last used, and you choose one by number or alias.
+
+After switching, the account's token is checked (and refreshed if it
+has expired) so a missing or revoked sign-in shows up straight away.`,
+	Example: `  # Choose from the configured accounts
+  goog account switch
+
+  # Switch directly
+  goog account switch personal`,
 	Aliases: []string{"use", "default"},
-	Args:    cobra.ExactArgs(1),
+	Args:    cobra.MaximumNArgs(1),
 	RunE:    runAccountSwitch,
 }
 
@@ -105,7 +125,10 @@ preserving all credentials and configuration.`,
 	RunE:    runAccountRename,
 }
 
-var accountAddScopes []string
+var (
+	accountAddScopes      []string
+	accountRemoveNoRevoke bool
+)
 
 func init() {
 	// Add account subcommands
@@ -118,6 +141,7 @@ func init() {
 
 	// Add flags
 	accountAddCmd.Flags().StringSliceVar(&accountAddScopes, "scopes", nil, "OAuth scopes to request")
+	accountRemoveCmd.Flags().BoolVar(&accountRemoveNoRevoke, "no-revoke", false, "do not revoke the token at Google")
 
 	// Add to root
 	rootCmd.AddCommand(accountCmd)
@@ -194,22 +218,72 @@ func runAccountRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("account not found: %s", alias)
 	}
 
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	// Remove account
-	if err := svc.Remove(alias); err != nil {
+	report, err := svc.RemoveAccount(ctx, alias, !accountRemoveNoRevoke)
+	if err != nil {
 		return fmt.Errorf("failed to remove account: %w", err)
 	}
 
 	cmd.Printf("Successfully removed account '%s' (%s)\n", acc.Alias, acc.Email)
+	printRemovalReport(cmd, report, !accountRemoveNoRevoke)
 	return nil
 }
 
+// printRemovalReport lists what removing an account cleaned up.
+func printRemovalReport(cmd *cobra.Command, report *accountuc.RemovalReport, revoke bool) {
+	switch {
+	case !revoke:
+		cmd.Println("  Token not revoked (--no-revoke)")
+	case report.Revoked:
+		cmd.Println("  Revoked the OAuth token at Google")
+	case errors.Is(report.RevokeErr, auth.ErrTokenNotFound):
+		cmd.Println("  No stored token to revoke")
+	case errors.Is(report.RevokeErr, auth.ErrTokenAlreadyInvalid):
+		cmd.Println("  Token was already expired or revoked at Google")
+	default:
+		output.Warnf(cmd, "could not revoke the token at Google: %v", report.RevokeErr)
+		cmd.PrintErrln("Remove goog's access at https://myaccount.google.com/permissions")
+	}
+
+	if len(report.DeletedKeys) > 0 {
+		cmd.Printf("  Deleted %d keyring entries: %s\n", len(report.DeletedKeys), strings.Join(report.DeletedKeys, ", "))
+	} else {
+		cmd.Println("  No keyring entries to delete")
+	}
+	if report.UsageForgotten {
+		cmd.Println("  Forgot the last-used time")
+	}
+	cmd.Println("  Removed from the config")
+	if report.WasDefault {
+		if report.NewDefault != "" {
+			cmd.Printf("  Default account is now '%s'\n", report.NewDefault)
+		} else {
+			cmd.Println("  No default account is left")
+		}
+	}
+}
+
 // runAccountSwitch handles the account switch command.
 func runAccountSwitch(cmd *cobra.Command, args []string) error {
-	alias := args[0]
-
 	// Get account service using dependency injection
 	svc := getAccountServiceFromDeps()
 
+	var alias string
+	if len(args) > 0 {
+		alias = args[0]
+	} else {
+		chosen, err := chooseAccount(cmd, svc)
+		if err != nil {
+			return err
+		}
+		alias = chosen
+	}
+
 	// Switch account
 	if err := svc.Switch(alias); err != nil {
 		return fmt.Errorf("failed to switch account: %w", err)
@@ -224,6 +298,84 @@ func runAccountSwitch(cmd *cobra.Command, args []string) error {
 	}
 	cmd.Println()
 
+	if err := verifyAccountToken(cmd.Context(), svc, alias); err != nil {
+		output.Warnf(cmd, "%v; run 'goog auth login --account %s' to sign in again", err, alias)
+	} else if !quietFlag {
+		cmd.Println("Token is valid.")
+	}
+	return nil
+}
+
+// chooseAccount lists the configured accounts and asks which one to switch
+// to, by number or alias. The default account is the default answer.
+func chooseAccount(cmd *cobra.Command, svc AccountService) (string, error) {
+	accounts, err := svc.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return "", fmt.Errorf("no accounts configured; run 'goog auth login' or 'goog account add' to add one")
+	}
+
+	locale := presenter.CurrentLocale()
+	current := ""
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "#\tALIAS\tEMAIL\tLAST USED\t")
+	for i, acc := range accounts {
+		lastUsed := "never"
+		if !acc.LastUsed.IsZero() {
+			lastUsed = locale.DateTime(acc.LastUsed.Local())
+		}
+		marker := ""
+		if acc.IsDefault {
+			marker = "(default)"
+			current = acc.Alias
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, acc.Alias, acc.Email, lastUsed, marker)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	p := newPrompter(cmd)
+	for {
+		answer, err := p.ask("Switch to account", current)
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(accounts) {
+			return accounts[n-1].Alias, nil
+		}
+		for _, acc := range accounts {
+			if answer == acc.Alias {
+				return acc.Alias, nil
+			}
+		}
+		cmd.Printf("Please enter a number from 1 to %d or an account alias\n", len(accounts))
+	}
+}
+
+// verifyAccountToken checks that alias has a token that can be used,
+// refreshing it if it has expired.
+func verifyAccountToken(ctx context.Context, svc AccountService, alias string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tokenMgr := svc.GetTokenManager()
+	if tokenMgr == nil {
+		return fmt.Errorf("cannot check the token")
+	}
+	ts, err := tokenMgr.GetTokenSource(ctx, alias)
+	if err != nil {
+		return fmt.Errorf("no token found")
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("token is not valid: %w", err)
+	}
+	if !token.Valid() {
+		return fmt.Errorf("token is not valid")
+	}
 	return nil
 }
 
@@ -285,18 +437,23 @@ func runAccountRename(cmd *cobra.Command, args []string) error {
 
 // outputAccountsTable outputs accounts in table format.
 func outputAccountsTable(cmd *cobra.Command, accounts []*accountuc.Account) error {
-	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "ALIAS\tEMAIL\tDEFAULT\tADDED")
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "ALIAS\tEMAIL\tDEFAULT\tADDED\tLAST USED")
 	for _, acc := range accounts {
 		defaultStr := ""
 		if acc.IsDefault {
 			defaultStr = "*"
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		lastUsed := "never"
+		if !acc.LastUsed.IsZero() {
+			lastUsed = presenter.CurrentLocale().DateTime(acc.LastUsed.Local())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			acc.Alias,
 			acc.Email,
 			defaultStr,
-			acc.Added.Format("2006-01-02"),
+			presenter.CurrentLocale().Date(acc.Added),
+			lastUsed,
 		)
 	}
 	return w.Flush()
@@ -321,6 +478,7 @@ func outputAccountsJSON(cmd *cobra.Command, accounts []*accountuc.Account) error
 		Email     string   `json:"email"`
 		IsDefault bool     `json:"is_default"`
 		Added     string   `json:"added"`
+		LastUsed  string   `json:"last_used,omitempty"`
 		Scopes    []string `json:"scopes,omitempty"`
 	}
 
@@ -333,6 +491,9 @@ func outputAccountsJSON(cmd *cobra.Command, accounts []*accountuc.Account) error
 			Added:     acc.Added.Format(time.RFC3339),
 			Scopes:    acc.Scopes,
 		}
+		if !acc.LastUsed.IsZero() {
+			result[i].LastUsed = acc.LastUsed.Format(time.RFC3339)
+		}
 	}
 
 	// Use standard library for JSON output
@@ -344,6 +505,9 @@ func outputAccountsJSON(cmd *cobra.Command, accounts []*accountuc.Account) error
 		fmt.Fprintf(encoder, "    \"email\": %q,\n", a.Email)
 		fmt.Fprintf(encoder, "    \"is_default\": %v,\n", a.IsDefault)
 		fmt.Fprintf(encoder, "    \"added\": %q", a.Added)
+		if a.LastUsed != "" {
+			fmt.Fprintf(encoder, ",\n    \"last_used\": %q", a.LastUsed)
+		}
 		if len(a.Scopes) > 0 {
 			fmt.Fprintf(encoder, ",\n    \"scopes\": [")
 			for j, s := range a.Scopes {
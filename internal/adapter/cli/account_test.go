@@ -4,11 +4,13 @@ package cli
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
 	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
 )
 
@@ -292,7 +294,7 @@ func TestAccountSwitchCmd_ArgsValidation(t *testing.T) {
 		{
 			name:      "no args",
 			args:      []string{},
-			expectErr: true,
+			expectErr: false,
 		},
 		{
 			name:      "one arg",
@@ -574,6 +576,15 @@ func (m *MockAccountServiceFull) Remove(alias string) error {
 	return m.RemoveErr
 }
 
+// RemoveAccount removes an account through Remove and reports a cleanup of
+// the token and scopes.
+func (m *MockAccountServiceFull) RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error) {
+	if err := m.Remove(alias); err != nil {
+		return nil, err
+	}
+	return &accountuc.RemovalReport{Alias: alias, Revoked: revoke, DeletedKeys: []string{"oauth_scopes", "oauth_token"}}, nil
+}
+
 func (m *MockAccountServiceFull) Switch(alias string) error {
 	if m.SwitchFunc != nil {
 		return m.SwitchFunc(alias)
@@ -787,6 +798,32 @@ func TestRunAccountRemove_Execution(t *testing.T) {
 		if !contains(output, "Successfully removed account") {
 			t.Error("expected success message")
 		}
+		for _, want := range []string{"Revoked the OAuth token", "Deleted 2 keyring entries: oauth_scopes, oauth_token"} {
+			if !contains(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("no revoke", func(t *testing.T) {
+		ResetDependencies()
+		defer ResetDependencies()
+		SetDependencies(&Dependencies{
+			AccountService: &MockAccountService{Account: &accountuc.Account{Alias: "work", Email: "work@company.com"}},
+			RepoFactory:    &MockRepositoryFactory{},
+		})
+		accountRemoveNoRevoke = true
+		defer func() { accountRemoveNoRevoke = false }()
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		if err := runAccountRemove(cmd, []string{"work"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !contains(buf.String(), "Token not revoked (--no-revoke)") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
 	})
 
 	t.Run("remove error", func(t *testing.T) {
@@ -817,6 +854,49 @@ func TestRunAccountRemove_Execution(t *testing.T) {
 	})
 }
 
+func TestPrintRemovalReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  accountuc.RemovalReport
+		wantOut []string
+		wantErr string
+	}{
+		{
+			name:    "already revoked",
+			report:  accountuc.RemovalReport{RevokeErr: auth.ErrTokenAlreadyInvalid, UsageForgotten: true},
+			wantOut: []string{"already expired or revoked", "No keyring entries to delete", "Forgot the last-used time"},
+		},
+		{
+			name:    "revoke failed",
+			report:  accountuc.RemovalReport{RevokeErr: errors.New("connection refused"), WasDefault: true, NewDefault: "personal"},
+			wantOut: []string{"Default account is now 'personal'"},
+			wantErr: "could not revoke the token at Google: connection refused",
+		},
+		{
+			name:    "last account",
+			report:  accountuc.RemovalReport{Revoked: true, WasDefault: true},
+			wantOut: []string{"No default account is left"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+			cmd.SetOut(out)
+			cmd.SetErr(errOut)
+			printRemovalReport(cmd, &tt.report, true)
+			for _, want := range tt.wantOut {
+				if !contains(out.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+				}
+			}
+			if tt.wantErr != "" && !contains(errOut.String(), tt.wantErr) {
+				t.Errorf("expected warning %q, got %q", tt.wantErr, errOut.String())
+			}
+		})
+	}
+}
+
 // TestRunAccountSwitch_Execution tests the runAccountSwitch function with mocks.
 func TestRunAccountSwitch_Execution(t *testing.T) {
 	t.Run("switch account successfully", func(t *testing.T) {
@@ -876,6 +956,124 @@ func TestRunAccountSwitch_Execution(t *testing.T) {
 	})
 }
 
+// TestRunAccountSwitch_Interactive tests choosing an account at the prompt.
+func TestRunAccountSwitch_Interactive(t *testing.T) {
+	accounts := []*accountuc.Account{
+		{Alias: "personal", Email: "me@gmail.com", IsDefault: true, LastUsed: time.Now().Add(-time.Hour)},
+		{Alias: "work", Email: "me@company.com"},
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		prompt string
+	}{
+		{name: "by number", input: "2\n", want: "work"},
+		{name: "by alias", input: "work\n", want: "work"},
+		{name: "default", input: "\n", want: "personal"},
+		{name: "retry after invalid answer", input: "3\nwork\n", want: "work", prompt: "Please enter a number from 1 to 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetDependencies()
+			defer ResetDependencies()
+
+			var switched string
+			SetDependencies(&Dependencies{
+				AccountService: &MockAccountService{
+					Accounts:   accounts,
+					SwitchFunc: func(alias string) error { switched = alias; return nil },
+				},
+				RepoFactory: &MockRepositoryFactory{},
+			})
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetIn(bytes.NewBufferString(tt.input))
+
+			if err := runAccountSwitch(cmd, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if switched != tt.want {
+				t.Errorf("switched to %q, want %q", switched, tt.want)
+			}
+			output := buf.String()
+			for _, want := range []string{"LAST USED", "me@company.com", "never", "(default)", "Switch to account [personal]", "Token is valid", tt.prompt} {
+				if !contains(output, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+		})
+	}
+
+	t.Run("no accounts", func(t *testing.T) {
+		ResetDependencies()
+		defer ResetDependencies()
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{}, RepoFactory: &MockRepositoryFactory{}})
+
+		if err := runAccountSwitch(&cobra.Command{}, nil); err == nil {
+			t.Error("expected an error without accounts")
+		}
+	})
+
+	t.Run("no answer", func(t *testing.T) {
+		ResetDependencies()
+		defer ResetDependencies()
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{Accounts: accounts}, RepoFactory: &MockRepositoryFactory{}})
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetIn(new(bytes.Buffer))
+		if err := runAccountSwitch(cmd, nil); err == nil {
+			t.Error("expected an error when the input ends")
+		}
+	})
+}
+
+// TestRunAccountSwitch_TokenCheck tests the token check after switching.
+func TestRunAccountSwitch_TokenCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenMgr    *MockTokenManager
+		wantWarning string
+	}{
+		{name: "missing token", tokenMgr: &MockTokenManager{Err: fmt.Errorf("token not found")}, wantWarning: "no token found"},
+		{name: "refresh fails", tokenMgr: &MockTokenManager{TokenSource: &MockTokenSource{err: fmt.Errorf("invalid_grant")}}, wantWarning: "invalid_grant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ResetDependencies()
+			defer ResetDependencies()
+			SetDependencies(&Dependencies{
+				AccountService: &MockAccountService{
+					Account:      &accountuc.Account{Alias: "work", Email: "work@company.com"},
+					TokenManager: tt.tokenMgr,
+				},
+				RepoFactory: &MockRepositoryFactory{},
+			})
+
+			cmd := &cobra.Command{}
+			out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+			cmd.SetOut(out)
+			cmd.SetErr(errOut)
+
+			if err := runAccountSwitch(cmd, []string{"work"}); err != nil {
+				t.Fatalf("a bad token should not fail the switch: %v", err)
+			}
+			if !contains(out.String(), "Switched to account 'work'") {
+				t.Errorf("expected the switch to be reported, got %q", out.String())
+			}
+			for _, want := range []string{tt.wantWarning, "goog auth login --account work"} {
+				if !contains(errOut.String(), want) {
+					t.Errorf("expected warning to contain %q, got %q", want, errOut.String())
+				}
+			}
+		})
+	}
+}
+
 // TestRunAccountShow_Execution tests the runAccountShow function with mocks.
 func TestRunAccountShow_Execution(t *testing.T) {
 	t.Run("show account successfully", func(t *testing.T) {
@@ -0,0 +1,336 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// aliasNamePattern matches valid alias names. Names are lower case because
+// the config file keys are case-insensitive.
+var aliasNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// aliasCmd represents the alias command group.
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage command aliases",
+	Long: `Manage user-defined command aliases.
+
+An alias is a name for a goog command line. Running 'goog <alias>'
+runs the command it stands for, followed by any further arguments, so
+flags given after the alias add to or override those in it.
+
+Aliases are stored under 'aliases' in the config file. They cannot
+replace built-in commands and are not expanded inside other aliases.`,
+	Example: `  # Define an alias
+  goog alias add inbox "mail list --labels INBOX --unread-only --max-results 50"
+
+  # Use it, adding a flag
+  goog inbox --format json
+
+  # List and remove aliases
+  goog alias list
+  goog alias remove inbox`,
+}
+
+// aliasAddCmd defines an alias.
+var aliasAddCmd = &cobra.Command{
+	Use:   "add <name> <command>",
+	Short: "Define an alias",
+	Long: `Define an alias for a goog command line, replacing any alias of the
+same name. Quote the command so it is passed as one argument; quotes
+inside it group words as in a shell.`,
+	Example: `  # Unread inbox messages
+  goog alias add inbox "mail list --labels INBOX --unread-only --max-results 50"
+
+  # This week's events on the team calendar
+  goog alias add team "cal week --calendar team@group.calendar.google.com"
+
+  # A search with a quoted query
+  goog alias add boss "mail search 'from:boss@example.com is:unread'"`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAliasAdd,
+}
+
+// aliasListCmd lists aliases.
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List aliases",
+	Long:    `List the defined aliases and the commands they expand to.`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runAliasList,
+}
+
+// aliasRemoveCmd removes an alias.
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Short:   "Remove an alias",
+	Long:    `Remove an alias from the config.`,
+	Aliases: []string{"rm", "delete"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runAliasRemove,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasAddCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+
+	rootCmd.AddCommand(aliasCmd)
+}
+
+// runAliasAdd handles the alias add command.
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	name, expansion := args[0], strings.TrimSpace(args[1])
+	if err := validateAlias(name, expansion); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.Aliases[name] = expansion
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if !quietFlag {
+		cmd.Printf("Alias %s added: goog %s\n", name, expansion)
+	}
+	return nil
+}
+
+// runAliasList handles the alias list command.
+func runAliasList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(cfg.Aliases, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode aliases: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(cfg.Aliases) == 0 {
+		if !quietFlag {
+			cmd.Println("No aliases defined.")
+		}
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "NAME\tCOMMAND")
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\tgoog %s\n", name, cfg.Aliases[name])
+	}
+	return w.Flush()
+}
+
+// runAliasRemove handles the alias remove command.
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if _, ok := cfg.Aliases[name]; !ok {
+		return fmt.Errorf("alias not found: %s", name)
+	}
+	delete(cfg.Aliases, name)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if !quietFlag {
+		cmd.Printf("Alias %s removed\n", name)
+	}
+	return nil
+}
+
+// validateAlias checks that name can be an alias and that expansion is a
+// goog command line.
+func validateAlias(name, expansion string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use lower-case letters, digits, - and _, starting with a letter", name)
+	}
+	if isBuiltinCommand(name) {
+		return fmt.Errorf("alias %q would replace the built-in command %s", name, name)
+	}
+	words, err := splitCommandLine(expansion)
+	if err != nil {
+		return fmt.Errorf("invalid alias command: %w", err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("alias command cannot be empty")
+	}
+	if words[0] == rootCmd.Name() {
+		return fmt.Errorf("leave out %q at the start of the alias command", rootCmd.Name())
+	}
+	if !isBuiltinCommand(words[0]) {
+		return fmt.Errorf("alias command must start with a goog command, not %q", words[0])
+	}
+	return nil
+}
+
+// isBuiltinCommand reports whether name is a top-level command or one of
+// its aliases.
+func isBuiltinCommand(name string) bool {
+	switch name {
+	case "help", "completion":
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAlias replaces a user-defined alias in args, the arguments after
+// the program name, with the command line it stands for. Global flags may
+// come before the alias; the arguments after it are kept after the
+// expansion. Built-in commands are never expanded.
+func expandAlias(args []string, aliases map[string]string) ([]string, error) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args, nil
+		}
+		if strings.HasPrefix(arg, "-") {
+			if takesValue(arg) {
+				i++
+			}
+			continue
+		}
+
+		expansion, ok := aliases[arg]
+		if !ok || isBuiltinCommand(arg) {
+			return args, nil
+		}
+		words, err := splitCommandLine(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %s: %w", arg, err)
+		}
+		expanded := make([]string, 0, len(args)+len(words))
+		expanded = append(expanded, args[:i]...)
+		expanded = append(expanded, words...)
+		return append(expanded, args[i+1:]...), nil
+	}
+	return args, nil
+}
+
+// takesValue reports whether arg is a global flag whose value is the next
+// argument, as in "--account work".
+func takesValue(arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	name := strings.TrimLeft(arg, "-")
+	f := rootCmd.PersistentFlags().Lookup(name)
+	if !strings.HasPrefix(arg, "--") {
+		f = nil
+		if len(name) == 1 {
+			f = rootCmd.PersistentFlags().ShorthandLookup(name)
+		}
+	}
+	return f != nil && f.NoOptDefVal == ""
+}
+
+// applyAliases expands a user-defined alias in the command-line arguments
+// before cobra parses them. Without a config file there are no aliases.
+func applyAliases() error {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil || len(cfg.Aliases) == 0 {
+		return nil
+	}
+	args, err := expandAlias(os.Args[1:], cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	rootCmd.SetArgs(args)
+	return nil
+}
+
+// splitCommandLine splits a command line into words like a POSIX shell,
+// without expansions: single quotes keep text as is, double quotes allow
+// backslash escapes, and a backslash outside quotes escapes the next
+// character.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,216 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// setupAliasTest points the config at a temporary file holding aliases.
+func setupAliasTest(t *testing.T, aliases map[string]string) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	for name, expansion := range aliases {
+		cfg.Aliases[name] = expansion
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	origFormat := formatFlag
+	formatFlag = "table"
+	t.Cleanup(func() { formatFlag = origFormat })
+}
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"inbox": "mail list --labels INBOX --unread-only --max-results 50",
+		"boss":  "mail search 'from:boss@example.com is:unread'",
+		"mail":  "cal today",
+	}
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "expands an alias",
+			args: []string{"inbox"},
+			want: []string{"mail", "list", "--labels", "INBOX", "--unread-only", "--max-results", "50"},
+		},
+		{
+			name: "keeps arguments after the alias",
+			args: []string{"inbox", "--max-results", "10", "--format", "json"},
+			want: []string{"mail", "list", "--labels", "INBOX", "--unread-only", "--max-results", "50", "--max-results", "10", "--format", "json"},
+		},
+		{
+			name: "skips global flags before the alias",
+			args: []string{"--account", "work", "--quiet", "boss"},
+			want: []string{"--account", "work", "--quiet", "mail", "search", "from:boss@example.com is:unread"},
+		},
+		{
+			name: "does not treat a flag value as an alias",
+			args: []string{"--account", "inbox", "cal", "today"},
+			want: []string{"--account", "inbox", "cal", "today"},
+		},
+		{
+			name: "never replaces built-in commands",
+			args: []string{"mail", "list"},
+			want: []string{"mail", "list"},
+		},
+		{
+			name: "leaves unknown commands alone",
+			args: []string{"unknown"},
+			want: []string{"unknown"},
+		},
+		{
+			name: "stops at --",
+			args: []string{"--", "inbox"},
+			want: []string{"--", "inbox"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAlias(tt.args, aliases)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandAlias(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("reports a broken alias", func(t *testing.T) {
+		if _, err := expandAlias([]string{"bad"}, map[string]string{"bad": "mail search 'unterminated"}); err == nil {
+			t.Error("expected an error for an unterminated quote")
+		}
+	})
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "mail list  --unread-only", want: []string{"mail", "list", "--unread-only"}},
+		{line: `mail search 'from:a b'`, want: []string{"mail", "search", "from:a b"}},
+		{line: `mail search "subject:\"q3\" report"`, want: []string{"mail", "search", `subject:"q3" report`}},
+		{line: `cal quick lunch\ at\ noon`, want: []string{"cal", "quick", "lunch at noon"}},
+		{line: `mail search ''`, want: []string{"mail", "search", ""}},
+		{line: "", want: nil},
+		{line: `mail search "open`, wantErr: true},
+		{line: `mail search \`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := splitCommandLine(tt.line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitCommandLine(%q) should fail", tt.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitCommandLine(%q) failed: %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		alias     string
+		expansion string
+		wantErr   string
+	}{
+		{name: "valid", alias: "inbox", expansion: "mail list --unread-only"},
+		{name: "upper case name", alias: "Inbox", expansion: "mail list", wantErr: "invalid alias name"},
+		{name: "dotted name", alias: "my.inbox", expansion: "mail list", wantErr: "invalid alias name"},
+		{name: "built-in command", alias: "mail", expansion: "mail list", wantErr: "built-in command"},
+		{name: "help command", alias: "help", expansion: "cal today", wantErr: "built-in command"},
+		{name: "empty command", alias: "inbox", expansion: "  ", wantErr: "cannot be empty"},
+		{name: "program name", alias: "inbox", expansion: "goog mail list", wantErr: "leave out"},
+		{name: "unknown command", alias: "inbox", expansion: "gmail list", wantErr: "must start with a goog command"},
+		{name: "bad quoting", alias: "inbox", expansion: "mail search 'x", wantErr: "unterminated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAlias(tt.alias, tt.expansion)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAliasCommands(t *testing.T) {
+	setupAliasTest(t, nil)
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "alias"}
+	cmd.SetOut(buf)
+
+	if err := runAliasAdd(cmd, []string{"inbox", "mail list --labels INBOX --unread-only"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Aliases["inbox"] != "mail list --labels INBOX --unread-only" {
+		t.Errorf("alias not saved, got %q", cfg.Aliases["inbox"])
+	}
+
+	buf.Reset()
+	if err := runAliasList(cmd, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "inbox") || !strings.Contains(buf.String(), "goog mail list --labels INBOX --unread-only") {
+		t.Errorf("unexpected list output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	formatFlag = "json"
+	if err := runAliasList(cmd, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var aliases map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &aliases); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(aliases) != 1 {
+		t.Errorf("aliases = %v, want one", aliases)
+	}
+
+	if err := runAliasRemove(cmd, []string{"inbox"}); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := runAliasRemove(cmd, []string{"inbox"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Aliases) != 0 {
+		t.Errorf("aliases = %v after remove, want none", cfg.Aliases)
+	}
+}
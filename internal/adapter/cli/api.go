@@ -0,0 +1,679 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+)
+
+// Command flags for api command.
+var (
+	apiInput   string
+	apiExample bool
+)
+
+// apiCmd calls an operation with a JSON request.
+var apiCmd = &cobra.Command{
+	Use:   "api [<resource>.<verb>]",
+	Short: "Call an operation with a JSON request and get JSON back",
+	Long: `Call an operation with a JSON request and print the JSON result, for
+scripts that want a stable interface instead of parsing flags and
+output.
+
+Requests and results use the JSON shapes of goog's own entities, as
+'--format json' prints them: a message is {"To": ["Bob <bob@example.com>"],
+"Subject": ..., "Body": ...}, an event {"Title": ..., "Start": ...,
+"End": ...}. Field names match without regard to case; unknown fields
+are an error. Times are RFC 3339. The request is read from --input, or
+from standard input when --input is "-" or not given; an empty request
+is {}.
+
+Without a method, the methods are listed. --example prints a request
+for a method to start from. The result is always JSON, and so are
+errors, on standard error.
+
+Mail sent with mail.send goes through the same checks as 'goog mail
+send': suppressed recipients are dropped, recipients are verified
+(--no-verify and --check-mx apply), the send throttle applies,
+mail.auto_bcc is added and the sender defaults to the account. Extra
+Headers follow the rules of --header: goog's own headers, such as To or
+Bcc, are refused, and names and values must be a single clean line.
+Read-only mode and the account selection work as for every command.
+Authorize an account for methods with 'goog auth login --for "api
+mail.send, api cal.list"'.`,
+	Example: `  # List the methods
+  goog api
+
+  # Send a message
+  echo '{"To": ["bob@example.com"], "Subject": "Hi", "Body": "Hello"}' | goog api mail.send
+
+  # Start from an example request
+  goog api cal.create --example > event.json
+  goog api cal.create --input event.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAPI,
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().StringVar(&apiInput, "input", "-", `file holding the JSON request ("-" for standard input)`)
+	apiCmd.Flags().BoolVar(&apiExample, "example", false, "print an example request for the method instead of calling it")
+	addVerifyFlags(apiCmd)
+}
+
+// apiMethod is an operation of goog api.
+type apiMethod struct {
+	// Description says what the method does.
+	Description string
+	// Scopes are the OAuth scopes the method needs.
+	Scopes []string
+	// Example is a request for the method.
+	Example any
+	// call decodes the request and performs the method.
+	call func(ctx context.Context, cmd *cobra.Command, input []byte) (any, error)
+}
+
+// apiHandler adapts a function taking a typed request to an apiMethod
+// call, decoding the request strictly.
+func apiHandler[T any](fn func(ctx context.Context, cmd *cobra.Command, req T) (any, error)) func(context.Context, *cobra.Command, []byte) (any, error) {
+	return func(ctx context.Context, cmd *cobra.Command, input []byte) (any, error) {
+		var req T
+		if len(bytes.TrimSpace(input)) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(input))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&req); err != nil {
+				return nil, fmt.Errorf("invalid request: %w", err)
+			}
+			if dec.More() {
+				return nil, fmt.Errorf("invalid request: more than one JSON value")
+			}
+		}
+		return fn(ctx, cmd, req)
+	}
+}
+
+// apiID names one item, such as a message.
+type apiID struct {
+	ID string
+}
+
+// apiEventRequest selects an event, or a calendar for a new event.
+type apiEventRequest struct {
+	// CalendarID defaults to the primary calendar.
+	CalendarID string
+	ID         string
+	Event      *calendar.Event
+}
+
+// apiEventsRequest selects the events of a calendar in a time range.
+type apiEventsRequest struct {
+	// CalendarID defaults to the primary calendar.
+	CalendarID string
+	// TimeMin defaults to now and TimeMax to 30 days after TimeMin.
+	TimeMin time.Time
+	TimeMax time.Time
+}
+
+// apiMessageModify changes the labels of a message.
+type apiMessageModify struct {
+	ID string
+	mail.ModifyRequest
+}
+
+// apiTaskRequest selects a task, or a task list for a new task.
+type apiTaskRequest struct {
+	// TaskListID defaults to the default list.
+	TaskListID string
+	ID         string
+	Task       *domaintasks.Task
+}
+
+// apiTasksRequest selects the tasks of a task list.
+type apiTasksRequest struct {
+	// TaskListID defaults to the default list.
+	TaskListID string
+	domaintasks.ListOptions
+}
+
+// apiContactRequest selects a contact.
+type apiContactRequest struct {
+	ResourceName string
+}
+
+// apiDone is the result of a method that returns nothing else.
+type apiDone struct {
+	ID   string
+	Done bool
+}
+
+// apiMethods are the methods of goog api, by name.
+var apiMethods = map[string]*apiMethod{
+	"mail.list": {
+		Description: "List messages by label",
+		Scopes:      []string{auth.ScopeGmailReadonly},
+		Example:     mail.ListOptions{MaxResults: 10, LabelIDs: []string{"INBOX"}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req mail.ListOptions) (any, error) {
+			repo, _, err := getMessageRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.List(ctx, req)
+		}),
+	},
+	"mail.search": {
+		Description: "Search messages with a Gmail query",
+		Scopes:      []string{auth.ScopeGmailReadonly},
+		Example:     mail.ListOptions{MaxResults: 10, Query: "from:bob@example.com newer_than:7d"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req mail.ListOptions) (any, error) {
+			if strings.TrimSpace(req.Query) == "" {
+				return nil, fmt.Errorf("request needs a Query")
+			}
+			repo, _, err := getMessageRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Search(ctx, req.Query, req)
+		}),
+	},
+	"mail.get": {
+		Description: "Get a message",
+		Scopes:      []string{auth.ScopeGmailReadonly},
+		Example:     apiID{ID: "18c1234abcd"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiID) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, _, err := getMessageRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Get(ctx, req.ID)
+		}),
+	},
+	"mail.send": {
+		Description: "Send a message",
+		Scopes:      []string{auth.ScopeGmailSend},
+		Example:     mail.Message{To: []mail.Address{{Name: "Bob", Email: "bob@example.com"}}, Subject: "Hello", Body: "Hi Bob"},
+		call:        apiHandler(apiSendMessage),
+	},
+	"mail.modify": {
+		Description: "Add and remove labels of a message",
+		Scopes:      []string{auth.ScopeGmailModify},
+		Example:     apiMessageModify{ID: "18c1234abcd", ModifyRequest: mail.ModifyRequest{AddLabels: []string{"STARRED"}, RemoveLabels: []string{"UNREAD"}}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiMessageModify) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, _, err := getMessageRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Modify(ctx, req.ID, req.ModifyRequest)
+		}),
+	},
+	"mail.trash": {
+		Description: "Move a message to the trash",
+		Scopes:      []string{auth.ScopeGmailModify},
+		Example:     apiID{ID: "18c1234abcd"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiID) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, _, err := getMessageRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.Trash(ctx, req.ID); err != nil {
+				return nil, err
+			}
+			return apiDone{ID: req.ID, Done: true}, nil
+		}),
+	},
+	"mail.labels": {
+		Description: "List labels",
+		Scopes:      []string{auth.ScopeGmailLabels},
+		Example:     struct{}{},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req struct{}) (any, error) {
+			repo, err := getLabelRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.List(ctx)
+		}),
+	},
+	"cal.list": {
+		Description: "List the events of a calendar in a time range",
+		Scopes:      []string{auth.ScopeCalendarReadonly},
+		Example:     apiEventsRequest{CalendarID: "primary", TimeMin: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), TimeMax: time.Date(2025, 8, 8, 0, 0, 0, 0, time.UTC)},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiEventsRequest) (any, error) {
+			if req.TimeMin.IsZero() {
+				req.TimeMin = time.Now()
+			}
+			if req.TimeMax.IsZero() {
+				req.TimeMax = req.TimeMin.AddDate(0, 0, 30)
+			}
+			repo, err := getEventRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			events, err := repo.List(ctx, apiCalendarID(req.CalendarID), req.TimeMin, req.TimeMax)
+			if events == nil && err == nil {
+				events = []*calendar.Event{}
+			}
+			return events, err
+		}),
+	},
+	"cal.get": {
+		Description: "Get an event",
+		Scopes:      []string{auth.ScopeCalendarReadonly},
+		Example:     apiEventRequest{CalendarID: "primary", ID: "abc123def456"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiEventRequest) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, err := getEventRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Get(ctx, apiCalendarID(req.CalendarID), req.ID)
+		}),
+	},
+	"cal.create": {
+		Description: "Create an event",
+		Scopes:      []string{auth.ScopeCalendarEvents},
+		Example: apiEventRequest{CalendarID: "primary", Event: &calendar.Event{
+			Title: "Planning", Location: "Room 4",
+			Start: time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC), End: time.Date(2025, 8, 4, 10, 0, 0, 0, time.UTC),
+		}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiEventRequest) (any, error) {
+			if req.Event == nil {
+				return nil, fmt.Errorf("request needs an Event")
+			}
+			if err := apiCheckEvent(req.Event); err != nil {
+				return nil, err
+			}
+			repo, err := getEventRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Create(ctx, apiCalendarID(req.CalendarID), req.Event)
+		}),
+	},
+	"cal.update": {
+		Description: "Replace an event with the one given, by its ID",
+		Scopes:      []string{auth.ScopeCalendarEvents},
+		Example: apiEventRequest{CalendarID: "primary", Event: &calendar.Event{
+			ID: "abc123def456", Title: "Planning (moved)",
+			Start: time.Date(2025, 8, 4, 14, 0, 0, 0, time.UTC), End: time.Date(2025, 8, 4, 15, 0, 0, 0, time.UTC),
+		}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiEventRequest) (any, error) {
+			if req.Event == nil || req.Event.ID == "" {
+				return nil, fmt.Errorf("request needs an Event with an ID")
+			}
+			if err := apiCheckEvent(req.Event); err != nil {
+				return nil, err
+			}
+			repo, err := getEventRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Update(ctx, apiCalendarID(req.CalendarID), req.Event)
+		}),
+	},
+	"cal.delete": {
+		Description: "Delete an event",
+		Scopes:      []string{auth.ScopeCalendarEvents},
+		Example:     apiEventRequest{CalendarID: "primary", ID: "abc123def456"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiEventRequest) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, err := getEventRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.Delete(ctx, apiCalendarID(req.CalendarID), req.ID); err != nil {
+				return nil, err
+			}
+			return apiDone{ID: req.ID, Done: true}, nil
+		}),
+	},
+	"tasks.lists": {
+		Description: "List task lists",
+		Scopes:      []string{auth.ScopeTasksReadonly},
+		Example:     struct{}{},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req struct{}) (any, error) {
+			ts, err := getTokenSourceFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			repo, err := GetDependencies().RepoFactory.NewTaskListRepository(ctx, ts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create repository: %w", err)
+			}
+			return repo.List(ctx)
+		}),
+	},
+	"tasks.list": {
+		Description: "List the tasks of a task list",
+		Scopes:      []string{auth.ScopeTasksReadonly},
+		Example:     apiTasksRequest{TaskListID: "@default", ListOptions: domaintasks.ListOptions{MaxResults: 20}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiTasksRequest) (any, error) {
+			repo, err := apiTaskRepository(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.List(ctx, apiTaskListID(req.TaskListID), req.ListOptions)
+		}),
+	},
+	"tasks.create": {
+		Description: "Create a task",
+		Scopes:      []string{auth.ScopeTasks},
+		Example:     apiTaskRequest{TaskListID: "@default", Task: &domaintasks.Task{Title: "Send the report", Notes: "Q3 numbers"}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiTaskRequest) (any, error) {
+			if req.Task == nil {
+				return nil, fmt.Errorf("request needs a Task")
+			}
+			repo, err := apiTaskRepository(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Create(ctx, apiTaskListID(req.TaskListID), req.Task)
+		}),
+	},
+	"tasks.update": {
+		Description: "Replace a task with the one given, by its ID",
+		Scopes:      []string{auth.ScopeTasks},
+		Example:     apiTaskRequest{TaskListID: "@default", Task: &domaintasks.Task{ID: "MTIzNDU2", Title: "Send the report", Status: domaintasks.StatusCompleted}},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiTaskRequest) (any, error) {
+			if req.Task == nil || req.Task.ID == "" {
+				return nil, fmt.Errorf("request needs a Task with an ID")
+			}
+			repo, err := apiTaskRepository(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Update(ctx, apiTaskListID(req.TaskListID), req.Task)
+		}),
+	},
+	"tasks.delete": {
+		Description: "Delete a task",
+		Scopes:      []string{auth.ScopeTasks},
+		Example:     apiTaskRequest{TaskListID: "@default", ID: "MTIzNDU2"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiTaskRequest) (any, error) {
+			if req.ID == "" {
+				return nil, fmt.Errorf("request needs an ID")
+			}
+			repo, err := apiTaskRepository(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := repo.Delete(ctx, apiTaskListID(req.TaskListID), req.ID); err != nil {
+				return nil, err
+			}
+			return apiDone{ID: req.ID, Done: true}, nil
+		}),
+	},
+	"contacts.list": {
+		Description: "List contacts",
+		Scopes:      []string{auth.ScopeContactsReadonly},
+		Example:     domaincontacts.ListOptions{MaxResults: 50},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req domaincontacts.ListOptions) (any, error) {
+			repo, err := getContactRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.List(ctx, req)
+		}),
+	},
+	"contacts.search": {
+		Description: "Search contacts by name, email address or phone number",
+		Scopes:      []string{auth.ScopeContactsReadonly},
+		Example:     domaincontacts.SearchOptions{Query: "bob", MaxResults: 10},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req domaincontacts.SearchOptions) (any, error) {
+			if strings.TrimSpace(req.Query) == "" {
+				return nil, fmt.Errorf("request needs a Query")
+			}
+			repo, err := getContactRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Search(ctx, req)
+		}),
+	},
+	"contacts.get": {
+		Description: "Get a contact",
+		Scopes:      []string{auth.ScopeContactsReadonly},
+		Example:     apiContactRequest{ResourceName: "people/c1234567890"},
+		call: apiHandler(func(ctx context.Context, cmd *cobra.Command, req apiContactRequest) (any, error) {
+			if req.ResourceName == "" {
+				return nil, fmt.Errorf("request needs a ResourceName")
+			}
+			repo, err := getContactRepositoryFromDeps(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return repo.Get(ctx, req.ResourceName)
+		}),
+	},
+}
+
+// apiCalendarID returns the calendar of a request, primary by default.
+func apiCalendarID(id string) string {
+	if id == "" {
+		return "primary"
+	}
+	return id
+}
+
+// apiCheckEvent checks that an event has a time the API accepts.
+func apiCheckEvent(e *calendar.Event) error {
+	if e.Start.IsZero() || e.End.IsZero() {
+		return fmt.Errorf("event needs a Start and an End")
+	}
+	if e.End.Before(e.Start) {
+		return fmt.Errorf("event ends before it starts")
+	}
+	return nil
+}
+
+// apiTaskListID returns the task list of a request, the default list by
+// default.
+func apiTaskListID(id string) string {
+	if id == "" {
+		return "@default"
+	}
+	return id
+}
+
+// apiTaskRepository creates the task repository of the account in use.
+func apiTaskRepository(ctx context.Context) (TaskRepository, error) {
+	ts, err := getTokenSourceFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := GetDependencies().RepoFactory.NewTaskRepository(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	return repo, nil
+}
+
+// apiSendMessage sends a message with the checks of mail send. Extra
+// headers must pass the checks of --header.
+func apiSendMessage(ctx context.Context, cmd *cobra.Command, msg mail.Message) (any, error) {
+	for i, h := range msg.Headers {
+		checked, err := mail.CheckHeader(h)
+		if err != nil {
+			return nil, err
+		}
+		msg.Headers[i] = checked
+	}
+
+	lists := [][]mail.Address{msg.To, msg.Cc, msg.Bcc}
+	recipients := make([][]string, len(lists))
+	for i, list := range lists {
+		for _, addr := range list {
+			email, err := mail.ValidateAddress(addr.Email)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", addr.Email, err)
+			}
+			recipients[i] = append(recipients[i], email)
+		}
+	}
+	if len(recipients[0]) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required in To")
+	}
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kept, err := dropSuppressed(cmd, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if len(kept[0]) == 0 {
+		return nil, fmt.Errorf("every recipient in To is suppressed")
+	}
+	if err := verifyRecipients(ctx, cmd, senderEmail, kept...); err != nil {
+		return nil, err
+	}
+	if err := checkThrottle(cmd, kept...); err != nil {
+		return nil, err
+	}
+	msg.To, msg.Cc, msg.Bcc = keepAddresses(msg.To, kept[0]), keepAddresses(msg.Cc, kept[1]), keepAddresses(msg.Bcc, kept[2])
+	if msg.From.Email == "" {
+		msg.From = mail.ParseAddress(senderEmail)
+	}
+	msg.AddAutoBcc(autoBccAddresses(cmd))
+	if err := checkDuplicate(ctx, cmd, "api mail.send", repo, &msg); err != nil {
+		return nil, err
+	}
+
+	sent, err := repo.Send(ctx, &msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	recordRecipients(kept...)
+	return sent, nil
+}
+
+// keepAddresses returns the addresses of list whose email is in kept,
+// keeping their display names.
+func keepAddresses(list []mail.Address, kept []string) []mail.Address {
+	var out []mail.Address
+	for _, addr := range list {
+		for _, email := range kept {
+			if strings.EqualFold(addr.Email, email) {
+				out = append(out, addr)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// apiMethodJSON is the JSON representation of a method in the list.
+type apiMethodJSON struct {
+	Method      string   `json:"method"`
+	Description string   `json:"description"`
+	Scopes      []string `json:"scopes"`
+	Example     any      `json:"example"`
+}
+
+// runAPI handles the api command.
+func runAPI(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listAPIMethods(cmd)
+	}
+	// Errors are part of the interface, so they are JSON too
+	formatFlag = presenter.FormatJSON
+
+	method, ok := apiMethods[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown method %q; run 'goog api' for the list", args[0])
+	}
+	if apiExample {
+		return printAPIJSON(cmd, method.Example)
+	}
+
+	input, err := readAPIInput(cmd)
+	if err != nil {
+		return err
+	}
+	result, err := method.call(context.Background(), cmd, input)
+	if err != nil {
+		return err
+	}
+	return printAPIJSON(cmd, result)
+}
+
+// readAPIInput reads the request from --input or standard input.
+func readAPIInput(cmd *cobra.Command) ([]byte, error) {
+	if apiInput == "" || apiInput == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the request: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(apiInput)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("request file %s does not exist", apiInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the request: %w", err)
+	}
+	return data, nil
+}
+
+// printAPIJSON prints v as indented JSON.
+func printAPIJSON(cmd *cobra.Command, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the result: %w", err)
+	}
+	cmd.Println(string(data))
+	return nil
+}
+
+// listAPIMethods prints the methods, with their example requests in JSON.
+func listAPIMethods(cmd *cobra.Command) error {
+	names := make([]string, 0, len(apiMethods))
+	for name := range apiMethods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if formatFlag == presenter.FormatJSON {
+		out := make([]apiMethodJSON, len(names))
+		for i, name := range names {
+			m := apiMethods[name]
+			out[i] = apiMethodJSON{Method: name, Description: m.Description, Scopes: m.Scopes, Example: m.Example}
+		}
+		return printAPIJSON(cmd, out)
+	}
+	for _, name := range names {
+		cmd.Printf("%-16s %s\n", name, apiMethods[name].Description)
+	}
+	return nil
+}
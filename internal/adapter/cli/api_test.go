@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupAPITest sets up dependencies with repo and returns a command whose
+// standard input is input.
+func setupAPITest(t *testing.T, repo *MockMessageRepository, input string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, EventRepo: &MockEventRepository{}},
+	})
+	t.Cleanup(ResetDependencies)
+	origInput, origExample, origFormat := apiInput, apiExample, formatFlag
+	t.Cleanup(func() { apiInput, apiExample, formatFlag = origInput, origExample, origFormat })
+	apiInput, apiExample, formatFlag = "-", false, "table"
+
+	cmd := &cobra.Command{Use: "api"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader(input))
+	return cmd, buf
+}
+
+func TestRunAPI_MailSend(t *testing.T) {
+	repo := &MockMessageRepository{}
+	cmd, buf := setupAPITest(t, repo, `{"to": ["Bob <Bob@Example.com>"], "Subject": "Hi", "Body": "Hello"}`)
+
+	if err := runAPI(cmd, []string{"mail.send"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var sent mail.Message
+	if err := json.Unmarshal(buf.Bytes(), &sent); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if sent.From.Email != "me@example.com" || len(sent.To) != 1 || sent.To[0].Name != "Bob" || sent.Subject != "Hi" {
+		t.Errorf("sent %+v", sent)
+	}
+	if formatFlag != "json" {
+		t.Errorf("expected errors to be printed as JSON, format is %q", formatFlag)
+	}
+}
+
+func TestRunAPI_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		method, input, wantErr string
+	}{
+		{"mail.send", `{"Subject": "Hi"}`, "at least one recipient"},
+		{"mail.send", `{"To": ["not an address"]}`, "invalid recipient"},
+		{"mail.send", `{"To": ["bob@gmial.com"]}`, "did you mean bob@gmail.com"},
+		{"mail.send", `{"To": ["bob@example.com"], "Headers": [{"Name": "Bcc", "Value": "eve@example.com"}]}`, "set by goog"},
+		{"mail.send", `{"To": ["bob@example.com"], "Headers": [{"Name": "X-A\r\nBcc", "Value": "eve@example.com"}]}`, "invalid header name"},
+		{"mail.send", `{"To": ["bob@example.com"], "Headers": [{"Name": "X-A", "Value": "b\r\nBcc: eve@example.com"}]}`, "control character"},
+		{"mail.get", `{"Id": "m1", "Colour": "red"}`, "unknown field"},
+		{"mail.get", ``, "needs an ID"},
+		{"mail.get", `{"ID": "m1"} {"ID": "m2"}`, "more than one"},
+		{"cal.create", `{"Event": {"Title": "x", "Start": "2025-08-04T10:00:00Z", "End": "2025-08-04T09:00:00Z"}}`, "ends before"},
+		{"mail.nope", `{}`, "unknown method"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.wantErr, func(t *testing.T) {
+			cmd, _ := setupAPITest(t, &MockMessageRepository{}, tt.input)
+			err := runAPI(cmd, []string{tt.method})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunAPI_InputFileAndList(t *testing.T) {
+	repo := &MockMessageRepository{Message: &mail.Message{ID: "m1", Subject: "Hello"}}
+	cmd, buf := setupAPITest(t, repo, "")
+	apiInput = filepath.Join(t.TempDir(), "req.json")
+	if err := os.WriteFile(apiInput, []byte(`{"id": "m1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runAPI(cmd, []string{"mail.get"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"Subject": "Hello"`) {
+		t.Errorf("unexpected result:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	apiExample = true
+	if err := runAPI(cmd, []string{"cal.create"}); err != nil {
+		t.Fatal(err)
+	}
+	var example apiEventRequest
+	if err := json.Unmarshal(buf.Bytes(), &example); err != nil || example.Event == nil || apiCheckEvent(example.Event) != nil {
+		t.Errorf("invalid example %q: %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	formatFlag = "table"
+	if err := runAPI(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "mail.send") || !strings.Contains(buf.String(), "tasks.list") {
+		t.Errorf("unexpected method list:\n%s", buf.String())
+	}
+}
+
+func TestAPIMethods_ExamplesDecode(t *testing.T) {
+	for name, method := range apiMethods {
+		data, err := json.Marshal(method.Example)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(method.Scopes) == 0 {
+			t.Errorf("%s declares no scopes", name)
+		}
+		// The example must decode into the method's own request type
+		cmd, _ := setupAPITest(t, &MockMessageRepository{}, "")
+		_, err = method.call(t.Context(), cmd, data)
+		if err != nil && strings.Contains(err.Error(), "invalid request") {
+			t.Errorf("%s: example does not decode: %v", name, err)
+		}
+	}
+}
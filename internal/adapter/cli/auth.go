@@ -10,10 +10,12 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
 )
 
 var (
 	authScopes []string
+	authFor    string
 )
 
 // authCmd represents the auth command group.
@@ -40,13 +42,19 @@ By default, the following scopes are requested:
   - Calendar (readonly)
   - User email info
 
-Use --scopes to request specific scopes.`,
+Use --scopes to request specific scopes, or --for to request only
+the scopes the listed commands need. The commands given with --for
+are remembered, and 'goog auth status' warns when the account holds
+scopes none of them needs.`,
 	Example: `  # Login with default scopes
   goog auth login
 
   # Login with specific scopes
   goog auth login --scopes gmail.modify,calendar
 
+  # Login with just what two commands need
+  goog auth login --for "mail list, cal today"
+
   # Login and add as a named account
   goog auth login --account work`,
 	RunE: runAuthLogin,
@@ -109,6 +117,7 @@ func init() {
 
 	// Login flags
 	authLoginCmd.Flags().StringSliceVar(&authScopes, "scopes", nil, "OAuth scopes to request (comma-separated)")
+	authLoginCmd.Flags().StringVar(&authFor, "for", "", "request only the scopes these commands need (comma-separated)")
 
 	// Add to root
 	rootCmd.AddCommand(authCmd)
@@ -130,6 +139,17 @@ func runAuthLogin(cmd *cobra.Command, args []string) error {
 	// Parse scopes
 	scopes := parseScopes(authScopes)
 
+	var commands []string
+	if authFor != "" {
+		if len(authScopes) > 0 {
+			return fmt.Errorf("--for and --scopes cannot be used together")
+		}
+		var err error
+		if commands, scopes, err = scopesForLogin(authFor); err != nil {
+			return err
+		}
+	}
+
 	// Add account
 	acc, err := svc.Add(ctx, alias, scopes)
 	if err != nil {
@@ -142,6 +162,49 @@ func runAuthLogin(cmd *cobra.Command, args []string) error {
 		cmd.Println("This account is set as the default.")
 	}
 
+	if len(commands) > 0 {
+		if err := saveAccountCommands(acc.Alias, commands); err != nil {
+			return err
+		}
+		cmd.Printf("Scopes limited to what %s need\n", strings.Join(commands, ", "))
+	}
+
+	return nil
+}
+
+// scopesForLogin returns the commands listed in a --for value and the
+// scopes to request for them, including the identity scopes.
+func scopesForLogin(list string) ([]string, []string, error) {
+	commands := parseCommandList(list)
+	if len(commands) == 0 {
+		return nil, nil, fmt.Errorf("--for needs at least one command")
+	}
+	var aliases map[string]string
+	if cfg, err := config.Load(); err == nil {
+		aliases = cfg.Aliases
+	}
+	scopes, err := scopesForCommands(commands, aliases)
+	if err != nil {
+		return nil, nil, err
+	}
+	return commands, parseScopes(scopes), nil
+}
+
+// saveAccountCommands records the commands an account was authorized for.
+func saveAccountCommands(alias string, commands []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	acc, ok := cfg.Accounts[alias]
+	if !ok {
+		return fmt.Errorf("account not found: %s", alias)
+	}
+	acc.Commands = commands
+	cfg.Accounts[alias] = acc
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
 	return nil
 }
 
@@ -200,6 +263,7 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 		for _, scope := range acc.Scopes {
 			cmd.Printf("  - %s\n", scope)
 		}
+		warnBroaderScopes(cmd, acc.Alias, acc.Scopes, acc.Commands)
 	}
 
 	return nil
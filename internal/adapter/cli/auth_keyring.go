@@ -0,0 +1,106 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+)
+
+// Command flags for auth keyring commands.
+var (
+	authKeyringBackend string
+)
+
+// diagnoseKeyring reports on a keyring backend. It is a variable so tests
+// can avoid touching the real keyring.
+var diagnoseKeyring = keyring.Diagnose
+
+// authKeyringCmd represents the auth keyring command group.
+var authKeyringCmd = &cobra.Command{
+	Use:   "keyring",
+	Short: "Inspect token storage",
+	Long: `Inspect where goog stores OAuth tokens.
+
+By default goog uses the system keyring (macOS Keychain, Secret Service
+on Linux, Windows Credential Manager) and falls back to an encrypted
+file when it is unavailable. Set keyring.backend in the config to pin a
+backend; a pinned backend that cannot be opened is an error instead of
+a silent fallback.`,
+}
+
+// authKeyringStatusCmd shows the active keyring backend.
+var authKeyringStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the active keyring backend",
+	Long: `Show which keyring backend is active and whether it can be unlocked.
+
+Use --backend to check a specific backend instead of the configured
+one. Checking may prompt to unlock the system keyring.`,
+	Example: `  # Show the active backend
+  goog auth keyring status
+
+  # Check whether the Secret Service is usable
+  goog auth keyring status --backend secret-service
+
+  # Always store tokens in the encrypted file
+  goog config set keyring.backend file`,
+	Args: cobra.NoArgs,
+	RunE: runAuthKeyringStatus,
+}
+
+func init() {
+	authCmd.AddCommand(authKeyringCmd)
+	authKeyringCmd.AddCommand(authKeyringStatusCmd)
+
+	authKeyringStatusCmd.Flags().StringVar(&authKeyringBackend, "backend", "", "backend to check (auto|keychain|secret-service|wincred|file)")
+}
+
+// runAuthKeyringStatus handles the auth keyring status command.
+func runAuthKeyringStatus(cmd *cobra.Command, args []string) error {
+	if authKeyringBackend != "" {
+		if err := keyring.ValidateBackend(authKeyringBackend); err != nil {
+			return err
+		}
+	}
+
+	status := diagnoseKeyring(authKeyringBackend)
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode keyring status: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		backend := status.Backend
+		if backend == "" {
+			backend = "none"
+		}
+		if status.Fallback {
+			backend += " (fallback)"
+		}
+		cmd.Printf("Backend:     %s\n", backend)
+		cmd.Printf("Requested:   %s\n", status.Requested)
+		cmd.Printf("Unlockable:  %v\n", status.Unlockable)
+		if status.Location != "" {
+			cmd.Printf("Location:    %s\n", status.Location)
+		}
+		if status.Protector != "" {
+			cmd.Printf("Protector:   %s\n", status.Protector)
+		}
+		cmd.Printf("Supported:   %s\n", strings.Join(status.Supported, ", "))
+		if status.Reason != "" {
+			cmd.Printf("Reason:      %s\n", status.Reason)
+		}
+	}
+
+	if status.Backend == "" || !status.Unlockable {
+		return fmt.Errorf("keyring backend %s is not usable", status.Requested)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+)
+
+// setupAuthKeyringTest replaces the keyring diagnosis with one returning
+// status and records the backend it was asked about.
+func setupAuthKeyringTest(t *testing.T, status keyring.Status) (*bytes.Buffer, *string) {
+	t.Helper()
+
+	requested := new(string)
+	origDiagnose, origBackend, origFormat := diagnoseKeyring, authKeyringBackend, formatFlag
+	diagnoseKeyring = func(name string) keyring.Status {
+		*requested = name
+		return status
+	}
+	authKeyringBackend = ""
+	formatFlag = "table"
+	t.Cleanup(func() {
+		diagnoseKeyring, authKeyringBackend, formatFlag = origDiagnose, origBackend, origFormat
+	})
+
+	return new(bytes.Buffer), requested
+}
+
+func TestAuthKeyringStatusCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(authCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"auth", "keyring", "status", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--backend", "keyring.backend", "secret-service"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunAuthKeyringStatus(t *testing.T) {
+	t.Run("shows fallback to file", func(t *testing.T) {
+		buf, requested := setupAuthKeyringTest(t, keyring.Status{
+			Requested:  keyring.BackendAuto,
+			Backend:    keyring.BackendFile,
+			Fallback:   true,
+			Unlockable: true,
+			Location:   "/home/me/.config/goog/keyring",
+			Supported:  []string{keyring.BackendSecretService, keyring.BackendFile},
+			Reason:     "system keyring unavailable: secret-service: no dbus",
+		})
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthKeyringStatus(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *requested != "" {
+			t.Errorf("diagnosed %q, want the configured backend", *requested)
+		}
+		output := buf.String()
+		for _, want := range []string{
+			"Backend:     file (fallback)",
+			"Requested:   auto",
+			"Unlockable:  true",
+			"Location:    /home/me/.config/goog/keyring",
+			"Supported:   secret-service, file",
+			"Reason:      system keyring unavailable",
+		} {
+			if !containsStr(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("backend flag overrides config", func(t *testing.T) {
+		buf, requested := setupAuthKeyringTest(t, keyring.Status{
+			Requested:  keyring.BackendFile,
+			Backend:    keyring.BackendFile,
+			Unlockable: true,
+			Supported:  []string{keyring.BackendFile},
+		})
+		authKeyringBackend = keyring.BackendFile
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthKeyringStatus(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *requested != keyring.BackendFile {
+			t.Errorf("diagnosed %q, want file", *requested)
+		}
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		buf, _ := setupAuthKeyringTest(t, keyring.Status{})
+		authKeyringBackend = "kwallet"
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		err := runAuthKeyringStatus(cmd, nil)
+		if !errors.Is(err, keyring.ErrUnknownBackend) {
+			t.Errorf("expected ErrUnknownBackend, got %v", err)
+		}
+	})
+
+	t.Run("unusable backend returns error", func(t *testing.T) {
+		buf, _ := setupAuthKeyringTest(t, keyring.Status{
+			Requested: keyring.BackendSecretService,
+			Supported: []string{keyring.BackendSecretService, keyring.BackendFile},
+			Reason:    "keyring backend secret-service is unavailable: no dbus",
+		})
+		authKeyringBackend = keyring.BackendSecretService
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		err := runAuthKeyringStatus(cmd, nil)
+		if err == nil || !containsStr(err.Error(), "not usable") {
+			t.Errorf("expected not usable error, got %v", err)
+		}
+		if !containsStr(buf.String(), "Backend:     none") {
+			t.Errorf("expected status to be printed, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		buf, _ := setupAuthKeyringTest(t, keyring.Status{
+			Requested:  keyring.BackendAuto,
+			Backend:    keyring.BackendKeychain,
+			Unlockable: true,
+			Supported:  []string{keyring.BackendKeychain, keyring.BackendFile},
+		})
+		formatFlag = "json"
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthKeyringStatus(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got keyring.Status
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+		}
+		if got.Backend != keyring.BackendKeychain || !got.Unlockable {
+			t.Errorf("unexpected status: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	"golang.org/x/term"
+)
+
+// Command flags for auth rotate-storage.
+var (
+	rotateStoragePassphrase       bool
+	rotateStorageRemovePassphrase bool
+	rotateStorageToKeyring        bool
+	rotateStorageDryRun           bool
+)
+
+// openTokenFiles and openSystemKeyring open the token stores. They are
+// variables so tests can avoid touching the real config directory and
+// keyring.
+var (
+	openTokenFiles    = keyring.OpenFileStore
+	openSystemKeyring = keyring.OpenSystemStore
+)
+
+// authRotateStorageCmd re-encrypts the token files or moves them into the
+// system keyring.
+var authRotateStorageCmd = &cobra.Command{
+	Use:   "rotate-storage",
+	Short: "Re-encrypt token files or move them into the system keyring",
+	Long: `Re-encrypt the encrypted token files in ~/.config/goog/tokens/.
+
+When the system keyring is unavailable goog stores tokens in encrypted
+files. This command re-encrypts every file with a new random salt,
+keeping the current passphrase from GOOG_TOKEN_PASSPHRASE. Use
+--passphrase to set a new passphrase, asked for on the terminal, or
+--remove-passphrase to go back to protecting the files with the
+machine identity alone. Once files have a passphrase, goog needs
+GOOG_TOKEN_PASSPHRASE set to read them. Rotating also binds the files to
+the hardware key set in keyring.protector, or unbinds them when it is
+empty.
+
+Use --to-keyring to move the tokens into the system keyring (Keychain,
+Secret Service or Credential Manager) once it is available. goog uses
+it instead of the files from then on.
+
+Every file is read before anything is changed, and every new copy is
+read back and compared before the old files are replaced or deleted; a
+failure leaves the token files as they were. Use --dry-run to only check
+that every file can be read.`,
+	Example: `  # Re-encrypt the token files with a new salt
+  goog auth rotate-storage
+
+  # Protect the token files with a passphrase
+  goog auth rotate-storage --passphrase
+
+  # Move the tokens into the system keyring
+  goog auth rotate-storage --to-keyring`,
+	Args: cobra.NoArgs,
+	RunE: runAuthRotateStorage,
+}
+
+func init() {
+	authCmd.AddCommand(authRotateStorageCmd)
+
+	authRotateStorageCmd.Flags().BoolVar(&rotateStoragePassphrase, "passphrase", false, "ask for a new passphrase for the token files")
+	authRotateStorageCmd.Flags().BoolVar(&rotateStorageRemovePassphrase, "remove-passphrase", false, "remove the passphrase from the token files")
+	authRotateStorageCmd.Flags().BoolVar(&rotateStorageToKeyring, "to-keyring", false, "move the tokens into the system keyring")
+	authRotateStorageCmd.Flags().BoolVar(&rotateStorageDryRun, "dry-run", false, "check that every token file can be read without changing anything")
+	authRotateStorageCmd.MarkFlagsMutuallyExclusive("passphrase", "remove-passphrase", "to-keyring")
+}
+
+// rotateStorageJSON is the JSON representation of a storage rotation.
+type rotateStorageJSON struct {
+	Destination string                   `json:"destination"`
+	Passphrase  bool                     `json:"passphrase"`
+	Protector   string                   `json:"protector,omitempty"`
+	DryRun      bool                     `json:"dry_run"`
+	Accounts    []keyring.RotatedAccount `json:"accounts"`
+}
+
+// runAuthRotateStorage handles the auth rotate-storage command.
+func runAuthRotateStorage(cmd *cobra.Command, args []string) error {
+	files, err := openTokenFiles()
+	if err != nil {
+		return fmt.Errorf("failed to open token files: %w", err)
+	}
+
+	result := rotateStorageJSON{Destination: keyring.BackendFile, DryRun: rotateStorageDryRun}
+	passphrase := os.Getenv(keyring.PassphraseEnv)
+	switch {
+	case rotateStorageRemovePassphrase:
+		passphrase = ""
+	case rotateStoragePassphrase && !rotateStorageDryRun:
+		if passphrase, err = readNewPassphrase(cmd); err != nil {
+			return err
+		}
+	}
+	result.Passphrase = passphrase != ""
+
+	var store keyring.Store
+	if rotateStorageToKeyring {
+		result.Passphrase = false
+		store, result.Destination, err = openSystemKeyring()
+		if err != nil {
+			return fmt.Errorf("system keyring is unavailable, token files were not changed: %w", err)
+		}
+	}
+
+	switch {
+	case rotateStorageDryRun:
+		result.Accounts, err = files.Check()
+	case store != nil:
+		result.Accounts, err = files.MoveTo(store)
+	default:
+		result.Accounts, err = files.Rotate(passphrase)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate token storage: %w", err)
+	}
+	if p := keyring.CurrentProtector(); p != nil && store == nil {
+		// The spec names the key only once it has been used
+		result.Protector = p.Spec()
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode rotation: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(result.Accounts) == 0 {
+		cmd.Println("No token files to rotate")
+		return nil
+	}
+	for _, acc := range result.Accounts {
+		cmd.Printf("  %-30s %d key(s)\n", acc.Account, acc.Keys)
+	}
+	switch {
+	case rotateStorageDryRun:
+		cmd.Printf("Dry run: all %d token file(s) can be read; nothing was changed\n", len(result.Accounts))
+	case store != nil:
+		cmd.Printf("Moved %d account(s) into the %s keyring and deleted the token files\n", len(result.Accounts), result.Destination)
+		if keyring.CurrentBackend() == keyring.BackendFile {
+			cmd.Println("keyring.backend is file: run 'goog config set keyring.backend auto' to use the keyring")
+		}
+	default:
+		cmd.Printf("Re-encrypted %d token file(s)\n", len(result.Accounts))
+		if result.Protector != "" {
+			cmd.Printf("The files are bound to %s\n", result.Protector)
+		}
+		if rotateStoragePassphrase {
+			cmd.Printf("Set %s to the new passphrase: goog cannot read the tokens without it\n", keyring.PassphraseEnv)
+		}
+	}
+	return nil
+}
+
+// readNewPassphrase asks for a new passphrase twice. On a terminal the
+// input is not echoed.
+func readNewPassphrase(cmd *cobra.Command) (string, error) {
+	p := newPrompter(cmd)
+	read := func(question string) (string, error) {
+		if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+			cmd.Printf("%s: ", question)
+			data, err := term.ReadPassword(int(f.Fd()))
+			cmd.Println()
+			return string(data), err
+		}
+		return p.ask(question, "")
+	}
+
+	passphrase, err := read("New passphrase")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("passphrase must not be empty: use --remove-passphrase to remove it")
+	}
+	repeated, err := read("Repeat passphrase")
+	if err != nil {
+		return "", err
+	}
+	if repeated != passphrase {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+)
+
+// setupRotateStorageTest points the command at token files in a temp dir
+// holding one account.
+func setupRotateStorageTest(t *testing.T) (string, *bytes.Buffer, *cobra.Command) {
+	t.Helper()
+	t.Setenv(keyring.PassphraseEnv, "")
+	dir := t.TempDir()
+	files, err := keyring.NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := files.Set("work", "token", []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	origOpen, origSystem, origFormat := openTokenFiles, openSystemKeyring, formatFlag
+	origPass, origRemove, origTo, origDry := rotateStoragePassphrase, rotateStorageRemovePassphrase, rotateStorageToKeyring, rotateStorageDryRun
+	openTokenFiles = func() (*keyring.FileStore, error) { return keyring.NewFileStore(dir) }
+	formatFlag = "table"
+	rotateStoragePassphrase, rotateStorageRemovePassphrase, rotateStorageToKeyring, rotateStorageDryRun = false, false, false, false
+	t.Cleanup(func() {
+		openTokenFiles, openSystemKeyring, formatFlag = origOpen, origSystem, origFormat
+		rotateStoragePassphrase, rotateStorageRemovePassphrase, rotateStorageToKeyring, rotateStorageDryRun = origPass, origRemove, origTo, origDry
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return dir, buf, cmd
+}
+
+func TestRunAuthRotateStorage(t *testing.T) {
+	t.Run("sets a passphrase", func(t *testing.T) {
+		dir, buf, cmd := setupRotateStorageTest(t)
+		rotateStoragePassphrase = true
+		cmd.SetIn(strings.NewReader("hunter2\nhunter2\n"))
+
+		if err := runAuthRotateStorage(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !contains(buf.String(), "Re-encrypted 1 token file(s)") || !contains(buf.String(), keyring.PassphraseEnv) {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+
+		t.Setenv(keyring.PassphraseEnv, "hunter2")
+		files, _ := keyring.NewFileStore(dir)
+		if value, err := files.Get("work", "token"); err != nil || string(value) != "secret" {
+			t.Errorf("Get = %q, %v", value, err)
+		}
+	})
+
+	t.Run("rejects mismatched passphrases", func(t *testing.T) {
+		_, _, cmd := setupRotateStorageTest(t)
+		rotateStoragePassphrase = true
+		cmd.SetIn(strings.NewReader("hunter2\nhunter3\n"))
+
+		if err := runAuthRotateStorage(cmd, nil); err == nil || !contains(err.Error(), "do not match") {
+			t.Errorf("expected a mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("moves tokens into the keyring", func(t *testing.T) {
+		dir, buf, cmd := setupRotateStorageTest(t)
+		system, _ := keyring.NewFileStore(t.TempDir())
+		openSystemKeyring = func() (keyring.Store, string, error) { return system, keyring.BackendSecretService, nil }
+		rotateStorageToKeyring = true
+		formatFlag = "json"
+
+		if err := runAuthRotateStorage(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out rotateStorageJSON
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if out.Destination != keyring.BackendSecretService || len(out.Accounts) != 1 || out.Accounts[0].Keys != 1 {
+			t.Errorf("unexpected result: %+v", out)
+		}
+		if value, err := system.Get("work", "token"); err != nil || string(value) != "secret" {
+			t.Errorf("keyring Get = %q, %v", value, err)
+		}
+		files, _ := keyring.NewFileStore(dir)
+		if accounts, _ := files.Accounts(); len(accounts) != 0 {
+			t.Errorf("expected the token files to be deleted, got %v", accounts)
+		}
+	})
+
+	t.Run("keeps files when the keyring is unavailable", func(t *testing.T) {
+		dir, _, cmd := setupRotateStorageTest(t)
+		openSystemKeyring = func() (keyring.Store, string, error) { return nil, "", errors.New("no dbus") }
+		rotateStorageToKeyring = true
+
+		if err := runAuthRotateStorage(cmd, nil); err == nil || !contains(err.Error(), "were not changed") {
+			t.Errorf("expected an unavailable keyring error, got %v", err)
+		}
+		files, _ := keyring.NewFileStore(dir)
+		if accounts, _ := files.Accounts(); len(accounts) != 1 {
+			t.Errorf("expected the token files to be kept, got %v", accounts)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		_, buf, cmd := setupRotateStorageTest(t)
+		rotateStorageDryRun = true
+
+		if err := runAuthRotateStorage(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !contains(buf.String(), "nothing was changed") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+}
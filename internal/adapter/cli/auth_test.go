@@ -383,6 +383,15 @@ func (m *MockAccountServiceExtended) Remove(alias string) error {
 	return m.RemoveErr
 }
 
+// RemoveAccount removes an account through Remove and reports a cleanup of
+// the token and scopes.
+func (m *MockAccountServiceExtended) RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error) {
+	if err := m.Remove(alias); err != nil {
+		return nil, err
+	}
+	return &accountuc.RemovalReport{Alias: alias, Revoked: revoke, DeletedKeys: []string{"oauth_scopes", "oauth_token"}}, nil
+}
+
 func (m *MockAccountServiceExtended) Switch(alias string) error {
 	if m.SwitchFunc != nil {
 		return m.SwitchFunc(alias)
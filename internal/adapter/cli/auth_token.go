@@ -0,0 +1,187 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+)
+
+// Command flags for auth token commands.
+var (
+	authTokenScopes  []string
+	authTokenXOAuth2 bool
+)
+
+// scopeImpliedBy lists broader scopes that also grant a narrower scope.
+var scopeImpliedBy = map[string][]string{
+	auth.ScopeGmailReadonly:    {auth.ScopeGmailModify},
+	auth.ScopeGmailSend:        {auth.ScopeGmailModify, auth.ScopeGmailCompose},
+	auth.ScopeGmailLabels:      {auth.ScopeGmailModify},
+	auth.ScopeCalendarReadonly: {auth.ScopeCalendar, auth.ScopeCalendarEvents},
+	auth.ScopeCalendarEvents:   {auth.ScopeCalendar},
+	auth.ScopeTasksReadonly:    {auth.ScopeTasks},
+	auth.ScopeContactsReadonly: {auth.ScopeContacts},
+	auth.ScopeDriveReadonly:    {auth.ScopeDrive},
+	auth.ScopeDriveFile:        {auth.ScopeDrive},
+}
+
+// authTokenCmd represents the auth token command group.
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Hand out access tokens to other programs",
+	Long: `Hand out OAuth access tokens to other programs.
+
+This lets goog act as the single OAuth broker on a machine: mail
+clients such as msmtp or mbsync call goog to obtain a fresh token
+instead of storing their own credentials.`,
+}
+
+// authTokenPrintCmd prints a short-lived access token.
+var authTokenPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print a short-lived access token",
+	Long: `Print a short-lived OAuth access token for the current account.
+
+The token is refreshed if it has expired. Use --scope to check that
+the account was granted the scope the calling program needs; a token
+is never issued for scopes that were not granted at login.
+
+Use --xoauth2 to print the base64-encoded SASL XOAUTH2 string used by
+SMTP and IMAP clients instead of the bare token.
+
+The token is written to stdout with no other output so it can be used
+directly from passwordeval-style settings. Treat it as a secret.`,
+	Example: `  # Print an access token for sending mail
+  goog auth token print --scope gmail.send
+
+  # Print an XOAUTH2 string for a specific account
+  goog auth token print --scope gmail.send --xoauth2 --account work
+
+  # msmtp configuration
+  #   auth oauthbearer
+  #   passwordeval "goog auth token print --scope gmail.send"`,
+	Args: cobra.NoArgs,
+	RunE: runAuthTokenPrint,
+}
+
+func init() {
+	authCmd.AddCommand(authTokenCmd)
+	authTokenCmd.AddCommand(authTokenPrintCmd)
+
+	authTokenPrintCmd.Flags().StringSliceVar(&authTokenScopes, "scope", nil, "scope(s) the token must carry, e.g. gmail.send")
+	authTokenPrintCmd.Flags().BoolVar(&authTokenXOAuth2, "xoauth2", false, "print a base64-encoded SASL XOAUTH2 string")
+}
+
+// authTokenJSON is the JSON output of auth token print.
+type authTokenJSON struct {
+	Account     string `json:"account"`
+	Email       string `json:"email"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Expiry      string `json:"expiry,omitempty"`
+	XOAuth2     string `json:"xoauth2,omitempty"`
+}
+
+// runAuthTokenPrint handles the auth token print command.
+func runAuthTokenPrint(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	svc := getAccountServiceFromDeps()
+
+	acc, err := svc.ResolveAccount(accountFlag)
+	if err != nil {
+		return fmt.Errorf("no account found: %w", err)
+	}
+
+	tokenMgr := svc.GetTokenManager()
+
+	if len(authTokenScopes) > 0 {
+		granted, err := tokenMgr.GetGrantedScopes(acc.Alias)
+		if err != nil && !errors.Is(err, auth.ErrScopesNotSet) {
+			return fmt.Errorf("failed to get granted scopes: %w", err)
+		}
+		// Accounts added before scopes were recorded cannot be checked
+		if err == nil {
+			if missing := missingScopes(expandScopes(authTokenScopes), granted); len(missing) > 0 {
+				return fmt.Errorf("account %s was not granted %v; run 'goog auth login --account %s --scopes ...' to add it",
+					acc.Alias, missing, acc.Alias)
+			}
+		}
+	}
+
+	tokenSource, err := tokenMgr.GetTokenSource(ctx, acc.Alias)
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("no access token available for %s", acc.Alias)
+	}
+
+	var xoauth2 string
+	if authTokenXOAuth2 {
+		xoauth2 = buildXOAuth2(acc.Email, token.AccessToken)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		out := authTokenJSON{
+			Account:     acc.Alias,
+			Email:       acc.Email,
+			AccessToken: token.AccessToken,
+			TokenType:   token.Type(),
+			XOAuth2:     xoauth2,
+		}
+		if !token.Expiry.IsZero() {
+			out.Expiry = token.Expiry.Format(time.RFC3339)
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode token: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if authTokenXOAuth2 {
+		cmd.Println(xoauth2)
+	} else {
+		cmd.Println(token.AccessToken)
+	}
+	return nil
+}
+
+// expandScopes converts scope shorthand to full scope URLs without adding
+// the identity scopes that parseScopes always requests at login.
+func expandScopes(scopes []string) []string {
+	full := parseScopes(scopes)
+	return full[:len(scopes)]
+}
+
+// missingScopes returns the required scopes that are neither granted
+// directly nor implied by a broader granted scope.
+func missingScopes(required, granted []string) []string {
+	var missing []string
+	for _, s := range required {
+		if checkScope(s, granted).Status == scopeMissing {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// buildXOAuth2 returns the base64-encoded SASL XOAUTH2 initial response.
+func buildXOAuth2(email, accessToken string) string {
+	raw := fmt.Sprintf("user=%s\x01auth=Bearer %s\x01\x01", email, accessToken)
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
@@ -0,0 +1,189 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+	"golang.org/x/oauth2"
+)
+
+// setupAuthTokenTest injects an account with the given token manager and
+// resets token flags.
+func setupAuthTokenTest(t *testing.T, tokenMgr TokenManager) *bytes.Buffer {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "work", Email: "me@example.com"},
+			TokenManager: tokenMgr,
+		},
+		RepoFactory: &MockRepositoryFactory{},
+	})
+
+	origScopes, origXOAuth2, origFormat := authTokenScopes, authTokenXOAuth2, formatFlag
+	authTokenScopes = nil
+	authTokenXOAuth2 = false
+	formatFlag = "table"
+	t.Cleanup(func() {
+		ResetDependencies()
+		authTokenScopes, authTokenXOAuth2, formatFlag = origScopes, origXOAuth2, origFormat
+	})
+
+	return new(bytes.Buffer)
+}
+
+// tokenManagerWithToken returns a token manager that issues the given access token.
+func tokenManagerWithToken(accessToken string, granted []string) *MockTokenManager {
+	return &MockTokenManager{
+		TokenSource: &MockTokenSource{token: &oauth2.Token{
+			AccessToken: accessToken,
+			TokenType:   "Bearer",
+			Expiry:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		GrantedScopes: granted,
+	}
+}
+
+func TestAuthTokenPrintCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(authCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"auth", "token", "print", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--scope", "--xoauth2", "msmtp"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunAuthTokenPrint(t *testing.T) {
+	t.Run("prints bare token", func(t *testing.T) {
+		buf := setupAuthTokenTest(t, tokenManagerWithToken("ya29.token", []string{auth.ScopeGmailSend}))
+		authTokenScopes = []string{"gmail.send"}
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthTokenPrint(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "ya29.token\n" {
+			t.Errorf("output = %q, want bare token", buf.String())
+		}
+	})
+
+	t.Run("prints xoauth2 string", func(t *testing.T) {
+		buf := setupAuthTokenTest(t, tokenManagerWithToken("ya29.token", nil))
+		authTokenXOAuth2 = true
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthTokenPrint(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(buf.String()))
+		if err != nil {
+			t.Fatalf("output is not base64: %v", err)
+		}
+		if string(decoded) != "user=me@example.com\x01auth=Bearer ya29.token\x01\x01" {
+			t.Errorf("decoded = %q", decoded)
+		}
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		buf := setupAuthTokenTest(t, tokenManagerWithToken("ya29.token", nil))
+		formatFlag = "json"
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runAuthTokenPrint(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out authTokenJSON
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if out.Account != "work" || out.AccessToken != "ya29.token" || out.TokenType != "Bearer" || out.Expiry != "2030-01-01T00:00:00Z" {
+			t.Errorf("unexpected JSON: %+v", out)
+		}
+		if out.XOAuth2 != "" {
+			t.Errorf("expected no xoauth2 without --xoauth2, got %q", out.XOAuth2)
+		}
+	})
+
+	t.Run("broader scope satisfies request", func(t *testing.T) {
+		setupAuthTokenTest(t, tokenManagerWithToken("ya29.token", []string{auth.ScopeGmailModify}))
+		authTokenScopes = []string{"gmail.send"}
+
+		if err := runAuthTokenPrint(&cobra.Command{Use: "test"}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		setupAuthTokenTest(t, tokenManagerWithToken("ya29.token", []string{auth.ScopeGmailReadonly}))
+		authTokenScopes = []string{"gmail.send"}
+
+		err := runAuthTokenPrint(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "was not granted") || !containsStr(err.Error(), auth.ScopeGmailSend) {
+			t.Errorf("expected missing scope error, got %v", err)
+		}
+	})
+
+	t.Run("scopes not recorded", func(t *testing.T) {
+		tokenMgr := tokenManagerWithToken("ya29.token", nil)
+		tokenMgr.GrantedScopesErr = auth.ErrScopesNotSet
+		setupAuthTokenTest(t, tokenMgr)
+		authTokenScopes = []string{"gmail.send"}
+
+		if err := runAuthTokenPrint(&cobra.Command{Use: "test"}, nil); err != nil {
+			t.Fatalf("expected unrecorded scopes to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("token error", func(t *testing.T) {
+		setupAuthTokenTest(t, &MockTokenManager{TokenSource: &MockTokenSource{err: errors.New("expired")}})
+
+		err := runAuthTokenPrint(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "failed to refresh token") {
+			t.Errorf("expected refresh error, got %v", err)
+		}
+	})
+}
+
+func TestMissingScopes(t *testing.T) {
+	granted := []string{auth.ScopeCalendar, auth.ScopeUserInfoEmail}
+	required := []string{auth.ScopeCalendarReadonly, auth.ScopeUserInfoEmail, auth.ScopeDriveFile}
+
+	missing := missingScopes(required, granted)
+	if len(missing) != 1 || missing[0] != auth.ScopeDriveFile {
+		t.Errorf("missingScopes() = %v, want [%s]", missing, auth.ScopeDriveFile)
+	}
+}
+
+func TestExpandScopes(t *testing.T) {
+	got := expandScopes([]string{"gmail.send", "email"})
+	if len(got) != 2 || got[0] != auth.ScopeGmailSend || got[1] != auth.ScopeUserInfoEmail {
+		t.Errorf("expandScopes() = %v", got)
+	}
+}
@@ -0,0 +1,273 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Command flags for auth verify.
+var (
+	authVerifyRequire  []string
+	authVerifyFor      string
+	authVerifyExitCode bool
+)
+
+// Scope check results reported by auth verify.
+const (
+	scopeGranted = "granted"
+	scopeImplied = "implied"
+	scopeMissing = "missing"
+	scopeUnknown = "unknown"
+)
+
+// authVerifyCmd checks that the stored credentials cover what a job needs.
+var authVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the stored credentials have the scopes a job needs",
+	Long: `Check that the stored credentials of an account can be used and were
+granted the scopes a job needs, before the job runs.
+
+The required scopes come from --require (scope shorthand such as
+gmail.send or full scope URLs) and from --for, which takes the commands
+the job runs and looks up the scopes they need. A scope is satisfied
+when it was granted or a broader granted scope implies it, e.g.
+gmail.modify implies gmail.send. The access token is also refreshed to
+check it still works.
+
+The check fails when the token cannot be refreshed, a scope is missing,
+or the account was added before goog recorded granted scopes. Granted
+scopes broader than the job needs are listed, since a narrower login
+would do; they do not fail the check.
+
+With --exit-code the command exits with status 1 when the check fails,
+so pipelines can stop early; without it only the report shows the
+result.`,
+	Example: `  # Fail the pipeline unless the CI account can send mail and edit events
+  goog auth verify --require gmail.send,calendar.events --account ci@corp.com --exit-code
+
+  # Check the scopes of the commands a job runs, as JSON
+  goog auth verify --for "mail send, cal create" --format json --exit-code`,
+	Args: cobra.NoArgs,
+	RunE: runAuthVerify,
+}
+
+func init() {
+	authCmd.AddCommand(authVerifyCmd)
+
+	authVerifyCmd.Flags().StringSliceVar(&authVerifyRequire, "require", nil, "scopes the job needs (comma-separated), e.g. gmail.send")
+	authVerifyCmd.Flags().StringVar(&authVerifyFor, "for", "", "commands the job runs (comma-separated), e.g. \"mail send, cal today\"")
+	authVerifyCmd.Flags().BoolVar(&authVerifyExitCode, "exit-code", false, "exit with status 1 when the check fails")
+}
+
+// scopeCheckJSON is the result for one required scope.
+type scopeCheckJSON struct {
+	Scope  string `json:"scope"`
+	Status string `json:"status"`
+	// Via is the broader granted scope that implies an implied scope.
+	Via string `json:"via,omitempty"`
+}
+
+// authVerifyJSON is the JSON output of auth verify.
+type authVerifyJSON struct {
+	Account    string           `json:"account"`
+	Email      string           `json:"email"`
+	OK         bool             `json:"ok"`
+	TokenValid bool             `json:"token_valid"`
+	TokenError string           `json:"token_error,omitempty"`
+	Expiry     string           `json:"expiry,omitempty"`
+	Scopes     []scopeCheckJSON `json:"scopes"`
+	Missing    []string         `json:"missing"`
+	Broader    []string         `json:"broader"`
+	Problems   []string         `json:"problems"`
+}
+
+// runAuthVerify handles the auth verify command.
+func runAuthVerify(cmd *cobra.Command, args []string) error {
+	required, err := requiredScopes(authVerifyRequire, authVerifyFor)
+	if err != nil {
+		return err
+	}
+
+	svc := getAccountServiceFromDeps()
+	acc, err := svc.ResolveAccount(accountFlag)
+	if err != nil {
+		return fmt.Errorf("no account found: %w", err)
+	}
+
+	result := authVerifyJSON{
+		Account:  acc.Alias,
+		Email:    acc.Email,
+		Scopes:   []scopeCheckJSON{},
+		Missing:  []string{},
+		Broader:  []string{},
+		Problems: []string{},
+	}
+	tokenMgr := svc.GetTokenManager()
+
+	if tokenSource, err := tokenMgr.GetTokenSource(context.Background(), acc.Alias); err != nil {
+		result.TokenError = err.Error()
+	} else if token, err := tokenSource.Token(); err != nil {
+		result.TokenError = err.Error()
+	} else if token.AccessToken == "" {
+		result.TokenError = "no access token available"
+	} else {
+		result.TokenValid = true
+		if !token.Expiry.IsZero() {
+			result.Expiry = token.Expiry.Format(time.RFC3339)
+		}
+	}
+	if !result.TokenValid {
+		result.Problems = append(result.Problems, "token cannot be used: "+result.TokenError)
+	}
+
+	granted, err := tokenMgr.GetGrantedScopes(acc.Alias)
+	switch {
+	case errors.Is(err, auth.ErrScopesNotSet):
+		for _, scope := range required {
+			result.Scopes = append(result.Scopes, scopeCheckJSON{Scope: scope, Status: scopeUnknown})
+		}
+		result.Problems = append(result.Problems, fmt.Sprintf("granted scopes were not recorded; run 'goog auth login --account %s' again", acc.Alias))
+	case err != nil:
+		return fmt.Errorf("failed to get granted scopes: %w", err)
+	default:
+		for _, scope := range required {
+			check := checkScope(scope, granted)
+			result.Scopes = append(result.Scopes, check)
+			if check.Status == scopeMissing {
+				result.Missing = append(result.Missing, scope)
+			}
+		}
+		result.Broader = append(result.Broader, broaderScopes(granted, required)...)
+		if len(result.Missing) > 0 {
+			result.Problems = append(result.Problems, fmt.Sprintf("%d required scope(s) missing; run 'goog auth login --account %s --scopes ...' to add them", len(result.Missing), acc.Alias))
+		}
+	}
+	result.OK = len(result.Problems) == 0
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode verification: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		printAuthVerify(cmd, result)
+	}
+
+	if !result.OK && authVerifyExitCode {
+		return fmt.Errorf("%w: credentials of %s do not cover the job", errReported, acc.Alias)
+	}
+	return nil
+}
+
+// requiredScopes returns the full URLs of the scopes given with --require
+// and the scopes the commands given with --for need, sorted and without
+// duplicates.
+func requiredScopes(require []string, forCommands string) ([]string, error) {
+	if len(require) == 0 && forCommands == "" {
+		return nil, errors.New("name the scopes with --require or the commands with --for")
+	}
+
+	set := make(map[string]bool)
+	for i, scope := range expandScopes(require) {
+		if !strings.HasPrefix(scope, "https://") {
+			return nil, fmt.Errorf("unknown scope %q: use shorthand such as gmail.send or a full scope URL", require[i])
+		}
+		set[scope] = true
+	}
+	if forCommands != "" {
+		commands := parseCommandList(forCommands)
+		if len(commands) == 0 {
+			return nil, errors.New("--for needs at least one command")
+		}
+		var aliases map[string]string
+		if cfg, err := config.Load(); err == nil {
+			aliases = cfg.Aliases
+		}
+		scopes, err := scopesForCommands(commands, aliases)
+		if err != nil {
+			return nil, err
+		}
+		for _, scope := range scopes {
+			set[scope] = true
+		}
+	}
+
+	scopes := make([]string, 0, len(set))
+	for scope := range set {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+// checkScope reports whether scope was granted directly, is implied by a
+// broader granted scope, or is missing.
+func checkScope(scope string, granted []string) scopeCheckJSON {
+	have := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		have[s] = true
+	}
+	if have[scope] {
+		return scopeCheckJSON{Scope: scope, Status: scopeGranted}
+	}
+	for _, broader := range scopeImpliedBy[scope] {
+		if have[broader] {
+			return scopeCheckJSON{Scope: scope, Status: scopeImplied, Via: broader}
+		}
+	}
+	return scopeCheckJSON{Scope: scope, Status: scopeMissing}
+}
+
+// printAuthVerify prints the verification as text.
+func printAuthVerify(cmd *cobra.Command, result authVerifyJSON) {
+	cmd.Printf("Account:  %s (%s)\n", result.Account, result.Email)
+	if result.TokenValid {
+		token := "valid"
+		if result.Expiry != "" {
+			token += ", expires " + result.Expiry
+		}
+		cmd.Printf("Token:    %s\n", token)
+	} else {
+		cmd.Printf("Token:    INVALID (%s)\n", result.TokenError)
+	}
+
+	cmd.Println("Scopes:")
+	for _, check := range result.Scopes {
+		mark := "ok "
+		if check.Status == scopeMissing || check.Status == scopeUnknown {
+			mark = "!! "
+		}
+		line := fmt.Sprintf("  %s%-50s %s", mark, check.Scope, check.Status)
+		if check.Via != "" {
+			line += " by " + check.Via
+		}
+		cmd.Println(line)
+	}
+	if len(result.Broader) > 0 {
+		cmd.Println("Broader than needed:")
+		for _, scope := range result.Broader {
+			cmd.Printf("  - %s\n", scope)
+		}
+	}
+
+	if result.OK {
+		cmd.Println("Result:   OK")
+		return
+	}
+	cmd.Println("Result:   FAIL")
+	for _, problem := range result.Problems {
+		cmd.Printf("  - %s\n", problem)
+	}
+}
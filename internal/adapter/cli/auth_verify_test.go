@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+)
+
+// setupAuthVerifyTest injects an account with the given token manager and
+// resets the verify flags.
+func setupAuthVerifyTest(t *testing.T, tokenMgr TokenManager) (*bytes.Buffer, *cobra.Command) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	buf := setupAuthTokenTest(t, tokenMgr)
+
+	origRequire, origFor, origExit := authVerifyRequire, authVerifyFor, authVerifyExitCode
+	authVerifyRequire, authVerifyFor, authVerifyExitCode = nil, "", false
+	t.Cleanup(func() { authVerifyRequire, authVerifyFor, authVerifyExitCode = origRequire, origFor, origExit })
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	return buf, cmd
+}
+
+func TestRunAuthVerify(t *testing.T) {
+	granted := []string{auth.ScopeGmailModify, auth.ScopeCalendar, auth.ScopeDrive, auth.ScopeUserInfoEmail}
+
+	t.Run("passes with granted and implied scopes", func(t *testing.T) {
+		buf, cmd := setupAuthVerifyTest(t, tokenManagerWithToken("ya29.token", granted))
+		authVerifyRequire = []string{"gmail.send", "calendar.full"}
+		authVerifyExitCode = true
+		formatFlag = "json"
+
+		if err := runAuthVerify(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out authVerifyJSON
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if !out.OK || !out.TokenValid || len(out.Missing) != 0 {
+			t.Errorf("unexpected result: %+v", out)
+		}
+		want := []scopeCheckJSON{
+			{Scope: auth.ScopeCalendar, Status: scopeGranted},
+			{Scope: auth.ScopeGmailSend, Status: scopeImplied, Via: auth.ScopeGmailModify},
+		}
+		if len(out.Scopes) != 2 || out.Scopes[0] != want[0] || out.Scopes[1] != want[1] {
+			t.Errorf("scopes = %+v, want %+v", out.Scopes, want)
+		}
+		if len(out.Broader) != 2 || out.Broader[0] != auth.ScopeGmailModify || out.Broader[1] != auth.ScopeDrive {
+			t.Errorf("broader = %v", out.Broader)
+		}
+	})
+
+	t.Run("fails on missing scopes with exit code", func(t *testing.T) {
+		buf, cmd := setupAuthVerifyTest(t, tokenManagerWithToken("ya29.token", []string{auth.ScopeGmailReadonly}))
+		authVerifyFor = "mail send, cal today"
+		authVerifyExitCode = true
+
+		err := runAuthVerify(cmd, nil)
+		if !errors.Is(err, errReported) {
+			t.Fatalf("expected errReported, got %v", err)
+		}
+		for _, want := range []string{auth.ScopeGmailSend + " ", "missing", "Result:   FAIL", "2 required scope(s) missing"} {
+			if !contains(buf.String(), want) {
+				t.Errorf("expected output to contain %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("reports without exit code", func(t *testing.T) {
+		_, cmd := setupAuthVerifyTest(t, tokenManagerWithToken("ya29.token", nil))
+		authVerifyRequire = []string{"gmail.send"}
+
+		if err := runAuthVerify(cmd, nil); err != nil {
+			t.Errorf("expected no error without --exit-code, got %v", err)
+		}
+	})
+
+	t.Run("fails when the token cannot be refreshed", func(t *testing.T) {
+		buf, cmd := setupAuthVerifyTest(t, &MockTokenManager{Err: errors.New("token revoked"), GrantedScopes: granted})
+		authVerifyRequire = []string{"gmail.send"}
+		authVerifyExitCode = true
+
+		if err := runAuthVerify(cmd, nil); !errors.Is(err, errReported) {
+			t.Fatalf("expected errReported, got %v", err)
+		}
+		if !contains(buf.String(), "INVALID (token revoked)") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("fails when scopes were not recorded", func(t *testing.T) {
+		mgr := tokenManagerWithToken("ya29.token", nil)
+		mgr.GrantedScopesErr = auth.ErrScopesNotSet
+		buf, cmd := setupAuthVerifyTest(t, mgr)
+		authVerifyRequire = []string{"gmail.send"}
+		authVerifyExitCode = true
+
+		if err := runAuthVerify(cmd, nil); !errors.Is(err, errReported) {
+			t.Fatalf("expected errReported, got %v", err)
+		}
+		if !contains(buf.String(), "unknown") || !contains(buf.String(), "not recorded") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+}
+
+func TestRequiredScopes(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	if _, err := requiredScopes(nil, ""); err == nil {
+		t.Error("expected an error without --require or --for")
+	}
+	if _, err := requiredScopes([]string{"gmail.sned"}, ""); err == nil || !contains(err.Error(), "unknown scope") {
+		t.Errorf("expected an unknown scope error, got %v", err)
+	}
+	scopes, err := requiredScopes([]string{"gmail.send"}, "mail send")
+	if err != nil || len(scopes) != 1 || scopes[0] != auth.ScopeGmailSend {
+		t.Errorf("scopes = %v, %v", scopes, err)
+	}
+}
@@ -0,0 +1,317 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/bridge"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+)
+
+// Command flags for bridge commands.
+var (
+	bridgeIMAPListen      string
+	bridgeIMAPPassword    string
+	bridgeIMAPMaxMessages int
+
+	bridgeCalDAVListen         string
+	bridgeCalDAVPassword       string
+	bridgeCalDAVCalendars      []string
+	bridgeCalDAVPast           string
+	bridgeCalDAVFuture         string
+	bridgeCalDAVTLSCert        string
+	bridgeCalDAVTLSKey         string
+	bridgeCalDAVAllowPlaintext bool
+)
+
+// bridgeCmd represents the bridge command group.
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Serve Google data to local clients over standard protocols",
+	Long: `Serve Google data to local clients over standard protocols.
+
+Bridges let existing clients reuse goog's authentication instead of
+managing their own OAuth credentials. Bridges are read-only and listen
+on the loopback interface unless told otherwise.`,
+}
+
+// bridgeIMAPCmd serves Gmail over IMAP.
+var bridgeIMAPCmd = &cobra.Command{
+	Use:   "imap",
+	Short: "Serve Gmail over a read-only IMAP server",
+	Long: `Serve the Gmail account over a minimal read-only IMAP4rev1 server.
+
+Gmail labels appear as mailboxes (INBOX, Sent, Drafts, Spam, Trash,
+Starred, Important, and user labels). Each mailbox shows its most
+recent --max-messages messages. Message UIDs are stable while the
+bridge runs; restarting it changes UIDVALIDITY, so clients resync.
+
+Clients log in with the account email address and the bridge
+password. Unless --password is given, a random password is generated
+and printed at startup. The connection is not encrypted, so the
+bridge only listens on loopback addresses.
+
+Flag changes, copies, moves and deletes are rejected. Press Ctrl+C to
+stop the bridge.`,
+	Example: `  # Start the bridge on the default port
+  goog bridge imap
+
+  # Use a fixed password and a larger window
+  goog bridge imap --listen 127.0.0.1:1143 --password s3cret --max-messages 2000
+
+  # mutt configuration
+  #   set folder = "imap://me@example.com@127.0.0.1:1143/"
+  #   set imap_pass = "s3cret"
+  #   set ssl_starttls = no`,
+	Args: cobra.NoArgs,
+	RunE: runBridgeIMAP,
+}
+
+// bridgeCalDAVCmd serves Google Calendar over CalDAV.
+var bridgeCalDAVCmd = &cobra.Command{
+	Use:   "caldav",
+	Short: "Serve calendars over a read-only CalDAV server",
+	Long: `Serve Google calendars over a read-only CalDAV server.
+
+Every calendar the account can read is exposed unless --calendar
+selects specific ones ("primary" names the primary calendar). Events
+from --past before now to --future after now are served; recurring
+events appear as individual occurrences. Event data is cached for a
+minute, so changes in Google Calendar show up on the next sync after
+that.
+
+Clients authenticate with HTTP Basic auth using the account email
+address and the bridge password. Unless --password is given, a random
+password is generated and printed at startup. Point clients at
+http://HOST:PORT/ (or /dav/principal/); CalDAV discovery finds the
+calendars from there.
+
+By default the bridge listens on loopback only. To serve phones or
+other machines on the LAN, listen on a LAN address and provide
+--tls-cert and --tls-key, or pass --allow-plaintext on a trusted
+network. Creating, changing and deleting events is rejected. Press
+Ctrl+C to stop the bridge.`,
+	Example: `  # Serve all calendars locally (Thunderbird on the same machine)
+  goog bridge caldav
+
+  # Serve the primary calendar to an iPhone on the LAN over TLS
+  goog bridge caldav --listen 192.168.1.20:5232 --calendar primary \
+    --tls-cert cert.pem --tls-key key.pem
+
+  # Serve a wider window of events
+  goog bridge caldav --past 90d --future 104w`,
+	Args: cobra.NoArgs,
+	RunE: runBridgeCalDAV,
+}
+
+func init() {
+	rootCmd.AddCommand(bridgeCmd)
+	bridgeCmd.AddCommand(bridgeIMAPCmd)
+	bridgeCmd.AddCommand(bridgeCalDAVCmd)
+
+	bridgeIMAPCmd.Flags().StringVar(&bridgeIMAPListen, "listen", "127.0.0.1:1143", "loopback address to listen on")
+	bridgeIMAPCmd.Flags().StringVar(&bridgeIMAPPassword, "password", "", "password clients log in with (default: generated)")
+	bridgeIMAPCmd.Flags().IntVar(&bridgeIMAPMaxMessages, "max-messages", bridge.DefaultMaxMessages, "most recent messages shown per mailbox")
+
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVListen, "listen", "127.0.0.1:5232", "address to listen on")
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVPassword, "password", "", "password clients log in with (default: generated)")
+	bridgeCalDAVCmd.Flags().StringSliceVar(&bridgeCalDAVCalendars, "calendar", nil, "calendar ID to expose (repeatable, default: all)")
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVPast, "past", "30d", "how far back to serve events (e.g. 30d, 12w, 2025-01-01)")
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVFuture, "future", "365d", "how far ahead to serve events (e.g. 365d, 26w, 2026-12-31)")
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVTLSCert, "tls-cert", "", "TLS certificate file (PEM)")
+	bridgeCalDAVCmd.Flags().StringVar(&bridgeCalDAVTLSKey, "tls-key", "", "TLS private key file (PEM)")
+	bridgeCalDAVCmd.Flags().BoolVar(&bridgeCalDAVAllowPlaintext, "allow-plaintext", false, "allow unencrypted connections on non-loopback addresses")
+}
+
+// runBridgeIMAP handles the bridge imap command.
+func runBridgeIMAP(cmd *cobra.Command, args []string) error {
+	if bridgeIMAPMaxMessages <= 0 {
+		return fmt.Errorf("--max-messages must be positive")
+	}
+	if err := requireLoopback(bridgeIMAPListen); err != nil {
+		return err
+	}
+
+	password := bridgeIMAPPassword
+	if password == "" {
+		var err error
+		if password, err = generateBridgePassword(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	msgRepo, email, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	server := bridge.NewIMAPServer(msgRepo, labelRepo, bridge.IMAPConfig{
+		Username:    email,
+		Password:    password,
+		MaxMessages: bridgeIMAPMaxMessages,
+		CacheLookup: recordCacheLookup("imap.raw"),
+		Logf: func(format string, args ...any) {
+			output.Verbosef(cmd, format, args...)
+		},
+	})
+
+	ln, err := net.Listen("tcp", bridgeIMAPListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", bridgeIMAPListen, err)
+	}
+
+	cmd.Printf("IMAP bridge listening on %s (Ctrl+C to stop)\n", ln.Addr())
+	cmd.Printf("Username: %s\n", email)
+	if bridgeIMAPPassword == "" {
+		cmd.Printf("Password: %s\n", password)
+	}
+
+	return server.Serve(ctx, ln)
+}
+
+// runBridgeCalDAV handles the bridge caldav command.
+func runBridgeCalDAV(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	since, err := dateparse.ParseSinceAt(bridgeCalDAVPast, now)
+	if err != nil {
+		return fmt.Errorf("invalid --past: %w", err)
+	}
+	until, err := dateparse.ParseUntilAt(bridgeCalDAVFuture, now)
+	if err != nil {
+		return fmt.Errorf("invalid --future: %w", err)
+	}
+	past, future := now.Sub(since), until.Sub(now)
+
+	useTLS := bridgeCalDAVTLSCert != "" || bridgeCalDAVTLSKey != ""
+	if useTLS && (bridgeCalDAVTLSCert == "" || bridgeCalDAVTLSKey == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	loopback, err := isLoopbackAddr(bridgeCalDAVListen)
+	if err != nil {
+		return err
+	}
+	if !loopback && !useTLS && !bridgeCalDAVAllowPlaintext {
+		return fmt.Errorf("listen address %s is not a loopback address; provide --tls-cert and --tls-key, or --allow-plaintext on a trusted network", bridgeCalDAVListen)
+	}
+
+	password := bridgeCalDAVPassword
+	if password == "" {
+		if password, err = generateBridgePassword(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	_, email, err := getTokenSourceWithEmailFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	calRepo, err := getCalendarRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	handler := bridge.NewCalDAVServer(calRepo, eventRepo, bridge.CalDAVConfig{
+		Username:    email,
+		Password:    password,
+		CalendarIDs: bridgeCalDAVCalendars,
+		Past:        past,
+		Future:      future,
+		CacheLookup: recordCacheLookup("caldav"),
+		Logf: func(format string, args ...any) {
+			output.Verbosef(cmd, format, args...)
+		},
+	})
+
+	ln, err := net.Listen("tcp", bridgeCalDAVListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", bridgeCalDAVListen, err)
+	}
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	scheme := "http"
+	if useTLS {
+		scheme = "https"
+	}
+	cmd.Printf("CalDAV bridge listening on %s://%s/ (Ctrl+C to stop)\n", scheme, ln.Addr())
+	cmd.Printf("Username: %s\n", email)
+	if bridgeCalDAVPassword == "" {
+		cmd.Printf("Password: %s\n", password)
+	}
+
+	if useTLS {
+		err = server.ServeTLS(ln, bridgeCalDAVTLSCert, bridgeCalDAVTLSKey)
+	} else {
+		err = server.Serve(ln)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("CalDAV bridge failed: %w", err)
+	}
+	return nil
+}
+
+// requireLoopback rejects listen addresses outside the loopback interface,
+// since bridges speak plaintext protocols.
+func requireLoopback(addr string) error {
+	loopback, err := isLoopbackAddr(addr)
+	if err != nil {
+		return err
+	}
+	if !loopback {
+		return fmt.Errorf("listen address %s is not a loopback address; bridges are unencrypted and only serve local clients", addr)
+	}
+	return nil
+}
+
+// isLoopbackAddr reports whether a host:port listen address is on the
+// loopback interface.
+func isLoopbackAddr(addr string) (bool, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return true, nil
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback(), nil
+}
+
+// generateBridgePassword returns a random password for bridge clients.
+func generateBridgePassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
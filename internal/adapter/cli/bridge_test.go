@@ -0,0 +1,241 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupBridgeIMAPTest injects repositories and resets bridge flags.
+func setupBridgeIMAPTest(t *testing.T, factory *MockRepositoryFactory) {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: factory,
+	})
+
+	origListen, origPassword, origMax := bridgeIMAPListen, bridgeIMAPPassword, bridgeIMAPMaxMessages
+	bridgeIMAPListen = "127.0.0.1:0"
+	bridgeIMAPPassword = ""
+	bridgeIMAPMaxMessages = 500
+	t.Cleanup(func() {
+		ResetDependencies()
+		bridgeIMAPListen, bridgeIMAPPassword, bridgeIMAPMaxMessages = origListen, origPassword, origMax
+	})
+}
+
+func TestBridgeIMAPCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(bridgeCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"bridge", "imap", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--listen", "--password", "--max-messages", "read-only"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRequireLoopback(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:1143", false},
+		{"[::1]:1143", false},
+		{"localhost:1143", false},
+		{"0.0.0.0:1143", true},
+		{"192.168.1.10:1143", true},
+		{":1143", true},
+		{"127.0.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if err := requireLoopback(tt.addr); (err != nil) != tt.wantErr {
+				t.Errorf("requireLoopback(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateBridgePassword(t *testing.T) {
+	a, err := generateBridgePassword()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := generateBridgePassword()
+	if len(a) != 24 || a == b {
+		t.Errorf("expected distinct 24-character passwords, got %q and %q", a, b)
+	}
+}
+
+func TestRunBridgeIMAP_Errors(t *testing.T) {
+	t.Run("remote listen address", func(t *testing.T) {
+		setupBridgeIMAPTest(t, &MockRepositoryFactory{})
+		bridgeIMAPListen = "0.0.0.0:1143"
+
+		err := runBridgeIMAP(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "not a loopback address") {
+			t.Errorf("expected loopback error, got %v", err)
+		}
+	})
+
+	t.Run("invalid max messages", func(t *testing.T) {
+		setupBridgeIMAPTest(t, &MockRepositoryFactory{})
+		bridgeIMAPMaxMessages = 0
+
+		err := runBridgeIMAP(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "--max-messages") {
+			t.Errorf("expected max-messages error, got %v", err)
+		}
+	})
+
+	t.Run("label repository error", func(t *testing.T) {
+		setupBridgeIMAPTest(t, &MockRepositoryFactory{
+			MessageRepo: &MockMessageRepository{},
+			LabelErr:    errors.New("boom"),
+		})
+
+		err := runBridgeIMAP(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "boom") {
+			t.Errorf("expected repository error, got %v", err)
+		}
+	})
+}
+
+// setupBridgeCalDAVTest injects repositories and resets CalDAV bridge flags.
+func setupBridgeCalDAVTest(t *testing.T, factory *MockRepositoryFactory) {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: factory,
+	})
+
+	origListen, origPassword, origCalendars := bridgeCalDAVListen, bridgeCalDAVPassword, bridgeCalDAVCalendars
+	origPast, origFuture := bridgeCalDAVPast, bridgeCalDAVFuture
+	origCert, origKey, origPlain := bridgeCalDAVTLSCert, bridgeCalDAVTLSKey, bridgeCalDAVAllowPlaintext
+	bridgeCalDAVListen = "127.0.0.1:0"
+	bridgeCalDAVPassword = ""
+	bridgeCalDAVCalendars = nil
+	bridgeCalDAVPast, bridgeCalDAVFuture = "30d", "365d"
+	bridgeCalDAVTLSCert, bridgeCalDAVTLSKey, bridgeCalDAVAllowPlaintext = "", "", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		bridgeCalDAVListen, bridgeCalDAVPassword, bridgeCalDAVCalendars = origListen, origPassword, origCalendars
+		bridgeCalDAVPast, bridgeCalDAVFuture = origPast, origFuture
+		bridgeCalDAVTLSCert, bridgeCalDAVTLSKey, bridgeCalDAVAllowPlaintext = origCert, origKey, origPlain
+	})
+}
+
+func TestBridgeCalDAVCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(bridgeCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"bridge", "caldav", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--listen", "--calendar", "--past", "--future", "--tls-cert", "--allow-plaintext", "read-only"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	if ok, err := isLoopbackAddr("127.0.0.1:5232"); !ok || err != nil {
+		t.Errorf("expected loopback, got %v, %v", ok, err)
+	}
+	if ok, err := isLoopbackAddr("192.168.1.20:5232"); ok || err != nil {
+		t.Errorf("expected non-loopback, got %v, %v", ok, err)
+	}
+	if _, err := isLoopbackAddr("5232"); err == nil {
+		t.Error("expected error for address without port")
+	}
+}
+
+func TestRunBridgeCalDAV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func()
+		factory *MockRepositoryFactory
+		wantErr string
+	}{
+		{
+			name:    "invalid past",
+			setup:   func() { bridgeCalDAVPast = "soon" },
+			wantErr: "--past",
+		},
+		{
+			name:    "invalid future",
+			setup:   func() { bridgeCalDAVFuture = "-3d" },
+			wantErr: "--future",
+		},
+		{
+			name:    "partial TLS configuration",
+			setup:   func() { bridgeCalDAVTLSCert = "cert.pem" },
+			wantErr: "must be given together",
+		},
+		{
+			name:    "LAN address without TLS",
+			setup:   func() { bridgeCalDAVListen = "0.0.0.0:5232" },
+			wantErr: "--allow-plaintext",
+		},
+		{
+			name:    "calendar repository error",
+			factory: &MockRepositoryFactory{CalendarErr: errors.New("calendar boom")},
+			wantErr: "calendar boom",
+		},
+		{
+			name:    "event repository error",
+			factory: &MockRepositoryFactory{CalendarRepo: &MockCalendarRepository{}, EventErr: errors.New("event boom")},
+			wantErr: "event boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := tt.factory
+			if factory == nil {
+				factory = &MockRepositoryFactory{}
+			}
+			setupBridgeCalDAVTest(t, factory)
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := runBridgeCalDAV(&cobra.Command{Use: "test"}, nil)
+			if err == nil || !containsStr(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 )
@@ -14,6 +16,13 @@ import (
 // Command flags for calendar event list/show commands.
 var (
 	calListMaxResults int
+	calListRange      string
+
+	// Show flags
+	calShowAttachments bool
+	calShowDownload    bool
+	calShowDest        string
+	calShowQR          bool
 )
 
 // getGCalEventRepository creates a GCalEventRepository using the current account's credentials.
@@ -33,8 +42,14 @@ var calListCmd = &cobra.Command{
 	Long: `List upcoming events from your Google Calendar.
 
 By default, lists events from the primary calendar for the next
-30 days. Use --calendar to specify a different calendar and
---max-results to limit the number of events returned.`,
+30 days. Use --range to choose another period, --calendar to specify
+a different calendar and --max-results to limit the number of events
+returned.
+
+--range accepts a single day ("tomorrow", "2025-08-01"), an offset
+from now ("+7d", "-2w"), or two expressions joined by ".."
+("2025-08-01..2025-08-15", "monday..friday", "today..+3d"). A date
+without a time at the end of a range includes that whole day.`,
 	Example: `  # List upcoming events
   goog cal list
 
@@ -45,7 +60,13 @@ By default, lists events from the primary calendar for the next
   goog cal list --format json
 
   # Limit number of results
-  goog cal list --max-results 10`,
+  goog cal list --max-results 10
+
+  # List events in a date range
+  goog cal list --range 2025-08-01..2025-08-15
+
+  # List events since last Monday
+  goog cal list --range "last monday..now"`,
 	Aliases: []string{"ls"},
 	RunE:    runCalList,
 }
@@ -57,7 +78,16 @@ var calShowCmd = &cobra.Command{
 	Long: `Show detailed information about a specific calendar event.
 
 Retrieves and displays all available information about the
-specified event including attendees, location, and conference data.`,
+specified event including attendees, location, and conference data.
+
+With --attachments, lists the files attached to the event instead.
+Add --download to save the Drive files into --dest; this needs the
+drive.readonly scope (goog auth login --scopes drive.readonly). Google
+Docs, Sheets and Slides are saved as PDF.
+
+--qr adds the event's Google Calendar link as a QR code after it, to
+scan it onto a phone. The link selects the account as 'goog cal open'
+does.`,
 	Example: `  # Show event details
   goog cal show abc123def456
 
@@ -65,7 +95,16 @@ specified event including attendees, location, and conference data.`,
   goog cal show abc123def456 --format json
 
   # Show event from a specific calendar
-  goog cal show abc123def456 --calendar work@group.calendar.google.com`,
+  goog cal show abc123def456 --calendar work@group.calendar.google.com
+
+  # List the event's attachments
+  goog cal show abc123def456 --attachments
+
+  # Download the attachments into ./handouts
+  goog cal show abc123def456 --attachments --download --dest ./handouts
+
+  # Add a QR code to open the event on a phone
+  goog cal show abc123def456 --qr`,
 	Aliases: []string{"get"},
 	Args:    cobra.ExactArgs(1),
 	RunE:    runCalShow,
@@ -96,11 +135,16 @@ var calWeekCmd = &cobra.Command{
 	Short: "Show this week's events",
 	Long: `Show all events scheduled for the current week.
 
-Lists all events from the start of the current week (Monday)
-to the end of the week (Sunday) in your local timezone.`,
+Lists all events of the current week in your local timezone. The
+week begins on the day set by calendar.week_start (sunday or monday).
+With --grid, the week is drawn as seven day columns sized to the
+terminal width.`,
 	Example: `  # Show this week's events
   goog cal week
 
+  # Show this week as a grid
+  goog cal week --grid
+
   # Show this week's events from a specific calendar
   goog cal week --calendar work@group.calendar.google.com
 
@@ -119,9 +163,14 @@ func init() {
 	// List command flags
 	calListCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to use")
 	calListCmd.Flags().IntVar(&calListMaxResults, "max-results", 25, "maximum number of events to return")
+	calListCmd.Flags().StringVar(&calListRange, "range", "", "time range to list, e.g. 2025-08-01..2025-08-15 or +7d (default: next 30 days)")
 
 	// Show command flags
 	calShowCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to use")
+	calShowCmd.Flags().BoolVar(&calShowAttachments, "attachments", false, "list the event's attachments")
+	calShowCmd.Flags().BoolVar(&calShowDownload, "download", false, "download the attachments (implies --attachments)")
+	calShowCmd.Flags().StringVar(&calShowDest, "dest", ".", "directory to download attachments into")
+	calShowCmd.Flags().BoolVar(&calShowQR, "qr", false, "also print the event's Google Calendar link as a QR code")
 
 	// Today command flags
 	calTodayCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to use")
@@ -140,10 +189,27 @@ func runCalList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Calculate time range (now to 30 days from now)
+	// Calculate time range (now to 30 days from now unless --range is set)
 	now := time.Now()
 	timeMin := now
 	timeMax := now.AddDate(0, 0, 30)
+	if calListRange != "" {
+		start, end, err := dateparse.ParseRangeAt(calListRange, now)
+		if err != nil {
+			return fmt.Errorf("invalid --range: %w", err)
+		}
+		if !start.IsZero() {
+			timeMin = start
+		}
+		if !end.IsZero() {
+			timeMax = end
+		} else {
+			timeMax = timeMin.AddDate(0, 0, 30)
+		}
+		if !timeMin.Before(timeMax) {
+			return fmt.Errorf("invalid --range: end is not after start")
+		}
+	}
 
 	// List events
 	events, err := repo.List(ctx, calCalendarFlag, timeMin, timeMax)
@@ -157,7 +223,7 @@ func runCalList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvents(events)
@@ -171,6 +237,13 @@ func runCalShow(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	eventID := args[0]
 
+	if calShowQR && (calShowAttachments || calShowDownload) {
+		return fmt.Errorf("--qr cannot be used with --attachments")
+	}
+	if err := checkQRFormat(calShowQR); err != nil {
+		return err
+	}
+
 	// Get event repository using dependency injection
 	repo, err := getEventRepositoryFromDeps(ctx)
 	if err != nil {
@@ -183,13 +256,26 @@ func runCalShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get event: %w", err)
 	}
 
+	if calShowAttachments || calShowDownload {
+		return showEventAttachments(ctx, cmd, event, calShowDownload, calShowDest)
+	}
+
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvent(event)
 	cmd.Println(output)
 
+	if calShowQR {
+		link, err := eventLink(event)
+		if err != nil {
+			return err
+		}
+		cmd.Println()
+		return printQR(cmd, link)
+	}
+
 	return nil
 }
 
@@ -216,7 +302,7 @@ func runCalToday(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvents(events)
@@ -240,27 +326,23 @@ func runCalWeek(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Calculate this week's time range (Monday to Sunday)
-	now := time.Now()
-	loc := now.Location()
-
-	// Find the start of the week (Monday)
-	weekday := int(now.Weekday())
-	if weekday == 0 {
-		weekday = 7 // Sunday is day 7
-	}
-	daysFromMonday := weekday - 1
-	startOfWeek := time.Date(now.Year(), now.Month(), now.Day()-daysFromMonday, 0, 0, 0, 0, loc)
-	endOfWeek := startOfWeek.AddDate(0, 0, 7)
+	// Calculate this week's time range from the configured first day
+	weekStart := startOfWeek(time.Now(), configuredWeekStart())
+	weekEnd := weekStart.AddDate(0, 0, 7)
 
 	// List events for this week
-	events, err := repo.List(ctx, calCalendarFlag, startOfWeek, endOfWeek)
+	events, err := repo.List(ctx, calCalendarFlag, weekStart, weekEnd)
 	if err != nil {
 		return fmt.Errorf("failed to list this week's events: %w", err)
 	}
 
+	if calWeekGrid && strings.EqualFold(formatFlag, presenter.FormatTable) && !presenter.Accessible() {
+		cmd.Print(presenter.RenderWeekGrid(events, weekStart, terminalWidth()))
+		return nil
+	}
+
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvents(events)
@@ -268,7 +350,7 @@ func runCalWeek(cmd *cobra.Command, args []string) error {
 
 	// Show event count if not quiet
 	if !quietFlag && len(events) > 0 {
-		cmd.Printf("\n%d event(s) for week of %s\n", len(events), startOfWeek.Format("January 2, 2006"))
+		cmd.Printf("\n%d event(s) for week of %s\n", len(events), weekStart.Format("January 2, 2006"))
 	}
 
 	return nil
@@ -4,11 +4,12 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
 )
 
 // ACL command flags.
@@ -25,7 +26,16 @@ var aclCmd = &cobra.Command{
 	Long: `Manage access control list (ACL) rules for Google Calendars.
 
 ACL rules control who can access a calendar and what permissions they have.
-Available roles are: reader, writer, owner, freeBusyReader.`,
+Available roles are: reader, writer, owner, freeBusyReader.
+
+A <calendar-id> may also be the calendar's name as 'goog cal calendars
+list' shows it, ignoring case and with dashes for spaces: "work-calendar"
+is the calendar named "Work Calendar". A name shared by several calendars
+must be given by ID.
+
+Calendars are shared with a person by email address, or with a group,
+a whole domain or everyone by prefixing group:, domain: or using
+"default" (public): group:team@example.com, domain:example.com.`,
 }
 
 // aclListCmd lists ACL rules for a calendar.
@@ -41,6 +51,9 @@ Shows who has access to the calendar and their permission level.`,
   # List sharing rules for a specific calendar
   goog cal acl list "example@group.calendar.google.com"
 
+  # List sharing rules for a calendar by name
+  goog cal acl list work-calendar
+
   # List with JSON output
   goog cal acl list primary --format json`,
 	Aliases: []string{"ls"},
@@ -54,8 +67,10 @@ var aclAddCmd = &cobra.Command{
 	Short: "Add a sharing rule",
 	Long: `Add a new access control rule to share a calendar with a user.
 
-You must specify the email address and role for the new rule.
-Available roles: reader (view only), writer (edit events), owner (full control).`,
+You must specify the email address and role for the new rule. --email
+also takes group:, domain: and default grantees, as 'goog cal share'
+does. Available roles: reader (view only), writer (edit events), owner
+(full control), freeBusyReader (free/busy only).`,
 	Example: `  # Share calendar with a user as reader
   goog cal acl add primary --email user@example.com --role reader
 
@@ -80,8 +95,8 @@ var aclRemoveCmd = &cobra.Command{
 	Short: "Remove a sharing rule",
 	Long: `Remove an access control rule from a calendar.
 
-This will revoke the user's access to the calendar.
-Requires --confirm flag for safety.`,
+This will revoke the user's access to the calendar. A person's rule
+may be given by their email address alone instead of its rule ID. Requires --confirm flag for safety.`,
 	Example: `  # Remove a sharing rule (requires confirmation)
   goog cal acl remove primary "user:user@example.com" --confirm
 
@@ -100,21 +115,30 @@ Requires --confirm flag for safety.`,
 
 // shareCmd is a user-friendly alias for acl add.
 var shareCmd = &cobra.Command{
-	Use:   "share <calendar-id>",
+	Use:   "share <calendar-id> [grantee]",
 	Short: "Share a calendar with a user",
 	Long: `Share a calendar with a user by adding an access control rule.
 
-This is a user-friendly alias for 'goog cal acl add'.
-You must specify the email address and role for sharing.
-Available roles: reader (view only), writer (edit events), owner (full control).`,
-	Example: `  # Share calendar with a user as reader
-  goog cal share primary --email user@example.com --role reader
+This is a user-friendly alias for 'goog cal acl add'. The calendar is
+given by ID or by name, and the grantee as the second argument or with
+--email: an email address, group:<address> for a Google Group,
+domain:<name> for everyone in a domain, or default for everyone.
+Available roles: reader (view only), writer (edit events), owner (full
+control), freeBusyReader (free/busy only).`,
+	Example: `  # Share the "Work Calendar" calendar with a user as reader
+  goog cal share work-calendar alice@example.com --role reader
 
   # Share calendar with a user as writer
-  goog cal share "mywork@group.calendar.google.com" --email colleague@example.com --role writer`,
-	Args: cobra.ExactArgs(1),
+  goog cal share "mywork@group.calendar.google.com" --email colleague@example.com --role writer
+
+  # Let everyone at the company see free/busy times
+  goog cal share primary domain:example.com --role freeBusyReader`,
+	Args: cobra.RangeArgs(1, 2),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if aclEmail == "" {
+		if len(args) == 2 && aclEmail != "" {
+			return fmt.Errorf("give who to share with as an argument or with --email, not both")
+		}
+		if len(args) < 2 && aclEmail == "" {
 			return fmt.Errorf("required flag \"email\" not set")
 		}
 		return nil
@@ -131,7 +155,7 @@ var unshareCmd = &cobra.Command{
 This is a user-friendly alias for 'goog cal acl remove'.
 Requires --confirm flag for safety.`,
 	Example: `  # Unshare calendar from a user (requires confirmation)
-  goog cal unshare primary "user:user@example.com" --confirm
+  goog cal unshare work-calendar user@example.com --confirm
 
   # Unshare from a specific calendar
   goog cal unshare "mywork@group.calendar.google.com" "user:colleague@example.com" --confirm`,
@@ -146,7 +170,7 @@ func init() {
 	aclCmd.AddCommand(aclRemoveCmd)
 
 	// Add flags to aclAddCmd
-	aclAddCmd.Flags().StringVar(&aclEmail, "email", "", "email address of the user to share with (required)")
+	aclAddCmd.Flags().StringVar(&aclEmail, "email", "", "email address to share with, or group:, domain: or default (required)")
 	aclAddCmd.Flags().StringVar(&aclRole, "role", "reader", "access role: reader, writer, owner, freeBusyReader")
 	_ = aclAddCmd.MarkFlagRequired("email")
 
@@ -154,9 +178,8 @@ func init() {
 	aclRemoveCmd.Flags().BoolVar(&aclConfirm, "confirm", false, "confirm removal of sharing rule")
 
 	// Add flags to shareCmd (alias for aclAddCmd)
-	shareCmd.Flags().StringVar(&aclEmail, "email", "", "email address of the user to share with (required)")
+	shareCmd.Flags().StringVar(&aclEmail, "email", "", "email address of the user to share with, unless given as an argument")
 	shareCmd.Flags().StringVar(&aclRole, "role", "reader", "access role: reader, writer, owner, freeBusyReader")
-	_ = shareCmd.MarkFlagRequired("email")
 
 	// Add flags to unshareCmd (alias for aclRemoveCmd)
 	unshareCmd.Flags().BoolVar(&aclConfirm, "confirm", false, "confirm removal of sharing rule")
@@ -186,7 +209,10 @@ func getACLRepository(ctx context.Context) (*repository.GCalACLRepository, error
 // runACLList handles the acl list command.
 func runACLList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	calendarID := args[0]
+	calendarID, err := resolveCalendarRef(ctx, args[0])
+	if err != nil {
+		return err
+	}
 
 	// Get repository using dependency injection
 	repo, err := getACLRepositoryFromDeps(ctx)
@@ -200,7 +226,7 @@ func runACLList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderACLRules(rules)
 	cmd.Println(output)
@@ -211,7 +237,15 @@ func runACLList(cmd *cobra.Command, args []string) error {
 // runACLAdd handles the acl add and share commands.
 func runACLAdd(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	calendarID := args[0]
+
+	grantee := aclEmail
+	if len(args) > 1 {
+		grantee = args[1]
+	}
+	scope, err := parseACLGrantee(grantee)
+	if err != nil {
+		return err
+	}
 
 	// Validate role
 	validRoles := map[string]bool{
@@ -224,6 +258,11 @@ func runACLAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid role %q: must be one of reader, writer, owner, freeBusyReader", aclRole)
 	}
 
+	calendarID, err := resolveCalendarRef(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
 	// Get repository using dependency injection
 	repo, err := getACLRepositoryFromDeps(ctx)
 	if err != nil {
@@ -231,7 +270,6 @@ func runACLAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create new ACL rule
-	scope := calendar.NewUserACLScope(aclEmail)
 	rule := calendar.NewACLRule(scope, aclRole)
 
 	created, err := repo.Insert(ctx, calendarID, rule)
@@ -240,14 +278,18 @@ func runACLAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderACLRule(created))
 	} else {
 		cmd.Printf("Sharing rule added successfully.\n")
 		cmd.Printf("ID: %s\n", created.ID)
-		cmd.Printf("Email: %s\n", aclEmail)
+		if scope.IsUser() {
+			cmd.Printf("Email: %s\n", scope.Value)
+		} else {
+			cmd.Printf("Scope: %s\n", formatACLScope(scope))
+		}
 		cmd.Printf("Role: %s\n", created.Role)
 	}
 
@@ -257,14 +299,18 @@ func runACLAdd(cmd *cobra.Command, args []string) error {
 // runACLRemove handles the acl remove and unshare commands.
 func runACLRemove(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	calendarID := args[0]
-	ruleID := args[1]
+	ruleID := aclRuleID(args[1])
 
 	// Require confirmation
 	if !aclConfirm {
 		return fmt.Errorf("removal requires --confirm flag")
 	}
 
+	calendarID, err := resolveCalendarRef(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
 	// Get repository using dependency injection
 	repo, err := getACLRepositoryFromDeps(ctx)
 	if err != nil {
@@ -294,3 +340,96 @@ func runACLRemove(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// resolveCalendarRef returns the ID of the calendar ref names. IDs are
+// returned as they are: "primary" and anything with an @, as every other
+// calendar ID is an address. Other refs are looked up among the account's
+// calendars by name, ignoring case and treating dashes, underscores and
+// spaces alike.
+func resolveCalendarRef(ctx context.Context, ref string) (string, error) {
+	if ref == "primary" || strings.Contains(ref, "@") {
+		return ref, nil
+	}
+
+	repo, err := getCalendarRepositoryFromDeps(ctx)
+	if err != nil {
+		return "", err
+	}
+	calendars, err := repo.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up calendar %q: %w", ref, err)
+	}
+
+	var matches []string
+	for _, cal := range calendars {
+		if cal.ID == ref {
+			return cal.ID, nil
+		}
+		if calendarNameKey(cal.Title) == calendarNameKey(ref) {
+			matches = append(matches, cal.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no calendar named %q; see 'goog cal calendars list'", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d calendars are named %q; give one by ID: %s", len(matches), ref, strings.Join(matches, ", "))
+	}
+}
+
+// calendarNameKey folds a calendar name for matching: lower case, with
+// runs of spaces, dashes and underscores made a single dash.
+func calendarNameKey(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "-")
+}
+
+// parseACLGrantee parses who a calendar is shared with: an email address,
+// group:<address>, domain:<name>, or default for everyone. user:<address>
+// is accepted too, as rule IDs are written that way.
+func parseACLGrantee(grantee string) (*calendar.ACLScope, error) {
+	grantee = strings.TrimSpace(grantee)
+	if strings.EqualFold(grantee, calendar.ACLScopeTypeDefault) {
+		return calendar.NewDefaultACLScope(), nil
+	}
+
+	scopeType, value, found := strings.Cut(grantee, ":")
+	if !found {
+		scopeType, value = calendar.ACLScopeTypeUser, grantee
+	}
+	switch strings.ToLower(scopeType) {
+	case calendar.ACLScopeTypeUser, calendar.ACLScopeTypeGroup:
+		addr, err := mail.ValidateAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return calendar.NewACLScope(strings.ToLower(scopeType), addr), nil
+	case calendar.ACLScopeTypeDomain:
+		if value == "" || strings.ContainsAny(value, "@ ") {
+			return nil, fmt.Errorf("invalid domain %q", value)
+		}
+		return calendar.NewDomainACLScope(strings.ToLower(value)), nil
+	default:
+		return nil, fmt.Errorf("invalid grantee %q: give an email address, group:<address>, domain:<name> or default", grantee)
+	}
+}
+
+// formatACLScope writes a scope the way rule IDs do, e.g. "domain:example.com".
+func formatACLScope(scope *calendar.ACLScope) string {
+	if scope.IsDefault() || scope.Value == "" {
+		return scope.Type
+	}
+	return scope.Type + ":" + scope.Value
+}
+
+// aclRuleID returns the rule ID an argument names: a bare email address
+// stands for the rule sharing with that person.
+func aclRuleID(arg string) string {
+	if !strings.Contains(arg, ":") && strings.Contains(arg, "@") {
+		return calendar.ACLScopeTypeUser + ":" + arg
+	}
+	return arg
+}
@@ -3,6 +3,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
@@ -382,7 +383,7 @@ func TestCalCmd_HasShareSubcommand(t *testing.T) {
 	// Verify that cal command has share subcommand
 	found := false
 	for _, sub := range calCmd.Commands() {
-		if sub.Use == "share <calendar-id>" {
+		if sub.Name() == "share" {
 			found = true
 			break
 		}
@@ -544,9 +545,14 @@ func TestShareCmd_ArgsValidation(t *testing.T) {
 			args:      []string{"primary"},
 			expectErr: false,
 		},
+		{
+			name:      "calendar and grantee",
+			args:      []string{"primary", "user@example.com"},
+			expectErr: false,
+		},
 		{
 			name:      "too many args",
-			args:      []string{"primary", "extra"},
+			args:      []string{"primary", "user@example.com", "extra"},
 			expectErr: true,
 		},
 	}
@@ -928,3 +934,133 @@ func TestRunACLRemove_RuleNotFound(t *testing.T) {
 		t.Errorf("expected rule not found error, got: %v", err)
 	}
 }
+
+func TestRunACLAdd_CalendarByNameAndGrantee(t *testing.T) {
+	aclRepo := &MockACLRepository{}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			ACLRepo: aclRepo,
+			CalendarRepo: &MockCalendarRepository{Calendars: []*calendar.Calendar{
+				{ID: "primary", Title: "test@example.com"},
+				{ID: "abc123@group.calendar.google.com", Title: "Work Calendar"},
+			}},
+		},
+	})
+	defer ResetDependencies()
+
+	origEmail, origRole, origFormat := aclEmail, aclRole, formatFlag
+	aclEmail, aclRole, formatFlag = "", "writer", "plain"
+	defer func() { aclEmail, aclRole, formatFlag = origEmail, origRole, origFormat }()
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := runACLAdd(cmd, []string{"work-calendar", "group:Team@Example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aclRepo.InsertedCalendarID != "abc123@group.calendar.google.com" {
+		t.Errorf("calendar ID = %q", aclRepo.InsertedCalendarID)
+	}
+	scope := aclRepo.InsertedRule.Scope
+	if scope.Type != calendar.ACLScopeTypeGroup || scope.Value != "Team@Example.com" || aclRepo.InsertedRule.Role != "writer" {
+		t.Errorf("rule = %+v %+v", aclRepo.InsertedRule, scope)
+	}
+	if !contains(buf.String(), "Scope: group:Team@Example.com") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestResolveCalendarRef(t *testing.T) {
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			CalendarRepo: &MockCalendarRepository{Calendars: []*calendar.Calendar{
+				{ID: "a@group.calendar.google.com", Title: "Work Calendar"},
+				{ID: "b@group.calendar.google.com", Title: "Family"},
+				{ID: "c@group.calendar.google.com", Title: "family"},
+			}},
+		},
+	})
+	defer ResetDependencies()
+
+	ctx := context.Background()
+	for ref, want := range map[string]string{
+		"primary":                     "primary",
+		"x@group.calendar.google.com": "x@group.calendar.google.com",
+		"work_calendar":               "a@group.calendar.google.com",
+		"WORK CALENDAR":               "a@group.calendar.google.com",
+	} {
+		if got, err := resolveCalendarRef(ctx, ref); err != nil || got != want {
+			t.Errorf("resolveCalendarRef(%q) = %q, %v, want %q", ref, got, err, want)
+		}
+	}
+	if _, err := resolveCalendarRef(ctx, "family"); err == nil || !contains(err.Error(), "2 calendars") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+	if _, err := resolveCalendarRef(ctx, "holidays"); err == nil || !contains(err.Error(), "no calendar named") {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+}
+
+func TestParseACLGrantee(t *testing.T) {
+	for grantee, want := range map[string]string{
+		"alice@example.com":      "user:alice@example.com",
+		"user:alice@example.com": "user:alice@example.com",
+		"group:team@example.com": "group:team@example.com",
+		"domain:Example.com":     "domain:example.com",
+		"default":                "default",
+	} {
+		scope, err := parseACLGrantee(grantee)
+		if err != nil || formatACLScope(scope) != want {
+			t.Errorf("parseACLGrantee(%q) = %v, %v, want %s", grantee, scope, err, want)
+		}
+	}
+	for _, grantee := range []string{"alice", "domain:", "domain:a@b.com", "robot:x@example.com"} {
+		if _, err := parseACLGrantee(grantee); err == nil {
+			t.Errorf("parseACLGrantee(%q): expected an error", grantee)
+		}
+	}
+}
+
+func TestRunACLRemove_ByEmail(t *testing.T) {
+	aclRepo := &MockACLRepository{Rule: &calendar.ACLRule{ID: "user:user@example.com", Role: "reader"}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{ACLRepo: aclRepo},
+	})
+	defer ResetDependencies()
+
+	origConfirm := aclConfirm
+	aclConfirm = true
+	defer func() { aclConfirm = origConfirm }()
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&bytes.Buffer{})
+	if err := runACLRemove(cmd, []string{"primary", "user@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aclRepo.DeletedRuleID != "user:user@example.com" {
+		t.Errorf("deleted rule = %q", aclRepo.DeletedRuleID)
+	}
+}
+
+func TestShareCmd_GranteeArgAndFlag(t *testing.T) {
+	origEmail := aclEmail
+	aclEmail = "user@example.com"
+	defer func() { aclEmail = origEmail }()
+
+	if err := shareCmd.PreRunE(shareCmd, []string{"primary", "other@example.com"}); err == nil {
+		t.Error("expected an error when the grantee is given twice")
+	}
+}
@@ -0,0 +1,140 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+)
+
+// driveOpenURL is the link format Calendar uses for attached Drive files.
+const driveOpenURL = "https://drive.google.com/open?id="
+
+// parseDriveFileID returns the Drive file ID in value, which is either a
+// bare ID or a Drive or Docs link such as
+// https://docs.google.com/document/d/<id>/edit or
+// https://drive.google.com/open?id=<id>.
+func parseDriveFileID(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("empty Drive file ID")
+	}
+	if !strings.Contains(value, "/") {
+		return value, nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid Drive file %q: use a file ID or a Drive link", value)
+	}
+	if id := u.Query().Get("id"); id != "" {
+		return id, nil
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "d" && parts[i+1] != "" {
+			return parts[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no file ID found in %q", value)
+}
+
+// driveAttachments converts --attach-drive values into event attachments.
+func driveAttachments(values []string) ([]*calendar.Attachment, error) {
+	attachments := make([]*calendar.Attachment, 0, len(values))
+	for _, value := range values {
+		id, err := parseDriveFileID(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --attach-drive value: %w", err)
+		}
+		attachments = append(attachments, &calendar.Attachment{FileURL: driveOpenURL + id, FileID: id})
+	}
+	return attachments, nil
+}
+
+// attachmentJSON is the JSON representation of an event attachment.
+type attachmentJSON struct {
+	Title    string `json:"title"`
+	MimeType string `json:"mime_type,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	FileURL  string `json:"file_url"`
+	File     string `json:"file,omitempty"`
+}
+
+// showEventAttachments lists the attachments of event, downloading them to
+// dest when download is set.
+func showEventAttachments(ctx context.Context, cmd *cobra.Command, event *calendar.Event, download bool, dest string) error {
+	entries := make([]attachmentJSON, 0, len(event.Attachments))
+	for _, a := range event.Attachments {
+		entries = append(entries, attachmentJSON{Title: a.Title, MimeType: a.MimeType, FileID: a.FileID, FileURL: a.FileURL})
+	}
+
+	if len(entries) == 0 && formatFlag != presenter.FormatJSON {
+		cmd.Println("No attachments")
+		return nil
+	}
+
+	if download && len(entries) > 0 {
+		if err := requireScope(auth.ScopeDriveReadonly, "cal show"); err != nil {
+			return err
+		}
+		repo, err := getDriveFileRepositoryFromDeps(ctx)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dest, 0o755); err != nil {
+			return fmt.Errorf("failed to create destination directory: %w", err)
+		}
+		used := make(map[string]bool)
+		for i := range entries {
+			if entries[i].FileID == "" {
+				cmd.PrintErrf("Skipping %s: not a Drive file\n", entries[i].FileURL)
+				continue
+			}
+			name, data, err := repo.Download(ctx, entries[i].FileID)
+			if err != nil {
+				return fmt.Errorf("failed to download %s: %w", entries[i].Title, err)
+			}
+			name = sanitizeFilename(name)
+			if name == "" {
+				name = entries[i].FileID
+			}
+			name = uniqueAttachmentName(name, used)
+			path := filepath.Join(dest, name)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			entries[i].File = path
+			if !quietFlag && formatFlag != presenter.FormatJSON {
+				cmd.Printf("Saved %s (%d bytes)\n", path, len(data))
+			}
+		}
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode attachments: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	if download {
+		return nil
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "TITLE\tTYPE\tFILE ID\tURL")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Title, e.MimeType, e.FileID, e.FileURL)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestParseDriveFileID(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "1AbCdEf", want: "1AbCdEf"},
+		{value: " 1AbCdEf ", want: "1AbCdEf"},
+		{value: "https://drive.google.com/file/d/1AbCdEf/view?usp=sharing", want: "1AbCdEf"},
+		{value: "https://docs.google.com/document/d/1AbCdEf/edit", want: "1AbCdEf"},
+		{value: "https://drive.google.com/open?id=1AbCdEf", want: "1AbCdEf"},
+		{value: "", wantErr: true},
+		{value: "https://drive.google.com/drive/my-drive", wantErr: true},
+		{value: "folder/file", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDriveFileID(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDriveFileID(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDriveFileID(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCalCreate_AttachDrive(t *testing.T) {
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: &MockEventRepository{}},
+	})
+	defer ResetDependencies()
+
+	origTitle, origStart, origEnd, origAttach, origFormat := calCreateTitle, calCreateStart, calCreateEnd, calCreateAttachDrive, formatFlag
+	defer func() {
+		calCreateTitle, calCreateStart, calCreateEnd, calCreateAttachDrive, formatFlag = origTitle, origStart, origEnd, origAttach, origFormat
+	}()
+	calCreateTitle = "Review"
+	calCreateStart = time.Now().Add(24 * time.Hour).Format("2006-01-02 15:04")
+	calCreateEnd = ""
+	calCreateAttachDrive = []string{"https://docs.google.com/document/d/doc1/edit", "file2"}
+	formatFlag = "plain"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runCalCreate(cmd, nil); err != nil {
+		t.Fatalf("runCalCreate failed: %v", err)
+	}
+	want := "Attachments: https://drive.google.com/open?id=doc1, https://drive.google.com/open?id=file2"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in output, got: %s", want, buf.String())
+	}
+
+	calCreateAttachDrive = []string{"https://drive.google.com/drive/my-drive"}
+	if err := runCalCreate(cmd, nil); err == nil || !strings.Contains(err.Error(), "--attach-drive") {
+		t.Errorf("expected an --attach-drive error, got %v", err)
+	}
+}
+
+func TestRunCalShow_Attachments(t *testing.T) {
+	event := &calendar.Event{
+		ID:    "event123",
+		Title: "Planning",
+		Attachments: []*calendar.Attachment{
+			{Title: "Agenda", MimeType: "application/vnd.google-apps.document", FileID: "doc1", FileURL: "https://drive.google.com/open?id=doc1"},
+			{Title: "Slides", MimeType: "application/pdf", FileID: "pdf1", FileURL: "https://drive.google.com/open?id=pdf1"},
+		},
+	}
+	tokens := &MockTokenManager{GrantedScopes: []string{auth.ScopeCalendarReadonly, auth.ScopeDriveReadonly}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com", Commands: []string{"cal today"}},
+			TokenManager: tokens,
+		},
+		RepoFactory: &MockRepositoryFactory{
+			EventRepo:     &MockEventRepository{Event: event},
+			DriveFileRepo: &MockDriveFileRepository{Files: map[string][]byte{"doc1": []byte("doc"), "pdf1": []byte("pdf")}},
+		},
+	})
+	defer ResetDependencies()
+
+	origAttachments, origDownload, origDest, origFormat := calShowAttachments, calShowDownload, calShowDest, formatFlag
+	defer func() {
+		calShowAttachments, calShowDownload, calShowDest, formatFlag = origAttachments, origDownload, origDest, origFormat
+	}()
+	formatFlag = "table"
+
+	t.Run("list", func(t *testing.T) {
+		calShowAttachments, calShowDownload = true, false
+		cmd := &cobra.Command{Use: "test"}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		if err := runCalShow(cmd, []string{"event123"}); err != nil {
+			t.Fatalf("runCalShow failed: %v", err)
+		}
+		for _, want := range []string{"TITLE", "Agenda", "pdf1", "https://drive.google.com/open?id=doc1"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected %q in output, got: %s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("download", func(t *testing.T) {
+		calShowAttachments, calShowDownload = false, true
+		calShowDest = filepath.Join(t.TempDir(), "handouts")
+		cmd := &cobra.Command{Use: "test"}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		if err := runCalShow(cmd, []string{"event123"}); err != nil {
+			t.Fatalf("runCalShow failed: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(calShowDest, "pdf1.bin"))
+		if err != nil || string(data) != "pdf" {
+			t.Errorf("downloaded file = %q, %v", data, err)
+		}
+		if !strings.Contains(buf.String(), "Saved") {
+			t.Errorf("expected saved files to be reported, got: %s", buf.String())
+		}
+	})
+
+	t.Run("download without the Drive scope", func(t *testing.T) {
+		calShowAttachments, calShowDownload = false, true
+		calShowDest = filepath.Join(t.TempDir(), "handouts")
+		tokens.GrantedScopes = []string{auth.ScopeCalendarReadonly}
+		defer func() { tokens.GrantedScopes = []string{auth.ScopeCalendarReadonly, auth.ScopeDriveReadonly} }()
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(new(bytes.Buffer))
+		err := runCalShow(cmd, []string{"event123"})
+		if err == nil || !strings.Contains(err.Error(), "was not granted "+auth.ScopeDriveReadonly) ||
+			!strings.Contains(err.Error(), `goog auth login --account test --for "cal today, cal show"`) {
+			t.Errorf("expected a re-login hint, got %v", err)
+		}
+		if _, err := os.Stat(calShowDest); !os.IsNotExist(err) {
+			t.Error("expected nothing to be downloaded")
+		}
+	})
+
+	t.Run("no attachments", func(t *testing.T) {
+		calShowAttachments, calShowDownload = true, false
+		saved := event.Attachments
+		event.Attachments = nil
+		defer func() { event.Attachments = saved }()
+		cmd := &cobra.Command{Use: "test"}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		if err := runCalShow(cmd, []string{"event123"}); err != nil {
+			t.Fatalf("runCalShow failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "No attachments") {
+			t.Errorf("unexpected output: %s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,297 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// Command flags for calendar availability command.
+var (
+	calAvailabilityNext      string
+	calAvailabilityDuration  time.Duration
+	calAvailabilityTZ        string
+	calAvailabilityHours     string
+	calAvailabilityWeekends  bool
+	calAvailabilityCalendars []string
+)
+
+// calAvailabilityCmd lists open slots for scheduling emails.
+var calAvailabilityCmd = &cobra.Command{
+	Use:   "availability",
+	Short: "List open slots to paste into scheduling emails",
+	Long: `List your open time slots over the coming days.
+
+Busy periods come from the free/busy query used by 'goog cal freebusy'.
+Slots fall within working hours (--hours, in your own time zone),
+skip weekends unless --weekends is given, start on a quarter hour,
+and last at least --duration.
+
+Times are shown in the recipient's time zone when --tz is given, as
+an IANA name such as America/New_York. Use --format md or html for a
+snippet to paste into an email, or --format json for the raw slots.`,
+	Example: `  # Open 30-minute slots over the next 5 days, as Markdown
+  goog cal availability --next 5d --duration 30m --format md
+
+  # Hour-long slots in the recipient's time zone, as HTML
+  goog cal availability --duration 1h --tz Europe/Berlin --format html
+
+  # Consider a shared calendar as well
+  goog cal availability --calendars primary,team@group.calendar.google.com`,
+	Args: cobra.NoArgs,
+	RunE: runCalAvailability,
+}
+
+func init() {
+	calCmd.AddCommand(calAvailabilityCmd)
+
+	calAvailabilityCmd.Flags().StringVar(&calAvailabilityNext, "next", "5d", "how far ahead to look (e.g. 5d, 2w, 36h, friday)")
+	calAvailabilityCmd.Flags().DurationVar(&calAvailabilityDuration, "duration", 30*time.Minute, "minimum slot length")
+	calAvailabilityCmd.Flags().StringVar(&calAvailabilityTZ, "tz", "", "time zone to show slots in (default: local)")
+	calAvailabilityCmd.Flags().StringVar(&calAvailabilityHours, "hours", "09:00-17:00", "working hours in your time zone")
+	calAvailabilityCmd.Flags().BoolVar(&calAvailabilityWeekends, "weekends", false, "include Saturdays and Sundays")
+	calAvailabilityCmd.Flags().StringSliceVar(&calAvailabilityCalendars, "calendars", []string{"primary"}, "calendar IDs to check (comma-separated)")
+}
+
+// runCalAvailability handles the cal availability command.
+func runCalAvailability(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	from := time.Now()
+	until, err := dateparse.ParseUntilAt(calAvailabilityNext, from)
+	if err != nil {
+		return fmt.Errorf("invalid --next value: %w", err)
+	}
+	if calAvailabilityDuration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	hours, err := parseWorkingHours(calAvailabilityHours)
+	if err != nil {
+		return err
+	}
+	hours.Weekends = calAvailabilityWeekends
+
+	loc := time.Local
+	if calAvailabilityTZ != "" {
+		if loc, err = time.LoadLocation(calAvailabilityTZ); err != nil {
+			return fmt.Errorf("invalid --tz value: %w", err)
+		}
+	}
+
+	repo, err := getFreeBusyRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	request, err := calendar.NewFreeBusyRequest(from, until, calAvailabilityCalendars...)
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	response, err := repo.Query(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed to query free/busy: %w", err)
+	}
+
+	var busy []*calendar.TimePeriod
+	if response != nil {
+		for _, periods := range response.Calendars {
+			busy = append(busy, periods...)
+		}
+	}
+	slots := calendar.FreeSlots(hours.Windows(from, until), busy, calAvailabilityDuration, calendar.DefaultSlotStep)
+	for _, slot := range slots {
+		slot.Start, slot.End = slot.Start.In(loc), slot.End.In(loc)
+	}
+
+	switch strings.ToLower(formatFlag) {
+	case presenter.FormatJSON:
+		output, err := renderAvailabilityJSON(slots, loc)
+		if err != nil {
+			return err
+		}
+		cmd.Println(output)
+	case "md", "markdown":
+		cmd.Print(renderAvailabilityMarkdown(slots, loc))
+	case "html":
+		cmd.Print(renderAvailabilityHTML(slots, loc))
+	default:
+		cmd.Print(renderAvailabilityText(slots, loc))
+	}
+	return nil
+}
+
+// parseWorkingHours parses a range such as "09:00-17:00".
+func parseWorkingHours(value string) (calendar.WorkingHours, error) {
+	startStr, endStr, ok := strings.Cut(value, "-")
+	if !ok {
+		return calendar.WorkingHours{}, fmt.Errorf("invalid --hours value %q: use HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return calendar.WorkingHours{}, fmt.Errorf("invalid --hours value %q: %w", value, err)
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return calendar.WorkingHours{}, fmt.Errorf("invalid --hours value %q: %w", value, err)
+	}
+	if end <= start {
+		return calendar.WorkingHours{}, fmt.Errorf("invalid --hours value %q: end must be after start", value)
+	}
+	return calendar.WorkingHours{Start: start, End: end}, nil
+}
+
+// parseClock parses a time of day such as "9:30" or "17:00" as an offset
+// from midnight. "24:00" is accepted as the end of the day.
+func parseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// availabilityDay is the slots that start on one day.
+type availabilityDay struct {
+	Date  time.Time
+	Slots []*calendar.TimePeriod
+}
+
+// groupSlotsByDay groups slots by the day they start on.
+func groupSlotsByDay(slots []*calendar.TimePeriod) []availabilityDay {
+	var days []availabilityDay
+	for _, slot := range slots {
+		y, m, d := slot.Start.Date()
+		if n := len(days); n > 0 {
+			if py, pm, pd := days[n-1].Date.Date(); py == y && pm == m && pd == d {
+				days[n-1].Slots = append(days[n-1].Slots, slot)
+				continue
+			}
+		}
+		days = append(days, availabilityDay{Date: slot.Start, Slots: []*calendar.TimePeriod{slot}})
+	}
+	return days
+}
+
+// availabilityHeading describes the slots, e.g. "Available times (CET, 30 min or longer)".
+func availabilityHeading(slots []*calendar.TimePeriod, loc *time.Location) string {
+	zone := loc.String()
+	if zone == "Local" && len(slots) > 0 {
+		zone, _ = slots[0].Start.Zone()
+	}
+	return fmt.Sprintf("Available times (%s, %s or longer)", zone, formatSlotDuration(calAvailabilityDuration))
+}
+
+// formatSlotDuration formats a slot length as "30 min", "1 hour" or "1h30m".
+func formatSlotDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	case d == time.Hour:
+		return "1 hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	default:
+		return strings.TrimSuffix(d.String(), "0s")
+	}
+}
+
+// formatAvailabilityDay formats the day heading, e.g. "Monday, 2024-03-11".
+func formatAvailabilityDay(t time.Time) string {
+	return t.Weekday().String() + ", " + presenter.CurrentLocale().Date(t)
+}
+
+// formatSlotRanges joins the slots of a day, e.g. "09:00 – 11:30, 14:00 – 17:00".
+func formatSlotRanges(slots []*calendar.TimePeriod) string {
+	locale := presenter.CurrentLocale()
+	ranges := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		ranges = append(ranges, locale.Time(slot.Start)+" – "+locale.Time(slot.End))
+	}
+	return strings.Join(ranges, ", ")
+}
+
+// renderAvailabilityText renders slots as plain lines, one per day.
+func renderAvailabilityText(slots []*calendar.TimePeriod, loc *time.Location) string {
+	if len(slots) == 0 {
+		return "No open slots found\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(availabilityHeading(slots, loc) + ":\n\n")
+	for _, day := range groupSlotsByDay(slots) {
+		fmt.Fprintf(&sb, "%s: %s\n", formatAvailabilityDay(day.Date), formatSlotRanges(day.Slots))
+	}
+	return sb.String()
+}
+
+// renderAvailabilityMarkdown renders slots as a Markdown list, one item per day.
+func renderAvailabilityMarkdown(slots []*calendar.TimePeriod, loc *time.Location) string {
+	if len(slots) == 0 {
+		return "No open slots found\n"
+	}
+	var sb strings.Builder
+	sb.WriteString(availabilityHeading(slots, loc) + ":\n\n")
+	for _, day := range groupSlotsByDay(slots) {
+		fmt.Fprintf(&sb, "- **%s**: %s\n", formatAvailabilityDay(day.Date), formatSlotRanges(day.Slots))
+	}
+	return sb.String()
+}
+
+// renderAvailabilityHTML renders slots as an HTML snippet, one list item per day.
+func renderAvailabilityHTML(slots []*calendar.TimePeriod, loc *time.Location) string {
+	if len(slots) == 0 {
+		return "<p>No open slots found</p>\n"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<p>%s:</p>\n<ul>\n", html.EscapeString(availabilityHeading(slots, loc)))
+	for _, day := range groupSlotsByDay(slots) {
+		fmt.Fprintf(&sb, "  <li><strong>%s</strong>: %s</li>\n",
+			html.EscapeString(formatAvailabilityDay(day.Date)), html.EscapeString(formatSlotRanges(day.Slots)))
+	}
+	sb.WriteString("</ul>\n")
+	return sb.String()
+}
+
+// availabilityJSON is the JSON representation of open slots.
+type availabilityJSON struct {
+	TimeZone        string             `json:"time_zone"`
+	DurationMinutes int                `json:"duration_minutes"`
+	Slots           []availabilitySlot `json:"slots"`
+}
+
+// availabilitySlot is one open slot in JSON output.
+type availabilitySlot struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// renderAvailabilityJSON renders slots as JSON.
+func renderAvailabilityJSON(slots []*calendar.TimePeriod, loc *time.Location) (string, error) {
+	out := availabilityJSON{
+		TimeZone:        loc.String(),
+		DurationMinutes: int(calAvailabilityDuration.Minutes()),
+		Slots:           make([]availabilitySlot, 0, len(slots)),
+	}
+	for _, slot := range slots {
+		out.Slots = append(out.Slots, availabilitySlot{
+			Start: slot.Start.Format(time.RFC3339),
+			End:   slot.End.Format(time.RFC3339),
+		})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode availability: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,157 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupCalAvailabilityTest installs a free/busy repository and resets the
+// availability flags to their defaults for the test.
+func setupCalAvailabilityTest(t *testing.T, repo *MockFreeBusyRepository) *cobra.Command {
+	t.Helper()
+	ResetDependencies()
+	t.Cleanup(ResetDependencies)
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{FreeBusyRepo: repo},
+	})
+
+	origNext, origDuration, origTZ, origHours, origWeekends, origCalendars, origFormat :=
+		calAvailabilityNext, calAvailabilityDuration, calAvailabilityTZ, calAvailabilityHours, calAvailabilityWeekends, calAvailabilityCalendars, formatFlag
+	calAvailabilityNext, calAvailabilityDuration, calAvailabilityTZ = "2d", 30*time.Minute, ""
+	calAvailabilityHours, calAvailabilityWeekends, calAvailabilityCalendars, formatFlag = "00:00-24:00", true, []string{"primary"}, "table"
+	t.Cleanup(func() {
+		calAvailabilityNext, calAvailabilityDuration, calAvailabilityTZ, calAvailabilityHours, calAvailabilityWeekends, calAvailabilityCalendars, formatFlag =
+			origNext, origDuration, origTZ, origHours, origWeekends, origCalendars, origFormat
+	})
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(new(bytes.Buffer))
+	return cmd
+}
+
+func TestRunCalAvailability(t *testing.T) {
+	t.Run("json slots skip busy periods", func(t *testing.T) {
+		busyStart := time.Now().Add(3 * time.Hour).Truncate(time.Hour)
+		busy := &calendar.TimePeriod{Start: busyStart, End: busyStart.Add(2 * time.Hour)}
+		cmd := setupCalAvailabilityTest(t, &MockFreeBusyRepository{Response: &calendar.FreeBusyResponse{
+			Calendars: map[string][]*calendar.TimePeriod{"primary": {busy}},
+		}})
+		calAvailabilityTZ, formatFlag = "Asia/Tokyo", "json"
+
+		if err := runCalAvailability(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out availabilityJSON
+		if err := json.Unmarshal(cmd.OutOrStdout().(*bytes.Buffer).Bytes(), &out); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if out.TimeZone != "Asia/Tokyo" || out.DurationMinutes != 30 || len(out.Slots) == 0 {
+			t.Fatalf("unexpected output: %+v", out)
+		}
+		for _, slot := range out.Slots {
+			start, _ := time.Parse(time.RFC3339, slot.Start)
+			end, _ := time.Parse(time.RFC3339, slot.End)
+			if !strings.HasSuffix(slot.Start, "+09:00") {
+				t.Errorf("slot %s is not in the requested time zone", slot.Start)
+			}
+			if start.Before(busy.End) && busy.Start.Before(end) {
+				t.Errorf("slot %s - %s overlaps the busy period", slot.Start, slot.End)
+			}
+		}
+	})
+
+	t.Run("fully booked", func(t *testing.T) {
+		now := time.Now()
+		cmd := setupCalAvailabilityTest(t, &MockFreeBusyRepository{Response: &calendar.FreeBusyResponse{
+			Calendars: map[string][]*calendar.TimePeriod{"primary": {{Start: now.Add(-time.Hour), End: now.Add(72 * time.Hour)}}},
+		}})
+		if err := runCalAvailability(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := cmd.OutOrStdout().(*bytes.Buffer).String(); got != "No open slots found\n" {
+			t.Errorf("output = %q", got)
+		}
+	})
+
+	t.Run("rejects bad flags", func(t *testing.T) {
+		tests := []struct {
+			set     func()
+			wantErr string
+		}{
+			{func() { calAvailabilityNext = "soon" }, "--next"},
+			{func() { calAvailabilityDuration = 0 }, "--duration"},
+			{func() { calAvailabilityTZ = "Mars/Olympus" }, "--tz"},
+			{func() { calAvailabilityHours = "17:00-09:00" }, "end must be after start"},
+		}
+		for _, tt := range tests {
+			cmd := setupCalAvailabilityTest(t, &MockFreeBusyRepository{})
+			tt.set()
+			if err := runCalAvailability(cmd, nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		}
+	})
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	hours, err := parseWorkingHours("9:30-17:00")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hours.Start != 9*time.Hour+30*time.Minute || hours.End != 17*time.Hour {
+		t.Errorf("hours = %+v", hours)
+	}
+	for _, bad := range []string{"9-5", "09:00", "25:00-26:00", "10:00-10:00"} {
+		if _, err := parseWorkingHours(bad); err == nil {
+			t.Errorf("parseWorkingHours(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRenderAvailabilitySnippets(t *testing.T) {
+	origDuration := calAvailabilityDuration
+	defer func() { calAvailabilityDuration = origDuration }()
+	calAvailabilityDuration = time.Hour
+
+	loc := time.FixedZone("CET", 3600)
+	at := func(day, h, m int) time.Time { return time.Date(2024, 3, day, h, m, 0, 0, loc) }
+	slots := []*calendar.TimePeriod{
+		{Start: at(11, 9, 0), End: at(11, 11, 30)},
+		{Start: at(11, 14, 0), End: at(11, 17, 0)},
+		{Start: at(12, 9, 0), End: at(12, 10, 0)},
+	}
+
+	md := renderAvailabilityMarkdown(slots, loc)
+	for _, want := range []string{
+		"Available times (CET, 1 hour or longer):",
+		"- **Monday, 2024-03-11**: 09:00 – 11:30, 14:00 – 17:00\n",
+		"- **Tuesday, 2024-03-12**: 09:00 – 10:00\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown should contain %q, got:\n%s", want, md)
+		}
+	}
+
+	htmlOut := renderAvailabilityHTML(slots, loc)
+	for _, want := range []string{"<ul>", "<li><strong>Tuesday, 2024-03-12</strong>: 09:00 – 10:00</li>", "</ul>"} {
+		if !strings.Contains(htmlOut, want) {
+			t.Errorf("HTML should contain %q, got:\n%s", want, htmlOut)
+		}
+	}
+
+	if text := renderAvailabilityText(slots, loc); !strings.Contains(text, "Monday, 2024-03-11: 09:00 – 11:30") {
+		t.Errorf("unexpected text output:\n%s", text)
+	}
+}
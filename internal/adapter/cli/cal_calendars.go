@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
 )
@@ -222,7 +221,7 @@ func runCalendarsList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderCalendars(calendars)
 	cmd.Println(output)
@@ -247,7 +246,7 @@ func runCalendarsShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderCalendar(cal)
 	cmd.Println(output)
@@ -276,7 +275,7 @@ func runCalendarsCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderCalendar(created))
@@ -334,7 +333,7 @@ func runCalendarsUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderCalendar(updated))
@@ -4,13 +4,11 @@ package cli
 import (
 	"context"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
 	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
 )
 
@@ -25,6 +23,7 @@ var (
 	calCreateAttendees   []string
 	calCreateAllDay      bool
 	calCreateCalendar    string
+	calCreateAttachDrive []string
 
 	// Update flags
 	calUpdateTitle       string
@@ -66,7 +65,11 @@ Date/time formats supported:
     --location "Conference Room A" --attendees user1@example.com,user2@example.com
 
   # Create an event in a specific calendar
-  goog cal create --title "Personal Errand" --start "today 3pm" --calendar work@example.com`,
+  goog cal create --title "Personal Errand" --start "today 3pm" --calendar work@example.com
+
+  # Attach a Drive file by ID or link
+  goog cal create --title "Review" --start "tomorrow 2pm" \
+    --attach-drive https://docs.google.com/document/d/1AbC/edit`,
 	RunE: runCalCreate,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if calCreateTitle == "" {
@@ -144,6 +147,8 @@ func init() {
 	calCreateCmd.Flags().StringSliceVar(&calCreateAttendees, "attendees", nil, "attendee email addresses (comma-separated)")
 	calCreateCmd.Flags().BoolVar(&calCreateAllDay, "all-day", false, "create an all-day event")
 	calCreateCmd.Flags().StringVar(&calCreateCalendar, "calendar", "primary", "calendar ID to use")
+	calCreateCmd.Flags().StringSliceVar(&calCreateAttachDrive, "attach-drive", nil, "Drive file IDs or links to attach (comma-separated)")
+	addCheckTravelFlag(calCreateCmd)
 
 	// Update command flags
 	calUpdateCmd.Flags().StringVar(&calUpdateTitle, "title", "", "new event title")
@@ -229,6 +234,11 @@ func runCalCreate(cmd *cobra.Command, args []string) error {
 		event.AddAttendee(calendar.NewAttendee(email))
 	}
 
+	// Attach Drive files
+	if event.Attachments, err = driveAttachments(calCreateAttachDrive); err != nil {
+		return err
+	}
+
 	// Create event
 	created, err := repo.Create(ctx, calCreateCalendar, event)
 	if err != nil {
@@ -236,10 +246,12 @@ func runCalCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output result
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	output := p.RenderEvent(created)
 	cmd.Println(output)
 
+	warnTravel(ctx, cmd, repo, calCreateCalendar, created)
+
 	if !quietFlag {
 		cmd.Printf("\nEvent created successfully.\n")
 		cmd.Printf("Event ID: %s\n", created.ID)
@@ -321,7 +333,7 @@ func runCalUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output result
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	output := p.RenderEvent(updated)
 	cmd.Println(output)
 
@@ -359,114 +371,15 @@ func runCalDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// parseDateTime parses a date/time string into a time.Time.
-// Supports various formats including:
-// - "2024-01-15 14:00" (date and time)
-// - "2024-01-15" (date only)
-// - "2024-01-15 14:30:45" (date with seconds)
-// - "2024-01-15T14:00:00Z" (RFC3339)
-// - "tomorrow" or "tomorrow 3pm" (relative)
-// - "today" or "today 2pm" (relative)
+// parseDateTime parses a date/time flag value using the shared date
+// expressions, e.g. "2024-01-15 14:00", "tomorrow 3pm" or "next monday 14:00".
 func parseDateTime(input string) (time.Time, error) {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return time.Time{}, fmt.Errorf("empty date/time string")
-	}
-
-	// Try RFC3339 first
-	if t, err := time.Parse(time.RFC3339, input); err == nil {
-		return t, nil
-	}
-
-	// Try common formats
-	formats := []string{
-		"2006-01-02 15:04:05",
-		"2006-01-02 15:04",
-		"2006-01-02",
-	}
-
-	for _, format := range formats {
-		if t, err := time.ParseInLocation(format, input, time.Local); err == nil {
-			return t, nil
-		}
-	}
-
-	// Handle relative dates
-	lower := strings.ToLower(input)
-
-	if strings.HasPrefix(lower, "tomorrow") {
-		return parseRelativeDate(input, 1)
-	}
-
-	if strings.HasPrefix(lower, "today") {
-		return parseRelativeDate(input, 0)
-	}
-
-	return time.Time{}, fmt.Errorf("unable to parse date/time: %q", input)
-}
-
-// parseRelativeDate parses a relative date string like "tomorrow 3pm".
-func parseRelativeDate(input string, daysOffset int) (time.Time, error) {
-	now := time.Now()
-	baseDate := now.AddDate(0, 0, daysOffset)
-
-	// Extract time component if present
-	parts := strings.Fields(strings.ToLower(input))
-	if len(parts) == 1 {
-		// Just "tomorrow" or "today" - return start of day
-		return time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), 0, 0, 0, 0, time.Local), nil
-	}
-
-	// Try to parse time component
-	timeStr := parts[1]
-	hour, minute, err := parseTimeOfDay(timeStr)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid time in relative date: %w", err)
-	}
-
-	return time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, minute, 0, 0, time.Local), nil
+	return dateparse.Parse(input)
 }
 
 // parseTimeOfDay parses a time string like "3pm", "3:30pm", or "14:00".
 func parseTimeOfDay(input string) (hour, minute int, err error) {
-	input = strings.ToLower(strings.TrimSpace(input))
-
-	// Try 24-hour format first (e.g., "14:00")
-	if matched, _ := regexp.MatchString(`^\d{1,2}:\d{2}$`, input); matched {
-		parts := strings.Split(input, ":")
-		hour, _ = strconv.Atoi(parts[0])
-		minute, _ = strconv.Atoi(parts[1])
-		if hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59 {
-			return hour, minute, nil
-		}
-		return 0, 0, fmt.Errorf("invalid time: %s", input)
-	}
-
-	// Try 12-hour format (e.g., "3pm", "3:30pm")
-	ampmRegex := regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
-	matches := ampmRegex.FindStringSubmatch(input)
-	if matches != nil {
-		hour, _ = strconv.Atoi(matches[1])
-		if matches[2] != "" {
-			minute, _ = strconv.Atoi(matches[2])
-		}
-		isPM := matches[3] == "pm"
-
-		// Convert to 24-hour format
-		if hour == 12 {
-			if !isPM {
-				hour = 0 // 12am = midnight
-			}
-		} else if isPM {
-			hour += 12
-		}
-
-		if hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59 {
-			return hour, minute, nil
-		}
-	}
-
-	return 0, 0, fmt.Errorf("invalid time format: %s", input)
+	return dateparse.ParseTimeOfDay(input)
 }
 
 // parseAttendees cleans, validates, and returns attendee email addresses.
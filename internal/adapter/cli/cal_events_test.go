@@ -1042,10 +1042,6 @@ func TestParseDateTime_InvalidFormats(t *testing.T) {
 			name:  "today with invalid time format",
 			input: "today 25:00",
 		},
-		{
-			name:  "yesterday keyword (not supported)",
-			input: "yesterday",
-		},
 		{
 			name:  "next week (not supported)",
 			input: "next week",
@@ -1125,6 +1121,25 @@ func TestParseDateTime_ValidEdgeCases(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "yesterday keyword",
+			input: "yesterday",
+			checkResult: func(t *testing.T, result time.Time) {
+				yesterday := time.Now().AddDate(0, 0, -1)
+				if result.Day() != yesterday.Day() || result.Hour() != 0 {
+					t.Errorf("expected yesterday at midnight, got %v", result)
+				}
+			},
+		},
+		{
+			name:  "next weekday with time",
+			input: "next monday 14:00",
+			checkResult: func(t *testing.T, result time.Time) {
+				if result.Weekday() != time.Monday || result.Hour() != 14 || !result.After(time.Now()) {
+					t.Errorf("expected a future Monday at 14:00, got %v", result)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1298,107 +1313,6 @@ func TestParseAttendees_EdgeCases(t *testing.T) {
 	}
 }
 
-func TestParseRelativeDate_EdgeCases(t *testing.T) {
-	tests := []struct {
-		name        string
-		input       string
-		daysOffset  int
-		expectErr   bool
-		checkResult func(t *testing.T, result time.Time)
-	}{
-		{
-			name:       "tomorrow with 12am",
-			input:      "tomorrow 12am",
-			daysOffset: 1,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 0 {
-					t.Errorf("expected hour 0, got %d", result.Hour())
-				}
-			},
-		},
-		{
-			name:       "today with 12pm",
-			input:      "today 12pm",
-			daysOffset: 0,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 12 {
-					t.Errorf("expected hour 12, got %d", result.Hour())
-				}
-			},
-		},
-		{
-			name:       "tomorrow with 11:59pm",
-			input:      "tomorrow 11:59pm",
-			daysOffset: 1,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 23 || result.Minute() != 59 {
-					t.Errorf("expected 23:59, got %02d:%02d", result.Hour(), result.Minute())
-				}
-			},
-		},
-		{
-			name:       "today with 1:30am",
-			input:      "today 1:30am",
-			daysOffset: 0,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 1 || result.Minute() != 30 {
-					t.Errorf("expected 01:30, got %02d:%02d", result.Hour(), result.Minute())
-				}
-			},
-		},
-		{
-			name:       "tomorrow only (no time)",
-			input:      "tomorrow",
-			daysOffset: 1,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 0 || result.Minute() != 0 {
-					t.Errorf("expected 00:00, got %02d:%02d", result.Hour(), result.Minute())
-				}
-			},
-		},
-		{
-			name:       "today only (no time)",
-			input:      "today",
-			daysOffset: 0,
-			checkResult: func(t *testing.T, result time.Time) {
-				if result.Hour() != 0 || result.Minute() != 0 {
-					t.Errorf("expected 00:00, got %02d:%02d", result.Hour(), result.Minute())
-				}
-			},
-		},
-		{
-			name:       "tomorrow with invalid time",
-			input:      "tomorrow 25:00",
-			daysOffset: 1,
-			expectErr:  true,
-		},
-		{
-			name:       "today with text instead of time",
-			input:      "today afternoon",
-			daysOffset: 0,
-			expectErr:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseRelativeDate(tt.input, tt.daysOffset)
-			if tt.expectErr {
-				if err == nil {
-					t.Errorf("expected error for input %q", tt.input)
-				}
-			} else {
-				if err != nil {
-					t.Fatalf("unexpected error: %v", err)
-				}
-				if tt.checkResult != nil {
-					tt.checkResult(t, result)
-				}
-			}
-		})
-	}
-}
-
 func TestCalCreateCmd_AllFlagCombinations(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1486,78 +1400,6 @@ func TestCalDeleteCmd_WithoutConfirm(t *testing.T) {
 // Additional Edge Case Tests for Helper Functions
 // =============================================================================
 
-func TestParseRelativeDate_NegativeOffset(t *testing.T) {
-	// Test with negative offset (yesterday - not typically used, but should work)
-	result, err := parseRelativeDate("yesterday", -1)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	yesterday := time.Now().AddDate(0, 0, -1)
-	if result.Year() != yesterday.Year() ||
-		result.Month() != yesterday.Month() ||
-		result.Day() != yesterday.Day() {
-		t.Errorf("expected yesterday's date, got %v", result)
-	}
-}
-
-func TestParseRelativeDate_LargeOffset(t *testing.T) {
-	// Test with large offset (far future)
-	result, err := parseRelativeDate("future", 365)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	future := time.Now().AddDate(0, 0, 365)
-	if result.Year() != future.Year() ||
-		result.Month() != future.Month() ||
-		result.Day() != future.Day() {
-		t.Errorf("expected date 365 days from now, got %v", result)
-	}
-}
-
-func TestParseRelativeDate_WithExtraSpaces(t *testing.T) {
-	// Test with extra spaces in input
-	result, err := parseRelativeDate("tomorrow   3pm", 1)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	tomorrow := time.Now().AddDate(0, 0, 1)
-	if result.Year() != tomorrow.Year() ||
-		result.Month() != tomorrow.Month() ||
-		result.Day() != tomorrow.Day() ||
-		result.Hour() != 15 {
-		t.Errorf("expected tomorrow at 3pm, got %v", result)
-	}
-}
-
-func TestParseRelativeDate_CaseInsensitive(t *testing.T) {
-	// Test that TODAY, ToMoRrOw, etc. work
-	tests := []struct {
-		name   string
-		input  string
-		offset int
-	}{
-		{"uppercase TODAY", "TODAY", 0},
-		{"mixed case ToMoRrOw", "ToMoRrOw", 1},
-		{"uppercase TOMORROW", "TOMORROW", 1},
-		{"mixed case tOdAy", "tOdAy", 0},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseRelativeDate(tt.input, tt.offset)
-			if err != nil {
-				t.Fatalf("unexpected error: %v", err)
-			}
-			expected := time.Now().AddDate(0, 0, tt.offset)
-			if result.Year() != expected.Year() ||
-				result.Month() != expected.Month() ||
-				result.Day() != expected.Day() {
-				t.Errorf("expected date %v, got %v", expected, result)
-			}
-		})
-	}
-}
-
 func TestParseTimeOfDay_InvalidFormats(t *testing.T) {
 	// Additional invalid format tests
 	tests := []string{
@@ -1801,45 +1643,6 @@ func TestParseDateTime_WithSecondsAndSubseconds(t *testing.T) {
 	}
 }
 
-func TestParseRelativeDate_WithMultipleWords(t *testing.T) {
-	// Test that only first two words are processed (relative date + time)
-	// Additional words should cause error
-	tests := []struct {
-		name      string
-		input     string
-		offset    int
-		expectErr bool
-	}{
-		{
-			name:      "tomorrow at 3pm (three words)",
-			input:     "tomorrow at 3pm",
-			offset:    1,
-			expectErr: true, // "at" would be treated as time component and fail
-		},
-		{
-			name:      "today in morning",
-			input:     "today in morning",
-			offset:    0,
-			expectErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := parseRelativeDate(tt.input, tt.offset)
-			if tt.expectErr {
-				if err == nil {
-					t.Error("expected error for multi-word input")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("unexpected error: %v", err)
-				}
-			}
-		})
-	}
-}
-
 func TestIsValidEmail_ComprehensiveCoverage(t *testing.T) {
 	// Additional email validation edge cases
 	tests := []struct {
@@ -0,0 +1,119 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"golang.org/x/term"
+)
+
+// defaultTerminalWidth is the grid width used when the terminal width
+// cannot be detected, e.g. when output is piped.
+const defaultTerminalWidth = 80
+
+// Command flags for calendar grid views.
+var (
+	calWeekGrid bool
+	calMonthArg string
+)
+
+// calMonthCmd shows a month of events as a grid.
+var calMonthCmd = &cobra.Command{
+	Use:   "month",
+	Short: "Show a month of events as a grid",
+	Long: `Show the events of a month as a calendar grid.
+
+Each cell lists the day's events with their start times, truncated to
+fit the terminal width; busy days end with "+N more". Weeks begin on
+the day set by calendar.week_start (sunday or monday).
+
+Use --format json or plain, or --a11y, to list the month's events
+instead.`,
+	Example: `  # Show this month
+  goog cal month
+
+  # Show another month from a specific calendar
+  goog cal month --month 2025-08 --calendar work@group.calendar.google.com`,
+	Args: cobra.NoArgs,
+	RunE: runCalMonth,
+}
+
+func init() {
+	calCmd.AddCommand(calMonthCmd)
+
+	calMonthCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to use")
+	calMonthCmd.Flags().StringVar(&calMonthArg, "month", "", "month to show as YYYY-MM (default: this month)")
+
+	calWeekCmd.Flags().BoolVar(&calWeekGrid, "grid", false, "draw the week as a grid of day columns (lists the events with --a11y)")
+}
+
+// runCalMonth handles the cal month command.
+func runCalMonth(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	month := time.Now()
+	if calMonthArg != "" {
+		parsed, err := time.ParseInLocation("2006-01", calMonthArg, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --month value %q: use YYYY-MM", calMonthArg)
+		}
+		month = parsed
+	}
+	weekStart := configuredWeekStart()
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	gridStart := startOfWeek(first, weekStart)
+	gridEnd := startOfWeek(first.AddDate(0, 1, 0).Add(-time.Nanosecond), weekStart).AddDate(0, 0, 7)
+
+	repo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	events, err := repo.List(ctx, calCalendarFlag, gridStart, gridEnd)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	if !strings.EqualFold(formatFlag, presenter.FormatTable) || presenter.Accessible() {
+		cmd.Println(newPresenter().RenderEvents(events))
+		return nil
+	}
+	cmd.Print(presenter.RenderMonthGrid(events, first, weekStart, terminalWidth()))
+	return nil
+}
+
+// configuredWeekStart returns the first day of the week set by
+// calendar.week_start: Monday for "monday", otherwise Sunday.
+func configuredWeekStart() time.Weekday {
+	cfg, err := config.Load()
+	if err == nil && strings.EqualFold(strings.TrimSpace(cfg.Calendar.WeekStart), "monday") {
+		return time.Monday
+	}
+	return time.Sunday
+}
+
+// startOfWeek returns midnight on the last weekStart day on or before t.
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -int((day.Weekday()-weekStart+7)%7))
+}
+
+// terminalWidth returns the width of the terminal on stdout. COLUMNS
+// overrides detection, and defaultTerminalWidth is used when neither is
+// available.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	return defaultTerminalWidth
+}
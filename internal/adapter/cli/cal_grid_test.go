@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestStartOfWeek(t *testing.T) {
+	wed := time.Date(2024, 3, 13, 15, 30, 0, 0, time.UTC)
+	if got := startOfWeek(wed, time.Sunday); !got.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("startOfWeek(Sunday) = %v", got)
+	}
+	if got := startOfWeek(wed, time.Monday); !got.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("startOfWeek(Monday) = %v", got)
+	}
+	sun := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	if got := startOfWeek(sun, time.Monday); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("startOfWeek(Sunday, Monday) = %v", got)
+	}
+}
+
+func TestConfiguredWeekStart(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	if got := configuredWeekStart(); got != time.Sunday {
+		t.Errorf("default week start = %v, want Sunday", got)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Calendar.WeekStart = "monday"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := configuredWeekStart(); got != time.Monday {
+		t.Errorf("week start = %v, want Monday", got)
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "132")
+	if got := terminalWidth(); got != 132 {
+		t.Errorf("terminalWidth() = %d, want 132", got)
+	}
+	t.Setenv("COLUMNS", "wide")
+	if got := terminalWidth(); got <= 0 {
+		t.Errorf("terminalWidth() = %d, want a positive fallback", got)
+	}
+}
+
+func TestRunCalMonth(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("COLUMNS", "120")
+
+	repo := &MockEventRepository{Events: []*calendar.Event{
+		{Title: "Review", Start: time.Date(2025, 8, 12, 10, 0, 0, 0, time.Local), End: time.Date(2025, 8, 12, 11, 0, 0, 0, time.Local)},
+	}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+	defer ResetDependencies()
+
+	origMonth, origFormat := calMonthArg, formatFlag
+	defer func() { calMonthArg, formatFlag = origMonth, origFormat }()
+	calMonthArg, formatFlag = "2025-08", "table"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runCalMonth(cmd, nil); err != nil {
+		t.Fatalf("runCalMonth failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "August 2025\n") || !strings.Contains(out, "Review") {
+		t.Errorf("unexpected grid:\n%s", out)
+	}
+	if header := strings.Split(out, "\n")[2]; !strings.HasPrefix(strings.TrimLeft(header, "| "), "Sun") {
+		t.Errorf("expected weeks to start on Sunday by default, got %q", header)
+	}
+
+	calMonthArg = "August"
+	if err := runCalMonth(cmd, nil); err == nil || !strings.Contains(err.Error(), "YYYY-MM") {
+		t.Errorf("expected an invalid --month error, got %v", err)
+	}
+}
+
+func TestRunCalWeek_Grid(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("COLUMNS", "120")
+
+	start := startOfWeek(time.Now(), time.Sunday).Add(26 * time.Hour)
+	repo := &MockEventRepository{Events: []*calendar.Event{
+		{Title: "Sync", Start: start, End: start.Add(time.Hour)},
+	}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+	defer ResetDependencies()
+
+	origGrid, origFormat := calWeekGrid, formatFlag
+	defer func() { calWeekGrid, formatFlag = origGrid, origFormat }()
+	calWeekGrid, formatFlag = true, "table"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runCalWeek(cmd, nil); err != nil {
+		t.Fatalf("runCalWeek failed: %v", err)
+	}
+	if out := buf.String(); !strings.HasPrefix(out, "Week of ") || !strings.Contains(out, "Sync") {
+		t.Errorf("unexpected grid:\n%s", out)
+	}
+}
@@ -0,0 +1,190 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/ics"
+)
+
+// Command flags for calendar import command.
+var (
+	calImportCalendar string
+	calImportForce    bool
+)
+
+// calImportCmd imports events from an iCalendar file.
+var calImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import events from an ICS file",
+	Long: `Import events from an iCalendar (.ics) file.
+
+Use "-" as the file to read from standard input.
+
+Importing is idempotent: an event is skipped when the calendar already
+has one with the same iCalendar UID, or with the same title and start
+time, so re-running an import creates only the events that are missing.
+Duplicates within the file are skipped the same way. Use --force to
+create every event regardless; forced copies of an event already in the
+calendar get a new UID.
+
+Cancelled events and changed instances of recurring events are not
+imported.`,
+	Example: `  # Import an exported calendar
+  goog cal import holidays.ics
+
+  # Import into a shared calendar from standard input
+  curl -s https://example.com/team.ics | goog cal import - --calendar team@group.calendar.google.com
+
+  # Import everything, even events that already exist
+  goog cal import holidays.ics --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCalImport,
+}
+
+func init() {
+	calCmd.AddCommand(calImportCmd)
+
+	calImportCmd.Flags().StringVar(&calImportCalendar, "calendar", "primary", "calendar ID to import into")
+	calImportCmd.Flags().BoolVar(&calImportForce, "force", false, "create events even when they duplicate existing ones")
+}
+
+// calImportEventJSON is the JSON representation of an imported or skipped event.
+type calImportEventJSON struct {
+	ID          string `json:"id,omitempty"`
+	ICalUID     string `json:"ical_uid,omitempty"`
+	Title       string `json:"title"`
+	Start       string `json:"start"`
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+}
+
+// calImportJSON is the JSON representation of an import report.
+type calImportJSON struct {
+	Created []calImportEventJSON `json:"created"`
+	Skipped []calImportEventJSON `json:"skipped"`
+}
+
+// runCalImport handles the cal import command.
+func runCalImport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	events, err := readICSFile(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no events found in %s", args[0])
+	}
+
+	repo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Pad the range by a day, as all-day events may be stored at midnight
+	// in another time zone.
+	timeMin, timeMax := importRange(events)
+	existing, err := repo.List(ctx, calImportCalendar, timeMin.AddDate(0, 0, -1), timeMax.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to list existing events: %w", err)
+	}
+
+	report := calImportJSON{Created: []calImportEventJSON{}, Skipped: []calImportEventJSON{}}
+	for _, event := range events {
+		if dup := calendar.FindDuplicate(existing, event); dup != nil {
+			if !calImportForce {
+				skipped := importEventJSON(event)
+				skipped.DuplicateOf = dup.ID
+				report.Skipped = append(report.Skipped, skipped)
+				continue
+			}
+			if dup.ICalUID == event.ICalUID {
+				// A calendar holds one event per UID; let Google assign
+				// the copy a new one.
+				event.ICalUID = ""
+			}
+		}
+		created, err := repo.Create(ctx, calImportCalendar, event)
+		if err != nil {
+			return fmt.Errorf("failed to create event %q: %w", event.Title, err)
+		}
+		existing = append(existing, created)
+		report.Created = append(report.Created, importEventJSON(created))
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode import report: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if !quietFlag {
+		for _, e := range report.Created {
+			cmd.Printf("Created: %s (%s)\n", e.Title, e.Start)
+		}
+		for _, e := range report.Skipped {
+			cmd.Printf("Skipped duplicate: %s (%s), already event %s\n", e.Title, e.Start, e.DuplicateOf)
+		}
+	}
+	cmd.Printf("Imported %d event(s), skipped %d duplicate(s).\n", len(report.Created), len(report.Skipped))
+	return nil
+}
+
+// readICSFile parses the events of an ICS file, or of standard input for "-".
+func readICSFile(cmd *cobra.Command, path string) ([]*calendar.Event, error) {
+	var r io.Reader
+	if path == "-" {
+		r = cmd.InOrStdin()
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	events, err := ics.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return events, nil
+}
+
+// importRange returns the span of time covered by events.
+func importRange(events []*calendar.Event) (time.Time, time.Time) {
+	timeMin, timeMax := events[0].Start, events[0].End
+	for _, e := range events[1:] {
+		if e.Start.Before(timeMin) {
+			timeMin = e.Start
+		}
+		if e.End.After(timeMax) {
+			timeMax = e.End
+		}
+	}
+	return timeMin, timeMax
+}
+
+// importEventJSON describes an event in an import report.
+func importEventJSON(event *calendar.Event) calImportEventJSON {
+	start := event.Start.Format("2006-01-02 15:04")
+	if event.AllDay {
+		start = event.Start.Format("2006-01-02")
+	}
+	return calImportEventJSON{
+		ID:      event.ID,
+		ICalUID: event.ICalUID,
+		Title:   event.Title,
+		Start:   start,
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+const calImportSample = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\nUID:a@example.com\r\nSUMMARY:Kickoff\r\nDTSTART:20240304T150000Z\r\nDTEND:20240304T160000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:b@example.com\r\nSUMMARY:Review\r\nDTSTART:20240305T150000Z\r\nDTEND:20240305T160000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:b@example.com\r\nSUMMARY:Review\r\nDTSTART:20240305T150000Z\r\nDTEND:20240305T160000Z\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// setupCalImportTest injects an event repository, writes the sample file
+// and resets import flags.
+func setupCalImportTest(t *testing.T, repo *MockEventRepository) string {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+
+	origCal, origForce, origFormat, origQuiet := calImportCalendar, calImportForce, formatFlag, quietFlag
+	calImportCalendar, calImportForce, formatFlag, quietFlag = "primary", false, "table", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		calImportCalendar, calImportForce, formatFlag, quietFlag = origCal, origForce, origFormat, origQuiet
+	})
+
+	path := filepath.Join(t.TempDir(), "events.ics")
+	if err := os.WriteFile(path, []byte(calImportSample), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCalImport_SkipsDuplicates(t *testing.T) {
+	existing := &calendar.Event{ID: "evt1", ICalUID: "a@example.com", Title: "Kickoff (moved)", Start: time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC)}
+	repo := &MockEventRepository{Events: []*calendar.Event{existing}}
+	path := setupCalImportTest(t, repo)
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	if err := runCalImport(cmd, []string{path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"Created: Review (2024-03-05 15:00)",
+		"Skipped duplicate: Kickoff (2024-03-04 15:00), already event evt1",
+		"Imported 1 event(s), skipped 2 duplicate(s).",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if !repo.ListTimeMin.Before(time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)) || !repo.ListTimeMax.After(time.Date(2024, 3, 5, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("listed %v to %v, want a range covering the file", repo.ListTimeMin, repo.ListTimeMax)
+	}
+}
+
+func TestRunCalImport_ForceJSON(t *testing.T) {
+	existing := &calendar.Event{ID: "evt1", ICalUID: "a@example.com", Title: "Kickoff", Start: time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)}
+	repo := &MockEventRepository{Events: []*calendar.Event{existing}}
+	path := setupCalImportTest(t, repo)
+	calImportForce = true
+	formatFlag = "json"
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	if err := runCalImport(cmd, []string{path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report calImportJSON
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(report.Created) != 3 || len(report.Skipped) != 0 {
+		t.Fatalf("created %d, skipped %d; want 3 and 0", len(report.Created), len(report.Skipped))
+	}
+	if report.Created[0].ICalUID != "" {
+		t.Errorf("forced copy kept UID %q, want a new one", report.Created[0].ICalUID)
+	}
+}
+
+func TestRunCalImport_Stdin(t *testing.T) {
+	setupCalImportTest(t, &MockEventRepository{})
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	if err := runCalImport(cmd, []string{"-"}); err == nil || !strings.Contains(err.Error(), "no events") {
+		t.Errorf("error = %v, want a no events error", err)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 )
 
 // Command flags for instances command.
@@ -36,6 +35,9 @@ modify individual instances, or check for conflicts.`,
   # List instances within a specific time range
   goog cal instances abc123def456 --start "2024-01-01T00:00:00Z" --end "2024-03-01T00:00:00Z"
 
+  # List instances over the next eight weeks
+  goog cal instances abc123def456 --start today --end +8w
+
   # List instances from a specific calendar
   goog cal instances abc123def456 --calendar work@group.calendar.google.com
 
@@ -55,8 +57,8 @@ func init() {
 
 	// Instances command flags
 	calInstancesCmd.Flags().StringVar(&calInstancesCalendar, "calendar", "primary", "calendar ID to use")
-	calInstancesCmd.Flags().StringVar(&calInstancesStart, "start", "", "start time for instances (RFC3339 format)")
-	calInstancesCmd.Flags().StringVar(&calInstancesEnd, "end", "", "end time for instances (RFC3339 format)")
+	calInstancesCmd.Flags().StringVar(&calInstancesStart, "start", "", "start time for instances (e.g. 2024-01-15, today, -1w)")
+	calInstancesCmd.Flags().StringVar(&calInstancesEnd, "end", "", "end time for instances (e.g. 2024-02-15, +30d)")
 	calInstancesCmd.Flags().IntVar(&calInstancesMaxResults, "max-results", 25, "maximum number of instances to return")
 }
 
@@ -69,9 +71,9 @@ func runCalInstances(cmd *cobra.Command, args []string) error {
 	var timeMin time.Time
 	if calInstancesStart != "" {
 		var err error
-		timeMin, err = time.Parse(time.RFC3339, calInstancesStart)
+		timeMin, err = parseDateTime(calInstancesStart)
 		if err != nil {
-			return fmt.Errorf("invalid start time format (use RFC3339, e.g., 2024-01-15T09:00:00Z): %w", err)
+			return fmt.Errorf("invalid start time format: %w", err)
 		}
 	}
 
@@ -79,9 +81,9 @@ func runCalInstances(cmd *cobra.Command, args []string) error {
 	var timeMax time.Time
 	if calInstancesEnd != "" {
 		var err error
-		timeMax, err = time.Parse(time.RFC3339, calInstancesEnd)
+		timeMax, err = parseDateTime(calInstancesEnd)
 		if err != nil {
-			return fmt.Errorf("invalid end time format (use RFC3339, e.g., 2024-01-15T17:00:00Z): %w", err)
+			return fmt.Errorf("invalid end time format: %w", err)
 		}
 	}
 
@@ -108,7 +110,7 @@ func runCalInstances(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvents(instances)
@@ -0,0 +1,232 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// Command flags for calendar stats command.
+var (
+	calStatsSince         string
+	calStatsCalendar      string
+	calStatsTopOrganizers int
+	calStatsGap           time.Duration
+)
+
+// calStatsCmd summarizes time spent in meetings.
+var calStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize time spent in meetings",
+	Long: `Summarize time spent in meetings over a recent period.
+
+Aggregates events locally to report hours in meetings per week,
+the most frequent organizers, the ratio of recurring to ad-hoc
+meetings, and runs of back-to-back meetings.
+
+All-day, cancelled, and declined events are not counted.
+
+The --since flag accepts a lookback such as 90d, 4w, or 36h, or a
+date such as "last monday" or 2025-01-01.`,
+	Example: `  # Summarize the last 90 days
+  goog cal stats --since 90d
+
+  # Summarize the last 4 weeks of a shared calendar
+  goog cal stats --since 4w --calendar team@group.calendar.google.com
+
+  # Show the top 10 organizers as JSON
+  goog cal stats --since 30d --top 10 --format json`,
+	Aliases: []string{"analytics"},
+	Args:    cobra.NoArgs,
+	RunE:    runCalStats,
+}
+
+func init() {
+	calCmd.AddCommand(calStatsCmd)
+
+	calStatsCmd.Flags().StringVar(&calStatsSince, "since", "90d", "lookback period or start date (e.g. 90d, 4w, 36h, 2025-01-01)")
+	calStatsCmd.Flags().StringVar(&calStatsCalendar, "calendar", "primary", "calendar ID to use")
+	calStatsCmd.Flags().IntVar(&calStatsTopOrganizers, "top", 5, "number of top organizers to show")
+	calStatsCmd.Flags().DurationVar(&calStatsGap, "gap", calendar.DefaultBackToBackGap, "maximum gap between back-to-back meetings")
+}
+
+// runCalStats handles the cal stats command.
+func runCalStats(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	until := time.Now()
+	since, err := dateparse.ParseSinceAt(calStatsSince, until)
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+
+	// Get event repository using dependency injection
+	repo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := repo.List(ctx, calStatsCalendar, since, until)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	stats := calendar.ComputeStats(events, since, until, calendar.StatsOptions{
+		TopOrganizers: calStatsTopOrganizers,
+		BackToBackGap: calStatsGap,
+	})
+
+	if formatFlag == presenter.FormatJSON {
+		output, err := renderCalStatsJSON(stats)
+		if err != nil {
+			return err
+		}
+		cmd.Println(output)
+		return nil
+	}
+
+	cmd.Println(renderCalStatsText(stats))
+	return nil
+}
+
+// calStatsJSON is the JSON representation of meeting statistics.
+type calStatsJSON struct {
+	Since              string              `json:"since"`
+	Until              string              `json:"until"`
+	Meetings           int                 `json:"meetings"`
+	TotalHours         float64             `json:"total_hours"`
+	AverageWeeklyHours float64             `json:"average_weekly_hours"`
+	Weeks              []calStatsWeekJSON  `json:"weeks"`
+	TopOrganizers      []calStatsOrgJSON   `json:"top_organizers"`
+	Recurring          int                 `json:"recurring"`
+	AdHoc              int                 `json:"ad_hoc"`
+	RecurringRatio     float64             `json:"recurring_ratio"`
+	BackToBackStreaks  int                 `json:"back_to_back_streaks"`
+	LongestStreak      *calStatsStreakJSON `json:"longest_streak,omitempty"`
+}
+
+type calStatsWeekJSON struct {
+	WeekStart string  `json:"week_start"`
+	Meetings  int     `json:"meetings"`
+	Hours     float64 `json:"hours"`
+}
+
+type calStatsOrgJSON struct {
+	Email    string `json:"email"`
+	Name     string `json:"name,omitempty"`
+	Meetings int    `json:"meetings"`
+}
+
+type calStatsStreakJSON struct {
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+// roundHours rounds an hour value to two decimal places for display.
+func roundHours(h float64) float64 {
+	return float64(int64(h*100+0.5)) / 100
+}
+
+// renderCalStatsJSON renders meeting statistics as a JSON string.
+func renderCalStatsJSON(stats *calendar.Stats) (string, error) {
+	out := calStatsJSON{
+		Since:              stats.Since.Format(time.RFC3339),
+		Until:              stats.Until.Format(time.RFC3339),
+		Meetings:           stats.MeetingCount,
+		TotalHours:         roundHours(stats.TotalHours),
+		AverageWeeklyHours: roundHours(stats.AverageWeeklyHours),
+		Weeks:              make([]calStatsWeekJSON, 0, len(stats.Weeks)),
+		TopOrganizers:      make([]calStatsOrgJSON, 0, len(stats.TopOrganizers)),
+		Recurring:          stats.RecurringCount,
+		AdHoc:              stats.AdHocCount,
+		RecurringRatio:     roundHours(stats.RecurringRatio()),
+		BackToBackStreaks:  stats.BackToBackStreaks,
+	}
+
+	for _, w := range stats.Weeks {
+		out.Weeks = append(out.Weeks, calStatsWeekJSON{
+			WeekStart: w.WeekStart.Format("2006-01-02"),
+			Meetings:  w.Meetings,
+			Hours:     roundHours(w.Hours),
+		})
+	}
+	for _, o := range stats.TopOrganizers {
+		out.TopOrganizers = append(out.TopOrganizers, calStatsOrgJSON{
+			Email:    o.Email,
+			Name:     o.Name,
+			Meetings: o.Meetings,
+		})
+	}
+	if stats.LongestStreak != nil {
+		out.LongestStreak = &calStatsStreakJSON{
+			Start:    stats.LongestStreak.Start.Format(time.RFC3339),
+			End:      stats.LongestStreak.End.Format(time.RFC3339),
+			Meetings: stats.LongestStreak.Meetings,
+			Hours:    roundHours(stats.LongestStreak.Duration().Hours()),
+		}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode stats: %w", err)
+	}
+	return string(data), nil
+}
+
+// renderCalStatsText renders meeting statistics as human-readable text.
+func renderCalStatsText(stats *calendar.Stats) string {
+	var sb strings.Builder
+	locale := presenter.CurrentLocale()
+
+	sb.WriteString(fmt.Sprintf("Meeting Statistics (%s to %s)\n\n",
+		locale.Date(stats.Since),
+		locale.Date(stats.Until)))
+
+	if stats.MeetingCount == 0 {
+		sb.WriteString("No meetings found")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Meetings:            %d\n", stats.MeetingCount))
+	sb.WriteString(fmt.Sprintf("Total hours:         %.1f\n", stats.TotalHours))
+	sb.WriteString(fmt.Sprintf("Average hours/week:  %.1f\n", stats.AverageWeeklyHours))
+	sb.WriteString(fmt.Sprintf("Recurring / ad-hoc:  %d / %d (%.0f%% recurring)\n",
+		stats.RecurringCount, stats.AdHocCount, stats.RecurringRatio()*100))
+	sb.WriteString(fmt.Sprintf("Back-to-back runs:   %d\n", stats.BackToBackStreaks))
+	if stats.LongestStreak != nil {
+		sb.WriteString(fmt.Sprintf("Longest run:         %d meetings on %s (%s - %s)\n",
+			stats.LongestStreak.Meetings,
+			locale.Date(stats.LongestStreak.Start),
+			locale.Time(stats.LongestStreak.Start),
+			locale.Time(stats.LongestStreak.End)))
+	}
+
+	sb.WriteString("\nHours per week:\n")
+	for _, w := range stats.Weeks {
+		sb.WriteString(fmt.Sprintf("  %s  %5.1fh  %3d meeting(s)\n",
+			locale.Date(w.WeekStart), w.Hours, w.Meetings))
+	}
+
+	if len(stats.TopOrganizers) > 0 {
+		sb.WriteString("\nTop organizers:\n")
+		for _, o := range stats.TopOrganizers {
+			name := o.Email
+			if o.Name != "" {
+				name = fmt.Sprintf("%s <%s>", o.Name, o.Email)
+			}
+			sb.WriteString(fmt.Sprintf("  %3d  %s\n", o.Meetings, name))
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,192 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestCalStatsCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(calCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"cal", "stats", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--since", "--calendar", "--top", "--gap"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+// setupCalStatsTest injects an event repository and resets stats flags.
+func setupCalStatsTest(t *testing.T, repo *MockEventRepository) *bytes.Buffer {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+
+	origSince, origCal, origTop, origGap, origFormat := calStatsSince, calStatsCalendar, calStatsTopOrganizers, calStatsGap, formatFlag
+	calStatsSince = "14d"
+	calStatsCalendar = "primary"
+	calStatsTopOrganizers = 5
+	calStatsGap = calendar.DefaultBackToBackGap
+	t.Cleanup(func() {
+		ResetDependencies()
+		calStatsSince, calStatsCalendar, calStatsTopOrganizers, calStatsGap, formatFlag = origSince, origCal, origTop, origGap, origFormat
+	})
+
+	return new(bytes.Buffer)
+}
+
+func TestRunCalStats_Text(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	event := calendar.NewEvent("Sync", start, start.Add(time.Hour))
+	event.Organizer = &calendar.Attendee{Email: "lead@example.com", DisplayName: "Lead"}
+
+	buf := setupCalStatsTest(t, &MockEventRepository{Events: []*calendar.Event{event}})
+	formatFlag = "table"
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runCalStats(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Meeting Statistics", "Meetings:            1", "Lead <lead@example.com>", "Hours per week"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunCalStats_JSON(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	event := calendar.NewEvent("Sync", start, start.Add(90*time.Minute))
+
+	buf := setupCalStatsTest(t, &MockEventRepository{Events: []*calendar.Event{event}})
+	formatFlag = "json"
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runCalStats(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got calStatsJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if got.Meetings != 1 {
+		t.Errorf("meetings = %d, want 1", got.Meetings)
+	}
+	if got.TotalHours != 1.5 {
+		t.Errorf("total_hours = %v, want 1.5", got.TotalHours)
+	}
+	if got.AdHoc != 1 {
+		t.Errorf("ad_hoc = %d, want 1", got.AdHoc)
+	}
+}
+
+func TestRunCalStats_Empty(t *testing.T) {
+	buf := setupCalStatsTest(t, &MockEventRepository{})
+	formatFlag = "plain"
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runCalStats(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !containsStr(buf.String(), "No meetings found") {
+		t.Errorf("expected empty message, got: %s", buf.String())
+	}
+}
+
+func TestRunCalStats_SinceDate(t *testing.T) {
+	tests := []struct {
+		since string
+		want  time.Time
+	}{
+		{"2025-08-01", time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local)},
+		{"yesterday", func() time.Time {
+			y, m, d := time.Now().AddDate(0, 0, -1).Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.since, func(t *testing.T) {
+			buf := setupCalStatsTest(t, &MockEventRepository{})
+			calStatsSince = tt.since
+			formatFlag = "json"
+
+			cmd := &cobra.Command{Use: "test"}
+			cmd.SetOut(buf)
+
+			if err := runCalStats(cmd, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got calStatsJSON
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+			}
+			if want := tt.want.Format(time.RFC3339); got.Since != want {
+				t.Errorf("since = %s, want %s", got.Since, want)
+			}
+		})
+	}
+}
+
+func TestRunCalStats_Errors(t *testing.T) {
+	t.Run("invalid since", func(t *testing.T) {
+		setupCalStatsTest(t, &MockEventRepository{})
+		calStatsSince = "soon"
+
+		err := runCalStats(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "invalid --since") {
+			t.Errorf("expected invalid --since error, got %v", err)
+		}
+	})
+
+	t.Run("future since", func(t *testing.T) {
+		setupCalStatsTest(t, &MockEventRepository{})
+		calStatsSince = "tomorrow"
+
+		err := runCalStats(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "not in the past") {
+			t.Errorf("expected not in the past error, got %v", err)
+		}
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		setupCalStatsTest(t, &MockEventRepository{ListErr: errors.New("boom")})
+
+		err := runCalStats(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "failed to list events") {
+			t.Errorf("expected list error, got %v", err)
+		}
+	})
+}
@@ -1297,3 +1297,82 @@ func TestGetGCalEventRepository_NoAccount(t *testing.T) {
 		t.Error("expected error when no account is configured")
 	}
 }
+
+func TestRunCalList_WithRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		rangeFlag string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   string
+	}{
+		{
+			name:      "closed date range includes end day",
+			rangeFlag: "2025-08-01..2025-08-15",
+			wantStart: time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2025, 8, 16, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:      "open end defaults to 30 days",
+			rangeFlag: "2025-08-01..",
+			wantStart: time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2025, 8, 31, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:      "single day",
+			rangeFlag: "2025-08-01",
+			wantStart: time.Date(2025, 8, 1, 0, 0, 0, 0, time.Local),
+			wantEnd:   time.Date(2025, 8, 2, 0, 0, 0, 0, time.Local),
+		},
+		{name: "invalid", rangeFlag: "soon..later", wantErr: "invalid --range"},
+		{name: "reversed", rangeFlag: "2025-08-15..2025-08-01", wantErr: "invalid --range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockEventRepository{}
+			SetDependencies(&Dependencies{
+				AccountService: &MockAccountService{
+					Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+					TokenManager: &MockTokenManager{},
+				},
+				RepoFactory: &MockRepositoryFactory{
+					EventRepo: mockRepo,
+				},
+			})
+			origFormat := formatFlag
+			origRange := calListRange
+			formatFlag = "plain"
+			calListRange = tt.rangeFlag
+			t.Cleanup(func() {
+				formatFlag = origFormat
+				calListRange = origRange
+				ResetDependencies()
+			})
+
+			cmd := &cobra.Command{Use: "test"}
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+
+			err := runCalList(cmd, []string{})
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runCalList failed: %v", err)
+			}
+			if !mockRepo.ListTimeMin.Equal(tt.wantStart) || !mockRepo.ListTimeMax.Equal(tt.wantEnd) {
+				t.Errorf("listed %v .. %v, want %v .. %v", mockRepo.ListTimeMin, mockRepo.ListTimeMax, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestCalListCmd_HasRangeFlag(t *testing.T) {
+	if calListCmd.Flags().Lookup("range") == nil {
+		t.Error("expected --range flag on cal list")
+	}
+}
@@ -0,0 +1,86 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/travel"
+)
+
+// checkTravelFlag is the name of the flag that turns the travel check on
+// or off for one command, overriding calendar.travel_check.
+const checkTravelFlag = "check-travel"
+
+// addCheckTravelFlag registers --check-travel on cmd.
+func addCheckTravelFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(checkTravelFlag, false, "warn when a neighbouring event at another location leaves too little travel time (default: calendar.travel_check)")
+}
+
+// travelCheckSettings returns whether the travel check is on for cmd and
+// the estimator configured for it, if any.
+func travelCheckSettings(cmd *cobra.Command) (bool, calendar.TravelEstimator) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.NewConfig()
+	}
+	enabled := cfg.Calendar.TravelCheck
+	if f := cmd.Flags().Lookup(checkTravelFlag); f != nil && f.Changed {
+		enabled, _ = cmd.Flags().GetBool(checkTravelFlag)
+	}
+	if !enabled {
+		return false, nil
+	}
+	if command := strings.TrimSpace(cfg.Calendar.TravelCommand); command != "" {
+		return true, &travel.CommandEstimator{Command: command}
+	}
+	return true, nil
+}
+
+// warnTravel prints a warning to stderr for each neighbouring event that
+// leaves too little time to travel to or from event, when the travel check
+// is on. Problems fetching the neighbours are reported but never fail the
+// command, since the event has already been saved.
+func warnTravel(ctx context.Context, cmd *cobra.Command, repo EventRepository, calendarID string, event *calendar.Event) {
+	enabled, estimator := travelCheckSettings(cmd)
+	if !enabled || event == nil || event.AllDay || strings.TrimSpace(event.Location) == "" {
+		return
+	}
+	others, err := repo.List(ctx, calendarID, event.Start.Add(-calendar.TravelLookaround), event.End.Add(calendar.TravelLookaround))
+	if err != nil {
+		output.Warnf(cmd, "could not check travel time: %v", err)
+		return
+	}
+	for _, w := range calendar.CheckTravel(ctx, event, others, estimator) {
+		output.Warnf(cmd, "%s", formatTravelWarning(event, w))
+	}
+}
+
+// formatTravelWarning describes a travel warning, e.g. `"Lunch" at Cafe
+// is only 5m after "Standup" at Office (travel takes about 25m)`.
+func formatTravelWarning(event *calendar.Event, w *calendar.TravelWarning) string {
+	relation := "after"
+	if w.After {
+		relation = "before"
+	}
+	need := "different location"
+	if w.Travel > 0 {
+		need = "travel takes about " + formatTravelDuration(w.Travel)
+	}
+	return fmt.Sprintf("%q at %s is only %s %s %q at %s (%s)",
+		event.Title, event.Location, formatTravelDuration(w.Gap), relation, w.Other.Title, w.Other.Location, need)
+}
+
+// formatTravelDuration formats a gap or travel time, e.g. "0m", "25m" or "1h10m".
+func formatTravelDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return strings.TrimSuffix(d.String(), "0s")
+}
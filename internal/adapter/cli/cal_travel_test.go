@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestRunCalCreate_TravelWarning(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	repo := &MockEventRepository{Events: []*calendar.Event{
+		{ID: "a", Title: "Standup", Location: "Office", Start: start.Add(-time.Hour), End: start.Add(-5 * time.Minute)},
+	}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+	defer ResetDependencies()
+
+	origTitle, origStart, origEnd, origLocation, origFormat := calCreateTitle, calCreateStart, calCreateEnd, calCreateLocation, formatFlag
+	defer func() {
+		calCreateTitle, calCreateStart, calCreateEnd, calCreateLocation, formatFlag = origTitle, origStart, origEnd, origLocation, origFormat
+	}()
+	calCreateTitle, calCreateLocation, formatFlag = "Lunch", "Cafe Luna", "plain"
+	calCreateStart, calCreateEnd = start.Format("2006-01-02 15:04"), ""
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		cmd := &cobra.Command{Use: "test"}
+		addCheckTravelFlag(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		var out, errOut bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		if err := runCalCreate(cmd, nil); err != nil {
+			t.Fatalf("runCalCreate failed: %v", err)
+		}
+		return errOut.String()
+	}
+
+	if got := run(t); got != "" {
+		t.Errorf("expected no check by default, got %q", got)
+	}
+	want := `Warning: "Lunch" at Cafe Luna is only 5m after "Standup" at Office (different location)`
+	if got := run(t, "--check-travel"); !strings.Contains(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Calendar.TravelCheck = true
+	cfg.Calendar.TravelCommand = "echo 20"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(t); !strings.Contains(got, "travel takes about 20m") {
+		t.Errorf("expected the configured estimate, got %q", got)
+	}
+	if got := run(t, "--check-travel=false"); got != "" {
+		t.Errorf("expected --check-travel=false to skip the check, got %q", got)
+	}
+}
+
+func TestRunCalRSVP_TravelWarning(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	start := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	repo := &MockEventRepository{
+		Event: &calendar.Event{ID: "inv", Title: "Offsite sync", Location: "HQ", Start: start, End: start.Add(time.Hour)},
+		Events: []*calendar.Event{
+			{ID: "b", Title: "Review", Location: "Office", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)},
+		},
+	}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+	defer ResetDependencies()
+
+	origAccept, origDecline, origTentative := calRSVPAccept, calRSVPDecline, calRSVPTentative
+	defer func() { calRSVPAccept, calRSVPDecline, calRSVPTentative = origAccept, origDecline, origTentative }()
+	calRSVPAccept, calRSVPDecline, calRSVPTentative = true, false, false
+
+	cmd := &cobra.Command{Use: "test"}
+	addCheckTravelFlag(cmd)
+	if err := cmd.ParseFlags([]string{"--check-travel"}); err != nil {
+		t.Fatal(err)
+	}
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	if err := runCalRSVP(cmd, []string{"inv"}); err != nil {
+		t.Fatalf("runCalRSVP failed: %v", err)
+	}
+	if !strings.Contains(errOut.String(), `is only 0m before "Review" at Office`) {
+		t.Errorf("unexpected warning: %q", errOut.String())
+	}
+}
@@ -73,9 +73,12 @@ var calFreeBusyCmd = &cobra.Command{
 
 Returns busy time periods within the specified time range.
 Use this to check availability before scheduling meetings.`,
-	Example: `  # Check availability for the next 24 hours
+	Example: `  # Check availability for a working day
   goog cal freebusy --start "2024-01-15T09:00:00Z" --end "2024-01-15T17:00:00Z"
 
+  # Relative times
+  goog cal freebusy --start "tomorrow 9am" --end "tomorrow 5pm"
+
   # Check multiple calendars
   goog cal freebusy --start "2024-01-15T09:00:00Z" --end "2024-01-16T17:00:00Z" \
     --calendars "primary,work@group.calendar.google.com"
@@ -83,7 +86,7 @@ Use this to check availability before scheduling meetings.`,
   # Check with specific calendar
   goog cal freebusy --start "2024-01-15T09:00:00Z" --end "2024-01-15T18:00:00Z" \
     --calendars "team@group.calendar.google.com"`,
-	Aliases: []string{"busy", "availability"},
+	Aliases: []string{"busy"},
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if calFreeBusyStart == "" {
 			return fmt.Errorf("--start flag is required")
@@ -174,8 +177,8 @@ func init() {
 	calQuickAddCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to create event in")
 
 	// Free/busy flags
-	calFreeBusyCmd.Flags().StringVar(&calFreeBusyStart, "start", "", "start time (RFC3339 format, required)")
-	calFreeBusyCmd.Flags().StringVar(&calFreeBusyEnd, "end", "", "end time (RFC3339 format, required)")
+	calFreeBusyCmd.Flags().StringVar(&calFreeBusyStart, "start", "", "start time, e.g. 2024-01-15T09:00:00Z or \"tomorrow 9am\" (required)")
+	calFreeBusyCmd.Flags().StringVar(&calFreeBusyEnd, "end", "", "end time, e.g. 2024-01-15T17:00:00Z or \"tomorrow 5pm\" (required)")
 	calFreeBusyCmd.Flags().StringSliceVar(&calFreeBusyCalendars, "calendars", []string{"primary"}, "calendar IDs to check (comma-separated)")
 
 	// RSVP flags
@@ -183,6 +186,7 @@ func init() {
 	calRSVPCmd.Flags().BoolVar(&calRSVPDecline, "decline", false, "decline the invitation")
 	calRSVPCmd.Flags().BoolVar(&calRSVPTentative, "tentative", false, "mark as tentative")
 	calRSVPCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID containing the event")
+	addCheckTravelFlag(calRSVPCmd)
 
 	// Move flags
 	calMoveCmd.Flags().StringVar(&calMoveDestination, "to", "", "destination calendar ID (required)")
@@ -229,7 +233,7 @@ func runCalQuickAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvent(event)
@@ -250,15 +254,15 @@ func runCalFreeBusy(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Parse start time
-	startTime, err := time.Parse(time.RFC3339, calFreeBusyStart)
+	startTime, err := parseDateTime(calFreeBusyStart)
 	if err != nil {
-		return fmt.Errorf("invalid start time format (use RFC3339, e.g., 2024-01-15T09:00:00Z): %w", err)
+		return fmt.Errorf("invalid start time format: %w", err)
 	}
 
 	// Parse end time
-	endTime, err := time.Parse(time.RFC3339, calFreeBusyEnd)
+	endTime, err := parseDateTime(calFreeBusyEnd)
 	if err != nil {
-		return fmt.Errorf("invalid end time format (use RFC3339, e.g., 2024-01-15T17:00:00Z): %w", err)
+		return fmt.Errorf("invalid end time format: %w", err)
 	}
 
 	// Validate time range
@@ -308,8 +312,8 @@ func renderFreeBusyTable(response *calendar.FreeBusyResponse, start, end time.Ti
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Free/Busy Information (%s to %s)\n\n",
-		start.Format("2006-01-02 15:04"),
-		end.Format("2006-01-02 15:04")))
+		presenter.CurrentLocale().DateTime(start),
+		presenter.CurrentLocale().DateTime(end)))
 
 	for calID, periods := range response.Calendars {
 		sb.WriteString(fmt.Sprintf("Calendar: %s\n", calID))
@@ -318,8 +322,8 @@ func renderFreeBusyTable(response *calendar.FreeBusyResponse, start, end time.Ti
 		} else {
 			for _, period := range periods {
 				sb.WriteString(fmt.Sprintf("  BUSY: %s - %s (%s)\n",
-					period.Start.Format("2006-01-02 15:04"),
-					period.End.Format("2006-01-02 15:04"),
+					presenter.CurrentLocale().DateTime(period.Start),
+					presenter.CurrentLocale().DateTime(period.End),
 					period.Duration().String()))
 			}
 		}
@@ -381,21 +385,26 @@ func runCalRSVP(cmd *cobra.Command, args []string) error {
 		response = calendar.ResponseTentative
 	}
 
-	// Get calendar service
-	gcalSvc, err := getGCalService(ctx)
+	// Get event repository using dependency injection
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Get events repository
-	eventRepo := gcalSvc.Events()
-
 	// RSVP to the event
 	err = eventRepo.RSVP(ctx, calCalendarFlag, eventID, response)
 	if err != nil {
 		return fmt.Errorf("failed to update RSVP: %w", err)
 	}
 
+	if response == calendar.ResponseAccepted {
+		if enabled, _ := travelCheckSettings(cmd); enabled {
+			if event, err := eventRepo.Get(ctx, calCalendarFlag, eventID); err == nil {
+				warnTravel(ctx, cmd, eventRepo, calCalendarFlag, event)
+			}
+		}
+	}
+
 	if !quietFlag {
 		responseText := map[string]string{
 			calendar.ResponseAccepted:  "accepted",
@@ -429,7 +438,7 @@ func runCalMove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderEvent(event)
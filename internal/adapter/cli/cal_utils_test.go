@@ -426,26 +426,22 @@ func TestCalCmd_Aliases(t *testing.T) {
 }
 
 func TestCalFreeBusyCmd_Aliases(t *testing.T) {
-	// Test that busy and availability work as aliases
+	// Test that busy works as an alias; availability is its own command
 	aliases := calFreeBusyCmd.Aliases
 	foundBusy := false
-	foundAvailability := false
 
 	for _, alias := range aliases {
 		if alias == "busy" {
 			foundBusy = true
 		}
 		if alias == "availability" {
-			foundAvailability = true
+			t.Error("availability should name the cal availability command, not alias freebusy")
 		}
 	}
 
 	if !foundBusy {
 		t.Error("expected 'busy' to be an alias")
 	}
-	if !foundAvailability {
-		t.Error("expected 'availability' to be an alias")
-	}
 }
 
 func TestCalRSVPCmd_Aliases(t *testing.T) {
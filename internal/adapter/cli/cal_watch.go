@@ -0,0 +1,284 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/calwatch"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/rules"
+)
+
+// Command flags for calendar watch command.
+var (
+	calWatchCalendar    string
+	calWatchNotify      bool
+	calWatchIncludePast bool
+	calWatchReset       bool
+)
+
+// calWatchNotifyFunc shows the --notify notification. It is a variable so
+// tests can record it instead.
+var calWatchNotifyFunc = rules.Notify
+
+// calWatchMaxNotified is how many changes a notification lists by name.
+const calWatchMaxNotified = 5
+
+// calWatchCmd reports what changed in a calendar since the last run.
+var calWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Report events created, changed or cancelled since the last run",
+	Long: `Report the events of a calendar created, changed or cancelled since
+the command last ran, for a scheduled job that tells you when meetings
+move.
+
+The first run only records the calendar's events. Later runs ask
+Google Calendar for the changes since then, with the sync token it
+returned, and compare them with the events recorded:
+
+  New        Offsite, Wed 21 Oct 09:00 - 11:00
+  Changed    Design review: moved from Mon 19 Oct 11:00 - 12:00 to Mon 19 Oct 14:00 - 15:00
+  Cancelled  Lunch, Mon 19 Oct 12:00 - 13:00
+
+Changes to the title, time, location and status are reported; changes
+to anything else, such as guests' answers, are not. A moved occurrence
+of a recurring event is reported as changed. Changes to events that
+ended before now are left out unless --include-past is given.
+
+What was last seen is kept in cal_watch.json next to the config file,
+per account and calendar. When Google no longer accepts the sync token,
+the events are listed in full and compared with it instead. --reset
+forgets it and starts again from the next run.
+
+--notify also shows a desktop notification when there are changes.
+Plain output is one tab-separated line per change: kind, event ID,
+start, title and what changed.`,
+	Example: `  # Record the calendar, then report changes on later runs
+  goog cal watch
+
+  # Check a shared calendar every 15 minutes and notify on changes
+  goog schedule add "*/15 * * * *" "cal watch --calendar team-calendar --notify"
+
+  # Changes as JSON for a script
+  goog cal watch --format json`,
+	Args: cobra.NoArgs,
+	RunE: runCalWatch,
+}
+
+func init() {
+	calCmd.AddCommand(calWatchCmd)
+
+	calWatchCmd.Flags().StringVar(&calWatchCalendar, "calendar", "primary", "calendar ID or name to watch")
+	calWatchCmd.Flags().BoolVar(&calWatchNotify, "notify", false, "show a desktop notification when there are changes")
+	calWatchCmd.Flags().BoolVar(&calWatchIncludePast, "include-past", false, "also report changes to events that have ended")
+	calWatchCmd.Flags().BoolVar(&calWatchReset, "reset", false, "forget what was last seen and record the calendar again")
+}
+
+// runCalWatch handles the cal watch command.
+func runCalWatch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	calendarID, err := resolveCalendarRef(ctx, calWatchCalendar)
+	if err != nil {
+		return err
+	}
+	_, account, err := getTokenSourceWithEmailFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	repo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	history, ok := repo.(EventHistory)
+	if !ok {
+		return fmt.Errorf("calendar changes cannot be listed for this account")
+	}
+
+	path := calwatch.Path()
+	if calWatchReset {
+		if err := calwatch.Remove(path, account, calendarID); err != nil {
+			return err
+		}
+	}
+	state, err := calwatch.Load(path, account, calendarID)
+	if err != nil {
+		return err
+	}
+
+	token := ""
+	if state != nil {
+		token = state.SyncToken
+	}
+	changes, err := history.Sync(ctx, calendarID, token)
+	if errors.Is(err, calendar.ErrSyncTokenExpired) {
+		output.Verbosef(cmd, "Sync token expired; comparing the full list of events")
+		changes, err = history.Sync(ctx, calendarID, "")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list calendar changes: %w", err)
+	}
+
+	now := time.Now()
+	if state == nil {
+		state = &calwatch.State{
+			Account:    account,
+			CalendarID: calendarID,
+			SyncToken:  changes.SyncToken,
+			CheckedAt:  now,
+			Events:     calendar.ApplyEvents(nil, changes.Events, true),
+		}
+		if err := calwatch.Save(path, state); err != nil {
+			return err
+		}
+		switch {
+		case formatFlag == presenter.FormatJSON:
+			cmd.Println("[]")
+		case formatFlag != presenter.FormatPlain && !quietFlag:
+			cmd.Printf("Watching %d event(s) in %s; changes are reported from the next run.\n", len(state.Events), calendarID)
+		}
+		return nil
+	}
+
+	var reported []*calendar.EventChange
+	for _, c := range calendar.DiffEvents(state.Events, changes.Events, changes.Full) {
+		if calWatchIncludePast || !changeIsPast(c, now) {
+			reported = append(reported, c)
+		}
+	}
+	since := state.CheckedAt
+	state.Events = calendar.ApplyEvents(state.Events, changes.Events, changes.Full)
+	state.SyncToken = changes.SyncToken
+	state.CheckedAt = now
+	if err := calwatch.Save(path, state); err != nil {
+		return err
+	}
+
+	if err := renderEventChanges(cmd, reported, since); err != nil {
+		return err
+	}
+	if calWatchNotify && len(reported) > 0 {
+		if err := calWatchNotifyFunc(ctx, fmt.Sprintf("goog: %d calendar change(s)", len(reported)), notificationText(reported)); err != nil {
+			output.Warnf(cmd, "notification not shown: %v", err)
+		}
+	}
+	return nil
+}
+
+// changeIsPast reports whether a change is to an event that had ended by
+// now, both before and after the change.
+func changeIsPast(c *calendar.EventChange, now time.Time) bool {
+	if c.Before != nil && !c.Before.End.Before(now) {
+		return false
+	}
+	return c.Event.End.Before(now)
+}
+
+// renderEventChanges prints the changes in the output format.
+func renderEventChanges(cmd *cobra.Command, changes []*calendar.EventChange, since time.Time) error {
+	switch formatFlag {
+	case presenter.FormatJSON:
+		if changes == nil {
+			changes = []*calendar.EventChange{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode changes: %w", err)
+		}
+		cmd.Println(string(data))
+	case presenter.FormatPlain:
+		for _, c := range changes {
+			cmd.Printf("%s\t%s\t%s\t%s\t%s\n", c.Kind, c.Event.ID, c.Event.Start.Format(time.RFC3339), c.Event.Title, changeDetails(c))
+		}
+	default:
+		for _, c := range changes {
+			cmd.Println(describeEventChange(c))
+		}
+		if !quietFlag {
+			if len(changes) == 0 {
+				cmd.Printf("No changes since %s.\n", presenter.CurrentLocale().DateTime(since.Local()))
+			} else {
+				cmd.Printf("%d change(s) since %s.\n", len(changes), presenter.CurrentLocale().DateTime(since.Local()))
+			}
+		}
+	}
+	return nil
+}
+
+// describeEventChange writes a change as one line, as table output and
+// notifications show it.
+func describeEventChange(c *calendar.EventChange) string {
+	title := c.Event.Title
+	if title == "" {
+		title = "(no title)"
+	}
+	switch c.Kind {
+	case calendar.ChangeCreated:
+		return fmt.Sprintf("%-10s %s, %s", "New", title, eventWhen(c.Event))
+	case calendar.ChangeCancelled:
+		return fmt.Sprintf("%-10s %s, %s", "Cancelled", title, eventWhen(c.Event))
+	default:
+		return fmt.Sprintf("%-10s %s: %s", "Changed", title, changeDetails(c))
+	}
+}
+
+// changeDetails describes what changed in an updated event.
+func changeDetails(c *calendar.EventChange) string {
+	if c.Kind != calendar.ChangeUpdated || c.Before == nil {
+		return ""
+	}
+	var details []string
+	for _, field := range c.Fields {
+		switch field {
+		case calendar.FieldTime:
+			details = append(details, fmt.Sprintf("moved from %s to %s", eventWhen(c.Before), eventWhen(c.Event)))
+		case calendar.FieldTitle:
+			details = append(details, fmt.Sprintf("renamed from %q", c.Before.Title))
+		case calendar.FieldLocation:
+			switch {
+			case c.Event.Location == "":
+				details = append(details, fmt.Sprintf("location %q removed", c.Before.Location))
+			case c.Before.Location == "":
+				details = append(details, fmt.Sprintf("location set to %q", c.Event.Location))
+			default:
+				details = append(details, fmt.Sprintf("location changed from %q to %q", c.Before.Location, c.Event.Location))
+			}
+		case calendar.FieldStatus:
+			details = append(details, fmt.Sprintf("now %s", c.Event.Status))
+		}
+	}
+	return strings.Join(details, "; ")
+}
+
+// eventWhen writes when an event takes place, in local time.
+func eventWhen(e *calendar.Event) string {
+	locale := presenter.CurrentLocale()
+	if e.AllDay {
+		return locale.Date(e.Start) + " (All Day)"
+	}
+	start, end := e.Start.Local(), e.End.Local()
+	if locale.Date(start) == locale.Date(end) {
+		return locale.DateTime(start) + " - " + locale.Time(end)
+	}
+	return locale.DateTime(start) + " - " + locale.DateTime(end)
+}
+
+// notificationText lists the first changes for a notification.
+func notificationText(changes []*calendar.EventChange) string {
+	var lines []string
+	for i, c := range changes {
+		if i == calWatchMaxNotified {
+			lines = append(lines, fmt.Sprintf("and %d more", len(changes)-i))
+			break
+		}
+		lines = append(lines, strings.Join(strings.Fields(describeEventChange(c)), " "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// syncEventRepository adds sync tokens to a mock event repository.
+type syncEventRepository struct {
+	*MockEventRepository
+	// syncs are the results of Sync by sync token; other tokens have
+	// expired.
+	syncs  map[string]*calendar.EventChanges
+	tokens []string
+}
+
+func (r *syncEventRepository) Sync(ctx context.Context, calendarID, syncToken string) (*calendar.EventChanges, error) {
+	r.tokens = append(r.tokens, syncToken)
+	changes, ok := r.syncs[syncToken]
+	if !ok {
+		return nil, calendar.ErrSyncTokenExpired
+	}
+	return changes, nil
+}
+
+// setupCalWatchTest installs a syncable event repository and keeps the
+// watch state in a temporary directory.
+func setupCalWatchTest(t *testing.T, syncs map[string]*calendar.EventChanges) *syncEventRepository {
+	t.Helper()
+	repo := &syncEventRepository{MockEventRepository: &MockEventRepository{}, syncs: syncs}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{EventRepo: repo},
+	})
+	t.Cleanup(ResetDependencies)
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	origCalendar, origNotify, origPast, origReset := calWatchCalendar, calWatchNotify, calWatchIncludePast, calWatchReset
+	origFormat, origQuiet, origNotifyFunc := formatFlag, quietFlag, calWatchNotifyFunc
+	calWatchCalendar, calWatchNotify, calWatchIncludePast, calWatchReset = "primary", false, false, false
+	formatFlag, quietFlag = "table", false
+	t.Cleanup(func() {
+		calWatchCalendar, calWatchNotify, calWatchIncludePast, calWatchReset = origCalendar, origNotify, origPast, origReset
+		formatFlag, quietFlag, calWatchNotifyFunc = origFormat, origQuiet, origNotifyFunc
+	})
+	return repo
+}
+
+// runWatch runs cal watch and returns its output.
+func runWatch(t *testing.T) string {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&bytes.Buffer{})
+	if err := runCalWatch(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRunCalWatch(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	review := &calendar.Event{ID: "review", Title: "Review", Start: start, End: start.Add(time.Hour)}
+	lunch := &calendar.Event{ID: "lunch", Title: "Lunch", Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)}
+	old := &calendar.Event{ID: "old", Title: "Retro", Start: start.Add(-96 * time.Hour), End: start.Add(-95 * time.Hour)}
+	repo := setupCalWatchTest(t, map[string]*calendar.EventChanges{
+		"": {Events: []*calendar.Event{review, lunch, old}, SyncToken: "t1", Full: true},
+		"t1": {SyncToken: "t2", Events: []*calendar.Event{
+			{ID: "review", Title: "Review", Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour)},
+			{ID: "lunch", Status: calendar.StatusCancelled},
+			{ID: "old", Title: "Retro (notes)", Start: old.Start, End: old.End},
+			{ID: "offsite", Title: "Offsite", Start: start.Add(24 * time.Hour), End: start.Add(26 * time.Hour)},
+		}},
+		"t2": {SyncToken: "t3"},
+	})
+
+	if out := runWatch(t); !contains(out, "Watching 3 event(s)") {
+		t.Errorf("first run output = %q", out)
+	}
+
+	var notified string
+	calWatchNotify = true
+	calWatchNotifyFunc = func(ctx context.Context, title, text string) error {
+		notified = title + "\n" + text
+		return nil
+	}
+	out := runWatch(t)
+	for _, want := range []string{"Changed    Review: moved from", "Cancelled  Lunch", "New        Offsite", "3 change(s) since"} {
+		if !contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if contains(out, "Retro") {
+		t.Errorf("a change to a past event was reported:\n%s", out)
+	}
+	if !contains(notified, "goog: 3 calendar change(s)") || !contains(notified, "Cancelled Lunch") {
+		t.Errorf("notification = %q", notified)
+	}
+
+	notified = ""
+	if out := runWatch(t); !contains(out, "No changes since") || notified != "" {
+		t.Errorf("third run output = %q, notification %q", out, notified)
+	}
+
+	// t3 has expired: the full list is compared with what was seen
+	moved := repo.syncs["t1"].Events[0]
+	repo.syncs[""] = &calendar.EventChanges{Events: []*calendar.Event{moved}, SyncToken: "t4", Full: true}
+	formatFlag = "plain"
+	out = runWatch(t)
+	if !contains(out, "cancelled\toffsite\t") || contains(out, "review") {
+		t.Errorf("resync output = %q", out)
+	}
+	if got := repo.tokens[len(repo.tokens)-2:]; got[0] != "t3" || got[1] != "" {
+		t.Errorf("sync tokens = %v", repo.tokens)
+	}
+}
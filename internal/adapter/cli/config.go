@@ -4,6 +4,7 @@ package cli
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
@@ -44,8 +45,61 @@ Available keys:
   timezone                 - Timezone for date/time display
   mail.default_label       - Default mail label to list
   mail.page_size           - Default number of messages per page
+  mail.todo_label          - Label applied by 'mail todo add'
+  mail.done_label          - Label applied by 'mail todo done' (optional)
+  mail.mute_label          - Label marking threads muted by 'mail mute'
+  mail.readlater_dir       - Directory 'mail readlater' saves notes to
+  mail.translate_api_key   - Cloud Translation API key for 'mail show --translate'
+  mail.summarize_command   - Command 'thread summarize' pipes threads to
+  mail.summarize_url       - Endpoint 'thread summarize' posts threads to
+  mail.check_mx            - Check recipient mail servers before sending (true|false)
+  mail.queue_offline       - Queue mail in the outbox when offline (true|false)
+  mail.headers             - Headers added to sent mail, as "Name: value"
+                             entries separated by semicolons
+  mail.throttle            - Minimum time between messages to the same
+                             recipient, e.g. 10m (empty or 0 disables)
+  mail.throttle_overrides  - Comma-separated address=duration or
+                             @domain=duration cooldowns (0 exempts)
+  mail.duplicate_window    - How far back 'mail send' looks for the same
+                             message already sent, e.g. 1h (empty or 0
+                             disables)
+  mail.reply_quote         - Where replies quote the original: top (reply
+                             above it), bottom or none (default)
+  mail.quote_prefix        - Prefix of quoted lines in replies (default "> ")
+  mail.auto_bcc            - Comma-separated addresses blind-copied on all
+                             sent mail, e.g. a CRM dropbox (empty disables)
+  mail.search_cache_ttl    - How long 'mail search' reuses the results of
+                             the same search, e.g. 2m (empty or 0 disables)
+  mail.views.<name>        - Gmail search used by 'mail digest --view <name>'
+                             (empty removes the view)
+  mail.groups.<name>       - Comma-separated addresses sent to for 'group:<name>'
+                             recipients (empty removes the group)
   calendar.default_calendar - Default calendar ID
-  calendar.week_start      - First day of week (sunday|monday)`,
+  calendar.week_start      - First day of week (sunday|monday)
+  calendar.travel_check    - Warn about travel between events (true|false)
+  calendar.travel_command  - Command that estimates travel time (optional)
+  display.date_format      - Date format (iso|us|eu|de or a Go layout)
+  display.time_format      - Time format (24h|12h or a Go layout)
+  display.size_units       - Byte size units (iec|si)
+  display.a11y             - Accessible output, as --a11y (true|false)
+  keyring.backend          - Token storage (auto|keychain|secret-service|wincred|file)
+  keyring.protector        - Hardware key for token files
+                             (ssh-agent[:<fingerprint>]|plugin:<name>)
+  metrics.enabled          - Record local usage metrics (true|false)
+  metrics.textfile         - Also write metrics to this Prometheus textfile
+  history.enabled          - Record commands for 'goog history' (true|false)
+  api.retry_budget         - Retries one command may make after rate limits
+                             and temporary errors (0 for no limit)
+  accounts.<alias>.read_only - Block changes made with this account (true|false)
+  accounts.<alias>.<service>_endpoint - Base URL used instead of Google's for
+                             gmail, calendar, tasks or people (empty resets)
+  accounts.<alias>.label_map - Labels for mail copied into the account, as
+                             Source=Target pairs separated by commas
+  accounts.<alias>.metadata.<key> - Account metadata for templates and
+                             rules (empty removes; see 'goog config account')
+  <command>.<flag>         - Default for a command's flag, e.g.
+                             mail.list.max_results (with --account, only
+                             for that account; see 'goog config settings')`,
 	Example: `  # Set default format to JSON
   goog config set default_format json
 
@@ -53,7 +107,26 @@ Available keys:
   goog config set mail.page_size 50
 
   # Set default calendar
-  goog config set calendar.default_calendar primary`,
+  goog config set calendar.default_calendar primary
+
+  # Show dates as 31/12/2024 and times as 2:30 PM
+  goog config set display.date_format eu
+  goog config set display.time_format 12h
+
+  # Use a custom date layout
+  goog config set display.date_format "Jan 2, 2006"
+
+  # Never change anything in the work account
+  goog config set accounts.work.read_only true
+
+  # Send the work account's Gmail requests through a gateway
+  goog config set accounts.work.gmail_endpoint https://gateway.example.com/gmail/
+
+  # File work mail copied into the personal account under Archive/
+  goog config set accounts.personal.label_map "Work/*=Archive/Work/*,INBOX="
+
+  # List 50 messages by default in mail list
+  goog config set mail.list.page_size 50`,
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
 }
@@ -70,8 +143,45 @@ Available keys:
   timezone                 - Timezone for date/time display
   mail.default_label       - Default mail label
   mail.page_size           - Messages per page
+  mail.todo_label          - Todo workflow label
+  mail.done_label          - Done workflow label
+  mail.mute_label          - Muted thread label
+  mail.readlater_dir       - Read-later notes directory
+  mail.translate_api_key   - Cloud Translation API key
+  mail.summarize_command   - Thread summarizer command
+  mail.summarize_url       - Thread summarizer endpoint
+  mail.check_mx            - Whether recipient mail servers are checked
+  mail.queue_offline       - Whether mail is queued when offline
+  mail.headers             - Headers added to sent mail
+  mail.throttle            - Cooldown between messages to a recipient
+  mail.throttle_overrides  - Per-address and per-domain cooldowns
+  mail.duplicate_window    - How far back sent mail is checked for duplicates
+  mail.reply_quote         - Where replies quote the original
+  mail.quote_prefix        - Prefix of quoted lines in replies
+  mail.auto_bcc            - Addresses blind-copied on sent mail
+  mail.search_cache_ttl    - How long search results are reused
+  mail.views.<name>        - Search of a saved view
+  mail.groups.<name>       - Addresses of a recipient group
   calendar.default_calendar - Default calendar ID
-  calendar.week_start      - First day of week`,
+  calendar.week_start      - First day of week
+  calendar.travel_check    - Whether travel between events is checked
+  calendar.travel_command  - Travel time estimator command
+  display.date_format      - Date format
+  display.time_format      - Time format
+  display.size_units       - Byte size units
+  display.a11y             - Whether output is accessible
+  keyring.backend          - Token storage backend
+  keyring.protector        - Hardware key protecting token files
+  metrics.enabled          - Whether local usage metrics are recorded
+  metrics.textfile         - Prometheus textfile for metrics
+  history.enabled          - Whether commands are recorded in the history
+  api.retry_budget         - Retries allowed per command
+  accounts.<alias>.read_only - Whether changes are blocked for an account
+  accounts.<alias>.<service>_endpoint - Endpoint override for a service
+  accounts.<alias>.label_map - Label map for mail copied into an account
+  accounts.<alias>.metadata.<key> - Metadata of an account
+  <command>.<flag>         - Per-command setting (with --account, the
+                             account's own)`,
 	Example: `  # Get default format
   goog config get default_format
 
@@ -117,11 +227,108 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	cmd.Println("mail:")
 	cmd.Printf("  default_label: %s\n", cfg.Mail.DefaultLabel)
 	cmd.Printf("  page_size: %d\n", cfg.Mail.PageSize)
+	cmd.Printf("  todo_label: %s\n", cfg.Mail.TodoLabel)
+	cmd.Printf("  done_label: %s\n", cfg.Mail.DoneLabel)
+	cmd.Printf("  mute_label: %s\n", cfg.Mail.MuteLabel)
+	if cfg.Mail.ReadLaterDir != "" {
+		cmd.Printf("  readlater_dir: %s\n", cfg.Mail.ReadLaterDir)
+	}
+	if cfg.Mail.TranslateAPIKey != "" {
+		cmd.Println("  translate_api_key: (set)")
+	}
+	if cfg.Mail.SummarizeCommand != "" {
+		cmd.Printf("  summarize_command: %s\n", cfg.Mail.SummarizeCommand)
+	}
+	if cfg.Mail.SummarizeURL != "" {
+		cmd.Printf("  summarize_url: %s\n", cfg.Mail.SummarizeURL)
+	}
+	cmd.Printf("  check_mx: %t\n", cfg.Mail.CheckMX)
+	cmd.Printf("  queue_offline: %t\n", cfg.Mail.QueueOffline)
+	if len(cfg.Mail.Headers) > 0 {
+		cmd.Println("  headers:")
+		for _, h := range cfg.Mail.Headers {
+			cmd.Printf("    - %s\n", h)
+		}
+	}
+	if cfg.Mail.Throttle != "" {
+		cmd.Printf("  throttle: %s\n", cfg.Mail.Throttle)
+	}
+	if len(cfg.Mail.ThrottleOverrides) > 0 {
+		cmd.Println("  throttle_overrides:")
+		for _, o := range cfg.Mail.ThrottleOverrides {
+			cmd.Printf("    - %s\n", o)
+		}
+	}
+	if cfg.Mail.DuplicateWindow != "" {
+		cmd.Printf("  duplicate_window: %s\n", cfg.Mail.DuplicateWindow)
+	}
+	if cfg.Mail.ReplyQuote != "" {
+		cmd.Printf("  reply_quote: %s\n", cfg.Mail.ReplyQuote)
+	}
+	if cfg.Mail.QuotePrefix != "" {
+		cmd.Printf("  quote_prefix: %q\n", cfg.Mail.QuotePrefix)
+	}
+	if len(cfg.Mail.AutoBcc) > 0 {
+		cmd.Printf("  auto_bcc: %s\n", strings.Join(cfg.Mail.AutoBcc, ", "))
+	}
+	if cfg.Mail.SearchCacheTTL != "" {
+		cmd.Printf("  search_cache_ttl: %s\n", cfg.Mail.SearchCacheTTL)
+	}
+	if len(cfg.Mail.Views) > 0 {
+		cmd.Println("  views:")
+		names := make([]string, 0, len(cfg.Mail.Views))
+		for name := range cfg.Mail.Views {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			cmd.Printf("    %s: %s\n", name, cfg.Mail.Views[name])
+		}
+	}
+	if len(cfg.Mail.Groups) > 0 {
+		cmd.Println("  groups:")
+		names := make([]string, 0, len(cfg.Mail.Groups))
+		for name := range cfg.Mail.Groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			cmd.Printf("    %s: %s\n", name, strings.Join(cfg.Mail.Groups[name], ", "))
+		}
+	}
 
 	cmd.Println()
 	cmd.Println("calendar:")
 	cmd.Printf("  default_calendar: %s\n", cfg.Calendar.DefaultCalendar)
 	cmd.Printf("  week_start: %s\n", cfg.Calendar.WeekStart)
+	cmd.Printf("  travel_check: %t\n", cfg.Calendar.TravelCheck)
+	if cfg.Calendar.TravelCommand != "" {
+		cmd.Printf("  travel_command: %s\n", cfg.Calendar.TravelCommand)
+	}
+
+	cmd.Println()
+	cmd.Println("display:")
+	cmd.Printf("  date_format: %s\n", cfg.Display.DateFormat)
+	cmd.Printf("  time_format: %s\n", cfg.Display.TimeFormat)
+	cmd.Printf("  size_units: %s\n", cfg.Display.SizeUnits)
+
+	cmd.Println()
+	cmd.Println("keyring:")
+	cmd.Printf("  backend: %s\n", cfg.Keyring.Backend)
+	cmd.Printf("  protector: %s\n", cfg.Keyring.Protector)
+
+	cmd.Println()
+	cmd.Println("metrics:")
+	cmd.Printf("  enabled: %v\n", cfg.Metrics.Enabled)
+	cmd.Printf("  textfile: %s\n", cfg.Metrics.Textfile)
+
+	cmd.Println()
+	cmd.Println("history:")
+	cmd.Printf("  enabled: %v\n", cfg.History.Enabled)
+
+	cmd.Println()
+	cmd.Println("api:")
+	cmd.Printf("  retry_budget: %d\n", cfg.API.RetryBudget)
 
 	if len(cfg.Accounts) > 0 {
 		cmd.Println()
@@ -140,6 +347,36 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 			if !acc.AddedAt.IsZero() {
 				cmd.Printf("    added_at: %s\n", acc.AddedAt.Format("2006-01-02T15:04:05Z07:00"))
 			}
+			if acc.ReadOnly {
+				cmd.Println("    read_only: true")
+			}
+			if len(acc.Commands) > 0 {
+				cmd.Println("    commands:")
+				for _, command := range acc.Commands {
+					cmd.Printf("      - %s\n", command)
+				}
+			}
+			for _, service := range []string{"gmail", "calendar", "tasks", "people"} {
+				if endpoint := acc.Endpoints()[service]; endpoint != "" {
+					cmd.Printf("    %s_endpoint: %s\n", service, endpoint)
+				}
+			}
+			if len(acc.LabelMap) > 0 {
+				cmd.Println("    label_map:")
+				for _, entry := range acc.LabelMap {
+					cmd.Printf("      - %s\n", entry)
+				}
+			}
+			if len(acc.Metadata) > 0 {
+				cmd.Println("    metadata:")
+				for _, key := range sortedMetadataKeys(acc.Metadata) {
+					cmd.Printf("      %s: %s\n", key, acc.Metadata[key])
+				}
+			}
+			if len(acc.Settings) > 0 {
+				cmd.Println("    settings:")
+				printSettings(cmd, acc.Settings, "      ")
+			}
 			if len(acc.Scopes) > 0 {
 				cmd.Println("    scopes:")
 				for _, scope := range acc.Scopes {
@@ -149,9 +386,42 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(cfg.Aliases) > 0 {
+		cmd.Println()
+		cmd.Println("aliases:")
+		names := make([]string, 0, len(cfg.Aliases))
+		for name := range cfg.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			cmd.Printf("  %s: %s\n", name, cfg.Aliases[name])
+		}
+	}
+
+	if len(cfg.Settings) > 0 {
+		cmd.Println()
+		cmd.Println("settings:")
+		printSettings(cmd, cfg.Settings, "  ")
+	}
+
 	return nil
 }
 
+// printSettings prints per-command settings as sorted key: value lines.
+func printSettings(cmd *cobra.Command, settings config.Settings, indent string) {
+	flat := settings.Flatten()
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		cmd.Printf("%s%s: %s\n", indent, key, flat[key])
+	}
+}
+
 // runConfigSet handles the config set command.
 func runConfigSet(cmd *cobra.Command, args []string) error {
 	key := args[0]
@@ -163,10 +433,19 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Set value
-	if err := cfg.SetValue(key, value); err != nil {
+	// Set value; keys that are not config keys may be per-command settings
+	if _, getErr := cfg.GetValue(key); getErr != nil && commandSetting(key) {
+		if err := setCommandSetting(cfg, key, value); err != nil {
+			return fmt.Errorf("failed to set config value: %w", err)
+		}
+	} else if err := cfg.SetValue(key, value); err != nil {
 		return fmt.Errorf("failed to set config value: %w", err)
 	}
+	if strings.HasPrefix(key, "display.") {
+		if _, err := localeFromConfig(cfg); err != nil {
+			return fmt.Errorf("failed to set config value: %w", err)
+		}
+	}
 
 	// Save config
 	if err := cfg.Save(); err != nil {
@@ -189,6 +468,9 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 
 	// Get value
 	value, err := cfg.GetValue(key)
+	if err != nil && commandSetting(key) {
+		value, err = getCommandSetting(cfg, key)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get config value: %w", err)
 	}
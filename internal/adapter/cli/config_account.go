@@ -0,0 +1,178 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// configAccountCmd groups the account metadata commands.
+var configAccountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Manage metadata describing accounts",
+	Long: `Manage metadata describing accounts: free key=value pairs such as
+role=work or cost-centre=4711, kept with the account in the config file.
+
+Metadata can be used where goog fills in templates: {account.<key>} is
+replaced with the value in mail rules' notify text, 'mail attachments
+extract --rename' and 'mail watchdir --subject/--body'. Rules can test it
+with a condition on the field account.<key>, and exec actions get it as
+GOOG_ACCOUNT_<KEY>. A key the account has no value for is empty.
+
+Keys are letters, digits, hyphens and underscores, and are stored in
+lower case.`,
+}
+
+// configAccountSetCmd sets metadata of an account.
+var configAccountSetCmd = &cobra.Command{
+	Use:   "set <account> <key=value>...",
+	Short: "Set metadata of an account",
+	Long: `Set metadata of an account, given by alias or email address. An
+empty value removes the key.`,
+	Example: `  # Describe the work account
+  goog config account set work role=work cost-centre=4711
+
+  # Remove a key
+  goog config account set work cost-centre=`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runConfigAccountSet,
+}
+
+// configAccountUnsetCmd removes metadata of an account.
+var configAccountUnsetCmd = &cobra.Command{
+	Use:     "unset <account> <key>...",
+	Short:   "Remove metadata of an account",
+	Example: `  goog config account unset work cost-centre`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    runConfigAccountUnset,
+}
+
+// configAccountShowCmd shows the metadata of an account.
+var configAccountShowCmd = &cobra.Command{
+	Use:   "show [account]",
+	Short: "Show the metadata of an account",
+	Long: `Show the metadata of an account, given by alias or email address,
+or of the account in use (--account or the default account).`,
+	Example: `  goog config account show work
+  goog config account show work --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigAccountShow,
+}
+
+func init() {
+	configCmd.AddCommand(configAccountCmd)
+	configAccountCmd.AddCommand(configAccountSetCmd)
+	configAccountCmd.AddCommand(configAccountUnsetCmd)
+	configAccountCmd.AddCommand(configAccountShowCmd)
+}
+
+// runConfigAccountSet handles the config account set command.
+func runConfigAccountSet(cmd *cobra.Command, args []string) error {
+	pairs := make([][2]string, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid metadata %q: use key=value", arg)
+		}
+		pairs = append(pairs, [2]string{key, value})
+	}
+	return updateAccountMetadata(cmd, args[0], pairs)
+}
+
+// runConfigAccountUnset handles the config account unset command.
+func runConfigAccountUnset(cmd *cobra.Command, args []string) error {
+	pairs := make([][2]string, 0, len(args)-1)
+	for _, key := range args[1:] {
+		pairs = append(pairs, [2]string{key, ""})
+	}
+	return updateAccountMetadata(cmd, args[0], pairs)
+}
+
+// updateAccountMetadata sets the key/value pairs in the metadata of an
+// account and saves the config. Nothing is saved when a pair is invalid.
+func updateAccountMetadata(cmd *cobra.Command, name string, pairs [][2]string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	alias, err := accountAlias(cfg, name)
+	if err != nil {
+		return err
+	}
+	for _, pair := range pairs {
+		if err := cfg.SetAccountMetadata(alias, pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if !quietFlag {
+		cmd.Printf("Updated metadata of account %s\n", alias)
+	}
+	return nil
+}
+
+// runConfigAccountShow handles the config account show command.
+func runConfigAccountShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	name := accountFlag
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		name = cfg.DefaultAccount
+	}
+	alias, err := accountAlias(cfg, name)
+	if err != nil {
+		return err
+	}
+	metadata := cfg.Accounts[alias].Metadata
+
+	switch formatFlag {
+	case presenter.FormatJSON:
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		data, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	case presenter.FormatPlain:
+		for _, key := range sortedMetadataKeys(metadata) {
+			cmd.Printf("%s\t%s\n", key, metadata[key])
+		}
+		return nil
+	}
+	if len(metadata) == 0 {
+		if !quietFlag {
+			cmd.Printf("Account %s has no metadata\n", alias)
+		}
+		return nil
+	}
+	for _, key := range sortedMetadataKeys(metadata) {
+		cmd.Printf("%s=%s\n", key, metadata[key])
+	}
+	return nil
+}
+
+// sortedMetadataKeys returns the keys of metadata in order.
+func sortedMetadataKeys(metadata map[string]string) []string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfigAccountMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", path)
+	data := "default_account: work\naccounts:\n  work:\n    email: me@work.example\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	origFormat, origQuiet, origAccount := formatFlag, quietFlag, accountFlag
+	t.Cleanup(func() { formatFlag, quietFlag, accountFlag = origFormat, origQuiet, origAccount })
+	formatFlag, quietFlag, accountFlag = "table", false, ""
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runConfigAccountSet(cmd, []string{"me@work.example", "Role=work", "cost-centre=4711"}); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if err := runConfigAccountSet(cmd, []string{"work", "role"}); err == nil {
+		t.Error("expected an error for a pair without =")
+	}
+	if err := runConfigAccountSet(cmd, []string{"home", "role=x"}); err == nil {
+		t.Error("expected an error for an unknown account")
+	}
+
+	buf.Reset()
+	if err := runConfigAccountShow(cmd, nil); err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	if got := buf.String(); got != "cost-centre=4711\nrole=work\n" {
+		t.Errorf("show = %q", got)
+	}
+
+	if err := runConfigAccountUnset(cmd, []string{"work", "cost-centre"}); err != nil {
+		t.Fatalf("unset failed: %v", err)
+	}
+	formatFlag = "json"
+	buf.Reset()
+	if err := runConfigAccountShow(cmd, []string{"work"}); err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &metadata); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(metadata) != 1 || metadata["role"] != "work" {
+		t.Errorf("metadata after unset = %v", metadata)
+	}
+}
@@ -0,0 +1,99 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// configMigrateDryRun previews migrations without writing them.
+var configMigrateDryRun bool
+
+// configMigrateCmd upgrades the config file to the current format.
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current format",
+	Long: `Upgrade the config file to the format of this version of goog.
+
+The config file records its format in schema_version. goog reads older
+files by migrating them in memory, and rewrites them in the new format
+the next time it saves the config. This command rewrites the file now.
+Before the file is rewritten, the previous file is copied to
+config.yaml.v<old version>.bak next to it.
+
+Use --dry-run to list the migrations and the changes they would make
+without writing anything.`,
+	Example: `  # Preview the changes
+  goog config migrate --dry-run
+
+  # Upgrade the file
+  goog config migrate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigMigrate,
+}
+
+func init() {
+	configCmd.AddCommand(configMigrateCmd)
+
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "show the changes without writing them")
+}
+
+// configMigrateJSON is the JSON representation of a migration.
+type configMigrateJSON struct {
+	*config.MigrationPlan
+	DryRun bool   `json:"dry_run"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// runConfigMigrate handles the config migrate command.
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	var (
+		plan   *config.MigrationPlan
+		backup string
+		err    error
+	)
+	if configMigrateDryRun {
+		plan, err = config.PlanMigration()
+	} else {
+		plan, backup, err = config.Migrate()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate config: %w", err)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(configMigrateJSON{MigrationPlan: plan, DryRun: configMigrateDryRun, Backup: backup}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode migration: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if !plan.Pending() {
+		cmd.Printf("%s is up to date (schema version %d)\n", plan.Path, plan.To)
+		return nil
+	}
+
+	cmd.Printf("%s: schema version %d -> %d\n", plan.Path, plan.From, plan.To)
+	for _, step := range plan.Steps {
+		cmd.Printf("  v%d: %s\n", step.Version, step.Description)
+		if len(step.Changes) == 0 {
+			cmd.Println("      (no changes to this file)")
+		}
+		for _, change := range step.Changes {
+			cmd.Printf("      %s\n", change)
+		}
+	}
+
+	if configMigrateDryRun {
+		cmd.Println("Dry run: nothing was written")
+	} else {
+		cmd.Printf("Migrated; previous file saved to %s\n", backup)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+func TestRunConfigMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", path)
+	legacy := "default_account: work@example.com\naccounts:\n  work:\n    email: work@example.com\n"
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	origDryRun, origFormat := configMigrateDryRun, formatFlag
+	t.Cleanup(func() { configMigrateDryRun, formatFlag = origDryRun, origFormat })
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	configMigrateDryRun, formatFlag = true, "table"
+	if err := runConfigMigrate(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(buf.String(), `default_account: "work@example.com" -> "work"`) || !contains(buf.String(), "nothing was written") {
+		t.Errorf("unexpected dry run output:\n%s", buf.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != legacy {
+		t.Error("expected --dry-run to leave the file unchanged")
+	}
+
+	configMigrateDryRun, formatFlag = false, "json"
+	buf.Reset()
+	if err := runConfigMigrate(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out struct {
+		From   int    `json:"from"`
+		To     int    `json:"to"`
+		Backup string `json:"backup"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.From != 0 || out.To != config.SchemaVersion || out.Backup != config.BackupPath(path, 0) {
+		t.Errorf("unexpected result: %+v", out)
+	}
+
+	formatFlag = "table"
+	buf.Reset()
+	if err := runConfigMigrate(cmd, nil); err != nil || !contains(buf.String(), "is up to date") {
+		t.Errorf("expected an up-to-date file, got %q, %v", buf.String(), err)
+	}
+}
@@ -356,6 +356,10 @@ func TestRunConfigShow(t *testing.T) {
 				"calendar:",
 				"default_calendar:",
 				"week_start:",
+				"display:",
+				"date_format:",
+				"time_format:",
+				"size_units:",
 			},
 		},
 	}
@@ -395,6 +399,9 @@ func TestRunConfigShow_WithAccounts(t *testing.T) {
 	// Add test accounts
 	cfg.Accounts["work"] = accountConfigForTest("work@example.com", []string{"gmail.readonly"})
 	cfg.Accounts["personal"] = accountConfigForTest("personal@example.com", []string{"gmail.modify"})
+	work := cfg.Accounts["work"]
+	work.PeopleEndpoint = "http://localhost:8080/people/"
+	cfg.Accounts["work"] = work
 	if err := cfg.Save(); err != nil {
 		t.Fatalf("failed to save config: %v", err)
 	}
@@ -417,6 +424,7 @@ func TestRunConfigShow_WithAccounts(t *testing.T) {
 		"email: personal@example.com",
 		"work:",
 		"email: work@example.com",
+		"people_endpoint: http://localhost:8080/people/",
 		"scopes:",
 	}
 
@@ -609,6 +617,44 @@ func TestRunConfigSet(t *testing.T) {
 			value:       "monday",
 			expectError: false,
 		},
+		{
+			name:        "set display.date_format preset",
+			key:         "display.date_format",
+			value:       "eu",
+			expectError: false,
+		},
+		{
+			name:        "set display.date_format layout",
+			key:         "display.date_format",
+			value:       "Jan 2, 2006",
+			expectError: false,
+		},
+		{
+			name:        "set display.date_format to invalid value",
+			key:         "display.date_format",
+			value:       "british",
+			expectError: true,
+			errorMsg:    "invalid date format",
+		},
+		{
+			name:        "set display.time_format",
+			key:         "display.time_format",
+			value:       "12h",
+			expectError: false,
+		},
+		{
+			name:        "set display.time_format to invalid value",
+			key:         "display.time_format",
+			value:       "military",
+			expectError: true,
+			errorMsg:    "invalid time format",
+		},
+		{
+			name:        "set display.size_units",
+			key:         "display.size_units",
+			value:       "si",
+			expectError: false,
+		},
 		{
 			name:        "set unknown key",
 			key:         "unknown.key",
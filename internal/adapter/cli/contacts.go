@@ -51,7 +51,8 @@ var contactsListCmd = &cobra.Command{
 	Long: `List all contacts in your Google Contacts account.
 
 Use --max-results to limit the number of results.
-Use --page-token to retrieve the next page of results.`,
+Use --page-token to retrieve the next page of results; with --format
+json --envelope the output holds the token as next_page_token.`,
 	Example: `  # List all contacts
   goog contacts list
 
@@ -297,8 +298,8 @@ func runContactsList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list contacts: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
-	cmd.Println(p.RenderContacts(result.Items))
+	p := newPresenter()
+	printList(cmd, p.RenderContacts(result.Items), contactPage(result))
 
 	return nil
 }
@@ -317,7 +318,7 @@ func runContactsGet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get contact: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContact(contact))
 
 	return nil
@@ -366,7 +367,7 @@ func runContactsCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create contact: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContact(created))
 
 	return nil
@@ -422,7 +423,7 @@ func runContactsUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update contact: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContact(updated))
 
 	return nil
@@ -442,7 +443,7 @@ func runContactsDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete contact: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Contact '%s' deleted", resourceName)))
 
 	return nil
@@ -468,8 +469,8 @@ func runContactsSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to search contacts: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
-	cmd.Println(p.RenderContacts(result.Items))
+	p := newPresenter()
+	printList(cmd, p.RenderContacts(result.Items), contactPage(result))
 
 	return nil
 }
@@ -486,7 +487,7 @@ func runContactsGroups(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list contact groups: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContactGroups(groups))
 
 	return nil
@@ -511,7 +512,7 @@ func runContactsGroupCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create contact group: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContactGroup(created))
 
 	return nil
@@ -540,7 +541,7 @@ func runContactsGroupUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update contact group: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderContactGroup(updated))
 
 	return nil
@@ -560,7 +561,7 @@ func runContactsGroupDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete contact group: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Contact group '%s' deleted", resourceName)))
 
 	return nil
@@ -585,8 +586,8 @@ func runContactsGroupMembers(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list group members: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
-	cmd.Println(p.RenderContacts(result.Items))
+	p := newPresenter()
+	printList(cmd, p.RenderContacts(result.Items), contactPage(result))
 
 	return nil
 }
@@ -606,7 +607,7 @@ func runContactsGroupAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to add members to group: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Added %d contact(s) to group", len(contactResourceNames))))
 
 	return nil
@@ -627,7 +628,7 @@ func runContactsGroupRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to remove members from group: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Removed %d contact(s) from group", len(contactResourceNames))))
 
 	return nil
@@ -699,3 +700,12 @@ func init() {
 	contactsGroupMembersCmd.Flags().Int64Var(&contactsMaxResults, "max-results", 100, "maximum number of results")
 	contactsGroupMembersCmd.Flags().StringVar(&contactsPageToken, "page-token", "", "token for pagination")
 }
+
+// contactPage returns the page metadata of a contact listing.
+func contactPage(result *domaincontacts.ListResult[*domaincontacts.Contact]) presenter.Page {
+	return presenter.Page{
+		NextPageToken: result.NextPageToken,
+		TotalEstimate: presenter.Estimate(result.TotalSize),
+		FetchedCount:  len(result.Items),
+	}
+}
@@ -0,0 +1,316 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Command flags for context commands.
+var (
+	contextLabel    string
+	contextCalendar string
+	contextView     string
+)
+
+// mailListContext is the search the directory's context scopes mail list
+// to, set before the command runs. It is empty when no context applies.
+var mailListContext string
+
+// contextCalendarCommands are the commands whose --calendar flag the
+// directory's context defaults.
+var contextCalendarCommands = map[string]bool{
+	"cal.list":  true,
+	"cal.today": true,
+	"cal.week":  true,
+}
+
+// contextCmd groups the project context commands.
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Scope commands run in a directory to a project",
+	Long: `Scope the commands run in a directory, such as a project's
+repository, to the project's mail label, calendar and saved view.
+
+The context is kept under context: in .goog.yaml, the directory-local
+settings file (see 'goog config settings'). The nearest .goog.yaml in
+the working directory or its parents is used. Inside the directory:
+
+  mail list               lists the messages with the label and matching
+                          the view (mail.views.<name> or a built-in view)
+                          instead of the inbox
+  cal list, today, week   show the calendar instead of the primary one
+
+Flags given on the command line win: 'goog mail list --labels INBOX'
+lists the inbox and 'goog cal today --calendar primary' the primary
+calendar. A context also wins over per-command settings for the same
+flags.`,
+}
+
+// contextSetCmd sets the context of the working directory.
+var contextSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the project context of this directory",
+	Long: `Set the project context in the nearest .goog.yaml, or in a new
+.goog.yaml in the working directory when there is none.
+
+Only the parts given are changed; an empty value removes a part. A
+calendar can be given by ID or name and is stored by ID. A view must be
+a saved mail view (mail.views.<name>) or a built-in one.`,
+	Example: `  # Scope this repository to its project
+  goog context set --label Project/Foo --calendar foo-team --view foo
+
+  # Stop scoping the calendar
+  goog context set --calendar ""`,
+	Args: cobra.NoArgs,
+	RunE: runContextSet,
+}
+
+// contextShowCmd shows the context in effect.
+var contextShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the project context in effect here",
+	Args:  cobra.NoArgs,
+	RunE:  runContextShow,
+}
+
+// contextClearCmd removes the context in effect.
+var contextClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the project context in effect here",
+	Long: `Remove the context from the nearest .goog.yaml. Its settings are
+kept; a file left empty is deleted.`,
+	Args: cobra.NoArgs,
+	RunE: runContextClear,
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextSetCmd)
+	contextCmd.AddCommand(contextShowCmd)
+	contextCmd.AddCommand(contextClearCmd)
+
+	contextSetCmd.Flags().StringVar(&contextLabel, "label", "", "mail label of the project")
+	contextSetCmd.Flags().StringVar(&contextCalendar, "calendar", "", "calendar ID or name of the project")
+	contextSetCmd.Flags().StringVar(&contextView, "view", "", "saved mail view of the project")
+}
+
+// contextFile returns the .goog.yaml in effect for the working directory,
+// and whether it exists. When none exists, the path of a new one in the
+// working directory is returned.
+func contextFile() (string, bool, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get the working directory: %w", err)
+	}
+	if path := config.FindLocalFile(dir); path != "" {
+		return path, true, nil
+	}
+	return filepath.Join(dir, config.LocalFileName), false, nil
+}
+
+// runContextSet handles the context set command.
+func runContextSet(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("label") && !flags.Changed("calendar") && !flags.Changed("view") {
+		return fmt.Errorf("give at least one of --label, --calendar or --view")
+	}
+
+	path, exists, err := contextFile()
+	if err != nil {
+		return err
+	}
+	current := &config.Context{}
+	if exists {
+		loaded, err := config.LoadLocalContext(path)
+		if err != nil {
+			return err
+		}
+		if loaded != nil {
+			current = loaded
+		}
+	}
+
+	if flags.Changed("label") {
+		current.Label = strings.TrimSpace(contextLabel)
+	}
+	if flags.Changed("view") {
+		view := strings.ToLower(strings.TrimSpace(contextView))
+		if view != "" {
+			if _, err := resolveView(view); err != nil {
+				return err
+			}
+		}
+		current.View = view
+	}
+	if flags.Changed("calendar") {
+		current.Calendar = ""
+		if ref := strings.TrimSpace(contextCalendar); ref != "" {
+			calendarID, err := resolveCalendarRef(context.Background(), ref)
+			if err != nil {
+				return err
+			}
+			current.Calendar = calendarID
+		}
+	}
+
+	if err := config.SaveLocalContext(path, current); err != nil {
+		return fmt.Errorf("failed to save context: %w", err)
+	}
+	if !quietFlag {
+		if current.IsZero() {
+			cmd.Printf("Removed the context from %s\n", path)
+		} else {
+			cmd.Printf("Context saved in %s\n", path)
+		}
+	}
+	return nil
+}
+
+// contextJSON is the JSON representation of the context in effect.
+type contextJSON struct {
+	*config.Context
+	File string `json:"file,omitempty"`
+}
+
+// runContextShow handles the context show command.
+func runContextShow(cmd *cobra.Command, args []string) error {
+	path, exists, err := contextFile()
+	if err != nil {
+		return err
+	}
+	var current *config.Context
+	if exists {
+		if current, err = config.LoadLocalContext(path); err != nil {
+			return err
+		}
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		out := contextJSON{Context: &config.Context{}}
+		if current != nil {
+			out = contextJSON{Context: current, File: path}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode context: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if current == nil {
+		if !quietFlag {
+			cmd.Println("No context in effect here")
+		}
+		return nil
+	}
+	cmd.Printf("File:     %s\n", path)
+	if current.Label != "" {
+		cmd.Printf("Label:    %s\n", current.Label)
+	}
+	if current.Calendar != "" {
+		cmd.Printf("Calendar: %s\n", current.Calendar)
+	}
+	if current.View != "" {
+		cmd.Printf("View:     %s\n", current.View)
+	}
+	return nil
+}
+
+// runContextClear handles the context clear command.
+func runContextClear(cmd *cobra.Command, args []string) error {
+	path, exists, err := contextFile()
+	if err != nil {
+		return err
+	}
+	if exists {
+		current, err := config.LoadLocalContext(path)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			if err := config.SaveLocalContext(path, nil); err != nil {
+				return fmt.Errorf("failed to save context: %w", err)
+			}
+			if !quietFlag {
+				cmd.Printf("Removed the context from %s\n", path)
+			}
+			return nil
+		}
+	}
+	if !quietFlag {
+		cmd.Println("No context in effect here")
+	}
+	return nil
+}
+
+// applyContext scopes the command to the context of the working
+// directory, for the flags not given on the command line. A context that
+// cannot be read is reported and ignored.
+func applyContext(cmd *cobra.Command) {
+	mailListContext = ""
+	path := settingPrefix(cmd)
+	if path != "mail.list" && !contextCalendarCommands[path] {
+		return
+	}
+	file, exists, err := contextFile()
+	if err != nil || !exists {
+		return
+	}
+	current, err := config.LoadLocalContext(file)
+	if err != nil {
+		output.Warnf(cmd, "ignoring context: %v", err)
+		return
+	}
+	if current == nil {
+		return
+	}
+
+	if path == "mail.list" {
+		if cmd.Flags().Changed("labels") {
+			return
+		}
+		query, err := contextMailQuery(current)
+		if err != nil {
+			output.Warnf(cmd, "ignoring context: %v", err)
+			return
+		}
+		mailListContext = query
+		output.Verbosef(cmd, "Scoped to %q by the context in %s", query, file)
+		return
+	}
+	if current.Calendar != "" && !cmd.Flags().Changed("calendar") {
+		if err := cmd.Flags().Set("calendar", current.Calendar); err != nil {
+			output.Warnf(cmd, "ignoring context: %v", err)
+			return
+		}
+		output.Verbosef(cmd, "Using calendar %s from the context in %s", current.Calendar, file)
+	}
+}
+
+// contextMailQuery returns the Gmail search for the label and view of a
+// context, or "" when it has neither.
+func contextMailQuery(c *config.Context) (string, error) {
+	var parts []string
+	if c.Label != "" {
+		parts = append(parts, "label:"+mail.SearchLabelTerm(c.Label))
+	}
+	if c.View != "" {
+		query, err := resolveView(c.View)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+query+")")
+	}
+	return strings.Join(parts, " "), nil
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestContextSetShowClear(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	dir := t.TempDir()
+	t.Chdir(dir)
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{CalendarRepo: &MockCalendarRepository{
+			Calendars: []*calendar.Calendar{{ID: "foo@group.calendar.google.com", Title: "Foo Team"}},
+		}},
+	})
+	t.Cleanup(ResetDependencies)
+	origFormat, origQuiet := formatFlag, quietFlag
+	t.Cleanup(func() { formatFlag, quietFlag = origFormat, origQuiet })
+	formatFlag, quietFlag = "table", false
+
+	newSetCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "set"}
+		cmd.Flags().StringVar(&contextLabel, "label", "", "")
+		cmd.Flags().StringVar(&contextCalendar, "calendar", "", "")
+		cmd.Flags().StringVar(&contextView, "view", "", "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		cmd.SetOut(new(bytes.Buffer))
+		return cmd
+	}
+
+	if err := runContextSet(newSetCmd(), nil); err == nil {
+		t.Error("expected an error without flags")
+	}
+	if err := runContextSet(newSetCmd("--view", "nope"), nil); err == nil {
+		t.Error("expected an error for an unknown view")
+	}
+	if err := runContextSet(newSetCmd("--label", "Project/Foo", "--calendar", "foo-team", "--view", "updates"), nil); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if err := runContextSet(newSetCmd("--view", ""), nil); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	formatFlag = "json"
+	cmd := &cobra.Command{Use: "show"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	if err := runContextShow(cmd, nil); err != nil {
+		t.Fatalf("show failed: %v", err)
+	}
+	var got struct {
+		Label    string `json:"label"`
+		Calendar string `json:"calendar"`
+		View     string `json:"view"`
+		File     string `json:"file"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Label != "Project/Foo" || got.Calendar != "foo@group.calendar.google.com" || got.View != "" || filepath.Base(got.File) != config.LocalFileName {
+		t.Errorf("show = %+v", got)
+	}
+
+	formatFlag = "table"
+	if err := runContextClear(cmd, nil); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, config.LocalFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected .goog.yaml to be removed, got %v", err)
+	}
+}
+
+func TestApplyContext(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	dir := t.TempDir()
+	ctx := &config.Context{Label: "Project/Foo", Calendar: "foo@group.calendar.google.com", View: "updates"}
+	if err := config.SaveLocalContext(filepath.Join(dir, config.LocalFileName), ctx); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "src")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	t.Cleanup(func() { mailListContext = "" })
+
+	root := &cobra.Command{Use: "goog"}
+	mailGroup := &cobra.Command{Use: "mail"}
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	list.Flags().StringSlice("labels", []string{"INBOX"}, "")
+	calGroup := &cobra.Command{Use: "cal"}
+	today := &cobra.Command{Use: "today", Run: func(*cobra.Command, []string) {}}
+	var calendarID string
+	today.Flags().StringVar(&calendarID, "calendar", "primary", "")
+	root.AddCommand(mailGroup, calGroup)
+	mailGroup.AddCommand(list)
+	calGroup.AddCommand(today)
+
+	applyContext(list)
+	if mailListContext != "label:project-foo ("+builtinViews["updates"]+")" {
+		t.Errorf("mail list context = %q", mailListContext)
+	}
+
+	if err := list.ParseFlags([]string{"--labels", "INBOX"}); err != nil {
+		t.Fatal(err)
+	}
+	applyContext(list)
+	if mailListContext != "" {
+		t.Errorf("expected --labels to win, got %q", mailListContext)
+	}
+
+	applyContext(today)
+	if calendarID != "foo@group.calendar.google.com" {
+		t.Errorf("calendar = %q", calendarID)
+	}
+}
@@ -4,6 +4,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
@@ -24,6 +25,9 @@ type MessageRepository interface {
 	List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error)
 	Get(ctx context.Context, id string) (*mail.Message, error)
 	Send(ctx context.Context, msg *mail.Message) (*mail.Message, error)
+	GetRaw(ctx context.Context, id string) ([]byte, error)
+	SendRaw(ctx context.Context, raw []byte) (*mail.Message, error)
+	Import(ctx context.Context, raw []byte, labelIDs []string) (*mail.Message, error)
 	Reply(ctx context.Context, messageID string, reply *mail.Message) (*mail.Message, error)
 	Forward(ctx context.Context, messageID string, forward *mail.Message) (*mail.Message, error)
 	Trash(ctx context.Context, id string) error
@@ -31,7 +35,16 @@ type MessageRepository interface {
 	Delete(ctx context.Context, id string) error
 	Archive(ctx context.Context, id string) error
 	Modify(ctx context.Context, id string, req mail.ModifyRequest) (*mail.Message, error)
+	BatchModify(ctx context.Context, ids []string, req mail.ModifyRequest) error
 	Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error)
+	SearchEach(ctx context.Context, query string, opts mail.ListOptions, fn func(*mail.Message) error) (int, []string, error)
+}
+
+// MailboxHistory reports what changed in a mailbox. Message repositories
+// that implement it let mail search reuse cached results.
+type MailboxHistory interface {
+	HistoryID(ctx context.Context) (uint64, error)
+	Changes(ctx context.Context, since uint64) (*mail.MailboxChanges, error)
 }
 
 // DraftRepository defines operations for managing email drafts.
@@ -67,6 +80,12 @@ type LabelRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// AttachmentRepository defines operations for retrieving message attachments.
+// This interface mirrors mail.AttachmentRepository for dependency injection.
+type AttachmentRepository interface {
+	Get(ctx context.Context, messageID, attachmentID string) ([]byte, error)
+}
+
 // EventRepository defines operations for managing calendar events.
 // This interface mirrors calendar.EventRepository for dependency injection.
 type EventRepository interface {
@@ -81,6 +100,12 @@ type EventRepository interface {
 	RSVP(ctx context.Context, calendarID, eventID, response string) error
 }
 
+// EventHistory reports what changed in a calendar. Event repositories that
+// implement it can be watched with cal watch.
+type EventHistory interface {
+	Sync(ctx context.Context, calendarID, syncToken string) (*calendar.EventChanges, error)
+}
+
 // CalendarRepository defines operations for managing calendars.
 // This interface mirrors calendar.CalendarRepository for dependency injection.
 type CalendarRepository interface {
@@ -108,6 +133,19 @@ type FreeBusyRepository interface {
 	Query(ctx context.Context, request *calendar.FreeBusyRequest) (*calendar.FreeBusyResponse, error)
 }
 
+// DriveFileRepository defines operations for downloading and uploading
+// Google Drive files.
+type DriveFileRepository interface {
+	Download(ctx context.Context, fileID string) (name string, data []byte, err error)
+	Upload(ctx context.Context, name, mimeType string, content io.Reader, readers []string) (link string, err error)
+}
+
+// Translator translates message text.
+// This interface mirrors mail.Translator for dependency injection.
+type Translator interface {
+	Translate(ctx context.Context, texts []string, target string) ([]mail.TranslatedText, error)
+}
+
 // TaskListRepository defines operations for managing task lists.
 // This interface mirrors domaintasks.TaskListRepository for dependency injection.
 type TaskListRepository interface {
@@ -160,6 +198,7 @@ type AccountService interface {
 	List() ([]*accountuc.Account, error)
 	Add(ctx context.Context, alias string, scopes []string) (*accountuc.Account, error)
 	Remove(alias string) error
+	RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error)
 	Switch(alias string) error
 	Rename(oldAlias, newAlias string) error
 	ResolveAccount(flagValue string) (*accountuc.Account, error)
@@ -181,6 +220,7 @@ type RepositoryFactory interface {
 	NewDraftRepository(ctx context.Context, tokenSource oauth2.TokenSource) (DraftRepository, error)
 	NewThreadRepository(ctx context.Context, tokenSource oauth2.TokenSource) (ThreadRepository, error)
 	NewLabelRepository(ctx context.Context, tokenSource oauth2.TokenSource) (LabelRepository, error)
+	NewAttachmentRepository(ctx context.Context, tokenSource oauth2.TokenSource) (AttachmentRepository, error)
 
 	// Calendar repositories
 	NewEventRepository(ctx context.Context, tokenSource oauth2.TokenSource) (EventRepository, error)
@@ -188,6 +228,12 @@ type RepositoryFactory interface {
 	NewACLRepository(ctx context.Context, tokenSource oauth2.TokenSource) (ACLRepository, error)
 	NewFreeBusyRepository(ctx context.Context, tokenSource oauth2.TokenSource) (FreeBusyRepository, error)
 
+	// Drive repositories
+	NewDriveFileRepository(ctx context.Context, tokenSource oauth2.TokenSource) (DriveFileRepository, error)
+
+	// Translation backend, authenticated with an API key
+	NewTranslator(ctx context.Context, apiKey string) (Translator, error)
+
 	// Tasks repositories
 	NewTaskListRepository(ctx context.Context, tokenSource oauth2.TokenSource) (TaskListRepository, error)
 	NewTaskRepository(ctx context.Context, tokenSource oauth2.TokenSource) (TaskRepository, error)
@@ -288,7 +334,14 @@ func (s *defaultAccountService) Add(ctx context.Context, alias string, scopes []
 	}
 	flow := accountuc.NewDefaultOAuthFlow()
 	svcWithFlow := accountuc.NewService(cfg, store, flow)
-	return svcWithFlow.Add(ctx, alias, scopes)
+	acc, err := svcWithFlow.Add(ctx, alias, scopes)
+	if err != nil {
+		return nil, err
+	}
+	// The cached service holds the config from before the add; reload it
+	// on next use so later calls see the new account.
+	s.svc = nil
+	return acc, nil
 }
 
 // Remove removes an account.
@@ -299,6 +352,15 @@ func (s *defaultAccountService) Remove(alias string) error {
 	return s.svc.Remove(alias)
 }
 
+// RemoveAccount removes an account, optionally revoking its token, and
+// reports what was cleaned up.
+func (s *defaultAccountService) RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error) {
+	if err := s.ensureService(); err != nil {
+		return nil, err
+	}
+	return s.svc.RemoveAccount(ctx, alias, revoke)
+}
+
 // Switch switches the default account.
 func (s *defaultAccountService) Switch(alias string) error {
 	if err := s.ensureService(); err != nil {
@@ -336,9 +398,14 @@ type defaultTokenManager struct {
 	tm *auth.TokenManager
 }
 
-// GetTokenSource returns an OAuth2 token source for the given account alias.
+// GetTokenSource returns an OAuth2 token source for the given account alias
+// and records that the account was used.
 func (m *defaultTokenManager) GetTokenSource(ctx context.Context, alias string) (oauth2.TokenSource, error) {
-	return m.tm.GetTokenSource(ctx, alias)
+	ts, err := m.tm.GetTokenSource(ctx, alias)
+	if err == nil {
+		markAccountUsed(alias)
+	}
+	return ts, err
 }
 
 // GetTokenInfo returns token information for the given account alias.
@@ -391,6 +458,15 @@ func (f *defaultRepositoryFactory) NewLabelRepository(ctx context.Context, token
 	return repository.NewGmailLabelRepository(gmailRepo), nil
 }
 
+// NewAttachmentRepository creates a new attachment repository.
+func (f *defaultRepositoryFactory) NewAttachmentRepository(ctx context.Context, tokenSource oauth2.TokenSource) (AttachmentRepository, error) {
+	gmailRepo, err := repository.NewGmailRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewGmailAttachmentRepository(gmailRepo), nil
+}
+
 // NewEventRepository creates a new event repository.
 func (f *defaultRepositoryFactory) NewEventRepository(ctx context.Context, tokenSource oauth2.TokenSource) (EventRepository, error) {
 	gcalSvc, err := repository.NewGCalService(ctx, tokenSource)
@@ -427,6 +503,16 @@ func (f *defaultRepositoryFactory) NewFreeBusyRepository(ctx context.Context, to
 	return gcalSvc.FreeBusy(), nil
 }
 
+// NewDriveFileRepository creates a new Drive file repository.
+func (f *defaultRepositoryFactory) NewDriveFileRepository(ctx context.Context, tokenSource oauth2.TokenSource) (DriveFileRepository, error) {
+	return repository.NewGDriveFileRepository(ctx, tokenSource)
+}
+
+// NewTranslator creates a Cloud Translation backend.
+func (f *defaultRepositoryFactory) NewTranslator(ctx context.Context, apiKey string) (Translator, error) {
+	return repository.NewGTranslateRepository(ctx, apiKey)
+}
+
 // NewTaskListRepository creates a new task list repository.
 func (f *defaultRepositoryFactory) NewTaskListRepository(ctx context.Context, tokenSource oauth2.TokenSource) (TaskListRepository, error) {
 	gtasksRepo, err := repository.NewGTasksRepository(ctx, tokenSource)
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -166,6 +168,15 @@ func (m *MockAccountService) Remove(alias string) error {
 	return m.RemoveErr
 }
 
+// RemoveAccount removes an account through Remove and reports a cleanup of
+// the token and scopes.
+func (m *MockAccountService) RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error) {
+	if err := m.Remove(alias); err != nil {
+		return nil, err
+	}
+	return &accountuc.RemovalReport{Alias: alias, Revoked: revoke, DeletedKeys: []string{"oauth_scopes", "oauth_token"}}, nil
+}
+
 // Switch switches the default account.
 func (m *MockAccountService) Switch(alias string) error {
 	if m.SwitchFunc != nil {
@@ -219,9 +230,36 @@ type MockMessageRepository struct {
 	ReplyResult   *mail.Message
 	ForwardResult *mail.Message
 	SearchResult  *mail.ListResult[*mail.Message]
+	// SearchEachFetched counts the messages SearchEach passed on.
+	SearchEachFetched int
+	// ListOpts and SearchOpts are the options of the last List and Search.
+	ListOpts   mail.ListOptions
+	SearchOpts mail.ListOptions
+	Raw        []byte
+	GetRawErr  error
+	SendRawErr error
+	SentRaw    []byte
+	ImportErr  error
+	Imported   []ImportCall
+
+	BatchModifyErr error
+	BatchModified  []BatchModifyCall
+}
+
+// ImportCall records one Import call on MockMessageRepository.
+type ImportCall struct {
+	Raw      []byte
+	LabelIDs []string
+}
+
+// BatchModifyCall records one BatchModify call on MockMessageRepository.
+type BatchModifyCall struct {
+	IDs []string
+	Req mail.ModifyRequest
 }
 
 func (m *MockMessageRepository) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.ListOpts = opts
 	if m.ListErr != nil {
 		return nil, m.ListErr
 	}
@@ -248,6 +286,24 @@ func (m *MockMessageRepository) Send(ctx context.Context, msg *mail.Message) (*m
 	return msg, nil
 }
 
+func (m *MockMessageRepository) GetRaw(ctx context.Context, id string) ([]byte, error) {
+	if m.GetRawErr != nil {
+		return nil, m.GetRawErr
+	}
+	return m.Raw, nil
+}
+
+func (m *MockMessageRepository) SendRaw(ctx context.Context, raw []byte) (*mail.Message, error) {
+	m.SentRaw = raw
+	if m.SendRawErr != nil {
+		return nil, m.SendRawErr
+	}
+	if m.SendResult != nil {
+		return m.SendResult, nil
+	}
+	return &mail.Message{ID: "sent-raw"}, nil
+}
+
 func (m *MockMessageRepository) Reply(ctx context.Context, messageID string, reply *mail.Message) (*mail.Message, error) {
 	if m.ReplyErr != nil {
 		return nil, m.ReplyErr
@@ -258,6 +314,14 @@ func (m *MockMessageRepository) Reply(ctx context.Context, messageID string, rep
 	return reply, nil
 }
 
+func (m *MockMessageRepository) Import(ctx context.Context, raw []byte, labelIDs []string) (*mail.Message, error) {
+	if m.ImportErr != nil {
+		return nil, m.ImportErr
+	}
+	m.Imported = append(m.Imported, ImportCall{Raw: raw, LabelIDs: labelIDs})
+	return &mail.Message{ID: "imported", Labels: labelIDs}, nil
+}
+
 func (m *MockMessageRepository) Forward(ctx context.Context, messageID string, forward *mail.Message) (*mail.Message, error) {
 	if m.ForwardErr != nil {
 		return nil, m.ForwardErr
@@ -294,7 +358,16 @@ func (m *MockMessageRepository) Modify(ctx context.Context, id string, req mail.
 	return m.Message, nil
 }
 
+func (m *MockMessageRepository) BatchModify(ctx context.Context, ids []string, req mail.ModifyRequest) error {
+	if m.BatchModifyErr != nil {
+		return m.BatchModifyErr
+	}
+	m.BatchModified = append(m.BatchModified, BatchModifyCall{IDs: ids, Req: req})
+	return nil
+}
+
 func (m *MockMessageRepository) Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.SearchOpts = opts
 	if m.SearchErr != nil {
 		return nil, m.SearchErr
 	}
@@ -304,6 +377,38 @@ func (m *MockMessageRepository) Search(ctx context.Context, query string, opts m
 	return &mail.ListResult[*mail.Message]{Items: m.Messages}, nil
 }
 
+func (m *MockMessageRepository) SearchEach(ctx context.Context, query string, opts mail.ListOptions, fn func(*mail.Message) error) (int, []string, error) {
+	result, err := m.Search(ctx, query, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, msg := range result.Items {
+		m.SearchEachFetched++
+		if err := fn(msg); err != nil {
+			if errors.Is(err, mail.ErrStopSearch) {
+				break
+			}
+			return result.Total, result.Warnings, err
+		}
+	}
+	return result.Total, result.Warnings, nil
+}
+
+// MockAttachmentRepository implements AttachmentRepository for testing.
+type MockAttachmentRepository struct {
+	Data   map[string][]byte
+	GetErr error
+	Calls  []string
+}
+
+func (m *MockAttachmentRepository) Get(ctx context.Context, messageID, attachmentID string) ([]byte, error) {
+	m.Calls = append(m.Calls, messageID+"/"+attachmentID)
+	if m.GetErr != nil {
+		return nil, m.GetErr
+	}
+	return m.Data[attachmentID], nil
+}
+
 // MockDraftRepository implements DraftRepository for testing.
 type MockDraftRepository struct {
 	Drafts       []*mail.Draft
@@ -502,9 +607,18 @@ type MockEventRepository struct {
 	UpdateResult   *calendar.Event
 	MoveResult     *calendar.Event
 	QuickAddResult *calendar.Event
+
+	// Captured arguments of the last List call
+	ListTimeMin time.Time
+	ListTimeMax time.Time
+
+	// Captured arguments of the last RSVP call
+	RSVPEventID  string
+	RSVPResponse string
 }
 
 func (m *MockEventRepository) List(ctx context.Context, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	m.ListTimeMin, m.ListTimeMax = timeMin, timeMax
 	if m.ListErr != nil {
 		return nil, m.ListErr
 	}
@@ -571,6 +685,7 @@ func (m *MockEventRepository) Instances(ctx context.Context, calendarID, eventID
 }
 
 func (m *MockEventRepository) RSVP(ctx context.Context, calendarID, eventID, response string) error {
+	m.RSVPEventID, m.RSVPResponse = eventID, response
 	return m.RSVPErr
 }
 
@@ -642,6 +757,12 @@ type MockACLRepository struct {
 	DeleteErr    error
 	InsertResult *calendar.ACLRule
 	UpdateResult *calendar.ACLRule
+
+	// Captured arguments
+	InsertedCalendarID string
+	InsertedRule       *calendar.ACLRule
+	DeletedCalendarID  string
+	DeletedRuleID      string
 }
 
 func (m *MockACLRepository) List(ctx context.Context, calendarID string) ([]*calendar.ACLRule, error) {
@@ -659,6 +780,8 @@ func (m *MockACLRepository) Get(ctx context.Context, calendarID, ruleID string)
 }
 
 func (m *MockACLRepository) Insert(ctx context.Context, calendarID string, rule *calendar.ACLRule) (*calendar.ACLRule, error) {
+	m.InsertedCalendarID = calendarID
+	m.InsertedRule = rule
 	if m.InsertErr != nil {
 		return nil, m.InsertErr
 	}
@@ -680,6 +803,8 @@ func (m *MockACLRepository) Update(ctx context.Context, calendarID string, rule
 }
 
 func (m *MockACLRepository) Delete(ctx context.Context, calendarID, ruleID string) error {
+	m.DeletedCalendarID = calendarID
+	m.DeletedRuleID = ruleID
 	return m.DeleteErr
 }
 
@@ -699,6 +824,63 @@ func (m *MockFreeBusyRepository) Query(ctx context.Context, request *calendar.Fr
 	return &calendar.FreeBusyResponse{Calendars: make(map[string][]*calendar.TimePeriod)}, nil
 }
 
+// MockTranslator implements Translator for testing. It prefixes each text
+// with the target language.
+type MockTranslator struct {
+	SourceLanguage string
+	TranslateErr   error
+	Calls          int
+}
+
+func (m *MockTranslator) Translate(ctx context.Context, texts []string, target string) ([]mail.TranslatedText, error) {
+	m.Calls++
+	if m.TranslateErr != nil {
+		return nil, m.TranslateErr
+	}
+	result := make([]mail.TranslatedText, len(texts))
+	for i, text := range texts {
+		result[i] = mail.TranslatedText{Text: "[" + target + "] " + text, SourceLanguage: m.SourceLanguage}
+	}
+	return result, nil
+}
+
+// MockDriveFileRepository implements DriveFileRepository for testing.
+type MockDriveFileRepository struct {
+	Files       map[string][]byte
+	DownloadErr error
+	UploadErr   error
+	// Uploaded holds the uploaded files by name, and Readers who each was
+	// shared with.
+	Uploaded map[string][]byte
+	Readers  map[string][]string
+}
+
+func (m *MockDriveFileRepository) Upload(ctx context.Context, name, mimeType string, content io.Reader, readers []string) (string, error) {
+	if m.UploadErr != nil {
+		return "", m.UploadErr
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	if m.Uploaded == nil {
+		m.Uploaded, m.Readers = map[string][]byte{}, map[string][]string{}
+	}
+	m.Uploaded[name], m.Readers[name] = data, readers
+	return "https://drive.google.com/file/d/" + name + "/view", nil
+}
+
+func (m *MockDriveFileRepository) Download(ctx context.Context, fileID string) (string, []byte, error) {
+	if m.DownloadErr != nil {
+		return "", nil, m.DownloadErr
+	}
+	data, ok := m.Files[fileID]
+	if !ok {
+		return "", nil, errors.New("file not found")
+	}
+	return fileID + ".bin", data, nil
+}
+
 // MockTaskListRepository implements TaskListRepository for testing.
 type MockTaskListRepository struct {
 	Lists     []*domaintasks.TaskList
@@ -962,10 +1144,13 @@ type MockRepositoryFactory struct {
 	DraftRepo        DraftRepository
 	ThreadRepo       ThreadRepository
 	LabelRepo        LabelRepository
+	AttachmentRepo   AttachmentRepository
 	EventRepo        EventRepository
 	CalendarRepo     CalendarRepository
 	ACLRepo          ACLRepository
 	FreeBusyRepo     FreeBusyRepository
+	DriveFileRepo    DriveFileRepository
+	Translator       Translator
 	TaskListRepo     TaskListRepository
 	TaskRepo         TaskRepository
 	ContactRepo      ContactRepository
@@ -974,10 +1159,14 @@ type MockRepositoryFactory struct {
 	DraftErr         error
 	ThreadErr        error
 	LabelErr         error
+	AttachmentErr    error
 	EventErr         error
 	CalendarErr      error
 	ACLErr           error
 	FreeBusyErr      error
+	DriveFileErr     error
+	TranslatorErr    error
+	TranslatorKey    string
 	TaskListErr      error
 	TaskErr          error
 	ContactErr       error
@@ -1024,6 +1213,16 @@ func (f *MockRepositoryFactory) NewLabelRepository(ctx context.Context, tokenSou
 	return f.LabelRepo, nil
 }
 
+func (f *MockRepositoryFactory) NewAttachmentRepository(ctx context.Context, tokenSource oauth2.TokenSource) (AttachmentRepository, error) {
+	if f.AttachmentErr != nil {
+		return nil, f.AttachmentErr
+	}
+	if f.AttachmentRepo == nil {
+		return &MockAttachmentRepository{}, nil
+	}
+	return f.AttachmentRepo, nil
+}
+
 func (f *MockRepositoryFactory) NewEventRepository(ctx context.Context, tokenSource oauth2.TokenSource) (EventRepository, error) {
 	if f.EventErr != nil {
 		return nil, f.EventErr
@@ -1064,6 +1263,27 @@ func (f *MockRepositoryFactory) NewFreeBusyRepository(ctx context.Context, token
 	return f.FreeBusyRepo, nil
 }
 
+func (f *MockRepositoryFactory) NewDriveFileRepository(ctx context.Context, tokenSource oauth2.TokenSource) (DriveFileRepository, error) {
+	if f.DriveFileErr != nil {
+		return nil, f.DriveFileErr
+	}
+	if f.DriveFileRepo == nil {
+		return &MockDriveFileRepository{}, nil
+	}
+	return f.DriveFileRepo, nil
+}
+
+func (f *MockRepositoryFactory) NewTranslator(ctx context.Context, apiKey string) (Translator, error) {
+	f.TranslatorKey = apiKey
+	if f.TranslatorErr != nil {
+		return nil, f.TranslatorErr
+	}
+	if f.Translator == nil {
+		return &MockTranslator{}, nil
+	}
+	return f.Translator, nil
+}
+
 func (f *MockRepositoryFactory) NewTaskListRepository(ctx context.Context, tokenSource oauth2.TokenSource) (TaskListRepository, error) {
 	if f.TaskListErr != nil {
 		return nil, f.TaskListErr
@@ -1283,4 +1503,24 @@ func TestMockRepositoryFactory(t *testing.T) {
 			t.Error("NewFreeBusyRepository() returned nil")
 		}
 	})
+
+	t.Run("NewDriveFileRepository", func(t *testing.T) {
+		repo, err := factory.NewDriveFileRepository(ctx, tokenSource)
+		if err != nil {
+			t.Errorf("NewDriveFileRepository() error = %v", err)
+		}
+		if repo == nil {
+			t.Error("NewDriveFileRepository() returned nil")
+		}
+	})
+
+	t.Run("NewTranslator", func(t *testing.T) {
+		translator, err := factory.NewTranslator(ctx, "key")
+		if err != nil {
+			t.Errorf("NewTranslator() error = %v", err)
+		}
+		if translator == nil {
+			t.Error("NewTranslator() returned nil")
+		}
+	})
 }
@@ -4,9 +4,9 @@ package cli
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
@@ -14,10 +14,13 @@ import (
 
 // Draft command flags.
 var (
-	draftTo      []string
-	draftSubject string
-	draftBody    string
-	draftLimit   int
+	draftTo        []string
+	draftSubject   string
+	draftBody      string
+	draftLimit     int
+	draftStrict    bool
+	draftPageToken string
+	draftSpamTrash bool
 )
 
 // draftCmd represents the draft command group.
@@ -45,8 +48,8 @@ last updated date.`,
   # List drafts with JSON output
   goog draft list --format json
 
-  # List drafts with limit
-  goog draft list --limit 10`,
+  # List at most 10 drafts
+  goog draft list --max-results 10`,
 	RunE: runDraftList,
 }
 
@@ -151,7 +154,11 @@ func init() {
 	draftCmd.AddCommand(draftDeleteCmd)
 
 	// List flags
-	draftListCmd.Flags().IntVar(&draftLimit, "limit", 20, "maximum number of drafts to list")
+	draftListCmd.Flags().IntVar(&draftLimit, "max-results", 20, "maximum number of drafts to list")
+	draftListCmd.Flags().SetNormalizeFunc(draftListFlagName)
+	draftListCmd.Flags().BoolVar(&draftSpamTrash, "include-spam-trash", false, "also list drafts in SPAM and TRASH")
+	draftListCmd.Flags().StringVar(&draftPageToken, "page-token", "", "token of the page to list, from next_page_token of --envelope output")
+	draftListCmd.Flags().BoolVar(&draftStrict, "strict", false, "exit with an error when some drafts could not be fetched in full")
 
 	// Create flags
 	draftCreateCmd.Flags().StringSliceVar(&draftTo, "to", nil, "recipient email addresses")
@@ -184,6 +191,16 @@ func getDraftRepository(ctx context.Context) (*repository.GmailDraftRepository,
 	return repository.NewGmailDraftRepository(gmailRepo), nil
 }
 
+// draftListFlagName reads the deprecated --limit of draft list as
+// --max-results, so the older name keeps working without a second flag
+// in the help.
+func draftListFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "limit" {
+		name = "max-results"
+	}
+	return pflag.NormalizedName(name)
+}
+
 // runDraftList handles the draft list command.
 func runDraftList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -194,7 +211,9 @@ func runDraftList(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := mail.ListOptions{
-		MaxResults: draftLimit,
+		MaxResults:       draftLimit,
+		PageToken:        draftPageToken,
+		IncludeSpamTrash: draftSpamTrash,
 	}
 
 	result, err := repo.List(ctx, opts)
@@ -203,16 +222,20 @@ func runDraftList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
-	output := p.RenderDrafts(result.Items)
-	cmd.Println(output)
+	printList(cmd, p.RenderDrafts(result.Items), presenter.Page{
+		NextPageToken: result.NextPageToken,
+		TotalEstimate: presenter.Estimate(result.Total),
+		FetchedCount:  len(result.Items),
+		Warnings:      result.Warnings,
+	})
 
-	if !quietFlag && result.NextPageToken != "" {
-		cmd.Println("\n(More drafts available. Use --limit to adjust.)")
+	if !quietFlag && result.NextPageToken != "" && formatFlag != presenter.FormatJSON {
+		cmd.Println("\n(More drafts available. Use --max-results to adjust.)")
 	}
 
-	return nil
+	return output.Incomplete(cmd, "draft(s)", result.Warnings, draftStrict)
 }
 
 // runDraftShow handles the draft show command.
@@ -231,7 +254,7 @@ func runDraftShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderDraft(draft)
 	cmd.Println(output)
@@ -256,7 +279,7 @@ func runDraftCreate(cmd *cobra.Command, args []string) error {
 
 	// Create draft message
 	msg := &mail.Message{
-		To:      draftTo,
+		To:      mail.ParseAddresses(draftTo),
 		Subject: draftSubject,
 		Body:    draftBody,
 	}
@@ -271,7 +294,7 @@ func runDraftCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderDraft(created))
@@ -279,7 +302,7 @@ func runDraftCreate(cmd *cobra.Command, args []string) error {
 		cmd.Printf("Draft created successfully.\n")
 		cmd.Printf("ID: %s\n", created.ID)
 		if created.Message != nil {
-			cmd.Printf("To: %s\n", strings.Join(created.Message.To, ", "))
+			cmd.Printf("To: %s\n", mail.JoinAddresses(created.Message.To))
 			cmd.Printf("Subject: %s\n", created.Message.Subject)
 		}
 	}
@@ -309,7 +332,7 @@ func runDraftUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(draftTo) > 0 {
-		existing.Message.To = draftTo
+		existing.Message.To = mail.ParseAddresses(draftTo)
 	}
 	if draftSubject != "" {
 		existing.Message.Subject = draftSubject
@@ -324,7 +347,7 @@ func runDraftUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderDraft(updated))
@@ -352,7 +375,7 @@ func runDraftSend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderMessage(sent))
@@ -360,7 +383,7 @@ func runDraftSend(cmd *cobra.Command, args []string) error {
 		cmd.Printf("Draft sent successfully.\n")
 		cmd.Printf("Message ID: %s\n", sent.ID)
 		if len(sent.To) > 0 {
-			cmd.Printf("To: %s\n", strings.Join(sent.To, ", "))
+			cmd.Printf("To: %s\n", mail.JoinAddresses(sent.To))
 		}
 		cmd.Printf("Subject: %s\n", sent.Subject)
 	}
@@ -210,7 +210,7 @@ func TestRunDraftSend_EmptyRecipients(t *testing.T) {
 		SendResult: &mail.Message{
 			ID:      "sent-msg-id",
 			Subject: "Sent Subject",
-			To:      []string{},
+			To:      []mail.Address{},
 		},
 	}
 
@@ -252,7 +252,7 @@ func TestRunDraftUpdate_EmptyFlags(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 			Body:    "Original body",
 		},
 	}
@@ -439,7 +439,7 @@ func TestRunDraftUpdate_PartialFieldUpdate(t *testing.T) {
 				ID: "draft123",
 				Message: &mail.Message{
 					Subject: "Original Subject",
-					To:      []string{"original@example.com"},
+					To:      []mail.Address{{Email: "original@example.com"}},
 					Body:    "Original body",
 				},
 			}
@@ -504,7 +504,7 @@ func TestRunDraftShow_JSONFormat(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Test Draft Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 			Body:    "This is the draft body.",
 		},
 	}
@@ -612,8 +612,8 @@ func TestDraftCreateCmd_ValidationEdgeCases(t *testing.T) {
 // TestRunDraftList_TableFormat tests draft list with table output format
 func TestRunDraftList_TableFormat(t *testing.T) {
 	mockDrafts := []*mail.Draft{
-		{ID: "draft1", Message: &mail.Message{Subject: "Table Test Draft 1", To: []string{"user@example.com"}}},
-		{ID: "draft2", Message: &mail.Message{Subject: "Table Test Draft 2", To: []string{"user2@example.com"}}},
+		{ID: "draft1", Message: &mail.Message{Subject: "Table Test Draft 1", To: []mail.Address{{Email: "user@example.com"}}}},
+		{ID: "draft2", Message: &mail.Message{Subject: "Table Test Draft 2", To: []mail.Address{{Email: "user2@example.com"}}}},
 	}
 
 	mockRepo := &MockDraftRepository{
@@ -663,7 +663,7 @@ func TestRunDraftCreate_WithCreatedDraftHavingColor(t *testing.T) {
 		CreateResult: &mail.Draft{
 			ID: "draft-with-color",
 			Message: &mail.Message{
-				To:      []string{"test@example.com"},
+				To:      []mail.Address{{Email: "test@example.com"}},
 				Subject: "Colored Draft",
 				Body:    "Test body",
 			},
@@ -722,7 +722,7 @@ func TestRunDraftUpdate_JSONOutputFormat(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 		},
 	}
 
@@ -732,7 +732,7 @@ func TestRunDraftUpdate_JSONOutputFormat(t *testing.T) {
 			ID: "draft123",
 			Message: &mail.Message{
 				Subject: "Updated Subject",
-				To:      []string{"original@example.com"},
+				To:      []mail.Address{{Email: "original@example.com"}},
 			},
 		},
 	}
@@ -61,7 +61,7 @@ func TestMockDraftRepository_Get(t *testing.T) {
 			ID: "draft1",
 			Message: &mail.Message{
 				Subject: "Test Draft",
-				To:      []string{"user@example.com"},
+				To:      []mail.Address{{Email: "user@example.com"}},
 				Body:    "Draft body content",
 			},
 		}
@@ -94,7 +94,7 @@ func TestMockDraftRepository_Create(t *testing.T) {
 		repo := &MockDraftRepository{}
 		draft := &mail.Draft{
 			Message: &mail.Message{
-				To:      []string{"user@example.com"},
+				To:      []mail.Address{{Email: "user@example.com"}},
 				Subject: "New Draft",
 			},
 		}
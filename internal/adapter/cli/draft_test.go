@@ -71,8 +71,11 @@ func TestDraftListCmd_Help(t *testing.T) {
 	if !contains(output, "list") {
 		t.Error("expected output to contain 'list'")
 	}
-	if !contains(output, "--limit") {
-		t.Error("expected output to contain '--limit'")
+	if !contains(output, "--max-results") {
+		t.Error("expected output to contain '--max-results'")
+	}
+	if contains(output, "--limit") {
+		t.Error("expected the deprecated --limit to be left out of the help")
 	}
 }
 
@@ -566,8 +569,8 @@ func TestDraftListCmd_DefaultLimit(t *testing.T) {
 
 func TestRunDraftList_WithMockDependencies(t *testing.T) {
 	mockDrafts := []*mail.Draft{
-		{ID: "draft1", Message: &mail.Message{Subject: "Test Draft 1", To: []string{"user@example.com"}}},
-		{ID: "draft2", Message: &mail.Message{Subject: "Test Draft 2", To: []string{"user2@example.com"}}},
+		{ID: "draft1", Message: &mail.Message{Subject: "Test Draft 1", To: []mail.Address{{Email: "user@example.com"}}}},
+		{ID: "draft2", Message: &mail.Message{Subject: "Test Draft 2", To: []mail.Address{{Email: "user2@example.com"}}}},
 	}
 
 	mockRepo := &MockDraftRepository{
@@ -647,7 +650,7 @@ func TestRunDraftShow_WithMockDependencies(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Test Draft Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 			Body:    "This is the draft body.",
 		},
 	}
@@ -813,7 +816,7 @@ func TestRunDraftUpdate_WithMockDependencies(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 		},
 	}
 
@@ -891,7 +894,7 @@ func TestRunDraftSend_WithMockDependencies(t *testing.T) {
 		SendResult: &mail.Message{
 			ID:      "sent-msg-id",
 			Subject: "Sent Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 		},
 	}
 
@@ -1178,7 +1181,7 @@ func TestRunDraftUpdate_UpdateError(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 		},
 	}
 
@@ -1222,7 +1225,7 @@ func TestRunDraftUpdate_JSONFormat(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 		},
 	}
 
@@ -1272,7 +1275,7 @@ func TestRunDraftUpdate_WithMultipleFields(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Original Subject",
-			To:      []string{"original@example.com"},
+			To:      []mail.Address{{Email: "original@example.com"}},
 			Body:    "Original body",
 		},
 	}
@@ -1477,7 +1480,7 @@ func TestRunDraftShow_WithBody(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Test Draft Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 			Body:    "This is the draft body content.",
 		},
 	}
@@ -1531,7 +1534,7 @@ func TestRunDraftShow_WithoutBody(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Test Draft Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 			Body:    "",
 		},
 	}
@@ -1582,7 +1585,7 @@ func TestRunDraftShow_QuietMode(t *testing.T) {
 		ID: "draft123",
 		Message: &mail.Message{
 			Subject: "Test Draft Subject",
-			To:      []string{"recipient@example.com"},
+			To:      []mail.Address{{Email: "recipient@example.com"}},
 			Body:    "This is the draft body.",
 		},
 	}
@@ -1682,7 +1685,7 @@ func TestRunDraftSend_WithRecipients(t *testing.T) {
 		SendResult: &mail.Message{
 			ID:      "sent-msg-id",
 			Subject: "Sent Subject",
-			To:      []string{"user1@example.com", "user2@example.com"},
+			To:      []mail.Address{{Email: "user1@example.com"}, {Email: "user2@example.com"}},
 		},
 	}
 
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupEnvelopeTest serves repo, selects JSON output with --envelope and
+// restores the flags afterwards.
+func setupEnvelopeTest(t *testing.T, repo *MockMessageRepository) {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+	t.Cleanup(ResetDependencies)
+
+	origFormat, origEnvelope, origToken := formatFlag, envelopeFlag, mailPageToken
+	origLimit, origAll, origThreaded := mailSearchLimit, mailSearchAll, mailThreaded
+	t.Cleanup(func() {
+		formatFlag, envelopeFlag, mailPageToken = origFormat, origEnvelope, origToken
+		mailSearchLimit, mailSearchAll, mailThreaded = origLimit, origAll, origThreaded
+	})
+	formatFlag, envelopeFlag, mailThreaded = "json", true, false
+	mailSearchLimit, mailSearchAll = 0, false
+}
+
+// envelopeResult is the envelope as scripts read it.
+type envelopeResult struct {
+	Items         []json.RawMessage `json:"items"`
+	NextPageToken string            `json:"next_page_token"`
+	TotalEstimate *int              `json:"total_estimate"`
+	FetchedCount  int               `json:"fetched_count"`
+	Warnings      []string          `json:"warnings"`
+}
+
+func TestRunMailList_Envelope(t *testing.T) {
+	repo := &MockMessageRepository{ListResult: &mail.ListResult[*mail.Message]{
+		Items:         []*mail.Message{{ID: "msg1"}, {ID: "msg2"}},
+		NextPageToken: "page-3",
+		Total:         57,
+		Warnings:      []string{"message msg2: only its ID"},
+	}}
+	setupEnvelopeTest(t, repo)
+	mailPageToken = "page-2"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&bytes.Buffer{})
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("runMailList() error = %v", err)
+	}
+
+	if repo.ListOpts.PageToken != "page-2" {
+		t.Errorf("page token = %q, want page-2", repo.ListOpts.PageToken)
+	}
+	var got envelopeResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not an envelope: %v\n%s", err, buf.String())
+	}
+	if len(got.Items) != 2 || got.FetchedCount != 2 || got.NextPageToken != "page-3" {
+		t.Errorf("envelope = %+v", got)
+	}
+	if got.TotalEstimate == nil || *got.TotalEstimate != 57 {
+		t.Errorf("total_estimate = %v, want 57", got.TotalEstimate)
+	}
+	if len(got.Warnings) != 1 {
+		t.Errorf("warnings = %v, want one", got.Warnings)
+	}
+}
+
+func TestRunMailList_EnvelopeNeedsJSON(t *testing.T) {
+	setupEnvelopeTest(t, &MockMessageRepository{Messages: []*mail.Message{{ID: "msg1"}}})
+	formatFlag = "plain"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("runMailList() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "next_page_token") {
+		t.Errorf("plain output was wrapped: %s", buf.String())
+	}
+}
+
+func TestRunMailSearch_PageToken(t *testing.T) {
+	repo := &MockMessageRepository{SearchResult: &mail.ListResult[*mail.Message]{
+		Items:         []*mail.Message{{ID: "msg1"}},
+		NextPageToken: "page-2",
+		Total:         3,
+	}}
+	setupEnvelopeTest(t, repo)
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"is:unread"}); err != nil {
+		t.Fatalf("runMailSearch() error = %v", err)
+	}
+	if repo.SearchEachFetched != 0 {
+		t.Errorf("search followed pages; want one page")
+	}
+	var got envelopeResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not an envelope: %v\n%s", err, buf.String())
+	}
+	if got.NextPageToken != "page-2" || got.FetchedCount != 1 {
+		t.Errorf("envelope = %+v", got)
+	}
+
+	// The next call passes the token on
+	mailPageToken = got.NextPageToken
+	buf.Reset()
+	if err := runMailSearch(cmd, []string{"is:unread"}); err != nil {
+		t.Fatalf("runMailSearch() error = %v", err)
+	}
+	if repo.SearchOpts.PageToken != "page-2" {
+		t.Errorf("page token = %q, want page-2", repo.SearchOpts.PageToken)
+	}
+
+	mailSearchAll = true
+	err := runMailSearch(cmd, []string{"is:unread"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be used with --limit or --all") {
+		t.Errorf("runMailSearch() with --all error = %v", err)
+	}
+}
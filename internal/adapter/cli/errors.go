@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+)
+
+// ANSI escape codes for error output on a terminal.
+const (
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+	ansiReset  = "\033[0m"
+)
+
+// errReported marks an error that has already been shown to the user, so
+// Execute only sets the exit status.
+var errReported = errors.New("error already reported")
+
+// printError writes a command error to w. JSON output gets an error object
+// with the API status, reason and hint so scripts can act on it; other
+// formats get the message and, unless output is quiet, the hint, colored
+// when color is true: yellow for errors that may succeed on retry, red for
+// the rest. Only the text output is translated; the JSON error stays in
+// English for scripts.
+func printError(w io.Writer, err error, color bool) {
+	if formatFlag == "json" {
+		fmt.Fprintln(w, presenter.New("json").RenderError(err))
+		return
+	}
+
+	retryable := errors.Is(err, repository.ErrRateLimited) || errors.Is(err, repository.ErrTemporary)
+	label := i18n.T("Error:")
+	if retryable && presenter.Accessible() {
+		// Accessible output says in words what the yellow label shows.
+		label = i18n.T("Error (retryable):")
+	}
+	if color {
+		code := ansiRed
+		if retryable {
+			code = ansiYellow
+		}
+		label = code + label + ansiReset
+	}
+	fmt.Fprintln(w, label, err)
+
+	if hint := presenter.ErrorHint(err); hint != "" && !output.Quiet() {
+		label := i18n.T("Hint:")
+		if color {
+			label = ansiCyan + label + ansiReset
+		}
+		fmt.Fprintln(w, label, i18n.T(hint))
+	}
+}
+
+// colorEnabled reports whether error output to f should be colored: f must
+// be a terminal, and NO_COLOR, TERM=dumb and accessible output turn color
+// off.
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || presenter.Accessible() {
+		return false
+	}
+	return isTerminal(f)
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
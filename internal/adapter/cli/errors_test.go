@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+)
+
+func TestPrintError(t *testing.T) {
+	origFormat := formatFlag
+	t.Cleanup(func() { formatFlag = origFormat })
+
+	apiErr := fmt.Errorf("failed to list messages: %w", &repository.APIError{
+		Service: "gmail",
+		Status:  403,
+		Reason:  "userRateLimitExceeded",
+		Message: "User rate limit exceeded",
+		Hint:    "pause between commands",
+		Err:     repository.ErrRateLimited,
+	})
+
+	t.Run("text with hint", func(t *testing.T) {
+		formatFlag = "table"
+		var buf bytes.Buffer
+		printError(&buf, apiErr, false)
+
+		want := "Error: failed to list messages: rate limited (status 403): User rate limit exceeded\nHint: pause between commands\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("quiet leaves out the hint", func(t *testing.T) {
+		formatFlag = "table"
+		withVerbosity(t, true, 0)
+		var buf bytes.Buffer
+		printError(&buf, apiErr, false)
+
+		want := "Error: failed to list messages: rate limited (status 403): User rate limit exceeded\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("plain error has no hint", func(t *testing.T) {
+		formatFlag = "plain"
+		var buf bytes.Buffer
+		printError(&buf, errors.New("no account found"), false)
+
+		if buf.String() != "Error: no account found\n" {
+			t.Errorf("got %q", buf.String())
+		}
+	})
+
+	t.Run("color by retryability", func(t *testing.T) {
+		formatFlag = "table"
+		var buf bytes.Buffer
+		printError(&buf, apiErr, true)
+		if !containsStr(buf.String(), ansiYellow+"Error:"+ansiReset) || !containsStr(buf.String(), ansiCyan+"Hint:"+ansiReset) {
+			t.Errorf("expected yellow error and cyan hint, got %q", buf.String())
+		}
+
+		buf.Reset()
+		printError(&buf, errors.New("bad flag"), true)
+		if !containsStr(buf.String(), ansiRed+"Error:"+ansiReset) {
+			t.Errorf("expected red error, got %q", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		formatFlag = "json"
+		var buf bytes.Buffer
+		printError(&buf, apiErr, true)
+
+		var got struct {
+			Error  string `json:"error"`
+			Status int    `json:"status"`
+			Reason string `json:"reason"`
+			Hint   string `json:"hint"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("expected JSON, got %q: %v", buf.String(), err)
+		}
+		if got.Status != 403 || got.Reason != "userRateLimitExceeded" || got.Hint != "pause between commands" {
+			t.Errorf("unexpected JSON error %+v", got)
+		}
+		if containsStr(buf.String(), "\033[") {
+			t.Error("JSON output should never be colored")
+		}
+	})
+}
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	if colorEnabled(f) {
+		t.Error("regular files are not terminals")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled(os.Stderr) {
+		t.Error("NO_COLOR should disable color")
+	}
+}
+
+func TestPrintError_Accessible(t *testing.T) {
+	origFormat := formatFlag
+	t.Cleanup(func() { formatFlag = origFormat })
+	formatFlag = "table"
+	presenter.SetAccessible(true)
+	t.Cleanup(func() { presenter.SetAccessible(false) })
+
+	var buf bytes.Buffer
+	printError(&buf, fmt.Errorf("failed to list messages: %w", repository.ErrTemporary), false)
+	if !strings.HasPrefix(buf.String(), "Error (retryable): ") {
+		t.Errorf("got %q, want the retryable label in words", buf.String())
+	}
+
+	buf.Reset()
+	printError(&buf, errors.New("no account found"), false)
+	if buf.String() != "Error: no account found\n" {
+		t.Errorf("got %q, want the plain label", buf.String())
+	}
+}
@@ -0,0 +1,329 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/history"
+)
+
+// Command flags for history commands.
+var (
+	historyListLimit  int
+	historyRerunPrint bool
+)
+
+// historyContentFlags are flags whose values are message, event, task or
+// contact content, or secrets. Their values are not recorded.
+var historyContentFlags = map[string]bool{
+	"body":         true,
+	"body-html":    true,
+	"html":         true,
+	"subject":      true,
+	"template":     true,
+	"text":         true,
+	"title":        true,
+	"description":  true,
+	"notes":        true,
+	"location":     true,
+	"password":     true,
+	"given-name":   true,
+	"family-name":  true,
+	"phone":        true,
+	"address":      true,
+	"birthday":     true,
+	"organization": true,
+}
+
+// historyContentArgs are commands whose arguments are content rather than
+// IDs or queries. Their arguments are not recorded.
+var historyContentArgs = map[string]bool{
+	"cal quick":         true,
+	"tasks create":      true,
+	"tasks create-list": true,
+}
+
+// runHistoryArgs runs goog with args, connected to the command's input and
+// output. It is a variable so tests can rerun without starting a process.
+var runHistoryArgs = func(cmd *cobra.Command, args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the goog executable: %w", err)
+	}
+	c := exec.Command(exe, args...)
+	c.Stdin, c.Stdout, c.Stderr = cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr()
+	return c.Run()
+}
+
+// historyCmd represents the history command group.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show and rerun previous commands",
+	Long: `Show and rerun previous goog commands.
+
+Each command is recorded in a history file next to the config file, with
+its flags and arguments but not its content: values of flags such as
+--body, --subject and --notes, and the text of 'cal quick' and
+'tasks create', are replaced with REDACTED. The last 1000 commands are
+kept. Set history.enabled to false to stop recording.
+
+Commands are recorded as typed, so a rerun resolves relative dates such
+as --since 2d or 'today' again, and uses the account named by --account
+or, without it, the current default account.`,
+	Example: `  # Show recent commands
+  goog history list
+
+  # Run command 42 again
+  goog history rerun 42
+
+  # Stop recording history
+  goog config set history.enabled false`,
+}
+
+// historyListCmd lists recorded commands.
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List previous commands",
+	Long:  `List the most recent goog commands, oldest first, with their numbers for 'goog history rerun'.`,
+	Example: `  # Show the last 20 commands
+  goog history list
+
+  # Show the last 100 commands as JSON
+  goog history list --limit 100 --format json`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runHistoryList,
+}
+
+// historyRerunCmd runs a recorded command again.
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <n>",
+	Short: "Run a previous command again",
+	Long: `Run command number n from 'goog history list' again.
+
+Relative dates are resolved again, so 'goog cal today' shows the events
+of the day it is rerun. Commands whose content was redacted cannot be
+rerun.`,
+	Example: `  # Run command 42 again
+  goog history rerun 42
+
+  # Print command 42 without running it
+  goog history rerun 42 --print`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryRerun,
+}
+
+// historyClearCmd clears the history.
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the command history",
+	Long:  `Remove every recorded command from the history.`,
+	Args:  cobra.NoArgs,
+	RunE:  runHistoryClear,
+}
+
+func init() {
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyRerunCmd)
+	historyCmd.AddCommand(historyClearCmd)
+
+	historyListCmd.Flags().IntVar(&historyListLimit, "limit", 20, "number of most recent commands to show (0 for all)")
+	historyRerunCmd.Flags().BoolVar(&historyRerunPrint, "print", false, "print the command instead of running it")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+// runHistoryList handles the history list command.
+func runHistoryList(cmd *cobra.Command, args []string) error {
+	entries, err := history.Load(history.Path())
+	if err != nil {
+		return err
+	}
+	if historyListLimit > 0 && len(entries) > historyListLimit {
+		entries = entries[len(entries)-historyListLimit:]
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		if !quietFlag {
+			cmd.Println("No commands recorded.")
+		}
+		return nil
+	}
+	locale := presenter.CurrentLocale()
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "#\tTIME\tSTATUS\tCOMMAND")
+	for _, e := range entries {
+		status := "ok"
+		if e.Failed {
+			status = "failed"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.ID, locale.DateTime(e.Time.Local()), status, commandLine(e.Args))
+	}
+	return w.Flush()
+}
+
+// runHistoryRerun handles the history rerun command.
+func runHistoryRerun(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid history number %q: use a number from 'goog history list'", args[0])
+	}
+	entry, err := history.Get(history.Path(), id)
+	if err != nil {
+		return err
+	}
+
+	if historyRerunPrint {
+		cmd.Println(commandLine(entry.Args))
+		return nil
+	}
+	if entry.Redacted {
+		return fmt.Errorf("command %d cannot be rerun because its content was not recorded: %s", id, commandLine(entry.Args))
+	}
+	if !quietFlag {
+		cmd.PrintErrln(commandLine(entry.Args))
+	}
+
+	err = runHistoryArgs(cmd, entry.Args)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// The command has already printed its error
+		return fmt.Errorf("%w: command %d exited with status %d", errReported, id, exitErr.ExitCode())
+	}
+	return err
+}
+
+// runHistoryClear handles the history clear command.
+func runHistoryClear(cmd *cobra.Command, args []string) error {
+	if err := history.Clear(history.Path()); err != nil {
+		return err
+	}
+	if !quietFlag {
+		cmd.Println("History cleared.")
+	}
+	return nil
+}
+
+// recordHistory adds the command that ran to the history when history is
+// enabled. Help, completion and history commands are not recorded. A
+// failure to save is only reported with --verbose, so history never gets
+// in the way of a command.
+func recordHistory(cmd *cobra.Command, runErr error) {
+	entry, ok := historyEntry(cmd)
+	if !ok || !historyEnabled() {
+		return
+	}
+	entry.Failed = runErr != nil
+	if _, err := history.Append(history.Path(), entry); err != nil {
+		output.Verbosef(cmd, "Warning: failed to save history: %v", err)
+	}
+}
+
+// historyEnabled reports whether history.enabled is set, which it is by
+// default.
+func historyEnabled() bool {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return config.NewConfig().History.Enabled
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.History.Enabled
+}
+
+// historyEntry builds the history entry for a parsed command, with content
+// redacted. It reports false for commands that are not recorded.
+func historyEntry(cmd *cobra.Command) (history.Entry, bool) {
+	if cmd == nil || !cmd.Runnable() || !cmd.Flags().Parsed() || isHistoryCommand(cmd) {
+		return history.Entry{}, false
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return history.Entry{}, false
+	}
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	if path == cmd.Root().Name() {
+		return history.Entry{}, false
+	}
+
+	entry := history.Entry{Command: path}
+	args := strings.Fields(path)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		values := []string{f.Value.String()}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			values = slice.GetSlice()
+		}
+		for _, value := range values {
+			if historyContentFlags[f.Name] {
+				value = history.Redacted
+				entry.Redacted = true
+			}
+			args = append(args, "--"+f.Name+"="+value)
+		}
+	})
+
+	positional := cmd.Flags().Args()
+	for _, arg := range positional {
+		if strings.HasPrefix(arg, "-") {
+			args = append(args, "--")
+			break
+		}
+	}
+	for _, arg := range positional {
+		if historyContentArgs[path] {
+			arg = history.Redacted
+			entry.Redacted = true
+		}
+		args = append(args, arg)
+	}
+	entry.Args = args
+	return entry, true
+}
+
+// isHistoryCommand reports whether cmd is the history command, one of its
+// subcommands, or a help or completion command.
+func isHistoryCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == historyCmd {
+			return true
+		}
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
+
+// commandLine formats args as a goog command line, quoting arguments the
+// shell would split or expand.
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, rootCmd.Name())
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`!*?;&|<>()[]{}#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
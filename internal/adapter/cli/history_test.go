@@ -0,0 +1,317 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/history"
+)
+
+// setupHistoryTest points the config at a temporary directory with
+// history enabled or not, and restores the history flags afterwards.
+func setupHistoryTest(t *testing.T, enabled bool) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.History.Enabled = enabled
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	origFormat, origLimit, origPrint, origRun := formatFlag, historyListLimit, historyRerunPrint, runHistoryArgs
+	formatFlag = "table"
+	historyListLimit = 20
+	historyRerunPrint = false
+	t.Cleanup(func() {
+		formatFlag, historyListLimit, historyRerunPrint, runHistoryArgs = origFormat, origLimit, origPrint, origRun
+	})
+}
+
+// parseTestCommand runs args against a small command tree and returns the
+// command that ran, with its flags parsed.
+func parseTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	root := &cobra.Command{Use: "goog"}
+	root.PersistentFlags().String("account", "", "")
+	mail := &cobra.Command{Use: "mail"}
+	send := &cobra.Command{Use: "send", Run: func(*cobra.Command, []string) {}}
+	send.Flags().StringSlice("to", nil, "")
+	send.Flags().String("subject", "", "")
+	send.Flags().String("body", "", "")
+	search := &cobra.Command{Use: "search", Run: func(*cobra.Command, []string) {}}
+	search.Flags().String("since", "", "")
+	awaiting := &cobra.Command{Use: "awaiting", Run: func(*cobra.Command, []string) {}}
+	awaiting.Flags().Bool("nudge", false, "")
+	awaiting.Flags().String("template", "", "")
+	tasks := &cobra.Command{Use: "tasks"}
+	create := &cobra.Command{Use: "create", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(mail, tasks)
+	mail.AddCommand(send, search, awaiting)
+	tasks.AddCommand(create)
+
+	root.SetArgs(args)
+	root.SetOut(new(bytes.Buffer))
+	cmd, err := root.ExecuteC()
+	if err != nil {
+		t.Fatalf("failed to run %v: %v", args, err)
+	}
+	return cmd
+}
+
+func TestHistoryEntry(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantArgs     []string
+		wantRedacted bool
+	}{
+		{
+			name:     "records flags and arguments",
+			args:     []string{"mail", "search", "from:boss", "--since", "2d", "--account", "work"},
+			wantArgs: []string{"mail", "search", "--account=work", "--since=2d", "from:boss"},
+		},
+		{
+			name:         "redacts content flags",
+			args:         []string{"mail", "send", "--to", "a@example.com,b@example.com", "--subject", "Salary", "--body", "secret"},
+			wantArgs:     []string{"mail", "send", "--body=REDACTED", "--subject=REDACTED", "--to=a@example.com", "--to=b@example.com"},
+			wantRedacted: true,
+		},
+		{
+			name:         "redacts follow-up templates",
+			args:         []string{"mail", "awaiting", "--nudge", "--template", "Hi {name}, about the offer"},
+			wantArgs:     []string{"mail", "awaiting", "--nudge=true", "--template=REDACTED"},
+			wantRedacted: true,
+		},
+		{
+			name:         "redacts content arguments",
+			args:         []string{"tasks", "create", "Call the bank"},
+			wantArgs:     []string{"tasks", "create", "REDACTED"},
+			wantRedacted: true,
+		},
+		{
+			name:     "keeps dash arguments after --",
+			args:     []string{"mail", "search", "--", "-in:spam"},
+			wantArgs: []string{"mail", "search", "--", "-in:spam"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := historyEntry(parseTestCommand(t, tt.args...))
+			if !ok {
+				t.Fatal("expected the command to be recorded")
+			}
+			if !reflect.DeepEqual(entry.Args, tt.wantArgs) {
+				t.Errorf("Args = %q, want %q", entry.Args, tt.wantArgs)
+			}
+			if entry.Redacted != tt.wantRedacted {
+				t.Errorf("Redacted = %v, want %v", entry.Redacted, tt.wantRedacted)
+			}
+		})
+	}
+
+	t.Run("skips help", func(t *testing.T) {
+		if _, ok := historyEntry(parseTestCommand(t, "mail", "search", "--help")); ok {
+			t.Error("help should not be recorded")
+		}
+	})
+
+	t.Run("skips command groups", func(t *testing.T) {
+		if _, ok := historyEntry(parseTestCommand(t, "mail")); ok {
+			t.Error("a command group should not be recorded")
+		}
+	})
+
+	t.Run("skips history commands", func(t *testing.T) {
+		if _, ok := historyEntry(historyListCmd); ok {
+			t.Error("history commands should not be recorded")
+		}
+	})
+}
+
+func TestRecordHistory(t *testing.T) {
+	t.Run("appends the command", func(t *testing.T) {
+		setupHistoryTest(t, true)
+		recordHistory(parseTestCommand(t, "mail", "search", "is:unread"), errors.New("failed"))
+
+		entries, err := history.Load(history.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("got %d entries, want 1", len(entries))
+		}
+		if entries[0].Command != "mail search" || !entries[0].Failed {
+			t.Errorf("entry = %+v, want a failed mail search", entries[0])
+		}
+	})
+
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		setupHistoryTest(t, false)
+		recordHistory(parseTestCommand(t, "mail", "search", "is:unread"), nil)
+
+		entries, err := history.Load(history.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("got %d entries, want none", len(entries))
+		}
+	})
+}
+
+func TestRunHistoryList(t *testing.T) {
+	setupHistoryTest(t, true)
+	for _, args := range [][]string{{"cal", "today"}, {"mail", "search", "from:a b"}} {
+		if _, err := history.Append(history.Path(), history.Entry{Command: strings.Join(args[:2], " "), Args: args}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("table", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "list"}
+		cmd.SetOut(buf)
+		if err := runHistoryList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"#", "COMMAND", "goog cal today", "goog mail search 'from:a b'"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		historyListLimit = 1
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "list"}
+		cmd.SetOut(buf)
+		if err := runHistoryList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(buf.String(), "cal today") {
+			t.Errorf("expected only the last command, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		historyListLimit = 0
+		formatFlag = "json"
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "list"}
+		cmd.SetOut(buf)
+		if err := runHistoryList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var entries []history.Entry
+		if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(entries) != 2 || entries[1].ID != 2 {
+			t.Errorf("entries = %+v", entries)
+		}
+	})
+}
+
+func TestRunHistoryRerun(t *testing.T) {
+	setup := func(t *testing.T) *[]string {
+		t.Helper()
+		setupHistoryTest(t, true)
+		ran := new([]string)
+		runHistoryArgs = func(cmd *cobra.Command, args []string) error {
+			*ran = args
+			return nil
+		}
+		if _, err := history.Append(history.Path(), history.Entry{Command: "cal today", Args: []string{"cal", "today"}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := history.Append(history.Path(), history.Entry{Command: "tasks create", Args: []string{"tasks", "create", history.Redacted}, Redacted: true}); err != nil {
+			t.Fatal(err)
+		}
+		return ran
+	}
+
+	t.Run("runs the command", func(t *testing.T) {
+		ran := setup(t)
+		cmd := &cobra.Command{Use: "rerun"}
+		cmd.SetErr(new(bytes.Buffer))
+		if err := runHistoryRerun(cmd, []string{"1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(*ran, []string{"cal", "today"}) {
+			t.Errorf("ran %q, want cal today", *ran)
+		}
+	})
+
+	t.Run("prints the command", func(t *testing.T) {
+		ran := setup(t)
+		historyRerunPrint = true
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "rerun"}
+		cmd.SetOut(buf)
+		if err := runHistoryRerun(cmd, []string{"1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.TrimSpace(buf.String()) != "goog cal today" {
+			t.Errorf("output = %q, want goog cal today", buf.String())
+		}
+		if *ran != nil {
+			t.Error("--print should not run the command")
+		}
+	})
+
+	t.Run("refuses redacted commands", func(t *testing.T) {
+		ran := setup(t)
+		err := runHistoryRerun(&cobra.Command{Use: "rerun"}, []string{"2"})
+		if err == nil || !strings.Contains(err.Error(), "content was not recorded") {
+			t.Errorf("expected a redaction error, got %v", err)
+		}
+		if *ran != nil {
+			t.Error("a redacted command should not run")
+		}
+	})
+
+	t.Run("rejects unknown numbers", func(t *testing.T) {
+		setup(t)
+		if err := runHistoryRerun(&cobra.Command{Use: "rerun"}, []string{"9"}); !errors.Is(err, history.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if err := runHistoryRerun(&cobra.Command{Use: "rerun"}, []string{"last"}); err == nil {
+			t.Error("expected an error for a non-numeric argument")
+		}
+	})
+
+	t.Run("reports a failed rerun once", func(t *testing.T) {
+		setup(t)
+		runHistoryArgs = func(cmd *cobra.Command, args []string) error {
+			return exec.Command("sh", "-c", "exit 3").Run()
+		}
+		cmd := &cobra.Command{Use: "rerun"}
+		cmd.SetErr(new(bytes.Buffer))
+		err := runHistoryRerun(cmd, []string{"1"})
+		if !errors.Is(err, errReported) {
+			t.Errorf("expected errReported, got %v", err)
+		}
+		if err == nil || !strings.Contains(err.Error(), "status 3") {
+			t.Errorf("expected the exit status in %v", err)
+		}
+	})
+}
+
+func TestCommandLine(t *testing.T) {
+	got := commandLine([]string{"mail", "search", "from:a b", "it's", "--since=2d"})
+	want := `goog mail search 'from:a b' 'it'\''s' --since=2d`
+	if got != want {
+		t.Errorf("commandLine = %q, want %q", got, want)
+	}
+}
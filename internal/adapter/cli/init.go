@@ -0,0 +1,410 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Command flags for init command.
+var (
+	initClientFile string
+)
+
+// initAccessLevels maps the access levels offered by init to OAuth scopes.
+var initAccessLevels = map[string][]string{
+	"read-only": {
+		auth.ScopeGmailReadonly,
+		auth.ScopeCalendarReadonly,
+		auth.ScopeTasksReadonly,
+		auth.ScopeContactsReadonly,
+		auth.ScopeUserInfoEmail,
+		auth.ScopeOpenID,
+	},
+	"full": {
+		auth.ScopeGmailModify,
+		auth.ScopeCalendar,
+		auth.ScopeTasks,
+		auth.ScopeContacts,
+		auth.ScopeUserInfoEmail,
+		auth.ScopeOpenID,
+	},
+}
+
+// initFormats are the output formats offered as the default by init.
+var initFormats = []string{"table", "json", "plain"}
+
+// initCmd runs the first-run setup wizard.
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up goog interactively",
+	Long: `Set up goog step by step.
+
+The wizard walks through:
+  1. OAuth client: use GOOG_CLIENT_ID, the bundled client, or the
+     client JSON downloaded from the Google Cloud Console
+  2. Sign in: authorize an account in the browser
+  3. Defaults: choose the default account and output format
+  4. Check: optionally list a few messages or send yourself a test email
+
+Each step is validated before moving on, and the wizard can be re-run
+at any time to add another account or change the defaults.`,
+	Example: `  # Run the setup wizard
+  goog init
+
+  # Use an OAuth client JSON downloaded from the Cloud Console
+  goog init --client-file ~/Downloads/client_secret_123.json`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initClientFile, "client-file", "", "OAuth client JSON downloaded from the Google Cloud Console")
+
+	rootCmd.AddCommand(initCmd)
+}
+
+// runInit handles the init command.
+func runInit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	p := newPrompter(cmd)
+
+	cmd.Println("Welcome to goog. This wizard connects goog to your Google account.")
+
+	cmd.Println("\nStep 1/4: OAuth client")
+	if err := initClientStep(cmd, p); err != nil {
+		return err
+	}
+
+	cmd.Println("\nStep 2/4: Sign in")
+	alias, err := initLoginStep(ctx, cmd, p)
+	if err != nil {
+		return err
+	}
+
+	cmd.Println("\nStep 3/4: Defaults")
+	if err := initDefaultsStep(cmd, p); err != nil {
+		return err
+	}
+
+	cmd.Println("\nStep 4/4: Check")
+	if err := initCheckStep(ctx, cmd, p, alias); err != nil {
+		return err
+	}
+
+	cmd.Println("\nSetup complete. Try 'goog mail list' or 'goog cal today'.")
+	return nil
+}
+
+// initClientStep makes sure an OAuth client is configured, saving one to
+// the client file if needed.
+func initClientStep(cmd *cobra.Command, p *prompter) error {
+	if initClientFile == "" {
+		_, source := auth.ResolveClientCredentials()
+		switch source {
+		case auth.ClientSourceEnv:
+			cmd.Printf("Using the OAuth client from %s.\n", auth.EnvClientID)
+			return nil
+		case auth.ClientSourceFile:
+			keep, err := p.confirm(i18n.Sprintf("Keep the OAuth client in %s?", auth.ClientFilePath()), true)
+			if err != nil || keep {
+				return err
+			}
+		case auth.ClientSourceBundled:
+			use, err := p.confirm("Use the OAuth client bundled with goog?", true)
+			if err != nil || use {
+				return err
+			}
+		}
+	}
+
+	path := initClientFile
+	for {
+		if path == "" {
+			cmd.Println("Create a Desktop app OAuth client in the Google Cloud Console and download its JSON")
+			cmd.Println("(see documentation/SETUP.md). Leave the path empty to paste the ID and secret instead.")
+			var err error
+			if path, err = p.ask("Path to client JSON", ""); err != nil {
+				return err
+			}
+		}
+
+		creds, err := initReadClient(p, path)
+		if err == nil {
+			if err := auth.SaveClientFile(auth.ClientFilePath(), creds); err != nil {
+				return err
+			}
+			cmd.Printf("Saved OAuth client to %s\n", auth.ClientFilePath())
+			return nil
+		}
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		cmd.Printf("Error: %v\n", err)
+		path = ""
+	}
+}
+
+// initReadClient loads client credentials from a JSON file or, when path
+// is empty, from pasted values.
+func initReadClient(p *prompter, path string) (auth.ClientCredentials, error) {
+	if path != "" {
+		return auth.LoadClientFile(expandHome(path))
+	}
+	id, err := p.ask("Client ID", "")
+	if err != nil {
+		return auth.ClientCredentials{}, err
+	}
+	secret, err := p.ask("Client secret", "")
+	if err != nil {
+		return auth.ClientCredentials{}, err
+	}
+	creds := auth.ClientCredentials{ClientID: id, ClientSecret: secret}
+	return creds, creds.Validate()
+}
+
+// initLoginStep signs in an account and returns its alias. When accounts
+// already exist the user may skip signing in another one, in which case
+// the current default account is returned.
+func initLoginStep(ctx context.Context, cmd *cobra.Command, p *prompter) (string, error) {
+	svc := getAccountServiceFromDeps()
+	accounts, err := svc.List()
+	if err != nil {
+		return "", fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	existing := make(map[string]bool, len(accounts))
+	defaultAlias := ""
+	for _, acc := range accounts {
+		existing[acc.Alias] = true
+		if acc.IsDefault {
+			defaultAlias = acc.Alias
+		}
+	}
+
+	if len(accounts) > 0 {
+		cmd.Printf("%d account(s) already signed in.\n", len(accounts))
+		another, err := p.confirm("Sign in another account?", false)
+		if err != nil {
+			return "", err
+		}
+		if !another {
+			return defaultAlias, nil
+		}
+	}
+
+	suggested := "default"
+	if existing[suggested] {
+		suggested = ""
+	}
+	var alias string
+	for {
+		if alias, err = p.ask("Account alias", suggested); err != nil {
+			return "", err
+		}
+		if alias == "" || strings.ContainsAny(alias, " \t") {
+			cmd.Println("Error: alias must be a single word")
+			continue
+		}
+		if existing[alias] {
+			cmd.Printf("Error: account %q already exists\n", alias)
+			continue
+		}
+		break
+	}
+
+	level, err := p.choose("Access level", []string{"full", "read-only"}, "full")
+	if err != nil {
+		return "", err
+	}
+
+	cmd.Println("Opening your browser to sign in with Google...")
+	acc, err := svc.Add(ctx, alias, initAccessLevels[level])
+	if err != nil {
+		return "", fmt.Errorf("sign-in failed: %w", err)
+	}
+	cmd.Printf("Signed in as %s (alias %s)\n", acc.Email, acc.Alias)
+	return acc.Alias, nil
+}
+
+// initDefaultsStep chooses the default account and output format.
+func initDefaultsStep(cmd *cobra.Command, p *prompter) error {
+	svc := getAccountServiceFromDeps()
+	accounts, err := svc.List()
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+	if len(accounts) > 1 {
+		aliases := make([]string, 0, len(accounts))
+		current := ""
+		for _, acc := range accounts {
+			aliases = append(aliases, acc.Alias)
+			if acc.IsDefault {
+				current = acc.Alias
+			}
+		}
+		choice, err := p.choose("Default account", aliases, current)
+		if err != nil {
+			return err
+		}
+		if choice != current {
+			if err := svc.Switch(choice); err != nil {
+				return fmt.Errorf("failed to set default account: %w", err)
+			}
+		}
+		cmd.Printf("Default account: %s\n", choice)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	format, err := p.choose("Default output format", initFormats, cfg.DefaultFormat)
+	if err != nil {
+		return err
+	}
+	if err := cfg.SetValue("default_format", format); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	cmd.Printf("Default output format: %s\n", format)
+	return nil
+}
+
+// initCheckStep optionally verifies access by listing inbox messages or
+// sending a test message to the signed-in account.
+func initCheckStep(ctx context.Context, cmd *cobra.Command, p *prompter, alias string) error {
+	choice, err := p.choose("Check access by listing messages, sending yourself a test email, or skip", []string{"list", "send", "skip"}, "list")
+	if err != nil || choice == "skip" {
+		return err
+	}
+
+	origAccount := accountFlag
+	accountFlag = alias
+	defer func() { accountFlag = origAccount }()
+
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	if choice == "list" {
+		result, err := repo.List(ctx, mail.ListOptions{MaxResults: 5, LabelIDs: []string{"INBOX"}})
+		if err != nil {
+			return fmt.Errorf("check failed: could not list messages (is the Gmail API enabled?): %w", err)
+		}
+		cmd.Printf("Gmail access works: %d recent inbox message(s).\n", len(result.Items))
+		return nil
+	}
+
+	sent, err := repo.Send(ctx, &mail.Message{
+		From:    mail.ParseAddress(email),
+		To:      mail.ParseAddresses([]string{email}),
+		Subject: "goog test message",
+		Body:    "This message was sent by 'goog init' to confirm that goog can send mail.",
+	})
+	if err != nil {
+		return fmt.Errorf("check failed: could not send the test message (sending needs full access): %w", err)
+	}
+	cmd.Printf("Sent a test message to %s (ID %s).\n", email, sent.ID)
+	return nil
+}
+
+// expandHome replaces a leading "~/" with the user's home directory.
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// prompter asks interactive questions on the command's input and output.
+type prompter struct {
+	cmd *cobra.Command
+	in  *bufio.Reader
+}
+
+// newPrompter creates a prompter reading from the command's input.
+func newPrompter(cmd *cobra.Command) *prompter {
+	return &prompter{cmd: cmd, in: bufio.NewReader(cmd.InOrStdin())}
+}
+
+// ask prints question, translated, and returns the trimmed answer, or
+// def when the answer is empty. It fails with io.EOF when the input ends.
+func (p *prompter) ask(question, def string) (string, error) {
+	return p.prompt(i18n.T(question), def)
+}
+
+// prompt prints question as it is and reads the answer like ask.
+func (p *prompter) prompt(question, def string) (string, error) {
+	if def != "" {
+		p.cmd.Printf("%s [%s]: ", question, def)
+	} else {
+		p.cmd.Printf("%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		p.cmd.Println()
+		return "", fmt.Errorf("no answer to %q: %w", question, err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// confirm asks a yes/no question until it gets a valid answer. The
+// answers of the message language are accepted as well as English ones.
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	hint := i18n.T("y/N")
+	if def {
+		hint = i18n.T("Y/n")
+	}
+	for {
+		answer, err := p.prompt(i18n.T(question)+" ("+hint+")", "")
+		if err != nil {
+			return false, err
+		}
+		switch answer = strings.ToLower(answer); {
+		case answer == "":
+			return def, nil
+		case answer == "y" || answer == "yes" || answer == i18n.T("y") || answer == i18n.T("yes"):
+			return true, nil
+		case answer == "n" || answer == "no" || answer == i18n.T("n") || answer == i18n.T("no"):
+			return false, nil
+		}
+		p.cmd.Println(i18n.T("Please answer y or n."))
+	}
+}
+
+// choose asks for one of options until it gets a valid answer.
+func (p *prompter) choose(question string, options []string, def string) (string, error) {
+	for {
+		answer, err := p.prompt(fmt.Sprintf("%s (%s)", i18n.T(question), strings.Join(options, ", ")), def)
+		if err != nil {
+			return "", err
+		}
+		for _, option := range options {
+			if strings.EqualFold(answer, option) {
+				return option, nil
+			}
+		}
+		p.cmd.Println(i18n.Sprintf("Please choose one of: %s", strings.Join(options, ", ")))
+	}
+}
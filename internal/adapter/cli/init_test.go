@@ -0,0 +1,329 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+const initTestClientJSON = `{"installed":{"client_id":"123.apps.googleusercontent.com","client_secret":"s3cret"}}`
+
+// setupInitTest isolates the config directory, clears any configured OAuth
+// client and returns a command reading input and capturing output.
+func setupInitTest(t *testing.T, input string) (*cobra.Command, *bytes.Buffer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+	t.Setenv(auth.EnvClientID, "")
+	t.Setenv(auth.EnvClientSecret, "")
+	t.Setenv("GOOG_FORMAT", "")
+
+	origBundledID, origBundledSecret := auth.BundledClientID, auth.BundledClientSecret
+	origClientFile := initClientFile
+	origAccount := accountFlag
+	auth.BundledClientID, auth.BundledClientSecret = "", ""
+	initClientFile = ""
+	t.Cleanup(func() {
+		auth.BundledClientID, auth.BundledClientSecret = origBundledID, origBundledSecret
+		initClientFile = origClientFile
+		accountFlag = origAccount
+		ResetDependencies()
+	})
+
+	cmd := &cobra.Command{Use: "init"}
+	var buf bytes.Buffer
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&buf)
+	return cmd, &buf, dir
+}
+
+func TestInitCmd_Help(t *testing.T) {
+	if initCmd.Use != "init" {
+		t.Errorf("expected Use 'init', got %q", initCmd.Use)
+	}
+	if initCmd.Flags().Lookup("client-file") == nil {
+		t.Error("expected --client-file flag")
+	}
+	for _, want := range []string{"OAuth client", "Sign in", "Defaults", "Check"} {
+		if !strings.Contains(initCmd.Long, want) {
+			t.Errorf("expected Long to mention %q", want)
+		}
+	}
+}
+
+func TestPrompter(t *testing.T) {
+	newTestPrompter := func(input string) (*prompter, *bytes.Buffer) {
+		cmd := &cobra.Command{Use: "test"}
+		var buf bytes.Buffer
+		cmd.SetIn(strings.NewReader(input))
+		cmd.SetOut(&buf)
+		return newPrompter(cmd), &buf
+	}
+
+	t.Run("ask returns answer or default", func(t *testing.T) {
+		p, out := newTestPrompter("  work \n\n")
+		if got, _ := p.ask("Alias", "default"); got != "work" {
+			t.Errorf("ask() = %q, want work", got)
+		}
+		if got, _ := p.ask("Alias", "default"); got != "default" {
+			t.Errorf("ask() = %q, want default", got)
+		}
+		if !strings.Contains(out.String(), "Alias [default]: ") {
+			t.Errorf("expected prompt with default, got %q", out.String())
+		}
+	})
+
+	t.Run("ask accepts a final line without newline", func(t *testing.T) {
+		p, _ := newTestPrompter("last")
+		if got, err := p.ask("Q", ""); err != nil || got != "last" {
+			t.Errorf("ask() = %q, %v", got, err)
+		}
+	})
+
+	t.Run("ask fails at end of input", func(t *testing.T) {
+		p, _ := newTestPrompter("")
+		if _, err := p.ask("Q", "x"); !errors.Is(err, io.EOF) {
+			t.Errorf("expected io.EOF, got %v", err)
+		}
+	})
+
+	t.Run("confirm retries until valid", func(t *testing.T) {
+		p, out := newTestPrompter("maybe\nYES\n\n")
+		if got, _ := p.confirm("Continue?", false); !got {
+			t.Error("confirm() = false, want true")
+		}
+		if got, _ := p.confirm("Continue?", false); got {
+			t.Error("confirm() = true, want default false")
+		}
+		if !strings.Contains(out.String(), "Please answer y or n.") {
+			t.Errorf("expected retry message, got %q", out.String())
+		}
+	})
+
+	t.Run("choose matches case-insensitively and retries", func(t *testing.T) {
+		p, out := newTestPrompter("xml\nJSON\n")
+		got, err := p.choose("Format", []string{"table", "json"}, "table")
+		if err != nil || got != "json" {
+			t.Errorf("choose() = %q, %v, want json", got, err)
+		}
+		if !strings.Contains(out.String(), "Please choose one of: table, json") {
+			t.Errorf("expected retry message, got %q", out.String())
+		}
+	})
+}
+
+func TestInitClientStep(t *testing.T) {
+	t.Run("uses environment client without prompting", func(t *testing.T) {
+		cmd, out, _ := setupInitTest(t, "")
+		t.Setenv(auth.EnvClientID, "env-id")
+
+		if err := initClientStep(cmd, newPrompter(cmd)); err != nil {
+			t.Fatalf("initClientStep failed: %v", err)
+		}
+		if !strings.Contains(out.String(), auth.EnvClientID) {
+			t.Errorf("expected environment message, got %q", out.String())
+		}
+		if _, err := os.Stat(auth.ClientFilePath()); !os.IsNotExist(err) {
+			t.Error("expected no client file to be written")
+		}
+	})
+
+	t.Run("saves client from --client-file", func(t *testing.T) {
+		cmd, _, dir := setupInitTest(t, "")
+		src := filepath.Join(dir, "download.json")
+		if err := os.WriteFile(src, []byte(initTestClientJSON), 0600); err != nil {
+			t.Fatalf("failed to write client JSON: %v", err)
+		}
+		initClientFile = src
+
+		if err := initClientStep(cmd, newPrompter(cmd)); err != nil {
+			t.Fatalf("initClientStep failed: %v", err)
+		}
+		creds, err := auth.LoadClientFile(auth.ClientFilePath())
+		if err != nil {
+			t.Fatalf("expected saved client file: %v", err)
+		}
+		if creds.ClientID != "123.apps.googleusercontent.com" {
+			t.Errorf("saved ClientID = %q", creds.ClientID)
+		}
+	})
+
+	t.Run("re-prompts after an invalid file and accepts pasted values", func(t *testing.T) {
+		cmd, out, dir := setupInitTest(t, "")
+		bad := filepath.Join(dir, "bad.json")
+		if err := os.WriteFile(bad, []byte(`{"type":"service_account"}`), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		cmd.SetIn(strings.NewReader(bad + "\n\npasted-id\npasted-secret\n"))
+
+		if err := initClientStep(cmd, newPrompter(cmd)); err != nil {
+			t.Fatalf("initClientStep failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "invalid OAuth client file") {
+			t.Errorf("expected validation error in output, got %q", out.String())
+		}
+		creds, err := auth.LoadClientFile(auth.ClientFilePath())
+		if err != nil || creds.ClientID != "pasted-id" || creds.ClientSecret != "pasted-secret" {
+			t.Errorf("saved client = %+v, %v", creds, err)
+		}
+	})
+
+	t.Run("keeps bundled client when accepted", func(t *testing.T) {
+		cmd, _, _ := setupInitTest(t, "\n")
+		auth.BundledClientID, auth.BundledClientSecret = "bundled-id", "bundled-secret"
+
+		if err := initClientStep(cmd, newPrompter(cmd)); err != nil {
+			t.Fatalf("initClientStep failed: %v", err)
+		}
+		if _, err := os.Stat(auth.ClientFilePath()); !os.IsNotExist(err) {
+			t.Error("expected no client file to be written")
+		}
+	})
+
+	t.Run("fails when input ends", func(t *testing.T) {
+		cmd, _, _ := setupInitTest(t, "")
+		if err := initClientStep(cmd, newPrompter(cmd)); !errors.Is(err, io.EOF) {
+			t.Errorf("expected io.EOF, got %v", err)
+		}
+	})
+}
+
+func TestRunInit_FirstRun(t *testing.T) {
+	// client ID, secret (pasted), alias, access level, format, check
+	cmd, out, _ := setupInitTest(t, "\nid\nsecret\nwork\nread-only\njson\nlist\n")
+
+	var addedAlias string
+	var addedScopes []string
+	msgRepo := &MockMessageRepository{
+		ListResult: &mail.ListResult[*mail.Message]{Items: []*mail.Message{{ID: "m1"}, {ID: "m2"}}},
+	}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "work", Email: "me@example.com"},
+			AddFunc: func(ctx context.Context, alias string, scopes []string) (*accountuc.Account, error) {
+				addedAlias, addedScopes = alias, scopes
+				return &accountuc.Account{Alias: alias, Email: "me@example.com", IsDefault: true}, nil
+			},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo},
+	})
+
+	if err := runInit(cmd, nil); err != nil {
+		t.Fatalf("runInit failed: %v\noutput:\n%s", err, out.String())
+	}
+
+	if addedAlias != "work" {
+		t.Errorf("added alias = %q, want work", addedAlias)
+	}
+	if len(addedScopes) == 0 || addedScopes[0] != auth.ScopeGmailReadonly {
+		t.Errorf("added scopes = %v, want read-only scopes", addedScopes)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.DefaultFormat != "json" {
+		t.Errorf("default_format = %q, want json", cfg.DefaultFormat)
+	}
+	for _, want := range []string{"Saved OAuth client", "Signed in as me@example.com", "2 recent inbox message(s)", "Setup complete"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunInit_ExistingAccounts(t *testing.T) {
+	// keep env client, skip sign-in, choose default account, format, send check
+	cmd, out, _ := setupInitTest(t, "n\npersonal\n\nsend\n")
+	t.Setenv(auth.EnvClientID, "env-id")
+
+	var switched string
+	msgRepo := &MockMessageRepository{SendResult: &mail.Message{ID: "sent-1"}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Accounts: []*accountuc.Account{
+				{Alias: "work", Email: "work@example.com", IsDefault: true},
+				{Alias: "personal", Email: "me@example.com"},
+			},
+			Account:      &accountuc.Account{Alias: "work", Email: "work@example.com"},
+			SwitchFunc:   func(alias string) error { switched = alias; return nil },
+			AddErr:       errors.New("add should not be called"),
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo},
+	})
+
+	if err := runInit(cmd, nil); err != nil {
+		t.Fatalf("runInit failed: %v\noutput:\n%s", err, out.String())
+	}
+	if switched != "personal" {
+		t.Errorf("switched default to %q, want personal", switched)
+	}
+	if accountFlag != "" {
+		t.Errorf("accountFlag = %q, want it restored after the check", accountFlag)
+	}
+	for _, want := range []string{"2 account(s) already signed in", "Default output format: table", "Sent a test message to work@example.com (ID sent-1)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunInit_SignInError(t *testing.T) {
+	cmd, _, _ := setupInitTest(t, "\n\nfull\n")
+	t.Setenv(auth.EnvClientID, "env-id")
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{AddErr: errors.New("access_denied")},
+		RepoFactory:    &MockRepositoryFactory{},
+	})
+
+	err := runInit(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "sign-in failed") {
+		t.Errorf("expected sign-in error, got %v", err)
+	}
+}
+
+func TestRunInit_CheckError(t *testing.T) {
+	cmd, _, _ := setupInitTest(t, "\n\n\n\nlist\n")
+	t.Setenv(auth.EnvClientID, "env-id")
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "default", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: &MockMessageRepository{ListErr: errors.New("API not enabled")}},
+	})
+
+	err := runInit(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "check failed") {
+		t.Errorf("expected check error, got %v", err)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if got := expandHome("~/Downloads/client.json"); got != filepath.Join(home, "Downloads", "client.json") {
+		t.Errorf("expandHome() = %q", got)
+	}
+	if got := expandHome("/tmp/client.json"); got != "/tmp/client.json" {
+		t.Errorf("expandHome() = %q, want unchanged", got)
+	}
+}
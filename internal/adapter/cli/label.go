@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
 )
@@ -182,7 +181,7 @@ func runLabelList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderLabels(labels)
 	cmd.Println(output)
@@ -211,7 +210,7 @@ func runLabelShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderLabel(label)
 	cmd.Println(output)
@@ -251,7 +250,7 @@ func runLabelCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderLabel(created))
@@ -321,7 +320,7 @@ func runLabelUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderLabel(updated))
@@ -0,0 +1,246 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Command flags for label report.
+var (
+	labelReportPruneEmpty bool
+	labelReportConfirm    bool
+)
+
+// labelReportCmd reports label usage and suggests cleanups.
+var labelReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show message counts per label and suggest cleanups",
+	Long: `Show the message and thread counts of every label and flag labels
+that may need cleaning up:
+
+  empty      - a user label with no messages and no nested labels
+  similar    - user labels whose names differ only in case, separators
+               or one typo, e.g. "Receipts" and "receipt"
+
+Use --prune-empty to delete the empty user labels. goog lists them and
+asks before deleting; --confirm skips the question. Messages are never
+deleted, and similar labels are only reported, since merging them needs
+a decision about which name to keep.
+
+The counts come from one request per label, so the report takes a
+moment for accounts with many labels.`,
+	Example: `  # Show label usage
+  goog label report
+
+  # Remove unused labels
+  goog label report --prune-empty
+
+  # Report as JSON
+  goog label report --format json`,
+	Args: cobra.NoArgs,
+	RunE: runLabelReport,
+}
+
+func init() {
+	labelCmd.AddCommand(labelReportCmd)
+
+	labelReportCmd.Flags().BoolVar(&labelReportPruneEmpty, "prune-empty", false, "delete empty user labels after confirmation")
+	labelReportCmd.Flags().BoolVar(&labelReportConfirm, "confirm", false, "delete without asking (with --prune-empty)")
+}
+
+// labelUsageJSON is the JSON representation of one label in the report.
+type labelUsageJSON struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	MessagesTotal  int      `json:"messages_total"`
+	MessagesUnread int      `json:"messages_unread"`
+	ThreadsTotal   int      `json:"threads_total"`
+	ThreadsUnread  int      `json:"threads_unread"`
+	Empty          bool     `json:"empty,omitempty"`
+	SimilarTo      []string `json:"similar_to,omitempty"`
+}
+
+// labelReportJSON is the JSON output of label report.
+type labelReportJSON struct {
+	Labels  []labelUsageJSON `json:"labels"`
+	Empty   []string         `json:"empty"`
+	Similar [][2]string      `json:"similar"`
+	Pruned  []string         `json:"pruned"`
+}
+
+// runLabelReport handles the label report command.
+func runLabelReport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labels, err := labelsWithCounts(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	report := buildLabelReport(labels)
+
+	if labelReportPruneEmpty && len(report.Empty) > 0 {
+		if report.Pruned, err = pruneEmptyLabels(ctx, cmd, repo, labels, report.Empty); err != nil {
+			return err
+		}
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode label report: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printLabelReport(cmd, report)
+	return nil
+}
+
+// labelsWithCounts lists the labels and fetches each one for its counts,
+// user labels first, each group sorted by name.
+func labelsWithCounts(ctx context.Context, repo LabelRepository) ([]*mail.Label, error) {
+	listed, err := repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	labels := make([]*mail.Label, 0, len(listed))
+	for _, l := range listed {
+		full, err := repo.Get(ctx, l.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get label %s: %w", l.Name, err)
+		}
+		labels = append(labels, full)
+	}
+	sort.SliceStable(labels, func(i, j int) bool {
+		if labels[i].IsUserLabel() != labels[j].IsUserLabel() {
+			return labels[i].IsUserLabel()
+		}
+		return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name)
+	})
+	return labels, nil
+}
+
+// buildLabelReport flags empty and similar user labels.
+func buildLabelReport(labels []*mail.Label) labelReportJSON {
+	report := labelReportJSON{
+		Labels:  make([]labelUsageJSON, len(labels)),
+		Empty:   []string{},
+		Similar: [][2]string{},
+		Pruned:  []string{},
+	}
+	for i, l := range labels {
+		report.Labels[i] = labelUsageJSON{
+			ID:             l.ID,
+			Name:           l.Name,
+			Type:           l.Type,
+			MessagesTotal:  l.MessagesTotal,
+			MessagesUnread: l.MessagesUnread,
+			ThreadsTotal:   l.ThreadsTotal,
+			ThreadsUnread:  l.ThreadsUnread,
+		}
+		if !l.IsUserLabel() {
+			continue
+		}
+		if l.MessagesTotal == 0 && !hasNestedLabel(l, labels) {
+			report.Labels[i].Empty = true
+			report.Empty = append(report.Empty, l.Name)
+		}
+		for j := i + 1; j < len(labels); j++ {
+			other := labels[j]
+			if other.IsUserLabel() && mail.SimilarLabelNames(l.Name, other.Name) {
+				report.Similar = append(report.Similar, [2]string{l.Name, other.Name})
+				report.Labels[i].SimilarTo = append(report.Labels[i].SimilarTo, other.Name)
+				report.Labels[j].SimilarTo = append(report.Labels[j].SimilarTo, l.Name)
+			}
+		}
+	}
+	return report
+}
+
+// hasNestedLabel reports whether any label is nested under l. Deleting
+// such a parent would break up the nesting, so it is never empty.
+func hasNestedLabel(l *mail.Label, labels []*mail.Label) bool {
+	for _, other := range labels {
+		if l.IsParentOf(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneEmptyLabels deletes the empty labels after listing them and asking
+// for confirmation, unless --confirm was given. It returns the names of
+// the deleted labels.
+func pruneEmptyLabels(ctx context.Context, cmd *cobra.Command, repo LabelRepository, labels []*mail.Label, empty []string) ([]string, error) {
+	if !labelReportConfirm {
+		cmd.Printf("Empty labels to delete (%d):\n", len(empty))
+		for _, name := range empty {
+			cmd.Printf("  - %s\n", name)
+		}
+		ok, err := newPrompter(cmd).confirm(i18n.Sprintf("Delete %d label(s)?", len(empty)), false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			cmd.Println("No labels deleted")
+			return []string{}, nil
+		}
+	}
+
+	ids := make(map[string]string, len(labels))
+	for _, l := range labels {
+		ids[l.Name] = l.ID
+	}
+	pruned := []string{}
+	for _, name := range empty {
+		if err := repo.Delete(ctx, ids[name]); err != nil {
+			return pruned, fmt.Errorf("failed to delete label %s: %w", name, err)
+		}
+		pruned = append(pruned, name)
+	}
+	return pruned, nil
+}
+
+// printLabelReport prints the report as a table followed by suggestions.
+func printLabelReport(cmd *cobra.Command, report labelReportJSON) {
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "NAME\tTYPE\tMESSAGES\tUNREAD\tTHREADS\tNOTE")
+	for _, l := range report.Labels {
+		var notes []string
+		if l.Empty {
+			notes = append(notes, "empty")
+		}
+		if len(l.SimilarTo) > 0 {
+			notes = append(notes, "similar to "+strings.Join(l.SimilarTo, ", "))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", l.Name, l.Type, l.MessagesTotal, l.MessagesUnread, l.ThreadsTotal, strings.Join(notes, "; "))
+	}
+	_ = w.Flush()
+
+	if len(report.Pruned) > 0 {
+		cmd.Printf("\nDeleted %d empty label(s): %s\n", len(report.Pruned), strings.Join(report.Pruned, ", "))
+	} else if len(report.Empty) > 0 && !labelReportPruneEmpty {
+		cmd.Printf("\n%d empty label(s); run 'goog label report --prune-empty' to delete them\n", len(report.Empty))
+	}
+	if len(report.Similar) > 0 {
+		cmd.Printf("\n%d pair(s) of similar labels; consider merging:\n", len(report.Similar))
+		for _, pair := range report.Similar {
+			cmd.Printf("  %s ~ %s\n", pair[0], pair[1])
+		}
+	}
+}
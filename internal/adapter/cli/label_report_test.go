@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// countedLabelRepository returns labels by ID and records deletions.
+type countedLabelRepository struct {
+	MockLabelRepository
+	Deleted []string
+}
+
+func (m *countedLabelRepository) Get(ctx context.Context, id string) (*mail.Label, error) {
+	for _, l := range m.Labels {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, mail.ErrLabelNotFound
+}
+
+func (m *countedLabelRepository) Delete(ctx context.Context, id string) error {
+	m.Deleted = append(m.Deleted, id)
+	return nil
+}
+
+// setupLabelReportTest injects a label repository and resets the report
+// flags.
+func setupLabelReportTest(t *testing.T, repo LabelRepository, input string) (*bytes.Buffer, *cobra.Command) {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{LabelRepo: repo},
+	})
+
+	origPrune, origConfirm, origFormat := labelReportPruneEmpty, labelReportConfirm, formatFlag
+	labelReportPruneEmpty, labelReportConfirm, formatFlag = false, false, "plain"
+	t.Cleanup(func() {
+		ResetDependencies()
+		labelReportPruneEmpty, labelReportConfirm, formatFlag = origPrune, origConfirm, origFormat
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetIn(strings.NewReader(input))
+	return buf, cmd
+}
+
+func reportLabels() *countedLabelRepository {
+	return &countedLabelRepository{MockLabelRepository: MockLabelRepository{Labels: []*mail.Label{
+		{ID: "INBOX", Name: "INBOX", Type: mail.LabelTypeSystem, MessagesTotal: 120},
+		{ID: "Label_1", Name: "Receipts", Type: mail.LabelTypeUser, MessagesTotal: 40, ThreadsTotal: 30},
+		{ID: "Label_2", Name: "receipt", Type: mail.LabelTypeUser, MessagesTotal: 3, ThreadsTotal: 3},
+		{ID: "Label_3", Name: "Old", Type: mail.LabelTypeUser},
+		{ID: "Label_4", Name: "Projects", Type: mail.LabelTypeUser},
+		{ID: "Label_5", Name: "Projects/Alpha", Type: mail.LabelTypeUser, MessagesTotal: 7},
+	}}}
+}
+
+func TestRunLabelReport(t *testing.T) {
+	repo := reportLabels()
+	buf, cmd := setupLabelReportTest(t, repo, "")
+	formatFlag = "json"
+
+	if err := runLabelReport(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out labelReportJSON
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	// The empty parent of Projects/Alpha is kept
+	if !slices.Equal(out.Empty, []string{"Old"}) {
+		t.Errorf("empty = %v, want [Old]", out.Empty)
+	}
+	if len(out.Similar) != 1 || out.Similar[0] != [2]string{"receipt", "Receipts"} {
+		t.Errorf("similar = %v", out.Similar)
+	}
+	if out.Labels[len(out.Labels)-1].Name != "INBOX" {
+		t.Errorf("expected system labels last, got %+v", out.Labels)
+	}
+	if len(repo.Deleted) != 0 {
+		t.Errorf("nothing should be deleted without --prune-empty, got %v", repo.Deleted)
+	}
+}
+
+func TestRunLabelReport_PruneEmpty(t *testing.T) {
+	t.Run("deletes after confirmation", func(t *testing.T) {
+		repo := reportLabels()
+		buf, cmd := setupLabelReportTest(t, repo, "y\n")
+		labelReportPruneEmpty = true
+
+		if err := runLabelReport(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(repo.Deleted, []string{"Label_3"}) {
+			t.Errorf("deleted = %v, want [Label_3]", repo.Deleted)
+		}
+		if !contains(buf.String(), "Deleted 1 empty label(s): Old") {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("keeps labels when declined", func(t *testing.T) {
+		repo := reportLabels()
+		buf, cmd := setupLabelReportTest(t, repo, "n\n")
+		labelReportPruneEmpty = true
+
+		if err := runLabelReport(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repo.Deleted) != 0 || !contains(buf.String(), "No labels deleted") {
+			t.Errorf("deleted = %v, output:\n%s", repo.Deleted, buf.String())
+		}
+	})
+}
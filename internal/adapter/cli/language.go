@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+)
+
+// localizedUsageTemplate is cobra's default usage template with its
+// headings translated.
+const localizedUsageTemplate = `{{T "Usage:"}}{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+{{T "Aliases:"}}
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+{{T "Examples:"}}
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+
+{{T "Available Commands:"}}{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
+
+{{.Title}}{{range $cmds}}{{if (and (eq .GroupID $group.ID) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if not .AllChildCommandsHaveGroup}}
+
+{{T "Additional Commands:"}}{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+{{T "Flags:"}}
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+{{T "Global Flags:"}}
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+{{T "Additional help topics:"}}{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+{{Tf "Use \"%s [command] --help\" for more information about a command." .CommandPath}}{{end}}
+`
+
+// setupLanguage selects the language of help, prompts, warnings and
+// error messages from GOOG_LANG or the locale, and translates the command
+// tree into it. A language without a catalog falls back to English, with
+// a warning when it was asked for by GOOG_LANG.
+func setupLanguage() {
+	lang, from := i18n.Detect(os.Getenv)
+	if err := i18n.SetLanguage(lang); err != nil {
+		if from == "GOOG_LANG" {
+			fmt.Fprintf(rootCmd.ErrOrStderr(), "Warning: ignoring GOOG_LANG: %v\n", err)
+		}
+		return
+	}
+	if i18n.Language() != i18n.English {
+		localizeCommands(rootCmd)
+	}
+}
+
+// localizeCommands translates the short descriptions and flag usages of
+// root and its subcommands, and the headings of their help.
+func localizeCommands(root *cobra.Command) {
+	cobra.AddTemplateFunc("T", i18n.T)
+	cobra.AddTemplateFunc("Tf", i18n.Sprintf)
+	root.SetUsageTemplate(localizedUsageTemplate)
+
+	// Cobra adds its help and completion commands when it runs; add them
+	// now so they are translated too.
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	seen := make(map[*pflag.Flag]bool)
+	localizeFlag := func(f *pflag.Flag) {
+		if !seen[f] {
+			seen[f] = true
+			f.Usage = i18n.T(f.Usage)
+		}
+	}
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		c.Short = i18n.T(c.Short)
+		c.PersistentFlags().VisitAll(localizeFlag)
+		c.LocalNonPersistentFlags().VisitAll(localizeFlag)
+		if c.Flags().Lookup("help") == nil {
+			c.Flags().BoolP("help", "h", false, i18n.Sprintf("help for %s", c.DisplayName()))
+			_ = c.Flags().SetAnnotation("help", cobra.FlagSetByCobraAnnotation, []string{"true"})
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
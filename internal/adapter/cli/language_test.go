@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+)
+
+// useLanguage shows messages in lang for the rest of the test.
+func useLanguage(t *testing.T, lang string) {
+	t.Helper()
+	if err := i18n.SetLanguage(lang); err != nil {
+		t.Fatalf("SetLanguage(%s) error = %v", lang, err)
+	}
+	t.Cleanup(func() { _ = i18n.SetLanguage(i18n.English) })
+}
+
+func TestLocalizeCommands(t *testing.T) {
+	useLanguage(t, "de")
+
+	root := &cobra.Command{Use: "goog", Short: "CLI for Google Mail, Calendar, and Tasks"}
+	var quiet bool
+	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "print only IDs and errors")
+	mail := &cobra.Command{Use: "mail", Short: "Manage Gmail messages", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(mail)
+	localizeCommands(root)
+
+	if mail.Short != "Gmail-Nachrichten verwalten" {
+		t.Errorf("mail Short = %q, want it translated", mail.Short)
+	}
+	if help := mail.Flags().Lookup("help"); help == nil || help.Usage != "Hilfe zu mail" {
+		t.Errorf("mail --help = %+v, want a translated usage", help)
+	}
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"--help"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, want := range []string{
+		"Aufruf:",
+		"Verfügbare Befehle:",
+		"Gmail-Nachrichten verwalten",
+		"Optionen:",
+		"nur IDs und Fehler ausgeben",
+		`"goog [Befehl] --help" zeigt mehr über einen Befehl.`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPrompter_Localized(t *testing.T) {
+	useLanguage(t, "de")
+
+	cmd := &cobra.Command{Use: "test"}
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader("vielleicht\nja\nyes\nNEIN\n"))
+	cmd.SetOut(&out)
+	p := newPrompter(cmd)
+
+	for _, want := range []bool{true, true, false} {
+		if got, err := p.confirm("Sign in another account?", false); err != nil || got != want {
+			t.Errorf("confirm() = %v, %v; want %v", got, err, want)
+		}
+	}
+	for _, want := range []string{"Ein weiteres Konto anmelden? (j/N): ", "Bitte mit j oder n antworten."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q: %q", want, out.String())
+		}
+	}
+}
+
+func TestPrintError_Localized(t *testing.T) {
+	useLanguage(t, "es")
+	origFormat := formatFlag
+	t.Cleanup(func() { formatFlag = origFormat })
+
+	err := &repository.APIError{
+		Service: "gmail",
+		Status:  400,
+		Message: "Invalid id value",
+		Hint:    "Google rejected the request; check the IDs, dates and other values passed to the command.",
+		Err:     repository.ErrBadRequest,
+	}
+
+	formatFlag = "table"
+	var buf bytes.Buffer
+	printError(&buf, err, false)
+	if !strings.HasPrefix(buf.String(), "Error: ") || !strings.Contains(buf.String(), "Sugerencia: Google rechazó la petición") {
+		t.Errorf("text error = %q, want a translated hint", buf.String())
+	}
+
+	formatFlag = "json"
+	buf.Reset()
+	printError(&buf, err, false)
+	if !strings.Contains(buf.String(), `"hint": "Google rejected the request`) {
+		t.Errorf("JSON error = %q, want the English hint", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+func TestListCommands_UniformFlags(t *testing.T) {
+	commands := map[string]*cobra.Command{
+		"mail list":   mailListCmd,
+		"mail search": mailSearchCmd,
+		"draft list":  draftListCmd,
+		"thread list": threadListCmd,
+	}
+	for name, cmd := range commands {
+		for _, flag := range []string{"max-results", "page-token", "include-spam-trash"} {
+			if cmd.Flags().Lookup(flag) == nil {
+				t.Errorf("%s has no --%s", name, flag)
+			}
+		}
+		if name != "draft list" && cmd.Flags().Lookup("labels") == nil {
+			t.Errorf("%s has no --labels", name)
+		}
+	}
+
+	if draftListCmd.Flags().Lookup("limit") != draftListCmd.Flags().Lookup("max-results") {
+		t.Error("draft list --limit should remain as the older name of --max-results")
+	}
+}
+
+func TestDraftList_LimitAlias(t *testing.T) {
+	origLimit := draftLimit
+	t.Cleanup(func() {
+		draftLimit = origLimit
+		draftListCmd.Flags().VisitAll(func(f *pflag.Flag) { f.Changed = false })
+	})
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"--limit", "5"}, 5},
+		{[]string{"--limit", "5", "--max-results", "7"}, 7},
+		{[]string{"--max-results", "7", "--limit", "5"}, 5},
+	}
+	for _, tt := range tests {
+		draftLimit = 20
+		if err := draftListCmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("ParseFlags(%v) error = %v", tt.args, err)
+		}
+		// Both names are one flag, so the last value given wins.
+		limit, maxResults := draftListCmd.Flags().Lookup("limit"), draftListCmd.Flags().Lookup("max-results")
+		if draftLimit != tt.want || limit != maxResults || maxResults.Value.String() != strconv.Itoa(tt.want) {
+			t.Errorf("%v set the limit to %d (flag %s), want %d", tt.args, draftLimit, maxResults.Value, tt.want)
+		}
+	}
+	if usage := draftListCmd.Flags().FlagUsages(); strings.Contains(usage, "--limit") {
+		t.Errorf("help lists --limit:\n%s", usage)
+	}
+}
+
+func TestRunMailSearch_ListOptions(t *testing.T) {
+	repo := &MockMessageRepository{Messages: []*mail.Message{{ID: "msg1"}}}
+	setupEnvelopeTest(t, repo)
+	envelopeFlag = false
+	origLabels, origSpamTrash := mailSearchLabels, mailIncludeSpamTrash
+	t.Cleanup(func() { mailSearchLabels, mailIncludeSpamTrash = origLabels, origSpamTrash })
+	mailSearchLabels, mailIncludeSpamTrash = []string{"Label_1"}, true
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(&bytes.Buffer{})
+	if err := runMailSearch(cmd, []string{"invoice"}); err != nil {
+		t.Fatalf("runMailSearch() error = %v", err)
+	}
+	if !slices.Equal(repo.SearchOpts.LabelIDs, []string{"Label_1"}) || !repo.SearchOpts.IncludeSpamTrash {
+		t.Errorf("streamed search options = %+v", repo.SearchOpts)
+	}
+
+	// A single page gets the same options
+	repo.SearchOpts = mail.ListOptions{}
+	mailPageToken = "page-2"
+	if err := runMailSearch(cmd, []string{"invoice"}); err != nil {
+		t.Fatalf("runMailSearch() error = %v", err)
+	}
+	if !slices.Equal(repo.SearchOpts.LabelIDs, []string{"Label_1"}) || !repo.SearchOpts.IncludeSpamTrash || repo.SearchOpts.PageToken != "page-2" {
+		t.Errorf("paged search options = %+v", repo.SearchOpts)
+	}
+}
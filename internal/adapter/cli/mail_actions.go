@@ -4,8 +4,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
@@ -23,10 +26,32 @@ var (
 	mailListMaxResults     int
 	mailListLabels         []string
 	mailListUnreadOnly     bool
+	mailListIncludeMuted   bool
+	mailListStrict         bool
 	mailSearchMaxResults   int
+	mailSearchLimit        int
+	mailSearchAll          bool
+	mailSearchStrict       bool
 	mailMoveDestination    string
+	mailAfter              string
+	mailBefore             string
+	mailShowImportance     bool
+	mailReadTranslate      string
+	mailReadHighlight      string
+	mailReadStructure      bool
+	mailReadQR             bool
+	mailSearchHighlight    bool
+	mailColumns            []string
+	mailRelativeDates      bool
+	mailPageToken          string
+	mailIncludeSpamTrash   bool
+	mailSearchLabels       []string
 )
 
+// searchPageSize is the page size mail search fetches with when --limit is
+// given without --max-results.
+const searchPageSize = 100
+
 // mailCmd represents the mail command group.
 var mailCmd = &cobra.Command{
 	Use:   "mail",
@@ -217,8 +242,17 @@ var mailListCmd = &cobra.Command{
 	Long: `List messages from your Gmail inbox.
 
 By default, lists messages from the INBOX label. Use --labels
-to filter by specific labels and --unread-only to show only
-unread messages.`,
+to filter by specific labels, --unread-only to show only
+unread messages, and --after/--before to limit the date range.
+Messages in muted threads are left out unless --include-muted is
+given or the mute label is listed.
+
+Inside a directory with a project context (see 'goog context'), the
+messages with the project's label and view are listed instead of the
+inbox unless --labels is given.
+
+With --format json --envelope the messages are wrapped in an object
+with the next_page_token to pass to --page-token for the next page.`,
 	Example: `  # List recent inbox messages
   goog mail list
 
@@ -232,7 +266,21 @@ unread messages.`,
   goog mail list --format json
 
   # List more messages
-  goog mail list --max-results 50`,
+  goog mail list --max-results 50
+
+  # List messages received since yesterday
+  goog mail list --after yesterday
+
+  # List important messages with a marker column
+  goog mail list --filter important --importance
+
+  # Group messages by conversation, then show the third thread
+  goog mail list --threaded
+  goog mail show thread:3
+
+  # Page through the inbox in a script
+  goog mail list --format json --envelope > page.json
+  goog mail list --format json --envelope --page-token "$(jq -r .next_page_token page.json)"`,
 	Aliases: []string{"ls"},
 	RunE:    runMailList,
 }
@@ -244,7 +292,31 @@ var mailReadCmd = &cobra.Command{
 	Long: `Read and display a single email message.
 
 Retrieves the full content of the specified message including
-headers, body, and metadata.`,
+headers, body, and metadata.
+
+In place of a message ID, thread:N shows the Nth thread of the last
+'goog mail list --threaded' or 'goog mail search --threaded' listing.
+
+--translate shows the subject and body translated into another language,
+with the detected source language in the header. It uses Google Cloud
+Translation and needs an API key in mail.translate_api_key or
+GOOG_TRANSLATE_API_KEY; the message text is sent to that service.
+
+--highlight colours the given words and "quoted phrases" wherever they
+appear in the message, including the body of plain output. Colour is
+only added when output is a terminal and NO_COLOR is unset.
+
+--structure prints the MIME part tree of the raw message instead: each
+part's section number, content type, transfer encoding, decoded size,
+filename and content ID, with the parts goog shows as the plain-text and
+HTML body marked.
+
+A meeting invitation in the message is shown after its fields: the
+event, its time, organizer and guests, and your answer as Google
+Calendar holds it. Answer it with 'goog mail rsvp'.
+
+--qr adds the message's Gmail link as a QR code after it, to scan it
+onto a phone. The link selects the account as 'goog mail open' does.`,
 	Example: `  # Read a message by ID
   goog mail read 18abc123def456
 
@@ -252,7 +324,22 @@ headers, body, and metadata.`,
   goog mail read 18abc123def456 --format json
 
   # Read with plain text output
-  goog mail read 18abc123def456 --format plain`,
+  goog mail read 18abc123def456 --format plain
+
+  # Show the third thread of the last threaded listing
+  goog mail show thread:3
+
+  # Translate a message into English
+  goog mail show 18abc123def456 --translate en
+
+  # Colour words in the body
+  goog mail show 18abc123def456 --format plain --highlight 'invoice "due date"'
+
+  # Show how the message is built from MIME parts
+  goog mail show 18abc123def456 --structure
+
+  # Add a QR code to open the message on a phone
+  goog mail show 18abc123def456 --qr`,
 	Aliases: []string{"get", "show"},
 	Args:    cobra.ExactArgs(1),
 	RunE:    runMailRead,
@@ -272,7 +359,42 @@ Common operators include:
   - is:unread, is:starred, is:important
   - has:attachment
   - after:YYYY/MM/DD, before:YYYY/MM/DD
-  - label:labelname`,
+  - label:labelname
+
+--after and --before add date limits using goog's date expressions
+("yesterday", "-3d", "last monday 9am", "2025-08-01") and are
+combined with the query.
+
+Table, plain, JSON and quiet output is printed as each message is
+fetched, so long searches show results straight away and memory use
+stays flat. Without --limit one page of --max-results messages is read;
+with --limit, pages are followed until that many messages have been
+printed (after --filter) or the results run out, and with --all until
+they run out. Fetching also stops when the output pipe is closed, e.g.
+by head. --sort and --threaded need every message first, so they are
+printed at the end and hold every message in memory.
+
+When mail.search_cache_ttl is set, the results of a search are cached
+for that long, and running the same search again replays them: only
+messages whose labels changed are fetched again, and deleted ones are
+left out. A message that no longer matches because its labels changed
+may still be shown until the cache expires. The cache is skipped once
+new mail has arrived, and --no-cache runs the search again.
+
+--highlight colours the words of the query in senders, subjects and
+thread snippets. Operators such as from: and is: are not highlighted,
+but the value of subject: is. Colour is only added when output is a
+terminal and NO_COLOR is unset.
+
+--labels keeps only messages with all of the given label IDs, and
+--include-spam-trash also searches Spam and Trash, which Gmail leaves
+out otherwise.
+
+--page-token, or --format json --envelope, reads a single page of
+--max-results messages, so a script can page through the results one
+call at a time: the envelope holds the next_page_token for the next
+call. Neither can be used with --limit or --all, and the page is not
+cached.`,
 	Example: `  # Search for unread messages
   goog mail search "is:unread"
 
@@ -286,7 +408,22 @@ Common operators include:
   goog mail search "from:boss@company.com is:unread after:2024/01/01"
 
   # Search with JSON output
-  goog mail search "has:attachment" --format json`,
+  goog mail search "has:attachment" --format json
+
+  # Export every matching message as JSON
+  goog mail search "label:archive-2019" --all --format json > archive-2019.json
+
+  # Search the last three days
+  goog mail search "from:boss@company.com" --after -3d
+
+  # The first 500 matches, printed as they arrive
+  goog mail search "has:attachment larger:5M" --limit 500
+
+  # Colour the search words in the results
+  goog mail search invoice --highlight
+
+  # Stop fetching once head has read enough
+  goog mail search "label:receipts" --limit 10000 --format plain | head -20`,
 	Aliases: []string{"find", "query"},
 	Args:    cobra.ExactArgs(1),
 	RunE:    runMailSearch,
@@ -309,9 +446,41 @@ func init() {
 	mailListCmd.Flags().IntVar(&mailListMaxResults, "max-results", 10, "maximum number of messages to return")
 	mailListCmd.Flags().StringSliceVar(&mailListLabels, "labels", []string{"INBOX"}, "filter by labels")
 	mailListCmd.Flags().BoolVar(&mailListUnreadOnly, "unread-only", false, "show only unread messages")
+	mailListCmd.Flags().BoolVar(&mailListIncludeMuted, "include-muted", false, "also list messages in muted threads")
+	mailListCmd.Flags().BoolVar(&mailListStrict, "strict", false, "exit with an error when some messages could not be fetched in full")
+	mailListCmd.Flags().StringVar(&mailAfter, "after", "", "only messages after this date (e.g. yesterday, -3d, 2025-08-01)")
+	mailListCmd.Flags().StringVar(&mailBefore, "before", "", "only messages before this date (e.g. today, -1w, 2025-08-15)")
 
 	// Search command flags
-	mailSearchCmd.Flags().IntVar(&mailSearchMaxResults, "max-results", 10, "maximum number of messages to return")
+	mailSearchCmd.Flags().IntVar(&mailSearchMaxResults, "max-results", 10, "maximum number of messages to return, or the page size with --limit")
+	mailSearchCmd.Flags().IntVar(&mailSearchLimit, "limit", 0, "stop after printing this many messages, fetching as many pages as needed")
+	mailSearchCmd.Flags().BoolVar(&mailSearchAll, "all", false, "print every matching message, fetching pages until the results run out")
+	mailSearchCmd.Flags().BoolVar(&mailSearchStrict, "strict", false, "exit with an error when some messages could not be fetched in full")
+	mailSearchCmd.MarkFlagsMutuallyExclusive("all", "limit")
+	mailSearchCmd.Flags().StringVar(&mailAfter, "after", "", "only messages after this date (e.g. yesterday, -3d, 2025-08-01)")
+	mailSearchCmd.Flags().StringVar(&mailBefore, "before", "", "only messages before this date (e.g. today, -1w, 2025-08-15)")
+	mailListCmd.Flags().StringVar(&mailPageToken, "page-token", "", "token of the page to list, from next_page_token of --envelope output")
+	mailSearchCmd.Flags().StringVar(&mailPageToken, "page-token", "", "token of the page to search, from next_page_token of --envelope output")
+	mailSearchCmd.Flags().StringSliceVar(&mailSearchLabels, "labels", nil, "only messages with all of these label IDs")
+	mailListCmd.Flags().BoolVar(&mailIncludeSpamTrash, "include-spam-trash", false, "also list messages in SPAM and TRASH")
+	mailSearchCmd.Flags().BoolVar(&mailIncludeSpamTrash, "include-spam-trash", false, "also search messages in SPAM and TRASH")
+	mailListCmd.Flags().BoolVar(&mailShowImportance, "importance", false, "add a column marking important messages (table output)")
+	mailSearchCmd.Flags().BoolVar(&mailShowImportance, "importance", false, "add a column marking important messages (table output)")
+	mailListCmd.Flags().BoolVar(&mailThreaded, "threaded", false, "group messages by thread with unread counts")
+	mailSearchCmd.Flags().BoolVar(&mailThreaded, "threaded", false, "group messages by thread with unread counts")
+	mailSearchCmd.Flags().BoolVar(&mailSearchNoCache, "no-cache", false, "run the search again instead of using cached results (see mail.search_cache_ttl)")
+	mailSearchCmd.Flags().BoolVar(&mailSearchHighlight, "highlight", false, "colour the query terms in the results (terminal output)")
+	columnsUsage := "message table columns, e.g. id,from_name,from_email,subject (" + strings.Join(presenter.MessageColumnNames(), ", ") + ")"
+	mailListCmd.Flags().StringSliceVar(&mailColumns, "columns", nil, columnsUsage)
+	mailSearchCmd.Flags().StringSliceVar(&mailColumns, "columns", nil, columnsUsage)
+	mailListCmd.Flags().BoolVar(&mailRelativeDates, "relative-dates", false, "show message dates relative to now, e.g. 2h ago (table and plain output)")
+	mailSearchCmd.Flags().BoolVar(&mailRelativeDates, "relative-dates", false, "show message dates relative to now, e.g. 2h ago (table and plain output)")
+
+	// Read flags
+	mailReadCmd.Flags().StringVar(&mailReadTranslate, "translate", "", "translate the message into this language (e.g. en, de, ja)")
+	mailReadCmd.Flags().BoolVar(&mailReadStructure, "structure", false, "print the MIME part tree of the raw message")
+	mailReadCmd.Flags().StringVar(&mailReadHighlight, "highlight", "", "colour these words or \"phrases\" in the message (terminal output)")
+	mailReadCmd.Flags().BoolVar(&mailReadQR, "qr", false, "also print the message's Gmail link as a QR code")
 
 	// Delete flags
 	mailDeleteCmd.Flags().BoolVar(&mailDeleteConfirm, "confirm", false, "confirm permanent deletion")
@@ -353,59 +522,123 @@ func getGmailRepository(ctx context.Context) (*repository.GmailRepository, strin
 // runMailList handles the mail list command.
 func runMailList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+	if err := presenter.SetMessageColumns(mailColumns); err != nil {
+		return err
+	}
+	presenter.SetRelativeDates(mailRelativeDates)
 
 	// Get message repository using dependency injection
-	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Build list options
+	// Build list options; a directory's context replaces the labels
+	labels := mailListLabels
+	if mailListContext != "" {
+		labels = nil
+	}
 	opts := mail.ListOptions{
-		MaxResults: mailListMaxResults,
-		LabelIDs:   mailListLabels,
+		MaxResults:       mailListMaxResults,
+		PageToken:        mailPageToken,
+		LabelIDs:         labels,
+		Query:            mailListContext,
+		IncludeSpamTrash: mailIncludeSpamTrash,
 	}
 
 	// Add unread filter if requested
 	if mailListUnreadOnly {
-		opts.Query = "is:unread"
+		opts.Query = strings.TrimSpace(opts.Query + " is:unread")
 	}
 
+	// Add date limits if requested
+	dateQuery, err := mailDateQuery(mailAfter, mailBefore)
+	if err != nil {
+		return err
+	}
+	opts.Query = strings.TrimSpace(opts.Query + " " + dateQuery)
+	opts.Query = strings.TrimSpace(opts.Query + " " + mutedExclusion(labels, mailListIncludeMuted))
+
 	// List messages
 	result, err := repo.List(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to list messages: %w", err)
 	}
 
+	page := messagePage(result)
+	if mailThreaded {
+		if err := renderThreadedMessages(cmd, result.Items, email, page); err != nil {
+			return err
+		}
+		return output.Incomplete(cmd, "message(s)", result.Warnings, mailListStrict)
+	}
+
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
+	presenter.SetImportanceColumn(mailShowImportance)
 
 	// Output result
-	output := p.RenderMessages(result.Items)
-	cmd.Println(output)
+	printList(cmd, p.RenderMessages(result.Items), page)
 
-	return nil
+	return output.Incomplete(cmd, "message(s)", result.Warnings, mailListStrict)
 }
 
 // runMailRead handles the mail read command.
 func runMailRead(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	messageID := args[0]
+	setHighlight(mail.QueryTerms(mailReadHighlight))
+
+	if mailReadStructure && mailReadTranslate != "" {
+		return fmt.Errorf("--structure and --translate cannot be used together")
+	}
+	if mailReadStructure && mailReadQR {
+		return fmt.Errorf("--structure and --qr cannot be used together")
+	}
+	if err := checkQRFormat(mailReadQR); err != nil {
+		return err
+	}
+
+	if position, ok, err := parseThreadRef(messageID); ok {
+		if err != nil {
+			return err
+		}
+		if mailReadStructure || mailReadQR {
+			return fmt.Errorf("--structure and --qr need a message ID, not a thread")
+		}
+		return runMailShowThread(cmd, position)
+	}
 
 	// Get message repository using dependency injection
-	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
 	if err != nil {
 		return err
 	}
 
+	if mailReadStructure {
+		return showMessageStructure(ctx, cmd, repo, messageID)
+	}
+
 	// Get the message
 	msg, err := repo.Get(ctx, messageID)
 	if err != nil {
 		return fmt.Errorf("failed to read message: %w", err)
 	}
+	showInvitation(ctx, cmd, msg, email)
+
+	if mailReadTranslate != "" {
+		translator, err := getTranslatorFromDeps(ctx)
+		if err != nil {
+			return err
+		}
+		msg.Translation, err = mail.TranslateMessage(ctx, translator, msg.Subject, exportBody(msg), mailReadTranslate)
+		if err != nil {
+			return fmt.Errorf("failed to translate message: %w", err)
+		}
+	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	// Output result
 	output := p.RenderMessage(msg)
@@ -414,51 +647,234 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	// For plain format, also show the body content
 	if formatFlag == "plain" && msg.Body != "" {
 		cmd.Println("\n--- Message Body ---")
-		cmd.Println(msg.Body)
+		cmd.Println(presenter.Highlight(msg.Body))
+	}
+
+	if msg.Translation != nil && formatFlag != presenter.FormatJSON {
+		cmd.Printf("\n--- Translated Body (%s) ---\n", presenter.FormatTranslationLanguages(msg.Translation))
+		cmd.Println(msg.Translation.Body)
+	}
+
+	if mailReadQR {
+		cmd.Println()
+		return printQR(cmd, gmailPermalink(browserUser(), msg.ID))
 	}
 
 	return nil
 }
 
+// setHighlight sets the terms coloured in message output. Only table and
+// plain output to a terminal is coloured; elsewhere the escape codes would
+// end up in files and scripts. Accessible output marks the terms in text,
+// which needs a terminal but not color.
+func setHighlight(terms []string) {
+	marked := colorEnabled(os.Stdout) || (presenter.Accessible() && isTerminal(os.Stdout))
+	if (formatFlag != presenter.FormatTable && formatFlag != presenter.FormatPlain) || !marked {
+		terms = nil
+	}
+	presenter.SetHighlightTerms(terms)
+}
+
 // runMailSearch handles the mail search command.
 func runMailSearch(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	query := args[0]
+
+	// Add date limits if requested
+	dateQuery, err := mailDateQuery(mailAfter, mailBefore)
+	if err != nil {
+		return err
+	}
+	query := strings.TrimSpace(args[0] + " " + dateQuery)
 
 	// Get message repository using dependency injection
-	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Build search options
+	// One page is read when a script pages explicitly
+	if mailPageToken != "" || (envelopeFlag && formatFlag == presenter.FormatJSON) {
+		if mailSearchLimit > 0 || mailSearchAll {
+			return fmt.Errorf("--page-token and --envelope cannot be used with --limit or --all")
+		}
+		opts := mail.ListOptions{
+			MaxResults:       mailSearchMaxResults,
+			PageToken:        mailPageToken,
+			LabelIDs:         mailSearchLabels,
+			IncludeSpamTrash: mailIncludeSpamTrash,
+		}
+		return searchMessagePage(ctx, cmd, repo, email, query, opts)
+	}
+
+	// With --limit, pages are followed until enough messages are shown,
+	// and with --all until there are no more
+	pageSize := mailSearchMaxResults
+	if !cmd.Flags().Changed("max-results") {
+		if mailSearchAll {
+			pageSize = searchPageSize
+		} else if mailSearchLimit > 0 {
+			pageSize = min(mailSearchLimit, searchPageSize)
+		}
+	}
 	opts := mail.ListOptions{
-		MaxResults: mailSearchMaxResults,
+		MaxResults:       pageSize,
+		LabelIDs:         mailSearchLabels,
+		IncludeSpamTrash: mailIncludeSpamTrash,
+	}
+	presenter.SetImportanceColumn(mailShowImportance)
+	if err := presenter.SetMessageColumns(mailColumns); err != nil {
+		return err
+	}
+	presenter.SetRelativeDates(mailRelativeDates)
+	if mailSearchHighlight {
+		setHighlight(mail.QueryTerms(args[0]))
+	} else {
+		setHighlight(nil)
 	}
 
-	// Search messages
-	result, err := repo.Search(ctx, query, opts)
+	// Messages are printed as they arrive unless the output needs all of them
+	var stream *presenter.MessageStream
+	if !mailThreaded && listOptions.Sort == "" {
+		stream, _ = presenter.NewMessageStream(cmd.OutOrStdout(), output.Renderer(formatFlag))
+	}
+	stopPipe := catchBrokenPipe()
+	defer stopPipe()
+
+	var msgs []*mail.Message
+	fetched, closed := 0, false
+	total, warnings, err := cachedSearchEach(ctx, cmd, repo, email, query, opts, func(msg *mail.Message) error {
+		fetched++
+		kept := []*mail.Message{msg}
+		if !mailThreaded {
+			// Threads are filtered once grouped, by thread fields
+			var err error
+			if kept, err = listOptions.ApplyToMessages(kept); err != nil {
+				return err
+			}
+		}
+		for _, msg := range kept {
+			if stream == nil {
+				msgs = append(msgs, msg)
+			} else if err := stream.Write(msg); err != nil {
+				if !isBrokenPipe(err) {
+					return err
+				}
+				// Stop with the error, so a search cut short is not cached
+				closed = true
+				return err
+			}
+		}
+		shown := len(msgs)
+		if stream != nil {
+			shown = stream.Count()
+		}
+		if mailSearchAll {
+			return nil
+		}
+		if (mailSearchLimit > 0 && shown >= mailSearchLimit) || (mailSearchLimit == 0 && fetched >= mailSearchMaxResults) {
+			return mail.ErrStopSearch
+		}
+		return nil
+	})
+	if closed {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to search messages: %w", err)
 	}
 
-	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
-
-	// Output result
-	output := p.RenderMessages(result.Items)
-	cmd.Println(output)
+	shown := len(msgs)
+	if stream != nil {
+		if err := stream.Close(); err != nil {
+			if isBrokenPipe(err) {
+				return nil
+			}
+			return err
+		}
+		shown = stream.Count()
+	} else if mailThreaded {
+		if err := renderThreadedMessages(cmd, msgs, email, presenter.Page{}); err != nil {
+			return err
+		}
+		return output.Incomplete(cmd, "message(s)", warnings, mailSearchStrict)
+	} else {
+		cmd.Println(newPresenter().RenderMessages(msgs))
+	}
 
-	// Show result count if not empty
-	if len(result.Items) > 0 && !quietFlag {
-		cmd.Printf("\nFound %d message(s)", len(result.Items))
-		if result.Total > len(result.Items) {
-			cmd.Printf(" (showing first %d of ~%d)", len(result.Items), result.Total)
+	// Show result count if not empty, keeping JSON output valid
+	if shown > 0 && !quietFlag && formatFlag != presenter.FormatJSON {
+		cmd.Printf("\nFound %d message(s)", shown)
+		if total > fetched {
+			cmd.Printf(" (showing first %d of ~%d)", shown, total)
 		}
 		cmd.Println()
 	}
 
-	return nil
+	return output.Incomplete(cmd, "message(s)", warnings, mailSearchStrict)
+}
+
+// searchMessagePage prints one page of the messages matching query, for
+// scripts paging through a search with --page-token.
+func searchMessagePage(ctx context.Context, cmd *cobra.Command, repo MessageRepository, email, query string, opts mail.ListOptions) error {
+	presenter.SetImportanceColumn(mailShowImportance)
+	if err := presenter.SetMessageColumns(mailColumns); err != nil {
+		return err
+	}
+	presenter.SetRelativeDates(mailRelativeDates)
+	if mailSearchHighlight {
+		setHighlight(mail.QueryTerms(query))
+	} else {
+		setHighlight(nil)
+	}
+
+	result, err := repo.Search(ctx, query, opts)
+	if err != nil {
+		return fmt.Errorf("failed to search messages: %w", err)
+	}
+	page := messagePage(result)
+	if mailThreaded {
+		if err := renderThreadedMessages(cmd, result.Items, email, page); err != nil {
+			return err
+		}
+	} else {
+		printList(cmd, newPresenter().RenderMessages(result.Items), page)
+	}
+	if !quietFlag && result.NextPageToken != "" && formatFlag != presenter.FormatJSON {
+		cmd.Printf("\n(More messages available. Use --page-token %s for the next page.)\n", result.NextPageToken)
+	}
+	return output.Incomplete(cmd, "message(s)", result.Warnings, mailSearchStrict)
+}
+
+// messagePage returns the page metadata of a message listing.
+func messagePage(result *mail.ListResult[*mail.Message]) presenter.Page {
+	return presenter.Page{
+		NextPageToken: result.NextPageToken,
+		TotalEstimate: presenter.Estimate(result.Total),
+		FetchedCount:  len(result.Items),
+		Warnings:      result.Warnings,
+	}
+}
+
+// mailDateQuery converts --after and --before date expressions into Gmail
+// search operators. Epoch seconds are used so relative times such as "-3h"
+// keep their precision.
+func mailDateQuery(after, before string) (string, error) {
+	var terms []string
+	if after != "" {
+		t, err := dateparse.Parse(after)
+		if err != nil {
+			return "", fmt.Errorf("invalid --after: %w", err)
+		}
+		terms = append(terms, fmt.Sprintf("after:%d", t.Unix()))
+	}
+	if before != "" {
+		t, err := dateparse.Parse(before)
+		if err != nil {
+			return "", fmt.Errorf("invalid --before: %w", err)
+		}
+		terms = append(terms, fmt.Sprintf("before:%d", t.Unix()))
+	}
+	return strings.Join(terms, " "), nil
 }
 
 // runMailTrash handles the mail trash command.
@@ -568,7 +984,7 @@ func runMailModify(cmd *cobra.Command, args []string) error {
 
 	if !quietFlag {
 		cmd.Printf("Message %s labels modified\n", messageID)
-		if verboseFlag && msg != nil {
+		if output.Verbose() && msg != nil {
 			cmd.Printf("Current labels: %v\n", msg.Labels)
 		}
 	}
@@ -655,7 +1071,7 @@ func runMailMove(cmd *cobra.Command, args []string) error {
 
 	if !quietFlag {
 		cmd.Printf("Message %s moved to %s\n", messageID, mailMoveDestination)
-		if verboseFlag && msg != nil {
+		if output.Verbose() && msg != nil {
 			cmd.Printf("Current labels: %v\n", msg.Labels)
 		}
 	}
@@ -3,7 +3,11 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -815,8 +819,8 @@ func TestMailMarkCmd_HasFlags(t *testing.T) {
 func TestRunMailList_WithMockDependencies(t *testing.T) {
 	// Setup mock dependencies
 	mockMessages := []*mail.Message{
-		{ID: "msg1", Subject: "Test Subject 1", From: "sender1@example.com"},
-		{ID: "msg2", Subject: "Test Subject 2", From: "sender2@example.com"},
+		{ID: "msg1", Subject: "Test Subject 1", From: mail.Address{Email: "sender1@example.com"}},
+		{ID: "msg2", Subject: "Test Subject 2", From: mail.Address{Email: "sender2@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -873,7 +877,7 @@ func TestRunMailList_WithMockDependencies(t *testing.T) {
 
 func TestRunMailList_WithUnreadOnly(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "unread1", Subject: "Unread Message", From: "sender@example.com"},
+		{ID: "unread1", Subject: "Unread Message", From: mail.Address{Email: "sender@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -912,6 +916,55 @@ func TestRunMailList_WithUnreadOnly(t *testing.T) {
 	}
 }
 
+func TestRunMailList_Incomplete(t *testing.T) {
+	mockRepo := &MockMessageRepository{
+		ListResult: &mail.ListResult[*mail.Message]{
+			Items: []*mail.Message{
+				{ID: "msg1", Subject: "Complete", From: mail.Address{Email: "sender@example.com"}},
+				{ID: "msg2"},
+			},
+			Warnings: []string{"message msg2: gmail error: backend error"},
+		},
+	}
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: mockRepo},
+	})
+	defer ResetDependencies()
+
+	origFormat, origStrict, origQuiet := formatFlag, mailListStrict, quietFlag
+	formatFlag, quietFlag = "plain", false
+	defer func() {
+		formatFlag, mailListStrict, quietFlag = origFormat, origStrict, origQuiet
+	}()
+
+	for _, strict := range []bool{false, true} {
+		mailListStrict = strict
+		cmd := &cobra.Command{Use: "test"}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+
+		err := runMailList(cmd, []string{})
+		if strict != (err != nil) {
+			t.Fatalf("strict=%v: runMailList error = %v", strict, err)
+		}
+		if strict && !contains(err.Error(), "incomplete results: 1 message(s)") {
+			t.Errorf("error = %v, want an incomplete results error", err)
+		}
+		if !contains(stdout.String(), "msg1") || !contains(stdout.String(), "msg2") {
+			t.Errorf("strict=%v: expected both messages in output, got: %s", strict, stdout.String())
+		}
+		if !contains(stderr.String(), "Warning: incomplete result, message msg2: gmail error: backend error") {
+			t.Errorf("strict=%v: expected a warning on stderr, got: %s", strict, stderr.String())
+		}
+	}
+}
+
 func TestRunMailList_Error(t *testing.T) {
 	mockRepo := &MockMessageRepository{
 		ListErr: fmt.Errorf("API error"),
@@ -947,8 +1000,8 @@ func TestRunMailRead_WithMockDependencies(t *testing.T) {
 	mockMessage := &mail.Message{
 		ID:      "msg123",
 		Subject: "Test Email Subject",
-		From:    "sender@example.com",
-		To:      []string{"recipient@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
 		Body:    "This is the test email body.",
 	}
 
@@ -1024,8 +1077,8 @@ func TestRunMailRead_Error(t *testing.T) {
 
 func TestRunMailSearch_WithMockDependencies(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "search1", Subject: "Meeting Tomorrow", From: "boss@example.com"},
-		{ID: "search2", Subject: "Meeting Update", From: "boss@example.com"},
+		{ID: "search1", Subject: "Meeting Tomorrow", From: mail.Address{Email: "boss@example.com"}},
+		{ID: "search2", Subject: "Meeting Update", From: mail.Address{Email: "boss@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -1394,7 +1447,7 @@ func TestRunMailModify_WithMockDependencies(t *testing.T) {
 	origAddLabels := mailModifyAddLabels
 	origRemoveLabels := mailModifyRemoveLabels
 	quietFlag = false
-	verboseFlag = true
+	verboseFlag = 1
 	mailModifyAddLabels = []string{"IMPORTANT"}
 	mailModifyRemoveLabels = []string{"INBOX"}
 	defer func() {
@@ -1641,7 +1694,7 @@ func TestRunMailMark_Error(t *testing.T) {
 
 func TestRunMailList_QuietMode(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "msg1", Subject: "Test Subject 1", From: "sender1@example.com"},
+		{ID: "msg1", Subject: "Test Subject 1", From: mail.Address{Email: "sender1@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -1722,7 +1775,7 @@ func TestRunMailSearch_EmptyResults(t *testing.T) {
 
 func TestRunMailSearch_WithPagination(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "msg1", Subject: "Test 1", From: "sender@example.com"},
+		{ID: "msg1", Subject: "Test 1", From: mail.Address{Email: "sender@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -2116,8 +2169,8 @@ func TestRunMailRead_JSONFormat(t *testing.T) {
 	mockMessage := &mail.Message{
 		ID:      "msg123",
 		Subject: "JSON Test Subject",
-		From:    "sender@example.com",
-		To:      []string{"recipient@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -2156,9 +2209,72 @@ func TestRunMailRead_JSONFormat(t *testing.T) {
 	}
 }
 
+func TestRunMailRead_Translate(t *testing.T) {
+	mockRepo := &MockMessageRepository{
+		Message: &mail.Message{ID: "msg123", Subject: "Bonjour", From: mail.Address{Email: "ami@example.fr"}, Body: "Merci beaucoup"},
+	}
+	translator := &MockTranslator{SourceLanguage: "fr"}
+	factory := &MockRepositoryFactory{MessageRepo: mockRepo, Translator: translator}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: factory,
+	})
+	defer ResetDependencies()
+
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Setenv("GOOG_TRANSLATE_API_KEY", "")
+	origFormat, origTranslate := formatFlag, mailReadTranslate
+	defer func() { formatFlag, mailReadTranslate = origFormat, origTranslate }()
+	formatFlag, mailReadTranslate = "table", "en"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	err := runMailRead(cmd, []string{"msg123"})
+	if err == nil || !strings.Contains(err.Error(), "no translation backend configured") {
+		t.Fatalf("expected a missing backend error, got %v", err)
+	}
+
+	t.Setenv("GOOG_TRANSLATE_API_KEY", "secret")
+	if err := runMailRead(cmd, []string{"msg123"}); err != nil {
+		t.Fatalf("runMailRead failed: %v", err)
+	}
+	if factory.TranslatorKey != "secret" {
+		t.Errorf("translator created with key %q, want secret", factory.TranslatorKey)
+	}
+	output := buf.String()
+	for _, want := range []string{"fr -> en", "[en] Bonjour", "--- Translated Body (fr -> en) ---\n[en] Merci beaucoup"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	formatFlag = "json"
+	buf.Reset()
+	if err := runMailRead(cmd, []string{"msg123"}); err != nil {
+		t.Fatalf("runMailRead failed: %v", err)
+	}
+	var msg mail.Message
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if msg.Translation == nil || msg.Translation.SourceLanguage != "fr" || msg.Translation.Body != "[en] Merci beaucoup" {
+		t.Errorf("unexpected translation in JSON: %+v", msg.Translation)
+	}
+
+	translator.TranslateErr = errors.New("quota exceeded")
+	if err := runMailRead(cmd, []string{"msg123"}); err == nil || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("expected the translator error, got %v", err)
+	}
+}
+
 func TestRunMailList_JSONFormat(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "msg1", Subject: "JSON List Test", From: "sender@example.com"},
+		{ID: "msg1", Subject: "JSON List Test", From: mail.Address{Email: "sender@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -2199,7 +2315,7 @@ func TestRunMailList_JSONFormat(t *testing.T) {
 
 func TestRunMailSearch_JSONFormat(t *testing.T) {
 	mockMessages := []*mail.Message{
-		{ID: "msg1", Subject: "JSON Search Test", From: "sender@example.com"},
+		{ID: "msg1", Subject: "JSON Search Test", From: mail.Address{Email: "sender@example.com"}},
 	}
 
 	mockRepo := &MockMessageRepository{
@@ -2535,7 +2651,7 @@ func TestRunMailMove_VerboseMode(t *testing.T) {
 	origVerbose := verboseFlag
 	origDestination := mailMoveDestination
 	quietFlag = false
-	verboseFlag = true
+	verboseFlag = 1
 	mailMoveDestination = "IMPORTANT"
 	defer func() {
 		quietFlag = origQuiet
@@ -2572,3 +2688,43 @@ func TestMailCmd_MoveSubcommandRegistered(t *testing.T) {
 		t.Error("expected subcommand 'move' to be registered with mailCmd")
 	}
 }
+
+func TestMailDateQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		after   string
+		before  string
+		want    []string
+		wantErr string
+	}{
+		{name: "none", want: nil},
+		{name: "absolute after", after: "2025-08-01T00:00:00Z", want: []string{"after:1754006400"}},
+		{name: "range", after: "2025-08-01T00:00:00Z", before: "2025-08-15T00:00:00Z", want: []string{"after:1754006400", "before:1755216000"}},
+		{name: "relative after", after: "-3d", want: []string{"after:"}},
+		{name: "invalid after", after: "someday", wantErr: "invalid --after"},
+		{name: "invalid before", before: "someday", wantErr: "invalid --before"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mailDateQuery(tt.after, tt.before)
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tt.want) == 0 && got != "" {
+				t.Errorf("expected empty query, got %q", got)
+			}
+			for _, w := range tt.want {
+				if !contains(got, w) {
+					t.Errorf("expected query to contain %q, got %q", w, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,580 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// attachmentsPageSize is the number of messages requested per search page.
+const attachmentsPageSize = 100
+
+// defaultCatMaxBytes is the largest attachment mail attachments cat prints
+// unless --max-bytes says otherwise.
+const defaultCatMaxBytes = 1 << 20
+
+// Command flags for mail attachments commands.
+var (
+	mailAttachmentsQuery     string
+	mailAttachmentsDest      string
+	mailAttachmentsRename    string
+	mailAttachmentsManifest  string
+	mailAttachmentsLimit     int
+	mailAttachmentsMaxBytes  int64
+	mailAttachmentsForce     bool
+	mailAttachmentsSpamTrash bool
+)
+
+// mailAttachmentsCmd represents the mail attachments command group.
+var mailAttachmentsCmd = &cobra.Command{
+	Use:     "attachments",
+	Aliases: []string{"attachment"},
+	Short:   "Work with message attachments",
+	Long: `Work with attachments on Gmail messages.
+
+Attachments of a message can be listed and text attachments printed
+without saving them, or downloaded in bulk from every message matching
+a Gmail search query.`,
+}
+
+// mailAttachmentsListCmd lists the attachments of a message.
+var mailAttachmentsListCmd = &cobra.Command{
+	Use:     "list <message-id>",
+	Aliases: []string{"ls"},
+	Short:   "List the attachments of a message",
+	Long: `List the attachments of a message with their filename, MIME type and
+size, without downloading them.
+
+The # column numbers the attachments for 'goog mail attachments cat'.
+With --quiet only the numbers are printed.`,
+	Example: `  # List the attachments of a message
+  goog mail attachments list 18abc123def456
+
+  # As JSON, including the attachment IDs
+  goog mail attachments list 18abc123def456 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailAttachmentsList,
+}
+
+// mailAttachmentsCatCmd prints a text attachment.
+var mailAttachmentsCatCmd = &cobra.Command{
+	Use:   "cat <message-id> <attachment>",
+	Short: "Print a text attachment",
+	Long: `Print a text attachment to stdout without writing a file.
+
+The attachment is given by its number from 'goog mail attachments list',
+its filename or its attachment ID. Gmail issues new attachment IDs each
+time a message is fetched, so the number or filename is the reliable
+choice.
+
+Attachments larger than --max-bytes (1 MiB by default) are refused
+before they are downloaded; use 'goog mail attachments extract' for
+those. Binary attachments, such as images and PDFs, are refused unless
+--force is given. Output stops quietly when the reading end of a pipe
+is closed.`,
+	Example: `  # Print the first attachment
+  goog mail attachments cat 18abc123def456 1
+
+  # Print an attachment by filename
+  goog mail attachments cat 18abc123def456 report.csv | head
+
+  # Allow a larger log file
+  goog mail attachments cat 18abc123def456 build.log --max-bytes 10000000`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMailAttachmentsCat,
+}
+
+// mailAttachmentsExtractCmd downloads attachments from matching messages.
+var mailAttachmentsExtractCmd = &cobra.Command{
+	Use:   "extract",
+	Short: "Download attachments from messages matching a query",
+	Long: `Download every attachment from messages matching a Gmail search query.
+
+All pages of search results are processed; --include-spam-trash also
+searches spam and trash, such as for attachments of auto-filtered
+mail. Each attachment is saved
+in the destination directory using the --rename template, and a
+manifest describing every saved file is written alongside them.
+
+Template placeholders:
+  {date}      Message date (YYYY-MM-DD)
+  {from}      Sender email address
+  {subject}   Message subject
+  {id}        Message ID
+  {filename}  Original attachment filename
+  {account.<key>}  Account metadata (see 'goog config account')
+
+Characters that are not valid in filenames are replaced with "_".
+When two attachments render to the same name, a numeric suffix is
+added. Existing files with the same name are overwritten.`,
+	Example: `  # Download all invoice attachments
+  goog mail attachments extract --query "from:invoices@ has:attachment" --dest ./invoices
+
+  # Prefix filenames with the date and sender
+  goog mail attachments extract --query "has:attachment label:receipts" \
+    --dest ./receipts --rename "{date}-{from}-{filename}"
+
+  # Only process the 20 most recent matching messages
+  goog mail attachments extract --query "has:attachment" --dest ./recent --limit 20`,
+	Args: cobra.NoArgs,
+	RunE: runMailAttachmentsExtract,
+}
+
+func init() {
+	mailCmd.AddCommand(mailAttachmentsCmd)
+	mailAttachmentsCmd.AddCommand(mailAttachmentsExtractCmd)
+	mailAttachmentsCmd.AddCommand(mailAttachmentsListCmd)
+	mailAttachmentsCmd.AddCommand(mailAttachmentsCatCmd)
+
+	mailAttachmentsExtractCmd.Flags().StringVar(&mailAttachmentsQuery, "query", "", "Gmail search query (required)")
+	mailAttachmentsExtractCmd.Flags().StringVar(&mailAttachmentsDest, "dest", ".", "destination directory")
+	mailAttachmentsExtractCmd.Flags().StringVar(&mailAttachmentsRename, "rename", "{filename}", "filename template")
+	mailAttachmentsExtractCmd.Flags().StringVar(&mailAttachmentsManifest, "manifest", "manifest.json", "manifest filename written in the destination directory")
+	mailAttachmentsExtractCmd.Flags().IntVar(&mailAttachmentsLimit, "limit", 0, "maximum number of messages to process (0 for no limit)")
+	mailAttachmentsExtractCmd.Flags().BoolVar(&mailAttachmentsSpamTrash, "include-spam-trash", false, "also search messages in SPAM and TRASH")
+	_ = mailAttachmentsExtractCmd.MarkFlagRequired("query")
+
+	mailAttachmentsCatCmd.Flags().Int64Var(&mailAttachmentsMaxBytes, "max-bytes", defaultCatMaxBytes, "refuse attachments larger than this many bytes (0 for no limit)")
+	mailAttachmentsCatCmd.Flags().BoolVar(&mailAttachmentsForce, "force", false, "print binary attachments too")
+}
+
+// attachmentManifestEntry describes one saved attachment in the manifest.
+type attachmentManifestEntry struct {
+	MessageID    string `json:"message_id"`
+	AttachmentID string `json:"attachment_id"`
+	Date         string `json:"date,omitempty"`
+	From         string `json:"from"`
+	Subject      string `json:"subject"`
+	Filename     string `json:"filename"`
+	File         string `json:"file"`
+	MimeType     string `json:"mime_type"`
+	Size         int64  `json:"size"`
+}
+
+// attachmentManifest is the manifest written after an extraction.
+type attachmentManifest struct {
+	Query       string                    `json:"query"`
+	Messages    int                       `json:"messages"`
+	Attachments []attachmentManifestEntry `json:"attachments"`
+}
+
+// runMailAttachmentsExtract handles the mail attachments extract command.
+func runMailAttachmentsExtract(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if strings.TrimSpace(mailAttachmentsQuery) == "" {
+		return fmt.Errorf("--query is required")
+	}
+	if strings.TrimSpace(mailAttachmentsRename) == "" {
+		return fmt.Errorf("--rename template cannot be empty")
+	}
+	if mailAttachmentsLimit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+
+	msgRepo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	attRepo, err := getAttachmentRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(mailAttachmentsDest, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	manifest := &attachmentManifest{
+		Query:       mailAttachmentsQuery,
+		Attachments: []attachmentManifestEntry{},
+	}
+	used := make(map[string]bool)
+	used[mailAttachmentsManifest] = true
+	template := config.ExpandMetadata(mailAttachmentsRename, sanitizeMetadata(accountMetadata()))
+
+	pageToken := ""
+	for {
+		opts := mail.ListOptions{
+			MaxResults:       attachmentsPageSize,
+			PageToken:        pageToken,
+			IncludeSpamTrash: mailAttachmentsSpamTrash,
+		}
+		result, err := msgRepo.Search(ctx, mailAttachmentsQuery, opts)
+		if err != nil {
+			return fmt.Errorf("failed to search messages: %w", err)
+		}
+
+		for _, msg := range result.Items {
+			if mailAttachmentsLimit > 0 && manifest.Messages >= mailAttachmentsLimit {
+				break
+			}
+			if msg == nil {
+				continue
+			}
+			manifest.Messages++
+
+			for _, att := range msg.Attachments {
+				entry, err := saveAttachment(ctx, attRepo, template, msg, att, used)
+				if err != nil {
+					return err
+				}
+				manifest.Attachments = append(manifest.Attachments, entry)
+				if !quietFlag && formatFlag != presenter.FormatJSON {
+					cmd.Printf("Saved %s (%d bytes)\n", entry.File, entry.Size)
+				}
+			}
+		}
+
+		if result.NextPageToken == "" || result.NextPageToken == pageToken {
+			break
+		}
+		if mailAttachmentsLimit > 0 && manifest.Messages >= mailAttachmentsLimit {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	manifestPath := filepath.Join(mailAttachmentsDest, mailAttachmentsManifest)
+	if err := os.WriteFile(manifestPath, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if !quietFlag {
+		cmd.Printf("Saved %d attachment(s) from %d message(s) to %s\n",
+			len(manifest.Attachments), manifest.Messages, mailAttachmentsDest)
+		cmd.Printf("Manifest written to %s\n", manifestPath)
+	}
+	return nil
+}
+
+// saveAttachment downloads a single attachment and writes it to the destination directory.
+func saveAttachment(ctx context.Context, repo AttachmentRepository, template string, msg *mail.Message, att *mail.Attachment, used map[string]bool) (attachmentManifestEntry, error) {
+	data, err := repo.Get(ctx, msg.ID, att.ID)
+	if err != nil {
+		return attachmentManifestEntry{}, fmt.Errorf("failed to download attachment %q from message %s: %w", att.Filename, msg.ID, err)
+	}
+
+	name := uniqueAttachmentName(renderAttachmentName(template, msg, att), used)
+	if err := os.WriteFile(filepath.Join(mailAttachmentsDest, name), data, 0o644); err != nil {
+		return attachmentManifestEntry{}, fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	entry := attachmentManifestEntry{
+		MessageID:    msg.ID,
+		AttachmentID: att.ID,
+		From:         msg.From.String(),
+		Subject:      msg.Subject,
+		Filename:     att.Filename,
+		File:         name,
+		MimeType:     att.MimeType,
+		Size:         int64(len(data)),
+	}
+	if !msg.Date.IsZero() {
+		entry.Date = msg.Date.Format(time.RFC3339)
+	}
+	return entry, nil
+}
+
+// renderAttachmentName expands the filename template for an attachment.
+func renderAttachmentName(template string, msg *mail.Message, att *mail.Attachment) string {
+	date := "undated"
+	if !msg.Date.IsZero() {
+		date = msg.Date.Format("2006-01-02")
+	}
+
+	filename := att.Filename
+	if filename == "" {
+		filename = "attachment"
+	}
+
+	replacer := strings.NewReplacer(
+		"{date}", sanitizeFilename(date),
+		"{from}", sanitizeFilename(msg.From.Email),
+		"{subject}", sanitizeFilename(msg.Subject),
+		"{id}", sanitizeFilename(msg.ID),
+		"{filename}", sanitizeFilename(filename),
+	)
+
+	name := sanitizeFilename(replacer.Replace(template))
+	if name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// sanitizeMetadata makes account metadata values safe to use in a
+// filename.
+func sanitizeMetadata(metadata map[string]string) map[string]string {
+	safe := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		safe[key] = sanitizeFilename(value)
+	}
+	return safe
+}
+
+// sanitizeFilename replaces characters that are unsafe in filenames.
+func sanitizeFilename(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f:
+			sb.WriteRune('_')
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			sb.WriteRune('_')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return strings.Trim(sb.String(), " .")
+}
+
+// uniqueAttachmentName returns name, or name with a numeric suffix if it was
+// already used during this extraction.
+func uniqueAttachmentName(name string, used map[string]bool) string {
+	candidate := name
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// attachmentListEntry describes one attachment in mail attachments list.
+type attachmentListEntry struct {
+	Number       int    `json:"number"`
+	AttachmentID string `json:"attachment_id"`
+	Filename     string `json:"filename"`
+	MimeType     string `json:"mime_type"`
+	Size         int64  `json:"size"`
+	Inline       bool   `json:"inline,omitempty"`
+}
+
+// runMailAttachmentsList handles the mail attachments list command.
+func runMailAttachmentsList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	msg, err := repo.Get(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+
+	entries := make([]attachmentListEntry, 0, len(msg.Attachments))
+	for i, att := range msg.Attachments {
+		entries = append(entries, attachmentListEntry{
+			Number:       i + 1,
+			AttachmentID: att.ID,
+			Filename:     att.Filename,
+			MimeType:     att.MimeType,
+			Size:         att.Size,
+			Inline:       att.IsInline(),
+		})
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode attachments: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if output.Quiet() {
+		for _, entry := range entries {
+			cmd.Println(entry.Number)
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		cmd.Println("No attachments")
+		return nil
+	}
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "#\tFILENAME\tTYPE\tSIZE")
+	for _, entry := range entries {
+		name := entry.Filename
+		if entry.Inline {
+			name += " (inline)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entry.Number, name, entry.MimeType, formatSize(entry.Size))
+	}
+	return w.Flush()
+}
+
+// runMailAttachmentsCat handles the mail attachments cat command.
+func runMailAttachmentsCat(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if mailAttachmentsMaxBytes < 0 {
+		return fmt.Errorf("--max-bytes cannot be negative")
+	}
+
+	msgRepo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	msg, err := msgRepo.Get(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	att, err := findAttachment(msg, args[1])
+	if err != nil {
+		return err
+	}
+
+	// The size Gmail reports lets large attachments be refused unread
+	if err := checkAttachmentSize(att, att.Size); err != nil {
+		return err
+	}
+	if !mailAttachmentsForce && !textMimeType(att.MimeType) && !otherMimeType(att.MimeType) {
+		return fmt.Errorf("attachment %q is %s, not text; use --force to print it anyway", att.Filename, att.MimeType)
+	}
+
+	attRepo, err := getAttachmentRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := attRepo.Get(ctx, msg.ID, att.ID)
+	if err != nil {
+		return fmt.Errorf("failed to download attachment %q: %w", att.Filename, err)
+	}
+	if err := checkAttachmentSize(att, int64(len(data))); err != nil {
+		return err
+	}
+	if !mailAttachmentsForce && !textMimeType(att.MimeType) && !looksLikeText(data) {
+		return fmt.Errorf("attachment %q is binary; use --force to print it anyway", att.Filename)
+	}
+
+	stopPipe := catchBrokenPipe()
+	defer stopPipe()
+	if _, err := io.Copy(cmd.OutOrStdout(), bytes.NewReader(data)); err != nil {
+		if isBrokenPipe(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to write attachment: %w", err)
+	}
+	return nil
+}
+
+// findAttachment returns the attachment of msg given by ref: its number
+// in mail attachments list, its filename, ignoring case, or its ID.
+func findAttachment(msg *mail.Message, ref string) (*mail.Attachment, error) {
+	ref = strings.TrimSpace(ref)
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(msg.Attachments) {
+			return nil, fmt.Errorf("message %s has %d attachment(s), no attachment %d", msg.ID, len(msg.Attachments), n)
+		}
+		return msg.Attachments[n-1], nil
+	}
+	var byName []*mail.Attachment
+	for _, att := range msg.Attachments {
+		if att.ID == ref {
+			return att, nil
+		}
+		if strings.EqualFold(att.Filename, ref) {
+			byName = append(byName, att)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return nil, fmt.Errorf("message %s has no attachment %q; see 'goog mail attachments list %s'", msg.ID, ref, msg.ID)
+	case 1:
+		return byName[0], nil
+	default:
+		return nil, fmt.Errorf("message %s has %d attachments named %q; give its number instead", msg.ID, len(byName), ref)
+	}
+}
+
+// checkAttachmentSize fails when size is over --max-bytes.
+func checkAttachmentSize(att *mail.Attachment, size int64) error {
+	if mailAttachmentsMaxBytes > 0 && size > mailAttachmentsMaxBytes {
+		return fmt.Errorf("attachment %q is %s, over the --max-bytes limit of %s; raise --max-bytes or use 'goog mail attachments extract'",
+			att.Filename, formatSize(size), formatSize(mailAttachmentsMaxBytes))
+	}
+	return nil
+}
+
+// textMimeType reports whether mimeType is a text format.
+func textMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if base, _, ok := strings.Cut(mimeType, ";"); ok {
+		mimeType = strings.TrimSpace(base)
+	}
+	switch {
+	case strings.HasPrefix(mimeType, "text/"),
+		strings.HasSuffix(mimeType, "+json"),
+		strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-yaml", "application/yaml", "application/x-sh",
+		"application/sql", "application/csv", "application/x-ndjson",
+		"message/rfc822", "message/delivery-status":
+		return true
+	}
+	return false
+}
+
+// otherMimeType reports whether mimeType says nothing about the content,
+// so the content itself decides whether it is text.
+func otherMimeType(mimeType string) bool {
+	switch strings.ToLower(strings.TrimSpace(mimeType)) {
+	case "", "application/octet-stream":
+		return true
+	}
+	return false
+}
+
+// looksLikeText reports whether data is UTF-8 without NUL bytes, as text
+// files sent with a generic MIME type are.
+func looksLikeText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// formatSize formats a byte count for people, e.g. "532 B" or "1.4 MB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KB", "MB", "GB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f TB", value)
+}
@@ -0,0 +1,461 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// pagedMessageRepository returns search results keyed by page token.
+type pagedMessageRepository struct {
+	MockMessageRepository
+	Pages  map[string]*mail.ListResult[*mail.Message]
+	Tokens []string
+}
+
+func (m *pagedMessageRepository) Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Tokens = append(m.Tokens, opts.PageToken)
+	if page, ok := m.Pages[opts.PageToken]; ok {
+		return page, nil
+	}
+	return &mail.ListResult[*mail.Message]{}, nil
+}
+
+// attachmentMessage builds a message carrying the given attachments.
+func attachmentMessage(id, from string, date time.Time, attachments ...*mail.Attachment) *mail.Message {
+	msg := mail.NewMessage(id, id, from, "Invoice "+id, "")
+	msg.Date = date
+	msg.Attachments = attachments
+	return msg
+}
+
+// setupMailAttachmentsTest injects repositories and resets extract flags.
+func setupMailAttachmentsTest(t *testing.T, msgRepo MessageRepository, attRepo AttachmentRepository) string {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo, AttachmentRepo: attRepo},
+	})
+
+	dest := filepath.Join(t.TempDir(), "out")
+
+	origQuery, origDest, origRename, origManifest, origLimit := mailAttachmentsQuery, mailAttachmentsDest, mailAttachmentsRename, mailAttachmentsManifest, mailAttachmentsLimit
+	origFormat, origQuiet, origSpamTrash := formatFlag, quietFlag, mailAttachmentsSpamTrash
+	mailAttachmentsQuery = "has:attachment"
+	mailAttachmentsDest = dest
+	mailAttachmentsRename = "{filename}"
+	mailAttachmentsManifest = "manifest.json"
+	mailAttachmentsLimit = 0
+	mailAttachmentsSpamTrash = false
+	formatFlag = "table"
+	quietFlag = false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailAttachmentsQuery, mailAttachmentsDest, mailAttachmentsRename, mailAttachmentsManifest, mailAttachmentsLimit = origQuery, origDest, origRename, origManifest, origLimit
+		formatFlag, quietFlag, mailAttachmentsSpamTrash = origFormat, origQuiet, origSpamTrash
+	})
+
+	return dest
+}
+
+func readAttachmentManifest(t *testing.T, path string) attachmentManifest {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var manifest attachmentManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	return manifest
+}
+
+func TestMailAttachmentsExtractCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "attachments", "extract", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--query", "--dest", "--rename", "--manifest", "--limit", "{filename}"} {
+		if !contains(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunMailAttachmentsExtract(t *testing.T) {
+	date := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	msgRepo := &pagedMessageRepository{
+		Pages: map[string]*mail.ListResult[*mail.Message]{
+			"": {
+				Items: []*mail.Message{
+					attachmentMessage("msg1", "Billing <invoices@example.com>", date,
+						&mail.Attachment{ID: "a1", Filename: "invoice.pdf", MimeType: "application/pdf"}),
+					attachmentMessage("msg2", "invoices@example.com", date),
+				},
+				NextPageToken: "page2",
+			},
+			"page2": {
+				Items: []*mail.Message{
+					attachmentMessage("msg3", "invoices@example.com", date.AddDate(0, 0, 1),
+						&mail.Attachment{ID: "a3", Filename: "invoice.pdf", MimeType: "application/pdf"},
+						&mail.Attachment{ID: "a4", Filename: "receipt.png", MimeType: "image/png"}),
+				},
+			},
+		},
+	}
+	attRepo := &MockAttachmentRepository{Data: map[string][]byte{
+		"a1": []byte("first"),
+		"a3": []byte("second"),
+		"a4": []byte("png"),
+	}}
+
+	dest := setupMailAttachmentsTest(t, msgRepo, attRepo)
+	mailAttachmentsRename = "{date}-{from}-{filename}"
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailAttachmentsExtract(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(msgRepo.Tokens) != 2 || msgRepo.Tokens[1] != "page2" {
+		t.Errorf("expected two pages to be fetched, got tokens %v", msgRepo.Tokens)
+	}
+
+	files := map[string]string{
+		"2024-05-01-invoices@example.com-invoice.pdf": "first",
+		"2024-05-02-invoices@example.com-invoice.pdf": "second",
+		"2024-05-02-invoices@example.com-receipt.png": "png",
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Errorf("expected file %s: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	manifest := readAttachmentManifest(t, filepath.Join(dest, "manifest.json"))
+	if manifest.Messages != 3 {
+		t.Errorf("manifest messages = %d, want 3", manifest.Messages)
+	}
+	if len(manifest.Attachments) != 3 {
+		t.Fatalf("manifest attachments = %d, want 3", len(manifest.Attachments))
+	}
+	if manifest.Attachments[0].MessageID != "msg1" || manifest.Attachments[0].Filename != "invoice.pdf" {
+		t.Errorf("unexpected first entry: %+v", manifest.Attachments[0])
+	}
+
+	if !contains(buf.String(), "Saved 3 attachment(s) from 3 message(s)") {
+		t.Errorf("expected summary, got: %s", buf.String())
+	}
+}
+
+func TestRunMailAttachmentsExtract_DuplicateNames(t *testing.T) {
+	date := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	msgRepo := &MockMessageRepository{Messages: []*mail.Message{
+		attachmentMessage("msg1", "a@example.com", date, &mail.Attachment{ID: "a1", Filename: "scan.pdf"}),
+		attachmentMessage("msg2", "b@example.com", date, &mail.Attachment{ID: "a2", Filename: "scan.pdf"}),
+	}}
+	attRepo := &MockAttachmentRepository{Data: map[string][]byte{"a1": []byte("1"), "a2": []byte("2")}}
+
+	dest := setupMailAttachmentsTest(t, msgRepo, attRepo)
+	quietFlag = true
+
+	if err := runMailAttachmentsExtract(&cobra.Command{Use: "test"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, want := range map[string]string{"scan.pdf": "1", "scan-1.pdf": "2"} {
+		got, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q (err %v), want %q", name, got, err, want)
+		}
+	}
+}
+
+func TestRunMailAttachmentsExtract_Limit(t *testing.T) {
+	date := time.Now()
+	msgRepo := &MockMessageRepository{Messages: []*mail.Message{
+		attachmentMessage("msg1", "a@example.com", date, &mail.Attachment{ID: "a1", Filename: "one.txt"}),
+		attachmentMessage("msg2", "b@example.com", date, &mail.Attachment{ID: "a2", Filename: "two.txt"}),
+	}}
+	attRepo := &MockAttachmentRepository{}
+
+	dest := setupMailAttachmentsTest(t, msgRepo, attRepo)
+	mailAttachmentsLimit = 1
+	formatFlag = "json"
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailAttachmentsExtract(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(attRepo.Calls) != 1 || attRepo.Calls[0] != "msg1/a1" {
+		t.Errorf("expected only msg1/a1 to be downloaded, got %v", attRepo.Calls)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "two.txt")); !os.IsNotExist(err) {
+		t.Error("expected two.txt not to be written")
+	}
+
+	var out attachmentManifest
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("expected JSON manifest on stdout: %v\n%s", err, buf.String())
+	}
+	if out.Messages != 1 || len(out.Attachments) != 1 {
+		t.Errorf("unexpected manifest: %+v", out)
+	}
+}
+
+func TestRunMailAttachmentsExtract_IncludeSpamTrash(t *testing.T) {
+	msgRepo := &MockMessageRepository{}
+	setupMailAttachmentsTest(t, msgRepo, &MockAttachmentRepository{})
+	mailAttachmentsSpamTrash = true
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+	if err := runMailAttachmentsExtract(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !msgRepo.SearchOpts.IncludeSpamTrash {
+		t.Errorf("search options = %+v, want spam and trash included", msgRepo.SearchOpts)
+	}
+}
+
+func TestRunMailAttachmentsExtract_Errors(t *testing.T) {
+	t.Run("empty rename template", func(t *testing.T) {
+		setupMailAttachmentsTest(t, &MockMessageRepository{}, &MockAttachmentRepository{})
+		mailAttachmentsRename = " "
+
+		err := runMailAttachmentsExtract(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !contains(err.Error(), "--rename") {
+			t.Errorf("expected rename error, got %v", err)
+		}
+	})
+
+	t.Run("search error", func(t *testing.T) {
+		setupMailAttachmentsTest(t, &MockMessageRepository{SearchErr: errors.New("boom")}, &MockAttachmentRepository{})
+
+		err := runMailAttachmentsExtract(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !contains(err.Error(), "failed to search messages") {
+			t.Errorf("expected search error, got %v", err)
+		}
+	})
+
+	t.Run("download error", func(t *testing.T) {
+		msgRepo := &MockMessageRepository{Messages: []*mail.Message{
+			attachmentMessage("msg1", "a@example.com", time.Now(), &mail.Attachment{ID: "a1", Filename: "x.pdf"}),
+		}}
+		setupMailAttachmentsTest(t, msgRepo, &MockAttachmentRepository{GetErr: errors.New("boom")})
+
+		err := runMailAttachmentsExtract(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !contains(err.Error(), "failed to download attachment") {
+			t.Errorf("expected download error, got %v", err)
+		}
+	})
+}
+
+func TestRenderAttachmentName(t *testing.T) {
+	msg := mail.NewMessage("msg1", "t1", "Jane Doe <jane@example.com>", "Q1: Report/Final", "")
+	msg.Date = time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	att := &mail.Attachment{ID: "a1", Filename: "report.pdf"}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{filename}", "report.pdf"},
+		{"{date}-{from}-{filename}", "2024-01-15-jane@example.com-report.pdf"},
+		{"{subject} {filename}", "Q1_ Report_Final report.pdf"},
+		{"{id}_{filename}", "msg1_report.pdf"},
+		{"../{filename}", "_report.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := renderAttachmentName(tt.template, msg, att); got != tt.want {
+				t.Errorf("renderAttachmentName(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing date and filename", func(t *testing.T) {
+		bare := &mail.Message{ID: "m"}
+		got := renderAttachmentName("{date}-{filename}", bare, &mail.Attachment{})
+		if got != "undated-attachment" {
+			t.Errorf("got %q, want %q", got, "undated-attachment")
+		}
+	})
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"a/b\\c", "a_b_c"},
+		{"what?*.txt", "what__.txt"},
+		{"  ..hidden.. ", "hidden"},
+		{"tab\there", "tab_here"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeFilename(tt.input); got != tt.want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// setupAttachmentViewTest serves one message with a CSV, a PDF and a text
+// file sent as application/octet-stream, and resets the cat flags.
+func setupAttachmentViewTest(t *testing.T) *MockAttachmentRepository {
+	t.Helper()
+	msg := attachmentMessage("msg1", "billing@example.com", time.Now(),
+		&mail.Attachment{ID: "a1", Filename: "report.csv", MimeType: "text/csv", Size: 18},
+		&mail.Attachment{ID: "a2", Filename: "invoice.pdf", MimeType: "application/pdf", Size: 2048},
+		&mail.Attachment{ID: "a3", Filename: "notes.txt", MimeType: "application/octet-stream", Size: 11},
+	)
+	attRepo := &MockAttachmentRepository{Data: map[string][]byte{
+		"a1": []byte("name,total\nacme,42\n"),
+		"a2": []byte("%PDF-1.4\x00\x01"),
+		"a3": []byte("hello world"),
+	}}
+	setupMailAttachmentsTest(t, &MockMessageRepository{Message: msg}, attRepo)
+
+	origMax, origForce := mailAttachmentsMaxBytes, mailAttachmentsForce
+	t.Cleanup(func() { mailAttachmentsMaxBytes, mailAttachmentsForce = origMax, origForce })
+	mailAttachmentsMaxBytes, mailAttachmentsForce = defaultCatMaxBytes, false
+	return attRepo
+}
+
+func TestRunMailAttachmentsList(t *testing.T) {
+	setupAttachmentViewTest(t)
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailAttachmentsList(cmd, []string{"msg1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"FILENAME", "report.csv", "text/csv", "18 B", "invoice.pdf", "2.0 KB"} {
+		if !contains(buf.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, buf.String())
+		}
+	}
+
+	t.Run("quiet prints numbers", func(t *testing.T) {
+		quietFlag = true
+		buf.Reset()
+		if err := runMailAttachmentsList(cmd, []string{"msg1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "1\n2\n3\n" {
+			t.Errorf("unexpected output %q", buf.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		quietFlag, formatFlag = false, "json"
+		buf.Reset()
+		if err := runMailAttachmentsList(cmd, []string{"msg1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var entries []attachmentListEntry
+		if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(entries) != 3 || entries[1].AttachmentID != "a2" || entries[1].Number != 2 {
+			t.Errorf("unexpected entries %+v", entries)
+		}
+	})
+}
+
+func TestRunMailAttachmentsCat(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		maxBytes int64
+		force    bool
+		want     string
+		wantErr  string
+		download bool
+	}{
+		{name: "by number", ref: "1", want: "name,total\nacme,42\n", download: true},
+		{name: "by filename", ref: "REPORT.csv", want: "name,total\nacme,42\n", download: true},
+		{name: "by ID", ref: "a3", want: "hello world", download: true},
+		{name: "binary refused", ref: "invoice.pdf", wantErr: "not text"},
+		{name: "binary forced", ref: "2", force: true, want: "%PDF-1.4\x00\x01", download: true},
+		{name: "too large", ref: "2", force: true, maxBytes: 1024, wantErr: "--max-bytes"},
+		{name: "unknown number", ref: "4", wantErr: "no attachment 4"},
+		{name: "unknown name", ref: "missing.txt", wantErr: "no attachment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attRepo := setupAttachmentViewTest(t)
+			mailAttachmentsForce = tt.force
+			if tt.maxBytes > 0 {
+				mailAttachmentsMaxBytes = tt.maxBytes
+			}
+
+			cmd := &cobra.Command{Use: "test"}
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			err := runMailAttachmentsCat(cmd, []string{"msg1", tt.ref})
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+			if downloaded := len(attRepo.Calls) > 0; downloaded != tt.download {
+				t.Errorf("downloaded = %v, want %v", downloaded, tt.download)
+			}
+		})
+	}
+}
+
+func TestLooksLikeText(t *testing.T) {
+	if !looksLikeText([]byte("plain text ✓\n")) {
+		t.Error("expected UTF-8 text to look like text")
+	}
+	if looksLikeText([]byte{0x89, 'P', 'N', 'G', 0}) {
+		t.Error("expected binary data not to look like text")
+	}
+}
@@ -0,0 +1,36 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// noAutoBccFlag is the name of the flag that skips mail.auto_bcc for one
+// message.
+const noAutoBccFlag = "no-auto-bcc"
+
+// addAutoBccFlag registers --no-auto-bcc on a sending command.
+func addAutoBccFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(noAutoBccFlag, false, "do not blind-copy the mail.auto_bcc addresses")
+}
+
+// autoBccAddresses returns the mail.auto_bcc addresses for a message sent
+// by cmd, or none when --no-auto-bcc is set. The addresses bypass the
+// suppression list, recipient checks and throttling, which are meant for
+// the recipients the user chose.
+func autoBccAddresses(cmd *cobra.Command) []string {
+	if skip, _ := cmd.Flags().GetBool(noAutoBccFlag); skip {
+		return nil
+	}
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cfg.Mail.AutoBcc
+}
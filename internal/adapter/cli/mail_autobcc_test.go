@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// setAutoBcc adds mail.auto_bcc to the test config.
+func setAutoBcc(t *testing.T, value string) {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetValue("mail.auto_bcc", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunMailSend_AutoBcc(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addAutoBccFlag(cmd)
+	setAutoBcc(t, "dropbox@crm.example.com, bo@example.com")
+	mailSendTo = []string{"ana@example.com"}
+	mailSendBcc = []string{"BO@example.com"}
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(repo.Sent))
+	}
+	// bo@example.com is already blind-copied
+	if want := []string{"BO@example.com", "dropbox@crm.example.com"}; !slices.Equal(mail.AddressStrings(repo.Sent[0].Bcc), want) {
+		t.Errorf("Bcc = %v, want %v", mail.AddressStrings(repo.Sent[0].Bcc), want)
+	}
+}
+
+func TestRunMailSend_NoAutoBcc(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addAutoBccFlag(cmd)
+	setAutoBcc(t, "dropbox@crm.example.com")
+	if err := cmd.ParseFlags([]string{"--no-auto-bcc"}); err != nil {
+		t.Fatal(err)
+	}
+	mailSendTo = []string{"ana@example.com"}
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 || len(repo.Sent[0].Bcc) != 0 {
+		t.Errorf("expected no Bcc with --no-auto-bcc, got %+v", repo.Sent)
+	}
+}
+
+func TestRunMailSend_DryRunShowsAutoBcc(t *testing.T) {
+	repo, cmd, out, _ := setupMailGroupsTest(t)
+	addAutoBccFlag(cmd)
+	setAutoBcc(t, "dropbox@crm.example.com")
+	mailSendTo = []string{"ana@example.com"}
+	mailSendDryRun = true
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 0 {
+		t.Error("expected nothing to be sent on a dry run")
+	}
+	if want := "Auto Bcc: dropbox@crm.example.com (mail.auto_bcc)"; !contains(out.String(), want) {
+		t.Errorf("expected output to contain %q:\n%s", want, out.String())
+	}
+}
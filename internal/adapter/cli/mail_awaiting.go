@@ -0,0 +1,349 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// defaultNudgeTemplate is the body of a follow-up draft made by --nudge.
+const defaultNudgeTemplate = `Hi {name},
+
+I wanted to follow up on my message of {date} about "{subject}". Could you let me know where things stand?
+
+Thanks!`
+
+// awaitingPageSize is the number of sent threads listed per page.
+const awaitingPageSize = 100
+
+// awaitingFetchBatchSize is the number of threads fetched at once.
+const awaitingFetchBatchSize = 10
+
+// Command flags for mail awaiting command.
+var (
+	mailAwaitingDays     int
+	mailAwaitingSince    string
+	mailAwaitingMax      int
+	mailAwaitingNudge    bool
+	mailAwaitingTemplate string
+)
+
+// mailAwaitingCmd lists sent mail that got no reply.
+var mailAwaitingCmd = &cobra.Command{
+	Use:   "awaiting",
+	Short: "List sent mail still awaiting a reply",
+	Long: `List the threads where your message is the latest and no reply has
+arrived for at least --days days.
+
+Sent mail of the last --since period is scanned thread by thread, most
+recent first. A thread is listed when its latest message, drafts aside,
+is from you and went to someone other than you.
+
+--nudge saves a polite follow-up draft in each listed thread, addressed
+to the recipients of your message, for you to review and send. Threads
+that already hold a draft are skipped. --template sets the draft body;
+it supports {name} (the first recipient's first name), {subject},
+{date} (when you wrote), {days} (days waiting) and {account.<key>} for
+the account's metadata (see 'goog config account').`,
+	Example: `  # Sent mail without a reply for 3 days or more
+  goog mail awaiting
+
+  # Look back 90 days for threads waiting a week
+  goog mail awaiting --since 90d --days 7
+
+  # Save follow-up drafts for them
+  goog mail awaiting --days 7 --nudge
+
+  # With your own wording and signature
+  goog mail awaiting --nudge --template "Hi {name}, any news on {subject}? {account.signature}"`,
+	Args: cobra.NoArgs,
+	RunE: runMailAwaiting,
+}
+
+func init() {
+	mailCmd.AddCommand(mailAwaitingCmd)
+
+	mailAwaitingCmd.Flags().IntVar(&mailAwaitingDays, "days", 3, "minimum days without a reply")
+	mailAwaitingCmd.Flags().StringVar(&mailAwaitingSince, "since", "30d", "how far back to scan sent mail (e.g. 30d, 8w, 2025-01-01)")
+	mailAwaitingCmd.Flags().IntVar(&mailAwaitingMax, "max", 20, "maximum number of threads to list")
+	mailAwaitingCmd.Flags().BoolVar(&mailAwaitingNudge, "nudge", false, "save a follow-up draft in each listed thread")
+	mailAwaitingCmd.Flags().StringVar(&mailAwaitingTemplate, "template", defaultNudgeTemplate, "body of the follow-up drafts")
+}
+
+// awaitingThread is a thread awaiting a reply to your latest message.
+type awaitingThread struct {
+	thread *mail.Thread
+	latest *mail.Message
+	draft  string
+}
+
+// awaitingJSON is the JSON representation of a thread awaiting a reply.
+type awaitingJSON struct {
+	ThreadID string   `json:"thread_id"`
+	Subject  string   `json:"subject"`
+	To       []string `json:"to"`
+	Sent     string   `json:"sent"`
+	Days     int      `json:"days"`
+	DraftID  string   `json:"draft_id,omitempty"`
+}
+
+// runMailAwaiting handles the mail awaiting command.
+func runMailAwaiting(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if mailAwaitingDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	if mailAwaitingMax <= 0 {
+		return fmt.Errorf("--max must be positive")
+	}
+	now := time.Now()
+	since, err := dateparse.ParseSinceAt(mailAwaitingSince, now)
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+	if mailAwaitingNudge && strings.TrimSpace(mailAwaitingTemplate) == "" {
+		return fmt.Errorf("--template must not be empty")
+	}
+
+	tokenSource, self, err := getTokenSourceWithEmailFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	repo, err := GetDependencies().RepoFactory.NewThreadRepository(ctx, tokenSource)
+	if err != nil {
+		return fmt.Errorf("failed to create thread repository: %w", err)
+	}
+	var drafts DraftRepository
+	if mailAwaitingNudge {
+		if drafts, err = getDraftRepositoryFromDeps(ctx); err != nil {
+			return err
+		}
+	}
+
+	cutoff := now.AddDate(0, 0, -mailAwaitingDays)
+	found, failed, err := findAwaiting(ctx, cmd, repo, self, since, cutoff)
+	if err != nil {
+		return err
+	}
+
+	nudgeFailed := 0
+	if mailAwaitingNudge {
+		nudgeFailed = nudgeAwaiting(ctx, cmd, drafts, found, self, now)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		out := make([]awaitingJSON, 0, len(found))
+		for _, a := range found {
+			out = append(out, awaitingJSON{
+				ThreadID: a.thread.ID,
+				Subject:  a.latest.Subject,
+				To:       mail.AddressStrings(awaitingRecipients(a.latest, self)),
+				Sent:     a.latest.Date.Format(time.RFC3339),
+				Days:     daysWaiting(a.latest, now),
+				DraftID:  a.draft,
+			})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode threads: %w", err)
+		}
+		cmd.Println(string(data))
+	} else {
+		printAwaiting(cmd, found, self, now)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to read %d thread(s)", failed)
+	}
+	if nudgeFailed > 0 {
+		return fmt.Errorf("failed to save %d follow-up draft(s)", nudgeFailed)
+	}
+	return nil
+}
+
+// findAwaiting scans the threads with mail sent between since and cutoff,
+// most recent first, and returns up to --max of them awaiting a reply
+// from before cutoff, with the number of threads that could not be read.
+func findAwaiting(ctx context.Context, cmd *cobra.Command, repo ThreadRepository, self string, since, cutoff time.Time) ([]*awaitingThread, int, error) {
+	opts := mail.ListOptions{
+		Query:      fmt.Sprintf("in:sent after:%d before:%d", since.Unix(), cutoff.Unix()),
+		MaxResults: awaitingPageSize,
+	}
+	var found []*awaitingThread
+	failed := 0
+	for {
+		result, err := repo.List(ctx, opts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list sent threads: %w", err)
+		}
+
+		ids := make([]string, 0, len(result.Items))
+		for _, t := range result.Items {
+			ids = append(ids, t.ID)
+		}
+		for start := 0; start < len(ids) && len(found) < mailAwaitingMax; start += awaitingFetchBatchSize {
+			batch := ids[start:min(start+awaitingFetchBatchSize, len(ids))]
+			threads, errs := getThreads(ctx, repo, batch)
+			for i, thread := range threads {
+				if errs[i] != nil {
+					output.Warnf(cmd, "thread %s: %v", batch[i], errs[i])
+					failed++
+					continue
+				}
+				if len(found) < mailAwaitingMax && thread.AwaitingReply(self, cutoff) {
+					found = append(found, &awaitingThread{thread: thread, latest: thread.LatestNonDraft()})
+				}
+			}
+		}
+
+		if len(found) >= mailAwaitingMax || result.NextPageToken == "" {
+			return found, failed, nil
+		}
+		opts.PageToken = result.NextPageToken
+	}
+}
+
+// getThreads fetches the threads with ids at once and returns each with
+// its error, in the order of ids.
+func getThreads(ctx context.Context, repo ThreadRepository, ids []string) ([]*mail.Thread, []error) {
+	threads := make([]*mail.Thread, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			threads[i], errs[i] = repo.Get(ctx, ids[i])
+		}(i)
+	}
+	wg.Wait()
+	return threads, errs
+}
+
+// nudgeAwaiting saves a follow-up draft in each thread that holds none yet
+// and returns the number of drafts that could not be saved.
+func nudgeAwaiting(ctx context.Context, cmd *cobra.Command, drafts DraftRepository, found []*awaitingThread, self string, now time.Time) int {
+	metadata := accountMetadata()
+	failed := 0
+	for _, a := range found {
+		if a.thread.HasDraft() {
+			output.Warnf(cmd, "thread %s already has a draft; no follow-up saved", a.thread.ID)
+			continue
+		}
+		// Answer your latest message, so that the follow-up is threaded
+		// under it by the recipients' clients as well as by Gmail.
+		msg := &mail.Message{
+			ThreadID:   a.thread.ID,
+			To:         withoutAddress(a.latest.To, self),
+			Cc:         withoutAddress(a.latest.Cc, self),
+			Subject:    followUpSubject(a.latest.Subject),
+			Body:       nudgeBody(mailAwaitingTemplate, a.latest, self, now, metadata),
+			References: a.latest.ReplyReferences(),
+		}
+		if len(msg.To) == 0 {
+			// Only copied or blind copied: follow up with them directly.
+			msg.To, msg.Cc = awaitingRecipients(a.latest, self), nil
+		}
+		created, err := drafts.Create(ctx, &mail.Draft{Message: msg})
+		if err != nil {
+			output.Warnf(cmd, "thread %s: failed to save a follow-up draft: %v", a.thread.ID, err)
+			failed++
+			continue
+		}
+		a.draft = created.ID
+	}
+	return failed
+}
+
+// followUpSubject returns subject with a single "Re: " prefix in place of
+// any reply prefixes it has, such as "RE:" or "Re: Re:".
+func followUpSubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	for len(subject) >= 3 && strings.EqualFold(subject[:3], "re:") {
+		subject = strings.TrimSpace(subject[3:])
+	}
+	return strings.TrimSpace("Re: " + subject)
+}
+
+// nudgeBody fills in the follow-up template for your latest message.
+func nudgeBody(template string, latest *mail.Message, self string, now time.Time, metadata map[string]string) string {
+	name := ""
+	if to := awaitingRecipients(latest, self); len(to) > 0 {
+		name = firstName(to[0])
+	}
+	return strings.NewReplacer(
+		"{name}", name,
+		"{subject}", latest.Subject,
+		"{date}", presenter.CurrentLocale().Date(latest.Date.Local()),
+		"{days}", strconv.Itoa(daysWaiting(latest, now)),
+	).Replace(config.ExpandMetadata(template, metadata))
+}
+
+// firstName returns the first word of an address's display name, or else
+// the part of the address before the @.
+func firstName(addr mail.Address) string {
+	if fields := strings.Fields(addr.Name); len(fields) > 0 {
+		return fields[0]
+	}
+	local, _, _ := strings.Cut(addr.Email, "@")
+	return local
+}
+
+// awaitingRecipients returns the recipients of msg other than self.
+func awaitingRecipients(msg *mail.Message, self string) []mail.Address {
+	return withoutAddress(slices.Concat(msg.To, msg.Cc, msg.Bcc), self)
+}
+
+// withoutAddress returns list without email and without empty addresses.
+func withoutAddress(list []mail.Address, email string) []mail.Address {
+	var out []mail.Address
+	for _, addr := range list {
+		if addr.Email != "" && !strings.EqualFold(addr.Email, email) {
+			out = append(out, addr)
+		}
+	}
+	return out
+}
+
+// daysWaiting returns the whole days since msg was sent.
+func daysWaiting(msg *mail.Message, now time.Time) int {
+	return int(now.Sub(msg.Date).Hours() / 24)
+}
+
+// printAwaiting prints the threads awaiting a reply as text.
+func printAwaiting(cmd *cobra.Command, found []*awaitingThread, self string, now time.Time) {
+	if len(found) == 0 {
+		if !quietFlag {
+			cmd.Println("No sent mail is awaiting a reply")
+		}
+		return
+	}
+	locale := presenter.CurrentLocale()
+	for _, a := range found {
+		subject := a.latest.Subject
+		if subject == "" {
+			subject = "(no subject)"
+		}
+		cmd.Printf("%s  %s  %3dd  %s  to %s\n", a.thread.ID, locale.Date(a.latest.Date.Local()),
+			daysWaiting(a.latest, now), subject, mail.JoinAddresses(awaitingRecipients(a.latest, self)))
+		if a.draft != "" {
+			cmd.Printf("    follow-up draft %s\n", a.draft)
+		}
+	}
+	if !quietFlag {
+		cmd.Printf("\n%d thread(s) awaiting a reply.\n", len(found))
+	}
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// awaitingThreadRepository pages through Threads and returns them whole
+// from Full. Get is safe for concurrent use since it only reads.
+type awaitingThreadRepository struct {
+	bulkThreadRepository
+	Full map[string]*mail.Thread
+}
+
+func (r *awaitingThreadRepository) Get(ctx context.Context, id string) (*mail.Thread, error) {
+	if r.FailOn[id] {
+		return nil, errors.New("rate limited")
+	}
+	return r.Full[id], nil
+}
+
+// nudgeDraftRepository records the drafts it creates and numbers them.
+type nudgeDraftRepository struct {
+	MockDraftRepository
+	Created []*mail.Draft
+}
+
+func (r *nudgeDraftRepository) Create(ctx context.Context, draft *mail.Draft) (*mail.Draft, error) {
+	if r.CreateErr != nil {
+		return nil, r.CreateErr
+	}
+	r.Created = append(r.Created, draft)
+	return &mail.Draft{ID: fmt.Sprintf("d%d", len(r.Created)), Message: draft.Message}, nil
+}
+
+// newAwaitingRepository holds threads t0..t(n-1), where the even ones
+// await a reply to a message sent days ago and the odd ones were answered.
+func newAwaitingRepository(n int, days int) *awaitingThreadRepository {
+	now := time.Now()
+	repo := &awaitingThreadRepository{Full: make(map[string]*mail.Thread)}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("t%d", i)
+		thread := &mail.Thread{ID: id, Messages: []*mail.Message{{
+			ThreadID:   id,
+			From:       mail.Address{Email: "me@example.com"},
+			To:         []mail.Address{{Name: "Ana Lima", Email: "ana@example.com"}, {Email: "me@example.com"}},
+			Cc:         []mail.Address{{Email: "lea@example.com"}},
+			Subject:    "Plan " + id,
+			Date:       now.AddDate(0, 0, -days),
+			MessageID:  id + "-2@mail.example.com",
+			References: []string{id + "-1@mail.example.com"},
+		}}}
+		if i%2 == 1 {
+			thread.Messages = append(thread.Messages, &mail.Message{From: mail.Address{Email: "ana@example.com"}, Date: now.Add(-time.Hour)})
+		}
+		repo.Threads = append(repo.Threads, &mail.Thread{ID: id})
+		repo.Full[id] = thread
+	}
+	return repo
+}
+
+// setupMailAwaitingTest injects repo and drafts and resets the awaiting flags.
+func setupMailAwaitingTest(t *testing.T, repo *awaitingThreadRepository, drafts *nudgeDraftRepository) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com", Metadata: map[string]string{"signature": "Sam"}},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{ThreadRepo: repo, DraftRepo: drafts},
+	})
+
+	origDays, origSince, origMax := mailAwaitingDays, mailAwaitingSince, mailAwaitingMax
+	origNudge, origTemplate := mailAwaitingNudge, mailAwaitingTemplate
+	origQuiet, origFormat := quietFlag, formatFlag
+	mailAwaitingDays, mailAwaitingSince, mailAwaitingMax = 3, "30d", 20
+	mailAwaitingNudge, mailAwaitingTemplate = false, defaultNudgeTemplate
+	quietFlag, formatFlag = false, presenter.FormatPlain
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailAwaitingDays, mailAwaitingSince, mailAwaitingMax = origDays, origSince, origMax
+		mailAwaitingNudge, mailAwaitingTemplate = origNudge, origTemplate
+		quietFlag, formatFlag = origQuiet, origFormat
+	})
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	return cmd, buf
+}
+
+func TestRunMailAwaiting(t *testing.T) {
+	repo := newAwaitingRepository(250, 5)
+	cmd, buf := setupMailAwaitingTest(t, repo, &nudgeDraftRepository{})
+	mailAwaitingMax = 3
+
+	if err := runMailAwaiting(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"t0  ", "t2  ", "t4  ", "  5d  Plan t0  to Ana Lima <ana@example.com>, lea@example.com\n", "3 thread(s) awaiting a reply."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "t1  ") || strings.Contains(out, "t6  ") {
+		t.Errorf("unexpected thread listed:\n%s", out)
+	}
+	// The first page was enough.
+	if len(repo.Lists) != 1 || !strings.HasPrefix(repo.Lists[0].Query, "in:sent after:") || !strings.Contains(repo.Lists[0].Query, " before:") {
+		t.Errorf("unexpected list calls: %+v", repo.Lists)
+	}
+}
+
+func TestRunMailAwaiting_Pages(t *testing.T) {
+	repo := newAwaitingRepository(250, 5)
+	cmd, buf := setupMailAwaitingTest(t, repo, &nudgeDraftRepository{})
+	mailAwaitingMax = 1000
+	formatFlag = presenter.FormatJSON
+
+	if err := runMailAwaiting(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []awaitingJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 125 || len(repo.Lists) != 3 {
+		t.Errorf("got %d threads from %d pages, want 125 from 3", len(got), len(repo.Lists))
+	}
+	if got[0].Days != 5 || got[0].Subject != "Plan t0" || len(got[0].To) != 2 || got[0].DraftID != "" {
+		t.Errorf("unexpected first thread: %+v", got[0])
+	}
+}
+
+func TestRunMailAwaiting_TooRecent(t *testing.T) {
+	cmd, buf := setupMailAwaitingTest(t, newAwaitingRepository(4, 1), &nudgeDraftRepository{})
+
+	if err := runMailAwaiting(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "No sent mail is awaiting a reply\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestRunMailAwaiting_Nudge(t *testing.T) {
+	repo := newAwaitingRepository(6, 5)
+	repo.Full["t2"].Messages = append(repo.Full["t2"].Messages, &mail.Message{Labels: []string{"DRAFT"}, Date: time.Now()})
+	drafts := &nudgeDraftRepository{}
+	cmd, buf := setupMailAwaitingTest(t, repo, drafts)
+	mailAwaitingNudge = true
+	mailAwaitingTemplate = "Hi {name}, any news on {subject} after {days} days? {account.signature}"
+
+	if err := runMailAwaiting(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drafts.Created) != 2 {
+		t.Fatalf("created %d drafts, want 2 (t2 already has one)", len(drafts.Created))
+	}
+	msg := drafts.Created[0].Message
+	if msg.ThreadID != "t0" || msg.Subject != "Re: Plan t0" {
+		t.Errorf("unexpected draft: thread %q subject %q", msg.ThreadID, msg.Subject)
+	}
+	if len(msg.References) != 2 || msg.References[0] != "t0-1@mail.example.com" || msg.References[1] != "t0-2@mail.example.com" {
+		t.Errorf("draft references %q, want the thread's and the latest message's Message-IDs", msg.References)
+	}
+	if mail.JoinAddresses(msg.To) != "Ana Lima <ana@example.com>" || mail.JoinAddresses(msg.Cc) != "lea@example.com" {
+		t.Errorf("draft to %v cc %v", msg.To, msg.Cc)
+	}
+	if msg.Body != "Hi Ana, any news on Plan t0 after 5 days? Sam" {
+		t.Errorf("body = %q", msg.Body)
+	}
+	out := buf.String()
+	for _, want := range []string{"follow-up draft d1", "follow-up draft d2", "thread t2 already has a draft"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunMailAwaiting_Failures(t *testing.T) {
+	t.Run("thread", func(t *testing.T) {
+		repo := newAwaitingRepository(4, 5)
+		repo.FailOn = map[string]bool{"t0": true}
+		cmd, buf := setupMailAwaitingTest(t, repo, &nudgeDraftRepository{})
+
+		err := runMailAwaiting(cmd, nil)
+		if err == nil || !strings.Contains(err.Error(), "failed to read 1 thread(s)") {
+			t.Errorf("error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "t2  ") {
+			t.Errorf("other threads were not listed:\n%s", buf.String())
+		}
+	})
+
+	t.Run("draft", func(t *testing.T) {
+		drafts := &nudgeDraftRepository{MockDraftRepository: MockDraftRepository{CreateErr: errors.New("read-only")}}
+		cmd, _ := setupMailAwaitingTest(t, newAwaitingRepository(4, 5), drafts)
+		mailAwaitingNudge = true
+
+		err := runMailAwaiting(cmd, nil)
+		if err == nil || !strings.Contains(err.Error(), "failed to save 2 follow-up draft(s)") {
+			t.Errorf("error = %v", err)
+		}
+	})
+}
+
+func TestRunMailAwaiting_FlagErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		want  string
+	}{
+		{"days", func() { mailAwaitingDays = 0 }, "--days must be positive"},
+		{"max", func() { mailAwaitingMax = -1 }, "--max must be positive"},
+		{"since", func() { mailAwaitingSince = "soon" }, "invalid --since"},
+		{"template", func() { mailAwaitingNudge, mailAwaitingTemplate = true, " " }, "--template must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _ := setupMailAwaitingTest(t, newAwaitingRepository(0, 5), &nudgeDraftRepository{})
+			tt.setup()
+			if err := runMailAwaiting(cmd, nil); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowUpSubject(t *testing.T) {
+	tests := map[string]string{
+		"Plan":            "Re: Plan",
+		"RE: Plan":        "Re: Plan",
+		"re:Re:  Plan ":   "Re: Plan",
+		"Regarding plans": "Re: Regarding plans",
+		"":                "Re:",
+	}
+	for in, want := range tests {
+		if got := followUpSubject(in); got != want {
+			t.Errorf("followUpSubject(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFirstName(t *testing.T) {
+	tests := map[mail.Address]string{
+		{Name: "Ana Lima", Email: "ana@example.com"}: "Ana",
+		{Email: "lea.m@example.com"}:                 "lea.m",
+	}
+	for addr, want := range tests {
+		if got := firstName(addr); got != want {
+			t.Errorf("firstName(%v) = %q, want %q", addr, got, want)
+		}
+	}
+}
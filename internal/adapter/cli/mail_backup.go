@@ -0,0 +1,407 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/archive"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// restoreProgressDir is the directory, next to the config file, holding
+// the progress files of restores.
+const restoreProgressDir = "restore"
+
+// backupPageSize is the number of message IDs listed per request, the most
+// Gmail allows.
+const backupPageSize = 500
+
+// Command flags for mail backup and restore.
+var (
+	mailBackupOut       string
+	mailBackupCompress  string
+	mailRestoreLabel    string
+	mailRestoreResume   bool
+	mailBackupSpamTrash bool
+)
+
+// mailBackupCmd saves messages to a compressed archive.
+var mailBackupCmd = &cobra.Command{
+	Use:   "backup [query]",
+	Short: "Back up messages to a compressed archive",
+	Long: `Back up the messages matching a Gmail query to one archive file.
+
+The archive is a tar file holding each message unchanged, in RFC 2822
+form, as messages/<id>.eml, followed by an index.json manifest listing
+every message with its size, SHA-256 checksum, sender, subject and date.
+Without a query, all mail except spam and trash is backed up;
+--include-spam-trash adds spam and trash, whether or not a query is
+given, and is recorded in the manifest.
+
+--compress picks zstd, gzip or none. Without it, the compression follows
+the --out extension: .tar.gz or .tgz for gzip, .tar for none, and zstd
+otherwise. Use "-" as --out to write the archive to standard output.
+
+Messages are written as they are fetched, so memory use stays flat for
+large mailboxes. The archive is written to a temporary file and renamed
+into place once complete.`,
+	Example: `  # Back up all mail
+  goog mail backup --out mail.tar.zst
+
+  # Back up one label with gzip
+  goog mail backup "label:projects" --out projects.tar.gz
+
+  # Stream a backup to another machine
+  goog mail backup "before:2020/01/01" --out - --compress zstd | ssh nas 'cat > old-mail.tar.zst'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailBackup,
+}
+
+// mailRestoreCmd imports the messages of a backup archive.
+var mailRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore messages from a backup archive",
+	Long: `Import the messages of an archive written by "goog mail backup".
+
+Each message is imported unchanged, with its original date, and given
+the --label label, which is created if needed. The compression is
+detected from the archive itself. Use "-" to read from standard input.
+
+Every message is checked against the checksum in the archive's manifest
+before it is imported. Messages that do not match, are missing or fail
+to import are listed and the restore goes on with the rest; the command
+then exits with an error. Progress is recorded as messages are imported,
+so --resume continues an interrupted or partly failed restore without
+importing any message twice.`,
+	Example: `  # Restore a backup under the Restored label
+  goog mail restore mail.tar.zst --label Restored
+
+  # Continue after an interruption
+  goog mail restore mail.tar.zst --label Restored --resume
+
+  # Restore from standard input
+  ssh nas 'cat old-mail.tar.zst' | goog mail restore - --label "Old mail"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailRestore,
+}
+
+func init() {
+	mailCmd.AddCommand(mailBackupCmd)
+	mailCmd.AddCommand(mailRestoreCmd)
+
+	mailBackupCmd.Flags().StringVarP(&mailBackupOut, "out", "o", "", "archive file to write, or - for stdout (required)")
+	mailBackupCmd.Flags().StringVar(&mailBackupCompress, "compress", "", "compression: zstd, gzip or none (default: from the --out extension)")
+	mailBackupCmd.Flags().BoolVar(&mailBackupSpamTrash, "include-spam-trash", false, "also back up messages in SPAM and TRASH")
+	_ = mailBackupCmd.MarkFlagRequired("out")
+
+	mailRestoreCmd.Flags().StringVar(&mailRestoreLabel, "label", "Restored", "label given to restored messages")
+	mailRestoreCmd.Flags().BoolVar(&mailRestoreResume, "resume", false, "skip messages restored by an earlier, interrupted run")
+}
+
+// mailArchiveJSON is the JSON summary of a backup.
+type mailArchiveJSON struct {
+	Archive     string `json:"archive"`
+	Compression string `json:"compression"`
+	Messages    int    `json:"messages"`
+}
+
+// mailRestoreJSON is the JSON report of a restore.
+type mailRestoreJSON struct {
+	Archive  string                   `json:"archive"`
+	Label    string                   `json:"label"`
+	Imported int                      `json:"imported"`
+	Skipped  int                      `json:"skipped"`
+	Failed   []mailRestoreFailureJSON `json:"failed"`
+}
+
+// mailRestoreFailureJSON is a message that could not be restored.
+type mailRestoreFailureJSON struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// runMailBackup handles the mail backup command.
+func runMailBackup(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	compression := archive.CompressionFor(mailBackupOut)
+	if mailBackupCompress != "" {
+		var err error
+		if compression, err = archive.ParseCompression(mailBackupCompress); err != nil {
+			return err
+		}
+	}
+	query := ""
+	if len(args) > 0 {
+		query = args[0]
+	}
+
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = cmd.OutOrStdout()
+	var file *os.File
+	if mailBackupOut != "-" {
+		if file, err = os.CreateTemp(filepath.Dir(mailBackupOut), ".goog-backup-*"); err != nil {
+			return fmt.Errorf("failed to create archive: %w", err)
+		}
+		defer func() {
+			// Removes the temporary file unless it was renamed into place
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}()
+		out = file
+	}
+
+	count, err := writeMailBackup(ctx, cmd, repo, out, compression, archive.Index{Account: email, Query: query, IncludeSpamTrash: mailBackupSpamTrash})
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		if err := os.Rename(file.Name(), mailBackupOut); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	} else {
+		// The archive is on stdout, so the summary can only go to stderr
+		output.Verbosef(cmd, "Backed up %d message(s)", count)
+		return nil
+	}
+
+	summary := mailArchiveJSON{Archive: mailBackupOut, Compression: compression, Messages: count}
+	if formatFlag == presenter.FormatJSON {
+		return printArchiveSummary(cmd, summary)
+	}
+	if !quietFlag {
+		cmd.Printf("Backed up %d message(s) to %s (%s)\n", count, mailBackupOut, compression)
+	}
+	return nil
+}
+
+// writeMailBackup writes an archive of the messages matching index.Query
+// to out, fetching them one at a time, and returns how many it wrote.
+func writeMailBackup(ctx context.Context, cmd *cobra.Command, repo MessageRepository, out io.Writer, compression string, index archive.Index) (int, error) {
+	w, err := archive.NewWriter(out, compression, index)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := mail.ListOptions{Query: index.Query, MaxResults: backupPageSize, IDsOnly: true, IncludeSpamTrash: index.IncludeSpamTrash}
+	for {
+		page, err := repo.List(ctx, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list messages: %w", err)
+		}
+		for _, msg := range page.Items {
+			raw, err := repo.GetRaw(ctx, msg.ID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get message %s: %w", msg.ID, err)
+			}
+			if err := w.Add(msg.ID, raw); err != nil {
+				return 0, err
+			}
+		}
+		output.Verbosef(cmd, "Backed up %d message(s) so far", w.Count())
+		if page.NextPageToken == "" || page.NextPageToken == opts.PageToken {
+			break
+		}
+		opts.PageToken = page.NextPageToken
+	}
+
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return w.Count(), nil
+}
+
+// runMailRestore handles the mail restore command.
+func runMailRestore(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	name := strings.TrimSpace(mailRestoreLabel)
+	if name == "" {
+		return errors.New("--label must not be empty")
+	}
+
+	f, err := openRestoreArchive(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if args[0] == "-" {
+		defer os.Remove(f.Name())
+	}
+	defer f.Close()
+	// The manifest is the last entry, so it is read in a first pass to
+	// check each message before it is imported
+	index, err := archive.ReadIndex(f)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	r, err := archive.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	label, err := findOrCreateLabel(ctx, labelRepo, name, true)
+	if err != nil {
+		return err
+	}
+
+	progress, err := archive.OpenProgress(restoreProgressPath(index, email, label.Name), mailRestoreResume)
+	if err != nil {
+		return err
+	}
+	report, err := restoreMessages(ctx, cmd, repo, r, index, progress, label.ID)
+	if closeErr := progress.Close(err == nil && len(report.Failed) == 0); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	report.Archive, report.Label = args[0], label.Name
+
+	if err := printRestoreReport(cmd, report); err != nil {
+		return err
+	}
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d message(s) could not be restored; run again with --resume to retry them", len(report.Failed))
+	}
+	return nil
+}
+
+// openRestoreArchive opens the archive at path. Standard input, "-", is
+// first copied to a temporary file, since the archive is read twice.
+func openRestoreArchive(cmd *cobra.Command, path string) (*os.File, error) {
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+		return f, nil
+	}
+
+	f, err := os.CreateTemp("", "goog-restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to buffer archive: %w", err)
+	}
+	if _, err = io.Copy(f, cmd.InOrStdin()); err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to buffer archive: %w", err)
+	}
+	return f, nil
+}
+
+// restoreProgressPath returns the progress file of restoring the archive
+// with the given manifest into an account under a label.
+func restoreProgressPath(index *archive.Index, account, label string) string {
+	key := archive.ProgressKey(index, account, label)
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), restoreProgressDir, key+".progress")
+}
+
+// restoreMessages imports each message of the archive that matches its
+// manifest entry and was not restored by an earlier run, recording it in
+// progress. A message that cannot be checked or imported is reported
+// rather than stopping the restore.
+func restoreMessages(ctx context.Context, cmd *cobra.Command, repo MessageRepository, r *archive.Reader, index *archive.Index, progress *archive.Progress, labelID string) (mailRestoreJSON, error) {
+	report := mailRestoreJSON{Failed: []mailRestoreFailureJSON{}}
+	fail := func(id, reason string) {
+		report.Failed = append(report.Failed, mailRestoreFailureJSON{ID: id, Error: reason})
+	}
+
+	seen := make(map[string]bool)
+	for {
+		id, raw, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		seen[id] = true
+
+		entry, ok := index.Lookup(id)
+		switch {
+		case !ok:
+			fail(id, "not listed in the manifest")
+		case !entry.Matches(raw):
+			fail(id, "does not match its checksum")
+		case progress.Done(id):
+			report.Skipped++
+		default:
+			if _, err := repo.Import(ctx, raw, []string{labelID}); err != nil {
+				fail(id, err.Error())
+				continue
+			}
+			if err := progress.Mark(id); err != nil {
+				return report, err
+			}
+			report.Imported++
+			output.Debugf(cmd, "Imported %s", id)
+		}
+	}
+	for _, e := range index.Messages {
+		if !seen[e.ID] {
+			fail(e.ID, "missing from the archive")
+		}
+	}
+	return report, nil
+}
+
+// printRestoreReport prints the outcome of a restore.
+func printRestoreReport(cmd *cobra.Command, report mailRestoreJSON) error {
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode restore report: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	if !quietFlag {
+		for _, f := range report.Failed {
+			cmd.Printf("Failed: %s: %s\n", f.ID, f.Error)
+		}
+	}
+	cmd.Printf("Imported %d message(s) with label %s, skipped %d already restored, %d failed.\n",
+		report.Imported, report.Label, report.Skipped, len(report.Failed))
+	return nil
+}
+
+// printArchiveSummary prints the JSON summary of a backup.
+func printArchiveSummary(cmd *cobra.Command, summary mailArchiveJSON) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	cmd.Println(string(data))
+	return nil
+}
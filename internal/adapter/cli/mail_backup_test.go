@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/archive"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// pagedRawRepository lists its messages two per page and returns raw
+// content by ID.
+type pagedRawRepository struct {
+	MockMessageRepository
+	ids    []string
+	listed []mail.ListOptions
+}
+
+func (m *pagedRawRepository) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.listed = append(m.listed, opts)
+	start := 0
+	if opts.PageToken != "" {
+		start = int(opts.PageToken[0] - '0')
+	}
+	end := min(start+2, len(m.ids))
+	result := &mail.ListResult[*mail.Message]{}
+	for _, id := range m.ids[start:end] {
+		result.Items = append(result.Items, &mail.Message{ID: id})
+	}
+	if end < len(m.ids) {
+		result.NextPageToken = string(rune('0' + end))
+	}
+	return result, nil
+}
+
+func (m *pagedRawRepository) GetRaw(ctx context.Context, id string) ([]byte, error) {
+	return []byte("Subject: Message " + id + "\r\n\r\nBody of " + id + "\r\n"), nil
+}
+
+func setupMailBackupTest(t *testing.T, msgRepo MessageRepository, labelRepo LabelRepository) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo, LabelRepo: labelRepo},
+	})
+
+	origOut, origCompress, origLabel, origResume := mailBackupOut, mailBackupCompress, mailRestoreLabel, mailRestoreResume
+	origFormat, origQuiet, origSpamTrash := formatFlag, quietFlag, mailBackupSpamTrash
+	mailBackupOut, mailBackupCompress, mailRestoreLabel, mailRestoreResume = "", "", "Restored", false
+	formatFlag, quietFlag, mailBackupSpamTrash = "", false, false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailBackupOut, mailBackupCompress, mailRestoreLabel, mailRestoreResume = origOut, origCompress, origLabel, origResume
+		formatFlag, quietFlag, mailBackupSpamTrash = origFormat, origQuiet, origSpamTrash
+	})
+}
+
+func TestRunMailBackupRestore(t *testing.T) {
+	for _, name := range []string{"mail.tar.zst", "mail.tar.gz", "mail.tar"} {
+		t.Run(name, func(t *testing.T) {
+			repo := &pagedRawRepository{ids: []string{"m1", "m2", "m3"}}
+			labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+			setupMailBackupTest(t, repo, labelRepo)
+			mailBackupOut = filepath.Join(t.TempDir(), name)
+
+			cmd := &cobra.Command{Use: "test"}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			if err := runMailBackup(cmd, []string{"label:projects"}); err != nil {
+				t.Fatalf("backup: %v", err)
+			}
+			if !strings.Contains(buf.String(), "Backed up 3 message(s) to "+mailBackupOut) {
+				t.Errorf("output = %q", buf.String())
+			}
+			if len(repo.listed) != 2 || !repo.listed[0].IDsOnly || repo.listed[0].Query != "label:projects" || repo.listed[1].PageToken != "2" {
+				t.Errorf("listed %+v, want two ID-only pages", repo.listed)
+			}
+			// Only the archive is left behind
+			if entries, _ := os.ReadDir(filepath.Dir(mailBackupOut)); len(entries) != 1 {
+				t.Errorf("directory holds %d files, want 1", len(entries))
+			}
+
+			buf.Reset()
+			if err := runMailRestore(cmd, []string{mailBackupOut}); err != nil {
+				t.Fatalf("restore: %v", err)
+			}
+			if !strings.Contains(buf.String(), "Imported 3 message(s) with label Restored, skipped 0 already restored, 0 failed.") {
+				t.Errorf("output = %q", buf.String())
+			}
+			if !slices.Equal(labelRepo.Created, []string{"Restored"}) {
+				t.Errorf("created labels = %v, want [Restored]", labelRepo.Created)
+			}
+			if len(repo.Imported) != 3 {
+				t.Fatalf("imported %d messages, want 3", len(repo.Imported))
+			}
+			if got := string(repo.Imported[2].Raw); got != "Subject: Message m3\r\n\r\nBody of m3\r\n" {
+				t.Errorf("imported %q", got)
+			}
+			if !slices.Equal(repo.Imported[0].LabelIDs, []string{"Label_Restored"}) {
+				t.Errorf("label IDs = %v", repo.Imported[0].LabelIDs)
+			}
+		})
+	}
+}
+
+func TestRunMailBackup_StdoutCompress(t *testing.T) {
+	repo := &pagedRawRepository{ids: []string{"m1"}}
+	setupMailBackupTest(t, repo, &MockLabelRepository{})
+	mailBackupOut, mailBackupCompress, mailBackupSpamTrash = "-", "gzip", true
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	if err := runMailBackup(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("stdout does not hold a gzip archive: %q", buf.Bytes()[:min(10, buf.Len())])
+	}
+
+	r, err := archive.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for {
+		if _, _, err := r.Next(); err != nil {
+			break
+		}
+	}
+	if index := r.Index(); index == nil || index.Account != "me@example.com" || !index.IncludeSpamTrash || len(index.Messages) != 1 {
+		t.Errorf("index = %+v", index)
+	}
+	if len(repo.listed) != 1 || !repo.listed[0].IncludeSpamTrash {
+		t.Errorf("listed %+v, want spam and trash included", repo.listed)
+	}
+}
+
+func TestRunMailRestore_JSON(t *testing.T) {
+	var archiveBuf bytes.Buffer
+	w, _ := archive.NewWriter(&archiveBuf, archive.CompressZstd, archive.Index{})
+	_ = w.Add("m1", []byte("Subject: Hi\r\n\r\nHi\r\n"))
+	_ = w.Close()
+
+	repo := &MockMessageRepository{}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{"Old mail": {ID: "Label_9", Name: "Old mail"}}}
+	setupMailBackupTest(t, repo, labelRepo)
+	mailRestoreLabel = "Old mail"
+	formatFlag = presenter.FormatJSON
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetIn(&archiveBuf)
+	if err := runMailRestore(cmd, []string{"-"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var report mailRestoreJSON
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if report.Imported != 1 || report.Label != "Old mail" || report.Failed == nil || len(labelRepo.Created) != 0 {
+		t.Errorf("report = %+v, created %v", report, labelRepo.Created)
+	}
+}
+
+// failingImportRepository fails the import of the messages in failIDs,
+// recognised by their subject.
+type failingImportRepository struct {
+	MockMessageRepository
+	failIDs []string
+}
+
+func (m *failingImportRepository) Import(ctx context.Context, raw []byte, labelIDs []string) (*mail.Message, error) {
+	for _, id := range m.failIDs {
+		if strings.Contains(string(raw), "Message "+id+"\r\n") {
+			return nil, errors.New("quota exceeded")
+		}
+	}
+	return m.MockMessageRepository.Import(ctx, raw, labelIDs)
+}
+
+func TestRunMailRestore_Resume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.tar.zst")
+	f, _ := os.Create(path)
+	w, _ := archive.NewWriter(f, archive.CompressZstd, archive.Index{Account: "old@example.com"})
+	for _, id := range []string{"m1", "m2", "m3"} {
+		_ = w.Add(id, []byte("Subject: Message "+id+"\r\n\r\nBody\r\n"))
+	}
+	_ = w.Close()
+	_ = f.Close()
+
+	repo := &failingImportRepository{failIDs: []string{"m2"}}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	setupMailBackupTest(t, repo, labelRepo)
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	err := runMailRestore(cmd, []string{path})
+	if err == nil || !strings.Contains(err.Error(), "1 message(s) could not be restored") {
+		t.Fatalf("error = %v, want one failure", err)
+	}
+	if !strings.Contains(buf.String(), "Failed: m2: quota exceeded") ||
+		!strings.Contains(buf.String(), "Imported 2 message(s) with label Restored, skipped 0 already restored, 1 failed.") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	// The second run imports only the message that failed
+	repo.failIDs = nil
+	labelRepo.ByName["Restored"] = &mail.Label{ID: "Label_Restored", Name: "Restored"}
+	mailRestoreResume = true
+	buf.Reset()
+	if err := runMailRestore(cmd, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Imported 1 message(s) with label Restored, skipped 2 already restored, 0 failed.") {
+		t.Errorf("output = %q", buf.String())
+	}
+	if len(repo.Imported) != 3 {
+		t.Errorf("imported %d messages in all, want 3", len(repo.Imported))
+	}
+	// A completed restore leaves no progress behind
+	progress, _ := filepath.Glob(filepath.Join(filepath.Dir(os.Getenv("GOOG_CONFIG")), restoreProgressDir, "*.progress"))
+	if len(progress) != 0 {
+		t.Errorf("progress files left: %v", progress)
+	}
+}
+
+func TestRunMailRestore_Damaged(t *testing.T) {
+	// An archive whose manifest lists m1 with another checksum and m2,
+	// which is missing
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, file := range []struct{ name, data string }{
+		{"messages/m1.eml", "Subject: Changed\r\n\r\n"},
+		{"messages/m3.eml", "Subject: Unlisted\r\n\r\n"},
+		{archive.IndexName, `{"version":1,"messages":[{"id":"m1","file":"messages/m1.eml","sha256":"00"},{"id":"m2","file":"messages/m2.eml","sha256":"00"}]}`},
+	} {
+		_ = tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0o600, Size: int64(len(file.data)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(file.data))
+	}
+	_ = tw.Close()
+
+	repo := &MockMessageRepository{}
+	setupMailBackupTest(t, repo, &namedLabelRepository{ByName: map[string]*mail.Label{}})
+	formatFlag = presenter.FormatJSON
+
+	cmd := &cobra.Command{Use: "test"}
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetIn(&buf)
+	if err := runMailRestore(cmd, []string{"-"}); err == nil {
+		t.Fatal("expected an error for a damaged archive")
+	}
+	if len(repo.Imported) != 0 {
+		t.Errorf("imported %d damaged messages", len(repo.Imported))
+	}
+
+	var report mailRestoreJSON
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	want := []mailRestoreFailureJSON{
+		{ID: "m1", Error: "does not match its checksum"},
+		{ID: "m3", Error: "not listed in the manifest"},
+		{ID: "m2", Error: "missing from the archive"},
+	}
+	if !slices.Equal(report.Failed, want) {
+		t.Errorf("failed = %+v, want %+v", report.Failed, want)
+	}
+}
+
+func TestRunMailBackup_Errors(t *testing.T) {
+	setupMailBackupTest(t, &MockMessageRepository{}, &MockLabelRepository{})
+	cmd := &cobra.Command{Use: "test"}
+
+	mailBackupOut, mailBackupCompress = filepath.Join(t.TempDir(), "mail.tar"), "bzip2"
+	if err := runMailBackup(cmd, nil); err == nil || !strings.Contains(err.Error(), "invalid compression") {
+		t.Errorf("error = %v, want invalid compression", err)
+	}
+
+	mailRestoreLabel = " "
+	if err := runMailRestore(cmd, []string{"missing.tar.zst"}); err == nil || !strings.Contains(err.Error(), "--label") {
+		t.Errorf("error = %v, want a --label error", err)
+	}
+}
@@ -0,0 +1,257 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/suppressions"
+)
+
+// defaultBouncesQuery matches delivery status notifications sent by mail servers.
+const defaultBouncesQuery = "from:(mailer-daemon OR postmaster)"
+
+// Command flags for mail bounces command.
+var (
+	mailBouncesSince      string
+	mailBouncesQuery      string
+	mailBouncesLimit      int
+	mailBouncesNoSuppress bool
+)
+
+// mailBouncesCmd groups bounced recipients from delivery status notifications.
+var mailBouncesCmd = &cobra.Command{
+	Use:   "bounces",
+	Short: "Summarize bounced recipients",
+	Long: `Summarize recipients that bounced within a lookback period.
+
+Delivery status notifications (multipart/report) are parsed and every
+failed recipient is grouped by address, showing how often it bounced,
+when it last bounced and the most recent status code. Use this to
+clean up mailing lists.
+
+Permanent failures (5.x.x status codes) are listed before temporary
+ones, and their recipients are added to the suppression list so the
+sending commands skip them (see 'goog mail suppress'). Use --no-suppress
+to only report them.`,
+	Example: `  # Bounces from the last week
+  goog mail bounces --since 7d
+
+  # Bounces from the last 30 days as JSON
+  goog mail bounces --since 30d --format json
+
+  # Narrow the search to a specific bounce sender
+  goog mail bounces --query "from:mailer-daemon@googlemail.com"`,
+	Args: cobra.NoArgs,
+	RunE: runMailBounces,
+}
+
+func init() {
+	mailCmd.AddCommand(mailBouncesCmd)
+
+	mailBouncesCmd.Flags().StringVar(&mailBouncesSince, "since", "7d", "lookback period or start date (e.g. 7d, 4w, 36h, yesterday)")
+	mailBouncesCmd.Flags().StringVar(&mailBouncesQuery, "query", defaultBouncesQuery, "Gmail search query used to find bounce messages")
+	mailBouncesCmd.Flags().IntVar(&mailBouncesLimit, "limit", 0, "maximum number of messages to inspect (0 for no limit)")
+	mailBouncesCmd.Flags().BoolVar(&mailBouncesNoSuppress, "no-suppress", false, "do not add permanently bounced recipients to the suppression list")
+}
+
+// bouncedRecipient aggregates the bounces seen for one recipient.
+type bouncedRecipient struct {
+	Recipient  string `json:"recipient"`
+	Count      int    `json:"count"`
+	LastSeen   string `json:"last_seen,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Permanent  bool   `json:"permanent"`
+	Diagnostic string `json:"diagnostic,omitempty"`
+
+	lastSeen time.Time
+}
+
+// bouncesJSON is the JSON representation of a bounce summary.
+type bouncesJSON struct {
+	Since      string              `json:"since"`
+	Messages   int                 `json:"messages"`
+	Recipients []*bouncedRecipient `json:"recipients"`
+	Suppressed []string            `json:"suppressed,omitempty"`
+}
+
+// runMailBounces handles the mail bounces command.
+func runMailBounces(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	since, err := dateparse.ParseSinceAt(mailBouncesSince, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+	if mailBouncesLimit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(mailBouncesQuery)
+	if query == "" {
+		query = defaultBouncesQuery
+	}
+	query = fmt.Sprintf("%s after:%d", query, since.Unix())
+
+	var bounces []*mail.Message
+	inspected := 0
+	pageToken := ""
+	for {
+		opts := mail.ListOptions{
+			MaxResults: attachmentsPageSize,
+			PageToken:  pageToken,
+		}
+		result, err := repo.Search(ctx, query, opts)
+		if err != nil {
+			return fmt.Errorf("failed to search messages: %w", err)
+		}
+
+		for _, msg := range result.Items {
+			if mailBouncesLimit > 0 && inspected >= mailBouncesLimit {
+				break
+			}
+			if msg == nil {
+				continue
+			}
+			inspected++
+			if msg.IsBounce() {
+				bounces = append(bounces, msg)
+			}
+		}
+
+		if result.NextPageToken == "" || result.NextPageToken == pageToken {
+			break
+		}
+		if mailBouncesLimit > 0 && inspected >= mailBouncesLimit {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	recipients := groupBouncedRecipients(bounces)
+
+	var suppressed []string
+	if !mailBouncesNoSuppress {
+		if suppressed, err = suppressBounced(recipients); err != nil {
+			return err
+		}
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		out := bouncesJSON{
+			Since:      since.Format(time.RFC3339),
+			Messages:   len(bounces),
+			Recipients: recipients,
+			Suppressed: suppressed,
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode bounces: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(recipients) == 0 {
+		cmd.Println("No bounces found")
+		return nil
+	}
+
+	cmd.Printf("Bounced recipients since %s (%d bounce message(s)):\n\n", presenter.CurrentLocale().Date(since), len(bounces))
+	for _, r := range recipients {
+		kind := "temporary"
+		if r.Permanent {
+			kind = "permanent"
+		}
+		line := fmt.Sprintf("  %-35s %3dx  %-7s %-9s", r.Recipient, r.Count, r.Status, kind)
+		if r.LastSeen != "" {
+			line += "  last " + presenter.CurrentLocale().Date(r.lastSeen)
+		}
+		cmd.Println(strings.TrimRight(line, " "))
+		if r.Diagnostic != "" {
+			cmd.Printf("      %s\n", r.Diagnostic)
+		}
+	}
+	if len(suppressed) > 0 {
+		cmd.Printf("\nAdded %d address(es) to the suppression list: %s\n", len(suppressed), strings.Join(suppressed, ", "))
+	}
+	return nil
+}
+
+// suppressBounced adds the permanently bounced recipients to the
+// suppression list, with their status code as the reason, and returns
+// the addresses that were not suppressed before.
+func suppressBounced(recipients []*bouncedRecipient) ([]string, error) {
+	entries := make(map[string]suppressions.Entry)
+	for _, r := range recipients {
+		if r.Permanent {
+			entries[r.Recipient] = suppressions.Entry{Source: suppressions.SourceBounce, Reason: r.Status, Added: time.Now()}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return suppressions.Add(suppressions.Path(), entries)
+}
+
+// groupBouncedRecipients aggregates failed recipients across bounce messages.
+// Recipients are compared case-insensitively and the status and diagnostic of
+// the most recent bounce are kept. Permanent failures sort first, then by
+// count descending and recipient ascending.
+func groupBouncedRecipients(msgs []*mail.Message) []*bouncedRecipient {
+	byAddress := make(map[string]*bouncedRecipient)
+
+	for _, msg := range msgs {
+		if msg == nil || msg.Report == nil {
+			continue
+		}
+		for _, rs := range msg.Report.FailedRecipients() {
+			key := strings.ToLower(rs.Recipient)
+			entry, ok := byAddress[key]
+			if !ok {
+				entry = &bouncedRecipient{Recipient: key}
+				byAddress[key] = entry
+			}
+			entry.Count++
+			if !ok || msg.Date.After(entry.lastSeen) {
+				entry.lastSeen = msg.Date
+				entry.Status = rs.Status
+				entry.Permanent = rs.IsPermanent()
+				entry.Diagnostic = rs.DiagnosticCode
+			}
+		}
+	}
+
+	recipients := make([]*bouncedRecipient, 0, len(byAddress))
+	for _, entry := range byAddress {
+		if !entry.lastSeen.IsZero() {
+			entry.LastSeen = entry.lastSeen.Format(time.RFC3339)
+		}
+		recipients = append(recipients, entry)
+	}
+
+	sort.Slice(recipients, func(i, j int) bool {
+		if recipients[i].Permanent != recipients[j].Permanent {
+			return recipients[i].Permanent
+		}
+		if recipients[i].Count != recipients[j].Count {
+			return recipients[i].Count > recipients[j].Count
+		}
+		return recipients[i].Recipient < recipients[j].Recipient
+	})
+
+	return recipients
+}
@@ -0,0 +1,248 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/suppressions"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// bounceMessage builds a bounce message reporting the given failed recipients.
+func bounceMessage(id string, date time.Time, statuses ...*mail.RecipientStatus) *mail.Message {
+	msg := mail.NewMessage(id, id, "mailer-daemon@googlemail.com", "Delivery Status Notification (Failure)", "")
+	msg.Date = date
+	msg.Report = &mail.DeliveryReport{Type: mail.ReportDeliveryStatus, Recipients: statuses}
+	return msg
+}
+
+// failed returns a failed recipient status.
+func failed(recipient, status, diagnostic string) *mail.RecipientStatus {
+	return &mail.RecipientStatus{Recipient: recipient, Action: mail.ActionFailed, Status: status, DiagnosticCode: diagnostic}
+}
+
+// setupMailBouncesTest injects a message repository, isolates the config
+// directory and resets bounces flags.
+func setupMailBouncesTest(t *testing.T, repo MessageRepository) *bytes.Buffer {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origSince, origQuery, origLimit, origFormat := mailBouncesSince, mailBouncesQuery, mailBouncesLimit, formatFlag
+	origNoSuppress := mailBouncesNoSuppress
+	mailBouncesSince = "7d"
+	mailBouncesQuery = defaultBouncesQuery
+	mailBouncesLimit = 0
+	mailBouncesNoSuppress = false
+	formatFlag = "table"
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailBouncesSince, mailBouncesQuery, mailBouncesLimit, formatFlag = origSince, origQuery, origLimit, origFormat
+		mailBouncesNoSuppress = origNoSuppress
+	})
+
+	return new(bytes.Buffer)
+}
+
+func TestMailBouncesCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "bounces", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--since", "--query", "--limit"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestGroupBouncedRecipients(t *testing.T) {
+	day := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	msgs := []*mail.Message{
+		bounceMessage("b1", day, failed("Gone@example.com", "5.1.1", "old diagnostic")),
+		bounceMessage("b2", day.Add(24*time.Hour), failed("gone@example.com", "5.1.1", "user unknown"), failed("full@example.org", "4.2.2", "mailbox full")),
+		bounceMessage("b3", day.Add(-24*time.Hour), failed("gone@example.com", "5.0.0", "older")),
+		bounceMessage("b4", day, failed("alpha@example.net", "5.7.1", "blocked")),
+		nil,
+	}
+
+	got := groupBouncedRecipients(msgs)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 recipients, got %d", len(got))
+	}
+
+	if got[0].Recipient != "gone@example.com" || got[0].Count != 3 {
+		t.Errorf("got[0] = %+v, want gone@example.com x3", got[0])
+	}
+	if got[0].Status != "5.1.1" || got[0].Diagnostic != "user unknown" {
+		t.Errorf("expected latest status to be kept, got %+v", got[0])
+	}
+	if got[1].Recipient != "alpha@example.net" || !got[1].Permanent {
+		t.Errorf("got[1] = %+v, want alpha@example.net permanent", got[1])
+	}
+	if got[2].Recipient != "full@example.org" || got[2].Permanent {
+		t.Errorf("got[2] = %+v, want full@example.org temporary", got[2])
+	}
+}
+
+func TestRunMailBounces_Text(t *testing.T) {
+	now := time.Now()
+	repo := &pagedMessageRepository{
+		Pages: map[string]*mail.ListResult[*mail.Message]{
+			"": {
+				Items: []*mail.Message{
+					bounceMessage("b1", now.Add(-time.Hour), failed("gone@example.com", "5.1.1", "user unknown")),
+					mail.NewMessage("m2", "m2", "postmaster@example.com", "Delivery delayed", ""),
+				},
+				NextPageToken: "page2",
+			},
+			"page2": {
+				Items: []*mail.Message{
+					bounceMessage("b3", now.Add(-2*time.Hour), failed("gone@example.com", "5.1.1", "")),
+				},
+			},
+		},
+	}
+	buf := setupMailBouncesTest(t, repo)
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailBounces(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.Tokens) != 2 {
+		t.Errorf("expected 2 search pages, got %v", repo.Tokens)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"2 bounce message(s)", "gone@example.com", "2x", "5.1.1", "permanent", "user unknown"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunMailBounces_JSON(t *testing.T) {
+	repo := &pagedMessageRepository{
+		Pages: map[string]*mail.ListResult[*mail.Message]{
+			"": {Items: []*mail.Message{
+				bounceMessage("b1", time.Now(), failed("gone@example.com", "5.1.1", "")),
+			}},
+		},
+	}
+	buf := setupMailBouncesTest(t, repo)
+	formatFlag = "json"
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailBounces(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got bouncesJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+	}
+	if got.Messages != 1 || len(got.Recipients) != 1 {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+	if got.Recipients[0].Recipient != "gone@example.com" || !got.Recipients[0].Permanent {
+		t.Errorf("unexpected recipient: %+v", got.Recipients[0])
+	}
+	if len(got.Suppressed) != 1 || got.Suppressed[0] != "gone@example.com" {
+		t.Errorf("expected gone@example.com to be suppressed, got %v", got.Suppressed)
+	}
+	if list, _ := suppressions.Load(suppressions.Path()); list["gone@example.com"].Source != suppressions.SourceBounce {
+		t.Errorf("expected a bounce entry in the suppression list, got %v", list)
+	}
+}
+
+func TestRunMailBounces_Empty(t *testing.T) {
+	buf := setupMailBouncesTest(t, &pagedMessageRepository{})
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailBounces(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !containsStr(buf.String(), "No bounces found") {
+		t.Errorf("expected empty message, got: %s", buf.String())
+	}
+}
+
+func TestRunMailBounces_Limit(t *testing.T) {
+	repo := &pagedMessageRepository{
+		Pages: map[string]*mail.ListResult[*mail.Message]{
+			"": {
+				Items: []*mail.Message{
+					bounceMessage("b1", time.Now(), failed("one@example.com", "5.1.1", "")),
+					bounceMessage("b2", time.Now(), failed("two@example.com", "5.1.1", "")),
+				},
+				NextPageToken: "page2",
+			},
+		},
+	}
+	buf := setupMailBouncesTest(t, repo)
+	mailBouncesLimit = 1
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailBounces(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Tokens) != 1 {
+		t.Errorf("expected a single search page, got %v", repo.Tokens)
+	}
+	if containsStr(buf.String(), "two@example.com") {
+		t.Errorf("expected limit to skip second message, got:\n%s", buf.String())
+	}
+}
+
+func TestRunMailBounces_Errors(t *testing.T) {
+	t.Run("invalid since", func(t *testing.T) {
+		setupMailBouncesTest(t, &pagedMessageRepository{})
+		mailBouncesSince = "soon"
+
+		err := runMailBounces(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "invalid --since") {
+			t.Errorf("expected invalid --since error, got %v", err)
+		}
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		setupMailBouncesTest(t, &pagedMessageRepository{})
+		mailBouncesLimit = -1
+
+		err := runMailBounces(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "--limit") {
+			t.Errorf("expected limit error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,356 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/rules"
+)
+
+// maxClassifyBatch is the largest --batch-size, the most messages Gmail
+// lists in one page.
+const maxClassifyBatch = 500
+
+// Command flags for mail classify commands.
+var (
+	mailClassifyRules     string
+	mailClassifySince     string
+	mailClassifyQuery     string
+	mailClassifyBatchSize int
+	mailClassifyDryRun    bool
+	mailClassifyUndoList  bool
+)
+
+// mailClassifyCmd applies the label and archive actions of the rules to
+// past mail.
+var mailClassifyCmd = &cobra.Command{
+	Use:   "classify",
+	Short: "Apply the rules' labels to past mail",
+	Long: `Apply the label and archive actions of the local rules (see 'goog
+rules') to the mail received within --since, for example to sort an
+archive imported without labels.
+
+Messages are listed and classified in batches of --batch-size, and a
+progress line is printed on stderr after each batch. Every message is
+checked, whether or not 'goog rules run' has seen it, and rules stop as
+they do in a rules run. Forward, notify and exec actions are skipped, so
+old mail does not send mail, pop up notifications or run commands; they
+are counted in the summary.
+
+Each run keeps a journal of the labels it added and removed, written as
+each batch is applied, in the classify directory next to the config
+file. 'goog mail classify undo' reverts the latest run, or the run given,
+even one that was interrupted.`,
+	Example: `  # Preview the rules against the last 30 days of mail
+  goog mail classify --since 30d --dry-run
+
+  # Classify a year of mail with another rules file
+  goog mail classify --rules archive-rules.yaml --since 1y
+
+  # Only classify mail that has no user label yet
+  goog mail classify --since 90d --query "has:nouserlabels"
+
+  # Revert the last run
+  goog mail classify undo`,
+	Args: cobra.NoArgs,
+	RunE: runMailClassify,
+}
+
+// mailClassifyUndoCmd reverts a classify run.
+var mailClassifyUndoCmd = &cobra.Command{
+	Use:   "undo [run]",
+	Short: "Revert a classify run",
+	Long: `Revert the label changes of a 'mail classify' run: labels it added are
+removed and messages it archived go back to the inbox. Without a run ID
+the latest run that has not been undone is reverted. --list shows the
+runs that can be undone.`,
+	Example: `  # Revert the latest run
+  goog mail classify undo
+
+  # List the runs, then revert one
+  goog mail classify undo --list
+  goog mail classify undo 20260316-140502`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMailClassifyUndo,
+}
+
+func init() {
+	mailCmd.AddCommand(mailClassifyCmd)
+	mailClassifyCmd.AddCommand(mailClassifyUndoCmd)
+
+	mailClassifyCmd.Flags().StringVar(&mailClassifyRules, "rules", "", "rules file (default: rules.yaml next to the config file)")
+	mailClassifyCmd.Flags().StringVar(&mailClassifySince, "since", "30d", "classify mail received within this period or since this date (e.g. 30d, 12w, 2025-01-01)")
+	mailClassifyCmd.Flags().StringVar(&mailClassifyQuery, "query", "", "Gmail search query further selecting the messages")
+	mailClassifyCmd.Flags().IntVar(&mailClassifyBatchSize, "batch-size", 100, fmt.Sprintf("messages listed and updated per batch (at most %d)", maxClassifyBatch))
+	mailClassifyCmd.Flags().BoolVar(&mailClassifyDryRun, "dry-run", false, "show the changes without making them")
+
+	mailClassifyUndoCmd.Flags().BoolVar(&mailClassifyUndoList, "list", false, "list the runs that can be undone")
+}
+
+// classifier works out the label changes the rules make to messages.
+type classifier struct {
+	ctx       context.Context
+	labelRepo LabelRepository
+	ruleSet   []*mail.Rule
+	names     map[string]string
+	account   map[string]string
+	dryRun    bool
+	labels    map[string]*mail.Label
+	// skipped counts the actions classify does not run.
+	skipped int
+}
+
+// label returns the label an action names, creating it unless this is a
+// dry run. In a dry run a missing label stands in for itself.
+func (c *classifier) label(name string) (*mail.Label, error) {
+	if label, ok := c.labels[name]; ok {
+		return label, nil
+	}
+	label, err := findOrCreateLabel(c.ctx, c.labelRepo, name, !c.dryRun)
+	if err != nil {
+		if !c.dryRun || !errors.Is(err, mail.ErrLabelNotFound) {
+			return nil, err
+		}
+		label = mail.NewLabel(name, name)
+	}
+	c.labels[name] = label
+	c.names[label.ID] = label.Name
+	return label, nil
+}
+
+// classify returns the labels the matching rules add to msg and remove
+// from it, leaving out those it already has or lacks, and the rules that
+// made a change.
+func (c *classifier) classify(msg *mail.Message) (rules.JournalEntry, []string, error) {
+	entry := rules.JournalEntry{ID: msg.ID}
+	var changedBy []string
+	for _, rule := range mail.MatchingRules(c.ruleSet, withLabelNames(msg, c.names), c.account) {
+		changed := false
+		for _, action := range rule.Actions {
+			switch action.Type {
+			case mail.RuleActionLabel:
+				label, err := c.label(action.Value)
+				if err != nil {
+					return entry, nil, err
+				}
+				if !slices.Contains(msg.Labels, label.ID) && !slices.Contains(entry.Add, label.ID) {
+					entry.Add = append(entry.Add, label.ID)
+					changed = true
+				}
+			case mail.RuleActionArchive:
+				if slices.Contains(msg.Labels, "INBOX") && !slices.Contains(entry.Remove, "INBOX") {
+					entry.Remove = append(entry.Remove, "INBOX")
+					changed = true
+				}
+			default:
+				c.skipped++
+			}
+		}
+		if changed {
+			changedBy = append(changedBy, rule.Name)
+		}
+	}
+	return entry, changedBy, nil
+}
+
+// describe names the changes of entry, e.g. "label Receipts, archive".
+func (c *classifier) describe(entry rules.JournalEntry) string {
+	var parts []string
+	for _, id := range entry.Add {
+		parts = append(parts, "label "+c.names[id])
+	}
+	if len(entry.Remove) > 0 {
+		parts = append(parts, "archive")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runMailClassify handles the mail classify command.
+func runMailClassify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	since, err := dateparse.ParseSinceAt(mailClassifySince, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+	if mailClassifyBatchSize < 1 || mailClassifyBatchSize > maxClassifyBatch {
+		return fmt.Errorf("--batch-size must be between 1 and %d", maxClassifyBatch)
+	}
+
+	path := mailClassifyRules
+	if path == "" {
+		path = rules.Path()
+	}
+	ruleSet, err := rules.Load(expandHome(path))
+	if err != nil {
+		return err
+	}
+	if len(ruleSet) == 0 {
+		cmd.Printf("No rules defined in %s.\n", path)
+		return nil
+	}
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	names, err := labelNamesByID(ctx, labelRepo)
+	if err != nil {
+		return err
+	}
+	c := &classifier{ctx: ctx, labelRepo: labelRepo, ruleSet: ruleSet, names: names,
+		account: accountMetadata(), dryRun: mailClassifyDryRun, labels: make(map[string]*mail.Label)}
+
+	var journal *rules.Journal
+	if !mailClassifyDryRun {
+		if journal, err = rules.CreateJournal(rules.JournalDir(), time.Now()); err != nil {
+			return err
+		}
+		defer func() { _ = journal.Close() }()
+	}
+
+	query := strings.TrimSpace(fmt.Sprintf("%s after:%d", mailClassifyQuery, since.Unix()))
+	checked, changed, batch := 0, 0, 0
+	pageToken := ""
+	for {
+		result, err := repo.Search(ctx, query, mail.ListOptions{MaxResults: mailClassifyBatchSize, PageToken: pageToken})
+		if err != nil {
+			return classifyStopped(journal, changed, fmt.Errorf("failed to list messages: %w", err))
+		}
+		batch++
+
+		var entries []rules.JournalEntry
+		var queue labelQueue
+		for _, msg := range result.Items {
+			if msg == nil || msg.ID == "" {
+				continue
+			}
+			checked++
+			entry, changedBy, err := c.classify(msg)
+			if err != nil {
+				return classifyStopped(journal, changed, err)
+			}
+			if len(entry.Add) == 0 && len(entry.Remove) == 0 {
+				continue
+			}
+			entries = append(entries, entry)
+			queue.add(msg.ID, entry.Add, entry.Remove)
+			if mailClassifyDryRun {
+				cmd.Printf("Would %s: %s (rule %s)\n", c.describe(entry), msg.Subject, strings.Join(changedBy, ", "))
+			}
+		}
+
+		if !mailClassifyDryRun && len(entries) > 0 {
+			// Journal first: undoing a change that was never applied is harmless
+			if err := journal.Append(entries...); err != nil {
+				return classifyStopped(journal, changed, err)
+			}
+			if err := queue.apply(ctx, repo); err != nil {
+				return classifyStopped(journal, changed+len(entries), err)
+			}
+		}
+		changed += len(entries)
+		if !quietFlag {
+			cmd.PrintErrf("Batch %d: %d message(s) checked, %d changed\n", batch, checked, changed)
+		}
+
+		if result.NextPageToken == "" || result.NextPageToken == pageToken {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	summary := fmt.Sprintf("%d message(s) checked, %d changed", checked, changed)
+	if mailClassifyDryRun {
+		summary = fmt.Sprintf("Dry run: %d message(s) checked, %d would change", checked, changed)
+	}
+	if c.skipped > 0 {
+		summary += fmt.Sprintf("; %d forward, notify or exec action(s) skipped", c.skipped)
+	}
+	cmd.Println(summary + ".")
+	if journal != nil && changed > 0 {
+		cmd.Printf("Undo with 'goog mail classify undo %s'.\n", journal.ID)
+	}
+	return nil
+}
+
+// classifyStopped adds to err how to undo the part of a run that was
+// done before it stopped.
+func classifyStopped(journal *rules.Journal, changed int, err error) error {
+	if journal == nil || changed == 0 {
+		return err
+	}
+	return fmt.Errorf("%w; %d message(s) were changed before the run stopped, undo them with 'goog mail classify undo %s'", err, changed, journal.ID)
+}
+
+// runMailClassifyUndo handles the mail classify undo command.
+func runMailClassifyUndo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	dir := rules.JournalDir()
+
+	runs, err := rules.Journals(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list classify runs: %w", err)
+	}
+	if mailClassifyUndoList {
+		if len(runs) == 0 {
+			cmd.Println("No classify runs to undo.")
+			return nil
+		}
+		tw := newTableWriter(cmd.OutOrStdout())
+		_, _ = fmt.Fprintln(tw, "RUN\tMESSAGES")
+		for _, id := range runs {
+			entries, err := rules.ReadJournal(dir, id)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%d\n", id, len(entries))
+		}
+		return tw.Flush()
+	}
+
+	var id string
+	switch {
+	case len(args) == 1:
+		id = args[0]
+	case len(runs) > 0:
+		id = runs[len(runs)-1]
+	default:
+		return fmt.Errorf("no classify runs to undo")
+	}
+	entries, err := rules.ReadJournal(dir, id)
+	if err != nil {
+		return err
+	}
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	var queue labelQueue
+	for _, e := range entries {
+		queue.add(e.ID, e.Remove, e.Add)
+	}
+	if err := queue.apply(ctx, repo); err != nil {
+		return err
+	}
+	if err := rules.MarkUndone(dir, id); err != nil {
+		return err
+	}
+	if !quietFlag {
+		cmd.Printf("Undid classify run %s: restored the labels of %d message(s).\n", id, len(entries))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/rules"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// classifyMessageRepository returns its messages from Search in pages of
+// opts.MaxResults, with the offset as page token.
+type classifyMessageRepository struct {
+	MockMessageRepository
+	Queries []string
+}
+
+func (m *classifyMessageRepository) Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Queries = append(m.Queries, query)
+	start := 0
+	if opts.PageToken != "" {
+		start = len(opts.PageToken)
+	}
+	end := min(start+opts.MaxResults, len(m.Messages))
+	result := &mail.ListResult[*mail.Message]{Items: m.Messages[start:end]}
+	if end < len(m.Messages) {
+		result.NextPageToken = strings.Repeat("x", end)
+	}
+	return result, nil
+}
+
+// setupMailClassifyTest writes the rules file next to a temp config and
+// injects repositories holding four messages.
+func setupMailClassifyTest(t *testing.T) (*classifyMessageRepository, *namedLabelRepository, *cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+	if err := os.WriteFile(filepath.Join(dir, rules.FileName), []byte(testRulesFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &classifyMessageRepository{}
+	repo.Messages = []*mail.Message{
+		{ID: "m1", From: mail.Address{Email: "orders@shop.example.com"}, Subject: "Receipt", Labels: []string{"INBOX"}},
+		{ID: "m2", From: mail.Address{Email: "monitor@example.com"}, Subject: "Disk full", Labels: []string{"Label_9"}},
+		{ID: "m3", From: mail.Address{Email: "friend@example.com"}, Subject: "Hello", Labels: []string{"INBOX"}},
+		{ID: "m4", From: mail.Address{Email: "orders@shop.example.com"}, Subject: "Old receipt", Labels: []string{"Label_R"}},
+	}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	labelRepo.Labels = []*mail.Label{{ID: "INBOX", Name: "INBOX"}, {ID: "Label_9", Name: "Alerts"}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, LabelRepo: labelRepo},
+	})
+	t.Cleanup(ResetDependencies)
+
+	origRules, origSince, origQuery, origBatch, origDry, origList, origQuiet := mailClassifyRules, mailClassifySince, mailClassifyQuery, mailClassifyBatchSize, mailClassifyDryRun, mailClassifyUndoList, quietFlag
+	t.Cleanup(func() {
+		mailClassifyRules, mailClassifySince, mailClassifyQuery, mailClassifyBatchSize, mailClassifyDryRun, mailClassifyUndoList, quietFlag = origRules, origSince, origQuery, origBatch, origDry, origList, origQuiet
+	})
+	mailClassifyRules, mailClassifySince, mailClassifyQuery, mailClassifyBatchSize, mailClassifyDryRun, mailClassifyUndoList, quietFlag = "", "30d", "", 2, false, false, false
+
+	cmd := &cobra.Command{Use: "test"}
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	return repo, labelRepo, cmd, out, errOut
+}
+
+func TestRunMailClassify(t *testing.T) {
+	repo, labelRepo, cmd, out, errOut := setupMailClassifyTest(t)
+	labelRepo.ByName["Receipts"] = &mail.Label{ID: "Label_R", Name: "Receipts"}
+	mailClassifyQuery = "has:nouserlabels"
+
+	if err := runMailClassify(cmd, nil); err != nil {
+		t.Fatalf("runMailClassify failed: %v", err)
+	}
+
+	if len(repo.Queries) != 2 || !strings.HasPrefix(repo.Queries[0], "has:nouserlabels after:") {
+		t.Errorf("expected two batches of the query, got %q", repo.Queries)
+	}
+	// m4 already has the label and is archived; m2's actions are skipped
+	want := BatchModifyCall{IDs: []string{"m1"}, Req: mail.ModifyRequest{AddLabels: []string{"Label_R"}, RemoveLabels: []string{"INBOX"}}}
+	if len(repo.BatchModified) != 1 || !slices.Equal(repo.BatchModified[0].IDs, want.IDs) ||
+		!slices.Equal(repo.BatchModified[0].Req.AddLabels, want.Req.AddLabels) || !slices.Equal(repo.BatchModified[0].Req.RemoveLabels, want.Req.RemoveLabels) {
+		t.Fatalf("BatchModify calls = %+v, want %+v", repo.BatchModified, want)
+	}
+	for _, line := range []string{"Batch 1: 2 message(s) checked, 1 changed", "Batch 2: 4 message(s) checked, 1 changed"} {
+		if !strings.Contains(errOut.String(), line) {
+			t.Errorf("progress is missing %q:\n%s", line, errOut.String())
+		}
+	}
+	if !strings.Contains(out.String(), "4 message(s) checked, 1 changed; 3 forward, notify or exec action(s) skipped.") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+
+	runs, err := rules.Journals(rules.JournalDir())
+	if err != nil || len(runs) != 1 || !strings.Contains(out.String(), "goog mail classify undo "+runs[0]) {
+		t.Fatalf("expected one journal named in the output, got %v, %v:\n%s", runs, err, out.String())
+	}
+
+	repo.BatchModified = nil
+	if err := runMailClassifyUndo(cmd, nil); err != nil {
+		t.Fatalf("runMailClassifyUndo failed: %v", err)
+	}
+	if len(repo.BatchModified) != 1 || !slices.Equal(repo.BatchModified[0].Req.AddLabels, []string{"INBOX"}) || !slices.Equal(repo.BatchModified[0].Req.RemoveLabels, []string{"Label_R"}) {
+		t.Errorf("undo BatchModify calls = %+v", repo.BatchModified)
+	}
+	if err := runMailClassifyUndo(cmd, nil); err == nil || !strings.Contains(err.Error(), "no classify runs") {
+		t.Errorf("expected nothing left to undo, got %v", err)
+	}
+}
+
+func TestRunMailClassify_DryRun(t *testing.T) {
+	repo, labelRepo, cmd, out, _ := setupMailClassifyTest(t)
+	mailClassifyDryRun = true
+
+	if err := runMailClassify(cmd, nil); err != nil {
+		t.Fatalf("runMailClassify failed: %v", err)
+	}
+	if len(repo.BatchModified) != 0 || len(labelRepo.Created) != 0 {
+		t.Errorf("a dry run changed mail: %+v, created %v", repo.BatchModified, labelRepo.Created)
+	}
+	for _, line := range []string{
+		"Would label Receipts, archive: Receipt (rule Receipts)",
+		"Would label Receipts: Old receipt (rule Receipts)",
+		"Dry run: 4 message(s) checked, 2 would change",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output is missing %q:\n%s", line, out.String())
+		}
+	}
+	if _, err := os.Stat(rules.JournalDir()); !os.IsNotExist(err) {
+		t.Errorf("a dry run should keep no journal, got %v", err)
+	}
+}
+
+func TestRunMailClassifyUndo_List(t *testing.T) {
+	_, _, cmd, out, _ := setupMailClassifyTest(t)
+	mailClassifyUndoList = true
+
+	if err := runMailClassifyUndo(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "No classify runs to undo.") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	mailClassifyUndoList = false
+	if err := runMailClassify(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	mailClassifyUndoList = true
+	if err := runMailClassifyUndo(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "RUN") || !strings.Contains(out.String(), "2\n") {
+		t.Errorf("expected the run with its two messages:\n%s", out.String())
+	}
+}
@@ -4,29 +4,42 @@ package cli
 import (
 	"context"
 	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/outbox"
 )
 
 // Mail compose command flags.
 var (
 	// Send flags
-	mailSendTo      []string
-	mailSendCc      []string
-	mailSendBcc     []string
-	mailSendSubject string
-	mailSendBody    string
-	mailSendHTML    bool
+	mailSendTo       []string
+	mailSendCc       []string
+	mailSendBcc      []string
+	mailSendSubject  string
+	mailSendBody     string
+	mailSendHTML     bool
+	mailSendBodyHTML string
+	mailSendInline   []string
+	mailSendDryRun   bool
+	mailSendEditor   bool
+	mailSendAutosave time.Duration
 
 	// Reply flags
 	mailReplyBody string
 	mailReplyAll  bool
 
 	// Forward flags
-	mailForwardTo   []string
-	mailForwardBody string
+	mailForwardTo           []string
+	mailForwardBody         string
+	mailForwardAsAttachment bool
 )
 
 // mailSendCmd handles sending new messages.
@@ -36,7 +49,62 @@ var mailSendCmd = &cobra.Command{
 	Long: `Send a new email message.
 
 Compose and send a new email to one or more recipients.
-The --to flag is required and can be specified multiple times.`,
+The --to flag is required and can be specified multiple times.
+
+Use --body-html to read an HTML body from a file. Images can be
+embedded in an HTML body with --inline path=cid:name and referenced
+from the HTML as <img src="cid:name">. When the cid is omitted, the
+file name without its extension is used.
+
+Before sending, every recipient is parsed as an RFC 5322 address and its
+domain is compared with the domains you have sent to before and common
+providers, so that a typo such as gamil.com stops the send with a
+suggestion. --check-mx (or mail.check_mx) also checks that each domain
+has mail servers. --no-verify skips these checks.
+
+With --queue-offline (or mail.queue_offline), a message that cannot be
+sent because the network is unavailable is queued in the encrypted
+outbox; send it later with 'goog mail outbox flush'.
+
+--header adds a header such as Reply-To or X-Campaign; headers goog sets
+itself, such as From or Subject, are rejected. Headers in mail.headers
+are added to every message sent with send, reply or forward, unless a
+--header of the same name replaces them.
+
+A recipient of the form group:<name> is replaced by the members of a
+recipient group: a group defined with 'goog config set mail.groups.<name>',
+or else the Contacts group with that name (matched ignoring case, with
+spaces written as hyphens, so group:team-platform finds "Team Platform").
+Contacts without an email address are skipped with a warning, and an
+address already among the recipients is not added twice. Use --dry-run to
+see the expanded recipients without sending.
+
+--throttle (or mail.throttle) refuses to send to a recipient who was sent
+mail less than the given time ago, protecting against scripts that loop.
+mail.throttle_overrides sets other cooldowns for some addresses or
+domains; a cooldown of 0 exempts them. Set it for scripts only with
+GOOG_MAIL_SEND_THROTTLE=10m or a per-command setting.
+
+Addresses on the suppression list ('goog mail suppress', fed by 'goog
+mail bounces') are left out and reported; when every recipient is
+suppressed nothing is sent. --include-suppressed sends to them anyway.
+
+Addresses in mail.auto_bcc, such as a CRM dropbox, are blind-copied on
+every message sent with send, reply, forward, resend or watchdir, and
+shown by --dry-run. --no-auto-bcc leaves them out of one message.
+
+--duplicate-window (or mail.duplicate_window) refuses to send a message
+whose recipients, subject and body match a message in the sent mail of
+that period, such as a notification script run twice. --force sends it
+anyway.
+
+--editor opens $VISUAL or $EDITOR on the body, starting from --body, and
+sends what is saved when the editor exits. While the editor is open the
+message is saved as a Gmail draft every --autosave (30s by default), so a
+crashed terminal does not lose it. The draft is deleted once the message
+is sent or queued, or when the body is left empty, which discards the
+message. If sending fails the draft is kept; send it with 'goog draft
+send <id>'.`,
 	Example: `  # Send a simple message
   goog mail send --to user@example.com --subject "Hello" --body "Hi there!"
 
@@ -48,8 +116,32 @@ The --to flag is required and can be specified multiple times.`,
   goog mail send --to user@example.com --subject "Report" \
     --body "<h1>Report</h1><p>See attached.</p>" --html
 
+  # Send an HTML file with an inline image
+  goog mail send --to user@example.com --subject "Weekly report" \
+    --body-html report.html --inline chart.png=cid:chart
+
   # Send using a specific account
-  goog mail send --to user@example.com --subject "Hello" --body "Hi" --account work`,
+  goog mail send --to user@example.com --subject "Hello" --body "Hi" --account work
+
+  # Check recipient mail servers too, or skip all recipient checks
+  goog mail send --to user@example.com --subject "Hello" --body "Hi" --check-mx
+  goog mail send --to user@intranet.example --subject "Hello" --body "Hi" --no-verify
+
+  # Send to a Contacts group, checking the expansion first
+  goog mail send --to group:team-platform --subject "Release" --body "..." --dry-run
+
+  # Add custom headers
+  goog mail send --to user@example.com --subject "Launch" --body "..." \
+    --header "X-Campaign: launch" --header "Reply-To: support@example.com"
+
+  # From a script: do not send the same alert twice within an hour
+  goog mail send --to oncall@example.com --subject "Backup failed" --body "..." --duplicate-window 1h
+
+  # Write the body in your editor, autosaving a draft every minute
+  goog mail send --to user@example.com --subject "Proposal" --editor --autosave 1m
+
+  # From a script: mail each person at most once every 10 minutes
+  goog mail send --to oncall@example.com --subject "Disk full" --body "..." --throttle 10m`,
 	RunE: runMailSend,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if len(mailSendTo) == 0 {
@@ -67,10 +159,19 @@ var mailReplyCmd = &cobra.Command{
 
 Send a reply to the specified message. Use --all to reply to all
 recipients (reply-all). The reply will be part of the same thread
-as the original message.`,
+as the original message.
+
+--quote top adds the original below the reply, under a line such as
+"On Mon, Mar 2, 2026 at 9:00 AM, Ana <ana@example.com> wrote:", with each
+line prefixed by "> " (or --quote-prefix). --quote bottom puts the reply
+below the quote instead. mail.reply_quote and mail.quote_prefix set the
+defaults; --quote none leaves the original out.`,
 	Example: `  # Reply to a message
   goog mail reply abc123 --body "Thanks for your message!"
 
+  # Reply below the quoted original
+  goog mail reply abc123 --body "See my answers above." --quote bottom
+
   # Reply-all
   goog mail reply abc123 --body "I agree with everyone." --all
 
@@ -94,7 +195,11 @@ var mailForwardCmd = &cobra.Command{
 
 Forward the specified message to one or more recipients.
 The --to flag is required. An optional intro message can be
-added using --body.`,
+added using --body.
+
+--as-attachment attaches the original as a message/rfc822 part, with all
+its headers and attachments unchanged, instead of quoting its text. Use
+it for abuse reports and IT tickets, where the original headers matter.`,
 	Example: `  # Forward a message
   goog mail forward abc123 --to colleague@example.com
 
@@ -105,8 +210,14 @@ added using --body.`,
   # Forward to multiple recipients
   goog mail forward abc123 --to user1@example.com --to user2@example.com
 
+  # Report a phishing message with its original headers
+  goog mail forward abc123 --to abuse@example.com --as-attachment
+
   # Forward using a specific account
-  goog mail forward abc123 --to user@example.com --account work`,
+  goog mail forward abc123 --to user@example.com --account work
+
+  # Queue the forward if the network is down
+  goog mail forward abc123 --to user@example.com --queue-offline`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailForward,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -130,14 +241,38 @@ func init() {
 	mailSendCmd.Flags().StringVar(&mailSendSubject, "subject", "", "email subject")
 	mailSendCmd.Flags().StringVar(&mailSendBody, "body", "", "email body content")
 	mailSendCmd.Flags().BoolVar(&mailSendHTML, "html", false, "treat body as HTML content")
+	mailSendCmd.Flags().StringVar(&mailSendBodyHTML, "body-html", "", "read HTML body content from file")
+	mailSendCmd.Flags().StringArrayVar(&mailSendInline, "inline", nil, "inline image as path=cid:name (repeatable)")
+	mailSendCmd.Flags().BoolVar(&mailSendDryRun, "dry-run", false, "show the recipients, with groups expanded, without sending")
+	mailSendCmd.Flags().BoolVar(&mailSendEditor, "editor", false, "write the body in $VISUAL or $EDITOR, starting from --body")
+	mailSendCmd.Flags().DurationVar(&mailSendAutosave, "autosave", defaultAutosaveInterval, "with --editor, save the message as a draft this often (0 to only save it if sending fails)")
+	addVerifyFlags(mailSendCmd)
+	addQueueFlag(mailSendCmd)
+	addHeaderFlag(mailSendCmd)
+	addThrottleFlag(mailSendCmd)
+	addDuplicateFlags(mailSendCmd)
+	addSuppressFlag(mailSendCmd)
+	addAutoBccFlag(mailSendCmd)
 
 	// Reply command flags
 	mailReplyCmd.Flags().StringVar(&mailReplyBody, "body", "", "reply body content (required)")
 	mailReplyCmd.Flags().BoolVar(&mailReplyAll, "all", false, "reply to all recipients")
+	addHeaderFlag(mailReplyCmd)
+	addThrottleFlag(mailReplyCmd)
+	addSuppressFlag(mailReplyCmd)
+	addAutoBccFlag(mailReplyCmd)
+	addQuoteFlags(mailReplyCmd)
 
 	// Forward command flags
 	mailForwardCmd.Flags().StringSliceVar(&mailForwardTo, "to", nil, "recipient email address(es) (required)")
 	mailForwardCmd.Flags().StringVar(&mailForwardBody, "body", "", "intro message to add before forwarded content")
+	mailForwardCmd.Flags().BoolVar(&mailForwardAsAttachment, "as-attachment", false, "attach the original message (message/rfc822) instead of quoting it")
+	addVerifyFlags(mailForwardCmd)
+	addQueueFlag(mailForwardCmd)
+	addHeaderFlag(mailForwardCmd)
+	addThrottleFlag(mailForwardCmd)
+	addSuppressFlag(mailForwardCmd)
+	addAutoBccFlag(mailForwardCmd)
 }
 
 // runMailSend handles the mail send command.
@@ -150,42 +285,160 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Parse and validate recipients
-	toRecipients, err := parseEmailRecipients(mailSendTo)
+	// Expand recipient groups, then parse and validate recipients
+	lists, expansions, err := expandRecipientGroups(ctx, mailSendTo, mailSendCc, mailSendBcc)
+	if err != nil {
+		return err
+	}
+	if !mailSendDryRun {
+		for _, e := range expansions {
+			if len(e.Skipped) > 0 {
+				output.Warnf(cmd, "group %s: skipped %d contact(s) without an email address: %s", e.Group, len(e.Skipped), strings.Join(e.Skipped, ", "))
+			}
+		}
+	}
+
+	toRecipients, err := parseEmailRecipients(lists[0])
 	if err != nil {
 		return fmt.Errorf("invalid 'to' recipient: %w", err)
 	}
 
-	ccRecipients, err := parseEmailRecipients(mailSendCc)
+	ccRecipients, err := parseEmailRecipients(lists[1])
 	if err != nil {
 		return fmt.Errorf("invalid 'cc' recipient: %w", err)
 	}
 
-	bccRecipients, err := parseEmailRecipients(mailSendBcc)
+	bccRecipients, err := parseEmailRecipients(lists[2])
 	if err != nil {
 		return fmt.Errorf("invalid 'bcc' recipient: %w", err)
 	}
 
+	kept, err := dropSuppressed(cmd, toRecipients, ccRecipients, bccRecipients)
+	if err != nil {
+		return err
+	}
+	toRecipients, ccRecipients, bccRecipients = kept[0], kept[1], kept[2]
+
+	if err := verifyRecipients(ctx, cmd, senderEmail, toRecipients, ccRecipients, bccRecipients); err != nil {
+		return err
+	}
+	if err := checkThrottle(cmd, toRecipients, ccRecipients, bccRecipients); err != nil {
+		return err
+	}
+
+	headers, err := messageHeaders(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Build message
 	msg := &mail.Message{
-		From:    senderEmail,
-		To:      toRecipients,
-		Cc:      ccRecipients,
-		Bcc:     bccRecipients,
+		From:    mail.ParseAddress(senderEmail),
+		To:      mail.ParseAddresses(toRecipients),
+		Cc:      mail.ParseAddresses(ccRecipients),
+		Bcc:     mail.ParseAddresses(bccRecipients),
 		Subject: mailSendSubject,
+		Headers: headers,
 	}
 
-	if mailSendHTML {
+	autoBcc := msg.AddAutoBcc(autoBccAddresses(cmd))
+
+	// saver keeps the message written with --editor as a draft until it
+	// is sent
+	var saver *draftAutosaver
+	switch {
+	case mailSendBodyHTML != "":
+		if mailSendBody != "" || mailSendHTML {
+			return fmt.Errorf("--body-html cannot be combined with --body or --html")
+		}
+		if mailSendEditor {
+			return fmt.Errorf("--body-html cannot be combined with --editor")
+		}
+		content, err := os.ReadFile(mailSendBodyHTML)
+		if err != nil {
+			return fmt.Errorf("failed to read HTML body: %w", err)
+		}
+		msg.BodyHTML = string(content)
+	case mailSendEditor:
+		if !mailSendDryRun {
+			// Drafts need gmail.compose; without it the message is still
+			// written and sent, but not kept as a draft.
+			if err := requireScope(auth.ScopeGmailCompose, "mail send"); err != nil {
+				output.Warnf(cmd, "autosave is off and the message is lost if sending fails: %v", err)
+			} else {
+				draftRepo, err := getDraftRepositoryFromDeps(ctx)
+				if err != nil {
+					return err
+				}
+				saver = &draftAutosaver{ctx: ctx, repo: draftRepo, msg: msg, html: mailSendHTML}
+			}
+		}
+		body, err := composeInEditor(cmd, mailSendBody, saver, mailSendAutosave)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(body) == "" {
+			if err := saver.discard(); err != nil {
+				output.Warnf(cmd, "%v", err)
+			}
+			return fmt.Errorf("message body is empty; not sent")
+		}
+		if mailSendHTML {
+			msg.BodyHTML = body
+		} else {
+			msg.Body = body
+		}
+	case mailSendHTML:
 		msg.BodyHTML = mailSendBody
-	} else {
+	default:
 		msg.Body = mailSendBody
 	}
+	body := msg.Body + msg.BodyHTML
+
+	if len(mailSendInline) > 0 {
+		if msg.BodyHTML == "" {
+			return saver.finish(cmd, body, fmt.Errorf("--inline requires an HTML body (use --body-html or --html)"))
+		}
+		for _, spec := range mailSendInline {
+			att, err := loadInlineAttachment(spec)
+			if err != nil {
+				return saver.finish(cmd, body, err)
+			}
+			if !strings.Contains(msg.BodyHTML, "cid:"+att.ContentID) {
+				return saver.finish(cmd, body, fmt.Errorf("inline image %q is not referenced as cid:%s in the HTML body", att.Filename, att.ContentID))
+			}
+			msg.Attachments = append(msg.Attachments, att)
+		}
+	}
+
+	if mailSendDryRun {
+		printGroupExpansions(cmd, expansions)
+		cmd.Printf("To: %s\n", strings.Join(toRecipients, ", "))
+		if len(ccRecipients) > 0 {
+			cmd.Printf("Cc: %s\n", strings.Join(ccRecipients, ", "))
+		}
+		if len(bccRecipients) > 0 {
+			cmd.Printf("Bcc: %s\n", strings.Join(bccRecipients, ", "))
+		}
+		if len(autoBcc) > 0 {
+			cmd.Printf("Auto Bcc: %s (mail.auto_bcc)\n", strings.Join(autoBcc, ", "))
+		}
+		cmd.Printf("Subject: %s\n", msg.Subject)
+		cmd.Println("Dry run: message not sent")
+		return nil
+	}
+
+	if err := checkDuplicate(ctx, cmd, "mail send", repo, msg); err != nil {
+		return saver.finish(cmd, body, err)
+	}
 
 	// Send message
 	sent, err := repo.Send(ctx, msg)
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return saver.finish(cmd, body, queueOrFail(cmd, &outbox.Entry{Kind: outbox.KindSend, Account: senderEmail, Message: msg}, "send message", err))
 	}
+	recordRecipients(toRecipients, ccRecipients, bccRecipients)
+	_ = saver.finish(cmd, body, nil)
 
 	cmd.Printf("Message sent successfully.\n")
 	cmd.Printf("Message ID: %s\n", sent.ID)
@@ -205,32 +458,56 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	headers, err := messageHeaders(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Get original message to determine recipients
 	original, err := repo.Get(ctx, messageID)
 	if err != nil {
 		return fmt.Errorf("failed to get original message: %w", err)
 	}
 
+	body, err := quoteReplyBody(cmd, mailReplyBody, original)
+	if err != nil {
+		return err
+	}
+
 	// Build reply message
 	reply := &mail.Message{
-		From:    senderEmail,
-		Body:    mailReplyBody,
+		From:    mail.ParseAddress(senderEmail),
+		Body:    body,
 		Subject: buildReplySubject(original.Subject),
+		Headers: headers,
 	}
 
 	// Set recipients based on reply-all flag
+	var to, cc []string
 	if mailReplyAll {
 		// Reply to sender and all original recipients (except ourselves)
-		reply.To = []string{original.From}
-		for _, to := range original.To {
-			if to != senderEmail {
-				reply.To = append(reply.To, to)
+		to = []string{original.From.String()}
+		for _, addr := range original.To {
+			if !strings.EqualFold(addr.Email, senderEmail) {
+				to = append(to, addr.String())
 			}
 		}
-		reply.Cc = original.Cc
+		cc = mail.AddressStrings(original.Cc)
 	} else {
 		// Reply only to sender
-		reply.To = []string{original.From}
+		to = []string{original.From.String()}
+	}
+
+	kept, err := dropSuppressed(cmd, to, cc)
+	if err != nil {
+		return err
+	}
+	to, cc = kept[0], kept[1]
+	reply.To, reply.Cc = mail.ParseAddresses(to), mail.ParseAddresses(cc)
+	reply.AddAutoBcc(autoBccAddresses(cmd))
+
+	if err := checkThrottle(cmd, to, cc); err != nil {
+		return err
 	}
 
 	// Send reply
@@ -238,6 +515,7 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to send reply: %w", err)
 	}
+	recordRecipients(to, cc)
 
 	cmd.Printf("Reply sent successfully.\n")
 	cmd.Printf("Message ID: %s\n", sent.ID)
@@ -263,18 +541,48 @@ func runMailForward(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid 'to' recipient: %w", err)
 	}
 
+	kept, err := dropSuppressed(cmd, toRecipients)
+	if err != nil {
+		return err
+	}
+	toRecipients = kept[0]
+
+	if err := verifyRecipients(ctx, cmd, senderEmail, toRecipients); err != nil {
+		return err
+	}
+	if err := checkThrottle(cmd, toRecipients); err != nil {
+		return err
+	}
+
+	headers, err := messageHeaders(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Build forward message
 	forward := &mail.Message{
-		From: senderEmail,
-		To:   toRecipients,
-		Body: mailForwardBody,
+		From:    mail.ParseAddress(senderEmail),
+		To:      mail.ParseAddresses(toRecipients),
+		Body:    mailForwardBody,
+		Headers: headers,
 	}
+	forward.AddAutoBcc(autoBccAddresses(cmd))
 
 	// Send forward
-	sent, err := repo.Forward(ctx, messageID, forward)
-	if err != nil {
-		return fmt.Errorf("failed to forward message: %w", err)
+	var sent *mail.Message
+	if mailForwardAsAttachment {
+		if err := attachOriginal(ctx, repo, messageID, forward); err != nil {
+			return err
+		}
+		// The message is complete, so it is queued as a plain send
+		if sent, err = repo.Send(ctx, forward); err != nil {
+			return queueOrFail(cmd, &outbox.Entry{Kind: outbox.KindSend, Account: senderEmail, Message: forward}, "forward message", err)
+		}
+	} else if sent, err = repo.Forward(ctx, messageID, forward); err != nil {
+		entry := &outbox.Entry{Kind: outbox.KindForward, MessageID: messageID, Account: senderEmail, Message: forward}
+		return queueOrFail(cmd, entry, "forward message", err)
 	}
+	recordRecipients(toRecipients)
 
 	cmd.Printf("Message forwarded successfully.\n")
 	cmd.Printf("Message ID: %s\n", sent.ID)
@@ -283,6 +591,32 @@ func runMailForward(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// attachOriginal attaches the raw original message to forward as a
+// message/rfc822 part named after its subject, and sets the forward's
+// subject as Forward does.
+func attachOriginal(ctx context.Context, repo MessageRepository, messageID string, forward *mail.Message) error {
+	original, err := repo.Get(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get original message: %w", err)
+	}
+	raw, err := repo.GetRaw(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get raw message: %w", err)
+	}
+
+	name := sanitizeFilename(original.Subject)
+	if name == "" {
+		name = "message"
+	}
+	att := mail.NewAttachment("", name+".eml", "message/rfc822")
+	att.SetData(raw)
+	forward.Attachments = append(forward.Attachments, att)
+	if forward.Subject == "" {
+		forward.Subject = "Fwd: " + original.Subject
+	}
+	return nil
+}
+
 // parseEmailRecipients cleans, validates, and returns email recipients.
 // Returns an error if any email address is invalid.
 func parseEmailRecipients(recipients []string) ([]string, error) {
@@ -310,3 +644,59 @@ func buildReplySubject(subject string) string {
 	}
 	return "Re: " + subject
 }
+
+// parseInlineSpec parses an --inline value of the form path=cid:name.
+// The "cid:" prefix is optional, and when no name is given the file name
+// without its extension is used.
+func parseInlineSpec(spec string) (path, contentID string, err error) {
+	path = spec
+	if idx := strings.LastIndex(spec, "="); idx >= 0 {
+		path = spec[:idx]
+		contentID = strings.TrimPrefix(strings.TrimSpace(spec[idx+1:]), "cid:")
+		if contentID == "" {
+			return "", "", fmt.Errorf("invalid inline image %q: missing content ID", spec)
+		}
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", "", fmt.Errorf("invalid inline image %q: missing file path", spec)
+	}
+
+	if contentID == "" {
+		base := filepath.Base(path)
+		contentID = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if strings.ContainsAny(contentID, " <>\"") {
+		return "", "", fmt.Errorf("invalid inline image %q: content ID %q contains invalid characters", spec, contentID)
+	}
+
+	return path, contentID, nil
+}
+
+// loadInlineAttachment reads the file named by an --inline value into an inline attachment.
+func loadInlineAttachment(spec string) (*mail.Attachment, error) {
+	path, contentID, err := parseInlineSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inline image: %w", err)
+	}
+
+	att := mail.NewAttachment("", filepath.Base(path), detectMimeType(path, data))
+	att.ContentID = contentID
+	att.SetData(data)
+	return att, nil
+}
+
+// detectMimeType returns the MIME type for a file from its extension,
+// falling back to sniffing its content.
+func detectMimeType(path string, data []byte) string {
+	if mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(data)
+}
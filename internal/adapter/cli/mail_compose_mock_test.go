@@ -3,7 +3,10 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -80,8 +83,8 @@ func TestRunMailSend_WithMockDependencies(t *testing.T) {
 func TestRunMailReply_WithMockDependencies(t *testing.T) {
 	originalMsg := &mail.Message{
 		ID:      "original-id",
-		From:    "sender@example.com",
-		To:      []string{"me@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "me@example.com"}},
 		Subject: "Original Subject",
 	}
 
@@ -183,13 +186,55 @@ func TestRunMailForward_WithMockDependencies(t *testing.T) {
 	}
 }
 
+func TestRunMailForward_AsAttachment(t *testing.T) {
+	raw := []byte("From: phisher@example.net\r\nSubject: Verify: your account\r\n\r\nClick here\r\n")
+	repo := &sendRecordingRepository{MockMessageRepository: MockMessageRepository{
+		Message: &mail.Message{ID: "original-id", Subject: "Verify: your account"},
+		Raw:     raw,
+	}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+	defer ResetDependencies()
+
+	origTo, origBody, origAttach := mailForwardTo, mailForwardBody, mailForwardAsAttachment
+	mailForwardTo, mailForwardBody, mailForwardAsAttachment = []string{"abuse@example.com"}, "Phishing report", true
+	defer func() {
+		mailForwardTo, mailForwardBody, mailForwardAsAttachment = origTo, origBody, origAttach
+	}()
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+	if err := runMailForward(cmd, []string{"original-id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 {
+		t.Fatalf("expected the forward to be sent as a new message, got %d", len(repo.Sent))
+	}
+	sent := repo.Sent[0]
+	if sent.Subject != "Fwd: Verify: your account" || sent.Body != "Phishing report" {
+		t.Errorf("subject %q, body %q", sent.Subject, sent.Body)
+	}
+	if len(sent.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %d", len(sent.Attachments))
+	}
+	att := sent.Attachments[0]
+	if att.MimeType != "message/rfc822" || att.Filename != "Verify_ your account.eml" || !bytes.Equal(att.Data, raw) {
+		t.Errorf("attachment = %s (%s), %q", att.Filename, att.MimeType, att.Data)
+	}
+}
+
 func TestMockMessageRepository_Send(t *testing.T) {
 	t.Run("Send success", func(t *testing.T) {
 		repo := &MockMessageRepository{
 			SendResult: &mail.Message{ID: "sent-id"},
 		}
 
-		msg := &mail.Message{To: []string{"user@example.com"}, Subject: "Test"}
+		msg := &mail.Message{To: []mail.Address{{Email: "user@example.com"}}, Subject: "Test"}
 		result, err := repo.Send(nil, msg)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -202,7 +247,7 @@ func TestMockMessageRepository_Send(t *testing.T) {
 	t.Run("Send error", func(t *testing.T) {
 		repo := &MockMessageRepository{SendErr: fmt.Errorf("send error")}
 
-		msg := &mail.Message{To: []string{"user@example.com"}}
+		msg := &mail.Message{To: []mail.Address{{Email: "user@example.com"}}}
 		_, err := repo.Send(nil, msg)
 		if err == nil {
 			t.Error("expected error, got nil")
@@ -243,7 +288,7 @@ func TestMockMessageRepository_Forward(t *testing.T) {
 			ForwardResult: &mail.Message{ID: "forward-id"},
 		}
 
-		forward := &mail.Message{To: []string{"user@example.com"}}
+		forward := &mail.Message{To: []mail.Address{{Email: "user@example.com"}}}
 		result, err := repo.Forward(nil, "msg-id", forward)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -256,7 +301,7 @@ func TestMockMessageRepository_Forward(t *testing.T) {
 	t.Run("Forward error", func(t *testing.T) {
 		repo := &MockMessageRepository{ForwardErr: fmt.Errorf("forward error")}
 
-		forward := &mail.Message{To: []string{"user@example.com"}}
+		forward := &mail.Message{To: []mail.Address{{Email: "user@example.com"}}}
 		_, err := repo.Forward(nil, "msg-id", forward)
 		if err == nil {
 			t.Error("expected error, got nil")
@@ -648,8 +693,8 @@ func TestRunMailReply_GetOriginalError(t *testing.T) {
 func TestRunMailReply_RepositoryError(t *testing.T) {
 	originalMsg := &mail.Message{
 		ID:      "original-id",
-		From:    "sender@example.com",
-		To:      []string{"me@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "me@example.com"}},
 		Subject: "Original Subject",
 	}
 
@@ -693,9 +738,9 @@ func TestRunMailReply_RepositoryError(t *testing.T) {
 func TestRunMailReply_ReplyAll(t *testing.T) {
 	originalMsg := &mail.Message{
 		ID:      "original-id",
-		From:    "sender@example.com",
-		To:      []string{"me@example.com", "other@example.com"},
-		Cc:      []string{"cc@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "me@example.com"}, {Email: "other@example.com"}},
+		Cc:      []mail.Address{{Email: "cc@example.com"}},
 		Subject: "Original Subject",
 	}
 
@@ -963,3 +1008,153 @@ func TestMailReplyCmd_AllFlag(t *testing.T) {
 		t.Errorf("expected default all to be 'false', got '%s'", flag.DefValue)
 	}
 }
+
+// captureSendRepository records the message passed to Send.
+type captureSendRepository struct {
+	MockMessageRepository
+	Sent *mail.Message
+}
+
+func (m *captureSendRepository) Send(ctx context.Context, msg *mail.Message) (*mail.Message, error) {
+	m.Sent = msg
+	return &mail.Message{ID: "sent-id", ThreadID: "thread-id"}, nil
+}
+
+// setupMailSendInlineTest injects a capturing repository and resets send flags.
+func setupMailSendInlineTest(t *testing.T) *captureSendRepository {
+	t.Helper()
+
+	repo := &captureSendRepository{}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "sender@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origTo, origCc, origBcc, origSubject := mailSendTo, mailSendCc, mailSendBcc, mailSendSubject
+	origBody, origHTML, origBodyHTML, origInline := mailSendBody, mailSendHTML, mailSendBodyHTML, mailSendInline
+	mailSendTo = []string{"recipient@example.com"}
+	mailSendCc, mailSendBcc = nil, nil
+	mailSendSubject = "Report"
+	mailSendBody, mailSendHTML, mailSendBodyHTML, mailSendInline = "", false, "", nil
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailSendTo, mailSendCc, mailSendBcc, mailSendSubject = origTo, origCc, origBcc, origSubject
+		mailSendBody, mailSendHTML, mailSendBodyHTML, mailSendInline = origBody, origHTML, origBodyHTML, origInline
+	})
+
+	return repo
+}
+
+func TestRunMailSend_BodyHTMLWithInlineImage(t *testing.T) {
+	repo := setupMailSendInlineTest(t)
+
+	dir := t.TempDir()
+	htmlPath := filepath.Join(dir, "report.html")
+	imagePath := filepath.Join(dir, "chart.png")
+	if err := os.WriteFile(htmlPath, []byte(`<h1>Report</h1><img src="cid:chart">`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(imagePath, []byte("\x89PNG\r\n\x1a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mailSendBodyHTML = htmlPath
+	mailSendInline = []string{imagePath + "=cid:chart"}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.Sent == nil {
+		t.Fatal("expected message to be sent")
+	}
+	if !contains(repo.Sent.BodyHTML, "cid:chart") {
+		t.Errorf("BodyHTML = %q, want file contents", repo.Sent.BodyHTML)
+	}
+	if len(repo.Sent.Attachments) != 1 {
+		t.Fatalf("expected 1 inline attachment, got %d", len(repo.Sent.Attachments))
+	}
+	att := repo.Sent.Attachments[0]
+	if att.ContentID != "chart" || att.Filename != "chart.png" || att.MimeType != "image/png" || !att.HasData() {
+		t.Errorf("unexpected inline attachment: %+v", att)
+	}
+}
+
+func TestRunMailSend_InlineErrors(t *testing.T) {
+	dir := t.TempDir()
+	htmlPath := filepath.Join(dir, "report.html")
+	imagePath := filepath.Join(dir, "chart.png")
+	if err := os.WriteFile(htmlPath, []byte(`<img src="cid:other">`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(imagePath, []byte("png"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func()
+		wantErr string
+	}{
+		{
+			name: "inline without HTML body",
+			setup: func() {
+				mailSendBody = "plain"
+				mailSendInline = []string{imagePath}
+			},
+			wantErr: "--inline requires an HTML body",
+		},
+		{
+			name: "body-html combined with body",
+			setup: func() {
+				mailSendBody = "plain"
+				mailSendBodyHTML = htmlPath
+			},
+			wantErr: "cannot be combined",
+		},
+		{
+			name: "missing HTML file",
+			setup: func() {
+				mailSendBodyHTML = filepath.Join(dir, "missing.html")
+			},
+			wantErr: "failed to read HTML body",
+		},
+		{
+			name: "unreferenced content ID",
+			setup: func() {
+				mailSendBodyHTML = htmlPath
+				mailSendInline = []string{imagePath + "=cid:chart"}
+			},
+			wantErr: "is not referenced as cid:chart",
+		},
+		{
+			name: "missing image file",
+			setup: func() {
+				mailSendBodyHTML = htmlPath
+				mailSendInline = []string{filepath.Join(dir, "missing.png") + "=cid:other"}
+			},
+			wantErr: "failed to read inline image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := setupMailSendInlineTest(t)
+			tt.setup()
+
+			err := runMailSend(&cobra.Command{Use: "test"}, nil)
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if repo.Sent != nil {
+				t.Error("expected no message to be sent")
+			}
+		})
+	}
+}
@@ -1453,3 +1453,43 @@ func TestMailComposeCmd_Aliases(t *testing.T) {
 		}
 	})
 }
+
+func TestParseInlineSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+		path    string
+		cid     string
+	}{
+		{spec: "chart.png=cid:chart", path: "chart.png", cid: "chart"},
+		{spec: "img/logo.png=logo", path: "img/logo.png", cid: "logo"},
+		{spec: "img/logo.png", path: "img/logo.png", cid: "logo"},
+		{spec: "a=b.png=cid:x", path: "a=b.png", cid: "x"},
+		{spec: "chart.png=cid:", wantErr: true},
+		{spec: "=cid:chart", wantErr: true},
+		{spec: "chart.png=cid:bad id", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			path, cid, err := parseInlineSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseInlineSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if path != tt.path || cid != tt.cid {
+				t.Errorf("parseInlineSpec(%q) = (%q, %q), want (%q, %q)", tt.spec, path, cid, tt.path, tt.cid)
+			}
+		})
+	}
+}
+
+func TestMailSendCmd_InlineFlags(t *testing.T) {
+	for _, name := range []string{"body-html", "inline"} {
+		if mailSendCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected mail send to have --%s flag", name)
+		}
+	}
+}
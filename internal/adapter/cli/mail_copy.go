@@ -0,0 +1,256 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// Command flags for mail copy command.
+var (
+	mailCopyToAccount string
+	mailCopyMap       []string
+	mailCopyDryRun    bool
+)
+
+// mailCopyCmd copies messages from one account into another.
+var mailCopyCmd = &cobra.Command{
+	Use:   "copy <message-id>...",
+	Short: "Copy messages into another account",
+	Long: `Copy messages from the current account into another account.
+
+Each message is fetched in raw form and imported into the account named
+by --to-account, unchanged and with its original date, as if it had been
+received there. Its labels are translated by name: entries from the
+destination's accounts.<alias>.label_map config and --map flags, of the
+form Source=Target, rename labels. An empty target drops a label, and a
+Source/* entry covers nested labels, keeping the nested part when the
+target also ends in /*. Unmapped labels keep their names. Labels missing
+in the destination are created. --map entries take precedence over the
+config.
+
+The source message is left as it is.`,
+	Example: `  # Copy a message into the personal account
+  goog mail copy 18c1234abcd --account work --to-account personal
+
+  # File copies under Archive/Work and keep them out of the inbox
+  goog mail copy 18c1234abcd --to-account personal \
+    --map "Work/*=Archive/Work/*" --map "INBOX="
+
+  # Show how labels would be translated without copying
+  goog mail copy 18c1234abcd --to-account personal --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailCopy,
+}
+
+func init() {
+	mailCmd.AddCommand(mailCopyCmd)
+
+	mailCopyCmd.Flags().StringVar(&mailCopyToAccount, "to-account", "", "account to copy messages into (required)")
+	mailCopyCmd.Flags().StringArrayVar(&mailCopyMap, "map", nil, "label mapping as Source=Target (repeatable)")
+	mailCopyCmd.Flags().BoolVar(&mailCopyDryRun, "dry-run", false, "show the translated labels without copying")
+	_ = mailCopyCmd.MarkFlagRequired("to-account")
+}
+
+// copiedMessage is the result of copying one message.
+type copiedMessage struct {
+	SourceID  string   `json:"source_id"`
+	MessageID string   `json:"message_id,omitempty"`
+	Labels    []string `json:"labels"`
+}
+
+// runMailCopy handles the mail copy command.
+func runMailCopy(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	deps := GetDependencies()
+
+	source, err := deps.AccountService.ResolveAccount(accountFlag)
+	if err != nil {
+		return fmt.Errorf("no account found: %w (run 'goog auth login' to authenticate)", err)
+	}
+	dest, err := deps.AccountService.ResolveAccount(mailCopyToAccount)
+	if err != nil {
+		return fmt.Errorf("destination account %q not found: %w", mailCopyToAccount, err)
+	}
+	if dest.Alias == source.Alias {
+		return fmt.Errorf("cannot copy messages into the account they are in (%s)", source.Alias)
+	}
+
+	mapping, err := copyLabelMapping(dest.Alias)
+	if err != nil {
+		return err
+	}
+
+	// Read everything from the source first: the repositories for the
+	// destination are created with its read-only mode and endpoints.
+	srcMessages, srcLabels, err := mailRepositoriesForAccount(ctx, source)
+	if err != nil {
+		return err
+	}
+	labelNames, err := labelNamesByID(ctx, srcLabels)
+	if err != nil {
+		return err
+	}
+
+	type pending struct {
+		id     string
+		raw    []byte
+		labels []string
+	}
+	messages := make([]pending, 0, len(args))
+	for _, id := range args {
+		msg, err := srcMessages.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get message %s: %w", id, err)
+		}
+		raw, err := srcMessages.GetRaw(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get raw message %s: %w", id, err)
+		}
+		names := make([]string, 0, len(msg.Labels))
+		for _, labelID := range msg.Labels {
+			if name, ok := labelNames[labelID]; ok {
+				names = append(names, name)
+			} else {
+				names = append(names, labelID)
+			}
+		}
+		messages = append(messages, pending{id: id, raw: raw, labels: mail.TranslateLabels(names, mapping)})
+	}
+
+	if mailCopyDryRun {
+		results := make([]copiedMessage, 0, len(messages))
+		for _, m := range messages {
+			results = append(results, copiedMessage{SourceID: m.id, Labels: m.labels})
+		}
+		return printCopiedMessages(cmd, results, dest.Alias, true)
+	}
+
+	repository.SetReadOnly(readOnlyFlag || dest.ReadOnly)
+	repository.SetEndpoints(dest.Endpoints)
+	destMessages, destLabels, err := mailRepositoriesForAccount(ctx, dest)
+	if err != nil {
+		return err
+	}
+	labelIDs, err := labelIDsByName(ctx, destLabels)
+	if err != nil {
+		return err
+	}
+
+	results := make([]copiedMessage, 0, len(messages))
+	for _, m := range messages {
+		ids := make([]string, 0, len(m.labels))
+		for _, name := range m.labels {
+			id, ok := labelIDs[strings.ToLower(name)]
+			if !ok {
+				created, err := destLabels.Create(ctx, mail.NewLabel("", name))
+				if err != nil {
+					return fmt.Errorf("failed to create label %q in %s: %w", name, dest.Alias, err)
+				}
+				id = created.ID
+				labelIDs[strings.ToLower(name)] = id
+				if !quietFlag && formatFlag != presenter.FormatJSON {
+					cmd.Printf("Created label %q in %s.\n", name, dest.Alias)
+				}
+			}
+			ids = append(ids, id)
+		}
+
+		imported, err := destMessages.Import(ctx, m.raw, ids)
+		if err != nil {
+			return fmt.Errorf("failed to import message %s into %s: %w", m.id, dest.Alias, err)
+		}
+		results = append(results, copiedMessage{SourceID: m.id, MessageID: imported.ID, Labels: m.labels})
+	}
+	return printCopiedMessages(cmd, results, dest.Alias, false)
+}
+
+// copyLabelMapping returns the label map for messages copied into the
+// account alias: its configured label_map overridden by --map flags.
+func copyLabelMapping(alias string) (map[string]string, error) {
+	flagEntries, err := config.ParseLabelMap(strings.Join(mailCopyMap, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := map[string]string{}
+	if cfg, err := config.Load(); err == nil {
+		if acc, ok := cfg.Accounts[alias]; ok {
+			maps.Copy(mapping, acc.LabelMapping())
+		}
+	}
+	maps.Copy(mapping, config.AccountConfig{LabelMap: flagEntries}.LabelMapping())
+	return mapping, nil
+}
+
+// mailRepositoriesForAccount creates message and label repositories for
+// acc rather than the account selected with --account.
+func mailRepositoriesForAccount(ctx context.Context, acc *accountuc.Account) (MessageRepository, LabelRepository, error) {
+	deps := GetDependencies()
+	tokenSource, err := deps.AccountService.GetTokenManager().GetTokenSource(ctx, acc.Alias)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get token for %s: %w (run 'goog auth login --account %s' to authenticate)", acc.Alias, err, acc.Alias)
+	}
+	messages, err := deps.RepoFactory.NewMessageRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create message repository: %w", err)
+	}
+	labels, err := deps.RepoFactory.NewLabelRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create label repository: %w", err)
+	}
+	return messages, labels, nil
+}
+
+// labelIDsByName maps the lower-cased names of an account's labels to
+// their IDs. System labels are also found by ID, which is how the label
+// map names them.
+func labelIDsByName(ctx context.Context, repo LabelRepository) (map[string]string, error) {
+	labels, err := repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	ids := make(map[string]string, len(labels))
+	for _, label := range labels {
+		if label.IsSystemLabel() {
+			ids[strings.ToLower(label.ID)] = label.ID
+		}
+		ids[strings.ToLower(label.Name)] = label.ID
+	}
+	return ids, nil
+}
+
+// printCopiedMessages prints the results of mail copy.
+func printCopiedMessages(cmd *cobra.Command, results []copiedMessage, alias string, dryRun bool) error {
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	for _, r := range results {
+		labels := strings.Join(r.Labels, ", ")
+		if labels == "" {
+			labels = "none"
+		}
+		if dryRun {
+			cmd.Printf("Would copy %s to %s with labels: %s\n", r.SourceID, alias, labels)
+		} else {
+			cmd.Printf("Copied %s to %s as %s with labels: %s\n", r.SourceID, alias, r.MessageID, labels)
+		}
+	}
+	return nil
+}
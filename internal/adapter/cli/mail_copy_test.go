@@ -0,0 +1,206 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+	"golang.org/x/oauth2"
+)
+
+// aliasTokenSource is a token source that remembers its account.
+type aliasTokenSource string
+
+func (s aliasTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: string(s)}, nil
+}
+
+// copyTokenManager hands out an aliasTokenSource per account.
+type copyTokenManager struct {
+	MockTokenManager
+}
+
+func (m *copyTokenManager) GetTokenSource(ctx context.Context, alias string) (oauth2.TokenSource, error) {
+	return aliasTokenSource(alias), nil
+}
+
+// copyAccountService resolves accounts by alias.
+type copyAccountService struct {
+	MockAccountService
+	accounts map[string]*accountuc.Account
+}
+
+func (m *copyAccountService) ResolveAccount(flagValue string) (*accountuc.Account, error) {
+	if flagValue == "" {
+		flagValue = "work"
+	}
+	if acc, ok := m.accounts[flagValue]; ok {
+		return acc, nil
+	}
+	return nil, account.ErrAccountNotFound
+}
+
+func (m *copyAccountService) GetTokenManager() TokenManager {
+	return &copyTokenManager{}
+}
+
+// copyRepoFactory returns the repositories of the account a token source
+// belongs to.
+type copyRepoFactory struct {
+	MockRepositoryFactory
+	messages map[string]*MockMessageRepository
+	labels   map[string]*MockLabelRepository
+}
+
+func (f *copyRepoFactory) NewMessageRepository(ctx context.Context, ts oauth2.TokenSource) (MessageRepository, error) {
+	return f.messages[string(ts.(aliasTokenSource))], nil
+}
+
+func (f *copyRepoFactory) NewLabelRepository(ctx context.Context, ts oauth2.TokenSource) (LabelRepository, error) {
+	return f.labels[string(ts.(aliasTokenSource))], nil
+}
+
+// setupCopyTest injects a work account holding one labelled message and
+// an empty personal account.
+func setupCopyTest(t *testing.T) *copyRepoFactory {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	factory := &copyRepoFactory{
+		messages: map[string]*MockMessageRepository{
+			"work": {
+				Message: &mail.Message{ID: "msg-1", Labels: []string{"INBOX", "UNREAD", "Label_1", "Label_2"}},
+				Raw:     []byte("Subject: Hello\r\n\r\nHi"),
+			},
+			"personal": {},
+		},
+		labels: map[string]*MockLabelRepository{
+			"work": {Labels: []*mail.Label{
+				mail.NewSystemLabel("INBOX", "INBOX"),
+				mail.NewSystemLabel("UNREAD", "UNREAD"),
+				mail.NewLabel("Label_1", "Work/Clients"),
+				mail.NewLabel("Label_2", "Receipts"),
+			}},
+			"personal": {Labels: []*mail.Label{
+				mail.NewSystemLabel("INBOX", "INBOX"),
+				mail.NewSystemLabel("UNREAD", "UNREAD"),
+				mail.NewLabel("Label_9", "Finance"),
+			}},
+		},
+	}
+	SetDependencies(&Dependencies{
+		AccountService: &copyAccountService{accounts: map[string]*accountuc.Account{
+			"work":     {Alias: "work", Email: "me@work.example.com"},
+			"personal": {Alias: "personal", Email: "me@example.com"},
+		}},
+		RepoFactory: factory,
+	})
+
+	origTo, origMap, origDryRun, origFormat, origAccount := mailCopyToAccount, mailCopyMap, mailCopyDryRun, formatFlag, accountFlag
+	mailCopyToAccount, mailCopyMap, mailCopyDryRun, accountFlag = "personal", nil, false, ""
+	t.Cleanup(func() {
+		ResetDependencies()
+		repository.SetReadOnly(false)
+		repository.SetEndpoints(nil)
+		mailCopyToAccount, mailCopyMap, mailCopyDryRun, formatFlag, accountFlag = origTo, origMap, origDryRun, origFormat, origAccount
+	})
+	return factory
+}
+
+func TestRunMailCopy(t *testing.T) {
+	factory := setupCopyTest(t)
+
+	cfg := config.NewConfig()
+	cfg.Accounts["personal"] = config.AccountConfig{Email: "me@example.com", LabelMap: []string{"Work/*=Archive/Work/*", "Receipts=Finance"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	mailCopyMap = []string{"INBOX="}
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runMailCopy(cmd, []string{"msg-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	imported := factory.messages["personal"].Imported
+	if len(imported) != 1 {
+		t.Fatalf("expected one import, got %d", len(imported))
+	}
+	if string(imported[0].Raw) != "Subject: Hello\r\n\r\nHi" {
+		t.Errorf("unexpected raw message: %q", imported[0].Raw)
+	}
+	if want := []string{"UNREAD", "mock-label-id", "Label_9"}; !slices.Equal(imported[0].LabelIDs, want) {
+		t.Errorf("label IDs = %v, want %v", imported[0].LabelIDs, want)
+	}
+	for _, want := range []string{
+		`Created label "Archive/Work/Clients" in personal.`,
+		"Copied msg-1 to personal as imported with labels: UNREAD, Archive/Work/Clients, Finance",
+	} {
+		if !contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got: %s", want, buf.String())
+		}
+	}
+	if len(factory.messages["work"].Imported) != 0 {
+		t.Error("expected nothing imported into the source account")
+	}
+}
+
+func TestRunMailCopy_DryRunJSON(t *testing.T) {
+	factory := setupCopyTest(t)
+	mailCopyDryRun = true
+	formatFlag = "json"
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runMailCopy(cmd, []string{"msg-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(factory.messages["personal"].Imported) != 0 {
+		t.Error("expected a dry run not to import")
+	}
+
+	var results []copiedMessage
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	want := []string{"INBOX", "UNREAD", "Work/Clients", "Receipts"}
+	if len(results) != 1 || results[0].SourceID != "msg-1" || results[0].MessageID != "" || !slices.Equal(results[0].Labels, want) {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestRunMailCopy_Errors(t *testing.T) {
+	setupCopyTest(t)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+
+	mailCopyToAccount = "work"
+	if err := runMailCopy(cmd, []string{"msg-1"}); err == nil || !contains(err.Error(), "cannot copy messages into the account they are in") {
+		t.Errorf("expected a same-account error, got %v", err)
+	}
+
+	mailCopyToAccount = "missing"
+	if err := runMailCopy(cmd, []string{"msg-1"}); err == nil || !contains(err.Error(), `destination account "missing" not found`) {
+		t.Errorf("expected a missing-account error, got %v", err)
+	}
+
+	mailCopyToAccount, mailCopyMap = "personal", []string{"Work"}
+	if err := runMailCopy(cmd, []string{"msg-1"}); err == nil {
+		t.Error("expected an error for a --map entry without '='")
+	}
+}
@@ -0,0 +1,308 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// builtinViews are the views available without configuration. They map to
+// Gmail's inbox categories.
+var builtinViews = map[string]string{
+	"newsletters": "category:promotions",
+	"updates":     "category:updates",
+	"social":      "category:social",
+	"forums":      "category:forums",
+}
+
+// Command flags for mail digest command.
+var (
+	mailDigestView    string
+	mailDigestQuery   string
+	mailDigestSince   string
+	mailDigestSendTo  []string
+	mailDigestOut     string
+	mailDigestSubject string
+	mailDigestLimit   int
+	mailDigestKeep    bool
+)
+
+// mailDigestCmd aggregates matching messages into a single digest.
+var mailDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Combine matching messages into one digest",
+	Long: `Combine the inbox messages of a view into a single digest, then
+archive them.
+
+The messages are selected with --view or --query and --since (default:
+7d, also accepts weeks such as 2w or durations such as 12h). Built-in
+views are newsletters, updates, social and forums, which match Gmail's
+inbox categories; mail.views.<name> adds views or replaces them. Only
+messages still in the inbox are included, so running the digest again
+does not repeat them.
+
+The digest lists every message, oldest first, with its sender, date,
+body and a Gmail link. It is rendered as Markdown or HTML, taken from
+--format (md or html), or inferred from the --out file extension.
+
+With --send-to the digest is mailed (HTML digests as HTML mail); with
+--out it is written to a file. Either way the original messages are
+archived afterwards unless --keep is given. Without --send-to or --out
+the digest is only printed and nothing is archived.`,
+	Example: `  # Mail last week's newsletters as an HTML digest
+  goog mail digest --view newsletters --since 7d --format html --send-to me@example.com
+
+  # Preview the digest in the terminal
+  goog mail digest --view updates
+
+  # Digest a custom search into a Markdown file and keep the originals
+  goog mail digest --query "list:golang-nuts.googlegroups.com" --out nuts.md --keep
+
+  # Define a view of your own
+  goog config set mail.views.blogs "from:substack.com"
+  goog mail digest --view blogs --send-to me@example.com`,
+	Args: cobra.NoArgs,
+	RunE: runMailDigest,
+}
+
+func init() {
+	mailCmd.AddCommand(mailDigestCmd)
+
+	mailDigestCmd.Flags().StringVar(&mailDigestView, "view", "", "named view to digest (newsletters, updates, social, forums or mail.views.<name>)")
+	mailDigestCmd.Flags().StringVar(&mailDigestQuery, "query", "", "Gmail search to digest instead of a view")
+	mailDigestCmd.Flags().StringVar(&mailDigestSince, "since", "7d", "include messages received within this period or since this date (e.g. 7d, yesterday)")
+	mailDigestCmd.Flags().StringSliceVar(&mailDigestSendTo, "send-to", nil, "mail the digest to these recipients")
+	mailDigestCmd.Flags().StringVarP(&mailDigestOut, "out", "o", "", "write the digest to this file")
+	mailDigestCmd.Flags().StringVar(&mailDigestSubject, "subject", "", "digest subject (default: generated from the view and period)")
+	mailDigestCmd.Flags().IntVar(&mailDigestLimit, "limit", 100, "maximum number of messages to include (0 for no limit)")
+	mailDigestCmd.Flags().BoolVar(&mailDigestKeep, "keep", false, "do not archive digested messages")
+}
+
+// runMailDigest handles the mail digest command.
+func runMailDigest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if (mailDigestView == "") == (mailDigestQuery == "") {
+		return fmt.Errorf("specify exactly one of --view or --query")
+	}
+	if mailDigestLimit < 0 {
+		return fmt.Errorf("--limit cannot be negative")
+	}
+	since, err := dateparse.ParseSinceAt(mailDigestSince, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+
+	format, err := resolveThreadExportFormat(formatFlag, mailDigestOut)
+	if err != nil {
+		return err
+	}
+
+	query, title := strings.TrimSpace(mailDigestQuery), "Mail"
+	if mailDigestView != "" {
+		if query, err = resolveView(mailDigestView); err != nil {
+			return err
+		}
+		title = strings.ToUpper(mailDigestView[:1]) + mailDigestView[1:]
+	}
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	var recipients []string
+	if len(mailDigestSendTo) > 0 {
+		if recipients, err = parseEmailRecipients(mailDigestSendTo); err != nil {
+			return fmt.Errorf("invalid 'send-to' recipient: %w", err)
+		}
+	}
+
+	msgs, err := searchDigestMessages(ctx, repo, fmt.Sprintf("%s in:inbox after:%d", query, since.Unix()), mailDigestLimit)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		if !quietFlag {
+			cmd.Println("No messages to digest")
+		}
+		return nil
+	}
+
+	subject := mailDigestSubject
+	if subject == "" {
+		subject = fmt.Sprintf("%s digest: %d message(s) since %s", title, len(msgs), presenter.CurrentLocale().Date(since))
+	}
+
+	var doc string
+	if format == threadExportHTML {
+		doc = renderDigestHTML(subject, msgs, senderEmail)
+	} else {
+		doc = renderDigestMarkdown(subject, msgs, senderEmail)
+	}
+
+	if len(recipients) == 0 && mailDigestOut == "" {
+		cmd.Print(doc)
+		return nil
+	}
+
+	if mailDigestOut != "" {
+		if err := os.WriteFile(mailDigestOut, []byte(doc), 0o644); err != nil {
+			return fmt.Errorf("failed to write digest: %w", err)
+		}
+		if !quietFlag {
+			cmd.Printf("Wrote digest of %d message(s) to %s\n", len(msgs), mailDigestOut)
+		}
+	}
+
+	if len(recipients) > 0 {
+		digest := &mail.Message{From: mail.ParseAddress(senderEmail), To: mail.ParseAddresses(recipients), Subject: subject}
+		if format == threadExportHTML {
+			digest.BodyHTML = doc
+		} else {
+			digest.Body = doc
+		}
+		sent, err := repo.Send(ctx, digest)
+		if err != nil {
+			return fmt.Errorf("failed to send digest: %w", err)
+		}
+		if !quietFlag {
+			cmd.Printf("Sent digest of %d message(s) to %s (ID: %s)\n", len(msgs), strings.Join(recipients, ", "), sent.ID)
+		}
+	}
+
+	if mailDigestKeep {
+		return nil
+	}
+	ids := make([]string, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+	if err := repo.BatchModify(ctx, ids, mail.ModifyRequest{RemoveLabels: []string{"INBOX"}}); err != nil {
+		return fmt.Errorf("digest delivered but failed to archive the messages: %w", err)
+	}
+	if !quietFlag {
+		cmd.Printf("Archived %d message(s)\n", len(ids))
+	}
+	return nil
+}
+
+// resolveView returns the search of a named view: mail.views.<name> from
+// the config file, or a built-in view.
+func resolveView(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if cfg, err := config.Load(); err == nil {
+		if query, ok := cfg.Mail.Views[name]; ok {
+			return query, nil
+		}
+	}
+	if query, ok := builtinViews[name]; ok {
+		return query, nil
+	}
+	return "", fmt.Errorf("unknown view %q (use newsletters, updates, social, forums or set mail.views.%s)", name, name)
+}
+
+// searchDigestMessages returns up to limit messages matching query (0 for
+// no limit), oldest first.
+func searchDigestMessages(ctx context.Context, repo MessageRepository, query string, limit int) ([]*mail.Message, error) {
+	var msgs []*mail.Message
+	pageToken := ""
+	for {
+		result, err := repo.Search(ctx, query, mail.ListOptions{MaxResults: attachmentsPageSize, PageToken: pageToken})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search messages: %w", err)
+		}
+		for _, msg := range result.Items {
+			if msg != nil && (limit == 0 || len(msgs) < limit) {
+				msgs = append(msgs, msg)
+			}
+		}
+		if result.NextPageToken == "" || result.NextPageToken == pageToken || (limit > 0 && len(msgs) >= limit) {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Date.Before(msgs[j].Date) })
+	return msgs, nil
+}
+
+// digestSubject returns the subject of msg for a digest entry.
+func digestSubject(msg *mail.Message) string {
+	if msg.Subject == "" {
+		return "(no subject)"
+	}
+	return msg.Subject
+}
+
+// renderDigestMarkdown renders messages as a Markdown digest. account is
+// the mailbox the Gmail links open.
+func renderDigestMarkdown(title string, msgs []*mail.Message, account string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	for i, msg := range msgs {
+		fmt.Fprintf(&sb, "%d. %s — %s\n", i+1, digestSubject(msg), msg.From)
+	}
+
+	for i, msg := range msgs {
+		fmt.Fprintf(&sb, "\n---\n\n## %d. %s\n\n", i+1, digestSubject(msg))
+		fmt.Fprintf(&sb, "- **From:** %s\n", msg.From)
+		if !msg.Date.IsZero() {
+			fmt.Fprintf(&sb, "- **Date:** %s\n", msg.Date.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+		}
+		fmt.Fprintf(&sb, "- **Link:** <%s>\n", gmailPermalink(account, msg.ID))
+		if body := strings.TrimSpace(strings.ReplaceAll(exportBody(msg), "\r\n", "\n")); body != "" {
+			sb.WriteString("\n" + body + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// renderDigestHTML renders messages as a standalone HTML digest. account
+// is the mailbox the Gmail links open.
+func renderDigestHTML(title string, msgs []*mail.Message, account string) string {
+	var sb strings.Builder
+	esc := html.EscapeString
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", esc(title))
+	sb.WriteString("<style>body{font-family:sans-serif;max-width:50em;margin:2em auto}" +
+		".message{border-top:1px solid #ccc;padding-top:1em}" +
+		".body{white-space:pre-wrap}</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n<ol>\n", esc(title))
+	for i, msg := range msgs {
+		fmt.Fprintf(&sb, "<li><a href=\"#m%d\">%s</a> &mdash; %s</li>\n", i+1, esc(digestSubject(msg)), esc(msg.From.String()))
+	}
+	sb.WriteString("</ol>\n")
+
+	for i, msg := range msgs {
+		fmt.Fprintf(&sb, "<div class=\"message\" id=\"m%d\">\n", i+1)
+		fmt.Fprintf(&sb, "<h2>%d. %s</h2>\n<dl>\n", i+1, esc(digestSubject(msg)))
+		fmt.Fprintf(&sb, "<dt>From</dt><dd>%s</dd>\n", esc(msg.From.String()))
+		if !msg.Date.IsZero() {
+			fmt.Fprintf(&sb, "<dt>Date</dt><dd>%s</dd>\n", esc(msg.Date.Format("Mon, 02 Jan 2006 15:04:05 -0700")))
+		}
+		fmt.Fprintf(&sb, "</dl>\n<p><a href=\"%s\">Open in Gmail</a></p>\n", esc(gmailPermalink(account, msg.ID)))
+		if body := strings.TrimSpace(strings.ReplaceAll(exportBody(msg), "\r\n", "\n")); body != "" {
+			fmt.Fprintf(&sb, "<div class=\"body\">%s</div>\n", esc(body))
+		}
+		sb.WriteString("</div>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// digestRepository records the search query and the sent digest.
+type digestRepository struct {
+	captureSendRepository
+	Query string
+}
+
+func (m *digestRepository) Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Query = query
+	return m.MockMessageRepository.Search(ctx, query, opts)
+}
+
+// setupMailDigestTest injects a repository holding msgs and resets the
+// digest flags.
+func setupMailDigestTest(t *testing.T, msgs ...*mail.Message) (*digestRepository, *cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	repo := &digestRepository{}
+	repo.Messages = msgs
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origView, origQuery, origSince, origSendTo := mailDigestView, mailDigestQuery, mailDigestSince, mailDigestSendTo
+	origOut, origSubject, origLimit, origKeep := mailDigestOut, mailDigestSubject, mailDigestLimit, mailDigestKeep
+	origFormat, origQuiet := formatFlag, quietFlag
+	mailDigestView, mailDigestQuery, mailDigestSince, mailDigestSendTo = "", "", "7d", nil
+	mailDigestOut, mailDigestSubject, mailDigestLimit, mailDigestKeep = "", "", 100, false
+	formatFlag, quietFlag = "table", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailDigestView, mailDigestQuery, mailDigestSince, mailDigestSendTo = origView, origQuery, origSince, origSendTo
+		mailDigestOut, mailDigestSubject, mailDigestLimit, mailDigestKeep = origOut, origSubject, origLimit, origKeep
+		formatFlag, quietFlag = origFormat, origQuiet
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return repo, cmd, buf
+}
+
+// digestMessages returns two newsletters, newest first.
+func digestMessages() []*mail.Message {
+	newer := mail.NewMessage("m2", "t2", "Weekly <weekly@example.com>", "Issue <42>", "Plain & simple")
+	newer.Date = time.Date(2026, 10, 9, 8, 0, 0, 0, time.UTC)
+	older := mail.NewMessage("m1", "t1", "Daily <daily@example.com>", "Morning brief", "")
+	older.BodyHTML = "<p>Top <b>stories</b></p>"
+	older.Date = time.Date(2026, 10, 8, 8, 0, 0, 0, time.UTC)
+	return []*mail.Message{newer, older}
+}
+
+func TestRunMailDigest_SendHTML(t *testing.T) {
+	repo, cmd, buf := setupMailDigestTest(t, digestMessages()...)
+	mailDigestView, mailDigestSendTo, formatFlag = "newsletters", []string{"me@example.com"}, "html"
+
+	if err := runMailDigest(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(repo.Query, "category:promotions in:inbox after:") {
+		t.Errorf("unexpected query %q", repo.Query)
+	}
+
+	sent := repo.Sent
+	if sent == nil {
+		t.Fatal("expected the digest to be sent")
+	}
+	if !slices.Equal(mail.AddressStrings(sent.To), []string{"me@example.com"}) || !strings.HasPrefix(sent.Subject, "Newsletters digest: 2 message(s) since ") {
+		t.Errorf("unexpected digest: to=%v subject=%q", sent.To, sent.Subject)
+	}
+	if sent.Body != "" || !contains(sent.BodyHTML, "Issue &lt;42&gt;") || !contains(sent.BodyHTML, "Plain &amp; simple") ||
+		!contains(sent.BodyHTML, "https://mail.google.com/mail/u/me@example.com/#all/m1") {
+		t.Errorf("unexpected digest body:\n%s", sent.BodyHTML)
+	}
+	if strings.Index(sent.BodyHTML, "Morning brief") > strings.Index(sent.BodyHTML, "Issue &lt;42&gt;") {
+		t.Error("expected messages oldest first")
+	}
+
+	if len(repo.BatchModified) != 1 || !slices.Equal(repo.BatchModified[0].IDs, []string{"m1", "m2"}) ||
+		!slices.Equal(repo.BatchModified[0].Req.RemoveLabels, []string{"INBOX"}) {
+		t.Errorf("unexpected archive calls: %+v", repo.BatchModified)
+	}
+	if !contains(buf.String(), "Archived 2 message(s)") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRunMailDigest_OutMarkdownKeep(t *testing.T) {
+	repo, cmd, _ := setupMailDigestTest(t, digestMessages()...)
+	mailDigestQuery, mailDigestKeep = "from:example.com", true
+	mailDigestOut = filepath.Join(t.TempDir(), "digest.md")
+
+	if err := runMailDigest(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(mailDigestOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(data)
+	if !strings.HasPrefix(doc, "# Mail digest: 2 message(s)") || !contains(doc, "1. Morning brief — Daily <daily@example.com>") ||
+		!contains(doc, "\nTop stories\n") {
+		t.Errorf("unexpected digest:\n%s", doc)
+	}
+	if repo.Sent != nil || len(repo.BatchModified) != 0 {
+		t.Errorf("expected no send or archive, got sent=%v archived=%+v", repo.Sent, repo.BatchModified)
+	}
+}
+
+func TestRunMailDigest_PreviewDoesNotArchive(t *testing.T) {
+	repo, cmd, buf := setupMailDigestTest(t, digestMessages()...)
+	mailDigestView, mailDigestLimit = "updates", 1
+
+	if err := runMailDigest(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(buf.String(), "# Updates digest: 1 message(s)") || len(repo.BatchModified) != 0 {
+		t.Errorf("unexpected preview (archived %+v):\n%s", repo.BatchModified, buf.String())
+	}
+}
+
+func TestRunMailDigest_Errors(t *testing.T) {
+	_, cmd, _ := setupMailDigestTest(t)
+	tests := []struct {
+		name    string
+		setup   func()
+		wantErr string
+	}{
+		{"no view or query", func() {}, "exactly one of --view or --query"},
+		{"view and query", func() { mailDigestView, mailDigestQuery = "updates", "x" }, "exactly one of --view or --query"},
+		{"unknown view", func() { mailDigestView = "blogs" }, "unknown view"},
+		{"bad since", func() { mailDigestView, mailDigestSince = "updates", "soon" }, "invalid --since"},
+		{"bad format", func() { mailDigestView, formatFlag = "updates", "json" }, "unsupported export format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailDigestView, mailDigestQuery, mailDigestSince, formatFlag = "", "", "7d", "table"
+			tt.setup()
+			if err := runMailDigest(cmd, nil); err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveView(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Mail.Views = map[string]string{"blogs": "from:substack.com", "social": "from:linkedin.com"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"blogs":       "from:substack.com",
+		"Social":      "from:linkedin.com",
+		"newsletters": "category:promotions",
+	} {
+		if got, err := resolveView(name); err != nil || got != want {
+			t.Errorf("resolveView(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Names of the flags of the duplicate-send guard.
+const (
+	duplicateWindowFlag = "duplicate-window"
+	forceFlag           = "force"
+)
+
+// duplicateSearchLimit is the number of recent sent messages the
+// duplicate-send guard compares.
+const duplicateSearchLimit = 100
+
+// addDuplicateFlags registers --duplicate-window and --force on a sending
+// command.
+func addDuplicateFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(duplicateWindowFlag, 0, "refuse to send a message identical to one sent within this time, 0 to not check (default: mail.duplicate_window)")
+	cmd.Flags().Bool(forceFlag, false, "send even if an identical message was sent within the duplicate window")
+}
+
+// duplicateWindow returns how far back the sent mail of cmd is checked
+// for the same message: --duplicate-window, or mail.duplicate_window when
+// the flag is not given. Zero means no check, as does --force.
+func duplicateWindow(cmd *cobra.Command) (time.Duration, error) {
+	if force, _ := cmd.Flags().GetBool(forceFlag); force {
+		return 0, nil
+	}
+	if f := cmd.Flags().Lookup(duplicateWindowFlag); f != nil && f.Changed {
+		window, _ := cmd.Flags().GetDuration(duplicateWindowFlag)
+		return window, nil
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg.Mail.DuplicateWindow == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(cfg.Mail.DuplicateWindow)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mail.duplicate_window %q: %w", cfg.Mail.DuplicateWindow, err)
+	}
+	return window, nil
+}
+
+// checkDuplicate fails when a message with the same recipients, subject
+// and body as msg is among the mail sent within the duplicate window, so
+// that a notification script run twice does not send twice. The search
+// covers the most recent duplicateSearchLimit sent messages of the window
+// to the first recipient. When the sent mail cannot be listed because the
+// network is down and messages are queued offline, the check is skipped
+// with a warning so the message can still be queued. Listing sent mail
+// needs gmail.readonly, which command, the name to authorize with
+// 'auth login --for', may not have been granted.
+func checkDuplicate(ctx context.Context, cmd *cobra.Command, command string, repo MessageRepository, msg *mail.Message) error {
+	window, err := duplicateWindow(cmd)
+	if err != nil || window <= 0 {
+		return err
+	}
+	if err := requireScope(auth.ScopeGmailReadonly, command); err != nil {
+		return fmt.Errorf("cannot check for a duplicate of this message (use --%s to skip the check): %w", forceFlag, err)
+	}
+
+	query := fmt.Sprintf("after:%d", time.Now().Add(-window).Unix())
+	if first := firstRecipient(msg); first != "" {
+		query += fmt.Sprintf(" {to:%[1]s cc:%[1]s bcc:%[1]s}", first)
+	}
+	result, err := repo.List(ctx, mail.ListOptions{
+		Query:      query,
+		LabelIDs:   []string{"SENT"},
+		MaxResults: duplicateSearchLimit,
+	})
+	if err != nil {
+		if isNetworkError(err) && queueOfflineEnabled(cmd) {
+			output.Warnf(cmd, "cannot check for a duplicate of this message: %v", err)
+			return nil
+		}
+		return fmt.Errorf("cannot check for a duplicate of this message (use --%s to skip the check): %w", forceFlag, err)
+	}
+
+	dup := mail.FindDuplicate(msg, result.Items)
+	if dup == nil {
+		return nil
+	}
+	return fmt.Errorf("an identical message was sent %s ago as %s (use --%s to send it again)",
+		time.Since(dup.Date).Round(time.Second), dup.ID, forceFlag)
+}
+
+// firstRecipient returns the first address msg is sent to, lower-cased.
+func firstRecipient(msg *mail.Message) string {
+	for _, list := range [][]mail.Address{msg.To, msg.Cc, msg.Bcc} {
+		if len(list) > 0 {
+			return strings.ToLower(list[0].Email)
+		}
+	}
+	return ""
+}
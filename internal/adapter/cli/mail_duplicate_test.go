@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// setDuplicateWindow sets mail.duplicate_window in the test config.
+func setDuplicateWindow(t *testing.T, value string) {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetValue("mail.duplicate_window", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sentRelease is a message already sent with the values mail send gets
+// from setupMailGroupsTest.
+func sentRelease() *mail.Message {
+	return &mail.Message{
+		ID:      "sent-1",
+		To:      mail.ParseAddresses([]string{"Ana <ana@example.com>"}),
+		Subject: "Release",
+		Body:    "Shipping today\r\n",
+		Date:    time.Now().Add(-10 * time.Minute),
+	}
+}
+
+func TestRunMailSend_DuplicateRefused(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addDuplicateFlags(cmd)
+	repo.Messages = []*mail.Message{sentRelease()}
+	mailSendTo = []string{"ana@example.com"}
+	if err := cmd.ParseFlags([]string{"--duplicate-window", "1h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "identical message was sent 10m0s ago as sent-1") {
+		t.Fatalf("expected a duplicate error, got %v", err)
+	}
+	if len(repo.Sent) != 0 {
+		t.Error("expected nothing to be sent")
+	}
+	if got := repo.ListOpts; got.LabelIDs[0] != "SENT" || !strings.Contains(got.Query, "{to:ana@example.com cc:ana@example.com bcc:ana@example.com}") {
+		t.Errorf("unexpected sent mail search: %+v", got)
+	}
+
+	if err := cmd.ParseFlags([]string{"--force"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error with --force: %v", err)
+	}
+	if len(repo.Sent) != 1 {
+		t.Errorf("expected --force to send, got %d sent", len(repo.Sent))
+	}
+}
+
+func TestRunMailSend_DuplicateWindowFromConfig(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addDuplicateFlags(cmd)
+	repo.Messages = []*mail.Message{sentRelease()}
+	mailSendTo = []string{"ana@example.com"}
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("expected no check without a window, got %v", err)
+	}
+
+	setDuplicateWindow(t, "1h")
+	mailSendBody = "Shipping tomorrow"
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("expected a different body to be sent, got %v", err)
+	}
+	mailSendBody = "Shipping today"
+	if err := runMailSend(cmd, nil); err == nil {
+		t.Fatal("expected mail.duplicate_window to refuse the duplicate")
+	}
+	if len(repo.Sent) != 2 {
+		t.Errorf("expected 2 messages sent, got %d", len(repo.Sent))
+	}
+}
+
+func TestRunMailSend_DuplicateWithoutReadScope(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addDuplicateFlags(cmd)
+	grantScopes(auth.ScopeGmailSend)
+	mailSendTo = []string{"ana@example.com"}
+	if err := cmd.ParseFlags([]string{"--duplicate-window", "1h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "was not granted "+auth.ScopeGmailReadonly) ||
+		!strings.Contains(err.Error(), `goog auth login --account test --for "mail send"`) || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected a scope error, got %v", err)
+	}
+	if len(repo.Sent) != 0 || repo.ListOpts.LabelIDs != nil {
+		t.Errorf("expected no listing and nothing sent, got %d sent, list %+v", len(repo.Sent), repo.ListOpts)
+	}
+
+	// Without a window, the send-only login is enough.
+	if err := cmd.ParseFlags([]string{"--duplicate-window", "0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMailSend(cmd, nil); err != nil || len(repo.Sent) != 1 {
+		t.Errorf("expected the message to be sent without a window, got %v", err)
+	}
+}
+
+func TestCheckDuplicate_ListError(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	addDuplicateFlags(cmd)
+	if err := cmd.ParseFlags([]string{"--duplicate-window", "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	msg := sentRelease()
+
+	repo.ListErr = errors.New("quota exceeded")
+	if err := checkDuplicate(t.Context(), cmd, "mail send", repo, msg); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected the error to mention --force, got %v", err)
+	}
+
+	repo.ListErr = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	addQueueFlag(cmd)
+	if err := cmd.ParseFlags([]string{"--queue-offline"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDuplicate(t.Context(), cmd, "mail send", repo, msg); err != nil {
+		t.Errorf("expected the check to be skipped when offline with --queue-offline, got %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/editor"
+)
+
+// defaultAutosaveInterval is how often a message being composed in the
+// editor is saved as a draft.
+const defaultAutosaveInterval = 30 * time.Second
+
+// draftAutosaver keeps a Gmail draft of a message being composed, so that
+// a crashed terminal does not lose it. The draft is created on the first
+// save and updated on later ones.
+type draftAutosaver struct {
+	ctx  context.Context
+	repo DraftRepository
+	msg  *mail.Message
+	html bool
+
+	mu    sync.Mutex
+	draft *mail.Draft
+	saved string
+	errs  []error
+}
+
+// save saves body as the draft's body, unless it is empty or unchanged
+// since the last save.
+func (a *draftAutosaver) save(body string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if strings.TrimSpace(body) == "" || (a.draft != nil && body == a.saved) {
+		return nil
+	}
+
+	msg := *a.msg
+	if a.html {
+		msg.BodyHTML = body
+	} else {
+		msg.Body = body
+	}
+	var draft *mail.Draft
+	var err error
+	if a.draft == nil {
+		draft, err = a.repo.Create(a.ctx, mail.NewDraft("", &msg))
+	} else {
+		a.draft.UpdateMessage(&msg)
+		draft, err = a.repo.Update(a.ctx, a.draft)
+	}
+	if err != nil {
+		return err
+	}
+	a.draft, a.saved = draft, body
+	return nil
+}
+
+// draftID returns the ID of the draft, or "" when none was saved or a is
+// nil.
+func (a *draftAutosaver) draftID() string {
+	if a == nil {
+		return ""
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.draft == nil {
+		return ""
+	}
+	return a.draft.ID
+}
+
+// discard deletes the draft, if one was saved. a may be nil.
+func (a *draftAutosaver) discard() error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.draft == nil {
+		return nil
+	}
+	if err := a.repo.Delete(a.ctx, a.draft.ID); err != nil {
+		return fmt.Errorf("failed to delete autosaved draft %s: %w", a.draft.ID, err)
+	}
+	a.draft = nil
+	return nil
+}
+
+// composeInEditor opens the user's editor on body and returns the text
+// saved when the editor exits. While the editor is open, the text is saved
+// with saver every interval; saver may be nil to not autosave. Autosave
+// errors are reported once the editor has exited, so that they do not
+// disturb its screen.
+func composeInEditor(cmd *cobra.Command, body string, saver *draftAutosaver, interval time.Duration) (string, error) {
+	f, err := os.CreateTemp("", "goog-message-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create message file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+	_, err = f.WriteString(body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write message file: %w", err)
+	}
+
+	command := editor.Command(os.Getenv)
+	c, err := editor.Start(command, path, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return "", fmt.Errorf("failed to start editor %q: %w", command, err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	if saver != nil && interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					content, err := os.ReadFile(path)
+					if err == nil {
+						err = saver.save(string(content))
+					}
+					if err != nil {
+						saver.mu.Lock()
+						saver.errs = append(saver.errs, err)
+						saver.mu.Unlock()
+					}
+				}
+			}
+		}()
+	}
+	waitErr := c.Wait()
+	close(done)
+	wg.Wait()
+
+	if saver != nil && len(saver.errs) > 0 {
+		output.Warnf(cmd, "autosave failed %d time(s); last error: %v", len(saver.errs), saver.errs[len(saver.errs)-1])
+	}
+	if waitErr != nil {
+		if id := saver.draftID(); id != "" {
+			return "", fmt.Errorf("editor %q failed: %w; the message was autosaved as draft %s", command, waitErr, id)
+		}
+		return "", fmt.Errorf("editor %q failed: %w", command, waitErr)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read message file: %w", err)
+	}
+	return string(content), nil
+}
+
+// finish settles the draft once the message has been handled. When err is
+// nil the message was sent or queued and the draft is deleted. Otherwise
+// body is saved as the draft, so the message written in the editor is not
+// lost, and err is returned saying where to find it. a may be nil.
+func (a *draftAutosaver) finish(cmd *cobra.Command, body string, err error) error {
+	if a == nil {
+		return err
+	}
+	if err == nil {
+		if discardErr := a.discard(); discardErr != nil {
+			output.Warnf(cmd, "%v", discardErr)
+		}
+		return nil
+	}
+	if saveErr := a.save(body); saveErr != nil {
+		return fmt.Errorf("%w; the message could not be saved as a draft: %v", err, saveErr)
+	}
+	id := a.draftID()
+	return fmt.Errorf("%w; the message was saved as draft %s: send it with 'goog draft send %s'", err, id, id)
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+)
+
+// recordingDraftRepository records the drafts saved and deleted.
+type recordingDraftRepository struct {
+	MockDraftRepository
+	mu      sync.Mutex
+	saves   int
+	last    *mail.Message
+	deleted []string
+}
+
+func (r *recordingDraftRepository) Create(ctx context.Context, draft *mail.Draft) (*mail.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saves++
+	r.last = draft.Message
+	return r.MockDraftRepository.Create(ctx, draft)
+}
+
+func (r *recordingDraftRepository) Update(ctx context.Context, draft *mail.Draft) (*mail.Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saves++
+	r.last = draft.Message
+	return r.MockDraftRepository.Update(ctx, draft)
+}
+
+func (r *recordingDraftRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// setupMailEditorTest sets up mail send with --editor, the given shell
+// script as the editor and a fast autosave.
+func setupMailEditorTest(t *testing.T, script string) (*sendRecordingRepository, *recordingDraftRepository, *cobra.Command) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell as the editor")
+	}
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	drafts := &recordingDraftRepository{}
+	GetDependencies().RepoFactory.(*MockRepositoryFactory).DraftRepo = drafts
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "f() { "+script+"; }; f")
+
+	origEditor, origAutosave := mailSendEditor, mailSendAutosave
+	mailSendEditor, mailSendAutosave = true, 20*time.Millisecond
+	t.Cleanup(func() { mailSendEditor, mailSendAutosave = origEditor, origAutosave })
+	mailSendTo, mailSendBody = []string{"ana@example.com"}, ""
+	return repo, drafts, cmd
+}
+
+func TestRunMailSend_EditorAutosave(t *testing.T) {
+	repo, drafts, cmd := setupMailEditorTest(t, `printf 'Long email' > "$1"; sleep 0.3`)
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 || repo.Sent[0].Body != "Long email" {
+		t.Fatalf("expected the edited body to be sent, got %+v", repo.Sent)
+	}
+	if drafts.saves != 1 {
+		t.Errorf("expected one autosave of the unchanged body, got %d", drafts.saves)
+	}
+	if drafts.last == nil || drafts.last.Subject != "Release" || !slices.Equal(mail.AddressStrings(drafts.last.To), []string{"ana@example.com"}) {
+		t.Errorf("autosaved draft should hold the whole message, got %+v", drafts.last)
+	}
+	if !slices.Equal(drafts.deleted, []string{"mock-draft-id"}) {
+		t.Errorf("expected the draft to be deleted after sending, got %v", drafts.deleted)
+	}
+}
+
+func TestRunMailSend_EditorStartsFromBody(t *testing.T) {
+	repo, drafts, cmd := setupMailEditorTest(t, `printf ' and more' >> "$1"`)
+	mailSendBody = "Draft"
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 || repo.Sent[0].Body != "Draft and more" {
+		t.Fatalf("expected the edited --body to be sent, got %+v", repo.Sent)
+	}
+	if drafts.saves != 0 || len(drafts.deleted) != 0 {
+		t.Errorf("a quick edit should leave no draft, got %d saves and %v deleted", drafts.saves, drafts.deleted)
+	}
+}
+
+func TestRunMailSend_EditorEmptyBodyDiscards(t *testing.T) {
+	repo, drafts, cmd := setupMailEditorTest(t, `printf 'Half written' > "$1"; sleep 0.2; : > "$1"`)
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "body is empty") {
+		t.Fatalf("expected an empty body error, got %v", err)
+	}
+	if len(repo.Sent) != 0 {
+		t.Error("expected nothing to be sent")
+	}
+	if !slices.Equal(drafts.deleted, []string{"mock-draft-id"}) {
+		t.Errorf("expected the autosaved draft to be discarded, got %v", drafts.deleted)
+	}
+}
+
+func TestRunMailSend_EditorKeepsDraftOnFailure(t *testing.T) {
+	repo, drafts, cmd := setupMailEditorTest(t, `printf 'Important words' > "$1"`)
+	repo.FailOn = map[string]bool{"Release": true}
+	mailSendAutosave = 0
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "saved as draft mock-draft-id") {
+		t.Fatalf("expected the error to name the saved draft, got %v", err)
+	}
+	if len(drafts.deleted) != 0 {
+		t.Errorf("expected the draft to be kept, got %v deleted", drafts.deleted)
+	}
+	if drafts.last == nil || drafts.last.Body != "Important words" {
+		t.Errorf("expected the final body in the draft, got %+v", drafts.last)
+	}
+}
+
+func TestRunMailSend_EditorWithoutComposeScope(t *testing.T) {
+	repo, drafts, cmd := setupMailEditorTest(t, `printf 'Long email' > "$1"; sleep 0.1`)
+	grantScopes(auth.ScopeGmailSend)
+	errOut := new(bytes.Buffer)
+	cmd.SetErr(errOut)
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 || repo.Sent[0].Body != "Long email" {
+		t.Fatalf("expected the edited body to be sent, got %+v", repo.Sent)
+	}
+	if drafts.saves != 0 {
+		t.Errorf("expected no draft without gmail.compose, got %d saves", drafts.saves)
+	}
+	if got := errOut.String(); !strings.Contains(got, "autosave is off") || !strings.Contains(got, "was not granted "+auth.ScopeGmailCompose) {
+		t.Errorf("expected a warning naming the scope, got %q", got)
+	}
+}
+
+func TestRunMailSend_EditorWithBodyHTML(t *testing.T) {
+	_, _, cmd := setupMailEditorTest(t, `true`)
+	mailSendBodyHTML = "report.html"
+	t.Cleanup(func() { mailSendBodyHTML = "" })
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--editor") {
+		t.Fatalf("expected --body-html and --editor to conflict, got %v", err)
+	}
+}
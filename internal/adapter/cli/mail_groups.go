@@ -0,0 +1,169 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// groupRecipientPrefix marks a recipient that names a group, as in
+// "group:team-platform".
+const groupRecipientPrefix = "group:"
+
+// Sources a recipient group is expanded from.
+const (
+	groupSourceConfig   = "config"
+	groupSourceContacts = "contacts"
+)
+
+// groupExpansion records the addresses a group recipient expanded to.
+type groupExpansion struct {
+	Group   string
+	Source  string
+	Members []string
+	// Skipped lists Contacts group members without an email address.
+	Skipped []string
+}
+
+// groupResolver looks up recipient groups, first in mail.groups and then
+// in Contacts, when the account may read them. The Contacts groups are
+// listed at most once.
+type groupResolver struct {
+	ctx    context.Context
+	local  map[string][]string
+	repo   ContactGroupRepository
+	groups []*domaincontacts.ContactGroup
+}
+
+// resolve returns the members of the named group.
+func (r *groupResolver) resolve(name string) (groupExpansion, error) {
+	if members, ok := r.local[name]; ok {
+		return groupExpansion{Group: name, Source: groupSourceConfig, Members: members}, nil
+	}
+
+	if r.repo == nil {
+		// Contacts groups need contacts.readonly, which mail send does
+		// not need otherwise.
+		if err := requireScope(auth.ScopeContactsReadonly, "mail send"); err != nil {
+			return groupExpansion{}, fmt.Errorf("recipient group %q is not in mail.groups and Contacts cannot be read: %w", name, err)
+		}
+		repo, err := getContactGroupRepositoryFromDeps(r.ctx)
+		if err != nil {
+			return groupExpansion{}, err
+		}
+		groups, err := repo.List(r.ctx)
+		if err != nil {
+			return groupExpansion{}, fmt.Errorf("failed to list contact groups: %w", err)
+		}
+		r.repo, r.groups = repo, groups
+	}
+
+	var group *domaincontacts.ContactGroup
+	for _, g := range r.groups {
+		if g.MatchesName(name) {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		return groupExpansion{}, fmt.Errorf("unknown recipient group %q: define it with 'goog config set mail.groups.%s' or create a Contacts group", name, name)
+	}
+
+	members, err := r.repo.ListMembers(r.ctx, group.ResourceName, domaincontacts.ListOptions{})
+	if err != nil {
+		return groupExpansion{}, fmt.Errorf("failed to list members of group %s: %w", name, err)
+	}
+	expansion := groupExpansion{Group: name, Source: groupSourceContacts, Members: []string{}}
+	for _, contact := range members.Items {
+		email, err := contact.GetPrimaryEmail()
+		if err != nil {
+			expansion.Skipped = append(expansion.Skipped, contactLabel(contact))
+			continue
+		}
+		expansion.Members = append(expansion.Members, email)
+	}
+	return expansion, nil
+}
+
+// contactLabel names a contact for messages: its display name, or its
+// resource name when it has none.
+func contactLabel(contact *domaincontacts.Contact) string {
+	if name := contact.GetDisplayName(); name != "" {
+		return name
+	}
+	return contact.ResourceName
+}
+
+// expandRecipientGroups replaces "group:<name>" recipients in each list
+// with the members of the group. An address already among the recipients
+// is not added again, whichever list it is in. Lists without groups are
+// returned unchanged.
+func expandRecipientGroups(ctx context.Context, lists ...[]string) ([][]string, []groupExpansion, error) {
+	seen := make(map[string]bool)
+	hasGroup := false
+	for _, list := range lists {
+		for _, recipient := range list {
+			recipient = strings.TrimSpace(recipient)
+			if strings.HasPrefix(recipient, groupRecipientPrefix) {
+				hasGroup = true
+				continue
+			}
+			seen[strings.ToLower(recipient)] = true
+		}
+	}
+	if !hasGroup {
+		return lists, nil, nil
+	}
+
+	resolver := &groupResolver{ctx: ctx}
+	if cfg, err := config.Load(); err == nil {
+		resolver.local = cfg.Mail.Groups
+	}
+
+	var expansions []groupExpansion
+	expanded := make([][]string, len(lists))
+	for i, list := range lists {
+		for _, recipient := range list {
+			name, isGroup := strings.CutPrefix(strings.TrimSpace(recipient), groupRecipientPrefix)
+			if !isGroup {
+				expanded[i] = append(expanded[i], recipient)
+				continue
+			}
+			if name == "" {
+				return nil, nil, fmt.Errorf("recipient %q names no group", recipient)
+			}
+			expansion, err := resolver.resolve(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(expansion.Members) == 0 {
+				return nil, nil, fmt.Errorf("recipient group %s has no members with an email address", name)
+			}
+			for _, member := range expansion.Members {
+				if key := strings.ToLower(member); !seen[key] {
+					seen[key] = true
+					expanded[i] = append(expanded[i], member)
+				}
+			}
+			expansions = append(expansions, expansion)
+		}
+	}
+	return expanded, expansions, nil
+}
+
+// printGroupExpansions shows which addresses each group recipient
+// expanded to.
+func printGroupExpansions(cmd *cobra.Command, expansions []groupExpansion) {
+	for _, e := range expansions {
+		cmd.Printf("group:%s (%s) -> %s\n", e.Group, e.Source, strings.Join(e.Members, ", "))
+		if len(e.Skipped) > 0 {
+			cmd.Printf("  skipped without an email address: %s\n", strings.Join(e.Skipped, ", "))
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// grantScopes replaces the scopes granted to the account of
+// setupMailGroupsTest.
+func grantScopes(scopes ...string) {
+	GetDependencies().AccountService.GetTokenManager().(*MockTokenManager).GrantedScopes = scopes
+}
+
+// contactWithEmail returns a contact with a name and, unless email is
+// empty, an email address.
+func contactWithEmail(name, email string) *domaincontacts.Contact {
+	c := domaincontacts.NewContact()
+	c.ResourceName = "people/" + name
+	c.Names = []domaincontacts.Name{{DisplayName: name}}
+	if email != "" {
+		_ = c.AddEmail(email, "work", true)
+	}
+	return c
+}
+
+// setupMailGroupsTest injects a message repository and a Contacts group
+// "Team Platform", writes a config with a local "oncall" group and resets
+// the send flags.
+func setupMailGroupsTest(t *testing.T) (*sendRecordingRepository, *cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	if err := cfg.SetValue("mail.groups.oncall", "pager@example.com, ana@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &sendRecordingRepository{}
+	groupRepo := &MockContactGroupRepository{
+		Groups: []*domaincontacts.ContactGroup{
+			{ResourceName: "contactGroups/family", Name: "Family"},
+			{ResourceName: "contactGroups/team", Name: "Team Platform"},
+		},
+		Members: &domaincontacts.ListResult[*domaincontacts.Contact]{Items: []*domaincontacts.Contact{
+			contactWithEmail("Ana", "ana@example.com"),
+			contactWithEmail("Bo", "bo@example.com"),
+			contactWithEmail("Cy", ""),
+		}},
+	}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "test", Email: "me@example.com", Commands: []string{"mail send"}},
+			TokenManager: &MockTokenManager{GrantedScopes: []string{
+				auth.ScopeGmailReadonly, auth.ScopeGmailSend, auth.ScopeGmailCompose, auth.ScopeContactsReadonly,
+			}},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, ContactGroupRepo: groupRepo},
+	})
+
+	origTo, origCc, origBcc := mailSendTo, mailSendCc, mailSendBcc
+	origSubject, origBody, origDryRun := mailSendSubject, mailSendBody, mailSendDryRun
+	mailSendCc, mailSendBcc = nil, nil
+	mailSendSubject, mailSendBody, mailSendDryRun = "Release", "Shipping today", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailSendTo, mailSendCc, mailSendBcc = origTo, origCc, origBcc
+		mailSendSubject, mailSendBody, mailSendDryRun = origSubject, origBody, origDryRun
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	addVerifyFlags(cmd)
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	return repo, cmd, out, errOut
+}
+
+func TestRunMailSend_ExpandsGroups(t *testing.T) {
+	repo, cmd, _, errOut := setupMailGroupsTest(t)
+	mailSendTo = []string{"group:team-platform", "bo@example.com"}
+	mailSendCc = []string{"group:oncall"}
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 {
+		t.Fatalf("expected one message to be sent, got %d", len(repo.Sent))
+	}
+	// bo@example.com is given explicitly and ana@example.com is in both groups
+	if want := []string{"ana@example.com", "bo@example.com"}; !slices.Equal(mail.AddressStrings(repo.Sent[0].To), want) {
+		t.Errorf("To = %v, want %v", repo.Sent[0].To, want)
+	}
+	if want := []string{"pager@example.com"}; !slices.Equal(mail.AddressStrings(repo.Sent[0].Cc), want) {
+		t.Errorf("Cc = %v, want %v", repo.Sent[0].Cc, want)
+	}
+	if !contains(errOut.String(), "skipped 1 contact(s) without an email address: Cy") {
+		t.Errorf("expected a warning about Cy, got %q", errOut.String())
+	}
+}
+
+func TestRunMailSend_DryRunShowsExpansion(t *testing.T) {
+	repo, cmd, out, _ := setupMailGroupsTest(t)
+	mailSendTo = []string{"group:oncall"}
+	mailSendDryRun = true
+
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 0 {
+		t.Error("expected nothing to be sent on a dry run")
+	}
+	for _, want := range []string{"group:oncall (config) -> pager@example.com, ana@example.com", "To: pager@example.com, ana@example.com", "Dry run: message not sent"} {
+		if !contains(out.String(), want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunMailSend_ContactsGroupWithoutScope(t *testing.T) {
+	repo, cmd, _, _ := setupMailGroupsTest(t)
+	grantScopes(auth.ScopeGmailSend)
+	mailSendTo = []string{"group:Team Platform"}
+
+	err := runMailSend(cmd, nil)
+	if err == nil || !contains(err.Error(), `recipient group "Team Platform" is not in mail.groups`) ||
+		!contains(err.Error(), "was not granted "+auth.ScopeContactsReadonly) {
+		t.Fatalf("expected an error naming the missing scope, got %v", err)
+	}
+	if len(repo.Sent) != 0 {
+		t.Error("expected nothing to be sent")
+	}
+
+	// Groups from mail.groups need no Contacts access.
+	mailSendTo = []string{"group:oncall"}
+	if err := runMailSend(cmd, nil); err != nil || len(repo.Sent) != 1 {
+		t.Errorf("expected mail.groups to expand on a send-only login, got %v", err)
+	}
+}
+
+func TestExpandRecipientGroups_Errors(t *testing.T) {
+	setupMailGroupsTest(t)
+	ctx := context.Background()
+
+	if _, _, err := expandRecipientGroups(ctx, []string{"group:missing"}); err == nil || !contains(err.Error(), "unknown recipient group") {
+		t.Errorf("expected an unknown group error, got %v", err)
+	}
+	if _, _, err := expandRecipientGroups(ctx, []string{"group:"}); err == nil {
+		t.Error("expected an error for an empty group name")
+	}
+
+	lists, expansions, err := expandRecipientGroups(ctx, []string{"a@example.com"}, nil)
+	if err != nil || expansions != nil || !slices.Equal(lists[0], []string{"a@example.com"}) {
+		t.Errorf("expected lists without groups to be unchanged, got %v, %v, %v", lists, expansions, err)
+	}
+}
@@ -0,0 +1,45 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// headerFlag is the repeatable flag adding a header to a sent message.
+const headerFlag = "header"
+
+// addHeaderFlag adds the --header flag to a sending command.
+func addHeaderFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray(headerFlag, nil, `extra header as "Name: value" (repeatable)`)
+}
+
+// messageHeaders returns the extra headers for a message sent by cmd: the
+// mail.headers config defaults, with --header values replacing defaults of
+// the same name. Every header is validated.
+func messageHeaders(cmd *cobra.Command) ([]mail.Header, error) {
+	var defaults []mail.Header
+	if cfg, err := config.Load(); err == nil {
+		for _, entry := range cfg.Mail.Headers {
+			h, err := mail.ParseHeader(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mail.headers entry: %w", err)
+			}
+			defaults = append(defaults, h)
+		}
+	}
+
+	values, _ := cmd.Flags().GetStringArray(headerFlag)
+	headers := make([]mail.Header, 0, len(values))
+	for _, value := range values {
+		h, err := mail.ParseHeader(value)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, h)
+	}
+	return mail.MergeHeaders(defaults, headers), nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+func TestMessageHeaders(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Mail.Headers = []string{"X-Mailer: goog", "Reply-To: team@example.com"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		addHeaderFlag(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	got, err := messageHeaders(newCmd("--header", "X-Campaign: launch", "--header", "reply-to: me@example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []mail.Header{
+		{Name: "X-Mailer", Value: "goog"},
+		{Name: "X-Campaign", Value: "launch"},
+		{Name: "reply-to", Value: "<me@example.com>"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("messageHeaders() = %v, want %v", got, want)
+	}
+
+	if _, err := messageHeaders(newCmd("--header", "Subject: spoofed")); err == nil || !strings.Contains(err.Error(), "set by goog") {
+		t.Errorf("expected a reserved header error, got %v", err)
+	}
+
+	cfg.Mail.Headers = []string{"Bcc: hidden@example.com"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := messageHeaders(newCmd()); err == nil || !strings.Contains(err.Error(), "mail.headers") {
+		t.Errorf("expected an invalid config error, got %v", err)
+	}
+}
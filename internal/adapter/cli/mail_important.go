@@ -0,0 +1,87 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// importantLabel is Gmail's system label for important messages.
+const importantLabel = "IMPORTANT"
+
+// mailImportantCmd marks messages as important.
+var mailImportantCmd = &cobra.Command{
+	Use:   "important <message-id>...",
+	Short: "Mark messages as important",
+	Long: `Mark one or more messages as important.
+
+This applies Gmail's IMPORTANT label, the same marker Gmail sets
+automatically. Use 'goog mail list --filter important' to show only
+important messages and --importance to add a marker column.`,
+	Example: `  # Mark a message as important
+  goog mail important 18c1234abcd
+
+  # List important unread messages with a marker column
+  goog mail list --unread-only --filter important --importance`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailImportant,
+}
+
+// mailUnimportantCmd removes the important marker from messages.
+var mailUnimportantCmd = &cobra.Command{
+	Use:   "unimportant <message-id>...",
+	Short: "Mark messages as not important",
+	Long: `Remove the important marker from one or more messages.
+
+This removes Gmail's IMPORTANT label. Gmail may mark similar messages
+as important again based on its own signals.`,
+	Example: `  # Mark a message as not important
+  goog mail unimportant 18c1234abcd`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailUnimportant,
+}
+
+func init() {
+	mailCmd.AddCommand(mailImportantCmd)
+	mailCmd.AddCommand(mailUnimportantCmd)
+}
+
+// runMailImportant handles the mail important command.
+func runMailImportant(cmd *cobra.Command, args []string) error {
+	return setMailImportance(cmd, args, true)
+}
+
+// runMailUnimportant handles the mail unimportant command.
+func runMailUnimportant(cmd *cobra.Command, args []string) error {
+	return setMailImportance(cmd, args, false)
+}
+
+// setMailImportance adds or removes the IMPORTANT label on messages.
+func setMailImportance(cmd *cobra.Command, ids []string, important bool) error {
+	ctx := context.Background()
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := mail.ModifyRequest{RemoveLabels: []string{importantLabel}}
+	state := "not important"
+	if important {
+		req = mail.ModifyRequest{AddLabels: []string{importantLabel}}
+		state = "important"
+	}
+
+	for _, id := range ids {
+		if _, err := repo.Modify(ctx, id, req); err != nil {
+			return fmt.Errorf("failed to mark message %s as %s: %w", id, state, err)
+		}
+		if !quietFlag {
+			cmd.Printf("Message %s marked as %s\n", id, state)
+		}
+	}
+	return nil
+}
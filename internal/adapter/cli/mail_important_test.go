@@ -0,0 +1,128 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupMailImportantTest injects a message repository and resets the
+// output flags.
+func setupMailImportantTest(t *testing.T, repo MessageRepository) *cobra.Command {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origFormat, origQuiet, origShow := formatFlag, quietFlag, mailShowImportance
+	formatFlag = "table"
+	quietFlag = false
+	mailShowImportance = false
+	t.Cleanup(func() {
+		ResetDependencies()
+		formatFlag, quietFlag, mailShowImportance = origFormat, origQuiet, origShow
+		presenter.SetImportanceColumn(false)
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+	return cmd
+}
+
+func TestMailImportantCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "important", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"<message-id>", "IMPORTANT", "--filter important"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunMailImportant(t *testing.T) {
+	t.Run("adds the label to each message", func(t *testing.T) {
+		repo := &modifyRecordingRepository{}
+		cmd := setupMailImportantTest(t, repo)
+
+		if err := runMailImportant(cmd, []string{"m1", "m2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, id := range []string{"m1", "m2"} {
+			req := repo.Modified[id]
+			if len(req.AddLabels) != 1 || req.AddLabels[0] != "IMPORTANT" || len(req.RemoveLabels) != 0 {
+				t.Errorf("message %s modify = %+v, want add IMPORTANT", id, req)
+			}
+		}
+		if out := cmd.OutOrStdout().(*bytes.Buffer).String(); !containsStr(out, "Message m2 marked as important") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+
+	t.Run("unimportant removes the label", func(t *testing.T) {
+		repo := &modifyRecordingRepository{}
+		cmd := setupMailImportantTest(t, repo)
+
+		if err := runMailUnimportant(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		req := repo.Modified["m1"]
+		if len(req.RemoveLabels) != 1 || req.RemoveLabels[0] != "IMPORTANT" || len(req.AddLabels) != 0 {
+			t.Errorf("modify = %+v, want remove IMPORTANT", req)
+		}
+		if out := cmd.OutOrStdout().(*bytes.Buffer).String(); !containsStr(out, "marked as not important") {
+			t.Errorf("unexpected output: %s", out)
+		}
+	})
+
+	t.Run("modify error", func(t *testing.T) {
+		repo := &modifyRecordingRepository{MockMessageRepository: MockMessageRepository{ModifyErr: errors.New("boom")}}
+		cmd := setupMailImportantTest(t, repo)
+
+		err := runMailImportant(cmd, []string{"m1"})
+		if err == nil || !containsStr(err.Error(), "failed to mark message m1 as important") {
+			t.Errorf("expected modify error, got %v", err)
+		}
+	})
+}
+
+func TestRunMailList_ImportanceColumn(t *testing.T) {
+	important := mail.NewMessage("m1", "t1", "boss@example.com", "Urgent", "")
+	important.IsImportant = true
+	repo := &MockMessageRepository{ListResult: &mail.ListResult[*mail.Message]{
+		Items: []*mail.Message{important, mail.NewMessage("m2", "t2", "news@example.com", "Weekly", "")},
+	}}
+	cmd := setupMailImportantTest(t, repo)
+	mailShowImportance = true
+
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !presenter.ImportanceColumn() {
+		t.Error("expected --importance to enable the importance column")
+	}
+	if out := cmd.OutOrStdout().(*bytes.Buffer).String(); !containsStr(out, "!") {
+		t.Errorf("expected importance marker in output:\n%s", out)
+	}
+}
@@ -0,0 +1,185 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/ics"
+)
+
+// Command flags for mail rsvp command.
+var mailRSVPCalendar string
+
+// mailRSVPCmd answers a meeting invitation received by mail.
+var mailRSVPCmd = &cobra.Command{
+	Use:   "rsvp <message-id> accept|decline|tentative",
+	Short: "Answer a meeting invitation received by mail",
+	Long: `Answer the meeting invitation in a message through Google Calendar.
+
+The event is found in --calendar by the UID in the message's
+text/calendar part, and your response is set there, so the organizer
+is told as if you had answered in Calendar. An invitation for a whole
+recurring event answers every occurrence; one for a single occurrence
+answers only that one. Google Calendar adds invitations to your
+calendar when they arrive; an invitation it has not added cannot be
+answered.
+
+'goog mail read' shows the invitation, with your current answer, under
+the message's own fields.`,
+	Example: `  # Accept an invitation
+  goog mail rsvp 18c1234abcd accept
+
+  # Decline one that landed in another calendar
+  goog mail rsvp 18c1234abcd decline --calendar team@group.calendar.google.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMailRSVP,
+}
+
+func init() {
+	mailCmd.AddCommand(mailRSVPCmd)
+
+	mailRSVPCmd.Flags().StringVar(&mailRSVPCalendar, "calendar", "primary", "calendar ID holding the event")
+	addCheckTravelFlag(mailRSVPCmd)
+}
+
+// errNoInvitedEvent is returned when the event of an invitation is not in
+// the calendar.
+var errNoInvitedEvent = errors.New("event not found in the calendar")
+
+// runMailRSVP handles the mail rsvp command.
+func runMailRSVP(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	messageID := args[0]
+	response, err := mail.ParseResponse(args[1])
+	if err != nil {
+		return err
+	}
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	msg, err := repo.Get(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to read message: %w", err)
+	}
+	inv, err := messageInvitation(msg)
+	if err != nil {
+		return err
+	}
+	if inv.IsCancelled() {
+		return fmt.Errorf("message %s cancels %q; there is nothing to answer", messageID, inv.Summary)
+	}
+
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	event, err := findInvitedEvent(ctx, eventRepo, mailRSVPCalendar, inv)
+	if err != nil {
+		return err
+	}
+
+	// A whole recurring event is answered on the series, not the
+	// occurrence the listing found
+	eventID := event.ID
+	if inv.RecurrenceID.IsZero() && event.RecurringEventID != "" {
+		eventID = event.RecurringEventID
+	}
+	if err := eventRepo.RSVP(ctx, mailRSVPCalendar, eventID, response); err != nil {
+		return fmt.Errorf("failed to update RSVP: %w", err)
+	}
+
+	if response == mail.ResponseAccepted {
+		warnTravel(ctx, cmd, eventRepo, mailRSVPCalendar, event)
+	}
+	if !quietFlag {
+		cmd.Printf("RSVP updated: %s (%s)\n", response, inv.Summary)
+	}
+	return nil
+}
+
+// messageInvitation reads the invitation in a message's text/calendar
+// part.
+func messageInvitation(msg *mail.Message) (*mail.Invitation, error) {
+	if strings.TrimSpace(msg.Calendar) == "" {
+		return nil, fmt.Errorf("message %s has no calendar invitation", msg.ID)
+	}
+	inv, err := ics.ParseInvitation(strings.NewReader(msg.Calendar))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the invitation in message %s: %w", msg.ID, err)
+	}
+	return inv, nil
+}
+
+// findInvitedEvent finds the event of an invitation in a calendar by its
+// UID. The events around the invitation's time are listed, as the UID
+// cannot be searched for; an occurrence starting at the invited time is
+// preferred over others of the same recurring event.
+func findInvitedEvent(ctx context.Context, repo EventRepository, calendarID string, inv *mail.Invitation) (*calendar.Event, error) {
+	end := inv.End
+	if end.Before(inv.Start) {
+		end = inv.Start
+	}
+	events, err := repo.List(ctx, calendarID, inv.Start.Add(-24*time.Hour), end.Add(24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the event: %w", err)
+	}
+
+	var found *calendar.Event
+	for _, e := range events {
+		if e.ICalUID != inv.UID {
+			continue
+		}
+		if e.Start.Equal(inv.Start) {
+			return e, nil
+		}
+		if found == nil {
+			found = e
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q is not in calendar %s", errNoInvitedEvent, inv.Summary, calendarID)
+	}
+	return found, nil
+}
+
+// showInvitation reads the invitation of a message being shown, with the
+// account's answer as Calendar holds it. When the event cannot be looked
+// up, the answer recorded in the invitation is shown instead.
+func showInvitation(ctx context.Context, cmd *cobra.Command, msg *mail.Message, account string) {
+	if strings.TrimSpace(msg.Calendar) == "" {
+		return
+	}
+	inv, err := messageInvitation(msg)
+	if err != nil {
+		output.Warnf(cmd, "%v", err)
+		return
+	}
+	msg.Invitation = inv
+	inv.Response = inv.ResponseOf(account)
+	if inv.IsCancelled() {
+		return
+	}
+
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
+	if err == nil {
+		var event *calendar.Event
+		if event, err = findInvitedEvent(ctx, eventRepo, "primary", inv); err == nil {
+			for _, a := range event.Attendees {
+				if a.Self {
+					inv.Response = a.ResponseStatus
+				}
+			}
+			return
+		}
+	}
+	output.Debugf(cmd, "using the answer recorded in the invitation: %v", err)
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+const testInvite = "BEGIN:VCALENDAR\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:review@google.com\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T093000Z\r\n" +
+	"RRULE:FREQ=WEEKLY\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"ORGANIZER;CN=Ana:mailto:ana@example.com\r\n" +
+	"ATTENDEE;PARTSTAT=ACCEPTED:mailto:ana@example.com\r\n" +
+	"ATTENDEE;PARTSTAT=NEEDS-ACTION:mailto:me@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// setupInviteTest injects a message repository holding msg and an event
+// repository holding events.
+func setupInviteTest(t *testing.T, msg *mail.Message, events ...*calendar.Event) *MockEventRepository {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	eventRepo := &MockEventRepository{Events: events}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: &MockMessageRepository{Message: msg}, EventRepo: eventRepo},
+	})
+
+	origCalendar, origFormat, origQuiet := mailRSVPCalendar, formatFlag, quietFlag
+	mailRSVPCalendar, formatFlag, quietFlag = "primary", "plain", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailRSVPCalendar, formatFlag, quietFlag = origCalendar, origFormat, origQuiet
+	})
+	return eventRepo
+}
+
+// inviteOccurrence is the first occurrence of testInvite as Calendar
+// lists it.
+func inviteOccurrence(response string) *calendar.Event {
+	return &calendar.Event{
+		ID:               "series1_20260302T090000Z",
+		ICalUID:          "review@google.com",
+		RecurringEventID: "series1",
+		Start:            time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		End:              time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+		Attendees:        []*calendar.Attendee{{Email: "me@example.com", Self: true, ResponseStatus: response}},
+	}
+}
+
+func TestRunMailRSVP(t *testing.T) {
+	msg := &mail.Message{ID: "m1", Subject: "Invitation: Design review", Calendar: testInvite}
+	other := &calendar.Event{ID: "other", ICalUID: "other@google.com", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)}
+	eventRepo := setupInviteTest(t, msg, other, inviteOccurrence(calendar.ResponseNeedsAction))
+
+	cmd := &cobra.Command{Use: "test"}
+	addCheckTravelFlag(cmd)
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	if err := runMailRSVP(cmd, []string{"m1", "accept"}); err != nil {
+		t.Fatalf("runMailRSVP() error = %v", err)
+	}
+	if eventRepo.RSVPEventID != "series1" || eventRepo.RSVPResponse != calendar.ResponseAccepted {
+		t.Errorf("RSVP(%q, %q), want the series accepted", eventRepo.RSVPEventID, eventRepo.RSVPResponse)
+	}
+	if got := buf.String(); got != "RSVP updated: accepted (Design review)\n" {
+		t.Errorf("output = %q", got)
+	}
+	if !eventRepo.ListTimeMin.Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("listed from %v, want a day before the event", eventRepo.ListTimeMin)
+	}
+}
+
+func TestRunMailRSVP_Errors(t *testing.T) {
+	cancelled := strings.Replace(testInvite, "METHOD:REQUEST", "METHOD:CANCEL", 1)
+	tests := []struct {
+		name     string
+		calendar string
+		response string
+		events   []*calendar.Event
+		want     string
+	}{
+		{name: "bad response", calendar: testInvite, response: "later", want: "invalid response"},
+		{name: "no invitation", response: "accept", want: "has no calendar invitation"},
+		{name: "cancelled", calendar: cancelled, response: "accept", want: "nothing to answer"},
+		{name: "not in calendar", calendar: testInvite, response: "decline", want: "is not in calendar primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventRepo := setupInviteTest(t, &mail.Message{ID: "m1", Calendar: tt.calendar}, tt.events...)
+			err := runMailRSVP(&cobra.Command{Use: "test"}, []string{"m1", tt.response})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("runMailRSVP() error = %v, want %q", err, tt.want)
+			}
+			if tt.name == "not in calendar" && !errors.Is(err, errNoInvitedEvent) {
+				t.Errorf("error = %v, want errNoInvitedEvent", err)
+			}
+			if eventRepo.RSVPEventID != "" {
+				t.Error("RSVP was called")
+			}
+		})
+	}
+}
+
+func TestRunMailRead_Invitation(t *testing.T) {
+	msg := &mail.Message{ID: "m1", Subject: "Invitation: Design review", Calendar: testInvite}
+
+	t.Run("answer from calendar", func(t *testing.T) {
+		setupInviteTest(t, msg, inviteOccurrence(calendar.ResponseTentative))
+		cmd := &cobra.Command{Use: "test"}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		if err := runMailRead(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("runMailRead() error = %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{"Invitation: Design review\n", "Organizer: Ana <ana@example.com>", "Guests: 2 (1 accepted, 1 not answered)", "RSVP: tentative"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output should contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("answer from invitation", func(t *testing.T) {
+		setupInviteTest(t, msg)
+		cmd := &cobra.Command{Use: "test"}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		if err := runMailRead(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("runMailRead() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "RSVP: not answered") {
+			t.Errorf("output should fall back to the invitation's answer, got:\n%s", buf.String())
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupMailLinksTest injects a repository holding msg and resets the
+// links flags.
+func setupMailLinksTest(t *testing.T, msg *mail.Message, format string) *bytes.Buffer {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: &MockMessageRepository{Message: msg}},
+	})
+	origFlagged, origFormat, origQuiet := mailLinksFlagged, formatFlag, quietFlag
+	mailLinksFlagged, formatFlag, quietFlag = false, format, false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailLinksFlagged, formatFlag, quietFlag = origFlagged, origFormat, origQuiet
+	})
+	return new(bytes.Buffer)
+}
+
+var linksMessage = &mail.Message{
+	ID: "m1",
+	BodyHTML: `<a href="https://eur01.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Fdocs">Docs</a>
+<a href="https://account-verify.example.net/login">https://accounts.google.com</a>`,
+}
+
+func TestRunMailLinks(t *testing.T) {
+	buf := setupMailLinksTest(t, linksMessage, "table")
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	if err := runMailLinks(cmd, []string{"m1"}); err != nil {
+		t.Fatalf("runMailLinks() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"1. Docs\n",
+		"   Destination: https://example.com/docs (via eur01.safelinks.protection.outlook.com)\n",
+		"2. https://accounts.google.com  [FLAGGED]\n",
+		"   ! text shows accounts.google.com but the link goes to account-verify.example.net\n",
+		"2 link(s), 1 flagged.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunMailLinks_Formats(t *testing.T) {
+	t.Run("plain flagged", func(t *testing.T) {
+		buf := setupMailLinksTest(t, linksMessage, "plain")
+		mailLinksFlagged = true
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+		if err := runMailLinks(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("runMailLinks() error = %v", err)
+		}
+		want := "https://account-verify.example.net/login\thttps://accounts.google.com\ttext shows accounts.google.com but the link goes to account-verify.example.net\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := setupMailLinksTest(t, linksMessage, "json")
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+		if err := runMailLinks(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("runMailLinks() error = %v", err)
+		}
+		var links []*mail.Link
+		if err := json.Unmarshal(buf.Bytes(), &links); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if len(links) != 2 || links[0].Destination != "https://example.com/docs" {
+			t.Errorf("links = %+v", links)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Command flags for mail mute commands.
+var (
+	mailMuteSweep   bool
+	mailUnmuteInbox bool
+)
+
+// mailMuteCmd mutes threads.
+var mailMuteCmd = &cobra.Command{
+	Use:   "mute <thread-id>...",
+	Short: "Mute threads",
+	Long: `Mute one or more threads, as Gmail's mute does.
+
+Muted threads carry the mute label (mail.mute_label, default "Muted") and
+are archived. Replies that arrive later land in the inbox until the next
+sweep archives them again: 'goog rules run' sweeps on every run, and
+--sweep does it on its own, e.g. from 'goog schedule'.
+
+'goog mail list' and 'goog thread list' leave muted threads out unless
+--include-muted is given or the mute label is listed explicitly.`,
+	Example: `  # Mute a thread
+  goog mail mute 18c1234abcd
+
+  # Archive new replies to muted threads every ten minutes
+  goog schedule add "*/10 * * * *" "mail mute --sweep"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !mailMuteSweep {
+			return fmt.Errorf("requires at least one thread ID or --sweep")
+		}
+		return nil
+	},
+	RunE: runMailMute,
+}
+
+// mailUnmuteCmd unmutes threads.
+var mailUnmuteCmd = &cobra.Command{
+	Use:   "unmute <thread-id>...",
+	Short: "Unmute threads",
+	Long: `Remove the mute label from one or more threads.
+
+Unmuted threads stay archived, as in Gmail; use --inbox to move them
+back to the inbox.`,
+	Example: `  # Unmute a thread
+  goog mail unmute 18c1234abcd
+
+  # Unmute a thread and return it to the inbox
+  goog mail unmute 18c1234abcd --inbox`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailUnmute,
+}
+
+func init() {
+	mailCmd.AddCommand(mailMuteCmd)
+	mailCmd.AddCommand(mailUnmuteCmd)
+
+	mailMuteCmd.Flags().BoolVar(&mailMuteSweep, "sweep", false, "archive new messages in muted threads")
+	mailUnmuteCmd.Flags().BoolVar(&mailUnmuteInbox, "inbox", false, "also move the threads back to the inbox")
+}
+
+// muteLabelName returns the configured mute label name.
+func muteLabelName() string {
+	cfg, err := config.Load()
+	if err != nil || strings.TrimSpace(cfg.Mail.MuteLabel) == "" {
+		return config.NewConfig().Mail.MuteLabel
+	}
+	return strings.TrimSpace(cfg.Mail.MuteLabel)
+}
+
+// mutedExclusion returns the search term that leaves muted threads out of a
+// listing of labelIDs, or "" when include is set or the mute label is
+// listed.
+func mutedExclusion(labelIDs []string, include bool) string {
+	if include {
+		return ""
+	}
+	name := muteLabelName()
+	for _, id := range labelIDs {
+		if strings.EqualFold(id, name) || strings.EqualFold(id, mail.SearchLabelTerm(name)) {
+			return ""
+		}
+	}
+	return "-label:" + mail.SearchLabelTerm(name)
+}
+
+// runMailMute handles the mail mute command.
+func runMailMute(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		labelRepo, err := getLabelRepositoryFromDeps(ctx)
+		if err != nil {
+			return err
+		}
+		label, err := findOrCreateLabel(ctx, labelRepo, muteLabelName(), true)
+		if err != nil {
+			return err
+		}
+
+		req := mail.ModifyRequest{AddLabels: []string{label.ID}, RemoveLabels: []string{"INBOX"}}
+		for _, id := range args {
+			if _, err := repo.Modify(ctx, id, req); err != nil {
+				return fmt.Errorf("failed to mute thread %s: %w", id, err)
+			}
+			if !quietFlag {
+				cmd.Printf("Thread %s muted\n", id)
+			}
+		}
+	}
+
+	if mailMuteSweep {
+		swept, err := sweepMutedThreads(ctx, repo, false)
+		if err != nil {
+			return err
+		}
+		if !quietFlag {
+			cmd.Printf("Archived %d muted thread(s) with new messages.\n", len(swept))
+		}
+	}
+	return nil
+}
+
+// runMailUnmute handles the mail unmute command.
+func runMailUnmute(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	label, err := findOrCreateLabel(ctx, labelRepo, muteLabelName(), false)
+	if err != nil {
+		return err
+	}
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := mail.ModifyRequest{RemoveLabels: []string{label.ID}}
+	if mailUnmuteInbox {
+		req.AddLabels = []string{"INBOX"}
+	}
+	for _, id := range args {
+		if _, err := repo.Modify(ctx, id, req); err != nil {
+			return fmt.Errorf("failed to unmute thread %s: %w", id, err)
+		}
+		if !quietFlag {
+			cmd.Printf("Thread %s unmuted\n", id)
+		}
+	}
+	return nil
+}
+
+// sweepMutedThreads archives muted threads that have messages in the inbox
+// again and returns their IDs. Modifying the whole thread also labels the
+// new messages. With dryRun the threads are only found.
+func sweepMutedThreads(ctx context.Context, repo ThreadRepository, dryRun bool) ([]string, error) {
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	label, err := labelRepo.GetByName(ctx, muteLabelName())
+	if errors.Is(err, mail.ErrLabelNotFound) {
+		// Nothing has been muted yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the mute label: %w", err)
+	}
+
+	var ids []string
+	opts := mail.ListOptions{MaxResults: 100, LabelIDs: []string{label.ID, "INBOX"}}
+	for {
+		result, err := repo.List(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list muted threads: %w", err)
+		}
+		for _, thread := range result.Items {
+			ids = append(ids, thread.ID)
+		}
+		if result.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = result.NextPageToken
+	}
+
+	if dryRun {
+		return ids, nil
+	}
+	req := mail.ModifyRequest{AddLabels: []string{label.ID}, RemoveLabels: []string{"INBOX"}}
+	for _, id := range ids {
+		if _, err := repo.Modify(ctx, id, req); err != nil {
+			return nil, fmt.Errorf("failed to archive muted thread %s: %w", id, err)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// modifyRecordingThreadRepository records thread listings and label
+// modifications.
+type modifyRecordingThreadRepository struct {
+	MockThreadRepository
+	Modified map[string]mail.ModifyRequest
+	Listed   []mail.ListOptions
+}
+
+func (m *modifyRecordingThreadRepository) Modify(ctx context.Context, id string, req mail.ModifyRequest) (*mail.Thread, error) {
+	if m.Modified == nil {
+		m.Modified = make(map[string]mail.ModifyRequest)
+	}
+	m.Modified[id] = req
+	return mail.NewThread(id), nil
+}
+
+func (m *modifyRecordingThreadRepository) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Thread], error) {
+	m.Listed = append(m.Listed, opts)
+	return m.MockThreadRepository.List(ctx, opts)
+}
+
+// setupMailMuteTest injects repositories with an empty config and resets
+// the mute flags.
+func setupMailMuteTest(t *testing.T, threadRepo ThreadRepository, labelRepo LabelRepository) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{ThreadRepo: threadRepo, LabelRepo: labelRepo},
+	})
+
+	origSweep, origInbox, origQuiet := mailMuteSweep, mailUnmuteInbox, quietFlag
+	mailMuteSweep, mailUnmuteInbox, quietFlag = false, false, false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailMuteSweep, mailUnmuteInbox, quietFlag = origSweep, origInbox, origQuiet
+	})
+}
+
+func TestRunMailMute(t *testing.T) {
+	threadRepo := &modifyRecordingThreadRepository{}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	setupMailMuteTest(t, threadRepo, labelRepo)
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runMailMute(cmd, []string{"t1", "t2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(labelRepo.Created, []string{"Muted"}) {
+		t.Errorf("created labels = %v, want [Muted]", labelRepo.Created)
+	}
+	for _, id := range []string{"t1", "t2"} {
+		req := threadRepo.Modified[id]
+		if !slices.Equal(req.AddLabels, []string{"Label_Muted"}) || !slices.Equal(req.RemoveLabels, []string{"INBOX"}) {
+			t.Errorf("thread %s modified with %+v", id, req)
+		}
+	}
+	if !contains(buf.String(), "Thread t2 muted") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRunMailMute_Sweep(t *testing.T) {
+	threadRepo := &modifyRecordingThreadRepository{}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	setupMailMuteTest(t, threadRepo, labelRepo)
+	mailMuteSweep = true
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	// Without a mute label nothing has been muted
+	if err := runMailMute(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(threadRepo.Listed) != 0 || !contains(buf.String(), "Archived 0 muted thread(s)") {
+		t.Errorf("expected no sweep without a mute label, got %v: %s", threadRepo.Listed, buf.String())
+	}
+
+	labelRepo.ByName["Muted"] = &mail.Label{ID: "Label_5", Name: "Muted"}
+	threadRepo.Threads = []*mail.Thread{mail.NewThread("t1")}
+	buf.Reset()
+	if err := runMailMute(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(threadRepo.Listed) != 1 || !slices.Equal(threadRepo.Listed[0].LabelIDs, []string{"Label_5", "INBOX"}) {
+		t.Errorf("unexpected listing: %+v", threadRepo.Listed)
+	}
+	if req := threadRepo.Modified["t1"]; !slices.Equal(req.RemoveLabels, []string{"INBOX"}) || !slices.Equal(req.AddLabels, []string{"Label_5"}) {
+		t.Errorf("thread t1 modified with %+v", req)
+	}
+	if !contains(buf.String(), "Archived 1 muted thread(s) with new messages.") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRunMailUnmute(t *testing.T) {
+	threadRepo := &modifyRecordingThreadRepository{}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	setupMailMuteTest(t, threadRepo, labelRepo)
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+
+	if err := runMailUnmute(cmd, []string{"t1"}); err == nil {
+		t.Error("expected an error when the mute label does not exist")
+	}
+
+	labelRepo.ByName["Muted"] = &mail.Label{ID: "Label_5", Name: "Muted"}
+	mailUnmuteInbox = true
+	if err := runMailUnmute(cmd, []string{"t1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req := threadRepo.Modified["t1"]; !slices.Equal(req.RemoveLabels, []string{"Label_5"}) || !slices.Equal(req.AddLabels, []string{"INBOX"}) {
+		t.Errorf("thread t1 modified with %+v", req)
+	}
+}
+
+func TestMutedExclusion(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Mail.MuteLabel = "Quiet Threads"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		labelIDs []string
+		include  bool
+		want     string
+	}{
+		{"default listing", []string{"INBOX"}, false, "-label:quiet-threads"},
+		{"include muted", []string{"INBOX"}, true, ""},
+		{"mute label by name", []string{"quiet threads"}, false, ""},
+		{"mute label by search term", []string{"quiet-threads"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mutedExclusion(tt.labelIDs, tt.include); got != tt.want {
+				t.Errorf("mutedExclusion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMailMuteCmd_Args(t *testing.T) {
+	origSweep := mailMuteSweep
+	defer func() { mailMuteSweep = origSweep }()
+
+	mailMuteSweep = false
+	if err := mailMuteCmd.Args(mailMuteCmd, nil); err == nil {
+		t.Error("expected an error without thread IDs or --sweep")
+	}
+	mailMuteSweep = true
+	if err := mailMuteCmd.Args(mailMuteCmd, nil); err != nil {
+		t.Errorf("unexpected error with --sweep: %v", err)
+	}
+}
@@ -0,0 +1,388 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/outbox"
+)
+
+// queueOfflineFlag names the flag that queues unsendable mail in the outbox.
+const queueOfflineFlag = "queue-offline"
+
+// Command flags for mail outbox commands.
+var (
+	mailOutboxDiscardAll bool
+)
+
+// openOutboxKeyStore opens the credential store holding the outbox key. It
+// is a variable so tests can avoid touching the real keyring.
+var openOutboxKeyStore = keyring.NewStore
+
+// mailOutboxCmd represents the mail outbox command group.
+var mailOutboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Manage mail queued while offline",
+	Long: `Manage messages queued while the network was unavailable.
+
+When 'mail send' or 'mail forward' fails with a network error and
+--queue-offline is given (or mail.queue_offline is true), the message is
+queued in an encrypted local outbox instead. The messages are encrypted
+with a key kept in the system keyring and stay in the outbox until they
+are flushed or discarded.`,
+	Example: `  # Queue mail when offline by default
+  goog config set mail.queue_offline true
+
+  # Review, send and drop queued messages
+  goog mail outbox list
+  goog mail outbox flush
+  goog mail outbox discard 20260501T091500-a1b2c3`,
+}
+
+// mailOutboxListCmd lists queued messages.
+var mailOutboxListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List queued messages",
+	Long:    `List the messages waiting in the outbox, oldest first.`,
+	Example: `  # List queued messages
+  goog mail outbox list
+
+  # List queued messages as JSON
+  goog mail outbox list --format json`,
+	Args: cobra.NoArgs,
+	RunE: runMailOutboxList,
+}
+
+// mailOutboxFlushCmd sends queued messages.
+var mailOutboxFlushCmd = &cobra.Command{
+	Use:   "flush [id]...",
+	Short: "Send queued messages",
+	Long: `Send queued messages, oldest first, and remove each one that is sent.
+
+Without IDs every message queued for the current account is sent;
+messages queued from other accounts are skipped, so flush again with
+--account to send them. A message that fails stays queued with its
+attempt count and last error. Flushing stops at the first network error,
+since the remaining messages would fail the same way.`,
+	Example: `  # Send everything queued for the default account
+  goog mail outbox flush
+
+  # Send one message
+  goog mail outbox flush 20260501T091500-a1b2c3`,
+	RunE: runMailOutboxFlush,
+}
+
+// mailOutboxDiscardCmd removes queued messages without sending them.
+var mailOutboxDiscardCmd = &cobra.Command{
+	Use:   "discard <id>...",
+	Short: "Remove queued messages without sending",
+	Long:  `Remove messages from the outbox without sending them.`,
+	Example: `  # Drop one message
+  goog mail outbox discard 20260501T091500-a1b2c3
+
+  # Empty the outbox
+  goog mail outbox discard --all`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if mailOutboxDiscardAll && len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with message IDs")
+		}
+		if !mailOutboxDiscardAll && len(args) == 0 {
+			return fmt.Errorf("requires at least one message ID, or --all")
+		}
+		return nil
+	},
+	RunE: runMailOutboxDiscard,
+}
+
+func init() {
+	mailCmd.AddCommand(mailOutboxCmd)
+	mailOutboxCmd.AddCommand(mailOutboxListCmd)
+	mailOutboxCmd.AddCommand(mailOutboxFlushCmd)
+	mailOutboxCmd.AddCommand(mailOutboxDiscardCmd)
+
+	mailOutboxDiscardCmd.Flags().BoolVar(&mailOutboxDiscardAll, "all", false, "discard every queued message")
+}
+
+// outboxItem is the JSON form of a queued message in 'mail outbox list'.
+type outboxItem struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Account   string    `json:"account"`
+	MessageID string    `json:"message_id,omitempty"`
+	To        []string  `json:"to"`
+	Cc        []string  `json:"cc,omitempty"`
+	Bcc       []string  `json:"bcc,omitempty"`
+	Subject   string    `json:"subject"`
+	QueuedAt  time.Time `json:"queued_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// runMailOutboxList handles the mail outbox list command.
+func runMailOutboxList(cmd *cobra.Command, args []string) error {
+	box, err := openOutbox()
+	if err != nil {
+		return err
+	}
+	entries, err := box.List()
+	if err != nil {
+		return err
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		items := make([]outboxItem, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, outboxItem{
+				ID:        e.ID,
+				Kind:      string(e.Kind),
+				Account:   e.Account,
+				MessageID: e.MessageID,
+				To:        mail.AddressStrings(e.Message.To),
+				Cc:        mail.AddressStrings(e.Message.Cc),
+				Bcc:       mail.AddressStrings(e.Message.Bcc),
+				Subject:   e.Message.Subject,
+				QueuedAt:  e.QueuedAt,
+				Attempts:  e.Attempts,
+				LastError: e.LastError,
+			})
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode outbox: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		if !quietFlag {
+			cmd.Println("Outbox is empty.")
+		}
+		return nil
+	}
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "ID\tQUEUED\tACCOUNT\tTO\tSUBJECT\tATTEMPTS\tLAST ERROR")
+	for _, e := range entries {
+		subject := e.Message.Subject
+		if e.Kind == outbox.KindForward {
+			subject = "(forward of " + e.MessageID + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			e.ID,
+			presenter.CurrentLocale().DateTime(e.QueuedAt.Local()),
+			e.Account,
+			mail.JoinAddresses(e.Message.To),
+			subject,
+			e.Attempts,
+			e.LastError,
+		)
+	}
+	return w.Flush()
+}
+
+// runMailOutboxFlush handles the mail outbox flush command.
+func runMailOutboxFlush(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	box, err := openOutbox()
+	if err != nil {
+		return err
+	}
+	lock, err := box.Lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := selectOutboxEntries(box, args)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if !quietFlag {
+			cmd.Println("Outbox is empty.")
+		}
+		return nil
+	}
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sentCount, failed, skipped int
+	for _, e := range entries {
+		if !strings.EqualFold(e.Account, senderEmail) {
+			skipped++
+			continue
+		}
+		sent, err := sendQueued(ctx, repo, e)
+		if err != nil {
+			failed++
+			e.Attempts++
+			e.LastError = err.Error()
+			if saveErr := box.Save(e); saveErr != nil {
+				return saveErr
+			}
+			cmd.PrintErrf("Failed to send %s: %v\n", e.ID, err)
+			if isNetworkError(err) {
+				return fmt.Errorf("still offline; the remaining messages stay in the outbox")
+			}
+			continue
+		}
+		if err := box.Remove(e.ID); err != nil {
+			return fmt.Errorf("message %s was sent but could not be removed from the outbox: %w", e.ID, err)
+		}
+		recordRecipients(mail.AddressStrings(e.Message.To), mail.AddressStrings(e.Message.Cc), mail.AddressStrings(e.Message.Bcc))
+		sentCount++
+		cmd.Printf("Sent %s (Message ID: %s)\n", e.ID, sent.ID)
+	}
+
+	if skipped > 0 {
+		cmd.Printf("Skipped %d message(s) queued from other accounts; flush with --account to send them.\n", skipped)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d queued message(s) could not be sent", failed)
+	}
+	if !quietFlag {
+		cmd.Printf("Sent %d queued message(s).\n", sentCount)
+	}
+	return nil
+}
+
+// runMailOutboxDiscard handles the mail outbox discard command.
+func runMailOutboxDiscard(cmd *cobra.Command, args []string) error {
+	box, err := openOutbox()
+	if err != nil {
+		return err
+	}
+	lock, err := box.Lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	ids := args
+	if mailOutboxDiscardAll {
+		entries, err := box.List()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+	}
+	for _, id := range ids {
+		if err := box.Remove(id); err != nil {
+			return err
+		}
+		cmd.Printf("Discarded %s\n", id)
+	}
+	if len(ids) == 0 && !quietFlag {
+		cmd.Println("Outbox is empty.")
+	}
+	return nil
+}
+
+// openOutbox opens the outbox next to the config file.
+func openOutbox() (*outbox.Outbox, error) {
+	store, err := openOutboxKeyStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keyring for the outbox: %w", err)
+	}
+	return outbox.Open(outbox.Dir(), store)
+}
+
+// selectOutboxEntries returns the queued messages with the given IDs, or
+// all of them when no IDs are given.
+func selectOutboxEntries(box *outbox.Outbox, ids []string) ([]*outbox.Entry, error) {
+	if len(ids) == 0 {
+		return box.List()
+	}
+	entries := make([]*outbox.Entry, 0, len(ids))
+	for _, id := range ids {
+		e, err := box.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// sendQueued sends a queued message the way it was first attempted.
+func sendQueued(ctx context.Context, repo MessageRepository, e *outbox.Entry) (*mail.Message, error) {
+	switch e.Kind {
+	case outbox.KindSend:
+		return repo.Send(ctx, e.Message)
+	case outbox.KindForward:
+		return repo.Forward(ctx, e.MessageID, e.Message)
+	default:
+		return nil, fmt.Errorf("unknown queued message kind %q", e.Kind)
+	}
+}
+
+// addQueueFlag registers --queue-offline on a sending command.
+func addQueueFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(queueOfflineFlag, false, "queue the message in the outbox if the network is unavailable (default: mail.queue_offline)")
+}
+
+// queueOfflineEnabled reports whether --queue-offline, or
+// mail.queue_offline when the flag is not given, turns queueing on.
+func queueOfflineEnabled(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup(queueOfflineFlag); f != nil && f.Changed {
+		enabled, _ := cmd.Flags().GetBool(queueOfflineFlag)
+		return enabled
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return false
+	}
+	return cfg.Mail.QueueOffline
+}
+
+// queueOrFail handles a failed send. When the failure is a network error
+// and queueing is on, entry is queued in the outbox and nil is returned;
+// otherwise the error is returned, described by action.
+func queueOrFail(cmd *cobra.Command, entry *outbox.Entry, action string, sendErr error) error {
+	if !isNetworkError(sendErr) {
+		return fmt.Errorf("failed to %s: %w", action, sendErr)
+	}
+	if !queueOfflineEnabled(cmd) {
+		return fmt.Errorf("failed to %s: %w (use --%s to queue it until you are back online)", action, sendErr, queueOfflineFlag)
+	}
+
+	box, err := openOutbox()
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w; it could not be queued: %v", action, sendErr, err)
+	}
+	if err := box.Add(entry); err != nil {
+		return fmt.Errorf("failed to %s: %w; it could not be queued: %v", action, sendErr, err)
+	}
+	output.Warnf(cmd, "network unavailable: %v", sendErr)
+	cmd.Printf("Message queued in the outbox as %s.\n", entry.ID)
+	cmd.Printf("Run 'goog mail outbox flush' to send it.\n")
+	return nil
+}
+
+// isNetworkError reports whether err means Gmail could not be reached, as
+// opposed to Gmail rejecting the request.
+func isNetworkError(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
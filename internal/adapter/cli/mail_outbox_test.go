@@ -0,0 +1,297 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/outbox"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// memKeyStore is an in-memory keyring.Store.
+type memKeyStore map[string][]byte
+
+func (m memKeyStore) Set(account, key string, value []byte) error {
+	m[account+"/"+key] = value
+	return nil
+}
+
+func (m memKeyStore) Get(account, key string) ([]byte, error) {
+	if v, ok := m[account+"/"+key]; ok {
+		return v, nil
+	}
+	return nil, keyring.ErrKeyNotFound
+}
+
+func (m memKeyStore) Delete(account, key string) error {
+	delete(m, account+"/"+key)
+	return nil
+}
+
+func (m memKeyStore) List(account string) ([]string, error) { return nil, nil }
+
+// errOffline is the kind of error a send returns without a network.
+var errOffline = &url.Error{
+	Op:  "Post",
+	URL: "https://gmail.googleapis.com/gmail/v1/users/me/messages/send",
+	Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: network is unreachable")},
+}
+
+// setupOutboxTest isolates the outbox and its key, and injects repo for
+// the sender me@example.com.
+func setupOutboxTest(t *testing.T, repo *MockMessageRepository) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	store := memKeyStore{}
+	origStore, origFormat, origAll := openOutboxKeyStore, formatFlag, mailOutboxDiscardAll
+	openOutboxKeyStore = func() (keyring.Store, error) { return store, nil }
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+	t.Cleanup(func() {
+		ResetDependencies()
+		openOutboxKeyStore, formatFlag, mailOutboxDiscardAll = origStore, origFormat, origAll
+	})
+}
+
+// queuedEntries returns the messages in the test outbox.
+func queuedEntries(t *testing.T) []*outbox.Entry {
+	t.Helper()
+	box, err := openOutbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := box.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestIsNetworkError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"dial failure", errOffline, true},
+		{"wrapped dial failure", fmt.Errorf("max retries (3) exceeded: %w", errOffline), true},
+		{"dns failure", &url.Error{Op: "Post", URL: "https://x", Err: &net.DNSError{Err: "no such host", Name: "gmail.googleapis.com"}}, true},
+		{"token refresh rejected", &url.Error{Op: "Post", URL: "https://x", Err: errors.New("oauth2: invalid_grant")}, false},
+		{"api error", errors.New("bad request"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNetworkError(tt.err); got != tt.want {
+				t.Errorf("isNetworkError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunMailSend_QueueOffline(t *testing.T) {
+	repo := &MockMessageRepository{SendErr: errOffline}
+	setupOutboxTest(t, repo)
+
+	origTo, origSubject, origBody := mailSendTo, mailSendSubject, mailSendBody
+	origCc, origBcc := mailSendCc, mailSendBcc
+	mailSendTo, mailSendSubject, mailSendBody = []string{"bob@example.com"}, "Offline", "Written on a plane"
+	mailSendCc, mailSendBcc = nil, nil
+	t.Cleanup(func() {
+		mailSendTo, mailSendSubject, mailSendBody = origTo, origSubject, origBody
+		mailSendCc, mailSendBcc = origCc, origBcc
+	})
+
+	newCmd := func(args ...string) (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{Use: "test"}
+		addVerifyFlags(cmd)
+		addQueueFlag(cmd)
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(new(bytes.Buffer))
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd, buf
+	}
+
+	cmd, _ := newCmd()
+	err := runMailSend(cmd, nil)
+	if err == nil || !contains(err.Error(), "--queue-offline") {
+		t.Fatalf("expected an error with a queueing hint, got %v", err)
+	}
+	if entries := queuedEntries(t); len(entries) != 0 {
+		t.Fatalf("expected nothing queued, got %d", len(entries))
+	}
+
+	cmd, buf := newCmd("--queue-offline")
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries := queuedEntries(t)
+	if len(entries) != 1 {
+		t.Fatalf("expected one queued message, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Kind != outbox.KindSend || e.Account != "me@example.com" || e.Message.Body != "Written on a plane" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if !contains(buf.String(), "queued in the outbox as "+e.ID) {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	// Other failures are never queued.
+	repo.SendErr = errors.New("bad request")
+	cmd, _ = newCmd("--queue-offline")
+	if err := runMailSend(cmd, nil); err == nil || contains(err.Error(), "queue") {
+		t.Errorf("expected a plain send error, got %v", err)
+	}
+	if entries := queuedEntries(t); len(entries) != 1 {
+		t.Errorf("expected one queued message, got %d", len(entries))
+	}
+}
+
+func TestRunMailOutboxFlush(t *testing.T) {
+	repo := &MockMessageRepository{SendErr: errOffline, SendResult: &mail.Message{ID: "sent-1"}, ForwardResult: &mail.Message{ID: "sent-2"}}
+	setupOutboxTest(t, repo)
+
+	box, err := openOutbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, e := range []*outbox.Entry{
+		{Kind: outbox.KindSend, Account: "me@example.com", Message: &mail.Message{To: []mail.Address{{Email: "a@example.com"}}, Subject: "One"}},
+		{Kind: outbox.KindForward, MessageID: "orig", Account: "me@example.com", Message: &mail.Message{To: []mail.Address{{Email: "b@example.com"}}}},
+		{Kind: outbox.KindSend, Account: "work@example.com", Message: &mail.Message{To: []mail.Address{{Email: "c@example.com"}}}},
+	} {
+		e.QueuedAt = queued.Add(time.Duration(i) * time.Minute)
+		if err := box.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+
+	if err := runMailOutboxFlush(cmd, nil); err == nil || !contains(err.Error(), "still offline") {
+		t.Fatalf("expected an offline error, got %v", err)
+	}
+	entries := queuedEntries(t)
+	if len(entries) != 3 {
+		t.Fatalf("expected all messages to stay queued, got %d", len(entries))
+	}
+	if entries[0].Attempts != 1 || entries[0].LastError == "" || entries[1].Attempts != 0 {
+		t.Errorf("expected one recorded attempt, got %+v and %+v", entries[0], entries[1])
+	}
+
+	repo.SendErr = nil
+	if err := runMailOutboxFlush(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries = queuedEntries(t)
+	if len(entries) != 1 || entries[0].Account != "work@example.com" {
+		t.Fatalf("expected only the other account's message to remain, got %+v", entries)
+	}
+	for _, want := range []string{"(Message ID: sent-1)", "(Message ID: sent-2)", "Skipped 1 message(s)"} {
+		if !contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got: %s", want, buf.String())
+		}
+	}
+}
+
+func TestRunMailOutboxListAndDiscard(t *testing.T) {
+	setupOutboxTest(t, &MockMessageRepository{})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runMailOutboxList(cmd, nil); err != nil || !contains(buf.String(), "Outbox is empty.") {
+		t.Fatalf("unexpected list of an empty outbox: %v, %s", err, buf.String())
+	}
+
+	box, err := openOutbox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	first := &outbox.Entry{Kind: outbox.KindSend, Account: "me@example.com", QueuedAt: queued, Message: &mail.Message{To: []mail.Address{{Email: "a@example.com"}}, Subject: "Queued subject"}}
+	second := &outbox.Entry{Kind: outbox.KindForward, MessageID: "orig", Account: "me@example.com", QueuedAt: queued.Add(time.Minute), Message: &mail.Message{To: []mail.Address{{Email: "b@example.com"}}}}
+	for _, e := range []*outbox.Entry{first, second} {
+		if err := box.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf.Reset()
+	if err := runMailOutboxList(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{first.ID, "Queued subject", "(forward of orig)", "a@example.com"} {
+		if !contains(buf.String(), want) {
+			t.Errorf("expected table to contain %q, got: %s", want, buf.String())
+		}
+	}
+
+	formatFlag = "json"
+	buf.Reset()
+	if err := runMailOutboxList(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	var items []outboxItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(items) != 2 || items[1].Kind != "forward" || items[1].MessageID != "orig" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+
+	buf.Reset()
+	if err := runMailOutboxDiscard(cmd, []string{first.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMailOutboxDiscard(cmd, []string{first.ID}); !errors.Is(err, outbox.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	mailOutboxDiscardAll = true
+	if err := runMailOutboxDiscard(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if entries := queuedEntries(t); len(entries) != 0 {
+		t.Errorf("expected an empty outbox, got %d", len(entries))
+	}
+}
+
+func TestMailOutboxDiscardCmd_Args(t *testing.T) {
+	origAll := mailOutboxDiscardAll
+	defer func() { mailOutboxDiscardAll = origAll }()
+
+	mailOutboxDiscardAll = false
+	if err := mailOutboxDiscardCmd.Args(mailOutboxDiscardCmd, nil); err == nil {
+		t.Error("expected an error without IDs or --all")
+	}
+	mailOutboxDiscardAll = true
+	if err := mailOutboxDiscardCmd.Args(mailOutboxDiscardCmd, []string{"x"}); err == nil {
+		t.Error("expected an error for --all with IDs")
+	}
+}
@@ -0,0 +1,98 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// messageFetch is one message being fetched by a messagePrefetcher.
+type messageFetch struct {
+	done chan struct{}
+	msg  *mail.Message
+	err  error
+}
+
+// messagePrefetcher fetches the messages of an interactive session in
+// the background, a few ahead of the one being shown, so moving on to the
+// next message does not wait for the API. No more than the message shown
+// and the ones ahead of it are fetched at once, and rate limits are
+// retried with backoff like any other call.
+type messagePrefetcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	repo   MessageRepository
+	ids    []string
+	ahead  int
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	fetches map[int]*messageFetch
+}
+
+// newMessagePrefetcher creates a prefetcher for the messages with the
+// given IDs, fetching up to ahead of them past the one asked for. Zero
+// turns prefetching off. Close must be called when done.
+func newMessagePrefetcher(ctx context.Context, repo MessageRepository, ids []string, ahead int) *messagePrefetcher {
+	ctx, cancel := context.WithCancel(ctx)
+	return &messagePrefetcher{
+		ctx:     ctx,
+		cancel:  cancel,
+		repo:    repo,
+		ids:     ids,
+		ahead:   max(ahead, 0),
+		slots:   make(chan struct{}, max(ahead, 0)+1),
+		fetches: make(map[int]*messageFetch),
+	}
+}
+
+// Get returns message i, waiting for it if it has not been fetched yet,
+// and starts fetching the messages after it.
+func (p *messagePrefetcher) Get(i int) (*mail.Message, error) {
+	f := p.start(i)
+	for next := i + 1; next <= i+p.ahead && next < len(p.ids); next++ {
+		p.start(next)
+	}
+	<-f.done
+
+	// Messages are visited once, so a fetched message is not kept
+	p.mu.Lock()
+	delete(p.fetches, i)
+	p.mu.Unlock()
+	return f.msg, f.err
+}
+
+// start begins fetching message i unless it is already under way.
+func (p *messagePrefetcher) start(i int) *messageFetch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f, ok := p.fetches[i]; ok {
+		return f
+	}
+	f := &messageFetch{done: make(chan struct{})}
+	p.fetches[i] = f
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(f.done)
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.ctx.Done():
+			f.err = p.ctx.Err()
+			return
+		}
+		defer func() { <-p.slots }()
+		f.msg, f.err = p.repo.Get(p.ctx, p.ids[i])
+	}()
+	return f
+}
+
+// Close stops fetches still under way and waits for them to end.
+func (p *messagePrefetcher) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
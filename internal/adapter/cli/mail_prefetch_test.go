@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// countingMessageRepository records the messages fetched, blocking each
+// fetch until release is closed when it is set.
+type countingMessageRepository struct {
+	MockMessageRepository
+	release chan struct{}
+
+	mu      sync.Mutex
+	fetched map[string]int
+	running int
+	peak    int
+}
+
+func (m *countingMessageRepository) Get(ctx context.Context, id string) (*mail.Message, error) {
+	m.mu.Lock()
+	if m.fetched == nil {
+		m.fetched = make(map[string]int)
+	}
+	m.fetched[id]++
+	m.running++
+	m.peak = max(m.peak, m.running)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.running--
+		m.mu.Unlock()
+	}()
+
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &mail.Message{ID: id, Subject: "Subject " + id}, nil
+}
+
+// fetchedIDs returns how many times each message was fetched.
+func (m *countingMessageRepository) fetchedIDs() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.fetched)
+}
+
+func TestMessagePrefetcher(t *testing.T) {
+	repo := &countingMessageRepository{}
+	ids := []string{"m0", "m1", "m2", "m3", "m4", "m5"}
+	p := newMessagePrefetcher(context.Background(), repo, ids, 2)
+	defer p.Close()
+
+	msg, err := p.Get(0)
+	if err != nil || msg.ID != "m0" {
+		t.Fatalf("Get(0) = %v, %v", msg, err)
+	}
+	// The next two are fetched in the background
+	deadline := time.Now().Add(time.Second)
+	for len(repo.fetchedIDs()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := fmt.Sprint(repo.fetchedIDs()); got != "map[m0:1 m1:1 m2:1]" {
+		t.Fatalf("fetched %s after Get(0), want m0 to m2", got)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		msg, err := p.Get(i)
+		if err != nil || msg.ID != ids[i] {
+			t.Fatalf("Get(%d) = %v, %v", i, msg, err)
+		}
+	}
+	for id, n := range repo.fetchedIDs() {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, want once", id, n)
+		}
+	}
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.peak > 3 {
+		t.Errorf("%d fetches ran at once, want at most 3", repo.peak)
+	}
+}
+
+func TestMessagePrefetcher_CloseCancels(t *testing.T) {
+	repo := &countingMessageRepository{release: make(chan struct{})}
+	p := newMessagePrefetcher(context.Background(), repo, []string{"m0", "m1", "m2"}, 2)
+	p.start(0)
+	p.start(1)
+
+	done := make(chan struct{})
+	go func() {
+		p.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop fetches under way")
+	}
+}
+
+func TestMessagePrefetcher_Off(t *testing.T) {
+	repo := &countingMessageRepository{}
+	p := newMessagePrefetcher(context.Background(), repo, []string{"m0", "m1"}, 0)
+	defer p.Close()
+
+	if _, err := p.Get(0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := fmt.Sprint(repo.fetchedIDs()); got != "map[m0:1]" {
+		t.Errorf("fetched %s with prefetching off, want only m0", got)
+	}
+}
@@ -0,0 +1,46 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Names of the flags that quote the original in a reply.
+const (
+	quoteFlag       = "quote"
+	quotePrefixFlag = "quote-prefix"
+)
+
+// addQuoteFlags registers --quote and --quote-prefix on a replying command.
+func addQuoteFlags(cmd *cobra.Command) {
+	cmd.Flags().String(quoteFlag, "", "quote the original: top (reply above it), bottom or none (default: mail.reply_quote)")
+	cmd.Flags().String(quotePrefixFlag, "", `prefix of quoted lines (default: mail.quote_prefix, or "> ")`)
+}
+
+// quoteReplyBody returns the body of a reply to original, quoting the
+// original as --quote and --quote-prefix say, or mail.reply_quote and
+// mail.quote_prefix when the flags are not given.
+func quoteReplyBody(cmd *cobra.Command, body string, original *mail.Message) (string, error) {
+	var style, prefix string
+	if _, err := os.Stat(config.GetConfigPath()); err == nil {
+		if cfg, err := config.Load(); err == nil {
+			style, prefix = cfg.Mail.ReplyQuote, cfg.Mail.QuotePrefix
+		}
+	}
+	if f := cmd.Flags().Lookup(quoteFlag); f != nil && f.Changed {
+		style = f.Value.String()
+	}
+	if f := cmd.Flags().Lookup(quotePrefixFlag); f != nil && f.Changed {
+		prefix = f.Value.String()
+	}
+
+	style, err := mail.ParseQuoteStyle(style)
+	if err != nil {
+		return "", err
+	}
+	return mail.QuoteReply(body, exportBody(original), original, style, prefix), nil
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// replyRecordingRepository records the replies sent.
+type replyRecordingRepository struct {
+	MockMessageRepository
+	Replies []*mail.Message
+}
+
+func (m *replyRecordingRepository) Reply(ctx context.Context, messageID string, reply *mail.Message) (*mail.Message, error) {
+	m.Replies = append(m.Replies, reply)
+	return &mail.Message{ID: "reply-id", ThreadID: "thread-id"}, nil
+}
+
+// setupMailQuoteTest injects a repository holding a dated original and
+// writes a config with the given values.
+func setupMailQuoteTest(t *testing.T, values map[string]string) (*replyRecordingRepository, *cobra.Command) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	for key, value := range values {
+		if err := cfg.SetValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &replyRecordingRepository{MockMessageRepository: MockMessageRepository{Message: &mail.Message{
+		ID:       "original-id",
+		From:     mail.Address{Name: "Ana", Email: "ana@example.com"},
+		Subject:  "Lunch",
+		Date:     time.Date(2026, 3, 2, 9, 5, 0, 0, time.UTC),
+		BodyHTML: "<p>Thursday?</p>",
+	}}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origBody, origAll := mailReplyBody, mailReplyAll
+	mailReplyBody, mailReplyAll = "Yes.", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailReplyBody, mailReplyAll = origBody, origAll
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	addQuoteFlags(cmd)
+	return repo, cmd
+}
+
+func TestRunMailReply_QuoteFromConfig(t *testing.T) {
+	repo, cmd := setupMailQuoteTest(t, map[string]string{"mail.reply_quote": "top"})
+
+	if err := runMailReply(cmd, []string{"original-id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Yes.\n\nOn Mon, Mar 2, 2026 at 9:05 AM, Ana <ana@example.com> wrote:\n> Thursday?\n"
+	if len(repo.Replies) != 1 || repo.Replies[0].Body != want {
+		t.Errorf("reply body = %q, want %q", repo.Replies[0].Body, want)
+	}
+}
+
+func TestRunMailReply_QuoteFlags(t *testing.T) {
+	repo, cmd := setupMailQuoteTest(t, map[string]string{"mail.reply_quote": "top", "mail.quote_prefix": "| "})
+	if err := cmd.ParseFlags([]string{"--quote", "bottom"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMailReply(cmd, []string{"original-id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "On Mon, Mar 2, 2026 at 9:05 AM, Ana <ana@example.com> wrote:\n| Thursday?\n\nYes.\n"
+	if len(repo.Replies) != 1 || repo.Replies[0].Body != want {
+		t.Errorf("reply body = %q, want %q", repo.Replies[0].Body, want)
+	}
+
+	repo, cmd = setupMailQuoteTest(t, map[string]string{"mail.reply_quote": "top"})
+	if err := cmd.ParseFlags([]string{"--quote", "none"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMailReply(cmd, []string{"original-id"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Replies[0].Body != "Yes." {
+		t.Errorf("reply body = %q with --quote none", repo.Replies[0].Body)
+	}
+}
+
+func TestRunMailReply_QuoteInvalid(t *testing.T) {
+	repo, cmd := setupMailQuoteTest(t, nil)
+	if err := cmd.ParseFlags([]string{"--quote", "inline"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runMailReply(cmd, []string{"original-id"}); err == nil || !contains(err.Error(), "invalid quote style") {
+		t.Errorf("expected an invalid quote style error, got %v", err)
+	}
+	if len(repo.Replies) != 0 {
+		t.Error("expected nothing to be sent")
+	}
+}
@@ -0,0 +1,260 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Command flags for mail readlater command.
+var (
+	mailReadLaterDest string
+	mailReadLaterKeep bool
+)
+
+// mailReadLaterCmd saves messages as Markdown notes and archives them.
+var mailReadLaterCmd = &cobra.Command{
+	Use:   "readlater <message-id>...",
+	Short: "Save messages as Markdown notes and archive them",
+	Long: `Save messages as Markdown notes for plain-text note systems such as
+Obsidian, then archive them.
+
+Each message is written to its own file in --dest (default:
+mail.readlater_dir), named after its date and subject. The note starts
+with YAML front matter holding the subject, sender, recipients, date,
+label names, attachment names and a Gmail permalink, followed by the
+message body. HTML-only messages are converted to text. Existing files
+are never overwritten; a numeric suffix is added instead.
+
+Messages are archived once their note is written; use --keep to leave
+them in the inbox.`,
+	Example: `  # Save a newsletter to an Obsidian inbox and archive it
+  goog mail readlater 18c1234abcd --dest ~/notes/inbox
+
+  # Use a default directory
+  goog config set mail.readlater_dir ~/notes/inbox
+  goog mail readlater 18c1234abcd 18c5678efgh
+
+  # Save without archiving
+  goog mail readlater 18c1234abcd --keep`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailReadLater,
+}
+
+func init() {
+	mailCmd.AddCommand(mailReadLaterCmd)
+
+	mailReadLaterCmd.Flags().StringVar(&mailReadLaterDest, "dest", "", "directory to save notes to (default: mail.readlater_dir)")
+	mailReadLaterCmd.Flags().BoolVar(&mailReadLaterKeep, "keep", false, "do not archive saved messages")
+}
+
+// readLaterFrontMatter is the YAML front matter of a read-later note.
+type readLaterFrontMatter struct {
+	Title       string   `yaml:"title"`
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to,omitempty"`
+	Cc          []string `yaml:"cc,omitempty"`
+	Date        string   `yaml:"date,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	Attachments []string `yaml:"attachments,omitempty"`
+	MessageID   string   `yaml:"message_id"`
+	ThreadID    string   `yaml:"thread_id,omitempty"`
+	Permalink   string   `yaml:"permalink"`
+}
+
+// readLaterResult is the outcome of saving one message.
+type readLaterResult struct {
+	ID       string `json:"id"`
+	File     string `json:"file"`
+	Archived bool   `json:"archived"`
+}
+
+// runMailReadLater handles the mail readlater command.
+func runMailReadLater(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	dest := mailReadLaterDest
+	if dest == "" {
+		if cfg, err := config.Load(); err == nil {
+			dest = cfg.Mail.ReadLaterDir
+		}
+	}
+	if dest == "" {
+		return fmt.Errorf("no destination directory (use --dest or set mail.readlater_dir)")
+	}
+	dest = expandHome(dest)
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+
+	repo, email, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	names, err := labelNamesByID(ctx, labelRepo)
+	if err != nil {
+		return err
+	}
+
+	results := make([]readLaterResult, 0, len(args))
+	for _, id := range args {
+		msg, err := repo.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get message %s: %w", id, err)
+		}
+
+		note, err := renderReadLaterNote(msg, names, email)
+		if err != nil {
+			return err
+		}
+		path, err := writeNewFile(dest, readLaterFilename(msg), []byte(note))
+		if err != nil {
+			return err
+		}
+
+		result := readLaterResult{ID: id, File: path}
+		if !mailReadLaterKeep {
+			if err := repo.Archive(ctx, id); err != nil {
+				return fmt.Errorf("saved message %s to %s but failed to archive it: %w", id, path, err)
+			}
+			result.Archived = true
+		}
+		results = append(results, result)
+
+		if !quietFlag && formatFlag != presenter.FormatJSON {
+			if result.Archived {
+				cmd.Printf("Saved %s to %s and archived it.\n", id, path)
+			} else {
+				cmd.Printf("Saved %s to %s.\n", id, path)
+			}
+		}
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		cmd.Println(string(data))
+	}
+	return nil
+}
+
+// renderReadLaterNote renders msg as a Markdown note with YAML front
+// matter. names maps label IDs to names; account is the mailbox the
+// permalink opens.
+func renderReadLaterNote(msg *mail.Message, names map[string]string, account string) (string, error) {
+	subject := msg.Subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+
+	front := readLaterFrontMatter{
+		Title:     subject,
+		From:      msg.From.String(),
+		To:        mail.AddressStrings(msg.To),
+		Cc:        mail.AddressStrings(msg.Cc),
+		MessageID: msg.ID,
+		ThreadID:  msg.ThreadID,
+		Permalink: gmailPermalink(account, msg.ID),
+	}
+	if !msg.Date.IsZero() {
+		front.Date = msg.Date.Format(time.RFC3339)
+	}
+	for _, id := range msg.Labels {
+		if name, ok := names[id]; ok {
+			front.Labels = append(front.Labels, name)
+		} else {
+			front.Labels = append(front.Labels, id)
+		}
+	}
+	for _, att := range msg.Attachments {
+		if att != nil && att.Filename != "" {
+			front.Attachments = append(front.Attachments, att.Filename)
+		}
+	}
+
+	data, err := yaml.Marshal(front)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode front matter: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.Write(data)
+	sb.WriteString("---\n\n")
+	fmt.Fprintf(&sb, "# %s\n", subject)
+	if body := strings.TrimSpace(strings.ReplaceAll(exportBody(msg), "\r\n", "\n")); body != "" {
+		sb.WriteString("\n" + body + "\n")
+	}
+	return sb.String(), nil
+}
+
+// gmailPermalink returns the Gmail web address of a message in account.
+func gmailPermalink(account, messageID string) string {
+	user := "0"
+	if account != "" {
+		user = url.PathEscape(account)
+	}
+	return fmt.Sprintf("https://mail.google.com/mail/u/%s/#all/%s", user, messageID)
+}
+
+// readLaterFilename returns the note file name for msg: its date and
+// subject.
+func readLaterFilename(msg *mail.Message) string {
+	name := sanitizeFilename(strings.Join(strings.Fields(msg.Subject), " "))
+	if name == "" {
+		name = "no subject"
+	}
+	if runes := []rune(name); len(runes) > 100 {
+		name = strings.TrimSpace(string(runes[:100]))
+	}
+	if !msg.Date.IsZero() {
+		name = msg.Date.Format("2006-01-02") + " " + name
+	}
+	return name + ".md"
+}
+
+// writeNewFile writes data to name in dir without replacing an existing
+// file, adding a numeric suffix to the name if needed, and returns the
+// path written.
+func writeNewFile(dir, name string, data []byte) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			name = fmt.Sprintf("%s-%d%s", base, i, ext)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			_ = f.Close()
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := f.Close(); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return path, nil
+	}
+}
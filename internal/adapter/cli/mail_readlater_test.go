@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+	"gopkg.in/yaml.v3"
+)
+
+// archiveRecordingRepository records archived message IDs.
+type archiveRecordingRepository struct {
+	MockMessageRepository
+	Archived []string
+}
+
+func (m *archiveRecordingRepository) Archive(ctx context.Context, id string) error {
+	if m.ArchiveErr != nil {
+		return m.ArchiveErr
+	}
+	m.Archived = append(m.Archived, id)
+	return nil
+}
+
+// setupReadLaterTest injects a repository holding msg and resets the
+// readlater flags.
+func setupReadLaterTest(t *testing.T, msg *mail.Message) *archiveRecordingRepository {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	repo := &archiveRecordingRepository{}
+	repo.Message = msg
+	labelRepo := &MockLabelRepository{Labels: []*mail.Label{
+		mail.NewSystemLabel("INBOX", "INBOX"),
+		mail.NewLabel("Label_3", "Newsletters/Tech"),
+	}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, LabelRepo: labelRepo},
+	})
+
+	origDest, origKeep, origFormat, origQuiet := mailReadLaterDest, mailReadLaterKeep, formatFlag, quietFlag
+	mailReadLaterDest, mailReadLaterKeep, formatFlag, quietFlag = t.TempDir(), false, "table", false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailReadLaterDest, mailReadLaterKeep, formatFlag, quietFlag = origDest, origKeep, origFormat, origQuiet
+	})
+	return repo
+}
+
+func TestRunMailReadLater(t *testing.T) {
+	msg := &mail.Message{
+		ID:          "18c1",
+		ThreadID:    "18c0",
+		From:        mail.Address{Name: "Weekly", Email: "news@example.com"},
+		To:          []mail.Address{{Email: "me@example.com"}},
+		Subject:     "Issue #42: Go: what's new?",
+		BodyHTML:    "<p>Hello &amp; welcome</p><p>Second</p>",
+		Labels:      []string{"INBOX", "Label_3"},
+		Date:        time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC),
+		Attachments: []*mail.Attachment{{Filename: "slides.pdf"}},
+	}
+	repo := setupReadLaterTest(t, msg)
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runMailReadLater(cmd, []string{"18c1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(mailReadLaterDest, "2026-10-01 Issue #42_ Go_ what's new_.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected note at %s: %v\n%s", path, err, buf.String())
+	}
+	if !slices.Equal(repo.Archived, []string{"18c1"}) {
+		t.Errorf("archived = %v, want [18c1]", repo.Archived)
+	}
+	if !contains(buf.String(), "and archived it.") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	front, body, ok := strings.Cut(strings.TrimPrefix(string(data), "---\n"), "---\n")
+	if !ok {
+		t.Fatalf("note has no front matter:\n%s", data)
+	}
+	var fm readLaterFrontMatter
+	if err := yaml.Unmarshal([]byte(front), &fm); err != nil {
+		t.Fatalf("invalid front matter: %v\n%s", err, front)
+	}
+	if fm.Title != msg.Subject || fm.From != msg.From.String() || fm.Date != "2026-10-01T08:30:00Z" ||
+		!slices.Equal(fm.Labels, []string{"INBOX", "Newsletters/Tech"}) || !slices.Equal(fm.Attachments, []string{"slides.pdf"}) ||
+		fm.Permalink != "https://mail.google.com/mail/u/me@example.com/#all/18c1" {
+		t.Errorf("unexpected front matter: %+v", fm)
+	}
+	if want := "\n# Issue #42: Go: what's new?\n\nHello & welcome\nSecond\n"; body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+
+	// A second save keeps the first note
+	mailReadLaterKeep = true
+	formatFlag = "json"
+	buf.Reset()
+	if err := runMailReadLater(cmd, []string{"18c1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var results []readLaterResult
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(results) != 1 || results[0].Archived || filepath.Base(results[0].File) != "2026-10-01 Issue #42_ Go_ what's new_-1.md" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(repo.Archived) != 1 {
+		t.Errorf("expected --keep not to archive, got %v", repo.Archived)
+	}
+}
+
+func TestRunMailReadLater_NoDestination(t *testing.T) {
+	setupReadLaterTest(t, &mail.Message{ID: "18c1"})
+	mailReadLaterDest = ""
+
+	err := runMailReadLater(&cobra.Command{Use: "test"}, []string{"18c1"})
+	if err == nil || !contains(err.Error(), "mail.readlater_dir") {
+		t.Errorf("expected a missing destination error, got %v", err)
+	}
+}
+
+func TestReadLaterFilename(t *testing.T) {
+	if got := readLaterFilename(&mail.Message{}); got != "no subject.md" {
+		t.Errorf("readLaterFilename() = %q", got)
+	}
+	long := &mail.Message{Subject: strings.Repeat("ü", 150)}
+	if got := readLaterFilename(long); got != strings.Repeat("ü", 100)+".md" {
+		t.Errorf("expected the subject to be cut at 100 characters, got %d", len([]rune(got)))
+	}
+}
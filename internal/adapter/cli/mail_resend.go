@@ -0,0 +1,195 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Command flags for mail resend command.
+var (
+	mailResendTo  []string
+	mailResendCc  []string
+	mailResendBcc []string
+)
+
+// resendDroppedHeaders are removed from the original message before it is
+// re-submitted. Recipients and Date are replaced; the rest are trace,
+// authentication and identity headers that belong to the original delivery.
+var resendDroppedHeaders = map[string]bool{
+	"to":                         true,
+	"cc":                         true,
+	"bcc":                        true,
+	"date":                       true,
+	"message-id":                 true,
+	"received":                   true,
+	"return-path":                true,
+	"delivered-to":               true,
+	"dkim-signature":             true,
+	"arc-seal":                   true,
+	"arc-message-signature":      true,
+	"arc-authentication-results": true,
+	"authentication-results":     true,
+	"received-spf":               true,
+	"x-received":                 true,
+	"x-google-smtp-source":       true,
+	"x-gm-message-state":         true,
+}
+
+// mailResendCmd re-submits a previously sent message to new recipients.
+var mailResendCmd = &cobra.Command{
+	Use:   "resend <message-id>",
+	Short: "Resend a message to new recipients",
+	Long: `Resend a previously sent message to corrected recipients.
+
+The original message is fetched in raw form and re-submitted unchanged,
+preserving its subject, body, formatting and attachments. Only the
+recipient headers, Date and Message-ID are replaced. Use this after a
+typo in an address or a bounce.
+
+Pass the ID of the original message, not the bounce notification.
+Use 'goog mail bounces' to find recipients that failed.`,
+	Example: `  # Resend to a corrected address
+  goog mail resend 18c1234abcd --to corrected@example.com
+
+  # Resend to several recipients with a CC
+  goog mail resend 18c1234abcd --to a@example.com,b@example.com --cc lead@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailResend,
+}
+
+func init() {
+	mailCmd.AddCommand(mailResendCmd)
+
+	mailResendCmd.Flags().StringSliceVar(&mailResendTo, "to", nil, "recipient email address(es) (required)")
+	mailResendCmd.Flags().StringSliceVar(&mailResendCc, "cc", nil, "CC recipient email address(es)")
+	mailResendCmd.Flags().StringSliceVar(&mailResendBcc, "bcc", nil, "BCC recipient email address(es)")
+	_ = mailResendCmd.MarkFlagRequired("to")
+	addVerifyFlags(mailResendCmd)
+	addThrottleFlag(mailResendCmd)
+	addSuppressFlag(mailResendCmd)
+	addAutoBccFlag(mailResendCmd)
+}
+
+// runMailResend handles the mail resend command.
+func runMailResend(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	messageID := args[0]
+
+	if len(mailResendTo) == 0 {
+		return fmt.Errorf("at least one recipient is required (--to)")
+	}
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept, err := dropSuppressed(cmd, mailResendTo, mailResendCc, mailResendBcc)
+	if err != nil {
+		return err
+	}
+	to, cc, bcc := kept[0], kept[1], kept[2]
+
+	if err := verifyRecipients(ctx, cmd, senderEmail, to, cc, bcc); err != nil {
+		return err
+	}
+	if err := checkThrottle(cmd, to, cc, bcc); err != nil {
+		return err
+	}
+
+	original, err := repo.Get(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	if original != nil && original.IsBounce() {
+		return fmt.Errorf("message %s is a bounce notification; pass the ID of the original message instead", messageID)
+	}
+
+	raw, err := repo.GetRaw(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get raw message: %w", err)
+	}
+
+	autoBcc := mail.MissingAddresses(autoBccAddresses(cmd), to, cc, bcc)
+	resent, err := rewriteResendHeaders(raw, to, cc, append(slices.Clip(bcc), autoBcc...), time.Now())
+	if err != nil {
+		return err
+	}
+
+	sent, err := repo.SendRaw(ctx, resent)
+	if err != nil {
+		return fmt.Errorf("failed to resend message: %w", err)
+	}
+	recordRecipients(to, cc, bcc)
+
+	cmd.Printf("Message resent successfully.\n")
+	cmd.Printf("Message ID: %s\n", sent.ID)
+	cmd.Printf("To: %s\n", strings.Join(to, ", "))
+
+	return nil
+}
+
+// rewriteResendHeaders replaces the recipient and Date headers of a raw
+// RFC 2822 message and drops headers tied to the original delivery. The
+// message body is left byte-for-byte unchanged.
+func rewriteResendHeaders(raw []byte, to, cc, bcc []string, date time.Time) ([]byte, error) {
+	newline := []byte("\r\n")
+	sep := bytes.Index(raw, []byte("\r\n\r\n"))
+	if sep < 0 {
+		newline = []byte("\n")
+		sep = bytes.Index(raw, []byte("\n\n"))
+	}
+	if sep < 0 {
+		return nil, fmt.Errorf("message has no header section")
+	}
+	header, body := raw[:sep], raw[sep+2*len(newline):]
+
+	var out bytes.Buffer
+	writeHeader := func(name, value string) {
+		out.WriteString(name + ": " + value)
+		out.Write(newline)
+	}
+
+	writeHeader("To", mail.EncodeAddressList(to))
+	if len(cc) > 0 {
+		writeHeader("Cc", mail.EncodeAddressList(cc))
+	}
+	if len(bcc) > 0 {
+		writeHeader("Bcc", mail.EncodeAddressList(bcc))
+	}
+	writeHeader("Date", date.Format(time.RFC1123Z))
+
+	// Copy the remaining headers, including their folded continuation lines
+	skipping := false
+	for _, line := range bytes.Split(header, newline) {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			if !skipping {
+				out.Write(line)
+				out.Write(newline)
+			}
+			continue
+		}
+		name, _, found := bytes.Cut(line, []byte(":"))
+		if !found {
+			skipping = false
+			continue
+		}
+		skipping = resendDroppedHeaders[strings.ToLower(strings.TrimSpace(string(name)))]
+		if !skipping {
+			out.Write(line)
+			out.Write(newline)
+		}
+	}
+
+	out.Write(newline)
+	out.Write(body)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,215 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+const sampleSentRaw = "Received: from mail.example.com\r\n" +
+	"    by smtp.gmail.com with ESMTPSA\r\n" +
+	"DKIM-Signature: v=1; a=rsa-sha256;\r\n" +
+	"\tb=abc123\r\n" +
+	"From: Me <me@example.com>\r\n" +
+	"To: typo@exmaple.com\r\n" +
+	"Cc: team@example.com\r\n" +
+	"Subject: Quarterly report\r\n" +
+	"Date: Mon, 1 Apr 2024 10:00:00 +0000\r\n" +
+	"Message-ID: <orig@mail.example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"See attached.\r\n" +
+	"--b1--\r\n"
+
+func TestMailResendCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "resend", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--to", "--cc", "--bcc", "<message-id>"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRewriteResendHeaders(t *testing.T) {
+	date := time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC)
+
+	got, err := rewriteResendHeaders([]byte(sampleSentRaw), []string{"fixed@example.com"}, nil, []string{"audit@example.com"}, date)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(got)
+
+	for _, want := range []string{
+		"To: fixed@example.com\r\n",
+		"Bcc: audit@example.com\r\n",
+		"Date: Tue, 02 Apr 2024 09:00:00 +0000\r\n",
+		"From: Me <me@example.com>\r\n",
+		"Subject: Quarterly report\r\n",
+		"Content-Type: multipart/mixed; boundary=\"b1\"\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	for _, unwanted := range []string{"typo@exmaple.com", "Cc: team@example.com", "Message-ID", "Received", "by smtp.gmail.com", "DKIM", "b=abc123", "Mon, 1 Apr 2024"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected output not to contain %q, got:\n%s", unwanted, out)
+		}
+	}
+
+	_, body, _ := strings.Cut(sampleSentRaw, "\r\n\r\n")
+	if !strings.HasSuffix(out, "\r\n\r\n"+body) {
+		t.Errorf("expected body to be preserved unchanged, got:\n%s", out)
+	}
+}
+
+func TestRewriteResendHeaders_LFOnly(t *testing.T) {
+	raw := "From: me@example.com\nTo: old@example.com\nSubject: Hi\n\nBody\n"
+
+	got, err := rewriteResendHeaders([]byte(raw), []string{"new@example.com"}, []string{"cc@example.com"}, nil, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := string(got)
+
+	if !strings.HasPrefix(out, "To: new@example.com\nCc: cc@example.com\n") {
+		t.Errorf("unexpected headers:\n%s", out)
+	}
+	if strings.Contains(out, "\r\n") || strings.Contains(out, "old@example.com") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "Subject: Hi\n\nBody\n") {
+		t.Errorf("expected body to be preserved, got:\n%s", out)
+	}
+}
+
+func TestRewriteResendHeaders_NoHeaderSection(t *testing.T) {
+	if _, err := rewriteResendHeaders([]byte("just text"), []string{"a@example.com"}, nil, nil, time.Now()); err == nil {
+		t.Error("expected error for message without header section")
+	}
+}
+
+// setupMailResendTest injects a message repository and resets resend flags.
+func setupMailResendTest(t *testing.T, repo *MockMessageRepository) *bytes.Buffer {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origTo, origCc, origBcc := mailResendTo, mailResendCc, mailResendBcc
+	mailResendTo = []string{"fixed@example.com"}
+	mailResendCc = nil
+	mailResendBcc = nil
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailResendTo, mailResendCc, mailResendBcc = origTo, origCc, origBcc
+	})
+
+	return new(bytes.Buffer)
+}
+
+func TestRunMailResend(t *testing.T) {
+	repo := &MockMessageRepository{
+		Message: mail.NewMessage("orig1", "t1", "me@example.com", "Quarterly report", ""),
+		Raw:     []byte(sampleSentRaw),
+	}
+	buf := setupMailResendTest(t, repo)
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runMailResend(cmd, []string{"orig1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(repo.SentRaw), "To: fixed@example.com") {
+		t.Errorf("expected resent message to use new recipient, got:\n%s", repo.SentRaw)
+	}
+	if !containsStr(buf.String(), "Message resent successfully") || !containsStr(buf.String(), "sent-raw") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRunMailResend_Errors(t *testing.T) {
+	t.Run("bounce message", func(t *testing.T) {
+		bounce := mail.NewMessage("b1", "t1", "mailer-daemon@googlemail.com", "Delivery Status Notification (Failure)", "")
+		bounce.Report = &mail.DeliveryReport{
+			Type:       mail.ReportDeliveryStatus,
+			Recipients: []*mail.RecipientStatus{{Recipient: "typo@exmaple.com", Action: mail.ActionFailed, Status: "5.1.2"}},
+		}
+		repo := &MockMessageRepository{Message: bounce, Raw: []byte(sampleSentRaw)}
+		setupMailResendTest(t, repo)
+
+		err := runMailResend(&cobra.Command{Use: "test"}, []string{"b1"})
+		if err == nil || !containsStr(err.Error(), "bounce notification") {
+			t.Errorf("expected bounce error, got %v", err)
+		}
+		if repo.SentRaw != nil {
+			t.Error("expected nothing to be sent")
+		}
+	})
+
+	t.Run("no recipients", func(t *testing.T) {
+		setupMailResendTest(t, &MockMessageRepository{})
+		mailResendTo = nil
+
+		err := runMailResend(&cobra.Command{Use: "test"}, []string{"orig1"})
+		if err == nil || !containsStr(err.Error(), "--to") {
+			t.Errorf("expected recipient error, got %v", err)
+		}
+	})
+
+	t.Run("get raw error", func(t *testing.T) {
+		setupMailResendTest(t, &MockMessageRepository{
+			Message:   mail.NewMessage("orig1", "t1", "me@example.com", "Hi", ""),
+			GetRawErr: errors.New("boom"),
+		})
+
+		err := runMailResend(&cobra.Command{Use: "test"}, []string{"orig1"})
+		if err == nil || !containsStr(err.Error(), "failed to get raw message") {
+			t.Errorf("expected raw error, got %v", err)
+		}
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		setupMailResendTest(t, &MockMessageRepository{
+			Message:    mail.NewMessage("orig1", "t1", "me@example.com", "Hi", ""),
+			Raw:        []byte(sampleSentRaw),
+			SendRawErr: errors.New("boom"),
+		})
+
+		err := runMailResend(&cobra.Command{Use: "test"}, []string{"orig1"})
+		if err == nil || !containsStr(err.Error(), "failed to resend message") {
+			t.Errorf("expected send error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/searchcache"
+)
+
+// mailSearchNoCache makes mail search ignore cached results.
+var mailSearchNoCache bool
+
+// searchCacheTTL returns mail.search_cache_ttl, or zero when the search
+// cache is off.
+func searchCacheTTL() time.Duration {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return 0
+	}
+	cfg, err := config.Load()
+	if err != nil || cfg.Mail.SearchCacheTTL == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(cfg.Mail.SearchCacheTTL)
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// searchCacheKey identifies a search by its query and the flags that
+// change which messages are fetched.
+func searchCacheKey(query string) string {
+	return fmt.Sprintf("%s\x00max=%d limit=%d threaded=%t filters=%v labels=%v spam_trash=%t",
+		query, mailSearchMaxResults, mailSearchLimit, mailThreaded, listOptions.Filters, mailSearchLabels, mailIncludeSpamTrash)
+}
+
+// cachedSearchEach runs a search like repo.SearchEach, through the search
+// cache. A search cached less than mail.search_cache_ttl ago is replayed
+// from the cache, fetching again only the messages relabeled since; other
+// searches are run and cached, unless some messages could not be fetched
+// in full. The cache is not used with --no-cache or
+// --all, when mail.search_cache_ttl is unset, or when repo cannot report
+// mailbox history. Like repo.SearchEach, it returns the estimated total
+// and the warnings about messages passed with partial data.
+func cachedSearchEach(ctx context.Context, cmd *cobra.Command, repo MessageRepository, account, query string, opts mail.ListOptions, fn func(*mail.Message) error) (int, []string, error) {
+	ttl := searchCacheTTL()
+	history, ok := repo.(MailboxHistory)
+	if ttl <= 0 || !ok || mailSearchAll {
+		return repo.SearchEach(ctx, query, opts, fn)
+	}
+
+	path, key := searchcache.Path(), searchCacheKey(query)
+	if !mailSearchNoCache {
+		entry, err := searchcache.Lookup(path, account, key, ttl, time.Now())
+		if err != nil {
+			output.Verbosef(cmd, "Warning: %v", err)
+		}
+		if entry != nil {
+			if total, ok, err := replaySearch(ctx, cmd, repo, history, entry, fn); ok {
+				return total, nil, err
+			}
+		}
+	}
+
+	// The history ID is taken first, so changes made while searching show
+	// up next time
+	historyID, err := history.HistoryID(ctx)
+	if err != nil {
+		output.Verbosef(cmd, "Warning: search results not cached: %v", err)
+		return repo.SearchEach(ctx, query, opts, fn)
+	}
+	var msgs []*mail.Message
+	total, warnings, err := repo.SearchEach(ctx, query, opts, func(msg *mail.Message) error {
+		msgs = append(msgs, msg)
+		return fn(msg)
+	})
+	if err != nil {
+		return total, warnings, err
+	}
+	if len(warnings) > 0 {
+		// Partial messages would be replayed as complete until the TTL ends
+		output.Verbosef(cmd, "Search results not cached: %d message(s) could not be fetched in full", len(warnings))
+		return total, warnings, nil
+	}
+
+	entry := searchcache.Entry{
+		Account:   account,
+		Key:       key,
+		HistoryID: historyID,
+		CachedAt:  time.Now(),
+		Total:     total,
+		Messages:  msgs,
+	}
+	if err := searchcache.Store(path, entry, ttl); err != nil {
+		output.Verbosef(cmd, "Warning: search results not cached: %v", err)
+	}
+	return total, warnings, nil
+}
+
+// replaySearch calls fn with the messages of a cached search, leaving out
+// those deleted since and fetching again those relabeled since. It
+// reports ok=false, without calling fn, when the cache cannot be used:
+// the mailbox history is no longer available, or messages were added,
+// which may match the search.
+func replaySearch(ctx context.Context, cmd *cobra.Command, repo MessageRepository, history MailboxHistory, entry *searchcache.Entry, fn func(*mail.Message) error) (total int, ok bool, err error) {
+	changes, err := history.Changes(ctx, entry.HistoryID)
+	if err != nil {
+		output.Verbosef(cmd, "Cached search results not used: %v", err)
+		return 0, false, nil
+	}
+	if len(changes.Added) > 0 {
+		output.Verbosef(cmd, "Cached search results not used: %d message(s) added since", len(changes.Added))
+		return 0, false, nil
+	}
+
+	deleted := make(map[string]bool, len(changes.Deleted))
+	for _, id := range changes.Deleted {
+		deleted[id] = true
+	}
+	relabeled := make(map[string]bool, len(changes.Relabeled))
+	for _, id := range changes.Relabeled {
+		relabeled[id] = true
+	}
+	output.Verbosef(cmd, "Using search results cached %s ago", time.Since(entry.CachedAt).Round(time.Second))
+
+	for _, msg := range entry.Messages {
+		if deleted[msg.ID] {
+			continue
+		}
+		if relabeled[msg.ID] {
+			fresh, err := repo.Get(ctx, msg.ID)
+			if errors.Is(err, mail.ErrMessageNotFound) {
+				continue
+			}
+			if err != nil {
+				return entry.Total, true, err
+			}
+			msg = fresh
+		}
+		if err := fn(msg); err != nil {
+			if errors.Is(err, mail.ErrStopSearch) {
+				return entry.Total, true, nil
+			}
+			return entry.Total, true, err
+		}
+	}
+	return entry.Total, true, nil
+}
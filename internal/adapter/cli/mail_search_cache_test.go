@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// historyMessageRepository adds mailbox history to a mock message
+// repository.
+type historyMessageRepository struct {
+	*MockMessageRepository
+	historyID uint64
+	changes   *mail.MailboxChanges
+	since     uint64
+	got       []string
+}
+
+func (r *historyMessageRepository) HistoryID(ctx context.Context) (uint64, error) {
+	return r.historyID, nil
+}
+
+func (r *historyMessageRepository) Changes(ctx context.Context, since uint64) (*mail.MailboxChanges, error) {
+	r.since = since
+	return r.changes, nil
+}
+
+func (r *historyMessageRepository) Get(ctx context.Context, id string) (*mail.Message, error) {
+	r.got = append(r.got, id)
+	return &mail.Message{ID: id, Subject: "Relabeled " + id, From: mail.Address{Email: "sender@example.com"}}, nil
+}
+
+// setupSearchCacheTest wraps the mail search mock with mailbox history and
+// turns the search cache on.
+func setupSearchCacheTest(t *testing.T) *historyMessageRepository {
+	t.Helper()
+	mock := setupMailSearchTest(t, 5)
+	repo := &historyMessageRepository{MockMessageRepository: mock, historyID: 500, changes: &mail.MailboxChanges{HistoryID: 500}}
+	deps := GetDependencies()
+	deps.RepoFactory = &MockRepositoryFactory{MessageRepo: repo}
+	SetDependencies(deps)
+
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Mail.SearchCacheTTL = "5m"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	origNoCache := mailSearchNoCache
+	mailSearchNoCache = false
+	t.Cleanup(func() { mailSearchNoCache = origNoCache })
+	return repo
+}
+
+// runSearch runs mail search and returns its output.
+func runSearch(t *testing.T) string {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRunMailSearch_CachedResults(t *testing.T) {
+	repo := setupSearchCacheTest(t)
+
+	runSearch(t)
+	if repo.SearchEachFetched != 5 {
+		t.Fatalf("expected the first search to fetch 5 messages, got %d", repo.SearchEachFetched)
+	}
+
+	repo.changes = &mail.MailboxChanges{HistoryID: 510, Deleted: []string{"msg2"}, Relabeled: []string{"msg4"}}
+	out := runSearch(t)
+	if repo.SearchEachFetched != 5 {
+		t.Errorf("expected the second search to use the cache, fetched %d more", repo.SearchEachFetched-5)
+	}
+	if repo.since != 500 {
+		t.Errorf("changes asked since %d, want the cached history ID 500", repo.since)
+	}
+	if len(repo.got) != 1 || repo.got[0] != "msg4" {
+		t.Errorf("fetched %v again, want only the relabeled msg4", repo.got)
+	}
+	if contains(out, "msg2") || !contains(out, "Relabeled msg4") || !contains(out, "msg5") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	// New mail may match, so the search runs again
+	repo.changes = &mail.MailboxChanges{HistoryID: 520, Added: []string{"msg9"}}
+	runSearch(t)
+	if repo.SearchEachFetched != 10 {
+		t.Errorf("expected new mail to skip the cache, fetched %d in all", repo.SearchEachFetched)
+	}
+
+	mailSearchNoCache = true
+	repo.changes = &mail.MailboxChanges{HistoryID: 500}
+	runSearch(t)
+	if repo.SearchEachFetched != 15 {
+		t.Errorf("expected --no-cache to run the search, fetched %d in all", repo.SearchEachFetched)
+	}
+}
+
+func TestRunMailSearch_CacheKeyedByOptions(t *testing.T) {
+	repo := setupSearchCacheTest(t)
+
+	runSearch(t)
+	mailSearchMaxResults = 3
+	out := runSearch(t)
+	if repo.SearchEachFetched != 8 {
+		t.Errorf("expected a different --max-results to run the search, fetched %d in all", repo.SearchEachFetched)
+	}
+	if contains(out, "msg4") {
+		t.Errorf("expected 3 messages, got:\n%s", out)
+	}
+}
+
+func TestRunMailSearch_IncompleteNotCached(t *testing.T) {
+	repo := setupSearchCacheTest(t)
+	repo.SearchResult.Items[1] = &mail.Message{ID: "msg2"}
+	repo.SearchResult.Warnings = []string{"message msg2: gmail error: backend error"}
+
+	runSearch(t)
+	repo.SearchResult.Warnings = nil
+	runSearch(t)
+	if repo.SearchEachFetched != 10 {
+		t.Errorf("expected the incomplete search not to be cached, fetched %d in all", repo.SearchEachFetched)
+	}
+	runSearch(t)
+	if repo.SearchEachFetched != 10 {
+		t.Errorf("expected the complete search to be cached, fetched %d in all", repo.SearchEachFetched)
+	}
+}
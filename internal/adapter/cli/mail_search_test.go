@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupMailSearchTest serves n messages, msg1 to msgN, from a mock
+// repository and resets the search flags afterwards.
+func setupMailSearchTest(t *testing.T, n int) *MockMessageRepository {
+	t.Helper()
+	msgs := make([]*mail.Message, n)
+	for i := range msgs {
+		msgs[i] = &mail.Message{ID: fmt.Sprintf("msg%d", i+1), Subject: fmt.Sprintf("Subject %d", i+1), From: mail.Address{Email: "sender@example.com"}}
+	}
+	repo := &MockMessageRepository{SearchResult: &mail.ListResult[*mail.Message]{Items: msgs, Total: 1000}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origFormat, origQuiet, origOpts := formatFlag, quietFlag, listOptions
+	origMax, origLimit, origAll, origThreaded := mailSearchMaxResults, mailSearchLimit, mailSearchAll, mailThreaded
+	origStrict := mailSearchStrict
+	t.Cleanup(func() {
+		ResetDependencies()
+		formatFlag, quietFlag, listOptions = origFormat, origQuiet, origOpts
+		mailSearchMaxResults, mailSearchLimit, mailSearchAll, mailThreaded = origMax, origLimit, origAll, origThreaded
+		mailSearchStrict = origStrict
+	})
+	formatFlag, quietFlag, listOptions = presenter.FormatPlain, false, presenter.ListOptions{}
+	mailSearchMaxResults, mailSearchLimit, mailSearchAll, mailThreaded = 10, 0, false, false
+	mailSearchStrict = false
+	return repo
+}
+
+func TestRunMailSearch_StopsAtMaxResults(t *testing.T) {
+	repo := setupMailSearchTest(t, 30)
+	mailSearchMaxResults = 3
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.SearchEachFetched != 3 {
+		t.Errorf("expected 3 messages fetched, got %d", repo.SearchEachFetched)
+	}
+	if !contains(buf.String(), "msg3") || contains(buf.String(), "msg4") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	if !contains(buf.String(), "showing first 3 of ~1000") {
+		t.Errorf("expected the result estimate, got:\n%s", buf.String())
+	}
+}
+
+func TestRunMailSearch_LimitCountsShownMessages(t *testing.T) {
+	repo := setupMailSearchTest(t, 30)
+	mailSearchLimit = 2
+	opts, err := parseListOptions("", false, []string{"subject~1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listOptions = opts
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Subjects 1 and 10 match, so fetching stops at the tenth message
+	if repo.SearchEachFetched != 10 {
+		t.Errorf("expected 10 messages fetched, got %d", repo.SearchEachFetched)
+	}
+	if !contains(buf.String(), "msg1\t") || !contains(buf.String(), "msg10\t") || contains(buf.String(), "msg2\t") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestRunMailSearch_Incomplete(t *testing.T) {
+	repo := setupMailSearchTest(t, 3)
+	repo.SearchResult.Items[1] = &mail.Message{ID: "msg2"}
+	repo.SearchResult.Warnings = []string{"message msg2: gmail error: backend error"}
+
+	for _, strict := range []bool{false, true} {
+		mailSearchStrict = strict
+		cmd := &cobra.Command{Use: "test"}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+
+		err := runMailSearch(cmd, []string{"in:inbox"})
+		if strict != (err != nil) {
+			t.Fatalf("strict=%v: runMailSearch error = %v", strict, err)
+		}
+		if strict && !contains(err.Error(), "incomplete results: 1 message(s)") {
+			t.Errorf("error = %v, want an incomplete results error", err)
+		}
+		if !contains(stdout.String(), "msg2") {
+			t.Errorf("strict=%v: expected the partial message in output, got: %s", strict, stdout.String())
+		}
+		if !contains(stderr.String(), "Warning: incomplete result, message msg2: gmail error: backend error") {
+			t.Errorf("strict=%v: expected a warning on stderr, got: %s", strict, stderr.String())
+		}
+	}
+}
+
+func TestRunMailSearch_JSONStillLimited(t *testing.T) {
+	setupMailSearchTest(t, 30)
+	mailSearchLimit = 4
+	formatFlag = presenter.FormatJSON
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(buf.String(), `"ID": "msg4"`) || contains(buf.String(), `"ID": "msg5"`) {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestRunMailSearch_AllJSON(t *testing.T) {
+	repo := setupMailSearchTest(t, 30)
+	mailSearchAll = true
+	formatFlag = presenter.FormatJSON
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.SearchEachFetched != 30 {
+		t.Errorf("expected every message fetched, got %d", repo.SearchEachFetched)
+	}
+	var msgs []*mail.Message
+	if err := json.Unmarshal(buf.Bytes(), &msgs); err != nil {
+		t.Fatalf("expected a JSON array alone, got %v:\n%s", err, buf.String())
+	}
+	if len(msgs) != 30 || msgs[29].ID != "msg30" {
+		t.Errorf("decoded %d messages, want all 30", len(msgs))
+	}
+}
+
+// pipeWriter accepts a number of writes and then fails like a pipe whose
+// reader has gone.
+type pipeWriter struct {
+	bytes.Buffer
+	left int
+}
+
+func (w *pipeWriter) Write(p []byte) (int, error) {
+	if w.left == 0 {
+		return 0, syscall.EPIPE
+	}
+	w.left--
+	return w.Buffer.Write(p)
+}
+
+func TestRunMailSearch_ClosedPipe(t *testing.T) {
+	repo := setupMailSearchTest(t, 30)
+	mailSearchLimit = 1000
+
+	cmd := &cobra.Command{Use: "test"}
+	out := &pipeWriter{left: 2}
+	cmd.SetOut(out)
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("expected a closed pipe to end the search quietly, got %v", err)
+	}
+	if repo.SearchEachFetched != 3 {
+		t.Errorf("expected fetching to stop at the failed write, got %d message(s)", repo.SearchEachFetched)
+	}
+}
+
+func TestRunMailSearch_HighlightOnlyInTerminal(t *testing.T) {
+	setupMailSearchTest(t, 3)
+	origHighlight := mailSearchHighlight
+	t.Cleanup(func() {
+		mailSearchHighlight = origHighlight
+		presenter.SetHighlightTerms(nil)
+	})
+	mailSearchHighlight = true
+	presenter.SetHighlightTerms([]string{"stale"})
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailSearch(cmd, []string{"subject:Subject"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Test output is not a terminal, so no colour codes are written
+	if terms := presenter.HighlightTerms(); len(terms) != 0 {
+		t.Errorf("expected highlighting off, got terms %q", terms)
+	}
+	if contains(buf.String(), "\033[") {
+		t.Errorf("unexpected escape codes in output:\n%q", buf.String())
+	}
+}
+
+func TestRunMailSearch_Columns(t *testing.T) {
+	setupMailSearchTest(t, 2)
+	origColumns := mailColumns
+	t.Cleanup(func() {
+		mailColumns = origColumns
+		_ = presenter.SetMessageColumns(nil)
+	})
+	formatFlag = presenter.FormatTable
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	mailColumns = []string{"id", "from_email"}
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(buf.String(), "FROM EMAIL") || contains(buf.String(), "SUBJECT") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	mailColumns = []string{"sender"}
+	if err := runMailSearch(cmd, []string{"in:inbox"}); err == nil || !contains(err.Error(), "unknown column") {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// showMessageStructure prints the MIME part tree of a message, for mail
+// show --structure.
+func showMessageStructure(ctx context.Context, cmd *cobra.Command, repo MessageRepository, messageID string) error {
+	raw, err := repo.GetRaw(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to get raw message: %w", err)
+	}
+	root, err := mail.ParseStructure(raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse message %s: %w", messageID, err)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode structure: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "PART\tTYPE\tENCODING\tSIZE\tDETAILS")
+	root.Walk(func(part *mail.MIMEPart, depth int) {
+		section := part.Section
+		if section == "" {
+			section = "-"
+		}
+		contentType := strings.Repeat("  ", depth) + part.ContentType
+		if part.Charset != "" {
+			contentType += "; charset=" + part.Charset
+		}
+		encoding := part.Encoding
+		if encoding == "" {
+			encoding = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", section, contentType, encoding, formatSize(int64(part.DecodedSize)), structureDetails(part))
+	})
+	return w.Flush()
+}
+
+// structureDetails describes a part's disposition, filename, content ID
+// and body role.
+func structureDetails(part *mail.MIMEPart) string {
+	var details []string
+	if part.Disposition != "" {
+		details = append(details, part.Disposition)
+	}
+	if part.Filename != "" {
+		details = append(details, fmt.Sprintf("%q", part.Filename))
+	}
+	if part.ContentID != "" {
+		details = append(details, "cid:"+part.ContentID)
+	}
+	switch part.Body {
+	case mail.BodyPlain:
+		details = append(details, "[plain body]")
+	case mail.BodyHTML:
+		details = append(details, "[HTML body]")
+	}
+	return strings.Join(details, " ")
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// structureRaw is an alternative message with an inline image.
+var structureRaw = strings.ReplaceAll(`Subject: Logo
+Content-Type: multipart/related; boundary="rel"
+
+--rel
+Content-Type: multipart/alternative; boundary="alt"
+
+--alt
+Content-Type: text/plain; charset=us-ascii
+
+See the logo.
+--alt
+Content-Type: text/html; charset=us-ascii
+Content-Transfer-Encoding: quoted-printable
+
+<img src=3D"cid:logo@example.com">
+--alt--
+--rel
+Content-Type: image/png
+Content-Disposition: inline; filename="logo.png"
+Content-Transfer-Encoding: base64
+Content-ID: <logo@example.com>
+
+iVBORw0KGgo=
+--rel--
+`, "\n", "\r\n")
+
+// setupMailStructureTest serves structureRaw and resets the read flags.
+func setupMailStructureTest(t *testing.T) {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: &MockMessageRepository{Raw: []byte(structureRaw)}},
+	})
+	origFormat, origStructure, origTranslate := formatFlag, mailReadStructure, mailReadTranslate
+	t.Cleanup(func() {
+		ResetDependencies()
+		formatFlag, mailReadStructure, mailReadTranslate = origFormat, origStructure, origTranslate
+	})
+	formatFlag, mailReadStructure, mailReadTranslate = "table", true, ""
+}
+
+func TestRunMailRead_Structure(t *testing.T) {
+	setupMailStructureTest(t)
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailRead(cmd, []string{"msg1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"multipart/related",
+		"1.1  ",
+		"text/plain; charset=us-ascii",
+		"[plain body]",
+		"quoted-printable",
+		"[HTML body]",
+		`inline "logo.png" cid:logo@example.com`,
+		"8 B",
+	} {
+		if !contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunMailRead_StructureJSON(t *testing.T) {
+	setupMailStructureTest(t)
+	formatFlag = "json"
+
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := runMailRead(cmd, []string{"msg1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var root mail.MIMEPart
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(root.Parts) != 2 || root.Parts[1].ContentID != "logo@example.com" {
+		t.Errorf("unexpected structure %+v", root)
+	}
+}
+
+func TestRunMailRead_StructureConflicts(t *testing.T) {
+	setupMailStructureTest(t)
+
+	cmd := &cobra.Command{Use: "test"}
+	if err := runMailRead(cmd, []string{"thread:1"}); err == nil || !contains(err.Error(), "--structure") {
+		t.Errorf("expected a --structure error for a thread, got %v", err)
+	}
+
+	mailReadTranslate = "en"
+	if err := runMailRead(cmd, []string{"msg1"}); err == nil || !contains(err.Error(), "--translate") {
+		t.Errorf("expected a --translate error, got %v", err)
+	}
+}
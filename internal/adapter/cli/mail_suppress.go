@@ -0,0 +1,220 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/suppressions"
+)
+
+// includeSuppressedFlag is the name of the flag that sends to suppressed
+// addresses anyway.
+const includeSuppressedFlag = "include-suppressed"
+
+// Command flags for mail suppress commands.
+var mailSuppressReason string
+
+// mailSuppressCmd manages the suppression list.
+var mailSuppressCmd = &cobra.Command{
+	Use:   "suppress",
+	Short: "Manage addresses mail is not sent to",
+	Long: `Manage the suppression list: addresses the sending commands skip.
+
+Addresses are added by hand with 'suppress add' and automatically by
+'goog mail bounces' for every permanent bounce it finds. The sending
+commands (send, reply, forward, resend and watchdir) leave suppressed
+addresses out of a message and report them; a message whose every
+recipient is suppressed is not sent. Use --include-suppressed on a
+sending command to send to them anyway.
+
+The list is kept in suppressions.json next to the config file and
+applies to every account.`,
+}
+
+// mailSuppressAddCmd adds addresses to the suppression list.
+var mailSuppressAddCmd = &cobra.Command{
+	Use:   "add <address>...",
+	Short: "Suppress addresses",
+	Example: `  # Stop mailing an address that asked to be removed
+  goog mail suppress add user@example.com --reason "asked to unsubscribe"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailSuppressAdd,
+}
+
+// mailSuppressRemoveCmd removes addresses from the suppression list.
+var mailSuppressRemoveCmd = &cobra.Command{
+	Use:     "remove <address>...",
+	Aliases: []string{"rm"},
+	Short:   "Send to suppressed addresses again",
+	Example: `  # The mailbox works again
+  goog mail suppress remove user@example.com`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailSuppressRemove,
+}
+
+// mailSuppressListCmd lists the suppression list.
+var mailSuppressListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List suppressed addresses",
+	Example: `  # Show the suppression list
+  goog mail suppress list
+
+  # As JSON
+  goog mail suppress list --format json`,
+	Args: cobra.NoArgs,
+	RunE: runMailSuppressList,
+}
+
+func init() {
+	mailCmd.AddCommand(mailSuppressCmd)
+	mailSuppressCmd.AddCommand(mailSuppressAddCmd)
+	mailSuppressCmd.AddCommand(mailSuppressRemoveCmd)
+	mailSuppressCmd.AddCommand(mailSuppressListCmd)
+
+	mailSuppressAddCmd.Flags().StringVar(&mailSuppressReason, "reason", "", "why the addresses are suppressed")
+}
+
+// suppressionItem is the JSON form of an entry in 'mail suppress list'.
+type suppressionItem struct {
+	Address string    `json:"address"`
+	Source  string    `json:"source"`
+	Reason  string    `json:"reason,omitempty"`
+	Added   time.Time `json:"added"`
+}
+
+// runMailSuppressAdd handles the mail suppress add command.
+func runMailSuppressAdd(cmd *cobra.Command, args []string) error {
+	entries := make(map[string]suppressions.Entry, len(args))
+	for _, arg := range args {
+		addr, err := mail.ValidateAddress(arg)
+		if err != nil {
+			return err
+		}
+		entries[addr] = suppressions.Entry{Source: suppressions.SourceManual, Reason: strings.TrimSpace(mailSuppressReason), Added: time.Now()}
+	}
+	added, err := suppressions.Add(suppressions.Path(), entries)
+	if err != nil {
+		return err
+	}
+	for _, addr := range added {
+		cmd.Printf("Suppressed %s\n", addr)
+	}
+	if skipped := len(entries) - len(added); skipped > 0 && !quietFlag {
+		cmd.Printf("%d address(es) already suppressed\n", skipped)
+	}
+	return nil
+}
+
+// runMailSuppressRemove handles the mail suppress remove command.
+func runMailSuppressRemove(cmd *cobra.Command, args []string) error {
+	removed, err := suppressions.Remove(suppressions.Path(), args)
+	if err != nil {
+		return err
+	}
+	for _, addr := range removed {
+		cmd.Printf("Removed %s\n", addr)
+	}
+	if len(removed) < len(args) && !quietFlag {
+		cmd.Printf("%d address(es) were not suppressed\n", len(args)-len(removed))
+	}
+	return nil
+}
+
+// runMailSuppressList handles the mail suppress list command.
+func runMailSuppressList(cmd *cobra.Command, args []string) error {
+	list, err := suppressions.Load(suppressions.Path())
+	if err != nil {
+		return err
+	}
+	items := make([]suppressionItem, 0, len(list))
+	for addr, entry := range list {
+		items = append(items, suppressionItem{Address: addr, Source: entry.Source, Reason: entry.Reason, Added: entry.Added})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Address < items[j].Address })
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode suppression list: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(items) == 0 {
+		if !quietFlag {
+			cmd.Println("No addresses are suppressed.")
+		}
+		return nil
+	}
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "ADDRESS\tSOURCE\tADDED\tREASON")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Address, item.Source, presenter.CurrentLocale().Date(item.Added.Local()), item.Reason)
+	}
+	return w.Flush()
+}
+
+// addSuppressFlag registers --include-suppressed on a sending command.
+func addSuppressFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(includeSuppressedFlag, false, "send to addresses on the suppression list too")
+}
+
+// dropSuppressed removes suppressed addresses from each recipient list,
+// unless --include-suppressed is set, and reports them on stderr. It fails
+// when no recipient is left, so nothing is sent.
+func dropSuppressed(cmd *cobra.Command, lists ...[]string) ([][]string, error) {
+	if include, _ := cmd.Flags().GetBool(includeSuppressedFlag); include {
+		return lists, nil
+	}
+	list, err := suppressions.Load(suppressions.Path())
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return lists, nil
+	}
+
+	var skipped []string
+	kept := make([][]string, len(lists))
+	remaining := 0
+	for i, recipients := range lists {
+		for _, recipient := range recipients {
+			addr := recipient
+			if parsed, err := mail.ValidateAddress(recipient); err == nil {
+				addr = parsed
+			}
+			if entry, ok := list[strings.ToLower(strings.TrimSpace(addr))]; ok {
+				skipped = append(skipped, describeSuppressed(addr, entry))
+				continue
+			}
+			kept[i] = append(kept[i], recipient)
+			remaining++
+		}
+	}
+	if len(skipped) == 0 {
+		return lists, nil
+	}
+	if remaining == 0 {
+		return nil, fmt.Errorf("every recipient is suppressed (use --%s to send anyway):\n  %s", includeSuppressedFlag, strings.Join(skipped, "\n  "))
+	}
+	cmd.PrintErrf("Skipped %d suppressed recipient(s):\n  %s\n", len(skipped), strings.Join(skipped, "\n  "))
+	return kept, nil
+}
+
+// describeSuppressed formats a suppressed address with where its entry
+// came from, e.g. "a@example.com (bounce: 5.1.1)".
+func describeSuppressed(addr string, entry suppressions.Entry) string {
+	if entry.Reason != "" {
+		return fmt.Sprintf("%s (%s: %s)", addr, entry.Source, entry.Reason)
+	}
+	return fmt.Sprintf("%s (%s)", addr, entry.Source)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/suppressions"
+)
+
+// newSuppressTestCmd returns a command with --include-suppressed parsed
+// from args, in an isolated config directory.
+func newSuppressTestCmd(t *testing.T, args ...string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cmd := &cobra.Command{Use: "test"}
+	addSuppressFlag(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	return cmd, out, errOut
+}
+
+func TestRunMailSuppress(t *testing.T) {
+	cmd, out, _ := newSuppressTestCmd(t)
+	origReason := mailSuppressReason
+	mailSuppressReason = "asked to unsubscribe"
+	t.Cleanup(func() { mailSuppressReason = origReason })
+
+	if err := runMailSuppressAdd(cmd, []string{"Ana <Ana@Example.com>", "bo@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runMailSuppressAdd(cmd, []string{"not an address"}); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+	list, err := suppressions.Load(suppressions.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := list["ana@example.com"]; got.Source != suppressions.SourceManual || got.Reason != "asked to unsubscribe" {
+		t.Errorf("entry = %+v", got)
+	}
+
+	out.Reset()
+	if err := runMailSuppressList(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(out.String(), "ana@example.com") || !contains(out.String(), "manual") {
+		t.Errorf("unexpected list output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runMailSuppressRemove(cmd, []string{"ANA@example.com", "cy@example.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(out.String(), "Removed ana@example.com") || !contains(out.String(), "1 address(es) were not suppressed") {
+		t.Errorf("unexpected remove output:\n%s", out.String())
+	}
+}
+
+func TestDropSuppressed(t *testing.T) {
+	add := func(t *testing.T) {
+		t.Helper()
+		if _, err := suppressions.Add(suppressions.Path(), map[string]suppressions.Entry{
+			"gone@example.com": {Source: suppressions.SourceBounce, Reason: "5.1.1", Added: time.Now()},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("skips and reports suppressed recipients", func(t *testing.T) {
+		cmd, _, errOut := newSuppressTestCmd(t)
+		add(t)
+		kept, err := dropSuppressed(cmd, []string{"Gone@Example.com", "ana@example.com"}, []string{"gone@example.com"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(kept[0], []string{"ana@example.com"}) || len(kept[1]) != 0 {
+			t.Errorf("kept = %v", kept)
+		}
+		if !contains(errOut.String(), "Skipped 2 suppressed recipient(s)") || !contains(errOut.String(), "(bounce: 5.1.1)") {
+			t.Errorf("unexpected warning: %q", errOut.String())
+		}
+	})
+
+	t.Run("fails when every recipient is suppressed", func(t *testing.T) {
+		cmd, _, _ := newSuppressTestCmd(t)
+		add(t)
+		if _, err := dropSuppressed(cmd, []string{"gone@example.com"}); err == nil || !contains(err.Error(), "every recipient is suppressed") {
+			t.Errorf("expected an error, got %v", err)
+		}
+	})
+
+	t.Run("include-suppressed sends anyway", func(t *testing.T) {
+		cmd, _, _ := newSuppressTestCmd(t, "--include-suppressed")
+		add(t)
+		kept, err := dropSuppressed(cmd, []string{"gone@example.com"})
+		if err != nil || !slices.Equal(kept[0], []string{"gone@example.com"}) {
+			t.Errorf("dropSuppressed() = %v, %v", kept, err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// threadRefPrefix marks a thread position in place of a message ID, as in
+// "goog mail show thread:3".
+const threadRefPrefix = "thread:"
+
+// threadPositionsFile stores the thread IDs of the last threaded listing,
+// next to the config file.
+const threadPositionsFile = "threads.json"
+
+// mailThreaded groups mail list and search output by thread.
+var mailThreaded bool
+
+// threadPositions is the on-disk record of the last threaded listing.
+type threadPositions struct {
+	Account string   `json:"account"`
+	Threads []string `json:"threads"`
+}
+
+// threadPositionsPath returns the path of the thread positions file.
+func threadPositionsPath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), threadPositionsFile)
+}
+
+// renderThreadedMessages groups messages by thread, numbers the threads in
+// displayed order and remembers the numbering for "thread:N" references.
+// The page metadata is printed with --envelope.
+func renderThreadedMessages(cmd *cobra.Command, msgs []*mail.Message, email string, page presenter.Page) error {
+	threads, err := listOptions.ApplyToThreads(mail.GroupByThread(msgs))
+	if err != nil {
+		return err
+	}
+
+	if err := saveThreadPositions(email, threads); err != nil {
+		output.Verbosef(cmd, "Warning: failed to save thread positions: %v", err)
+	}
+
+	// List options are already applied, so render without them
+	printList(cmd, output.Renderer(formatFlag).RenderThreads(threads), page)
+	return nil
+}
+
+// saveThreadPositions records the thread IDs of a threaded listing.
+func saveThreadPositions(email string, threads []*mail.Thread) error {
+	positions := threadPositions{Account: email, Threads: make([]string, 0, len(threads))}
+	for _, thread := range threads {
+		positions.Threads = append(positions.Threads, thread.ID)
+	}
+
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := threadPositionsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return filelock.WriteFile(path, data, 0600)
+}
+
+// parseThreadRef parses a "thread:N" reference, reporting ok=false for
+// anything else.
+func parseThreadRef(ref string) (position int, ok bool, err error) {
+	value, ok := strings.CutPrefix(ref, threadRefPrefix)
+	if !ok {
+		return 0, false, nil
+	}
+	position, err = strconv.Atoi(value)
+	if err != nil || position < 1 {
+		return 0, true, fmt.Errorf("invalid thread reference %q: expected %s<position>, e.g. %s3", ref, threadRefPrefix, threadRefPrefix)
+	}
+	return position, true, nil
+}
+
+// resolveThreadPosition returns the ID of the thread at position in the last
+// threaded listing for email.
+func resolveThreadPosition(email string, position int) (string, error) {
+	data, err := os.ReadFile(threadPositionsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no threaded listing to resolve %s%d (run 'goog mail list --threaded' first)", threadRefPrefix, position)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read thread positions: %w", err)
+	}
+
+	var positions threadPositions
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return "", fmt.Errorf("failed to parse thread positions: %w", err)
+	}
+	if positions.Account != email {
+		return "", fmt.Errorf("the last threaded listing was for %s (run 'goog mail list --threaded' for this account first)", positions.Account)
+	}
+	if position > len(positions.Threads) {
+		return "", fmt.Errorf("%s%d is out of range: the last threaded listing had %d thread(s)", threadRefPrefix, position, len(positions.Threads))
+	}
+	return positions.Threads[position-1], nil
+}
+
+// runMailShowThread shows the thread at a position of the last threaded
+// listing.
+func runMailShowThread(cmd *cobra.Command, position int) error {
+	ctx := context.Background()
+
+	tokenSource, email, err := getTokenSourceWithEmailFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	threadID, err := resolveThreadPosition(email, position)
+	if err != nil {
+		return err
+	}
+
+	repo, err := GetDependencies().RepoFactory.NewThreadRepository(ctx, tokenSource)
+	if err != nil {
+		return fmt.Errorf("failed to create thread repository: %w", err)
+	}
+	thread, err := repo.Get(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	cmd.Println(newPresenter().RenderThread(thread))
+	return nil
+}
@@ -0,0 +1,228 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// idRecordingThreadRepository records the ID passed to Get.
+type idRecordingThreadRepository struct {
+	MockThreadRepository
+	gotID string
+}
+
+func (r *idRecordingThreadRepository) Get(ctx context.Context, id string) (*mail.Thread, error) {
+	r.gotID = id
+	return r.MockThreadRepository.Get(ctx, id)
+}
+
+// threadedTestMessages returns a newest-first listing of two threads.
+func threadedTestMessages() []*mail.Message {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newMsg := func(id, threadID, snippet string, hours int, read bool) *mail.Message {
+		msg := mail.NewMessage(id, threadID, "a@example.com", "Subject "+threadID, "")
+		msg.Date = base.Add(time.Duration(hours) * time.Hour)
+		msg.Snippet = snippet
+		msg.IsRead = read
+		return msg
+	}
+	return []*mail.Message{
+		newMsg("m3", "t-alpha", "latest alpha reply", 3, false),
+		newMsg("m2", "t-beta", "beta only", 2, true),
+		newMsg("m1", "t-alpha", "first alpha", 1, true),
+	}
+}
+
+// setupMailThreadedTest injects repositories, points the config at a
+// temporary directory and resets the output flags.
+func setupMailThreadedTest(t *testing.T, email string, msgRepo MessageRepository, threadRepo ThreadRepository) *cobra.Command {
+	t.Helper()
+
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: email},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo, ThreadRepo: threadRepo},
+	})
+
+	origFormat, origThreaded, origOpts := formatFlag, mailThreaded, listOptions
+	formatFlag = "plain"
+	mailThreaded = true
+	listOptions = presenter.ListOptions{}
+	t.Cleanup(func() {
+		ResetDependencies()
+		formatFlag, mailThreaded, listOptions = origFormat, origThreaded, origOpts
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	return cmd
+}
+
+func TestMailListCmd_ThreadedFlag(t *testing.T) {
+	for _, cmd := range []*cobra.Command{mailListCmd, mailSearchCmd} {
+		if cmd.Flags().Lookup("threaded") == nil {
+			t.Errorf("%s should have a --threaded flag", cmd.Name())
+		}
+	}
+}
+
+func TestRunMailList_Threaded(t *testing.T) {
+	repo := &MockMessageRepository{ListResult: &mail.ListResult[*mail.Message]{Items: threadedTestMessages()}}
+	cmd := setupMailThreadedTest(t, "me@example.com", repo, nil)
+
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := cmd.OutOrStdout().(*bytes.Buffer).String()
+	for _, want := range []string{"1\tt-alpha\t2\t1\tlatest alpha reply", "2\tt-beta\t1\t0\tbeta only"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	id, err := resolveThreadPosition("me@example.com", 2)
+	if err != nil {
+		t.Fatalf("resolveThreadPosition failed: %v", err)
+	}
+	if id != "t-beta" {
+		t.Errorf("thread:2 = %q, want t-beta", id)
+	}
+	info, err := os.Stat(threadPositionsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("positions file permissions = %o, want 600", perm)
+	}
+}
+
+func TestRunMailList_ThreadedNumbersDisplayedOrder(t *testing.T) {
+	repo := &MockMessageRepository{ListResult: &mail.ListResult[*mail.Message]{Items: threadedTestMessages()}}
+	cmd := setupMailThreadedTest(t, "me@example.com", repo, nil)
+	listOptions = presenter.ListOptions{Filters: []presenter.Filter{{Field: "unread", Op: presenter.FilterEquals, Value: "0"}}}
+
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := cmd.OutOrStdout().(*bytes.Buffer).String()
+	if !containsStr(output, "1\tt-beta") || containsStr(output, "t-alpha") {
+		t.Errorf("expected only t-beta numbered 1, got:\n%s", output)
+	}
+	if id, err := resolveThreadPosition("me@example.com", 1); err != nil || id != "t-beta" {
+		t.Errorf("thread:1 = %q (%v), want t-beta", id, err)
+	}
+}
+
+func TestRunMailSearch_Threaded(t *testing.T) {
+	repo := &MockMessageRepository{SearchResult: &mail.ListResult[*mail.Message]{Items: threadedTestMessages()}}
+	cmd := setupMailThreadedTest(t, "me@example.com", repo, nil)
+
+	if err := runMailSearch(cmd, []string{"subject:alpha"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output := cmd.OutOrStdout().(*bytes.Buffer).String(); !containsStr(output, "1\tt-alpha") {
+		t.Errorf("expected threaded search output, got:\n%s", output)
+	}
+}
+
+func TestRunMailRead_ThreadRef(t *testing.T) {
+	listRepo := &MockMessageRepository{ListResult: &mail.ListResult[*mail.Message]{Items: threadedTestMessages()}}
+	thread := mail.NewThread("t-beta")
+	thread.Snippet = "beta thread body"
+	threadRepo := &idRecordingThreadRepository{MockThreadRepository: MockThreadRepository{Thread: thread}}
+	cmd := setupMailThreadedTest(t, "me@example.com", listRepo, threadRepo)
+
+	t.Run("before any threaded listing", func(t *testing.T) {
+		err := runMailRead(cmd, []string{"thread:1"})
+		if err == nil || !containsStr(err.Error(), "--threaded") {
+			t.Errorf("expected hint to run a threaded listing, got %v", err)
+		}
+	})
+
+	if err := runMailList(cmd, nil); err != nil {
+		t.Fatalf("runMailList failed: %v", err)
+	}
+
+	t.Run("resolves the position", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		cmd.SetOut(out)
+		if err := runMailRead(cmd, []string{"thread:2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if threadRepo.gotID != "t-beta" {
+			t.Errorf("fetched thread %q, want t-beta", threadRepo.gotID)
+		}
+		if !containsStr(out.String(), "beta thread body") {
+			t.Errorf("expected thread output, got:\n%s", out.String())
+		}
+	})
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"thread:3", "out of range"},
+		{"thread:0", "invalid thread reference"},
+		{"thread:abc", "invalid thread reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			err := runMailRead(cmd, []string{tt.ref})
+			if err == nil || !containsStr(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	t.Run("other account", func(t *testing.T) {
+		SetDependencies(&Dependencies{
+			AccountService: &MockAccountService{
+				Account:      &accountuc.Account{Alias: "work", Email: "work@example.com"},
+				TokenManager: &MockTokenManager{},
+			},
+			RepoFactory: &MockRepositoryFactory{ThreadRepo: threadRepo},
+		})
+		err := runMailRead(cmd, []string{"thread:1"})
+		if err == nil || !containsStr(err.Error(), "me@example.com") {
+			t.Errorf("expected account mismatch error, got %v", err)
+		}
+	})
+}
+
+func TestParseThreadRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantOK  bool
+		wantErr bool
+	}{
+		{"18abc123def456", 0, false, false},
+		{"thread:4", 4, true, false},
+		{"thread:", 0, true, true},
+		{"thread:-1", 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, ok, err := parseThreadRef(tt.ref)
+			if got != tt.want || ok != tt.wantOK || (err != nil) != tt.wantErr {
+				t.Errorf("parseThreadRef(%q) = %d, %v, %v", tt.ref, got, ok, err)
+			}
+		})
+	}
+}
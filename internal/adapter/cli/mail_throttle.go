@@ -0,0 +1,85 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/addresses"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// throttleFlag is the name of the flag that sets the per-recipient
+// cooldown of a sending command.
+const throttleFlag = "throttle"
+
+// addThrottleFlag registers --throttle on a sending command.
+func addThrottleFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration(throttleFlag, 0, "minimum time between messages to the same recipient, 0 to send anyway (default: mail.throttle)")
+}
+
+// throttlePolicy returns the throttle policy for cmd: the cooldown of
+// --throttle, or of mail.throttle when the flag is not given, with the
+// overrides of mail.throttle_overrides. It returns nil when the cooldown
+// is zero, as throttling is then off.
+func throttlePolicy(cmd *cobra.Command) (*mail.ThrottlePolicy, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.NewConfig()
+	}
+
+	var cooldown time.Duration
+	if f := cmd.Flags().Lookup(throttleFlag); f != nil && f.Changed {
+		cooldown, _ = cmd.Flags().GetDuration(throttleFlag)
+	} else if cfg.Mail.Throttle != "" {
+		if cooldown, err = time.ParseDuration(cfg.Mail.Throttle); err != nil {
+			return nil, fmt.Errorf("invalid mail.throttle %q: %w", cfg.Mail.Throttle, err)
+		}
+	}
+	if cooldown <= 0 {
+		return nil, nil
+	}
+	return mail.NewThrottlePolicy(cooldown, cfg.Mail.ThrottleOverrides)
+}
+
+// checkThrottle fails when any recipient was sent mail more recently than
+// the throttle policy allows, going by the last use recorded in the
+// address cache. All throttled recipients are reported together.
+func checkThrottle(cmd *cobra.Command, recipients ...[]string) error {
+	policy, err := throttlePolicy(cmd)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	cache, err := addresses.Load(addresses.Path())
+	if err != nil {
+		return fmt.Errorf("cannot check send throttle: %w", err)
+	}
+	lastSent := make(map[string]time.Time, len(cache))
+	for addr, entry := range cache {
+		lastSent[addr] = entry.LastUsed
+	}
+
+	var addrs []string
+	for _, list := range recipients {
+		for _, recipient := range list {
+			if addr, err := mail.ValidateAddress(recipient); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	throttled := policy.Check(addrs, lastSent, time.Now())
+	if len(throttled) == 0 {
+		return nil
+	}
+	problems := make([]string, 0, len(throttled))
+	for _, r := range throttled {
+		problems = append(problems, fmt.Sprintf("%s: mailed %s ago, next allowed in %s",
+			r.Address, time.Since(r.LastSent).Round(time.Second), r.Remaining.Round(time.Second)))
+	}
+	return fmt.Errorf("send throttled (use --%s 0 to send anyway):\n  %s", throttleFlag, strings.Join(problems, "\n  "))
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/addresses"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// newThrottleTestCmd returns a command with --throttle parsed from args, in
+// an isolated config directory holding the given config values.
+func newThrottleTestCmd(t *testing.T, values map[string]string, args ...string) *cobra.Command {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	for key, value := range values {
+		if err := cfg.SetValue(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cmd := &cobra.Command{Use: "test"}
+	addThrottleFlag(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestCheckThrottle(t *testing.T) {
+	values := map[string]string{"mail.throttle": "10m", "mail.throttle_overrides": "oncall@example.com=0"}
+
+	t.Run("refuses recently mailed recipients", func(t *testing.T) {
+		cmd := newThrottleTestCmd(t, values)
+		if err := addresses.Record(addresses.Path(), []string{"ana@example.com", "oncall@example.com"}, time.Now().Add(-2*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		err := checkThrottle(cmd, []string{"Ana <Ana@Example.com>", "oncall@example.com"}, []string{"new@example.com"})
+		if err == nil || !contains(err.Error(), "ana@example.com: mailed 2m") {
+			t.Fatalf("expected ana@example.com to be throttled, got %v", err)
+		}
+		if contains(err.Error(), "oncall@example.com") {
+			t.Errorf("oncall@example.com is exempt, got %v", err)
+		}
+	})
+
+	t.Run("flag replaces mail.throttle", func(t *testing.T) {
+		cmd := newThrottleTestCmd(t, values, "--throttle", "1m")
+		if err := addresses.Record(addresses.Path(), []string{"ana@example.com"}, time.Now().Add(-2*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkThrottle(cmd, []string{"ana@example.com"}); err != nil {
+			t.Errorf("expected the 1m cooldown to have passed, got %v", err)
+		}
+	})
+
+	t.Run("zero turns throttling off", func(t *testing.T) {
+		cmd := newThrottleTestCmd(t, values, "--throttle", "0")
+		if err := addresses.Record(addresses.Path(), []string{"ana@example.com"}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkThrottle(cmd, []string{"ana@example.com"}); err != nil {
+			t.Errorf("expected no throttling, got %v", err)
+		}
+	})
+
+	t.Run("off without a cooldown", func(t *testing.T) {
+		cmd := newThrottleTestCmd(t, nil)
+		if err := addresses.Record(addresses.Path(), []string{"ana@example.com"}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkThrottle(cmd, []string{"ana@example.com"}); err != nil {
+			t.Errorf("expected no throttling, got %v", err)
+		}
+	})
+}
+
+func TestRunMailWatchdir_Throttle(t *testing.T) {
+	repo := &sendRecordingRepository{}
+	dir, out := setupMailWatchdirTest(t, repo)
+	writeTestFile(t, dir, "a.txt", []byte("a"))
+	writeTestFile(t, dir, "b.txt", []byte("b"))
+
+	cmd := &cobra.Command{Use: "test"}
+	addThrottleFlag(cmd)
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	if err := cmd.ParseFlags([]string{"--throttle", "10m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMailWatchdir(cmd, []string{dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Sent) != 1 || repo.Sent[0].Subject != "a.txt" {
+		t.Fatalf("expected only a.txt to be sent, got %d message(s)", len(repo.Sent))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("expected b.txt to be held back in place: %v", err)
+	}
+	if !contains(out.String(), "Held back b.txt: send throttled") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
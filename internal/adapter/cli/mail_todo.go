@@ -0,0 +1,281 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Command flags for mail todo commands.
+var (
+	mailTodoLabel      string
+	mailTodoDoneLabel  string
+	mailTodoMaxResults int
+	mailTodoArchive    bool
+)
+
+// mailTodoCmd represents the mail todo command group.
+var mailTodoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "Use labels as a lightweight task queue",
+	Long: `Use Gmail labels as a lightweight task queue.
+
+Messages are marked as todo by applying a label, listed by that
+label, and completed by removing it. The label names come from the
+mail.todo_label (default "todo") and mail.done_label (default none)
+config keys and can be overridden with --label and --done-label.
+
+Labels that do not exist yet are created automatically.`,
+	Example: `  # Configure the workflow labels
+  goog config set mail.todo_label "Action"
+  goog config set mail.done_label "Action/Done"
+
+  # Queue a message, review the queue, and complete it
+  goog mail todo add 18c1234abcd
+  goog mail todo list
+  goog mail todo done 18c1234abcd --archive`,
+}
+
+// mailTodoAddCmd applies the todo label to messages.
+var mailTodoAddCmd = &cobra.Command{
+	Use:   "add <id>...",
+	Short: "Mark messages as todo",
+	Long: `Apply the todo label to one or more messages.
+
+The todo label is created if it does not exist.`,
+	Example: `  # Mark a message as todo
+  goog mail todo add 18c1234abcd
+
+  # Mark several messages with a custom label
+  goog mail todo add 18c1234abcd 18c5678efgh --label "Follow up"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailTodoAdd,
+}
+
+// mailTodoListCmd lists messages carrying the todo label.
+var mailTodoListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List todo messages",
+	Long:    `List messages that carry the todo label.`,
+	Example: `  # List the todo queue
+  goog mail todo list
+
+  # List the queue as JSON
+  goog mail todo list --format json`,
+	Args: cobra.NoArgs,
+	RunE: runMailTodoList,
+}
+
+// mailTodoDoneCmd removes the todo label from messages.
+var mailTodoDoneCmd = &cobra.Command{
+	Use:   "done <id>...",
+	Short: "Mark todo messages as done",
+	Long: `Remove the todo label from one or more messages.
+
+If a done label is configured (mail.done_label or --done-label), it
+is applied and created if it does not exist. Use --archive to also
+remove the messages from the inbox.`,
+	Example: `  # Complete a todo
+  goog mail todo done 18c1234abcd
+
+  # Complete and archive, applying a done label
+  goog mail todo done 18c1234abcd --done-label "Done" --archive`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMailTodoDone,
+}
+
+func init() {
+	mailCmd.AddCommand(mailTodoCmd)
+	mailTodoCmd.AddCommand(mailTodoAddCmd)
+	mailTodoCmd.AddCommand(mailTodoListCmd)
+	mailTodoCmd.AddCommand(mailTodoDoneCmd)
+
+	mailTodoCmd.PersistentFlags().StringVar(&mailTodoLabel, "label", "", "todo label name (default from mail.todo_label)")
+
+	mailTodoListCmd.Flags().IntVar(&mailTodoMaxResults, "max-results", 20, "maximum number of messages to list")
+
+	mailTodoDoneCmd.Flags().StringVar(&mailTodoDoneLabel, "done-label", "", "label to apply when done (default from mail.done_label)")
+	mailTodoDoneCmd.Flags().BoolVar(&mailTodoArchive, "archive", false, "also remove messages from the inbox")
+}
+
+// resolveTodoLabels returns the todo and done label names, applying flag
+// overrides on top of the configuration.
+func resolveTodoLabels() (todo, done string, err error) {
+	todo = strings.TrimSpace(mailTodoLabel)
+	done = strings.TrimSpace(mailTodoDoneLabel)
+	if todo != "" && done != "" {
+		return todo, done, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if todo == "" {
+		todo = strings.TrimSpace(cfg.Mail.TodoLabel)
+	}
+	if done == "" {
+		done = strings.TrimSpace(cfg.Mail.DoneLabel)
+	}
+	if todo == "" {
+		return "", "", fmt.Errorf("no todo label configured (set mail.todo_label or use --label)")
+	}
+	return todo, done, nil
+}
+
+// findOrCreateLabel looks up a label by name, creating it when create is true
+// and the label does not exist.
+func findOrCreateLabel(ctx context.Context, repo LabelRepository, name string, create bool) (*mail.Label, error) {
+	label, err := repo.GetByName(ctx, name)
+	if err == nil {
+		return label, nil
+	}
+	if !create || !errors.Is(err, mail.ErrLabelNotFound) {
+		return nil, fmt.Errorf("failed to find label %q: %w", name, err)
+	}
+
+	label, err = repo.Create(ctx, mail.NewLabel("", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create label %q: %w", name, err)
+	}
+	return label, nil
+}
+
+// runMailTodoAdd handles the mail todo add command.
+func runMailTodoAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	todoName, _, err := resolveTodoLabels()
+	if err != nil {
+		return err
+	}
+
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	label, err := findOrCreateLabel(ctx, labelRepo, todoName, true)
+	if err != nil {
+		return err
+	}
+
+	msgRepo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		req := mail.ModifyRequest{AddLabels: []string{label.ID}}
+		if _, err := msgRepo.Modify(ctx, id, req); err != nil {
+			return fmt.Errorf("failed to add todo label to message %s: %w", id, err)
+		}
+	}
+
+	if !quietFlag {
+		cmd.Printf("Added %d message(s) to %s.\n", len(args), todoName)
+	}
+	return nil
+}
+
+// runMailTodoList handles the mail todo list command.
+func runMailTodoList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	todoName, _, err := resolveTodoLabels()
+	if err != nil {
+		return err
+	}
+
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	p := newPresenter()
+
+	label, err := labelRepo.GetByName(ctx, todoName)
+	if errors.Is(err, mail.ErrLabelNotFound) {
+		// Nothing has been queued yet
+		cmd.Println(p.RenderMessages(nil))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find label %q: %w", todoName, err)
+	}
+
+	msgRepo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := mail.ListOptions{
+		MaxResults: mailTodoMaxResults,
+		LabelIDs:   []string{label.ID},
+	}
+	result, err := msgRepo.List(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	cmd.Println(p.RenderMessages(result.Items))
+
+	if !quietFlag && result.NextPageToken != "" && formatFlag != presenter.FormatJSON {
+		cmd.Println("\n(More messages available. Use --max-results to adjust.)")
+	}
+	return nil
+}
+
+// runMailTodoDone handles the mail todo done command.
+func runMailTodoDone(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	todoName, doneName, err := resolveTodoLabels()
+	if err != nil {
+		return err
+	}
+
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	todoLabel, err := findOrCreateLabel(ctx, labelRepo, todoName, false)
+	if err != nil {
+		return err
+	}
+
+	req := mail.ModifyRequest{RemoveLabels: []string{todoLabel.ID}}
+	if doneName != "" {
+		doneLabel, err := findOrCreateLabel(ctx, labelRepo, doneName, true)
+		if err != nil {
+			return err
+		}
+		req.AddLabels = []string{doneLabel.ID}
+	}
+	if mailTodoArchive {
+		req.RemoveLabels = append(req.RemoveLabels, "INBOX")
+	}
+
+	msgRepo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		if _, err := msgRepo.Modify(ctx, id, req); err != nil {
+			return fmt.Errorf("failed to complete message %s: %w", id, err)
+		}
+	}
+
+	if !quietFlag {
+		cmd.Printf("Marked %d message(s) as done.\n", len(args))
+	}
+	return nil
+}
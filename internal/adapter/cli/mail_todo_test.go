@@ -0,0 +1,283 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// namedLabelRepository resolves labels by name and records created labels.
+type namedLabelRepository struct {
+	MockLabelRepository
+	ByName  map[string]*mail.Label
+	Created []string
+}
+
+func (m *namedLabelRepository) GetByName(ctx context.Context, name string) (*mail.Label, error) {
+	if m.GetByNameErr != nil {
+		return nil, m.GetByNameErr
+	}
+	if label, ok := m.ByName[name]; ok {
+		return label, nil
+	}
+	return nil, fmt.Errorf("%w: %s", mail.ErrLabelNotFound, name)
+}
+
+func (m *namedLabelRepository) Create(ctx context.Context, label *mail.Label) (*mail.Label, error) {
+	if m.CreateErr != nil {
+		return nil, m.CreateErr
+	}
+	m.Created = append(m.Created, label.Name)
+	label.ID = "Label_" + label.Name
+	return label, nil
+}
+
+// modifyRecordingRepository records message label modifications.
+type modifyRecordingRepository struct {
+	MockMessageRepository
+	Modified map[string]mail.ModifyRequest
+	Listed   []mail.ListOptions
+}
+
+func (m *modifyRecordingRepository) Modify(ctx context.Context, id string, req mail.ModifyRequest) (*mail.Message, error) {
+	if m.ModifyErr != nil {
+		return nil, m.ModifyErr
+	}
+	if m.Modified == nil {
+		m.Modified = make(map[string]mail.ModifyRequest)
+	}
+	m.Modified[id] = req
+	return &mail.Message{ID: id}, nil
+}
+
+func (m *modifyRecordingRepository) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Listed = append(m.Listed, opts)
+	return m.MockMessageRepository.List(ctx, opts)
+}
+
+// setupMailTodoTest injects repositories, points config at a temp file with
+// the given mail settings, and resets todo flags.
+func setupMailTodoTest(t *testing.T, msgRepo MessageRepository, labelRepo LabelRepository, mailConfig string) *bytes.Buffer {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if mailConfig != "" {
+		if err := os.WriteFile(configPath, []byte("mail:\n"+mailConfig), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	t.Setenv("GOOG_CONFIG", configPath)
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: msgRepo, LabelRepo: labelRepo},
+	})
+
+	origLabel, origDone, origMax, origArchive := mailTodoLabel, mailTodoDoneLabel, mailTodoMaxResults, mailTodoArchive
+	origFormat, origQuiet := formatFlag, quietFlag
+	mailTodoLabel = ""
+	mailTodoDoneLabel = ""
+	mailTodoMaxResults = 20
+	mailTodoArchive = false
+	formatFlag = "plain"
+	quietFlag = false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailTodoLabel, mailTodoDoneLabel, mailTodoMaxResults, mailTodoArchive = origLabel, origDone, origMax, origArchive
+		formatFlag, quietFlag = origFormat, origQuiet
+	})
+
+	return new(bytes.Buffer)
+}
+
+func TestMailTodoCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "todo", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"add", "list", "done", "mail.todo_label", "--label"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunMailTodoAdd(t *testing.T) {
+	t.Run("creates missing label from config", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{}
+		labelRepo := &namedLabelRepository{}
+		buf := setupMailTodoTest(t, msgRepo, labelRepo, "  todo_label: Action\n")
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runMailTodoAdd(cmd, []string{"m1", "m2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(labelRepo.Created) != 1 || labelRepo.Created[0] != "Action" {
+			t.Errorf("expected Action label to be created, got %v", labelRepo.Created)
+		}
+		for _, id := range []string{"m1", "m2"} {
+			req := msgRepo.Modified[id]
+			if len(req.AddLabels) != 1 || req.AddLabels[0] != "Label_Action" {
+				t.Errorf("message %s modify = %+v, want add Label_Action", id, req)
+			}
+		}
+		if !containsStr(buf.String(), "Added 2 message(s) to Action") {
+			t.Errorf("unexpected output: %s", buf.String())
+		}
+	})
+
+	t.Run("flag overrides config and reuses existing label", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{}
+		labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{
+			"Follow up": mail.NewLabel("Label_9", "Follow up"),
+		}}
+		setupMailTodoTest(t, msgRepo, labelRepo, "")
+		mailTodoLabel = "Follow up"
+
+		if err := runMailTodoAdd(&cobra.Command{Use: "test"}, []string{"m1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(labelRepo.Created) != 0 {
+			t.Errorf("expected no labels to be created, got %v", labelRepo.Created)
+		}
+		if got := msgRepo.Modified["m1"].AddLabels; len(got) != 1 || got[0] != "Label_9" {
+			t.Errorf("AddLabels = %v, want [Label_9]", got)
+		}
+	})
+
+	t.Run("modify error", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{MockMessageRepository: MockMessageRepository{ModifyErr: errors.New("boom")}}
+		setupMailTodoTest(t, msgRepo, &namedLabelRepository{}, "")
+
+		err := runMailTodoAdd(&cobra.Command{Use: "test"}, []string{"m1"})
+		if err == nil || !containsStr(err.Error(), "failed to add todo label") {
+			t.Errorf("expected modify error, got %v", err)
+		}
+	})
+}
+
+func TestRunMailTodoList(t *testing.T) {
+	t.Run("lists messages with the todo label", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{MockMessageRepository: MockMessageRepository{
+			Messages: []*mail.Message{mail.NewMessage("m1", "t1", "boss@example.com", "Review budget", "")},
+		}}
+		labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{
+			"todo": mail.NewLabel("Label_1", "todo"),
+		}}
+		buf := setupMailTodoTest(t, msgRepo, labelRepo, "")
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runMailTodoList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(msgRepo.Listed) != 1 || len(msgRepo.Listed[0].LabelIDs) != 1 || msgRepo.Listed[0].LabelIDs[0] != "Label_1" {
+			t.Errorf("expected list by Label_1, got %+v", msgRepo.Listed)
+		}
+		if !containsStr(buf.String(), "Review budget") {
+			t.Errorf("expected output to contain message, got: %s", buf.String())
+		}
+	})
+
+	t.Run("missing label lists nothing", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{}
+		setupMailTodoTest(t, msgRepo, &namedLabelRepository{}, "")
+
+		if err := runMailTodoList(&cobra.Command{Use: "test"}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(msgRepo.Listed) != 0 {
+			t.Errorf("expected no list call, got %+v", msgRepo.Listed)
+		}
+	})
+
+	t.Run("label lookup error", func(t *testing.T) {
+		labelRepo := &namedLabelRepository{MockLabelRepository: MockLabelRepository{GetByNameErr: errors.New("boom")}}
+		setupMailTodoTest(t, &modifyRecordingRepository{}, labelRepo, "")
+
+		err := runMailTodoList(&cobra.Command{Use: "test"}, nil)
+		if err == nil || !containsStr(err.Error(), "failed to find label") {
+			t.Errorf("expected lookup error, got %v", err)
+		}
+	})
+}
+
+func TestRunMailTodoDone(t *testing.T) {
+	t.Run("removes todo and applies configured done label", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{}
+		labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{
+			"todo": mail.NewLabel("Label_1", "todo"),
+		}}
+		buf := setupMailTodoTest(t, msgRepo, labelRepo, "  done_label: Done\n")
+		mailTodoArchive = true
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.SetOut(buf)
+
+		if err := runMailTodoDone(cmd, []string{"m1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		req := msgRepo.Modified["m1"]
+		if len(req.RemoveLabels) != 2 || req.RemoveLabels[0] != "Label_1" || req.RemoveLabels[1] != "INBOX" {
+			t.Errorf("RemoveLabels = %v, want [Label_1 INBOX]", req.RemoveLabels)
+		}
+		if len(req.AddLabels) != 1 || req.AddLabels[0] != "Label_Done" {
+			t.Errorf("AddLabels = %v, want [Label_Done]", req.AddLabels)
+		}
+		if !containsStr(buf.String(), "Marked 1 message(s) as done") {
+			t.Errorf("unexpected output: %s", buf.String())
+		}
+	})
+
+	t.Run("without done label only removes todo", func(t *testing.T) {
+		msgRepo := &modifyRecordingRepository{}
+		labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{
+			"todo": mail.NewLabel("Label_1", "todo"),
+		}}
+		setupMailTodoTest(t, msgRepo, labelRepo, "")
+
+		if err := runMailTodoDone(&cobra.Command{Use: "test"}, []string{"m1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		req := msgRepo.Modified["m1"]
+		if len(req.AddLabels) != 0 || len(req.RemoveLabels) != 1 {
+			t.Errorf("unexpected modify request: %+v", req)
+		}
+	})
+
+	t.Run("missing todo label", func(t *testing.T) {
+		setupMailTodoTest(t, &modifyRecordingRepository{}, &namedLabelRepository{}, "")
+
+		err := runMailTodoDone(&cobra.Command{Use: "test"}, []string{"m1"})
+		if err == nil || !errors.Is(err, mail.ErrLabelNotFound) {
+			t.Errorf("expected label not found error, got %v", err)
+		}
+	})
+}
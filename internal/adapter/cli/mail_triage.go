@@ -0,0 +1,375 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"golang.org/x/term"
+)
+
+// keyCtrlC is the byte read for Ctrl-C while the terminal is in raw mode.
+const keyCtrlC = 3
+
+// triagePrompt lists the single-key actions offered for each message.
+const triagePrompt = "[a]rchive [t]rash [s]tar [l]abel [r]eply [space] skip [q]uit > "
+
+// Command flags for mail triage.
+var (
+	mailTriageQuery      string
+	mailTriageMaxResults int
+	mailTriageLines      int
+	mailTriagePrefetch   int
+)
+
+// mailTriageCmd walks through unread messages one key at a time.
+var mailTriageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Process unread mail one message at a time",
+	Long: `Show unread messages one at a time and act on each with a single key.
+
+For each message the sender, subject, snippet and the first lines of
+the body are shown, followed by a prompt:
+
+  a      archive (remove from the inbox) and move on
+  t      move to trash and move on
+  s      star, then choose another action
+  l      add a label by name, then choose another action
+  r      type a one-line reply and send it now, then choose another action
+  space  skip the message
+  q      stop and apply the actions so far
+  Ctrl-C stop without applying anything
+
+Label changes are collected and applied together at the end with one
+batch request per distinct change; replies are sent immediately. On a
+terminal keys take effect without Enter; when input is piped, each line
+holds one key and an empty line skips.
+
+While a message is shown, the next few (--prefetch) are fetched in the
+background, so moving on is instant.`,
+	Example: `  # Triage unread inbox messages
+  goog mail triage
+
+  # Triage unread mail from one sender, showing more of each body
+  goog mail triage --query "is:unread from:alerts@example.com" --lines 10`,
+	Args: cobra.NoArgs,
+	RunE: runMailTriage,
+}
+
+func init() {
+	mailCmd.AddCommand(mailTriageCmd)
+
+	mailTriageCmd.Flags().StringVar(&mailTriageQuery, "query", "is:unread in:inbox", "Gmail search query selecting the messages to triage")
+	mailTriageCmd.Flags().IntVar(&mailTriageMaxResults, "max-results", 50, "maximum number of messages to triage")
+	mailTriageCmd.Flags().IntVar(&mailTriageLines, "lines", 5, "number of body lines to show for each message")
+	mailTriageCmd.Flags().IntVar(&mailTriagePrefetch, "prefetch", 3, "number of messages to fetch ahead while one is shown, 0 to fetch each when reached")
+}
+
+// triageStats counts what happened during a triage session.
+type triageStats struct {
+	archived, trashed, starred, labelled, replied, skipped int
+}
+
+// String summarises the session, e.g. "Archived 3, trashed 1, skipped 2".
+func (s triageStats) String() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		verb string
+	}{
+		{s.archived, "archived"}, {s.trashed, "trashed"}, {s.starred, "starred"},
+		{s.labelled, "labelled"}, {s.replied, "replied to"}, {s.skipped, "skipped"},
+	} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", c.verb, c.n))
+		}
+	}
+	if len(parts) == 0 {
+		return "No messages triaged"
+	}
+	summary := strings.Join(parts, ", ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// labelQueue collects the label changes chosen for each message so they
+// can be applied with one batchModify request per distinct change.
+type labelQueue struct {
+	order   []string
+	changes map[string]*mail.ModifyRequest
+}
+
+// add queues adding and removing labels on the message with the given ID.
+func (q *labelQueue) add(id string, addLabels, removeLabels []string) {
+	if q.changes == nil {
+		q.changes = make(map[string]*mail.ModifyRequest)
+	}
+	req, ok := q.changes[id]
+	if !ok {
+		req = &mail.ModifyRequest{}
+		q.changes[id] = req
+		q.order = append(q.order, id)
+	}
+	for _, label := range addLabels {
+		if !slices.Contains(req.AddLabels, label) {
+			req.AddLabels = append(req.AddLabels, label)
+		}
+	}
+	for _, label := range removeLabels {
+		if !slices.Contains(req.RemoveLabels, label) {
+			req.RemoveLabels = append(req.RemoveLabels, label)
+		}
+	}
+}
+
+// labelBatch is one batchModify request: a label change and the
+// messages it applies to.
+type labelBatch struct {
+	ids []string
+	req mail.ModifyRequest
+}
+
+// batches groups the queued messages by identical label changes, in the
+// order the messages were first queued.
+func (q *labelQueue) batches() []*labelBatch {
+	var batches []*labelBatch
+	byKey := make(map[string]*labelBatch)
+	for _, id := range q.order {
+		req := *q.changes[id]
+		req.AddLabels = slices.Sorted(slices.Values(req.AddLabels))
+		req.RemoveLabels = slices.Sorted(slices.Values(req.RemoveLabels))
+		key := strings.Join(req.AddLabels, ",") + "|" + strings.Join(req.RemoveLabels, ",")
+		batch, ok := byKey[key]
+		if !ok {
+			batch = &labelBatch{req: req}
+			byKey[key] = batch
+			batches = append(batches, batch)
+		}
+		batch.ids = append(batch.ids, id)
+	}
+	return batches
+}
+
+// apply commits the queued changes, one batchModify request per batch.
+func (q *labelQueue) apply(ctx context.Context, repo MessageRepository) error {
+	for _, batch := range q.batches() {
+		if err := repo.BatchModify(ctx, batch.ids, batch.req); err != nil {
+			return fmt.Errorf("failed to update labels on %d message(s): %w", len(batch.ids), err)
+		}
+	}
+	return nil
+}
+
+// triageInput reads action keys and free-text answers during triage.
+type triageInput struct {
+	*prompter
+	fd  int
+	raw bool
+}
+
+// newTriageInput creates a triageInput on the command's input, reading
+// keys without Enter when the input is a terminal.
+func newTriageInput(cmd *cobra.Command) *triageInput {
+	input := &triageInput{prompter: newPrompter(cmd)}
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		input.fd, input.raw = int(f.Fd()), true
+	}
+	return input
+}
+
+// key reads one action key. On a terminal the key is read in raw mode;
+// otherwise each line holds one key and an empty line reads as a space.
+func (in *triageInput) key() (byte, error) {
+	if in.raw {
+		state, err := term.MakeRaw(in.fd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read key: %w", err)
+		}
+		defer func() { _ = term.Restore(in.fd, state) }()
+		return in.in.ReadByte()
+	}
+	line, err := in.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return 0, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return ' ', nil
+	}
+	return line[0], nil
+}
+
+// runMailTriage handles the mail triage command.
+func runMailTriage(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	// Messages are fetched as they come up, with the next few prefetched
+	result, err := repo.List(ctx, mail.ListOptions{Query: mailTriageQuery, MaxResults: mailTriageMaxResults, IDsOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to list messages: %w", err)
+	}
+	if len(result.Items) == 0 {
+		cmd.Println("No messages to triage.")
+		return nil
+	}
+
+	ids := make([]string, len(result.Items))
+	for i, summary := range result.Items {
+		ids[i] = summary.ID
+	}
+	prefetch := newMessagePrefetcher(ctx, repo, ids, mailTriagePrefetch)
+	defer prefetch.Close()
+
+	input := newTriageInput(cmd)
+	var (
+		queue     labelQueue
+		stats     triageStats
+		labelRepo LabelRepository
+	)
+
+messages:
+	for i, id := range ids {
+		msg, err := prefetch.Get(i)
+		if err != nil {
+			output.Warnf(cmd, "skipping message %s: %v", id, err)
+			continue
+		}
+		printTriageMessage(cmd, msg, i+1, len(ids))
+
+		for {
+			cmd.Print(triagePrompt)
+			key, err := input.key()
+			if errors.Is(err, io.EOF) {
+				cmd.Println()
+				break messages
+			}
+			if err != nil {
+				return err
+			}
+			if key == keyCtrlC {
+				cmd.Println()
+				return errors.New("triage aborted; no changes were applied")
+			}
+			cmd.Println(triageKeyName(key))
+
+			switch key {
+			case 'a', 'A':
+				queue.add(msg.ID, nil, []string{"INBOX"})
+				stats.archived++
+				continue messages
+			case 't', 'T':
+				queue.add(msg.ID, []string{"TRASH"}, nil)
+				stats.trashed++
+				continue messages
+			case ' ', 'n', 'N':
+				stats.skipped++
+				continue messages
+			case 'q', 'Q':
+				break messages
+			case 's', 'S':
+				queue.add(msg.ID, []string{"STARRED"}, nil)
+				stats.starred++
+			case 'l', 'L':
+				if labelRepo == nil {
+					if labelRepo, err = getLabelRepositoryFromDeps(ctx); err != nil {
+						return err
+					}
+				}
+				name, err := input.ask("Label", "")
+				if err != nil || name == "" {
+					continue
+				}
+				label, err := findOrCreateLabel(ctx, labelRepo, name, false)
+				if err != nil {
+					output.Warnf(cmd, "%v", err)
+					continue
+				}
+				queue.add(msg.ID, []string{label.ID}, nil)
+				stats.labelled++
+			case 'r', 'R':
+				body, err := input.ask("Reply (empty to cancel)", "")
+				if err != nil || body == "" {
+					continue
+				}
+				if body, err = quoteReplyBody(cmd, body, msg); err != nil {
+					output.Warnf(cmd, "%v", err)
+					continue
+				}
+				reply := &mail.Message{
+					From:    mail.ParseAddress(senderEmail),
+					To:      []mail.Address{msg.From},
+					Subject: buildReplySubject(msg.Subject),
+					Body:    body,
+				}
+				reply.AddAutoBcc(autoBccAddresses(cmd))
+				if _, err := repo.Reply(ctx, msg.ID, reply); err != nil {
+					output.Warnf(cmd, "failed to send reply: %v", err)
+					continue
+				}
+				cmd.Println("Reply sent.")
+				stats.replied++
+			default:
+				cmd.Println("Unknown key.")
+			}
+		}
+	}
+
+	if err := queue.apply(ctx, repo); err != nil {
+		return err
+	}
+	if !quietFlag {
+		cmd.Printf("%s.\n", stats)
+	}
+	return nil
+}
+
+// printTriageMessage shows the header, snippet and top of the body of msg.
+func printTriageMessage(cmd *cobra.Command, msg *mail.Message, n, total int) {
+	cmd.Printf("\n[%d/%d] %s\n", n, total, msg.Subject)
+	cmd.Printf("From: %s\n", msg.From)
+	if !msg.Date.IsZero() {
+		cmd.Printf("Date: %s\n", msg.Date.Format("2006-01-02 15:04"))
+	}
+	if msg.Snippet != "" {
+		cmd.Printf("\n%s\n", msg.Snippet)
+	}
+	if lines := topLines(exportBody(msg), mailTriageLines); len(lines) > 0 {
+		cmd.Println("---")
+		for _, line := range lines {
+			cmd.Println(line)
+		}
+		cmd.Println("---")
+	}
+}
+
+// topLines returns up to n non-blank lines from the start of body.
+func topLines(body string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if len(lines) >= n {
+			break
+		}
+		if line = strings.TrimRight(line, " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// triageKeyName echoes an action key after the prompt.
+func triageKeyName(key byte) string {
+	if key == ' ' {
+		return "space"
+	}
+	return string(rune(key))
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// triageMessageRepository returns listed messages by ID and records replies.
+type triageMessageRepository struct {
+	MockMessageRepository
+	Replies map[string]*mail.Message
+}
+
+func (m *triageMessageRepository) Get(ctx context.Context, id string) (*mail.Message, error) {
+	for _, msg := range m.Messages {
+		if msg.ID == id {
+			return msg, nil
+		}
+	}
+	return nil, mail.ErrMessageNotFound
+}
+
+func (m *triageMessageRepository) Reply(ctx context.Context, messageID string, reply *mail.Message) (*mail.Message, error) {
+	if m.Replies == nil {
+		m.Replies = make(map[string]*mail.Message)
+	}
+	m.Replies[messageID] = reply
+	return reply, nil
+}
+
+// runTriage runs mail triage against repo with the given input lines.
+func runTriage(t *testing.T, repo *triageMessageRepository, input string) (string, error) {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			MessageRepo: repo,
+			LabelRepo:   &namedLabelRepository{ByName: map[string]*mail.Label{"Receipts": {ID: "Label_7", Name: "Receipts"}}},
+		},
+	})
+	t.Cleanup(ResetDependencies)
+
+	origLines, origQuiet := mailTriageLines, quietFlag
+	t.Cleanup(func() { mailTriageLines, quietFlag = origLines, origQuiet })
+	mailTriageLines, quietFlag = 2, false
+
+	cmd := &cobra.Command{Use: "test"}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader(input))
+	err := runMailTriage(cmd, nil)
+	return out.String(), err
+}
+
+func triageMessages() []*mail.Message {
+	return []*mail.Message{
+		{ID: "m1", From: mail.Address{Email: "alice@example.com"}, Subject: "Lunch?", Snippet: "Are you free", Body: "Hi,\n\nAre you free\nfor lunch\ntomorrow?"},
+		{ID: "m2", From: mail.Address{Email: "shop@example.com"}, Subject: "Receipt"},
+		{ID: "m3", From: mail.Address{Email: "spam@example.com"}, Subject: "Offer"},
+		{ID: "m4", From: mail.Address{Email: "news@example.com"}, Subject: "Digest"},
+		{ID: "m5", From: mail.Address{Email: "bob@example.com"}, Subject: "Notes"},
+	}
+}
+
+func TestRunMailTriage(t *testing.T) {
+	repo := &triageMessageRepository{MockMessageRepository: MockMessageRepository{Messages: triageMessages()}}
+
+	// m1: reply then archive; m2: star, label, archive; m3: trash; m4: skip; m5: archive
+	out, err := runTriage(t, repo, "r\nSure!\na\ns\nl\nReceipts\na\nt\n\na\n")
+	if err != nil {
+		t.Fatalf("runMailTriage failed: %v", err)
+	}
+
+	if !strings.Contains(out, "[1/5] Lunch?") || !strings.Contains(out, "Are you free\n---") || strings.Contains(out, "for lunch") {
+		t.Errorf("expected the header and the first two body lines, got:\n%s", out)
+	}
+	if reply := repo.Replies["m1"]; reply == nil || reply.Body != "Sure!" || reply.To[0].String() != "alice@example.com" || reply.Subject != "Re: Lunch?" {
+		t.Errorf("unexpected reply: %+v", reply)
+	}
+
+	want := []BatchModifyCall{
+		{IDs: []string{"m1", "m5"}, Req: mail.ModifyRequest{AddLabels: []string{}, RemoveLabels: []string{"INBOX"}}},
+		{IDs: []string{"m2"}, Req: mail.ModifyRequest{AddLabels: []string{"Label_7", "STARRED"}, RemoveLabels: []string{"INBOX"}}},
+		{IDs: []string{"m3"}, Req: mail.ModifyRequest{AddLabels: []string{"TRASH"}, RemoveLabels: []string{}}},
+	}
+	if !reflect.DeepEqual(normalizeBatches(repo.BatchModified), normalizeBatches(want)) {
+		t.Errorf("BatchModify calls = %+v, want %+v", repo.BatchModified, want)
+	}
+	if !strings.Contains(out, "Archived 3, trashed 1, starred 1, labelled 1, replied to 1, skipped 1.") {
+		t.Errorf("expected a summary, got:\n%s", out)
+	}
+}
+
+func TestRunMailTriage_QuitAndEOF(t *testing.T) {
+	repo := &triageMessageRepository{MockMessageRepository: MockMessageRepository{Messages: triageMessages()}}
+	if _, err := runTriage(t, repo, "a\nq\n"); err != nil {
+		t.Fatalf("runMailTriage failed: %v", err)
+	}
+	if len(repo.BatchModified) != 1 || !reflect.DeepEqual(repo.BatchModified[0].IDs, []string{"m1"}) {
+		t.Errorf("expected only m1 to be archived after q, got %+v", repo.BatchModified)
+	}
+
+	repo = &triageMessageRepository{MockMessageRepository: MockMessageRepository{Messages: triageMessages()}}
+	if _, err := runTriage(t, repo, "t\n"); err != nil {
+		t.Fatalf("runMailTriage failed: %v", err)
+	}
+	if len(repo.BatchModified) != 1 || repo.BatchModified[0].Req.AddLabels[0] != "TRASH" {
+		t.Errorf("expected the queued trash to be applied at end of input, got %+v", repo.BatchModified)
+	}
+}
+
+func TestRunMailTriage_Abort(t *testing.T) {
+	repo := &triageMessageRepository{MockMessageRepository: MockMessageRepository{Messages: triageMessages()}}
+	_, err := runTriage(t, repo, "a\n\x03\n")
+	if err == nil || !strings.Contains(err.Error(), "aborted") {
+		t.Fatalf("expected an abort error, got %v", err)
+	}
+	if len(repo.BatchModified) != 0 {
+		t.Errorf("expected nothing applied after Ctrl-C, got %+v", repo.BatchModified)
+	}
+}
+
+func TestRunMailTriage_NoMessages(t *testing.T) {
+	repo := &triageMessageRepository{}
+	out, err := runTriage(t, repo, "")
+	if err != nil {
+		t.Fatalf("runMailTriage failed: %v", err)
+	}
+	if !strings.Contains(out, "No messages to triage.") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestTopLines(t *testing.T) {
+	got := topLines("\nfirst  \n\n  second\nthird\n", 2)
+	if !reflect.DeepEqual(got, []string{"first", "  second"}) {
+		t.Errorf("topLines() = %q", got)
+	}
+	if got := topLines("", 5); len(got) != 0 {
+		t.Errorf("topLines(empty) = %q", got)
+	}
+}
+
+// normalizeBatches makes nil and empty label lists compare equal.
+func normalizeBatches(calls []BatchModifyCall) []BatchModifyCall {
+	out := make([]BatchModifyCall, len(calls))
+	for i, c := range calls {
+		out[i] = BatchModifyCall{IDs: c.IDs, Req: mail.ModifyRequest{
+			AddLabels:    append([]string{}, c.Req.AddLabels...),
+			RemoveLabels: append([]string{}, c.Req.RemoveLabels...),
+		}}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/addresses"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Names of the flags that control recipient verification.
+const (
+	noVerifyFlag = "no-verify"
+	checkMXFlag  = "check-mx"
+)
+
+// mxCheckTimeout bounds the MX lookup for one recipient domain.
+const mxCheckTimeout = 5 * time.Second
+
+// recipientResolver looks up recipient mail servers for --check-mx.
+var recipientResolver addresses.Resolver = net.DefaultResolver
+
+// addVerifyFlags registers --no-verify and --check-mx on a sending command.
+func addVerifyFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(noVerifyFlag, false, "send without checking recipients for syntax errors and typos")
+	cmd.Flags().Bool(checkMXFlag, false, "also check that recipient domains have mail servers (default: mail.check_mx)")
+}
+
+// verifyRecipients checks recipients before a message is sent, unless
+// --no-verify is set: every address must parse as RFC 5322, and its domain
+// must not look like a typo of a domain in the address cache, a common
+// provider or senderEmail's domain. With the MX check on, each domain must
+// also be able to receive mail; lookups that fail for other reasons only
+// print a warning. All problems are reported together.
+func verifyRecipients(ctx context.Context, cmd *cobra.Command, senderEmail string, recipients ...[]string) error {
+	if skip, _ := cmd.Flags().GetBool(noVerifyFlag); skip {
+		return nil
+	}
+
+	cache, err := addresses.Load(addresses.Path())
+	if err != nil {
+		cache = map[string]addresses.Entry{}
+	}
+	known := addresses.Domains(cache)
+	if domain := mail.AddressDomain(senderEmail); domain != "" {
+		known = append(known, domain)
+	}
+	known = append(known, mail.CommonMailDomains...)
+
+	var problems []string
+	var domains []string
+	checked := map[string]bool{}
+	for _, list := range recipients {
+		for _, recipient := range list {
+			addr, err := mail.ValidateAddress(recipient)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			if _, ok := cache[strings.ToLower(addr)]; !ok {
+				if suggestion := mail.SuggestAddress(addr, known); suggestion != "" {
+					problems = append(problems, fmt.Sprintf("possible typo in %q: did you mean %s?", recipient, suggestion))
+					continue
+				}
+			}
+			if domain := mail.AddressDomain(addr); !checked[domain] {
+				checked[domain] = true
+				domains = append(domains, domain)
+			}
+		}
+	}
+
+	if mxCheckEnabled(cmd) {
+		for _, domain := range domains {
+			lookupCtx, cancel := context.WithTimeout(ctx, mxCheckTimeout)
+			err := addresses.CheckMX(lookupCtx, recipientResolver, domain)
+			cancel()
+			switch {
+			case errors.Is(err, addresses.ErrNoMailServer):
+				problems = append(problems, err.Error())
+			case err != nil:
+				output.Warnf(cmd, "could not check mail servers: %v", err)
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("recipient check failed (use --%s to send anyway):\n  %s", noVerifyFlag, strings.Join(problems, "\n  "))
+}
+
+// mxCheckEnabled reports whether --check-mx, or mail.check_mx when the flag
+// is not given, turns the MX check on.
+func mxCheckEnabled(cmd *cobra.Command) bool {
+	if f := cmd.Flags().Lookup(checkMXFlag); f != nil && f.Changed {
+		enabled, _ := cmd.Flags().GetBool(checkMXFlag)
+		return enabled
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return false
+	}
+	return cfg.Mail.CheckMX
+}
+
+// recordRecipients adds the addresses a message was sent to to the address
+// cache used for typo checks. Failures are ignored: the message has
+// already been sent.
+func recordRecipients(recipients ...[]string) {
+	var addrs []string
+	for _, list := range recipients {
+		for _, recipient := range list {
+			if addr, err := mail.ValidateAddress(recipient); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	_ = addresses.Record(addresses.Path(), addrs, time.Now())
+}
@@ -0,0 +1,154 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/addresses"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// stubResolver resolves MX records from a map; other names are not found.
+type stubResolver struct {
+	mx map[string][]*net.MX
+}
+
+func (s *stubResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if records, ok := s.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (s *stubResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// newVerifyTestCmd returns a command with the verify flags and an isolated
+// config directory.
+func newVerifyTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cmd := &cobra.Command{Use: "test"}
+	addVerifyFlags(cmd)
+	cmd.SetErr(new(bytes.Buffer))
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestVerifyRecipients(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid recipients pass", func(t *testing.T) {
+		cmd := newVerifyTestCmd(t)
+		err := verifyRecipients(ctx, cmd, "me@example.com", []string{"Alice <alice@gmail.com>"}, []string{"bob@example.com"})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("typos and syntax errors are reported together", func(t *testing.T) {
+		cmd := newVerifyTestCmd(t)
+		err := verifyRecipients(ctx, cmd, "me@example.com", []string{"alice@gamil.com"}, []string{"bob@localhost"})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		for _, want := range []string{"did you mean alice@gmail.com?", `invalid address "bob@localhost"`, "--no-verify"} {
+			if !contains(err.Error(), want) {
+				t.Errorf("expected error to contain %q, got: %v", want, err)
+			}
+		}
+	})
+
+	t.Run("no-verify skips the checks", func(t *testing.T) {
+		cmd := newVerifyTestCmd(t, "--no-verify")
+		if err := verifyRecipients(ctx, cmd, "me@example.com", []string{"alice@gamil.com"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("cached addresses are trusted", func(t *testing.T) {
+		cmd := newVerifyTestCmd(t)
+		if err := addresses.Record(addresses.Path(), []string{"ops@gamil.com"}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		if err := verifyRecipients(ctx, cmd, "me@example.com", []string{"ops@gamil.com"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		// Domains in the cache are also suggested for new addresses.
+		err := verifyRecipients(ctx, cmd, "me@example.com", []string{"dev@gamil.co"})
+		if err == nil || !contains(err.Error(), "did you mean dev@gamil.com?") {
+			t.Errorf("expected a suggestion from the cache, got %v", err)
+		}
+	})
+
+	t.Run("check-mx rejects domains without mail servers", func(t *testing.T) {
+		orig := recipientResolver
+		recipientResolver = &stubResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}}}
+		t.Cleanup(func() { recipientResolver = orig })
+
+		cmd := newVerifyTestCmd(t, "--check-mx")
+		if err := verifyRecipients(ctx, cmd, "me@example.com", []string{"a@example.com"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		err := verifyRecipients(ctx, cmd, "me@example.com", []string{"a@nomail.example.org"})
+		if err == nil || !contains(err.Error(), "nomail.example.org has no MX or address records") {
+			t.Errorf("expected an MX error, got %v", err)
+		}
+	})
+}
+
+func TestRunMailSend_VerifiesAndRecordsRecipients(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	mockRepo := &MockMessageRepository{SendResult: &mail.Message{ID: "sent-msg-id"}}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "sender@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: mockRepo},
+	})
+	defer ResetDependencies()
+
+	origTo, origSubject, origBody := mailSendTo, mailSendSubject, mailSendBody
+	origCc, origBcc := mailSendCc, mailSendBcc
+	mailSendSubject, mailSendBody = "Hi", "Hello"
+	mailSendCc, mailSendBcc = nil, nil
+	defer func() {
+		mailSendTo, mailSendSubject, mailSendBody = origTo, origSubject, origBody
+		mailSendCc, mailSendBcc = origCc, origBcc
+	}()
+
+	cmd := &cobra.Command{Use: "test"}
+	addVerifyFlags(cmd)
+	cmd.SetOut(new(bytes.Buffer))
+
+	mailSendTo = []string{"friend@hotmial.com"}
+	if err := runMailSend(cmd, nil); err == nil || !contains(err.Error(), "did you mean friend@hotmail.com?") {
+		t.Fatalf("expected a typo error, got %v", err)
+	}
+	if cache, _ := addresses.Load(addresses.Path()); len(cache) != 0 {
+		t.Fatalf("expected nothing to be recorded, got %v", cache)
+	}
+
+	mailSendTo = []string{"Friend@Hotmail.com"}
+	if err := runMailSend(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache, err := addresses.Load(addresses.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache["friend@hotmail.com"]; !ok {
+		t.Errorf("expected the recipient to be recorded, got %v", cache)
+	}
+}
@@ -0,0 +1,323 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// Subfolders of a watched directory that processed files are moved into.
+const (
+	watchdirSentDir   = "sent"
+	watchdirFailedDir = "failed"
+)
+
+// Command flags for mail watchdir command.
+var (
+	mailWatchdirTo        []string
+	mailWatchdirCc        []string
+	mailWatchdirSubject   string
+	mailWatchdirBody      string
+	mailWatchdirLinkBody  string
+	mailWatchdirInterval  time.Duration
+	mailWatchdirMinAge    time.Duration
+	mailWatchdirMaxSizeMB int
+	mailWatchdirOnce      bool
+)
+
+// mailWatchdirCmd emails files dropped into a directory.
+var mailWatchdirCmd = &cobra.Command{
+	Use:   "watchdir <dir>",
+	Short: "Email files dropped into a directory",
+	Long: `Watch a directory and email each new file as an attachment.
+
+The directory is polled every --interval. Each regular file is sent
+as one message and then moved to the sent/ subfolder. Files that are
+still being written (modified within --min-age) are left for the next
+poll, and hidden files are ignored.
+
+Files larger than --max-size-mb are too large to attach. They are
+uploaded to Google Drive instead, shared read-only with the recipients,
+and sent as a link with the --link-body template. This needs the
+drive.file scope ('goog auth login --for "mail watchdir"'); without it
+large files are moved to the failed/ subfolder. Files that fail to
+upload or send stay in place and are retried on the next poll, as do
+files held back by --throttle: with a cooldown, at most one file per
+cooldown is sent to the same recipients. A file already uploaded is
+not uploaded again.
+
+The --subject, --body and --link-body templates support {filename} and
+{size}, and {account.<key>} for the account's metadata (see 'goog
+config account'); --link-body also supports {link}.
+Use --once to process the directory a single time, e.g. from cron.
+Press Ctrl+C to stop watching.`,
+	Example: `  # Email every file dropped into ./outbox
+  goog mail watchdir ./outbox --to team@example.com
+
+  # Custom subject, polling every minute
+  goog mail watchdir /var/spool/reports --to ops@example.com \
+    --subject "Nightly report: {filename}" --interval 1m
+
+  # Process the directory once and exit
+  goog mail watchdir ./outbox --to team@example.com --once
+
+  # Send at most one file every 10 minutes
+  goog mail watchdir ./outbox --to team@example.com --throttle 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailWatchdir,
+}
+
+func init() {
+	mailCmd.AddCommand(mailWatchdirCmd)
+
+	mailWatchdirCmd.Flags().StringSliceVar(&mailWatchdirTo, "to", nil, "recipient email address(es) (required)")
+	mailWatchdirCmd.Flags().StringSliceVar(&mailWatchdirCc, "cc", nil, "CC recipient email address(es)")
+	mailWatchdirCmd.Flags().StringVar(&mailWatchdirSubject, "subject", "{filename}", "subject template")
+	mailWatchdirCmd.Flags().StringVar(&mailWatchdirBody, "body", "Attached: {filename} ({size})", "body template")
+	mailWatchdirCmd.Flags().StringVar(&mailWatchdirLinkBody, "link-body", "Shared on Google Drive: {filename} ({size})\n{link}", "body template for files sent as Drive links")
+	mailWatchdirCmd.Flags().DurationVar(&mailWatchdirInterval, "interval", 10*time.Second, "polling interval")
+	mailWatchdirCmd.Flags().DurationVar(&mailWatchdirMinAge, "min-age", 5*time.Second, "minimum time since a file was last modified before it is sent")
+	mailWatchdirCmd.Flags().IntVar(&mailWatchdirMaxSizeMB, "max-size-mb", 18, "largest file to attach, in megabytes; larger files are sent as Drive links")
+	mailWatchdirCmd.Flags().BoolVar(&mailWatchdirOnce, "once", false, "process the directory once and exit")
+	_ = mailWatchdirCmd.MarkFlagRequired("to")
+	addThrottleFlag(mailWatchdirCmd)
+	addSuppressFlag(mailWatchdirCmd)
+	addAutoBccFlag(mailWatchdirCmd)
+}
+
+// runMailWatchdir handles the mail watchdir command.
+func runMailWatchdir(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+
+	if mailWatchdirInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if mailWatchdirMinAge < 0 {
+		return fmt.Errorf("--min-age cannot be negative")
+	}
+	if mailWatchdirMaxSizeMB <= 0 {
+		return fmt.Errorf("--max-size-mb must be positive")
+	}
+
+	toRecipients, err := parseEmailRecipients(mailWatchdirTo)
+	if err != nil {
+		return fmt.Errorf("invalid 'to' recipient: %w", err)
+	}
+	if len(toRecipients) == 0 {
+		return fmt.Errorf("at least one recipient is required (--to)")
+	}
+	ccRecipients, err := parseEmailRecipients(mailWatchdirCc)
+	if err != nil {
+		return fmt.Errorf("invalid 'cc' recipient: %w", err)
+	}
+
+	kept, err := dropSuppressed(cmd, toRecipients, ccRecipients)
+	if err != nil {
+		return err
+	}
+	toRecipients, ccRecipients = kept[0], kept[1]
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open watch directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	for _, sub := range []string{watchdirSentDir, watchdirFailedDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", sub, err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	repo, senderEmail, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	template := &mail.Message{
+		From: mail.ParseAddress(senderEmail),
+		To:   mail.ParseAddresses(toRecipients),
+		Cc:   mail.ParseAddresses(ccRecipients),
+	}
+	template.AddAutoBcc(autoBccAddresses(cmd))
+
+	if !quietFlag && !mailWatchdirOnce {
+		cmd.Printf("Watching %s every %s (Ctrl+C to stop)\n", dir, mailWatchdirInterval)
+	}
+
+	links := make(map[string]string)
+	for {
+		if err := processWatchdir(ctx, cmd, repo, dir, template, links, time.Now()); err != nil {
+			return err
+		}
+		if mailWatchdirOnce {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(mailWatchdirInterval):
+		}
+	}
+}
+
+// processWatchdir sends every eligible file in dir once. Send failures are
+// reported and the file is left in place for the next poll. links holds
+// the Drive links of large files by path, so a file is uploaded once even
+// when its message has to be sent again.
+func processWatchdir(ctx context.Context, cmd *cobra.Command, repo MessageRepository, dir string, template *mail.Message, links map[string]string, now time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read watch directory: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	maxSize := int64(mailWatchdirMaxSizeMB) << 20
+	account := accountMetadata()
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		name := entry.Name()
+		if !entry.Type().IsRegular() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if now.Sub(info.ModTime()) < mailWatchdirMinAge {
+			// Still being written
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		large := info.Size() > maxSize
+
+		if large {
+			if err := requireScope(auth.ScopeDriveFile, "mail watchdir"); err != nil {
+				dest, moveErr := moveToSubdir(path, watchdirFailedDir)
+				if moveErr != nil {
+					return moveErr
+				}
+				cmd.PrintErrf("Skipped %s: %s exceeds --max-size-mb %d and cannot be sent as a Drive link: %v; moved to %s\n",
+					name, formatAttachmentSize(info.Size()), mailWatchdirMaxSizeMB, err, dest)
+				continue
+			}
+		}
+
+		if err := checkThrottle(cmd, mail.AddressStrings(template.To), mail.AddressStrings(template.Cc)); err != nil {
+			cmd.PrintErrf("Held back %s: %v\n", name, err)
+			continue
+		}
+
+		msg := *template
+		size := formatAttachmentSize(info.Size())
+		msg.Subject = strings.NewReplacer("{filename}", name, "{size}", size).Replace(config.ExpandMetadata(mailWatchdirSubject, account))
+		if large {
+			link, ok := links[path]
+			if !ok {
+				var err error
+				if link, err = uploadToDrive(ctx, path, name, &msg); err != nil {
+					cmd.PrintErrf("Failed to upload %s: %v\n", name, err)
+					continue
+				}
+				links[path] = link
+			}
+			msg.Body = strings.NewReplacer("{filename}", name, "{size}", size, "{link}", link).Replace(config.ExpandMetadata(mailWatchdirLinkBody, account))
+		} else {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				cmd.PrintErrf("Failed to read %s: %v\n", name, err)
+				continue
+			}
+			msg.Body = strings.NewReplacer("{filename}", name, "{size}", size).Replace(config.ExpandMetadata(mailWatchdirBody, account))
+			att := mail.NewAttachment("", name, detectMimeType(name, data))
+			att.SetData(data)
+			msg.Attachments = []*mail.Attachment{att}
+		}
+
+		sent, err := repo.Send(ctx, &msg)
+		if err != nil {
+			cmd.PrintErrf("Failed to send %s: %v\n", name, err)
+			continue
+		}
+		recordRecipients(mail.AddressStrings(msg.To), mail.AddressStrings(msg.Cc))
+		delete(links, path)
+
+		if _, err := moveToSubdir(path, watchdirSentDir); err != nil {
+			return err
+		}
+		if !quietFlag {
+			cmd.Printf("Sent %s (message %s)\n", name, sent.ID)
+		}
+	}
+
+	return nil
+}
+
+// uploadToDrive uploads a file too large to attach to Drive, shares it
+// with every recipient of msg and returns its link.
+func uploadToDrive(ctx context.Context, path, name string, msg *mail.Message) (string, error) {
+	drive, err := getDriveFileRepositoryFromDeps(ctx)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	// The start of the file tells its type when the extension does not
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	head = head[:n]
+	readers := slices.Concat(mail.AddressStrings(msg.To), mail.AddressStrings(msg.Cc), mail.AddressStrings(msg.Bcc))
+	return drive.Upload(ctx, name, detectMimeType(name, head), io.MultiReader(bytes.NewReader(head), f), readers)
+}
+
+// moveToSubdir moves a file into a subfolder of its directory, adding a
+// numeric suffix if a file with the same name is already there.
+func moveToSubdir(path, subdir string) (string, error) {
+	destDir := filepath.Join(filepath.Dir(path), subdir)
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	dest := filepath.Join(destDir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dest); os.IsNotExist(err) {
+			break
+		}
+		dest = filepath.Join(destDir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("failed to move %s to %s: %w", name, subdir, err)
+	}
+	return dest, nil
+}
@@ -0,0 +1,341 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// sendRecordingRepository records every sent message and can fail by subject.
+type sendRecordingRepository struct {
+	MockMessageRepository
+	Sent   []*mail.Message
+	FailOn map[string]bool
+}
+
+func (m *sendRecordingRepository) Send(ctx context.Context, msg *mail.Message) (*mail.Message, error) {
+	if m.FailOn[msg.Subject] {
+		return nil, errors.New("send failed")
+	}
+	m.Sent = append(m.Sent, msg)
+	return &mail.Message{ID: "sent-" + msg.Subject}, nil
+}
+
+// setupMailWatchdirTest injects a recording repository, isolates the
+// config directory and resets watchdir flags.
+func setupMailWatchdirTest(t *testing.T, repo MessageRepository) (string, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "robot@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo},
+	})
+
+	origTo, origCc, origSubject, origBody := mailWatchdirTo, mailWatchdirCc, mailWatchdirSubject, mailWatchdirBody
+	origLinkBody := mailWatchdirLinkBody
+	origInterval, origMinAge, origMax, origOnce, origQuiet := mailWatchdirInterval, mailWatchdirMinAge, mailWatchdirMaxSizeMB, mailWatchdirOnce, quietFlag
+	mailWatchdirTo = []string{"team@example.com"}
+	mailWatchdirCc = nil
+	mailWatchdirSubject = "{filename}"
+	mailWatchdirBody = "Attached: {filename} ({size})"
+	mailWatchdirLinkBody = "{filename}: {link}"
+	mailWatchdirInterval = time.Second
+	mailWatchdirMinAge = 0
+	mailWatchdirMaxSizeMB = 1
+	mailWatchdirOnce = true
+	quietFlag = false
+	t.Cleanup(func() {
+		ResetDependencies()
+		mailWatchdirTo, mailWatchdirCc, mailWatchdirSubject, mailWatchdirBody = origTo, origCc, origSubject, origBody
+		mailWatchdirLinkBody = origLinkBody
+		mailWatchdirInterval, mailWatchdirMinAge, mailWatchdirMaxSizeMB, mailWatchdirOnce, quietFlag = origInterval, origMinAge, origMax, origOnce, origQuiet
+	})
+
+	return t.TempDir(), new(bytes.Buffer)
+}
+
+// writeTestFile writes a file into dir and fails the test on error.
+func writeTestFile(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestMailWatchdirCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(mailCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"mail", "watchdir", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--to", "--interval", "--min-age", "--max-size-mb", "--once", "sent/"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestRunMailWatchdir_Once(t *testing.T) {
+	repo := &sendRecordingRepository{}
+	dir, buf := setupMailWatchdirTest(t, repo)
+	mailWatchdirSubject = "Drop: {filename}"
+
+	writeTestFile(t, dir, "b-report.csv", []byte("a,b\n1,2\n"))
+	writeTestFile(t, dir, "a-notes.txt", []byte("hello"))
+	writeTestFile(t, dir, ".partial", []byte("ignored"))
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	if err := runMailWatchdir(cmd, []string{dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.Sent) != 2 {
+		t.Fatalf("expected 2 messages sent, got %d", len(repo.Sent))
+	}
+
+	first := repo.Sent[0]
+	if first.Subject != "Drop: a-notes.txt" {
+		t.Errorf("Subject = %q, want files in name order", first.Subject)
+	}
+	if first.From.String() != "robot@example.com" || len(first.To) != 1 || first.To[0].String() != "team@example.com" {
+		t.Errorf("unexpected sender or recipients: %+v", first)
+	}
+	if first.Body != "Attached: a-notes.txt (5 B)" {
+		t.Errorf("Body = %q", first.Body)
+	}
+	if len(first.Attachments) != 1 || string(first.Attachments[0].Data) != "hello" || first.Attachments[0].Filename != "a-notes.txt" {
+		t.Errorf("unexpected attachment: %+v", first.Attachments)
+	}
+	if first.Attachments[0].MimeType == "" {
+		t.Error("expected attachment MIME type to be set")
+	}
+
+	for _, name := range []string{"a-notes.txt", "b-report.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, "sent", name)); err != nil {
+			t.Errorf("expected %s to be moved to sent/: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed from the watch directory", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".partial")); err != nil {
+		t.Error("expected hidden file to be left in place")
+	}
+	if !containsStr(buf.String(), "Sent a-notes.txt (message sent-Drop: a-notes.txt)") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestRunMailWatchdir_SkipsRecentAndOversizeFiles(t *testing.T) {
+	repo := &sendRecordingRepository{}
+	dir, buf := setupMailWatchdirTest(t, repo)
+	mailWatchdirMinAge = time.Hour
+
+	writeTestFile(t, dir, "old.txt", []byte("old"))
+	writeTestFile(t, dir, "new.txt", []byte("new"))
+	writeTestFile(t, dir, "big.bin", bytes.Repeat([]byte{1}, 2<<20))
+
+	past := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"old.txt", "big.bin"} {
+		if err := os.Chtimes(filepath.Join(dir, name), past, past); err != nil {
+			t.Fatalf("failed to set times: %v", err)
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	if err := runMailWatchdir(cmd, []string{dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.Sent) != 1 || repo.Sent[0].Subject != "old.txt" {
+		t.Fatalf("expected only old.txt to be sent, got %d message(s)", len(repo.Sent))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); err != nil {
+		t.Error("expected recently modified file to stay in place")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "failed", "big.bin")); err != nil {
+		t.Errorf("expected oversize file to be moved to failed/: %v", err)
+	}
+	if !containsStr(buf.String(), "Skipped big.bin") {
+		t.Errorf("expected skip notice, got: %s", buf.String())
+	}
+}
+
+func TestRunMailWatchdir_LargeFilesAsDriveLinks(t *testing.T) {
+	repo := &sendRecordingRepository{FailOn: map[string]bool{"big.bin": true}}
+	dir, buf := setupMailWatchdirTest(t, repo)
+	drive := &MockDriveFileRepository{}
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "robot@example.com"},
+			TokenManager: &MockTokenManager{GrantedScopes: []string{auth.ScopeGmailSend, auth.ScopeDriveFile}},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, DriveFileRepo: drive},
+	})
+	mailWatchdirCc = []string{"lead@example.com"}
+	mailWatchdirLinkBody = "Get {filename} ({size}): {link}"
+	writeTestFile(t, dir, "big.bin", bytes.Repeat([]byte{1}, 2<<20))
+	if err := os.MkdirAll(filepath.Join(dir, "sent"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	// The first send fails, and the retry reuses the upload
+	links := map[string]string{}
+	template := &mail.Message{To: mail.ParseAddresses(mailWatchdirTo), Cc: mail.ParseAddresses(mailWatchdirCc)}
+	for range 2 {
+		if err := processWatchdir(context.Background(), cmd, repo, dir, template, links, time.Now()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		repo.FailOn = nil
+	}
+
+	if len(drive.Uploaded) != 1 || len(drive.Uploaded["big.bin"]) != 2<<20 {
+		t.Fatalf("expected big.bin to be uploaded once, got %d file(s)", len(drive.Uploaded))
+	}
+	if got := drive.Readers["big.bin"]; len(got) != 2 || got[0] != "team@example.com" || got[1] != "lead@example.com" {
+		t.Errorf("shared with %v, want the recipients", got)
+	}
+	if len(repo.Sent) != 1 {
+		t.Fatalf("expected 1 message sent, got %d", len(repo.Sent))
+	}
+	sent := repo.Sent[0]
+	if sent.Body != "Get big.bin (2.0 MiB): https://drive.google.com/file/d/big.bin/view" || len(sent.Attachments) != 0 {
+		t.Errorf("unexpected message: body %q, %d attachment(s)", sent.Body, len(sent.Attachments))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sent", "big.bin")); err != nil {
+		t.Errorf("expected big.bin to be moved to sent/: %v", err)
+	}
+	if len(links) != 0 {
+		t.Errorf("expected the link to be forgotten once sent, got %v", links)
+	}
+}
+
+func TestRunMailWatchdir_LargeFileWithoutDriveScope(t *testing.T) {
+	repo := &sendRecordingRepository{}
+	dir, buf := setupMailWatchdirTest(t, repo)
+	writeTestFile(t, dir, "big.bin", bytes.Repeat([]byte{1}, 2<<20))
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	if err := runMailWatchdir(cmd, []string{dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "failed", "big.bin")); err != nil {
+		t.Errorf("expected big.bin to be moved to failed/: %v", err)
+	}
+	if !containsStr(buf.String(), `was not granted https://www.googleapis.com/auth/drive.file`) || !containsStr(buf.String(), `--for "mail watchdir"`) {
+		t.Errorf("expected a scope hint, got: %s", buf.String())
+	}
+}
+
+func TestRunMailWatchdir_SendFailureLeavesFile(t *testing.T) {
+	repo := &sendRecordingRepository{FailOn: map[string]bool{"broken.txt": true}}
+	dir, buf := setupMailWatchdirTest(t, repo)
+
+	writeTestFile(t, dir, "broken.txt", []byte("x"))
+	writeTestFile(t, dir, "ok.txt", []byte("y"))
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	if err := runMailWatchdir(cmd, []string{dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "broken.txt")); err != nil {
+		t.Error("expected failed file to stay in place for retry")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sent", "ok.txt")); err != nil {
+		t.Errorf("expected ok.txt to be sent: %v", err)
+	}
+	if !containsStr(buf.String(), "Failed to send broken.txt") {
+		t.Errorf("expected failure notice, got: %s", buf.String())
+	}
+}
+
+func TestMoveToSubdir_Collision(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sent"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "sent"), "report.csv", []byte("earlier"))
+	writeTestFile(t, dir, "report.csv", []byte("later"))
+
+	dest, err := moveToSubdir(filepath.Join(dir, "report.csv"), "sent")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(dest) != "report-1.csv" {
+		t.Errorf("dest = %q, want report-1.csv", dest)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "sent", "report.csv"))
+	if string(data) != "earlier" {
+		t.Error("expected existing file to be preserved")
+	}
+}
+
+func TestRunMailWatchdir_Errors(t *testing.T) {
+	t.Run("missing directory", func(t *testing.T) {
+		dir, _ := setupMailWatchdirTest(t, &sendRecordingRepository{})
+
+		err := runMailWatchdir(&cobra.Command{Use: "test"}, []string{filepath.Join(dir, "nope")})
+		if err == nil || !containsStr(err.Error(), "failed to open watch directory") {
+			t.Errorf("expected directory error, got %v", err)
+		}
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		dir, _ := setupMailWatchdirTest(t, &sendRecordingRepository{})
+		mailWatchdirTo = []string{"not-an-email"}
+
+		err := runMailWatchdir(&cobra.Command{Use: "test"}, []string{dir})
+		if err == nil || !containsStr(err.Error(), "invalid 'to' recipient") {
+			t.Errorf("expected recipient error, got %v", err)
+		}
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		dir, _ := setupMailWatchdirTest(t, &sendRecordingRepository{})
+		mailWatchdirInterval = 0
+
+		err := runMailWatchdir(&cobra.Command{Use: "test"}, []string{dir})
+		if err == nil || !containsStr(err.Error(), "--interval") {
+			t.Errorf("expected interval error, got %v", err)
+		}
+	})
+}
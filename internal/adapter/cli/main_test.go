@@ -0,0 +1,23 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain points the config at a temporary directory, so commands that
+// keep state next to the config file, such as the address cache written
+// after sending, never touch the real one. Tests that need a config of
+// their own still set GOOG_CONFIG with t.Setenv.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "goog-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,264 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/usecase/meeting"
+)
+
+// Command flags for meet commands.
+var (
+	meetCalendar   string
+	meetDays       int
+	meetMaxThreads int
+)
+
+// meetDescriptionLimit caps the event description shown in a briefing.
+const meetDescriptionLimit = 500
+
+// meetCmd represents the meet command group.
+var meetCmd = &cobra.Command{
+	Use:   "meet",
+	Short: "Prepare for meetings",
+	Long: `Prepare for meetings by combining calendar events with related mail.
+
+The meet commands read an event from Google Calendar and look for the
+mail that gives it context.`,
+}
+
+// meetContextCmd prints a briefing for an event.
+var meetContextCmd = &cobra.Command{
+	Use:   "context <event-id>",
+	Short: "Show an event with the mail threads related to it",
+	Long: `Show a briefing for a calendar event: the event's time, place,
+organizer, attendees and their responses, followed by the mail threads
+of the last --days days that involve any of the other attendees or have
+the event title in their subject, most recent first.
+
+Rooms and other resources, and you, are not searched for. Large
+meetings are searched for their first 25 attendees only.`,
+	Example: `  # Brief yourself before a meeting
+  goog meet context abc123
+
+  # Look back 90 days, show up to 20 threads
+  goog meet context abc123 --days 90 --max 20
+
+  # Event in another calendar, as JSON
+  goog meet context abc123 --calendar team@group.calendar.google.com --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMeetContext,
+}
+
+func init() {
+	rootCmd.AddCommand(meetCmd)
+	meetCmd.AddCommand(meetContextCmd)
+
+	meetContextCmd.Flags().StringVar(&meetCalendar, "calendar", "primary", "calendar ID to use")
+	meetContextCmd.Flags().IntVar(&meetDays, "days", 30, "number of days of mail to search")
+	meetContextCmd.Flags().IntVar(&meetMaxThreads, "max", meeting.DefaultMaxThreads, "maximum number of threads to show")
+}
+
+// meetAttendeeJSON is the JSON representation of an event attendee.
+type meetAttendeeJSON struct {
+	Email    string `json:"email"`
+	Name     string `json:"name,omitempty"`
+	Response string `json:"response,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// meetThreadJSON is the JSON representation of a related thread.
+type meetThreadJSON struct {
+	ThreadID  string   `json:"thread_id"`
+	Subject   string   `json:"subject"`
+	Latest    string   `json:"latest"`
+	Messages  int      `json:"messages"`
+	Attendees []string `json:"attendees"`
+	Snippet   string   `json:"snippet,omitempty"`
+}
+
+// meetContextJSON is the JSON output of meet context.
+type meetContextJSON struct {
+	EventID          string             `json:"event_id"`
+	Title            string             `json:"title"`
+	Start            string             `json:"start"`
+	End              string             `json:"end"`
+	Location         string             `json:"location,omitempty"`
+	ConferenceURI    string             `json:"conference_uri,omitempty"`
+	Organizer        string             `json:"organizer,omitempty"`
+	Description      string             `json:"description,omitempty"`
+	Attendees        []meetAttendeeJSON `json:"attendees"`
+	Searched         []string           `json:"searched"`
+	OmittedAttendees int                `json:"omitted_attendees,omitempty"`
+	Since            string             `json:"since"`
+	Query            string             `json:"query"`
+	Threads          []meetThreadJSON   `json:"threads"`
+}
+
+// runMeetContext handles the meet context command.
+func runMeetContext(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	if meetDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	if meetMaxThreads <= 0 {
+		return fmt.Errorf("--max must be positive")
+	}
+
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	messageRepo, self, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	briefing, err := meeting.NewService(eventRepo, messageRepo).Brief(ctx, args[0], meeting.Options{
+		CalendarID: meetCalendar,
+		Lookback:   time.Duration(meetDays) * 24 * time.Hour,
+		MaxThreads: meetMaxThreads,
+		Self:       self,
+	})
+	if err != nil {
+		return err
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(meetContextToJSON(briefing), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode briefing: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	printMeetContext(cmd, briefing)
+	return nil
+}
+
+// meetContextToJSON converts a briefing to its JSON representation.
+func meetContextToJSON(b *meeting.Briefing) meetContextJSON {
+	e := b.Event
+	out := meetContextJSON{
+		EventID:          e.ID,
+		Title:            e.Title,
+		Start:            e.Start.Format(time.RFC3339),
+		End:              e.End.Format(time.RFC3339),
+		Location:         e.Location,
+		Description:      e.Description,
+		Attendees:        []meetAttendeeJSON{},
+		Searched:         b.Attendees,
+		OmittedAttendees: b.OmittedAttendees,
+		Since:            b.Since.Format(time.RFC3339),
+		Query:            b.Query,
+		Threads:          []meetThreadJSON{},
+	}
+	if out.Searched == nil {
+		out.Searched = []string{}
+	}
+	if e.HasConference() {
+		out.ConferenceURI = e.ConferenceData.URI
+	}
+	if e.Organizer != nil {
+		out.Organizer = e.Organizer.Email
+	}
+	for _, a := range e.Attendees {
+		out.Attendees = append(out.Attendees, meetAttendeeJSON{Email: a.Email, Name: a.DisplayName, Response: a.ResponseStatus, Optional: a.Optional})
+	}
+	for _, t := range b.Threads {
+		attendees := t.Attendees
+		if attendees == nil {
+			attendees = []string{}
+		}
+		out.Threads = append(out.Threads, meetThreadJSON{
+			ThreadID:  t.ThreadID,
+			Subject:   t.Subject,
+			Latest:    t.Latest.Format(time.RFC3339),
+			Messages:  t.Messages,
+			Attendees: attendees,
+			Snippet:   t.Snippet,
+		})
+	}
+	return out
+}
+
+// printMeetContext prints a briefing as text.
+func printMeetContext(cmd *cobra.Command, b *meeting.Briefing) {
+	locale := presenter.CurrentLocale()
+	e := b.Event
+
+	cmd.Println(e.Title)
+	if e.AllDay {
+		cmd.Printf("When:      %s (all day)\n", locale.Date(e.Start))
+	} else {
+		cmd.Printf("When:      %s - %s\n", locale.DateTime(e.Start.Local()), locale.Time(e.End.Local()))
+	}
+	if e.Location != "" {
+		cmd.Printf("Where:     %s\n", e.Location)
+	}
+	if e.HasConference() {
+		cmd.Printf("Join:      %s\n", e.ConferenceData.URI)
+	}
+	if e.Organizer != nil {
+		cmd.Printf("Organizer: %s\n", attendeeName(e.Organizer))
+	}
+	if len(e.Attendees) > 0 {
+		cmd.Printf("Attendees (%d):\n", len(e.Attendees))
+		for _, a := range e.Attendees {
+			line := fmt.Sprintf("  - %s", attendeeName(a))
+			if a.ResponseStatus != "" {
+				line += " [" + a.ResponseStatus + "]"
+			}
+			if a.Optional {
+				line += " (optional)"
+			}
+			cmd.Println(line)
+		}
+	}
+	if desc := strings.TrimSpace(e.Description); desc != "" {
+		if runes := []rune(desc); len(runes) > meetDescriptionLimit {
+			desc = strings.TrimSpace(string(runes[:meetDescriptionLimit])) + "..."
+		}
+		cmd.Println()
+		cmd.Println(desc)
+	}
+
+	cmd.Println()
+	if len(b.Threads) == 0 {
+		cmd.Printf("No related mail since %s\n", locale.Date(b.Since))
+	} else {
+		cmd.Printf("Related mail since %s (%d thread(s)):\n", locale.Date(b.Since), len(b.Threads))
+		for _, t := range b.Threads {
+			subject := t.Subject
+			if subject == "" {
+				subject = "(no subject)"
+			}
+			cmd.Printf("\n  %s  %s  [%d message(s)]\n", locale.Date(t.Latest.Local()), subject, t.Messages)
+			if len(t.Attendees) > 0 {
+				cmd.Printf("    with %s\n", strings.Join(t.Attendees, ", "))
+			}
+			if t.Snippet != "" {
+				cmd.Printf("    %s\n", t.Snippet)
+			}
+			cmd.Printf("    thread %s\n", t.ThreadID)
+		}
+	}
+	if b.OmittedAttendees > 0 {
+		cmd.Printf("\n%d more attendee(s) were not searched\n", b.OmittedAttendees)
+	}
+}
+
+// attendeeName formats an attendee as "Name <email>", or the email alone.
+func attendeeName(a *calendar.Attendee) string {
+	if a.DisplayName != "" {
+		return fmt.Sprintf("%s <%s>", a.DisplayName, a.Email)
+	}
+	return a.Email
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupMeetTest injects an event and mail and resets the meet flags.
+func setupMeetTest(t *testing.T, event *calendar.Event, messages []*mail.Message) (*bytes.Buffer, *cobra.Command) {
+	t.Helper()
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			EventRepo:   &MockEventRepository{Event: event},
+			MessageRepo: &MockMessageRepository{Messages: messages},
+		},
+	})
+
+	origCalendar, origDays, origMax, origFormat := meetCalendar, meetDays, meetMaxThreads, formatFlag
+	meetCalendar, meetDays, meetMaxThreads, formatFlag = "primary", 30, 10, "plain"
+	t.Cleanup(func() {
+		ResetDependencies()
+		meetCalendar, meetDays, meetMaxThreads, formatFlag = origCalendar, origDays, origMax, origFormat
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return buf, cmd
+}
+
+func meetTestEvent() *calendar.Event {
+	start := time.Now().Add(2 * time.Hour)
+	return &calendar.Event{
+		ID:             "ev1",
+		Title:          "Roadmap review",
+		Start:          start,
+		End:            start.Add(time.Hour),
+		Location:       "Room 4",
+		ConferenceData: &calendar.ConferenceData{Type: "hangoutsMeet", URI: "https://meet.google.com/abc-defg-hij"},
+		Organizer:      &calendar.Attendee{Email: "lead@example.com", DisplayName: "Lea"},
+		Attendees: []*calendar.Attendee{
+			{Email: "lead@example.com", DisplayName: "Lea", ResponseStatus: calendar.ResponseAccepted},
+			{Email: "me@example.com", Self: true, ResponseStatus: calendar.ResponseNeedsAction},
+			{Email: "ana@example.com", ResponseStatus: calendar.ResponseTentative, Optional: true},
+		},
+	}
+}
+
+func TestRunMeetContext(t *testing.T) {
+	messages := []*mail.Message{
+		{ID: "m1", ThreadID: "t1", From: mail.Address{Email: "ana@example.com"}, To: []mail.Address{{Email: "me@example.com"}}, Subject: "Roadmap draft", Date: time.Now().Add(-24 * time.Hour), Snippet: "Here is the draft"},
+	}
+	buf, cmd := setupMeetTest(t, meetTestEvent(), messages)
+
+	if err := runMeetContext(cmd, []string{"ev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"Roadmap review",
+		"Join:      https://meet.google.com/abc-defg-hij",
+		"Organizer: Lea <lead@example.com>",
+		"ana@example.com [tentative] (optional)",
+		"Related mail since",
+		"Roadmap draft  [1 message(s)]",
+		"with ana@example.com",
+		"Here is the draft",
+	} {
+		if !contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRunMeetContext_JSON(t *testing.T) {
+	buf, cmd := setupMeetTest(t, meetTestEvent(), nil)
+	formatFlag = "json"
+
+	if err := runMeetContext(cmd, []string{"ev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out meetContextJSON
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if out.EventID != "ev1" || len(out.Attendees) != 3 || len(out.Threads) != 0 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if len(out.Searched) != 2 || out.Searched[0] != "lead@example.com" || out.Searched[1] != "ana@example.com" {
+		t.Errorf("searched = %v", out.Searched)
+	}
+	if !contains(out.Query, `subject:"Roadmap review"`) {
+		t.Errorf("query = %q", out.Query)
+	}
+}
+
+func TestRunMeetContext_InvalidFlags(t *testing.T) {
+	_, cmd := setupMeetTest(t, meetTestEvent(), nil)
+	meetDays = 0
+	if err := runMeetContext(cmd, []string{"ev1"}); err == nil {
+		t.Error("expected an error for --days 0")
+	}
+}
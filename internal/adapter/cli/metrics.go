@@ -0,0 +1,281 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/metrics"
+)
+
+// Command flags for metrics commands.
+var (
+	metricsShowPrometheus bool
+)
+
+// Metrics collection state for the running command. metricsRecorder is nil
+// unless metrics.enabled is set.
+var (
+	metricsRecorder *metrics.Recorder
+	metricsTextfile string
+	metricsBase     http.RoundTripper
+)
+
+// metricsCmd represents the metrics command group.
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show local usage metrics",
+	Long: `Show local usage metrics recorded by goog.
+
+Metrics are off by default. When metrics.enabled is set in the config,
+each command records its run time, the requests it sent to each Google
+API, retries after rate limits or temporary errors, and bridge cache hits
+in a metrics file next to the config file. Nothing is sent anywhere.
+
+Use the metrics to tune concurrency and stay within API quotas. Set
+metrics.textfile to also write them in the Prometheus text format after
+each command, for the node_exporter textfile collector.`,
+	Example: `  # Start recording metrics
+  goog config set metrics.enabled true
+
+  # Export to the node_exporter textfile collector
+  goog config set metrics.textfile /var/lib/node_exporter/textfile/goog.prom
+
+  # Show what has been recorded
+  goog metrics show`,
+}
+
+// metricsShowCmd shows the recorded metrics.
+var metricsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show recorded metrics",
+	Long: `Show the metrics recorded since they were last reset: run time per
+command, requests per API, retries and cache hit rates.
+
+Use --format json for the raw counters, or --prometheus for the
+Prometheus text format.`,
+	Example: `  # Show metrics as tables
+  goog metrics show
+
+  # Show metrics as JSON
+  goog metrics show --format json
+
+  # Print metrics in the Prometheus text format
+  goog metrics show --prometheus`,
+	Args: cobra.NoArgs,
+	RunE: runMetricsShow,
+}
+
+// metricsResetCmd clears the recorded metrics.
+var metricsResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear recorded metrics",
+	Long:  `Clear the recorded metrics and start counting again.`,
+	Args:  cobra.NoArgs,
+	RunE:  runMetricsReset,
+}
+
+// metricsPathCmd shows the metrics file path.
+var metricsPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show metrics file path",
+	Long:  `Show the path to the metrics file.`,
+	Args:  cobra.NoArgs,
+	RunE:  runMetricsPath,
+}
+
+func init() {
+	metricsCmd.AddCommand(metricsShowCmd)
+	metricsCmd.AddCommand(metricsResetCmd)
+	metricsCmd.AddCommand(metricsPathCmd)
+
+	metricsShowCmd.Flags().BoolVar(&metricsShowPrometheus, "prometheus", false, "print metrics in the Prometheus text format")
+
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// runMetricsShow handles the metrics show command.
+func runMetricsShow(cmd *cobra.Command, args []string) error {
+	snap, err := metrics.Load(metrics.Path())
+	if err != nil {
+		return err
+	}
+
+	if metricsShowPrometheus {
+		return metrics.WritePrometheus(cmd.OutOrStdout(), snap)
+	}
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode metrics: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(snap.Commands) == 0 && len(snap.APICalls) == 0 && len(snap.Caches) == 0 {
+		cmd.Println("No metrics recorded.")
+		if !metricsEnabled() {
+			cmd.Println("Run 'goog config set metrics.enabled true' to start recording.")
+		}
+		return nil
+	}
+	renderMetricsText(cmd, snap)
+	return nil
+}
+
+// renderMetricsText prints metrics as tables.
+func renderMetricsText(cmd *cobra.Command, snap *metrics.Snapshot) {
+	if !snap.Since.IsZero() {
+		cmd.Printf("Since: %s\n", snap.Since.Local().Format(time.RFC3339))
+	}
+
+	w := newTableWriter(cmd.OutOrStdout())
+	if len(snap.Commands) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "COMMAND\tRUNS\tERRORS\tRETRIES\tAVG\tMAX")
+		for _, name := range sortedNames(snap.Commands) {
+			c := snap.Commands[name]
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", name, c.Count, c.Errors, c.Retries,
+				millis(c.AverageMillis()), millis(c.MaxMillis))
+		}
+	}
+	if len(snap.APICalls) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "API\tCALLS\tERRORS\tRATE LIMITED\tAVG")
+		for _, name := range sortedNames(snap.APICalls) {
+			a := snap.APICalls[name]
+			var avg int64
+			if a.Calls > 0 {
+				avg = a.TotalMillis / a.Calls
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", name, a.Calls, a.Errors, a.RateLimited, millis(avg))
+		}
+	}
+	if len(snap.Caches) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "CACHE\tHITS\tMISSES\tHIT RATE")
+		for _, name := range sortedNames(snap.Caches) {
+			c := snap.Caches[name]
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.0f%%\n", name, c.Hits, c.Misses, c.HitRate()*100)
+		}
+	}
+	_ = w.Flush()
+
+	cmd.Printf("\nRetries: %d\n", snap.Retries)
+}
+
+// millis formats a duration in milliseconds for display.
+func millis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// sortedNames returns the keys of m in order.
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runMetricsReset handles the metrics reset command.
+func runMetricsReset(cmd *cobra.Command, args []string) error {
+	if err := metrics.Reset(metrics.Path()); err != nil {
+		return fmt.Errorf("failed to reset metrics: %w", err)
+	}
+	if !quietFlag {
+		cmd.Println("Metrics cleared.")
+	}
+	return nil
+}
+
+// runMetricsPath handles the metrics path command.
+func runMetricsPath(cmd *cobra.Command, args []string) error {
+	cmd.Println(metrics.Path())
+	return nil
+}
+
+// metricsEnabled reports whether metrics.enabled is set in the config file.
+func metricsEnabled() bool {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return false
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.Metrics.Enabled
+}
+
+// setupMetrics starts recording metrics for the command when
+// metrics.enabled is set: API requests through a wrapping transport and
+// retries through the hook set by setupRetries. The metrics commands
+// themselves are not recorded.
+func setupMetrics(cmd *cobra.Command) {
+	metricsRecorder = nil
+	if isMetricsCommand(cmd) {
+		return
+	}
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil || !cfg.Metrics.Enabled {
+		return
+	}
+
+	metricsRecorder = metrics.NewRecorder()
+	metricsTextfile = cfg.Metrics.Textfile
+	metricsBase = repository.Transport()
+	repository.SetTransport(metricsRecorder.Transport(metricsBase))
+}
+
+// isMetricsCommand reports whether cmd is the metrics command or one of
+// its subcommands.
+func isMetricsCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == metricsCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// finishMetrics records the run of cmd, merges the metrics into the
+// metrics file and writes the Prometheus textfile if one is configured. A
+// failure only prints a warning, so metrics never fail a command.
+func finishMetrics(cmd *cobra.Command, elapsed time.Duration, runErr error) {
+	rec := metricsRecorder
+	if rec == nil {
+		return
+	}
+	metricsRecorder = nil
+	repository.SetTransport(metricsBase)
+
+	rec.RecordCommand(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "), elapsed, runErr)
+	total, err := rec.Flush(metrics.Path())
+	if err == nil && metricsTextfile != "" {
+		err = metrics.WriteTextfile(metricsTextfile, total)
+	}
+	if err != nil {
+		output.Warnf(cmd, "failed to save metrics: %v", err)
+	}
+}
+
+// recordCacheLookup returns a function recording lookups in the named
+// cache, or nil when metrics are off.
+func recordCacheLookup(name string) func(hit bool) {
+	rec := metricsRecorder
+	if rec == nil {
+		return nil
+	}
+	return func(hit bool) { rec.RecordCache(name, hit) }
+}
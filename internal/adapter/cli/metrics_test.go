@@ -0,0 +1,222 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/metrics"
+	"golang.org/x/oauth2"
+)
+
+// setupMetricsTest points the config at a temporary directory with
+// metrics enabled or not, and restores the metrics state afterwards.
+func setupMetricsTest(t *testing.T, enabled bool, textfile string) {
+	t.Helper()
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Metrics.Enabled = enabled
+	cfg.Metrics.Textfile = textfile
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	origFormat, origPrometheus := formatFlag, metricsShowPrometheus
+	formatFlag = "table"
+	metricsShowPrometheus = false
+	t.Cleanup(func() {
+		formatFlag, metricsShowPrometheus = origFormat, origPrometheus
+		metricsRecorder = nil
+		repository.SetTransport(nil)
+		repository.SetRetryHook(nil)
+	})
+}
+
+func TestSetupMetrics(t *testing.T) {
+	t.Run("does nothing when disabled", func(t *testing.T) {
+		setupMetricsTest(t, false, "")
+		setupMetrics(&cobra.Command{Use: "list"})
+		if metricsRecorder != nil {
+			t.Error("metrics should not be recorded unless enabled")
+		}
+		if repository.Transport() != nil {
+			t.Error("transport should not be wrapped when disabled")
+		}
+	})
+
+	t.Run("skips the metrics commands", func(t *testing.T) {
+		setupMetricsTest(t, true, "")
+		setupMetrics(metricsShowCmd)
+		if metricsRecorder != nil {
+			t.Error("metrics commands should not be recorded")
+		}
+	})
+
+	t.Run("records API calls and the command run", func(t *testing.T) {
+		textfile := filepath.Join(t.TempDir(), "goog.prom")
+		setupMetricsTest(t, true, textfile)
+		api := &fakeTasksAPI{}
+		repository.SetTransport(api)
+
+		root := &cobra.Command{Use: "goog"}
+		tasks := &cobra.Command{Use: "tasks"}
+		list := &cobra.Command{Use: "lists"}
+		root.AddCommand(tasks)
+		tasks.AddCommand(list)
+
+		setupMetrics(list)
+		if metricsRecorder == nil {
+			t.Fatal("expected metrics to be recorded when enabled")
+		}
+
+		ctx := context.Background()
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+		repo, err := repository.NewGTasksRepository(ctx, ts)
+		if err != nil {
+			t.Fatalf("NewGTasksRepository failed: %v", err)
+		}
+		if _, err := repository.NewGTaskListRepository(repo).List(ctx); err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+
+		finishMetrics(list, 120*time.Millisecond, nil)
+		if metricsRecorder != nil {
+			t.Error("finishMetrics should stop recording")
+		}
+		if repository.Transport() != http.RoundTripper(api) {
+			t.Error("finishMetrics should restore the transport")
+		}
+
+		snap, err := metrics.Load(metrics.Path())
+		if err != nil {
+			t.Fatalf("failed to load metrics: %v", err)
+		}
+		if c := snap.Commands["tasks lists"]; c == nil || c.Count != 1 || c.TotalMillis != 120 {
+			t.Errorf("command stats = %+v, want one 120ms run of tasks lists", c)
+		}
+		if a := snap.APICalls["tasks"]; a == nil || a.Calls != 1 {
+			t.Errorf("api stats = %+v, want one tasks call", a)
+		}
+		data, err := os.ReadFile(textfile)
+		if err != nil {
+			t.Fatalf("expected the Prometheus textfile to be written: %v", err)
+		}
+		if !strings.Contains(string(data), `goog_api_calls_total{service="tasks"} 1`) {
+			t.Errorf("textfile = %s, want the tasks call", data)
+		}
+	})
+}
+
+func TestRunMetricsShow(t *testing.T) {
+	record := func(t *testing.T) {
+		t.Helper()
+		r := metrics.NewRecorder()
+		r.RecordCommand("mail list", 200*time.Millisecond, nil)
+		r.RecordAPICall("gmail", http.StatusOK, 80*time.Millisecond)
+		r.RecordRetry()
+		r.RecordCache("imap.raw", true)
+		if _, err := r.Flush(metrics.Path()); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+
+	t.Run("suggests enabling metrics", func(t *testing.T) {
+		setupMetricsTest(t, false, "")
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "show"}
+		cmd.SetOut(buf)
+
+		if err := runMetricsShow(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "metrics.enabled true") {
+			t.Errorf("expected a hint to enable metrics, got %q", buf.String())
+		}
+	})
+
+	t.Run("shows tables", func(t *testing.T) {
+		setupMetricsTest(t, true, "")
+		record(t)
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "show"}
+		cmd.SetOut(buf)
+
+		if err := runMetricsShow(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"COMMAND", "mail list", "200ms", "gmail", "80ms", "imap.raw", "100%", "Retries: 1"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("shows JSON", func(t *testing.T) {
+		setupMetricsTest(t, true, "")
+		record(t)
+		formatFlag = "json"
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "show"}
+		cmd.SetOut(buf)
+
+		if err := runMetricsShow(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var snap metrics.Snapshot
+		if err := json.Unmarshal(buf.Bytes(), &snap); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if snap.Commands["mail list"].Count != 1 || snap.Retries != 1 {
+			t.Errorf("snapshot = %+v, want the recorded metrics", snap)
+		}
+	})
+
+	t.Run("prints the Prometheus format", func(t *testing.T) {
+		setupMetricsTest(t, true, "")
+		record(t)
+		metricsShowPrometheus = true
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "show"}
+		cmd.SetOut(buf)
+
+		if err := runMetricsShow(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), `goog_command_runs_total{command="mail list"} 1`) {
+			t.Errorf("unexpected output:\n%s", buf.String())
+		}
+	})
+}
+
+func TestRunMetricsReset(t *testing.T) {
+	setupMetricsTest(t, true, "")
+	r := metrics.NewRecorder()
+	r.RecordRetry()
+	if _, err := r.Flush(metrics.Path()); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{Use: "reset"}
+	cmd.SetOut(new(bytes.Buffer))
+	if err := runMetricsReset(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snap, err := metrics.Load(metrics.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Retries != 0 {
+		t.Errorf("retries = %d after reset, want 0", snap.Retries)
+	}
+}
@@ -0,0 +1,196 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/qrcode"
+)
+
+// authUserMetadataKey is the account metadata key holding the account's
+// position in the browser's list of signed-in Google accounts.
+const authUserMetadataKey = "authuser"
+
+// Open command flags.
+var (
+	mailOpenPrintOnly bool
+	mailOpenQR        bool
+	calOpenPrintOnly  bool
+	calOpenQR         bool
+)
+
+// openURLFunc opens a link in the default browser. It is a variable so
+// tests can record it instead.
+var openURLFunc = auth.OpenBrowser
+
+// mailOpenCmd opens a message in Gmail on the web.
+var mailOpenCmd = &cobra.Command{
+	Use:   "open <message-id>",
+	Short: "Open a message in Gmail on the web",
+	Long: `Open a message in Gmail in the default browser.
+
+The link selects the account in use, so it opens in the right mailbox
+when the browser is signed in to several Google accounts. Google finds
+the session by the account's email address; to use the browser's
+account number instead, set it as metadata:
+
+  goog config account set work authuser=1
+
+--print-only prints the link instead, e.g. on a machine without a
+browser. --qr prints it as a QR code too, to scan it onto a phone.`,
+	Example: `  # Open a message in the browser
+  goog mail open 18c1234abcd
+
+  # Print the link
+  goog mail open 18c1234abcd --print-only
+
+  # Show a QR code to open the message on a phone
+  goog mail open 18c1234abcd --qr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailOpen,
+}
+
+// calOpenCmd opens an event in Google Calendar on the web.
+var calOpenCmd = &cobra.Command{
+	Use:   "open <event-id>",
+	Short: "Open an event in Google Calendar on the web",
+	Long: `Open an event in Google Calendar in the default browser.
+
+The event is fetched for its web link, which is then made to select the
+account in use, as for 'goog mail open'. --print-only prints the link
+instead, and --qr prints it as a QR code too.`,
+	Example: `  # Open an event in the browser
+  goog cal open abc123
+
+  # Show a QR code to open the event on a phone
+  goog cal open abc123 --qr
+
+  # Print the link of an event in another calendar
+  goog cal open abc123 --calendar team@example.com --print-only`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCalOpen,
+}
+
+func init() {
+	mailCmd.AddCommand(mailOpenCmd)
+	calCmd.AddCommand(calOpenCmd)
+
+	mailOpenCmd.Flags().BoolVar(&mailOpenPrintOnly, "print-only", false, "print the link instead of opening it")
+	mailOpenCmd.Flags().BoolVar(&mailOpenQR, "qr", false, "print the link as a QR code instead of opening it")
+	calOpenCmd.Flags().BoolVar(&calOpenPrintOnly, "print-only", false, "print the link instead of opening it")
+	calOpenCmd.Flags().BoolVar(&calOpenQR, "qr", false, "print the link as a QR code instead of opening it")
+	calOpenCmd.Flags().StringVar(&calCalendarFlag, "calendar", "primary", "calendar ID to use")
+}
+
+// browserUser returns how web links select the account in use: its
+// authuser metadata, or else its email address. It is empty when the
+// account cannot be resolved.
+func browserUser() string {
+	svc := GetDependencies().AccountService
+	if svc == nil {
+		return ""
+	}
+	acc, err := svc.ResolveAccount(accountFlag)
+	if err != nil {
+		return ""
+	}
+	if user := strings.TrimSpace(acc.Metadata[authUserMetadataKey]); user != "" {
+		return user
+	}
+	return acc.Email
+}
+
+// withAuthUser returns link with its authuser parameter set to user.
+func withAuthUser(link, user string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid link %q: %w", link, err)
+	}
+	if user != "" {
+		q := u.Query()
+		q.Set("authuser", user)
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
+
+// eventLink returns the web link of event for the account in use.
+func eventLink(event *calendar.Event) (string, error) {
+	if event.HTMLLink == "" {
+		return "", fmt.Errorf("event %s has no web link", event.ID)
+	}
+	return withAuthUser(event.HTMLLink, browserUser())
+}
+
+// printQR prints link as a QR code followed by the link itself. Accessible
+// output prints only the link, which a screen reader can read.
+func printQR(cmd *cobra.Command, link string) error {
+	if !presenter.Accessible() {
+		code, err := qrcode.Encode(link)
+		if err != nil {
+			return fmt.Errorf("failed to make a QR code: %w", err)
+		}
+		cmd.Print(code.Terminal())
+	}
+	cmd.Println(link)
+	return nil
+}
+
+// checkQRFormat rejects --qr with JSON output, which the code would break.
+func checkQRFormat(qr bool) error {
+	if qr && formatFlag == presenter.FormatJSON {
+		return fmt.Errorf("--qr cannot be used with --format json")
+	}
+	return nil
+}
+
+// openLink opens link in the browser, or prints it with printOnly, or as a
+// QR code with qr.
+func openLink(cmd *cobra.Command, link string, printOnly, qr bool) error {
+	if qr {
+		return printQR(cmd, link)
+	}
+	if printOnly {
+		cmd.Println(link)
+		return nil
+	}
+	if err := openURLFunc(link); err != nil {
+		return fmt.Errorf("failed to open a browser: %w; open %s yourself or use --print-only", err, link)
+	}
+	if !quietFlag {
+		cmd.Printf("Opened %s\n", link)
+	}
+	return nil
+}
+
+// runMailOpen handles the mail open command.
+func runMailOpen(cmd *cobra.Command, args []string) error {
+	return openLink(cmd, gmailPermalink(browserUser(), args[0]), mailOpenPrintOnly, mailOpenQR)
+}
+
+// runCalOpen handles the cal open command.
+func runCalOpen(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	event, err := repo.Get(ctx, calCalendarFlag, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+	link, err := eventLink(event)
+	if err != nil {
+		return err
+	}
+	return openLink(cmd, link, calOpenPrintOnly, calOpenQR)
+}
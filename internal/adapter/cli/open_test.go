@@ -0,0 +1,241 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupOpenTest injects account, eventRepo and a message repository
+// holding message 18c1, records opened links and resets the open and
+// --qr flags.
+func setupOpenTest(t *testing.T, account *accountuc.Account, eventRepo *MockEventRepository) (*cobra.Command, *bytes.Buffer, *[]string) {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{Account: account, TokenManager: &MockTokenManager{}},
+		RepoFactory: &MockRepositoryFactory{
+			EventRepo:   eventRepo,
+			MessageRepo: &MockMessageRepository{Message: mail.NewMessage("18c1", "t1", "ana@example.com", "Plans", "See you")},
+		},
+	})
+
+	var opened []string
+	origOpen, origMail, origCal := openURLFunc, mailOpenPrintOnly, calOpenPrintOnly
+	origCalendar, origQuiet, origFormat := calCalendarFlag, quietFlag, formatFlag
+	origMailQR, origCalQR, origReadQR, origShowQR := mailOpenQR, calOpenQR, mailReadQR, calShowQR
+	openURLFunc = func(link string) error {
+		opened = append(opened, link)
+		return nil
+	}
+	mailOpenPrintOnly, calOpenPrintOnly, calCalendarFlag, quietFlag = false, false, "primary", false
+	mailOpenQR, calOpenQR, mailReadQR, calShowQR, formatFlag = false, false, false, false, presenter.FormatPlain
+	t.Cleanup(func() {
+		ResetDependencies()
+		openURLFunc, mailOpenPrintOnly, calOpenPrintOnly = origOpen, origMail, origCal
+		calCalendarFlag, quietFlag, formatFlag = origCalendar, origQuiet, origFormat
+		mailOpenQR, calOpenQR, mailReadQR, calShowQR = origMailQR, origCalQR, origReadQR, origShowQR
+	})
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+	return cmd, buf, &opened
+}
+
+func TestRunMailOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		account *accountuc.Account
+		want    string
+	}{
+		{"email", &accountuc.Account{Alias: "work", Email: "me@example.com"},
+			"https://mail.google.com/mail/u/me@example.com/#all/18c1"},
+		{"authuser metadata", &accountuc.Account{Alias: "work", Email: "me@example.com",
+			Metadata: map[string]string{"authuser": "2"}},
+			"https://mail.google.com/mail/u/2/#all/18c1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, buf, opened := setupOpenTest(t, tt.account, &MockEventRepository{})
+
+			if err := runMailOpen(cmd, []string{"18c1"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*opened) != 1 || (*opened)[0] != tt.want {
+				t.Errorf("opened %v, want %s", *opened, tt.want)
+			}
+			if !strings.Contains(buf.String(), "Opened "+tt.want) {
+				t.Errorf("output = %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestRunMailOpen_PrintOnly(t *testing.T) {
+	cmd, buf, opened := setupOpenTest(t, &accountuc.Account{Alias: "work", Email: "me@example.com"}, &MockEventRepository{})
+	mailOpenPrintOnly = true
+
+	if err := runMailOpen(cmd, []string{"18c1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*opened) != 0 {
+		t.Errorf("--print-only opened %v", *opened)
+	}
+	if got := buf.String(); got != "https://mail.google.com/mail/u/me@example.com/#all/18c1\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestRunMailOpen_BrowserFails(t *testing.T) {
+	cmd, _, _ := setupOpenTest(t, &accountuc.Account{Alias: "work", Email: "me@example.com"}, &MockEventRepository{})
+	openURLFunc = func(string) error { return errors.New("no display") }
+
+	err := runMailOpen(cmd, []string{"18c1"})
+	if err == nil || !strings.Contains(err.Error(), "https://mail.google.com/mail/u/me@example.com/#all/18c1") {
+		t.Errorf("error = %v, want the link in it", err)
+	}
+}
+
+func TestRunCalOpen(t *testing.T) {
+	eventRepo := &MockEventRepository{Event: &calendar.Event{
+		ID:       "ev1",
+		HTMLLink: "https://www.google.com/calendar/event?eid=ZXYx",
+	}}
+	cmd, _, opened := setupOpenTest(t, &accountuc.Account{Alias: "work", Email: "me@example.com"}, eventRepo)
+
+	if err := runCalOpen(cmd, []string{"ev1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://www.google.com/calendar/event?authuser=me%40example.com&eid=ZXYx"
+	if len(*opened) != 1 || (*opened)[0] != want {
+		t.Errorf("opened %v, want %s", *opened, want)
+	}
+}
+
+func TestRunCalOpen_Errors(t *testing.T) {
+	account := &accountuc.Account{Alias: "work", Email: "me@example.com"}
+
+	t.Run("get error", func(t *testing.T) {
+		cmd, _, _ := setupOpenTest(t, account, &MockEventRepository{GetErr: errors.New("not found")})
+		if err := runCalOpen(cmd, []string{"ev1"}); err == nil || !strings.Contains(err.Error(), "failed to get event") {
+			t.Errorf("error = %v", err)
+		}
+	})
+
+	t.Run("no link", func(t *testing.T) {
+		cmd, _, opened := setupOpenTest(t, account, &MockEventRepository{Event: &calendar.Event{ID: "ev1"}})
+		if err := runCalOpen(cmd, []string{"ev1"}); err == nil || !strings.Contains(err.Error(), "no web link") {
+			t.Errorf("error = %v", err)
+		}
+		if len(*opened) != 0 {
+			t.Errorf("opened %v", *opened)
+		}
+	})
+}
+
+// qrTestEvent is an event with a web link.
+func qrTestEvent() *calendar.Event {
+	return &calendar.Event{ID: "ev1", Title: "Standup", HTMLLink: "https://www.google.com/calendar/event?eid=ZXYx"}
+}
+
+func TestOpenCommands_QR(t *testing.T) {
+	account := &accountuc.Account{Alias: "work", Email: "me@example.com"}
+	tests := []struct {
+		name string
+		run  func(*cobra.Command) error
+		want string
+	}{
+		{"mail open", func(cmd *cobra.Command) error {
+			mailOpenQR = true
+			return runMailOpen(cmd, []string{"18c1"})
+		}, "https://mail.google.com/mail/u/me@example.com/#all/18c1"},
+		{"cal open", func(cmd *cobra.Command) error {
+			calOpenQR = true
+			return runCalOpen(cmd, []string{"ev1"})
+		}, "https://www.google.com/calendar/event?authuser=me%40example.com&eid=ZXYx"},
+		{"mail show", func(cmd *cobra.Command) error {
+			mailReadQR = true
+			return runMailRead(cmd, []string{"18c1"})
+		}, "https://mail.google.com/mail/u/me@example.com/#all/18c1"},
+		{"cal show", func(cmd *cobra.Command) error {
+			calShowQR = true
+			return runCalShow(cmd, []string{"ev1"})
+		}, "https://www.google.com/calendar/event?authuser=me%40example.com&eid=ZXYx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, buf, opened := setupOpenTest(t, account, &MockEventRepository{Event: qrTestEvent()})
+
+			if err := tt.run(cmd); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(*opened) != 0 {
+				t.Errorf("--qr opened %v", *opened)
+			}
+			out := buf.String()
+			if !strings.Contains(out, "█") {
+				t.Errorf("no QR code in output:\n%s", out)
+			}
+			if !strings.HasSuffix(out, tt.want+"\n") {
+				t.Errorf("output does not end with the link %s:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestOpenCommands_QRAccessible(t *testing.T) {
+	cmd, buf, _ := setupOpenTest(t, &accountuc.Account{Alias: "work", Email: "me@example.com"}, &MockEventRepository{})
+	presenter.SetAccessible(true)
+	t.Cleanup(func() { presenter.SetAccessible(false) })
+	mailOpenQR = true
+
+	if err := runMailOpen(cmd, []string{"18c1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "https://mail.google.com/mail/u/me@example.com/#all/18c1\n" {
+		t.Errorf("output = %q, want only the link", got)
+	}
+}
+
+func TestShowCommands_QRErrors(t *testing.T) {
+	account := &accountuc.Account{Alias: "work", Email: "me@example.com"}
+	tests := []struct {
+		name string
+		run  func(*cobra.Command) error
+		want string
+	}{
+		{"mail show json", func(cmd *cobra.Command) error {
+			mailReadQR, formatFlag = true, presenter.FormatJSON
+			return runMailRead(cmd, []string{"18c1"})
+		}, "--format json"},
+		{"mail show thread", func(cmd *cobra.Command) error {
+			mailReadQR = true
+			return runMailRead(cmd, []string{"thread:1"})
+		}, "need a message ID"},
+		{"cal show attachments", func(cmd *cobra.Command) error {
+			calShowQR, calShowAttachments = true, true
+			t.Cleanup(func() { calShowAttachments = false })
+			return runCalShow(cmd, []string{"ev1"})
+		}, "--attachments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _, _ := setupOpenTest(t, account, &MockEventRepository{Event: qrTestEvent()})
+			if err := tt.run(cmd); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/i18n"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+)
+
+// outputLevel is how much a command prints, from --quiet to -vv.
+type outputLevel int
+
+// Output levels.
+const (
+	// levelQuiet prints only the IDs of results and only errors on stderr.
+	levelQuiet outputLevel = iota - 1
+	// levelNormal prints results, confirmations and warnings.
+	levelNormal
+	// levelVerbose also prints detail and progress messages on stderr.
+	levelVerbose
+	// levelDebug also traces every API request on stderr.
+	levelDebug
+)
+
+// tableWriter is where commands write the tables they align themselves:
+// a header line, then a line per row, with tab-separated cells.
+type tableWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// newTableWriter returns a tableWriter aligning its columns on w, or, for
+// accessible output, writing each row as a block of "Key: value" lines.
+func newTableWriter(w io.Writer) tableWriter {
+	if presenter.Accessible() {
+		return presenter.NewBlockWriter(w)
+	}
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// outputController interprets --quiet and --verbose for every command, so
+// that the levels mean the same thing everywhere. It reads the flags when
+// asked, so per-command settings and tests that set them are honoured.
+type outputController struct{}
+
+// output is the output controller shared by all commands.
+var output outputController
+
+// Level returns the output level chosen with --quiet and --verbose.
+func (outputController) Level() outputLevel {
+	if quietFlag {
+		return levelQuiet
+	}
+	return outputLevel(min(verboseFlag, int(levelDebug)))
+}
+
+// Quiet reports whether only IDs and errors are printed.
+func (o outputController) Quiet() bool { return o.Level() == levelQuiet }
+
+// Verbose reports whether detail messages are printed.
+func (o outputController) Verbose() bool { return o.Level() >= levelVerbose }
+
+// Debug reports whether API requests are traced.
+func (o outputController) Debug() bool { return o.Level() >= levelDebug }
+
+// Warnf prints a warning on stderr unless output is quiet.
+func (o outputController) Warnf(cmd *cobra.Command, format string, args ...any) {
+	if !o.Quiet() {
+		cmd.PrintErrf(i18n.T("Warning:")+" "+i18n.T(format)+"\n", args...)
+	}
+}
+
+// Verbosef prints a detail message on stderr at -v and above.
+func (o outputController) Verbosef(cmd *cobra.Command, format string, args ...any) {
+	if o.Verbose() {
+		cmd.PrintErrf(format+"\n", args...)
+	}
+}
+
+// Debugf prints a debugging message on stderr at -vv.
+func (o outputController) Debugf(cmd *cobra.Command, format string, args ...any) {
+	if o.Debug() {
+		cmd.PrintErrf("debug: "+format+"\n", args...)
+	}
+}
+
+// Incomplete warns about each item a listing could only return in part
+// and, when strict, fails the command so scripts notice instead of
+// trusting the partial data. It is called after the results are printed.
+func (o outputController) Incomplete(cmd *cobra.Command, noun string, warnings []string, strict bool) error {
+	for _, w := range warnings {
+		o.Warnf(cmd, "incomplete result, %s", w)
+	}
+	if strict && len(warnings) > 0 {
+		return fmt.Errorf("incomplete results: %d %s could not be fetched in full", len(warnings), noun)
+	}
+	return nil
+}
+
+// Renderer returns the renderer for format at the current level: quiet
+// output replaces the human formats, table and plain, with IDs only, while
+// machine formats such as JSON are kept.
+func (o outputController) Renderer(format string) presenter.Renderer {
+	if o.Quiet() && (format == presenter.FormatTable || format == presenter.FormatPlain) {
+		return presenter.NewIDPresenter()
+	}
+	return presenter.New(format)
+}
+
+// traceBase is the transport requests were sent through before setupOutput
+// wrapped it to trace them, restored on the next run.
+var (
+	traceBase    http.RoundTripper
+	traceEnabled bool
+)
+
+// setupOutput checks --quiet against --verbose and, at -vv, traces every
+// API request on stderr.
+func setupOutput(cmd *cobra.Command) error {
+	if traceEnabled {
+		repository.SetTransport(traceBase)
+		traceEnabled = false
+	}
+	if quietFlag && verboseFlag > 0 {
+		return errors.New("--quiet and --verbose cannot be used together")
+	}
+	if output.Debug() {
+		traceBase = repository.Transport()
+		traceEnabled = true
+		repository.SetTransport(&traceTransport{base: traceBase, cmd: cmd})
+	}
+	return nil
+}
+
+// traceTransport prints each request and its outcome. Only the method,
+// host and path are printed, as query strings can hold API keys.
+type traceTransport struct {
+	base http.RoundTripper
+	cmd  *cobra.Command
+}
+
+// RoundTrip sends req and prints how it went.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		output.Debugf(t.cmd, "%s %s%s failed after %s: %v", req.Method, req.URL.Host, req.URL.Path, elapsed, err)
+		return nil, err
+	}
+	output.Debugf(t.cmd, "%s %s%s -> %d (%s)", req.Method, req.URL.Host, req.URL.Path, resp.StatusCode, elapsed)
+	return resp, nil
+}
+
+// catchBrokenPipe makes writes to a closed stdout pipe fail with EPIPE
+// instead of killing the process, so a command streaming output can stop
+// fetching and exit cleanly, e.g. when piped into head. The returned
+// function restores the default.
+func catchBrokenPipe() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGPIPE)
+	return func() { signal.Stop(signals) }
+}
+
+// isBrokenPipe reports whether err comes from writing to a pipe whose
+// reader has gone.
+func isBrokenPipe(err error) bool {
+	return errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// withVerbosity sets --quiet and --verbose for the rest of the test.
+func withVerbosity(t *testing.T, quiet bool, verbose int) {
+	t.Helper()
+	origQuiet, origVerbose := quietFlag, verboseFlag
+	quietFlag, verboseFlag = quiet, verbose
+	t.Cleanup(func() { quietFlag, verboseFlag = origQuiet, origVerbose })
+}
+
+func TestOutputController_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		quiet   bool
+		verbose int
+		want    outputLevel
+	}{
+		{"default", false, 0, levelNormal},
+		{"quiet", true, 0, levelQuiet},
+		{"verbose", false, 1, levelVerbose},
+		{"debug", false, 2, levelDebug},
+		{"more v than levels", false, 5, levelDebug},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withVerbosity(t, tt.quiet, tt.verbose)
+			if got := output.Level(); got != tt.want {
+				t.Errorf("Level() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputController_Messages(t *testing.T) {
+	tests := []struct {
+		name    string
+		quiet   bool
+		verbose int
+		want    []string
+		notWant []string
+	}{
+		{"quiet prints nothing", true, 0, nil, []string{"Warning", "detail", "debug"}},
+		{"normal prints warnings", false, 0, []string{"Warning: careful"}, []string{"detail", "debug"}},
+		{"verbose prints detail", false, 1, []string{"Warning: careful", "detail 1"}, []string{"debug"}},
+		{"debug prints everything", false, 2, []string{"Warning: careful", "detail 1", "debug: trace"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withVerbosity(t, tt.quiet, tt.verbose)
+			cmd := &cobra.Command{Use: "test"}
+			var errOut bytes.Buffer
+			cmd.SetErr(&errOut)
+
+			output.Warnf(cmd, "careful")
+			output.Verbosef(cmd, "detail %d", 1)
+			output.Debugf(cmd, "trace")
+
+			for _, s := range tt.want {
+				if !strings.Contains(errOut.String(), s) {
+					t.Errorf("expected %q in:\n%s", s, errOut.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(errOut.String(), s) {
+					t.Errorf("unexpected %q in:\n%s", s, errOut.String())
+				}
+			}
+		})
+	}
+}
+
+func TestOutputController_Renderer(t *testing.T) {
+	msgs := []*mail.Message{{ID: "m1", Subject: "Hello"}, {ID: "m2", Subject: "World"}}
+
+	withVerbosity(t, true, 0)
+	for _, format := range []string{presenter.FormatTable, presenter.FormatPlain} {
+		if got := output.Renderer(format).RenderMessages(msgs); got != "m1\nm2" {
+			t.Errorf("quiet %s output = %q, want IDs only", format, got)
+		}
+	}
+	if got := output.Renderer(presenter.FormatJSON).RenderMessages(msgs); !strings.Contains(got, `"Subject": "Hello"`) {
+		t.Errorf("quiet JSON output should be unchanged, got %s", got)
+	}
+
+	withVerbosity(t, false, 0)
+	if got := output.Renderer(presenter.FormatTable).RenderMessages(msgs); !strings.Contains(got, "Hello") {
+		t.Errorf("expected a table, got %s", got)
+	}
+}
+
+func TestNewTableWriter(t *testing.T) {
+	write := func() string {
+		var buf bytes.Buffer
+		w := newTableWriter(&buf)
+		_, _ = w.Write([]byte("NAME\tCOMMAND\ninbox\tgoog mail list\n"))
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	if got := write(); got != "NAME   COMMAND\ninbox  goog mail list\n" {
+		t.Errorf("aligned table = %q", got)
+	}
+
+	presenter.SetAccessible(true)
+	t.Cleanup(func() { presenter.SetAccessible(false) })
+	if got := write(); got != "Name: inbox\nCommand: goog mail list\n" {
+		t.Errorf("accessible table = %q", got)
+	}
+}
+
+func TestSetupOutput(t *testing.T) {
+	origTransport := repository.Transport()
+	t.Cleanup(func() {
+		traceEnabled = false
+		repository.SetTransport(origTransport)
+	})
+
+	t.Run("quiet and verbose conflict", func(t *testing.T) {
+		withVerbosity(t, true, 1)
+		if err := setupOutput(&cobra.Command{Use: "test"}); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+			t.Errorf("expected a conflict error, got %v", err)
+		}
+	})
+
+	t.Run("debug traces requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		withVerbosity(t, false, 2)
+		cmd := &cobra.Command{Use: "test"}
+		var errOut bytes.Buffer
+		cmd.SetErr(&errOut)
+		if err := setupOutput(cmd); err != nil {
+			t.Fatal(err)
+		}
+
+		client := &http.Client{Transport: repository.Transport()}
+		resp, err := client.Get(server.URL + "/gmail/v1/users/me/messages?key=secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+
+		got := errOut.String()
+		if !strings.Contains(got, "debug: GET ") || !strings.Contains(got, "/gmail/v1/users/me/messages -> 404") {
+			t.Errorf("unexpected trace: %q", got)
+		}
+		if strings.Contains(got, "secret") {
+			t.Errorf("trace printed the query string: %q", got)
+		}
+	})
+
+	t.Run("trace is removed on the next run", func(t *testing.T) {
+		withVerbosity(t, false, 0)
+		if err := setupOutput(&cobra.Command{Use: "test"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := repository.Transport().(*traceTransport); ok {
+			t.Error("expected the trace transport to be removed")
+		}
+	})
+}
@@ -0,0 +1,142 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/cassette"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+	"golang.org/x/oauth2"
+)
+
+// Environment variables that enable recording and replaying API traffic.
+const (
+	EnvRecord = "GOOG_RECORD"
+	EnvReplay = "GOOG_REPLAY"
+)
+
+// replayAlias is the account alias reported while replaying.
+const replayAlias = "replay"
+
+// recordBase sends recorded requests; nil uses http.DefaultTransport. It is
+// a variable so tests can record without reaching Google.
+var recordBase http.RoundTripper
+
+// errReplayReadOnly is returned by account changes while replaying.
+var errReplayReadOnly = errors.New("accounts cannot be changed while " + EnvReplay + " is set")
+
+// setupRecordReplay installs a recording or replaying transport when
+// GOOG_RECORD or GOOG_REPLAY is set. Replay also replaces the account
+// service so no login, config or keyring is needed.
+func setupRecordReplay(cmd *cobra.Command) error {
+	recordPath, replayPath := os.Getenv(EnvRecord), os.Getenv(EnvReplay)
+	switch {
+	case recordPath != "" && replayPath != "":
+		return fmt.Errorf("%s and %s cannot both be set", EnvRecord, EnvReplay)
+
+	case replayPath != "":
+		replayer, err := cassette.NewReplayer(replayPath)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", EnvReplay, err)
+		}
+		repository.SetTransport(replayer)
+		deps := GetDependencies()
+		SetDependencies(&Dependencies{
+			AccountService: &replayAccountService{email: replayer.Account()},
+			RepoFactory:    deps.RepoFactory,
+		})
+
+	case recordPath != "":
+		var email string
+		if acc, err := GetDependencies().AccountService.ResolveAccount(accountFlag); err == nil {
+			email = acc.Email
+		}
+		repository.SetTransport(cassette.NewRecorder(recordPath, email, recordBase))
+		output.Verbosef(cmd, "Recording API traffic to %s", recordPath)
+	}
+	return nil
+}
+
+// replayAccountService is the account service used while replaying. It
+// acts as the single account the cassette was recorded as.
+type replayAccountService struct {
+	email string
+}
+
+func (s *replayAccountService) account() *accountuc.Account {
+	return &accountuc.Account{Alias: replayAlias, Email: s.email, IsDefault: true}
+}
+
+// List returns the replay account.
+func (s *replayAccountService) List() ([]*accountuc.Account, error) {
+	return []*accountuc.Account{s.account()}, nil
+}
+
+// Add is not supported while replaying.
+func (s *replayAccountService) Add(ctx context.Context, alias string, scopes []string) (*accountuc.Account, error) {
+	return nil, errReplayReadOnly
+}
+
+// Remove is not supported while replaying.
+func (s *replayAccountService) Remove(alias string) error {
+	return errReplayReadOnly
+}
+
+// RemoveAccount is not supported while replaying.
+func (s *replayAccountService) RemoveAccount(ctx context.Context, alias string, revoke bool) (*accountuc.RemovalReport, error) {
+	return nil, errReplayReadOnly
+}
+
+// Switch is not supported while replaying.
+func (s *replayAccountService) Switch(alias string) error {
+	return errReplayReadOnly
+}
+
+// Rename is not supported while replaying.
+func (s *replayAccountService) Rename(oldAlias, newAlias string) error {
+	return errReplayReadOnly
+}
+
+// ResolveAccount returns the replay account whatever --account says.
+func (s *replayAccountService) ResolveAccount(flagValue string) (*accountuc.Account, error) {
+	return s.account(), nil
+}
+
+// GetTokenManager returns a token manager handing out placeholder tokens.
+func (s *replayAccountService) GetTokenManager() TokenManager {
+	return replayTokenManager{}
+}
+
+// replayToken is the placeholder token used while replaying. Requests never
+// leave the process, so it is not checked.
+var replayToken = &oauth2.Token{AccessToken: cassette.Redacted, TokenType: "Bearer"}
+
+// replayTokenManager is the token manager used while replaying.
+type replayTokenManager struct{}
+
+// GetTokenSource returns a source of the placeholder token.
+func (replayTokenManager) GetTokenSource(ctx context.Context, alias string) (oauth2.TokenSource, error) {
+	return oauth2.StaticTokenSource(replayToken), nil
+}
+
+// GetTokenInfo reports a valid placeholder token.
+func (replayTokenManager) GetTokenInfo(alias string) (*auth.TokenInfo, error) {
+	return &auth.TokenInfo{Account: alias, HasToken: true, TokenType: replayToken.TokenType}, nil
+}
+
+// RefreshToken returns the placeholder token.
+func (replayTokenManager) RefreshToken(ctx context.Context, alias string, cfg *oauth2.Config) (*oauth2.Token, error) {
+	return replayToken, nil
+}
+
+// GetGrantedScopes reports no scopes; replay does not check them.
+func (replayTokenManager) GetGrantedScopes(alias string) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,187 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/cassette"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// fakeTasksAPI answers every request with a single task list and counts
+// the requests it sees.
+type fakeTasksAPI struct {
+	calls int
+}
+
+func (f *fakeTasksAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"items":[{"id":"L1","title":"Groceries"}]}`)),
+		Request:    req,
+	}, nil
+}
+
+// setupRecordReplayTest isolates the environment, transport and
+// dependencies changed by setupRecordReplay.
+func setupRecordReplayTest(t *testing.T) {
+	t.Helper()
+	t.Setenv(EnvRecord, "")
+	t.Setenv(EnvReplay, "")
+	origBase, origFormat, origAccount := recordBase, formatFlag, accountFlag
+	formatFlag = "table"
+	accountFlag = ""
+	t.Cleanup(func() {
+		recordBase, formatFlag, accountFlag = origBase, origFormat, origAccount
+		repository.SetTransport(nil)
+		ResetDependencies()
+	})
+}
+
+func TestSetupRecordReplay(t *testing.T) {
+	t.Run("does nothing by default", func(t *testing.T) {
+		setupRecordReplayTest(t)
+		mock := &MockAccountService{}
+		SetDependencies(&Dependencies{AccountService: mock})
+
+		if err := setupRecordReplay(&cobra.Command{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if GetDependencies().AccountService != mock {
+			t.Error("account service should not be replaced")
+		}
+	})
+
+	t.Run("rejects both variables", func(t *testing.T) {
+		setupRecordReplayTest(t)
+		t.Setenv(EnvRecord, "a.json")
+		t.Setenv(EnvReplay, "b.json")
+
+		err := setupRecordReplay(&cobra.Command{})
+		if err == nil || !strings.Contains(err.Error(), "cannot both be set") {
+			t.Errorf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		setupRecordReplayTest(t)
+		t.Setenv(EnvReplay, filepath.Join(t.TempDir(), "missing.json"))
+
+		err := setupRecordReplay(&cobra.Command{})
+		if err == nil || !strings.Contains(err.Error(), EnvReplay) {
+			t.Errorf("expected load error, got %v", err)
+		}
+	})
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	t.Run("record", func(t *testing.T) {
+		setupRecordReplayTest(t)
+		t.Setenv(EnvRecord, path)
+		api := &fakeTasksAPI{}
+		recordBase = api
+		SetDependencies(&Dependencies{
+			AccountService: &MockAccountService{
+				Account:      &accountuc.Account{Alias: "work", Email: "me@example.com"},
+				TokenManager: &MockTokenManager{},
+			},
+			RepoFactory: &defaultRepositoryFactory{},
+		})
+
+		cmd := &cobra.Command{Use: "test"}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		if err := setupRecordReplay(cmd); err != nil {
+			t.Fatalf("setupRecordReplay failed: %v", err)
+		}
+		if err := runTasksLists(cmd, nil); err != nil {
+			t.Fatalf("runTasksLists failed: %v", err)
+		}
+		if api.calls != 1 || !strings.Contains(buf.String(), "Groceries") {
+			t.Fatalf("expected the live response, got %d calls:\n%s", api.calls, buf.String())
+		}
+
+		c, err := cassette.Load(path)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if c.Account != "me@example.com" || len(c.Interactions) != 1 {
+			t.Errorf("unexpected cassette: %+v", c)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		setupRecordReplayTest(t)
+		t.Setenv(EnvReplay, path)
+		ResetDependencies()
+
+		cmd := &cobra.Command{Use: "test"}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		if err := setupRecordReplay(cmd); err != nil {
+			t.Fatalf("setupRecordReplay failed: %v", err)
+		}
+
+		acc, err := GetDependencies().AccountService.ResolveAccount("other")
+		if err != nil || acc.Email != "me@example.com" || acc.Alias != replayAlias {
+			t.Errorf("ResolveAccount() = %+v, %v", acc, err)
+		}
+		if err := runTasksLists(cmd, nil); err != nil {
+			t.Fatalf("runTasksLists failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), "Groceries") {
+			t.Errorf("expected replayed task list, got:\n%s", buf.String())
+		}
+
+		err = runTasksLists(cmd, nil)
+		if !errors.Is(err, cassette.ErrNoInteraction) {
+			t.Errorf("expected ErrNoInteraction once the cassette is used up, got %v", err)
+		}
+	})
+}
+
+func TestReplayAccountService(t *testing.T) {
+	svc := &replayAccountService{email: "me@example.com"}
+
+	accounts, err := svc.List()
+	if err != nil || len(accounts) != 1 || accounts[0].Email != "me@example.com" {
+		t.Errorf("List() = %v, %v", accounts, err)
+	}
+	if _, err := svc.Add(context.Background(), "new", nil); !errors.Is(err, errReplayReadOnly) {
+		t.Errorf("Add() error = %v, want errReplayReadOnly", err)
+	}
+	for name, err := range map[string]error{
+		"Remove": svc.Remove("a"),
+		"Switch": svc.Switch("a"),
+		"Rename": svc.Rename("a", "b"),
+	} {
+		if !errors.Is(err, errReplayReadOnly) {
+			t.Errorf("%s() error = %v, want errReplayReadOnly", name, err)
+		}
+	}
+
+	tm := svc.GetTokenManager()
+	ts, err := tm.GetTokenSource(context.Background(), replayAlias)
+	if err != nil {
+		t.Fatalf("GetTokenSource failed: %v", err)
+	}
+	if tok, err := ts.Token(); err != nil || !tok.Valid() {
+		t.Errorf("Token() = %v, %v; want a valid token", tok, err)
+	}
+	if info, err := tm.GetTokenInfo(replayAlias); err != nil || !info.HasToken {
+		t.Errorf("GetTokenInfo() = %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,47 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// setupRetries gives the command the retry budget set in api.retry_budget
+// and reports each API retry: on stderr at -vv, and in the metrics when
+// they are recorded. It runs after setupMetrics.
+func setupRetries(cmd *cobra.Command) {
+	repository.SetRetryBudget(retryBudget())
+	rec := metricsRecorder
+	repository.SetRetryHook(func(r repository.Retry) {
+		output.Debugf(cmd, "retry %d in %s after: %v", r.Attempt, r.Wait.Round(time.Millisecond), r.Err)
+		if rec != nil {
+			rec.RecordRetry()
+		}
+	})
+}
+
+// retryBudget returns api.retry_budget, or its default without a config
+// file.
+func retryBudget() int {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return config.DefaultRetryBudget
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return config.DefaultRetryBudget
+	}
+	return cfg.API.RetryBudget
+}
+
+// finishRetries prints how many API retries the command made at -vv and
+// removes the retry hook.
+func finishRetries(cmd *cobra.Command) {
+	repository.SetRetryHook(nil)
+	if cmd != nil {
+		output.Debugf(cmd, "%d API retries", repository.Retries())
+	}
+}
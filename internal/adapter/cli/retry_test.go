@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/metrics"
+	"golang.org/x/oauth2"
+)
+
+// flakyTasksAPI fails every request with 503 Service Unavailable.
+type flakyTasksAPI struct {
+	calls int
+}
+
+func (f *flakyTasksAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"code":503,"message":"backend error"}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestSetupRetries(t *testing.T) {
+	setupMetricsTest(t, true, "")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.API.RetryBudget = 1
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	origVerbose := verboseFlag
+	verboseFlag = 2
+	t.Cleanup(func() {
+		verboseFlag = origVerbose
+		repository.SetRetryBudget(0)
+		repository.SetRetryHook(nil)
+	})
+
+	api := &flakyTasksAPI{}
+	repository.SetTransport(api)
+
+	buf := new(bytes.Buffer)
+	root := &cobra.Command{Use: "goog"}
+	list := &cobra.Command{Use: "lists"}
+	root.AddCommand(list)
+	list.SetErr(buf)
+
+	setupMetrics(list)
+	setupRetries(list)
+
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	repo, err := repository.NewGTasksRepository(ctx, ts)
+	if err != nil {
+		t.Fatalf("NewGTasksRepository failed: %v", err)
+	}
+	if _, err := repository.NewGTaskListRepository(repo).List(ctx); err == nil {
+		t.Fatal("expected the failing API to return an error")
+	}
+	if api.calls != 2 {
+		t.Errorf("API called %d times, want 2 with a budget of one retry", api.calls)
+	}
+
+	finishRetries(list)
+	finishMetrics(list, 0, nil)
+
+	output := buf.String()
+	for _, want := range []string{"debug: retry 1 in ", "debug: 1 API retries"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected stderr to contain %q, got:\n%s", want, output)
+		}
+	}
+	snap, err := metrics.Load(metrics.Path())
+	if err != nil {
+		t.Fatalf("failed to load metrics: %v", err)
+	}
+	if snap.Retries != 1 || snap.Commands["lists"].Retries != 1 {
+		t.Errorf("metrics = %+v, want one retry charged to lists", snap)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	setupMetricsTest(t, false, "")
+	if got := retryBudget(); got != config.DefaultRetryBudget {
+		t.Errorf("retryBudget() = %d, want the default %d", got, config.DefaultRetryBudget)
+	}
+}
@@ -0,0 +1,296 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/usecase/review"
+)
+
+// Command flags for the review command.
+var (
+	reviewWeek       bool
+	reviewSince      string
+	reviewAhead      string
+	reviewCalendar   string
+	reviewMaxThreads int
+)
+
+// reviewCmd prints a status report of the last week.
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Report on your week for a status update",
+	Long: `Report on the past week as Markdown, ready to paste into a status
+update. The report has:
+
+  - the number of messages received and sent
+  - the inbox threads awaiting your reply: those whose last message is
+    not from you, most recent first (promotions and social updates are
+    left out)
+  - the meetings attended, with their total time (all-day, cancelled and
+    declined events are left out)
+  - the open tasks of every list due in the next --ahead days, and those
+    overdue
+
+--week reviews the last 7 days, which is the default. --since reviews
+another period instead, such as 14d or 2w. Counts stop at 10000
+messages.`,
+	Example: `  # Review the last week
+  goog review --week
+
+  # Review the last two weeks, with deadlines of the next 14 days
+  goog review --since 2w --ahead 14d
+
+  # Copy the report to the clipboard (macOS)
+  goog review --week | pbcopy
+
+  # As JSON
+  goog review --week --format json`,
+	Args: cobra.NoArgs,
+	RunE: runReview,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().BoolVar(&reviewWeek, "week", false, "review the last 7 days (the default)")
+	reviewCmd.Flags().StringVar(&reviewSince, "since", "", "review another period or start date (e.g. 14d, 2w, last monday)")
+	reviewCmd.Flags().StringVar(&reviewAhead, "ahead", "7d", "how far ahead to list deadlines (e.g. 7d, 2w, friday)")
+	reviewCmd.Flags().StringVar(&reviewCalendar, "calendar", "primary", "calendar ID to read meetings from")
+	reviewCmd.Flags().IntVar(&reviewMaxThreads, "max-threads", review.DefaultMaxThreads, "maximum number of threads awaiting a reply")
+}
+
+// reviewThreadJSON is the JSON representation of a thread awaiting a reply.
+type reviewThreadJSON struct {
+	ThreadID string `json:"thread_id"`
+	Subject  string `json:"subject"`
+	From     string `json:"from"`
+	Latest   string `json:"latest"`
+	Messages int    `json:"messages"`
+	Snippet  string `json:"snippet,omitempty"`
+}
+
+// reviewMeetingJSON is the JSON representation of a meeting attended.
+type reviewMeetingJSON struct {
+	EventID string  `json:"event_id"`
+	Title   string  `json:"title"`
+	Start   string  `json:"start"`
+	End     string  `json:"end"`
+	Hours   float64 `json:"hours"`
+}
+
+// reviewDeadlineJSON is the JSON representation of a task deadline.
+type reviewDeadlineJSON struct {
+	TaskID string `json:"task_id"`
+	Title  string `json:"title"`
+	List   string `json:"list"`
+	Due    string `json:"due"`
+}
+
+// reviewJSON is the JSON output of review.
+type reviewJSON struct {
+	Since          string               `json:"since"`
+	Until          string               `json:"until"`
+	Received       int                  `json:"received"`
+	Sent           int                  `json:"sent"`
+	Awaiting       []reviewThreadJSON   `json:"awaiting_reply"`
+	Meetings       []reviewMeetingJSON  `json:"meetings"`
+	MeetingHours   float64              `json:"meeting_hours"`
+	DeadlinesUntil string               `json:"deadlines_until"`
+	Deadlines      []reviewDeadlineJSON `json:"deadlines"`
+	Overdue        []reviewDeadlineJSON `json:"overdue"`
+}
+
+// runReview handles the review command.
+func runReview(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if reviewWeek && reviewSince != "" {
+		return fmt.Errorf("--week and --since cannot be used together")
+	}
+	now := time.Now()
+	period := review.DefaultPeriod
+	if reviewSince != "" {
+		since, err := dateparse.ParseSinceAt(reviewSince, now)
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		period = now.Sub(since)
+	}
+	until, err := dateparse.ParseUntilAt(reviewAhead, now)
+	if err != nil {
+		return fmt.Errorf("invalid --ahead value: %w", err)
+	}
+	ahead := until.Sub(now)
+	if reviewMaxThreads <= 0 {
+		return fmt.Errorf("--max-threads must be positive")
+	}
+
+	messageRepo, self, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	threadRepo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	eventRepo, err := getEventRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	taskListRepo, err := getTaskListRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	taskRepo, err := getTaskRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := review.NewService(messageRepo, threadRepo, eventRepo, taskListRepo, taskRepo).Review(ctx, review.Options{
+		CalendarID: reviewCalendar,
+		Period:     period,
+		Horizon:    ahead,
+		MaxThreads: reviewMaxThreads,
+		Self:       self,
+	})
+	if err != nil {
+		return err
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(reviewToJSON(report), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode review: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+	cmd.Print(renderReviewMarkdown(report))
+	return nil
+}
+
+// reviewToJSON converts a report to its JSON representation.
+func reviewToJSON(r *review.Report) reviewJSON {
+	out := reviewJSON{
+		Since:          r.Since.Format(time.RFC3339),
+		Until:          r.Until.Format(time.RFC3339),
+		Received:       r.Received,
+		Sent:           r.Sent,
+		Awaiting:       []reviewThreadJSON{},
+		Meetings:       []reviewMeetingJSON{},
+		MeetingHours:   roundHours(r.MeetingTime.Hours()),
+		DeadlinesUntil: r.DeadlinesUntil.Format("2006-01-02"),
+		Deadlines:      reviewDeadlinesToJSON(r.Deadlines),
+		Overdue:        reviewDeadlinesToJSON(r.Overdue),
+	}
+	for _, t := range r.Awaiting {
+		out.Awaiting = append(out.Awaiting, reviewThreadJSON{
+			ThreadID: t.ThreadID,
+			Subject:  t.Subject,
+			From:     t.From.String(),
+			Latest:   t.Latest.Format(time.RFC3339),
+			Messages: t.Messages,
+			Snippet:  t.Snippet,
+		})
+	}
+	for _, e := range r.Meetings {
+		out.Meetings = append(out.Meetings, reviewMeetingJSON{
+			EventID: e.ID,
+			Title:   e.Title,
+			Start:   e.Start.Format(time.RFC3339),
+			End:     e.End.Format(time.RFC3339),
+			Hours:   roundHours(e.Duration().Hours()),
+		})
+	}
+	return out
+}
+
+// reviewDeadlinesToJSON converts deadlines to their JSON representation.
+func reviewDeadlinesToJSON(deadlines []*review.Deadline) []reviewDeadlineJSON {
+	out := []reviewDeadlineJSON{}
+	for _, d := range deadlines {
+		out = append(out, reviewDeadlineJSON{
+			TaskID: d.Task.ID,
+			Title:  d.Task.Title,
+			List:   d.List,
+			Due:    d.Due.Format("2006-01-02"),
+		})
+	}
+	return out
+}
+
+// renderReviewMarkdown renders a report as a Markdown document.
+func renderReviewMarkdown(r *review.Report) string {
+	locale := presenter.CurrentLocale()
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Review: %s - %s\n\n", locale.Date(r.Since.Local()), locale.Date(r.Until.Local()))
+
+	sb.WriteString("## Mail\n\n")
+	fmt.Fprintf(&sb, "- **Received:** %s\n", reviewCount(r.Received))
+	fmt.Fprintf(&sb, "- **Sent:** %s\n", reviewCount(r.Sent))
+
+	fmt.Fprintf(&sb, "\n## Awaiting reply (%d)\n\n", len(r.Awaiting))
+	if len(r.Awaiting) == 0 {
+		sb.WriteString("Nothing is awaiting a reply.\n")
+	}
+	for _, t := range r.Awaiting {
+		subject := t.Subject
+		if subject == "" {
+			subject = "(no subject)"
+		}
+		from := t.From.Name
+		if from == "" {
+			from = t.From.Email
+		}
+		fmt.Fprintf(&sb, "- **%s** from %s, %s\n", subject, from, locale.Date(t.Latest.Local()))
+	}
+
+	fmt.Fprintf(&sb, "\n## Meetings attended (%d, %s h)\n\n", len(r.Meetings), formatReviewHours(r.MeetingTime))
+	if len(r.Meetings) == 0 {
+		sb.WriteString("No meetings.\n")
+	}
+	for _, e := range r.Meetings {
+		fmt.Fprintf(&sb, "- %s %s: %s (%s h)\n", locale.Date(e.Start.Local()), locale.Time(e.Start.Local()), e.Title, formatReviewHours(e.Duration()))
+	}
+
+	fmt.Fprintf(&sb, "\n## Upcoming deadlines (to %s)\n\n", locale.Date(r.DeadlinesUntil))
+	if len(r.Deadlines) == 0 {
+		sb.WriteString("No deadlines.\n")
+	}
+	writeReviewDeadlines(&sb, r.Deadlines)
+	if len(r.Overdue) > 0 {
+		fmt.Fprintf(&sb, "\n### Overdue (%d)\n\n", len(r.Overdue))
+		writeReviewDeadlines(&sb, r.Overdue)
+	}
+	return sb.String()
+}
+
+// writeReviewDeadlines writes deadlines as a Markdown task list.
+func writeReviewDeadlines(sb *strings.Builder, deadlines []*review.Deadline) {
+	locale := presenter.CurrentLocale()
+	for _, d := range deadlines {
+		fmt.Fprintf(sb, "- [ ] %s: %s (%s)\n", locale.Date(d.Due), d.Task.Title, d.List)
+	}
+}
+
+// reviewCount formats a message count, marking one that reached the cap.
+func reviewCount(n int) string {
+	if n >= review.MaxCount {
+		return fmt.Sprintf("%d+", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// formatReviewHours formats a duration as hours with up to one decimal.
+func formatReviewHours(d time.Duration) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", d.Hours()), ".0")
+}
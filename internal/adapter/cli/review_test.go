@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// setupReviewTest injects mail, a thread awaiting a reply, a meeting and a
+// task due tomorrow, and resets the review flags.
+func setupReviewTest(t *testing.T) (*bytes.Buffer, *cobra.Command) {
+	t.Helper()
+	now := time.Now()
+	due := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			MessageRepo: &MockMessageRepository{Messages: []*mail.Message{{ID: "m1"}, {ID: "m2"}}},
+			ThreadRepo: &MockThreadRepository{
+				Threads: []*mail.Thread{{ID: "t1"}},
+				Thread: &mail.Thread{ID: "t1", Messages: []*mail.Message{
+					{From: mail.Address{Email: "me@example.com"}, Subject: "Launch plan", Date: now.Add(-48 * time.Hour)},
+					{From: mail.Address{Name: "Lea", Email: "lea@example.com"}, Subject: "Re: Launch plan", Date: now.Add(-24 * time.Hour)},
+				}},
+			},
+			EventRepo: &MockEventRepository{Events: []*calendar.Event{
+				{ID: "ev1", Title: "Roadmap review", Start: now.Add(-26 * time.Hour), End: now.Add(-24*time.Hour - 30*time.Minute)},
+			}},
+			TaskListRepo: &MockTaskListRepository{Lists: []*domaintasks.TaskList{{ID: "l1", Title: "Work"}}},
+			TaskRepo: &MockTaskRepository{Tasks: &domaintasks.ListResult[*domaintasks.Task]{Items: []*domaintasks.Task{
+				{ID: "k1", Title: "Send slides", Due: &due},
+			}}},
+		},
+	})
+
+	origWeek, origSince, origAhead, origCalendar := reviewWeek, reviewSince, reviewAhead, reviewCalendar
+	origMax, origFormat := reviewMaxThreads, formatFlag
+	reviewWeek, reviewSince, reviewAhead, reviewCalendar = true, "", "7d", "primary"
+	reviewMaxThreads, formatFlag = 10, presenter.FormatPlain
+	t.Cleanup(func() {
+		ResetDependencies()
+		reviewWeek, reviewSince, reviewAhead, reviewCalendar = origWeek, origSince, origAhead, origCalendar
+		reviewMaxThreads, formatFlag = origMax, origFormat
+	})
+
+	cmd := &cobra.Command{Use: "test"}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	return buf, cmd
+}
+
+func TestRunReview(t *testing.T) {
+	buf, cmd := setupReviewTest(t)
+
+	if err := runReview(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Review: ",
+		"## Mail\n\n- **Received:** 2\n- **Sent:** 2\n",
+		"## Awaiting reply (1)\n\n- **Launch plan** from Lea, ",
+		"## Meetings attended (1, 1.5 h)\n\n- ",
+		": Roadmap review (1.5 h)\n",
+		"## Upcoming deadlines (to ",
+		": Send slides (Work)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Overdue") {
+		t.Errorf("unexpected overdue section:\n%s", out)
+	}
+}
+
+func TestRunReview_JSON(t *testing.T) {
+	buf, cmd := setupReviewTest(t)
+	formatFlag = presenter.FormatJSON
+
+	if err := runReview(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got reviewJSON
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Received != 2 || len(got.Awaiting) != 1 || got.Awaiting[0].From != "Lea <lea@example.com>" {
+		t.Errorf("unexpected mail: %+v", got)
+	}
+	if len(got.Meetings) != 1 || got.MeetingHours != 1.5 {
+		t.Errorf("unexpected meetings: %+v", got.Meetings)
+	}
+	if len(got.Deadlines) != 1 || got.Deadlines[0].List != "Work" || got.Overdue == nil {
+		t.Errorf("unexpected deadlines: %+v, overdue %+v", got.Deadlines, got.Overdue)
+	}
+}
+
+func TestRunReview_FlagErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		want  string
+	}{
+		{"week and since", func() { reviewSince = "14d" }, "cannot be used together"},
+		{"bad since", func() { reviewWeek, reviewSince = false, "soon" }, "invalid --since"},
+		{"bad ahead", func() { reviewAhead = "-1d" }, "invalid --ahead"},
+		{"max threads", func() { reviewMaxThreads = 0 }, "--max-threads must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cmd := setupReviewTest(t)
+			tt.setup()
+			if err := runReview(cmd, nil); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatReviewHours(t *testing.T) {
+	tests := map[time.Duration]string{0: "0", 90 * time.Minute: "1.5", 2 * time.Hour: "2", 20 * time.Minute: "0.3"}
+	for d, want := range tests {
+		if got := formatReviewHours(d); got != want {
+			t.Errorf("formatReviewHours(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -2,18 +2,46 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	"golang.org/x/term"
 )
 
 var (
 	// Global flags
-	accountFlag string
-	formatFlag  string
-	quietFlag   bool
-	verboseFlag bool
-	configFlag  string
+	accountFlag  string
+	formatFlag   string
+	quietFlag    bool
+	verboseFlag  int
+	configFlag   string
+	readOnlyFlag bool
+
+	// List output flags
+	sortFlag    string
+	descFlag    bool
+	filterFlags []string
+	// envelopeFlag wraps JSON list output in an object with paging metadata
+	envelopeFlag bool
+
+	// Table output flags
+	wrapFlag      bool
+	truncateFlags []string
+	// a11yFlag turns on accessible output
+	a11yFlag bool
 )
 
+// listOptions holds the parsed --sort, --desc and --filter flags.
+var listOptions presenter.ListOptions
+
 // Version information set at build time.
 var (
 	version = "dev"
@@ -38,6 +66,30 @@ Examples:
   goog cal create --title "Meeting"  # Create a calendar event
   goog tasks list                    # List tasks
   goog tasks create "Buy groceries"  # Create a task`,
+	// Errors are printed by Execute with remediation hints
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		applyContext(cmd)
+		applySettings(cmd)
+		opts, err := parseListOptions(sortFlag, descFlag, filterFlags)
+		if err != nil {
+			return err
+		}
+		listOptions = opts
+		if err := applyTableLayout(wrapFlag, truncateFlags); err != nil {
+			return err
+		}
+		applyConfigDefaults(cmd)
+		presenter.SetAccessible(a11yFlag)
+		if err := setupRecordReplay(cmd); err != nil {
+			return err
+		}
+		applyReadOnly()
+		applyEndpoints()
+		setupMetrics(cmd)
+		setupRetries(cmd)
+		return setupOutput(cmd)
+	},
 }
 
 // versionCmd prints the version information.
@@ -52,17 +104,183 @@ var versionCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
-	return rootCmd.Execute()
+	setupLanguage()
+	if err := applyAliases(); err != nil {
+		printError(rootCmd.ErrOrStderr(), err, colorEnabled(os.Stderr))
+		return err
+	}
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	finishRetries(cmd)
+	finishMetrics(cmd, time.Since(start), err)
+	recordHistory(cmd, err)
+	if err != nil && !errors.Is(err, errReported) {
+		printError(rootCmd.ErrOrStderr(), err, colorEnabled(os.Stderr))
+	}
+	return err
 }
 
 func init() {
 	// Global flags available to all commands
 	rootCmd.PersistentFlags().StringVar(&accountFlag, "account", "", "use specific account")
-	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "table", "output format (json|plain|table)")
-	rootCmd.PersistentFlags().BoolVar(&quietFlag, "quiet", false, "suppress non-essential output")
-	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "table", "output format ("+strings.Join(presenter.Formats(), "|")+")")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "print only IDs and errors")
+	rootCmd.PersistentFlags().CountVarP(&verboseFlag, "verbose", "v", "print detail on stderr; -vv also traces API requests")
 	rootCmd.PersistentFlags().StringVar(&configFlag, "config", "", "config file path")
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "refuse any change to mail, calendars, tasks or contacts")
+	rootCmd.PersistentFlags().StringVar(&sortFlag, "sort", "", "sort list output by field (e.g. date, from, subject, title, due)")
+	rootCmd.PersistentFlags().BoolVar(&descFlag, "desc", false, "sort list output in descending order")
+	rootCmd.PersistentFlags().StringArrayVar(&filterFlags, "filter", nil, "show list items matching field~text, field!~text, field=value, field!=value, or a yes/no field such as important or !read (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&envelopeFlag, "envelope", false, "wrap JSON list output in an object with items, next_page_token, total_estimate, fetched_count and warnings")
+	rootCmd.PersistentFlags().BoolVar(&wrapFlag, "wrap", false, "wrap long table cells onto several lines instead of truncating them")
+	rootCmd.PersistentFlags().StringSliceVar(&truncateFlags, "truncate", nil, "limit a table column to a width as column=width, e.g. subject=60 (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVar(&a11yFlag, "a11y", false, "accessible output: tables as key: value blocks, no color-only signals or grids (default: display.a11y)")
 
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
 }
+
+// parseListOptions builds presenter list options from the --sort, --desc
+// and --filter flags.
+func parseListOptions(sort string, desc bool, filters []string) (presenter.ListOptions, error) {
+	opts := presenter.ListOptions{Sort: strings.ToLower(strings.TrimSpace(sort)), Desc: desc}
+	for _, expr := range filters {
+		f, err := presenter.ParseFilter(expr)
+		if err != nil {
+			return presenter.ListOptions{}, err
+		}
+		opts.Filters = append(opts.Filters, f)
+	}
+	if desc && opts.Sort == "" {
+		return presenter.ListOptions{}, fmt.Errorf("--desc requires --sort")
+	}
+	if err := opts.Validate(); err != nil {
+		return presenter.ListOptions{}, err
+	}
+	return opts, nil
+}
+
+// applyTableLayout sets how tables fit their columns from --wrap and
+// --truncate. Tables are fitted to the terminal width only when stdout is a
+// terminal, so piped output keeps the default column widths.
+func applyTableLayout(wrap bool, specs []string) error {
+	limits, err := presenter.ParseTruncate(specs)
+	if err != nil {
+		return err
+	}
+	layout := presenter.TableLayout{Wrap: wrap, Truncate: limits}
+	if term.IsTerminal(int(os.Stdout.Fd())) {
+		layout.Width = terminalWidth()
+	}
+	presenter.SetTableLayout(layout)
+	return nil
+}
+
+// localeFromConfig builds the presenter locale from the display settings.
+func localeFromConfig(cfg *config.Config) (presenter.Locale, error) {
+	return presenter.NewLocale(cfg.Display.DateFormat, cfg.Display.TimeFormat, cfg.Display.SizeUnits)
+}
+
+// applyConfigDefaults applies the default output format (unless --format
+// was given), accessible output (unless --a11y was given), the presenter
+// locale and the keyring backend from the config file. It does nothing
+// when there is no config file yet, and falls back to the built-in defaults
+// with a warning when a setting is invalid, so a bad value never prevents
+// running "goog config set" to fix it.
+func applyConfigDefaults(cmd *cobra.Command) {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+
+	if f := cmd.Flags().Lookup("format"); f != nil && !f.Changed && cfg.DefaultFormat != "" {
+		if presenter.IsRegistered(cfg.DefaultFormat) {
+			formatFlag = cfg.DefaultFormat
+		} else {
+			output.Warnf(cmd, "ignoring unknown default_format %q", cfg.DefaultFormat)
+		}
+	}
+
+	if f := cmd.Flags().Lookup("a11y"); f != nil && !f.Changed && cfg.Display.A11y {
+		a11yFlag = true
+	}
+
+	locale, err := localeFromConfig(cfg)
+	if err != nil {
+		output.Warnf(cmd, "ignoring display settings: %v", err)
+		locale = presenter.DefaultLocale()
+	}
+	presenter.SetLocale(locale)
+
+	if err := keyring.SetBackend(cfg.Keyring.Backend); err != nil {
+		output.Warnf(cmd, "ignoring keyring settings: %v", err)
+		_ = keyring.SetBackend(keyring.BackendAuto)
+	}
+	if err := keyring.SetProtector(cfg.Keyring.Protector); err != nil {
+		output.Warnf(cmd, "ignoring keyring protector: %v", err)
+		_ = keyring.SetProtector("")
+	}
+}
+
+// applyReadOnly turns read-only mode on for --read-only or when the account
+// in use has read_only set in the config, and off otherwise. The account is
+// only resolved when some account is read-only.
+func applyReadOnly() {
+	on := readOnlyFlag
+	if !on && anyAccount(func(acc config.AccountConfig) bool { return acc.ReadOnly }) {
+		if acc, err := GetDependencies().AccountService.ResolveAccount(accountFlag); err == nil {
+			on = acc.ReadOnly
+		}
+	}
+	repository.SetReadOnly(on)
+}
+
+// applyEndpoints points repositories at the endpoint overrides of the
+// account in use, or at Google's endpoints. The account is only resolved
+// when some account has an override.
+func applyEndpoints() {
+	var endpoints map[string]string
+	if anyAccount(func(acc config.AccountConfig) bool { return len(acc.Endpoints()) > 0 }) {
+		if acc, err := GetDependencies().AccountService.ResolveAccount(accountFlag); err == nil {
+			endpoints = acc.Endpoints
+		}
+	}
+	repository.SetEndpoints(endpoints)
+}
+
+// anyAccount reports whether match is true for any account in the config
+// file.
+func anyAccount(match func(config.AccountConfig) bool) bool {
+	if _, err := os.Stat(config.GetConfigPath()); err != nil {
+		return false
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return false
+	}
+	for _, acc := range cfg.Accounts {
+		if match(acc) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPresenter returns the renderer selected by --format, applying the
+// --sort and --filter options to list output.
+func newPresenter() presenter.Renderer {
+	return presenter.WithListOptions(output.Renderer(formatFlag), listOptions)
+}
+
+// printList prints the rendering of one page of list results. With
+// --envelope and JSON output it is wrapped in an object holding the page
+// metadata, so scripts can page with --page-token.
+func printList(cmd *cobra.Command, rendered string, page presenter.Page) {
+	if envelopeFlag && formatFlag == presenter.FormatJSON {
+		rendered = presenter.Envelope(rendered, page)
+	}
+	cmd.Println(rendered)
+}
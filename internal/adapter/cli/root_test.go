@@ -3,9 +3,19 @@ package cli
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
 )
 
 func TestRootCmd_Help(t *testing.T) {
@@ -31,7 +41,7 @@ func TestRootCmd_Help(t *testing.T) {
 }
 
 func TestRootCmd_HasGlobalFlags(t *testing.T) {
-	flags := []string{"account", "format", "quiet", "verbose", "config"}
+	flags := []string{"account", "format", "quiet", "verbose", "config", "sort", "desc", "filter"}
 
 	for _, flagName := range flags {
 		flag := rootCmd.PersistentFlags().Lookup(flagName)
@@ -110,6 +120,18 @@ func TestRootCmd_FormatFlagDefaultValue(t *testing.T) {
 	}
 }
 
+func TestRootCmd_FormatFlagListsRegisteredFormats(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("format")
+	if flag == nil {
+		t.Fatal("expected format flag to exist")
+	}
+	for _, format := range presenter.Formats() {
+		if !strings.Contains(flag.Usage, format) {
+			t.Errorf("expected format flag usage %q to mention %q", flag.Usage, format)
+		}
+	}
+}
+
 func TestRootCmd_QuietFlagDefaultValue(t *testing.T) {
 	flag := rootCmd.PersistentFlags().Lookup("quiet")
 	if flag == nil {
@@ -125,15 +147,38 @@ func TestRootCmd_VerboseFlagDefaultValue(t *testing.T) {
 	if flag == nil {
 		t.Fatal("expected verbose flag to exist")
 	}
-	if flag.DefValue != "false" {
-		t.Errorf("expected verbose flag default to be 'false', got %s", flag.DefValue)
+	if flag.DefValue != "0" {
+		t.Errorf("expected verbose flag default to be '0', got %s", flag.DefValue)
+	}
+	if flag.Shorthand != "v" {
+		t.Errorf("expected verbose flag shorthand to be 'v', got %q", flag.Shorthand)
+	}
+}
+
+func TestRootCmd_ShorthandsDoNotClash(t *testing.T) {
+	// Merging the persistent flags into a command panics when one of its
+	// own flags uses the same shorthand, e.g. -q.
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("%s: %v", cmd.CommandPath(), r)
+			}
+		}()
+		_ = cmd.LocalFlags()
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
 	}
+	walk(rootCmd)
 }
 
 func TestExecute(t *testing.T) {
 	// Test that Execute function works
 	// We can't fully test it without side effects, but we can test that it's callable
-	// and returns an error type
+	// and returns an error type. The language is pinned so the shared
+	// command tree is not translated for the tests that follow.
+	t.Setenv("GOOG_LANG", "en")
 	err := Execute()
 	// Since we're not providing any arguments, it should either succeed or fail gracefully
 	// The important thing is that the function signature is correct
@@ -415,3 +460,318 @@ func TestVersionCmd_Short(t *testing.T) {
 		t.Error("expected version short description to mention 'version'")
 	}
 }
+
+func TestParseListOptions(t *testing.T) {
+	opts, err := parseListOptions(" Date ", true, []string{"from~github", "labels!=SPAM"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Sort != "date" || !opts.Desc || len(opts.Filters) != 2 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	tests := []struct {
+		name    string
+		sort    string
+		desc    bool
+		filters []string
+		wantErr string
+	}{
+		{"desc without sort", "", true, nil, "--desc requires --sort"},
+		{"unknown sort field", "colour", false, nil, "unknown sort field"},
+		{"malformed filter", "", false, []string{"from"}, "invalid filter"},
+		{"unknown filter field", "", false, []string{"nope=1"}, "unknown filter field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseListOptions(tt.sort, tt.desc, tt.filters)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyTableLayout(t *testing.T) {
+	orig := presenter.CurrentTableLayout()
+	t.Cleanup(func() { presenter.SetTableLayout(orig) })
+
+	if err := applyTableLayout(true, []string{"subject=60", "from=0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	layout := presenter.CurrentTableLayout()
+	if !layout.Wrap || layout.Truncate["subject"] != 60 || layout.Truncate["from"] != 0 {
+		t.Errorf("unexpected layout: %+v", layout)
+	}
+	if layout.Width != 0 {
+		t.Errorf("expected no width when stdout is not a terminal, got %d", layout.Width)
+	}
+
+	if err := applyTableLayout(false, []string{"subject"}); err == nil || !strings.Contains(err.Error(), "invalid --truncate") {
+		t.Errorf("expected an invalid --truncate error, got %v", err)
+	}
+}
+
+func TestNewPresenter_AppliesListOptions(t *testing.T) {
+	origFormat, origOpts := formatFlag, listOptions
+	t.Cleanup(func() { formatFlag, listOptions = origFormat, origOpts })
+
+	formatFlag = presenter.FormatPlain
+	listOptions = presenter.ListOptions{Filters: []presenter.Filter{{Field: "subject", Op: presenter.FilterContains, Value: "keep"}}}
+
+	out := newPresenter().RenderMessages([]*mail.Message{
+		{ID: "m1", Subject: "keep me"},
+		{ID: "m2", Subject: "drop me"},
+	})
+	if !strings.Contains(out, "keep me") || strings.Contains(out, "drop me") {
+		t.Errorf("expected filtered output, got %q", out)
+	}
+}
+
+func TestApplyConfigDefaults(t *testing.T) {
+	t.Cleanup(func() { presenter.SetLocale(presenter.DefaultLocale()) })
+	refTime := time.Date(2024, time.December, 31, 14, 5, 0, 0, time.UTC)
+
+	t.Run("no config file keeps defaults", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+		presenter.SetLocale(presenter.DefaultLocale())
+
+		applyConfigDefaults(&cobra.Command{Use: "test"})
+
+		if got := presenter.CurrentLocale().Date(refTime); got != "2024-12-31" {
+			t.Errorf("Date() = %q, want ISO default", got)
+		}
+		if _, err := os.Stat(config.GetConfigPath()); !os.IsNotExist(err) {
+			t.Errorf("expected no config file to be created, stat err = %v", err)
+		}
+	})
+
+	t.Run("applies display settings", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Display.DateFormat = "eu"
+		cfg.Display.TimeFormat = "12h"
+		cfg.Display.SizeUnits = "si"
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+
+		applyConfigDefaults(&cobra.Command{Use: "test"})
+
+		locale := presenter.CurrentLocale()
+		if got := locale.DateTime(refTime); got != "31/12/2024 2:05 PM" {
+			t.Errorf("DateTime() = %q, want %q", got, "31/12/2024 2:05 PM")
+		}
+		if got := locale.Size(1500); got != "1.5 kB" {
+			t.Errorf("Size() = %q, want %q", got, "1.5 kB")
+		}
+	})
+
+	t.Run("applies default format unless --format is set", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		t.Setenv("GOOG_FORMAT", "")
+		cfg := config.NewConfig()
+		cfg.DefaultFormat = "json"
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+		origFormat := formatFlag
+		t.Cleanup(func() { formatFlag = origFormat })
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().StringVar(&formatFlag, "format", "table", "")
+		applyConfigDefaults(cmd)
+		if formatFlag != "json" {
+			t.Errorf("formatFlag = %q, want json from config", formatFlag)
+		}
+
+		cmd = &cobra.Command{Use: "test"}
+		cmd.Flags().StringVar(&formatFlag, "format", "table", "")
+		if err := cmd.Flags().Set("format", "plain"); err != nil {
+			t.Fatalf("failed to set flag: %v", err)
+		}
+		applyConfigDefaults(cmd)
+		if formatFlag != "plain" {
+			t.Errorf("formatFlag = %q, want explicit plain", formatFlag)
+		}
+	})
+
+	t.Run("applies a11y unless --a11y is set", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Display.A11y = true
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+		t.Cleanup(func() { a11yFlag = false })
+
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().BoolVar(&a11yFlag, "a11y", false, "")
+		applyConfigDefaults(cmd)
+		if !a11yFlag {
+			t.Error("a11yFlag = false, want true from config")
+		}
+
+		cmd = &cobra.Command{Use: "test"}
+		cmd.Flags().BoolVar(&a11yFlag, "a11y", false, "")
+		if err := cmd.Flags().Set("a11y", "false"); err != nil {
+			t.Fatalf("failed to set flag: %v", err)
+		}
+		applyConfigDefaults(cmd)
+		if a11yFlag {
+			t.Error("a11yFlag = true, want explicit --a11y=false")
+		}
+	})
+
+	t.Run("applies keyring backend", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Keyring.Backend = keyring.BackendFile
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+		t.Cleanup(func() { _ = keyring.SetBackend(keyring.BackendAuto) })
+
+		applyConfigDefaults(&cobra.Command{Use: "test"})
+
+		if got := keyring.CurrentBackend(); got != keyring.BackendFile {
+			t.Errorf("CurrentBackend() = %q, want file from config", got)
+		}
+	})
+
+	t.Run("invalid settings warn and fall back", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Display.DateFormat = "british"
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+
+		cmd := &cobra.Command{Use: "test"}
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		applyConfigDefaults(cmd)
+
+		if !strings.Contains(stderr.String(), "invalid date format") {
+			t.Errorf("expected warning on stderr, got %q", stderr.String())
+		}
+		if got := presenter.CurrentLocale().Date(refTime); got != "2024-12-31" {
+			t.Errorf("Date() = %q, want ISO default", got)
+		}
+	})
+}
+
+func TestApplyReadOnly(t *testing.T) {
+	origFlag, origAccount := readOnlyFlag, accountFlag
+	t.Cleanup(func() {
+		readOnlyFlag, accountFlag = origFlag, origAccount
+		repository.SetReadOnly(false)
+		ResetDependencies()
+	})
+
+	// saveAccounts writes a config with a read-only work account.
+	saveAccounts := func(t *testing.T) {
+		t.Helper()
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com", ReadOnly: true}
+		cfg.Accounts["personal"] = config.AccountConfig{Email: "personal@example.com"}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+	}
+
+	t.Run("flag turns read-only on", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+		readOnlyFlag = true
+
+		applyReadOnly()
+		if !repository.ReadOnly() {
+			t.Error("expected read-only mode with --read-only")
+		}
+	})
+
+	t.Run("read-only account", func(t *testing.T) {
+		saveAccounts(t)
+		readOnlyFlag = false
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "work", ReadOnly: true},
+		}})
+
+		applyReadOnly()
+		if !repository.ReadOnly() {
+			t.Error("expected read-only mode for the work account")
+		}
+	})
+
+	t.Run("writable account", func(t *testing.T) {
+		saveAccounts(t)
+		readOnlyFlag = false
+		repository.SetReadOnly(true)
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "personal"},
+		}})
+
+		applyReadOnly()
+		if repository.ReadOnly() {
+			t.Error("expected read-only mode to be off for the personal account")
+		}
+	})
+
+	t.Run("no read-only accounts skips resolving", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+		readOnlyFlag = false
+		// Resolving would report a read-only account.
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "work", ReadOnly: true},
+		}})
+
+		applyReadOnly()
+		if repository.ReadOnly() {
+			t.Error("expected read-only mode to be off")
+		}
+	})
+}
+
+func TestApplyEndpoints(t *testing.T) {
+	origAccount := accountFlag
+	t.Cleanup(func() {
+		accountFlag = origAccount
+		repository.SetEndpoints(nil)
+		ResetDependencies()
+	})
+
+	t.Run("account with overrides", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com", CalendarEndpoint: "http://localhost:9000/cal/"}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "work", Endpoints: map[string]string{"calendar": "http://localhost:9000/cal/"}},
+		}})
+
+		applyEndpoints()
+		if got := repository.Endpoint(repository.ServiceCalendar); got != "http://localhost:9000/cal/" {
+			t.Errorf("calendar endpoint = %q", got)
+		}
+	})
+
+	t.Run("no overrides skips resolving", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+		// Resolving would report an override.
+		SetDependencies(&Dependencies{AccountService: &MockAccountService{
+			Account: &accountuc.Account{Alias: "work", Endpoints: map[string]string{"gmail": "http://localhost:9000/"}},
+		}})
+
+		applyEndpoints()
+		if got := repository.Endpoint(repository.ServiceGmail); got != "" {
+			t.Errorf("expected Google's endpoint, got %q", got)
+		}
+		if got := repository.Endpoint(repository.ServiceCalendar); got != "" {
+			t.Errorf("expected the earlier override to be cleared, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,448 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/rules"
+)
+
+// Command flags for rules commands.
+var (
+	rulesFileFlag   string
+	rulesQuery      string
+	rulesMaxResults int
+	rulesDryRun     bool
+	rulesReprocess  bool
+	rulesMessageID  string
+)
+
+// rulesNotify and rulesExec carry out the notify and exec actions. They
+// are variables so tests can record them instead.
+var (
+	rulesNotify = rules.Notify
+	rulesExec   = rules.Exec
+)
+
+// rulesCmd represents the rules command group.
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Run local mail automation rules",
+	Long: `Run mail automation rules defined in a local YAML file.
+
+Rules complement Gmail's server-side filters: they are evaluated by goog
+on this machine, so they can also notify you or run a command. The rules
+file is rules.yaml next to the config file unless --file is given.
+
+Each rule has conditions and actions:
+
+  rules:
+    - name: Receipts
+      match: any                # all (default) or any
+      conditions:
+        - field: from           # from, to, cc, subject, body, snippet, label
+          contains: "@shop.example.com"
+        - field: subject
+          matches: "(?i)order #[0-9]+"
+      actions:
+        - label: Receipts       # created if missing
+        - archive: true
+      stop: true                # skip later rules for matching messages
+    - name: Pager
+      conditions:
+        - field: label
+          equals: Alerts
+        - field: subject
+          contains: resolved
+          not: true
+      actions:
+        - forward: oncall@example.com
+        - notify: "{subject} from {from}"
+        - exec: ~/bin/page
+
+Conditions use exactly one of contains or equals (both ignore case) or
+matches (a regular expression). Label conditions accept label names or
+IDs. Notify text may use {rule}, {id}, {from} and {subject}. Exec
+commands run with sh, get the message body on stdin and GOOG_RULE,
+GOOG_MESSAGE_ID, GOOG_THREAD_ID, GOOG_MESSAGE_FROM and
+GOOG_MESSAGE_SUBJECT in the environment.
+
+Rules can also depend on the account they run for: a condition on the
+field account.<key> tests the account's metadata (see 'goog config
+account'), e.g. field: account.role with equals: work. Notify text may
+use {account.<key>}, and exec commands get GOOG_ACCOUNT_<KEY>.`,
+	Example: `  # Apply the rules to recent inbox mail
+  goog rules run
+
+  # See what would happen without changing anything
+  goog rules run --dry-run
+
+  # Check which rules match one message
+  goog rules test --message 18c1234abcd
+
+  # Run the rules every five minutes
+  goog schedule add "*/5 * * * *" "rules run"`,
+}
+
+// rulesRunCmd applies the rules to matching messages.
+var rulesRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply the rules to recent messages",
+	Long: `Apply the rules to the messages found by --query.
+
+Before the rules run, muted threads with new messages in the inbox are
+archived again (see 'goog mail mute').
+
+Messages are processed once: their IDs are recorded in rules-seen.json
+next to the config file and skipped on later runs, so the command can run
+from 'goog schedule' without repeating forwards or commands. Use
+--reprocess to evaluate them again. Label and archive actions are applied
+together at the end of the run with Gmail's batch modify.
+
+Failed actions are reported and the run carries on with the next one.`,
+	Example: `  # Apply the rules to recent inbox mail
+  goog rules run
+
+  # Preview the rules against the last week of mail
+  goog rules run --query "newer_than:7d" --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runRulesRun,
+}
+
+// rulesTestCmd shows how the rules evaluate one message.
+var rulesTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Show which rules match a message",
+	Long: `Evaluate the rules against one message without running any actions.
+
+Each rule is listed with the result of every condition, whether it
+matches, and the actions that would run.`,
+	Example: `  # Test the rules against a message
+  goog rules test --message 18c1234abcd
+
+  # Test a draft rules file
+  goog rules test --message 18c1234abcd --file ./rules.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runRulesTest,
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesRunCmd)
+	rulesCmd.AddCommand(rulesTestCmd)
+
+	rulesCmd.PersistentFlags().StringVar(&rulesFileFlag, "file", "", "rules file (default: rules.yaml next to the config file)")
+
+	rulesRunCmd.Flags().StringVar(&rulesQuery, "query", "in:inbox newer_than:1d", "Gmail search query selecting the messages to process")
+	rulesRunCmd.Flags().IntVar(&rulesMaxResults, "max-results", 100, "maximum number of messages to process")
+	rulesRunCmd.Flags().BoolVar(&rulesDryRun, "dry-run", false, "show the actions that would run without running them")
+	rulesRunCmd.Flags().BoolVar(&rulesReprocess, "reprocess", false, "also process messages handled by earlier runs")
+
+	rulesTestCmd.Flags().StringVar(&rulesMessageID, "message", "", "ID of the message to test (required)")
+	_ = rulesTestCmd.MarkFlagRequired("message")
+}
+
+// loadRules reads the rules file selected by --file.
+func loadRules() ([]*mail.Rule, string, error) {
+	path := rulesFileFlag
+	if path == "" {
+		path = rules.Path()
+	}
+	loaded, err := rules.Load(expandHome(path))
+	return loaded, path, err
+}
+
+// labelNamesByID returns the names of the account's labels keyed by ID.
+func labelNamesByID(ctx context.Context, repo LabelRepository) (map[string]string, error) {
+	labels, err := repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	names := make(map[string]string, len(labels))
+	for _, label := range labels {
+		names[label.ID] = label.Name
+	}
+	return names, nil
+}
+
+// withLabelNames returns a copy of msg whose labels include the names of
+// its labels as well as their IDs, so rules can refer to either.
+func withLabelNames(msg *mail.Message, names map[string]string) *mail.Message {
+	copied := *msg
+	copied.Labels = slices.Clone(msg.Labels)
+	for _, id := range msg.Labels {
+		if name, ok := names[id]; ok && name != id {
+			copied.Labels = append(copied.Labels, name)
+		}
+	}
+	return &copied
+}
+
+// rulesRunner carries out rule actions for one run.
+type rulesRunner struct {
+	cmd       *cobra.Command
+	repo      MessageRepository
+	labelRepo LabelRepository
+	sender    string
+	account   map[string]string
+	labels    map[string]*mail.Label
+	queue     labelQueue
+	failed    int
+}
+
+// run carries out action for msg, matched by rule. Label changes are
+// queued; everything else happens immediately.
+func (r *rulesRunner) run(ctx context.Context, rule *mail.Rule, action *mail.RuleAction, msg *mail.Message) error {
+	switch action.Type {
+	case mail.RuleActionLabel:
+		label, ok := r.labels[action.Value]
+		if !ok {
+			var err error
+			if label, err = findOrCreateLabel(ctx, r.labelRepo, action.Value, true); err != nil {
+				return err
+			}
+			r.labels[action.Value] = label
+		}
+		r.queue.add(msg.ID, []string{label.ID}, nil)
+	case mail.RuleActionArchive:
+		r.queue.add(msg.ID, nil, []string{"INBOX"})
+	case mail.RuleActionForward:
+		to, err := parseEmailRecipients([]string{action.Value})
+		if err != nil {
+			return err
+		}
+		if _, err := r.repo.Forward(ctx, msg.ID, &mail.Message{From: mail.ParseAddress(r.sender), To: mail.ParseAddresses(to)}); err != nil {
+			return fmt.Errorf("failed to forward: %w", err)
+		}
+	case mail.RuleActionNotify:
+		text := rules.Expand(action.Value, rule.Name, msg, r.account)
+		if err := rulesNotify(ctx, "goog: "+rule.Name, text); err != nil {
+			if !errors.Is(err, rules.ErrNoNotifier) {
+				return err
+			}
+			// Fall back to the command output, e.g. the schedule log
+			r.cmd.Printf("[%s] %s\n", rule.Name, text)
+		}
+	case mail.RuleActionExec:
+		plain := *msg
+		plain.Body = exportBody(msg)
+		if err := rulesExec(ctx, action.Value, rule.Name, &plain, r.account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runRulesRun handles the rules run command.
+func runRulesRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	ruleSet, path, err := loadRules()
+	if err != nil {
+		return err
+	}
+	if len(ruleSet) == 0 {
+		cmd.Printf("No rules defined in %s.\n", path)
+		return nil
+	}
+
+	if !rulesDryRun {
+		// Keep overlapping runs, e.g. from cron, from acting twice
+		lock, err := filelock.Acquire(rules.SeenPath(), filelock.DefaultTimeout)
+		if err != nil {
+			return fmt.Errorf("another rules run is in progress: %w", err)
+		}
+		defer func() { _ = lock.Unlock() }()
+	}
+	seen, err := rules.LoadSeen(rules.SeenPath())
+	if err != nil {
+		return err
+	}
+
+	repo, sender, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	names, err := labelNamesByID(ctx, labelRepo)
+	if err != nil {
+		return err
+	}
+
+	threadRepo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	muted, err := sweepMutedThreads(ctx, threadRepo, rulesDryRun)
+	if err != nil {
+		return err
+	}
+	if len(muted) > 0 {
+		if rulesDryRun {
+			cmd.Printf("Would archive %d muted thread(s) with new messages.\n", len(muted))
+		} else if !quietFlag {
+			cmd.Printf("Archived %d muted thread(s) with new messages.\n", len(muted))
+		}
+	}
+
+	result, err := repo.List(ctx, mail.ListOptions{Query: rulesQuery, MaxResults: rulesMaxResults})
+	if err != nil {
+		return fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	account := accountMetadata()
+	runner := &rulesRunner{cmd: cmd, repo: repo, labelRepo: labelRepo, sender: sender, account: account, labels: make(map[string]*mail.Label)}
+	checked, matched, actions := 0, 0, 0
+	for _, msg := range result.Items {
+		if !rulesReprocess && seen.Has(msg.ID) {
+			continue
+		}
+		checked++
+		matches := mail.MatchingRules(ruleSet, withLabelNames(msg, names), account)
+		if len(matches) > 0 {
+			matched++
+		}
+		for _, rule := range matches {
+			for _, action := range rule.Actions {
+				actions++
+				if rulesDryRun {
+					cmd.Printf("Would %s: %s (rule %s)\n", action, msg.Subject, rule.Name)
+					continue
+				}
+				if err := runner.run(ctx, rule, action, msg); err != nil {
+					runner.failed++
+					output.Warnf(cmd, "rule %s: %s on %s failed: %v", rule.Name, action, msg.ID, err)
+					continue
+				}
+				if !quietFlag {
+					cmd.Printf("%s: %s (rule %s)\n", action, msg.Subject, rule.Name)
+				}
+			}
+		}
+		seen.Add(msg.ID)
+	}
+
+	if rulesDryRun {
+		cmd.Printf("Dry run: %d message(s) checked, %d matched, %d action(s) would run.\n", checked, matched, actions)
+		return nil
+	}
+
+	if err := runner.queue.apply(ctx, repo); err != nil {
+		return err
+	}
+	if err := seen.Save(); err != nil {
+		return err
+	}
+	if !quietFlag {
+		cmd.Printf("%d message(s) checked, %d matched, %d action(s) run.\n", checked, matched, actions-runner.failed)
+	}
+	if runner.failed > 0 {
+		return fmt.Errorf("%d rule action(s) failed", runner.failed)
+	}
+	return nil
+}
+
+// ruleTestResult is how one rule evaluated against a message.
+type ruleTestResult struct {
+	Rule       string                `json:"rule"`
+	Matched    bool                  `json:"matched"`
+	Runs       bool                  `json:"runs"`
+	Conditions []ruleConditionResult `json:"conditions"`
+	Actions    []string              `json:"actions"`
+}
+
+// ruleConditionResult is how one condition evaluated against a message.
+type ruleConditionResult struct {
+	Condition string `json:"condition"`
+	Matched   bool   `json:"matched"`
+}
+
+// runRulesTest handles the rules test command.
+func runRulesTest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	ruleSet, path, err := loadRules()
+	if err != nil {
+		return err
+	}
+	if len(ruleSet) == 0 {
+		cmd.Printf("No rules defined in %s.\n", path)
+		return nil
+	}
+
+	repo, _, err := getMessageRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	labelRepo, err := getLabelRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+	names, err := labelNamesByID(ctx, labelRepo)
+	if err != nil {
+		return err
+	}
+	msg, err := repo.Get(ctx, rulesMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	msg = withLabelNames(msg, names)
+
+	account := accountMetadata()
+	runs := mail.MatchingRules(ruleSet, msg, account)
+	results := make([]ruleTestResult, 0, len(ruleSet))
+	for _, rule := range ruleSet {
+		result := ruleTestResult{Rule: rule.Name, Matched: rule.Matches(msg, account), Runs: slices.Contains(runs, rule)}
+		for _, c := range rule.Conditions {
+			result.Conditions = append(result.Conditions, ruleConditionResult{Condition: c.String(), Matched: c.Matches(msg, account)})
+		}
+		for _, a := range rule.Actions {
+			result.Actions = append(result.Actions, a.String())
+		}
+		results = append(results, result)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	cmd.Printf("Message %s: %s\n", msg.ID, msg.Subject)
+	for _, result := range results {
+		status := "no match"
+		switch {
+		case result.Runs:
+			status = "match"
+		case result.Matched:
+			status = "match (skipped by an earlier stop rule)"
+		}
+		cmd.Printf("\n%s: %s\n", result.Rule, status)
+		for _, c := range result.Conditions {
+			mark := " "
+			if c.Matched {
+				mark = "x"
+			}
+			cmd.Printf("  [%s] %s\n", mark, c.Condition)
+		}
+		if result.Runs {
+			cmd.Printf("  actions: %s\n", strings.Join(result.Actions, ", "))
+		}
+	}
+	return nil
+}
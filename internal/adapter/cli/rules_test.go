@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/rules"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+const testRulesFile = `
+rules:
+  - name: Receipts
+    conditions:
+      - field: from
+        contains: "@shop.example.com"
+    actions:
+      - label: Receipts
+      - archive: true
+    stop: true
+  - name: Alerts
+    match: any
+    conditions:
+      - field: label
+        equals: Alerts
+      - field: subject
+        contains: "[alert]"
+    actions:
+      - forward: oncall@example.com
+      - notify: "{subject}"
+      - exec: ./page
+  - name: Everything from shop
+    conditions:
+      - field: from
+        contains: shop
+    actions:
+      - label: Shop
+`
+
+// rulesMessageRepository returns listed messages by ID and records forwards.
+type rulesMessageRepository struct {
+	triageMessageRepository
+	Forwarded map[string][]string
+}
+
+func (m *rulesMessageRepository) Forward(ctx context.Context, messageID string, forward *mail.Message) (*mail.Message, error) {
+	if m.Forwarded == nil {
+		m.Forwarded = make(map[string][]string)
+	}
+	m.Forwarded[messageID] = mail.AddressStrings(forward.To)
+	return forward, nil
+}
+
+// setupRulesTest writes the rules file next to a temp config, injects
+// repositories and records notify and exec actions.
+func setupRulesTest(t *testing.T, repo *rulesMessageRepository, labelRepo *namedLabelRepository) (notified, executed *[]string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+	if err := os.WriteFile(filepath.Join(dir, rules.FileName), []byte(testRulesFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{MessageRepo: repo, LabelRepo: labelRepo},
+	})
+	t.Cleanup(ResetDependencies)
+
+	origNotify, origExec := rulesNotify, rulesExec
+	origFile, origDry, origReprocess, origMessage, origFormat, origQuiet := rulesFileFlag, rulesDryRun, rulesReprocess, rulesMessageID, formatFlag, quietFlag
+	t.Cleanup(func() {
+		rulesNotify, rulesExec = origNotify, origExec
+		rulesFileFlag, rulesDryRun, rulesReprocess, rulesMessageID, formatFlag, quietFlag = origFile, origDry, origReprocess, origMessage, origFormat, origQuiet
+	})
+	rulesFileFlag, rulesDryRun, rulesReprocess, rulesMessageID, formatFlag, quietFlag = "", false, false, "", "table", false
+
+	notified, executed = new([]string), new([]string)
+	rulesNotify = func(ctx context.Context, title, text string) error {
+		*notified = append(*notified, title+": "+text)
+		return nil
+	}
+	rulesExec = func(ctx context.Context, command, rule string, msg *mail.Message, account map[string]string) error {
+		*executed = append(*executed, command+" "+msg.ID)
+		return nil
+	}
+	return notified, executed
+}
+
+func rulesTestRepos() (*rulesMessageRepository, *namedLabelRepository) {
+	repo := &rulesMessageRepository{}
+	repo.Messages = []*mail.Message{
+		{ID: "m1", From: mail.Address{Email: "orders@shop.example.com"}, Subject: "Receipt", Labels: []string{"INBOX"}},
+		{ID: "m2", From: mail.Address{Email: "monitor@example.com"}, Subject: "Disk full", Labels: []string{"INBOX", "Label_9"}},
+		{ID: "m3", From: mail.Address{Email: "friend@example.com"}, Subject: "Hello", Labels: []string{"INBOX"}},
+	}
+	labelRepo := &namedLabelRepository{ByName: map[string]*mail.Label{}}
+	labelRepo.Labels = []*mail.Label{{ID: "INBOX", Name: "INBOX"}, {ID: "Label_9", Name: "Alerts"}}
+	return repo, labelRepo
+}
+
+func TestRunRulesRun(t *testing.T) {
+	repo, labelRepo := rulesTestRepos()
+	notified, executed := setupRulesTest(t, repo, labelRepo)
+
+	cmd := &cobra.Command{Use: "test"}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	if err := runRulesRun(cmd, nil); err != nil {
+		t.Fatalf("runRulesRun failed: %v\n%s", err, out.String())
+	}
+
+	// Receipts stops evaluation, so the Shop label is never created
+	if strings.Join(labelRepo.Created, ",") != "Receipts" {
+		t.Errorf("created labels = %v, want [Receipts]", labelRepo.Created)
+	}
+	if len(repo.BatchModified) != 1 || repo.BatchModified[0].IDs[0] != "m1" {
+		t.Fatalf("BatchModify calls = %+v", repo.BatchModified)
+	}
+	if req := repo.BatchModified[0].Req; req.AddLabels[0] != "Label_Receipts" || req.RemoveLabels[0] != "INBOX" {
+		t.Errorf("unexpected label change: %+v", req)
+	}
+	if to := repo.Forwarded["m2"]; len(to) != 1 || to[0] != "oncall@example.com" {
+		t.Errorf("forwards = %v", repo.Forwarded)
+	}
+	if strings.Join(*notified, "|") != "goog: Alerts: Disk full" || strings.Join(*executed, "|") != "./page m2" {
+		t.Errorf("notified %v, executed %v", *notified, *executed)
+	}
+	if !strings.Contains(out.String(), "3 message(s) checked, 2 matched, 5 action(s) run.") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+
+	// A second run skips the processed messages
+	out.Reset()
+	repo.Forwarded = nil
+	if err := runRulesRun(cmd, nil); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if repo.Forwarded != nil || !strings.Contains(out.String(), "0 message(s) checked") {
+		t.Errorf("expected processed messages to be skipped, got:\n%s", out.String())
+	}
+}
+
+func TestRunRulesRun_DryRun(t *testing.T) {
+	repo, labelRepo := rulesTestRepos()
+	notified, _ := setupRulesTest(t, repo, labelRepo)
+	rulesDryRun = true
+
+	cmd := &cobra.Command{Use: "test"}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runRulesRun(cmd, nil); err != nil {
+		t.Fatalf("runRulesRun failed: %v", err)
+	}
+	if len(repo.BatchModified) != 0 || repo.Forwarded != nil || len(*notified) != 0 || len(labelRepo.Created) != 0 {
+		t.Error("expected a dry run to change nothing")
+	}
+	if !strings.Contains(out.String(), "Would forward oncall@example.com: Disk full (rule Alerts)") ||
+		!strings.Contains(out.String(), "5 action(s) would run") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	if _, err := os.Stat(rules.SeenPath()); !os.IsNotExist(err) {
+		t.Errorf("expected no processed-messages file after a dry run, got %v", err)
+	}
+}
+
+func TestRunRulesTest(t *testing.T) {
+	repo, labelRepo := rulesTestRepos()
+	setupRulesTest(t, repo, labelRepo)
+	rulesMessageID = "m1"
+
+	cmd := &cobra.Command{Use: "test"}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runRulesTest(cmd, nil); err != nil {
+		t.Fatalf("runRulesTest failed: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{
+		"Receipts: match\n  [x] from contains \"@shop.example.com\"\n  actions: label Receipts, archive",
+		"Alerts: no match\n  [ ] label equals \"Alerts\"",
+		"Everything from shop: match (skipped by an earlier stop rule)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	formatFlag = "json"
+	rulesMessageID = "m2"
+	out.Reset()
+	if err := runRulesTest(cmd, nil); err != nil {
+		t.Fatalf("runRulesTest failed: %v", err)
+	}
+	var results []ruleTestResult
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(results) != 3 || !results[1].Runs || !results[1].Conditions[0].Matched {
+		t.Errorf("expected the Alerts rule to match by label name, got %+v", results)
+	}
+}
+
+func TestRunRulesRun_NoFile(t *testing.T) {
+	repo, labelRepo := rulesTestRepos()
+	setupRulesTest(t, repo, labelRepo)
+	rulesFileFlag = filepath.Join(t.TempDir(), "missing.yaml")
+
+	err := runRulesRun(&cobra.Command{Use: "test"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no rules file") {
+		t.Errorf("expected a missing file error, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/schedule"
+	"github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// scheduleLogName is the file next to the config file that scheduled jobs
+// append their output to by default.
+const scheduleLogName = "schedule.log"
+
+// Command flags for schedule commands.
+var scheduleLogFlag string
+
+// userCrontab is the crontab that schedule commands manage. It is a
+// variable so tests can use a crontab in memory.
+var userCrontab schedule.Crontab = schedule.UserCrontab{}
+
+// scheduleExecutable returns the path of the goog binary that scheduled
+// jobs run. It is a variable so tests get a stable path.
+var scheduleExecutable = func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// scheduleCmd represents the schedule command group.
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run goog commands on a schedule",
+	Long: `Run goog commands on a schedule with cron.
+
+Each job is an entry in your crontab that runs this goog binary with the
+config file it is using now, so jobs use the same accounts and stored
+tokens as your interactive commands. Sign in with 'goog auth login'
+first; jobs cannot open a browser. Output is appended to schedule.log
+next to the config file.
+
+goog only changes its own entries, marked with '# goog-schedule'
+comments, and leaves the rest of your crontab alone. The crontab command
+must be available (Linux, macOS and other Unix systems).`,
+	Example: `  # Report unread mail every weekday at 8:00
+  goog schedule add "0 8 * * 1-5" "mail list --unread-only --format plain"
+
+  # List and remove jobs
+  goog schedule list
+  goog schedule remove 1`,
+}
+
+// scheduleAddCmd adds a job.
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <schedule> <command>",
+	Short: "Schedule a goog command",
+	Long: `Add a crontab entry that runs a goog command on a cron schedule.
+
+The schedule has five fields (minute, hour, day of month, month, day of
+week) or is a macro such as @daily or @hourly. Quote the command, without
+the leading goog, so it is passed as one argument; it may use an alias.
+
+The entry sets GOOG_CONFIG to the current config file, passes --account
+when it is given, and keeps GOOG_ACCOUNT and the D-Bus session address
+(needed to reach the Linux keyring from cron) when they are set.`,
+	Example: `  # Weekdays at 8:00
+  goog schedule add "0 8 * * 1-5" "mail list --unread-only --format plain"
+
+  # Every morning for the work account
+  goog --account work schedule add @daily "cal today --format plain"
+
+  # Every 15 minutes, writing output to a custom log
+  goog schedule add "*/15 * * * *" "tasks list" --log /tmp/goog.log`,
+	Args: cobra.ExactArgs(2),
+	RunE: runScheduleAdd,
+}
+
+// scheduleListCmd lists jobs.
+var scheduleListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List scheduled commands",
+	Long:    `List the goog jobs in your crontab with their numbers for 'goog schedule remove'.`,
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	RunE:    runScheduleList,
+}
+
+// scheduleRemoveCmd removes a job.
+var scheduleRemoveCmd = &cobra.Command{
+	Use:     "remove <n>",
+	Short:   "Remove a scheduled command",
+	Long:    `Remove job number n from 'goog schedule list' from your crontab.`,
+	Aliases: []string{"rm", "delete"},
+	Args:    cobra.ExactArgs(1),
+	RunE:    runScheduleRemove,
+}
+
+func init() {
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+
+	scheduleAddCmd.Flags().StringVar(&scheduleLogFlag, "log", "", "file to append the job's output to (default schedule.log next to the config file)")
+
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// runScheduleAdd handles the schedule add command.
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	spec, command := strings.Join(strings.Fields(args[0]), " "), strings.TrimSpace(args[1])
+	if err := schedule.ValidateSpec(spec); err != nil {
+		return err
+	}
+	words, err := scheduleCommandArgs(command)
+	if err != nil {
+		return err
+	}
+	if err := checkCrontab(); err != nil {
+		return err
+	}
+
+	exe, err := scheduleExecutable()
+	if err != nil {
+		return fmt.Errorf("failed to find the goog executable: %w", err)
+	}
+	configPath, err := filepath.Abs(config.GetConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	logPath := scheduleLogFlag
+	if logPath == "" {
+		logPath = filepath.Join(filepath.Dir(configPath), scheduleLogName)
+	}
+	if logPath, err = filepath.Abs(logPath); err != nil {
+		return fmt.Errorf("failed to resolve log path: %w", err)
+	}
+	if accountFlag != "" {
+		words = append([]string{"--account", accountFlag}, words...)
+	}
+
+	current, err := userCrontab.Read()
+	if err != nil {
+		return err
+	}
+	updated, job := schedule.Add(current, schedule.Job{
+		Command: command,
+		Entry:   schedule.Entry(spec, scheduleEnv(configPath), exe, words, logPath),
+	})
+	if err := userCrontab.Write(updated); err != nil {
+		return err
+	}
+
+	if !quietFlag {
+		cmd.Printf("Scheduled job %d: %s  goog %s\n", job.ID, job.Spec, command)
+		cmd.Printf("Output is appended to %s\n", logPath)
+	}
+	if _, source := auth.ResolveClientCredentials(); source == auth.ClientSourceEnv {
+		output.Warnf(cmd, "the OAuth client comes from %s, which cron does not set; run 'goog init' to save it to %s",
+			auth.EnvClientID, auth.ClientFilePath())
+	}
+	return nil
+}
+
+// runScheduleList handles the schedule list command.
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	if err := checkCrontab(); err != nil {
+		return err
+	}
+	current, err := userCrontab.Read()
+	if err != nil {
+		return err
+	}
+	jobs := schedule.Parse(current)
+
+	if formatFlag == presenter.FormatJSON {
+		if jobs == nil {
+			jobs = []schedule.Job{}
+		}
+		data, err := json.MarshalIndent(jobs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode jobs: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(jobs) == 0 {
+		if !quietFlag {
+			cmd.Println("No scheduled jobs.")
+		}
+		return nil
+	}
+	w := newTableWriter(cmd.OutOrStdout())
+	fmt.Fprintln(w, "#\tSCHEDULE\tCOMMAND")
+	for _, job := range jobs {
+		fmt.Fprintf(w, "%d\t%s\tgoog %s\n", job.ID, job.Spec, job.Command)
+	}
+	return w.Flush()
+}
+
+// runScheduleRemove handles the schedule remove command.
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid job number %q: use a number from 'goog schedule list'", args[0])
+	}
+	if err := checkCrontab(); err != nil {
+		return err
+	}
+	current, err := userCrontab.Read()
+	if err != nil {
+		return err
+	}
+	updated, err := schedule.Remove(current, id)
+	if err != nil {
+		return err
+	}
+	if err := userCrontab.Write(updated); err != nil {
+		return err
+	}
+
+	if !quietFlag {
+		cmd.Printf("Removed job %d\n", id)
+	}
+	return nil
+}
+
+// scheduleCommandArgs splits a scheduled command into arguments and checks
+// that it starts with a goog command or alias.
+func scheduleCommandArgs(command string) ([]string, error) {
+	words, err := splitCommandLine(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command: %w", err)
+	}
+	if len(words) == 0 {
+		return nil, errors.New("command cannot be empty")
+	}
+	if words[0] == rootCmd.Name() {
+		return nil, fmt.Errorf("leave out %q at the start of the command", rootCmd.Name())
+	}
+	if isBuiltinCommand(words[0]) {
+		return words, nil
+	}
+	if cfg, err := config.Load(); err == nil {
+		if _, ok := cfg.Aliases[words[0]]; ok {
+			return words, nil
+		}
+	}
+	return nil, fmt.Errorf("command must start with a goog command or alias, not %q", words[0])
+}
+
+// scheduleEnv returns the environment a scheduled job needs to run without
+// a terminal: the config file, the account from GOOG_ACCOUNT, and the D-Bus
+// session bus that the Linux keyring is reached through.
+func scheduleEnv(configPath string) []string {
+	env := []string{"GOOG_CONFIG=" + configPath}
+	if acc := os.Getenv(account.EnvAccount); acc != "" && accountFlag == "" {
+		env = append(env, account.EnvAccount+"="+acc)
+	}
+	if bus := os.Getenv("DBUS_SESSION_BUS_ADDRESS"); bus != "" && runtime.GOOS == "linux" {
+		env = append(env, "DBUS_SESSION_BUS_ADDRESS="+bus)
+	}
+	return env
+}
+
+// checkCrontab reports an error on systems without cron.
+func checkCrontab() error {
+	if runtime.GOOS == "windows" {
+		return errors.New("goog schedule needs cron, which Windows does not have; use Task Scheduler to run goog instead")
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/schedule"
+)
+
+// memoryCrontab is a crontab held in memory.
+type memoryCrontab struct {
+	content string
+	writes  int
+}
+
+func (m *memoryCrontab) Read() (string, error) { return m.content, nil }
+
+func (m *memoryCrontab) Write(crontab string) error {
+	m.content = crontab
+	m.writes++
+	return nil
+}
+
+// setupScheduleTest installs an in-memory crontab and a temporary config
+// with an alias, and restores the schedule state afterwards.
+func setupScheduleTest(t *testing.T, initial string) *memoryCrontab {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", configPath)
+	t.Setenv("GOOG_ACCOUNT", "")
+	t.Setenv("GOOG_CLIENT_ID", "")
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "")
+	cfg := config.NewConfig()
+	cfg.Aliases["inbox"] = "mail list --unread-only"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	crontab := &memoryCrontab{content: initial}
+	origCrontab, origExe, origLog, origAccount, origFormat := userCrontab, scheduleExecutable, scheduleLogFlag, accountFlag, formatFlag
+	userCrontab = crontab
+	scheduleExecutable = func() (string, error) { return "/usr/local/bin/goog", nil }
+	scheduleLogFlag, accountFlag, formatFlag = "", "", "table"
+	t.Cleanup(func() {
+		userCrontab, scheduleExecutable, scheduleLogFlag, accountFlag, formatFlag = origCrontab, origExe, origLog, origAccount, origFormat
+	})
+	return crontab
+}
+
+func TestRunScheduleAdd(t *testing.T) {
+	t.Run("adds an entry", func(t *testing.T) {
+		crontab := setupScheduleTest(t, "0 2 * * * /usr/local/bin/backup\n")
+		accountFlag = "work"
+		buf := new(bytes.Buffer)
+		cmd := &cobra.Command{Use: "add"}
+		cmd.SetOut(buf)
+		if err := runScheduleAdd(cmd, []string{"0 8 * * 1-5", "mail search 'is:unread from:boss'"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		jobs := schedule.Parse(crontab.content)
+		if len(jobs) != 1 {
+			t.Fatalf("got %d jobs, want 1:\n%s", len(jobs), crontab.content)
+		}
+		entry := jobs[0].Entry
+		for _, want := range []string{
+			"0 8 * * 1-5 GOOG_CONFIG=",
+			"/usr/local/bin/goog --account work mail search 'is:unread from:boss'",
+			"schedule.log 2>&1",
+		} {
+			if !strings.Contains(entry, want) {
+				t.Errorf("entry %q should contain %q", entry, want)
+			}
+		}
+		if !strings.HasPrefix(crontab.content, "0 2 * * * /usr/local/bin/backup\n") {
+			t.Errorf("existing entries should be kept:\n%s", crontab.content)
+		}
+		if !strings.Contains(buf.String(), "Scheduled job 1") {
+			t.Errorf("unexpected output: %q", buf.String())
+		}
+	})
+
+	t.Run("accepts aliases", func(t *testing.T) {
+		setupScheduleTest(t, "")
+		if err := runScheduleAdd(&cobra.Command{Use: "add"}, []string{"@daily", "inbox"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("keeps the session bus", func(t *testing.T) {
+		crontab := setupScheduleTest(t, "")
+		t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/run/user/1000/bus")
+		if err := runScheduleAdd(&cobra.Command{Use: "add"}, []string{"@hourly", "tasks list"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if runtime.GOOS == "linux" && !strings.Contains(crontab.content, "DBUS_SESSION_BUS_ADDRESS=") {
+			t.Errorf("entry should set the session bus:\n%s", crontab.content)
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		crontab := setupScheduleTest(t, "")
+		tests := []struct {
+			spec, command, wantErr string
+		}{
+			{"0 25 * * *", "tasks list", "hour"},
+			{"0 8 * * *", "goog tasks list", "leave out"},
+			{"0 8 * * *", "rm -rf /", "must start with a goog command"},
+			{"0 8 * * *", "", "cannot be empty"},
+		}
+		for _, tt := range tests {
+			err := runScheduleAdd(&cobra.Command{Use: "add"}, []string{tt.spec, tt.command})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("add %q %q: expected error containing %q, got %v", tt.spec, tt.command, tt.wantErr, err)
+			}
+		}
+		if crontab.writes != 0 {
+			t.Errorf("rejected jobs should not change the crontab")
+		}
+	})
+}
+
+func TestRunScheduleListAndRemove(t *testing.T) {
+	crontab := setupScheduleTest(t, "")
+	for _, command := range []string{"cal today", "tasks list"} {
+		if err := runScheduleAdd(&cobra.Command{Use: "add"}, []string{"@daily", command}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "list"}
+	cmd.SetOut(buf)
+	if err := runScheduleList(cmd, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for _, want := range []string{"SCHEDULE", "@daily", "goog cal today", "goog tasks list"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected list output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+
+	if err := runScheduleRemove(cmd, []string{"1"}); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if err := runScheduleRemove(cmd, []string{"1"}); !errors.Is(err, schedule.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := runScheduleRemove(cmd, []string{"first"}); err == nil {
+		t.Error("expected an error for a non-numeric job number")
+	}
+
+	buf.Reset()
+	formatFlag = "json"
+	if err := runScheduleList(cmd, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var jobs []schedule.Job
+	if err := json.Unmarshal(buf.Bytes(), &jobs); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != 2 || jobs[0].Command != "tasks list" {
+		t.Errorf("jobs = %+v, want only job 2", jobs)
+	}
+	if strings.Contains(crontab.content, "cal today") {
+		t.Errorf("removed job is still in the crontab:\n%s", crontab.content)
+	}
+}
@@ -0,0 +1,277 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// commandScopes declares the narrowest OAuth scopes each command needs,
+// keyed by command path without "goog". A command that is not listed needs
+// what its nearest listed parent needs; commands with no listed parent do
+// not call Google APIs.
+var commandScopes = map[string][]string{
+	"mail":              {auth.ScopeGmailModify},
+	"mail list":         {auth.ScopeGmailReadonly},
+	"mail read":         {auth.ScopeGmailReadonly},
+	"mail search":       {auth.ScopeGmailReadonly},
+	"mail bounces":      {auth.ScopeGmailReadonly},
+	"mail attachments":  {auth.ScopeGmailReadonly},
+	"mail links":        {auth.ScopeGmailReadonly},
+	"mail todo list":    {auth.ScopeGmailReadonly},
+	"mail send":         {auth.ScopeGmailSend},
+	"mail watchdir":     {auth.ScopeGmailSend, auth.ScopeDriveFile},
+	"mail reply":        {auth.ScopeGmailReadonly, auth.ScopeGmailSend},
+	"mail forward":      {auth.ScopeGmailReadonly, auth.ScopeGmailSend},
+	"mail resend":       {auth.ScopeGmailReadonly, auth.ScopeGmailSend},
+	"mail outbox flush": {auth.ScopeGmailReadonly, auth.ScopeGmailSend},
+	"mail rsvp":         {auth.ScopeGmailReadonly, auth.ScopeCalendarEvents},
+
+	"draft": {auth.ScopeGmailCompose},
+	"label": {auth.ScopeGmailLabels},
+
+	"thread":           {auth.ScopeGmailModify},
+	"thread list":      {auth.ScopeGmailReadonly},
+	"thread show":      {auth.ScopeGmailReadonly},
+	"thread export":    {auth.ScopeGmailReadonly},
+	"thread summarize": {auth.ScopeGmailReadonly},
+
+	"cal":                {auth.ScopeCalendarReadonly},
+	"cal show":           {auth.ScopeCalendarReadonly, auth.ScopeDriveReadonly},
+	"cal create":         {auth.ScopeCalendarEvents},
+	"cal update":         {auth.ScopeCalendarEvents},
+	"cal delete":         {auth.ScopeCalendarEvents},
+	"cal move":           {auth.ScopeCalendarEvents},
+	"cal quick":          {auth.ScopeCalendarEvents},
+	"cal rsvp":           {auth.ScopeCalendarEvents},
+	"cal import":         {auth.ScopeCalendarEvents},
+	"cal share":          {auth.ScopeCalendar},
+	"cal unshare":        {auth.ScopeCalendar},
+	"cal acl":            {auth.ScopeCalendar},
+	"cal acl list":       {auth.ScopeCalendarReadonly},
+	"cal calendars":      {auth.ScopeCalendar},
+	"cal calendars list": {auth.ScopeCalendarReadonly},
+	"cal calendars show": {auth.ScopeCalendarReadonly},
+
+	"tasks":       {auth.ScopeTasks},
+	"tasks list":  {auth.ScopeTasksReadonly},
+	"tasks lists": {auth.ScopeTasksReadonly},
+	"tasks get":   {auth.ScopeTasksReadonly},
+
+	"contacts":               {auth.ScopeContacts},
+	"contacts list":          {auth.ScopeContactsReadonly},
+	"contacts get":           {auth.ScopeContactsReadonly},
+	"contacts search":        {auth.ScopeContactsReadonly},
+	"contacts groups":        {auth.ScopeContactsReadonly},
+	"contacts group-members": {auth.ScopeContactsReadonly},
+
+	"meet": {auth.ScopeCalendarReadonly, auth.ScopeGmailReadonly},
+
+	"rules":      {auth.ScopeGmailModify},
+	"rules test": {auth.ScopeGmailReadonly},
+
+	"context set": {auth.ScopeCalendarReadonly},
+
+	"review": {auth.ScopeGmailReadonly, auth.ScopeCalendarReadonly, auth.ScopeTasksReadonly},
+
+	"bridge imap":   {auth.ScopeGmailReadonly},
+	"bridge caldav": {auth.ScopeCalendarReadonly},
+}
+
+// identityScopes are requested at every login to identify the account, so
+// they are never reported as broader than needed.
+var identityScopes = map[string]bool{
+	auth.ScopeUserInfoEmail:   true,
+	auth.ScopeUserInfoProfile: true,
+	auth.ScopeOpenID:          true,
+}
+
+// parseCommandList splits a --for value such as "mail list, cal today"
+// into trimmed command lines.
+func parseCommandList(list string) []string {
+	var commands []string
+	for _, command := range strings.Split(list, ",") {
+		if command = strings.Join(strings.Fields(command), " "); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// scopesForCommand returns the scopes declared for a command line. The
+// first word may be a user-defined alias, which is expanded first.
+func scopesForCommand(command string, aliases map[string]string) ([]string, error) {
+	words, err := splitCommandLine(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command %q: %w", command, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("command cannot be empty")
+	}
+	if words[0] == rootCmd.Name() {
+		words = words[1:]
+	}
+	if expansion, ok := aliases[words[0]]; ok && !isBuiltinCommand(words[0]) {
+		expanded, err := splitCommandLine(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %s: %w", words[0], err)
+		}
+		words = append(expanded, words[1:]...)
+	}
+
+	found, rest, err := rootCmd.Find(words)
+	if err != nil || found == rootCmd || (found.HasSubCommands() && len(rest) > 0 && !strings.HasPrefix(rest[0], "-")) {
+		return nil, fmt.Errorf("unknown command %q", command)
+	}
+	if !found.Runnable() {
+		return nil, fmt.Errorf("%q is a command group; name one of its commands", command)
+	}
+	if found == apiCmd {
+		// goog api needs the scopes of the method it calls
+		if len(rest) == 0 || apiMethods[rest[0]] == nil {
+			return nil, fmt.Errorf("%q needs a method, e.g. api mail.send", command)
+		}
+		return apiMethods[rest[0]].Scopes, nil
+	}
+	for c := found; c != nil && c != rootCmd; c = c.Parent() {
+		if scopes, ok := commandScopes[commandKey(c)]; ok {
+			return scopes, nil
+		}
+	}
+	return nil, fmt.Errorf("%q does not use Google data, so it needs no scopes", command)
+}
+
+// commandKey returns the command's path without the root command name.
+func commandKey(c *cobra.Command) string {
+	return strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+}
+
+// scopesForCommands returns the smallest set of scopes that covers every
+// command, dropping scopes implied by a broader one in the set.
+func scopesForCommands(commands []string, aliases map[string]string) ([]string, error) {
+	needed := make(map[string]bool)
+	for _, command := range commands {
+		scopes, err := scopesForCommand(command, aliases)
+		if err != nil {
+			return nil, err
+		}
+		for _, scope := range scopes {
+			needed[scope] = true
+		}
+	}
+
+	result := make([]string, 0, len(needed))
+	for scope := range needed {
+		if len(missingScopes([]string{scope}, otherScopes(needed, scope))) == 0 {
+			continue
+		}
+		result = append(result, scope)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// otherScopes returns the scopes in set other than scope.
+func otherScopes(set map[string]bool, scope string) []string {
+	others := make([]string, 0, len(set))
+	for s := range set {
+		if s != scope {
+			others = append(others, s)
+		}
+	}
+	return others
+}
+
+// broaderScopes returns the granted scopes that none of the required
+// scopes calls for, either directly or as a narrower scope of it. Identity
+// scopes are not reported.
+func broaderScopes(granted, required []string) []string {
+	var broader []string
+	for _, scope := range granted {
+		if !identityScopes[scope] && len(missingScopes([]string{scope}, required)) > 0 {
+			broader = append(broader, scope)
+		}
+	}
+	return broader
+}
+
+// requireScope checks that the account in use was granted scope, directly
+// or through a broader scope, before a command relies on it. The error
+// says how to sign in again with the scopes of command added to those of
+// the account's commands. Accounts whose scopes were not recorded pass.
+func requireScope(scope, command string) error {
+	svc := GetDependencies().AccountService
+	acc, err := svc.ResolveAccount(accountFlag)
+	if err != nil {
+		return fmt.Errorf("no account found: %w (run 'goog auth login' to authenticate)", err)
+	}
+	granted, err := svc.GetTokenManager().GetGrantedScopes(acc.Alias)
+	if errors.Is(err, auth.ErrScopesNotSet) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get granted scopes: %w", err)
+	}
+	if len(missingScopes([]string{scope}, granted)) == 0 {
+		return nil
+	}
+
+	commands := acc.Commands
+	if !slices.Contains(commands, command) {
+		commands = append(slices.Clone(commands), command)
+	}
+	return fmt.Errorf("account %s was not granted %s, which %s needs; run 'goog auth login --account %s --for %q' to add it",
+		acc.Alias, scope, command, acc.Alias, strings.Join(commands, ", "))
+}
+
+// configuredCommands returns the commands an account is meant to run: the
+// commands it was authorized for with --for, or else the user's aliases.
+func configuredCommands(commands []string) ([]string, map[string]string) {
+	cfg, err := config.Load()
+	if err != nil {
+		return commands, nil
+	}
+	if len(commands) > 0 {
+		return commands, cfg.Aliases
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, cfg.Aliases
+}
+
+// warnBroaderScopes warns when the account was granted scopes that none of
+// its configured commands needs.
+func warnBroaderScopes(cmd *cobra.Command, alias string, granted, commands []string) {
+	commands, aliases := configuredCommands(commands)
+	if len(commands) == 0 {
+		return
+	}
+
+	var required []string
+	for _, command := range commands {
+		// Commands that no longer exist or need no scopes are skipped
+		scopes, err := scopesForCommand(command, aliases)
+		if err == nil {
+			required = append(required, scopes...)
+		}
+	}
+	broader := broaderScopes(granted, required)
+	if len(broader) == 0 || output.Quiet() {
+		return
+	}
+	output.Warnf(cmd, "account %s has scopes its commands (%s) do not need:", alias, strings.Join(commands, ", "))
+	for _, scope := range broader {
+		cmd.PrintErrf("  - %s\n", scope)
+	}
+	cmd.PrintErrf("Remove the account and sign in again with 'goog auth login --account %s --for \"...\"' to drop them\n", alias)
+}
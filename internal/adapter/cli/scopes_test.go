@@ -0,0 +1,201 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestCommandScopesKeysExist(t *testing.T) {
+	for key := range commandScopes {
+		found, _, err := rootCmd.Find(strings.Fields(key))
+		if err != nil || commandKey(found) != key {
+			t.Errorf("commandScopes key %q does not name a command", key)
+		}
+	}
+}
+
+func TestScopesForCommands(t *testing.T) {
+	aliases := map[string]string{"inbox": "mail list --unread-only"}
+	tests := []struct {
+		name     string
+		commands string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "read-only commands",
+			commands: "mail list, cal today",
+			want:     []string{auth.ScopeCalendarReadonly, auth.ScopeGmailReadonly},
+		},
+		{
+			name:     "broader scope covers narrower",
+			commands: "mail search, mail trash, goog mail read",
+			want:     []string{auth.ScopeGmailModify},
+		},
+		{
+			name:     "nested and flagged commands",
+			commands: "cal acl list --calendar-id primary, mail todo add",
+			want:     []string{auth.ScopeCalendarReadonly, auth.ScopeGmailModify},
+		},
+		{
+			name:     "aliases",
+			commands: "inbox, tasks create",
+			want:     []string{auth.ScopeGmailReadonly, auth.ScopeTasks},
+		},
+		{
+			name:     "calendar writes",
+			commands: "cal import, cal today",
+			want:     []string{auth.ScopeCalendarEvents},
+		},
+		{
+			name:     "attachment downloads",
+			commands: "cal show",
+			want:     []string{auth.ScopeCalendarReadonly, auth.ScopeDriveReadonly},
+		},
+		{
+			name:     "top-level command",
+			commands: "review --week",
+			want:     []string{auth.ScopeCalendarReadonly, auth.ScopeGmailReadonly, auth.ScopeTasksReadonly},
+		},
+		{
+			name:     "api methods",
+			commands: "api mail.send, api cal.list --input req.json",
+			want:     []string{auth.ScopeCalendarReadonly, auth.ScopeGmailSend},
+		},
+		{name: "api without a method", commands: "api", wantErr: "needs a method"},
+		{name: "unknown subcommand", commands: "mail sweep", wantErr: "unknown command"},
+		{name: "command group", commands: "cal", wantErr: "command group"},
+		{name: "local command", commands: "config show", wantErr: "does not use Google data"},
+		{name: "not a command", commands: "frobnicate", wantErr: "unknown command"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scopesForCommands(parseCommandList(tt.commands), aliases)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scopes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroaderScopes(t *testing.T) {
+	granted := []string{auth.ScopeGmailModify, auth.ScopeCalendarReadonly, auth.ScopeDriveReadonly, auth.ScopeUserInfoEmail, auth.ScopeOpenID}
+	required := []string{auth.ScopeGmailReadonly, auth.ScopeCalendarEvents}
+
+	got := broaderScopes(granted, required)
+	want := []string{auth.ScopeGmailModify, auth.ScopeDriveReadonly}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("broaderScopes = %v, want %v", got, want)
+	}
+}
+
+func TestRunAuthLogin_For(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	origFor, origScopes, origAccount := authFor, authScopes, accountFlag
+	defer func() { authFor, authScopes, accountFlag = origFor, origScopes, origAccount }()
+	authFor, authScopes, accountFlag = "mail list, cal today", nil, "work"
+
+	ResetDependencies()
+	defer ResetDependencies()
+	var requested []string
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountServiceExtended{
+			AddFunc: func(ctx context.Context, alias string, scopes []string) (*accountuc.Account, error) {
+				requested = scopes
+				cfg := config.NewConfig()
+				cfg.Accounts[alias] = config.AccountConfig{Email: "work@example.com", Scopes: scopes}
+				if err := cfg.Save(); err != nil {
+					return nil, err
+				}
+				return &accountuc.Account{Alias: alias, Email: "work@example.com"}, nil
+			},
+		},
+		RepoFactory: &MockRepositoryFactory{},
+	})
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{}
+	cmd.SetOut(buf)
+	if err := runAuthLogin(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{auth.ScopeCalendarReadonly, auth.ScopeGmailReadonly, auth.ScopeUserInfoEmail, auth.ScopeOpenID}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested scopes = %v, want %v", requested, want)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Accounts["work"].Commands; !reflect.DeepEqual(got, []string{"mail list", "cal today"}) {
+		t.Errorf("recorded commands = %v", got)
+	}
+	if !strings.Contains(buf.String(), "Scopes limited to what mail list, cal today need") {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+
+	authScopes = []string{"gmail"}
+	if err := runAuthLogin(cmd, nil); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected an error for --for with --scopes, got %v", err)
+	}
+}
+
+func TestWarnBroaderScopes(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Aliases["inbox"] = "mail list --unread-only"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	granted := []string{auth.ScopeGmailModify, auth.ScopeUserInfoEmail}
+
+	t.Run("recorded commands", func(t *testing.T) {
+		errBuf := new(bytes.Buffer)
+		cmd := &cobra.Command{}
+		cmd.SetErr(errBuf)
+		warnBroaderScopes(cmd, "work", granted, []string{"mail list"})
+		if !strings.Contains(errBuf.String(), auth.ScopeGmailModify) || strings.Contains(errBuf.String(), auth.ScopeUserInfoEmail) {
+			t.Errorf("unexpected warning: %q", errBuf.String())
+		}
+	})
+
+	t.Run("aliases when no commands were recorded", func(t *testing.T) {
+		errBuf := new(bytes.Buffer)
+		cmd := &cobra.Command{}
+		cmd.SetErr(errBuf)
+		warnBroaderScopes(cmd, "work", granted, nil)
+		if !strings.Contains(errBuf.String(), "(inbox)") {
+			t.Errorf("unexpected warning: %q", errBuf.String())
+		}
+	})
+
+	t.Run("no warning when scopes fit", func(t *testing.T) {
+		errBuf := new(bytes.Buffer)
+		cmd := &cobra.Command{}
+		cmd.SetErr(errBuf)
+		warnBroaderScopes(cmd, "work", granted, []string{"mail trash"})
+		if errBuf.Len() != 0 {
+			t.Errorf("unexpected warning: %q", errBuf.String())
+		}
+	})
+}
@@ -4,6 +4,8 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
 	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
@@ -67,6 +69,7 @@ func getTokenSource(ctx context.Context) (oauth2.TokenSource, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token: %w (run 'goog auth login' to authenticate)", err)
 	}
+	markAccountUsed(acc.Alias)
 
 	return tokenSource, nil
 }
@@ -87,10 +90,18 @@ func getTokenSourceWithEmail(ctx context.Context) (oauth2.TokenSource, string, e
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get token: %w (run 'goog auth login' to authenticate)", err)
 	}
+	markAccountUsed(acc.Alias)
 
 	return tokenSource, acc.Email, nil
 }
 
+// markAccountUsed records that the account alias was used for 'goog account
+// switch' and 'goog account list'. Failing to record it never fails a
+// command.
+func markAccountUsed(alias string) {
+	_ = config.MarkAccountUsed(alias, time.Now())
+}
+
 // =============================================================================
 // Dependency Injection-based Factory Functions
 // =============================================================================
@@ -133,6 +144,20 @@ func getTokenSourceWithEmailFromDeps(ctx context.Context) (oauth2.TokenSource, s
 	return tokenSource, acc.Email, nil
 }
 
+// accountMetadata returns the metadata of the account in use, or nil when
+// the account cannot be resolved.
+func accountMetadata() map[string]string {
+	svc := GetDependencies().AccountService
+	if svc == nil {
+		return nil
+	}
+	acc, err := svc.ResolveAccount(accountFlag)
+	if err != nil {
+		return nil
+	}
+	return acc.Metadata
+}
+
 // getMessageRepositoryFromDeps creates a message repository using injected dependencies.
 func getMessageRepositoryFromDeps(ctx context.Context) (MessageRepository, string, error) {
 	tokenSource, email, err := getTokenSourceWithEmailFromDeps(ctx)
@@ -197,6 +222,22 @@ func getLabelRepositoryFromDeps(ctx context.Context) (LabelRepository, error) {
 	return repo, nil
 }
 
+// getAttachmentRepositoryFromDeps creates an attachment repository using injected dependencies.
+func getAttachmentRepositoryFromDeps(ctx context.Context) (AttachmentRepository, error) {
+	tokenSource, err := getTokenSourceFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := GetDependencies()
+	repo, err := deps.RepoFactory.NewAttachmentRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment repository: %w", err)
+	}
+
+	return repo, nil
+}
+
 // getEventRepositoryFromDeps creates an event repository using injected dependencies.
 func getEventRepositoryFromDeps(ctx context.Context) (EventRepository, error) {
 	tokenSource, err := getTokenSourceFromDeps(ctx)
@@ -261,6 +302,77 @@ func getFreeBusyRepositoryFromDeps(ctx context.Context) (FreeBusyRepository, err
 	return repo, nil
 }
 
+// getDriveFileRepositoryFromDeps creates a Drive file repository using injected dependencies.
+func getDriveFileRepositoryFromDeps(ctx context.Context) (DriveFileRepository, error) {
+	tokenSource, err := getTokenSourceFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := GetDependencies()
+	repo, err := deps.RepoFactory.NewDriveFileRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive file repository: %w", err)
+	}
+
+	return repo, nil
+}
+
+// getTranslatorFromDeps creates a translation backend using injected
+// dependencies and the API key from GOOG_TRANSLATE_API_KEY or the
+// mail.translate_api_key setting.
+func getTranslatorFromDeps(ctx context.Context) (Translator, error) {
+	apiKey := os.Getenv("GOOG_TRANSLATE_API_KEY")
+	if apiKey == "" {
+		if cfg, err := config.Load(); err == nil {
+			apiKey = cfg.Mail.TranslateAPIKey
+		}
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("no translation backend configured: set mail.translate_api_key or GOOG_TRANSLATE_API_KEY")
+	}
+
+	deps := GetDependencies()
+	translator, err := deps.RepoFactory.NewTranslator(ctx, apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create translator: %w", err)
+	}
+
+	return translator, nil
+}
+
+// getTaskListRepositoryFromDeps creates a task list repository using injected dependencies.
+func getTaskListRepositoryFromDeps(ctx context.Context) (TaskListRepository, error) {
+	tokenSource, err := getTokenSourceFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := GetDependencies()
+	repo, err := deps.RepoFactory.NewTaskListRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task list repository: %w", err)
+	}
+
+	return repo, nil
+}
+
+// getTaskRepositoryFromDeps creates a task repository using injected dependencies.
+func getTaskRepositoryFromDeps(ctx context.Context) (TaskRepository, error) {
+	tokenSource, err := getTokenSourceFromDeps(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := GetDependencies()
+	repo, err := deps.RepoFactory.NewTaskRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task repository: %w", err)
+	}
+
+	return repo, nil
+}
+
 // getContactRepositoryFromDeps creates a contact repository using injected dependencies.
 func getContactRepositoryFromDeps(ctx context.Context) (ContactRepository, error) {
 	tokenSource, err := getTokenSourceFromDeps(ctx)
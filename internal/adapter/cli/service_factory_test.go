@@ -325,6 +325,51 @@ func TestGetLabelRepositoryFromDeps_Error(t *testing.T) {
 	}
 }
 
+func TestGetAttachmentRepositoryFromDeps_Success(t *testing.T) {
+	deps := &Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			AttachmentRepo: &MockAttachmentRepository{},
+		},
+	}
+
+	SetDependencies(deps)
+	defer ResetDependencies()
+
+	ctx := context.Background()
+	repo, err := getAttachmentRepositoryFromDeps(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo == nil {
+		t.Error("expected non-nil repository")
+	}
+}
+
+func TestGetAttachmentRepositoryFromDeps_Error(t *testing.T) {
+	deps := &Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "test@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{
+			AttachmentErr: fmt.Errorf("attachment factory error"),
+		},
+	}
+
+	SetDependencies(deps)
+	defer ResetDependencies()
+
+	ctx := context.Background()
+	_, err := getAttachmentRepositoryFromDeps(ctx)
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
 func TestGetEventRepositoryFromDeps_Success(t *testing.T) {
 	deps := &Dependencies{
 		AccountService: &MockAccountService{
@@ -0,0 +1,254 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// unsettableFlags are flags that per-command settings never default: they
+// select the account and config file the settings come from.
+var unsettableFlags = map[string]bool{
+	"account": true,
+	"config":  true,
+	"help":    true,
+}
+
+// settingSynonyms maps flag names to additional setting names, so
+// mail.list.page_size defaults --max-results like mail.list.max_results.
+var settingSynonyms = map[string]string{
+	"max-results": "page_size",
+}
+
+// configSettingsCmd lists the per-command settings in effect.
+var configSettingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Show per-command settings and where they come from",
+	Long: `Show the per-command settings in effect and the layer each one
+comes from.
+
+A per-command setting defaults a flag of one command. Its key is the
+command path and the flag name with underscores, e.g.
+mail.list.max_results for "goog mail list --max-results" (page_size is
+accepted as a synonym for max_results) or mail.search.format for the
+global --format flag of "goog mail search".
+
+Settings are resolved in this order, the first one found wins:
+  flag      - the flag given on the command line
+  env       - GOOG_<KEY>, e.g. GOOG_MAIL_LIST_MAX_RESULTS
+  local     - settings: in the nearest .goog.yaml in the working
+              directory or its parents
+  profile   - settings of the account in use (config set --account)
+  global    - settings: in the config file
+
+Environment variables are only listed for keys set in a file.`,
+	Example: `  # Default mail list to 50 messages
+  goog config set mail.list.page_size 50
+
+  # Show mail search results as JSON, for the work account only
+  goog config set mail.search.format json --account work
+
+  # See what applies in this directory
+  goog config settings`,
+	Args: cobra.NoArgs,
+	RunE: runConfigSettings,
+}
+
+func init() {
+	configCmd.AddCommand(configSettingsCmd)
+}
+
+// settingsResolver builds the resolver for the account selected by
+// --account and the working directory. An unreadable .goog.yaml is
+// reported and ignored.
+func settingsResolver(cmd *cobra.Command) *config.Resolver {
+	var cfg *config.Config
+	if _, err := os.Stat(config.GetConfigPath()); err == nil {
+		cfg, _ = config.Load()
+	}
+
+	alias := ""
+	if cfg != nil && anyAccount(func(acc config.AccountConfig) bool { return len(acc.Settings) > 0 }) {
+		if acc, err := GetDependencies().AccountService.ResolveAccount(accountFlag); err == nil {
+			alias = acc.Alias
+		}
+	}
+
+	dir, _ := os.Getwd()
+	resolver, err := config.NewResolver(cfg, alias, dir)
+	if err != nil {
+		output.Warnf(cmd, "ignoring %s: %v", config.LocalFileName, err)
+		resolver, _ = config.NewResolver(cfg, alias, "")
+	}
+	return resolver
+}
+
+// settingPrefix returns the setting key prefix of cmd: its command path
+// without the root command, joined with dots.
+func settingPrefix(cmd *cobra.Command) string {
+	path := strings.Fields(cmd.CommandPath())
+	if len(path) < 2 {
+		return ""
+	}
+	return strings.Join(path[1:], ".")
+}
+
+// settingKeys returns the setting keys that default a flag of the command
+// with the given prefix, the flag's own name first.
+func settingKeys(prefix, flag string) []string {
+	keys := []string{prefix + "." + strings.ReplaceAll(flag, "-", "_")}
+	if synonym, ok := settingSynonyms[flag]; ok {
+		keys = append(keys, prefix+"."+synonym)
+	}
+	return keys
+}
+
+// applySettings sets every flag of cmd that was not given on the command
+// line from the per-command settings. Invalid values are reported and
+// ignored, so a bad setting never prevents running "goog config set" to
+// fix it.
+func applySettings(cmd *cobra.Command) {
+	prefix := settingPrefix(cmd)
+	if prefix == "" {
+		return
+	}
+	resolver := settingsResolver(cmd)
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || unsettableFlags[f.Name] {
+			return
+		}
+		keys := settingKeys(prefix, f.Name)
+		value, layer, ok := resolver.Lookup(keys...)
+		if !ok {
+			return
+		}
+		if err := cmd.Flags().Set(f.Name, value); err != nil {
+			output.Warnf(cmd, "ignoring %s setting %s=%q: %v", layer, keys[0], value, err)
+		}
+	})
+}
+
+// commandSetting reports whether key names a per-command setting: the
+// path of an existing command followed by one of its flags, including the
+// persistent flags of its parents.
+func commandSetting(key string) bool {
+	if !config.ValidSettingKey(key) {
+		return false
+	}
+	parts := strings.Split(key, ".")
+	target := rootCmd
+	flagSets := []*pflag.FlagSet{rootCmd.PersistentFlags()}
+	for _, name := range parts[:len(parts)-1] {
+		var next *cobra.Command
+		for _, sub := range target.Commands() {
+			if sub.Name() == name {
+				next = sub
+			}
+		}
+		if next == nil {
+			return false
+		}
+		target = next
+		flagSets = append(flagSets, target.PersistentFlags())
+	}
+	flagSets = append(flagSets, target.Flags())
+
+	name := parts[len(parts)-1]
+	found := false
+	for _, flags := range flagSets {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if !unsettableFlags[f.Name] && slices.Contains(settingKeys("", f.Name), "."+name) {
+				found = true
+			}
+		})
+	}
+	return found
+}
+
+// accountAlias returns the alias of the configured account named by an
+// alias or email address.
+func accountAlias(cfg *config.Config, name string) (string, error) {
+	if _, ok := cfg.Accounts[name]; ok {
+		return name, nil
+	}
+	for alias, acc := range cfg.Accounts {
+		if strings.EqualFold(acc.Email, name) {
+			return alias, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", config.ErrAccountNotFound, name)
+}
+
+// setCommandSetting stores a per-command setting in the profile of the
+// account given with --account, or in the global settings.
+func setCommandSetting(cfg *config.Config, key, value string) error {
+	if accountFlag == "" {
+		return cfg.SetSetting(key, value)
+	}
+	alias, err := accountAlias(cfg, accountFlag)
+	if err != nil {
+		return err
+	}
+	return cfg.SetAccountSetting(alias, key, value)
+}
+
+// getCommandSetting returns a per-command setting from the profile of the
+// account given with --account, or from the global settings.
+func getCommandSetting(cfg *config.Config, key string) (string, error) {
+	settings := cfg.Settings
+	if accountFlag != "" {
+		alias, err := accountAlias(cfg, accountFlag)
+		if err != nil {
+			return "", err
+		}
+		settings = cfg.Accounts[alias].Settings
+	}
+	value, ok := settings.Get(key)
+	if !ok {
+		return "", fmt.Errorf("setting %s is not set", key)
+	}
+	return value, nil
+}
+
+// settingJSON is the JSON representation of a resolved setting.
+type settingJSON struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// runConfigSettings handles the config settings command.
+func runConfigSettings(cmd *cobra.Command, args []string) error {
+	all := settingsResolver(cmd).All()
+
+	if formatFlag == presenter.FormatJSON {
+		out := make([]settingJSON, len(all))
+		for i, s := range all {
+			out[i] = settingJSON{Key: s.Key, Value: s.Value, Source: s.Layer}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode settings: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if len(all) == 0 {
+		cmd.Println("No per-command settings")
+		return nil
+	}
+	for _, s := range all {
+		cmd.Printf("%-35s %-20s (%s)\n", s.Key, s.Value, s.Layer)
+	}
+	return nil
+}
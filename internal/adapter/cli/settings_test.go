@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+func TestApplySettings(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com"}
+	_ = cfg.SetSetting("mail.list.page_size", "50")
+	_ = cfg.SetSetting("mail.list.unread_only", "true")
+	_ = cfg.SetSetting("mail.list.labels", "not-a-number")
+	_ = cfg.SetAccountSetting("work", "mail.list.format", "plain")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, config.LocalFileName), []byte("settings:\n  mail:\n    list:\n      max_results: 30\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{Account: &accountuc.Account{Alias: "work", Email: "work@example.com"}},
+	})
+	t.Cleanup(ResetDependencies)
+
+	root := &cobra.Command{Use: "goog"}
+	var format string
+	root.PersistentFlags().StringVar(&format, "format", "table", "")
+	mail := &cobra.Command{Use: "mail"}
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	var maxResults, labels int
+	var unreadOnly bool
+	list.Flags().IntVar(&maxResults, "max-results", 10, "")
+	list.Flags().IntVar(&labels, "labels", 0, "")
+	list.Flags().BoolVar(&unreadOnly, "unread-only", false, "")
+	root.AddCommand(mail)
+	mail.AddCommand(list)
+	if err := list.ParseFlags([]string{"--unread-only=false"}); err != nil {
+		t.Fatal(err)
+	}
+	errBuf := new(bytes.Buffer)
+	list.SetErr(errBuf)
+
+	applySettings(list)
+
+	if maxResults != 30 {
+		t.Errorf("max-results = %d, want 30 from the local file", maxResults)
+	}
+	if format != "plain" {
+		t.Errorf("format = %q, want plain from the profile", format)
+	}
+	if unreadOnly {
+		t.Error("expected the flag given on the command line to win")
+	}
+	if labels != 0 || !contains(errBuf.String(), "ignoring global setting mail.list.labels") {
+		t.Errorf("expected the invalid setting to be ignored, got %d and %q", labels, errBuf.String())
+	}
+}
+
+func TestCommandSetting(t *testing.T) {
+	for key, want := range map[string]bool{
+		"mail.list.max_results": true,
+		"mail.list.page_size":   true,
+		"mail.search.format":    true,
+		"cal.today.calendar":    true,
+		"mail.list.account":     false,
+		"mail.list.nope":        false,
+		"mail.nope.max_results": false,
+		"mail.page_size":        false,
+		"mail.list":             false,
+	} {
+		if got := commandSetting(key); got != want {
+			t.Errorf("commandSetting(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestRunConfigSet_CommandSetting(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := config.NewConfig()
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com"}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	origAccount := accountFlag
+	t.Cleanup(func() { accountFlag = origAccount })
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(new(bytes.Buffer))
+	accountFlag = ""
+	if err := runConfigSet(cmd, []string{"mail.list.page_size", "50"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	accountFlag = "work@example.com"
+	if err := runConfigSet(cmd, []string{"mail.search.format", "json"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := runConfigSet(cmd, []string{"mail.list.nope", "1"}); err == nil {
+		t.Error("expected an error for a key that is neither a config key nor a setting")
+	}
+
+	loaded, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Settings.Get("mail.list.page_size"); got != "50" {
+		t.Errorf("global setting = %q", got)
+	}
+	if got, _ := loaded.Accounts["work"].Settings.Get("mail.search.format"); got != "json" {
+		t.Errorf("profile setting = %q", got)
+	}
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	if err := runConfigGet(cmd, []string{"mail.search.format"}); err != nil || buf.String() != "json\n" {
+		t.Errorf("config get = %q, %v", buf.String(), err)
+	}
+}
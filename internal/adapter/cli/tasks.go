@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/dateparse"
 	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
 	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
 )
@@ -18,6 +19,7 @@ var (
 	tasksDueMax        string
 	tasksDueMin        string
 	tasksMaxResults    int64
+	tasksPageToken     string
 	tasksTitle         string
 	tasksNotes         string
 	tasksDue           string
@@ -321,7 +323,7 @@ func runTasksLists(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTaskLists(lists))
 
 	return nil
@@ -362,7 +364,7 @@ func runTasksCreateList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTaskList(created))
 
 	return nil
@@ -397,7 +399,7 @@ func runTasksDeleteList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete task list: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Task list '%s' deleted", listID)))
 
 	return nil
@@ -442,7 +444,7 @@ func runTasksUpdateList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTaskList(updated))
 
 	return nil
@@ -475,6 +477,7 @@ func runTasksList(cmd *cobra.Command, args []string) error {
 		ShowCompleted: tasksShowCompleted,
 		ShowHidden:    tasksShowHidden,
 		MaxResults:    tasksMaxResults,
+		PageToken:     tasksPageToken,
 	}
 
 	// List tasks
@@ -484,8 +487,11 @@ func runTasksList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
-	cmd.Println(p.RenderTasks(result.Items))
+	p := newPresenter()
+	printList(cmd, p.RenderTasks(result.Items), presenter.Page{
+		NextPageToken: result.NextPageToken,
+		FetchedCount:  len(result.Items),
+	})
 
 	return nil
 }
@@ -520,7 +526,7 @@ func runTasksGet(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(task))
 
 	return nil
@@ -560,9 +566,9 @@ func runTasksCreate(cmd *cobra.Command, args []string) error {
 		task.Notes = tasksNotes
 	}
 	if tasksDue != "" {
-		dueDate, err := time.Parse("2006-01-02", tasksDue)
+		dueDate, err := parseDueDate(tasksDue)
 		if err != nil {
-			return fmt.Errorf("invalid due date format (use YYYY-MM-DD): %w", err)
+			return err
 		}
 		task.Due = &dueDate
 	}
@@ -579,7 +585,7 @@ func runTasksCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(created))
 
 	return nil
@@ -622,9 +628,9 @@ func runTasksUpdate(cmd *cobra.Command, args []string) error {
 		task.Notes = tasksNotes
 	}
 	if tasksDue != "" {
-		dueDate, err := time.Parse("2006-01-02", tasksDue)
+		dueDate, err := parseDueDate(tasksDue)
 		if err != nil {
-			return fmt.Errorf("invalid due date format (use YYYY-MM-DD): %w", err)
+			return err
 		}
 		task.Due = &dueDate
 	}
@@ -636,7 +642,7 @@ func runTasksUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(updated))
 
 	return nil
@@ -683,7 +689,7 @@ func runTasksComplete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(updated))
 
 	return nil
@@ -730,7 +736,7 @@ func runTasksReopen(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(updated))
 
 	return nil
@@ -765,7 +771,7 @@ func runTasksDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess(fmt.Sprintf("Task '%s' deleted", taskID)))
 
 	return nil
@@ -801,7 +807,7 @@ func runTasksMove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Render output
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderTask(moved))
 
 	return nil
@@ -835,7 +841,7 @@ func runTasksClear(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to clear completed tasks: %w", err)
 	}
 
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 	cmd.Println(p.RenderSuccess("Completed tasks cleared"))
 
 	return nil
@@ -870,16 +876,17 @@ func init() {
 	tasksListCmd.Flags().BoolVar(&tasksShowCompleted, "show-completed", false, "include completed tasks")
 	tasksListCmd.Flags().BoolVar(&tasksShowHidden, "show-hidden", false, "include hidden tasks")
 	tasksListCmd.Flags().Int64Var(&tasksMaxResults, "max-results", 100, "maximum number of tasks to return")
+	tasksListCmd.Flags().StringVar(&tasksPageToken, "page-token", "", "token of the page to list, from next_page_token of --envelope output")
 
 	// Flags for tasks create
 	tasksCreateCmd.Flags().StringVar(&tasksNotes, "notes", "", "task notes")
-	tasksCreateCmd.Flags().StringVar(&tasksDue, "due", "", "due date (YYYY-MM-DD)")
+	tasksCreateCmd.Flags().StringVar(&tasksDue, "due", "", "due date (e.g. 2025-08-01, tomorrow, friday, +3d)")
 	tasksCreateCmd.Flags().StringVar(&tasksParent, "parent", "", "parent task ID (for subtasks)")
 
 	// Flags for tasks update
 	tasksUpdateCmd.Flags().StringVar(&tasksTitle, "title", "", "new task title")
 	tasksUpdateCmd.Flags().StringVar(&tasksNotes, "notes", "", "new task notes")
-	tasksUpdateCmd.Flags().StringVar(&tasksDue, "due", "", "new due date (YYYY-MM-DD)")
+	tasksUpdateCmd.Flags().StringVar(&tasksDue, "due", "", "new due date (e.g. 2025-08-01, tomorrow, friday, +3d)")
 
 	// Flags for update-list
 	tasksUpdateListCmd.Flags().StringVar(&tasksTitle, "title", "", "new task list title")
@@ -893,3 +900,13 @@ func init() {
 	tasksMoveCmd.Flags().StringVar(&tasksParent, "parent", "", "parent task ID")
 	tasksMoveCmd.Flags().StringVar(&tasksPrevious, "previous", "", "previous sibling task ID")
 }
+
+// parseDueDate parses a due date expression. Google Tasks stores due dates
+// without a time, so only the calendar day is kept, as midnight UTC.
+func parseDueDate(input string) (time.Time, error) {
+	t, err := dateparse.Parse(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date: %w", err)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
@@ -1718,3 +1718,27 @@ func TestRunTasksClear_RepositoryError(t *testing.T) {
 		t.Error("expected error from repository")
 	}
 }
+
+func TestParseDueDate(t *testing.T) {
+	got, err := parseDueDate("2025-08-01 15:30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("parseDueDate() = %v, want %v", got, want)
+	}
+
+	got, err = parseDueDate("tomorrow")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if got.Day() != tomorrow.Day() || got.Hour() != 0 || got.Location() != time.UTC {
+		t.Errorf("parseDueDate(tomorrow) = %v, want midnight UTC on %s", got, tomorrow.Format("2006-01-02"))
+	}
+
+	if _, err := parseDueDate("whenever"); err == nil || !containsStr(err.Error(), "invalid due date") {
+		t.Errorf("expected invalid due date error, got %v", err)
+	}
+}
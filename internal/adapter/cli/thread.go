@@ -16,6 +16,9 @@ import (
 var (
 	threadMaxResults    int
 	threadLabels        []string
+	threadIncludeMuted  bool
+	threadPageToken     string
+	threadSpamTrash     bool
 	threadAddLabels     []string
 	threadRemoveLabels  []string
 	threadDeleteConfirm bool
@@ -29,7 +32,8 @@ var threadCmd = &cobra.Command{
 
 Threads group related messages together in a conversation.
 The thread commands allow you to list, view, trash, and
-modify labels on entire threads at once.`,
+modify labels on entire threads at once, and to mark many threads
+read, unread or starred with 'read', 'unread', 'star' and 'sweep'.`,
 }
 
 // threadListCmd lists threads.
@@ -39,7 +43,8 @@ var threadListCmd = &cobra.Command{
 	Long: `List email threads in your account.
 
 Displays threads with their ID, snippet, and message count.
-Use --labels to filter by specific labels.`,
+Use --labels to filter by specific labels. Muted threads are left
+out unless --include-muted is given or the mute label is listed.`,
 	Aliases: []string{"ls"},
 	Example: `  # List recent threads
   goog thread list
@@ -63,7 +68,7 @@ var threadShowCmd = &cobra.Command{
 
 Displays thread details including all messages in the
 conversation in chronological order.`,
-	Aliases: []string{"get", "read"},
+	Aliases: []string{"get"},
 	Example: `  # Show thread by ID
   goog thread show abc123
 
@@ -163,6 +168,9 @@ func init() {
 	// List flags
 	threadListCmd.Flags().IntVar(&threadMaxResults, "max-results", 20, "maximum number of threads to list")
 	threadListCmd.Flags().StringSliceVar(&threadLabels, "labels", nil, "filter by label IDs")
+	threadListCmd.Flags().BoolVar(&threadIncludeMuted, "include-muted", false, "also list muted threads")
+	threadListCmd.Flags().BoolVar(&threadSpamTrash, "include-spam-trash", false, "also list threads in SPAM and TRASH")
+	threadListCmd.Flags().StringVar(&threadPageToken, "page-token", "", "token of the page to list, from next_page_token of --envelope output")
 
 	// Modify flags
 	threadModifyCmd.Flags().StringSliceVar(&threadAddLabels, "add-labels", nil, "labels to add")
@@ -202,8 +210,11 @@ func runThreadList(cmd *cobra.Command, args []string) error {
 	}
 
 	opts := mail.ListOptions{
-		MaxResults: threadMaxResults,
-		LabelIDs:   threadLabels,
+		MaxResults:       threadMaxResults,
+		PageToken:        threadPageToken,
+		LabelIDs:         threadLabels,
+		Query:            mutedExclusion(threadLabels, threadIncludeMuted),
+		IncludeSpamTrash: threadSpamTrash,
 	}
 
 	result, err := repo.List(ctx, opts)
@@ -212,12 +223,16 @@ func runThreadList(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
-	output := p.RenderThreads(result.Items)
-	cmd.Println(output)
+	printList(cmd, p.RenderThreads(result.Items), presenter.Page{
+		NextPageToken: result.NextPageToken,
+		TotalEstimate: presenter.Estimate(result.Total),
+		FetchedCount:  len(result.Items),
+		Warnings:      result.Warnings,
+	})
 
-	if !quietFlag && result.NextPageToken != "" {
+	if !quietFlag && result.NextPageToken != "" && formatFlag != presenter.FormatJSON {
 		cmd.Println("\n(More threads available. Use --max-results to adjust.)")
 	}
 
@@ -240,7 +255,7 @@ func runThreadShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	output := p.RenderThread(thread)
 	cmd.Println(output)
@@ -303,7 +318,7 @@ func runThreadModify(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create presenter based on format flag
-	p := presenter.New(formatFlag)
+	p := newPresenter()
 
 	if formatFlag == "json" {
 		cmd.Println(p.RenderThread(thread))
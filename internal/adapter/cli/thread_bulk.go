@@ -0,0 +1,286 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// threadModifyBatchSize is the number of threads modified at once. Gmail
+// has no batch call for threads, so each batch is a set of concurrent
+// Modify calls.
+const threadModifyBatchSize = 10
+
+// threadSweepPageSize is the number of threads listed per page by sweep.
+const threadSweepPageSize = 100
+
+// Thread sweep command flags.
+var (
+	threadSweepQuery        string
+	threadSweepRead         bool
+	threadSweepUnread       bool
+	threadSweepStar         bool
+	threadSweepUnstar       bool
+	threadSweepArchive      bool
+	threadSweepAddLabels    []string
+	threadSweepRemoveLabels []string
+	threadSweepLimit        int
+	threadSweepDryRun       bool
+)
+
+// threadStateCmd returns a command that applies req to each thread given
+// as an argument, reporting each one as done.
+func threadStateCmd(use, short, long, example, done string, req mail.ModifyRequest) *cobra.Command {
+	return &cobra.Command{
+		Use:     use + " <thread-id>...",
+		Short:   short,
+		Long:    long,
+		Example: example,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runThreadState(cmd, args, req, done)
+		},
+	}
+}
+
+// threadReadCmd marks threads as read.
+var threadReadCmd = threadStateCmd("read", "Mark threads as read",
+	`Mark every message in one or more threads as read.
+
+The whole conversation is changed with one call per thread, which is
+much faster than marking its messages one by one.`,
+	`  # Mark two threads as read
+  goog thread read 18c1234abcd 18c5678efgh`,
+	"marked as read", mail.ModifyRequest{RemoveLabels: []string{"UNREAD"}})
+
+// threadUnreadCmd marks threads as unread.
+var threadUnreadCmd = threadStateCmd("unread", "Mark threads as unread",
+	`Mark every message in one or more threads as unread.`,
+	`  # Mark a thread as unread
+  goog thread unread 18c1234abcd`,
+	"marked as unread", mail.ModifyRequest{AddLabels: []string{"UNREAD"}})
+
+// threadStarCmd stars threads.
+var threadStarCmd = threadStateCmd("star", "Star threads",
+	`Star every message in one or more threads.`,
+	`  # Star a thread
+  goog thread star 18c1234abcd`,
+	"starred", mail.ModifyRequest{AddLabels: []string{"STARRED"}})
+
+// threadUnstarCmd removes the star from threads.
+var threadUnstarCmd = threadStateCmd("unstar", "Unstar threads",
+	`Remove the star from every message in one or more threads.`,
+	`  # Unstar a thread
+  goog thread unstar 18c1234abcd`,
+	"unstarred", mail.ModifyRequest{RemoveLabels: []string{"STARRED"}})
+
+// threadSweepCmd changes every thread matching a search.
+var threadSweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Change every thread matching a search",
+	Long: `Change every thread matching a Gmail search at once.
+
+Threads are listed page by page and changed whole, a batch of threads at
+a time, so long conversations take one call each instead of one per
+message. Give at least one action: --read, --unread, --star, --unstar,
+--archive, --add-labels or --remove-labels (label IDs, as for 'goog
+thread modify').
+
+--limit caps the number of threads changed (0 for no limit). --dry-run
+lists the matching threads without changing them. Threads that fail are
+reported and the rest are still changed.`,
+	Example: `  # Mark all newsletter threads older than a week as read and archive them
+  goog thread sweep --query "label:newsletters older_than:7d" --read --archive
+
+  # Star every thread from the boss this month
+  goog thread sweep --query "from:boss@example.com newer_than:30d" --star
+
+  # See what would be changed
+  goog thread sweep --query "is:unread older_than:1y" --read --dry-run`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if threadSweepQuery == "" {
+			return fmt.Errorf("--query is required")
+		}
+		if threadSweepRead && threadSweepUnread {
+			return fmt.Errorf("--read and --unread cannot be used together")
+		}
+		if threadSweepStar && threadSweepUnstar {
+			return fmt.Errorf("--star and --unstar cannot be used together")
+		}
+		if threadSweepLimit < 0 {
+			return fmt.Errorf("--limit must not be negative")
+		}
+		req := threadSweepRequest()
+		if len(req.AddLabels) == 0 && len(req.RemoveLabels) == 0 {
+			return fmt.Errorf("at least one action is required: --read, --unread, --star, --unstar, --archive, --add-labels or --remove-labels")
+		}
+		return nil
+	},
+	RunE: runThreadSweep,
+}
+
+func init() {
+	threadCmd.AddCommand(threadReadCmd)
+	threadCmd.AddCommand(threadUnreadCmd)
+	threadCmd.AddCommand(threadStarCmd)
+	threadCmd.AddCommand(threadUnstarCmd)
+	threadCmd.AddCommand(threadSweepCmd)
+
+	threadSweepCmd.Flags().StringVar(&threadSweepQuery, "query", "", "Gmail search selecting the threads (required)")
+	threadSweepCmd.Flags().BoolVar(&threadSweepRead, "read", false, "mark the threads as read")
+	threadSweepCmd.Flags().BoolVar(&threadSweepUnread, "unread", false, "mark the threads as unread")
+	threadSweepCmd.Flags().BoolVar(&threadSweepStar, "star", false, "star the threads")
+	threadSweepCmd.Flags().BoolVar(&threadSweepUnstar, "unstar", false, "unstar the threads")
+	threadSweepCmd.Flags().BoolVar(&threadSweepArchive, "archive", false, "archive the threads")
+	threadSweepCmd.Flags().StringSliceVar(&threadSweepAddLabels, "add-labels", nil, "labels to add")
+	threadSweepCmd.Flags().StringSliceVar(&threadSweepRemoveLabels, "remove-labels", nil, "labels to remove")
+	threadSweepCmd.Flags().IntVar(&threadSweepLimit, "limit", 0, "maximum number of threads to change (0 for no limit)")
+	threadSweepCmd.Flags().BoolVar(&threadSweepDryRun, "dry-run", false, "list the matching threads without changing them")
+}
+
+// threadSweepRequest builds the label change from the sweep action flags.
+func threadSweepRequest() mail.ModifyRequest {
+	req := mail.ModifyRequest{
+		AddLabels:    append([]string(nil), threadSweepAddLabels...),
+		RemoveLabels: append([]string(nil), threadSweepRemoveLabels...),
+	}
+	switch {
+	case threadSweepRead:
+		req.RemoveLabels = append(req.RemoveLabels, "UNREAD")
+	case threadSweepUnread:
+		req.AddLabels = append(req.AddLabels, "UNREAD")
+	}
+	switch {
+	case threadSweepStar:
+		req.AddLabels = append(req.AddLabels, "STARRED")
+	case threadSweepUnstar:
+		req.RemoveLabels = append(req.RemoveLabels, "STARRED")
+	}
+	if threadSweepArchive {
+		req.RemoveLabels = append(req.RemoveLabels, "INBOX")
+	}
+	return req
+}
+
+// modifyThreads applies req to every thread in ids, threadModifyBatchSize
+// at a time, and returns the error for each thread in the order of ids.
+func modifyThreads(ctx context.Context, repo ThreadRepository, ids []string, req mail.ModifyRequest) []error {
+	errs := make([]error, len(ids))
+	for start := 0; start < len(ids); start += threadModifyBatchSize {
+		end := min(start+threadModifyBatchSize, len(ids))
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repo.Modify(ctx, ids[i], req)
+			}(i)
+		}
+		wg.Wait()
+	}
+	return errs
+}
+
+// reportThreadErrors warns about each thread that could not be changed and
+// returns the number of them.
+func reportThreadErrors(cmd *cobra.Command, ids []string, errs []error) int {
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			output.Warnf(cmd, "thread %s: %v", ids[i], err)
+			failed++
+		}
+	}
+	return failed
+}
+
+// runThreadState handles the thread read, unread, star and unstar commands.
+func runThreadState(cmd *cobra.Command, args []string, req mail.ModifyRequest, done string) error {
+	ctx := context.Background()
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	errs := modifyThreads(ctx, repo, args, req)
+	if !quietFlag {
+		for i, id := range args {
+			if errs[i] == nil {
+				cmd.Printf("Thread %s %s\n", id, done)
+			}
+		}
+	}
+	if failed := reportThreadErrors(cmd, args, errs); failed > 0 {
+		return fmt.Errorf("failed to change %d of %d thread(s)", failed, len(args))
+	}
+	return nil
+}
+
+// runThreadSweep handles the thread sweep command.
+func runThreadSweep(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := threadSweepRequest()
+	opts := mail.ListOptions{MaxResults: threadSweepPageSize, Query: threadSweepQuery}
+	matched, failed := 0, 0
+	for {
+		if threadSweepLimit > 0 {
+			opts.MaxResults = min(threadSweepPageSize, threadSweepLimit-matched)
+		}
+		result, err := repo.List(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list threads: %w", err)
+		}
+
+		ids := make([]string, 0, len(result.Items))
+		for _, thread := range result.Items {
+			if threadSweepLimit > 0 && matched+len(ids) >= threadSweepLimit {
+				break
+			}
+			ids = append(ids, thread.ID)
+		}
+		matched += len(ids)
+
+		if threadSweepDryRun {
+			if !quietFlag {
+				for i, id := range ids {
+					cmd.Printf("%s  %s\n", id, result.Items[i].Snippet)
+				}
+			}
+		} else if len(ids) > 0 {
+			errs := modifyThreads(ctx, repo, ids, req)
+			failed += reportThreadErrors(cmd, ids, errs)
+			if !quietFlag {
+				cmd.PrintErrf("Changed %d thread(s) so far\n", matched-failed)
+			}
+		}
+
+		if result.NextPageToken == "" || (threadSweepLimit > 0 && matched >= threadSweepLimit) {
+			break
+		}
+		opts.PageToken = result.NextPageToken
+	}
+
+	if !quietFlag {
+		if threadSweepDryRun {
+			cmd.Printf("Would change %d thread(s).\n", matched)
+		} else {
+			cmd.Printf("Changed %d thread(s).\n", matched-failed)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to change %d of %d thread(s)", failed, matched)
+	}
+	return nil
+}
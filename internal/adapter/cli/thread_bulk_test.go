@@ -0,0 +1,298 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// bulkThreadRepository pages through Threads and records the threads it
+// modifies. Modify is safe for concurrent use.
+type bulkThreadRepository struct {
+	MockThreadRepository
+	FailOn map[string]bool
+	Lists  []mail.ListOptions
+
+	mu       sync.Mutex
+	Modified map[string]mail.ModifyRequest
+}
+
+func (r *bulkThreadRepository) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Thread], error) {
+	r.Lists = append(r.Lists, opts)
+	start := len(opts.PageToken)
+	end := min(start+opts.MaxResults, len(r.Threads))
+	result := &mail.ListResult[*mail.Thread]{Items: r.Threads[start:end]}
+	if end < len(r.Threads) {
+		result.NextPageToken = strings.Repeat("x", end)
+	}
+	return result, nil
+}
+
+func (r *bulkThreadRepository) Modify(ctx context.Context, id string, req mail.ModifyRequest) (*mail.Thread, error) {
+	if r.FailOn[id] {
+		return nil, errors.New("rate limited")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Modified == nil {
+		r.Modified = make(map[string]mail.ModifyRequest)
+	}
+	r.Modified[id] = req
+	return &mail.Thread{ID: id}, nil
+}
+
+// setupThreadBulkTest injects repo and resets the sweep flags.
+func setupThreadBulkTest(t *testing.T, repo *bulkThreadRepository) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{ThreadRepo: repo},
+	})
+
+	origQuery, origRead, origUnread := threadSweepQuery, threadSweepRead, threadSweepUnread
+	origStar, origUnstar, origArchive := threadSweepStar, threadSweepUnstar, threadSweepArchive
+	origAdd, origRemove := threadSweepAddLabels, threadSweepRemoveLabels
+	origLimit, origDryRun, origQuiet := threadSweepLimit, threadSweepDryRun, quietFlag
+	threadSweepQuery, threadSweepRead, threadSweepUnread = "", false, false
+	threadSweepStar, threadSweepUnstar, threadSweepArchive = false, false, false
+	threadSweepAddLabels, threadSweepRemoveLabels = nil, nil
+	threadSweepLimit, threadSweepDryRun, quietFlag = 0, false, false
+	t.Cleanup(func() {
+		ResetDependencies()
+		threadSweepQuery, threadSweepRead, threadSweepUnread = origQuery, origRead, origUnread
+		threadSweepStar, threadSweepUnstar, threadSweepArchive = origStar, origUnstar, origArchive
+		threadSweepAddLabels, threadSweepRemoveLabels = origAdd, origRemove
+		threadSweepLimit, threadSweepDryRun, quietFlag = origLimit, origDryRun, origQuiet
+	})
+
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+	return cmd, out, errOut
+}
+
+// bulkTestThreads returns n threads with IDs t0 to t(n-1).
+func bulkTestThreads(n int) []*mail.Thread {
+	threads := make([]*mail.Thread, n)
+	for i := range threads {
+		threads[i] = &mail.Thread{ID: fmt.Sprintf("t%d", i), Snippet: fmt.Sprintf("snippet %d", i)}
+	}
+	return threads
+}
+
+func TestThreadStateCommands(t *testing.T) {
+	tests := []struct {
+		cmd        *cobra.Command
+		wantAdd    []string
+		wantRemove []string
+		wantOutput string
+	}{
+		{threadReadCmd, nil, []string{"UNREAD"}, "Thread a marked as read"},
+		{threadUnreadCmd, []string{"UNREAD"}, nil, "Thread a marked as unread"},
+		{threadStarCmd, []string{"STARRED"}, nil, "Thread a starred"},
+		{threadUnstarCmd, nil, []string{"STARRED"}, "Thread a unstarred"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd.Name(), func(t *testing.T) {
+			repo := &bulkThreadRepository{}
+			cmd, out, _ := setupThreadBulkTest(t, repo)
+
+			if err := tt.cmd.RunE(cmd, []string{"a", "b"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, id := range []string{"a", "b"} {
+				req, ok := repo.Modified[id]
+				if !ok {
+					t.Fatalf("thread %s not modified", id)
+				}
+				if !slices.Equal(req.AddLabels, tt.wantAdd) || !slices.Equal(req.RemoveLabels, tt.wantRemove) {
+					t.Errorf("thread %s request = %+v", id, req)
+				}
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestThreadStateCommands_PartialFailure(t *testing.T) {
+	repo := &bulkThreadRepository{FailOn: map[string]bool{"b": true}}
+	cmd, out, errOut := setupThreadBulkTest(t, repo)
+
+	err := runThreadState(cmd, []string{"a", "b", "c"}, mail.ModifyRequest{RemoveLabels: []string{"UNREAD"}}, "marked as read")
+	if err == nil || !strings.Contains(err.Error(), "failed to change 1 of 3 thread(s)") {
+		t.Fatalf("error = %v", err)
+	}
+	if len(repo.Modified) != 2 {
+		t.Errorf("modified %d thread(s), want 2", len(repo.Modified))
+	}
+	if strings.Contains(out.String(), "Thread b") {
+		t.Errorf("failed thread reported as done: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "thread b: rate limited") {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
+
+func TestModifyThreads_Batches(t *testing.T) {
+	repo := &bulkThreadRepository{}
+	ids := make([]string, threadModifyBatchSize*2+3)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("t%d", i)
+	}
+
+	errs := modifyThreads(context.Background(), repo, ids, mail.ModifyRequest{AddLabels: []string{"STARRED"}})
+	if len(errs) != len(ids) {
+		t.Fatalf("got %d errors, want %d", len(errs), len(ids))
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("thread %s: %v", ids[i], err)
+		}
+	}
+	if len(repo.Modified) != len(ids) {
+		t.Errorf("modified %d thread(s), want %d", len(repo.Modified), len(ids))
+	}
+}
+
+func TestThreadSweepRequest(t *testing.T) {
+	repo := &bulkThreadRepository{}
+	setupThreadBulkTest(t, repo)
+	threadSweepRead, threadSweepStar, threadSweepArchive = true, true, true
+	threadSweepAddLabels = []string{"Label_1"}
+
+	req := threadSweepRequest()
+	if !slices.Equal(req.AddLabels, []string{"Label_1", "STARRED"}) {
+		t.Errorf("AddLabels = %v", req.AddLabels)
+	}
+	if !slices.Equal(req.RemoveLabels, []string{"UNREAD", "INBOX"}) {
+		t.Errorf("RemoveLabels = %v", req.RemoveLabels)
+	}
+}
+
+func TestThreadSweepCmd_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func()
+		want  string
+	}{
+		{"no query", func() { threadSweepRead = true }, "--query is required"},
+		{"no action", func() { threadSweepQuery = "is:unread" }, "at least one action"},
+		{"read and unread", func() {
+			threadSweepQuery, threadSweepRead, threadSweepUnread = "is:unread", true, true
+		}, "cannot be used together"},
+		{"negative limit", func() {
+			threadSweepQuery, threadSweepStar, threadSweepLimit = "is:unread", true, -1
+		}, "--limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _, _ := setupThreadBulkTest(t, &bulkThreadRepository{})
+			tt.setup()
+			err := threadSweepCmd.PreRunE(cmd, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunThreadSweep(t *testing.T) {
+	repo := &bulkThreadRepository{MockThreadRepository: MockThreadRepository{Threads: bulkTestThreads(threadSweepPageSize + 5)}}
+	cmd, out, errOut := setupThreadBulkTest(t, repo)
+	threadSweepQuery, threadSweepRead, threadSweepArchive = "label:news", true, true
+
+	if err := runThreadSweep(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Modified) != threadSweepPageSize+5 {
+		t.Errorf("modified %d thread(s), want %d", len(repo.Modified), threadSweepPageSize+5)
+	}
+	if len(repo.Lists) != 2 || repo.Lists[0].Query != "label:news" {
+		t.Errorf("lists = %+v", repo.Lists)
+	}
+	if !strings.Contains(out.String(), fmt.Sprintf("Changed %d thread(s).", threadSweepPageSize+5)) {
+		t.Errorf("output = %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), fmt.Sprintf("Changed %d thread(s) so far", threadSweepPageSize)) {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
+
+func TestRunThreadSweep_Limit(t *testing.T) {
+	repo := &bulkThreadRepository{MockThreadRepository: MockThreadRepository{Threads: bulkTestThreads(10)}}
+	cmd, out, _ := setupThreadBulkTest(t, repo)
+	threadSweepQuery, threadSweepStar, threadSweepLimit = "is:important", true, 4
+
+	if err := runThreadSweep(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Modified) != 4 {
+		t.Errorf("modified %d thread(s), want 4", len(repo.Modified))
+	}
+	if repo.Lists[0].MaxResults != 4 {
+		t.Errorf("MaxResults = %d, want 4", repo.Lists[0].MaxResults)
+	}
+	if !strings.Contains(out.String(), "Changed 4 thread(s).") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestRunThreadSweep_DryRun(t *testing.T) {
+	repo := &bulkThreadRepository{MockThreadRepository: MockThreadRepository{Threads: bulkTestThreads(3)}}
+	cmd, out, _ := setupThreadBulkTest(t, repo)
+	threadSweepQuery, threadSweepRead, threadSweepDryRun = "is:unread", true, true
+
+	if err := runThreadSweep(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Modified) != 0 {
+		t.Errorf("dry run modified %d thread(s)", len(repo.Modified))
+	}
+	for _, want := range []string{"t2  snippet 2", "Would change 3 thread(s)."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+	}
+}
+
+func TestRunThreadSweep_PartialFailure(t *testing.T) {
+	repo := &bulkThreadRepository{
+		MockThreadRepository: MockThreadRepository{Threads: bulkTestThreads(5)},
+		FailOn:               map[string]bool{"t1": true, "t3": true},
+	}
+	cmd, out, errOut := setupThreadBulkTest(t, repo)
+	threadSweepQuery, threadSweepArchive = "in:inbox", true
+
+	err := runThreadSweep(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to change 2 of 5 thread(s)") {
+		t.Fatalf("error = %v", err)
+	}
+	if len(repo.Modified) != 3 {
+		t.Errorf("modified %d thread(s), want 3", len(repo.Modified))
+	}
+	if !strings.Contains(out.String(), "Changed 3 thread(s).") {
+		t.Errorf("output = %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "thread t3: rate limited") {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
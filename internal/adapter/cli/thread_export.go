@@ -0,0 +1,419 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Thread export formats.
+const (
+	threadExportMarkdown = "md"
+	threadExportHTML     = "html"
+)
+
+// Thread export command flags.
+var (
+	threadExportOut           string
+	threadExportNoAttachments bool
+)
+
+// threadExportCmd exports a thread as a Markdown or HTML document.
+var threadExportCmd = &cobra.Command{
+	Use:   "export <id>",
+	Short: "Export a thread to Markdown or HTML",
+	Long: `Export a full conversation as a Markdown or HTML document.
+
+Each message is rendered with its headers and body. Quoted replies
+(lines starting with ">" and their "On ... wrote:" attribution) are
+collapsed into expandable sections. Attachments are downloaded into
+the same directory as the output file and linked from the document.
+
+The export format is taken from --format (md or html). When --format
+is not md or html, it is inferred from the --out file extension and
+defaults to Markdown. Without --out the document is written to
+standard output and attachments are listed but not downloaded.`,
+	Example: `  # Export a thread to Markdown with its attachments
+  goog thread export abc123 --out decisions/thread.md
+
+  # Export a thread to HTML
+  goog thread export abc123 --format html --out thread.html
+
+  # Print Markdown to stdout without downloading attachments
+  goog thread export abc123 --format md`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThreadExport,
+}
+
+func init() {
+	threadCmd.AddCommand(threadExportCmd)
+
+	threadExportCmd.Flags().StringVarP(&threadExportOut, "out", "o", "", "output file (default: stdout)")
+	threadExportCmd.Flags().BoolVar(&threadExportNoAttachments, "no-attachments", false, "do not download attachments")
+}
+
+// threadExportAttachment is an attachment referenced by an exported thread.
+type threadExportAttachment struct {
+	Attachment *mail.Attachment
+	// File is the saved file name relative to the output directory, or
+	// empty if the attachment was not downloaded.
+	File string
+}
+
+// runThreadExport handles the thread export command.
+func runThreadExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	threadID := args[0]
+
+	format, err := resolveThreadExportFormat(formatFlag, threadExportOut)
+	if err != nil {
+		return err
+	}
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	thread, err := repo.Get(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil {
+		return fmt.Errorf("thread %s not found", threadID)
+	}
+
+	saveAttachments := threadExportOut != "" && !threadExportNoAttachments
+	attachments, err := collectThreadAttachments(ctx, thread, saveAttachments)
+	if err != nil {
+		return err
+	}
+
+	var doc string
+	if format == threadExportHTML {
+		doc = renderThreadHTML(thread, attachments)
+	} else {
+		doc = renderThreadMarkdown(thread, attachments)
+	}
+
+	if threadExportOut == "" {
+		cmd.Print(doc)
+		return nil
+	}
+
+	if err := os.WriteFile(threadExportOut, []byte(doc), 0o644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if !quietFlag {
+		saved := 0
+		for _, list := range attachments {
+			for _, att := range list {
+				if att.File != "" {
+					saved++
+				}
+			}
+		}
+		cmd.Printf("Exported thread %s (%d message(s)) to %s\n", thread.ID, len(thread.Messages), threadExportOut)
+		if saved > 0 {
+			cmd.Printf("Saved %d attachment(s) to %s\n", saved, filepath.Dir(threadExportOut))
+		}
+	}
+	return nil
+}
+
+// resolveThreadExportFormat determines the export format from the --format
+// value, falling back to the output file extension.
+func resolveThreadExportFormat(format, out string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "md", "markdown":
+		return threadExportMarkdown, nil
+	case "html":
+		return threadExportHTML, nil
+	case "", "table":
+		// Not an export format; infer from the output file
+	default:
+		return "", fmt.Errorf("unsupported export format %q (use md or html)", format)
+	}
+
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".html", ".htm":
+		return threadExportHTML, nil
+	default:
+		return threadExportMarkdown, nil
+	}
+}
+
+// collectThreadAttachments gathers the attachments of every message in the
+// thread, keyed by message ID. When save is true each attachment is
+// downloaded next to the output file.
+func collectThreadAttachments(ctx context.Context, thread *mail.Thread, save bool) (map[string][]threadExportAttachment, error) {
+	result := make(map[string][]threadExportAttachment)
+
+	var repo AttachmentRepository
+	used := make(map[string]bool)
+	dir := filepath.Dir(threadExportOut)
+	if save {
+		used[filepath.Base(threadExportOut)] = true
+	}
+
+	for _, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		for _, att := range msg.Attachments {
+			entry := threadExportAttachment{Attachment: att}
+			if save {
+				if repo == nil {
+					var err error
+					repo, err = getAttachmentRepositoryFromDeps(ctx)
+					if err != nil {
+						return nil, err
+					}
+				}
+				data, err := repo.Get(ctx, msg.ID, att.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to download attachment %q from message %s: %w", att.Filename, msg.ID, err)
+				}
+				name := sanitizeFilename(att.Filename)
+				if name == "" {
+					name = "attachment"
+				}
+				name = uniqueAttachmentName(name, used)
+				if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+					return nil, fmt.Errorf("failed to write %s: %w", name, err)
+				}
+				entry.File = name
+			}
+			result[msg.ID] = append(result[msg.ID], entry)
+		}
+	}
+
+	return result, nil
+}
+
+// threadSubject returns the subject of the first message that has one.
+func threadSubject(thread *mail.Thread) string {
+	for _, msg := range thread.Messages {
+		if msg != nil && msg.Subject != "" {
+			return msg.Subject
+		}
+	}
+	return "(no subject)"
+}
+
+// bodySection is a run of message body lines that are either all quoted or
+// all original text.
+type bodySection struct {
+	Quoted bool
+	Lines  []string
+}
+
+// attributionPattern matches reply attribution lines such as
+// "On Mon, Apr 1, 2024 at 10:00 AM Alice <alice@example.com> wrote:".
+var attributionPattern = regexp.MustCompile(`^On .+ wrote:\s*$`)
+
+// splitQuotedSections splits a plain-text body into original and quoted
+// sections. A reply attribution line directly preceding a quoted block is
+// kept with the quoted section.
+func splitQuotedSections(body string) []bodySection {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+
+	var sections []bodySection
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		quoted := strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+
+		// Pull an attribution line (and the blank line after it) into the quote
+		if !quoted && attributionPattern.MatchString(strings.TrimSpace(line)) {
+			j := i + 1
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[j], " "), ">") {
+				quoted = true
+			}
+		}
+
+		if n := len(sections); n > 0 && sections[n-1].Quoted == quoted {
+			sections[n-1].Lines = append(sections[n-1].Lines, line)
+			continue
+		}
+		// Blank lines inside a quote stay with the quote
+		if n := len(sections); n > 0 && sections[n-1].Quoted && strings.TrimSpace(line) == "" {
+			next := i + 1
+			if next < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[next], " "), ">") {
+				sections[n-1].Lines = append(sections[n-1].Lines, line)
+				continue
+			}
+		}
+		sections = append(sections, bodySection{Quoted: quoted, Lines: []string{line}})
+	}
+
+	return sections
+}
+
+var (
+	htmlDropPattern  = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|tr|li|h[1-6]|blockquote)>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	blankRunPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText converts an HTML body to plain text for export.
+func htmlToText(s string) string {
+	s = htmlDropPattern.ReplaceAllString(s, "")
+	s = htmlBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = blankRunPattern.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// exportBody returns the plain-text body of a message.
+func exportBody(msg *mail.Message) string {
+	if strings.TrimSpace(msg.Body) != "" {
+		return msg.Body
+	}
+	if msg.BodyHTML != "" {
+		return htmlToText(msg.BodyHTML)
+	}
+	return ""
+}
+
+// formatAttachmentSize formats a byte count for display using the
+// configured size units.
+func formatAttachmentSize(size int64) string {
+	return presenter.CurrentLocale().Size(size)
+}
+
+// renderThreadMarkdown renders a thread as a Markdown document.
+func renderThreadMarkdown(thread *mail.Thread, attachments map[string][]threadExportAttachment) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s\n\n", threadSubject(thread))
+	fmt.Fprintf(&sb, "- **Thread ID:** %s\n", thread.ID)
+	fmt.Fprintf(&sb, "- **Messages:** %d\n", len(thread.Messages))
+
+	for i, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n---\n\n## %d. %s\n\n", i+1, msg.From)
+		fmt.Fprintf(&sb, "- **From:** %s\n", msg.From)
+		if len(msg.To) > 0 {
+			fmt.Fprintf(&sb, "- **To:** %s\n", mail.JoinAddresses(msg.To))
+		}
+		if len(msg.Cc) > 0 {
+			fmt.Fprintf(&sb, "- **Cc:** %s\n", mail.JoinAddresses(msg.Cc))
+		}
+		if !msg.Date.IsZero() {
+			fmt.Fprintf(&sb, "- **Date:** %s\n", msg.Date.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+		}
+		fmt.Fprintf(&sb, "- **Subject:** %s\n", msg.Subject)
+
+		if body := exportBody(msg); body != "" {
+			sb.WriteString("\n")
+			for _, section := range splitQuotedSections(body) {
+				if section.Quoted {
+					fmt.Fprintf(&sb, "<details>\n<summary>Quoted text (%d lines)</summary>\n\n```text\n%s\n```\n\n</details>\n\n",
+						len(section.Lines), strings.Join(section.Lines, "\n"))
+				} else {
+					text := strings.Trim(strings.Join(section.Lines, "\n"), "\n")
+					if text != "" {
+						sb.WriteString(text + "\n\n")
+					}
+				}
+			}
+		}
+
+		if list := attachments[msg.ID]; len(list) > 0 {
+			sb.WriteString("\n**Attachments:**\n\n")
+			for _, att := range list {
+				meta := fmt.Sprintf("%s, %s", att.Attachment.MimeType, formatAttachmentSize(att.Attachment.Size))
+				if att.File != "" {
+					fmt.Fprintf(&sb, "- [%s](<%s>) (%s)\n", att.Attachment.Filename, att.File, meta)
+				} else {
+					fmt.Fprintf(&sb, "- %s (%s)\n", att.Attachment.Filename, meta)
+				}
+			}
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderThreadHTML renders a thread as a standalone HTML document.
+func renderThreadHTML(thread *mail.Thread, attachments map[string][]threadExportAttachment) string {
+	var sb strings.Builder
+	esc := html.EscapeString
+	subject := esc(threadSubject(thread))
+
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", subject)
+	sb.WriteString("<style>body{font-family:sans-serif;max-width:50em;margin:2em auto}" +
+		".message{border-top:1px solid #ccc;padding-top:1em}" +
+		".body{white-space:pre-wrap}details{color:#666}</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n", subject)
+	fmt.Fprintf(&sb, "<p>Thread ID: %s &middot; Messages: %d</p>\n", esc(thread.ID), len(thread.Messages))
+
+	for i, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		sb.WriteString("<div class=\"message\">\n")
+		fmt.Fprintf(&sb, "<h2>%d. %s</h2>\n<dl>\n", i+1, esc(msg.From.String()))
+		fmt.Fprintf(&sb, "<dt>From</dt><dd>%s</dd>\n", esc(msg.From.String()))
+		if len(msg.To) > 0 {
+			fmt.Fprintf(&sb, "<dt>To</dt><dd>%s</dd>\n", esc(mail.JoinAddresses(msg.To)))
+		}
+		if len(msg.Cc) > 0 {
+			fmt.Fprintf(&sb, "<dt>Cc</dt><dd>%s</dd>\n", esc(mail.JoinAddresses(msg.Cc)))
+		}
+		if !msg.Date.IsZero() {
+			fmt.Fprintf(&sb, "<dt>Date</dt><dd>%s</dd>\n", esc(msg.Date.Format("Mon, 02 Jan 2006 15:04:05 -0700")))
+		}
+		fmt.Fprintf(&sb, "<dt>Subject</dt><dd>%s</dd>\n</dl>\n", esc(msg.Subject))
+
+		if body := exportBody(msg); body != "" {
+			for _, section := range splitQuotedSections(body) {
+				text := esc(strings.Join(section.Lines, "\n"))
+				if section.Quoted {
+					fmt.Fprintf(&sb, "<details><summary>Quoted text (%d lines)</summary><div class=\"body\">%s</div></details>\n",
+						len(section.Lines), text)
+				} else if strings.TrimSpace(text) != "" {
+					fmt.Fprintf(&sb, "<div class=\"body\">%s</div>\n", strings.Trim(text, "\n"))
+				}
+			}
+		}
+
+		if list := attachments[msg.ID]; len(list) > 0 {
+			sb.WriteString("<h3>Attachments</h3>\n<ul>\n")
+			for _, att := range list {
+				meta := esc(fmt.Sprintf("%s, %s", att.Attachment.MimeType, formatAttachmentSize(att.Attachment.Size)))
+				if att.File != "" {
+					fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a> (%s)</li>\n", esc(url.PathEscape(att.File)), esc(att.Attachment.Filename), meta)
+				} else {
+					fmt.Fprintf(&sb, "<li>%s (%s)</li>\n", esc(att.Attachment.Filename), meta)
+				}
+			}
+			sb.WriteString("</ul>\n")
+		}
+		sb.WriteString("</div>\n")
+	}
+
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
@@ -0,0 +1,295 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+)
+
+// exportTestThread builds a two-message thread with a quoted reply and an attachment.
+func exportTestThread() *mail.Thread {
+	first := mail.NewMessage("m1", "t1", "Alice <alice@example.com>", "Pick a database", "Should we use Postgres or MySQL?")
+	first.To = []mail.Address{{Email: "bob@example.com"}}
+	first.Date = time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	first.Attachments = []*mail.Attachment{
+		{ID: "a1", Filename: "comparison.pdf", MimeType: "application/pdf", Size: 2048},
+	}
+
+	reply := mail.NewMessage("m2", "t1", "Bob <bob@example.com>", "Re: Pick a database",
+		"Postgres. Decision made.\n\nOn Mon, Apr 1, 2024 at 10:00 AM Alice <alice@example.com> wrote:\n\n> Should we use Postgres or MySQL?\n> <script>alert(1)</script>\n")
+	reply.To = []mail.Address{{Email: "alice@example.com"}}
+	reply.Cc = []mail.Address{{Email: "team@example.com"}}
+	reply.Date = time.Date(2024, 4, 1, 11, 0, 0, 0, time.UTC)
+
+	thread := mail.NewThread("t1")
+	thread.Messages = []*mail.Message{first, reply}
+	return thread
+}
+
+// setupThreadExportTest injects repositories and resets export flags.
+func setupThreadExportTest(t *testing.T, threadRepo *MockThreadRepository, attRepo *MockAttachmentRepository) *bytes.Buffer {
+	t.Helper()
+
+	SetDependencies(&Dependencies{
+		AccountService: &MockAccountService{
+			Account:      &accountuc.Account{Alias: "test", Email: "me@example.com"},
+			TokenManager: &MockTokenManager{},
+		},
+		RepoFactory: &MockRepositoryFactory{ThreadRepo: threadRepo, AttachmentRepo: attRepo},
+	})
+
+	origOut, origNoAtt, origFormat, origQuiet := threadExportOut, threadExportNoAttachments, formatFlag, quietFlag
+	threadExportOut = ""
+	threadExportNoAttachments = false
+	formatFlag = "table"
+	quietFlag = false
+	t.Cleanup(func() {
+		ResetDependencies()
+		threadExportOut, threadExportNoAttachments, formatFlag, quietFlag = origOut, origNoAtt, origFormat, origQuiet
+	})
+
+	return new(bytes.Buffer)
+}
+
+func TestThreadExportCmd_Help(t *testing.T) {
+	cmd := &cobra.Command{Use: "goog"}
+	cmd.AddCommand(threadCmd)
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"thread", "export", "--help"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"--out", "--no-attachments", "Markdown"} {
+		if !containsStr(output, want) {
+			t.Errorf("expected help to contain %q", want)
+		}
+	}
+}
+
+func TestResolveThreadExportFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"md", "", threadExportMarkdown, false},
+		{"markdown", "x.html", threadExportMarkdown, false},
+		{"HTML", "", threadExportHTML, false},
+		{"table", "thread.html", threadExportHTML, false},
+		{"table", "thread.HTM", threadExportHTML, false},
+		{"table", "thread.md", threadExportMarkdown, false},
+		{"", "", threadExportMarkdown, false},
+		{"json", "thread.md", "", true},
+		{"pdf", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"_"+tt.out, func(t *testing.T) {
+			got, err := resolveThreadExportFormat(tt.format, tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQuotedSections(t *testing.T) {
+	body := "Sounds good.\n\nOn Mon, Apr 1, 2024 at 10:00 AM Alice wrote:\n\n> line one\n>\n> line two\n\n-- \nBob"
+
+	sections := splitQuotedSections(body)
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d: %+v", len(sections), sections)
+	}
+	if sections[0].Quoted || sections[2].Quoted {
+		t.Errorf("expected first and last sections to be original text: %+v", sections)
+	}
+	if !sections[1].Quoted {
+		t.Fatalf("expected middle section to be quoted: %+v", sections[1])
+	}
+	if !strings.HasPrefix(sections[1].Lines[0], "On Mon") {
+		t.Errorf("expected attribution to be part of the quote, got %q", sections[1].Lines[0])
+	}
+	if got := sections[1].Lines[len(sections[1].Lines)-1]; got != "> line two" {
+		t.Errorf("last quoted line = %q, want \"> line two\"", got)
+	}
+
+	// An attribution-like line without a following quote is original text
+	plain := splitQuotedSections("On Friday you wrote:\nthanks")
+	if len(plain) != 1 || plain[0].Quoted {
+		t.Errorf("expected a single original section, got %+v", plain)
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	in := "<html><head><style>p{}</style></head><body><p>Hello &amp; welcome</p><div>Line<br>Two</div><script>x()</script></body></html>"
+	got := htmlToText(in)
+	if got != "Hello & welcome\nLine\nTwo" {
+		t.Errorf("htmlToText() = %q", got)
+	}
+}
+
+func TestRenderThreadMarkdown(t *testing.T) {
+	thread := exportTestThread()
+	attachments := map[string][]threadExportAttachment{
+		"m1": {{Attachment: thread.Messages[0].Attachments[0], File: "comparison.pdf"}},
+	}
+
+	out := renderThreadMarkdown(thread, attachments)
+
+	for _, want := range []string{
+		"# Pick a database",
+		"- **Messages:** 2",
+		"## 1. Alice <alice@example.com>",
+		"- **Cc:** team@example.com",
+		"- **Date:** Mon, 01 Apr 2024 11:00:00 +0000",
+		"Postgres. Decision made.",
+		"<summary>Quoted text (4 lines)</summary>",
+		"- [comparison.pdf](<comparison.pdf>) (application/pdf, 2.0 KiB)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderThreadHTML(t *testing.T) {
+	thread := exportTestThread()
+	attachments := map[string][]threadExportAttachment{
+		"m1": {{Attachment: thread.Messages[0].Attachments[0]}},
+	}
+
+	out := renderThreadHTML(thread, attachments)
+
+	for _, want := range []string{
+		"<title>Pick a database</title>",
+		"<dt>From</dt><dd>Bob &lt;bob@example.com&gt;</dd>",
+		"<details><summary>Quoted text (4 lines)</summary>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		"<li>comparison.pdf (application/pdf, 2.0 KiB)</li>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected HTML to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("expected message content to be escaped")
+	}
+}
+
+func TestRunThreadExport_File(t *testing.T) {
+	attRepo := &MockAttachmentRepository{Data: map[string][]byte{"a1": []byte("%PDF")}}
+	buf := setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, attRepo)
+
+	dir := t.TempDir()
+	threadExportOut = filepath.Join(dir, "thread.md")
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runThreadExport(cmd, []string{"t1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc, err := os.ReadFile(threadExportOut)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if !strings.Contains(string(doc), "# Pick a database") {
+		t.Errorf("unexpected export:\n%s", doc)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "comparison.pdf"))
+	if err != nil || string(data) != "%PDF" {
+		t.Errorf("expected attachment to be saved, got %q, %v", data, err)
+	}
+
+	for _, want := range []string{"Exported thread t1 (2 message(s))", "Saved 1 attachment(s)"} {
+		if !containsStr(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got: %s", want, buf.String())
+		}
+	}
+}
+
+func TestRunThreadExport_Stdout(t *testing.T) {
+	attRepo := &MockAttachmentRepository{}
+	buf := setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, attRepo)
+	formatFlag = "html"
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.SetOut(buf)
+
+	if err := runThreadExport(cmd, []string{"t1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "<!DOCTYPE html>") {
+		t.Errorf("expected HTML document on stdout, got:\n%s", buf.String())
+	}
+	if len(attRepo.Calls) != 0 {
+		t.Errorf("expected no attachment downloads, got %v", attRepo.Calls)
+	}
+}
+
+func TestRunThreadExport_NoAttachments(t *testing.T) {
+	attRepo := &MockAttachmentRepository{}
+	setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, attRepo)
+	threadExportOut = filepath.Join(t.TempDir(), "thread.md")
+	threadExportNoAttachments = true
+
+	if err := runThreadExport(&cobra.Command{Use: "test"}, []string{"t1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attRepo.Calls) != 0 {
+		t.Errorf("expected no attachment downloads, got %v", attRepo.Calls)
+	}
+}
+
+func TestRunThreadExport_Errors(t *testing.T) {
+	t.Run("unsupported format", func(t *testing.T) {
+		setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, nil)
+		formatFlag = "json"
+
+		err := runThreadExport(&cobra.Command{Use: "test"}, []string{"t1"})
+		if err == nil || !containsStr(err.Error(), "unsupported export format") {
+			t.Errorf("expected format error, got %v", err)
+		}
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		setupThreadExportTest(t, &MockThreadRepository{GetErr: errors.New("boom")}, nil)
+
+		err := runThreadExport(&cobra.Command{Use: "test"}, []string{"t1"})
+		if err == nil || !containsStr(err.Error(), "failed to get thread") {
+			t.Errorf("expected get error, got %v", err)
+		}
+	})
+
+	t.Run("attachment error", func(t *testing.T) {
+		setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, &MockAttachmentRepository{GetErr: errors.New("boom")})
+		threadExportOut = filepath.Join(t.TempDir(), "thread.md")
+
+		err := runThreadExport(&cobra.Command{Use: "test"}, []string{"t1"})
+		if err == nil || !containsStr(err.Error(), "failed to download attachment") {
+			t.Errorf("expected attachment error, got %v", err)
+		}
+	})
+}
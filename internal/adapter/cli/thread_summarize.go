@@ -0,0 +1,148 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/adapter/presenter"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/summarize"
+)
+
+// Thread summarize command flags.
+var (
+	threadSummarizeCommand string
+	threadSummarizeURL     string
+)
+
+// threadSummarizeCmd summarizes a thread with an external tool.
+var threadSummarizeCmd = &cobra.Command{
+	Use:   "summarize <id>",
+	Short: "Summarize a thread with an external tool",
+	Long: `Summarize a conversation by handing it to a command or HTTP endpoint
+you provide, such as a script around a language model.
+
+The summarizer receives one JSON document:
+
+  {
+    "version": 1,
+    "thread": {
+      "id": "...", "subject": "...",
+      "messages": [{"id": "...", "from": "...", "to": ["..."], "cc": ["..."],
+                    "date": "2026-03-02T09:00:00Z", "subject": "...", "body": "..."}]
+    },
+    "text": "the whole thread rendered as Markdown"
+  }
+
+A command gets it on stdin, with the thread ID in GOOG_THREAD_ID. An
+endpoint gets it as the body of a POST, with GOOG_SUMMARIZE_TOKEN sent
+as a bearer token when set. Either replies with the summary as plain
+text or as {"summary": "..."}; {"error": "..."} reports a failure.
+
+The summarizer is taken from --command or --url, then from
+mail.summarize_command or mail.summarize_url. The thread is sent
+wherever you point it, so choose a tool you trust with your mail.`,
+	Example: `  # Summarize with a local command
+  goog thread summarize abc123 --command "jq -r .text | llm -s 'Summarize this email thread'"
+
+  # Use a configured HTTP endpoint
+  goog config set mail.summarize_url http://localhost:8080/summarize
+  goog thread summarize abc123
+
+  # Output the summary as JSON
+  goog thread summarize abc123 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runThreadSummarize,
+}
+
+func init() {
+	threadCmd.AddCommand(threadSummarizeCmd)
+
+	threadSummarizeCmd.Flags().StringVar(&threadSummarizeCommand, "command", "", "shell command that summarizes the thread (default: mail.summarize_command)")
+	threadSummarizeCmd.Flags().StringVar(&threadSummarizeURL, "url", "", "HTTP endpoint that summarizes the thread (default: mail.summarize_url)")
+}
+
+// threadSummary is the JSON output of thread summarize.
+type threadSummary struct {
+	ThreadID string `json:"thread_id"`
+	Subject  string `json:"subject"`
+	Messages int    `json:"messages"`
+	Summary  string `json:"summary"`
+}
+
+// runThreadSummarize handles the thread summarize command.
+func runThreadSummarize(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	threadID := args[0]
+
+	summarizer, err := threadSummarizer()
+	if err != nil {
+		return err
+	}
+
+	repo, err := getThreadRepositoryFromDeps(ctx)
+	if err != nil {
+		return err
+	}
+
+	thread, err := repo.Get(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to get thread: %w", err)
+	}
+	if thread == nil {
+		return fmt.Errorf("thread %s not found", threadID)
+	}
+
+	attachments, err := collectThreadAttachments(ctx, thread, false)
+	if err != nil {
+		return err
+	}
+	summary, err := summarizer.SummarizeThread(ctx, thread, renderThreadMarkdown(thread, attachments))
+	if err != nil {
+		return fmt.Errorf("failed to summarize thread: %w", err)
+	}
+
+	if formatFlag == presenter.FormatJSON {
+		data, err := json.MarshalIndent(threadSummary{
+			ThreadID: thread.ID,
+			Subject:  threadSubject(thread),
+			Messages: len(thread.Messages),
+			Summary:  summary,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode summary: %w", err)
+		}
+		cmd.Println(string(data))
+		return nil
+	}
+
+	if !quietFlag {
+		cmd.Printf("%s (%d message(s))\n\n", threadSubject(thread), len(thread.Messages))
+	}
+	cmd.Println(summary)
+	return nil
+}
+
+// threadSummarizer returns the summarizer chosen by the flags or the
+// config. A command wins over a URL at the same level.
+func threadSummarizer() (mail.ThreadSummarizer, error) {
+	command, url := strings.TrimSpace(threadSummarizeCommand), strings.TrimSpace(threadSummarizeURL)
+	if command == "" && url == "" {
+		if cfg, err := config.Load(); err == nil {
+			command, url = strings.TrimSpace(cfg.Mail.SummarizeCommand), strings.TrimSpace(cfg.Mail.SummarizeURL)
+		}
+	}
+	switch {
+	case command != "":
+		return &summarize.CommandSummarizer{Command: command, Body: exportBody}, nil
+	case url != "":
+		return &summarize.HTTPSummarizer{URL: url, Token: os.Getenv("GOOG_SUMMARIZE_TOKEN"), Body: exportBody}, nil
+	}
+	return nil, fmt.Errorf("no summarizer configured: pass --command or --url, or set mail.summarize_command or mail.summarize_url")
+}
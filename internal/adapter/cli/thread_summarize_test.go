@@ -0,0 +1,101 @@
+// Package cli provides command-line interface handlers for the goog application.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/summarize"
+)
+
+// setupThreadSummarizeTest injects the export test thread and resets the
+// summarize flags and config.
+func setupThreadSummarizeTest(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	setupThreadExportTest(t, &MockThreadRepository{Thread: exportTestThread()}, &MockAttachmentRepository{})
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	origCommand, origURL := threadSummarizeCommand, threadSummarizeURL
+	t.Cleanup(func() { threadSummarizeCommand, threadSummarizeURL = origCommand, origURL })
+	threadSummarizeCommand, threadSummarizeURL = "", ""
+
+	cmd := &cobra.Command{Use: "test"}
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	return cmd, out
+}
+
+func TestRunThreadSummarize_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	cmd, out := setupThreadSummarizeTest(t)
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	threadSummarizeCommand = "cat > " + requestFile + "; echo Postgres was chosen."
+
+	if err := runThreadSummarize(cmd, []string{"t1"}); err != nil {
+		t.Fatalf("runThreadSummarize failed: %v", err)
+	}
+	if got := out.String(); got != "Pick a database (2 message(s))\n\nPostgres was chosen.\n" {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	data, err := os.ReadFile(requestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req summarize.Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("invalid request: %v\n%s", err, data)
+	}
+	if req.Version != summarize.Version || req.Thread.ID != "t1" || req.Thread.Subject != "Pick a database" || len(req.Thread.Messages) != 2 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if !strings.Contains(req.Text, "# Pick a database") || !strings.Contains(req.Text, "comparison.pdf") {
+		t.Errorf("expected the rendered thread in the request, got:\n%s", req.Text)
+	}
+}
+
+func TestRunThreadSummarize_ConfiguredURL(t *testing.T) {
+	cmd, out := setupThreadSummarizeTest(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("missing bearer token")
+		}
+		_, _ = w.Write([]byte(`{"version":1,"summary":"Postgres was chosen."}`))
+	}))
+	defer server.Close()
+	t.Setenv("GOOG_SUMMARIZE_TOKEN", "tok")
+	if err := os.WriteFile(os.Getenv("GOOG_CONFIG"), []byte("mail:\n  summarize_url: "+server.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	formatFlag = "json"
+
+	if err := runThreadSummarize(cmd, []string{"t1"}); err != nil {
+		t.Fatalf("runThreadSummarize failed: %v", err)
+	}
+	var got threadSummary
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	want := threadSummary{ThreadID: "t1", Subject: "Pick a database", Messages: 2, Summary: "Postgres was chosen."}
+	if got != want {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunThreadSummarize_NotConfigured(t *testing.T) {
+	cmd, _ := setupThreadSummarizeTest(t)
+	err := runThreadSummarize(cmd, []string{"t1"})
+	if err == nil || !strings.Contains(err.Error(), "no summarizer configured") {
+		t.Errorf("expected a configuration error, got %v", err)
+	}
+}
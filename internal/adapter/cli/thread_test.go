@@ -178,7 +178,6 @@ func TestThreadCmd_Aliases(t *testing.T) {
 	}{
 		{"list alias ls", "list", "ls"},
 		{"show alias get", "show", "get"},
-		{"show alias read", "show", "read"},
 	}
 
 	for _, tt := range tests {
@@ -584,7 +583,7 @@ func TestRunThreadShow_WithMockDependencies(t *testing.T) {
 			{
 				ID:      "msg1",
 				Subject: "Test Subject",
-				From:    "sender@example.com",
+				From:    mail.Address{Email: "sender@example.com"},
 				Body:    "Message body",
 				Date:    time.Now(),
 			},
@@ -1650,7 +1649,7 @@ func TestRunThreadShow_WithQuietFlag(t *testing.T) {
 			{
 				ID:      "msg1",
 				Subject: "Test Subject",
-				From:    "sender@example.com",
+				From:    mail.Address{Email: "sender@example.com"},
 				Body:    "Message body",
 				Date:    time.Now(),
 			},
@@ -1708,7 +1707,7 @@ func TestRunThreadShow_JSONFormatQuietMode(t *testing.T) {
 		ID:      "thread123",
 		Snippet: "JSON thread",
 		Messages: []*mail.Message{
-			{ID: "msg1", Subject: "Test Subject", From: "sender@example.com", Date: time.Now()},
+			{ID: "msg1", Subject: "Test Subject", From: mail.Address{Email: "sender@example.com"}, Date: time.Now()},
 		},
 	}
 
@@ -1761,21 +1760,21 @@ func TestRunThreadShow_MultipleMessages(t *testing.T) {
 			{
 				ID:      "msg1",
 				Subject: "First message",
-				From:    "sender1@example.com",
+				From:    mail.Address{Email: "sender1@example.com"},
 				Body:    "First body",
 				Date:    time.Now().Add(-2 * time.Hour),
 			},
 			{
 				ID:      "msg2",
 				Subject: "Re: First message",
-				From:    "sender2@example.com",
+				From:    mail.Address{Email: "sender2@example.com"},
 				Body:    "Reply body",
 				Date:    time.Now().Add(-1 * time.Hour),
 			},
 			{
 				ID:      "msg3",
 				Subject: "Re: First message",
-				From:    "sender1@example.com",
+				From:    mail.Address{Email: "sender1@example.com"},
 				Body:    "Another reply",
 				Date:    time.Now(),
 			},
@@ -0,0 +1,353 @@
+// Package dateparse parses the absolute and relative date expressions
+// accepted by goog's date and time flags.
+//
+// Supported expressions:
+//
+//	2025-08-01T14:00:00Z             RFC 3339
+//	2025-08-01 14:00, 2025-08-01     ISO date with optional time
+//	now
+//	today, tomorrow, yesterday       optionally followed by a time
+//	monday, next fri, last tue       optionally followed by a time
+//	-3d, +2w, 36h, 3 days ago        offsets from now (m, h, d, w, mo, y)
+//
+// Times of day are written as 14:00, 3pm, 3:30pm, noon or midnight and may
+// be preceded by "at". Ranges join two expressions with "..".
+//
+// Flags that give the start of a period ending now, such as --since, or
+// the end of one starting now, such as --ahead, also take an offset
+// without a sign, such as 30d, which counts back or forward from now.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeLayouts are tried in order for absolute dates with a time.
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// dateLayout is the layout of an absolute date without a time.
+const dateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var (
+	offsetRegex = regexp.MustCompile(`^([+-]?)(\d+)(mo|months?|y|years?|w|weeks?|d|days?|h|hours?|m|mins?|minutes?)$`)
+	clock24     = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	clock12     = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+)
+
+// Parse parses a date expression relative to the current local time.
+func Parse(input string) (time.Time, error) {
+	return ParseAt(input, time.Now())
+}
+
+// ParseAt parses a date expression relative to now. Results are in now's
+// location. Expressions naming a day without a time resolve to midnight.
+func ParseAt(input string, now time.Time) (time.Time, error) {
+	t, _, err := parse(input, now)
+	return t, err
+}
+
+// ParseRange parses a range relative to the current local time.
+func ParseRange(input string) (start, end time.Time, err error) {
+	return ParseRangeAt(input, time.Now())
+}
+
+// ParseRangeAt parses a time range relative to now and returns its
+// half-open bounds [start, end).
+//
+// "A..B" spans from A to B; a date-only B includes that whole day, so
+// "2025-08-01..2025-08-15" covers both the 1st and the 15th. Either side
+// may be omitted for an open range, which is returned as a zero time.
+// A single day such as "yesterday" or "2025-08-01" covers that day, and a
+// single instant such as "-3d" spans between it and now.
+func ParseRangeAt(input string, now time.Time) (start, end time.Time, err error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("empty date range")
+	}
+
+	left, right, isRange := strings.Cut(input, "..")
+	if !isRange {
+		t, dateOnly, err := parse(input, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		switch {
+		case dateOnly:
+			return t, t.AddDate(0, 0, 1), nil
+		case t.Before(now):
+			return t, now, nil
+		default:
+			return now, t, nil
+		}
+	}
+
+	left, right = strings.TrimSpace(left), strings.TrimSpace(right)
+	if left == "" && right == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range %q: both ends are empty", input)
+	}
+	if left != "" {
+		if start, _, err = parse(left, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if right != "" {
+		var dateOnly bool
+		if end, dateOnly, err = parse(right, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if dateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range %q: end is not after start", input)
+	}
+	return start, end, nil
+}
+
+// ParseSinceAt parses the start of a period that ends at now. An offset
+// without a sign, such as "30d" or "2w", counts back from now, as does a
+// Go duration such as "1h30m"; other expressions are parsed as by ParseAt.
+// The start must be before now.
+func ParseSinceAt(input string, now time.Time) (time.Time, error) {
+	expr := input
+	fields := strings.Fields(strings.ToLower(input))
+	if m := offsetRegex.FindStringSubmatch(strings.Join(fields, "")); m != nil && m[1] == "" && fields[0] != "in" {
+		expr = "-" + strings.Join(fields, "")
+	}
+	t, _, err := parse(expr, now)
+	if err != nil {
+		d, durErr := time.ParseDuration(strings.TrimSpace(input))
+		if durErr != nil {
+			return time.Time{}, err
+		}
+		t = now.Add(-d)
+	}
+	if !t.Before(now) {
+		return time.Time{}, fmt.Errorf("%q is not in the past", strings.TrimSpace(input))
+	}
+	return t, nil
+}
+
+// ParseUntilAt parses the end of a period that starts at now. An offset
+// such as "7d" or a Go duration such as "1h30m" counts forward from now,
+// and a day without a time, such as "friday", includes that whole day;
+// other expressions are parsed as by ParseAt. The end must be after now.
+func ParseUntilAt(input string, now time.Time) (time.Time, error) {
+	t, dateOnly, err := parse(input, now)
+	switch {
+	case err != nil:
+		d, durErr := time.ParseDuration(strings.TrimSpace(input))
+		if durErr != nil {
+			return time.Time{}, err
+		}
+		t = now.Add(d)
+	case dateOnly:
+		t = t.AddDate(0, 0, 1)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("%q is not in the future", strings.TrimSpace(input))
+	}
+	return t, nil
+}
+
+// ParseTimeOfDay parses a time of day such as "14:00", "3pm", "3:30pm",
+// "noon" or "midnight".
+func ParseTimeOfDay(input string) (hour, minute int, err error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	switch input {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	if m := clock24.FindStringSubmatch(input); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		if hour <= 23 && minute <= 59 {
+			return hour, minute, nil
+		}
+		return 0, 0, fmt.Errorf("invalid time: %s", input)
+	}
+
+	if m := clock12.FindStringSubmatch(input); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+		if hour >= 1 && hour <= 12 && minute <= 59 {
+			// 12am is midnight and 12pm is noon
+			hour %= 12
+			if m[3] == "pm" {
+				hour += 12
+			}
+			return hour, minute, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("invalid time format: %s", input)
+}
+
+// parse resolves an expression and reports whether it named a whole day
+// rather than an instant.
+func parse(input string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, false, fmt.Errorf("empty date/time string")
+	}
+	loc := now.Location()
+
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, false, nil
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, input, loc); err == nil {
+			return t, false, nil
+		}
+	}
+
+	fields := strings.Fields(strings.ToLower(input))
+	if len(fields) == 1 && fields[0] == "now" {
+		return now, false, nil
+	}
+	if t, ok := parseOffset(fields, now); ok {
+		return t, false, nil
+	}
+
+	day, rest, ok := parseDay(fields, now)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("unable to parse date/time: %q", input)
+	}
+	if len(rest) > 0 && rest[0] == "at" {
+		rest = rest[1:]
+	}
+	switch len(rest) {
+	case 0:
+		return day, true, nil
+	case 1:
+		hour, minute, err := ParseTimeOfDay(rest[0])
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unable to parse date/time: %q: %w", input, err)
+		}
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("unable to parse date/time: %q", input)
+}
+
+// parseOffset resolves offsets such as "-3d", "+2w", "36h", "3 days ago"
+// and "in 2 hours".
+func parseOffset(fields []string, now time.Time) (time.Time, bool) {
+	sign := ""
+	if len(fields) > 1 && fields[len(fields)-1] == "ago" {
+		sign, fields = "-", fields[:len(fields)-1]
+	} else if len(fields) > 1 && fields[0] == "in" {
+		sign, fields = "+", fields[1:]
+	}
+
+	m := offsetRegex.FindStringSubmatch(strings.Join(fields, ""))
+	if m == nil || (sign != "" && m[1] != "") {
+		return time.Time{}, false
+	}
+	if sign == "" {
+		sign = m[1]
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	if sign == "-" {
+		n = -n
+	}
+
+	switch unit := m[3]; {
+	case strings.HasPrefix(unit, "mo"):
+		return now.AddDate(0, n, 0), true
+	case strings.HasPrefix(unit, "y"):
+		return now.AddDate(n, 0, 0), true
+	case strings.HasPrefix(unit, "w"):
+		return now.AddDate(0, 0, 7*n), true
+	case strings.HasPrefix(unit, "d"):
+		return now.AddDate(0, 0, n), true
+	case strings.HasPrefix(unit, "h"):
+		return now.Add(time.Duration(n) * time.Hour), true
+	default:
+		return now.Add(time.Duration(n) * time.Minute), true
+	}
+}
+
+// parseDay resolves the leading day expression of fields to midnight of
+// that day and returns the remaining fields.
+func parseDay(fields []string, now time.Time) (time.Time, []string, bool) {
+	if len(fields) == 0 {
+		return time.Time{}, nil, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch fields[0] {
+	case "today":
+		return today, fields[1:], true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), fields[1:], true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), fields[1:], true
+	case "this", "next", "last":
+		if len(fields) < 2 {
+			return time.Time{}, nil, false
+		}
+		wd, ok := weekdays[fields[1]]
+		if !ok {
+			return time.Time{}, nil, false
+		}
+		return weekdayFrom(today, wd, fields[0]), fields[2:], true
+	}
+
+	if wd, ok := weekdays[fields[0]]; ok {
+		return weekdayFrom(today, wd, "this"), fields[1:], true
+	}
+	if t, err := time.ParseInLocation(dateLayout, fields[0], now.Location()); err == nil {
+		return t, fields[1:], true
+	}
+	return time.Time{}, nil, false
+}
+
+// weekdayFrom returns the given weekday relative to today. "this" is the
+// next occurrence including today, "next" the next one after today, and
+// "last" the most recent one before today.
+func weekdayFrom(today time.Time, wd time.Weekday, which string) time.Time {
+	ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+	switch which {
+	case "next":
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead)
+	case "last":
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		if back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back)
+	}
+	return today.AddDate(0, 0, ahead)
+}
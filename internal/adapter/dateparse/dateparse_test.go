@@ -0,0 +1,262 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+)
+
+// refNow is Wednesday 2025-08-06 10:30 in a fixed zone.
+var (
+	refLoc = time.FixedZone("TEST", 2*3600)
+	refNow = time.Date(2025, 8, 6, 10, 30, 0, 0, refLoc)
+)
+
+func at(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2025, month, day, hour, minute, 0, 0, refLoc)
+}
+
+func TestParseAt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		// Absolute
+		{"2025-08-01T14:00:00Z", time.Date(2025, 8, 1, 14, 0, 0, 0, time.UTC)},
+		{"2025-08-01 14:00", at(8, 1, 14, 0)},
+		{"2025-08-01 14:00:30", time.Date(2025, 8, 1, 14, 0, 30, 0, refLoc)},
+		{"2025-08-01T09:15", at(8, 1, 9, 15)},
+		{"2025-08-01", at(8, 1, 0, 0)},
+		{"2025-08-01 3pm", at(8, 1, 15, 0)},
+
+		// Named days
+		{"now", refNow},
+		{"today", at(8, 6, 0, 0)},
+		{"TOMORROW", at(8, 7, 0, 0)},
+		{"yesterday", at(8, 5, 0, 0)},
+		{"tomorrow 3pm", at(8, 7, 15, 0)},
+		{"tomorrow   3pm", at(8, 7, 15, 0)},
+		{"tomorrow at 3pm", at(8, 7, 15, 0)},
+		{"today noon", at(8, 6, 12, 0)},
+		{"tomorrow 12am", at(8, 7, 0, 0)},
+		{"yesterday 11:59pm", at(8, 5, 23, 59)},
+		{"tomorrow 11:59pm", at(8, 7, 23, 59)},
+		{"today 12pm", at(8, 6, 12, 0)},
+		{"today 1:30am", at(8, 6, 1, 30)},
+		{"tOdAy", at(8, 6, 0, 0)},
+
+		// Weekdays (refNow is a Wednesday)
+		{"friday", at(8, 8, 0, 0)},
+		{"wednesday", at(8, 6, 0, 0)},
+		{"this wed", at(8, 6, 0, 0)},
+		{"next wednesday", at(8, 13, 0, 0)},
+		{"next monday 14:00", at(8, 11, 14, 0)},
+		{"last monday", at(8, 4, 0, 0)},
+		{"last wednesday", at(7, 30, 0, 0)},
+
+		// Offsets
+		{"-3d", at(8, 3, 10, 30)},
+		{"+2w", at(8, 20, 10, 30)},
+		{"36h", at(8, 7, 22, 30)},
+		{"-90m", at(8, 6, 9, 0)},
+		{"+1mo", at(9, 6, 10, 30)},
+		{"-1y", time.Date(2024, 8, 6, 10, 30, 0, 0, refLoc)},
+		{"3 days ago", at(8, 3, 10, 30)},
+		{"in 2 hours", at(8, 6, 12, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAt(tt.input, refNow)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseAt(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAt_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"14:30",
+		"2024-13-01",
+		"01/15/2024",
+		"next week",
+		"next",
+		"today 25:00",
+		"tomorrow at the meeting",
+		"tomorrow 3pm extra",
+		"today afternoon",
+		"today in morning",
+		"in 3 days ago",
+		"-3x",
+	} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseAt(input, refNow); err == nil {
+				t.Errorf("ParseAt(%q) expected error", input)
+			}
+		})
+	}
+}
+
+func TestParseRangeAt(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"2025-08-01..2025-08-15", at(8, 1, 0, 0), at(8, 16, 0, 0)},
+		{"2025-08-01 09:00..2025-08-01 17:00", at(8, 1, 9, 0), at(8, 1, 17, 0)},
+		{"friday..next monday", at(8, 8, 0, 0), at(8, 12, 0, 0)},
+		{"last monday..now", at(8, 4, 0, 0), refNow},
+		{"today..+3d", at(8, 6, 0, 0), at(8, 9, 10, 30)},
+		{"2025-08-01..", at(8, 1, 0, 0), time.Time{}},
+		{"..2025-08-15", time.Time{}, at(8, 16, 0, 0)},
+		{"yesterday", at(8, 5, 0, 0), at(8, 6, 0, 0)},
+		{"2025-08-01", at(8, 1, 0, 0), at(8, 2, 0, 0)},
+		{"-3d", at(8, 3, 10, 30), refNow},
+		{"+7d", refNow, at(8, 13, 10, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			start, end, err := ParseRangeAt(tt.input, refNow)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("ParseRangeAt(%q) = %v .. %v, want %v .. %v", tt.input, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseRangeAt_Invalid(t *testing.T) {
+	for _, input := range []string{"", "..", "2025-08-15..2025-08-01", "tomorrow..today", "monday..friday", "soon..later"} {
+		t.Run(input, func(t *testing.T) {
+			if _, _, err := ParseRangeAt(input, refNow); err == nil {
+				t.Errorf("ParseRangeAt(%q) expected error", input)
+			}
+		})
+	}
+}
+
+func TestParseSinceAt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"30d", at(7, 7, 10, 30)},
+		{"2w", at(7, 23, 10, 30)},
+		{"36h", at(8, 4, 22, 30)},
+		{"3 days", at(8, 3, 10, 30)},
+		{"1h30m", at(8, 6, 9, 0)},
+		{"-3d", at(8, 3, 10, 30)},
+		{"3 days ago", at(8, 3, 10, 30)},
+		{"yesterday", at(8, 5, 0, 0)},
+		{"last monday", at(8, 4, 0, 0)},
+		{"today", at(8, 6, 0, 0)},
+		{"2025-08-01", at(8, 1, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSinceAt(tt.input, refNow)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSinceAt(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "soon", "0d", "+3d", "in 2 days", "tomorrow"} {
+		if _, err := ParseSinceAt(input, refNow); err == nil {
+			t.Errorf("ParseSinceAt(%q) expected error", input)
+		}
+	}
+	if _, err := ParseSinceAt("0d", refNow); err == nil || err.Error() != `"0d" is not in the past` {
+		t.Errorf("ParseSinceAt(\"0d\") error = %v, want it to quote the input", err)
+	}
+}
+
+func TestParseUntilAt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"7d", at(8, 13, 10, 30)},
+		{"+2w", at(8, 20, 10, 30)},
+		{"1h30m", at(8, 6, 12, 0)},
+		{"in 2 days", at(8, 8, 10, 30)},
+		{"friday", at(8, 9, 0, 0)},
+		{"today", at(8, 7, 0, 0)},
+		{"2025-08-15", at(8, 16, 0, 0)},
+		{"tomorrow 3pm", at(8, 7, 15, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseUntilAt(tt.input, refNow)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseUntilAt(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "soon", "0d", "-3d", "yesterday", "today 9am"} {
+		if _, err := ParseUntilAt(input, refNow); err == nil {
+			t.Errorf("ParseUntilAt(%q) expected error", input)
+		}
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantHour   int
+		wantMinute int
+		wantErr    bool
+	}{
+		{"14:00", 14, 0, false},
+		{"9:05", 9, 5, false},
+		{"3pm", 15, 0, false},
+		{"3:30PM", 15, 30, false},
+		{"12am", 0, 0, false},
+		{"12pm", 12, 0, false},
+		{"noon", 12, 0, false},
+		{"midnight", 0, 0, false},
+		{"24:00", 0, 0, true},
+		{"13pm", 0, 0, true},
+		{"0am", 0, 0, true},
+		{"3", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			hour, minute, err := ParseTimeOfDay(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimeOfDay(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (hour != tt.wantHour || minute != tt.wantMinute) {
+				t.Errorf("ParseTimeOfDay(%q) = %d:%02d, want %d:%02d", tt.input, hour, minute, tt.wantHour, tt.wantMinute)
+			}
+		})
+	}
+}
+
+func TestParse_UsesLocalTime(t *testing.T) {
+	got, err := Parse("today")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	if got.Location() != now.Location() || got.Day() != now.Day() {
+		t.Errorf("Parse(today) = %v, want today in local time", got)
+	}
+}
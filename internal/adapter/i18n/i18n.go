@@ -0,0 +1,142 @@
+// Package i18n translates goog's user-facing messages: help, prompts,
+// warnings and error hints. Messages are keyed by their English text, so
+// a message missing from a catalog is shown in English. Machine formats,
+// such as JSON keys and values, are never translated.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// English is the language the messages are written in.
+const English = "en"
+
+// catalogFS holds one catalog per language, catalogs/<language>.json,
+// mapping English messages to their translation.
+//
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+var (
+	mu       sync.RWMutex
+	current  = English
+	messages map[string]string
+)
+
+// languageVars are the environment variables the language is taken from,
+// in order: goog's own, then the POSIX locale variables.
+var languageVars = []string{"GOOG_LANG", "LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Detect returns the language asked for by the environment: the first of
+// GOOG_LANG, LC_ALL, LC_MESSAGES and LANG that is set, reduced to its
+// language code ("de_DE.UTF-8" is "de"). The C and POSIX locales are
+// English. The variable the language came from is returned with it.
+func Detect(getenv func(string) string) (lang, from string) {
+	for _, name := range languageVars {
+		if value := getenv(name); value != "" {
+			return Normalize(value), name
+		}
+	}
+	return English, ""
+}
+
+// Normalize reduces a language tag or locale name, such as "de-AT" or
+// "es_ES.UTF-8@euro", to its lower-case language code.
+func Normalize(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "_-.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ToLower(tag)
+	if tag == "" || tag == "c" || tag == "posix" {
+		return English
+	}
+	return tag
+}
+
+// Languages returns the languages messages can be shown in, English
+// first.
+func Languages() []string {
+	langs := []string{English}
+	entries, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		return langs
+	}
+	var others []string
+	for _, entry := range entries {
+		others = append(others, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(others)
+	return append(langs, others...)
+}
+
+// Supported reports whether messages can be shown in lang.
+func Supported(lang string) bool {
+	for _, l := range Languages() {
+		if l == Normalize(lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLanguage shows messages in lang from now on. An unsupported language
+// selects English and returns an error.
+func SetLanguage(lang string) error {
+	lang = Normalize(lang)
+	catalog, err := loadCatalog(lang)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil {
+		current, messages = English, nil
+		return err
+	}
+	current, messages = lang, catalog
+	return nil
+}
+
+// loadCatalog reads the catalog of lang; English has none.
+func loadCatalog(lang string) (map[string]string, error) {
+	if lang == English {
+		return nil, nil
+	}
+	data, err := catalogFS.ReadFile("catalogs/" + lang + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no messages in %q; available: %s", lang, strings.Join(Languages(), ", "))
+	}
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog for %q: %w", lang, err)
+	}
+	return catalog, nil
+}
+
+// Language returns the language messages are shown in.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the translation of an English message, or the message itself
+// when the catalog has none.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := messages[msg]; ok && translated != "" {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats the translation of an English format string.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
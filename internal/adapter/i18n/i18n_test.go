@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantLang string
+		wantFrom string
+	}{
+		{"nothing set", nil, English, ""},
+		{"GOOG_LANG wins", map[string]string{"GOOG_LANG": "es", "LANG": "de_DE.UTF-8"}, "es", "GOOG_LANG"},
+		{"LC_ALL before LANG", map[string]string{"LC_ALL": "de_AT.UTF-8", "LANG": "es_ES.UTF-8"}, "de", "LC_ALL"},
+		{"LC_MESSAGES before LANG", map[string]string{"LC_MESSAGES": "es", "LANG": "de_DE"}, "es", "LC_MESSAGES"},
+		{"LANG", map[string]string{"LANG": "de_DE.UTF-8"}, "de", "LANG"},
+		{"C locale is English", map[string]string{"LC_ALL": "C.UTF-8"}, English, "LC_ALL"},
+		{"POSIX locale is English", map[string]string{"LANG": "POSIX"}, English, "LANG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lang, from := Detect(func(name string) string { return tt.env[name] })
+			if lang != tt.wantLang || from != tt.wantFrom {
+				t.Errorf("Detect() = %q, %q; want %q, %q", lang, from, tt.wantLang, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for tag, want := range map[string]string{
+		"de":               "de",
+		"DE":               "de",
+		"de-AT":            "de",
+		"es_ES.UTF-8@euro": "es",
+		" en_US ":          "en",
+		"":                 English,
+	} {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	t.Cleanup(func() { _ = SetLanguage(English) })
+
+	if err := SetLanguage("de_DE.UTF-8"); err != nil {
+		t.Fatalf("SetLanguage(de) error = %v", err)
+	}
+	if Language() != "de" {
+		t.Errorf("Language() = %q, want de", Language())
+	}
+	if got := T("Error:"); got != "Fehler:" {
+		t.Errorf("T(Error:) = %q, want Fehler:", got)
+	}
+	if got := Sprintf("help for %s", "mail"); got != "Hilfe zu mail" {
+		t.Errorf("Sprintf() = %q, want Hilfe zu mail", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("T() of an unknown message = %q, want it unchanged", got)
+	}
+
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("SetLanguage(xx) error = nil, want an error")
+	}
+	if Language() != English || T("Error:") != "Error:" {
+		t.Errorf("after an unsupported language, Language() = %q, want English", Language())
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	if len(langs) == 0 || langs[0] != English {
+		t.Fatalf("Languages() = %v, want English first", langs)
+	}
+	for _, lang := range []string{"de", "es"} {
+		if !slices.Contains(langs, lang) || !Supported(lang) {
+			t.Errorf("Languages() = %v, want %s", langs, lang)
+		}
+	}
+	if Supported("xx") {
+		t.Error("Supported(xx) = true, want false")
+	}
+}
+
+// formatVerb matches the fmt verbs of a message.
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogs checks every catalog parses and keeps the format verbs of
+// each message, so a translation cannot break the arguments it is given.
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Languages()[1:] {
+		catalog, err := loadCatalog(lang)
+		if err != nil {
+			t.Errorf("catalog %s: %v", lang, err)
+			continue
+		}
+		if len(catalog) == 0 {
+			t.Errorf("catalog %s is empty", lang)
+		}
+		for msg, translated := range catalog {
+			want := formatVerb.FindAllString(msg, -1)
+			got := formatVerb.FindAllString(translated, -1)
+			if !slices.Equal(got, want) {
+				t.Errorf("catalog %s: %q has verbs %v, want %v as in %q", lang, translated, got, want, msg)
+			}
+		}
+	}
+}
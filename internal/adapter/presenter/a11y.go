@@ -0,0 +1,166 @@
+package presenter
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Highlight markers of accessible output: text a screen reader can read,
+// in place of colour.
+const (
+	accessibleHighlightStart = "*"
+	accessibleHighlightEnd   = "*"
+)
+
+var (
+	accessibleMu sync.RWMutex
+	accessible   bool
+)
+
+// SetAccessible turns accessible output on or off. It avoids signals that
+// are only visual: tables are written as blocks of "Key: value" lines that
+// a screen reader reads in order, and highlighted terms are marked with
+// asterisks instead of colour. Callers replace calendar grids with lists.
+func SetAccessible(on bool) {
+	accessibleMu.Lock()
+	defer accessibleMu.Unlock()
+	accessible = on
+}
+
+// Accessible reports whether accessible output is on.
+func Accessible() bool {
+	accessibleMu.RLock()
+	defer accessibleMu.RUnlock()
+	return accessible
+}
+
+// blockKeys names the columns whose headers are symbols.
+var blockKeys = map[string]string{
+	"!": "Important",
+	"#": "Number",
+}
+
+// blockAcronyms are the words kept in upper case when a header is written
+// as a sentence.
+var blockAcronyms = map[string]bool{"ID": true, "API": true, "URL": true}
+
+// blockKey returns the key a column header is shown with in a block:
+// symbols are named and upper-case headers, such as "LAST USED" or "FILE
+// ID", are written as a sentence, "Last used" or "File ID".
+func blockKey(header string) string {
+	if key, ok := blockKeys[header]; ok {
+		return key
+	}
+	if header != strings.ToUpper(header) || header == strings.ToLower(header) {
+		return header
+	}
+	words := strings.Fields(header)
+	for i, word := range words {
+		if blockAcronyms[word] {
+			continue
+		}
+		word = strings.ToLower(word)
+		if i == 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+	return strings.Join(words, " ")
+}
+
+// writeBlocks writes each row as a block of "Key: value" lines, one per
+// column, with a blank line between blocks. Empty cells are left out and
+// the lines of a multi-line cell after the first are indented. A table of
+// Field and Value columns is one block of its rows.
+func writeBlocks(sb *strings.Builder, headers []string, rows [][]string) {
+	if len(headers) == 2 && headers[0] == "Field" && headers[1] == "Value" {
+		for _, row := range rows {
+			if len(row) == 2 {
+				writeBlockLine(sb, row[0], row[1])
+			}
+		}
+		return
+	}
+	for i, row := range rows {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for j, cell := range row {
+			header := ""
+			if j < len(headers) {
+				header = headers[j]
+			}
+			if header == "!" && cell != "" {
+				cell = "yes"
+			}
+			writeBlockLine(sb, blockKey(header), cell)
+		}
+	}
+}
+
+// writeBlockLine writes one "Key: value" line of a block, or the value
+// alone when the column has no header.
+func writeBlockLine(sb *strings.Builder, key, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if key != "" {
+		sb.WriteString(key + ": ")
+	}
+	sb.WriteString(strings.ReplaceAll(value, "\n", "\n  "))
+	sb.WriteString("\n")
+}
+
+// BlockWriter collects a table written for text/tabwriter, a header line
+// then a line per row with tab-separated cells, and writes it as blocks of
+// "Key: value" lines on Flush. A blank line starts another table.
+type BlockWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// NewBlockWriter returns a BlockWriter writing to w.
+func NewBlockWriter(w io.Writer) *BlockWriter {
+	return &BlockWriter{w: w}
+}
+
+// Write adds p to the table.
+func (b *BlockWriter) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+// Flush writes the tables collected so far as blocks.
+func (b *BlockWriter) Flush() error {
+	var sb strings.Builder
+	var headers []string
+	var rows [][]string
+	flushTable := func() {
+		if headers != nil && len(rows) > 0 {
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			writeBlocks(&sb, headers, rows)
+		}
+		headers, rows = nil, nil
+	}
+
+	text := strings.TrimSuffix(b.buf.String(), "\n")
+	b.buf.Reset()
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushTable()
+		case headers == nil:
+			headers = strings.Split(line, "\t")
+		default:
+			rows = append(rows, strings.Split(line, "\t"))
+		}
+	}
+	flushTable()
+
+	_, err := io.WriteString(b.w, sb.String())
+	return err
+}
@@ -0,0 +1,145 @@
+package presenter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// withAccessible turns accessible output on for the rest of the test.
+func withAccessible(t *testing.T) {
+	t.Helper()
+	SetAccessible(true)
+	t.Cleanup(func() { SetAccessible(false) })
+}
+
+func TestAccessible_MessageList(t *testing.T) {
+	withAccessible(t)
+	SetImportanceColumn(true)
+	t.Cleanup(func() { SetImportanceColumn(false) })
+
+	msgs := []*mail.Message{
+		mail.NewMessage("msg-1", "t-1", "alice@example.com", "A subject longer than the forty columns a table allows", "Body"),
+		mail.NewMessage("msg-2", "t-2", "bob@example.com", "Short", "Body"),
+	}
+	msgs[0].IsImportant = true
+	msgs[0].Date = time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	msgs[1].Date = time.Date(2024, 1, 16, 11, 0, 0, 0, time.UTC)
+
+	got := NewTablePresenter().RenderMessages(msgs)
+
+	blocks := strings.Split(strings.TrimSpace(got), "\n\n")
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want one per message:\n%s", len(blocks), got)
+	}
+	for _, want := range []string{
+		"Important: yes\nID: msg-1\n",
+		"Subject: A subject longer than the forty columns a table allows\n",
+	} {
+		if !strings.Contains(blocks[0], want) {
+			t.Errorf("first block is missing %q:\n%s", want, blocks[0])
+		}
+	}
+	if strings.Contains(blocks[1], "Important") {
+		t.Errorf("second block marks an unimportant message:\n%s", blocks[1])
+	}
+	if strings.ContainsAny(got, "|+─│") {
+		t.Errorf("blocks contain table borders:\n%s", got)
+	}
+}
+
+func TestAccessible_FieldValueTable(t *testing.T) {
+	withAccessible(t)
+
+	msg := mail.NewMessage("msg-1", "t-1", "alice@example.com", "Hello", "Body")
+	got := NewTablePresenter().RenderMessage(msg)
+
+	for _, want := range []string{"ID: msg-1\n", "Subject: Hello\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Field:") || strings.Contains(got, "\n\n") {
+		t.Errorf("a Field/Value table should be one block of its rows:\n%s", got)
+	}
+}
+
+func TestAccessible_Highlight(t *testing.T) {
+	withAccessible(t)
+	withHighlight(t, "invoice")
+
+	if got := Highlight("Your Invoice"); got != "Your *Invoice*" {
+		t.Errorf("Highlight() = %q, want the term marked with asterisks", got)
+	}
+}
+
+func TestAccessible_MessageStream(t *testing.T) {
+	withAccessible(t)
+
+	var buf bytes.Buffer
+	s, ok := NewMessageStream(&buf, NewTablePresenter())
+	if !ok {
+		t.Fatal("expected accessible tables to stream")
+	}
+	for _, id := range []string{"msg1", "msg2"} {
+		if err := s.Write(&mail.Message{ID: id, Subject: "Hi " + id, Date: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+	if len(blocks) != 2 || !strings.HasPrefix(blocks[1], "ID: msg2\n") {
+		t.Errorf("want a block per message separated by a blank line, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	s, _ = NewMessageStream(&buf, NewTablePresenter())
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No messages found\n" {
+		t.Errorf("empty stream = %q", buf.String())
+	}
+}
+
+func TestBlockWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBlockWriter(&buf)
+	_, _ = w.Write([]byte("#\tALIAS\tLAST USED\t\n"))
+	_, _ = w.Write([]byte("1\twork\tnever\t(default)\n2\thome\t\t\n"))
+	_, _ = w.Write([]byte("\nAPI\tCALLS\ngmail\t3\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Number: 1\nAlias: work\nLast used: never\n(default)\n" +
+		"\n" +
+		"Number: 2\nAlias: home\n" +
+		"\n" +
+		"API: gmail\nCalls: 3\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestBlockKey(t *testing.T) {
+	for header, want := range map[string]string{
+		"LAST USED": "Last used",
+		"FILE ID":   "File ID",
+		"ID":        "ID",
+		"Subject":   "Subject",
+		"!":         "Important",
+		"#":         "Number",
+		"":          "",
+	} {
+		if got := blockKey(header); got != want {
+			t.Errorf("blockKey(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"encoding/json"
+)
+
+// Page describes one page of a list command's results, for the JSON
+// envelope that lets scripts page through a listing one call at a time.
+type Page struct {
+	// NextPageToken is passed to --page-token for the next page; it is
+	// empty on the last page.
+	NextPageToken string `json:"next_page_token"`
+	// TotalEstimate is the API's estimate of the number of results over
+	// all pages, or nil when the API gives none.
+	TotalEstimate *int `json:"total_estimate"`
+	// FetchedCount is the number of items fetched for this page, before
+	// --filter is applied.
+	FetchedCount int `json:"fetched_count"`
+	// Warnings describes items included with partial data.
+	Warnings []string `json:"warnings"`
+}
+
+// envelope is the JSON object a page of results is wrapped in.
+type envelope struct {
+	Items json.RawMessage `json:"items"`
+	Page
+}
+
+// Envelope wraps the JSON rendering of a list in an object holding the
+// items and the page metadata. Output that is not valid JSON is returned
+// unchanged.
+func Envelope(items string, page Page) string {
+	if !json.Valid([]byte(items)) {
+		return items
+	}
+	if page.Warnings == nil {
+		page.Warnings = []string{}
+	}
+	data, err := json.MarshalIndent(envelope{Items: json.RawMessage(items), Page: page}, "", "  ")
+	if err != nil {
+		return items
+	}
+	return string(data)
+}
+
+// Estimate returns a pointer to n, for Page.TotalEstimate.
+func Estimate(n int) *int {
+	return &n
+}
@@ -0,0 +1,62 @@
+package presenter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Run("wraps items with page metadata", func(t *testing.T) {
+		out := Envelope(`[{"ID": "a"}, {"ID": "b"}]`, Page{
+			NextPageToken: "next",
+			TotalEstimate: Estimate(42),
+			FetchedCount:  2,
+			Warnings:      []string{"message c: only its ID"},
+		})
+
+		var got struct {
+			Items         []map[string]string `json:"items"`
+			NextPageToken string              `json:"next_page_token"`
+			TotalEstimate *int                `json:"total_estimate"`
+			FetchedCount  int                 `json:"fetched_count"`
+			Warnings      []string            `json:"warnings"`
+		}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("Envelope() is not JSON: %v\n%s", err, out)
+		}
+		if len(got.Items) != 2 || got.Items[1]["ID"] != "b" {
+			t.Errorf("items = %v, want the two items", got.Items)
+		}
+		if got.NextPageToken != "next" || got.FetchedCount != 2 {
+			t.Errorf("next_page_token = %q, fetched_count = %d", got.NextPageToken, got.FetchedCount)
+		}
+		if got.TotalEstimate == nil || *got.TotalEstimate != 42 {
+			t.Errorf("total_estimate = %v, want 42", got.TotalEstimate)
+		}
+		if len(got.Warnings) != 1 {
+			t.Errorf("warnings = %v, want one", got.Warnings)
+		}
+	})
+
+	t.Run("unknown estimate is null and warnings empty", func(t *testing.T) {
+		var got map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(Envelope("[]", Page{})), &got); err != nil {
+			t.Fatal(err)
+		}
+		if string(got["total_estimate"]) != "null" {
+			t.Errorf("total_estimate = %s, want null", got["total_estimate"])
+		}
+		if string(got["warnings"]) != "[]" {
+			t.Errorf("warnings = %s, want []", got["warnings"])
+		}
+		if string(got["next_page_token"]) != `""` {
+			t.Errorf("next_page_token = %s, want \"\"", got["next_page_token"])
+		}
+	})
+
+	t.Run("leaves output that is not JSON", func(t *testing.T) {
+		if out := Envelope("No messages", Page{}); out != "No messages" {
+			t.Errorf("Envelope() = %q, want the input unchanged", out)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package presenter
+
+import "errors"
+
+// DetailedError is implemented by errors that carry an HTTP status, Google's
+// error reason and a hint on how to fix the problem, such as the repository
+// package's APIError.
+type DetailedError interface {
+	error
+	HTTPStatus() int
+	ErrorReason() string
+	RemediationHint() string
+}
+
+// AsDetailedError returns the first DetailedError in err's chain.
+func AsDetailedError(err error) (DetailedError, bool) {
+	var detailed DetailedError
+	if errors.As(err, &detailed) {
+		return detailed, true
+	}
+	return nil, false
+}
+
+// ErrorHint returns the remediation hint carried by err, or "" if it has none.
+func ErrorHint(err error) string {
+	if detailed, ok := AsDetailedError(err); ok {
+		return detailed.RemediationHint()
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package presenter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// detailedTestError is a DetailedError for tests.
+type detailedTestError struct {
+	status       int
+	reason, hint string
+}
+
+func (e *detailedTestError) Error() string           { return "rate limited (status 403): slow down" }
+func (e *detailedTestError) HTTPStatus() int         { return e.status }
+func (e *detailedTestError) ErrorReason() string     { return e.reason }
+func (e *detailedTestError) RemediationHint() string { return e.hint }
+
+func newDetailedTestError() *detailedTestError {
+	return &detailedTestError{status: 403, reason: "userRateLimitExceeded", hint: "pause between commands"}
+}
+
+func TestAsDetailedError(t *testing.T) {
+	wrapped := fmt.Errorf("failed to list messages: %w", newDetailedTestError())
+
+	detailed, ok := AsDetailedError(wrapped)
+	if !ok || detailed.ErrorReason() != "userRateLimitExceeded" {
+		t.Fatalf("expected the wrapped detailed error, got %v, %v", detailed, ok)
+	}
+	if ErrorHint(wrapped) != "pause between commands" {
+		t.Errorf("ErrorHint() = %q", ErrorHint(wrapped))
+	}
+
+	if _, ok := AsDetailedError(errors.New("plain")); ok {
+		t.Error("plain errors have no details")
+	}
+	if ErrorHint(errors.New("plain")) != "" {
+		t.Error("plain errors have no hint")
+	}
+}
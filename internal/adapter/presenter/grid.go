@@ -0,0 +1,191 @@
+package presenter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// gridColumns is the number of day columns in a calendar grid.
+const gridColumns = 7
+
+// minGridCellWidth keeps cells readable on narrow terminals; below it the
+// grid overflows the terminal instead of shrinking further.
+const minGridCellWidth = 8
+
+// MonthCellEvents is how many events a month grid cell lists before
+// summarizing the rest as "+N more". A single extra event is listed
+// rather than counted, since it takes the same line.
+const MonthCellEvents = 3
+
+// RenderWeekGrid draws the seven days from start as one row of day cells
+// that list every event, sized to fit width columns.
+func RenderWeekGrid(events []*calendar.Event, start time.Time, width int) string {
+	days := gridDays(start, 1)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Week of %s\n", CurrentLocale().Date(days[0]))
+	writeGrid(&sb, days, events, gridCellWidth(width), 0, func(day time.Time) string {
+		return day.Format("Mon 2")
+	}, nil)
+	return sb.String()
+}
+
+// RenderMonthGrid draws the month containing month as rows of weeks that
+// begin on weekStart, sized to fit width columns. Days outside the month
+// are shown in parentheses and today is marked with "*".
+func RenderMonthGrid(events []*calendar.Event, month time.Time, weekStart time.Weekday, width int) string {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	start := first.AddDate(0, 0, -int((first.Weekday()-weekStart+7)%7))
+	span := int(civilDate(first.AddDate(0, 1, 0)).Sub(civilDate(start)).Hours() / 24)
+	days := gridDays(start, (span+gridColumns-1)/gridColumns)
+
+	var sb strings.Builder
+	sb.WriteString(first.Format("January 2006") + "\n")
+	today := time.Now().In(month.Location())
+	weekday := func(day time.Time) string { return day.Format("Mon") }
+	writeGrid(&sb, days, events, gridCellWidth(width), MonthCellEvents, weekday, func(day time.Time) string {
+		label := fmt.Sprintf("%d", day.Day())
+		if day.Month() != first.Month() {
+			label = "(" + label + ")"
+		}
+		if sameDay(day, today) {
+			label += " *"
+		}
+		return label
+	})
+	return sb.String()
+}
+
+// gridDays returns weeks*7 consecutive midnights starting at start's day.
+func gridDays(start time.Time, weeks int) []time.Time {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	days := make([]time.Time, weeks*gridColumns)
+	for i := range days {
+		days[i] = start.AddDate(0, 0, i)
+	}
+	return days
+}
+
+// gridCellWidth returns the cell width that fits seven cells and their
+// borders into width.
+func gridCellWidth(width int) int {
+	if w := (width - (gridColumns + 1)) / gridColumns; w > minGridCellWidth {
+		return w
+	}
+	return minGridCellWidth
+}
+
+// writeGrid writes days as rows of seven cells. header labels the columns
+// once, from the first row's days; label, when set, adds a first line to
+// every cell. maxEvents limits the events listed per cell; 0 lists all.
+func writeGrid(sb *strings.Builder, days []time.Time, events []*calendar.Event, cellWidth, maxEvents int,
+	header, label func(time.Time) string) {
+	border := "+" + strings.Repeat(strings.Repeat("-", cellWidth)+"+", gridColumns)
+	sb.WriteString(border + "\n")
+
+	cells := make([]string, gridColumns)
+	for i := range cells {
+		cells[i] = header(days[i])
+	}
+	writeGridLine(sb, cells, cellWidth)
+	sb.WriteString(border + "\n")
+
+	for row := 0; row < len(days); row += gridColumns {
+		lines := make([][]string, gridColumns)
+		height := 0
+		for i := range lines {
+			day := days[row+i]
+			if label != nil {
+				lines[i] = append(lines[i], label(day))
+			}
+			lines[i] = append(lines[i], cellEvents(events, day, maxEvents)...)
+			height = max(height, len(lines[i]))
+		}
+		for n := 0; n < max(height, 1); n++ {
+			for i := range cells {
+				cells[i] = ""
+				if n < len(lines[i]) {
+					cells[i] = lines[i][n]
+				}
+			}
+			writeGridLine(sb, cells, cellWidth)
+		}
+		sb.WriteString(border + "\n")
+	}
+}
+
+// writeGridLine writes one line of cells, truncating text to the cell width.
+func writeGridLine(sb *strings.Builder, cells []string, cellWidth int) {
+	sb.WriteString("|")
+	for _, cell := range cells {
+		text := runewidth.Truncate(" "+cell, cellWidth, "…")
+		sb.WriteString(runewidth.FillRight(text, cellWidth) + "|")
+	}
+	sb.WriteString("\n")
+}
+
+// cellEvents returns the lines for the events on day: all-day events
+// first, then timed events by start time, each prefixed with its start
+// time on the day it begins. Beyond maxEvents the rest are counted, as
+// described for MonthCellEvents.
+func cellEvents(events []*calendar.Event, day time.Time, maxEvents int) []string {
+	var onDay []*calendar.Event
+	for _, e := range events {
+		if e != nil && eventOnDay(e, day) {
+			onDay = append(onDay, e)
+		}
+	}
+	sort.SliceStable(onDay, func(i, j int) bool {
+		if onDay[i].AllDay != onDay[j].AllDay {
+			return onDay[i].AllDay
+		}
+		return onDay[i].Start.Before(onDay[j].Start)
+	})
+
+	lines := make([]string, 0, len(onDay))
+	for i, e := range onDay {
+		if maxEvents > 0 && i == maxEvents && len(onDay) > maxEvents+1 {
+			lines = append(lines, fmt.Sprintf("+%d more", len(onDay)-maxEvents))
+			break
+		}
+		if !e.AllDay && sameDay(e.Start.In(day.Location()), day) {
+			lines = append(lines, CurrentLocale().Time(e.Start.In(day.Location()))+" "+e.Title)
+		} else {
+			lines = append(lines, e.Title)
+		}
+	}
+	return lines
+}
+
+// eventOnDay reports whether e covers any part of day. All-day events are
+// compared by date, since their times are midnights in UTC.
+func eventOnDay(e *calendar.Event, day time.Time) bool {
+	if e.AllDay {
+		d := civilDate(day)
+		start := civilDate(e.Start)
+		end := civilDate(e.End)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+		return !d.Before(start) && d.Before(end)
+	}
+	dayEnd := day.AddDate(0, 0, 1)
+	if !e.End.After(e.Start) {
+		return !e.Start.Before(day) && e.Start.Before(dayEnd)
+	}
+	return e.Start.Before(dayEnd) && e.End.After(day)
+}
+
+// civilDate returns the date of t, in t's own time zone, as a UTC midnight.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sameDay reports whether a and b fall on the same date.
+func sameDay(a, b time.Time) bool {
+	return civilDate(a).Equal(civilDate(b))
+}
@@ -0,0 +1,100 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+func TestRenderWeekGrid(t *testing.T) {
+	loc := time.UTC
+	start := time.Date(2024, 3, 10, 0, 0, 0, 0, loc) // Sunday
+	events := []*calendar.Event{
+		{Title: "Standup", Start: time.Date(2024, 3, 11, 9, 0, 0, 0, loc), End: time.Date(2024, 3, 11, 9, 15, 0, 0, loc)},
+		{Title: "Offsite", AllDay: true, Start: time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{Title: "A very long planning session title", Start: time.Date(2024, 3, 12, 14, 0, 0, 0, loc), End: time.Date(2024, 3, 12, 15, 0, 0, 0, loc)},
+	}
+
+	out := RenderWeekGrid(events, start, 120)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+
+	if !strings.HasPrefix(lines[0], "Week of ") {
+		t.Errorf("expected a week heading, got %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "Sun 10") || !strings.Contains(lines[2], "Sat 16") {
+		t.Errorf("expected Sunday to Saturday columns, got %q", lines[2])
+	}
+	for _, line := range lines[1:] {
+		if n := utf8.RuneCountInString(line); n != utf8.RuneCountInString(lines[1]) || n > 120 {
+			t.Errorf("line %q is %d wide, want the border width within 120", line, n)
+		}
+	}
+	if !strings.Contains(out, "09:00 Standup") {
+		t.Errorf("expected the timed event with its start, got:\n%s", out)
+	}
+	if strings.Count(out, "Offsite") != 2 {
+		t.Errorf("expected the two-day event on both days, got:\n%s", out)
+	}
+	if strings.Contains(out, "planning session title") || !strings.Contains(out, "…") {
+		t.Errorf("expected long titles to be truncated, got:\n%s", out)
+	}
+	// The all-day event sorts before the timed one on Tuesday
+	if strings.Index(out, "Offsite") > strings.Index(out, "14:00 A") {
+		t.Errorf("expected all-day events first, got:\n%s", out)
+	}
+}
+
+func TestRenderMonthGrid(t *testing.T) {
+	loc := time.UTC
+	month := time.Date(2024, 2, 15, 0, 0, 0, 0, loc)
+	var events []*calendar.Event
+	for i := 0; i < 5; i++ {
+		at := time.Date(2024, 2, 20, 9+i, 0, 0, 0, loc)
+		events = append(events, &calendar.Event{Title: "Meeting", Start: at, End: at.Add(time.Hour)})
+	}
+
+	t.Run("week starting Monday", func(t *testing.T) {
+		out := RenderMonthGrid(events, month, time.Monday, 120)
+		if !strings.HasPrefix(out, "February 2024\n") {
+			t.Errorf("expected a month heading, got:\n%s", out)
+		}
+		header := strings.Split(out, "\n")[2]
+		if !strings.HasPrefix(strings.TrimLeft(header, "| "), "Mon") {
+			t.Errorf("expected Monday first, got %q", header)
+		}
+		// February 2024 starts on a Thursday, so the grid opens with 29 January
+		if !strings.Contains(out, "(29)") || !strings.Contains(out, "(3)") {
+			t.Errorf("expected neighbouring days in parentheses, got:\n%s", out)
+		}
+		if strings.Count(out, "Meeting") != MonthCellEvents || !strings.Contains(out, "+2 more") {
+			t.Errorf("expected %d events and a count of the rest, got:\n%s", MonthCellEvents, out)
+		}
+		// 29 Jan to 3 Mar is five weeks: heading, border, header, border, then five rows
+		if rows := strings.Count(out, "\n+"); rows != 7 {
+			t.Errorf("got %d borders, want 7", rows)
+		}
+	})
+
+	t.Run("week starting Sunday", func(t *testing.T) {
+		out := RenderMonthGrid(nil, month, time.Sunday, 80)
+		header := strings.Split(out, "\n")[2]
+		if !strings.HasPrefix(strings.TrimLeft(header, "| "), "Sun") {
+			t.Errorf("expected Sunday first, got %q", header)
+		}
+		if !strings.Contains(out, "(28)") {
+			t.Errorf("expected the grid to open on 28 January, got:\n%s", out)
+		}
+	})
+}
+
+func TestGridCellWidth(t *testing.T) {
+	if got := gridCellWidth(80); got != 10 {
+		t.Errorf("gridCellWidth(80) = %d, want 10", got)
+	}
+	if got := gridCellWidth(20); got != minGridCellWidth {
+		t.Errorf("gridCellWidth(20) = %d, want %d", got, minGridCellWidth)
+	}
+}
@@ -0,0 +1,102 @@
+package presenter
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Highlight escape codes: bold yellow, then back to normal text.
+const (
+	highlightStart = "\033[1;33m"
+	highlightEnd   = "\033[0m"
+)
+
+var (
+	highlightMu    sync.RWMutex
+	highlightTerms []string
+)
+
+// SetHighlightTerms sets the words and phrases coloured in message
+// subjects, senders, snippets and bodies. Matching ignores case. No terms
+// turns highlighting off; callers set terms only for terminal output, as
+// the colour codes would corrupt piped text. Accessible output marks the
+// terms with asterisks instead.
+func SetHighlightTerms(terms []string) {
+	highlightMu.Lock()
+	defer highlightMu.Unlock()
+	highlightTerms = nil
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			highlightTerms = append(highlightTerms, term)
+		}
+	}
+}
+
+// HighlightTerms returns the terms set with SetHighlightTerms.
+func HighlightTerms() []string {
+	highlightMu.RLock()
+	defer highlightMu.RUnlock()
+	return highlightTerms
+}
+
+// Highlight colours each match of the highlight terms in s. Tables apply it
+// after fitting their cells, so a cut or wrapped cell never leaves its
+// colour on at a border.
+func Highlight(s string) string {
+	terms := HighlightTerms()
+	if len(terms) == 0 || s == "" {
+		return s
+	}
+
+	lower := strings.ToLower(s)
+	if len(lower) != len(s) {
+		// Lower-casing changed byte offsets, so matches cannot be mapped
+		// back; leave such rare text as it is.
+		return s
+	}
+	marked := make([]bool, len(s))
+	found := false
+	for _, term := range terms {
+		needle := strings.ToLower(term)
+		for from := 0; from < len(lower); {
+			i := strings.Index(lower[from:], needle)
+			if i < 0 {
+				break
+			}
+			start := from + i
+			end := start + len(needle)
+			for j := start; j < end; j++ {
+				marked[j] = true
+			}
+			found = true
+			_, size := utf8.DecodeRuneInString(lower[start:])
+			from = start + size
+		}
+	}
+	if !found {
+		return s
+	}
+
+	start, end := highlightStart, highlightEnd
+	if Accessible() {
+		start, end = accessibleHighlightStart, accessibleHighlightEnd
+	}
+	var b strings.Builder
+	on := false
+	for i := 0; i < len(s); i++ {
+		if marked[i] != on {
+			if marked[i] {
+				b.WriteString(start)
+			} else {
+				b.WriteString(end)
+			}
+			on = marked[i]
+		}
+		b.WriteByte(s[i])
+	}
+	if on {
+		b.WriteString(end)
+	}
+	return b.String()
+}
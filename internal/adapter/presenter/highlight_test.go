@@ -0,0 +1,90 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// withHighlight sets the highlight terms for the rest of the test.
+func withHighlight(t *testing.T, terms ...string) {
+	t.Helper()
+	prev := HighlightTerms()
+	SetHighlightTerms(terms)
+	t.Cleanup(func() { SetHighlightTerms(prev) })
+}
+
+func TestHighlight(t *testing.T) {
+	mark := func(s string) string { return highlightStart + s + highlightEnd }
+
+	t.Run("off without terms", func(t *testing.T) {
+		withHighlight(t)
+		if got := Highlight("Your invoice"); got != "Your invoice" {
+			t.Errorf("Highlight() = %q, want the text unchanged", got)
+		}
+	})
+
+	t.Run("ignores case", func(t *testing.T) {
+		withHighlight(t, "invoice")
+		want := "Your " + mark("Invoice") + " and " + mark("invoice") + "s"
+		if got := Highlight("Your Invoice and invoices"); got != want {
+			t.Errorf("Highlight() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("merges overlapping terms", func(t *testing.T) {
+		withHighlight(t, "due date", "date")
+		want := "The " + mark("due date") + " is near"
+		if got := Highlight("The due date is near"); got != want {
+			t.Errorf("Highlight() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestHighlight_Renderers(t *testing.T) {
+	withHighlight(t, "invoice")
+	msg := &mail.Message{
+		ID:      "msg1",
+		From:    mail.Address{Email: "billing@example.com"},
+		Subject: "Invoice 42 for the quarterly subscription renewal of the team plan",
+		Snippet: "Please find your invoice attached",
+		Body:    "The invoice is due on Friday.",
+		Date:    time.Now(),
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		out := NewPlainPresenter().RenderMessage(msg)
+		for _, want := range []string{"Subject: " + highlightStart + "Invoice", "your " + highlightStart + "invoice", "The " + highlightStart + "invoice"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected %q in:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("table stays aligned", func(t *testing.T) {
+		withTableLayout(t, TableLayout{Width: 60})
+		out := NewTablePresenter().RenderMessages([]*mail.Message{msg})
+		if !strings.Contains(out, highlightStart) {
+			t.Fatalf("expected a highlighted subject:\n%s", out)
+		}
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		for _, line := range lines[1:] {
+			if twwidth.Width(line) != twwidth.Width(lines[0]) {
+				t.Fatalf("misaligned table:\n%s", out)
+			}
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var buf strings.Builder
+		stream, _ := NewMessageStream(&buf, NewTablePresenter())
+		_ = stream.Write(msg)
+		_ = stream.Close()
+		if !strings.Contains(buf.String(), highlightStart+"Invoice") {
+			t.Errorf("expected a highlighted subject:\n%s", buf.String())
+		}
+	})
+}
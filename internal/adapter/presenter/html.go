@@ -0,0 +1,193 @@
+//go:build goog_html
+
+package presenter
+
+import (
+	"html"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// FormatHTML renders standalone HTML reports. It is only available in
+// binaries built with the goog_html build tag:
+//
+//	go build -tags goog_html ./cmd/goog
+const FormatHTML = "html"
+
+func init() {
+	Register(FormatHTML, func() Renderer { return NewHTMLPresenter() })
+}
+
+// htmlStyle is the stylesheet embedded in every report.
+const htmlStyle = `body{font-family:system-ui,sans-serif;margin:2em;color:#222}
+table{border-collapse:collapse;margin-bottom:1.5em}
+th,td{border:1px solid #ccc;padding:.35em .7em;text-align:left;vertical-align:top}
+th{background:#f3f3f3}`
+
+// HTMLPresenter formats output as standalone HTML pages. It reuses the
+// table layouts of TablePresenter with tablewriter's HTML renderer.
+type HTMLPresenter struct {
+	table *TablePresenter
+}
+
+// NewHTMLPresenter creates a new HTMLPresenter.
+func NewHTMLPresenter() *HTMLPresenter {
+	return &HTMLPresenter{table: &TablePresenter{
+		tableOptions: []tablewriter.Option{tablewriter.WithRenderer(renderer.NewHTML())},
+		noFit:        true,
+	}}
+}
+
+// page wraps rendered content in an HTML document. Content without a table
+// (empty results, errors) is escaped into a paragraph.
+func (p *HTMLPresenter) page(title, content string) string {
+	if !strings.Contains(content, "<table") {
+		content = "<p>" + html.EscapeString(content) + "</p>"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>goog: " + html.EscapeString(title) + "</title>\n")
+	b.WriteString("<style>" + htmlStyle + "</style>\n</head>\n<body>\n")
+	b.WriteString("<h1>" + html.EscapeString(title) + "</h1>\n")
+	b.WriteString(content)
+	b.WriteString("\n</body>\n</html>")
+	return b.String()
+}
+
+// RenderMessage renders a single message as an HTML page.
+func (p *HTMLPresenter) RenderMessage(msg *mail.Message) string {
+	return p.page("Message", p.table.RenderMessage(msg))
+}
+
+// RenderMessages renders multiple messages as an HTML page.
+func (p *HTMLPresenter) RenderMessages(msgs []*mail.Message) string {
+	return p.page("Messages", p.table.RenderMessages(msgs))
+}
+
+// RenderDraft renders a single draft as an HTML page.
+func (p *HTMLPresenter) RenderDraft(draft *mail.Draft) string {
+	return p.page("Draft", p.table.RenderDraft(draft))
+}
+
+// RenderDrafts renders multiple drafts as an HTML page.
+func (p *HTMLPresenter) RenderDrafts(drafts []*mail.Draft) string {
+	return p.page("Drafts", p.table.RenderDrafts(drafts))
+}
+
+// RenderThread renders a single thread as an HTML page.
+func (p *HTMLPresenter) RenderThread(thread *mail.Thread) string {
+	return p.page("Thread", p.table.RenderThread(thread))
+}
+
+// RenderThreads renders multiple threads as an HTML page.
+func (p *HTMLPresenter) RenderThreads(threads []*mail.Thread) string {
+	return p.page("Threads", p.table.RenderThreads(threads))
+}
+
+// RenderLabel renders a single label as an HTML page.
+func (p *HTMLPresenter) RenderLabel(label *mail.Label) string {
+	return p.page("Label", p.table.RenderLabel(label))
+}
+
+// RenderLabels renders multiple labels as an HTML page.
+func (p *HTMLPresenter) RenderLabels(labels []*mail.Label) string {
+	return p.page("Labels", p.table.RenderLabels(labels))
+}
+
+// RenderEvent renders a single event as an HTML page.
+func (p *HTMLPresenter) RenderEvent(event *calendar.Event) string {
+	return p.page("Event", p.table.RenderEvent(event))
+}
+
+// RenderEvents renders multiple events as an HTML page.
+func (p *HTMLPresenter) RenderEvents(events []*calendar.Event) string {
+	return p.page("Events", p.table.RenderEvents(events))
+}
+
+// RenderCalendar renders a single calendar as an HTML page.
+func (p *HTMLPresenter) RenderCalendar(cal *calendar.Calendar) string {
+	return p.page("Calendar", p.table.RenderCalendar(cal))
+}
+
+// RenderCalendars renders multiple calendars as an HTML page.
+func (p *HTMLPresenter) RenderCalendars(cals []*calendar.Calendar) string {
+	return p.page("Calendars", p.table.RenderCalendars(cals))
+}
+
+// RenderACLRule renders a single sharing rule as an HTML page.
+func (p *HTMLPresenter) RenderACLRule(rule *calendar.ACLRule) string {
+	return p.page("Sharing Rule", p.table.RenderACLRule(rule))
+}
+
+// RenderACLRules renders multiple sharing rules as an HTML page.
+func (p *HTMLPresenter) RenderACLRules(rules []*calendar.ACLRule) string {
+	return p.page("Sharing Rules", p.table.RenderACLRules(rules))
+}
+
+// RenderAccount renders a single account as an HTML page.
+func (p *HTMLPresenter) RenderAccount(acct *account.Account) string {
+	return p.page("Account", p.table.RenderAccount(acct))
+}
+
+// RenderAccounts renders multiple accounts as an HTML page.
+func (p *HTMLPresenter) RenderAccounts(accts []*account.Account) string {
+	return p.page("Accounts", p.table.RenderAccounts(accts))
+}
+
+// RenderTaskList renders a single task list as an HTML page.
+func (p *HTMLPresenter) RenderTaskList(taskList *domaintasks.TaskList) string {
+	return p.page("Task List", p.table.RenderTaskList(taskList))
+}
+
+// RenderTaskLists renders multiple task lists as an HTML page.
+func (p *HTMLPresenter) RenderTaskLists(taskLists []*domaintasks.TaskList) string {
+	return p.page("Task Lists", p.table.RenderTaskLists(taskLists))
+}
+
+// RenderTask renders a single task as an HTML page.
+func (p *HTMLPresenter) RenderTask(task *domaintasks.Task) string {
+	return p.page("Task", p.table.RenderTask(task))
+}
+
+// RenderTasks renders multiple tasks as an HTML page.
+func (p *HTMLPresenter) RenderTasks(tasks []*domaintasks.Task) string {
+	return p.page("Tasks", p.table.RenderTasks(tasks))
+}
+
+// RenderContact renders a single contact as an HTML page.
+func (p *HTMLPresenter) RenderContact(contact *domaincontacts.Contact) string {
+	return p.page("Contact", p.table.RenderContact(contact))
+}
+
+// RenderContacts renders multiple contacts as an HTML page.
+func (p *HTMLPresenter) RenderContacts(contacts []*domaincontacts.Contact) string {
+	return p.page("Contacts", p.table.RenderContacts(contacts))
+}
+
+// RenderContactGroup renders a single contact group as an HTML page.
+func (p *HTMLPresenter) RenderContactGroup(group *domaincontacts.ContactGroup) string {
+	return p.page("Contact Group", p.table.RenderContactGroup(group))
+}
+
+// RenderContactGroups renders multiple contact groups as an HTML page.
+func (p *HTMLPresenter) RenderContactGroups(groups []*domaincontacts.ContactGroup) string {
+	return p.page("Contact Groups", p.table.RenderContactGroups(groups))
+}
+
+// RenderError renders an error as an HTML page.
+func (p *HTMLPresenter) RenderError(err error) string {
+	return p.page("Error", p.table.RenderError(err))
+}
+
+// RenderSuccess renders a success message as an HTML page.
+func (p *HTMLPresenter) RenderSuccess(msg string) string {
+	return p.page("Success", p.table.RenderSuccess(msg))
+}
@@ -0,0 +1,45 @@
+//go:build goog_html
+
+package presenter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+func TestHTMLPresenter_Registered(t *testing.T) {
+	if _, ok := New(FormatHTML).(*HTMLPresenter); !ok {
+		t.Errorf("New(%q) returned %T", FormatHTML, New(FormatHTML))
+	}
+}
+
+func TestHTMLPresenter_RenderMessages(t *testing.T) {
+	p := NewHTMLPresenter()
+	got := p.RenderMessages([]*mail.Message{
+		{ID: "m1", From: mail.Address{Name: "Alice", Email: "alice@example.com"}, Subject: "Q3 <draft> & notes", Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	})
+
+	for _, want := range []string{"<!DOCTYPE html>", "<title>goog: Messages</title>", "<table", "Q3 &lt;draft&gt; &amp; notes", "</html>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<draft>") {
+		t.Error("cell content should be escaped")
+	}
+}
+
+func TestHTMLPresenter_PlainContent(t *testing.T) {
+	p := NewHTMLPresenter()
+
+	if got := p.RenderMessages(nil); !strings.Contains(got, "<p>No messages found</p>") {
+		t.Errorf("expected empty result paragraph\n%s", got)
+	}
+	if got := p.RenderError(errors.New("bad <input>")); !strings.Contains(got, "<p>Error: bad &lt;input&gt;</p>") {
+		t.Errorf("expected escaped error paragraph\n%s", got)
+	}
+}
@@ -0,0 +1,224 @@
+package presenter
+
+import (
+	"strings"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// IDPresenter prints only the identifier of each entity, one per line, for
+// quiet output that scripts can read without parsing a table. Accounts are
+// identified by alias and contacts by resource name. Errors are rendered
+// as their message and success messages not at all.
+type IDPresenter struct{}
+
+// NewIDPresenter creates a new IDPresenter.
+func NewIDPresenter() *IDPresenter {
+	return &IDPresenter{}
+}
+
+// idLines returns the non-empty identifiers of items, one per line.
+func idLines[T any](items []T, id func(T) string) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		if s := id(item); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func messageID(m *mail.Message) string {
+	if m == nil {
+		return ""
+	}
+	return m.ID
+}
+
+func draftID(d *mail.Draft) string {
+	if d == nil {
+		return ""
+	}
+	return d.ID
+}
+
+func threadID(t *mail.Thread) string {
+	if t == nil {
+		return ""
+	}
+	return t.ID
+}
+
+func labelID(l *mail.Label) string {
+	if l == nil {
+		return ""
+	}
+	return l.ID
+}
+
+func eventID(e *calendar.Event) string {
+	if e == nil {
+		return ""
+	}
+	return e.ID
+}
+
+func calendarID(c *calendar.Calendar) string {
+	if c == nil {
+		return ""
+	}
+	return c.ID
+}
+
+func aclRuleID(r *calendar.ACLRule) string {
+	if r == nil {
+		return ""
+	}
+	return r.ID
+}
+
+func taskListID(tl *domaintasks.TaskList) string {
+	if tl == nil {
+		return ""
+	}
+	return tl.ID
+}
+
+func taskID(t *domaintasks.Task) string {
+	if t == nil {
+		return ""
+	}
+	return t.ID
+}
+
+func contactID(c *domaincontacts.Contact) string {
+	if c == nil {
+		return ""
+	}
+	return c.ResourceName
+}
+
+func contactGroupID(g *domaincontacts.ContactGroup) string {
+	if g == nil {
+		return ""
+	}
+	return g.ResourceName
+}
+
+func accountID(a *account.Account) string {
+	if a == nil {
+		return ""
+	}
+	return a.Alias
+}
+
+// RenderMessage renders the message ID.
+func (p *IDPresenter) RenderMessage(msg *mail.Message) string { return messageID(msg) }
+
+// RenderMessages renders message IDs.
+func (p *IDPresenter) RenderMessages(msgs []*mail.Message) string { return idLines(msgs, messageID) }
+
+// RenderDraft renders the draft ID.
+func (p *IDPresenter) RenderDraft(draft *mail.Draft) string { return draftID(draft) }
+
+// RenderDrafts renders draft IDs.
+func (p *IDPresenter) RenderDrafts(drafts []*mail.Draft) string { return idLines(drafts, draftID) }
+
+// RenderThread renders the thread ID.
+func (p *IDPresenter) RenderThread(thread *mail.Thread) string { return threadID(thread) }
+
+// RenderThreads renders thread IDs.
+func (p *IDPresenter) RenderThreads(threads []*mail.Thread) string {
+	return idLines(threads, threadID)
+}
+
+// RenderLabel renders the label ID.
+func (p *IDPresenter) RenderLabel(label *mail.Label) string { return labelID(label) }
+
+// RenderLabels renders label IDs.
+func (p *IDPresenter) RenderLabels(labels []*mail.Label) string { return idLines(labels, labelID) }
+
+// RenderEvent renders the event ID.
+func (p *IDPresenter) RenderEvent(event *calendar.Event) string { return eventID(event) }
+
+// RenderEvents renders event IDs.
+func (p *IDPresenter) RenderEvents(events []*calendar.Event) string {
+	return idLines(events, eventID)
+}
+
+// RenderCalendar renders the calendar ID.
+func (p *IDPresenter) RenderCalendar(cal *calendar.Calendar) string { return calendarID(cal) }
+
+// RenderCalendars renders calendar IDs.
+func (p *IDPresenter) RenderCalendars(cals []*calendar.Calendar) string {
+	return idLines(cals, calendarID)
+}
+
+// RenderACLRule renders the rule ID.
+func (p *IDPresenter) RenderACLRule(rule *calendar.ACLRule) string { return aclRuleID(rule) }
+
+// RenderACLRules renders rule IDs.
+func (p *IDPresenter) RenderACLRules(rules []*calendar.ACLRule) string {
+	return idLines(rules, aclRuleID)
+}
+
+// RenderTaskList renders the task list ID.
+func (p *IDPresenter) RenderTaskList(taskList *domaintasks.TaskList) string {
+	return taskListID(taskList)
+}
+
+// RenderTaskLists renders task list IDs.
+func (p *IDPresenter) RenderTaskLists(taskLists []*domaintasks.TaskList) string {
+	return idLines(taskLists, taskListID)
+}
+
+// RenderTask renders the task ID.
+func (p *IDPresenter) RenderTask(task *domaintasks.Task) string { return taskID(task) }
+
+// RenderTasks renders task IDs.
+func (p *IDPresenter) RenderTasks(tasks []*domaintasks.Task) string { return idLines(tasks, taskID) }
+
+// RenderContact renders the contact's resource name.
+func (p *IDPresenter) RenderContact(contact *domaincontacts.Contact) string {
+	return contactID(contact)
+}
+
+// RenderContacts renders contact resource names.
+func (p *IDPresenter) RenderContacts(contacts []*domaincontacts.Contact) string {
+	return idLines(contacts, contactID)
+}
+
+// RenderContactGroup renders the group's resource name.
+func (p *IDPresenter) RenderContactGroup(group *domaincontacts.ContactGroup) string {
+	return contactGroupID(group)
+}
+
+// RenderContactGroups renders group resource names.
+func (p *IDPresenter) RenderContactGroups(groups []*domaincontacts.ContactGroup) string {
+	return idLines(groups, contactGroupID)
+}
+
+// RenderAccount renders the account alias.
+func (p *IDPresenter) RenderAccount(acct *account.Account) string { return accountID(acct) }
+
+// RenderAccounts renders account aliases.
+func (p *IDPresenter) RenderAccounts(accts []*account.Account) string {
+	return idLines(accts, accountID)
+}
+
+// RenderError renders the error message.
+func (p *IDPresenter) RenderError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// RenderSuccess renders nothing: quiet output has no confirmations.
+func (p *IDPresenter) RenderSuccess(msg string) string {
+	return ""
+}
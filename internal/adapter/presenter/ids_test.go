@@ -0,0 +1,45 @@
+package presenter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+func TestIDPresenter(t *testing.T) {
+	p := NewIDPresenter()
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"messages", p.RenderMessages([]*mail.Message{{ID: "m1"}, nil, {ID: "m2"}}), "m1\nm2"},
+		{"message", p.RenderMessage(&mail.Message{ID: "m1", Subject: "Hi"}), "m1"},
+		{"nil message", p.RenderMessage(nil), ""},
+		{"drafts", p.RenderDrafts([]*mail.Draft{{ID: "d1"}}), "d1"},
+		{"threads", p.RenderThreads([]*mail.Thread{{ID: "t1"}, {ID: "t2"}}), "t1\nt2"},
+		{"labels", p.RenderLabels([]*mail.Label{{ID: "Label_1", Name: "Work"}}), "Label_1"},
+		{"events", p.RenderEvents([]*calendar.Event{{ID: "e1"}}), "e1"},
+		{"calendars", p.RenderCalendars([]*calendar.Calendar{{ID: "primary"}}), "primary"},
+		{"acl rules", p.RenderACLRules([]*calendar.ACLRule{{ID: "user:a@example.com"}}), "user:a@example.com"},
+		{"task lists", p.RenderTaskLists([]*domaintasks.TaskList{{ID: "l1"}}), "l1"},
+		{"tasks", p.RenderTasks([]*domaintasks.Task{{ID: "k1"}, {ID: "k2"}}), "k1\nk2"},
+		{"contacts", p.RenderContacts([]*domaincontacts.Contact{{ResourceName: "people/c1"}}), "people/c1"},
+		{"contact groups", p.RenderContactGroups([]*domaincontacts.ContactGroup{{ResourceName: "contactGroups/g1"}}), "contactGroups/g1"},
+		{"accounts", p.RenderAccounts([]*account.Account{{Alias: "work", Email: "me@example.com"}}), "work"},
+		{"error", p.RenderError(errors.New("boom")), "boom"},
+		{"success", p.RenderSuccess("Task deleted"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
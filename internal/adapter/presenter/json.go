@@ -162,7 +162,10 @@ func (p *JSONPresenter) RenderTasks(tasks []*domaintasks.Task) string {
 
 // errorResponse is the JSON structure for error output.
 type errorResponse struct {
-	Error string `json:"error"`
+	Error  string `json:"error"`
+	Status int    `json:"status,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Hint   string `json:"hint,omitempty"`
 }
 
 // successResponse is the JSON structure for success output.
@@ -201,7 +204,13 @@ func (p *JSONPresenter) RenderError(err error) string {
 	if err == nil {
 		return p.marshalJSON(errorResponse{Error: ""})
 	}
-	return p.marshalJSON(errorResponse{Error: err.Error()})
+	resp := errorResponse{Error: err.Error()}
+	if detailed, ok := AsDetailedError(err); ok {
+		resp.Status = detailed.HTTPStatus()
+		resp.Reason = detailed.ErrorReason()
+		resp.Hint = detailed.RemediationHint()
+	}
+	return p.marshalJSON(resp)
 }
 
 // RenderSuccess renders a success message as JSON.
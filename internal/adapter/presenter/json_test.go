@@ -3,6 +3,7 @@ package presenter
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -457,6 +458,21 @@ func TestJSONPresenter_RenderError(t *testing.T) {
 		}
 	})
 
+	t.Run("includes status, reason and hint of API errors", func(t *testing.T) {
+		result := p.RenderError(fmt.Errorf("failed to list messages: %w", newDetailedTestError()))
+
+		var got errorResponse
+		if err := json.Unmarshal([]byte(result), &got); err != nil {
+			t.Fatalf("Result is not valid JSON: %v", err)
+		}
+		if got.Status != 403 || got.Reason != "userRateLimitExceeded" || got.Hint != "pause between commands" {
+			t.Errorf("unexpected error response %+v", got)
+		}
+		if strings.Contains(NewJSONPresenter().RenderError(errors.New("plain")), "hint") {
+			t.Error("plain errors should not include empty detail fields")
+		}
+	})
+
 	t.Run("renders nil error", func(t *testing.T) {
 		result := p.RenderError(nil)
 
@@ -0,0 +1,311 @@
+package presenter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+)
+
+// minColumnWidth is the narrowest a column is squeezed to when a table is
+// fitted to the terminal, unless its content is narrower.
+const minColumnWidth = 8
+
+// detailTextWidth is the width long values, such as snippets and
+// descriptions, are cut or wrapped to in single-item tables.
+const detailTextWidth = 60
+
+// TableLayout controls how table output fits its columns.
+type TableLayout struct {
+	// Width is the terminal width tables are fitted to. Zero leaves
+	// tables as wide as their column limits allow, e.g. when output is
+	// piped.
+	Width int
+	// Wrap breaks long cells over several lines instead of truncating
+	// them.
+	Wrap bool
+	// Truncate maps column keys, as returned by ColumnKey, to the maximum
+	// width of the column, replacing its default. Zero removes the limit.
+	Truncate map[string]int
+}
+
+var (
+	layoutMu     sync.RWMutex
+	currentTable TableLayout
+)
+
+// SetTableLayout sets the layout used by the table renderer.
+func SetTableLayout(l TableLayout) {
+	layoutMu.Lock()
+	defer layoutMu.Unlock()
+	currentTable = l
+}
+
+// CurrentTableLayout returns the layout used by the table renderer.
+func CurrentTableLayout() TableLayout {
+	layoutMu.RLock()
+	defer layoutMu.RUnlock()
+	return currentTable
+}
+
+// ColumnKey returns the key --truncate uses for a column header: lower
+// case with spaces as underscores, e.g. "access_role" for "Access Role".
+func ColumnKey(header string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), " ", "_")
+}
+
+// ParseTruncate parses column width limits of the form column=width, as
+// given to --truncate, e.g. "subject=40" or "from=0".
+func ParseTruncate(specs []string) (map[string]int, error) {
+	limits := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		column, value, ok := strings.Cut(spec, "=")
+		key := ColumnKey(column)
+		width, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || key == "" || err != nil || width < 0 {
+			return nil, fmt.Errorf("invalid --truncate %q: use column=width, e.g. subject=40 (0 for no limit)", spec)
+		}
+		if width > 0 && width < 4 {
+			return nil, fmt.Errorf("invalid --truncate %q: width must be at least 4", spec)
+		}
+		limits[key] = width
+	}
+	return limits, nil
+}
+
+// layoutTable collects the rows of a table and fits its columns to their
+// limits and the terminal before handing them to tablewriter.
+type layoutTable struct {
+	table   *tablewriter.Table
+	out     *strings.Builder
+	headers []string
+	limits  []int
+	rows    [][]string
+	layout  TableLayout
+	// highlight marks the columns whose cells show the highlight terms.
+	highlight []bool
+	// accessible writes the rows to out as blocks instead of a table.
+	accessible bool
+}
+
+// highlightColumns colours the highlight terms in the columns with the
+// given headers.
+func (t *layoutTable) highlightColumns(headers ...string) {
+	if t.highlight == nil {
+		t.highlight = make([]bool, len(t.headers))
+	}
+	for i, header := range t.headers {
+		if slices.Contains(headers, header) {
+			t.highlight[i] = true
+		}
+	}
+}
+
+// Append adds a row.
+func (t *layoutTable) Append(row []string) error {
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+// Render fits the rows and renders the table.
+func (t *layoutTable) Render() error {
+	if t.accessible {
+		t.renderBlocks()
+		return nil
+	}
+	widths := t.columnWidths()
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell
+			if i < len(widths) && widths[i] > 0 {
+				cells[i] = fitCell(cell, widths[i], t.layout.Wrap)
+			}
+			if i < len(t.highlight) && t.highlight[i] {
+				cells[i] = Highlight(cells[i])
+			}
+		}
+		if err := t.table.Append(cells); err != nil {
+			return err
+		}
+	}
+	return t.table.Render()
+}
+
+// renderBlocks writes the rows as blocks of "Key: value" lines. Cells are
+// written whole, as lines of text are not cut to fit.
+func (t *layoutTable) renderBlocks() {
+	rows := make([][]string, len(t.rows))
+	for i, row := range t.rows {
+		rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			if j < len(t.highlight) && t.highlight[j] {
+				cell = Highlight(cell)
+			}
+			rows[i][j] = cell
+		}
+	}
+	writeBlocks(t.out, t.headers, rows)
+}
+
+// columnWidths returns the width of each column: the widest cell, at most
+// the column's limit, narrowed to fit the terminal when its width is
+// known. Zero means the column is left as it is.
+func (t *layoutTable) columnWidths() []int {
+	n := len(t.headers)
+	widths := make([]int, n)
+	floors := make([]int, n)
+	for i, header := range t.headers {
+		limit := 0
+		if i < len(t.limits) {
+			limit = t.limits[i]
+		}
+		limit = t.layout.columnLimit(header, limit)
+
+		natural := cellWidth(header)
+		for _, row := range t.rows {
+			if i < len(row) {
+				natural = max(natural, cellWidth(row[i]))
+			}
+		}
+		if limit > 0 {
+			natural = min(natural, limit)
+		}
+		widths[i] = natural
+		floors[i] = columnFloor(header, natural)
+	}
+
+	fitWidths(widths, floors, t.layout.Width)
+	return widths
+}
+
+// columnLimit returns the width limit of the column with header: its
+// --truncate override, or else limit.
+func (l TableLayout) columnLimit(header string, limit int) int {
+	if override, ok := l.Truncate[ColumnKey(header)]; ok {
+		return override
+	}
+	return limit
+}
+
+// columnFloor returns the narrowest a column of width is squeezed to.
+func columnFloor(header string, width int) int {
+	return min(width, max(cellWidth(header), minColumnWidth))
+}
+
+// fitWidths narrows the widest columns, down to their floors, until a table
+// with the given column widths fits in width. Zero width leaves them as
+// they are.
+func fitWidths(widths, floors []int, width int) {
+	if width <= 0 {
+		return
+	}
+	// Each column takes its width plus a space on both sides and a border;
+	// the table adds one more border.
+	avail := width - 3*len(widths) - 1
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	for total > avail {
+		widest := -1
+		for i, w := range widths {
+			if w > floors[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// cellWidth returns the display width of the widest line of a cell.
+func cellWidth(s string) int {
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		width = max(width, twwidth.Width(line))
+	}
+	return width
+}
+
+// fitCell cuts or wraps each line of a cell to width.
+func fitCell(s string, width int, wrap bool) string {
+	if cellWidth(s) <= width {
+		return s
+	}
+	if wrap {
+		return wrapText(s, width)
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens s to maxLen display columns, appending "..." if
+// truncated. Wide characters such as emoji count as two columns.
+func truncate(s string, maxLen int) string {
+	if twwidth.Width(s) <= maxLen {
+		return s
+	}
+	if maxLen <= 3 {
+		return twwidth.Truncate(s, maxLen)
+	}
+	return twwidth.Truncate(s, maxLen-3) + "..."
+}
+
+// wrapText breaks each line of s at spaces into lines of at most width
+// display columns. Words wider than width are split.
+func wrapText(s string, width int) string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		current, currentWidth := "", 0
+		for _, word := range strings.Fields(line) {
+			for twwidth.Width(word) > width {
+				if currentWidth > 0 {
+					out = append(out, current)
+					current, currentWidth = "", 0
+				}
+				head := twwidth.Truncate(word, width)
+				if head == "" || !strings.HasPrefix(word, head) {
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				out = append(out, head)
+				word = word[len(head):]
+			}
+			wordWidth := twwidth.Width(word)
+			switch {
+			case word == "":
+			case currentWidth == 0:
+				current, currentWidth = word, wordWidth
+			case currentWidth+1+wordWidth <= width:
+				current += " " + word
+				currentWidth += 1 + wordWidth
+			default:
+				out = append(out, current)
+				current, currentWidth = word, wordWidth
+			}
+		}
+		out = append(out, current)
+	}
+	return strings.Join(out, "\n")
+}
+
+// longText fits a long value of a single-item table: truncated to
+// detailTextWidth, or wrapped to it with --wrap.
+func longText(s string) string {
+	if CurrentTableLayout().Wrap {
+		return wrapText(s, detailTextWidth)
+	}
+	return truncate(s, detailTextWidth)
+}
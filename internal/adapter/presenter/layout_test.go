@@ -0,0 +1,128 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// withTableLayout sets the table layout for the rest of the test.
+func withTableLayout(t *testing.T, l TableLayout) {
+	t.Helper()
+	prev := CurrentTableLayout()
+	SetTableLayout(l)
+	t.Cleanup(func() { SetTableLayout(prev) })
+}
+
+// maxLineWidth returns the display width of the widest line of s.
+func maxLineWidth(s string) int {
+	width := 0
+	for _, line := range strings.Split(strings.TrimRight(s, "\n"), "\n") {
+		width = max(width, twwidth.Width(line))
+	}
+	return width
+}
+
+func TestParseTruncate(t *testing.T) {
+	got, err := ParseTruncate([]string{"subject=60", " From =0", "Access Role=12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"subject": 60, "from": 0, "access_role": 12}
+	for key, width := range want {
+		if got[key] != width {
+			t.Errorf("ParseTruncate()[%q] = %d, want %d", key, got[key], width)
+		}
+	}
+
+	for _, spec := range []string{"subject", "=10", "subject=x", "subject=-1", "subject=2"} {
+		if _, err := ParseTruncate([]string{spec}); err == nil {
+			t.Errorf("ParseTruncate(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "short text", 20, "short text"},
+		{"breaks at spaces", "the quick brown fox", 10, "the quick\nbrown fox"},
+		{"splits long words", "abcdefghij", 4, "abcd\nefgh\nij"},
+		{"keeps line breaks", "one\ntwo three", 5, "one\ntwo\nthree"},
+		{"wide characters", "🎉🎉🎉 fun", 4, "🎉🎉\n🎉\nfun"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.input, tt.width); got != tt.want {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate_WideCharacters(t *testing.T) {
+	got := truncate("🎉🎉🎉🎉🎉 launch party", 10)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncate() split a character: %q", got)
+	}
+	if w := twwidth.Width(got); w > 10 {
+		t.Errorf("truncate() = %q, %d columns wide, want at most 10", got, w)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("truncate() = %q, want a ... suffix", got)
+	}
+}
+
+func TestTablePresenter_Layout(t *testing.T) {
+	p := NewTablePresenter()
+	msgs := []*mail.Message{
+		{ID: "msg1", From: mail.Address{Email: "someone-with-a-long-name@example.com"}, Subject: "🎉🎉 Quarterly results are in and they look great 🎉🎉", Date: time.Now()},
+		{ID: "msg2", From: mail.Address{Email: "b@example.com"}, Subject: "Plain subject", Date: time.Now()},
+	}
+
+	t.Run("emoji rows stay aligned", func(t *testing.T) {
+		withTableLayout(t, TableLayout{})
+		out := p.RenderMessages(msgs)
+		lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+		for _, line := range lines[1:] {
+			if twwidth.Width(line) != twwidth.Width(lines[0]) {
+				t.Fatalf("misaligned table:\n%s", out)
+			}
+		}
+	})
+
+	t.Run("fits the terminal width", func(t *testing.T) {
+		withTableLayout(t, TableLayout{Width: 70})
+		out := p.RenderMessages(msgs)
+		if w := maxLineWidth(out); w > 70 {
+			t.Errorf("table is %d columns wide, want at most 70:\n%s", w, out)
+		}
+	})
+
+	t.Run("truncate overrides the default limit", func(t *testing.T) {
+		withTableLayout(t, TableLayout{Truncate: map[string]int{"subject": 0}})
+		out := p.RenderMessages(msgs)
+		if !strings.Contains(out, "they look great 🎉🎉") {
+			t.Errorf("expected the full subject:\n%s", out)
+		}
+	})
+
+	t.Run("wrap keeps the whole subject", func(t *testing.T) {
+		withTableLayout(t, TableLayout{Width: 70, Wrap: true})
+		out := p.RenderMessages(msgs)
+		if w := maxLineWidth(out); w > 70 {
+			t.Errorf("table is %d columns wide, want at most 70:\n%s", w, out)
+		}
+		if strings.Contains(out, "...") || !strings.Contains(out, "Quarterly") {
+			t.Errorf("expected wrapped cells:\n%s", out)
+		}
+	})
+}
@@ -0,0 +1,579 @@
+package presenter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	domaincontacts "github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// Filter operators.
+const (
+	FilterContains    = "~"
+	FilterNotContains = "!~"
+	FilterEquals      = "="
+	FilterNotEquals   = "!="
+)
+
+// ListOptions controls client-side sorting and filtering of list output.
+type ListOptions struct {
+	// Sort is the field to order items by; empty keeps the API order.
+	Sort string
+	// Desc reverses the sort order.
+	Desc bool
+	// Filters must all match for an item to be shown.
+	Filters []Filter
+}
+
+// Filter compares one field of each list item with a value. Comparisons
+// are case-insensitive.
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// ParseFilter parses a filter expression such as "from~github",
+// "status=completed", "subject!~newsletter" or "labels!=SPAM". A yes/no
+// field on its own, such as "important", means important=true, and
+// "!important" means important=false.
+func ParseFilter(expr string) (Filter, error) {
+	name, negated := strings.CutPrefix(strings.TrimSpace(expr), "!")
+	if name = strings.ToLower(name); flagFields[name] {
+		return Filter{Field: name, Op: FilterEquals, Value: strconv.FormatBool(!negated)}, nil
+	}
+
+	// Two-character operators first so "!=" is not read as "="
+	for _, op := range []string{FilterNotContains, FilterNotEquals, FilterContains, FilterEquals} {
+		idx := strings.Index(expr, op)
+		if idx < 0 {
+			continue
+		}
+		field := strings.ToLower(strings.TrimSpace(expr[:idx]))
+		if field == "" {
+			return Filter{}, fmt.Errorf("invalid filter %q: missing field name", expr)
+		}
+		if strings.ContainsAny(field, "!~=") {
+			continue
+		}
+		return Filter{Field: field, Op: op, Value: strings.TrimSpace(expr[idx+len(op):])}, nil
+	}
+	return Filter{}, fmt.Errorf("invalid filter %q: expected field~text, field!~text, field=value, field!=value or a yes/no field such as important or !read", expr)
+}
+
+// flagFields are the yes/no fields that can be filtered by name alone.
+var flagFields = map[string]bool{
+	"read":      true,
+	"starred":   true,
+	"important": true,
+	"primary":   true,
+	"default":   true,
+}
+
+// IsZero reports whether the options leave lists unchanged.
+func (o ListOptions) IsZero() bool {
+	return o.Sort == "" && len(o.Filters) == 0
+}
+
+// ApplyToThreads filters and sorts threads as list output would. It lets
+// callers that number threads see them in displayed order; rendering the
+// result again with the same options leaves it unchanged.
+func (o ListOptions) ApplyToThreads(threads []*mail.Thread) ([]*mail.Thread, error) {
+	return applyListOptions(threads, threadFields, "threads", o)
+}
+
+// ApplyToMessages filters and sorts messages as list output would, for
+// callers that render messages as they arrive.
+func (o ListOptions) ApplyToMessages(msgs []*mail.Message) ([]*mail.Message, error) {
+	return applyListOptions(msgs, messageFields, "messages", o)
+}
+
+// Validate checks that every field is known to at least one list type.
+// Fields that a particular list does not have are reported when rendering.
+func (o ListOptions) Validate() error {
+	known := make(map[string]bool)
+	for _, names := range ListFields() {
+		for _, name := range names {
+			known[name] = true
+		}
+	}
+
+	check := func(field, use string) error {
+		if !known[field] {
+			return fmt.Errorf("unknown %s field %q", use, field)
+		}
+		return nil
+	}
+	if o.Sort != "" {
+		if err := check(o.Sort, "sort"); err != nil {
+			return err
+		}
+	}
+	for _, f := range o.Filters {
+		if err := check(f.Field, "filter"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListFields returns the sortable and filterable field names of each list
+// type, keyed by the type's plural name.
+func ListFields() map[string][]string {
+	return map[string][]string{
+		"messages":       messageFields.names(),
+		"drafts":         draftFields.names(),
+		"threads":        threadFields.names(),
+		"labels":         labelFields.names(),
+		"events":         eventFields.names(),
+		"calendars":      calendarFields.names(),
+		"sharing rules":  aclRuleFields.names(),
+		"accounts":       accountFields.names(),
+		"task lists":     taskListFields.names(),
+		"tasks":          taskFields.names(),
+		"contacts":       contactFields.names(),
+		"contact groups": contactGroupFields.names(),
+	}
+}
+
+// fieldSet maps field names to accessors. Accessors return a string,
+// []string, time.Time, int or bool.
+type fieldSet[T any] map[string]func(T) any
+
+func (fs fieldSet[T]) names() []string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var messageFields = fieldSet[*mail.Message]{
+	"id":         func(m *mail.Message) any { return m.ID },
+	"date":       func(m *mail.Message) any { return m.Date },
+	"from":       func(m *mail.Message) any { return m.From.String() },
+	"from_name":  func(m *mail.Message) any { return m.From.Name },
+	"from_email": func(m *mail.Message) any { return m.From.Email },
+	"to":         func(m *mail.Message) any { return mail.AddressStrings(m.To) },
+	"cc":         func(m *mail.Message) any { return mail.AddressStrings(m.Cc) },
+	"subject":    func(m *mail.Message) any { return m.Subject },
+	"snippet":    func(m *mail.Message) any { return m.Snippet },
+	"labels":     func(m *mail.Message) any { return m.Labels },
+	"read":       func(m *mail.Message) any { return m.IsRead },
+	"starred":    func(m *mail.Message) any { return m.IsStarred },
+	"important":  func(m *mail.Message) any { return m.IsImportant },
+}
+
+var draftFields = fieldSet[*mail.Draft]{
+	"id":      func(d *mail.Draft) any { return d.ID },
+	"date":    func(d *mail.Draft) any { return d.Updated },
+	"to":      func(d *mail.Draft) any { return mail.AddressStrings(draftMessage(d).To) },
+	"subject": func(d *mail.Draft) any { return draftMessage(d).Subject },
+}
+
+var threadFields = fieldSet[*mail.Thread]{
+	"id":         func(t *mail.Thread) any { return t.ID },
+	"date":       func(t *mail.Thread) any { return threadMessage(t.LatestMessage()).Date },
+	"from":       func(t *mail.Thread) any { return threadMessage(t.FirstMessage()).From.String() },
+	"from_name":  func(t *mail.Thread) any { return threadMessage(t.FirstMessage()).From.Name },
+	"from_email": func(t *mail.Thread) any { return threadMessage(t.FirstMessage()).From.Email },
+	"subject":    func(t *mail.Thread) any { return threadMessage(t.FirstMessage()).Subject },
+	"snippet":    func(t *mail.Thread) any { return t.Snippet },
+	"labels":     func(t *mail.Thread) any { return t.Labels },
+	"messages":   func(t *mail.Thread) any { return t.MessageCount() },
+	"unread":     func(t *mail.Thread) any { return t.UnreadCount() },
+}
+
+var labelFields = fieldSet[*mail.Label]{
+	"id":   func(l *mail.Label) any { return l.ID },
+	"name": func(l *mail.Label) any { return l.Name },
+	"type": func(l *mail.Label) any { return l.Type },
+}
+
+var eventFields = fieldSet[*calendar.Event]{
+	"id":        func(e *calendar.Event) any { return e.ID },
+	"date":      func(e *calendar.Event) any { return e.Start },
+	"start":     func(e *calendar.Event) any { return e.Start },
+	"end":       func(e *calendar.Event) any { return e.End },
+	"title":     func(e *calendar.Event) any { return e.Title },
+	"subject":   func(e *calendar.Event) any { return e.Title },
+	"location":  func(e *calendar.Event) any { return e.Location },
+	"status":    func(e *calendar.Event) any { return e.Status },
+	"from":      func(e *calendar.Event) any { return eventOrganizer(e) },
+	"organizer": func(e *calendar.Event) any { return eventOrganizer(e) },
+}
+
+var calendarFields = fieldSet[*calendar.Calendar]{
+	"id":       func(c *calendar.Calendar) any { return c.ID },
+	"title":    func(c *calendar.Calendar) any { return c.Title },
+	"name":     func(c *calendar.Calendar) any { return c.Title },
+	"role":     func(c *calendar.Calendar) any { return c.AccessRole },
+	"timezone": func(c *calendar.Calendar) any { return c.TimeZone },
+	"primary":  func(c *calendar.Calendar) any { return c.Primary },
+}
+
+var aclRuleFields = fieldSet[*calendar.ACLRule]{
+	"id":    func(r *calendar.ACLRule) any { return r.ID },
+	"role":  func(r *calendar.ACLRule) any { return r.Role },
+	"type":  func(r *calendar.ACLRule) any { return aclScope(r).Type },
+	"email": func(r *calendar.ACLRule) any { return aclScope(r).Value },
+}
+
+var accountFields = fieldSet[*account.Account]{
+	"alias":   func(a *account.Account) any { return a.Alias },
+	"name":    func(a *account.Account) any { return a.Alias },
+	"email":   func(a *account.Account) any { return a.Email },
+	"date":    func(a *account.Account) any { return a.LastUsed },
+	"default": func(a *account.Account) any { return a.IsDefault },
+}
+
+var taskListFields = fieldSet[*domaintasks.TaskList]{
+	"id":    func(l *domaintasks.TaskList) any { return l.ID },
+	"title": func(l *domaintasks.TaskList) any { return l.Title },
+	"name":  func(l *domaintasks.TaskList) any { return l.Title },
+	"date":  func(l *domaintasks.TaskList) any { return l.Updated },
+}
+
+var taskFields = fieldSet[*domaintasks.Task]{
+	"id":      func(t *domaintasks.Task) any { return t.ID },
+	"title":   func(t *domaintasks.Task) any { return t.Title },
+	"subject": func(t *domaintasks.Task) any { return t.Title },
+	"notes":   func(t *domaintasks.Task) any { return t.Notes },
+	"status":  func(t *domaintasks.Task) any { return t.Status },
+	"due":     func(t *domaintasks.Task) any { return timeValue(t.Due) },
+	"date":    func(t *domaintasks.Task) any { return timeValue(t.Due) },
+	"updated": func(t *domaintasks.Task) any { return t.Updated },
+}
+
+var contactFields = fieldSet[*domaincontacts.Contact]{
+	"id":   func(c *domaincontacts.Contact) any { return c.ResourceName },
+	"name": func(c *domaincontacts.Contact) any { return c.GetDisplayName() },
+	"email": func(c *domaincontacts.Contact) any {
+		values := make([]string, 0, len(c.EmailAddresses))
+		for _, e := range c.EmailAddresses {
+			values = append(values, e.Value)
+		}
+		return values
+	},
+	"phone": func(c *domaincontacts.Contact) any {
+		values := make([]string, 0, len(c.PhoneNumbers))
+		for _, p := range c.PhoneNumbers {
+			values = append(values, p.Value)
+		}
+		return values
+	},
+	"organization": func(c *domaincontacts.Contact) any {
+		values := make([]string, 0, len(c.Organizations))
+		for _, o := range c.Organizations {
+			values = append(values, o.Name)
+		}
+		return values
+	},
+}
+
+var contactGroupFields = fieldSet[*domaincontacts.ContactGroup]{
+	"id":      func(g *domaincontacts.ContactGroup) any { return g.ResourceName },
+	"name":    func(g *domaincontacts.ContactGroup) any { return g.Name },
+	"type":    func(g *domaincontacts.ContactGroup) any { return g.GroupType },
+	"members": func(g *domaincontacts.ContactGroup) any { return g.MemberCount },
+}
+
+// listRenderer applies ListOptions to every list before delegating to the
+// wrapped Renderer.
+type listRenderer struct {
+	Renderer
+	opts ListOptions
+}
+
+// WithListOptions wraps a Renderer so that list output is filtered and
+// sorted client-side. Single items are rendered unchanged.
+func WithListOptions(r Renderer, opts ListOptions) Renderer {
+	if opts.IsZero() {
+		return r
+	}
+	return &listRenderer{Renderer: r, opts: opts}
+}
+
+// RenderMessages renders messages after filtering and sorting them.
+func (r *listRenderer) RenderMessages(msgs []*mail.Message) string {
+	msgs, err := applyListOptions(msgs, messageFields, "messages", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderMessages(msgs)
+}
+
+// RenderDrafts renders drafts after filtering and sorting them.
+func (r *listRenderer) RenderDrafts(drafts []*mail.Draft) string {
+	drafts, err := applyListOptions(drafts, draftFields, "drafts", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderDrafts(drafts)
+}
+
+// RenderThreads renders threads after filtering and sorting them.
+func (r *listRenderer) RenderThreads(threads []*mail.Thread) string {
+	threads, err := applyListOptions(threads, threadFields, "threads", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderThreads(threads)
+}
+
+// RenderLabels renders labels after filtering and sorting them.
+func (r *listRenderer) RenderLabels(labels []*mail.Label) string {
+	labels, err := applyListOptions(labels, labelFields, "labels", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderLabels(labels)
+}
+
+// RenderEvents renders events after filtering and sorting them.
+func (r *listRenderer) RenderEvents(events []*calendar.Event) string {
+	events, err := applyListOptions(events, eventFields, "events", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderEvents(events)
+}
+
+// RenderCalendars renders calendars after filtering and sorting them.
+func (r *listRenderer) RenderCalendars(cals []*calendar.Calendar) string {
+	cals, err := applyListOptions(cals, calendarFields, "calendars", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderCalendars(cals)
+}
+
+// RenderACLRules renders sharing rules after filtering and sorting them.
+func (r *listRenderer) RenderACLRules(rules []*calendar.ACLRule) string {
+	rules, err := applyListOptions(rules, aclRuleFields, "sharing rules", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderACLRules(rules)
+}
+
+// RenderAccounts renders accounts after filtering and sorting them.
+func (r *listRenderer) RenderAccounts(accts []*account.Account) string {
+	accts, err := applyListOptions(accts, accountFields, "accounts", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderAccounts(accts)
+}
+
+// RenderTaskLists renders task lists after filtering and sorting them.
+func (r *listRenderer) RenderTaskLists(taskLists []*domaintasks.TaskList) string {
+	taskLists, err := applyListOptions(taskLists, taskListFields, "task lists", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderTaskLists(taskLists)
+}
+
+// RenderTasks renders tasks after filtering and sorting them.
+func (r *listRenderer) RenderTasks(tasks []*domaintasks.Task) string {
+	tasks, err := applyListOptions(tasks, taskFields, "tasks", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderTasks(tasks)
+}
+
+// RenderContacts renders contacts after filtering and sorting them.
+func (r *listRenderer) RenderContacts(contacts []*domaincontacts.Contact) string {
+	contacts, err := applyListOptions(contacts, contactFields, "contacts", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderContacts(contacts)
+}
+
+// RenderContactGroups renders contact groups after filtering and sorting them.
+func (r *listRenderer) RenderContactGroups(groups []*domaincontacts.ContactGroup) string {
+	groups, err := applyListOptions(groups, contactGroupFields, "contact groups", r.opts)
+	if err != nil {
+		return r.RenderError(err)
+	}
+	return r.Renderer.RenderContactGroups(groups)
+}
+
+func draftMessage(d *mail.Draft) *mail.Message {
+	if d.Message == nil {
+		return &mail.Message{}
+	}
+	return d.Message
+}
+
+func threadMessage(m *mail.Message) *mail.Message {
+	if m == nil {
+		return &mail.Message{}
+	}
+	return m
+}
+
+func eventOrganizer(e *calendar.Event) string {
+	if e.Organizer == nil {
+		return ""
+	}
+	return e.Organizer.Email
+}
+
+func aclScope(r *calendar.ACLRule) *calendar.ACLScope {
+	if r.Scope == nil {
+		return &calendar.ACLScope{}
+	}
+	return r.Scope
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// applyListOptions filters and sorts items. kind names the list type in
+// error messages.
+func applyListOptions[T any](items []T, fields fieldSet[T], kind string, opts ListOptions) ([]T, error) {
+	if opts.IsZero() {
+		return items, nil
+	}
+
+	for _, f := range opts.Filters {
+		if _, ok := fields[f.Field]; !ok {
+			return nil, fmt.Errorf("cannot filter %s by %q (fields: %s)", kind, f.Field, strings.Join(fields.names(), ", "))
+		}
+	}
+	var key func(T) any
+	if opts.Sort != "" {
+		var ok bool
+		if key, ok = fields[opts.Sort]; !ok {
+			return nil, fmt.Errorf("cannot sort %s by %q (fields: %s)", kind, opts.Sort, strings.Join(fields.names(), ", "))
+		}
+	}
+
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		if reflect.ValueOf(item).IsNil() {
+			continue
+		}
+		if matchesFilters(item, fields, opts.Filters) {
+			result = append(result, item)
+		}
+	}
+
+	if key != nil {
+		sort.SliceStable(result, func(i, j int) bool {
+			c := compareValues(key(result[i]), key(result[j]))
+			if opts.Desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	return result, nil
+}
+
+// matchesFilters reports whether an item satisfies every filter.
+func matchesFilters[T any](item T, fields fieldSet[T], filters []Filter) bool {
+	for _, f := range filters {
+		text := strings.ToLower(valueText(fields[f.Field](item)))
+		want := strings.ToLower(f.Value)
+
+		var ok bool
+		switch f.Op {
+		case FilterContains:
+			ok = strings.Contains(text, want)
+		case FilterNotContains:
+			ok = !strings.Contains(text, want)
+		case FilterEquals:
+			ok = valueEquals(fields[f.Field](item), want)
+		case FilterNotEquals:
+			ok = !valueEquals(fields[f.Field](item), want)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// valueEquals compares a field with a lower-cased value. List fields match
+// when any element is equal.
+func valueEquals(v any, want string) bool {
+	if values, ok := v.([]string); ok {
+		for _, s := range values {
+			if strings.ToLower(s) == want {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.ToLower(valueText(v)) == want
+}
+
+// valueText returns the text form of a field value used by filters.
+func valueText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ", ")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04")
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// compareValues orders two field values. Times sort chronologically with
+// zero times last, numbers numerically, and text case-insensitively.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case time.Time:
+		b := b.(time.Time)
+		switch {
+		case a.Equal(b):
+			return 0
+		case a.IsZero():
+			return 1
+		case b.IsZero():
+			return -1
+		case a.Before(b):
+			return -1
+		}
+		return 1
+	case int:
+		return a - b.(int)
+	case bool:
+		// false before true
+		switch b := b.(bool); {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(strings.ToLower(valueText(a)), strings.ToLower(valueText(b)))
+}
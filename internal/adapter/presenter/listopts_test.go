@@ -0,0 +1,194 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Filter
+		wantErr bool
+	}{
+		{"from~github", Filter{Field: "from", Op: FilterContains, Value: "github"}, false},
+		{"Subject!~ newsletter", Filter{Field: "subject", Op: FilterNotContains, Value: "newsletter"}, false},
+		{"status=completed", Filter{Field: "status", Op: FilterEquals, Value: "completed"}, false},
+		{"labels!=SPAM", Filter{Field: "labels", Op: FilterNotEquals, Value: "SPAM"}, false},
+		{"subject=a~b", Filter{Field: "subject", Op: FilterEquals, Value: "a~b"}, false},
+		{"from", Filter{}, true},
+		{"important", Filter{Field: "important", Op: FilterEquals, Value: "true"}, false},
+		{" !Read", Filter{Field: "read", Op: FilterEquals, Value: "false"}, false},
+		{"!from", Filter{}, true},
+		{"~github", Filter{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseFilter(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFilter(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListOptions_Validate(t *testing.T) {
+	valid := ListOptions{Sort: "due", Filters: []Filter{{Field: "from", Op: FilterContains, Value: "x"}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := (ListOptions{Sort: "colour"}).Validate(); err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("expected unknown sort field error, got %v", err)
+	}
+	if err := (ListOptions{Filters: []Filter{{Field: "nope", Op: FilterEquals}}}).Validate(); err == nil {
+		t.Error("expected unknown filter field error")
+	}
+}
+
+func testMessages() []*mail.Message {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	return []*mail.Message{
+		{ID: "m1", From: mail.Address{Email: "alerts@github.com"}, Subject: "beta", Date: day(2), Labels: []string{"INBOX"}},
+		{ID: "m2", From: mail.Address{Name: "Alice", Email: "alice@example.com"}, Subject: "Alpha", Date: day(3), Labels: []string{"INBOX", "STARRED", "IMPORTANT"}, IsImportant: true},
+		nil,
+		{ID: "m3", From: mail.Address{Email: "noreply@GitHub.com"}, Subject: "gamma", Date: day(1), Labels: []string{"SPAM"}},
+	}
+}
+
+func messageIDs(msgs []*mail.Message) string {
+	ids := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestApplyListOptions_Messages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ListOptions
+		want string
+	}{
+		{"sort by date", ListOptions{Sort: "date"}, "m3,m1,m2"},
+		{"sort by date descending", ListOptions{Sort: "date", Desc: true}, "m2,m1,m3"},
+		{"sort by subject ignores case", ListOptions{Sort: "subject"}, "m2,m1,m3"},
+		{"contains ignores case", ListOptions{Filters: []Filter{{Field: "from", Op: FilterContains, Value: "github"}}}, "m1,m3"},
+		{"not contains", ListOptions{Filters: []Filter{{Field: "from", Op: FilterNotContains, Value: "github"}}}, "m2"},
+		{"display name", ListOptions{Filters: []Filter{{Field: "from_name", Op: FilterEquals, Value: "alice"}}}, "m2"},
+		{"email without name", ListOptions{Filters: []Filter{{Field: "from_email", Op: FilterContains, Value: "alice@"}}}, "m2"},
+		{"equals matches any list element", ListOptions{Filters: []Filter{{Field: "labels", Op: FilterEquals, Value: "starred"}}}, "m2"},
+		{"not equals on list", ListOptions{Filters: []Filter{{Field: "labels", Op: FilterNotEquals, Value: "spam"}}}, "m1,m2"},
+		{"important", ListOptions{Filters: []Filter{{Field: "important", Op: FilterEquals, Value: "true"}}}, "m2"},
+		{"not important", ListOptions{Filters: []Filter{{Field: "important", Op: FilterEquals, Value: "false"}}}, "m1,m3"},
+		{
+			"filters combine with sort",
+			ListOptions{Sort: "date", Desc: true, Filters: []Filter{{Field: "from", Op: FilterContains, Value: "github"}}},
+			"m1,m3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyListOptions(testMessages(), messageFields, "messages", tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ids := messageIDs(got); ids != tt.want {
+				t.Errorf("got %s, want %s", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyListOptions_UnknownField(t *testing.T) {
+	_, err := applyListOptions(testMessages(), messageFields, "messages", ListOptions{Sort: "due"})
+	if err == nil || !strings.Contains(err.Error(), "cannot sort messages") {
+		t.Errorf("expected sort field error, got %v", err)
+	}
+
+	_, err = applyListOptions(testMessages(), messageFields, "messages", ListOptions{Filters: []Filter{{Field: "due", Op: FilterEquals}}})
+	if err == nil || !strings.Contains(err.Error(), "cannot filter messages") {
+		t.Errorf("expected filter field error, got %v", err)
+	}
+}
+
+func TestApplyListOptions_TasksZeroDueLast(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	later := due.Add(48 * time.Hour)
+	tasks := []*domaintasks.Task{
+		{ID: "none", Title: "no due date"},
+		{ID: "later", Due: &later},
+		{ID: "soon", Due: &due},
+	}
+
+	got, err := applyListOptions(tasks, taskFields, "tasks", ListOptions{Sort: "due"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[0].ID != "soon" || got[1].ID != "later" || got[2].ID != "none" {
+		t.Errorf("unexpected order: %s, %s, %s", got[0].ID, got[1].ID, got[2].ID)
+	}
+}
+
+func TestListOptions_ApplyToThreads(t *testing.T) {
+	unread := mail.NewMessage("m1", "t1", "a@example.com", "Hi", "")
+	read := mail.NewMessage("m2", "t2", "b@example.com", "Hello", "")
+	read.IsRead = true
+	threads := mail.GroupByThread([]*mail.Message{read, unread})
+
+	got, err := ListOptions{Filters: []Filter{{Field: "unread", Op: FilterNotEquals, Value: "0"}}}.ApplyToThreads(threads)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "t1" {
+		t.Errorf("expected only the unread thread, got %d threads", len(got))
+	}
+
+	got, err = ListOptions{}.ApplyToThreads(threads)
+	if err != nil || len(got) != 2 {
+		t.Errorf("zero options should keep all threads, got %d (%v)", len(got), err)
+	}
+
+	if _, err := (ListOptions{Sort: "due"}).ApplyToThreads(threads); err == nil {
+		t.Error("expected error for unknown thread field")
+	}
+}
+
+func TestWithListOptions(t *testing.T) {
+	base := NewPlainPresenter()
+	if WithListOptions(base, ListOptions{}) != Renderer(base) {
+		t.Error("expected zero options to return the renderer unchanged")
+	}
+
+	r := WithListOptions(base, ListOptions{Sort: "start", Desc: true})
+	events := []*calendar.Event{
+		{ID: "e1", Title: "First", Start: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{ID: "e2", Title: "Second", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+	}
+	out := r.RenderEvents(events)
+	if strings.Index(out, "Second") > strings.Index(out, "First") {
+		t.Errorf("expected descending order\n%s", out)
+	}
+	if events[0].ID != "e1" {
+		t.Error("input slice should not be reordered")
+	}
+
+	out = r.RenderLabels([]*mail.Label{{ID: "l1", Name: "x"}})
+	if !strings.Contains(out, `cannot sort labels by "start"`) {
+		t.Errorf("expected unsupported field error, got %q", out)
+	}
+
+	if got := r.RenderEvent(events[0]); got != base.RenderEvent(events[0]) {
+		t.Error("single items should render unchanged")
+	}
+}
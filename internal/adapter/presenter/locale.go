@@ -0,0 +1,199 @@
+package presenter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Size unit systems accepted by NewLocale.
+const (
+	SizeUnitsIEC = "iec" // powers of 1024: KiB, MiB, GiB
+	SizeUnitsSI  = "si"  // powers of 1000: kB, MB, GB
+)
+
+// Date format presets accepted by NewLocale in addition to Go layouts.
+var datePresets = map[string]string{
+	"iso": "2006-01-02",
+	"us":  "01/02/2006",
+	"eu":  "02/01/2006",
+	"de":  "02.01.2006",
+}
+
+// Time format presets accepted by NewLocale in addition to Go layouts.
+// Each maps to the layout without and with seconds.
+var timePresets = map[string][2]string{
+	"24h": {"15:04", "15:04:05"},
+	"12h": {"3:04 PM", "3:04:05 PM"},
+}
+
+// Locale controls how table and plain output render dates, times and byte
+// sizes. JSON output is unaffected.
+type Locale struct {
+	dateLayout    string
+	timeLayout    string
+	secondsLayout string
+	sizeUnits     string
+}
+
+// DefaultLocale returns ISO dates, 24-hour times and IEC sizes.
+func DefaultLocale() Locale {
+	l, _ := NewLocale("", "", "")
+	return l
+}
+
+// NewLocale builds a Locale from configuration values. dateFormat is a
+// preset (iso, us, eu, de) or a Go layout such as "Jan 2, 2006"; timeFormat
+// is 24h, 12h or a Go layout; sizeUnits is iec or si. Empty values select
+// the defaults.
+func NewLocale(dateFormat, timeFormat, sizeUnits string) (Locale, error) {
+	var l Locale
+
+	switch {
+	case dateFormat == "":
+		l.dateLayout = datePresets["iso"]
+	case datePresets[strings.ToLower(dateFormat)] != "":
+		l.dateLayout = datePresets[strings.ToLower(dateFormat)]
+	case isLayout(dateFormat):
+		l.dateLayout = dateFormat
+	default:
+		return Locale{}, fmt.Errorf("invalid date format %q: use iso, us, eu, de or a Go layout such as \"Jan 2, 2006\"", dateFormat)
+	}
+
+	preset, ok := timePresets[strings.ToLower(timeFormat)]
+	switch {
+	case timeFormat == "":
+		l.timeLayout, l.secondsLayout = timePresets["24h"][0], timePresets["24h"][1]
+	case ok:
+		l.timeLayout, l.secondsLayout = preset[0], preset[1]
+	case isLayout(timeFormat):
+		l.timeLayout, l.secondsLayout = timeFormat, timeFormat
+	default:
+		return Locale{}, fmt.Errorf("invalid time format %q: use 24h, 12h or a Go layout such as \"15:04\"", timeFormat)
+	}
+
+	switch strings.ToLower(sizeUnits) {
+	case "", SizeUnitsIEC:
+		l.sizeUnits = SizeUnitsIEC
+	case SizeUnitsSI:
+		l.sizeUnits = SizeUnitsSI
+	default:
+		return Locale{}, fmt.Errorf("invalid size units %q: must be iec or si", sizeUnits)
+	}
+
+	return l, nil
+}
+
+// isLayout reports whether s contains at least one Go reference-time
+// element, i.e. formatting a time with it produces something other than s.
+func isLayout(s string) bool {
+	sample := time.Date(2009, time.November, 10, 23, 9, 8, 0, time.UTC)
+	return sample.Format(s) != s
+}
+
+// Date formats the calendar day of t.
+func (l Locale) Date(t time.Time) string {
+	return t.Format(l.dateLayout)
+}
+
+// Time formats the time of day of t.
+func (l Locale) Time(t time.Time) string {
+	return t.Format(l.timeLayout)
+}
+
+// DateTime formats t as a date followed by a time of day.
+func (l Locale) DateTime(t time.Time) string {
+	return l.Date(t) + " " + l.Time(t)
+}
+
+// Timestamp formats t like DateTime but includes seconds.
+func (l Locale) Timestamp(t time.Time) string {
+	return l.Date(t) + " " + t.Format(l.secondsLayout)
+}
+
+// Relative formats t relative to now for message listings: "just now",
+// "5m ago", "3h ago" or "2d ago" within a week, and the date after that or
+// for times more than a minute ahead.
+func (l Locale) Relative(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < -time.Minute || age >= 7*24*time.Hour:
+		return l.Date(t)
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	}
+}
+
+// Size formats a byte count, e.g. "1.5 MiB" (IEC) or "1.6 MB" (SI).
+func (l Locale) Size(n int64) string {
+	base, units := 1024.0, []string{"KiB", "MiB", "GiB", "TiB"}
+	if l.sizeUnits == SizeUnitsSI {
+		base, units = 1000.0, []string{"kB", "MB", "GB", "TB"}
+	}
+
+	if n < int64(base) {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / base
+	unit := 0
+	for value >= base && unit < len(units)-1 {
+		value /= base
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
+
+var (
+	localeMu      sync.RWMutex
+	currentLocale = DefaultLocale()
+	relativeDates bool
+)
+
+// SetLocale sets the locale used by the table and plain renderers.
+func SetLocale(l Locale) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	currentLocale = l
+}
+
+// CurrentLocale returns the locale used by the table and plain renderers.
+func CurrentLocale() Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return currentLocale
+}
+
+// SetRelativeDates makes message and thread listings show dates relative
+// to now, such as "2h ago", instead of the calendar day.
+func SetRelativeDates(on bool) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	relativeDates = on
+}
+
+// RelativeDates reports whether listings show relative dates.
+func RelativeDates() bool {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	return relativeDates
+}
+
+// listDate formats the date of a message in a listing: relative when
+// SetRelativeDates is on, otherwise the calendar day. A missing date is
+// left blank.
+func listDate(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case RelativeDates():
+		return CurrentLocale().Relative(t, time.Now())
+	}
+	return CurrentLocale().Date(t)
+}
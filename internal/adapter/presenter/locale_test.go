@@ -0,0 +1,174 @@
+package presenter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+var localeRefTime = time.Date(2024, time.December, 31, 14, 5, 9, 0, time.UTC)
+
+func TestNewLocale(t *testing.T) {
+	tests := []struct {
+		name          string
+		dateFormat    string
+		timeFormat    string
+		wantDate      string
+		wantDateTime  string
+		wantTimestamp string
+	}{
+		{"defaults", "", "", "2024-12-31", "2024-12-31 14:05", "2024-12-31 14:05:09"},
+		{"iso 24h", "iso", "24h", "2024-12-31", "2024-12-31 14:05", "2024-12-31 14:05:09"},
+		{"us 12h", "us", "12h", "12/31/2024", "12/31/2024 2:05 PM", "12/31/2024 2:05:09 PM"},
+		{"eu", "eu", "24h", "31/12/2024", "31/12/2024 14:05", "31/12/2024 14:05:09"},
+		{"de", "DE", "24h", "31.12.2024", "31.12.2024 14:05", "31.12.2024 14:05:09"},
+		{"custom layouts", "Jan 2, 2006", "15h04", "Dec 31, 2024", "Dec 31, 2024 14h05", "Dec 31, 2024 14h05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLocale(tt.dateFormat, tt.timeFormat, "")
+			if err != nil {
+				t.Fatalf("NewLocale() error = %v", err)
+			}
+			if got := l.Date(localeRefTime); got != tt.wantDate {
+				t.Errorf("Date() = %q, want %q", got, tt.wantDate)
+			}
+			if got := l.DateTime(localeRefTime); got != tt.wantDateTime {
+				t.Errorf("DateTime() = %q, want %q", got, tt.wantDateTime)
+			}
+			if got := l.Timestamp(localeRefTime); got != tt.wantTimestamp {
+				t.Errorf("Timestamp() = %q, want %q", got, tt.wantTimestamp)
+			}
+		})
+	}
+}
+
+func TestNewLocale_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		dateFormat string
+		timeFormat string
+		sizeUnits  string
+	}{
+		{"unknown date preset", "british", "", ""},
+		{"unknown time preset", "", "military", ""},
+		{"unknown size units", "", "", "metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewLocale(tt.dateFormat, tt.timeFormat, tt.sizeUnits); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestLocale_Size(t *testing.T) {
+	iec, _ := NewLocale("", "", SizeUnitsIEC)
+	si, _ := NewLocale("", "", SizeUnitsSI)
+
+	tests := []struct {
+		size    int64
+		wantIEC string
+		wantSI  string
+	}{
+		{0, "0 B", "0 B"},
+		{999, "999 B", "999 B"},
+		{1000, "1000 B", "1.0 kB"},
+		{1536, "1.5 KiB", "1.5 kB"},
+		{1 << 20, "1.0 MiB", "1.0 MB"},
+		{1500000, "1.4 MiB", "1.5 MB"},
+		{5 << 30, "5.0 GiB", "5.4 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := iec.Size(tt.size); got != tt.wantIEC {
+			t.Errorf("IEC Size(%d) = %q, want %q", tt.size, got, tt.wantIEC)
+		}
+		if got := si.Size(tt.size); got != tt.wantSI {
+			t.Errorf("SI Size(%d) = %q, want %q", tt.size, got, tt.wantSI)
+		}
+	}
+}
+
+func TestLocale_Relative(t *testing.T) {
+	l := DefaultLocale()
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(30 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-2*time.Hour - 59*time.Minute), "2h ago"},
+		{now.Add(-50 * time.Hour), "2d ago"},
+		{now.Add(-8 * 24 * time.Hour), "2026-05-02"},
+		{now.Add(2 * time.Hour), "2026-05-10"},
+	}
+	for _, tt := range tests {
+		if got := l.Relative(tt.t, now); got != tt.want {
+			t.Errorf("Relative(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSetRelativeDates(t *testing.T) {
+	t.Cleanup(func() { SetRelativeDates(false) })
+	msg := mail.NewMessage("msg-1", "thread-1", "sender@example.com", "Subject", "Body")
+	msg.Date = time.Now().Add(-3 * time.Hour)
+	undated := mail.NewMessage("msg-2", "thread-1", "sender@example.com", "Undated", "Body")
+	undated.Date = time.Time{}
+
+	SetRelativeDates(true)
+	out := NewPlainPresenter().RenderMessages([]*mail.Message{msg, undated})
+	if !strings.Contains(out, "3h ago") || strings.Contains(out, "0001") {
+		t.Errorf("expected a relative date and a blank one, got:\n%s", out)
+	}
+
+	SetRelativeDates(false)
+	if out := NewTablePresenter().RenderMessages([]*mail.Message{msg}); strings.Contains(out, "ago") {
+		t.Errorf("expected a calendar date, got:\n%s", out)
+	}
+}
+
+func TestSetLocale_AppliesToTableAndPlain(t *testing.T) {
+	l, err := NewLocale("us", "12h", "")
+	if err != nil {
+		t.Fatalf("NewLocale() error = %v", err)
+	}
+	SetLocale(l)
+	t.Cleanup(func() { SetLocale(DefaultLocale()) })
+
+	msg := mail.NewMessage("msg-1", "thread-1", "sender@example.com", "Subject", "Body")
+	msg.Date = localeRefTime
+	event := &calendar.Event{ID: "evt-1", Title: "Standup", Start: localeRefTime, End: localeRefTime.Add(time.Hour)}
+
+	outputs := map[string]string{
+		"table message": NewTablePresenter().RenderMessage(msg),
+		"table events":  NewTablePresenter().RenderEvents([]*calendar.Event{event}),
+		"plain message": NewPlainPresenter().RenderMessage(msg),
+		"plain events":  NewPlainPresenter().RenderEvents([]*calendar.Event{event}),
+	}
+	for name, out := range outputs {
+		if !strings.Contains(out, "12/31/2024") {
+			t.Errorf("%s: expected US date, got:\n%s", name, out)
+		}
+		if strings.Contains(out, "2024-12-31") {
+			t.Errorf("%s: unexpected ISO date, got:\n%s", name, out)
+		}
+	}
+	if !strings.Contains(outputs["table events"], "2:05 PM") {
+		t.Errorf("expected 12-hour time in table events, got:\n%s", outputs["table events"])
+	}
+
+	// JSON keeps machine-readable timestamps.
+	if out := NewJSONPresenter().RenderMessage(msg); !strings.Contains(out, "2024-12-31T14:05:09Z") {
+		t.Errorf("expected RFC 3339 date in JSON, got:\n%s", out)
+	}
+}
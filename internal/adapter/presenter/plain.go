@@ -28,24 +28,35 @@ func (p *PlainPresenter) RenderMessage(msg *mail.Message) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("ID: %s", msg.ID))
 	lines = append(lines, fmt.Sprintf("ThreadID: %s", msg.ThreadID))
-	lines = append(lines, fmt.Sprintf("From: %s", msg.From))
-	lines = append(lines, fmt.Sprintf("To: %s", strings.Join(msg.To, ", ")))
+	lines = append(lines, fmt.Sprintf("From: %s", Highlight(msg.From.String())))
+	lines = append(lines, fmt.Sprintf("To: %s", mail.JoinAddresses(msg.To)))
 	if len(msg.Cc) > 0 {
-		lines = append(lines, fmt.Sprintf("Cc: %s", strings.Join(msg.Cc, ", ")))
+		lines = append(lines, fmt.Sprintf("Cc: %s", mail.JoinAddresses(msg.Cc)))
 	}
 	if len(msg.Bcc) > 0 {
-		lines = append(lines, fmt.Sprintf("Bcc: %s", strings.Join(msg.Bcc, ", ")))
+		lines = append(lines, fmt.Sprintf("Bcc: %s", mail.JoinAddresses(msg.Bcc)))
 	}
-	lines = append(lines, fmt.Sprintf("Subject: %s", msg.Subject))
-	lines = append(lines, fmt.Sprintf("Date: %s", msg.Date.Format("2006-01-02 15:04:05")))
+	lines = append(lines, fmt.Sprintf("Subject: %s", Highlight(msg.Subject)))
+	lines = append(lines, fmt.Sprintf("Date: %s", CurrentLocale().Timestamp(msg.Date)))
 	lines = append(lines, fmt.Sprintf("Labels: %s", strings.Join(msg.Labels, ", ")))
 	lines = append(lines, fmt.Sprintf("Read: %v", msg.IsRead))
 	lines = append(lines, fmt.Sprintf("Starred: %v", msg.IsStarred))
+	lines = append(lines, fmt.Sprintf("Important: %v", msg.IsImportant))
 	if msg.Snippet != "" {
-		lines = append(lines, fmt.Sprintf("Snippet: %s", msg.Snippet))
+		lines = append(lines, fmt.Sprintf("Snippet: %s", Highlight(msg.Snippet)))
+	}
+	if report := formatDeliveryReport(msg.Report); report != "" {
+		lines = append(lines, fmt.Sprintf("Report: %s", report))
+	}
+	for _, field := range invitationFields(msg.Invitation) {
+		lines = append(lines, fmt.Sprintf("%s: %s", field[0], field[1]))
+	}
+	if msg.Translation != nil {
+		lines = append(lines, fmt.Sprintf("Language: %s", FormatTranslationLanguages(msg.Translation)))
+		lines = append(lines, fmt.Sprintf("Translated Subject: %s", msg.Translation.Subject))
 	}
 	if msg.Body != "" {
-		lines = append(lines, fmt.Sprintf("Body: %s", msg.Body))
+		lines = append(lines, fmt.Sprintf("Body: %s", Highlight(msg.Body)))
 	}
 
 	return strings.Join(lines, "\n")
@@ -64,9 +75,9 @@ func (p *PlainPresenter) RenderMessages(msgs []*mail.Message) string {
 		}
 		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s",
 			msg.ID,
-			msg.From,
-			msg.Subject,
-			msg.Date.Format("2006-01-02"),
+			Highlight(msg.From.String()),
+			Highlight(msg.Subject),
+			listDate(msg.Date),
 		))
 	}
 	return strings.Join(lines, "\n")
@@ -80,12 +91,12 @@ func (p *PlainPresenter) RenderDraft(draft *mail.Draft) string {
 
 	var lines []string
 	lines = append(lines, fmt.Sprintf("ID: %s", draft.ID))
-	lines = append(lines, fmt.Sprintf("Created: %s", draft.Created.Format("2006-01-02 15:04:05")))
-	lines = append(lines, fmt.Sprintf("Updated: %s", draft.Updated.Format("2006-01-02 15:04:05")))
+	lines = append(lines, fmt.Sprintf("Created: %s", CurrentLocale().Timestamp(draft.Created)))
+	lines = append(lines, fmt.Sprintf("Updated: %s", CurrentLocale().Timestamp(draft.Updated)))
 
 	if draft.Message != nil {
 		lines = append(lines, fmt.Sprintf("MessageID: %s", draft.Message.ID))
-		lines = append(lines, fmt.Sprintf("To: %s", strings.Join(draft.Message.To, ", ")))
+		lines = append(lines, fmt.Sprintf("To: %s", mail.JoinAddresses(draft.Message.To)))
 		lines = append(lines, fmt.Sprintf("Subject: %s", draft.Message.Subject))
 	}
 
@@ -110,7 +121,7 @@ func (p *PlainPresenter) RenderDrafts(drafts []*mail.Draft) string {
 		lines = append(lines, fmt.Sprintf("%s\t%s\t%s",
 			draft.ID,
 			subject,
-			draft.Updated.Format("2006-01-02"),
+			CurrentLocale().Date(draft.Updated),
 		))
 	}
 	return strings.Join(lines, "\n")
@@ -127,7 +138,7 @@ func (p *PlainPresenter) RenderThread(thread *mail.Thread) string {
 	lines = append(lines, fmt.Sprintf("Messages: %d", thread.MessageCount()))
 	lines = append(lines, fmt.Sprintf("Labels: %s", strings.Join(thread.Labels, ", ")))
 	if thread.Snippet != "" {
-		lines = append(lines, fmt.Sprintf("Snippet: %s", thread.Snippet))
+		lines = append(lines, fmt.Sprintf("Snippet: %s", Highlight(thread.Snippet)))
 	}
 
 	for i, msg := range thread.Messages {
@@ -148,14 +159,25 @@ func (p *PlainPresenter) RenderThreads(threads []*mail.Thread) string {
 	}
 
 	var lines []string
-	for _, thread := range threads {
+	grouped := threadsGrouped(threads)
+	for i, thread := range threads {
 		if thread == nil {
 			continue
 		}
+		if grouped {
+			lines = append(lines, fmt.Sprintf("%d\t%s\t%d\t%d\t%s",
+				i+1,
+				thread.ID,
+				thread.MessageCount(),
+				thread.UnreadCount(),
+				Highlight(thread.Snippet),
+			))
+			continue
+		}
 		lines = append(lines, fmt.Sprintf("%s\t%d\t%s",
 			thread.ID,
 			thread.MessageCount(),
-			thread.Snippet,
+			Highlight(thread.Snippet),
 		))
 	}
 	return strings.Join(lines, "\n")
@@ -222,10 +244,10 @@ func (p *PlainPresenter) RenderEvent(event *calendar.Event) string {
 	}
 
 	if event.AllDay {
-		lines = append(lines, fmt.Sprintf("Date: %s (All Day)", event.Start.Format("2006-01-02")))
+		lines = append(lines, fmt.Sprintf("Date: %s (All Day)", CurrentLocale().Date(event.Start)))
 	} else {
-		lines = append(lines, fmt.Sprintf("Start: %s", event.Start.Format("2006-01-02 15:04")))
-		lines = append(lines, fmt.Sprintf("End: %s", event.End.Format("2006-01-02 15:04")))
+		lines = append(lines, fmt.Sprintf("Start: %s", CurrentLocale().DateTime(event.Start)))
+		lines = append(lines, fmt.Sprintf("End: %s", CurrentLocale().DateTime(event.End)))
 	}
 
 	lines = append(lines, fmt.Sprintf("Status: %s", event.Status))
@@ -235,6 +257,9 @@ func (p *PlainPresenter) RenderEvent(event *calendar.Event) string {
 	if event.HasConference() {
 		lines = append(lines, fmt.Sprintf("Conference: %s", event.ConferenceData.URI))
 	}
+	if len(event.Attachments) > 0 {
+		lines = append(lines, fmt.Sprintf("Attachments: %s", strings.Join(event.AttachmentNames(), ", ")))
+	}
 	if event.HTMLLink != "" {
 		lines = append(lines, fmt.Sprintf("Link: %s", event.HTMLLink))
 	}
@@ -253,9 +278,9 @@ func (p *PlainPresenter) RenderEvents(events []*calendar.Event) string {
 		if event == nil {
 			continue
 		}
-		timeStr := event.Start.Format("2006-01-02 15:04")
+		timeStr := CurrentLocale().DateTime(event.Start)
 		if event.AllDay {
-			timeStr = event.Start.Format("2006-01-02") + " (All Day)"
+			timeStr = CurrentLocale().Date(event.Start) + " (All Day)"
 		}
 		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s",
 			event.ID,
@@ -369,9 +394,9 @@ func (p *PlainPresenter) RenderAccount(acct *account.Account) string {
 	lines = append(lines, fmt.Sprintf("Alias: %s", acct.Alias))
 	lines = append(lines, fmt.Sprintf("Email: %s", acct.Email))
 	lines = append(lines, fmt.Sprintf("Default: %v", acct.IsDefault))
-	lines = append(lines, fmt.Sprintf("Added: %s", acct.Added.Format("2006-01-02")))
+	lines = append(lines, fmt.Sprintf("Added: %s", CurrentLocale().Date(acct.Added)))
 	if !acct.LastUsed.IsZero() {
-		lines = append(lines, fmt.Sprintf("LastUsed: %s", acct.LastUsed.Format("2006-01-02")))
+		lines = append(lines, fmt.Sprintf("LastUsed: %s", CurrentLocale().Date(acct.LastUsed)))
 	}
 	lines = append(lines, fmt.Sprintf("Scopes: %d", len(acct.Scopes)))
 	for _, scope := range acct.Scopes {
@@ -415,7 +440,7 @@ func (p *PlainPresenter) RenderTaskList(taskList *domaintasks.TaskList) string {
 	var lines []string
 	lines = append(lines, fmt.Sprintf("ID: %s", taskList.ID))
 	lines = append(lines, fmt.Sprintf("Title: %s", taskList.Title))
-	lines = append(lines, fmt.Sprintf("Updated: %s", taskList.Updated.Format("2006-01-02 15:04:05")))
+	lines = append(lines, fmt.Sprintf("Updated: %s", CurrentLocale().Timestamp(taskList.Updated)))
 
 	return strings.Join(lines, "\n")
 }
@@ -434,7 +459,7 @@ func (p *PlainPresenter) RenderTaskLists(taskLists []*domaintasks.TaskList) stri
 		lines = append(lines, fmt.Sprintf("%s\t%s\t%s",
 			tl.ID,
 			tl.Title,
-			tl.Updated.Format("2006-01-02 15:04:05"),
+			CurrentLocale().Timestamp(tl.Updated),
 		))
 	}
 	return strings.Join(lines, "\n")
@@ -454,15 +479,15 @@ func (p *PlainPresenter) RenderTask(task *domaintasks.Task) string {
 		lines = append(lines, fmt.Sprintf("Notes: %s", task.Notes))
 	}
 	if task.Due != nil {
-		lines = append(lines, fmt.Sprintf("Due: %s", task.Due.Format("2006-01-02")))
+		lines = append(lines, fmt.Sprintf("Due: %s", CurrentLocale().Date(*task.Due)))
 	}
 	if task.Completed != nil {
-		lines = append(lines, fmt.Sprintf("Completed: %s", task.Completed.Format("2006-01-02 15:04:05")))
+		lines = append(lines, fmt.Sprintf("Completed: %s", CurrentLocale().Timestamp(*task.Completed)))
 	}
 	if task.Parent != nil {
 		lines = append(lines, fmt.Sprintf("Parent: %s", *task.Parent))
 	}
-	lines = append(lines, fmt.Sprintf("Updated: %s", task.Updated.Format("2006-01-02 15:04:05")))
+	lines = append(lines, fmt.Sprintf("Updated: %s", CurrentLocale().Timestamp(task.Updated)))
 
 	return strings.Join(lines, "\n")
 }
@@ -480,14 +505,14 @@ func (p *PlainPresenter) RenderTasks(tasks []*domaintasks.Task) string {
 		}
 		dueStr := ""
 		if task.Due != nil {
-			dueStr = task.Due.Format("2006-01-02")
+			dueStr = CurrentLocale().Date(*task.Due)
 		}
 		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
 			task.ID,
 			task.Title,
 			task.Status,
 			dueStr,
-			task.Updated.Format("2006-01-02 15:04:05"),
+			CurrentLocale().Timestamp(task.Updated),
 		))
 	}
 	return strings.Join(lines, "\n")
@@ -654,7 +679,7 @@ func (p *PlainPresenter) RenderContactGroup(group *domaincontacts.ContactGroup)
 	lines = append(lines, fmt.Sprintf("Type: %s", group.GroupType))
 	lines = append(lines, fmt.Sprintf("MemberCount: %d", group.MemberCount))
 	if group.Metadata != nil && !group.Metadata.UpdateTime.IsZero() {
-		lines = append(lines, fmt.Sprintf("Updated: %s", group.Metadata.UpdateTime.Format("2006-01-02 15:04:05")))
+		lines = append(lines, fmt.Sprintf("Updated: %s", CurrentLocale().Timestamp(group.Metadata.UpdateTime)))
 	}
 
 	return strings.Join(lines, "\n")
@@ -686,6 +711,9 @@ func (p *PlainPresenter) RenderError(err error) string {
 	if err == nil {
 		return ""
 	}
+	if hint := ErrorHint(err); hint != "" {
+		return fmt.Sprintf("error: %s\nhint: %s", err.Error(), hint)
+	}
 	return fmt.Sprintf("error: %s", err.Error())
 }
 
@@ -53,6 +53,58 @@ func TestPlainPresenter_RenderMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("renders read receipt report", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "reader@example.com", "Read: Hello", "")
+		msg.Report = &mail.DeliveryReport{
+			Type:           mail.ReportDisposition,
+			FinalRecipient: "reader@example.com",
+			Disposition:    "manual-action/MDN-sent-manually; displayed",
+		}
+
+		result := p.RenderMessage(msg)
+		if !strings.Contains(result, "Report: Read receipt (displayed) from reader@example.com") {
+			t.Errorf("Result should contain receipt summary, got:\n%s", result)
+		}
+	})
+
+	t.Run("renders translation", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "ami@example.fr", "Bonjour", "")
+		msg.Translation = &mail.Translation{SourceLanguage: "fr", TargetLanguage: "en", Subject: "Hello"}
+
+		result := p.RenderMessage(msg)
+		if !strings.Contains(result, "Language: fr -> en\nTranslated Subject: Hello") {
+			t.Errorf("Result should contain translation lines, got:\n%s", result)
+		}
+	})
+
+	t.Run("renders cancelled invitation", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "ana@example.com", "Cancelled: Design review", "")
+		msg.Invitation = &mail.Invitation{
+			Method:       mail.MethodCancel,
+			Summary:      "Design review",
+			Start:        time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local),
+			End:          time.Date(2026, 3, 9, 9, 30, 0, 0, time.Local),
+			RecurrenceID: time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local),
+			Location:     "Room 2",
+			Response:     mail.ResponseAccepted,
+		}
+
+		result := p.RenderMessage(msg)
+		if !strings.Contains(result, "Invitation: Design review (cancelled)\nWhen: ") || !strings.Contains(result, ", one occurrence\nWhere: Room 2") {
+			t.Errorf("Result should contain invitation lines, got:\n%s", result)
+		}
+		if strings.Contains(result, "RSVP:") {
+			t.Error("Result should not ask for an answer to a cancelled event")
+		}
+	})
+
+	t.Run("omits report for regular message", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "sender@example.com", "Hello", "")
+		if strings.Contains(p.RenderMessage(msg), "Report:") {
+			t.Error("Result should not contain a report line")
+		}
+	})
+
 	t.Run("renders nil message as empty string", func(t *testing.T) {
 		result := p.RenderMessage(nil)
 		if result != "" {
@@ -206,6 +258,16 @@ func TestPlainPresenter_RenderThreads(t *testing.T) {
 		}
 	})
 
+	t.Run("numbers grouped threads", func(t *testing.T) {
+		msg := mail.NewMessage("m-1", "t-1", "a@example.com", "Hi", "")
+		msg.Snippet = "Hello"
+		result := p.RenderThreads(mail.GroupByThread([]*mail.Message{msg}))
+
+		if result != "1\tt-1\t1\t1\tHello" {
+			t.Errorf("unexpected grouped line %q", result)
+		}
+	})
+
 	t.Run("renders empty list as empty string", func(t *testing.T) {
 		result := p.RenderThreads([]*mail.Thread{})
 		if result != "" {
@@ -498,6 +560,14 @@ func TestPlainPresenter_RenderError(t *testing.T) {
 		}
 	})
 
+	t.Run("adds the hint of API errors", func(t *testing.T) {
+		result := p.RenderError(newDetailedTestError())
+		want := "error: rate limited (status 403): slow down\nhint: pause between commands"
+		if result != want {
+			t.Errorf("Expected %q, got %q", want, result)
+		}
+	})
+
 	t.Run("renders nil error as empty string", func(t *testing.T) {
 		result := p.RenderError(nil)
 		if result != "" {
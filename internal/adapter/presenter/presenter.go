@@ -16,8 +16,10 @@ const (
 	FormatPlain = "plain"
 )
 
-// Presenter defines the interface for rendering domain entities as formatted output.
-type Presenter interface {
+// Renderer defines the interface for rendering domain entities as formatted
+// output. Each output format implements Renderer and registers itself with
+// Register; commands obtain one through New.
+type Renderer interface {
 	// Mail entities
 	RenderMessage(msg *mail.Message) string
 	RenderMessages(msgs []*mail.Message) string
@@ -57,18 +59,11 @@ type Presenter interface {
 	RenderSuccess(msg string) string
 }
 
-// New creates a new Presenter based on the specified format.
-// Supported formats: "json", "table", "plain".
+// New creates the Renderer registered for the specified format.
 // Returns a TablePresenter as the default if the format is not recognized.
-func New(format string) Presenter {
-	switch format {
-	case FormatJSON:
-		return NewJSONPresenter()
-	case FormatTable:
-		return NewTablePresenter()
-	case FormatPlain:
-		return NewPlainPresenter()
-	default:
-		return NewTablePresenter()
+func New(format string) Renderer {
+	if factory, ok := lookup(format); ok {
+		return factory()
 	}
+	return NewTablePresenter()
 }
@@ -52,7 +52,7 @@ func TestNew(t *testing.T) {
 	}
 }
 
-func getTypeName(p Presenter) string {
+func getTypeName(p Renderer) string {
 	switch p.(type) {
 	case *JSONPresenter:
 		return "*presenter.JSONPresenter"
@@ -0,0 +1,70 @@
+package presenter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory creates a Renderer for one output format.
+type Factory func() Renderer
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+func init() {
+	Register(FormatJSON, func() Renderer { return NewJSONPresenter() })
+	Register(FormatTable, func() Renderer { return NewTablePresenter() })
+	Register(FormatPlain, func() Renderer { return NewPlainPresenter() })
+}
+
+// Register makes an output format available to the --format flag.
+// Additional formats register themselves from an init function, usually in
+// a file guarded by a build tag so they are only compiled in on request.
+// Register panics if the name is empty, the factory is nil, or the format
+// is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("presenter: Register called with empty format name")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("presenter: Register called with nil factory for %q", name))
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("presenter: Register called twice for format %q", name))
+	}
+	registry[name] = factory
+}
+
+// Formats returns the names of all registered output formats, sorted.
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsRegistered reports whether an output format is available.
+func IsRegistered(name string) bool {
+	_, ok := lookup(name)
+	return ok
+}
+
+// lookup returns the factory registered for a format.
+func lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, ok := registry[name]
+	return factory, ok
+}
@@ -0,0 +1,74 @@
+package presenter
+
+import (
+	"slices"
+	"testing"
+)
+
+// stubRenderer is a Renderer used to exercise the registry.
+type stubRenderer struct {
+	*PlainPresenter
+}
+
+func TestFormats_BuiltIn(t *testing.T) {
+	formats := Formats()
+	for _, want := range []string{FormatJSON, FormatPlain, FormatTable} {
+		if !slices.Contains(formats, want) {
+			t.Errorf("Formats() = %v, missing %q", formats, want)
+		}
+	}
+	if !slices.IsSorted(formats) {
+		t.Errorf("Formats() = %v, want sorted", formats)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	const name = "test-stub"
+	Register(name, func() Renderer { return stubRenderer{NewPlainPresenter()} })
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, name)
+		registryMu.Unlock()
+	})
+
+	if !IsRegistered(name) {
+		t.Fatalf("expected %q to be registered", name)
+	}
+	if _, ok := New(name).(stubRenderer); !ok {
+		t.Errorf("New(%q) returned %T, want stubRenderer", name, New(name))
+	}
+	if !slices.Contains(Formats(), name) {
+		t.Errorf("Formats() does not list %q", name)
+	}
+}
+
+func TestRegister_Panics(t *testing.T) {
+	factory := func() Renderer { return NewPlainPresenter() }
+
+	tests := []struct {
+		name    string
+		format  string
+		factory Factory
+	}{
+		{"empty name", "", factory},
+		{"nil factory", "test-nil", nil},
+		{"duplicate", FormatJSON, factory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", tt.format)
+				}
+			}()
+			Register(tt.format, tt.factory)
+		})
+	}
+}
+
+func TestIsRegistered_Unknown(t *testing.T) {
+	if IsRegistered("does-not-exist") {
+		t.Error("expected unknown format to be unregistered")
+	}
+}
@@ -0,0 +1,211 @@
+package presenter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// MessageStream writes messages one at a time as they arrive, so a long
+// list shows results before its last message is fetched. Table output has
+// fixed column widths, from the column limits fitted to the terminal,
+// since rows already written cannot be widened; plain and ID output write
+// a line per message. JSON output writes the elements of one array, so
+// memory stays flat however many messages are written.
+type MessageStream struct {
+	out        *errWriter
+	lines      Renderer
+	json       bool
+	table      *tablewriter.Table
+	headers    []string
+	widths     []int
+	wrap       bool
+	importance bool
+	// blocks separates the messages with blank lines, for accessible
+	// output.
+	blocks bool
+	count  int
+}
+
+// NewMessageStream returns a stream writing messages to w as r renders
+// them. It returns false for renderers whose output cannot be written a
+// message at a time, such as HTML, which wraps every row in one page.
+func NewMessageStream(w io.Writer, r Renderer) (*MessageStream, bool) {
+	out := &errWriter{w: w}
+	switch p := r.(type) {
+	case *PlainPresenter, *IDPresenter:
+		return &MessageStream{out: out, lines: r}, true
+	case *JSONPresenter:
+		return &MessageStream{out: out, json: true}, true
+	case *TablePresenter:
+		if p.noFit {
+			return nil, false
+		}
+		if Accessible() {
+			// Each message is a block of its own, separated by a blank
+			// line, so no widths need fixing.
+			return &MessageStream{out: out, lines: r, blocks: true}, true
+		}
+		return p.newMessageStream(out), true
+	}
+	return nil, false
+}
+
+// newMessageStream returns a stream writing a message table to out.
+func (p *TablePresenter) newMessageStream(out *errWriter) *MessageStream {
+	layout := CurrentTableLayout()
+	showImportance := ImportanceColumn()
+	headers, limits := messageColumns(showImportance)
+
+	widths := make([]int, len(headers))
+	floors := make([]int, len(headers))
+	for i, header := range headers {
+		width := layout.columnLimit(header, limits[i])
+		if width == 0 {
+			// Without a limit a column is as wide as its values can be
+			switch header {
+			case "!":
+				width = 1
+			case "Date":
+				width = cellWidth(CurrentLocale().Date(time.Now()))
+				if RelativeDates() {
+					width = max(width, cellWidth("just now"))
+				}
+			default:
+				width = detailTextWidth
+			}
+		}
+		widths[i] = max(width, cellWidth(header))
+		floors[i] = columnFloor(header, widths[i])
+	}
+	fitWidths(widths, floors, layout.Width)
+
+	// tablewriter widths include the space on both sides of a cell
+	columns := tw.NewMapper[int, int]()
+	for i, width := range widths {
+		columns.Set(i, width+2)
+	}
+	opts := append(slices.Clone(p.tableOptions),
+		tablewriter.WithStreaming(tw.StreamConfig{Enable: true}),
+		tablewriter.WithWidths(tw.CellWidth{PerColumn: columns}),
+	)
+	return &MessageStream{
+		out:        out,
+		table:      tablewriter.NewTable(out, opts...),
+		headers:    headers,
+		widths:     widths,
+		wrap:       layout.Wrap,
+		importance: showImportance,
+	}
+}
+
+// Write writes msg. It returns the error writing the output, such as a
+// closed pipe, so the caller can stop fetching.
+func (s *MessageStream) Write(msg *mail.Message) error {
+	if msg == nil {
+		return nil
+	}
+	s.count++
+	if s.json {
+		return s.writeJSON(msg)
+	}
+	if s.table == nil {
+		out := s.lines.RenderMessages([]*mail.Message{msg})
+		if s.blocks {
+			if s.count > 1 {
+				_, _ = fmt.Fprintln(s.out)
+			}
+			out = strings.TrimSuffix(out, "\n")
+		}
+		_, _ = fmt.Fprintln(s.out, out)
+		return s.out.err
+	}
+
+	if s.count == 1 {
+		if err := s.table.Start(); err != nil {
+			return err
+		}
+		s.table.Header(s.headers)
+	}
+	row := messageRow(msg, s.importance)
+	for i, cell := range row {
+		row[i] = fitCell(cell, s.widths[i], s.wrap)
+		if slices.Contains(messageHighlightHeaders, s.headers[i]) {
+			row[i] = Highlight(row[i])
+		}
+	}
+	if err := s.table.Append(row); err != nil {
+		return err
+	}
+	return s.out.err
+}
+
+// writeJSON writes msg as the next element of a JSON array, indented as
+// JSONPresenter indents the whole array.
+func (s *MessageStream) writeJSON(msg *mail.Message) error {
+	data, err := json.MarshalIndent(msg, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode message %s: %w", msg.ID, err)
+	}
+	sep := ",\n  "
+	if s.count == 1 {
+		sep = "[\n  "
+	}
+	_, _ = io.WriteString(s.out, sep)
+	_, _ = s.out.Write(data)
+	return s.out.err
+}
+
+// Count returns the number of messages written.
+func (s *MessageStream) Count() int {
+	return s.count
+}
+
+// Close finishes the output: the end of a JSON array, the bottom border of
+// a table, or "No messages found" when no message was written to one.
+func (s *MessageStream) Close() error {
+	if s.json {
+		if s.count == 0 {
+			_, _ = io.WriteString(s.out, "[]\n")
+		} else {
+			_, _ = io.WriteString(s.out, "\n]\n")
+		}
+	}
+	if s.blocks && s.count == 0 {
+		_, _ = fmt.Fprintln(s.out, "No messages found")
+	}
+	if s.table != nil {
+		if s.count == 0 {
+			_, _ = fmt.Fprintln(s.out, "No messages found")
+		} else if err := s.table.Close(); err != nil {
+			return err
+		}
+	}
+	return s.out.err
+}
+
+// errWriter keeps the first error writing to w, as tablewriter does not
+// report them, and writes nothing after it.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write writes p unless an earlier write failed.
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.w.Write(p)
+	if err != nil {
+		e.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,125 @@
+package presenter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+func TestMessageStream_Table(t *testing.T) {
+	withTableLayout(t, TableLayout{Width: 90})
+	var buf bytes.Buffer
+	s, ok := NewMessageStream(&buf, NewTablePresenter())
+	if !ok {
+		t.Fatal("expected tables to stream")
+	}
+
+	if err := s.Write(&mail.Message{ID: "msg1", From: mail.Address{Email: "alice@example.com"}, Subject: "🎉 Launch", Date: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	// The first row is out before the next message arrives
+	if !strings.Contains(buf.String(), "msg1") || !strings.Contains(buf.String(), "SUBJECT") {
+		t.Fatalf("expected the header and first row, got:\n%s", buf.String())
+	}
+	if err := s.Write(&mail.Message{ID: "msg2", From: mail.Address{Email: "someone-with-a-very-long-address@example.com"}, Subject: strings.Repeat("long subject ", 10), Date: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		if twwidth.Width(line) != twwidth.Width(lines[0]) {
+			t.Fatalf("misaligned table:\n%s", buf.String())
+		}
+	}
+	if w := twwidth.Width(lines[0]); w > 90 {
+		t.Errorf("table is %d columns wide, want at most 90:\n%s", w, buf.String())
+	}
+	if s.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", s.Count())
+	}
+}
+
+func TestMessageStream_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	s, _ := NewMessageStream(&buf, NewTablePresenter())
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No messages found\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestMessageStream_Lines(t *testing.T) {
+	var buf bytes.Buffer
+	s, ok := NewMessageStream(&buf, NewIDPresenter())
+	if !ok {
+		t.Fatal("expected IDs to stream")
+	}
+	_ = s.Write(&mail.Message{ID: "msg1"})
+	_ = s.Write(nil)
+	_ = s.Write(&mail.Message{ID: "msg2"})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "msg1\nmsg2\n" {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestMessageStream_JSON(t *testing.T) {
+	msgs := []*mail.Message{
+		{ID: "msg1", Subject: "First", Labels: []string{"INBOX"}},
+		{ID: "msg2", Subject: "Second", To: []mail.Address{{Name: "Bob", Email: "bob@example.com"}}},
+	}
+	for _, n := range []int{0, 1, 2} {
+		var buf bytes.Buffer
+		s, ok := NewMessageStream(&buf, NewJSONPresenter())
+		if !ok {
+			t.Fatal("expected JSON to stream")
+		}
+		for _, msg := range msgs[:n] {
+			if err := s.Write(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The same document as rendering all the messages at once
+		want := NewJSONPresenter().RenderMessages(msgs[:n]) + "\n"
+		if buf.String() != want {
+			t.Errorf("%d messages: got\n%s\nwant\n%s", n, buf.String(), want)
+		}
+	}
+}
+
+func TestMessageStream_NotStreamable(t *testing.T) {
+	// As HTML output renders its tables
+	if _, ok := NewMessageStream(&bytes.Buffer{}, &TablePresenter{noFit: true}); ok {
+		t.Error("unfitted tables should not stream")
+	}
+}
+
+// failingWriter fails every write, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestMessageStream_WriteError(t *testing.T) {
+	for _, r := range []Renderer{NewTablePresenter(), NewPlainPresenter(), NewJSONPresenter()} {
+		s, _ := NewMessageStream(failingWriter{}, r)
+		if err := s.Write(&mail.Message{ID: "msg1"}); err == nil {
+			t.Errorf("%T: expected the write error", r)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package presenter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/stainedhead/go-goog-cli/internal/domain/account"
@@ -13,29 +15,152 @@ import (
 )
 
 // TablePresenter formats output as ASCII tables.
-type TablePresenter struct{}
+type TablePresenter struct {
+	// tableOptions are applied to every table, e.g. to swap the renderer.
+	tableOptions []tablewriter.Option
+	// noFit keeps columns at their limits, ignoring the terminal width and
+	// --wrap, for renderers whose output is not read in a terminal.
+	noFit bool
+}
 
 // NewTablePresenter creates a new TablePresenter.
 func NewTablePresenter() *TablePresenter {
 	return &TablePresenter{}
 }
 
-// truncate shortens s to maxLen characters, appending "..." if truncated.
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+var (
+	importanceMu     sync.RWMutex
+	importanceColumn bool
+)
+
+// SetImportanceColumn adds or removes the "!" column that marks important
+// messages in message tables.
+func SetImportanceColumn(on bool) {
+	importanceMu.Lock()
+	defer importanceMu.Unlock()
+	importanceColumn = on
+}
+
+// ImportanceColumn reports whether message tables show the importance column.
+func ImportanceColumn() bool {
+	importanceMu.RLock()
+	defer importanceMu.RUnlock()
+	return importanceColumn
+}
+
+// messageColumn is a column that message lists can show.
+type messageColumn struct {
+	header string
+	// limit is the default width limit; 0 leaves the column unlimited.
+	limit int
+	value func(*mail.Message) string
+}
+
+// messageColumnSet holds the columns selectable with SetMessageColumns,
+// keyed by name.
+var messageColumnSet = map[string]messageColumn{
+	"id":         {"ID", 12, func(m *mail.Message) string { return m.ID }},
+	"from":       {"From", 25, func(m *mail.Message) string { return m.From.String() }},
+	"from_name":  {"From Name", 25, func(m *mail.Message) string { return m.From.Name }},
+	"from_email": {"From Email", 30, func(m *mail.Message) string { return m.From.Email }},
+	"to":         {"To", 25, func(m *mail.Message) string { return mail.JoinAddresses(m.To) }},
+	"subject":    {"Subject", 40, func(m *mail.Message) string { return m.Subject }},
+	"snippet":    {"Snippet", 40, func(m *mail.Message) string { return m.Snippet }},
+	"date":       {"Date", 0, func(m *mail.Message) string { return listDate(m.Date) }},
+	"labels":     {"Labels", 20, func(m *mail.Message) string { return strings.Join(m.Labels, ", ") }},
+}
+
+// defaultMessageColumns are the columns message lists show by default.
+var defaultMessageColumns = []string{"id", "from", "subject", "date", "labels"}
+
+// messageHighlightHeaders are the message list columns whose text search
+// terms are highlighted in.
+var messageHighlightHeaders = []string{"From", "From Name", "From Email", "Subject", "Snippet"}
+
+var (
+	messageColumnsMu   sync.RWMutex
+	selectedMsgColumns []string
+)
+
+// SetMessageColumns sets the columns of message tables by name, such as
+// "id", "from_name", "from_email" and "subject"; MessageColumnNames lists
+// them all. No names restores the default columns.
+func SetMessageColumns(names []string) error {
+	var selected []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := messageColumnSet[name]; !ok {
+			return fmt.Errorf("unknown column %q: use %s", name, strings.Join(MessageColumnNames(), ", "))
+		}
+		selected = append(selected, name)
+	}
+	messageColumnsMu.Lock()
+	defer messageColumnsMu.Unlock()
+	selectedMsgColumns = selected
+	return nil
+}
+
+// MessageColumns returns the names of the columns message tables show.
+func MessageColumns() []string {
+	messageColumnsMu.RLock()
+	defer messageColumnsMu.RUnlock()
+	if len(selectedMsgColumns) == 0 {
+		return defaultMessageColumns
+	}
+	return selectedMsgColumns
+}
+
+// MessageColumnNames returns the names accepted by SetMessageColumns.
+func MessageColumnNames() []string {
+	names := make([]string, 0, len(messageColumnSet))
+	for name := range messageColumnSet {
+		names = append(names, name)
 	}
-	if maxLen <= 3 {
-		return s[:maxLen]
+	sort.Strings(names)
+	return names
+}
+
+// importanceMarker returns the importance column value for a message.
+func importanceMarker(msg *mail.Message) string {
+	if msg.IsImportant {
+		return "!"
 	}
-	return s[:maxLen-3] + "..."
+	return ""
 }
 
-// createTable creates a new tablewriter with standard settings.
-func createTable(buf *strings.Builder, headers []string) *tablewriter.Table {
-	table := tablewriter.NewTable(buf)
+// threadsGrouped reports whether threads carry their messages, as threads
+// grouped from a message listing do. Threads listed from the API only have
+// an ID and snippet, so per-thread counts and dates cannot be shown for them.
+func threadsGrouped(threads []*mail.Thread) bool {
+	for _, thread := range threads {
+		if thread != nil && len(thread.Messages) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// createTable creates a table with standard settings. limits are the
+// default maximum widths of the columns, 0 for none; --truncate replaces
+// them and the table layout fits the columns to the terminal.
+func (p *TablePresenter) createTable(buf *strings.Builder, headers []string, limits ...int) *layoutTable {
+	table := tablewriter.NewTable(buf, p.tableOptions...)
 	table.Header(headers)
-	return table
+	layout := CurrentTableLayout()
+	if p.noFit {
+		layout.Width, layout.Wrap = 0, false
+	}
+	return &layoutTable{
+		table:      table,
+		out:        buf,
+		headers:    headers,
+		limits:     limits,
+		layout:     layout,
+		accessible: Accessible() && !p.noFit,
+	}
 }
 
 // formatFieldWithTypeAndPrimary formats a value with optional type and primary markers.
@@ -50,6 +175,117 @@ func formatFieldWithTypeAndPrimary(value, fieldType string, primary bool) string
 	return result
 }
 
+// formatDeliveryReport summarizes a bounce or read receipt in one line.
+// It returns an empty string for reports that are neither.
+func formatDeliveryReport(report *mail.DeliveryReport) string {
+	if report == nil {
+		return ""
+	}
+	if report.IsBounce() {
+		var recipients []string
+		for _, rs := range report.FailedRecipients() {
+			if rs.Status != "" {
+				recipients = append(recipients, fmt.Sprintf("%s (%s)", rs.Recipient, rs.Status))
+			} else {
+				recipients = append(recipients, rs.Recipient)
+			}
+		}
+		return "Bounce: " + strings.Join(recipients, ", ")
+	}
+	if report.Type == mail.ReportDisposition {
+		label := "Disposition"
+		if report.IsReadReceipt() {
+			label = "Read receipt"
+		}
+		result := fmt.Sprintf("%s (%s)", label, report.DispositionType())
+		if report.FinalRecipient != "" {
+			result += " from " + report.FinalRecipient
+		}
+		return result
+	}
+	return ""
+}
+
+// invitationResponses names the answers to an invitation as shown.
+var invitationResponses = map[string]string{
+	mail.ResponseAccepted:    "accepted",
+	mail.ResponseDeclined:    "declined",
+	mail.ResponseTentative:   "tentative",
+	mail.ResponseNeedsAction: "not answered",
+}
+
+// invitationFields describes a meeting invitation as field and value
+// pairs, shown after the message's own fields. It returns nil for a
+// message without one.
+func invitationFields(inv *mail.Invitation) [][2]string {
+	if inv == nil {
+		return nil
+	}
+	title := inv.Summary
+	switch {
+	case inv.IsCancelled():
+		title += " (cancelled)"
+	case strings.EqualFold(inv.Method, mail.MethodReply):
+		title += " (reply)"
+	}
+	fields := [][2]string{{"Invitation", title}}
+
+	// Invitations usually give times in UTC; show them in local time
+	var when string
+	start, end := inv.Start.Local(), inv.End.Local()
+	switch {
+	case inv.AllDay:
+		when = CurrentLocale().Date(inv.Start) + " (All Day)"
+	case CurrentLocale().Date(start) == CurrentLocale().Date(end):
+		when = CurrentLocale().DateTime(start) + " - " + CurrentLocale().Time(end)
+	default:
+		when = CurrentLocale().DateTime(start) + " - " + CurrentLocale().DateTime(end)
+	}
+	switch {
+	case !inv.RecurrenceID.IsZero():
+		when += ", one occurrence"
+	case inv.Recurring:
+		when += ", repeats"
+	}
+	fields = append(fields, [2]string{"When", when})
+	if inv.Location != "" {
+		fields = append(fields, [2]string{"Where", inv.Location})
+	}
+	if !inv.Organizer.IsZero() {
+		fields = append(fields, [2]string{"Organizer", inv.Organizer.String()})
+	}
+	if len(inv.Attendees) > 0 {
+		counts := make(map[string]int)
+		for _, a := range inv.Attendees {
+			counts[a.Response]++
+		}
+		var parts []string
+		for _, response := range []string{mail.ResponseAccepted, mail.ResponseTentative, mail.ResponseDeclined, mail.ResponseNeedsAction} {
+			if counts[response] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[response], invitationResponses[response]))
+			}
+		}
+		fields = append(fields, [2]string{"Guests", fmt.Sprintf("%d (%s)", len(inv.Attendees), strings.Join(parts, ", "))})
+	}
+	if response, ok := invitationResponses[inv.Response]; ok && !inv.IsCancelled() {
+		fields = append(fields, [2]string{"RSVP", response})
+	}
+	return fields
+}
+
+// FormatTranslationLanguages describes a translation's languages, e.g.
+// "fr -> en". It returns an empty string for a nil translation.
+func FormatTranslationLanguages(t *mail.Translation) string {
+	if t == nil {
+		return ""
+	}
+	source := t.SourceLanguage
+	if source == "" {
+		source = "unknown"
+	}
+	return source + " -> " + t.TargetLanguage
+}
+
 // RenderMessage renders a single message as a table.
 func (p *TablePresenter) RenderMessage(msg *mail.Message) string {
 	if msg == nil {
@@ -57,22 +293,34 @@ func (p *TablePresenter) RenderMessage(msg *mail.Message) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
+	table.highlightColumns("Value")
 
 	_ = table.Append([]string{"ID", msg.ID})
 	_ = table.Append([]string{"Thread ID", msg.ThreadID})
-	_ = table.Append([]string{"From", msg.From})
-	_ = table.Append([]string{"To", strings.Join(msg.To, ", ")})
+	_ = table.Append([]string{"From", msg.From.String()})
+	_ = table.Append([]string{"To", mail.JoinAddresses(msg.To)})
 	if len(msg.Cc) > 0 {
-		_ = table.Append([]string{"Cc", strings.Join(msg.Cc, ", ")})
+		_ = table.Append([]string{"Cc", mail.JoinAddresses(msg.Cc)})
 	}
 	_ = table.Append([]string{"Subject", msg.Subject})
-	_ = table.Append([]string{"Date", msg.Date.Format("2006-01-02 15:04")})
+	_ = table.Append([]string{"Date", CurrentLocale().DateTime(msg.Date)})
 	_ = table.Append([]string{"Labels", strings.Join(msg.Labels, ", ")})
 	_ = table.Append([]string{"Read", fmt.Sprintf("%v", msg.IsRead)})
 	_ = table.Append([]string{"Starred", fmt.Sprintf("%v", msg.IsStarred)})
+	_ = table.Append([]string{"Important", fmt.Sprintf("%v", msg.IsImportant)})
 	if msg.Snippet != "" {
-		_ = table.Append([]string{"Snippet", truncate(msg.Snippet, 60)})
+		_ = table.Append([]string{"Snippet", longText(msg.Snippet)})
+	}
+	if report := formatDeliveryReport(msg.Report); report != "" {
+		_ = table.Append([]string{"Report", report})
+	}
+	for _, field := range invitationFields(msg.Invitation) {
+		_ = table.Append(field[:])
+	}
+	if msg.Translation != nil {
+		_ = table.Append([]string{"Language", FormatTranslationLanguages(msg.Translation)})
+		_ = table.Append([]string{"Translated Subject", msg.Translation.Subject})
 	}
 
 	_ = table.Render()
@@ -85,26 +333,53 @@ func (p *TablePresenter) RenderMessages(msgs []*mail.Message) string {
 		return "No messages found"
 	}
 
+	showImportance := ImportanceColumn()
+	headers, limits := messageColumns(showImportance)
+
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "From", "Subject", "Date", "Labels"})
+	table := p.createTable(&buf, headers, limits...)
+	table.highlightColumns(messageHighlightHeaders...)
 
 	for _, msg := range msgs {
 		if msg == nil {
 			continue
 		}
-		_ = table.Append([]string{
-			truncate(msg.ID, 12),
-			truncate(msg.From, 25),
-			truncate(msg.Subject, 40),
-			msg.Date.Format("2006-01-02"),
-			truncate(strings.Join(msg.Labels, ", "), 20),
-		})
+		_ = table.Append(messageRow(msg, showImportance))
 	}
 
 	_ = table.Render()
 	return buf.String()
 }
 
+// messageColumns returns the headers and default width limits of a message
+// list, with the importance column first when it is shown.
+func messageColumns(showImportance bool) ([]string, []int) {
+	var headers []string
+	var limits []int
+	for _, name := range MessageColumns() {
+		col := messageColumnSet[name]
+		headers = append(headers, col.header)
+		limits = append(limits, col.limit)
+	}
+	if showImportance {
+		headers = append([]string{"!"}, headers...)
+		limits = append([]int{0}, limits...)
+	}
+	return headers, limits
+}
+
+// messageRow returns the cells of msg in a message list.
+func messageRow(msg *mail.Message, showImportance bool) []string {
+	var row []string
+	for _, name := range MessageColumns() {
+		row = append(row, messageColumnSet[name].value(msg))
+	}
+	if showImportance {
+		row = append([]string{importanceMarker(msg)}, row...)
+	}
+	return row
+}
+
 // RenderDraft renders a single draft as a table.
 func (p *TablePresenter) RenderDraft(draft *mail.Draft) string {
 	if draft == nil {
@@ -112,15 +387,15 @@ func (p *TablePresenter) RenderDraft(draft *mail.Draft) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"Draft ID", draft.ID})
-	_ = table.Append([]string{"Created", draft.Created.Format("2006-01-02 15:04")})
-	_ = table.Append([]string{"Updated", draft.Updated.Format("2006-01-02 15:04")})
+	_ = table.Append([]string{"Created", CurrentLocale().DateTime(draft.Created)})
+	_ = table.Append([]string{"Updated", CurrentLocale().DateTime(draft.Updated)})
 
 	if draft.Message != nil {
 		_ = table.Append([]string{"Message ID", draft.Message.ID})
-		_ = table.Append([]string{"To", strings.Join(draft.Message.To, ", ")})
+		_ = table.Append([]string{"To", mail.JoinAddresses(draft.Message.To)})
 		_ = table.Append([]string{"Subject", draft.Message.Subject})
 	}
 
@@ -135,7 +410,7 @@ func (p *TablePresenter) RenderDrafts(drafts []*mail.Draft) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Subject", "To", "Updated"})
+	table := p.createTable(&buf, []string{"ID", "Subject", "To", "Updated"}, 12, 40, 25, 0)
 
 	for _, draft := range drafts {
 		if draft == nil {
@@ -145,13 +420,13 @@ func (p *TablePresenter) RenderDrafts(drafts []*mail.Draft) string {
 		to := ""
 		if draft.Message != nil {
 			subject = draft.Message.Subject
-			to = strings.Join(draft.Message.To, ", ")
+			to = mail.JoinAddresses(draft.Message.To)
 		}
 		_ = table.Append([]string{
-			truncate(draft.ID, 12),
-			truncate(subject, 40),
-			truncate(to, 25),
-			draft.Updated.Format("2006-01-02"),
+			draft.ID,
+			subject,
+			to,
+			CurrentLocale().Date(draft.Updated),
 		})
 	}
 
@@ -168,28 +443,30 @@ func (p *TablePresenter) RenderThread(thread *mail.Thread) string {
 	var buf strings.Builder
 
 	// Thread info table
-	infoTable := createTable(&buf, []string{"Field", "Value"})
+	infoTable := p.createTable(&buf, []string{"Field", "Value"})
+	infoTable.highlightColumns("Value")
 	_ = infoTable.Append([]string{"Thread ID", thread.ID})
 	_ = infoTable.Append([]string{"Message Count", fmt.Sprintf("%d", thread.MessageCount())})
 	_ = infoTable.Append([]string{"Labels", strings.Join(thread.Labels, ", ")})
 	if thread.Snippet != "" {
-		_ = infoTable.Append([]string{"Snippet", truncate(thread.Snippet, 60)})
+		_ = infoTable.Append([]string{"Snippet", longText(thread.Snippet)})
 	}
 	_ = infoTable.Render()
 
 	// Messages table if present
 	if len(thread.Messages) > 0 {
 		buf.WriteString("\nMessages:\n")
-		msgTable := createTable(&buf, []string{"ID", "From", "Subject", "Date"})
+		msgTable := p.createTable(&buf, []string{"ID", "From", "Subject", "Date"}, 12, 25, 40, 0)
+		msgTable.highlightColumns("From", "Subject")
 		for _, msg := range thread.Messages {
 			if msg == nil {
 				continue
 			}
 			_ = msgTable.Append([]string{
-				truncate(msg.ID, 12),
-				truncate(msg.From, 25),
-				truncate(msg.Subject, 40),
-				msg.Date.Format("2006-01-02"),
+				msg.ID,
+				msg.From.String(),
+				msg.Subject,
+				listDate(msg.Date),
 			})
 		}
 		_ = msgTable.Render()
@@ -205,17 +482,37 @@ func (p *TablePresenter) RenderThreads(threads []*mail.Thread) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Messages", "Snippet", "Labels"})
+	if threadsGrouped(threads) {
+		table := p.createTable(&buf, []string{"#", "ID", "Messages", "Unread", "Date", "Snippet"}, 0, 12, 0, 0, 0, 40)
+		table.highlightColumns("Snippet")
+		for i, thread := range threads {
+			if thread == nil {
+				continue
+			}
+			_ = table.Append([]string{
+				fmt.Sprintf("%d", i+1),
+				thread.ID,
+				fmt.Sprintf("%d", thread.MessageCount()),
+				fmt.Sprintf("%d", thread.UnreadCount()),
+				listDate(thread.LatestMessage().Date),
+				thread.Snippet,
+			})
+		}
+		_ = table.Render()
+		return buf.String()
+	}
 
+	table := p.createTable(&buf, []string{"ID", "Messages", "Snippet", "Labels"}, 12, 0, 40, 20)
+	table.highlightColumns("Snippet")
 	for _, thread := range threads {
 		if thread == nil {
 			continue
 		}
 		_ = table.Append([]string{
-			truncate(thread.ID, 12),
+			thread.ID,
 			fmt.Sprintf("%d", thread.MessageCount()),
-			truncate(thread.Snippet, 40),
-			truncate(strings.Join(thread.Labels, ", "), 20),
+			thread.Snippet,
+			strings.Join(thread.Labels, ", "),
 		})
 	}
 
@@ -230,7 +527,7 @@ func (p *TablePresenter) RenderLabel(label *mail.Label) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", label.ID})
 	_ = table.Append([]string{"Name", label.Name})
@@ -257,7 +554,7 @@ func (p *TablePresenter) RenderLabels(labels []*mail.Label) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Name", "Type"})
+	table := p.createTable(&buf, []string{"ID", "Name", "Type"})
 
 	for _, label := range labels {
 		if label == nil {
@@ -281,22 +578,22 @@ func (p *TablePresenter) RenderEvent(event *calendar.Event) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", event.ID})
 	_ = table.Append([]string{"Title", event.Title})
 	if event.Description != "" {
-		_ = table.Append([]string{"Description", truncate(event.Description, 60)})
+		_ = table.Append([]string{"Description", longText(event.Description)})
 	}
 	if event.Location != "" {
 		_ = table.Append([]string{"Location", event.Location})
 	}
 
 	if event.AllDay {
-		_ = table.Append([]string{"Date", event.Start.Format("2006-01-02") + " (All Day)"})
+		_ = table.Append([]string{"Date", CurrentLocale().Date(event.Start) + " (All Day)"})
 	} else {
-		_ = table.Append([]string{"Start", event.Start.Format("2006-01-02 15:04")})
-		_ = table.Append([]string{"End", event.End.Format("2006-01-02 15:04")})
+		_ = table.Append([]string{"Start", CurrentLocale().DateTime(event.Start)})
+		_ = table.Append([]string{"End", CurrentLocale().DateTime(event.End)})
 	}
 
 	_ = table.Append([]string{"Status", event.Status})
@@ -306,6 +603,9 @@ func (p *TablePresenter) RenderEvent(event *calendar.Event) string {
 	if event.HasConference() {
 		_ = table.Append([]string{"Conference", event.ConferenceData.URI})
 	}
+	if len(event.Attachments) > 0 {
+		_ = table.Append([]string{"Attachments", longText(strings.Join(event.AttachmentNames(), ", "))})
+	}
 
 	_ = table.Render()
 	return buf.String()
@@ -318,24 +618,24 @@ func (p *TablePresenter) RenderEvents(events []*calendar.Event) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Title", "Start", "End", "Location"})
+	table := p.createTable(&buf, []string{"ID", "Title", "Start", "End", "Location"}, 12, 30, 0, 0, 20)
 
 	for _, event := range events {
 		if event == nil {
 			continue
 		}
-		startStr := event.Start.Format("2006-01-02 15:04")
-		endStr := event.End.Format("2006-01-02 15:04")
+		startStr := CurrentLocale().DateTime(event.Start)
+		endStr := CurrentLocale().DateTime(event.End)
 		if event.AllDay {
-			startStr = event.Start.Format("2006-01-02")
+			startStr = CurrentLocale().Date(event.Start)
 			endStr = "(All Day)"
 		}
 		_ = table.Append([]string{
-			truncate(event.ID, 12),
-			truncate(event.Title, 30),
+			event.ID,
+			event.Title,
 			startStr,
 			endStr,
-			truncate(event.Location, 20),
+			event.Location,
 		})
 	}
 
@@ -350,12 +650,12 @@ func (p *TablePresenter) RenderCalendar(cal *calendar.Calendar) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", cal.ID})
 	_ = table.Append([]string{"Title", cal.Title})
 	if cal.Description != "" {
-		_ = table.Append([]string{"Description", truncate(cal.Description, 60)})
+		_ = table.Append([]string{"Description", longText(cal.Description)})
 	}
 	if cal.TimeZone != "" {
 		_ = table.Append([]string{"Time Zone", cal.TimeZone})
@@ -374,7 +674,7 @@ func (p *TablePresenter) RenderCalendars(cals []*calendar.Calendar) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Title", "Primary", "Access Role", "Time Zone"})
+	table := p.createTable(&buf, []string{"ID", "Title", "Primary", "Access Role", "Time Zone"}, 20, 25)
 
 	for _, cal := range cals {
 		if cal == nil {
@@ -385,8 +685,8 @@ func (p *TablePresenter) RenderCalendars(cals []*calendar.Calendar) string {
 			primary = "Yes"
 		}
 		_ = table.Append([]string{
-			truncate(cal.ID, 20),
-			truncate(cal.Title, 25),
+			cal.ID,
+			cal.Title,
 			primary,
 			cal.AccessRole,
 			cal.TimeZone,
@@ -404,7 +704,7 @@ func (p *TablePresenter) RenderACLRule(rule *calendar.ACLRule) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", rule.ID})
 	_ = table.Append([]string{"Role", rule.Role})
@@ -426,7 +726,7 @@ func (p *TablePresenter) RenderACLRules(rules []*calendar.ACLRule) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Role", "Scope Type", "Scope Value"})
+	table := p.createTable(&buf, []string{"ID", "Role", "Scope Type", "Scope Value"}, 30, 0, 0, 30)
 
 	for _, rule := range rules {
 		if rule == nil {
@@ -439,10 +739,10 @@ func (p *TablePresenter) RenderACLRules(rules []*calendar.ACLRule) string {
 			scopeValue = rule.Scope.Value
 		}
 		_ = table.Append([]string{
-			truncate(rule.ID, 30),
+			rule.ID,
 			rule.Role,
 			scopeType,
-			truncate(scopeValue, 30),
+			scopeValue,
 		})
 	}
 
@@ -457,15 +757,15 @@ func (p *TablePresenter) RenderAccount(acct *account.Account) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"Alias", acct.Alias})
 	_ = table.Append([]string{"Email", acct.Email})
 	_ = table.Append([]string{"Default", fmt.Sprintf("%v", acct.IsDefault)})
 	_ = table.Append([]string{"Scopes", fmt.Sprintf("%d", len(acct.Scopes))})
-	_ = table.Append([]string{"Added", acct.Added.Format("2006-01-02")})
+	_ = table.Append([]string{"Added", CurrentLocale().Date(acct.Added)})
 	if !acct.LastUsed.IsZero() {
-		_ = table.Append([]string{"Last Used", acct.LastUsed.Format("2006-01-02")})
+		_ = table.Append([]string{"Last Used", CurrentLocale().Date(acct.LastUsed)})
 	}
 
 	if len(acct.Scopes) > 0 {
@@ -483,7 +783,7 @@ func (p *TablePresenter) RenderAccounts(accts []*account.Account) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Alias", "Email", "Default", "Scopes"})
+	table := p.createTable(&buf, []string{"Alias", "Email", "Default", "Scopes"})
 
 	for _, acct := range accts {
 		if acct == nil {
@@ -512,11 +812,11 @@ func (p *TablePresenter) RenderTaskList(taskList *domaintasks.TaskList) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", taskList.ID})
 	_ = table.Append([]string{"Title", taskList.Title})
-	_ = table.Append([]string{"Updated", taskList.Updated.Format("2006-01-02 15:04")})
+	_ = table.Append([]string{"Updated", CurrentLocale().DateTime(taskList.Updated)})
 
 	_ = table.Render()
 	return buf.String()
@@ -529,16 +829,16 @@ func (p *TablePresenter) RenderTaskLists(taskLists []*domaintasks.TaskList) stri
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Title", "Updated"})
+	table := p.createTable(&buf, []string{"ID", "Title", "Updated"}, 30, 40)
 
 	for _, tl := range taskLists {
 		if tl == nil {
 			continue
 		}
 		_ = table.Append([]string{
-			truncate(tl.ID, 30),
-			truncate(tl.Title, 40),
-			tl.Updated.Format("2006-01-02 15:04"),
+			tl.ID,
+			tl.Title,
+			CurrentLocale().DateTime(tl.Updated),
 		})
 	}
 
@@ -553,24 +853,24 @@ func (p *TablePresenter) RenderTask(task *domaintasks.Task) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ID", task.ID})
 	_ = table.Append([]string{"Title", task.Title})
 	_ = table.Append([]string{"Status", task.Status})
 	if task.Notes != "" {
-		_ = table.Append([]string{"Notes", truncate(task.Notes, 60)})
+		_ = table.Append([]string{"Notes", longText(task.Notes)})
 	}
 	if task.Due != nil {
-		_ = table.Append([]string{"Due", task.Due.Format("2006-01-02")})
+		_ = table.Append([]string{"Due", CurrentLocale().Date(*task.Due)})
 	}
 	if task.Completed != nil {
-		_ = table.Append([]string{"Completed", task.Completed.Format("2006-01-02 15:04")})
+		_ = table.Append([]string{"Completed", CurrentLocale().DateTime(*task.Completed)})
 	}
 	if task.Parent != nil {
 		_ = table.Append([]string{"Parent", *task.Parent})
 	}
-	_ = table.Append([]string{"Updated", task.Updated.Format("2006-01-02 15:04")})
+	_ = table.Append([]string{"Updated", CurrentLocale().DateTime(task.Updated)})
 
 	_ = table.Render()
 	return buf.String()
@@ -583,7 +883,7 @@ func (p *TablePresenter) RenderTasks(tasks []*domaintasks.Task) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ID", "Title", "Status", "Due", "Updated"})
+	table := p.createTable(&buf, []string{"ID", "Title", "Status", "Due", "Updated"}, 20, 40)
 
 	for _, task := range tasks {
 		if task == nil {
@@ -591,14 +891,14 @@ func (p *TablePresenter) RenderTasks(tasks []*domaintasks.Task) string {
 		}
 		dueStr := ""
 		if task.Due != nil {
-			dueStr = task.Due.Format("2006-01-02")
+			dueStr = CurrentLocale().Date(*task.Due)
 		}
 		_ = table.Append([]string{
-			truncate(task.ID, 20),
-			truncate(task.Title, 40),
+			task.ID,
+			task.Title,
 			task.Status,
 			dueStr,
-			task.Updated.Format("2006-01-02 15:04"),
+			CurrentLocale().DateTime(task.Updated),
 		})
 	}
 
@@ -613,7 +913,7 @@ func (p *TablePresenter) RenderContact(contact *domaincontacts.Contact) string {
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ResourceName", contact.ResourceName})
 	_ = table.Append([]string{"Name", contact.GetDisplayName()})
@@ -674,7 +974,7 @@ func (p *TablePresenter) RenderContact(contact *domaincontacts.Contact) string {
 	}
 
 	if len(contact.Biographies) > 0 && contact.Biographies[0].Value != "" {
-		_ = table.Append([]string{"Biography", truncate(contact.Biographies[0].Value, 60)})
+		_ = table.Append([]string{"Biography", longText(contact.Biographies[0].Value)})
 	}
 
 	if contact.ETag != "" {
@@ -692,7 +992,7 @@ func (p *TablePresenter) RenderContacts(contacts []*domaincontacts.Contact) stri
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ResourceName", "Name", "Email", "Phone"})
+	table := p.createTable(&buf, []string{"ResourceName", "Name", "Email", "Phone"}, 25, 30, 30, 20)
 
 	for _, c := range contacts {
 		if c == nil {
@@ -717,10 +1017,10 @@ func (p *TablePresenter) RenderContacts(contacts []*domaincontacts.Contact) stri
 		}
 
 		_ = table.Append([]string{
-			truncate(c.ResourceName, 25),
-			truncate(name, 30),
-			truncate(email, 30),
-			truncate(phone, 20),
+			c.ResourceName,
+			name,
+			email,
+			phone,
 		})
 	}
 
@@ -735,7 +1035,7 @@ func (p *TablePresenter) RenderContactGroup(group *domaincontacts.ContactGroup)
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"Field", "Value"})
+	table := p.createTable(&buf, []string{"Field", "Value"})
 
 	_ = table.Append([]string{"ResourceName", group.ResourceName})
 	_ = table.Append([]string{"Name", group.Name})
@@ -748,7 +1048,7 @@ func (p *TablePresenter) RenderContactGroup(group *domaincontacts.ContactGroup)
 		_ = table.Append([]string{"ETag", group.ETag})
 	}
 	if group.Metadata != nil && !group.Metadata.UpdateTime.IsZero() {
-		_ = table.Append([]string{"Updated", group.Metadata.UpdateTime.Format("2006-01-02 15:04")})
+		_ = table.Append([]string{"Updated", CurrentLocale().DateTime(group.Metadata.UpdateTime)})
 	}
 
 	_ = table.Render()
@@ -762,15 +1062,15 @@ func (p *TablePresenter) RenderContactGroups(groups []*domaincontacts.ContactGro
 	}
 
 	var buf strings.Builder
-	table := createTable(&buf, []string{"ResourceName", "Name", "Type", "MemberCount"})
+	table := p.createTable(&buf, []string{"ResourceName", "Name", "Type", "MemberCount"}, 30, 30)
 
 	for _, g := range groups {
 		if g == nil {
 			continue
 		}
 		_ = table.Append([]string{
-			truncate(g.ResourceName, 30),
-			truncate(g.Name, 30),
+			g.ResourceName,
+			g.Name,
 			g.GroupType,
 			fmt.Sprintf("%d", g.MemberCount),
 		})
@@ -785,6 +1085,9 @@ func (p *TablePresenter) RenderError(err error) string {
 	if err == nil {
 		return ""
 	}
+	if hint := ErrorHint(err); hint != "" {
+		return fmt.Sprintf("Error: %s\nHint: %s", err.Error(), hint)
+	}
 	return fmt.Sprintf("Error: %s", err.Error())
 }
 
@@ -80,6 +80,52 @@ func TestTablePresenter_RenderMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("renders bounce report", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "mailer-daemon@googlemail.com", "Delivery Status Notification (Failure)", "")
+		msg.Report = &mail.DeliveryReport{
+			Type: mail.ReportDeliveryStatus,
+			Recipients: []*mail.RecipientStatus{
+				{Recipient: "gone@example.com", Action: mail.ActionFailed, Status: "5.1.1"},
+			},
+		}
+
+		result := p.RenderMessage(msg)
+		if !strings.Contains(result, "Bounce: gone@example.com (5.1.1)") {
+			t.Errorf("Result should contain bounce summary, got:\n%s", result)
+		}
+	})
+
+	t.Run("renders translation", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "ami@example.fr", "Bonjour", "")
+		msg.Translation = &mail.Translation{TargetLanguage: "en", Subject: "Hello"}
+
+		result := p.RenderMessage(msg)
+		if !strings.Contains(result, "unknown -> en") || !strings.Contains(result, "Hello") {
+			t.Errorf("Result should contain translation rows, got:\n%s", result)
+		}
+	})
+
+	t.Run("renders invitation", func(t *testing.T) {
+		msg := mail.NewMessage("msg-1", "thread-1", "ana@example.com", "Invitation: Design review", "")
+		msg.Invitation = &mail.Invitation{
+			Method:    mail.MethodRequest,
+			Summary:   "Design review",
+			Start:     time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local),
+			End:       time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local),
+			Recurring: true,
+			Organizer: mail.Address{Name: "Ana", Email: "ana@example.com"},
+			Attendees: []*mail.InvitationAttendee{{Response: mail.ResponseAccepted}, {Response: mail.ResponseNeedsAction}},
+			Response:  mail.ResponseNeedsAction,
+		}
+
+		result := p.RenderMessage(msg)
+		for _, want := range []string{"Design review", "repeats", "Ana <ana@example.com>", "2 (1 accepted, 1 not answered)", "RSVP"} {
+			if !strings.Contains(result, want) {
+				t.Errorf("Result should contain %q, got:\n%s", want, result)
+			}
+		}
+	})
+
 	t.Run("renders nil message", func(t *testing.T) {
 		result := p.RenderMessage(nil)
 		if result != "No message found" {
@@ -112,6 +158,54 @@ func TestTablePresenter_RenderMessages(t *testing.T) {
 		}
 	})
 
+	t.Run("importance column", func(t *testing.T) {
+		t.Cleanup(func() { SetImportanceColumn(false) })
+		important := mail.NewMessage("msg-1", "t-1", "boss@example.com", "Urgent", "")
+		important.IsImportant = true
+		msgs := []*mail.Message{important, mail.NewMessage("msg-2", "t-2", "news@example.com", "Weekly", "")}
+
+		if result := p.RenderMessages(msgs); strings.Contains(result, "!") {
+			t.Errorf("importance column should be off by default:\n%s", result)
+		}
+
+		SetImportanceColumn(true)
+		lines := strings.Split(p.RenderMessages(msgs), "\n")
+		var boss, news string
+		for _, line := range lines {
+			if strings.Contains(line, "boss@example.com") {
+				boss = line
+			}
+			if strings.Contains(line, "news@example.com") {
+				news = line
+			}
+		}
+		if !strings.Contains(boss, "!") || strings.Contains(news, "!") {
+			t.Errorf("expected only the important message to be marked:\n%s", strings.Join(lines, "\n"))
+		}
+	})
+
+	t.Run("selected columns", func(t *testing.T) {
+		t.Cleanup(func() { _ = SetMessageColumns(nil) })
+		msg := mail.NewMessage("msg-1", "t-1", "Ana Lima <ana@example.com>", "Hello", "")
+		if err := SetMessageColumns([]string{"from_name", " From_Email", "subject"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		result := p.RenderMessages([]*mail.Message{msg})
+		header := strings.ToUpper(strings.Split(result, "\n")[1])
+		for _, want := range []string{"FROM NAME", "FROM EMAIL", "SUBJECT"} {
+			if !strings.Contains(header, want) {
+				t.Errorf("expected a %s column:\n%s", want, result)
+			}
+		}
+		if strings.Contains(header, "LABELS") || !strings.Contains(result, " Ana Lima ") || !strings.Contains(result, " ana@example.com ") {
+			t.Errorf("unexpected table:\n%s", result)
+		}
+
+		if err := SetMessageColumns([]string{"sender"}); err == nil || !strings.Contains(err.Error(), "unknown column") {
+			t.Errorf("expected unknown column error, got %v", err)
+		}
+	})
+
 	t.Run("renders empty list", func(t *testing.T) {
 		result := p.RenderMessages([]*mail.Message{})
 		if result != "No messages found" {
@@ -227,6 +321,25 @@ func TestTablePresenter_RenderThreads(t *testing.T) {
 		}
 	})
 
+	t.Run("renders grouped threads with positions and unread counts", func(t *testing.T) {
+		read := mail.NewMessage("m-1", "t-1", "a@example.com", "Hi", "")
+		read.IsRead = true
+		unread := mail.NewMessage("m-2", "t-1", "b@example.com", "Re: Hi", "")
+		unread.Snippet = "Latest reply"
+		threads := mail.GroupByThread([]*mail.Message{unread, read})
+
+		result := p.RenderThreads(threads)
+
+		for _, want := range []string{"#", "UNREAD", "t-1", "Latest reply"} {
+			if !strings.Contains(strings.ToUpper(result), strings.ToUpper(want)) {
+				t.Errorf("Result should contain %q:\n%s", want, result)
+			}
+		}
+		if strings.Contains(strings.ToUpper(result), "LABELS") {
+			t.Errorf("Grouped threads should not show the labels column:\n%s", result)
+		}
+	})
+
 	t.Run("renders empty list", func(t *testing.T) {
 		result := p.RenderThreads([]*mail.Thread{})
 		if result != "No threads found" {
@@ -524,6 +637,14 @@ func TestTablePresenter_RenderError(t *testing.T) {
 		}
 	})
 
+	t.Run("adds the hint of API errors", func(t *testing.T) {
+		result := p.RenderError(newDetailedTestError())
+		want := "Error: rate limited (status 403): slow down\nHint: pause between commands"
+		if result != want {
+			t.Errorf("Expected %q, got %q", want, result)
+		}
+	})
+
 	t.Run("renders nil error", func(t *testing.T) {
 		result := p.RenderError(nil)
 		if result != "" {
@@ -1,7 +1,16 @@
 // Package repository provides adapter implementations for domain repository interfaces.
 package repository
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
 
 // Common repository errors for API operations.
 // These errors are used across different repository implementations (Gmail, Calendar, etc.)
@@ -15,4 +24,188 @@ var (
 
 	// ErrTemporary is returned for temporary/transient errors that may be retried.
 	ErrTemporary = errors.New("temporary error")
+
+	// ErrUnauthorized is returned when Google rejects the account's credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrPermissionDenied is returned when the account may not perform the
+	// request, for example because a scope was not granted.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrQuotaExceeded is returned when a daily quota is used up. Unlike
+	// ErrRateLimited it is not worth retrying soon.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrReadOnly is returned for requests that would change data while
+	// read-only mode is on.
+	ErrReadOnly = errors.New("read-only mode")
 )
+
+// APIError is an error returned by a Google API. It wraps one of the
+// sentinel errors above (or a domain not-found error), so errors.Is keeps
+// working, and carries the HTTP status, Google's error reason and a hint on
+// how to fix the problem.
+type APIError struct {
+	// Service is the API that failed, e.g. "gmail".
+	Service string
+	// Status is the HTTP status code.
+	Status int
+	// Reason is Google's error reason, e.g. "userRateLimitExceeded".
+	Reason string
+	// Message is Google's error message.
+	Message string
+	// Hint suggests how to fix the problem; it may be empty.
+	Hint string
+	// Err is the sentinel error the API error maps to; it may be nil.
+	Err error
+	// RetryAfter is how long the server asked callers to wait before
+	// retrying, from the Retry-After header; zero if it did not say.
+	RetryAfter time.Duration
+}
+
+// Error returns the sentinel error text with the status and Google's message.
+func (e *APIError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (status %d): %s", e.Err, e.Status, e.Message)
+	}
+	service := e.Service
+	if service == "" {
+		service = "Google"
+	}
+	return fmt.Sprintf("%s API error (status %d): %s", service, e.Status, e.Message)
+}
+
+// Unwrap returns the sentinel error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus returns the HTTP status code.
+func (e *APIError) HTTPStatus() int {
+	return e.Status
+}
+
+// ErrorReason returns Google's error reason.
+func (e *APIError) ErrorReason() string {
+	return e.Reason
+}
+
+// RemediationHint returns the hint on how to fix the problem.
+func (e *APIError) RemediationHint() string {
+	return e.Hint
+}
+
+// Remediation hints shown with API errors.
+const (
+	hintRateLimit     = "Google's per-project request limit was hit; wait a minute and try again."
+	hintUserRateLimit = "This account is sending requests too quickly; pause between commands or fetch fewer results with --max-results."
+	hintQuota         = "The daily API quota is used up; try again tomorrow or raise the quota in the Google Cloud console."
+	hintScope         = "The account has not granted the access this command needs; run 'goog auth login' to grant it."
+	hintUnauthorized  = "The saved credentials were rejected; run 'goog auth login' to sign in again."
+	hintForbidden     = "The account cannot access this item; check it is owned by or shared with the account you are using (--account)."
+	hintNotFound      = "Check the ID; the item may have been deleted or belong to another account."
+	hintBadRequest    = "Google rejected the request; check the IDs, dates and other values passed to the command."
+	hintTemporary     = "Google had a temporary problem; try again shortly."
+)
+
+// newAPIError classifies a Google API error. notFound is the error wrapped
+// for 404 responses.
+func newAPIError(service string, status int, reason, message string, notFound error) *APIError {
+	e := &APIError{Service: service, Status: status, Reason: reason, Message: message}
+
+	// The reason distinguishes cases that share a status, such as the
+	// rate limits and missing scopes that are all 403s.
+	switch reason {
+	case "rateLimitExceeded", "RATE_LIMIT_EXCEEDED":
+		e.Err, e.Hint = ErrRateLimited, hintRateLimit
+		return e
+	case "userRateLimitExceeded":
+		e.Err, e.Hint = ErrRateLimited, hintUserRateLimit
+		return e
+	case "dailyLimitExceeded", "quotaExceeded", "RESOURCE_EXHAUSTED":
+		e.Err, e.Hint = ErrQuotaExceeded, hintQuota
+		return e
+	case "insufficientPermissions", "ACCESS_TOKEN_SCOPE_INSUFFICIENT":
+		e.Err, e.Hint = ErrPermissionDenied, hintScope
+		return e
+	case "backendError", "internalError":
+		e.Err, e.Hint = ErrTemporary, hintTemporary
+		return e
+	}
+
+	switch status {
+	case http.StatusBadRequest:
+		e.Err, e.Hint = ErrBadRequest, hintBadRequest
+	case http.StatusUnauthorized:
+		e.Err, e.Hint = ErrUnauthorized, hintUnauthorized
+	case http.StatusForbidden:
+		e.Err, e.Hint = ErrPermissionDenied, hintForbidden
+	case http.StatusNotFound:
+		e.Err, e.Hint = notFound, hintNotFound
+	case http.StatusTooManyRequests:
+		e.Err, e.Hint = ErrRateLimited, hintRateLimit
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		e.Err, e.Hint = ErrTemporary, hintTemporary
+	}
+	return e
+}
+
+// apiErrorFrom classifies a googleapi.Error, keeping its Retry-After delay.
+func apiErrorFrom(service string, apiErr *googleapi.Error, notFound error) *APIError {
+	e := newAPIError(service, apiErr.Code, googleErrorReason(apiErr), apiErr.Message, notFound)
+	e.RetryAfter = parseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now())
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header, given either as seconds or
+// as an HTTP date. It returns zero for a missing, invalid or past value.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// retryAfter returns how long the server asked callers to wait before
+// retrying err, or zero.
+func retryAfter(err error) time.Duration {
+	var mapped *APIError
+	if errors.As(err, &mapped) {
+		return mapped.RetryAfter
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return parseRetryAfter(apiErr.Header.Get("Retry-After"), time.Now())
+	}
+	return 0
+}
+
+// googleErrorReason returns the reason of a Google API error, from the
+// legacy error list or from a google.rpc.ErrorInfo detail.
+func googleErrorReason(apiErr *googleapi.Error) string {
+	for _, item := range apiErr.Errors {
+		if item.Reason != "" {
+			return item.Reason
+		}
+	}
+	for _, detail := range apiErr.Details {
+		info, ok := detail.(map[string]interface{})
+		if !ok || info["@type"] != "type.googleapis.com/google.rpc.ErrorInfo" {
+			continue
+		}
+		if reason, ok := info["reason"].(string); ok {
+			return reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		reason   string
+		wantErr  error
+		wantHint string
+	}{
+		{"403 project rate limit", http.StatusForbidden, "rateLimitExceeded", ErrRateLimited, hintRateLimit},
+		{"403 user rate limit", http.StatusForbidden, "userRateLimitExceeded", ErrRateLimited, hintUserRateLimit},
+		{"403 daily limit", http.StatusForbidden, "dailyLimitExceeded", ErrQuotaExceeded, hintQuota},
+		{"403 missing scope", http.StatusForbidden, "insufficientPermissions", ErrPermissionDenied, hintScope},
+		{"403 ErrorInfo scope", http.StatusForbidden, "ACCESS_TOKEN_SCOPE_INSUFFICIENT", ErrPermissionDenied, hintScope},
+		{"403 other", http.StatusForbidden, "forbidden", ErrPermissionDenied, hintForbidden},
+		{"401", http.StatusUnauthorized, "authError", ErrUnauthorized, hintUnauthorized},
+		{"400", http.StatusBadRequest, "invalid", ErrBadRequest, hintBadRequest},
+		{"404", http.StatusNotFound, "notFound", mail.ErrMessageNotFound, hintNotFound},
+		{"429", http.StatusTooManyRequests, "", ErrRateLimited, hintRateLimit},
+		{"500 backend", http.StatusInternalServerError, "backendError", ErrTemporary, hintTemporary},
+		{"503", http.StatusServiceUnavailable, "", ErrTemporary, hintTemporary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError("gmail", tt.status, tt.reason, "details", mail.ErrMessageNotFound)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantErr)
+			}
+			if err.Hint != tt.wantHint {
+				t.Errorf("Hint = %q, want %q", err.Hint, tt.wantHint)
+			}
+			if err.HTTPStatus() != tt.status || err.ErrorReason() != tt.reason || err.RemediationHint() != tt.wantHint {
+				t.Errorf("unexpected accessors: %d %q %q", err.HTTPStatus(), err.ErrorReason(), err.RemediationHint())
+			}
+			if want := fmt.Sprintf("%s (status %d): details", tt.wantErr, tt.status); err.Error() != want {
+				t.Errorf("Error() = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+
+	t.Run("unknown status", func(t *testing.T) {
+		err := newAPIError("", http.StatusTeapot, "", "short and stout", nil)
+		if err.Err != nil || err.Hint != "" {
+			t.Errorf("expected no sentinel or hint, got %v %q", err.Err, err.Hint)
+		}
+		if err.Error() != "Google API error (status 418): short and stout" {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+}
+
+func TestGoogleErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *googleapi.Error
+		want string
+	}{
+		{"none", &googleapi.Error{Code: 500}, ""},
+		{"error list", &googleapi.Error{Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, "userRateLimitExceeded"},
+		{"error info", &googleapi.Error{Details: []interface{}{
+			map[string]interface{}{"@type": "type.googleapis.com/google.rpc.Help"},
+			map[string]interface{}{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "RATE_LIMIT_EXCEEDED"},
+		}}, "RATE_LIMIT_EXCEEDED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := googleErrorReason(tt.err); got != tt.want {
+				t.Errorf("googleErrorReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGmailRepository_HandleErrorReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    http.StatusForbidden,
+				"message": "Request had insufficient authentication scopes.",
+				"errors":  []map[string]string{{"reason": "insufficientPermissions", "message": "Insufficient Permission"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := gmail.NewService(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	repo := &GmailRepository{service: service, userID: "me"}
+
+	_, err = repo.Get(ctx, "msg123")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Service != "gmail" || apiErr.Status != http.StatusForbidden || apiErr.Reason != "insufficientPermissions" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrPermissionDenied) || !strings.Contains(apiErr.Hint, "goog auth login") {
+		t.Errorf("expected permission error with login hint, got %v (%q)", err, apiErr.Hint)
+	}
+}
+
+func TestMapAPIError_Typed(t *testing.T) {
+	err := mapAPIError(&googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Quota exceeded",
+		Errors:  []googleapi.ErrorItem{{Reason: "quotaExceeded"}},
+	}, "event")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected quota APIError, got %T: %v", err, err)
+	}
+	if isRetryableError(err) {
+		t.Error("quota errors should not be retried")
+	}
+
+	// Statuses without a mapping pass the original error through
+	orig := &googleapi.Error{Code: http.StatusConflict, Message: "conflict"}
+	if got := mapAPIError(orig, "event"); got != orig {
+		t.Errorf("expected passthrough, got %v", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"padded seconds", " 2 ", 2 * time.Second},
+		{"zero", "0", 0},
+		{"negative", "-3", 0},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"invalid", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableError_GoogleReasons(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"403 rate limit", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, true},
+		{"403 user rate limit", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, true},
+		{"403 forbidden", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, false},
+		{"403 daily limit", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "dailyLimitExceeded"}}}, false},
+		{"429", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"503", fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}), true},
+		{"404", &googleapi.Error{Code: http.StatusNotFound}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApiErrorFrom_RetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "7")
+	err := apiErrorFrom("gmail", &googleapi.Error{Code: http.StatusTooManyRequests, Header: header}, nil)
+	if err.RetryAfter != 7*time.Second {
+		t.Errorf("RetryAfter = %v, want 7s", err.RetryAfter)
+	}
+	if got := retryAfter(fmt.Errorf("wrapped: %w", err)); got != 7*time.Second {
+		t.Errorf("retryAfter() = %v, want 7s", got)
+	}
+}
+
+func TestRetryWithBackoff_RetryAfter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("waits as asked", func(t *testing.T) {
+		attempts := 0
+		start := time.Now()
+		got, err := retryWithBackoff(ctx, 3, time.Millisecond, func() (string, error) {
+			attempts++
+			if attempts == 1 {
+				return "", &APIError{Status: http.StatusTooManyRequests, Err: ErrRateLimited, RetryAfter: time.Second}
+			}
+			return "ok", nil
+		})
+		if err != nil || got != "ok" || attempts != 2 {
+			t.Fatalf("got %q, %v after %d attempts", got, err, attempts)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+		}
+	})
+
+	t.Run("gives up on long waits", func(t *testing.T) {
+		attempts := 0
+		_, err := retryWithBackoff(ctx, 3, time.Millisecond, func() (string, error) {
+			attempts++
+			return "", &APIError{Status: http.StatusTooManyRequests, Err: ErrRateLimited, RetryAfter: time.Hour}
+		})
+		if attempts != 1 || !errors.Is(err, ErrRateLimited) {
+			t.Errorf("expected one attempt returning the rate limit error, got %d attempts: %v", attempts, err)
+		}
+	})
+}
+
+func TestGmailRepository_RetriesRateLimitReason(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/json")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]interface{}{
+					"code":    http.StatusForbidden,
+					"message": "User-rate limit exceeded.",
+					"errors":  []map[string]string{{"reason": "userRateLimitExceeded"}},
+				},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg123", "threadId": "t1"})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := gmail.NewService(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	repo := &GmailRepository{service: service, userID: "me", maxRetries: 3, baseBackoff: time.Millisecond}
+
+	msg, err := repo.Get(ctx, "msg123")
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if msg.ID != "msg123" || attempts != 2 {
+		t.Errorf("got message %q after %d attempts, want msg123 after 2", msg.ID, attempts)
+	}
+}
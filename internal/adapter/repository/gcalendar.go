@@ -12,7 +12,6 @@ import (
 	"golang.org/x/oauth2"
 	gcal "google.golang.org/api/calendar/v3"
 	"google.golang.org/api/googleapi"
-	"google.golang.org/api/option"
 
 	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
 )
@@ -29,8 +28,7 @@ type GCalService struct {
 // NewGCalService creates a new GCalService with the given OAuth2 token source.
 // The token source is used to authenticate requests to the Google Calendar API.
 func NewGCalService(ctx context.Context, tokenSource oauth2.TokenSource) (*GCalService, error) {
-	httpClient := oauth2.NewClient(ctx, tokenSource)
-	service, err := gcal.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err := gcal.NewService(ctx, clientOptions(ctx, tokenSource, ServiceCalendar)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create calendar service: %w", err)
 	}
@@ -131,7 +129,7 @@ func (r *GCalEventRepository) Create(ctx context.Context, calendarID string, eve
 		return nil, ErrInvalidCalendarRequest
 	}
 
-	created, err := r.service.Events.Insert(calendarID, gcalEvent).Context(ctx).Do()
+	created, err := r.service.Events.Insert(calendarID, gcalEvent).SupportsAttachments(true).Context(ctx).Do()
 	if err != nil {
 		return nil, mapAPIError(err, "event")
 	}
@@ -150,7 +148,7 @@ func (r *GCalEventRepository) Update(ctx context.Context, calendarID string, eve
 		return nil, ErrInvalidCalendarRequest
 	}
 
-	updated, err := r.service.Events.Update(calendarID, event.ID, gcalEvent).Context(ctx).Do()
+	updated, err := r.service.Events.Update(calendarID, event.ID, gcalEvent).SupportsAttachments(true).Context(ctx).Do()
 	if err != nil {
 		return nil, mapAPIError(err, "event")
 	}
@@ -228,6 +226,42 @@ func (r *GCalEventRepository) Instances(ctx context.Context, calendarID, eventID
 	return events, nil
 }
 
+// Sync lists the events of a calendar changed since syncToken, including
+// deleted ones, with the token for the next sync. Without a token, every
+// event is listed. Recurring events are listed as series, with the
+// instances that differ from their series listed on their own. A token
+// the API no longer accepts returns calendar.ErrSyncTokenExpired.
+func (r *GCalEventRepository) Sync(ctx context.Context, calendarID, syncToken string) (*calendar.EventChanges, error) {
+	call := r.service.Events.List(calendarID).Context(ctx).MaxResults(2500)
+	changes := &calendar.EventChanges{Full: syncToken == ""}
+	if syncToken != "" {
+		call = call.SyncToken(syncToken)
+	}
+
+	err := call.Pages(ctx, func(page *gcal.Events) error {
+		for _, item := range page.Items {
+			event := gcalEventToDomain(item)
+			if event != nil {
+				event.CalendarID = calendarID
+				changes.Events = append(changes.Events, event)
+			}
+		}
+		if page.NextSyncToken != "" {
+			changes.SyncToken = page.NextSyncToken
+		}
+		return nil
+	})
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusGone {
+			return nil, calendar.ErrSyncTokenExpired
+		}
+		return nil, mapAPIError(err, "event")
+	}
+
+	return changes, nil
+}
+
 // RSVP updates the current user's response to an event.
 func (r *GCalEventRepository) RSVP(ctx context.Context, calendarID, eventID, response string) error {
 	if !calendar.IsValidResponseStatus(response) {
@@ -554,22 +588,26 @@ func gcalEventToDomain(event *gcal.Event) *calendar.Event {
 	// Created and updated are metadata fields - use zero time on parse failure
 	created, _ := time.Parse(time.RFC3339, event.Created)
 	updated, _ := time.Parse(time.RFC3339, event.Updated)
+	originalStart, _, _ := parseEventDateTime(event.OriginalStartTime)
 
 	domainEvent := &calendar.Event{
-		ID:          event.Id,
-		Title:       event.Summary,
-		Description: event.Description,
-		Location:    event.Location,
-		Start:       start,
-		End:         end,
-		AllDay:      allDay,
-		Recurrence:  parseRecurrence(event.Recurrence),
-		Status:      event.Status,
-		Visibility:  event.Visibility,
-		ColorID:     event.ColorId,
-		Created:     created,
-		Updated:     updated,
-		HTMLLink:    event.HtmlLink,
+		ID:               event.Id,
+		ICalUID:          event.ICalUID,
+		Title:            event.Summary,
+		Description:      event.Description,
+		Location:         event.Location,
+		Start:            start,
+		End:              end,
+		AllDay:           allDay,
+		Recurrence:       parseRecurrence(event.Recurrence),
+		Status:           event.Status,
+		RecurringEventID: event.RecurringEventId,
+		OriginalStart:    originalStart,
+		Visibility:       event.Visibility,
+		ColorID:          event.ColorId,
+		Created:          created,
+		Updated:          updated,
+		HTMLLink:         event.HtmlLink,
 	}
 
 	// Convert attendees
@@ -628,6 +666,16 @@ func gcalEventToDomain(event *gcal.Event) *calendar.Event {
 		}
 	}
 
+	// Convert attachments
+	for _, a := range event.Attachments {
+		domainEvent.Attachments = append(domainEvent.Attachments, &calendar.Attachment{
+			FileURL:  a.FileUrl,
+			FileID:   a.FileId,
+			Title:    a.Title,
+			MimeType: a.MimeType,
+		})
+	}
+
 	return domainEvent
 }
 
@@ -639,6 +687,7 @@ func domainEventToGcal(event *calendar.Event) *gcal.Event {
 
 	gcalEvent := &gcal.Event{
 		Id:          event.ID,
+		ICalUID:     event.ICalUID,
 		Summary:     event.Title,
 		Description: event.Description,
 		Location:    event.Location,
@@ -693,6 +742,15 @@ func domainEventToGcal(event *calendar.Event) *gcal.Event {
 		}
 	}
 
+	// Convert attachments; requests that send them must set supportsAttachments
+	for _, a := range event.Attachments {
+		gcalEvent.Attachments = append(gcalEvent.Attachments, &gcal.EventAttachment{
+			FileUrl:  a.FileURL,
+			Title:    a.Title,
+			MimeType: a.MimeType,
+		})
+	}
+
 	return gcalEvent
 }
 
@@ -795,14 +853,9 @@ func mapAPIError(err error, resource string) error {
 			if containsTimeRangeError(apiErr.Message) {
 				return calendar.ErrInvalidTimeRange
 			}
-			return fmt.Errorf("%w: %s", ErrBadRequest, apiErr.Message)
-		case http.StatusTooManyRequests:
-			return fmt.Errorf("%w: %s", ErrRateLimited, apiErr.Message)
-		case http.StatusInternalServerError,
-			http.StatusBadGateway,
-			http.StatusServiceUnavailable,
-			http.StatusGatewayTimeout:
-			return fmt.Errorf("%w: %s", ErrTemporary, apiErr.Message)
+		}
+		if mapped := apiErrorFrom("", apiErr, nil); mapped.Err != nil {
+			return mapped
 		}
 	}
 
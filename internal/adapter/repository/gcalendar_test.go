@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 	"time"
@@ -229,6 +230,27 @@ func TestGcalEventToDomain(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "recurring event instance",
+			input: &gcal.Event{
+				Id:               "recurring123_20250616T090000Z",
+				Summary:          "Weekly Standup",
+				RecurringEventId: "recurring123",
+				Start: &gcal.EventDateTime{
+					DateTime: "2025-06-16T09:00:00Z",
+				},
+				End: &gcal.EventDateTime{
+					DateTime: "2025-06-16T09:30:00Z",
+				},
+			},
+			want: &calendar.Event{
+				ID:               "recurring123_20250616T090000Z",
+				Title:            "Weekly Standup",
+				RecurringEventID: "recurring123",
+				Start:            time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC),
+				End:              time.Date(2025, 6, 16, 9, 30, 0, 0, time.UTC),
+			},
+		},
 		{
 			name: "event with reminders",
 			input: &gcal.Event{
@@ -334,6 +356,9 @@ func TestGcalEventToDomain(t *testing.T) {
 			if got.AllDay != tt.want.AllDay {
 				t.Errorf("AllDay = %v, want %v", got.AllDay, tt.want.AllDay)
 			}
+			if got.RecurringEventID != tt.want.RecurringEventID {
+				t.Errorf("RecurringEventID = %v, want %v", got.RecurringEventID, tt.want.RecurringEventID)
+			}
 			if got.Status != tt.want.Status {
 				t.Errorf("Status = %v, want %v", got.Status, tt.want.Status)
 			}
@@ -1642,6 +1667,59 @@ func TestGCalEventRepository_InstancesWithTestServer(t *testing.T) {
 	}
 }
 
+// TestGCalEventRepository_SyncWithTestServer tests full and incremental syncs.
+func TestGCalEventRepository_SyncWithTestServer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	now := time.Now().Truncate(time.Second)
+	ts.EventListHandler = func(w http.ResponseWriter, r *http.Request, calendarID string) {
+		switch r.URL.Query().Get("syncToken") {
+		case "":
+			resp := MockEventListResponse([]*gcal.Event{
+				MockEventResponse("ev1", "Standup", "", now, now.Add(15*time.Minute)),
+			}, "")
+			resp.NextSyncToken = "token-1"
+			WriteJSONResponse(w, resp)
+		case "token-1":
+			moved := MockEventResponse("series_20250615", "Weekly", "", now.Add(time.Hour), now.Add(2*time.Hour))
+			moved.RecurringEventId = "series"
+			moved.OriginalStartTime = &gcal.EventDateTime{DateTime: now.Format(time.RFC3339)}
+			resp := MockEventListResponse([]*gcal.Event{moved, {Id: "ev1", Status: "cancelled"}}, "")
+			resp.NextSyncToken = "token-2"
+			WriteJSONResponse(w, resp)
+		default:
+			WriteErrorResponse(w, http.StatusGone, "sync token is no longer valid")
+		}
+	}
+
+	repo := ts.GCalService(t).Events()
+	ctx := context.Background()
+
+	full, err := repo.Sync(ctx, "primary", "")
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if !full.Full || full.SyncToken != "token-1" || len(full.Events) != 1 {
+		t.Errorf("full sync = %+v", full)
+	}
+
+	changes, err := repo.Sync(ctx, "primary", "token-1")
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if changes.Full || changes.SyncToken != "token-2" || len(changes.Events) != 2 {
+		t.Fatalf("incremental sync = %+v", changes)
+	}
+	if !changes.Events[0].OriginalStart.Equal(now) || changes.Events[1].Status != calendar.StatusCancelled {
+		t.Errorf("events = %+v, %+v", changes.Events[0], changes.Events[1])
+	}
+
+	if _, err := repo.Sync(ctx, "primary", "stale"); !errors.Is(err, calendar.ErrSyncTokenExpired) {
+		t.Errorf("error = %v, want %v", err, calendar.ErrSyncTokenExpired)
+	}
+}
+
 // TestGCalEventRepository_UpdateNotFound tests updating a non-existent event.
 func TestGCalEventRepository_UpdateNotFound(t *testing.T) {
 	ts := NewTestServer()
@@ -2485,3 +2563,42 @@ func TestGCalEventRepository_RSVPNoSelfAttendee(t *testing.T) {
 		t.Fatalf("RSVP failed: %v", err)
 	}
 }
+
+func TestGCalEventRepository_Attachments(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	ts.EventCreateHandler = func(w http.ResponseWriter, r *http.Request, calendarID string) {
+		if r.URL.Query().Get("supportsAttachments") != "true" {
+			WriteErrorResponse(w, http.StatusBadRequest, "supportsAttachments not set")
+			return
+		}
+		var event gcal.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		for _, a := range event.Attachments {
+			a.FileId = "file123"
+			a.Title = "Agenda"
+			a.MimeType = "application/vnd.google-apps.document"
+		}
+		event.Id = "evt1"
+		WriteJSONResponse(w, &event)
+	}
+
+	created, err := ts.GCalService(t).Events().Create(context.Background(), "primary", &calendar.Event{
+		Title:       "Planning",
+		Attachments: []*calendar.Attachment{{FileURL: "https://drive.google.com/open?id=file123"}},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(created.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(created.Attachments))
+	}
+	got := created.Attachments[0]
+	if got.FileID != "file123" || got.Title != "Agenda" || got.FileURL != "https://drive.google.com/open?id=file123" {
+		t.Errorf("attachment = %+v", got)
+	}
+}
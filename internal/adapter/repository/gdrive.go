@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/drive/v3"
+)
+
+// googleAppsMimePrefix marks Google Docs, Sheets, Slides and other files
+// that have no binary content of their own and must be exported.
+const googleAppsMimePrefix = "application/vnd.google-apps."
+
+// exportMimeType is the format Google Workspace files are exported as.
+const exportMimeType = "application/pdf"
+
+// GDriveFileRepository downloads files from Google Drive, such as the files
+// attached to calendar events, and uploads files to share as links.
+type GDriveFileRepository struct {
+	service     *drive.Service
+	maxRetries  int
+	baseBackoff time.Duration
+}
+
+// NewGDriveFileRepository creates a new GDriveFileRepository with the given OAuth2 token source.
+func NewGDriveFileRepository(ctx context.Context, tokenSource oauth2.TokenSource) (*GDriveFileRepository, error) {
+	service, err := drive.NewService(ctx, clientOptions(ctx, tokenSource, ServiceDrive)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Drive service: %w", err)
+	}
+	return NewGDriveFileRepositoryWithService(service), nil
+}
+
+// NewGDriveFileRepositoryWithService creates a GDriveFileRepository with a pre-configured service.
+// This is useful for testing with mock servers.
+func NewGDriveFileRepositoryWithService(service *drive.Service) *GDriveFileRepository {
+	return &GDriveFileRepository{
+		service:     service,
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBaseBackoff,
+	}
+}
+
+// Download returns the name and content of a Drive file. Google Workspace
+// files are exported as PDF, and ".pdf" is added to their name.
+func (r *GDriveFileRepository) Download(ctx context.Context, fileID string) (string, []byte, error) {
+	file, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*drive.File, error) {
+		return r.service.Files.Get(fileID).Fields("name", "mimeType").SupportsAllDrives(true).Context(ctx).Do()
+	})
+	if err != nil {
+		return "", nil, mapAPIError(err, "file")
+	}
+
+	name := file.Name
+	data, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() ([]byte, error) {
+		if strings.HasPrefix(file.MimeType, googleAppsMimePrefix) {
+			return readDownload(r.service.Files.Export(fileID, exportMimeType).Context(ctx).Download())
+		}
+		return readDownload(r.service.Files.Get(fileID).SupportsAllDrives(true).Context(ctx).Download())
+	})
+	if err != nil {
+		return "", nil, mapAPIError(err, "file")
+	}
+	if strings.HasPrefix(file.MimeType, googleAppsMimePrefix) && !strings.HasSuffix(strings.ToLower(name), ".pdf") {
+		name += ".pdf"
+	}
+	return name, data, nil
+}
+
+// Upload creates a Drive file with content, shares it read-only with each
+// of readers without notifying them, and returns its link. The upload is
+// not retried, since a retry could create the file twice.
+func (r *GDriveFileRepository) Upload(ctx context.Context, name, mimeType string, content io.Reader, readers []string) (string, error) {
+	file, err := r.service.Files.Create(&drive.File{Name: name, MimeType: mimeType}).
+		Media(content).Fields("id", "webViewLink").Context(ctx).Do()
+	if err != nil {
+		return "", mapAPIError(err, "file")
+	}
+
+	for _, email := range readers {
+		_, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*drive.Permission, error) {
+			return r.service.Permissions.Create(file.Id, &drive.Permission{Type: "user", Role: "reader", EmailAddress: email}).
+				SendNotificationEmail(false).Fields("id").Context(ctx).Do()
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to share %s with %s: %w", name, email, mapAPIError(err, "file"))
+		}
+	}
+	return file.WebViewLink, nil
+}
+
+// readDownload reads and closes the body of a download response.
+func readDownload(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return io.ReadAll(resp.Body)
+}
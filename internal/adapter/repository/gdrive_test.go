@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
+)
+
+// newDriveTestRepository returns a GDriveFileRepository that talks to handler.
+func newDriveTestRepository(t *testing.T, handler http.HandlerFunc) *GDriveFileRepository {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	service, err := drive.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithoutAuthentication(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create Drive service: %v", err)
+	}
+	repo := NewGDriveFileRepositoryWithService(service)
+	repo.baseBackoff = 0
+	return repo
+}
+
+func TestGDriveFileRepository_Download(t *testing.T) {
+	files := map[string]*drive.File{
+		"pdf1": {Name: "agenda.pdf", MimeType: "application/pdf"},
+		"doc1": {Name: "Meeting notes", MimeType: "application/vnd.google-apps.document"},
+	}
+	repo := newDriveTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/files/")
+		id, export := strings.CutSuffix(id, "/export")
+		file, ok := files[id]
+		switch {
+		case !ok:
+			WriteErrorResponse(w, http.StatusNotFound, "File not found")
+		case export:
+			if got := r.URL.Query().Get("mimeType"); got != "application/pdf" {
+				t.Errorf("export mimeType = %q, want application/pdf", got)
+			}
+			_, _ = w.Write([]byte("exported " + id))
+		case r.URL.Query().Get("alt") == "media":
+			_, _ = w.Write([]byte("content " + id))
+		default:
+			WriteJSONResponse(w, file)
+		}
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		id       string
+		wantName string
+		wantData string
+	}{
+		{"pdf1", "agenda.pdf", "content pdf1"},
+		{"doc1", "Meeting notes.pdf", "exported doc1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			name, data, err := repo.Download(ctx, tt.id)
+			if err != nil {
+				t.Fatalf("Download failed: %v", err)
+			}
+			if name != tt.wantName || string(data) != tt.wantData {
+				t.Errorf("Download = %q, %q; want %q, %q", name, data, tt.wantName, tt.wantData)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, _, err := repo.Download(ctx, "nope"); err == nil || !strings.Contains(err.Error(), "file not found") {
+			t.Errorf("expected file not found, got %v", err)
+		}
+	})
+}
+
+func TestGDriveFileRepository_Upload(t *testing.T) {
+	var uploads int
+	var shared []string
+	repo := newDriveTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/upload/drive/v3/files"):
+			uploads++
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"name":"big.zip"`) || !strings.Contains(string(body), "zip content") {
+				t.Errorf("unexpected upload body:\n%s", body)
+			}
+			WriteJSONResponse(w, &drive.File{Id: "f1", WebViewLink: "https://drive.google.com/file/d/f1/view"})
+		case r.Method == http.MethodPost && r.URL.Path == "/files/f1/permissions":
+			if r.URL.Query().Get("sendNotificationEmail") != "false" {
+				t.Error("expected sharing without a notification")
+			}
+			var p drive.Permission
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Type != "user" || p.Role != "reader" {
+				t.Errorf("unexpected permission %+v", p)
+			}
+			shared = append(shared, p.EmailAddress)
+			WriteJSONResponse(w, &drive.Permission{Id: "p1"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			WriteErrorResponse(w, http.StatusNotFound, "not found")
+		}
+	})
+
+	link, err := repo.Upload(context.Background(), "big.zip", "application/zip", strings.NewReader("zip content"),
+		[]string{"ana@example.com", "lea@example.com"})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if link != "https://drive.google.com/file/d/f1/view" || uploads != 1 {
+		t.Errorf("link %q after %d upload(s)", link, uploads)
+	}
+	if strings.Join(shared, ",") != "ana@example.com,lea@example.com" {
+		t.Errorf("shared with %v", shared)
+	}
+}
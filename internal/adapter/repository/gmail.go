@@ -2,19 +2,27 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
 	"golang.org/x/oauth2"
+	"golang.org/x/text/encoding/htmlindex"
 	"google.golang.org/api/gmail/v1"
 	"google.golang.org/api/googleapi"
-	"google.golang.org/api/option"
 )
 
 // Repository errors are defined in errors.go to share across implementations.
@@ -26,9 +34,15 @@ const (
 	gmailLabelInbox     = "INBOX"
 	gmailLabelUnread    = "UNREAD"
 	gmailLabelStarred   = "STARRED"
+	gmailLabelImportant = "IMPORTANT"
 	gmailLabelTrash     = "TRASH"
 	gmailMessageFormat  = "full"
+	gmailRawFormat      = "raw"
 	gmailMetadataFormat = "metadata"
+
+	// gmailBatchModifyLimit is the most message IDs one batchModify
+	// request accepts.
+	gmailBatchModifyLimit = 1000
 )
 
 // GmailRepository implements MessageRepository using the Gmail API.
@@ -41,10 +55,11 @@ type GmailRepository struct {
 
 // Compile-time interface compliance checks.
 var (
-	_ mail.MessageRepository = (*GmailRepository)(nil)
-	_ mail.DraftRepository   = (*GmailDraftRepository)(nil)
-	_ mail.LabelRepository   = (*GmailLabelRepository)(nil)
-	_ mail.ThreadRepository  = (*GmailThreadRepository)(nil)
+	_ mail.MessageRepository    = (*GmailRepository)(nil)
+	_ mail.DraftRepository      = (*GmailDraftRepository)(nil)
+	_ mail.LabelRepository      = (*GmailLabelRepository)(nil)
+	_ mail.ThreadRepository     = (*GmailThreadRepository)(nil)
+	_ mail.AttachmentRepository = (*GmailAttachmentRepository)(nil)
 )
 
 // GmailDraftRepository wraps GmailRepository to implement DraftRepository.
@@ -77,11 +92,19 @@ func NewGmailThreadRepository(repo *GmailRepository) *GmailThreadRepository {
 	return &GmailThreadRepository{GmailRepository: repo}
 }
 
+// GmailAttachmentRepository wraps GmailRepository to implement AttachmentRepository.
+type GmailAttachmentRepository struct {
+	*GmailRepository
+}
+
+// NewGmailAttachmentRepository creates a new GmailAttachmentRepository.
+func NewGmailAttachmentRepository(repo *GmailRepository) *GmailAttachmentRepository {
+	return &GmailAttachmentRepository{GmailRepository: repo}
+}
+
 // NewGmailRepository creates a new GmailRepository with the given OAuth2 token source.
 func NewGmailRepository(ctx context.Context, tokenSource oauth2.TokenSource) (*GmailRepository, error) {
-	httpClient := oauth2.NewClient(ctx, tokenSource)
-
-	service, err := gmail.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err := gmail.NewService(ctx, clientOptions(ctx, tokenSource, ServiceGmail)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gmail service: %w", err)
 	}
@@ -118,22 +141,33 @@ func (r *GmailRepository) List(ctx context.Context, opts mail.ListOptions) (*mai
 	if opts.Query != "" {
 		call = call.Q(opts.Query)
 	}
+	if opts.IncludeSpamTrash {
+		call = call.IncludeSpamTrash(true)
+	}
 	if len(opts.LabelIDs) > 0 {
 		call = call.LabelIds(opts.LabelIDs...)
 	}
 
-	response, err := call.Context(ctx).Do()
+	response, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.ListMessagesResponse, error) {
+		return call.Context(ctx).Do()
+	})
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 
 	messages := make([]*mail.Message, 0, len(response.Messages))
+	var warnings []string
 	for _, gmailMsg := range response.Messages {
+		if opts.IDsOnly {
+			messages = append(messages, &mail.Message{ID: gmailMsg.Id, ThreadID: gmailMsg.ThreadId})
+			continue
+		}
 		// Fetch full message details
 		fullMsg, err := r.Get(ctx, gmailMsg.Id)
 		if err != nil {
-			// Log error and continue with partial data
+			// Continue with partial data, and say so
 			messages = append(messages, &mail.Message{ID: gmailMsg.Id, ThreadID: gmailMsg.ThreadId})
+			warnings = append(warnings, fmt.Sprintf("message %s: %v", gmailMsg.Id, err))
 			continue
 		}
 		messages = append(messages, fullMsg)
@@ -143,20 +177,80 @@ func (r *GmailRepository) List(ctx context.Context, opts mail.ListOptions) (*mai
 		Items:         messages,
 		NextPageToken: response.NextPageToken,
 		Total:         int(response.ResultSizeEstimate),
+		Warnings:      warnings,
 	}, nil
 }
 
 // Get retrieves a single message by ID.
 func (r *GmailRepository) Get(ctx context.Context, id string) (*mail.Message, error) {
-	gmailMsg, err := r.service.Users.Messages.Get(r.userID, id).
-		Format(gmailMessageFormat).
+	gmailMsg, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Message, error) {
+		return r.service.Users.Messages.Get(r.userID, id).
+			Format(gmailMessageFormat).
+			Context(ctx).
+			Do()
+	})
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+
+	return gmailMessageToDomain(gmailMsg), nil
+}
+
+// GetRaw retrieves the original RFC 2822 content of a message.
+func (r *GmailRepository) GetRaw(ctx context.Context, id string) ([]byte, error) {
+	gmailMsg, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Message, error) {
+		return r.service.Users.Messages.Get(r.userID, id).
+			Format(gmailRawFormat).
+			Context(ctx).
+			Do()
+	})
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+
+	raw, err := decodeAttachmentData(gmailMsg.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw message: %w", err)
+	}
+	return raw, nil
+}
+
+// SendRaw sends a message whose RFC 2822 content is already built.
+func (r *GmailRepository) SendRaw(ctx context.Context, raw []byte) (*mail.Message, error) {
+	gmailMsg := &gmail.Message{
+		Raw: base64.URLEncoding.EncodeToString(raw),
+	}
+
+	sent, err := r.service.Users.Messages.Send(r.userID, gmailMsg).
 		Context(ctx).
 		Do()
 	if err != nil {
 		return nil, r.handleError(err)
 	}
 
-	return gmailMessageToDomain(gmailMsg), nil
+	// Fetch the sent message to get full details
+	return r.Get(ctx, sent.Id)
+}
+
+// Import adds a message from its RFC 2822 content to the mailbox with the
+// given label IDs. The message keeps the date in its Date header and is
+// not classified as spam.
+func (r *GmailRepository) Import(ctx context.Context, raw []byte, labelIDs []string) (*mail.Message, error) {
+	gmailMsg := &gmail.Message{
+		Raw:      base64.URLEncoding.EncodeToString(raw),
+		LabelIds: labelIDs,
+	}
+
+	imported, err := r.service.Users.Messages.Import(r.userID, gmailMsg).
+		InternalDateSource("dateHeader").
+		NeverMarkSpam(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+
+	return r.Get(ctx, imported.Id)
 }
 
 // Send sends a new message.
@@ -234,9 +328,9 @@ func (r *GmailRepository) Forward(ctx context.Context, messageID string, forward
 
 // Trash moves a message to trash.
 func (r *GmailRepository) Trash(ctx context.Context, id string) error {
-	_, err := r.service.Users.Messages.Trash(r.userID, id).
-		Context(ctx).
-		Do()
+	_, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Message, error) {
+		return r.service.Users.Messages.Trash(r.userID, id).Context(ctx).Do()
+	})
 	if err != nil {
 		return r.handleError(err)
 	}
@@ -245,9 +339,9 @@ func (r *GmailRepository) Trash(ctx context.Context, id string) error {
 
 // Untrash removes a message from trash.
 func (r *GmailRepository) Untrash(ctx context.Context, id string) error {
-	_, err := r.service.Users.Messages.Untrash(r.userID, id).
-		Context(ctx).
-		Do()
+	_, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Message, error) {
+		return r.service.Users.Messages.Untrash(r.userID, id).Context(ctx).Do()
+	})
 	if err != nil {
 		return r.handleError(err)
 	}
@@ -280,9 +374,9 @@ func (r *GmailRepository) Modify(ctx context.Context, id string, req mail.Modify
 		RemoveLabelIds: req.RemoveLabels,
 	}
 
-	gmailMsg, err := r.service.Users.Messages.Modify(r.userID, id, modifyReq).
-		Context(ctx).
-		Do()
+	gmailMsg, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Message, error) {
+		return r.service.Users.Messages.Modify(r.userID, id, modifyReq).Context(ctx).Do()
+	})
 	if err != nil {
 		return nil, r.handleError(err)
 	}
@@ -290,35 +384,147 @@ func (r *GmailRepository) Modify(ctx context.Context, id string, req mail.Modify
 	return gmailMessageToDomain(gmailMsg), nil
 }
 
+// BatchModify applies the same label changes to many messages, sending
+// them in chunks of at most gmailBatchModifyLimit IDs per request.
+func (r *GmailRepository) BatchModify(ctx context.Context, ids []string, req mail.ModifyRequest) error {
+	for start := 0; start < len(ids); start += gmailBatchModifyLimit {
+		batchReq := &gmail.BatchModifyMessagesRequest{
+			Ids:            ids[start:min(start+gmailBatchModifyLimit, len(ids))],
+			AddLabelIds:    req.AddLabels,
+			RemoveLabelIds: req.RemoveLabels,
+		}
+		_, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (struct{}, error) {
+			return struct{}{}, r.service.Users.Messages.BatchModify(r.userID, batchReq).Context(ctx).Do()
+		})
+		if err != nil {
+			return r.handleError(err)
+		}
+	}
+	return nil
+}
+
 // Search searches for messages matching the query.
 func (r *GmailRepository) Search(ctx context.Context, query string, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
 	opts.Query = query
 	return r.List(ctx, opts)
 }
 
+// SearchEach calls fn with each message matching the query as soon as it
+// has been fetched, rather than after the whole page, so callers can print
+// results as they arrive. Pages of opts.MaxResults messages are listed
+// until the results run out or fn returns an error; mail.ErrStopSearch ends
+// the search without an error, before anything more is fetched. A message
+// that cannot be fetched is passed with only its IDs and a warning naming
+// it is returned, as List does.
+func (r *GmailRepository) SearchEach(ctx context.Context, query string, opts mail.ListOptions, fn func(*mail.Message) error) (int, []string, error) {
+	pageToken := opts.PageToken
+	total := 0
+	var warnings []string
+	for page := 0; ; page++ {
+		call := r.service.Users.Messages.List(r.userID).Q(query)
+		if opts.MaxResults > 0 {
+			call = call.MaxResults(int64(opts.MaxResults))
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		if len(opts.LabelIDs) > 0 {
+			call = call.LabelIds(opts.LabelIDs...)
+		}
+		if opts.IncludeSpamTrash {
+			call = call.IncludeSpamTrash(true)
+		}
+
+		response, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.ListMessagesResponse, error) {
+			return call.Context(ctx).Do()
+		})
+		if err != nil {
+			return total, warnings, r.handleError(err)
+		}
+		if page == 0 {
+			total = int(response.ResultSizeEstimate)
+		}
+
+		for _, gmailMsg := range response.Messages {
+			msg, err := r.Get(ctx, gmailMsg.Id)
+			if err != nil {
+				if ctx.Err() != nil {
+					return total, warnings, ctx.Err()
+				}
+				// Continue with partial data, and say so
+				msg = &mail.Message{ID: gmailMsg.Id, ThreadID: gmailMsg.ThreadId}
+				warnings = append(warnings, fmt.Sprintf("message %s: %v", gmailMsg.Id, err))
+			}
+			if err := fn(msg); err != nil {
+				if errors.Is(err, mail.ErrStopSearch) {
+					return total, warnings, nil
+				}
+				return total, warnings, err
+			}
+		}
+
+		if response.NextPageToken == "" || response.NextPageToken == pageToken {
+			return total, warnings, nil
+		}
+		pageToken = response.NextPageToken
+	}
+}
+
+// HistoryID returns the current history ID of the mailbox.
+func (r *GmailRepository) HistoryID(ctx context.Context) (uint64, error) {
+	profile, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Profile, error) {
+		return r.service.Users.GetProfile(r.userID).Context(ctx).Do()
+	})
+	if err != nil {
+		return 0, r.handleError(err)
+	}
+	return profile.HistoryId, nil
+}
+
+// Changes lists the messages added, deleted or relabeled since the history
+// ID. Gmail keeps about a week of history; older IDs return
+// mail.ErrMessageNotFound.
+func (r *GmailRepository) Changes(ctx context.Context, since uint64) (*mail.MailboxChanges, error) {
+	changes, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*mail.MailboxChanges, error) {
+		changes := &mail.MailboxChanges{HistoryID: since}
+		err := r.service.Users.History.List(r.userID).StartHistoryId(since).Pages(ctx, func(resp *gmail.ListHistoryResponse) error {
+			changes.HistoryID = max(changes.HistoryID, resp.HistoryId)
+			for _, h := range resp.History {
+				for _, m := range h.MessagesAdded {
+					changes.Added = append(changes.Added, m.Message.Id)
+				}
+				for _, m := range h.MessagesDeleted {
+					changes.Deleted = append(changes.Deleted, m.Message.Id)
+				}
+				for _, m := range h.LabelsAdded {
+					changes.Relabeled = append(changes.Relabeled, m.Message.Id)
+				}
+				for _, m := range h.LabelsRemoved {
+					changes.Relabeled = append(changes.Relabeled, m.Message.Id)
+				}
+			}
+			return nil
+		})
+		return changes, err
+	})
+	if err != nil {
+		return nil, r.handleError(err)
+	}
+	return changes, nil
+}
+
 // handleError maps Gmail API errors to domain errors.
 func (r *GmailRepository) handleError(err error) error {
 	var apiErr *googleapi.Error
 	if errors.As(err, &apiErr) {
-		return mapGmailError(apiErr.Code, apiErr.Message)
+		return apiErrorFrom("gmail", apiErr, mail.ErrMessageNotFound)
 	}
 	return fmt.Errorf("gmail error: %w", err)
 }
 
 // mapGmailError maps HTTP status codes to domain errors.
 func mapGmailError(statusCode int, message string) error {
-	switch statusCode {
-	case http.StatusNotFound:
-		return fmt.Errorf("%w: %s", mail.ErrMessageNotFound, message)
-	case http.StatusBadRequest:
-		return fmt.Errorf("%w: %s", ErrBadRequest, message)
-	case http.StatusTooManyRequests:
-		return fmt.Errorf("%w: %s", ErrRateLimited, message)
-	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
-		return fmt.Errorf("%w: %s", ErrTemporary, message)
-	default:
-		return fmt.Errorf("gmail API error (status %d): %s", statusCode, message)
-	}
+	return newAPIError("gmail", statusCode, "", message, mail.ErrMessageNotFound)
 }
 
 // gmailMessageToDomain converts a Gmail API message to a domain Message.
@@ -335,9 +541,9 @@ func gmailMessageToDomain(msg *gmail.Message) *mail.Message {
 	}
 
 	// Initialize slices
-	result.To = []string{}
-	result.Cc = []string{}
-	result.Bcc = []string{}
+	result.To = []mail.Address{}
+	result.Cc = []mail.Address{}
+	result.Bcc = []mail.Address{}
 	if result.Labels == nil {
 		result.Labels = []string{}
 	}
@@ -345,11 +551,12 @@ func gmailMessageToDomain(msg *gmail.Message) *mail.Message {
 	// Determine read and starred status from labels
 	result.IsRead = !hasLabel(msg.LabelIds, gmailLabelUnread)
 	result.IsStarred = hasLabel(msg.LabelIds, gmailLabelStarred)
+	result.IsImportant = hasLabel(msg.LabelIds, gmailLabelImportant)
 
 	// Parse headers and body from payload
 	if msg.Payload != nil {
 		from, to, subject, date := parseHeaders(msg.Payload.Headers)
-		result.From = from
+		result.From = mail.ParseAddress(from)
 		result.Subject = subject
 		result.Date = date
 
@@ -358,16 +565,38 @@ func gmailMessageToDomain(msg *gmail.Message) *mail.Message {
 			result.To = parseRecipients(to)
 		}
 
-		// Get Cc from headers
+		// Get Cc from headers, and Bcc, which the sender's copy keeps
 		for _, header := range msg.Payload.Headers {
-			if strings.EqualFold(header.Name, "Cc") {
-				result.Cc = parseRecipients(header.Value)
-				break
+			switch {
+			case strings.EqualFold(header.Name, "Cc"):
+				result.Cc = parseRecipients(decodeHeader(header.Value))
+			case strings.EqualFold(header.Name, "Bcc"):
+				result.Bcc = parseRecipients(decodeHeader(header.Value))
+			case strings.EqualFold(header.Name, "Message-ID"):
+				if ids := mail.ParseMessageIDs(header.Value); len(ids) > 0 {
+					result.MessageID = ids[0]
+				}
+			case strings.EqualFold(header.Name, "References"):
+				result.References = mail.ParseMessageIDs(header.Value)
 			}
 		}
 
 		// Extract body content
 		result.Body, result.BodyHTML = extractBody(msg.Payload)
+
+		// Collect attachment metadata
+		result.Attachments = extractAttachments(msg.Payload)
+
+		// Parse delivery status or read receipt reports
+		result.Report = extractDeliveryReport(msg.Payload)
+
+		// Keep the calendar part of meeting invitations
+		result.Calendar = extractCalendar(msg.Payload)
+	}
+
+	// Without a readable Date header, use the time Gmail received the message
+	if result.Date.IsZero() && msg.InternalDate > 0 {
+		result.Date = time.UnixMilli(msg.InternalDate)
 	}
 
 	return result
@@ -395,43 +624,52 @@ func parseHeaders(headers []*gmail.MessagePartHeader) (from, to, subject string,
 	for _, header := range headers {
 		switch strings.ToLower(header.Name) {
 		case "from":
-			from = header.Value
+			from = decodeHeader(header.Value)
 		case "to":
-			to = header.Value
+			to = decodeHeader(header.Value)
 		case "subject":
-			subject = header.Value
+			subject = decodeHeader(header.Value)
 		case "date":
-			// Try parsing RFC 2822 date format
-			parsed, err := time.Parse(time.RFC1123Z, header.Value)
-			if err == nil {
+			if parsed, err := mail.ParseDate(header.Value); err == nil {
 				date = parsed
-			} else {
-				// Try alternative formats
-				parsed, err = time.Parse("Mon, 2 Jan 2006 15:04:05 -0700", header.Value)
-				if err == nil {
-					date = parsed
-				}
 			}
 		}
 	}
 	return
 }
 
-// parseRecipients parses a comma-separated list of email addresses.
-func parseRecipients(addresses string) []string {
-	if addresses == "" {
-		return []string{}
-	}
-
-	parts := strings.Split(addresses, ",")
-	recipients := make([]string, 0, len(parts))
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed != "" {
-			recipients = append(recipients, trimmed)
+// headerDecoder decodes RFC 2047 encoded-words in any charset the
+// encoding index knows, not only the UTF-8 and ISO-8859-1 of the standard
+// library.
+var headerDecoder = &mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(charset)
+		if err != nil {
+			return nil, err
 		}
+		return enc.NewDecoder().Reader(input), nil
+	},
+}
+
+// decodeHeader decodes the RFC 2047 encoded-words in a header value, such
+// as =?UTF-8?B?...?= or =?ISO-2022-JP?B?...?=. Gmail decodes most headers
+// itself but passes on words it could not. A value that cannot be decoded
+// is returned as it is.
+func decodeHeader(value string) string {
+	if !strings.Contains(value, "=?") {
+		return value
 	}
-	return recipients
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// parseRecipients parses a comma-separated list of email addresses, with
+// or without display names.
+func parseRecipients(addresses string) []mail.Address {
+	return mail.ParseAddressList(addresses)
 }
 
 // hasLabel checks if a label exists in the label list.
@@ -445,46 +683,44 @@ func hasLabel(labels []string, target string) bool {
 }
 
 // extractBody extracts plain text and HTML body from message payload.
+// When the message has no body of its own, as when it only forwards
+// another message as an attachment, the body of the attached message is
+// used.
 func extractBody(payload *gmail.MessagePart) (plain, html string) {
 	if payload == nil {
 		return "", ""
 	}
 
 	// Single part message
-	if payload.Body != nil && payload.Body.Data != "" {
-		decoded, err := base64.URLEncoding.DecodeString(payload.Body.Data)
-		if err == nil {
-			if strings.HasPrefix(payload.MimeType, "text/html") {
-				return "", string(decoded)
+	if !strings.HasPrefix(strings.ToLower(payload.MimeType), "multipart/") && payload.Body != nil && payload.Body.Data != "" {
+		if text, ok := decodePartText(payload); ok {
+			if strings.HasPrefix(strings.ToLower(payload.MimeType), "text/html") {
+				return "", text
 			}
-			return string(decoded), ""
+			return text, ""
 		}
 	}
 
-	// Multipart message
-	if len(payload.Parts) > 0 {
-		for _, part := range payload.Parts {
-			partPlain, partHTML := extractBodyFromPart(part)
-			if partPlain != "" && plain == "" {
-				plain = partPlain
-			}
-			if partHTML != "" && html == "" {
-				html = partHTML
-			}
-		}
+	plain, html = extractBodyFromPart(payload)
+	if plain == "" && html == "" {
+		plain, html = extractAttachedMessageBody(payload)
 	}
-
 	return plain, html
 }
 
-// extractBodyFromPart recursively extracts body content from a message part.
+// extractBodyFromPart recursively extracts body content from a message part:
+// the first text/plain and text/html parts, depth first. Attachments and
+// attached messages are skipped, so a text file attached before the body
+// or a forwarded message is not taken for it.
 func extractBodyFromPart(part *gmail.MessagePart) (plain, html string) {
-	if part == nil {
+	if part == nil || isAttachmentPart(part) {
 		return "", ""
 	}
 
+	mimeType := strings.ToLower(part.MimeType)
+
 	// Recursively handle nested multipart
-	if strings.HasPrefix(part.MimeType, "multipart/") && len(part.Parts) > 0 {
+	if strings.HasPrefix(mimeType, "multipart/") {
 		for _, subpart := range part.Parts {
 			subPlain, subHTML := extractBodyFromPart(subpart)
 			if subPlain != "" && plain == "" {
@@ -498,79 +734,468 @@ func extractBodyFromPart(part *gmail.MessagePart) (plain, html string) {
 	}
 
 	// Extract content from leaf parts
-	if part.Body != nil && part.Body.Data != "" {
-		decoded, err := base64.URLEncoding.DecodeString(part.Body.Data)
-		if err == nil {
-			content := string(decoded)
-			switch part.MimeType {
-			case "text/plain":
-				return content, ""
-			case "text/html":
-				return "", content
+	if mimeType != "text/plain" && mimeType != "text/html" {
+		return "", ""
+	}
+	text, ok := decodePartText(part)
+	if !ok {
+		return "", ""
+	}
+	if mimeType == "text/plain" {
+		return text, ""
+	}
+	return "", text
+}
+
+// extractAttachedMessageBody returns the body of the first attached
+// message/rfc822 part that has one.
+func extractAttachedMessageBody(part *gmail.MessagePart) (plain, html string) {
+	if part == nil {
+		return "", ""
+	}
+	if strings.EqualFold(part.MimeType, "message/rfc822") {
+		for _, subpart := range part.Parts {
+			if plain, html = extractBody(subpart); plain != "" || html != "" {
+				return plain, html
 			}
 		}
+		return "", ""
+	}
+	for _, subpart := range part.Parts {
+		if plain, html = extractAttachedMessageBody(subpart); plain != "" || html != "" {
+			return plain, html
+		}
 	}
-
 	return "", ""
 }
 
+// isAttachmentPart reports whether part is a file attached to the
+// message rather than part of its body: it has a filename or an
+// attachment disposition.
+func isAttachmentPart(part *gmail.MessagePart) bool {
+	if part.Filename != "" {
+		return true
+	}
+	disposition, _, _ := mime.ParseMediaType(partHeader(part, "Content-Disposition"))
+	return disposition == "attachment"
+}
+
+// partHeader returns the value of the named header of part.
+func partHeader(part *gmail.MessagePart, name string) string {
+	for _, header := range part.Headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+	return ""
+}
+
+// decodePartText returns the text of a leaf part as UTF-8. Gmail normally
+// delivers part data with its transfer encoding undone; data that still
+// carries it, as some relayed messages do, is decoded here. Text in
+// another charset, such as ISO-8859-1 or Shift_JIS, is converted.
+func decodePartText(part *gmail.MessagePart) (string, bool) {
+	if part.Body == nil || part.Body.Data == "" {
+		return "", false
+	}
+	data, err := decodeAttachmentData(part.Body.Data)
+	if err != nil {
+		return "", false
+	}
+	data = undoTransferEncoding(partHeader(part, "Content-Transfer-Encoding"), data)
+
+	_, params, _ := mime.ParseMediaType(partHeader(part, "Content-Type"))
+	return decodeCharset(params["charset"], data), true
+}
+
+// undoTransferEncoding decodes data that is still base64 or
+// quoted-printable encoded. Data only counts as still encoded when it
+// looks it: nothing but base64 lines, or quoted-printable soft line breaks
+// or escapes in otherwise 7-bit text. Anything else is already decoded
+// and returned as is.
+func undoTransferEncoding(encoding string, data []byte) []byte {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		if !stillBase64(data) {
+			return data
+		}
+		compact := bytes.Map(func(r rune) rune {
+			if r == '\r' || r == '\n' {
+				return -1
+			}
+			return r
+		}, data)
+		if decoded, err := base64.StdEncoding.DecodeString(string(compact)); err == nil {
+			return decoded
+		}
+	case "quoted-printable":
+		if !stillQuotedPrintable(data) {
+			return data
+		}
+		if decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(data))); err == nil {
+			return decoded
+		}
+	}
+	return data
+}
+
+// stillBase64 reports whether data consists of base64 lines only. Decoded
+// text has spaces or punctuation that base64 never contains.
+func stillBase64(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return false
+	}
+	for _, c := range trimmed {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '+', c == '/', c == '=', c == '\r', c == '\n':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// qpEscape matches a quoted-printable escape such as =3D or =E9.
+var qpEscape = regexp.MustCompile(`=[0-9A-F]{2}`)
+
+// stillQuotedPrintable reports whether data is 7-bit text with
+// quoted-printable soft line breaks, or in which every "=" starts an
+// escape, as "=" itself is written =3D.
+func stillQuotedPrintable(data []byte) bool {
+	for _, c := range data {
+		if c >= 0x80 {
+			return false
+		}
+	}
+	if bytes.Contains(data, []byte("=\r\n")) || bytes.Contains(data, []byte("=\n")) {
+		return true
+	}
+	escapes := len(qpEscape.FindAllIndex(data, -1))
+	return escapes > 0 && escapes == bytes.Count(data, []byte("="))
+}
+
+// decodeCharset converts text in charset to UTF-8. Text that is already
+// valid UTF-8 is kept, as Gmail often converts it, unless it holds the
+// escape sequences of a 7-bit charset such as ISO-2022-JP. Unknown
+// charsets and invalid bytes are kept as they are.
+func decodeCharset(charset string, data []byte) string {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return string(data)
+	}
+	if utf8.Valid(data) && bytes.IndexByte(data, 0x1b) < 0 {
+		return string(data)
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return string(data)
+	}
+	decoded, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+// extractAttachments recursively collects attachment metadata from a message part.
+// Only parts with a filename and an attachment ID are treated as attachments.
+func extractAttachments(part *gmail.MessagePart) []*mail.Attachment {
+	if part == nil {
+		return nil
+	}
+
+	var attachments []*mail.Attachment
+	if part.Filename != "" && part.Body != nil && part.Body.AttachmentId != "" {
+		att := mail.NewAttachment(part.Body.AttachmentId, decodeHeader(part.Filename), part.MimeType)
+		att.Size = part.Body.Size
+		for _, header := range part.Headers {
+			if strings.EqualFold(header.Name, "Content-ID") {
+				att.ContentID = strings.Trim(strings.TrimSpace(header.Value), "<>")
+				break
+			}
+		}
+		attachments = append(attachments, att)
+	}
+
+	for _, subpart := range part.Parts {
+		attachments = append(attachments, extractAttachments(subpart)...)
+	}
+
+	return attachments
+}
+
+// extractDeliveryReport finds the first message/delivery-status or
+// message/disposition-notification part and parses it.
+func extractDeliveryReport(part *gmail.MessagePart) *mail.DeliveryReport {
+	if part == nil {
+		return nil
+	}
+
+	var reportType mail.ReportType
+	switch strings.ToLower(part.MimeType) {
+	case "message/delivery-status":
+		reportType = mail.ReportDeliveryStatus
+	case "message/disposition-notification":
+		reportType = mail.ReportDisposition
+	}
+	if reportType != "" && part.Body != nil && part.Body.Data != "" {
+		if decoded, err := decodeAttachmentData(part.Body.Data); err == nil {
+			if report := mail.ParseDeliveryReport(reportType, string(decoded)); report != nil {
+				return report
+			}
+		}
+	}
+
+	for _, subpart := range part.Parts {
+		if report := extractDeliveryReport(subpart); report != nil {
+			return report
+		}
+	}
+
+	return nil
+}
+
+// extractCalendar returns the text of the first text/calendar part that
+// Gmail delivered with the message. Parts only available as attachments,
+// such as the invite.ics copy of an invitation, are skipped.
+func extractCalendar(part *gmail.MessagePart) string {
+	if part == nil {
+		return ""
+	}
+	if strings.EqualFold(part.MimeType, "text/calendar") {
+		if text, ok := decodePartText(part); ok {
+			return text
+		}
+	}
+	for _, subpart := range part.Parts {
+		if text := extractCalendar(subpart); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
 // buildMimeMessage constructs a MIME message from a domain Message.
 func buildMimeMessage(msg *mail.Message) []byte {
 	var builder strings.Builder
 
 	// Write headers
-	builder.WriteString(fmt.Sprintf("From: %s\r\n", msg.From))
-	builder.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(msg.To, ", ")))
+	builder.WriteString(fmt.Sprintf("From: %s\r\n", mail.EncodeAddress(msg.From.String())))
+	builder.WriteString(fmt.Sprintf("To: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.To))))
 	if len(msg.Cc) > 0 {
-		builder.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(msg.Cc, ", ")))
+		builder.WriteString(fmt.Sprintf("Cc: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.Cc))))
 	}
 	if len(msg.Bcc) > 0 {
-		builder.WriteString(fmt.Sprintf("Bcc: %s\r\n", strings.Join(msg.Bcc, ", ")))
+		builder.WriteString(fmt.Sprintf("Bcc: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.Bcc))))
 	}
-	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", msg.Subject))
+	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", mail.EncodeHeaderText(msg.Subject)))
+	writeReferences(&builder, msg.References)
+	writeExtraHeaders(&builder, msg)
 	builder.WriteString("MIME-Version: 1.0\r\n")
 
-	// Determine content type
-	if msg.BodyHTML != "" {
-		builder.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
-		builder.WriteString("\r\n")
-		builder.WriteString(msg.BodyHTML)
-	} else {
-		builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
-		builder.WriteString("\r\n")
-		builder.WriteString(msg.Body)
-	}
+	writeMimeBody(&builder, msg)
 
 	return []byte(builder.String())
 }
 
+// writeReferences writes the In-Reply-To and References headers of a
+// message answering the last of refs, so that it is threaded as a reply.
+func writeReferences(builder *strings.Builder, refs []string) {
+	value := mail.FormatMessageIDs(refs)
+	if value == "" {
+		return
+	}
+	builder.WriteString(fmt.Sprintf("In-Reply-To: %s\r\n", value[strings.LastIndex(value, " ")+1:]))
+	builder.WriteString(fmt.Sprintf("References: %s\r\n", value))
+}
+
 // buildReplyMimeMessage constructs a MIME message for a reply.
 func buildReplyMimeMessage(msg *mail.Message, originalMessageID string) []byte {
 	var builder strings.Builder
 
 	// Write headers
-	builder.WriteString(fmt.Sprintf("From: %s\r\n", msg.From))
-	builder.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(msg.To, ", ")))
+	builder.WriteString(fmt.Sprintf("From: %s\r\n", mail.EncodeAddress(msg.From.String())))
+	builder.WriteString(fmt.Sprintf("To: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.To))))
 	if len(msg.Cc) > 0 {
-		builder.WriteString(fmt.Sprintf("Cc: %s\r\n", strings.Join(msg.Cc, ", ")))
+		builder.WriteString(fmt.Sprintf("Cc: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.Cc))))
 	}
-	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", msg.Subject))
+	if len(msg.Bcc) > 0 {
+		builder.WriteString(fmt.Sprintf("Bcc: %s\r\n", mail.EncodeAddressList(mail.AddressStrings(msg.Bcc))))
+	}
+	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", mail.EncodeHeaderText(msg.Subject)))
 	builder.WriteString(fmt.Sprintf("In-Reply-To: <%s>\r\n", originalMessageID))
 	builder.WriteString(fmt.Sprintf("References: <%s>\r\n", originalMessageID))
+	writeExtraHeaders(&builder, msg)
 	builder.WriteString("MIME-Version: 1.0\r\n")
 
-	// Determine content type
-	if msg.BodyHTML != "" {
+	writeMimeBody(&builder, msg)
+
+	return []byte(builder.String())
+}
+
+// writeExtraHeaders writes the message's extra headers, which the domain
+// has validated. Non-ASCII values are encoded as RFC 2047 encoded-words.
+func writeExtraHeaders(builder *strings.Builder, msg *mail.Message) {
+	for _, h := range msg.Headers {
+		builder.WriteString(fmt.Sprintf("%s: %s\r\n", h.Name, mail.EncodeHeaderText(h.Value)))
+	}
+}
+
+// writeMimeBody writes the Content-Type header and body of a message.
+// HTML bodies with inline attachments are written as multipart/related so
+// that cid: references in the HTML resolve to the attached images. File
+// attachments wrap the body in multipart/mixed.
+func writeMimeBody(builder *strings.Builder, msg *mail.Message) {
+	files := fileAttachments(msg)
+	if len(files) == 0 {
+		writeContentBody(builder, msg)
+		return
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	builder.WriteString(fmt.Sprintf("Content-Type: %s\r\n", mime.FormatMediaType("multipart/mixed", map[string]string{
+		"boundary": boundary,
+	})))
+	builder.WriteString("\r\n")
+
+	builder.WriteString("--" + boundary + "\r\n")
+	writeContentBody(builder, msg)
+	builder.WriteString("\r\n")
+
+	for _, att := range files {
+		mimeType := att.MimeType
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		filename := att.Filename
+		if filename == "" {
+			filename = "attachment"
+		}
+
+		builder.WriteString("--" + boundary + "\r\n")
+		builder.WriteString(fmt.Sprintf("Content-Type: %s\r\n", mime.FormatMediaType(mimeType, map[string]string{"name": filename})))
+		// RFC 2046 does not allow base64 for message/rfc822, so an
+		// attached message is written as it is
+		rfc822 := strings.EqualFold(mimeType, "message/rfc822")
+		if rfc822 {
+			builder.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		} else {
+			builder.WriteString("Content-Transfer-Encoding: base64\r\n")
+		}
+		builder.WriteString(fmt.Sprintf("Content-Disposition: %s\r\n", mime.FormatMediaType("attachment", map[string]string{"filename": filename})))
+		builder.WriteString("\r\n")
+		if rfc822 {
+			builder.Write(att.Data)
+			if !bytes.HasSuffix(att.Data, []byte("\r\n")) {
+				builder.WriteString("\r\n")
+			}
+		} else {
+			builder.Write(wrapBase64(att.Data))
+			builder.WriteString("\r\n")
+		}
+	}
+
+	builder.WriteString("--" + boundary + "--\r\n")
+}
+
+// writeContentBody writes the Content-Type header and the text, HTML, or
+// multipart/related content of a message.
+func writeContentBody(builder *strings.Builder, msg *mail.Message) {
+	inline := inlineAttachments(msg)
+
+	switch {
+	case msg.BodyHTML != "" && len(inline) > 0:
+		writeRelatedBody(builder, msg.BodyHTML, inline)
+	case msg.BodyHTML != "":
 		builder.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
 		builder.WriteString("\r\n")
 		builder.WriteString(msg.BodyHTML)
-	} else {
+	default:
 		builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
 		builder.WriteString("\r\n")
 		builder.WriteString(msg.Body)
 	}
+}
 
-	return []byte(builder.String())
+// inlineAttachments returns the attachments that have a content ID and data.
+func inlineAttachments(msg *mail.Message) []*mail.Attachment {
+	var inline []*mail.Attachment
+	for _, att := range msg.Attachments {
+		if att != nil && att.IsInline() && att.HasData() {
+			inline = append(inline, att)
+		}
+	}
+	return inline
+}
+
+// fileAttachments returns the attachments that have data but no content ID.
+func fileAttachments(msg *mail.Message) []*mail.Attachment {
+	var files []*mail.Attachment
+	for _, att := range msg.Attachments {
+		if att != nil && !att.IsInline() && att.HasData() {
+			files = append(files, att)
+		}
+	}
+	return files
+}
+
+// writeRelatedBody writes a multipart/related body containing the HTML part
+// followed by each inline attachment.
+func writeRelatedBody(builder *strings.Builder, html string, inline []*mail.Attachment) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	builder.WriteString(fmt.Sprintf("Content-Type: %s\r\n", mime.FormatMediaType("multipart/related", map[string]string{
+		"boundary": writer.Boundary(),
+		"type":     "text/html",
+	})))
+	builder.WriteString("\r\n")
+
+	htmlPart, _ := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=\"utf-8\""},
+	})
+	_, _ = htmlPart.Write([]byte(html))
+
+	for _, att := range inline {
+		mimeType := att.MimeType
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+
+		disposition := "inline"
+		if att.Filename != "" {
+			disposition = mime.FormatMediaType("inline", map[string]string{"filename": att.Filename})
+		}
+
+		part, _ := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mimeType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Id":                {"<" + att.ContentID + ">"},
+			"Content-Disposition":       {disposition},
+		})
+		_, _ = part.Write(wrapBase64(att.Data))
+	}
+
+	_ = writer.Close()
+	builder.Write(body.Bytes())
+}
+
+// wrapBase64 encodes data as base64 with lines of at most 76 characters.
+func wrapBase64(data []byte) []byte {
+	const lineLength = 76
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var wrapped bytes.Buffer
+	for len(encoded) > lineLength {
+		wrapped.WriteString(encoded[:lineLength])
+		wrapped.WriteString("\r\n")
+		encoded = encoded[lineLength:]
+	}
+	wrapped.WriteString(encoded)
+	return wrapped.Bytes()
 }
 
 // buildForwardBody creates the body text for a forwarded message.
@@ -581,17 +1206,30 @@ func buildForwardBody(original *mail.Message) string {
 	builder.WriteString(fmt.Sprintf("From: %s\r\n", original.From))
 	builder.WriteString(fmt.Sprintf("Date: %s\r\n", original.Date.Format(time.RFC1123Z)))
 	builder.WriteString(fmt.Sprintf("Subject: %s\r\n", original.Subject))
-	builder.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(original.To, ", ")))
+	builder.WriteString(fmt.Sprintf("To: %s\r\n", mail.JoinAddresses(original.To)))
 	builder.WriteString("\r\n")
 	builder.WriteString(original.Body)
 
 	return builder.String()
 }
 
+// maxRetryAfter is the longest Retry-After delay retryWithBackoff waits for;
+// a server asking for longer gets its error returned instead.
+const maxRetryAfter = 30 * time.Second
+
+// maxBackoff caps the exponential backoff between retries.
+const maxBackoff = 10 * time.Second
+
 // retryWithBackoff executes a function with exponential backoff retry.
+// The wait before each retry is drawn at random up to the backoff ("full
+// jitter"), so clients that failed together do not retry together; a
+// Retry-After delay sent with the error is waited instead. Retries also
+// stop once the budget set with SetRetryBudget is spent. fn is always
+// called at least once.
 func retryWithBackoff[T any](ctx context.Context, maxRetries int, baseBackoff time.Duration, fn func() (T, error)) (T, error) {
 	var zero T
 	var lastErr error
+	maxRetries = max(maxRetries, 1)
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		result, err := fn()
@@ -605,9 +1243,22 @@ func retryWithBackoff[T any](ctx context.Context, maxRetries int, baseBackoff ti
 		}
 
 		lastErr = err
+		if attempt == maxRetries-1 {
+			break
+		}
 
-		// Calculate backoff duration with exponential increase
-		backoff := baseBackoff * time.Duration(1<<attempt)
+		backoff := jitteredBackoff(baseBackoff, attempt)
+		if wait := retryAfter(err); wait > 0 {
+			if wait > maxRetryAfter {
+				return zero, err
+			}
+			backoff = wait
+		}
+
+		if !takeRetry() {
+			return zero, fmt.Errorf("retry budget exhausted: %w", err)
+		}
+		notifyRetry(Retry{Attempt: attempt + 1, Wait: backoff, Err: err})
 
 		// Wait for backoff or context cancellation
 		select {
@@ -621,8 +1272,27 @@ func retryWithBackoff[T any](ctx context.Context, maxRetries int, baseBackoff ti
 	return zero, fmt.Errorf("max retries (%d) exceeded: %w", maxRetries, lastErr)
 }
 
-// isRetryableError determines if an error should trigger a retry.
+// jitteredBackoff returns a random wait of up to baseBackoff doubled for
+// each earlier attempt, capped at maxBackoff.
+func jitteredBackoff(baseBackoff time.Duration, attempt int) time.Duration {
+	if baseBackoff <= 0 {
+		return 0
+	}
+	ceiling := min(baseBackoff, maxBackoff)
+	for ; attempt > 0 && ceiling < maxBackoff; attempt-- {
+		ceiling = min(ceiling*2, maxBackoff)
+	}
+	return rand.N(ceiling + 1)
+}
+
+// isRetryableError determines if an error should trigger a retry. Raw API
+// errors are classified by their reason as well as their status, so a 403
+// for a rate limit is retried while a 403 for missing access is not.
 func isRetryableError(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		err = newAPIError("", apiErr.Code, googleErrorReason(apiErr), apiErr.Message, nil)
+	}
 	return errors.Is(err, ErrTemporary) || errors.Is(err, ErrRateLimited)
 }
 
@@ -643,6 +1313,9 @@ func (r *GmailDraftRepository) List(ctx context.Context, opts mail.ListOptions)
 	if opts.Query != "" {
 		call = call.Q(opts.Query)
 	}
+	if opts.IncludeSpamTrash {
+		call = call.IncludeSpamTrash(true)
+	}
 
 	response, err := call.Context(ctx).Do()
 	if err != nil {
@@ -650,12 +1323,14 @@ func (r *GmailDraftRepository) List(ctx context.Context, opts mail.ListOptions)
 	}
 
 	drafts := make([]*mail.Draft, 0, len(response.Drafts))
+	var warnings []string
 	for _, gmailDraft := range response.Drafts {
 		// Fetch full draft details
 		fullDraft, err := r.Get(ctx, gmailDraft.Id)
 		if err != nil {
 			// Create minimal draft on error
 			drafts = append(drafts, &mail.Draft{ID: gmailDraft.Id})
+			warnings = append(warnings, fmt.Sprintf("draft %s: %v", gmailDraft.Id, err))
 			continue
 		}
 		drafts = append(drafts, fullDraft)
@@ -665,6 +1340,7 @@ func (r *GmailDraftRepository) List(ctx context.Context, opts mail.ListOptions)
 		Items:         drafts,
 		NextPageToken: response.NextPageToken,
 		Total:         int(response.ResultSizeEstimate),
+		Warnings:      warnings,
 	}, nil
 }
 
@@ -690,9 +1366,11 @@ func (r *GmailDraftRepository) Create(ctx context.Context, draft *mail.Draft) (*
 	raw := buildMimeMessage(draft.Message)
 	encodedRaw := base64.URLEncoding.EncodeToString(raw)
 
+	// A thread ID files the draft in that conversation, as a reply.
 	gmailDraft := &gmail.Draft{
 		Message: &gmail.Message{
-			Raw: encodedRaw,
+			Raw:      encodedRaw,
+			ThreadId: draft.Message.ThreadID,
 		},
 	}
 
@@ -773,10 +1451,7 @@ func (r *GmailDraftRepository) Delete(ctx context.Context, id string) error {
 func (r *GmailRepository) handleDraftError(err error) error {
 	var apiErr *googleapi.Error
 	if errors.As(err, &apiErr) {
-		if apiErr.Code == http.StatusNotFound {
-			return fmt.Errorf("%w: %s", mail.ErrDraftNotFound, apiErr.Message)
-		}
-		return mapGmailError(apiErr.Code, apiErr.Message)
+		return apiErrorFrom("gmail", apiErr, mail.ErrDraftNotFound)
 	}
 	return fmt.Errorf("gmail error: %w", err)
 }
@@ -890,10 +1565,7 @@ func (r *GmailLabelRepository) Delete(ctx context.Context, id string) error {
 func (r *GmailRepository) handleLabelError(err error) error {
 	var apiErr *googleapi.Error
 	if errors.As(err, &apiErr) {
-		if apiErr.Code == http.StatusNotFound {
-			return fmt.Errorf("%w: %s", mail.ErrLabelNotFound, apiErr.Message)
-		}
-		return mapGmailError(apiErr.Code, apiErr.Message)
+		return apiErrorFrom("gmail", apiErr, mail.ErrLabelNotFound)
 	}
 	return fmt.Errorf("gmail error: %w", err)
 }
@@ -910,6 +1582,10 @@ func gmailLabelToDomain(label *gmail.Label) *mail.Label {
 		Type:                  label.Type,
 		MessageListVisibility: label.MessageListVisibility,
 		LabelListVisibility:   label.LabelListVisibility,
+		MessagesTotal:         int(label.MessagesTotal),
+		MessagesUnread:        int(label.MessagesUnread),
+		ThreadsTotal:          int(label.ThreadsTotal),
+		ThreadsUnread:         int(label.ThreadsUnread),
 	}
 
 	if label.Color != nil {
@@ -963,6 +1639,9 @@ func (r *GmailThreadRepository) List(ctx context.Context, opts mail.ListOptions)
 	if opts.Query != "" {
 		call = call.Q(opts.Query)
 	}
+	if opts.IncludeSpamTrash {
+		call = call.IncludeSpamTrash(true)
+	}
 	if len(opts.LabelIDs) > 0 {
 		call = call.LabelIds(opts.LabelIDs...)
 	}
@@ -991,10 +1670,12 @@ func (r *GmailThreadRepository) List(ctx context.Context, opts mail.ListOptions)
 
 // Get retrieves a single thread by ID with all messages.
 func (r *GmailThreadRepository) Get(ctx context.Context, id string) (*mail.Thread, error) {
-	gmailThread, err := r.service.Users.Threads.Get(r.userID, id).
-		Format(gmailMessageFormat).
-		Context(ctx).
-		Do()
+	gmailThread, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Thread, error) {
+		return r.service.Users.Threads.Get(r.userID, id).
+			Format(gmailMessageFormat).
+			Context(ctx).
+			Do()
+	})
 	if err != nil {
 		return nil, r.handleThreadError(err)
 	}
@@ -1009,9 +1690,9 @@ func (r *GmailThreadRepository) Modify(ctx context.Context, id string, req mail.
 		RemoveLabelIds: req.RemoveLabels,
 	}
 
-	gmailThread, err := r.service.Users.Threads.Modify(r.userID, id, modifyReq).
-		Context(ctx).
-		Do()
+	gmailThread, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.Thread, error) {
+		return r.service.Users.Threads.Modify(r.userID, id, modifyReq).Context(ctx).Do()
+	})
 	if err != nil {
 		return nil, r.handleThreadError(err)
 	}
@@ -1056,10 +1737,7 @@ func (r *GmailThreadRepository) Delete(ctx context.Context, id string) error {
 func (r *GmailRepository) handleThreadError(err error) error {
 	var apiErr *googleapi.Error
 	if errors.As(err, &apiErr) {
-		if apiErr.Code == http.StatusNotFound {
-			return fmt.Errorf("%w: %s", mail.ErrThreadNotFound, apiErr.Message)
-		}
-		return mapGmailError(apiErr.Code, apiErr.Message)
+		return apiErrorFrom("gmail", apiErr, mail.ErrThreadNotFound)
 	}
 	return fmt.Errorf("gmail error: %w", err)
 }
@@ -1098,3 +1776,40 @@ func gmailThreadToDomain(thread *gmail.Thread) *mail.Thread {
 
 	return result
 }
+
+// =============================================================================
+// AttachmentRepository Implementation (GmailAttachmentRepository)
+// =============================================================================
+
+// Get retrieves the decoded content of an attachment on a message.
+func (r *GmailAttachmentRepository) Get(ctx context.Context, messageID, attachmentID string) ([]byte, error) {
+	body, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*gmail.MessagePartBody, error) {
+		return r.service.Users.Messages.Attachments.Get(r.userID, messageID, attachmentID).Context(ctx).Do()
+	})
+	if err != nil {
+		return nil, r.handleAttachmentError(err)
+	}
+
+	data, err := decodeAttachmentData(body.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attachment %s: %w", attachmentID, err)
+	}
+	return data, nil
+}
+
+// handleAttachmentError maps Gmail API errors to domain attachment errors.
+func (r *GmailRepository) handleAttachmentError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErrorFrom("gmail", apiErr, mail.ErrAttachmentNotFound)
+	}
+	return fmt.Errorf("gmail error: %w", err)
+}
+
+// decodeAttachmentData decodes base64url attachment data, with or without padding.
+func decodeAttachmentData(data string) ([]byte, error) {
+	if decoded, err := base64.URLEncoding.DecodeString(data); err == nil {
+		return decoded, nil
+	}
+	return base64.RawURLEncoding.DecodeString(data)
+}
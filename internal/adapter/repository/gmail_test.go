@@ -1,16 +1,23 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	netmail "net/mail"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
 	"google.golang.org/api/gmail/v1"
@@ -62,6 +69,13 @@ func TestParseHeaders(t *testing.T) {
 			expectedTo:      "upper@example.com",
 			expectedSubject: "Caps Subject",
 		},
+		{
+			name: "obsolete date",
+			headers: []*gmail.MessagePartHeader{
+				{Name: "Date", Value: "Tuesday, 3 January 2006 15:04 EST (Eastern)"},
+			},
+			expectedDate: "2006-01-03",
+		},
 		{
 			name:            "empty headers",
 			headers:         []*gmail.MessagePartHeader{},
@@ -93,6 +107,32 @@ func TestParseHeaders(t *testing.T) {
 	}
 }
 
+func TestGmailMessageToDomain_InternalDateFallback(t *testing.T) {
+	received := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	msg := gmailMessageToDomain(&gmail.Message{
+		Id:           "msg1",
+		InternalDate: received.UnixMilli(),
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "Date", Value: "sometime last week"},
+		}},
+	})
+	if !msg.Date.Equal(received) {
+		t.Errorf("Date = %v, want %v", msg.Date, received)
+	}
+
+	sent := time.Date(2026, 3, 4, 1, 0, 0, 0, time.UTC)
+	msg = gmailMessageToDomain(&gmail.Message{
+		Id:           "msg2",
+		InternalDate: received.UnixMilli(),
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "Date", Value: sent.Format(time.RFC1123Z)},
+		}},
+	})
+	if !msg.Date.Equal(sent) {
+		t.Errorf("Date = %v, want the Date header %v", msg.Date, sent)
+	}
+}
+
 // TestGmailMessageToDomain tests conversion from Gmail API message to domain message.
 func TestGmailMessageToDomain(t *testing.T) {
 	tests := []struct {
@@ -121,8 +161,8 @@ func TestGmailMessageToDomain(t *testing.T) {
 			want: &mail.Message{
 				ID:        "msg123",
 				ThreadID:  "thread456",
-				From:      "sender@example.com",
-				To:        []string{"recipient@example.com"},
+				From:      mail.Address{Email: "sender@example.com"},
+				To:        []mail.Address{{Email: "recipient@example.com"}},
 				Subject:   "Test Subject",
 				Body:      "Hello, World!",
 				Snippet:   "This is a preview...",
@@ -136,7 +176,7 @@ func TestGmailMessageToDomain(t *testing.T) {
 			gmailMsg: &gmail.Message{
 				Id:       "msg789",
 				ThreadId: "thread101",
-				LabelIds: []string{"INBOX", "STARRED"},
+				LabelIds: []string{"INBOX", "STARRED", "IMPORTANT"},
 				Payload: &gmail.MessagePart{
 					Headers: []*gmail.MessagePartHeader{
 						{Name: "From", Value: "another@example.com"},
@@ -146,14 +186,15 @@ func TestGmailMessageToDomain(t *testing.T) {
 				},
 			},
 			want: &mail.Message{
-				ID:        "msg789",
-				ThreadID:  "thread101",
-				From:      "another@example.com",
-				To:        []string{"me@example.com"},
-				Subject:   "Starred Message",
-				Labels:    []string{"INBOX", "STARRED"},
-				IsRead:    true,
-				IsStarred: true,
+				ID:          "msg789",
+				ThreadID:    "thread101",
+				From:        mail.Address{Email: "another@example.com"},
+				To:          []mail.Address{{Email: "me@example.com"}},
+				Subject:     "Starred Message",
+				Labels:      []string{"INBOX", "STARRED", "IMPORTANT"},
+				IsRead:      true,
+				IsStarred:   true,
+				IsImportant: true,
 			},
 		},
 		{
@@ -188,8 +229,8 @@ func TestGmailMessageToDomain(t *testing.T) {
 			want: &mail.Message{
 				ID:       "multipart123",
 				ThreadID: "thread789",
-				From:     "html@example.com",
-				To:       []string{"reader@example.com"},
+				From:     mail.Address{Email: "html@example.com"},
+				To:       []mail.Address{{Email: "reader@example.com"}},
 				Subject:  "HTML Email",
 				Body:     "Plain text content",
 				BodyHTML: "<p>HTML content</p>",
@@ -220,6 +261,9 @@ func TestGmailMessageToDomain(t *testing.T) {
 			if got.IsStarred != tt.want.IsStarred {
 				t.Errorf("IsStarred = %v, want %v", got.IsStarred, tt.want.IsStarred)
 			}
+			if got.IsImportant != tt.want.IsImportant {
+				t.Errorf("IsImportant = %v, want %v", got.IsImportant, tt.want.IsImportant)
+			}
 			if tt.want.Body != "" && got.Body != tt.want.Body {
 				t.Errorf("Body = %q, want %q", got.Body, tt.want.Body)
 			}
@@ -240,8 +284,8 @@ func TestBuildMimeMessage(t *testing.T) {
 		{
 			name: "basic message",
 			msg: &mail.Message{
-				From:    "sender@example.com",
-				To:      []string{"recipient@example.com"},
+				From:    mail.Address{Email: "sender@example.com"},
+				To:      []mail.Address{{Email: "recipient@example.com"}},
 				Subject: "Test Subject",
 				Body:    "Hello, World!",
 			},
@@ -256,10 +300,10 @@ func TestBuildMimeMessage(t *testing.T) {
 		{
 			name: "multiple recipients with cc and bcc",
 			msg: &mail.Message{
-				From:    "sender@example.com",
-				To:      []string{"one@example.com", "two@example.com"},
-				Cc:      []string{"cc@example.com"},
-				Bcc:     []string{"bcc@example.com"},
+				From:    mail.Address{Email: "sender@example.com"},
+				To:      []mail.Address{{Email: "one@example.com"}, {Email: "two@example.com"}},
+				Cc:      []mail.Address{{Email: "cc@example.com"}},
+				Bcc:     []mail.Address{{Email: "bcc@example.com"}},
 				Subject: "Multi Recipient",
 				Body:    "Content",
 			},
@@ -274,8 +318,8 @@ func TestBuildMimeMessage(t *testing.T) {
 		{
 			name: "html message",
 			msg: &mail.Message{
-				From:     "sender@example.com",
-				To:       []string{"recipient@example.com"},
+				From:     mail.Address{Email: "sender@example.com"},
+				To:       []mail.Address{{Email: "recipient@example.com"}},
 				Subject:  "HTML Email",
 				BodyHTML: "<p>Hello, World!</p>",
 			},
@@ -545,8 +589,8 @@ func TestGmailRepository_Send(t *testing.T) {
 	}
 
 	msg := &mail.Message{
-		From:    "sender@example.com",
-		To:      []string{"recipient@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
 		Subject: "Test Subject",
 		Body:    "Test Body",
 	}
@@ -632,6 +676,50 @@ func TestGmailRepository_Modify(t *testing.T) {
 	}
 }
 
+// TestGmailRepository_BatchModify tests that BatchModify chunks large ID lists.
+func TestGmailRepository_BatchModify(t *testing.T) {
+	var batches []gmail.BatchModifyMessagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && r.URL.Path == "/gmail/v1/users/me/messages/batchModify" {
+			var req gmail.BatchModifyMessagesRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			batches = append(batches, req)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := gmail.NewService(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	repo := &GmailRepository{service: service, userID: "me"}
+
+	ids := make([]string, gmailBatchModifyLimit+500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("msg%d", i)
+	}
+	err = repo.BatchModify(ctx, ids, mail.ModifyRequest{RemoveLabels: []string{"INBOX"}})
+	if err != nil {
+		t.Fatalf("BatchModify failed: %v", err)
+	}
+
+	if len(batches) != 2 {
+		t.Fatalf("got %d requests, want 2", len(batches))
+	}
+	if len(batches[0].Ids) != gmailBatchModifyLimit || len(batches[1].Ids) != 500 {
+		t.Errorf("batch sizes = %d, %d", len(batches[0].Ids), len(batches[1].Ids))
+	}
+	if batches[1].Ids[0] != "msg1000" || len(batches[1].RemoveLabelIds) != 1 || batches[1].RemoveLabelIds[0] != "INBOX" {
+		t.Errorf("unexpected second batch: ids[0]=%s remove=%v", batches[1].Ids[0], batches[1].RemoveLabelIds)
+	}
+}
+
 // TestRetryWithBackoff tests the retry mechanism.
 func TestRetryWithBackoff(t *testing.T) {
 	attempts := 0
@@ -656,6 +744,76 @@ func TestRetryWithBackoff(t *testing.T) {
 	}
 }
 
+// TestRetryWithBackoffRetryHook tests that each retry calls the retry hook.
+func TestRetryWithBackoffRetryHook(t *testing.T) {
+	retries := 0
+	SetRetryHook(func(Retry) { retries++ })
+	t.Cleanup(func() { SetRetryHook(nil) })
+
+	attempts := 0
+	_, err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", ErrTemporary
+		}
+		return "success", nil
+	})
+	if err != nil {
+		t.Fatalf("retryWithBackoff failed: %v", err)
+	}
+	if retries != 2 {
+		t.Errorf("retries = %d, want 2", retries)
+	}
+}
+
+// TestRetryWithBackoffBudget tests that retries stop once the budget is
+// spent, across calls.
+func TestRetryWithBackoffBudget(t *testing.T) {
+	SetRetryBudget(3)
+	t.Cleanup(func() { SetRetryBudget(0) })
+
+	var retries []Retry
+	SetRetryHook(func(r Retry) { retries = append(retries, r) })
+	t.Cleanup(func() { SetRetryHook(nil) })
+
+	attempts := 0
+	call := func() error {
+		_, err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() (string, error) {
+			attempts++
+			return "", ErrTemporary
+		})
+		return err
+	}
+	if err := call(); err == nil || !strings.Contains(err.Error(), "max retries") {
+		t.Fatalf("first call error = %v, want max retries exceeded", err)
+	}
+	if err := call(); err == nil || !strings.Contains(err.Error(), "retry budget exhausted") || !errors.Is(err, ErrTemporary) {
+		t.Fatalf("second call error = %v, want the budget exhausted", err)
+	}
+	if attempts != 5 || Retries() != 3 || len(retries) != 3 {
+		t.Errorf("attempts = %d, retries = %d, hook calls = %d; want 5, 3, 3", attempts, Retries(), len(retries))
+	}
+	if retries[0].Attempt != 1 || retries[1].Attempt != 2 || retries[2].Attempt != 1 {
+		t.Errorf("retry attempts = %+v", retries)
+	}
+}
+
+// TestJitteredBackoff tests that waits stay within the doubled, capped
+// backoff.
+func TestJitteredBackoff(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		ceiling := min(100*time.Millisecond<<min(attempt, 20), maxBackoff)
+		for range 20 {
+			if d := jitteredBackoff(100*time.Millisecond, attempt); d < 0 || d > ceiling {
+				t.Fatalf("jitteredBackoff(attempt %d) = %v, want 0 to %v", attempt, d, ceiling)
+			}
+		}
+	}
+	if d := jitteredBackoff(0, 3); d != 0 {
+		t.Errorf("jitteredBackoff(0) = %v, want 0", d)
+	}
+}
+
 // TestRetryWithBackoffExhausted tests retry exhaustion.
 func TestRetryWithBackoffExhausted(t *testing.T) {
 	ctx := context.Background()
@@ -747,6 +905,180 @@ func TestGmailRepository_ListWithTestServer(t *testing.T) {
 	}
 }
 
+// TestGmailRepository_ListPartialFailure tests that a message that cannot
+// be fetched is kept with partial data and reported in Warnings.
+func TestGmailRepository_ListPartialFailure(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	ts.MessageListHandler = func(w http.ResponseWriter, r *http.Request) {
+		WriteJSONResponse(w, MockMessageListResponse(
+			[]*gmail.Message{
+				{Id: "msg1", ThreadId: "thread1"},
+				{Id: "gone", ThreadId: "thread2"},
+			},
+			"",
+			2,
+		))
+	}
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		if msgID == "msg1" {
+			WriteJSONResponse(w, MockMessageResponse("msg1", "thread1", "Subject 1", "alice@example.com", "bob@example.com", "Hello"))
+			return
+		}
+		WriteErrorResponse(w, http.StatusNotFound, "message not found")
+	}
+
+	result, err := ts.GmailRepository(t).List(context.Background(), mail.ListOptions{MaxResults: 10})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Items) != 2 || result.Items[1].ID != "gone" || result.Items[1].ThreadID != "thread2" {
+		t.Fatalf("Items = %+v, want both messages, the second with only its IDs", result.Items)
+	}
+	if !result.Incomplete() || len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "message gone: ") {
+		t.Errorf("Warnings = %q, want one for message gone", result.Warnings)
+	}
+}
+
+func TestGmailRepository_ListIDsOnly(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	ts.MessageListHandler = func(w http.ResponseWriter, r *http.Request) {
+		WriteJSONResponse(w, MockMessageListResponse([]*gmail.Message{{Id: "msg1", ThreadId: "thread1"}}, "", 1))
+	}
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		t.Errorf("unexpected fetch of %s", msgID)
+		WriteErrorResponse(w, http.StatusNotFound, "message not found")
+	}
+
+	result, err := ts.GmailRepository(t).List(context.Background(), mail.ListOptions{IDsOnly: true})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(result.Items) != 1 || result.Items[0].ID != "msg1" || result.Items[0].ThreadID != "thread1" || result.Incomplete() {
+		t.Errorf("result = %+v, want msg1 with only its IDs", result)
+	}
+}
+
+func TestGmailRepositories_ListIncludeSpamTrash(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	var got []string
+	record := func(r *http.Request) {
+		got = append(got, r.URL.Query().Get("includeSpamTrash"))
+	}
+	ts.MessageListHandler = func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		WriteJSONResponse(w, MockMessageListResponse(nil, "", 0))
+	}
+	ts.DraftListHandler = func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		WriteJSONResponse(w, &gmail.ListDraftsResponse{})
+	}
+	ts.ThreadListHandler = func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		WriteJSONResponse(w, &gmail.ListThreadsResponse{})
+	}
+
+	ctx := context.Background()
+	repo := ts.GmailRepository(t)
+	for _, include := range []bool{false, true} {
+		got = nil
+		opts := mail.ListOptions{IncludeSpamTrash: include}
+		if _, err := repo.List(ctx, opts); err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if _, _, err := repo.SearchEach(ctx, "is:unread", opts, func(*mail.Message) error { return nil }); err != nil {
+			t.Fatalf("SearchEach failed: %v", err)
+		}
+		if _, err := NewGmailDraftRepository(repo).List(ctx, opts); err != nil {
+			t.Fatalf("draft List failed: %v", err)
+		}
+		if _, err := NewGmailThreadRepository(repo).List(ctx, opts); err != nil {
+			t.Fatalf("thread List failed: %v", err)
+		}
+
+		want := ""
+		if include {
+			want = "true"
+		}
+		for i, value := range got {
+			if value != want {
+				t.Errorf("IncludeSpamTrash=%t: request %d includeSpamTrash = %q, want %q", include, i, value, want)
+			}
+		}
+		if len(got) != 4 {
+			t.Errorf("requests = %d, want 4", len(got))
+		}
+	}
+}
+
+// TestGmailRepository_History tests HistoryID and Changes.
+func TestGmailRepository_History(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/profile":
+			json.NewEncoder(w).Encode(gmail.Profile{EmailAddress: "me@example.com", HistoryId: 100})
+		case "/gmail/v1/users/me/history":
+			if r.URL.Query().Get("startHistoryId") == "1" {
+				WriteErrorResponse(w, http.StatusNotFound, "history expired")
+				return
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				json.NewEncoder(w).Encode(gmail.ListHistoryResponse{
+					History: []*gmail.History{{
+						MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "new"}}},
+						LabelsAdded:   []*gmail.HistoryLabelAdded{{Message: &gmail.Message{Id: "msg1"}}},
+					}},
+					HistoryId:     110,
+					NextPageToken: "page2",
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(gmail.ListHistoryResponse{
+				History: []*gmail.History{{
+					MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: &gmail.Message{Id: "msg2"}}},
+					LabelsRemoved:   []*gmail.HistoryLabelRemoved{{Message: &gmail.Message{Id: "msg3"}}},
+				}},
+				HistoryId: 120,
+			})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := gmail.NewService(ctx, option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("failed to create Gmail service: %v", err)
+	}
+	repo := NewGmailRepositoryWithService(service, "me")
+
+	id, err := repo.HistoryID(ctx)
+	if err != nil || id != 100 {
+		t.Fatalf("HistoryID() = %d, %v; want 100", id, err)
+	}
+
+	changes, err := repo.Changes(ctx, 100)
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	if changes.HistoryID != 120 {
+		t.Errorf("HistoryID = %d, want 120", changes.HistoryID)
+	}
+	if fmt.Sprint(changes.Added, changes.Deleted, changes.Relabeled) != "[new] [msg2] [msg1 msg3]" {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	if _, err := repo.Changes(ctx, 1); !errors.Is(err, mail.ErrMessageNotFound) {
+		t.Errorf("Changes() of expired history error = %v, want ErrMessageNotFound", err)
+	}
+}
+
 // TestGmailRepository_GetWithTestServer tests Get using the TestServer.
 func TestGmailRepository_GetWithTestServer(t *testing.T) {
 	ts := NewTestServer()
@@ -780,7 +1112,7 @@ func TestGmailRepository_GetWithTestServer(t *testing.T) {
 	if msg.Subject != "Test Subject" {
 		t.Errorf("Subject = %q, want %q", msg.Subject, "Test Subject")
 	}
-	if msg.From != "sender@example.com" {
+	if msg.From.String() != "sender@example.com" {
 		t.Errorf("From = %q, want %q", msg.From, "sender@example.com")
 	}
 	if msg.IsRead {
@@ -826,8 +1158,8 @@ func TestGmailRepository_SendWithTestServer(t *testing.T) {
 	ctx := context.Background()
 
 	msg := &mail.Message{
-		From:    "sender@example.com",
-		To:      []string{"recipient@example.com"},
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
 		Subject: "Test Subject",
 		Body:    "Test Body",
 	}
@@ -1226,8 +1558,8 @@ func TestGmailDraftRepository_CreateWithTestServer(t *testing.T) {
 
 	draft := &mail.Draft{
 		Message: &mail.Message{
-			From:    "me@example.com",
-			To:      []string{"you@example.com"},
+			From:    mail.Address{Email: "me@example.com"},
+			To:      []mail.Address{{Email: "you@example.com"}},
 			Subject: "New Draft",
 			Body:    "Draft content",
 		},
@@ -1243,6 +1575,39 @@ func TestGmailDraftRepository_CreateWithTestServer(t *testing.T) {
 	}
 }
 
+// TestGmailDraftRepository_CreateInThread tests that a draft with a thread
+// ID is created in that thread.
+func TestGmailDraftRepository_CreateInThread(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	var createdDraft *gmail.Draft
+	ts.DraftCreateHandler = func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&createdDraft); err != nil {
+			WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		WriteJSONResponse(w, &gmail.Draft{Id: "draft_1", Message: &gmail.Message{Id: "msg_1"}})
+	}
+	ts.DraftGetHandler = func(w http.ResponseWriter, r *http.Request, draftID string) {
+		WriteJSONResponse(w, MockDraftResponse("draft_1", "msg_1", "Re: Plans", "me@example.com", "you@example.com", "Any news?"))
+	}
+
+	repo := NewGmailDraftRepository(ts.GmailRepository(t))
+	_, err := repo.Create(context.Background(), &mail.Draft{Message: &mail.Message{
+		ThreadID: "thread_9",
+		To:       []mail.Address{{Email: "you@example.com"}},
+		Subject:  "Re: Plans",
+		Body:     "Any news?",
+	}})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if createdDraft == nil || createdDraft.Message.ThreadId != "thread_9" {
+		t.Errorf("draft was not created in thread_9: %+v", createdDraft)
+	}
+}
+
 // TestGmailDraftRepository_UpdateWithTestServer tests updating a draft.
 func TestGmailDraftRepository_UpdateWithTestServer(t *testing.T) {
 	ts := NewTestServer()
@@ -1267,8 +1632,8 @@ func TestGmailDraftRepository_UpdateWithTestServer(t *testing.T) {
 	draft := &mail.Draft{
 		ID: "draft123",
 		Message: &mail.Message{
-			From:    "me@example.com",
-			To:      []string{"you@example.com"},
+			From:    mail.Address{Email: "me@example.com"},
+			To:      []mail.Address{{Email: "you@example.com"}},
 			Subject: "Updated Subject",
 			Body:    "Updated body",
 		},
@@ -1819,8 +2184,8 @@ func TestBuildReplyMimeMessage(t *testing.T) {
 		{
 			name: "basic reply",
 			msg: &mail.Message{
-				From:    "sender@example.com",
-				To:      []string{"recipient@example.com"},
+				From:    mail.Address{Email: "sender@example.com"},
+				To:      []mail.Address{{Email: "recipient@example.com"}},
 				Subject: "Re: Test Subject",
 				Body:    "Reply body",
 			},
@@ -1834,12 +2199,28 @@ func TestBuildReplyMimeMessage(t *testing.T) {
 				"MIME-Version: 1.0",
 			},
 		},
+		{
+			name: "reply with bcc",
+			msg: &mail.Message{
+				From:    mail.Address{Email: "sender@example.com"},
+				To:      []mail.Address{{Email: "recipient@example.com"}},
+				Bcc:     []mail.Address{{Email: "dropbox@crm.example.com"}},
+				Subject: "Re: Test Subject",
+				Body:    "Reply body",
+			},
+			originalMessageID: "original-msg-123",
+			wantHeaders: []string{
+				"To: recipient@example.com",
+				"Bcc: dropbox@crm.example.com",
+				"In-Reply-To: <original-msg-123>",
+			},
+		},
 		{
 			name: "reply with cc",
 			msg: &mail.Message{
-				From:    "sender@example.com",
-				To:      []string{"recipient@example.com"},
-				Cc:      []string{"cc1@example.com", "cc2@example.com"},
+				From:    mail.Address{Email: "sender@example.com"},
+				To:      []mail.Address{{Email: "recipient@example.com"}},
+				Cc:      []mail.Address{{Email: "cc1@example.com"}, {Email: "cc2@example.com"}},
 				Subject: "Re: With CC",
 				Body:    "Reply with cc",
 			},
@@ -1851,8 +2232,8 @@ func TestBuildReplyMimeMessage(t *testing.T) {
 		{
 			name: "html reply",
 			msg: &mail.Message{
-				From:     "sender@example.com",
-				To:       []string{"recipient@example.com"},
+				From:     mail.Address{Email: "sender@example.com"},
+				To:       []mail.Address{{Email: "recipient@example.com"}},
 				Subject:  "Re: HTML Reply",
 				BodyHTML: "<p>HTML reply content</p>",
 			},
@@ -1880,8 +2261,8 @@ func TestBuildReplyMimeMessage(t *testing.T) {
 // TestBuildForwardBody tests forward body generation.
 func TestBuildForwardBody(t *testing.T) {
 	original := &mail.Message{
-		From:    "original@example.com",
-		To:      []string{"recipient@example.com", "another@example.com"},
+		From:    mail.Address{Email: "original@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}, {Email: "another@example.com"}},
 		Subject: "Original Subject",
 		Body:    "Original message body",
 		Date:    time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
@@ -1930,8 +2311,8 @@ func TestDomainMessageToGmail(t *testing.T) {
 			msg: &mail.Message{
 				ID:       "msg123",
 				ThreadID: "thread456",
-				From:     "sender@example.com",
-				To:       []string{"recipient@example.com"},
+				From:     mail.Address{Email: "sender@example.com"},
+				To:       []mail.Address{{Email: "recipient@example.com"}},
 				Subject:  "Test Subject",
 				Body:     "Message body",
 				Labels:   []string{"INBOX", "UNREAD"},
@@ -1941,8 +2322,8 @@ func TestDomainMessageToGmail(t *testing.T) {
 			name: "message with html",
 			msg: &mail.Message{
 				ID:       "msg789",
-				From:     "sender@example.com",
-				To:       []string{"recipient@example.com"},
+				From:     mail.Address{Email: "sender@example.com"},
+				To:       []mail.Address{{Email: "recipient@example.com"}},
 				Subject:  "HTML Message",
 				BodyHTML: "<p>HTML content</p>",
 			},
@@ -2016,6 +2397,99 @@ func TestGmailRepository_SearchWithTestServer(t *testing.T) {
 	}
 }
 
+func TestGmailRepository_SearchEach(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	var lists, gets int
+	ts.MessageListHandler = func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		if r.URL.Query().Get("pageToken") == "" {
+			WriteJSONResponse(w, MockMessageListResponse(
+				[]*gmail.Message{{Id: "msg1", ThreadId: "t1"}, {Id: "msg2", ThreadId: "t2"}}, "page2", 40))
+			return
+		}
+		WriteJSONResponse(w, MockMessageListResponse(
+			[]*gmail.Message{{Id: "msg3", ThreadId: "t3"}, {Id: "msg4", ThreadId: "t4"}}, "", 40))
+	}
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		gets++
+		WriteJSONResponse(w, MockMessageResponse(msgID, "t", "Subject "+msgID, "alice@example.com", "bob@example.com", "Hello"))
+	}
+	repo := ts.GmailRepository(t)
+	ctx := context.Background()
+
+	t.Run("follows pages", func(t *testing.T) {
+		lists, gets = 0, 0
+		var ids []string
+		total, warnings, err := repo.SearchEach(ctx, "in:inbox", mail.ListOptions{MaxResults: 2}, func(msg *mail.Message) error {
+			ids = append(ids, msg.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SearchEach failed: %v", err)
+		}
+		if total != 40 || strings.Join(ids, ",") != "msg1,msg2,msg3,msg4" || len(warnings) != 0 {
+			t.Errorf("got total %d, ids %v, warnings %v", total, ids, warnings)
+		}
+		if lists != 2 {
+			t.Errorf("expected 2 list calls, got %d", lists)
+		}
+	})
+
+	t.Run("stops without fetching more", func(t *testing.T) {
+		lists, gets = 0, 0
+		var ids []string
+		_, _, err := repo.SearchEach(ctx, "in:inbox", mail.ListOptions{MaxResults: 2}, func(msg *mail.Message) error {
+			ids = append(ids, msg.ID)
+			if len(ids) == 2 {
+				return mail.ErrStopSearch
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SearchEach failed: %v", err)
+		}
+		if lists != 1 || gets != 2 {
+			t.Errorf("expected 1 list and 2 gets, got %d and %d", lists, gets)
+		}
+	})
+
+	t.Run("returns callback errors", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, _, err := repo.SearchEach(ctx, "in:inbox", mail.ListOptions{MaxResults: 2}, func(*mail.Message) error { return boom })
+		if !errors.Is(err, boom) {
+			t.Errorf("expected the callback error, got %v", err)
+		}
+	})
+
+	t.Run("warns about partial messages", func(t *testing.T) {
+		ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+			if msgID == "msg2" {
+				WriteErrorResponse(w, http.StatusNotFound, "gone")
+				return
+			}
+			WriteJSONResponse(w, MockMessageResponse(msgID, "t", "Subject "+msgID, "alice@example.com", "bob@example.com", "Hello"))
+		}
+		var partial *mail.Message
+		_, warnings, err := repo.SearchEach(ctx, "in:inbox", mail.ListOptions{MaxResults: 2}, func(msg *mail.Message) error {
+			if msg.ID == "msg2" {
+				partial = msg
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SearchEach failed: %v", err)
+		}
+		if partial == nil || partial.ThreadID != "t2" || partial.Subject != "" {
+			t.Errorf("expected msg2 with only its IDs, got %+v", partial)
+		}
+		if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "message msg2: ") {
+			t.Errorf("unexpected warnings %v", warnings)
+		}
+	})
+}
+
 // TestGmailLabelRepository_GetError tests error handling for label get.
 func TestGmailLabelRepository_GetError(t *testing.T) {
 	ts := NewTestServer()
@@ -2140,8 +2614,8 @@ func TestGmailDraftRepository_UpdateNotFound(t *testing.T) {
 	_, err := repo.Update(ctx, &mail.Draft{
 		ID: "nonexistent",
 		Message: &mail.Message{
-			From:    "me@example.com",
-			To:      []string{"you@example.com"},
+			From:    mail.Address{Email: "me@example.com"},
+			To:      []mail.Address{{Email: "you@example.com"}},
 			Subject: "Test",
 			Body:    "Body",
 		},
@@ -2492,23 +2966,289 @@ func TestExtractBodyFromPart_Nil(t *testing.T) {
 	}
 }
 
-// TestParseRecipients_EdgeCases tests recipient parsing edge cases.
-func TestParseRecipients_EdgeCases(t *testing.T) {
+// textPart builds a leaf part carrying data, with the given Content-Type
+// and Content-Transfer-Encoding headers.
+func textPart(mimeType, contentType, encoding string, data []byte) *gmail.MessagePart {
+	part := &gmail.MessagePart{
+		MimeType: mimeType,
+		Headers:  []*gmail.MessagePartHeader{{Name: "Content-Type", Value: contentType}},
+		Body:     &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString(data)},
+	}
+	if encoding != "" {
+		part.Headers = append(part.Headers, &gmail.MessagePartHeader{Name: "Content-Transfer-Encoding", Value: encoding})
+	}
+	return part
+}
+
+// TestExtractBody_RealWorldMessages tests messages that used to render
+// with an empty or wrong body.
+func TestExtractBody_RealWorldMessages(t *testing.T) {
 	tests := []struct {
-		name     string
-		input    string
-		expected []string
+		name      string
+		payload   *gmail.MessagePart
+		wantPlain string
+		wantHTML  string
 	}{
 		{
-			name:     "empty string",
-			input:    "",
-			expected: []string{},
+			name: "mixed with attachments before the body",
+			payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Parts: []*gmail.MessagePart{
+					{
+						MimeType: "text/plain",
+						Filename: "notes.txt",
+						Headers:  []*gmail.MessagePartHeader{{Name: "Content-Disposition", Value: `attachment; filename="notes.txt"`}},
+						Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte("attached notes"))},
+					},
+					{
+						MimeType: "multipart/alternative",
+						Parts: []*gmail.MessagePart{
+							textPart("text/plain", "text/plain; charset=utf-8", "", []byte("The body")),
+							textPart("text/html", "text/html; charset=utf-8", "", []byte("<p>The body</p>")),
+						},
+					},
+					{MimeType: "application/pdf", Filename: "invoice.pdf", Body: &gmail.MessagePartBody{AttachmentId: "att1"}},
+				},
+			},
+			wantPlain: "The body",
+			wantHTML:  "<p>The body</p>",
 		},
 		{
-			name:     "single recipient",
-			input:    "user@example.com",
-			expected: []string{"user@example.com"},
-		},
+			name: "forwarded message only",
+			payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Parts: []*gmail.MessagePart{
+					{
+						MimeType: "message/rfc822",
+						Parts: []*gmail.MessagePart{
+							textPart("text/plain", "text/plain", "", []byte("Forwarded text")),
+						},
+					},
+				},
+			},
+			wantPlain: "Forwarded text",
+		},
+		{
+			name: "own body wins over a forwarded message",
+			payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Parts: []*gmail.MessagePart{
+					{
+						MimeType: "message/rfc822",
+						Parts:    []*gmail.MessagePart{textPart("text/plain", "text/plain", "", []byte("Forwarded text"))},
+					},
+					textPart("text/plain", "text/plain", "", []byte("See below")),
+				},
+			},
+			wantPlain: "See below",
+		},
+		{
+			name:      "ISO-8859-1",
+			payload:   textPart("text/plain", "text/plain; charset=ISO-8859-1", "", []byte("caf\xe9")),
+			wantPlain: "café",
+		},
+		{
+			name:      "Shift_JIS",
+			payload:   textPart("text/plain", "text/plain; charset=Shift_JIS", "", []byte("\x93\xfa\x96\x7b")),
+			wantPlain: "日本",
+		},
+		{
+			name:      "ISO-2022-JP",
+			payload:   textPart("text/plain", "text/plain; charset=ISO-2022-JP", "", []byte("\x1b$BF|K\\\x1b(B")),
+			wantPlain: "日本",
+		},
+		{
+			name:      "already converted to UTF-8",
+			payload:   textPart("text/plain", "text/plain; charset=ISO-8859-1", "", []byte("café")),
+			wantPlain: "café",
+		},
+		{
+			name:      "still quoted-printable",
+			payload:   textPart("text/plain", "text/plain; charset=ISO-8859-1", "quoted-printable", []byte("caf=E9 au lait, a long line that was =\r\nwrapped")),
+			wantPlain: "café au lait, a long line that was wrapped",
+		},
+		{
+			name:      "quoted-printable already decoded",
+			payload:   textPart("text/plain", "text/plain", "quoted-printable", []byte("https://example.com/?id=AB12&x=1")),
+			wantPlain: "https://example.com/?id=AB12&x=1",
+		},
+		{
+			name:     "still base64",
+			payload:  textPart("text/html", "text/html; charset=utf-8", "base64", []byte(base64.StdEncoding.EncodeToString([]byte("<b>Hi</b>")))),
+			wantHTML: "<b>Hi</b>",
+		},
+		{
+			name:      "base64 already decoded",
+			payload:   textPart("text/plain", "text/plain", "base64", []byte("Hello there")),
+			wantPlain: "Hello there",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, html := extractBody(tt.payload)
+			if plain != tt.wantPlain {
+				t.Errorf("plain = %q, want %q", plain, tt.wantPlain)
+			}
+			if html != tt.wantHTML {
+				t.Errorf("html = %q, want %q", html, tt.wantHTML)
+			}
+		})
+	}
+}
+
+// TestDecodeHeader tests RFC 2047 encoded-word decoding across charsets.
+func TestDecodeHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Weekly update", "Weekly update"},
+		{"UTF-8 B", "=?UTF-8?B?Q2Fmw6kgbWVudQ==?=", "Café menu"},
+		{"UTF-8 Q", "=?utf-8?Q?Gr=C3=BC=C3=9Fe?= from Köln", "Grüße from Köln"},
+		{"ISO-8859-1", "=?ISO-8859-1?Q?caf=E9?=", "café"},
+		{"Windows-1252", "=?windows-1252?Q?=80100?=", "€100"},
+		{"Shift_JIS", "=?Shift_JIS?B?k/qWew==?=", "日本"},
+		{"ISO-2022-JP", "=?ISO-2022-JP?B?GyRCRnxLXBsoQg==?=", "日本"},
+		{"adjacent words", "=?UTF-8?Q?a?= =?UTF-8?Q?b?=", "ab"},
+		{"display name", "=?UTF-8?Q?Jos=C3=A9?= <jose@example.com>", "José <jose@example.com>"},
+		{"unknown charset", "=?x-unknown?Q?abc?=", "=?x-unknown?Q?abc?="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeHeader(tt.value); got != tt.want {
+				t.Errorf("decodeHeader(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGmailMessageToDomain_EncodedHeaders tests that encoded-words Gmail
+// passes on are decoded in the message fields.
+func TestGmailMessageToDomain_EncodedHeaders(t *testing.T) {
+	msg := gmailMessageToDomain(&gmail.Message{
+		Id: "msg1",
+		Payload: &gmail.MessagePart{
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "From", Value: "=?ISO-8859-1?Q?Andr=E9?= <andre@example.com>"},
+				{Name: "To", Value: "=?UTF-8?B?5bGx55Sw?= <yamada@example.jp>"},
+				{Name: "Subject", Value: "=?UTF-8?B?5Lya6K2w?= agenda"},
+			},
+			Parts: []*gmail.MessagePart{
+				{Filename: "=?UTF-8?Q?r=C3=A9sum=C3=A9.pdf?=", MimeType: "application/pdf", Body: &gmail.MessagePartBody{AttachmentId: "att1"}},
+			},
+		},
+	})
+
+	if msg.From.String() != "André <andre@example.com>" {
+		t.Errorf("From = %q", msg.From)
+	}
+	if len(msg.To) != 1 || msg.To[0].String() != "山田 <yamada@example.jp>" {
+		t.Errorf("To = %q", msg.To)
+	}
+	if msg.Subject != "会議 agenda" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Filename != "résumé.pdf" {
+		t.Errorf("Attachments = %+v", msg.Attachments)
+	}
+}
+
+// TestGmailMessageToDomain_Bcc tests that the Bcc kept in the sender's
+// copy of a message is read.
+func TestGmailMessageToDomain_Bcc(t *testing.T) {
+	msg := gmailMessageToDomain(&gmail.Message{
+		Id: "msg1",
+		Payload: &gmail.MessagePart{
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "To", Value: "ana@example.com"},
+				{Name: "CC", Value: "bo@example.com"},
+				{Name: "Bcc", Value: "dropbox@crm.example.com, Cy <cy@example.com>"},
+			},
+		},
+	})
+
+	if len(msg.Cc) != 1 || msg.Cc[0].Email != "bo@example.com" {
+		t.Errorf("Cc = %v", msg.Cc)
+	}
+	if got := mail.AddressStrings(msg.Bcc); len(got) != 2 || msg.Bcc[1].Email != "cy@example.com" {
+		t.Errorf("Bcc = %v", got)
+	}
+}
+
+func TestGmailMessageToDomain_MessageIDs(t *testing.T) {
+	msg := gmailMessageToDomain(&gmail.Message{
+		Id: "msg1",
+		Payload: &gmail.MessagePart{
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "Message-Id", Value: "<c3@mail.example.com>"},
+				{Name: "References", Value: "<a1@mail.example.com>\r\n <b2@mail.example.com>"},
+			},
+		},
+	})
+
+	if msg.MessageID != "c3@mail.example.com" {
+		t.Errorf("MessageID = %q", msg.MessageID)
+	}
+	if len(msg.References) != 2 || msg.References[1] != "b2@mail.example.com" {
+		t.Errorf("References = %q", msg.References)
+	}
+}
+
+// TestBuildMimeMessage_EncodesHeaders tests that non-ASCII subjects and
+// names are sent as encoded-words and read back unchanged.
+func TestBuildMimeMessage_EncodesHeaders(t *testing.T) {
+	msg := &mail.Message{
+		From:    mail.Address{Name: "José Pérez", Email: "jose@example.com"},
+		To:      []mail.Address{{Name: "山田", Email: "yamada@example.jp"}, {Email: "ann@example.com"}},
+		Subject: "Café menu ☕",
+		Body:    "Hello",
+	}
+
+	raw := string(buildMimeMessage(msg))
+	head, _, _ := strings.Cut(raw, "\r\n\r\n")
+	for _, line := range strings.Split(head, "\r\n") {
+		for _, r := range line {
+			if r >= utf8.RuneSelf {
+				t.Fatalf("header line %q is not ASCII", line)
+			}
+		}
+	}
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeHeader(parsed.Header.Get("Subject")); got != msg.Subject {
+		t.Errorf("Subject = %q, want %q", got, msg.Subject)
+	}
+	to, err := parsed.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[0].Name != "山田" || to[1].Address != "ann@example.com" {
+		t.Errorf("To = %v, %v", to, err)
+	}
+	from, err := parsed.Header.AddressList("From")
+	if err != nil || from[0].Name != "José Pérez" {
+		t.Errorf("From = %v, %v", from, err)
+	}
+}
+
+// TestParseRecipients_EdgeCases tests recipient parsing edge cases.
+func TestParseRecipients_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "empty string",
+			input:    "",
+			expected: []string{},
+		},
+		{
+			name:     "single recipient",
+			input:    "user@example.com",
+			expected: []string{"user@example.com"},
+		},
 		{
 			name:     "multiple with whitespace",
 			input:    "  user1@example.com  ,  user2@example.com  ",
@@ -2519,6 +3259,11 @@ func TestParseRecipients_EdgeCases(t *testing.T) {
 			input:    "user@example.com,",
 			expected: []string{"user@example.com"},
 		},
+		{
+			name:     "display name with comma",
+			input:    `"Doe, Jane" <jane@example.com>, Bob <bob@example.com>`,
+			expected: []string{`"Doe, Jane" <jane@example.com>`, "Bob <bob@example.com>"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -2529,7 +3274,7 @@ func TestParseRecipients_EdgeCases(t *testing.T) {
 				return
 			}
 			for i, r := range result {
-				if r != tt.expected[i] {
+				if r.String() != tt.expected[i] {
 					t.Errorf("result[%d] = %q, want %q", i, r, tt.expected[i])
 				}
 			}
@@ -2798,8 +3543,8 @@ func TestGmailRepository_ReplyWithTestServer(t *testing.T) {
 	ctx := context.Background()
 
 	reply := &mail.Message{
-		From:    "bob@example.com",
-		To:      []string{"alice@example.com"},
+		From:    mail.Address{Email: "bob@example.com"},
+		To:      []mail.Address{{Email: "alice@example.com"}},
 		Subject: "Re: Original Subject",
 		Body:    "This is my reply",
 	}
@@ -2849,8 +3594,8 @@ func TestGmailRepository_ReplyOriginalNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	reply := &mail.Message{
-		From:    "bob@example.com",
-		To:      []string{"alice@example.com"},
+		From:    mail.Address{Email: "bob@example.com"},
+		To:      []mail.Address{{Email: "alice@example.com"}},
 		Subject: "Re: Some Subject",
 		Body:    "Reply body",
 	}
@@ -2887,8 +3632,8 @@ func TestGmailRepository_ReplySendError(t *testing.T) {
 	ctx := context.Background()
 
 	reply := &mail.Message{
-		From:    "bob@example.com",
-		To:      []string{"alice@example.com"},
+		From:    mail.Address{Email: "bob@example.com"},
+		To:      []mail.Address{{Email: "alice@example.com"}},
 		Subject: "Re: Original Subject",
 		Body:    "Reply body",
 	}
@@ -2938,8 +3683,8 @@ func TestGmailRepository_ForwardWithTestServer(t *testing.T) {
 	ctx := context.Background()
 
 	forward := &mail.Message{
-		From: "bob@example.com",
-		To:   []string{"charlie@example.com"},
+		From: mail.Address{Email: "bob@example.com"},
+		To:   []mail.Address{{Email: "charlie@example.com"}},
 		Body: "FYI - see below",
 	}
 
@@ -3009,8 +3754,8 @@ func TestGmailRepository_ForwardWithCustomSubject(t *testing.T) {
 	ctx := context.Background()
 
 	forward := &mail.Message{
-		From:    "bob@example.com",
-		To:      []string{"charlie@example.com"},
+		From:    mail.Address{Email: "bob@example.com"},
+		To:      []mail.Address{{Email: "charlie@example.com"}},
 		Subject: "Custom Forward Subject", // Custom subject instead of auto-generated
 		Body:    "Check this out",
 	}
@@ -3040,8 +3785,8 @@ func TestGmailRepository_ForwardOriginalNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	forward := &mail.Message{
-		From: "bob@example.com",
-		To:   []string{"charlie@example.com"},
+		From: mail.Address{Email: "bob@example.com"},
+		To:   []mail.Address{{Email: "charlie@example.com"}},
 		Body: "Forwarding",
 	}
 
@@ -3091,8 +3836,8 @@ func TestGmailRepository_ForwardEmptyBody(t *testing.T) {
 
 	// Forward with empty body - should just include original message
 	forward := &mail.Message{
-		From: "bob@example.com",
-		To:   []string{"charlie@example.com"},
+		From: mail.Address{Email: "bob@example.com"},
+		To:   []mail.Address{{Email: "charlie@example.com"}},
 		Body: "", // Empty body
 	}
 
@@ -3144,8 +3889,8 @@ func TestGmailRepository_ReplyWithHTMLBody(t *testing.T) {
 	ctx := context.Background()
 
 	reply := &mail.Message{
-		From:     "bob@example.com",
-		To:       []string{"alice@example.com"},
+		From:     mail.Address{Email: "bob@example.com"},
+		To:       []mail.Address{{Email: "alice@example.com"}},
 		Subject:  "Re: Original Subject",
 		BodyHTML: "<p>This is an <strong>HTML</strong> reply</p>",
 	}
@@ -3164,3 +3909,640 @@ func TestGmailRepository_ReplyWithHTMLBody(t *testing.T) {
 		t.Error("HTML reply should contain HTML content")
 	}
 }
+
+// TestExtractAttachments tests collecting attachment metadata from nested parts.
+func TestExtractAttachments(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/mixed",
+		Parts: []*gmail.MessagePart{
+			{
+				MimeType: "multipart/alternative",
+				Parts: []*gmail.MessagePart{
+					{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+				},
+			},
+			{
+				MimeType: "application/pdf",
+				Filename: "invoice.pdf",
+				Body:     &gmail.MessagePartBody{AttachmentId: "att1", Size: 2048},
+			},
+			{
+				MimeType: "multipart/related",
+				Parts: []*gmail.MessagePart{
+					{
+						MimeType: "image/png",
+						Filename: "logo.png",
+						Body:     &gmail.MessagePartBody{AttachmentId: "att2", Size: 512},
+					},
+				},
+			},
+			{
+				// Filename without an attachment ID is inline data, not an attachment
+				MimeType: "text/plain",
+				Filename: "notes.txt",
+				Body:     &gmail.MessagePartBody{Data: "bm90ZXM="},
+			},
+		},
+	}
+
+	attachments := extractAttachments(payload)
+
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got %d", len(attachments))
+	}
+	if attachments[0].ID != "att1" || attachments[0].Filename != "invoice.pdf" || attachments[0].Size != 2048 {
+		t.Errorf("attachments[0] = %+v, want att1 invoice.pdf 2048", attachments[0])
+	}
+	if attachments[1].ID != "att2" || attachments[1].MimeType != "image/png" {
+		t.Errorf("attachments[1] = %+v, want att2 image/png", attachments[1])
+	}
+
+	if got := extractAttachments(nil); got != nil {
+		t.Errorf("expected nil for nil payload, got %v", got)
+	}
+}
+
+// TestGmailAttachmentRepository_GetWithTestServer tests downloading an attachment.
+func TestGmailAttachmentRepository_GetWithTestServer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	content := []byte("%PDF-1.4 invoice contents")
+
+	ts.AttachmentGetHandler = func(w http.ResponseWriter, r *http.Request, msgID, attachmentID string) {
+		if msgID != "msg1" || attachmentID != "att1" {
+			WriteErrorResponse(w, http.StatusNotFound, "Requested entity was not found.")
+			return
+		}
+		WriteJSONResponse(w, &gmail.MessagePartBody{
+			AttachmentId: attachmentID,
+			Data:         base64.RawURLEncoding.EncodeToString(content),
+			Size:         int64(len(content)),
+		})
+	}
+
+	repo := NewGmailAttachmentRepository(ts.GmailRepository(t))
+	ctx := context.Background()
+
+	data, err := repo.Get(ctx, "msg1", "att1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(data) != string(content) {
+		t.Errorf("data = %q, want %q", data, content)
+	}
+
+	_, err = repo.Get(ctx, "msg1", "missing")
+	if !errors.Is(err, mail.ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+// TestDecodeAttachmentData tests decoding padded and unpadded base64url data.
+func TestDecodeAttachmentData(t *testing.T) {
+	want := "attachment?"
+	for _, encoded := range []string{
+		base64.URLEncoding.EncodeToString([]byte(want)),
+		base64.RawURLEncoding.EncodeToString([]byte(want)),
+	} {
+		got, err := decodeAttachmentData(encoded)
+		if err != nil {
+			t.Fatalf("decodeAttachmentData(%q) error: %v", encoded, err)
+		}
+		if string(got) != want {
+			t.Errorf("decodeAttachmentData(%q) = %q, want %q", encoded, got, want)
+		}
+	}
+
+	if _, err := decodeAttachmentData("!!not base64!!"); err == nil {
+		t.Error("expected error for invalid data")
+	}
+}
+
+// TestBuildMimeMessage_InlineImages tests multipart/related output for inline images.
+func TestBuildMimeMessage_InlineImages(t *testing.T) {
+	image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 40)
+	msg := &mail.Message{
+		From:     mail.Address{Email: "sender@example.com"},
+		To:       []mail.Address{{Email: "recipient@example.com"}},
+		Subject:  "Report",
+		BodyHTML: `<p>Chart:</p><img src="cid:chart">`,
+		Attachments: []*mail.Attachment{
+			{Filename: "chart.png", MimeType: "image/png", ContentID: "chart", Data: image},
+		},
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(buildMimeMessage(msg)))
+	if err != nil {
+		t.Fatalf("failed to parse MIME message: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type: %v", err)
+	}
+	if mediaType != "multipart/related" {
+		t.Fatalf("Content-Type = %q, want multipart/related", mediaType)
+	}
+	if params["type"] != "text/html" {
+		t.Errorf("type parameter = %q, want text/html", params["type"])
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	htmlPart, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read HTML part: %v", err)
+	}
+	if !strings.HasPrefix(htmlPart.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("first part Content-Type = %q, want text/html", htmlPart.Header.Get("Content-Type"))
+	}
+	html, _ := io.ReadAll(htmlPart)
+	if string(html) != msg.BodyHTML {
+		t.Errorf("HTML part = %q, want %q", html, msg.BodyHTML)
+	}
+
+	imagePart, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read image part: %v", err)
+	}
+	if got := imagePart.Header.Get("Content-ID"); got != "<chart>" {
+		t.Errorf("Content-ID = %q, want <chart>", got)
+	}
+	if got := imagePart.Header.Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if got := imagePart.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "inline") {
+		t.Errorf("Content-Disposition = %q, want inline", got)
+	}
+	encoded, _ := io.ReadAll(imagePart)
+	for _, line := range strings.Split(string(encoded), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("base64 line exceeds 76 characters: %d", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("failed to decode image part: %v", err)
+	}
+	if !bytes.Equal(decoded, image) {
+		t.Error("decoded image does not match original data")
+	}
+
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected only two parts, got err %v", err)
+	}
+}
+
+// TestBuildMimeMessage_InlineRequiresHTML tests that inline images are ignored for plain text.
+func TestBuildMimeMessage_InlineRequiresHTML(t *testing.T) {
+	msg := &mail.Message{
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
+		Subject: "Plain",
+		Body:    "No HTML here",
+		Attachments: []*mail.Attachment{
+			{Filename: "chart.png", MimeType: "image/png", ContentID: "chart", Data: []byte("png")},
+		},
+	}
+
+	got := string(buildMimeMessage(msg))
+	if !strings.Contains(got, "Content-Type: text/plain; charset=\"utf-8\"") {
+		t.Errorf("expected plain text message, got:\n%s", got)
+	}
+	if strings.Contains(got, "multipart/related") {
+		t.Error("plain text message should not be multipart/related")
+	}
+}
+
+// TestBuildMimeMessage_ExtraHeaders tests that extra headers are written
+// before the body, with non-ASCII values encoded.
+func TestBuildMimeMessage_ExtraHeaders(t *testing.T) {
+	msg := &mail.Message{
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
+		Subject: "Launch",
+		Body:    "Hello",
+		Headers: []mail.Header{
+			{Name: "X-Campaign", Value: "launch"},
+			{Name: "X-Note", Value: "Grüße"},
+		},
+	}
+
+	got := string(buildMimeMessage(msg))
+	head, _, _ := strings.Cut(got, "\r\n\r\n")
+	if !strings.Contains(head, "\r\nX-Campaign: launch\r\n") {
+		t.Errorf("expected X-Campaign header, got:\n%s", head)
+	}
+	if !strings.Contains(head, "\r\nX-Note: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n") {
+		t.Errorf("expected an encoded X-Note header, got:\n%s", head)
+	}
+
+	reply := string(buildReplyMimeMessage(msg, "orig123"))
+	if head, _, _ := strings.Cut(reply, "\r\n\r\n"); !strings.Contains(head, "X-Campaign: launch") {
+		t.Errorf("expected X-Campaign header in reply, got:\n%s", head)
+	}
+}
+
+// TestBuildMimeMessage_References tests that a message with References is
+// written as a reply to the last of them.
+func TestBuildMimeMessage_References(t *testing.T) {
+	msg := &mail.Message{
+		From:       mail.Address{Email: "sender@example.com"},
+		To:         []mail.Address{{Email: "recipient@example.com"}},
+		Subject:    "Re: Launch",
+		Body:       "Any news?",
+		References: []string{"a1@mail.example.com", "b2@mail.example.com"},
+	}
+
+	head, _, _ := strings.Cut(string(buildMimeMessage(msg)), "\r\n\r\n")
+	if !strings.Contains(head, "\r\nIn-Reply-To: <b2@mail.example.com>\r\n") {
+		t.Errorf("expected In-Reply-To header, got:\n%s", head)
+	}
+	if !strings.Contains(head, "\r\nReferences: <a1@mail.example.com> <b2@mail.example.com>\r\n") {
+		t.Errorf("expected References header, got:\n%s", head)
+	}
+
+	msg.References = nil
+	if head, _, _ := strings.Cut(string(buildMimeMessage(msg)), "\r\n\r\n"); strings.Contains(head, "In-Reply-To") || strings.Contains(head, "References") {
+		t.Errorf("unexpected threading headers without References:\n%s", head)
+	}
+}
+
+// TestBuildReplyMimeMessage_InlineImages tests that replies also support inline images.
+func TestBuildReplyMimeMessage_InlineImages(t *testing.T) {
+	msg := &mail.Message{
+		From:     mail.Address{Email: "sender@example.com"},
+		To:       []mail.Address{{Email: "recipient@example.com"}},
+		Subject:  "Re: Report",
+		BodyHTML: `<img src="cid:logo">`,
+		Attachments: []*mail.Attachment{
+			{Filename: "logo.png", MimeType: "image/png", ContentID: "logo", Data: []byte("png")},
+		},
+	}
+
+	got := string(buildReplyMimeMessage(msg, "orig123"))
+	if !strings.Contains(got, "In-Reply-To: <orig123>") {
+		t.Error("expected In-Reply-To header")
+	}
+	if !strings.Contains(got, "multipart/related") || !strings.Contains(got, "Content-Id: <logo>") {
+		t.Errorf("expected multipart/related body with logo part, got:\n%s", got)
+	}
+}
+
+// TestExtractAttachments_ContentID tests that inline content IDs are captured.
+func TestExtractAttachments_ContentID(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/related",
+		Parts: []*gmail.MessagePart{
+			{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: "PHA-"}},
+			{
+				MimeType: "image/png",
+				Filename: "chart.png",
+				Headers:  []*gmail.MessagePartHeader{{Name: "Content-ID", Value: "<chart>"}},
+				Body:     &gmail.MessagePartBody{AttachmentId: "att1"},
+			},
+		},
+	}
+
+	attachments := extractAttachments(payload)
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(attachments))
+	}
+	if attachments[0].ContentID != "chart" || !attachments[0].IsInline() {
+		t.Errorf("ContentID = %q, want chart", attachments[0].ContentID)
+	}
+}
+
+func TestExtractDeliveryReport(t *testing.T) {
+	dsn := "Reporting-MTA: dns; googlemail.com\r\n\r\n" +
+		"Final-Recipient: rfc822; gone@example.com\r\n" +
+		"Action: failed\r\n" +
+		"Status: 5.1.1\r\n"
+
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/report",
+		Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+			{
+				MimeType: "message/delivery-status",
+				Body:     &gmail.MessagePartBody{Data: base64.RawURLEncoding.EncodeToString([]byte(dsn))},
+			},
+		},
+	}
+
+	report := extractDeliveryReport(payload)
+	if report == nil {
+		t.Fatal("expected report, got nil")
+	}
+	if !report.IsBounce() {
+		t.Error("expected report to be a bounce")
+	}
+	if got := report.FailedRecipients(); len(got) != 1 || got[0].Recipient != "gone@example.com" {
+		t.Errorf("FailedRecipients() = %+v, want gone@example.com", got)
+	}
+
+	msg := gmailMessageToDomain(&gmail.Message{Id: "m1", Payload: payload})
+	if !msg.IsBounce() {
+		t.Error("expected converted message to be a bounce")
+	}
+}
+
+func TestExtractDeliveryReport_MDN(t *testing.T) {
+	mdn := "Final-Recipient: rfc822; reader@example.com\n" +
+		"Original-Message-ID: <abc@example.com>\n" +
+		"Disposition: manual-action/MDN-sent-manually; displayed\n"
+
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/report",
+		Parts: []*gmail.MessagePart{
+			{
+				MimeType: "message/disposition-notification",
+				Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(mdn))},
+			},
+		},
+	}
+
+	report := extractDeliveryReport(payload)
+	if report == nil || !report.IsReadReceipt() {
+		t.Fatalf("expected read receipt, got %+v", report)
+	}
+	if report.OriginalMessageID != "abc@example.com" {
+		t.Errorf("OriginalMessageID = %q, want abc@example.com", report.OriginalMessageID)
+	}
+}
+
+func TestExtractDeliveryReport_None(t *testing.T) {
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/alternative",
+		Parts: []*gmail.MessagePart{
+			{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+		},
+	}
+
+	if got := extractDeliveryReport(payload); got != nil {
+		t.Errorf("expected nil report, got %+v", got)
+	}
+	if got := extractDeliveryReport(nil); got != nil {
+		t.Errorf("expected nil report for nil payload, got %+v", got)
+	}
+}
+
+// TestGmailRepository_GetRawWithTestServer tests fetching raw message content.
+func TestGmailRepository_GetRawWithTestServer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	content := "From: me@example.com\r\nTo: typo@example.com\r\nSubject: Hi\r\n\r\nHello"
+	var format string
+
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		format = r.URL.Query().Get("format")
+		if msgID != "msg1" {
+			WriteErrorResponse(w, http.StatusNotFound, "message not found")
+			return
+		}
+		WriteJSONResponse(w, &gmail.Message{
+			Id:  "msg1",
+			Raw: base64.RawURLEncoding.EncodeToString([]byte(content)),
+		})
+	}
+
+	repo := ts.GmailRepository(t)
+
+	raw, err := repo.GetRaw(context.Background(), "msg1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != content {
+		t.Errorf("raw = %q, want %q", raw, content)
+	}
+	if format != "raw" {
+		t.Errorf("format = %q, want raw", format)
+	}
+
+	_, err = repo.GetRaw(context.Background(), "missing")
+	if !errors.Is(err, mail.ErrMessageNotFound) {
+		t.Errorf("expected ErrMessageNotFound, got %v", err)
+	}
+}
+
+// TestGmailRepository_SendRawWithTestServer tests sending prebuilt message content.
+func TestGmailRepository_SendRawWithTestServer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	var sentRaw string
+	ts.MessageSendHandler = func(w http.ResponseWriter, r *http.Request) {
+		var msg gmail.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		sentRaw = msg.Raw
+		WriteJSONResponse(w, &gmail.Message{Id: "sent1", LabelIds: []string{"SENT"}})
+	}
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		WriteJSONResponse(w, MockMessageResponse(msgID, "thread1", "Hi", "me@example.com", "fixed@example.com", "Hello"))
+	}
+
+	repo := ts.GmailRepository(t)
+
+	content := []byte("To: fixed@example.com\r\nSubject: Hi\r\n\r\nHello")
+	sent, err := repo.SendRaw(context.Background(), content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent.ID != "sent1" {
+		t.Errorf("sent.ID = %q, want sent1", sent.ID)
+	}
+
+	decoded, err := decodeAttachmentData(sentRaw)
+	if err != nil {
+		t.Fatalf("failed to decode sent raw: %v", err)
+	}
+	if string(decoded) != string(content) {
+		t.Errorf("sent raw = %q, want %q", decoded, content)
+	}
+}
+
+// TestGmailRepository_ImportWithTestServer tests importing message content
+// with labels.
+func TestGmailRepository_ImportWithTestServer(t *testing.T) {
+	ts := NewTestServer()
+	defer ts.Close()
+
+	var imported gmail.Message
+	var query url.Values
+	ts.MessageImportHandler = func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if err := json.NewDecoder(r.Body).Decode(&imported); err != nil {
+			WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		WriteJSONResponse(w, &gmail.Message{Id: "imp1", LabelIds: imported.LabelIds})
+	}
+	ts.MessageGetHandler = func(w http.ResponseWriter, r *http.Request, msgID string) {
+		WriteJSONResponse(w, MockMessageResponse(msgID, "thread1", "Hi", "boss@work.example", "me@work.example", "Hello"))
+	}
+
+	repo := ts.GmailRepository(t)
+
+	content := []byte("From: boss@work.example\r\nSubject: Hi\r\n\r\nHello")
+	msg, err := repo.Import(context.Background(), content, []string{"INBOX", "Label_7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.ID != "imp1" {
+		t.Errorf("msg.ID = %q, want imp1", msg.ID)
+	}
+	if strings.Join(imported.LabelIds, ",") != "INBOX,Label_7" {
+		t.Errorf("label IDs = %v", imported.LabelIds)
+	}
+	if query.Get("internalDateSource") != "dateHeader" || query.Get("neverMarkSpam") != "true" {
+		t.Errorf("unexpected query %v", query)
+	}
+	decoded, err := decodeAttachmentData(imported.Raw)
+	if err != nil || string(decoded) != string(content) {
+		t.Errorf("imported raw = %q, %v", decoded, err)
+	}
+}
+
+// TestBuildMimeMessage_FileAttachments tests that file attachments wrap the body in multipart/mixed.
+func TestBuildMimeMessage_FileAttachments(t *testing.T) {
+	report := bytes.Repeat([]byte("col1,col2\n"), 20)
+	msg := &mail.Message{
+		From:     mail.Address{Email: "sender@example.com"},
+		To:       []mail.Address{{Email: "recipient@example.com"}},
+		Subject:  "Export",
+		BodyHTML: `<p>See chart</p><img src="cid:chart">`,
+		Attachments: []*mail.Attachment{
+			{Filename: "chart.png", MimeType: "image/png", ContentID: "chart", Data: []byte("png")},
+			{Filename: "report 1.csv", MimeType: "text/csv", Data: report},
+			// Attachments without data are metadata only and are skipped
+			{ID: "att1", Filename: "remote.pdf", MimeType: "application/pdf"},
+		},
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(buildMimeMessage(msg)))
+	if err != nil {
+		t.Fatalf("failed to parse MIME message: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q (%v), want multipart/mixed", mediaType, err)
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	bodyPart, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read body part: %v", err)
+	}
+	if !strings.HasPrefix(bodyPart.Header.Get("Content-Type"), "multipart/related") {
+		t.Errorf("body part Content-Type = %q, want multipart/related", bodyPart.Header.Get("Content-Type"))
+	}
+
+	filePart, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read attachment part: %v", err)
+	}
+	if got := filePart.FileName(); got != "report 1.csv" {
+		t.Errorf("attachment filename = %q, want %q", got, "report 1.csv")
+	}
+	if got := filePart.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "attachment") {
+		t.Errorf("Content-Disposition = %q, want attachment", got)
+	}
+	encoded, _ := io.ReadAll(filePart)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil {
+		t.Fatalf("failed to decode attachment: %v", err)
+	}
+	if !bytes.Equal(decoded, report) {
+		t.Error("decoded attachment does not match original data")
+	}
+
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected only two parts, got err %v", err)
+	}
+}
+
+// TestBuildMimeMessage_PlainWithAttachment tests a plain text body with a file attachment.
+func TestBuildMimeMessage_MessageAttachment(t *testing.T) {
+	original := "From: phisher@example.net\r\nSubject: Verify your account\r\n\r\nClick here\r\n"
+	msg := &mail.Message{
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "abuse@example.com"}},
+		Subject: "Fwd: Verify your account",
+		Attachments: []*mail.Attachment{
+			{Filename: "Verify your account.eml", MimeType: "message/rfc822", Data: []byte(original)},
+		},
+	}
+
+	got := string(buildMimeMessage(msg))
+	for _, want := range []string{
+		"Content-Type: message/rfc822; name=\"Verify your account.eml\"\r\nContent-Transfer-Encoding: 8bit\r\n",
+		"\r\n\r\n" + original + "--",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestBuildMimeMessage_PlainWithAttachment(t *testing.T) {
+	msg := &mail.Message{
+		From:    mail.Address{Email: "sender@example.com"},
+		To:      []mail.Address{{Email: "recipient@example.com"}},
+		Subject: "Plain",
+		Body:    "See attached",
+		Attachments: []*mail.Attachment{
+			{Filename: "data.bin", Data: []byte{0, 1, 2}},
+		},
+	}
+
+	got := string(buildMimeMessage(msg))
+	for _, want := range []string{
+		"Content-Type: multipart/mixed",
+		"Content-Type: text/plain; charset=\"utf-8\"\r\n\r\nSee attached",
+		"Content-Type: application/octet-stream; name=data.bin",
+		"Content-Disposition: attachment; filename=data.bin",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestExtractCalendar(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nMETHOD:REQUEST\r\nEND:VCALENDAR\r\n"
+	payload := &gmail.MessagePart{
+		MimeType: "multipart/mixed",
+		Parts: []*gmail.MessagePart{
+			{
+				MimeType: "multipart/alternative",
+				Parts: []*gmail.MessagePart{
+					{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "aGk="}},
+					{
+						MimeType: "text/calendar",
+						Headers:  []*gmail.MessagePartHeader{{Name: "Content-Type", Value: `text/calendar; charset="UTF-8"; method=REQUEST`}},
+						Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(ics))},
+					},
+				},
+			},
+			{MimeType: "application/ics", Filename: "invite.ics", Body: &gmail.MessagePartBody{AttachmentId: "att1"}},
+		},
+	}
+
+	msg := gmailMessageToDomain(&gmail.Message{Id: "m1", Payload: payload})
+	if msg.Calendar != ics {
+		t.Errorf("Calendar = %q, want the text/calendar part", msg.Calendar)
+	}
+	if msg.Body != "hi" {
+		t.Errorf("Body = %q, want the text/plain part", msg.Body)
+	}
+	if got := extractCalendar(payload.Parts[0].Parts[0]); got != "" {
+		t.Errorf("extractCalendar() = %q for a message without a calendar part", got)
+	}
+}
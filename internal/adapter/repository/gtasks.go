@@ -7,7 +7,6 @@ import (
 
 	domaintasks "github.com/stainedhead/go-goog-cli/internal/domain/tasks"
 	"golang.org/x/oauth2"
-	"google.golang.org/api/option"
 	"google.golang.org/api/tasks/v1"
 )
 
@@ -36,9 +35,7 @@ var (
 
 // NewGTasksRepository creates a new GTasksRepository with the given OAuth2 token source.
 func NewGTasksRepository(ctx context.Context, tokenSource oauth2.TokenSource) (*GTasksRepository, error) {
-	httpClient := oauth2.NewClient(ctx, tokenSource)
-
-	service, err := tasks.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err := tasks.NewService(ctx, clientOptions(ctx, tokenSource, ServiceTasks)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Tasks service: %w", err)
 	}
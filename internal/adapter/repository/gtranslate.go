@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"google.golang.org/api/option"
+	"google.golang.org/api/translate/v2"
+)
+
+// GTranslateRepository translates text with the Google Cloud Translation
+// API (v2), authenticating with an API key rather than the account's OAuth
+// token.
+type GTranslateRepository struct {
+	service     *translate.Service
+	maxRetries  int
+	baseBackoff time.Duration
+}
+
+// NewGTranslateRepository creates a new GTranslateRepository that sends
+// apiKey with every request. Translating changes no account data, so the
+// repository works in read-only mode too.
+func NewGTranslateRepository(ctx context.Context, apiKey string) (*GTranslateRepository, error) {
+	rt := &apiKeyTransport{key: apiKey, base: currentTransport()}
+	opts := []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: rt})}
+	if url := Endpoint(ServiceTranslate); url != "" {
+		opts = append(opts, option.WithEndpoint(url))
+	}
+	service, err := translate.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Translation service: %w", err)
+	}
+	return NewGTranslateRepositoryWithService(service), nil
+}
+
+// NewGTranslateRepositoryWithService creates a GTranslateRepository with a pre-configured service.
+// This is useful for testing with mock servers.
+func NewGTranslateRepositoryWithService(service *translate.Service) *GTranslateRepository {
+	return &GTranslateRepository{
+		service:     service,
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBaseBackoff,
+	}
+}
+
+// Translate translates texts into target, detecting their language.
+func (r *GTranslateRepository) Translate(ctx context.Context, texts []string, target string) ([]mail.TranslatedText, error) {
+	resp, err := retryWithBackoff(ctx, r.maxRetries, r.baseBackoff, func() (*translate.TranslationsListResponse, error) {
+		return r.service.Translations.Translate(&translate.TranslateTextRequest{
+			Q:      texts,
+			Target: target,
+			Format: "text",
+		}).Context(ctx).Do()
+	})
+	if err != nil {
+		return nil, mapAPIError(err, "translation")
+	}
+	if len(resp.Translations) != len(texts) {
+		return nil, fmt.Errorf("translation returned %d results for %d texts", len(resp.Translations), len(texts))
+	}
+
+	result := make([]mail.TranslatedText, len(texts))
+	for i, t := range resp.Translations {
+		result[i] = mail.TranslatedText{Text: t.TranslatedText, SourceLanguage: t.DetectedSourceLanguage}
+	}
+	return result, nil
+}
+
+// apiKeyTransport adds an API key to each request's query string.
+type apiKeyTransport struct {
+	key  string
+	base http.RoundTripper
+}
+
+// RoundTrip sends a copy of req with the key parameter set.
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	clone := req.Clone(req.Context())
+	query := clone.URL.Query()
+	query.Set("key", t.key)
+	clone.URL.RawQuery = query.Encode()
+	return base.RoundTrip(clone)
+}
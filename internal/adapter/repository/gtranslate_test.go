@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/translate/v2"
+)
+
+func TestGTranslateRepository_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("key"); got != "secret" {
+			WriteErrorResponse(w, http.StatusForbidden, "API key not valid")
+			return
+		}
+		var body struct {
+			Data translate.TranslateTextRequest `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		req := body.Data
+		if req.Target != "en" || req.Format != "text" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		resp := &translate.TranslationsListResponse{}
+		for _, q := range req.Q {
+			resp.Translations = append(resp.Translations, &translate.TranslationsResource{
+				TranslatedText:         strings.ToUpper(q),
+				DetectedSourceLanguage: "fr",
+			})
+		}
+		WriteJSONResponse(w, map[string]any{"data": resp})
+	}))
+	defer server.Close()
+
+	SetEndpoints(map[string]string{ServiceTranslate: server.URL + "/"})
+	t.Cleanup(func() { SetEndpoints(nil) })
+	ctx := context.Background()
+
+	repo, err := NewGTranslateRepository(ctx, "secret")
+	if err != nil {
+		t.Fatalf("NewGTranslateRepository failed: %v", err)
+	}
+	got, err := repo.Translate(ctx, []string{"bonjour", "merci"}, "en")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Text != "BONJOUR" || got[1].Text != "MERCI" || got[0].SourceLanguage != "fr" {
+		t.Errorf("Translate() = %+v", got)
+	}
+
+	t.Run("read-only mode allows translating", func(t *testing.T) {
+		SetReadOnly(true)
+		t.Cleanup(func() { SetReadOnly(false) })
+		repo, err := NewGTranslateRepository(ctx, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.Translate(ctx, []string{"salut"}, "en"); err != nil {
+			t.Errorf("expected translation in read-only mode, got %v", err)
+		}
+	})
+
+	t.Run("bad key", func(t *testing.T) {
+		repo, err := NewGTranslateRepository(ctx, "wrong")
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo.baseBackoff = 0
+		if _, err := repo.Translate(ctx, []string{"salut"}, "en"); err == nil || !strings.Contains(err.Error(), "API key not valid") {
+			t.Errorf("expected the API error, got %v", err)
+		}
+	})
+}
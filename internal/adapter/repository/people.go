@@ -8,7 +8,6 @@ import (
 
 	"github.com/stainedhead/go-goog-cli/internal/domain/contacts"
 	"golang.org/x/oauth2"
-	"google.golang.org/api/option"
 	"google.golang.org/api/people/v1"
 )
 
@@ -43,9 +42,7 @@ var (
 
 // NewPeopleRepository creates a new PeopleRepository with the given OAuth2 token source.
 func NewPeopleRepository(ctx context.Context, tokenSource oauth2.TokenSource) (*PeopleRepository, error) {
-	httpClient := oauth2.NewClient(ctx, tokenSource)
-
-	service, err := people.NewService(ctx, option.WithHTTPClient(httpClient))
+	service, err := people.NewService(ctx, clientOptions(ctx, tokenSource, ServicePeople)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create People service: %w", err)
 	}
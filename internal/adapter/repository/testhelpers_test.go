@@ -34,10 +34,12 @@ type TestServer struct {
 	MessageListHandler    func(w http.ResponseWriter, r *http.Request)
 	MessageGetHandler     func(w http.ResponseWriter, r *http.Request, msgID string)
 	MessageSendHandler    func(w http.ResponseWriter, r *http.Request)
+	MessageImportHandler  func(w http.ResponseWriter, r *http.Request)
 	MessageTrashHandler   func(w http.ResponseWriter, r *http.Request, msgID string)
 	MessageUntrashHandler func(w http.ResponseWriter, r *http.Request, msgID string)
 	MessageModifyHandler  func(w http.ResponseWriter, r *http.Request, msgID string)
 	MessageDeleteHandler  func(w http.ResponseWriter, r *http.Request, msgID string)
+	AttachmentGetHandler  func(w http.ResponseWriter, r *http.Request, msgID, attachmentID string)
 
 	DraftListHandler   func(w http.ResponseWriter, r *http.Request)
 	DraftGetHandler    func(w http.ResponseWriter, r *http.Request, draftID string)
@@ -149,6 +151,7 @@ func (ts *TestServer) GCalService(t *testing.T) *GCalService {
 func (ts *TestServer) setupRoutes() {
 	// Gmail API routes - more specific routes first
 	ts.mux.HandleFunc("/gmail/v1/users/me/messages/send", ts.handleGmailMessageSend)
+	ts.mux.HandleFunc("/gmail/v1/users/me/messages/import", ts.handleGmailMessageImport)
 	ts.mux.HandleFunc("/gmail/v1/users/me/messages", ts.handleGmailMessages)
 	ts.mux.HandleFunc("/gmail/v1/users/me/messages/", ts.handleGmailMessage)
 	ts.mux.HandleFunc("/gmail/v1/users/me/drafts/send", ts.handleGmailDraftSend)
@@ -186,6 +189,21 @@ func (ts *TestServer) handleGmailMessageSend(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+func (ts *TestServer) handleGmailMessageImport(w http.ResponseWriter, r *http.Request) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if ts.MessageImportHandler == nil {
+		http.Error(w, "import handler not configured", http.StatusInternalServerError)
+		return
+	}
+	ts.MessageImportHandler(w, r)
+}
+
 func (ts *TestServer) handleGmailMessages(w http.ResponseWriter, r *http.Request) {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
@@ -237,6 +255,13 @@ func (ts *TestServer) handleGmailMessage(w http.ResponseWriter, r *http.Request)
 				ts.MessageModifyHandler(w, r, msgID)
 			}
 			return
+		case "attachments":
+			if ts.AttachmentGetHandler != nil && len(parts) > 2 {
+				ts.AttachmentGetHandler(w, r, msgID, parts[2])
+			} else {
+				http.Error(w, "attachment not found", http.StatusNotFound)
+			}
+			return
 		}
 	}
 
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+// Service names used as keys for SetEndpoints.
+const (
+	ServiceGmail    = "gmail"
+	ServiceCalendar = "calendar"
+	ServiceTasks    = "tasks"
+	ServicePeople   = "people"
+	ServiceDrive    = "drive"
+	// ServiceTranslate is the Cloud Translation API, used with an API key.
+	ServiceTranslate = "translate"
+)
+
+var (
+	transportMu sync.RWMutex
+	transport   http.RoundTripper
+	readOnly    bool
+	endpoints   map[string]string
+	onRetry     func(Retry)
+	retryBudget int
+	retriesUsed int
+)
+
+// Retry describes an API call about to be retried.
+type Retry struct {
+	// Attempt counts the retries of the call, from 1.
+	Attempt int
+	// Wait is how long the call waits before it is retried.
+	Wait time.Duration
+	// Err is the rate limit or temporary error the call failed with.
+	Err error
+}
+
+// SetTransport sets the HTTP transport used by repositories created
+// afterwards, for example to record or replay API traffic. A nil transport
+// restores the default.
+func SetTransport(rt http.RoundTripper) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transport = rt
+}
+
+// Transport returns the transport set with SetTransport, or nil for the
+// default, so callers can wrap it.
+func Transport() http.RoundTripper {
+	return currentTransport()
+}
+
+// currentTransport returns the transport set with SetTransport.
+func currentTransport() http.RoundTripper {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
+
+// SetRetryHook sets a function called each time an API call is retried
+// after a rate limit or temporary error, for example to count retries. A
+// nil function removes the hook.
+func SetRetryHook(fn func(Retry)) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	onRetry = fn
+}
+
+// notifyRetry calls the hook set with SetRetryHook.
+func notifyRetry(retry Retry) {
+	transportMu.RLock()
+	fn := onRetry
+	transportMu.RUnlock()
+	if fn != nil {
+		fn(retry)
+	}
+}
+
+// SetRetryBudget limits the retries of all API calls made from now on to
+// n in total, so a command making many calls to a failing service gives
+// up instead of retrying each of them. Zero or less means no limit. It
+// also resets the count returned by Retries.
+func SetRetryBudget(n int) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	retryBudget = n
+	retriesUsed = 0
+}
+
+// Retries returns how many retries were made since SetRetryBudget.
+func Retries() int {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return retriesUsed
+}
+
+// takeRetry uses one retry of the budget, reporting false when it is
+// spent.
+func takeRetry() bool {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if retryBudget > 0 && retriesUsed >= retryBudget {
+		return false
+	}
+	retriesUsed++
+	return true
+}
+
+// SetReadOnly turns read-only mode on or off for repositories created
+// afterwards. In read-only mode every request that could change data fails
+// with ErrReadOnly before it is sent.
+func SetReadOnly(on bool) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	readOnly = on
+}
+
+// ReadOnly reports whether read-only mode is on.
+func ReadOnly() bool {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return readOnly
+}
+
+// SetEndpoints sets the base URLs that repositories created afterwards
+// send requests to, keyed by service name (ServiceGmail, ...), for example
+// to point them at a mock server or an API gateway. Services without an
+// entry use Google's endpoint.
+func SetEndpoints(urls map[string]string) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	endpoints = make(map[string]string, len(urls))
+	for service, url := range urls {
+		if url != "" {
+			endpoints[service] = url
+		}
+	}
+}
+
+// Endpoint returns the base URL set for service, or "" for Google's.
+func Endpoint(service string) string {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return endpoints[service]
+}
+
+// clientOptions returns the options for creating a service client: an
+// HTTP client from newHTTPClient and any endpoint set for service.
+func clientOptions(ctx context.Context, tokenSource oauth2.TokenSource, service string) []option.ClientOption {
+	opts := []option.ClientOption{option.WithHTTPClient(newHTTPClient(ctx, tokenSource))}
+	if url := Endpoint(service); url != "" {
+		opts = append(opts, option.WithEndpoint(url))
+	}
+	return opts
+}
+
+// newHTTPClient returns an HTTP client that authenticates with tokenSource
+// and sends requests through the transport set with SetTransport, blocking
+// changes in read-only mode.
+func newHTTPClient(ctx context.Context, tokenSource oauth2.TokenSource) *http.Client {
+	rt := currentTransport()
+	if ReadOnly() {
+		rt = &readOnlyTransport{base: rt}
+	}
+	if rt != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: rt})
+	}
+	return oauth2.NewClient(ctx, tokenSource)
+}
+
+// readOnlyPOSTs are path suffixes of POST endpoints that only read data.
+var readOnlyPOSTs = []string{
+	"/calendar/v3/freeBusy",
+}
+
+// readOnlyTransport rejects requests that could change data.
+type readOnlyTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends reads and fails everything else with ErrReadOnly.
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isReadRequest(req) {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s %s would change data (run without --read-only, or set accounts.<alias>.read_only to false)", ErrReadOnly, req.Method, req.URL.Path)
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// isReadRequest reports whether req only reads data.
+func isReadRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		for _, suffix := range readOnlyPOSTs {
+			if strings.HasSuffix(req.URL.Path, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
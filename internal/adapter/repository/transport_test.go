@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSetTransport(t *testing.T) {
+	t.Cleanup(func() { SetTransport(nil) })
+
+	errSent := errors.New("sent")
+	var auth string
+	SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		auth = req.Header.Get("Authorization")
+		return nil, errSent
+	}))
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	client := newHTTPClient(context.Background(), ts)
+	if _, err := client.Get("https://gmail.googleapis.com/gmail/v1/users/me/profile"); !errors.Is(err, errSent) {
+		t.Fatalf("expected request to use the transport, got %v", err)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("Authorization = %q, want Bearer token", auth)
+	}
+
+	SetTransport(nil)
+	if currentTransport() != nil {
+		t.Error("SetTransport(nil) should restore the default")
+	}
+}
+
+func TestNewRepositoriesUseTransport(t *testing.T) {
+	t.Cleanup(func() { SetTransport(nil) })
+
+	errSent := errors.New("sent")
+	calls := 0
+	SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errSent
+	}))
+
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	repo, err := NewGTasksRepository(ctx, ts)
+	if err != nil {
+		t.Fatalf("NewGTasksRepository failed: %v", err)
+	}
+	if _, err := repo.service.Tasklists.List().Do(); err == nil {
+		t.Fatal("expected transport error")
+	}
+	if calls == 0 {
+		t.Error("repository did not use the transport")
+	}
+}
+
+func TestReadOnlyTransport(t *testing.T) {
+	var sent []string
+	rt := &readOnlyTransport{base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.Method+" "+req.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})}
+
+	tests := []struct {
+		method, url string
+		allowed     bool
+	}{
+		{http.MethodGet, "https://gmail.googleapis.com/gmail/v1/users/me/messages", true},
+		{http.MethodHead, "https://tasks.googleapis.com/tasks/v1/users/@me/lists", true},
+		{http.MethodPost, "https://www.googleapis.com/calendar/v3/freeBusy", true},
+		{http.MethodPost, "https://gmail.googleapis.com/gmail/v1/users/me/messages/send", false},
+		{http.MethodPut, "https://www.googleapis.com/calendar/v3/calendars/primary/events/1", false},
+		{http.MethodPatch, "https://tasks.googleapis.com/tasks/v1/lists/1/tasks/2", false},
+		{http.MethodDelete, "https://people.googleapis.com/v1/people/c1:deleteContact", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			sent = nil
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = rt.RoundTrip(req)
+			if tt.allowed {
+				if err != nil || len(sent) != 1 {
+					t.Errorf("expected request to be sent, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrReadOnly) {
+				t.Errorf("expected ErrReadOnly, got %v", err)
+			}
+			if len(sent) != 0 {
+				t.Errorf("blocked request was sent: %v", sent)
+			}
+		})
+	}
+}
+
+func TestSetReadOnly(t *testing.T) {
+	t.Cleanup(func() {
+		SetReadOnly(false)
+		SetTransport(nil)
+	})
+
+	calls := 0
+	SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("sent")
+	}))
+	SetReadOnly(true)
+	if !ReadOnly() {
+		t.Fatal("ReadOnly() = false after SetReadOnly(true)")
+	}
+
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	repo, err := NewGTasksRepository(ctx, ts)
+	if err != nil {
+		t.Fatalf("NewGTasksRepository failed: %v", err)
+	}
+	if err := repo.service.Tasklists.Delete("list").Do(); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+	if calls != 0 {
+		t.Error("blocked request reached the transport")
+	}
+	if _, err := repo.service.Tasklists.List().Do(); err == nil || errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected reads to reach the transport, got %v", err)
+	}
+	if calls == 0 {
+		t.Error("read did not reach the transport")
+	}
+}
+
+func TestSetEndpoints(t *testing.T) {
+	t.Cleanup(func() { SetEndpoints(nil) })
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	SetEndpoints(map[string]string{ServiceTasks: server.URL + "/tasks-gw/", ServiceGmail: ""})
+	if Endpoint(ServiceTasks) != server.URL+"/tasks-gw/" {
+		t.Errorf("Endpoint(tasks) = %q", Endpoint(ServiceTasks))
+	}
+	if Endpoint(ServiceGmail) != "" {
+		t.Errorf("empty endpoints should be dropped, got %q", Endpoint(ServiceGmail))
+	}
+
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+	repo, err := NewGTasksRepository(ctx, ts)
+	if err != nil {
+		t.Fatalf("NewGTasksRepository failed: %v", err)
+	}
+	if _, err := repo.service.Tasklists.List().Do(); err != nil {
+		t.Fatalf("request to override failed: %v", err)
+	}
+	if len(paths) != 1 || !strings.HasPrefix(paths[0], "/tasks-gw/") {
+		t.Errorf("expected the request at the override, got %v", paths)
+	}
+
+	SetEndpoints(nil)
+	if Endpoint(ServiceTasks) != "" {
+		t.Error("SetEndpoints(nil) should restore Google's endpoints")
+	}
+}
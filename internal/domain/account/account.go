@@ -14,6 +14,20 @@ type Account struct {
 	Added     time.Time
 	LastUsed  time.Time
 	IsDefault bool
+
+	// ReadOnly is set when changes to the account's data are blocked.
+	ReadOnly bool
+	// Commands lists the commands the account was authorized for, when
+	// its scopes were chosen per command.
+	Commands []string
+
+	// Endpoints maps service names (gmail, calendar, tasks, people) to the
+	// base URLs used instead of Google's.
+	Endpoints map[string]string
+
+	// Metadata holds user-defined key/value pairs describing the account,
+	// such as role: work.
+	Metadata map[string]string
 }
 
 // NewAccount creates a new Account with the given alias and email.
@@ -1,6 +1,8 @@
 // Package calendar provides domain entities for Google Calendar operations.
 package calendar
 
+import "strings"
+
 // Attendee represents a participant in a calendar event.
 type Attendee struct {
 	// Email is the attendee's email address.
@@ -25,6 +27,10 @@ const (
 	ResponseAccepted    = "accepted"
 )
 
+// resourceDomain is the domain of the addresses Google Calendar gives
+// rooms and other bookable resources.
+const resourceDomain = "@resource.calendar.google.com"
+
 // NewAttendee creates a new Attendee with the given email.
 func NewAttendee(email string) *Attendee {
 	return &Attendee{
@@ -42,3 +48,9 @@ func IsValidResponseStatus(status string) bool {
 		return false
 	}
 }
+
+// IsResource reports whether the attendee is a room or other resource
+// rather than a person.
+func (a *Attendee) IsResource() bool {
+	return strings.HasSuffix(strings.ToLower(a.Email), resourceDomain)
+}
@@ -89,3 +89,20 @@ func TestAttendeeFields(t *testing.T) {
 		t.Error("expected Self to be true")
 	}
 }
+
+func TestAttendeeIsResource(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"c_1888abc@resource.calendar.google.com", true},
+		{"C_1888ABC@Resource.Calendar.Google.com", true},
+		{"alice@example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := (&Attendee{Email: tt.email}).IsResource(); got != tt.want {
+			t.Errorf("IsResource(%q) = %v, want %v", tt.email, got, tt.want)
+		}
+	}
+}
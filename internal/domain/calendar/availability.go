@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultSlotStep is the granularity free slots start on, so offered times
+// read like 9:15 rather than 9:07.
+const DefaultSlotStep = 15 * time.Minute
+
+// WorkingHours is the part of each day in which free slots are offered.
+type WorkingHours struct {
+	// Start and End are offsets from midnight, e.g. 9h and 17h.
+	Start time.Duration
+	End   time.Duration
+	// Weekends includes Saturdays and Sundays.
+	Weekends bool
+}
+
+// Windows returns the working-hour periods between from and until, in the
+// time zone of from.
+func (w WorkingHours) Windows(from, until time.Time) []*TimePeriod {
+	var windows []*TimePeriod
+	loc := from.Location()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for ; day.Before(until); day = day.AddDate(0, 0, 1) {
+		if !w.Weekends && (day.Weekday() == time.Saturday || day.Weekday() == time.Sunday) {
+			continue
+		}
+		start := atOffset(day, w.Start)
+		end := atOffset(day, w.End)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(until) {
+			end = until
+		}
+		if start.Before(end) {
+			windows = append(windows, &TimePeriod{Start: start, End: end})
+		}
+	}
+	return windows
+}
+
+// atOffset returns the wall-clock time offset after midnight on day, so
+// working hours keep their meaning on days when daylight saving changes.
+func atOffset(day time.Time, offset time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// FreeSlots returns the parts of windows not covered by busy periods that
+// last at least minDuration. Slot starts are rounded up to a multiple of
+// step; a zero step leaves them unrounded.
+func FreeSlots(windows, busy []*TimePeriod, minDuration, step time.Duration) []*TimePeriod {
+	sorted := make([]*TimePeriod, 0, len(busy))
+	for _, p := range busy {
+		if p != nil && p.Start.Before(p.End) {
+			sorted = append(sorted, p)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var slots []*TimePeriod
+	for _, window := range windows {
+		cursor := window.Start
+		for _, p := range sorted {
+			if !p.End.After(cursor) {
+				continue
+			}
+			if !p.Start.Before(window.End) {
+				break
+			}
+			slots = appendSlot(slots, cursor, p.Start, minDuration, step)
+			cursor = p.End
+		}
+		slots = appendSlot(slots, cursor, window.End, minDuration, step)
+	}
+	return slots
+}
+
+// appendSlot appends the period from start to end, with start rounded up
+// to step, when it is at least minDuration long.
+func appendSlot(slots []*TimePeriod, start, end time.Time, minDuration, step time.Duration) []*TimePeriod {
+	if step > 0 {
+		if rounded := start.Truncate(step); rounded.Before(start) {
+			start = rounded.Add(step)
+		}
+	}
+	if end.Sub(start) < minDuration || !start.Before(end) {
+		return slots
+	}
+	return append(slots, &TimePeriod{Start: start, End: end})
+}
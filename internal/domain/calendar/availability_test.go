@@ -0,0 +1,82 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkingHoursWindows(t *testing.T) {
+	// Friday, March 8 2024 at 10:20 through Tuesday, March 12 at noon
+	from := time.Date(2024, 3, 8, 10, 20, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC)
+	hours := WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour}
+
+	windows := hours.Windows(from, until)
+	want := []TimePeriod{
+		{from, time.Date(2024, 3, 8, 17, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 17, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC), until},
+	}
+	if len(windows) != len(want) {
+		t.Fatalf("got %d windows, want %d: %v", len(windows), len(want), windows)
+	}
+	for i, w := range windows {
+		if !w.Start.Equal(want[i].Start) || !w.End.Equal(want[i].End) {
+			t.Errorf("window %d = %v - %v, want %v - %v", i, w.Start, w.End, want[i].Start, want[i].End)
+		}
+	}
+
+	hours.Weekends = true
+	if got := len(hours.Windows(from, until)); got != 5 {
+		t.Errorf("with weekends got %d windows, want 5", got)
+	}
+}
+
+func TestWorkingHoursWindowsDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Daylight saving starts on Sunday, March 10 2024
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, loc)
+	windows := WorkingHours{Start: 9 * time.Hour, End: 17 * time.Hour, Weekends: true}.Windows(from, from.AddDate(0, 0, 1))
+	if len(windows) != 1 || windows[0].Start.Hour() != 9 || windows[0].End.Hour() != 17 {
+		t.Errorf("windows = %v, want 9:00 - 17:00 local time", windows)
+	}
+}
+
+func TestFreeSlots(t *testing.T) {
+	day := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	windows := []*TimePeriod{{at(9, 0), at(17, 0)}}
+	busy := []*TimePeriod{
+		{at(13, 0), at(14, 0)},
+		{at(9, 30), at(10, 10)},
+		{at(10, 0), at(10, 50)}, // overlaps the previous meeting
+		{at(16, 40), at(18, 0)},
+		{at(8, 0), at(8, 30)}, // before the window
+	}
+
+	slots := FreeSlots(windows, busy, 30*time.Minute, DefaultSlotStep)
+	want := []TimePeriod{
+		{at(9, 0), at(9, 30)},
+		{at(11, 0), at(13, 0)},
+		{at(14, 0), at(16, 40)},
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("got %d slots, want %d: %v", len(slots), len(want), slots)
+	}
+	for i, s := range slots {
+		if !s.Start.Equal(want[i].Start) || !s.End.Equal(want[i].End) {
+			t.Errorf("slot %d = %s - %s, want %s - %s", i,
+				s.Start.Format("15:04"), s.End.Format("15:04"), want[i].Start.Format("15:04"), want[i].End.Format("15:04"))
+		}
+	}
+
+	if got := FreeSlots(windows, busy, time.Hour, 0); len(got) != 2 {
+		t.Errorf("with a one-hour minimum got %d slots, want 2", len(got))
+	}
+	if got := FreeSlots(windows, nil, 30*time.Minute, DefaultSlotStep); len(got) != 1 || got[0].Duration() != 8*time.Hour {
+		t.Errorf("with no busy periods got %v, want the whole window", got)
+	}
+}
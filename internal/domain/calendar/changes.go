@@ -0,0 +1,153 @@
+package calendar
+
+import "sort"
+
+// Event change kinds.
+const (
+	ChangeCreated   = "created"
+	ChangeUpdated   = "updated"
+	ChangeCancelled = "cancelled"
+)
+
+// Changed event fields compared by DiffEvents.
+const (
+	FieldTitle    = "title"
+	FieldTime     = "time"
+	FieldLocation = "location"
+	FieldStatus   = "status"
+)
+
+// EventChange is how one event changed between two syncs of a calendar.
+type EventChange struct {
+	// Kind is ChangeCreated, ChangeUpdated or ChangeCancelled.
+	Kind string
+	// Event is the event as it is now; for a cancelled event, as it was
+	// last seen, when known.
+	Event *Event
+	// Before is the event as it was last seen, or nil when it is new.
+	Before *Event
+	// Fields lists the fields of an updated event that changed.
+	Fields []string
+}
+
+// Snapshot returns a copy of e with only the fields DiffEvents compares
+// and shows, for keeping between syncs.
+func (e *Event) Snapshot() *Event {
+	return &Event{
+		ID:               e.ID,
+		Title:            e.Title,
+		Location:         e.Location,
+		Start:            e.Start,
+		End:              e.End,
+		AllDay:           e.AllDay,
+		Status:           e.Status,
+		RecurringEventID: e.RecurringEventID,
+		OriginalStart:    e.OriginalStart,
+		Recurrence:       e.Recurrence,
+	}
+}
+
+// DiffEvents compares the events of a sync with the snapshots of the
+// events last seen, keyed by ID. Events changed only in fields it does
+// not compare, such as guests' answers, are left out. An instance of a
+// recurring event seen for the first time is compared with its series as
+// scheduled at its original start, so a moved occurrence is reported as
+// updated rather than created.
+//
+// When full is true, changed lists every event of the calendar, and
+// events last seen but no longer listed are reported as cancelled.
+func DiffEvents(before map[string]*Event, changed []*Event, full bool) []*EventChange {
+	var changes []*EventChange
+	listed := make(map[string]bool, len(changed))
+	for _, e := range changed {
+		listed[e.ID] = true
+		prev := before[e.ID]
+		if prev == nil {
+			prev = scheduledInstance(before, e)
+		}
+
+		if e.Status == StatusCancelled {
+			if prev != nil {
+				changes = append(changes, &EventChange{Kind: ChangeCancelled, Event: prev, Before: prev})
+			}
+			continue
+		}
+		if prev == nil {
+			changes = append(changes, &EventChange{Kind: ChangeCreated, Event: e})
+			continue
+		}
+		if fields := changedFields(prev, e); len(fields) > 0 {
+			changes = append(changes, &EventChange{Kind: ChangeUpdated, Event: e, Before: prev, Fields: fields})
+		}
+	}
+
+	if full {
+		var gone []string
+		for id := range before {
+			if !listed[id] {
+				gone = append(gone, id)
+			}
+		}
+		sort.Strings(gone)
+		for _, id := range gone {
+			changes = append(changes, &EventChange{Kind: ChangeCancelled, Event: before[id], Before: before[id]})
+		}
+	}
+	return changes
+}
+
+// ApplyEvents updates the snapshots of a calendar's events, keyed by ID,
+// with the events of a sync: changed events are stored as snapshots and
+// cancelled ones removed. When full is true, changed lists every event
+// and replaces the snapshots.
+func ApplyEvents(snapshots map[string]*Event, changed []*Event, full bool) map[string]*Event {
+	if full || snapshots == nil {
+		snapshots = make(map[string]*Event, len(changed))
+	}
+	for _, e := range changed {
+		if e.Status == StatusCancelled {
+			delete(snapshots, e.ID)
+			continue
+		}
+		snapshots[e.ID] = e.Snapshot()
+	}
+	return snapshots
+}
+
+// scheduledInstance returns an instance of a recurring event as its
+// series schedules it, from the series' snapshot: at the instance's
+// original start, with the series' duration. It returns nil when e is
+// not an instance or the series has not been seen.
+func scheduledInstance(before map[string]*Event, e *Event) *Event {
+	series := before[e.RecurringEventID]
+	if e.RecurringEventID == "" || series == nil || e.OriginalStart.IsZero() {
+		return nil
+	}
+	inst := series.Snapshot()
+	inst.ID = e.ID
+	inst.RecurringEventID = series.ID
+	inst.Recurrence = nil
+	inst.Start = e.OriginalStart
+	inst.End = e.OriginalStart.Add(series.End.Sub(series.Start))
+	inst.OriginalStart = e.OriginalStart
+	return inst
+}
+
+// changedFields lists the compared fields that differ between two
+// versions of an event.
+func changedFields(prev, cur *Event) []string {
+	var fields []string
+	if prev.Title != cur.Title {
+		fields = append(fields, FieldTitle)
+	}
+	if !prev.Start.Equal(cur.Start) || !prev.End.Equal(cur.End) || prev.AllDay != cur.AllDay {
+		fields = append(fields, FieldTime)
+	}
+	if prev.Location != cur.Location {
+		fields = append(fields, FieldLocation)
+	}
+	if prev.Status != cur.Status {
+		fields = append(fields, FieldStatus)
+	}
+	return fields
+}
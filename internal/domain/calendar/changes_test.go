@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffEvents(t *testing.T) {
+	nine := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	before := map[string]*Event{
+		"standup": {ID: "standup", Title: "Standup", Start: nine, End: nine.Add(15 * time.Minute), Status: StatusConfirmed},
+		"review":  {ID: "review", Title: "Review", Start: nine.Add(2 * time.Hour), End: nine.Add(3 * time.Hour)},
+		"lunch":   {ID: "lunch", Title: "Lunch", Location: "Cafe", Start: nine.Add(3 * time.Hour), End: nine.Add(4 * time.Hour)},
+		"weekly":  {ID: "weekly", Title: "Weekly", Start: nine, End: nine.Add(time.Hour), Recurrence: []string{"RRULE:FREQ=WEEKLY"}},
+	}
+	changed := []*Event{
+		// Only guests' answers changed
+		{ID: "standup", Title: "Standup", Start: nine, End: nine.Add(15 * time.Minute), Status: StatusConfirmed},
+		{ID: "review", Title: "Design review", Start: nine.Add(5 * time.Hour), End: nine.Add(6 * time.Hour)},
+		{ID: "lunch", Status: StatusCancelled},
+		{ID: "new", Title: "Offsite", Start: nine.Add(48 * time.Hour), End: nine.Add(50 * time.Hour)},
+		// One occurrence of the weekly meeting moved by an hour
+		{ID: "weekly_1026", Title: "Weekly", RecurringEventID: "weekly", OriginalStart: nine.Add(7 * 24 * time.Hour),
+			Start: nine.Add(7*24*time.Hour + time.Hour), End: nine.Add(7*24*time.Hour + 2*time.Hour)},
+		// Created and deleted between syncs
+		{ID: "gone", Status: StatusCancelled},
+	}
+
+	changes := DiffEvents(before, changed, false)
+	if len(changes) != 4 {
+		t.Fatalf("got %d changes, want 4: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.Kind != ChangeUpdated || c.Event.ID != "review" || !reflect.DeepEqual(c.Fields, []string{FieldTitle, FieldTime}) {
+		t.Errorf("changes[0] = %+v", c)
+	}
+	if c := changes[1]; c.Kind != ChangeCancelled || c.Event.Title != "Lunch" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+	if c := changes[2]; c.Kind != ChangeCreated || c.Event.Title != "Offsite" || c.Before != nil {
+		t.Errorf("changes[2] = %+v", c)
+	}
+	c := changes[3]
+	if c.Kind != ChangeUpdated || !reflect.DeepEqual(c.Fields, []string{FieldTime}) || !c.Before.Start.Equal(nine.Add(7*24*time.Hour)) || !c.Before.End.Equal(nine.Add(7*24*time.Hour+time.Hour)) {
+		t.Errorf("moved occurrence = %+v, before %+v", c, c.Before)
+	}
+}
+
+func TestDiffEvents_Full(t *testing.T) {
+	before := map[string]*Event{
+		"a": {ID: "a", Title: "Kept"},
+		"b": {ID: "b", Title: "Deleted"},
+	}
+	changes := DiffEvents(before, []*Event{{ID: "a", Title: "Kept"}}, true)
+	if len(changes) != 1 || changes[0].Kind != ChangeCancelled || changes[0].Event.Title != "Deleted" {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestApplyEvents(t *testing.T) {
+	snapshots := map[string]*Event{"a": {ID: "a"}, "b": {ID: "b"}}
+	got := ApplyEvents(snapshots, []*Event{
+		{ID: "a", Status: StatusCancelled},
+		{ID: "c", Title: "New", Description: "not kept"},
+	}, false)
+	if len(got) != 2 || got["b"] == nil || got["c"].Title != "New" || got["c"].Description != "" {
+		t.Errorf("ApplyEvents() = %+v", got)
+	}
+
+	got = ApplyEvents(got, []*Event{{ID: "d"}}, true)
+	if len(got) != 1 || got["d"] == nil {
+		t.Errorf("full ApplyEvents() = %+v", got)
+	}
+}
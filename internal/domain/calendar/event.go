@@ -1,6 +1,9 @@
 package calendar
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Event represents a Google Calendar event.
 type Event struct {
@@ -8,6 +11,9 @@ type Event struct {
 	ID string
 	// CalendarID is the ID of the calendar containing this event.
 	CalendarID string
+	// ICalUID is the iCalendar UID, shared by copies of the event in
+	// other calendars and by the file it was imported from.
+	ICalUID string
 	// Title is the event summary/title.
 	Title string
 	// Description is the event description.
@@ -22,6 +28,11 @@ type Event struct {
 	AllDay bool
 	// Recurrence contains RRULE strings for recurring events.
 	Recurrence []string
+	// RecurringEventID is the ID of the parent recurring event for expanded instances.
+	RecurringEventID string
+	// OriginalStart is when an instance of a recurring event was scheduled
+	// to start by the recurrence, before it was moved or cancelled.
+	OriginalStart time.Time
 	// Attendees is the list of event attendees.
 	Attendees []*Attendee
 	// Organizer is the event organizer.
@@ -36,6 +47,8 @@ type Event struct {
 	Reminders []*Reminder
 	// ConferenceData contains video conference information.
 	ConferenceData *ConferenceData
+	// Attachments lists the Drive files attached to the event.
+	Attachments []*Attachment
 	// Created is when the event was created.
 	Created time.Time
 	// Updated is when the event was last updated.
@@ -71,6 +84,18 @@ const (
 	ReminderMethodPopup = "popup"
 )
 
+// Attachment is a Google Drive file attached to an event.
+type Attachment struct {
+	// FileURL links to the file. It is the only field needed to attach one.
+	FileURL string
+	// FileID is the Drive file ID, set by Google for Drive files.
+	FileID string
+	// Title is the file name.
+	Title string
+	// MimeType is the file's media type.
+	MimeType string
+}
+
 // ConferenceData represents video conference information.
 type ConferenceData struct {
 	// Type is the conference type (e.g., "hangoutsMeet").
@@ -153,9 +178,10 @@ func (e *Event) Duration() time.Duration {
 	return e.End.Sub(e.Start)
 }
 
-// IsRecurring returns true if the event has recurrence rules.
+// IsRecurring returns true if the event has recurrence rules or is an
+// instance of a recurring event.
 func (e *Event) IsRecurring() bool {
-	return len(e.Recurrence) > 0
+	return len(e.Recurrence) > 0 || e.RecurringEventID != ""
 }
 
 // HasConference returns true if the event has conference data.
@@ -163,6 +189,20 @@ func (e *Event) HasConference() bool {
 	return e.ConferenceData != nil && e.ConferenceData.URI != ""
 }
 
+// AttachmentNames returns the titles of the event's attachments, or their
+// links for attachments without a title.
+func (e *Event) AttachmentNames() []string {
+	names := make([]string, 0, len(e.Attachments))
+	for _, a := range e.Attachments {
+		if a.Title != "" {
+			names = append(names, a.Title)
+		} else {
+			names = append(names, a.FileURL)
+		}
+	}
+	return names
+}
+
 // AddAttendee adds an attendee to the event.
 func (e *Event) AddAttendee(attendee *Attendee) {
 	if e.Attendees == nil {
@@ -178,3 +218,35 @@ func (e *Event) AddReminder(reminder *Reminder) {
 	}
 	e.Reminders = append(e.Reminders, reminder)
 }
+
+// IsDuplicateOf reports whether e is the same event as other, as imports
+// decide it: the same iCalendar UID, or the same title, ignoring case, and
+// the same start. All-day events match on their start date alone, as their
+// times depend on the time zone they were parsed in.
+func (e *Event) IsDuplicateOf(other *Event) bool {
+	if e.ICalUID != "" && e.ICalUID == other.ICalUID {
+		return true
+	}
+	if !strings.EqualFold(strings.TrimSpace(e.Title), strings.TrimSpace(other.Title)) || e.AllDay != other.AllDay {
+		return false
+	}
+	if e.AllDay {
+		return e.Start.Format("2006-01-02") == other.Start.Format("2006-01-02")
+	}
+	return e.Start.Equal(other.Start)
+}
+
+// FindDuplicate returns the event in existing that ev duplicates, or nil.
+// Cancelled events are not duplicates, so a deleted event can be imported
+// again.
+func FindDuplicate(existing []*Event, ev *Event) *Event {
+	for _, other := range existing {
+		if other == nil || other.Status == StatusCancelled {
+			continue
+		}
+		if ev.IsDuplicateOf(other) {
+			return other
+		}
+	}
+	return nil
+}
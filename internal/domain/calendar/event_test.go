@@ -186,6 +186,22 @@ func TestEventIsRecurring(t *testing.T) {
 	}
 }
 
+func TestEventAttachmentNames(t *testing.T) {
+	event := NewEvent("Test", time.Now(), time.Now().Add(time.Hour))
+	if names := event.AttachmentNames(); len(names) != 0 {
+		t.Errorf("expected no names, got %v", names)
+	}
+
+	event.Attachments = []*Attachment{
+		{Title: "Agenda", FileURL: "https://drive.google.com/open?id=a"},
+		{FileURL: "https://drive.google.com/open?id=b"},
+	}
+	names := event.AttachmentNames()
+	if len(names) != 2 || names[0] != "Agenda" || names[1] != "https://drive.google.com/open?id=b" {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
+
 func TestEventHasConference(t *testing.T) {
 	event := NewEvent("Test", time.Now(), time.Now().Add(time.Hour))
 
@@ -319,3 +335,49 @@ func TestEventFields(t *testing.T) {
 		t.Errorf("unexpected HTMLLink: %s", event.HTMLLink)
 	}
 }
+
+func TestEventIsDuplicateOf(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	base := &Event{ICalUID: "abc@example.com", Title: "Standup", Start: start}
+
+	tests := []struct {
+		name  string
+		other *Event
+		want  bool
+	}{
+		{"same uid", &Event{ICalUID: "abc@example.com", Title: "Renamed", Start: start.Add(time.Hour)}, true},
+		{"same title and start", &Event{ICalUID: "other@google.com", Title: " standup ", Start: start.In(time.FixedZone("CET", 3600))}, true},
+		{"different start", &Event{Title: "Standup", Start: start.Add(time.Hour)}, false},
+		{"different title", &Event{Title: "Retro", Start: start}, false},
+		{"all-day against timed", &Event{Title: "Standup", Start: start, AllDay: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.IsDuplicateOf(tt.other); got != tt.want {
+				t.Errorf("IsDuplicateOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("all-day matches on date", func(t *testing.T) {
+		a := NewAllDayEvent("Holiday", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+		b := NewAllDayEvent("Holiday", time.Date(2024, 5, 1, 0, 0, 0, 0, time.FixedZone("PST", -8*3600)))
+		if !a.IsDuplicateOf(b) {
+			t.Error("expected all-day events on the same date to match")
+		}
+	})
+}
+
+func TestFindDuplicate(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	cancelled := &Event{ID: "1", Title: "Standup", Start: start, Status: StatusCancelled}
+	live := &Event{ID: "2", Title: "Standup", Start: start}
+	ev := &Event{Title: "Standup", Start: start}
+
+	if got := FindDuplicate([]*Event{cancelled, nil, live}, ev); got != live {
+		t.Errorf("FindDuplicate() = %v, want the live event", got)
+	}
+	if got := FindDuplicate([]*Event{cancelled}, ev); got != nil {
+		t.Errorf("FindDuplicate() = %v, want nil for a cancelled event", got)
+	}
+}
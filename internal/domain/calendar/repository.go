@@ -16,6 +16,9 @@ var (
 	ErrACLNotFound = errors.New("ACL rule not found")
 	// ErrInvalidTimeRange is returned when an invalid time range is provided.
 	ErrInvalidTimeRange = errors.New("invalid time range: start must be before end")
+	// ErrSyncTokenExpired is returned when a sync token is no longer
+	// accepted and the events must be listed in full again.
+	ErrSyncTokenExpired = errors.New("sync token expired")
 )
 
 // EventRepository defines the interface for event persistence operations.
@@ -40,6 +43,19 @@ type EventRepository interface {
 	RSVP(ctx context.Context, calendarID, eventID, response string) error
 }
 
+// EventChanges lists the events of a calendar changed since a sync token.
+type EventChanges struct {
+	// Events are the events created, updated or deleted since the token;
+	// deleted events and cancelled instances have StatusCancelled. Without
+	// a token, every event of the calendar is listed, deleted ones left out.
+	Events []*Event
+	// SyncToken is the token to pass to the next sync.
+	SyncToken string
+	// Full reports whether Events lists every event, because no sync
+	// token was given.
+	Full bool
+}
+
 // CalendarRepository defines the interface for calendar persistence operations.
 type CalendarRepository interface {
 	// List returns all calendars accessible to the user.
@@ -0,0 +1,248 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultBackToBackGap is the maximum gap between two meetings for them to be
+// considered back-to-back.
+const DefaultBackToBackGap = 5 * time.Minute
+
+// Stats summarizes how time was spent in meetings over a period.
+type Stats struct {
+	// Since is the start of the analyzed period.
+	Since time.Time
+	// Until is the end of the analyzed period.
+	Until time.Time
+	// MeetingCount is the number of meetings counted.
+	MeetingCount int
+	// TotalHours is the total number of hours spent in meetings.
+	TotalHours float64
+	// AverageWeeklyHours is the mean number of meeting hours per week.
+	AverageWeeklyHours float64
+	// Weeks contains per-week meeting hours, oldest first.
+	Weeks []*WeekStats
+	// TopOrganizers lists the organizers with the most meetings, busiest first.
+	TopOrganizers []*OrganizerStats
+	// RecurringCount is the number of meetings that are recurring instances.
+	RecurringCount int
+	// AdHocCount is the number of one-off meetings.
+	AdHocCount int
+	// BackToBackStreaks is the number of runs of two or more back-to-back meetings.
+	BackToBackStreaks int
+	// LongestStreak is the longest run of back-to-back meetings, if any.
+	LongestStreak *Streak
+}
+
+// WeekStats holds meeting totals for a single week.
+type WeekStats struct {
+	// WeekStart is midnight on the Monday that starts the week.
+	WeekStart time.Time
+	// Meetings is the number of meetings that started in the week.
+	Meetings int
+	// Hours is the number of meeting hours in the week.
+	Hours float64
+}
+
+// OrganizerStats holds the number of meetings organized by one person.
+type OrganizerStats struct {
+	// Email is the organizer's email address.
+	Email string
+	// Name is the organizer's display name, if known.
+	Name string
+	// Meetings is the number of meetings organized.
+	Meetings int
+}
+
+// Streak is a run of back-to-back meetings.
+type Streak struct {
+	// Start is the start of the first meeting in the run.
+	Start time.Time
+	// End is the end of the last meeting in the run.
+	End time.Time
+	// Meetings is the number of meetings in the run.
+	Meetings int
+}
+
+// Duration returns the time between the start and end of the streak.
+func (s *Streak) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// RecurringRatio returns the fraction of meetings that are recurring, from 0 to 1.
+func (s *Stats) RecurringRatio() float64 {
+	if s.MeetingCount == 0 {
+		return 0
+	}
+	return float64(s.RecurringCount) / float64(s.MeetingCount)
+}
+
+// StatsOptions configures how meeting statistics are computed.
+type StatsOptions struct {
+	// TopOrganizers limits the number of organizers reported. Zero means no limit.
+	TopOrganizers int
+	// BackToBackGap is the maximum gap between back-to-back meetings.
+	// Defaults to DefaultBackToBackGap when zero.
+	BackToBackGap time.Duration
+}
+
+// IsMeeting reports whether the event counts as time spent in a meeting.
+// All-day, cancelled, and declined events are excluded.
+func (e *Event) IsMeeting() bool {
+	if e.AllDay || e.Status == StatusCancelled {
+		return false
+	}
+	for _, a := range e.Attendees {
+		if a != nil && a.Self && a.ResponseStatus == ResponseDeclined {
+			return false
+		}
+	}
+	return e.End.After(e.Start)
+}
+
+// ComputeStats aggregates meeting statistics for events within [since, until).
+func ComputeStats(events []*Event, since, until time.Time, opts StatsOptions) *Stats {
+	if opts.BackToBackGap == 0 {
+		opts.BackToBackGap = DefaultBackToBackGap
+	}
+
+	stats := &Stats{
+		Since: since,
+		Until: until,
+	}
+
+	meetings := make([]*Event, 0, len(events))
+	for _, e := range events {
+		if e == nil || !e.IsMeeting() {
+			continue
+		}
+		if e.Start.Before(since) || !e.Start.Before(until) {
+			continue
+		}
+		meetings = append(meetings, e)
+	}
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return meetings[i].Start.Before(meetings[j].Start)
+	})
+
+	stats.Weeks = buildWeeks(since, until)
+	organizers := make(map[string]*OrganizerStats)
+
+	for _, m := range meetings {
+		hours := m.Duration().Hours()
+		stats.MeetingCount++
+		stats.TotalHours += hours
+
+		if week := findWeek(stats.Weeks, m.Start); week != nil {
+			week.Meetings++
+			week.Hours += hours
+		}
+
+		if m.IsRecurring() {
+			stats.RecurringCount++
+		} else {
+			stats.AdHocCount++
+		}
+
+		if m.Organizer != nil && m.Organizer.Email != "" {
+			org, ok := organizers[m.Organizer.Email]
+			if !ok {
+				org = &OrganizerStats{Email: m.Organizer.Email, Name: m.Organizer.DisplayName}
+				organizers[m.Organizer.Email] = org
+			}
+			org.Meetings++
+		}
+	}
+
+	if len(stats.Weeks) > 0 {
+		stats.AverageWeeklyHours = stats.TotalHours / float64(len(stats.Weeks))
+	}
+
+	stats.TopOrganizers = rankOrganizers(organizers, opts.TopOrganizers)
+	stats.BackToBackStreaks, stats.LongestStreak = findStreaks(meetings, opts.BackToBackGap)
+
+	return stats
+}
+
+// startOfWeek returns midnight on the Monday of the week containing t.
+func startOfWeek(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Sunday is day 7
+	}
+	return time.Date(t.Year(), t.Month(), t.Day()-(weekday-1), 0, 0, 0, 0, t.Location())
+}
+
+// buildWeeks returns empty week buckets covering [since, until).
+func buildWeeks(since, until time.Time) []*WeekStats {
+	if !since.Before(until) {
+		return nil
+	}
+	var weeks []*WeekStats
+	for ws := startOfWeek(since); ws.Before(until); ws = ws.AddDate(0, 0, 7) {
+		weeks = append(weeks, &WeekStats{WeekStart: ws})
+	}
+	return weeks
+}
+
+// findWeek returns the bucket containing t, or nil if none does.
+func findWeek(weeks []*WeekStats, t time.Time) *WeekStats {
+	for _, w := range weeks {
+		if !t.Before(w.WeekStart) && t.Before(w.WeekStart.AddDate(0, 0, 7)) {
+			return w
+		}
+	}
+	return nil
+}
+
+// rankOrganizers sorts organizers by meeting count and applies the limit.
+func rankOrganizers(organizers map[string]*OrganizerStats, limit int) []*OrganizerStats {
+	ranked := make([]*OrganizerStats, 0, len(organizers))
+	for _, org := range organizers {
+		ranked = append(ranked, org)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Meetings != ranked[j].Meetings {
+			return ranked[i].Meetings > ranked[j].Meetings
+		}
+		return ranked[i].Email < ranked[j].Email
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// findStreaks counts runs of two or more back-to-back meetings in sorted
+// meetings and returns the longest one.
+func findStreaks(meetings []*Event, gap time.Duration) (int, *Streak) {
+	var count int
+	var longest *Streak
+	var current *Streak
+
+	closeStreak := func() {
+		if current == nil || current.Meetings < 2 {
+			return
+		}
+		count++
+		if longest == nil || current.Meetings > longest.Meetings {
+			longest = current
+		}
+	}
+
+	for _, m := range meetings {
+		if current != nil && m.Start.Sub(current.End) <= gap {
+			current.Meetings++
+			if m.End.After(current.End) {
+				current.End = m.End
+			}
+			continue
+		}
+		closeStreak()
+		current = &Streak{Start: m.Start, End: m.End, Meetings: 1}
+	}
+	closeStreak()
+
+	return count, longest
+}
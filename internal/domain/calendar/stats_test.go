@@ -0,0 +1,173 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+// statsMeeting builds a timed event for stats tests.
+func statsMeeting(start time.Time, minutes int, organizer string) *Event {
+	e := NewEvent("Meeting", start, start.Add(time.Duration(minutes)*time.Minute))
+	if organizer != "" {
+		e.Organizer = &Attendee{Email: organizer}
+	}
+	return e
+}
+
+func TestEventIsMeeting(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		event *Event
+		want  bool
+	}{
+		{"timed event", statsMeeting(start, 30, ""), true},
+		{"all-day event", NewAllDayEvent("Holiday", start), false},
+		{"cancelled event", func() *Event {
+			e := statsMeeting(start, 30, "")
+			e.Status = StatusCancelled
+			return e
+		}(), false},
+		{"declined by self", func() *Event {
+			e := statsMeeting(start, 30, "")
+			e.AddAttendee(&Attendee{Email: "me@example.com", Self: true, ResponseStatus: ResponseDeclined})
+			return e
+		}(), false},
+		{"accepted by self", func() *Event {
+			e := statsMeeting(start, 30, "")
+			e.AddAttendee(&Attendee{Email: "me@example.com", Self: true, ResponseStatus: ResponseAccepted})
+			return e
+		}(), true},
+		{"zero duration", statsMeeting(start, 0, ""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.IsMeeting(); got != tt.want {
+				t.Errorf("IsMeeting() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	// Monday, March 4 2024 through Sunday, March 17 2024 (two weeks)
+	since := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+
+	day1 := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	recurring := statsMeeting(day1.AddDate(0, 0, 7), 60, "boss@example.com")
+	recurring.RecurringEventID = "series1"
+
+	events := []*Event{
+		// Back-to-back run of three on day one
+		statsMeeting(day1, 60, "alice@example.com"),
+		statsMeeting(day1.Add(60*time.Minute), 30, "alice@example.com"),
+		statsMeeting(day1.Add(95*time.Minute), 30, "bob@example.com"),
+		// Isolated meeting later that day
+		statsMeeting(day1.Add(6*time.Hour), 60, "bob@example.com"),
+		// Second week
+		recurring,
+		// Outside the range
+		statsMeeting(until.Add(time.Hour), 60, "alice@example.com"),
+		// Excluded all-day event
+		NewAllDayEvent("Offsite", day1),
+		nil,
+	}
+
+	stats := ComputeStats(events, since, until, StatsOptions{TopOrganizers: 2})
+
+	if stats.MeetingCount != 5 {
+		t.Errorf("MeetingCount = %d, want 5", stats.MeetingCount)
+	}
+	if stats.TotalHours != 4 {
+		t.Errorf("TotalHours = %v, want 4", stats.TotalHours)
+	}
+	if len(stats.Weeks) != 2 {
+		t.Fatalf("len(Weeks) = %d, want 2", len(stats.Weeks))
+	}
+	if stats.Weeks[0].Hours != 3 || stats.Weeks[0].Meetings != 4 {
+		t.Errorf("week 1 = %+v, want 3h and 4 meetings", stats.Weeks[0])
+	}
+	if stats.Weeks[1].Hours != 1 || stats.Weeks[1].Meetings != 1 {
+		t.Errorf("week 2 = %+v, want 1h and 1 meeting", stats.Weeks[1])
+	}
+	if stats.AverageWeeklyHours != 2 {
+		t.Errorf("AverageWeeklyHours = %v, want 2", stats.AverageWeeklyHours)
+	}
+	if stats.RecurringCount != 1 || stats.AdHocCount != 4 {
+		t.Errorf("recurring/ad-hoc = %d/%d, want 1/4", stats.RecurringCount, stats.AdHocCount)
+	}
+	if stats.RecurringRatio() != 0.2 {
+		t.Errorf("RecurringRatio() = %v, want 0.2", stats.RecurringRatio())
+	}
+
+	if len(stats.TopOrganizers) != 2 {
+		t.Fatalf("len(TopOrganizers) = %d, want 2", len(stats.TopOrganizers))
+	}
+	if stats.TopOrganizers[0].Email != "alice@example.com" || stats.TopOrganizers[0].Meetings != 2 {
+		t.Errorf("TopOrganizers[0] = %+v, want alice with 2", stats.TopOrganizers[0])
+	}
+	if stats.TopOrganizers[1].Email != "bob@example.com" {
+		t.Errorf("TopOrganizers[1] = %+v, want bob", stats.TopOrganizers[1])
+	}
+
+	if stats.BackToBackStreaks != 1 {
+		t.Errorf("BackToBackStreaks = %d, want 1", stats.BackToBackStreaks)
+	}
+	if stats.LongestStreak == nil {
+		t.Fatal("expected a longest streak")
+	}
+	if stats.LongestStreak.Meetings != 3 {
+		t.Errorf("LongestStreak.Meetings = %d, want 3", stats.LongestStreak.Meetings)
+	}
+	if stats.LongestStreak.Duration() != 125*time.Minute {
+		t.Errorf("LongestStreak.Duration() = %v, want 2h5m", stats.LongestStreak.Duration())
+	}
+}
+
+func TestComputeStats_GapOption(t *testing.T) {
+	since := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	events := []*Event{
+		statsMeeting(start, 30, ""),
+		statsMeeting(start.Add(45*time.Minute), 30, ""),
+	}
+
+	if s := ComputeStats(events, since, until, StatsOptions{}); s.BackToBackStreaks != 0 {
+		t.Errorf("default gap: BackToBackStreaks = %d, want 0", s.BackToBackStreaks)
+	}
+	if s := ComputeStats(events, since, until, StatsOptions{BackToBackGap: 15 * time.Minute}); s.BackToBackStreaks != 1 {
+		t.Errorf("15m gap: BackToBackStreaks = %d, want 1", s.BackToBackStreaks)
+	}
+}
+
+func TestComputeStats_Empty(t *testing.T) {
+	since := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	stats := ComputeStats(nil, since, since.AddDate(0, 0, 3), StatsOptions{})
+
+	if stats.MeetingCount != 0 {
+		t.Errorf("MeetingCount = %d, want 0", stats.MeetingCount)
+	}
+	if stats.RecurringRatio() != 0 {
+		t.Errorf("RecurringRatio() = %v, want 0", stats.RecurringRatio())
+	}
+	if stats.LongestStreak != nil {
+		t.Errorf("LongestStreak = %+v, want nil", stats.LongestStreak)
+	}
+	if len(stats.Weeks) != 1 {
+		t.Errorf("len(Weeks) = %d, want 1", len(stats.Weeks))
+	}
+}
+
+func TestEventIsRecurring_Instance(t *testing.T) {
+	event := NewEvent("Standup", time.Now(), time.Now().Add(15*time.Minute))
+	event.RecurringEventID = "series123"
+
+	if !event.IsRecurring() {
+		t.Error("expected IsRecurring to be true for recurring instance")
+	}
+}
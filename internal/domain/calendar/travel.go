@@ -0,0 +1,115 @@
+package calendar
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// BackToBackGap is the least time assumed to be needed between events at
+// different locations when no travel estimate is available.
+const BackToBackGap = 15 * time.Minute
+
+// TravelLookaround is how far before and after an event neighbouring
+// events are checked for travel.
+const TravelLookaround = 3 * time.Hour
+
+// TravelEstimator estimates how long it takes to get from one location to
+// another.
+type TravelEstimator interface {
+	EstimateTravel(ctx context.Context, from, to string) (time.Duration, error)
+}
+
+// TravelWarning flags a neighbouring event at a different location that
+// leaves too little time to travel.
+type TravelWarning struct {
+	// Other is the neighbouring event.
+	Other *Event
+	// After is true when Other follows the event and false when it precedes it.
+	After bool
+	// Gap is the time between the two events.
+	Gap time.Duration
+	// Travel is the estimated travel time, or zero when no estimate was
+	// available and BackToBackGap was used instead.
+	Travel time.Duration
+}
+
+// CheckTravel returns warnings for the events in others that end just
+// before event or start just after it at a different location, with less
+// time between them than estimator predicts for the journey. Without an
+// estimator, or when it fails, gaps shorter than BackToBackGap are flagged.
+func CheckTravel(ctx context.Context, event *Event, others []*Event, estimator TravelEstimator) []*TravelWarning {
+	if event == nil || event.AllDay || strings.TrimSpace(event.Location) == "" {
+		return nil
+	}
+
+	var before, after *Event
+	for _, o := range others {
+		if !travelCandidate(event, o) {
+			continue
+		}
+		if !o.End.After(event.Start) && event.Start.Sub(o.End) <= TravelLookaround {
+			if before == nil || o.End.After(before.End) {
+				before = o
+			}
+		}
+		if !o.Start.Before(event.End) && o.Start.Sub(event.End) <= TravelLookaround {
+			if after == nil || o.Start.Before(after.Start) {
+				after = o
+			}
+		}
+	}
+
+	var warnings []*TravelWarning
+	if before != nil {
+		if w := travelWarning(ctx, estimator, before.Location, event.Location, event.Start.Sub(before.End)); w != nil {
+			w.Other = before
+			warnings = append(warnings, w)
+		}
+	}
+	if after != nil {
+		if w := travelWarning(ctx, estimator, event.Location, after.Location, after.Start.Sub(event.End)); w != nil {
+			w.Other, w.After = after, true
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// travelCandidate reports whether o is a timed event, other than event,
+// at a different location that the user is expected to attend.
+func travelCandidate(event, o *Event) bool {
+	if o == nil || o.AllDay || o.Status == StatusCancelled || strings.TrimSpace(o.Location) == "" {
+		return false
+	}
+	if o.ID != "" && o.ID == event.ID {
+		return false
+	}
+	for _, a := range o.Attendees {
+		if a.Self && a.ResponseStatus == ResponseDeclined {
+			return false
+		}
+	}
+	return !SameLocation(o.Location, event.Location)
+}
+
+// travelWarning returns a warning when gap is shorter than the time
+// needed to get from from to to, or nil.
+func travelWarning(ctx context.Context, estimator TravelEstimator, from, to string, gap time.Duration) *TravelWarning {
+	need, travel := BackToBackGap, time.Duration(0)
+	if estimator != nil {
+		if d, err := estimator.EstimateTravel(ctx, from, to); err == nil {
+			need, travel = d, d
+		}
+	}
+	if gap >= need {
+		return nil
+	}
+	return &TravelWarning{Gap: gap, Travel: travel}
+}
+
+// SameLocation reports whether two locations name the same place, ignoring
+// case and spacing.
+func SameLocation(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixedEstimator returns the same travel time, or err, for every journey.
+type fixedEstimator struct {
+	d   time.Duration
+	err error
+}
+
+func (e fixedEstimator) EstimateTravel(ctx context.Context, from, to string) (time.Duration, error) {
+	return e.d, e.err
+}
+
+func TestCheckTravel(t *testing.T) {
+	ctx := context.Background()
+	at := func(h, m int) time.Time { return time.Date(2024, 3, 11, h, m, 0, 0, time.UTC) }
+	event := &Event{ID: "new", Title: "Lunch", Location: "Cafe Luna", Start: at(12, 0), End: at(13, 0)}
+	standup := &Event{ID: "a", Title: "Standup", Location: "Office", Start: at(11, 0), End: at(11, 55)}
+	review := &Event{ID: "b", Title: "Review", Location: "Office", Start: at(13, 30), End: at(14, 0)}
+
+	t.Run("back-to-back without estimator", func(t *testing.T) {
+		warnings := CheckTravel(ctx, event, []*Event{review, standup}, nil)
+		if len(warnings) != 1 {
+			t.Fatalf("got %d warnings, want 1", len(warnings))
+		}
+		w := warnings[0]
+		if w.Other != standup || w.After || w.Gap != 5*time.Minute || w.Travel != 0 {
+			t.Errorf("unexpected warning: %+v", w)
+		}
+	})
+
+	t.Run("estimator", func(t *testing.T) {
+		warnings := CheckTravel(ctx, event, []*Event{standup, review}, fixedEstimator{d: 40 * time.Minute})
+		if len(warnings) != 2 {
+			t.Fatalf("got %d warnings, want 2", len(warnings))
+		}
+		if !warnings[1].After || warnings[1].Other != review || warnings[1].Travel != 40*time.Minute {
+			t.Errorf("unexpected warning: %+v", warnings[1])
+		}
+	})
+
+	t.Run("estimator error falls back", func(t *testing.T) {
+		warnings := CheckTravel(ctx, event, []*Event{standup, review}, fixedEstimator{err: errors.New("offline")})
+		if len(warnings) != 1 || warnings[0].Other != standup {
+			t.Errorf("unexpected warnings: %+v", warnings)
+		}
+	})
+
+	t.Run("ignored neighbours", func(t *testing.T) {
+		sameRoom := &Event{Title: "Prep", Location: "  cafe   LUNA ", Start: at(11, 30), End: at(12, 0)}
+		declined := &Event{Title: "Call", Location: "Office", Start: at(11, 0), End: at(12, 0),
+			Attendees: []*Attendee{{Email: "me@example.com", Self: true, ResponseStatus: ResponseDeclined}}}
+		cancelled := &Event{Title: "Old", Location: "Office", Start: at(13, 0), End: at(13, 30), Status: StatusCancelled}
+		allDay := &Event{Title: "Offsite", Location: "Office", AllDay: true, Start: at(0, 0), End: at(0, 0).AddDate(0, 0, 1)}
+		far := &Event{Title: "Dinner", Location: "Home", Start: at(18, 0), End: at(19, 0)}
+		self := &Event{ID: "new", Title: "Lunch", Location: "Elsewhere", Start: at(13, 0), End: at(13, 30)}
+		others := []*Event{sameRoom, declined, cancelled, allDay, far, self}
+		if warnings := CheckTravel(ctx, event, others, fixedEstimator{d: 6 * time.Hour}); len(warnings) != 0 {
+			t.Errorf("expected no warnings, got %+v", warnings)
+		}
+	})
+
+	t.Run("events without a location are not checked", func(t *testing.T) {
+		remote := &Event{Title: "Call", Start: at(12, 0), End: at(13, 0)}
+		if warnings := CheckTravel(ctx, remote, []*Event{standup}, nil); warnings != nil {
+			t.Errorf("expected no warnings, got %+v", warnings)
+		}
+	})
+}
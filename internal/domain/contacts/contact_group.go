@@ -2,6 +2,7 @@ package contacts
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -51,3 +52,30 @@ func (g *ContactGroup) IsSystemGroup() bool {
 func (g *ContactGroup) CanModify() bool {
 	return !g.IsSystemGroup()
 }
+
+// MatchesName returns true if name refers to the group: its resource name,
+// its name in any case, or its name in lower case with runs of spaces
+// replaced by hyphens, so "team-platform" matches "Team Platform"
+func (g *ContactGroup) MatchesName(name string) bool {
+	if name == "" {
+		return false
+	}
+	if name == g.ResourceName {
+		return true
+	}
+	for _, candidate := range []string{g.Name, g.FormattedName} {
+		if candidate == "" {
+			continue
+		}
+		if strings.EqualFold(candidate, name) || strings.EqualFold(GroupSlug(candidate), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupSlug returns name in lower case with runs of whitespace replaced by
+// single hyphens
+func GroupSlug(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
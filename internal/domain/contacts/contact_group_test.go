@@ -118,3 +118,29 @@ func TestContactGroup_CanModify(t *testing.T) {
 		})
 	}
 }
+
+func TestContactGroup_MatchesName(t *testing.T) {
+	group := &ContactGroup{ResourceName: "contactGroups/abc", Name: "Team  Platform", FormattedName: "Team  Platform"}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"contactGroups/abc", true},
+		{"Team  Platform", true},
+		{"team  platform", true},
+		{"team-platform", true},
+		{"TEAM-PLATFORM", true},
+		{"team", false},
+		{"team_platform", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := group.MatchesName(tt.name); got != tt.want {
+				t.Errorf("MatchesName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package mail
+
+import (
+	"fmt"
+	netmail "net/mail"
+	"strings"
+)
+
+// Limits RFC 5321 sets on the parts of an address.
+const (
+	maxLocalPartLength = 64
+	maxDomainLength    = 253
+	maxLabelLength     = 63
+)
+
+// CommonMailDomains are widely used mail providers, checked for typos
+// alongside the domains a user has written to before.
+var CommonMailDomains = []string{
+	"gmail.com", "googlemail.com", "yahoo.com", "ymail.com", "hotmail.com",
+	"outlook.com", "live.com", "msn.com", "icloud.com", "me.com", "mac.com",
+	"aol.com", "proton.me", "protonmail.com", "gmx.com", "gmx.de", "gmx.net",
+	"mail.com", "fastmail.com", "zoho.com", "yandex.com", "web.de", "qq.com",
+	"comcast.net", "yahoo.co.uk", "hotmail.co.uk",
+}
+
+// ValidateAddress parses addr as a single RFC 5322 address, optionally with
+// a display name, and checks the lengths and hostname rules RFC 5321 sets
+// for delivery over the internet. It returns the bare address, with any
+// quoting of the local part removed.
+func ValidateAddress(addr string) (string, error) {
+	parsed, err := netmail.ParseAddress(strings.TrimSpace(addr))
+	if err != nil {
+		return "", fmt.Errorf("invalid address %q: %s", addr, strings.TrimPrefix(err.Error(), "mail: "))
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], parsed.Address[at+1:]
+	if len(local) > maxLocalPartLength {
+		return "", fmt.Errorf("invalid address %q: local part is longer than %d characters", addr, maxLocalPartLength)
+	}
+	if err := validateMailDomain(domain); err != nil {
+		return "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	return parsed.Address, nil
+}
+
+// validateMailDomain checks that domain is a fully qualified hostname.
+func validateMailDomain(domain string) error {
+	if len(domain) > maxDomainLength {
+		return fmt.Errorf("domain is longer than %d characters", maxDomainLength)
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("domain %q is not fully qualified", domain)
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > maxLabelLength {
+			return fmt.Errorf("domain %q has an empty or overlong label", domain)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("domain %q has a label starting or ending with a hyphen", domain)
+		}
+		for _, r := range label {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f) {
+				return fmt.Errorf("domain %q contains %q", domain, r)
+			}
+		}
+	}
+	if tld := labels[len(labels)-1]; strings.Trim(tld, "0123456789") == "" {
+		return fmt.Errorf("domain %q has a numeric top-level domain", domain)
+	}
+	return nil
+}
+
+// AddressDomain returns the lower-cased domain of a bare address.
+func AddressDomain(addr string) string {
+	at := strings.LastIndex(addr, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(addr[at+1:])
+}
+
+// SuggestAddress returns addr with its domain replaced by the closest of
+// knownDomains when the domain itself is not known and differs from that
+// domain by a likely typo, e.g. "gamil.com" for "gmail.com". It returns ""
+// when there is nothing to suggest. Ties go to the domain listed first.
+func SuggestAddress(addr string, knownDomains []string) string {
+	domain := AddressDomain(addr)
+	if domain == "" {
+		return ""
+	}
+	for _, known := range knownDomains {
+		if strings.EqualFold(known, domain) {
+			return ""
+		}
+	}
+
+	best, bestDistance := "", 0
+	for _, known := range knownDomains {
+		known = strings.ToLower(known)
+		d := editDistance(domain, known)
+		if d > typoThreshold(known) {
+			continue
+		}
+		if best == "" || d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return addr[:strings.LastIndex(addr, "@")+1] + best
+}
+
+// typoThreshold is the largest edit distance from domain still treated as
+// a typo. Short domains allow one edit so that distinct short names are
+// not confused.
+func typoThreshold(domain string) int {
+	if len(domain) <= 6 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters needed to turn one into the other.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// Address is an email address with its optional display name, as in
+// "Alice Smith <alice@example.com>".
+type Address struct {
+	Name  string
+	Email string
+}
+
+// ParseAddress parses a single address with or without a display name.
+// Values that are not valid RFC 5322 addresses are kept rather than
+// rejected, as headers of received mail are often malformed: text in
+// angle brackets becomes the email and text before it the name, and
+// anything else is taken as the email.
+func ParseAddress(s string) Address {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Address{}
+	}
+	if parsed, err := netmail.ParseAddress(s); err == nil {
+		return Address{Name: parsed.Name, Email: parsed.Address}
+	}
+	if open := strings.LastIndex(s, "<"); open >= 0 && strings.HasSuffix(s, ">") {
+		name := strings.Trim(strings.TrimSpace(s[:open]), `"`)
+		return Address{Name: name, Email: strings.TrimSpace(s[open+1 : len(s)-1])}
+	}
+	return Address{Email: s}
+}
+
+// ParseAddressList parses a comma-separated address list such as a To
+// header. A list that is not valid RFC 5322 is split at commas and each
+// entry parsed with ParseAddress.
+func ParseAddressList(s string) []Address {
+	if strings.TrimSpace(s) == "" {
+		return []Address{}
+	}
+	if parsed, err := netmail.ParseAddressList(s); err == nil {
+		list := make([]Address, len(parsed))
+		for i, addr := range parsed {
+			list[i] = Address{Name: addr.Name, Email: addr.Address}
+		}
+		return list
+	}
+	return ParseAddresses(strings.Split(s, ","))
+}
+
+// ParseAddresses parses each value with ParseAddress, skipping empty ones.
+func ParseAddresses(values []string) []Address {
+	list := make([]Address, 0, len(values))
+	for _, v := range values {
+		if addr := ParseAddress(v); !addr.IsZero() {
+			list = append(list, addr)
+		}
+	}
+	return list
+}
+
+// IsZero reports whether a has neither a name nor an email.
+func (a Address) IsZero() bool {
+	return a.Name == "" && a.Email == ""
+}
+
+// String returns the address as "Name <email>", quoting the name when
+// needed, or just the email when there is no name. Names are not
+// encoded; EncodeAddress does that for headers of outgoing mail.
+func (a Address) String() string {
+	switch {
+	case a.Name == "":
+		return a.Email
+	case a.Email == "":
+		return a.Name
+	}
+	name := a.Name
+	if strings.ContainsAny(name, `()<>[]:;@\,."`) {
+		name = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`
+	}
+	return name + " <" + a.Email + ">"
+}
+
+// MarshalText writes the address as String does, so addresses appear as
+// plain strings in JSON output and stored messages.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses an address written by MarshalText.
+func (a *Address) UnmarshalText(text []byte) error {
+	*a = ParseAddress(string(text))
+	return nil
+}
+
+// AddressStrings returns each address as a string.
+func AddressStrings(list []Address) []string {
+	values := make([]string, len(list))
+	for i, addr := range list {
+		values[i] = addr.String()
+	}
+	return values
+}
+
+// JoinAddresses returns the addresses as a comma-separated list.
+func JoinAddresses(list []Address) string {
+	return strings.Join(AddressStrings(list), ", ")
+}
+
+// MissingAddresses returns the addresses that are not already in any of
+// the recipient lists, comparing the email parts without case. Repeated
+// addresses are returned once.
+func MissingAddresses(addrs []string, lists ...[]string) []string {
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, recipient := range list {
+			seen[strings.ToLower(ParseAddress(recipient).Email)] = true
+		}
+	}
+	var missing []string
+	for _, addr := range addrs {
+		key := strings.ToLower(ParseAddress(addr).Email)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		missing = append(missing, addr)
+	}
+	return missing
+}
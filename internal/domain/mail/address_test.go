@@ -0,0 +1,167 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		addr    string
+		want    string
+		wantErr string
+	}{
+		{addr: "user@example.com", want: "user@example.com"},
+		{addr: "Alice Smith <alice@example.com>", want: "alice@example.com"},
+		{addr: `"a b"@example.com`, want: "a b@example.com"},
+		{addr: "a..b@example.com", wantErr: "invalid address"},
+		{addr: "a@example.com, b@example.com", wantErr: "expected single address"},
+		{addr: "user@localhost", wantErr: "not fully qualified"},
+		{addr: "user@-example.com", wantErr: "hyphen"},
+		{addr: "user@exa_mple.com", wantErr: "contains"},
+		{addr: "user@10.0.0.1", wantErr: "numeric top-level domain"},
+		{addr: strings.Repeat("a", 65) + "@example.com", wantErr: "local part is longer"},
+		{addr: "user@" + strings.Repeat("a", 64) + ".com", wantErr: "overlong label"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			got, err := ValidateAddress(tt.addr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateAddress() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ValidateAddress() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestAddress(t *testing.T) {
+	known := append([]string{"acme-corp.com"}, CommonMailDomains...)
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"bob@gamil.com", "bob@gmail.com"},
+		{"bob@gmail.con", "bob@gmail.com"},
+		{"Bob@GMIAL.COM", "Bob@gmail.com"},
+		{"bob@hotmial.com", "bob@hotmail.com"},
+		{"bob@acme-crop.com", "bob@acme-corp.com"},
+		{"bob@gmail.com", ""},
+		{"bob@ACME-CORP.COM", ""},
+		{"bob@ymail.com", ""},
+		{"bob@example.org", ""},
+		{"bob@aol.co", "bob@aol.com"},
+		{"bob@mail.ru", ""},
+	}
+	for _, tt := range tests {
+		if got := SuggestAddress(tt.addr, known); got != tt.want {
+			t.Errorf("SuggestAddress(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"gmail.com", "gmail.com", 0},
+		{"gamil.com", "gmail.com", 1},
+		{"gmal.com", "gmail.com", 1},
+		{"gmaill.com", "gmail.com", 1},
+		{"hotmail.com", "gmail.com", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Address
+	}{
+		{"alice@example.com", Address{Email: "alice@example.com"}},
+		{"Alice Smith <alice@example.com>", Address{Name: "Alice Smith", Email: "alice@example.com"}},
+		{`"Smith, Alice" <alice@example.com>`, Address{Name: "Smith, Alice", Email: "alice@example.com"}},
+		{"=?UTF-8?Q?Jos=C3=A9?= <jose@example.com>", Address{Name: "José", Email: "jose@example.com"}},
+		{"Broken Sender <not an address>", Address{Name: "Broken Sender", Email: "not an address"}},
+		{"undisclosed-recipients", Address{Email: "undisclosed-recipients"}},
+		{"  ", Address{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := ParseAddress(tt.in); got != tt.want {
+				t.Errorf("ParseAddress(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAddressList(t *testing.T) {
+	got := ParseAddressList(`"Smith, Alice" <alice@example.com>, bob@example.com`)
+	want := []Address{{Name: "Smith, Alice", Email: "alice@example.com"}, {Email: "bob@example.com"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAddressList() = %#v, want %#v", got, want)
+	}
+
+	// Malformed lists fall back to splitting at commas
+	got = ParseAddressList("alice@example.com, , Bob <bob@example.com>,")
+	want = []Address{{Email: "alice@example.com"}, {Name: "Bob", Email: "bob@example.com"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAddressList() = %#v, want %#v", got, want)
+	}
+}
+
+func TestAddress_String(t *testing.T) {
+	tests := []struct {
+		addr Address
+		want string
+	}{
+		{Address{Email: "alice@example.com"}, "alice@example.com"},
+		{Address{Name: "Alice Smith", Email: "alice@example.com"}, "Alice Smith <alice@example.com>"},
+		{Address{Name: "Smith, Alice", Email: "alice@example.com"}, `"Smith, Alice" <alice@example.com>`},
+		{Address{Name: "José", Email: "jose@example.com"}, "José <jose@example.com>"},
+	}
+	for _, tt := range tests {
+		if got := tt.addr.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+		if got := ParseAddress(tt.want); got != tt.addr {
+			t.Errorf("ParseAddress(%q) = %#v, want %#v", tt.want, got, tt.addr)
+		}
+	}
+}
+
+func TestAddress_JSON(t *testing.T) {
+	msg := Message{From: Address{Name: "Alice", Email: "alice@example.com"}, To: []Address{{Email: "bob@example.com"}}}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if fields["From"] != "Alice <alice@example.com>" || fmt.Sprint(fields["To"]) != "[bob@example.com]" {
+		t.Errorf("addresses should encode as strings: %s", data)
+	}
+
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if decoded.From != msg.From || !slices.Equal(decoded.To, msg.To) {
+		t.Errorf("round trip = %#v, %#v", decoded.From, decoded.To)
+	}
+}
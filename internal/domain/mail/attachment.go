@@ -1,12 +1,15 @@
 package mail
 
 // Attachment represents an email attachment.
+// An attachment with a ContentID is rendered inline and referenced from the
+// HTML body as cid:<ContentID>.
 type Attachment struct {
-	ID       string
-	Filename string
-	MimeType string
-	Size     int64
-	Data     []byte
+	ID        string
+	Filename  string
+	MimeType  string
+	Size      int64
+	Data      []byte
+	ContentID string
 }
 
 // NewAttachment creates a new Attachment with the given parameters.
@@ -44,6 +47,11 @@ func (a *Attachment) IsImage() bool {
 	}
 }
 
+// IsInline returns true if the attachment is referenced inline by content ID.
+func (a *Attachment) IsInline() bool {
+	return a.ContentID != ""
+}
+
 // IsPDF returns true if the attachment is a PDF document.
 func (a *Attachment) IsPDF() bool {
 	return a.MimeType == "application/pdf"
@@ -107,3 +107,15 @@ func TestAttachment_IsPDF(t *testing.T) {
 		t.Error("expected IsPDF to return false for image/png")
 	}
 }
+
+func TestAttachment_IsInline(t *testing.T) {
+	att := NewAttachment("1", "chart.png", "image/png")
+	if att.IsInline() {
+		t.Error("expected attachment without content ID to not be inline")
+	}
+
+	att.ContentID = "chart"
+	if !att.IsInline() {
+		t.Error("expected attachment with content ID to be inline")
+	}
+}
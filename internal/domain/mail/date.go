@@ -0,0 +1,157 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// obsoleteZones are the zone names RFC 5322 section 4.3 still allows in
+// received mail, with their offsets.
+var obsoleteZones = map[string]string{
+	"UT":  "+0000",
+	"UTC": "+0000",
+	"GMT": "+0000",
+	"Z":   "+0000",
+	"EST": "-0500",
+	"EDT": "-0400",
+	"CST": "-0600",
+	"CDT": "-0500",
+	"MST": "-0700",
+	"MDT": "-0600",
+	"PST": "-0800",
+	"PDT": "-0700",
+}
+
+// dateLayouts are tried in order on a normalised Date header: the day of
+// the week and comments removed, and the zone given as an offset.
+var dateLayouts = []string{
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 -0700 2006",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC3339,
+}
+
+// ParseDate parses the value of a Date header. Besides the RFC 5322 form
+// it accepts what real mail carries: a missing or full day of the week,
+// comments such as "(PDT)", two-digit years, missing seconds, obsolete
+// zone names such as EST, single military zone letters (taken as UTC, as
+// RFC 5322 advises), "+02:00" offsets and asctime dates. A date without a
+// zone is taken as UTC.
+func ParseDate(value string) (time.Time, error) {
+	fields := strings.Fields(stripComments(value))
+	if len(fields) > 0 && isWeekday(strings.TrimSuffix(fields[0], ",")) {
+		fields = fields[1:]
+	}
+	for i, f := range fields {
+		f = strings.TrimSuffix(f, ",")
+		if month := monthPrefix(f); month != "" {
+			f = month
+		}
+		fields[i] = f
+	}
+	fields = normaliseZone(fields)
+
+	s := strings.Join(fields, " ")
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(layout, " 06 ") && t.Year() >= 2050 {
+			// RFC 5322: two-digit years from 50 are in the 1900s
+			t = t.AddDate(-100, 0, 0)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// stripComments removes parenthesised comments, which may nest.
+func stripComments(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normaliseZone rewrites a trailing zone name as an offset, drops a name
+// that follows an offset and removes the colon from "+02:00".
+func normaliseZone(fields []string) []string {
+	if len(fields) == 0 {
+		return fields
+	}
+	last := len(fields) - 1
+	zone := strings.ToUpper(fields[last])
+	offset, named := obsoleteZones[zone]
+	if !named && len(zone) == 1 && zone[0] >= 'A' && zone[0] <= 'Z' && zone != "J" {
+		offset, named = "-0000", true
+	}
+	if named {
+		if last > 0 && isOffset(fields[last-1]) {
+			return normaliseZone(fields[:last])
+		}
+		fields[last] = offset
+		return fields
+	}
+	if isOffset(fields[last]) {
+		fields[last] = strings.Replace(fields[last], ":", "", 1)
+	}
+	return fields
+}
+
+// isOffset reports whether s is a zone offset such as "-0700" or "+02:00".
+func isOffset(s string) bool {
+	if len(s) < 5 || (s[0] != '+' && s[0] != '-') {
+		return false
+	}
+	for _, r := range strings.Replace(s[1:], ":", "", 1) {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return len(strings.Replace(s, ":", "", 1)) == 5
+}
+
+// isWeekday reports whether s names a day of the week, in full or short.
+func isWeekday(s string) bool {
+	if len(s) < 3 {
+		return false
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// monthPrefix returns the short name of a month given in full, such as
+// "Jan" for "January", and "" for anything else.
+func monthPrefix(s string) string {
+	if len(s) <= 3 {
+		return ""
+	}
+	for m := time.January; m <= time.December; m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m.String()[:3]
+		}
+	}
+	return ""
+}
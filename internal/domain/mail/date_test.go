@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Mon, 2 Jan 2006 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"2 Jan 2006 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"Monday, 02 January 2006 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"Mon, 2 Jan 2006 15:04:05 -0700 (PDT)", "2006-01-02T15:04:05-07:00"},
+		{"Mon,  2 Jan 2006  9:04:05 +0000 (GMT)", "2006-01-02T09:04:05Z"},
+		{"Mon, 2 Jan 2006 15:04:05 GMT", "2006-01-02T15:04:05Z"},
+		{"Mon, 2 Jan 2006 15:04:05 EST", "2006-01-02T15:04:05-05:00"},
+		{"Mon, 2 Jan 2006 15:04:05 -0400 EDT", "2006-01-02T15:04:05-04:00"},
+		{"Mon, 2 Jan 2006 15:04:05 Z", "2006-01-02T15:04:05Z"},
+		{"Mon, 2 Jan 2006 15:04:05 M", "2006-01-02T15:04:05Z"},
+		{"Mon, 2 Jan 2006 15:04 +0100", "2006-01-02T15:04:00+01:00"},
+		{"Mon, 2 Jan 2006 15:04:05 +02:00", "2006-01-02T15:04:05+02:00"},
+		{"2 Jan 06 15:04:05 -0700", "2006-01-02T15:04:05-07:00"},
+		{"2 Jan 99 15:04:05 -0700", "1999-01-02T15:04:05-07:00"},
+		{"2 Jan 2006 15:04:05", "2006-01-02T15:04:05Z"},
+		{"Mon Jan  2 15:04:05 2006", "2006-01-02T15:04:05Z"},
+		{"2006-01-02T15:04:05+01:00", "2006-01-02T15:04:05+01:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDate(tt.value)
+			if err != nil {
+				t.Fatalf("ParseDate() error = %v", err)
+			}
+			if got.Format(time.RFC3339) != tt.want {
+				t.Errorf("ParseDate() = %s, want %s", got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "Mon, 32 Jan 2006 15:04:05 -0700"} {
+		if _, err := ParseDate(value); err == nil {
+			t.Errorf("ParseDate(%q) should fail", value)
+		}
+	}
+}
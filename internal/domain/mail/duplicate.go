@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// ContentHash returns a hash of what makes two messages the same message
+// sent twice: their recipients, ignoring order, case and display names,
+// their subject and their text and HTML bodies, ignoring line endings and
+// trailing white space, which Gmail may change when it stores a message.
+func ContentHash(m *Message) string {
+	h := sha256.New()
+	for _, list := range [][]Address{m.To, m.Cc, m.Bcc} {
+		emails := make([]string, 0, len(list))
+		for _, addr := range list {
+			emails = append(emails, strings.ToLower(strings.TrimSpace(addr.Email)))
+		}
+		sort.Strings(emails)
+		h.Write([]byte(strings.Join(emails, ",") + "\x00"))
+	}
+	h.Write([]byte(strings.TrimSpace(m.Subject) + "\x00"))
+	h.Write([]byte(normalizeBody(m.Body) + "\x00"))
+	h.Write([]byte(normalizeBody(m.BodyHTML)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeBody drops carriage returns and white space at the ends of
+// lines and of the body.
+func normalizeBody(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// FindDuplicate returns the first message in sent with the same content
+// hash as msg, or nil when there is none.
+func FindDuplicate(msg *Message, sent []*Message) *Message {
+	want := ContentHash(msg)
+	for _, s := range sent {
+		if ContentHash(s) == want {
+			return s
+		}
+	}
+	return nil
+}
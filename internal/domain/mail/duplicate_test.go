@@ -0,0 +1,48 @@
+package mail
+
+import "testing"
+
+func TestContentHash(t *testing.T) {
+	base := func() *Message {
+		return &Message{
+			To:      ParseAddresses([]string{"ana@example.com", "bo@example.com"}),
+			Cc:      ParseAddresses([]string{"cy@example.com"}),
+			Subject: "Disk full",
+			Body:    "Disk /var is full.\nPlease check.",
+		}
+	}
+
+	same := base()
+	same.To = ParseAddresses([]string{"Bo <BO@example.com>", "ana@example.com"})
+	same.Body = "Disk /var is full.  \r\nPlease check.\r\n"
+	if ContentHash(base()) != ContentHash(same) {
+		t.Error("recipient order, case, names and line endings should not change the hash")
+	}
+
+	for name, change := range map[string]func(*Message){
+		"recipient": func(m *Message) { m.To = m.To[:1] },
+		"cc as to":  func(m *Message) { m.To, m.Cc = append(m.To, m.Cc...), nil },
+		"subject":   func(m *Message) { m.Subject = "Disk almost full" },
+		"body":      func(m *Message) { m.Body = "Disk /tmp is full." },
+		"html":      func(m *Message) { m.BodyHTML, m.Body = m.Body, "" },
+	} {
+		m := base()
+		change(m)
+		if ContentHash(m) == ContentHash(base()) {
+			t.Errorf("changing the %s should change the hash", name)
+		}
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+	msg := &Message{To: ParseAddresses([]string{"ana@example.com"}), Subject: "Hi", Body: "Hello"}
+	other := &Message{ID: "1", To: msg.To, Subject: "Hi", Body: "Hello again"}
+	dup := &Message{ID: "2", To: msg.To, Subject: "Hi", Body: "Hello\n"}
+
+	if got := FindDuplicate(msg, []*Message{other, dup}); got != dup {
+		t.Errorf("FindDuplicate() = %v, want message 2", got)
+	}
+	if got := FindDuplicate(msg, []*Message{other}); got != nil {
+		t.Errorf("FindDuplicate() = %v, want nil", got)
+	}
+}
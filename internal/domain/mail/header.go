@@ -0,0 +1,232 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	netmail "net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHeaderLineLength is the longest header line RFC 5322 allows, without
+// the CRLF.
+const maxHeaderLineLength = 998
+
+// reservedHeaders are written by goog itself or by Gmail and cannot be set
+// as extra headers.
+var reservedHeaders = map[string]bool{
+	"from":                      true,
+	"sender":                    true,
+	"to":                        true,
+	"cc":                        true,
+	"bcc":                       true,
+	"subject":                   true,
+	"date":                      true,
+	"message-id":                true,
+	"in-reply-to":               true,
+	"references":                true,
+	"mime-version":              true,
+	"content-type":              true,
+	"content-transfer-encoding": true,
+	"content-disposition":       true,
+	"return-path":               true,
+	"received":                  true,
+	"dkim-signature":            true,
+}
+
+// addressHeaders hold address lists, which are checked as such.
+var addressHeaders = map[string]bool{
+	"reply-to":                    true,
+	"mail-followup-to":            true,
+	"disposition-notification-to": true,
+}
+
+// Header is an extra header field of an outgoing message.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// String returns the header as "Name: Value".
+func (h Header) String() string {
+	return h.Name + ": " + h.Value
+}
+
+// ParseHeader parses a header given as "Name: Value" and checks that it is
+// safe to add to a message: the name must be a valid field name that goog
+// does not set itself, and the value must be a single line. Address
+// headers such as Reply-To must hold valid addresses; their value is
+// returned in canonical form.
+func ParseHeader(s string) (Header, error) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return Header{}, fmt.Errorf("invalid header %q: use \"Name: value\"", s)
+	}
+	return CheckHeader(Header{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+}
+
+// CheckHeader checks a header given as name and value with the rules of
+// ParseHeader, for headers that do not come from a command line, such as
+// those of a goog api request. The value of an address header is returned
+// in canonical form.
+func CheckHeader(h Header) (Header, error) {
+	if err := h.validate(); err != nil {
+		return Header{}, err
+	}
+
+	if addressHeaders[strings.ToLower(h.Name)] {
+		list, err := netmail.ParseAddressList(h.Value)
+		if err != nil {
+			return Header{}, fmt.Errorf("invalid %s header: %s", h.Name, strings.TrimPrefix(err.Error(), "mail: "))
+		}
+		formatted := make([]string, 0, len(list))
+		for _, addr := range list {
+			formatted = append(formatted, addr.String())
+		}
+		h.Value = strings.Join(formatted, ", ")
+	}
+	return h, nil
+}
+
+// validate checks the header's name and value.
+func (h Header) validate() error {
+	if h.Name == "" {
+		return fmt.Errorf("invalid header %q: missing name", h.String())
+	}
+	for _, r := range h.Name {
+		// RFC 5322 field names are printable ASCII other than ':'
+		if r < '!' || r > '~' || r == ':' {
+			return fmt.Errorf("invalid header name %q", h.Name)
+		}
+	}
+	if reservedHeaders[strings.ToLower(h.Name)] {
+		return fmt.Errorf("header %s is set by goog and cannot be added", h.Name)
+	}
+	if h.Value == "" {
+		return fmt.Errorf("header %s has no value", h.Name)
+	}
+	for _, r := range h.Value {
+		if r != '\t' && (r < ' ' || r == 0x7f) {
+			return fmt.Errorf("header %s contains a control character", h.Name)
+		}
+	}
+	if len(h.String()) > maxHeaderLineLength {
+		return fmt.Errorf("header %s is longer than %d characters", h.Name, maxHeaderLineLength)
+	}
+	return nil
+}
+
+// MergeHeaders returns defaults followed by headers, where a header in
+// headers replaces any default with the same name, ignoring case.
+func MergeHeaders(defaults, headers []Header) []Header {
+	override := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		override[strings.ToLower(h.Name)] = true
+	}
+	var merged []Header
+	for _, h := range defaults {
+		if !override[strings.ToLower(h.Name)] {
+			merged = append(merged, h)
+		}
+	}
+	return append(merged, headers...)
+}
+
+// ParseMessageIDs returns the Message-IDs of a Message-ID, In-Reply-To or
+// References header value, without their angle brackets. Anything outside
+// angle brackets, such as comments, is skipped.
+func ParseMessageIDs(value string) []string {
+	var ids []string
+	for {
+		_, rest, ok := strings.Cut(value, "<")
+		if !ok {
+			return ids
+		}
+		id, after, ok := strings.Cut(rest, ">")
+		if !ok {
+			return ids
+		}
+		if validMessageID(id) {
+			ids = append(ids, id)
+		}
+		value = after
+	}
+}
+
+// FormatMessageIDs returns ids as a header value of Message-IDs in angle
+// brackets, leaving out any that could not be written safely.
+func FormatMessageIDs(ids []string) string {
+	var parts []string
+	for _, id := range ids {
+		if validMessageID(id) {
+			parts = append(parts, "<"+id+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// validMessageID reports whether id is a non-empty run of printable ASCII
+// without spaces or angle brackets.
+func validMessageID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; c <= ' ' || c >= 0x7f || c == '<' || c == '>' {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeHeaderText encodes a header value holding text, such as a
+// subject, as RFC 2047 encoded-words when it is not plain ASCII. Mostly
+// Latin text uses the readable Q encoding; text that is mostly non-ASCII,
+// such as Japanese, the shorter B encoding.
+func EncodeHeaderText(s string) string {
+	nonASCII, total := 0, 0
+	for _, r := range s {
+		total++
+		if r >= utf8.RuneSelf {
+			nonASCII++
+		}
+	}
+	if nonASCII*2 > total {
+		return mime.BEncoding.Encode("utf-8", s)
+	}
+	return mime.QEncoding.Encode("utf-8", s)
+}
+
+// EncodeAddress encodes the display name of an address such as
+// "José <jose@example.com>" as an RFC 2047 encoded-word. ASCII addresses,
+// and values that do not parse as an address, are returned unchanged.
+func EncodeAddress(addr string) string {
+	if isASCII(addr) {
+		return addr
+	}
+	parsed, err := netmail.ParseAddress(addr)
+	if err != nil || parsed.Name == "" {
+		return addr
+	}
+	return parsed.String()
+}
+
+// EncodeAddressList encodes each address with EncodeAddress and joins them
+// for an address header.
+func EncodeAddressList(addrs []string) string {
+	encoded := make([]string, len(addrs))
+	for i, addr := range addrs {
+		encoded[i] = EncodeAddress(addr)
+	}
+	return strings.Join(encoded, ", ")
+}
+
+// isASCII reports whether s is plain ASCII.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package mail
+
+import (
+	"mime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Header
+		wantErr string
+	}{
+		{input: "X-Campaign: launch", want: Header{Name: "X-Campaign", Value: "launch"}},
+		{input: "  List-Unsubscribe :  <mailto:unsub@example.com>, <https://example.com/u> ", want: Header{Name: "List-Unsubscribe", Value: "<mailto:unsub@example.com>, <https://example.com/u>"}},
+		{input: "Reply-To: Support <support@example.com>", want: Header{Name: "Reply-To", Value: `"Support" <support@example.com>`}},
+		{input: "X-Note: a: b", want: Header{Name: "X-Note", Value: "a: b"}},
+		{input: "X-Campaign", wantErr: "use \"Name: value\""},
+		{input: ": value", wantErr: "missing name"},
+		{input: "X Campaign: launch", wantErr: "invalid header name"},
+		{input: "subject: hi", wantErr: "set by goog"},
+		{input: "Bcc: hidden@example.com", wantErr: "set by goog"},
+		{input: "X-Empty:", wantErr: "no value"},
+		{input: "X-Inject: a\r\nBcc: evil@example.com", wantErr: "control character"},
+		{input: "Reply-To: not an address", wantErr: "invalid Reply-To header"},
+		{input: "X-Long: " + strings.Repeat("a", 1000), wantErr: "longer than 998"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHeader(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseHeader() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHeader() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHeader(t *testing.T) {
+	tests := []struct {
+		header  Header
+		want    Header
+		wantErr string
+	}{
+		{header: Header{"X-Campaign", "launch"}, want: Header{"X-Campaign", "launch"}},
+		{header: Header{"Reply-To", "Support <support@example.com>"}, want: Header{"Reply-To", `"Support" <support@example.com>`}},
+		{header: Header{"X-A\r\nBcc", "evil@example.com"}, wantErr: "invalid header name"},
+		{header: Header{"Bcc: x", "evil@example.com"}, wantErr: "invalid header name"},
+		{header: Header{"To", "evil@example.com"}, wantErr: "set by goog"},
+		{header: Header{"X-Inject", "a\nBcc: evil@example.com"}, wantErr: "control character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header.Name, func(t *testing.T) {
+			got, err := CheckHeader(tt.header)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CheckHeader() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckHeader() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeHeaders(t *testing.T) {
+	defaults := []Header{{"X-Mailer", "goog"}, {"Reply-To", "team@example.com"}}
+	headers := []Header{{"reply-to", "me@example.com"}, {"X-Campaign", "launch"}}
+
+	want := []Header{{"X-Mailer", "goog"}, {"reply-to", "me@example.com"}, {"X-Campaign", "launch"}}
+	if got := MergeHeaders(defaults, headers); !slices.Equal(got, want) {
+		t.Errorf("MergeHeaders() = %v, want %v", got, want)
+	}
+	if got := MergeHeaders(nil, nil); got != nil {
+		t.Errorf("MergeHeaders(nil, nil) = %v, want nil", got)
+	}
+}
+
+func TestEncodeHeaderText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Quarterly report", "Quarterly report"},
+		{"Grüße aus Köln", "=?utf-8?q?Gr=C3=BC=C3=9Fe_aus_K=C3=B6ln?="},
+		{"会議の議事録", "=?utf-8?b?5Lya6K2w44Gu6K2w5LqL6Yyy?="},
+	}
+	for _, tt := range tests {
+		got := EncodeHeaderText(tt.in)
+		if got != tt.want {
+			t.Errorf("EncodeHeaderText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if decoded, err := new(mime.WordDecoder).DecodeHeader(got); err != nil || decoded != tt.in {
+			t.Errorf("EncodeHeaderText(%q) decodes to %q, %v", tt.in, decoded, err)
+		}
+	}
+}
+
+func TestEncodeAddressList(t *testing.T) {
+	got := EncodeAddressList([]string{
+		"plain@example.com",
+		"Ann Lee <ann@example.com>",
+		"José Pérez <jose@example.com>",
+		"not an address ✓",
+	})
+	want := "plain@example.com, Ann Lee <ann@example.com>, =?utf-8?q?Jos=C3=A9_P=C3=A9rez?= <jose@example.com>, not an address ✓"
+	if got != want {
+		t.Errorf("EncodeAddressList() = %q, want %q", got, want)
+	}
+}
+
+func TestParseMessageIDs(t *testing.T) {
+	got := ParseMessageIDs("<a1@example.com> (first)\r\n <b 2@example.com> <c3@example.com>")
+	if !slices.Equal(got, []string{"a1@example.com", "c3@example.com"}) {
+		t.Errorf("ParseMessageIDs() = %q", got)
+	}
+	if got := ParseMessageIDs("no brackets"); got != nil {
+		t.Errorf("ParseMessageIDs(no brackets) = %q", got)
+	}
+}
+
+func TestFormatMessageIDs(t *testing.T) {
+	got := FormatMessageIDs([]string{"a1@example.com", "bad\r\nBcc: x@example.com", "", "c3@example.com"})
+	if want := "<a1@example.com> <c3@example.com>"; got != want {
+		t.Errorf("FormatMessageIDs() = %q, want %q", got, want)
+	}
+}
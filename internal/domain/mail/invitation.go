@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Invitation methods (RFC 5546) carried by a text/calendar part.
+const (
+	// MethodRequest invites the recipient to an event or updates it.
+	MethodRequest = "REQUEST"
+	// MethodCancel cancels an event.
+	MethodCancel = "CANCEL"
+	// MethodReply is an attendee's answer to an invitation.
+	MethodReply = "REPLY"
+)
+
+// Invitation responses. The values are those of the Calendar API, so they
+// can be passed to it unchanged.
+const (
+	ResponseNeedsAction = "needsAction"
+	ResponseAccepted    = "accepted"
+	ResponseDeclined    = "declined"
+	ResponseTentative   = "tentative"
+)
+
+// Invitation is a calendar event sent by mail, read from the message's
+// text/calendar part.
+type Invitation struct {
+	// Method is the iTIP method, e.g. REQUEST or CANCEL.
+	Method string
+	// UID is the event's iCalendar UID.
+	UID string
+	// RecurrenceID is the start of the one instance of a recurring event
+	// the invitation is about, or zero for the whole event.
+	RecurrenceID time.Time
+	Summary      string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	// Recurring reports whether the event repeats.
+	Recurring bool
+	Organizer Address
+	Attendees []*InvitationAttendee
+	// Response is the account's answer: the one Calendar holds when the
+	// event could be looked up there, or else the one in the invitation.
+	Response string
+}
+
+// InvitationAttendee is a guest listed in an invitation.
+type InvitationAttendee struct {
+	Address  Address
+	Response string
+	Optional bool
+}
+
+// ParseResponse parses an answer to an invitation: accept, decline or
+// tentative, or the matching Calendar API value.
+func ParseResponse(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "accept", "accepted", "yes":
+		return ResponseAccepted, nil
+	case "decline", "declined", "no":
+		return ResponseDeclined, nil
+	case "tentative", "maybe":
+		return ResponseTentative, nil
+	default:
+		return "", fmt.Errorf("invalid response %q: must be accept, decline or tentative", s)
+	}
+}
+
+// IsCancelled reports whether the invitation cancels the event.
+func (i *Invitation) IsCancelled() bool {
+	return strings.EqualFold(i.Method, MethodCancel)
+}
+
+// Attendee returns the attendee with the given address, or nil.
+func (i *Invitation) Attendee(email string) *InvitationAttendee {
+	for _, a := range i.Attendees {
+		if strings.EqualFold(a.Address.Email, email) {
+			return a
+		}
+	}
+	return nil
+}
+
+// ResponseOf returns the answer the invitation records for email, or
+// needsAction when it lists the address without one or not at all.
+func (i *Invitation) ResponseOf(email string) string {
+	if a := i.Attendee(email); a != nil && a.Response != "" {
+		return a.Response
+	}
+	return ResponseNeedsAction
+}
@@ -0,0 +1,41 @@
+package mail
+
+import "testing"
+
+func TestParseResponse(t *testing.T) {
+	for input, want := range map[string]string{
+		"accept":    ResponseAccepted,
+		" Decline ": ResponseDeclined,
+		"tentative": ResponseTentative,
+		"accepted":  ResponseAccepted,
+	} {
+		if got, err := ParseResponse(input); err != nil || got != want {
+			t.Errorf("ParseResponse(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseResponse("later"); err == nil {
+		t.Error("expected an error for an unknown response")
+	}
+}
+
+func TestInvitation_ResponseOf(t *testing.T) {
+	inv := &Invitation{
+		Method: "cancel",
+		Attendees: []*InvitationAttendee{
+			{Address: Address{Email: "Me@Example.com"}, Response: ResponseTentative},
+			{Address: Address{Email: "other@example.com"}},
+		},
+	}
+	if got := inv.ResponseOf("me@example.com"); got != ResponseTentative {
+		t.Errorf("ResponseOf(me) = %q", got)
+	}
+	if got := inv.ResponseOf("other@example.com"); got != ResponseNeedsAction {
+		t.Errorf("ResponseOf(other) = %q", got)
+	}
+	if got := inv.ResponseOf("stranger@example.com"); got != ResponseNeedsAction {
+		t.Errorf("ResponseOf(stranger) = %q", got)
+	}
+	if !inv.IsCancelled() {
+		t.Error("IsCancelled() = false for a CANCEL invitation")
+	}
+}
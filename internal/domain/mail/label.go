@@ -1,5 +1,7 @@
 package mail
 
+import "strings"
+
 // LabelType represents the type of a label.
 const (
 	LabelTypeSystem = "system"
@@ -30,6 +32,13 @@ type Label struct {
 	MessageListVisibility string
 	LabelListVisibility   string
 	Color                 *LabelColor
+
+	// Message and thread counts. Only labels fetched one at a time carry
+	// them; labels from a list have zero counts.
+	MessagesTotal  int
+	MessagesUnread int
+	ThreadsTotal   int
+	ThreadsUnread  int
 }
 
 // NewLabel creates a new user Label with the given ID and name.
@@ -77,3 +86,47 @@ func (l *Label) ClearColor() {
 func (l *Label) HasColor() bool {
 	return l.Color != nil
 }
+
+// SearchLabelTerm returns how name is written in a Gmail search query's
+// label: operator: lower case, with spaces and slashes replaced by dashes.
+func SearchLabelTerm(name string) string {
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(name))
+}
+
+// IsParentOf reports whether other is nested under this label, e.g. Work
+// is the parent of Work/Clients.
+func (l *Label) IsParentOf(other *Label) bool {
+	return strings.HasPrefix(other.Name, l.Name+"/")
+}
+
+// normalizeLabelName folds case and drops the separators people vary
+// between otherwise equal label names. Slashes are kept, since they nest.
+func normalizeLabelName(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "", ".", "").Replace(strings.ToLower(name))
+}
+
+// labelDigits returns the digits in name, in order.
+func labelDigits(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SimilarLabelNames reports whether two label names look like duplicates:
+// equal apart from case and separators, or one typo apart for names of at
+// least five characters. Names whose digits differ, such as 2023 and 2024,
+// are never similar, since numbered labels are usually deliberate.
+func SimilarLabelNames(a, b string) bool {
+	na, nb := normalizeLabelName(a), normalizeLabelName(b)
+	if na == nb {
+		return true
+	}
+	if labelDigits(na) != labelDigits(nb) || min(len([]rune(na)), len([]rune(nb))) < 5 {
+		return false
+	}
+	return editDistance(na, nb) <= 1
+}
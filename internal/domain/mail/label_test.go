@@ -123,3 +123,50 @@ func TestLabelColor(t *testing.T) {
 		t.Errorf("expected Text '#ffffff', got '%s'", color.Text)
 	}
 }
+
+func TestSearchLabelTerm(t *testing.T) {
+	tests := map[string]string{
+		"Muted":             "muted",
+		"Follow up":         "follow-up",
+		"Archive/Work 2026": "archive-work-2026",
+	}
+	for name, want := range tests {
+		if got := SearchLabelTerm(name); got != want {
+			t.Errorf("SearchLabelTerm(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSimilarLabelNames(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Receipts", "receipts", true},
+		{"Work-Projects", "Work Projects", true},
+		{"Newsletters", "Newsleters", true},
+		{"Receipt", "Receipts", true},
+		{"Taxes/2023", "Taxes/2024", false},
+		{"Q1-Plans", "Q2-Plans", false},
+		{"Work", "Word", false},
+		{"Travel", "Family", false},
+		{"Work/Clients", "Clients", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"~"+tt.b, func(t *testing.T) {
+			if got := SimilarLabelNames(tt.a, tt.b); got != tt.want {
+				t.Errorf("SimilarLabelNames(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelIsParentOf(t *testing.T) {
+	parent := NewLabel("1", "Work")
+	if !parent.IsParentOf(NewLabel("2", "Work/Clients")) {
+		t.Error("expected Work to be the parent of Work/Clients")
+	}
+	if parent.IsParentOf(NewLabel("3", "Workshop")) {
+		t.Error("expected Work not to be the parent of Workshop")
+	}
+}
@@ -0,0 +1,67 @@
+package mail
+
+import "strings"
+
+// uncopyableLabels are system labels a message cannot carry into another
+// mailbox: messages.import rejects them.
+var uncopyableLabels = map[string]bool{
+	"DRAFT": true,
+	"CHAT":  true,
+}
+
+// labelWildcard ends a label mapping entry that applies to a label and
+// everything nested under it.
+const labelWildcard = "/*"
+
+// TranslateLabels returns the label names a message copied into another
+// mailbox gets, given the names it has. Each name is looked up in mapping,
+// ignoring case: an exact entry wins, then the longest "Prefix/*" entry,
+// whose target may end in "/*" to keep the rest of the name. A name mapped
+// to "" is dropped and an unmapped name is kept. Labels that cannot be
+// copied, such as DRAFT, are dropped and duplicates are removed.
+func TranslateLabels(names []string, mapping map[string]string) []string {
+	exact := make(map[string]string, len(mapping))
+	for from, to := range mapping {
+		exact[strings.ToLower(from)] = to
+	}
+
+	var result []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		target := translateLabel(name, exact)
+		key := strings.ToLower(target)
+		if target == "" || uncopyableLabels[target] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, target)
+	}
+	return result
+}
+
+// translateLabel maps one label name with mapping, whose keys are lower
+// case.
+func translateLabel(name string, mapping map[string]string) string {
+	lower := strings.ToLower(name)
+	if to, ok := mapping[lower]; ok {
+		return to
+	}
+
+	bestPrefix, target, found := "", "", false
+	for from, to := range mapping {
+		prefix, ok := strings.CutSuffix(from, labelWildcard)
+		if !ok || found && len(prefix) <= len(bestPrefix) {
+			continue
+		}
+		if lower == prefix || strings.HasPrefix(lower, prefix+"/") {
+			bestPrefix, target, found = prefix, to, true
+		}
+	}
+	if !found {
+		return name
+	}
+	if base, ok := strings.CutSuffix(target, labelWildcard); ok {
+		return base + name[len(bestPrefix):]
+	}
+	return target
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTranslateLabels(t *testing.T) {
+	mapping := map[string]string{
+		"Work":           "Archive/Work",
+		"INBOX":          "",
+		"clients/*":      "Archive/Clients/*",
+		"Clients/Acme/*": "Archive/Acme",
+		"Receipts/*":     "Finance",
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{"exact match ignores case", []string{"work"}, []string{"Archive/Work"}},
+		{"empty target drops the label", []string{"INBOX", "UNREAD"}, []string{"UNREAD"}},
+		{"wildcard keeps the rest of the name", []string{"Clients/Globex/2026"}, []string{"Archive/Clients/Globex/2026"}},
+		{"wildcard matches the label itself", []string{"Clients"}, []string{"Archive/Clients"}},
+		{"longest prefix wins", []string{"Clients/Acme/Invoices"}, []string{"Archive/Acme"}},
+		{"plain wildcard target", []string{"Receipts/2026", "Receipts"}, []string{"Finance"}},
+		{"prefix must end at a label boundary", []string{"ClientsOld"}, []string{"ClientsOld"}},
+		{"unmapped names are kept", []string{"Personal", "STARRED"}, []string{"Personal", "STARRED"}},
+		{"uncopyable system labels are dropped", []string{"DRAFT", "CHAT", "SENT"}, []string{"SENT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TranslateLabels(tt.names, mapping); !slices.Equal(got, tt.want) {
+				t.Errorf("TranslateLabels(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+
+	if got := TranslateLabels([]string{"A", "B"}, nil); !slices.Equal(got, []string{"A", "B"}) {
+		t.Errorf("TranslateLabels without a mapping = %v", got)
+	}
+}
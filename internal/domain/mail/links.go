@@ -0,0 +1,232 @@
+package mail
+
+import (
+	"fmt"
+	"html"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxRedirectUnwrap is how many redirect wrappers are removed from one
+// link, for wrappers nested inside each other.
+const maxRedirectUnwrap = 5
+
+// Link is a link found in a message, checked before it is followed.
+type Link struct {
+	// URL is the link as written in the message.
+	URL string
+	// Text is the anchor text of an HTML link, empty for a link in the
+	// plain-text body.
+	Text string
+	// Destination is where the link leads once the redirect wrappers
+	// goog recognizes are removed; it equals URL when there are none.
+	Destination string
+	// Via lists the hosts of the removed redirect wrappers, outermost
+	// first.
+	Via []string
+	// Warnings describe what looks suspicious about the link.
+	Warnings []string
+}
+
+// Flagged reports whether the link has warnings.
+func (l *Link) Flagged() bool {
+	return len(l.Warnings) > 0
+}
+
+var (
+	// anchorPattern matches an HTML anchor with an href, capturing the
+	// href in one of its three quoting styles and the anchor's content.
+	anchorPattern = regexp.MustCompile(`(?is)<a\b[^>]*?\bhref\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))[^>]*>(.*?)</a\s*>`)
+	// textURLPattern matches a web address in plain text.
+	textURLPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"'` + "`" + `]+`)
+	// linkTagPattern matches an HTML tag, for reducing anchor content to
+	// its text.
+	linkTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+	// hostTextPattern matches anchor text that names a web address, such
+	// as "www.example.com" or "example.com/login".
+	hostTextPattern = regexp.MustCompile(`(?i)^(?:https?://)?((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,})(?:[:/?#]\S*)?$`)
+)
+
+// ExtractLinks returns the web links of a message body: the anchors of
+// the HTML body with their text, then the addresses in the plain-text
+// body that the HTML body does not already link to. Links that are not
+// web addresses, such as mailto: and cid:, are left out, but javascript:
+// and data: links are kept and flagged.
+func ExtractLinks(text, htmlBody string) []*Link {
+	var links []*Link
+	seen := make(map[string]bool)
+	for _, m := range anchorPattern.FindAllStringSubmatch(htmlBody, -1) {
+		href := strings.TrimSpace(html.UnescapeString(m[1] + m[2] + m[3]))
+		if !isCheckedLink(href) {
+			continue
+		}
+		anchor := strings.Join(strings.Fields(html.UnescapeString(linkTagPattern.ReplaceAllString(m[4], " "))), " ")
+		if seen[href+"\x00"+anchor] {
+			continue
+		}
+		seen[href+"\x00"+anchor], seen[href] = true, true
+		links = append(links, CheckLink(href, anchor))
+	}
+	for _, raw := range textURLPattern.FindAllString(text, -1) {
+		raw = strings.TrimRight(raw, ".,;:!?)]}>")
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		links = append(links, CheckLink(raw, ""))
+	}
+	return links
+}
+
+// isCheckedLink reports whether href is a link ExtractLinks reports: a web
+// address or a script or data link.
+func isCheckedLink(href string) bool {
+	scheme, _, ok := strings.Cut(strings.ToLower(href), ":")
+	if !ok {
+		return false
+	}
+	switch strings.TrimSpace(scheme) {
+	case "http", "https", "javascript", "vbscript", "data":
+		return true
+	}
+	return false
+}
+
+// CheckLink unwraps the redirects of a link and checks it, with the
+// anchor text it was shown with, if any.
+func CheckLink(rawURL, text string) *Link {
+	link := &Link{URL: rawURL, Text: text, Destination: rawURL}
+
+	scheme, _, _ := strings.Cut(strings.ToLower(rawURL), ":")
+	if scheme = strings.TrimSpace(scheme); scheme != "http" && scheme != "https" {
+		link.Warnings = append(link.Warnings, fmt.Sprintf("%s: link runs code or holds data instead of opening a page", scheme))
+		return link
+	}
+
+	for range maxRedirectUnwrap {
+		u, err := url.Parse(link.Destination)
+		if err != nil {
+			break
+		}
+		target, ok := unwrapRedirect(u)
+		if !ok {
+			break
+		}
+		link.Via = append(link.Via, strings.ToLower(u.Hostname()))
+		link.Destination = target
+	}
+
+	dest, err := url.Parse(link.Destination)
+	if err != nil || dest.Hostname() == "" {
+		link.Warnings = append(link.Warnings, "destination is not a valid web address")
+		return link
+	}
+	host := strings.ToLower(strings.TrimSuffix(dest.Hostname(), "."))
+	if dest.User != nil {
+		link.Warnings = append(link.Warnings, fmt.Sprintf("text before @ hides the real host %s", host))
+	}
+	if net.ParseIP(host) != nil {
+		link.Warnings = append(link.Warnings, "destination is an IP address, not a domain name")
+	}
+	if strings.HasPrefix(host, "xn--") || strings.Contains(host, ".xn--") {
+		link.Warnings = append(link.Warnings, fmt.Sprintf("%s is an international domain name, which can imitate another", host))
+	}
+	if shown := hostInText(text); shown != "" && linkSite(shown) != linkSite(host) {
+		link.Warnings = append(link.Warnings, fmt.Sprintf("text shows %s but the link goes to %s", shown, host))
+	}
+	return link
+}
+
+// redirectParams are the query parameters that commonly carry the target
+// of a redirect.
+var redirectParams = []string{"url", "u", "q", "target", "dest", "destination", "redirect", "redirect_url", "link"}
+
+// unwrapRedirect returns the target of a redirect wrapper: a known link
+// tracker or safety scanner, or any link that carries an absolute web
+// address in a parameter commonly used for redirects.
+func unwrapRedirect(u *url.URL) (string, bool) {
+	host := strings.ToLower(u.Hostname())
+
+	// Proofpoint URL Defense
+	if host == "urldefense.com" || host == "urldefense.proofpoint.com" {
+		if rest, ok := strings.CutPrefix(u.Path, "/v3/__"); ok {
+			target, _, _ := strings.Cut(rest, "__;")
+			return absoluteWebURL(target)
+		}
+		if encoded := u.Query().Get("u"); strings.HasPrefix(u.Path, "/v2/") && encoded != "" {
+			encoded = strings.NewReplacer("-", "%", "_", "/").Replace(encoded)
+			if target, err := url.PathUnescape(encoded); err == nil {
+				return absoluteWebURL(target)
+			}
+		}
+	}
+
+	query := u.Query()
+	for _, param := range redirectParams {
+		// A search query is only a redirect on Google's own /url
+		if param == "q" && u.Path != "/url" {
+			continue
+		}
+		if target, ok := absoluteWebURL(query.Get(param)); ok {
+			return target, true
+		}
+	}
+	return "", false
+}
+
+// absoluteWebURL returns s when it is an absolute http or https address.
+func absoluteWebURL(s string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// commonTLDs are top-level domains that anchor text without a scheme or
+// "www." must end in to be read as a web address, so that file names
+// such as "report.pdf" are not.
+var commonTLDs = map[string]bool{
+	"com": true, "net": true, "org": true, "edu": true, "gov": true, "mil": true,
+	"io": true, "co": true, "info": true, "biz": true, "me": true, "app": true,
+	"dev": true, "ai": true, "us": true, "uk": true, "ca": true, "au": true,
+	"nz": true, "de": true, "fr": true, "nl": true, "be": true, "at": true,
+	"ch": true, "it": true, "es": true, "se": true, "no": true, "pl": true,
+	"eu": true, "ru": true, "cn": true, "jp": true, "in": true, "br": true,
+}
+
+// hostInText returns the host named by anchor text that reads as a web
+// address, or "" for other text.
+func hostInText(text string) string {
+	text = strings.TrimSpace(text)
+	m := hostTextPattern.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	host := strings.ToLower(m[1])
+	lower := strings.ToLower(text)
+	tld := host[strings.LastIndex(host, ".")+1:]
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(host, "www.") && !commonTLDs[tld] {
+		return ""
+	}
+	return host
+}
+
+// linkSite approximates the registered domain of a host: its last two
+// labels, or three under a short second-level domain of a country code
+// such as co.uk or com.au. Subdomains of one site, such as
+// www.example.com and links.example.com, thus compare equal.
+func linkSite(host string) string {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "www."), ".")
+	labels := strings.Split(host, ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 && len(labels[len(labels)-2]) <= 3 {
+		n = 3
+	}
+	if len(labels) <= n {
+		return host
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	htmlBody := `<p>Your <a class="btn" href="https://www.paypal.com.secure-login.ru/signin?x=1&amp;y=2"><b>www.paypal.com</b></a> account.</p>
+<a href='https://example.com/news'>Read the news</a>
+<a href="mailto:help@example.com">help@example.com</a>
+<a href=https://example.com/news>Read the news</a>
+<a href="javascript:alert(1)">Open</a>`
+	text := "Read the news: https://example.com/news.\nUnsubscribe at https://lists.example.org/u?id=3)."
+
+	links := ExtractLinks(text, htmlBody)
+	if len(links) != 4 {
+		for _, l := range links {
+			t.Logf("%+v", l)
+		}
+		t.Fatalf("ExtractLinks() returned %d links, want 4", len(links))
+	}
+
+	phish := links[0]
+	if phish.URL != "https://www.paypal.com.secure-login.ru/signin?x=1&y=2" || phish.Text != "www.paypal.com" {
+		t.Errorf("links[0] = %+v", phish)
+	}
+	if !phish.Flagged() || !strings.Contains(phish.Warnings[0], "text shows www.paypal.com but the link goes to www.paypal.com.secure-login.ru") {
+		t.Errorf("links[0] warnings = %q", phish.Warnings)
+	}
+	if news := links[1]; news.Text != "Read the news" || news.Flagged() {
+		t.Errorf("links[1] = %+v", news)
+	}
+	if script := links[2]; !script.Flagged() || !strings.HasPrefix(script.Warnings[0], "javascript:") {
+		t.Errorf("links[2] = %+v", script)
+	}
+	if plain := links[3]; plain.URL != "https://lists.example.org/u?id=3" || plain.Text != "" {
+		t.Errorf("links[3] = %+v, want the plain-text link without trailing punctuation", plain)
+	}
+}
+
+func TestCheckLink_Redirects(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+		via  string
+	}{
+		{
+			name: "google",
+			url:  "https://www.google.com/url?q=https://example.com/a%3Fb%3D1&sa=D",
+			want: "https://example.com/a?b=1",
+			via:  "www.google.com",
+		},
+		{
+			name: "safe links",
+			url:  "https://eur01.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Fdoc&data=05",
+			want: "https://example.com/doc",
+			via:  "eur01.safelinks.protection.outlook.com",
+		},
+		{
+			name: "proofpoint v2",
+			url:  "https://urldefense.proofpoint.com/v2/url?u=https-3A__example.com_path&d=DwMFaQ",
+			want: "https://example.com/path",
+			via:  "urldefense.proofpoint.com",
+		},
+		{
+			name: "proofpoint v3",
+			url:  "https://urldefense.com/v3/__https://example.com/x__;!!abc$",
+			want: "https://example.com/x",
+			via:  "urldefense.com",
+		},
+		{
+			name: "nested",
+			url:  "https://l.facebook.com/l.php?u=" + "https%3A%2F%2Fclick.example.net%2Fr%3Fredirect%3Dhttps%253A%252F%252Fexample.com%252F",
+			want: "https://example.com/",
+			via:  "l.facebook.com,click.example.net",
+		},
+		{
+			name: "search is not a redirect",
+			url:  "https://search.example.com/find?q=https://example.com",
+			want: "https://search.example.com/find?q=https://example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := CheckLink(tt.url, "")
+			if link.Destination != tt.want || strings.Join(link.Via, ",") != tt.via {
+				t.Errorf("CheckLink() = %q via %q, want %q via %q", link.Destination, link.Via, tt.want, tt.via)
+			}
+		})
+	}
+}
+
+func TestCheckLink_Warnings(t *testing.T) {
+	tests := []struct {
+		url, text, want string
+	}{
+		{url: "https://paypal.com@evil.example/login", want: "hides the real host evil.example"},
+		{url: "http://192.0.2.7/login", want: "IP address"},
+		{url: "https://xn--pypal-4ve.com/", want: "international domain name"},
+		{url: "https://www.google.com/url?q=https://evil.example/", text: "https://accounts.google.com", want: "goes to evil.example"},
+	}
+	for _, tt := range tests {
+		link := CheckLink(tt.url, tt.text)
+		if !strings.Contains(strings.Join(link.Warnings, "; "), tt.want) {
+			t.Errorf("CheckLink(%q) warnings = %q, want %q", tt.url, link.Warnings, tt.want)
+		}
+	}
+
+	for _, ok := range []struct{ url, text string }{
+		{url: "https://links.example.com/c/1", text: "www.example.com"},
+		{url: "https://shop.example.co.uk/", text: "example.co.uk"},
+		{url: "https://drive.example.com/file/1", text: "report.pdf"},
+	} {
+		if link := CheckLink(ok.url, ok.text); link.Flagged() {
+			t.Errorf("CheckLink(%q, %q) warnings = %q, want none", ok.url, ok.text, link.Warnings)
+		}
+	}
+}
@@ -1,24 +1,46 @@
 // Package mail provides domain entities for email operations.
 package mail
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Message represents an email message.
 type Message struct {
-	ID        string
-	ThreadID  string
-	From      string
-	To        []string
-	Cc        []string
-	Bcc       []string
-	Subject   string
-	Body      string
-	BodyHTML  string
-	Labels    []string
-	Date      time.Time
-	IsRead    bool
-	IsStarred bool
-	Snippet   string
+	ID          string
+	ThreadID    string
+	From        Address
+	To          []Address
+	Cc          []Address
+	Bcc         []Address
+	Subject     string
+	Body        string
+	BodyHTML    string
+	Labels      []string
+	Date        time.Time
+	IsRead      bool
+	IsStarred   bool
+	IsImportant bool
+	Snippet     string
+	Attachments []*Attachment
+	Report      *DeliveryReport
+	// Headers are extra header fields written when the message is sent.
+	Headers []Header
+	// MessageID is the RFC 5322 Message-ID of a fetched message, without
+	// the angle brackets.
+	MessageID string
+	// References are the Message-IDs of the earlier messages of the
+	// conversation, oldest first. When a message with References is
+	// sent, the last one is written as In-Reply-To.
+	References []string
+	// Translation is set when the message was translated for display.
+	Translation *Translation
+	// Calendar is the text of the message's text/calendar part, such as
+	// a meeting invitation.
+	Calendar string
+	// Invitation is set when the Calendar part was read for display.
+	Invitation *Invitation
 }
 
 // NewMessage creates a new Message with the given parameters.
@@ -26,30 +48,41 @@ func NewMessage(id, threadID, from, subject, body string) *Message {
 	return &Message{
 		ID:       id,
 		ThreadID: threadID,
-		From:     from,
+		From:     ParseAddress(from),
 		Subject:  subject,
 		Body:     body,
-		To:       []string{},
-		Cc:       []string{},
-		Bcc:      []string{},
+		To:       []Address{},
+		Cc:       []Address{},
+		Bcc:      []Address{},
 		Labels:   []string{},
 		Date:     time.Now(),
 	}
 }
 
-// AddRecipient adds a recipient to the To field.
-func (m *Message) AddRecipient(email string) {
-	m.To = append(m.To, email)
+// AddRecipient adds a recipient, with or without a display name, to the
+// To field.
+func (m *Message) AddRecipient(addr string) {
+	m.To = append(m.To, ParseAddress(addr))
 }
 
 // AddCc adds a recipient to the Cc field.
-func (m *Message) AddCc(email string) {
-	m.Cc = append(m.Cc, email)
+func (m *Message) AddCc(addr string) {
+	m.Cc = append(m.Cc, ParseAddress(addr))
 }
 
 // AddBcc adds a recipient to the Bcc field.
-func (m *Message) AddBcc(email string) {
-	m.Bcc = append(m.Bcc, email)
+func (m *Message) AddBcc(addr string) {
+	m.Bcc = append(m.Bcc, ParseAddress(addr))
+}
+
+// AddAutoBcc blind-copies the message to each address that is not
+// already a recipient and returns the addresses added.
+func (m *Message) AddAutoBcc(addrs []string) []string {
+	added := MissingAddresses(addrs, AddressStrings(m.To), AddressStrings(m.Cc), AddressStrings(m.Bcc))
+	for _, addr := range added {
+		m.AddBcc(addr)
+	}
+	return added
 }
 
 // AddLabel adds a label to the message.
@@ -77,6 +110,32 @@ func (m *Message) HasLabel(label string) bool {
 	return false
 }
 
+// HasAttachments returns true if the message has one or more attachments.
+func (m *Message) HasAttachments() bool {
+	return len(m.Attachments) > 0
+}
+
+// ReplyReferences returns the References of a reply to the message: its
+// own References followed by its Message-ID.
+func (m *Message) ReplyReferences() []string {
+	refs := slices.Clone(m.References)
+	if m.MessageID != "" {
+		refs = append(refs, m.MessageID)
+	}
+	return refs
+}
+
+// IsBounce returns true if the message is a delivery status notification
+// reporting at least one failed recipient.
+func (m *Message) IsBounce() bool {
+	return m.Report != nil && m.Report.IsBounce()
+}
+
+// IsReadReceipt returns true if the message is a read receipt.
+func (m *Message) IsReadReceipt() bool {
+	return m.Report != nil && m.Report.IsReadReceipt()
+}
+
 // MarkAsRead marks the message as read.
 func (m *Message) MarkAsRead() {
 	m.IsRead = true
@@ -96,3 +155,13 @@ func (m *Message) Star() {
 func (m *Message) Unstar() {
 	m.IsStarred = false
 }
+
+// MarkImportant marks the message as important.
+func (m *Message) MarkImportant() {
+	m.IsImportant = true
+}
+
+// MarkNotImportant removes the important marker from the message.
+func (m *Message) MarkNotImportant() {
+	m.IsImportant = false
+}
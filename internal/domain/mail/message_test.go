@@ -14,7 +14,7 @@ func TestNewMessage(t *testing.T) {
 	if msg.ThreadID != "thread-456" {
 		t.Errorf("expected ThreadID 'thread-456', got '%s'", msg.ThreadID)
 	}
-	if msg.From != "sender@example.com" {
+	if msg.From.String() != "sender@example.com" {
 		t.Errorf("expected From 'sender@example.com', got '%s'", msg.From)
 	}
 	if msg.Subject != "Test Subject" {
@@ -48,10 +48,10 @@ func TestMessage_AddRecipient(t *testing.T) {
 	if len(msg.To) != 2 {
 		t.Errorf("expected 2 recipients, got %d", len(msg.To))
 	}
-	if msg.To[0] != "to1@example.com" {
+	if msg.To[0].String() != "to1@example.com" {
 		t.Errorf("expected first recipient 'to1@example.com', got '%s'", msg.To[0])
 	}
-	if msg.To[1] != "to2@example.com" {
+	if msg.To[1].String() != "to2@example.com" {
 		t.Errorf("expected second recipient 'to2@example.com', got '%s'", msg.To[1])
 	}
 }
@@ -63,7 +63,7 @@ func TestMessage_AddCc(t *testing.T) {
 	if len(msg.Cc) != 1 {
 		t.Errorf("expected 1 Cc recipient, got %d", len(msg.Cc))
 	}
-	if msg.Cc[0] != "cc@example.com" {
+	if msg.Cc[0].String() != "cc@example.com" {
 		t.Errorf("expected Cc 'cc@example.com', got '%s'", msg.Cc[0])
 	}
 }
@@ -75,11 +75,28 @@ func TestMessage_AddBcc(t *testing.T) {
 	if len(msg.Bcc) != 1 {
 		t.Errorf("expected 1 Bcc recipient, got %d", len(msg.Bcc))
 	}
-	if msg.Bcc[0] != "bcc@example.com" {
+	if msg.Bcc[0].String() != "bcc@example.com" {
 		t.Errorf("expected Bcc 'bcc@example.com', got '%s'", msg.Bcc[0])
 	}
 }
 
+func TestMessage_AddAutoBcc(t *testing.T) {
+	msg := NewMessage("1", "1", "from@example.com", "Subject", "Body")
+	msg.AddRecipient("Dropbox <DROPBOX@crm.example.com>")
+	msg.AddBcc("archive@example.com")
+
+	added := msg.AddAutoBcc([]string{"dropbox@crm.example.com", "audit@example.com", "Audit@example.com", "archive@example.com"})
+	if len(added) != 1 || added[0] != "audit@example.com" {
+		t.Errorf("added %v, want only audit@example.com", added)
+	}
+	if got := AddressStrings(msg.Bcc); len(got) != 2 || got[1] != "audit@example.com" {
+		t.Errorf("Bcc = %v", got)
+	}
+	if added := msg.AddAutoBcc(nil); added != nil {
+		t.Errorf("added %v with no addresses", added)
+	}
+}
+
 func TestMessage_Labels(t *testing.T) {
 	msg := NewMessage("1", "1", "from@example.com", "Subject", "Body")
 
@@ -151,6 +168,24 @@ func TestMessage_StarStatus(t *testing.T) {
 	}
 }
 
+func TestMessage_ImportantStatus(t *testing.T) {
+	msg := NewMessage("1", "1", "from@example.com", "Subject", "Body")
+
+	if msg.IsImportant {
+		t.Error("expected new message not to be important")
+	}
+
+	msg.MarkImportant()
+	if !msg.IsImportant {
+		t.Error("expected message to be important after MarkImportant")
+	}
+
+	msg.MarkNotImportant()
+	if msg.IsImportant {
+		t.Error("expected message not to be important after MarkNotImportant")
+	}
+}
+
 func TestMessage_DateIsSet(t *testing.T) {
 	before := time.Now()
 	msg := NewMessage("1", "1", "from@example.com", "Subject", "Body")
@@ -160,3 +195,30 @@ func TestMessage_DateIsSet(t *testing.T) {
 		t.Error("expected Date to be set to current time")
 	}
 }
+
+func TestMessage_HasAttachments(t *testing.T) {
+	msg := NewMessage("1", "1", "from@example.com", "Subject", "Body")
+
+	if msg.HasAttachments() {
+		t.Error("expected new message to have no attachments")
+	}
+
+	msg.Attachments = append(msg.Attachments, NewAttachment("att1", "report.pdf", "application/pdf"))
+	if !msg.HasAttachments() {
+		t.Error("expected message to have attachments")
+	}
+}
+
+func TestMessage_ReplyReferences(t *testing.T) {
+	msg := &Message{MessageID: "c3@example.com", References: []string{"a1@example.com", "b2@example.com"}}
+	got := msg.ReplyReferences()
+	if want := []string{"a1@example.com", "b2@example.com", "c3@example.com"}; len(got) != 3 || got[0] != want[0] || got[2] != want[2] {
+		t.Errorf("ReplyReferences() = %q, want %q", got, want)
+	}
+	if len(msg.References) != 2 {
+		t.Errorf("ReplyReferences changed the message's References: %q", msg.References)
+	}
+	if got := (&Message{}).ReplyReferences(); len(got) != 0 {
+		t.Errorf("ReplyReferences() without a Message-ID = %q", got)
+	}
+}
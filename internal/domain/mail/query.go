@@ -0,0 +1,82 @@
+package mail
+
+import (
+	"strings"
+	"unicode"
+)
+
+// QueryTerms returns the words and quoted phrases of a Gmail search query
+// that match message text, for highlighting them in results. Operators
+// such as from: and is: are dropped, except subject:, whose value is text;
+// so are negated terms and the OR, AND and AROUND keywords. Terms are
+// returned once each, in query order.
+func QueryTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		terms = append(terms, term)
+	}
+
+	for _, token := range splitQuery(query) {
+		if strings.HasPrefix(token, "-") {
+			continue
+		}
+		token = strings.TrimPrefix(token, "+")
+		if op, value, ok := strings.Cut(token, ":"); ok && !strings.HasPrefix(token, `"`) {
+			if strings.EqualFold(op, "subject") {
+				for _, term := range splitQuery(strings.Trim(value, "(){}")) {
+					if !strings.HasPrefix(term, "-") {
+						add(unquote(term))
+					}
+				}
+			}
+			continue
+		}
+		switch token {
+		case "OR", "AND", "AROUND", "|":
+			continue
+		}
+		add(unquote(token))
+	}
+	return terms
+}
+
+// splitQuery splits a query at spaces outside double quotes, dropping the
+// grouping characters ( ) { } around terms.
+func splitQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case quoted:
+			current.WriteRune(r)
+		case unicode.IsSpace(r), r == '(', r == ')', r == '{', r == '}':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// unquote removes the double quotes around a phrase.
+func unquote(term string) string {
+	return strings.Trim(term, `"`)
+}
@@ -0,0 +1,28 @@
+package mail
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"invoice", []string{"invoice"}},
+		{"invoice from:billing@example.com is:unread", []string{"invoice"}},
+		{`"quarterly report" budget`, []string{"quarterly report", "budget"}},
+		{`subject:meeting subject:"team sync"`, []string{"meeting", "team sync"}},
+		{"invoice -draft OR receipt", []string{"invoice", "receipt"}},
+		{"(invoice OR receipt) Invoice +paid", []string{"invoice", "receipt", "paid"}},
+		{"has:attachment after:2024/01/01", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := QueryTerms(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("QueryTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quote styles place the quoted original of a reply.
+const (
+	// QuoteNone sends the reply without the original.
+	QuoteNone = "none"
+	// QuoteTop puts the reply above the quoted original.
+	QuoteTop = "top"
+	// QuoteBottom puts the reply below the quoted original.
+	QuoteBottom = "bottom"
+)
+
+// DefaultQuotePrefix starts each quoted line when no prefix is set.
+const DefaultQuotePrefix = "> "
+
+// attributionDateLayout formats the date of the attribution line, as
+// mail clients commonly do.
+const attributionDateLayout = "Mon, Jan 2, 2006 at 3:04 PM"
+
+// ParseQuoteStyle validates a quote style; empty means QuoteNone.
+func ParseQuoteStyle(s string) (string, error) {
+	switch style := strings.ToLower(strings.TrimSpace(s)); style {
+	case "":
+		return QuoteNone, nil
+	case QuoteNone, QuoteTop, QuoteBottom:
+		return style, nil
+	default:
+		return "", fmt.Errorf("invalid quote style %q: must be top, bottom or none", s)
+	}
+}
+
+// QuoteReply returns the body of a reply to original: the reply text and
+// originalText, the original's body as plain text, quoted under an
+// attribution line such as "On Mon, Mar 2, 2026 at 9:00 AM, Ana
+// <ana@example.com> wrote:". Every quoted line starts with prefix, or
+// DefaultQuotePrefix when it is empty; blank lines get the prefix without
+// trailing spaces. With QuoteNone the reply is returned unchanged.
+func QuoteReply(reply, originalText string, original *Message, style, prefix string) string {
+	if style == QuoteNone || style == "" || original == nil {
+		return reply
+	}
+	if prefix == "" {
+		prefix = DefaultQuotePrefix
+	}
+
+	attribution := original.From.String() + " wrote:"
+	if !original.Date.IsZero() {
+		attribution = "On " + original.Date.Format(attributionDateLayout) + ", " + attribution
+	}
+
+	text := strings.TrimRight(strings.ReplaceAll(originalText, "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	var quoted strings.Builder
+	quoted.WriteString(attribution)
+	for _, line := range lines {
+		quoted.WriteString("\n")
+		if line == "" {
+			quoted.WriteString(strings.TrimRight(prefix, " \t"))
+		} else {
+			quoted.WriteString(prefix + line)
+		}
+	}
+
+	reply = strings.TrimRight(reply, "\n")
+	if style == QuoteBottom {
+		return quoted.String() + "\n\n" + reply + "\n"
+	}
+	return reply + "\n\n" + quoted.String() + "\n"
+}
@@ -0,0 +1,61 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuoteReply(t *testing.T) {
+	original := &Message{
+		From: Address{Name: "Ana", Email: "ana@example.com"},
+		Date: time.Date(2026, 3, 2, 9, 5, 0, 0, time.UTC),
+	}
+	text := "Can we meet?\r\n\r\nThursday works.\r\n"
+
+	tests := []struct {
+		name   string
+		style  string
+		prefix string
+		want   string
+	}{
+		{
+			name:  "top",
+			style: QuoteTop,
+			want:  "Yes.\n\nOn Mon, Mar 2, 2026 at 9:05 AM, Ana <ana@example.com> wrote:\n> Can we meet?\n>\n> Thursday works.\n",
+		},
+		{
+			name:   "bottom with prefix",
+			style:  QuoteBottom,
+			prefix: "| ",
+			want:   "On Mon, Mar 2, 2026 at 9:05 AM, Ana <ana@example.com> wrote:\n| Can we meet?\n|\n| Thursday works.\n\nYes.\n",
+		},
+		{
+			name:  "none",
+			style: QuoteNone,
+			want:  "Yes.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteReply("Yes.\n", text, original, tt.style, tt.prefix); got != tt.want {
+				t.Errorf("QuoteReply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	undated := &Message{From: Address{Email: "bo@example.com"}}
+	if got, want := QuoteReply("Ok", "Hi", undated, QuoteTop, ""), "Ok\n\nbo@example.com wrote:\n> Hi\n"; got != want {
+		t.Errorf("QuoteReply() = %q, want %q", got, want)
+	}
+}
+
+func TestParseQuoteStyle(t *testing.T) {
+	for in, want := range map[string]string{"": QuoteNone, " Top ": QuoteTop, "bottom": QuoteBottom, "none": QuoteNone} {
+		if got, err := ParseQuoteStyle(in); err != nil || got != want {
+			t.Errorf("ParseQuoteStyle(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseQuoteStyle("inline"); err == nil {
+		t.Error("expected an error for inline")
+	}
+}
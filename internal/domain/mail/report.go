@@ -0,0 +1,207 @@
+package mail
+
+import (
+	"strings"
+)
+
+// ReportType identifies the kind of machine-readable report carried by a
+// multipart/report message.
+type ReportType string
+
+// Supported report types.
+const (
+	// ReportDeliveryStatus is a delivery status notification (DSN, RFC 3464).
+	ReportDeliveryStatus ReportType = "delivery-status"
+	// ReportDisposition is a message disposition notification (MDN, RFC 8098).
+	ReportDisposition ReportType = "disposition-notification"
+)
+
+// Delivery status actions reported per recipient in a DSN.
+const (
+	ActionFailed    = "failed"
+	ActionDelayed   = "delayed"
+	ActionDelivered = "delivered"
+	ActionRelayed   = "relayed"
+	ActionExpanded  = "expanded"
+)
+
+// DeliveryReport holds the parsed content of a DSN or MDN.
+type DeliveryReport struct {
+	// Type is the kind of report.
+	Type ReportType
+	// ReportingMTA is the mail server that generated a DSN.
+	ReportingMTA string
+	// Recipients holds the per-recipient delivery status of a DSN.
+	Recipients []*RecipientStatus
+	// FinalRecipient is the recipient that generated an MDN.
+	FinalRecipient string
+	// OriginalMessageID is the Message-ID of the message the MDN refers to.
+	OriginalMessageID string
+	// Disposition is the raw disposition field of an MDN,
+	// e.g. "manual-action/MDN-sent-manually; displayed".
+	Disposition string
+}
+
+// RecipientStatus is the delivery status of one recipient in a DSN.
+type RecipientStatus struct {
+	// Recipient is the final recipient address.
+	Recipient string
+	// Action is the delivery action, e.g. "failed" or "delayed".
+	Action string
+	// Status is the enhanced status code, e.g. "5.1.1".
+	Status string
+	// DiagnosticCode is the remote server's diagnostic, if provided.
+	DiagnosticCode string
+	// RemoteMTA is the server that reported the status, if provided.
+	RemoteMTA string
+}
+
+// IsBounce returns true if the report is a DSN with at least one failed recipient.
+func (r *DeliveryReport) IsBounce() bool {
+	return len(r.FailedRecipients()) > 0
+}
+
+// IsReadReceipt returns true if the report is an MDN indicating the message was displayed.
+func (r *DeliveryReport) IsReadReceipt() bool {
+	return r.Type == ReportDisposition && r.DispositionType() == "displayed"
+}
+
+// FailedRecipients returns the recipient statuses of a DSN whose action is failed.
+func (r *DeliveryReport) FailedRecipients() []*RecipientStatus {
+	if r.Type != ReportDeliveryStatus {
+		return nil
+	}
+	var failed []*RecipientStatus
+	for _, rs := range r.Recipients {
+		if rs != nil && rs.Action == ActionFailed {
+			failed = append(failed, rs)
+		}
+	}
+	return failed
+}
+
+// DispositionType returns the disposition type of an MDN, e.g. "displayed"
+// or "deleted". It returns an empty string if there is no disposition.
+func (r *DeliveryReport) DispositionType() string {
+	_, dispType, found := strings.Cut(r.Disposition, ";")
+	if !found {
+		return ""
+	}
+	// Strip any modifiers such as "displayed/error"
+	dispType, _, _ = strings.Cut(strings.TrimSpace(dispType), "/")
+	return strings.ToLower(strings.TrimSpace(dispType))
+}
+
+// IsPermanent returns true if the status code indicates a permanent failure (5.x.x).
+func (rs *RecipientStatus) IsPermanent() bool {
+	return strings.HasPrefix(rs.Status, "5.")
+}
+
+// ParseDeliveryReport parses the body of a message/delivery-status or
+// message/disposition-notification part. It returns nil if the body contains
+// no recognizable fields.
+func ParseDeliveryReport(reportType ReportType, body string) *DeliveryReport {
+	groups := parseFieldGroups(body)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	report := &DeliveryReport{Type: reportType}
+
+	switch reportType {
+	case ReportDeliveryStatus:
+		// The first group holds per-message fields, the rest per-recipient fields.
+		report.ReportingMTA = stripTypePrefix(groups[0]["reporting-mta"])
+		for _, fields := range groups {
+			recipient := stripTypePrefix(fields["final-recipient"])
+			if recipient == "" {
+				recipient = stripTypePrefix(fields["original-recipient"])
+			}
+			if recipient == "" {
+				continue
+			}
+			report.Recipients = append(report.Recipients, &RecipientStatus{
+				Recipient:      recipient,
+				Action:         strings.ToLower(fields["action"]),
+				Status:         firstWord(fields["status"]),
+				DiagnosticCode: stripTypePrefix(fields["diagnostic-code"]),
+				RemoteMTA:      stripTypePrefix(fields["remote-mta"]),
+			})
+		}
+		if len(report.Recipients) == 0 {
+			return nil
+		}
+	case ReportDisposition:
+		fields := groups[0]
+		report.FinalRecipient = stripTypePrefix(fields["final-recipient"])
+		report.OriginalMessageID = strings.Trim(fields["original-message-id"], "<> ")
+		report.Disposition = fields["disposition"]
+		if report.Disposition == "" {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return report
+}
+
+// parseFieldGroups parses blocks of "Name: value" fields separated by blank lines.
+// Field names are lowercased and folded continuation lines are joined.
+func parseFieldGroups(body string) []map[string]string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	var groups []map[string]string
+	var current map[string]string
+	var lastKey string
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				groups = append(groups, current)
+			}
+			current = nil
+			lastKey = ""
+			continue
+		}
+
+		// Continuation of a folded field
+		if (line[0] == ' ' || line[0] == '\t') && lastKey != "" {
+			current[lastKey] += " " + strings.TrimSpace(line)
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+		lastKey = strings.ToLower(strings.TrimSpace(name))
+		current[lastKey] = strings.TrimSpace(value)
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	return groups
+}
+
+// stripTypePrefix removes an address or diagnostic type prefix such as
+// "rfc822;" or "smtp;" from a field value.
+func stripTypePrefix(value string) string {
+	if _, rest, found := strings.Cut(value, ";"); found {
+		return strings.TrimSpace(rest)
+	}
+	return strings.TrimSpace(value)
+}
+
+// firstWord returns the first whitespace-separated word of s.
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,152 @@
+package mail
+
+import "testing"
+
+const sampleDSN = "Reporting-MTA: dns; googlemail.com\r\n" +
+	"Arrival-Date: Mon, 1 Apr 2024 10:00:00 -0700\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; gone@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Remote-MTA: dns; mx.example.com. (192.0.2.1, the server for the domain example.com.)\r\n" +
+	"Diagnostic-Code: smtp; 550-5.1.1 The email account that you tried to reach\r\n" +
+	"    does not exist.\r\n" +
+	"Last-Attempt-Date: Mon, 1 Apr 2024 10:00:01 -0700\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; slow@example.org\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.4.1 (temporary failure)\r\n"
+
+const sampleMDN = "Reporting-UA: mail.example.com; Mailer\r\n" +
+	"Final-Recipient: rfc822; reader@example.com\r\n" +
+	"Original-Message-ID: <abc123@mail.example.com>\r\n" +
+	"Disposition: manual-action/MDN-sent-manually; displayed\r\n"
+
+func TestParseDeliveryReport_DSN(t *testing.T) {
+	report := ParseDeliveryReport(ReportDeliveryStatus, sampleDSN)
+	if report == nil {
+		t.Fatal("expected report, got nil")
+	}
+
+	if report.ReportingMTA != "googlemail.com" {
+		t.Errorf("ReportingMTA = %q, want googlemail.com", report.ReportingMTA)
+	}
+	if len(report.Recipients) != 2 {
+		t.Fatalf("expected 2 recipients, got %d", len(report.Recipients))
+	}
+
+	failed := report.Recipients[0]
+	if failed.Recipient != "gone@example.com" {
+		t.Errorf("Recipient = %q, want gone@example.com", failed.Recipient)
+	}
+	if failed.Action != ActionFailed || failed.Status != "5.1.1" || !failed.IsPermanent() {
+		t.Errorf("unexpected failed status: %+v", failed)
+	}
+	if failed.DiagnosticCode != "550-5.1.1 The email account that you tried to reach does not exist." {
+		t.Errorf("DiagnosticCode = %q", failed.DiagnosticCode)
+	}
+	if failed.RemoteMTA == "" {
+		t.Error("expected RemoteMTA to be set")
+	}
+
+	delayed := report.Recipients[1]
+	if delayed.Action != ActionDelayed || delayed.Status != "4.4.1" || delayed.IsPermanent() {
+		t.Errorf("unexpected delayed status: %+v", delayed)
+	}
+
+	if !report.IsBounce() {
+		t.Error("expected IsBounce to be true")
+	}
+	if report.IsReadReceipt() {
+		t.Error("expected IsReadReceipt to be false for a DSN")
+	}
+	if got := report.FailedRecipients(); len(got) != 1 || got[0].Recipient != "gone@example.com" {
+		t.Errorf("FailedRecipients() = %+v, want gone@example.com only", got)
+	}
+}
+
+func TestParseDeliveryReport_DelayedOnlyIsNotBounce(t *testing.T) {
+	body := "Reporting-MTA: dns; mx.example.com\n\nFinal-Recipient: rfc822; slow@example.org\nAction: delayed\nStatus: 4.4.1\n"
+	report := ParseDeliveryReport(ReportDeliveryStatus, body)
+	if report == nil {
+		t.Fatal("expected report, got nil")
+	}
+	if report.IsBounce() {
+		t.Error("expected delayed-only DSN not to be a bounce")
+	}
+}
+
+func TestParseDeliveryReport_MDN(t *testing.T) {
+	report := ParseDeliveryReport(ReportDisposition, sampleMDN)
+	if report == nil {
+		t.Fatal("expected report, got nil")
+	}
+
+	if report.FinalRecipient != "reader@example.com" {
+		t.Errorf("FinalRecipient = %q, want reader@example.com", report.FinalRecipient)
+	}
+	if report.OriginalMessageID != "abc123@mail.example.com" {
+		t.Errorf("OriginalMessageID = %q", report.OriginalMessageID)
+	}
+	if report.DispositionType() != "displayed" {
+		t.Errorf("DispositionType() = %q, want displayed", report.DispositionType())
+	}
+	if !report.IsReadReceipt() {
+		t.Error("expected IsReadReceipt to be true")
+	}
+	if report.IsBounce() {
+		t.Error("expected IsBounce to be false for an MDN")
+	}
+}
+
+func TestParseDeliveryReport_MDNDeleted(t *testing.T) {
+	body := "Final-Recipient: rfc822; reader@example.com\nDisposition: automatic-action/MDN-sent-automatically; deleted/error\n"
+	report := ParseDeliveryReport(ReportDisposition, body)
+	if report == nil {
+		t.Fatal("expected report, got nil")
+	}
+	if report.DispositionType() != "deleted" {
+		t.Errorf("DispositionType() = %q, want deleted", report.DispositionType())
+	}
+	if report.IsReadReceipt() {
+		t.Error("expected deleted disposition not to be a read receipt")
+	}
+}
+
+func TestParseDeliveryReport_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		reportType ReportType
+		body       string
+	}{
+		{"empty body", ReportDeliveryStatus, ""},
+		{"no recipients", ReportDeliveryStatus, "Reporting-MTA: dns; mx.example.com\n"},
+		{"no disposition", ReportDisposition, "Final-Recipient: rfc822; a@example.com\n"},
+		{"unknown type", ReportType("other"), sampleDSN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseDeliveryReport(tt.reportType, tt.body); got != nil {
+				t.Errorf("expected nil report, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestMessage_ReportHelpers(t *testing.T) {
+	msg := NewMessage("1", "1", "mailer-daemon@googlemail.com", "Delivery Status Notification (Failure)", "")
+	if msg.IsBounce() || msg.IsReadReceipt() {
+		t.Error("expected message without report to be neither bounce nor receipt")
+	}
+
+	msg.Report = ParseDeliveryReport(ReportDeliveryStatus, sampleDSN)
+	if !msg.IsBounce() {
+		t.Error("expected message to be a bounce")
+	}
+
+	msg.Report = ParseDeliveryReport(ReportDisposition, sampleMDN)
+	if !msg.IsReadReceipt() {
+		t.Error("expected message to be a read receipt")
+	}
+}
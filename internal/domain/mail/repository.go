@@ -7,19 +7,29 @@ import (
 
 // Domain errors for mail operations.
 var (
-	ErrMessageNotFound = errors.New("message not found")
-	ErrDraftNotFound   = errors.New("draft not found")
-	ErrThreadNotFound  = errors.New("thread not found")
-	ErrLabelNotFound   = errors.New("label not found")
-	ErrFilterNotFound  = errors.New("filter not found")
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrDraftNotFound      = errors.New("draft not found")
+	ErrThreadNotFound     = errors.New("thread not found")
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrFilterNotFound     = errors.New("filter not found")
+	ErrAttachmentNotFound = errors.New("attachment not found")
 )
 
+// ErrStopSearch is returned by a SearchEach callback to end the search
+// early. SearchEach then returns without an error.
+var ErrStopSearch = errors.New("stop search")
+
 // ListOptions contains common options for list operations.
 type ListOptions struct {
 	MaxResults int
 	PageToken  string
 	Query      string
 	LabelIDs   []string
+	// IDsOnly makes List return messages with only their IDs set, without
+	// fetching each one.
+	IDsOnly bool
+	// IncludeSpamTrash also lists items in SPAM and TRASH.
+	IncludeSpamTrash bool
 }
 
 // ListResult contains the result of a list operation with pagination.
@@ -27,6 +37,24 @@ type ListResult[T any] struct {
 	Items         []T
 	NextPageToken string
 	Total         int
+	// Warnings describes items that could not be fetched in full and are
+	// included with partial data, such as only their IDs.
+	Warnings []string
+}
+
+// Incomplete reports whether some items are included with partial data.
+func (r *ListResult[T]) Incomplete() bool {
+	return len(r.Warnings) > 0
+}
+
+// MailboxChanges lists the messages added to, deleted from or relabeled in
+// a mailbox since a history ID.
+type MailboxChanges struct {
+	// HistoryID is the mailbox's history ID after the changes.
+	HistoryID uint64
+	Added     []string
+	Deleted   []string
+	Relabeled []string
 }
 
 // ModifyRequest contains labels to add and remove from a message or thread.
@@ -58,6 +86,16 @@ type MessageRepository interface {
 	// Send sends a new message.
 	Send(ctx context.Context, msg *Message) (*Message, error)
 
+	// GetRaw retrieves the original RFC 2822 content of a message.
+	GetRaw(ctx context.Context, id string) ([]byte, error)
+
+	// SendRaw sends a message from its RFC 2822 content.
+	SendRaw(ctx context.Context, raw []byte) (*Message, error)
+
+	// Import adds a message from its RFC 2822 content to the mailbox, as if
+	// it had been received, with the given label IDs.
+	Import(ctx context.Context, raw []byte, labelIDs []string) (*Message, error)
+
 	// Reply sends a reply to an existing message.
 	Reply(ctx context.Context, messageID string, reply *Message) (*Message, error)
 
@@ -79,8 +117,19 @@ type MessageRepository interface {
 	// Modify modifies the labels on a message.
 	Modify(ctx context.Context, id string, req ModifyRequest) (*Message, error)
 
+	// BatchModify applies the same label changes to many messages at once.
+	BatchModify(ctx context.Context, ids []string, req ModifyRequest) error
+
 	// Search searches for messages matching the query.
 	Search(ctx context.Context, query string, opts ListOptions) (*ListResult[*Message], error)
+
+	// SearchEach calls fn with each message matching the query as soon as
+	// it has been fetched, following pages of opts.MaxResults messages
+	// until the results run out or fn returns an error. A message that
+	// cannot be fetched is passed with only its IDs. It returns the
+	// estimated total number of matches and, like ListResult.Warnings, a
+	// warning for each message passed with partial data.
+	SearchEach(ctx context.Context, query string, opts ListOptions, fn func(*Message) error) (int, []string, error)
 }
 
 // DraftRepository defines operations for managing email drafts.
@@ -125,6 +174,12 @@ type ThreadRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// AttachmentRepository defines operations for retrieving message attachments.
+type AttachmentRepository interface {
+	// Get retrieves the content of an attachment on a message.
+	Get(ctx context.Context, messageID, attachmentID string) ([]byte, error)
+}
+
 // LabelRepository defines operations for managing email labels.
 type LabelRepository interface {
 	// List retrieves all labels.
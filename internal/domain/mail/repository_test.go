@@ -13,6 +13,7 @@ func TestDomainErrors(t *testing.T) {
 		ErrThreadNotFound,
 		ErrLabelNotFound,
 		ErrFilterNotFound,
+		ErrAttachmentNotFound,
 	}
 
 	for i, err1 := range domainErrors {
@@ -0,0 +1,248 @@
+package mail
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RuleField names the part of a message a rule condition looks at.
+type RuleField string
+
+// Fields a rule condition can test.
+const (
+	RuleFieldFrom    RuleField = "from"
+	RuleFieldTo      RuleField = "to"
+	RuleFieldCc      RuleField = "cc"
+	RuleFieldSubject RuleField = "subject"
+	RuleFieldBody    RuleField = "body"
+	RuleFieldSnippet RuleField = "snippet"
+	RuleFieldLabel   RuleField = "label"
+)
+
+// RuleFieldAccountPrefix starts the field of a condition on the metadata
+// of the account the rules run for, e.g. "account.role".
+const RuleFieldAccountPrefix = "account."
+
+// AccountKey returns the metadata key of an account field, such as "role"
+// for "account.role".
+func (f RuleField) AccountKey() (string, bool) {
+	key, ok := strings.CutPrefix(string(f), RuleFieldAccountPrefix)
+	return key, ok && key != ""
+}
+
+// RuleOperator is how a rule condition compares a field with its value.
+type RuleOperator string
+
+// Operators a rule condition can use. Contains and equals ignore case;
+// matches uses the regular expression as written.
+const (
+	RuleOpContains RuleOperator = "contains"
+	RuleOpEquals   RuleOperator = "equals"
+	RuleOpMatches  RuleOperator = "matches"
+)
+
+// RuleActionType is what a rule does to a matching message.
+type RuleActionType string
+
+// Actions a rule can take.
+const (
+	// RuleActionLabel applies the label named by the action value.
+	RuleActionLabel RuleActionType = "label"
+	// RuleActionArchive removes the message from the inbox.
+	RuleActionArchive RuleActionType = "archive"
+	// RuleActionForward forwards the message to the address in the value.
+	RuleActionForward RuleActionType = "forward"
+	// RuleActionNotify shows a desktop notification with the value as text.
+	RuleActionNotify RuleActionType = "notify"
+	// RuleActionExec runs the value as a shell command.
+	RuleActionExec RuleActionType = "exec"
+)
+
+// RuleCondition tests one field of a message.
+type RuleCondition struct {
+	Field    RuleField
+	Operator RuleOperator
+	Value    string
+	// Negate inverts the result of the test.
+	Negate bool
+
+	pattern *regexp.Regexp
+}
+
+// RuleAction is one step taken for a message that matches a rule.
+type RuleAction struct {
+	Type  RuleActionType
+	Value string
+}
+
+// Rule is a local mail automation rule: when its conditions match a
+// message, its actions run. Unlike a Filter it is evaluated by goog, not
+// by Gmail.
+type Rule struct {
+	Name string
+	// MatchAny makes the rule match when any condition holds instead of all.
+	MatchAny   bool
+	Conditions []*RuleCondition
+	Actions    []*RuleAction
+	// Stop skips the remaining rules for a message this rule matched.
+	Stop bool
+}
+
+// Validate checks that the rule is complete and compiles the patterns of
+// its matches conditions.
+func (r *Rule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rule has no name")
+	}
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("rule %q has no conditions", r.Name)
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("rule %q has no actions", r.Name)
+	}
+	for _, c := range r.Conditions {
+		if err := c.compile(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	for _, a := range r.Actions {
+		switch a.Type {
+		case RuleActionArchive:
+		case RuleActionLabel, RuleActionForward, RuleActionNotify, RuleActionExec:
+			if strings.TrimSpace(a.Value) == "" {
+				return fmt.Errorf("rule %q: %s action needs a value", r.Name, a.Type)
+			}
+		default:
+			return fmt.Errorf("rule %q: unknown action %q", r.Name, a.Type)
+		}
+	}
+	return nil
+}
+
+// compile checks the condition and prepares its regular expression.
+func (c *RuleCondition) compile() error {
+	switch c.Field {
+	case RuleFieldFrom, RuleFieldTo, RuleFieldCc, RuleFieldSubject, RuleFieldBody, RuleFieldSnippet, RuleFieldLabel:
+	default:
+		if _, ok := c.Field.AccountKey(); !ok {
+			return fmt.Errorf("unknown condition field %q", c.Field)
+		}
+	}
+	switch c.Operator {
+	case RuleOpContains, RuleOpEquals:
+	case RuleOpMatches:
+		pattern, err := regexp.Compile(c.Value)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for %s: %w", c.Field, err)
+		}
+		c.pattern = pattern
+	default:
+		return fmt.Errorf("unknown operator %q for %s", c.Operator, c.Field)
+	}
+	return nil
+}
+
+// Matches reports whether msg, in the account with the given metadata,
+// satisfies the rule's conditions. Label conditions compare against
+// msg.Labels, which may hold label names as well as IDs.
+func (r *Rule) Matches(msg *Message, account map[string]string) bool {
+	if msg == nil || len(r.Conditions) == 0 {
+		return false
+	}
+	for _, c := range r.Conditions {
+		if c.Matches(msg, account) == r.MatchAny {
+			return r.MatchAny
+		}
+	}
+	return !r.MatchAny
+}
+
+// Matches reports whether msg, in the account with the given metadata,
+// satisfies the condition. List fields such as to and label match when
+// any of their values does; an account field the account has no value
+// for is tested as empty.
+func (c *RuleCondition) Matches(msg *Message, account map[string]string) bool {
+	var values []string
+	switch c.Field {
+	case RuleFieldFrom:
+		values = []string{msg.From.String()}
+	case RuleFieldTo:
+		values = AddressStrings(msg.To)
+	case RuleFieldCc:
+		values = AddressStrings(msg.Cc)
+	case RuleFieldSubject:
+		values = []string{msg.Subject}
+	case RuleFieldBody:
+		values = []string{msg.Body, msg.BodyHTML}
+	case RuleFieldSnippet:
+		values = []string{msg.Snippet}
+	case RuleFieldLabel:
+		values = msg.Labels
+	default:
+		if key, ok := c.Field.AccountKey(); ok {
+			values = []string{account[key]}
+		}
+	}
+
+	matched := false
+	for _, v := range values {
+		if c.test(v) {
+			matched = true
+			break
+		}
+	}
+	return matched != c.Negate
+}
+
+// test applies the condition's operator to one value.
+func (c *RuleCondition) test(v string) bool {
+	switch c.Operator {
+	case RuleOpContains:
+		return strings.Contains(strings.ToLower(v), strings.ToLower(c.Value))
+	case RuleOpEquals:
+		return strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(c.Value))
+	case RuleOpMatches:
+		if c.pattern == nil {
+			if c.compile() != nil {
+				return false
+			}
+		}
+		return c.pattern.MatchString(v)
+	}
+	return false
+}
+
+// String describes the condition, e.g. `subject contains "invoice"`.
+func (c *RuleCondition) String() string {
+	s := fmt.Sprintf("%s %s %q", c.Field, c.Operator, c.Value)
+	if c.Negate {
+		return "not " + s
+	}
+	return s
+}
+
+// String describes the action, e.g. "label Receipts" or "archive".
+func (a *RuleAction) String() string {
+	if a.Value == "" {
+		return string(a.Type)
+	}
+	return string(a.Type) + " " + a.Value
+}
+
+// MatchingRules returns the rules that match msg in the account with the
+// given metadata, in order, up to and including the first matching rule
+// with Stop set.
+func MatchingRules(rules []*Rule, msg *Message, account map[string]string) []*Rule {
+	var matched []*Rule
+	for _, r := range rules {
+		if !r.Matches(msg, account) {
+			continue
+		}
+		matched = append(matched, r)
+		if r.Stop {
+			break
+		}
+	}
+	return matched
+}
@@ -0,0 +1,145 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+func ruleTestMessage() *Message {
+	return &Message{
+		ID:      "m1",
+		From:    Address{Name: "Shop", Email: "orders@shop.example.com"},
+		To:      []Address{{Email: "me@example.com"}, {Email: "team@example.com"}},
+		Subject: "Your order #1234 has shipped",
+		Body:    "Tracking number: ZX99",
+		Labels:  []string{"INBOX", "Label_7", "Receipts"},
+	}
+}
+
+func TestRuleCondition_Matches(t *testing.T) {
+	msg := ruleTestMessage()
+	tests := []struct {
+		name string
+		cond RuleCondition
+		want bool
+	}{
+		{"from contains ignores case", RuleCondition{Field: RuleFieldFrom, Operator: RuleOpContains, Value: "@SHOP.example.com"}, true},
+		{"to matches any recipient", RuleCondition{Field: RuleFieldTo, Operator: RuleOpEquals, Value: "team@example.com"}, true},
+		{"cc empty", RuleCondition{Field: RuleFieldCc, Operator: RuleOpContains, Value: "x"}, false},
+		{"subject pattern", RuleCondition{Field: RuleFieldSubject, Operator: RuleOpMatches, Value: `#\d+`}, true},
+		{"body contains", RuleCondition{Field: RuleFieldBody, Operator: RuleOpContains, Value: "tracking"}, true},
+		{"label by name", RuleCondition{Field: RuleFieldLabel, Operator: RuleOpEquals, Value: "receipts"}, true},
+		{"negated label", RuleCondition{Field: RuleFieldLabel, Operator: RuleOpEquals, Value: "INBOX", Negate: true}, false},
+		{"negated miss", RuleCondition{Field: RuleFieldSubject, Operator: RuleOpContains, Value: "invoice", Negate: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.Matches(msg, nil); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleCondition_MatchesAccount(t *testing.T) {
+	msg := ruleTestMessage()
+	cond := &RuleCondition{Field: "account.role", Operator: RuleOpEquals, Value: "work"}
+	if err := cond.compile(); err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+	if !cond.Matches(msg, map[string]string{"role": "Work"}) {
+		t.Error("expected account.role equals work to match role=Work")
+	}
+	if cond.Matches(msg, map[string]string{"role": "personal"}) || cond.Matches(msg, nil) {
+		t.Error("expected account.role equals work not to match another or missing role")
+	}
+	if err := (&RuleCondition{Field: "account.", Operator: RuleOpEquals}).compile(); err == nil {
+		t.Error("expected an error for an account field without a key")
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	msg := ruleTestMessage()
+	hit := &RuleCondition{Field: RuleFieldFrom, Operator: RuleOpContains, Value: "shop"}
+	miss := &RuleCondition{Field: RuleFieldSubject, Operator: RuleOpContains, Value: "invoice"}
+
+	all := &Rule{Name: "all", Conditions: []*RuleCondition{hit, miss}}
+	if all.Matches(msg, nil) {
+		t.Error("expected a rule needing all conditions not to match")
+	}
+	anyRule := &Rule{Name: "any", MatchAny: true, Conditions: []*RuleCondition{miss, hit}}
+	if !anyRule.Matches(msg, nil) {
+		t.Error("expected a rule needing any condition to match")
+	}
+	if (&Rule{Name: "empty"}).Matches(msg, nil) {
+		t.Error("expected a rule without conditions never to match")
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	cond := []*RuleCondition{{Field: RuleFieldFrom, Operator: RuleOpContains, Value: "x"}}
+	act := []*RuleAction{{Type: RuleActionArchive}}
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr string
+	}{
+		{"valid", Rule{Name: "ok", Conditions: cond, Actions: act}, ""},
+		{"no name", Rule{Conditions: cond, Actions: act}, "no name"},
+		{"no conditions", Rule{Name: "r", Actions: act}, "no conditions"},
+		{"no actions", Rule{Name: "r", Conditions: cond}, "no actions"},
+		{"bad field", Rule{Name: "r", Conditions: []*RuleCondition{{Field: "date", Operator: RuleOpContains}}, Actions: act}, "unknown condition field"},
+		{"bad operator", Rule{Name: "r", Conditions: []*RuleCondition{{Field: RuleFieldFrom, Operator: "startswith"}}, Actions: act}, "unknown operator"},
+		{"bad pattern", Rule{Name: "r", Conditions: []*RuleCondition{{Field: RuleFieldFrom, Operator: RuleOpMatches, Value: "("}}, Actions: act}, "invalid pattern"},
+		{"bad action", Rule{Name: "r", Conditions: cond, Actions: []*RuleAction{{Type: "delete"}}}, "unknown action"},
+		{"missing value", Rule{Name: "r", Conditions: cond, Actions: []*RuleAction{{Type: RuleActionForward}}}, "needs a value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchingRules_Stop(t *testing.T) {
+	msg := ruleTestMessage()
+	cond := func(v string) []*RuleCondition {
+		return []*RuleCondition{{Field: RuleFieldFrom, Operator: RuleOpContains, Value: v}}
+	}
+	rules := []*Rule{
+		{Name: "miss", Conditions: cond("nobody")},
+		{Name: "first", Conditions: cond("shop")},
+		{Name: "stop", Conditions: cond("orders"), Stop: true},
+		{Name: "after", Conditions: cond("shop")},
+	}
+	matched := MatchingRules(rules, msg, nil)
+	if len(matched) != 2 || matched[0].Name != "first" || matched[1].Name != "stop" {
+		names := make([]string, len(matched))
+		for i, r := range matched {
+			names[i] = r.Name
+		}
+		t.Errorf("MatchingRules() = %v, want [first stop]", names)
+	}
+}
+
+func TestRule_String(t *testing.T) {
+	c := &RuleCondition{Field: RuleFieldSubject, Operator: RuleOpContains, Value: "invoice", Negate: true}
+	if got := c.String(); got != `not subject contains "invoice"` {
+		t.Errorf("RuleCondition.String() = %s", got)
+	}
+	if got := (&RuleAction{Type: RuleActionLabel, Value: "Receipts"}).String(); got != "label Receipts" {
+		t.Errorf("RuleAction.String() = %s", got)
+	}
+	if got := (&RuleAction{Type: RuleActionArchive}).String(); got != "archive" {
+		t.Errorf("RuleAction.String() = %s", got)
+	}
+}
@@ -0,0 +1,240 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// maxMIMEDepth is how deeply nested parts are parsed; anything deeper is
+// left as an opaque part, so a hostile message cannot exhaust the stack.
+const maxMIMEDepth = 32
+
+// Body roles of a MIME part: the part shown as the plain-text or HTML body.
+const (
+	BodyPlain = "plain"
+	BodyHTML  = "html"
+)
+
+// MIMEPart is a node of the MIME structure of a raw message, for inspecting
+// how a message is put together rather than reading it.
+type MIMEPart struct {
+	// Section numbers the part as IMAP does: "1.2" is the second part of
+	// the first part. The root part has no number.
+	Section string
+	// ContentType is the media type, e.g. "text/plain", in lower case.
+	ContentType string
+	Charset     string
+	// Encoding is the Content-Transfer-Encoding, empty when not given.
+	Encoding    string
+	Disposition string
+	Filename    string
+	ContentID   string
+	// Size is the length of the part's body as sent, DecodedSize after
+	// undoing its transfer encoding.
+	Size        int
+	DecodedSize int
+	// Body is BodyPlain or BodyHTML for the parts shown as the message
+	// body, and empty otherwise.
+	Body  string
+	Parts []*MIMEPart
+}
+
+// ParseStructure parses the MIME structure of a raw RFC 5322 message.
+// Malformed parts are kept as far as they could be read, as the point is
+// to see what a message actually contains.
+func ParseStructure(raw []byte) (*MIMEPart, error) {
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	root := parseMIMEPart(textproto.MIMEHeader(msg.Header), body, "text/plain", "", 0)
+	plain, html := root.BodyParts()
+	if plain != nil {
+		plain.Body = BodyPlain
+	}
+	if html != nil {
+		html.Body = BodyHTML
+	}
+	return root, nil
+}
+
+// parseMIMEPart parses a part and, for multipart and message/rfc822
+// parts, the parts inside it.
+func parseMIMEPart(header textproto.MIMEHeader, body []byte, defaultType, section string, depth int) *MIMEPart {
+	part := &MIMEPart{
+		Section:   section,
+		Encoding:  strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))),
+		ContentID: strings.Trim(strings.TrimSpace(header.Get("Content-ID")), "<>"),
+		Size:      len(body),
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !strings.Contains(mediaType, "/") {
+		mediaType, params = defaultType, nil
+	}
+	part.ContentType = strings.ToLower(mediaType)
+	part.Charset = params["charset"]
+
+	if disposition, dparams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		part.Disposition = disposition
+		part.Filename = dparams["filename"]
+	}
+	if part.Filename == "" {
+		part.Filename = params["name"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(part.Filename); err == nil {
+		part.Filename = decoded
+	}
+	part.DecodedSize = decodedSize(part.Encoding, body)
+
+	if depth >= maxMIMEDepth {
+		return part
+	}
+	switch {
+	case strings.HasPrefix(part.ContentType, "multipart/") && params["boundary"] != "":
+		childType := "text/plain"
+		if part.ContentType == "multipart/digest" {
+			childType = "message/rfc822"
+		}
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			child, err := reader.NextRawPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(child)
+			part.Parts = append(part.Parts, parseMIMEPart(child.Header, data, childType, childSection(section, len(part.Parts)+1), depth+1))
+		}
+	case part.ContentType == "message/rfc822":
+		if msg, err := netmail.ReadMessage(bytes.NewReader(body)); err == nil {
+			data, _ := io.ReadAll(msg.Body)
+			part.Parts = append(part.Parts, parseMIMEPart(textproto.MIMEHeader(msg.Header), data, "text/plain", childSection(section, 1), depth+1))
+		}
+	}
+	return part
+}
+
+// childSection returns the section number of the nth part inside section.
+func childSection(section string, n int) string {
+	if section == "" {
+		return strconv.Itoa(n)
+	}
+	return section + "." + strconv.Itoa(n)
+}
+
+// decodedSize returns the length of body after undoing encoding, or its
+// length as sent when it cannot be decoded.
+func decodedSize(encoding string, body []byte) int {
+	var r io.Reader
+	switch encoding {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, bytes.NewReader(stripWhitespace(body)))
+	case "quoted-printable":
+		r = quotedprintable.NewReader(bytes.NewReader(body))
+	default:
+		return len(body)
+	}
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return len(body)
+	}
+	return int(n)
+}
+
+// stripWhitespace removes the line breaks and spaces base64 bodies are
+// wrapped with.
+func stripWhitespace(data []byte) []byte {
+	return bytes.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return -1
+		}
+		return r
+	}, data)
+}
+
+// BodyParts returns the parts shown as the message body, as the Gmail
+// repository picks them: the first text/plain and the first text/html
+// part, depth first, inside multiparts but not inside attached messages.
+// Attachments, parts with a filename or an attachment disposition, are
+// never the body. A message with no body of its own shows that of the
+// first attached message.
+func (p *MIMEPart) BodyParts() (plain, html *MIMEPart) {
+	if plain, html = p.ownBodyParts(); plain != nil || html != nil {
+		return plain, html
+	}
+	return p.attachedBodyParts()
+}
+
+// ownBodyParts returns the body parts outside attached messages.
+func (p *MIMEPart) ownBodyParts() (plain, html *MIMEPart) {
+	if p == nil || p.Filename != "" || p.Disposition == "attachment" {
+		return nil, nil
+	}
+	if strings.HasPrefix(p.ContentType, "multipart/") {
+		for _, child := range p.Parts {
+			childPlain, childHTML := child.ownBodyParts()
+			if plain == nil {
+				plain = childPlain
+			}
+			if html == nil {
+				html = childHTML
+			}
+		}
+		return plain, html
+	}
+	switch p.ContentType {
+	case "text/plain":
+		return p, nil
+	case "text/html":
+		return nil, p
+	}
+	return nil, nil
+}
+
+// attachedBodyParts returns the body parts of the first attached message
+// that has any.
+func (p *MIMEPart) attachedBodyParts() (plain, html *MIMEPart) {
+	if p == nil {
+		return nil, nil
+	}
+	for _, child := range p.Parts {
+		if p.ContentType == "message/rfc822" {
+			plain, html = child.BodyParts()
+		} else {
+			plain, html = child.attachedBodyParts()
+		}
+		if plain != nil || html != nil {
+			return plain, html
+		}
+	}
+	return nil, nil
+}
+
+// Walk calls fn for p and every part inside it, depth first, with the
+// depth of each part below p.
+func (p *MIMEPart) Walk(fn func(part *MIMEPart, depth int)) {
+	p.walk(fn, 0)
+}
+
+func (p *MIMEPart) walk(fn func(part *MIMEPart, depth int), depth int) {
+	if p == nil {
+		return
+	}
+	fn(p, depth)
+	for _, child := range p.Parts {
+		child.walk(fn, depth+1)
+	}
+}
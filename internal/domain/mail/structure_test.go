@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+// structureMessage is a mixed message with an alternative body, a PDF and
+// a forwarded message, in the CRLF form Gmail returns.
+var structureMessage = strings.ReplaceAll(`From: a@example.com
+To: b@example.com
+Subject: Structure
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+Caf=C3=A9 menu
+--inner
+Content-Type: text/html; charset=utf-8
+
+<p>Café menu</p>
+--inner--
+--outer
+Content-Type: application/pdf; name="menu.pdf"
+Content-Disposition: attachment; filename="=?utf-8?q?men=C3=BA.pdf?="
+Content-Transfer-Encoding: base64
+Content-ID: <menu@example.com>
+
+JVBERi0xLjQK
+--outer
+Content-Type: message/rfc822
+
+Subject: Forwarded
+Content-Type: text/plain
+
+Inner body
+--outer--
+`, "\n", "\r\n")
+
+func TestParseStructure(t *testing.T) {
+	root, err := ParseStructure([]byte(structureMessage))
+	if err != nil {
+		t.Fatalf("ParseStructure() error = %v", err)
+	}
+
+	var sections, types []string
+	root.Walk(func(part *MIMEPart, depth int) {
+		sections = append(sections, part.Section)
+		types = append(types, strings.Repeat(" ", depth)+part.ContentType)
+	})
+	wantTypes := []string{
+		"multipart/mixed",
+		" multipart/alternative",
+		"  text/plain",
+		"  text/html",
+		" application/pdf",
+		" message/rfc822",
+		"  text/plain",
+	}
+	if strings.Join(types, "|") != strings.Join(wantTypes, "|") {
+		t.Fatalf("types = %q, want %q", types, wantTypes)
+	}
+	if got := strings.Join(sections, ","); got != ",1,1.1,1.2,2,3,3.1" {
+		t.Errorf("sections = %q", got)
+	}
+
+	plain := root.Parts[0].Parts[0]
+	if plain.Body != BodyPlain || plain.Encoding != "quoted-printable" || plain.Charset != "utf-8" {
+		t.Errorf("plain part = %+v", plain)
+	}
+	if plain.DecodedSize != len("Café menu") || plain.Size <= plain.DecodedSize {
+		t.Errorf("plain sizes = %d sent, %d decoded", plain.Size, plain.DecodedSize)
+	}
+	if html := root.Parts[0].Parts[1]; html.Body != BodyHTML {
+		t.Errorf("html part Body = %q, want %q", html.Body, BodyHTML)
+	}
+
+	pdf := root.Parts[1]
+	if pdf.Filename != "menú.pdf" || pdf.Disposition != "attachment" || pdf.ContentID != "menu@example.com" {
+		t.Errorf("pdf part = %+v", pdf)
+	}
+	if pdf.DecodedSize != 9 {
+		t.Errorf("pdf DecodedSize = %d, want 9", pdf.DecodedSize)
+	}
+
+	// The forwarded message's text is not the body
+	if inner := root.Parts[2].Parts[0]; inner.Body != "" {
+		t.Errorf("forwarded part Body = %q, want none", inner.Body)
+	}
+}
+
+func TestParseStructure_SinglePart(t *testing.T) {
+	root, err := ParseStructure([]byte("Subject: hi\r\n\r\nhello\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ContentType != "text/plain" || root.Body != BodyPlain || len(root.Parts) != 0 {
+		t.Errorf("root = %+v", root)
+	}
+
+	if _, err := ParseStructure([]byte("not a message")); err == nil {
+		t.Error("expected an error for a message without a header")
+	}
+}
+
+func TestParseStructure_ForwardedOnly(t *testing.T) {
+	raw := strings.ReplaceAll(`Subject: Fwd
+Content-Type: multipart/mixed; boundary="b"
+
+--b
+Content-Type: message/rfc822
+
+Subject: Original
+Content-Type: text/plain
+
+Original text
+--b--
+`, "\n", "\r\n")
+	root, err := ParseStructure([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner := root.Parts[0].Parts[0]; inner.Body != BodyPlain {
+		t.Errorf("forwarded part Body = %q, want %q", inner.Body, BodyPlain)
+	}
+}
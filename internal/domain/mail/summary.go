@@ -0,0 +1,11 @@
+package mail
+
+import "context"
+
+// ThreadSummarizer summarizes an email conversation, typically by handing
+// it to an external tool such as a language model.
+type ThreadSummarizer interface {
+	// SummarizeThread returns a summary of thread. text is the whole
+	// conversation rendered as a readable document.
+	SummarizeThread(ctx context.Context, thread *Thread, text string) (string, error)
+}
@@ -1,5 +1,12 @@
 package mail
 
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
 // Thread represents an email conversation thread.
 type Thread struct {
 	ID       string
@@ -67,3 +74,87 @@ func (t *Thread) FirstMessage() *Message {
 	}
 	return t.Messages[0]
 }
+
+// LatestNonDraft returns the latest message of the thread by date, leaving
+// out drafts, or nil if there is none.
+func (t *Thread) LatestNonDraft() *Message {
+	var latest *Message
+	for _, msg := range t.Messages {
+		if msg == nil || msg.HasLabel("DRAFT") {
+			continue
+		}
+		if latest == nil || !msg.Date.Before(latest.Date) {
+			latest = msg
+		}
+	}
+	return latest
+}
+
+// HasDraft checks if the thread holds a draft.
+func (t *Thread) HasDraft() bool {
+	return slices.ContainsFunc(t.Messages, func(msg *Message) bool {
+		return msg != nil && msg.HasLabel("DRAFT")
+	})
+}
+
+// AwaitingReply reports whether the latest message of the thread, drafts
+// aside, was sent by self before cutoff to someone other than self, so a
+// reply is still due.
+func (t *Thread) AwaitingReply(self string, cutoff time.Time) bool {
+	latest := t.LatestNonDraft()
+	if latest == nil || !strings.EqualFold(latest.From.Email, self) || !latest.Date.Before(cutoff) {
+		return false
+	}
+	for _, addr := range slices.Concat(latest.To, latest.Cc, latest.Bcc) {
+		if addr.Email != "" && !strings.EqualFold(addr.Email, self) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnreadCount returns the number of unread messages in the thread.
+func (t *Thread) UnreadCount() int {
+	count := 0
+	for _, msg := range t.Messages {
+		if msg != nil && !msg.IsRead {
+			count++
+		}
+	}
+	return count
+}
+
+// GroupByThread groups messages by ThreadID. Threads keep the order in which
+// they first appear in msgs, so a newest-first listing gives threads ordered
+// by their latest message. Messages within a thread are ordered oldest
+// first, and the thread snippet and labels come from its latest message.
+func GroupByThread(msgs []*Message) []*Thread {
+	var threads []*Thread
+	byID := make(map[string]*Thread)
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		id := msg.ThreadID
+		if id == "" {
+			id = msg.ID
+		}
+		thread, ok := byID[id]
+		if !ok {
+			thread = NewThread(id)
+			byID[id] = thread
+			threads = append(threads, thread)
+		}
+		thread.AddMessage(msg)
+	}
+
+	for _, thread := range threads {
+		sort.SliceStable(thread.Messages, func(i, j int) bool {
+			return thread.Messages[i].Date.Before(thread.Messages[j].Date)
+		})
+		latest := thread.LatestMessage()
+		thread.Snippet = latest.Snippet
+		thread.Labels = append(thread.Labels, latest.Labels...)
+	}
+	return threads
+}
@@ -1,6 +1,9 @@
 package mail
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestNewThread(t *testing.T) {
 	thread := NewThread("thread-123")
@@ -126,3 +129,125 @@ func TestThread_FirstMessage(t *testing.T) {
 		t.Error("expected FirstMessage to still be msg1 after adding second message")
 	}
 }
+
+func TestThread_UnreadCount(t *testing.T) {
+	thread := NewThread("thread-123")
+	if thread.UnreadCount() != 0 {
+		t.Errorf("expected 0 unread in empty thread, got %d", thread.UnreadCount())
+	}
+
+	read := NewMessage("msg-1", "thread-123", "from@example.com", "Subject", "Body")
+	read.IsRead = true
+	thread.AddMessage(read)
+	thread.AddMessage(NewMessage("msg-2", "thread-123", "from@example.com", "Subject", "Body"))
+	thread.AddMessage(NewMessage("msg-3", "thread-123", "from@example.com", "Subject", "Body"))
+
+	if thread.UnreadCount() != 2 {
+		t.Errorf("expected 2 unread, got %d", thread.UnreadCount())
+	}
+}
+
+func TestThread_LatestNonDraftAndHasDraft(t *testing.T) {
+	now := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	thread := NewThread("t1")
+	if thread.LatestNonDraft() != nil || thread.HasDraft() {
+		t.Error("expected no latest message and no draft in an empty thread")
+	}
+
+	later := &Message{ID: "m2", Date: now}
+	thread.AddMessage(later)
+	thread.AddMessage(&Message{ID: "m1", Date: now.Add(-time.Hour)})
+	thread.AddMessage(&Message{ID: "d1", Date: now.Add(time.Hour), Labels: []string{"DRAFT"}})
+
+	if got := thread.LatestNonDraft(); got != later {
+		t.Errorf("LatestNonDraft = %v, want m2", got)
+	}
+	if !thread.HasDraft() {
+		t.Error("expected HasDraft to be true")
+	}
+}
+
+func TestThread_AwaitingReply(t *testing.T) {
+	now := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-72 * time.Hour)
+	mine := func(age time.Duration, to ...Address) *Message {
+		return &Message{From: Address{Email: "Me@Example.com"}, To: to, Date: now.Add(-age)}
+	}
+	ana := Address{Email: "ana@example.com"}
+
+	tests := []struct {
+		name     string
+		messages []*Message
+		want     bool
+	}{
+		{"empty", nil, false},
+		{"mine, old enough", []*Message{mine(96*time.Hour, ana)}, true},
+		{"mine, too recent", []*Message{mine(24*time.Hour, ana)}, false},
+		{"answered", []*Message{mine(120*time.Hour, ana), {From: ana, Date: now.Add(-100 * time.Hour)}}, false},
+		{"to myself", []*Message{mine(96*time.Hour, Address{Email: "me@example.com"})}, false},
+		{"bcc only", []*Message{{From: Address{Email: "me@example.com"}, Bcc: []Address{ana}, Date: now.Add(-96 * time.Hour)}}, true},
+		{"draft after", []*Message{mine(96*time.Hour, ana), {From: Address{Email: "me@example.com"}, Labels: []string{"DRAFT"}, Date: now}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thread := &Thread{ID: "t1", Messages: tt.messages}
+			if got := thread.AwaitingReply("me@example.com", cutoff); got != tt.want {
+				t.Errorf("AwaitingReply = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupByThread(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newMsg := func(id, threadID string, hours int) *Message {
+		msg := NewMessage(id, threadID, "from@example.com", "Subject "+id, "")
+		msg.Date = base.Add(time.Duration(hours) * time.Hour)
+		msg.Snippet = "snippet " + id
+		msg.Labels = []string{"INBOX"}
+		return msg
+	}
+
+	// Newest first, as returned by a listing
+	msgs := []*Message{
+		newMsg("m4", "t1", 4),
+		newMsg("m3", "t2", 3),
+		nil,
+		newMsg("m2", "t1", 2),
+		newMsg("m1", "", 1),
+	}
+
+	threads := GroupByThread(msgs)
+	if len(threads) != 3 {
+		t.Fatalf("expected 3 threads, got %d", len(threads))
+	}
+
+	first := threads[0]
+	if first.ID != "t1" || first.MessageCount() != 2 {
+		t.Fatalf("unexpected first thread %s with %d messages", first.ID, first.MessageCount())
+	}
+	if first.FirstMessage().ID != "m2" || first.LatestMessage().ID != "m4" {
+		t.Errorf("expected messages oldest first, got %s then %s", first.FirstMessage().ID, first.LatestMessage().ID)
+	}
+	if first.Snippet != "snippet m4" {
+		t.Errorf("expected latest snippet, got %q", first.Snippet)
+	}
+	if !first.HasLabel("INBOX") {
+		t.Errorf("expected labels of the latest message, got %v", first.Labels)
+	}
+	if first.UnreadCount() != 2 {
+		t.Errorf("expected 2 unread, got %d", first.UnreadCount())
+	}
+
+	if threads[1].ID != "t2" {
+		t.Errorf("expected t2 second, got %s", threads[1].ID)
+	}
+	if threads[2].ID != "m1" {
+		t.Errorf("message without a thread ID should form its own thread, got %s", threads[2].ID)
+	}
+
+	if got := GroupByThread(nil); len(got) != 0 {
+		t.Errorf("expected no threads for no messages, got %d", len(got))
+	}
+}
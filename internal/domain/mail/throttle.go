@@ -0,0 +1,101 @@
+package mail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ThrottlePolicy limits how often mail is sent to the same recipient, so a
+// script stuck in a loop cannot flood someone with notifications.
+type ThrottlePolicy struct {
+	// Cooldown is the minimum time between two messages to a recipient.
+	Cooldown time.Duration
+	// Overrides replace Cooldown for a lower-cased address or, keyed as
+	// "@domain", for every address of a domain. Zero exempts them.
+	Overrides map[string]time.Duration
+}
+
+// ThrottledRecipient is a recipient a message may not be sent to yet.
+type ThrottledRecipient struct {
+	Address   string
+	LastSent  time.Time
+	Remaining time.Duration
+}
+
+// ParseThrottleOverride parses an override of the form "target=duration",
+// where target is an address or "@domain", e.g. "oncall@example.com=0" or
+// "@example.org=1h". The target is returned lower-cased.
+func ParseThrottleOverride(entry string) (string, time.Duration, error) {
+	target, value, ok := strings.Cut(entry, "=")
+	target = strings.ToLower(strings.TrimSpace(target))
+	if !ok || target == "" || !strings.Contains(target, "@") {
+		return "", 0, fmt.Errorf("invalid throttle override %q: expected address=duration or @domain=duration", entry)
+	}
+	if strings.HasPrefix(target, "@") && len(target) == 1 {
+		return "", 0, fmt.Errorf("invalid throttle override %q: missing domain", entry)
+	}
+	cooldown, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || cooldown < 0 {
+		return "", 0, fmt.Errorf("invalid throttle override %q: duration must be like 10m or 0", entry)
+	}
+	return target, cooldown, nil
+}
+
+// NewThrottlePolicy creates a policy with the given cooldown and overrides
+// in the form ParseThrottleOverride accepts.
+func NewThrottlePolicy(cooldown time.Duration, overrides []string) (*ThrottlePolicy, error) {
+	if cooldown < 0 {
+		return nil, fmt.Errorf("throttle cooldown cannot be negative")
+	}
+	policy := &ThrottlePolicy{Cooldown: cooldown, Overrides: make(map[string]time.Duration, len(overrides))}
+	for _, entry := range overrides {
+		target, d, err := ParseThrottleOverride(entry)
+		if err != nil {
+			return nil, err
+		}
+		policy.Overrides[target] = d
+	}
+	return policy, nil
+}
+
+// CooldownFor returns the cooldown that applies to addr: its own override,
+// then that of its domain, then the policy's cooldown.
+func (p *ThrottlePolicy) CooldownFor(addr string) time.Duration {
+	addr = strings.ToLower(strings.TrimSpace(addr))
+	if d, ok := p.Overrides[addr]; ok {
+		return d
+	}
+	if domain := AddressDomain(addr); domain != "" {
+		if d, ok := p.Overrides["@"+domain]; ok {
+			return d
+		}
+	}
+	return p.Cooldown
+}
+
+// Check returns the recipients whose cooldown has not passed at now, given
+// when mail was last sent to each lower-cased address. Each address is
+// reported once, in order of the time remaining.
+func (p *ThrottlePolicy) Check(recipients []string, lastSent map[string]time.Time, now time.Time) []ThrottledRecipient {
+	var throttled []ThrottledRecipient
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		addr := strings.ToLower(strings.TrimSpace(recipient))
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+
+		last, ok := lastSent[addr]
+		if !ok {
+			continue
+		}
+		if remaining := last.Add(p.CooldownFor(addr)).Sub(now); remaining > 0 {
+			throttled = append(throttled, ThrottledRecipient{Address: addr, LastSent: last, Remaining: remaining})
+		}
+	}
+	sort.SliceStable(throttled, func(i, j int) bool { return throttled[i].Remaining < throttled[j].Remaining })
+	return throttled
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseThrottleOverride(t *testing.T) {
+	tests := []struct {
+		entry      string
+		wantTarget string
+		want       time.Duration
+		wantErr    bool
+	}{
+		{entry: "OnCall@Example.com=0", wantTarget: "oncall@example.com", want: 0},
+		{entry: " @example.org = 1h ", wantTarget: "@example.org", want: time.Hour},
+		{entry: "example.org=1h", wantErr: true},
+		{entry: "@=1h", wantErr: true},
+		{entry: "a@example.com", wantErr: true},
+		{entry: "a@example.com=soon", wantErr: true},
+		{entry: "a@example.com=-1m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			target, d, err := ParseThrottleOverride(tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %q=%s", target, d)
+				}
+				return
+			}
+			if err != nil || target != tt.wantTarget || d != tt.want {
+				t.Errorf("ParseThrottleOverride() = %q, %s, %v; want %q, %s", target, d, err, tt.wantTarget, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottlePolicy_Check(t *testing.T) {
+	policy, err := NewThrottlePolicy(10*time.Minute, []string{"oncall@example.com=0", "@example.org=1h"})
+	if err != nil {
+		t.Fatalf("NewThrottlePolicy() error = %v", err)
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastSent := map[string]time.Time{
+		"ana@example.com":    now.Add(-3 * time.Minute),
+		"bo@example.com":     now.Add(-15 * time.Minute),
+		"oncall@example.com": now.Add(-time.Minute),
+		"cy@example.org":     now.Add(-30 * time.Minute),
+	}
+
+	got := policy.Check([]string{"cy@example.org", "Ana@Example.com", "bo@example.com", "oncall@example.com", "new@example.com", "ana@example.com"}, lastSent, now)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 throttled recipients, got %+v", got)
+	}
+	if got[0].Address != "ana@example.com" || got[0].Remaining != 7*time.Minute {
+		t.Errorf("got[0] = %+v, want ana@example.com with 7m remaining", got[0])
+	}
+	if got[1].Address != "cy@example.org" || got[1].Remaining != 30*time.Minute {
+		t.Errorf("got[1] = %+v, want cy@example.org with 30m remaining", got[1])
+	}
+
+	if _, err := NewThrottlePolicy(-time.Minute, nil); err == nil {
+		t.Error("expected an error for a negative cooldown")
+	}
+	if _, err := NewThrottlePolicy(time.Minute, []string{"bad"}); err == nil || !strings.Contains(err.Error(), "bad") {
+		t.Errorf("expected an error naming the bad override, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTranslationChunk is the largest piece of text, in bytes, sent to a
+// Translator at once. Longer bodies are split at line breaks.
+const MaxTranslationChunk = 5000
+
+// TranslatedText is one piece of text after translation.
+type TranslatedText struct {
+	// Text is the translated text.
+	Text string
+	// SourceLanguage is the language code detected for the original text,
+	// e.g. "fr".
+	SourceLanguage string
+}
+
+// Translator translates text into a target language, detecting the
+// language of the original.
+type Translator interface {
+	// Translate returns one TranslatedText per entry of texts, in order.
+	Translate(ctx context.Context, texts []string, target string) ([]TranslatedText, error)
+}
+
+// Translation is a message's subject and body translated into another
+// language.
+type Translation struct {
+	// SourceLanguage is the language detected for the message.
+	SourceLanguage string
+	// TargetLanguage is the language the message was translated into.
+	TargetLanguage string
+	Subject        string
+	Body           string
+}
+
+// TranslateMessage translates a message's subject and plain-text body into
+// target with one call to translator. The detected language is the one
+// reported for the body, or for the subject when the body is empty.
+func TranslateMessage(ctx context.Context, translator Translator, subject, body, target string) (*Translation, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("no target language given")
+	}
+
+	texts := []string{subject}
+	chunks := splitTranslationText(body, MaxTranslationChunk)
+	texts = append(texts, chunks...)
+
+	translated, err := translator.Translate(ctx, texts, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(translated) != len(texts) {
+		return nil, fmt.Errorf("translator returned %d texts for %d", len(translated), len(texts))
+	}
+
+	result := &Translation{TargetLanguage: target, Subject: translated[0].Text, SourceLanguage: translated[0].SourceLanguage}
+	var b strings.Builder
+	for i, t := range translated[1:] {
+		b.WriteString(t.Text)
+		if i == 0 && t.SourceLanguage != "" {
+			result.SourceLanguage = t.SourceLanguage
+		}
+	}
+	result.Body = b.String()
+	return result, nil
+}
+
+// splitTranslationText splits text into chunks of at most max bytes,
+// breaking after newlines where possible and never inside a UTF-8
+// sequence. Joining the chunks gives back text.
+func splitTranslationText(text string, max int) []string {
+	var chunks []string
+	for len(text) > max {
+		cut := strings.LastIndexByte(text[:max], '\n') + 1
+		if cut == 0 {
+			cut = max
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				cut = max
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
@@ -0,0 +1,91 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeTranslator upper-cases text and reports a fixed language per call.
+type fakeTranslator struct {
+	languages []string
+	calls     [][]string
+	err       error
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, texts []string, target string) ([]TranslatedText, error) {
+	f.calls = append(f.calls, texts)
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([]TranslatedText, len(texts))
+	for i, t := range texts {
+		out[i] = TranslatedText{Text: strings.ToUpper(t)}
+		if i < len(f.languages) {
+			out[i].SourceLanguage = f.languages[i]
+		}
+	}
+	return out, nil
+}
+
+func TestTranslateMessage(t *testing.T) {
+	tr := &fakeTranslator{languages: []string{"en", "fr"}}
+	got, err := TranslateMessage(context.Background(), tr, "Bonjour", "merci\nà bientôt", " en ")
+	if err != nil {
+		t.Fatalf("TranslateMessage failed: %v", err)
+	}
+	want := &Translation{SourceLanguage: "fr", TargetLanguage: "en", Subject: "BONJOUR", Body: "MERCI\nÀ BIENTÔT"}
+	if *got != *want {
+		t.Errorf("TranslateMessage() = %+v, want %+v", got, want)
+	}
+	if len(tr.calls) != 1 {
+		t.Errorf("expected a single translator call, got %d", len(tr.calls))
+	}
+}
+
+func TestTranslateMessage_EmptyBody(t *testing.T) {
+	got, err := TranslateMessage(context.Background(), &fakeTranslator{languages: []string{"de"}}, "Hallo", "", "en")
+	if err != nil {
+		t.Fatalf("TranslateMessage failed: %v", err)
+	}
+	if got.SourceLanguage != "de" || got.Body != "" {
+		t.Errorf("expected the subject's language and an empty body, got %+v", got)
+	}
+}
+
+func TestTranslateMessage_Errors(t *testing.T) {
+	if _, err := TranslateMessage(context.Background(), &fakeTranslator{}, "s", "b", ""); err == nil {
+		t.Error("expected an error without a target language")
+	}
+	want := errors.New("quota exceeded")
+	if _, err := TranslateMessage(context.Background(), &fakeTranslator{err: want}, "s", "b", "en"); !errors.Is(err, want) {
+		t.Errorf("expected the translator error, got %v", err)
+	}
+}
+
+func TestSplitTranslationText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{"short", "abc", 10, []string{"abc"}},
+		{"empty", "", 10, nil},
+		{"at newline", "aaa\nbbb\nccc", 9, []string{"aaa\nbbb\n", "ccc"}},
+		{"no newline", "abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"keeps runes whole", "ééé", 3, []string{"é", "é", "é"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitTranslationText(tt.text, tt.max)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("splitTranslationText() = %q, want %q", got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("chunks do not join back to the text: %q", got)
+			}
+		})
+	}
+}
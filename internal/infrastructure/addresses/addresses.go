@@ -0,0 +1,166 @@
+// Package addresses keeps a local cache of the addresses mail has been
+// sent to and checks recipient domains for mail servers, so recipients can
+// be verified before a message is sent.
+package addresses
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the address cache kept next to the config file.
+const FileName = "addresses.json"
+
+// MaxEntries is the number of most recently used addresses kept.
+const MaxEntries = 2000
+
+// ErrNoMailServer is returned by CheckMX for domains that cannot receive
+// mail.
+var ErrNoMailServer = errors.New("domain does not accept mail")
+
+// Path returns the path of the address cache.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// Entry records how often and when mail was last sent to an address.
+type Entry struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Load reads the address cache at path, keyed by lower-cased address. A
+// missing file is an empty cache.
+func Load(path string) (map[string]Entry, error) {
+	cache := map[string]Entry{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read address cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse address cache: %w", err)
+	}
+	return cache, nil
+}
+
+// Record adds addrs, as sent to at t, to the cache at path. Only the
+// MaxEntries most recently used addresses are kept.
+func Record(path string, addrs []string, t time.Time) error {
+	if len(addrs) == 0 {
+		return nil
+	}
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	cache, err := Load(path)
+	if err != nil {
+		return err
+	}
+	t = t.UTC().Truncate(time.Second)
+	for _, addr := range addrs {
+		key := strings.ToLower(strings.TrimSpace(addr))
+		if key == "" {
+			continue
+		}
+		entry := cache[key]
+		entry.Count++
+		entry.LastUsed = t
+		cache[key] = entry
+	}
+	if len(cache) > MaxEntries {
+		keys := make([]string, 0, len(cache))
+		for key := range cache {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return cache[b].LastUsed.Compare(cache[a].LastUsed)
+		})
+		for _, key := range keys[MaxEntries:] {
+			delete(cache, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode address cache: %w", err)
+	}
+	if err := filelock.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write address cache: %w", err)
+	}
+	return nil
+}
+
+// Domains returns the domains of the cached addresses, most used first.
+func Domains(cache map[string]Entry) []string {
+	counts := map[string]int{}
+	for addr, entry := range cache {
+		if domain := mail.AddressDomain(addr); domain != "" {
+			counts[domain] += entry.Count
+		}
+	}
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	slices.SortFunc(domains, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	return domains
+}
+
+// Resolver looks up DNS records. *net.Resolver implements it.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// CheckMX reports whether domain can receive mail: it needs MX records,
+// or an address record to fall back on when it has none (RFC 5321). A
+// domain that does not exist or publishes a null MX (RFC 7505) fails with
+// ErrNoMailServer; lookup failures are returned as they are.
+func CheckMX(ctx context.Context, r Resolver, domain string) error {
+	records, err := r.LookupMX(ctx, domain)
+	if err == nil {
+		if len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "" {
+			return fmt.Errorf("%w: %s publishes a null MX record", ErrNoMailServer, domain)
+		}
+		if len(records) > 0 {
+			return nil
+		}
+	} else if !isNotFound(err) {
+		return fmt.Errorf("MX lookup for %s failed: %w", domain, err)
+	}
+
+	if _, err := r.LookupHost(ctx, domain); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("%w: %s has no MX or address records", ErrNoMailServer, domain)
+		}
+		return fmt.Errorf("address lookup for %s failed: %w", domain, err)
+	}
+	return nil
+}
+
+// isNotFound reports whether err says the DNS name or record does not exist.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,117 @@
+package addresses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	day := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	cache, err := Load(path)
+	if err != nil || len(cache) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v; want an empty cache", cache, err)
+	}
+
+	if err := Record(path, []string{"Alice@Example.com", "bob@acme.io"}, day); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(path, []string{"alice@example.com", "carol@acme.io", "dave@acme.io", " "}, day.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	cache, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cache["alice@example.com"]; got.Count != 2 || !got.LastUsed.Equal(day.Add(time.Hour)) {
+		t.Errorf("alice entry = %+v", got)
+	}
+	if len(cache) != 4 {
+		t.Errorf("cache has %d entries, want 4", len(cache))
+	}
+	if got := Domains(cache); strings.Join(got, ",") != "acme.io,example.com" {
+		t.Errorf("Domains() = %v", got)
+	}
+}
+
+func TestRecord_KeepsMostRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	addrs := make([]string, MaxEntries)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	if err := Record(path, addrs, start); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record(path, []string{"new@example.com"}, start.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache["new@example.com"]; !ok || len(cache) != MaxEntries {
+		t.Errorf("expected %d entries including the newest, got %d", MaxEntries, len(cache))
+	}
+}
+
+// fakeResolver answers from maps; missing names are not found.
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	err   error
+}
+
+func (f *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if records, ok := f.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (f *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestCheckMX(t *testing.T) {
+	r := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.com":  {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"fallback.org": {"192.0.2.1"}},
+	}
+	ctx := context.Background()
+
+	for domain, wantNoMail := range map[string]bool{
+		"example.com":  false,
+		"fallback.org": false,
+		"nomail.com":   true,
+		"gamil.con":    true,
+	} {
+		err := CheckMX(ctx, r, domain)
+		if got := errors.Is(err, ErrNoMailServer); got != wantNoMail || (!wantNoMail && err != nil) {
+			t.Errorf("CheckMX(%s) = %v, want no mail server %v", domain, err, wantNoMail)
+		}
+	}
+
+	r.err = &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	if err := CheckMX(ctx, r, "example.com"); err == nil || errors.Is(err, ErrNoMailServer) {
+		t.Errorf("expected a lookup failure distinct from ErrNoMailServer, got %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+// Package archive writes and reads mail backup archives: a tar file,
+// optionally compressed with zstd or gzip, holding each message as an
+// .eml file and an index.json manifest listing them.
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	netmail "net/mail"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression formats of an archive.
+const (
+	CompressNone = "none"
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+)
+
+// IndexName is the name of the manifest in an archive. It is the last
+// entry, so messages can be written as they are fetched.
+const IndexName = "index.json"
+
+// IndexVersion is the version of the manifest format.
+const IndexVersion = 1
+
+// messageDir is the directory of the message files in an archive.
+const messageDir = "messages"
+
+// Magic numbers that start compressed streams.
+var (
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// Index is the manifest of an archive.
+type Index struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Account string    `json:"account,omitempty"`
+	Query   string    `json:"query,omitempty"`
+	// IncludeSpamTrash records that messages in spam and trash were
+	// backed up too.
+	IncludeSpamTrash bool    `json:"include_spam_trash,omitempty"`
+	Messages         []Entry `json:"messages"`
+}
+
+// Entry describes one message in an archive.
+type Entry struct {
+	ID      string `json:"id"`
+	File    string `json:"file"`
+	Size    int    `json:"size"`
+	SHA256  string `json:"sha256"`
+	From    string `json:"from,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Date    string `json:"date,omitempty"`
+}
+
+// ParseCompression checks a --compress value.
+func ParseCompression(s string) (string, error) {
+	switch c := strings.ToLower(strings.TrimSpace(s)); c {
+	case CompressNone, CompressGzip, CompressZstd:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid compression %q: must be zstd, gzip or none", s)
+	}
+}
+
+// CompressionFor returns the compression implied by an archive file name:
+// gzip for .tar.gz and .tgz, none for .tar, and zstd otherwise.
+func CompressionFor(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return CompressGzip
+	case strings.HasSuffix(name, ".tar"):
+		return CompressNone
+	default:
+		return CompressZstd
+	}
+}
+
+// Writer writes an archive, one message at a time.
+type Writer struct {
+	tw    *tar.Writer
+	comp  io.WriteCloser
+	index Index
+	now   time.Time
+}
+
+// NewWriter starts an archive written to w with the given compression.
+// index gives the account and query recorded in the manifest.
+func NewWriter(w io.Writer, compression string, index Index) (*Writer, error) {
+	var comp io.WriteCloser
+	switch compression {
+	case CompressZstd:
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start zstd compression: %w", err)
+		}
+		comp = enc
+	case CompressGzip:
+		comp = gzip.NewWriter(w)
+	case CompressNone:
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	index.Version, index.Created, index.Messages = IndexVersion, now, []Entry{}
+	out := w
+	if comp != nil {
+		out = comp
+	}
+	return &Writer{tw: tar.NewWriter(out), comp: comp, index: index, now: now}, nil
+}
+
+// Add writes the RFC 2822 content of the message with the given ID.
+func (w *Writer) Add(id string, raw []byte) error {
+	sum := sha256.Sum256(raw)
+	entry := Entry{
+		ID:     id,
+		File:   path.Join(messageDir, id+".eml"),
+		Size:   len(raw),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	if msg, err := netmail.ReadMessage(bytes.NewReader(raw)); err == nil {
+		entry.From = decodeHeader(msg.Header.Get("From"))
+		entry.Subject = decodeHeader(msg.Header.Get("Subject"))
+		entry.Date = msg.Header.Get("Date")
+	}
+
+	if err := w.writeFile(entry.File, raw); err != nil {
+		return err
+	}
+	w.index.Messages = append(w.index.Messages, entry)
+	return nil
+}
+
+// decodeHeader decodes the encoded words of a header value, keeping the
+// value as it is when it cannot be decoded.
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// Count returns the number of messages written.
+func (w *Writer) Count() int {
+	return len(w.index.Messages)
+}
+
+// Close writes the manifest and finishes the archive. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive index: %w", err)
+	}
+	if err := w.writeFile(IndexName, append(data, '\n')); err != nil {
+		return err
+	}
+	if err := w.tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if w.comp != nil {
+		if err := w.comp.Close(); err != nil {
+			return fmt.Errorf("failed to finish compression: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeFile adds a file to the tar stream.
+func (w *Writer) writeFile(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: w.now,
+		Format:  tar.FormatPAX,
+	}
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Reader reads the messages of an archive in order.
+type Reader struct {
+	tr     *tar.Reader
+	close  func()
+	index  *Index
+	hashes map[string]string
+}
+
+// NewReader opens an archive read from r, detecting its compression.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(zstdMagic))
+
+	reader := &Reader{close: func() {}, hashes: make(map[string]string)}
+	var in io.Reader = br
+	switch {
+	case bytes.HasPrefix(head, zstdMagic):
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zstd archive: %w", err)
+		}
+		in, reader.close = dec, dec.Close
+	case bytes.HasPrefix(head, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip archive: %w", err)
+		}
+		in, reader.close = gz, func() { _ = gz.Close() }
+	}
+	reader.tr = tar.NewReader(in)
+	return reader, nil
+}
+
+// Next returns the ID and content of the next message. It returns io.EOF
+// after the last entry; the manifest is then available from Index.
+func (r *Reader) Next() (string, []byte, error) {
+	for {
+		hdr, err := r.tr.Next()
+		if err == io.EOF {
+			return "", nil, io.EOF
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(r.tr)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		if hdr.Name == IndexName {
+			var index Index
+			if err := json.Unmarshal(data, &index); err != nil {
+				return "", nil, fmt.Errorf("failed to parse archive index: %w", err)
+			}
+			r.index = &index
+			continue
+		}
+		dir, file := path.Split(hdr.Name)
+		id, ok := strings.CutSuffix(file, ".eml")
+		if path.Clean(dir) != messageDir || !ok || id == "" {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		r.hashes[hdr.Name] = hex.EncodeToString(sum[:])
+		return id, data, nil
+	}
+}
+
+// ReadIndex reads the archive from r to its end and returns its manifest.
+func ReadIndex(r io.Reader) (*Index, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	for {
+		if _, _, err := reader.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if reader.index == nil {
+		return nil, errors.New("archive has no index")
+	}
+	return reader.index, nil
+}
+
+// Lookup returns the manifest entry of the message with the given ID.
+func (i *Index) Lookup(id string) (Entry, bool) {
+	for _, e := range i.Messages {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Matches reports whether raw is the content the entry was written with.
+func (e Entry) Matches(raw []byte) bool {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]) == e.SHA256
+}
+
+// Index returns the manifest, once Next has reached it.
+func (r *Reader) Index() *Index {
+	return r.index
+}
+
+// Verify checks, after the last message has been read, that the archive
+// held every message of its manifest unchanged.
+func (r *Reader) Verify() error {
+	if r.index == nil {
+		return errors.New("archive has no index")
+	}
+	var problems []string
+	for _, e := range r.index.Messages {
+		switch got, ok := r.hashes[e.File]; {
+		case !ok:
+			problems = append(problems, e.File+" is missing")
+		case got != e.SHA256:
+			problems = append(problems, e.File+" does not match its checksum")
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("archive is damaged: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Close releases the decompressor. It does not close the underlying
+// reader.
+func (r *Reader) Close() {
+	r.close()
+}
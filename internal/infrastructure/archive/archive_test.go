@@ -0,0 +1,143 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+const rawMessage = "From: =?UTF-8?Q?Ren=C3=A9?= <rene@example.com>\r\n" +
+	"Subject: Quarterly report\r\n" +
+	"Date: Mon, 2 Mar 2026 09:00:00 +0000\r\n" +
+	"\r\n" +
+	"Numbers attached.\r\n"
+
+func TestWriteRead(t *testing.T) {
+	for _, compression := range []string{CompressZstd, CompressGzip, CompressNone} {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, compression, Index{Account: "me@example.com", Query: "label:reports"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add("msg1", []byte(rawMessage)); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Add("msg2", []byte("Subject: Second\r\n\r\nHi\r\n")); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if w.Count() != 2 {
+				t.Errorf("Count() = %d, want 2", w.Count())
+			}
+
+			r, err := NewReader(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			var ids []string
+			for {
+				id, raw, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if id == "msg1" && string(raw) != rawMessage {
+					t.Errorf("msg1 = %q", raw)
+				}
+				ids = append(ids, id)
+			}
+			if strings.Join(ids, ",") != "msg1,msg2" {
+				t.Errorf("read %v, want msg1 and msg2", ids)
+			}
+			if err := r.Verify(); err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+
+			index := r.Index()
+			if index.Version != IndexVersion || index.Account != "me@example.com" || len(index.Messages) != 2 {
+				t.Fatalf("index = %+v", index)
+			}
+			if e := index.Messages[0]; e.From != "René <rene@example.com>" || e.Subject != "Quarterly report" || e.File != "messages/msg1.eml" || e.Size != len(rawMessage) {
+				t.Errorf("entry = %+v", e)
+			}
+		})
+	}
+}
+
+func TestVerify_Damaged(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range map[string]string{
+		"messages/msg1.eml": "changed",
+		IndexName:           `{"version":1,"messages":[{"id":"msg1","file":"messages/msg1.eml","sha256":"00"},{"id":"msg2","file":"messages/msg2.eml","sha256":"00"}]}`,
+	} {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		_, _ = tw.Write([]byte(data))
+	}
+	_ = tw.Close()
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, _, err := r.Next(); err != nil {
+			break
+		}
+	}
+	err = r.Verify()
+	if err == nil || !strings.Contains(err.Error(), "msg1.eml does not match") || !strings.Contains(err.Error(), "msg2.eml is missing") {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	for name, want := range map[string]string{
+		"backup.tar.zst": CompressZstd,
+		"backup.TAR.GZ":  CompressGzip,
+		"backup.tgz":     CompressGzip,
+		"backup.tar":     CompressNone,
+		"backup":         CompressZstd,
+	} {
+		if got := CompressionFor(name); got != want {
+			t.Errorf("CompressionFor(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got, err := ParseCompression(" ZSTD "); err != nil || got != CompressZstd {
+		t.Errorf("ParseCompression() = %q, %v", got, err)
+	}
+	if _, err := ParseCompression("bzip2"); err == nil {
+		t.Error("expected an error for bzip2")
+	}
+}
+
+func TestReadIndex(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, CompressGzip, Index{Query: "in:inbox"})
+	_ = w.Add("msg1", []byte(rawMessage))
+	_ = w.Close()
+
+	index, err := ReadIndex(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := index.Lookup("msg1")
+	if !ok || !entry.Matches([]byte(rawMessage)) || entry.Matches([]byte("changed")) {
+		t.Errorf("entry = %+v, %v", entry, ok)
+	}
+	if _, ok := index.Lookup("msg2"); ok {
+		t.Error("Lookup found a message not in the archive")
+	}
+
+	if _, err := ReadIndex(strings.NewReader("")); err == nil {
+		t.Error("expected an error for an archive without an index")
+	}
+}
@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// Progress records the messages of an archive already restored, one ID
+// per line, so an interrupted restore can continue where it stopped.
+type Progress struct {
+	path string
+	done map[string]bool
+	file *os.File
+	lock *filelock.Lock
+}
+
+// ProgressKey identifies the restore of the archive with the given
+// manifest into an account under a label.
+func ProgressKey(index *Index, account, label string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		index.Created.UTC().Format(time.RFC3339),
+		index.Account,
+		index.Query,
+		fmt.Sprint(len(index.Messages)),
+		account,
+		label,
+	}, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// OpenProgress opens the progress file at path, holding its lock until
+// Close. With resume, the messages it lists count as restored; otherwise
+// it is emptied.
+func OpenProgress(path string, resume bool) (*Progress, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create progress directory: %w", err)
+	}
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Progress{path: path, done: make(map[string]bool), lock: lock}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if resume {
+		if err := p.load(); err != nil {
+			_ = lock.Unlock()
+			return nil, err
+		}
+	} else {
+		flags |= os.O_TRUNC
+	}
+	if p.file, err = os.OpenFile(path, flags, 0600); err != nil {
+		_ = lock.Unlock()
+		return nil, fmt.Errorf("failed to open progress file: %w", err)
+	}
+	return p, nil
+}
+
+// load reads the IDs already recorded, if any.
+func (p *Progress) load() error {
+	f, err := os.Open(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read progress file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			p.done[id] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read progress file: %w", err)
+	}
+	return nil
+}
+
+// Done reports whether the message was restored by an earlier run.
+func (p *Progress) Done(id string) bool {
+	return p.done[id]
+}
+
+// Count returns the number of messages recorded as restored.
+func (p *Progress) Count() int {
+	return len(p.done)
+}
+
+// Mark records the message as restored.
+func (p *Progress) Mark(id string) error {
+	if _, err := p.file.WriteString(id + "\n"); err != nil {
+		return fmt.Errorf("failed to record progress: %w", err)
+	}
+	p.done[id] = true
+	return nil
+}
+
+// Close closes the progress file and releases its lock. With remove, the
+// file is deleted, as the restore has nothing left to resume.
+func (p *Progress) Close(remove bool) error {
+	err := p.file.Close()
+	if remove && err == nil {
+		if rmErr := os.Remove(p.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			err = rmErr
+		}
+	}
+	_ = p.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to close progress file: %w", err)
+	}
+	return nil
+}
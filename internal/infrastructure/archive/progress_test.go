@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "restore", "abc.progress")
+
+	p, err := OpenProgress(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Mark("m1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Mark("m2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(false); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = OpenProgress(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Done("m1") || !p.Done("m2") || p.Done("m3") || p.Count() != 2 {
+		t.Errorf("resumed progress = %v", p.done)
+	}
+	_ = p.Mark("m3")
+	_ = p.Close(false)
+
+	// Without resume the earlier progress is dropped
+	p, err = OpenProgress(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Count() != 0 {
+		t.Errorf("Count() = %d without resume, want 0", p.Count())
+	}
+	if err := p.Close(true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("progress file still exists: %v", err)
+	}
+}
+
+func TestProgressKey(t *testing.T) {
+	index := &Index{Created: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), Account: "a@example.com", Messages: make([]Entry, 3)}
+	key := ProgressKey(index, "b@example.com", "Restored")
+	if len(key) != 16 || key != ProgressKey(index, "b@example.com", "Restored") {
+		t.Errorf("ProgressKey() = %q", key)
+	}
+	if key == ProgressKey(index, "b@example.com", "Other") || key == ProgressKey(index, "c@example.com", "Restored") {
+		t.Error("ProgressKey() ignores the destination")
+	}
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// KeyAccessToken is the keyring entry holding the account's most recent
+// access token. It is shared by every goog process, so a burst of short
+// commands refreshes the token once instead of once per process. It is kept
+// apart from KeyToken so the long-lived refresh token is not rewritten on
+// every refresh.
+const KeyAccessToken = "oauth_access_token"
+
+// accessTokenMinLifetime is how long a cached access token must still be
+// valid to be handed out, so a command does not start with a token that
+// expires mid-request.
+const accessTokenMinLifetime = time.Minute
+
+// cachedAccessToken is the stored form of a shared access token.
+type cachedAccessToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type,omitempty"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// loadAccessToken returns the account's cached access token, or nil when
+// there is none or it expires within accessTokenMinLifetime.
+func (tm *TokenManager) loadAccessToken(account string) *cachedAccessToken {
+	data, err := tm.store.Get(account, KeyAccessToken)
+	if err != nil {
+		return nil
+	}
+	var cached cachedAccessToken
+	if err := json.Unmarshal(data, &cached); err != nil || cached.AccessToken == "" {
+		return nil
+	}
+	if time.Until(cached.Expiry) < accessTokenMinLifetime {
+		return nil
+	}
+	return &cached
+}
+
+// saveAccessToken shares the access token of token with other processes.
+// Tokens without an expiry are not cached since their lifetime is unknown.
+func (tm *TokenManager) saveAccessToken(account string, token *oauth2.Token) error {
+	if token.AccessToken == "" || token.Expiry.IsZero() {
+		return nil
+	}
+	data, err := json.Marshal(cachedAccessToken{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      token.Expiry,
+	})
+	if err != nil {
+		return err
+	}
+	return tm.store.Set(account, KeyAccessToken, data)
+}
+
+// withCachedAccessToken returns token with its access token replaced by the
+// cached one when the cache holds a token that lives longer.
+func (tm *TokenManager) withCachedAccessToken(account string, token *oauth2.Token) *oauth2.Token {
+	cached := tm.loadAccessToken(account)
+	if cached == nil || (!token.Expiry.IsZero() && !cached.Expiry.After(token.Expiry)) {
+		return token
+	}
+	merged := *token
+	merged.AccessToken = cached.AccessToken
+	merged.Expiry = cached.Expiry
+	if cached.TokenType != "" {
+		merged.TokenType = cached.TokenType
+	}
+	return &merged
+}
+
+// sharedTokenSource refreshes through base only when no other process has
+// cached a fresh access token, and caches the tokens it refreshes.
+type sharedTokenSource struct {
+	tm      *TokenManager
+	account string
+	base    oauth2.TokenSource
+
+	mu   sync.Mutex
+	last string
+}
+
+// Token returns a valid access token, preferring the shared cache.
+func (s *sharedTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached := s.tm.loadAccessToken(s.account); cached != nil {
+		return &oauth2.Token{
+			AccessToken: cached.AccessToken,
+			TokenType:   cached.TokenType,
+			Expiry:      cached.Expiry,
+		}, nil
+	}
+
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken != s.last {
+		// Caching is an optimization; a failed write only costs a refresh
+		_ = s.tm.saveAccessToken(s.account, token)
+		s.last = token.AccessToken
+	}
+	return token, nil
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// countingSource hands out a new token on every call and counts them.
+type countingSource struct {
+	calls int
+}
+
+func (s *countingSource) Token() (*oauth2.Token, error) {
+	s.calls++
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("refreshed-%d", s.calls),
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func TestSharedTokenSource(t *testing.T) {
+	store := newMockStore()
+	account := "work"
+
+	// Two token managers over one store stand in for two processes
+	first := &countingSource{}
+	ts := &sharedTokenSource{tm: NewTokenManager(store), account: account, base: first}
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.AccessToken != "refreshed-1" || first.calls != 1 {
+		t.Fatalf("first process got %q after %d refreshes", token.AccessToken, first.calls)
+	}
+
+	second := &countingSource{}
+	ts2 := &sharedTokenSource{tm: NewTokenManager(store), account: account, base: second}
+	token, err = ts2.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.AccessToken != "refreshed-1" || second.calls != 0 {
+		t.Errorf("second process got %q after %d refreshes, want the cached token", token.AccessToken, second.calls)
+	}
+
+	t.Run("nearly expired tokens are refreshed", func(t *testing.T) {
+		tm := NewTokenManager(store)
+		if err := tm.saveAccessToken(account, &oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(30 * time.Second)}); err != nil {
+			t.Fatal(err)
+		}
+		base := &countingSource{}
+		token, err := (&sharedTokenSource{tm: tm, account: account, base: base}).Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token.AccessToken == "old" || base.calls != 1 {
+			t.Errorf("got %q after %d refreshes, want a refreshed token", token.AccessToken, base.calls)
+		}
+	})
+}
+
+func TestGetTokenSourceUsesSharedAccessToken(t *testing.T) {
+	store := newMockStore()
+	tm := NewTokenManager(store)
+	account := "work"
+
+	expired := &oauth2.Token{
+		AccessToken:  "expired",
+		TokenType:    "Bearer",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Hour),
+	}
+	if err := tm.SaveToken(account, expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.saveAccessToken(account, &oauth2.Token{AccessToken: "shared", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	merged := tm.withCachedAccessToken(account, expired)
+	if merged.AccessToken != "shared" || merged.RefreshToken != "refresh" {
+		t.Errorf("merged token = %+v, want the shared access token and the stored refresh token", merged)
+	}
+
+	// The stored token has expired, so getting a token without the cache
+	// would contact Google's token endpoint
+	ts, err := tm.GetTokenSource(context.Background(), account)
+	if err != nil {
+		t.Fatalf("GetTokenSource failed: %v", err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.AccessToken != "shared" {
+		t.Errorf("access token = %q, want shared", token.AccessToken)
+	}
+
+	if err := tm.DeleteToken(account); err != nil {
+		t.Fatal(err)
+	}
+	if tm.loadAccessToken(account) != nil {
+		t.Error("DeleteToken should remove the shared access token")
+	}
+}
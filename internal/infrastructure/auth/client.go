@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// BundledClientID and BundledClientSecret identify an OAuth client compiled
+// into release builds, e.g.
+//
+//	go build -ldflags "-X github.com/stainedhead/go-goog-cli/internal/infrastructure/auth.BundledClientID=..."
+//
+// They are empty in source builds.
+var (
+	BundledClientID     string
+	BundledClientSecret string
+)
+
+// ClientFileName is the name of the OAuth client file kept next to the
+// config file.
+const ClientFileName = "client_secret.json"
+
+// Sources of OAuth client credentials, in order of precedence.
+const (
+	ClientSourceEnv     = "environment"
+	ClientSourceFile    = "client file"
+	ClientSourceBundled = "bundled"
+)
+
+// ErrInvalidClientFile is returned when a client file is not a Google OAuth
+// client JSON document.
+var ErrInvalidClientFile = errors.New("invalid OAuth client file")
+
+// ClientCredentials is an OAuth client ID and secret.
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// clientFile is the layout of the JSON file downloaded from the Google Cloud
+// Console for desktop ("installed") or web clients.
+type clientFile struct {
+	Installed *clientFileEntry `json:"installed,omitempty"`
+	Web       *clientFileEntry `json:"web,omitempty"`
+}
+
+type clientFileEntry struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	AuthURI      string `json:"auth_uri,omitempty"`
+	TokenURI     string `json:"token_uri,omitempty"`
+}
+
+// ClientFilePath returns the path of the OAuth client file.
+func ClientFilePath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), ClientFileName)
+}
+
+// ParseClientJSON extracts the client credentials from a Google Cloud
+// Console client JSON document.
+func ParseClientJSON(data []byte) (ClientCredentials, error) {
+	var f clientFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return ClientCredentials{}, fmt.Errorf("%w: %v", ErrInvalidClientFile, err)
+	}
+	entry := f.Installed
+	if entry == nil {
+		entry = f.Web
+	}
+	if entry == nil {
+		return ClientCredentials{}, fmt.Errorf("%w: expected an \"installed\" or \"web\" client", ErrInvalidClientFile)
+	}
+	creds := ClientCredentials{
+		ClientID:     strings.TrimSpace(entry.ClientID),
+		ClientSecret: strings.TrimSpace(entry.ClientSecret),
+	}
+	if err := creds.Validate(); err != nil {
+		return ClientCredentials{}, fmt.Errorf("%w: %v", ErrInvalidClientFile, err)
+	}
+	return creds, nil
+}
+
+// Validate checks that both the client ID and secret are present.
+func (c ClientCredentials) Validate() error {
+	if c.ClientID == "" {
+		return ErrMissingClientID
+	}
+	if c.ClientSecret == "" {
+		return ErrMissingClientSecret
+	}
+	return nil
+}
+
+// LoadClientFile reads client credentials from a client JSON file.
+func LoadClientFile(path string) (ClientCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ClientCredentials{}, fmt.Errorf("failed to read client file: %w", err)
+	}
+	return ParseClientJSON(data)
+}
+
+// SaveClientFile writes client credentials as a desktop client JSON file
+// readable only by the owner.
+func SaveClientFile(path string, creds ClientCredentials) error {
+	if err := creds.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(clientFile{Installed: &clientFileEntry{
+		ClientID:     creds.ClientID,
+		ClientSecret: creds.ClientSecret,
+		AuthURI:      "https://accounts.google.com/o/oauth2/auth",
+		TokenURI:     "https://oauth2.googleapis.com/token",
+	}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode client file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := filelock.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write client file: %w", err)
+	}
+	return nil
+}
+
+// ResolveClientCredentials returns the OAuth client to use and where it came
+// from: the GOOG_CLIENT_ID/GOOG_CLIENT_SECRET environment variables, then the
+// client file, then the bundled client. The source is empty when none is
+// configured.
+func ResolveClientCredentials() (ClientCredentials, string) {
+	if id := os.Getenv(EnvClientID); id != "" {
+		return ClientCredentials{ClientID: id, ClientSecret: os.Getenv(EnvClientSecret)}, ClientSourceEnv
+	}
+	if creds, err := LoadClientFile(ClientFilePath()); err == nil {
+		return creds, ClientSourceFile
+	}
+	if BundledClientID != "" {
+		return ClientCredentials{ClientID: BundledClientID, ClientSecret: BundledClientSecret}, ClientSourceBundled
+	}
+	return ClientCredentials{}, ""
+}
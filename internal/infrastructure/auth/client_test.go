@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const testClientJSON = `{"installed":{"client_id":"123.apps.googleusercontent.com","project_id":"goog","client_secret":"s3cret","redirect_uris":["http://localhost"]}}`
+
+// isolateClientConfig points the config (and so the client file) at a temp
+// directory and clears the client environment variables and bundled client.
+func isolateClientConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+	t.Setenv(EnvClientID, "")
+	t.Setenv(EnvClientSecret, "")
+	origID, origSecret := BundledClientID, BundledClientSecret
+	BundledClientID, BundledClientSecret = "", ""
+	t.Cleanup(func() { BundledClientID, BundledClientSecret = origID, origSecret })
+	return dir
+}
+
+func TestParseClientJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "installed client", data: testClientJSON, wantID: "123.apps.googleusercontent.com"},
+		{name: "web client", data: `{"web":{"client_id":"web-id","client_secret":"web-secret"}}`, wantID: "web-id"},
+		{name: "not json", data: "client_id=abc", wantErr: true},
+		{name: "no client section", data: `{"type":"service_account"}`, wantErr: true},
+		{name: "missing secret", data: `{"installed":{"client_id":"abc"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := ParseClientJSON([]byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidClientFile) {
+					t.Fatalf("expected ErrInvalidClientFile, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if creds.ClientID != tt.wantID {
+				t.Errorf("ClientID = %q, want %q", creds.ClientID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoadClientFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", ClientFileName)
+	want := ClientCredentials{ClientID: "id.apps.googleusercontent.com", ClientSecret: "secret"}
+
+	if err := SaveClientFile(path, want); err != nil {
+		t.Fatalf("SaveClientFile failed: %v", err)
+	}
+	got, err := LoadClientFile(path)
+	if err != nil {
+		t.Fatalf("LoadClientFile failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("LoadClientFile() = %+v, want %+v", got, want)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat failed: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("client file permissions = %o, want 600", perm)
+		}
+	}
+
+	if err := SaveClientFile(path, ClientCredentials{ClientID: "only-id"}); !errors.Is(err, ErrMissingClientSecret) {
+		t.Errorf("expected ErrMissingClientSecret, got %v", err)
+	}
+}
+
+func TestResolveClientCredentials(t *testing.T) {
+	t.Run("none configured", func(t *testing.T) {
+		isolateClientConfig(t)
+		if creds, source := ResolveClientCredentials(); source != "" || creds.ClientID != "" {
+			t.Errorf("expected no client, got %+v from %q", creds, source)
+		}
+	})
+
+	t.Run("bundled client", func(t *testing.T) {
+		isolateClientConfig(t)
+		BundledClientID, BundledClientSecret = "bundled-id", "bundled-secret"
+		creds, source := ResolveClientCredentials()
+		if source != ClientSourceBundled || creds.ClientID != "bundled-id" {
+			t.Errorf("got %+v from %q, want bundled client", creds, source)
+		}
+	})
+
+	t.Run("client file takes precedence over bundled", func(t *testing.T) {
+		dir := isolateClientConfig(t)
+		BundledClientID, BundledClientSecret = "bundled-id", "bundled-secret"
+		if err := os.WriteFile(filepath.Join(dir, ClientFileName), []byte(testClientJSON), 0600); err != nil {
+			t.Fatalf("failed to write client file: %v", err)
+		}
+		creds, source := ResolveClientCredentials()
+		if source != ClientSourceFile || creds.ClientID != "123.apps.googleusercontent.com" {
+			t.Errorf("got %+v from %q, want client file", creds, source)
+		}
+	})
+
+	t.Run("environment takes precedence over client file", func(t *testing.T) {
+		dir := isolateClientConfig(t)
+		if err := os.WriteFile(filepath.Join(dir, ClientFileName), []byte(testClientJSON), 0600); err != nil {
+			t.Fatalf("failed to write client file: %v", err)
+		}
+		t.Setenv(EnvClientID, "env-id")
+		t.Setenv(EnvClientSecret, "env-secret")
+		creds, source := ResolveClientCredentials()
+		if source != ClientSourceEnv || creds.ClientID != "env-id" || creds.ClientSecret != "env-secret" {
+			t.Errorf("got %+v from %q, want environment client", creds, source)
+		}
+	})
+
+	t.Run("NewOAuthConfig uses client file", func(t *testing.T) {
+		dir := isolateClientConfig(t)
+		if err := os.WriteFile(filepath.Join(dir, ClientFileName), []byte(testClientJSON), 0600); err != nil {
+			t.Fatalf("failed to write client file: %v", err)
+		}
+		cfg := NewOAuthConfig(nil)
+		if cfg.ClientID != "123.apps.googleusercontent.com" || cfg.ClientSecret != "s3cret" {
+			t.Errorf("NewOAuthConfig() client = %q/%q, want client file values", cfg.ClientID, cfg.ClientSecret)
+		}
+	})
+}
@@ -73,8 +73,8 @@ const (
 
 // Errors returned by the auth package.
 var (
-	ErrMissingClientID     = errors.New("GOOG_CLIENT_ID environment variable is not set")
-	ErrMissingClientSecret = errors.New("GOOG_CLIENT_SECRET environment variable is not set")
+	ErrMissingClientID     = errors.New("OAuth client ID is not configured: run 'goog init' or set GOOG_CLIENT_ID")
+	ErrMissingClientSecret = errors.New("OAuth client secret is not configured: run 'goog init' or set GOOG_CLIENT_SECRET")
 	ErrOAuthError          = errors.New("OAuth error")
 	ErrNoAuthCode          = errors.New("no authorization code received")
 	ErrCallbackTimeout     = errors.New("callback timeout")
@@ -114,9 +114,13 @@ func GenerateCodeChallenge(verifier string) string {
 //   - GOOG_CLIENT_ID: OAuth2 client ID
 //   - GOOG_CLIENT_SECRET: OAuth2 client secret
 //   - GOOG_REDIRECT_PORT: Localhost port for callback (default: 8085)
+//
+// When GOOG_CLIENT_ID is unset, the client file written by "goog init" and
+// then the bundled client are used (see ResolveClientCredentials).
 func NewOAuthConfig(scopes []string) *oauth2.Config {
-	clientID := os.Getenv(EnvClientID)
-	clientSecret := os.Getenv(EnvClientSecret)
+	creds, _ := ResolveClientCredentials()
+	clientID := creds.ClientID
+	clientSecret := creds.ClientSecret
 
 	port := DefaultRedirectPort
 	if portStr := os.Getenv(EnvRedirectPort); portStr != "" {
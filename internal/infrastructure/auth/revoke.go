@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// RevokeURL is Google's OAuth 2.0 token revocation endpoint. It is a
+// variable so tests can point it at a local server.
+var RevokeURL = "https://oauth2.googleapis.com/revoke"
+
+// ErrTokenAlreadyInvalid is returned by RevokeToken when Google no longer
+// accepts the token, because it has expired or was already revoked.
+var ErrTokenAlreadyInvalid = errors.New("token was already expired or revoked")
+
+// RevokeToken revokes token at Google, ending the grant it belongs to. The
+// refresh token is revoked when there is one, since that also invalidates
+// its access tokens.
+func RevokeToken(ctx context.Context, token *oauth2.Token) error {
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+	if value == "" {
+		return ErrTokenNotFound
+	}
+
+	form := url.Values{"token": {value}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, RevokeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revoke request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var oauthErr struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	_ = json.Unmarshal(body, &oauthErr)
+	if oauthErr.Error == "invalid_token" {
+		return ErrTokenAlreadyInvalid
+	}
+	if oauthErr.Error != "" {
+		return fmt.Errorf("failed to revoke token: %s (status %d)", oauthErr.Error, resp.StatusCode)
+	}
+	return fmt.Errorf("failed to revoke token: status %d", resp.StatusCode)
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestRevokeToken(t *testing.T) {
+	var revoked string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		revoked = r.FormValue("token")
+		switch revoked {
+		case "expired":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_token", "error_description": "Token expired or revoked"}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	origURL := RevokeURL
+	RevokeURL = server.URL
+	defer func() { RevokeURL = origURL }()
+	ctx := context.Background()
+
+	t.Run("revokes the refresh token", func(t *testing.T) {
+		if err := RevokeToken(ctx, &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revoked != "refresh" {
+			t.Errorf("revoked %q, want the refresh token", revoked)
+		}
+	})
+
+	t.Run("falls back to the access token", func(t *testing.T) {
+		if err := RevokeToken(ctx, &oauth2.Token{AccessToken: "access"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if revoked != "access" {
+			t.Errorf("revoked %q, want the access token", revoked)
+		}
+	})
+
+	t.Run("reports an invalid token", func(t *testing.T) {
+		if err := RevokeToken(ctx, &oauth2.Token{RefreshToken: "expired"}); !errors.Is(err, ErrTokenAlreadyInvalid) {
+			t.Errorf("expected ErrTokenAlreadyInvalid, got %v", err)
+		}
+	})
+
+	t.Run("reports server errors", func(t *testing.T) {
+		err := RevokeToken(ctx, &oauth2.Token{RefreshToken: "broken"})
+		if err == nil || errors.Is(err, ErrTokenAlreadyInvalid) {
+			t.Errorf("expected a server error, got %v", err)
+		}
+	})
+
+	t.Run("needs a token", func(t *testing.T) {
+		if err := RevokeToken(ctx, &oauth2.Token{}); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("expected ErrTokenNotFound, got %v", err)
+		}
+	})
+}
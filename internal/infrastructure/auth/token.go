@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 
 	"golang.org/x/oauth2"
 )
@@ -92,9 +93,34 @@ func (tm *TokenManager) DeleteToken(account string) error {
 		return fmt.Errorf("failed to delete scopes: %w", err)
 	}
 
+	// And the shared access token
+	if err := tm.store.Delete(account, KeyAccessToken); err != nil && !isKeyNotFoundError(err) {
+		return fmt.Errorf("failed to delete access token: %w", err)
+	}
+
 	return nil
 }
 
+// DeleteAll removes every keyring entry stored for the given account, not
+// only the token and scopes, and returns the sorted names of the entries
+// it deleted.
+func (tm *TokenManager) DeleteAll(account string) ([]string, error) {
+	keys, err := tm.store.List(account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keyring entries: %w", err)
+	}
+	sort.Strings(keys)
+
+	deleted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := tm.store.Delete(account, key); err != nil && !isKeyNotFoundError(err) {
+			return deleted, fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+		deleted = append(deleted, key)
+	}
+	return deleted, nil
+}
+
 // RefreshToken refreshes an expired OAuth2 token for the given account.
 // It loads the existing token, refreshes it using the provided config, and saves the new token.
 func (tm *TokenManager) RefreshToken(ctx context.Context, account string, cfg *oauth2.Config) (*oauth2.Token, error) {
@@ -125,12 +151,15 @@ func (tm *TokenManager) RefreshToken(ctx context.Context, account string, cfg *o
 
 // GetTokenSource returns an oauth2.TokenSource for the given account.
 // The token source will automatically refresh the token when it expires.
+// Access tokens are shared with other processes through the keyring (see
+// KeyAccessToken), so only one of them needs to refresh.
 func (tm *TokenManager) GetTokenSource(ctx context.Context, account string) (oauth2.TokenSource, error) {
-	// Load the token
+	// Load the token, with the newest access token any process obtained
 	token, err := tm.LoadToken(account)
 	if err != nil {
 		return nil, err
 	}
+	token = tm.withCachedAccessToken(account, token)
 
 	// Load scopes to create the config
 	scopes, err := tm.GetGrantedScopes(account)
@@ -143,8 +172,14 @@ func (tm *TokenManager) GetTokenSource(ctx context.Context, account string) (oau
 	// Create OAuth config
 	cfg := NewOAuthConfig(scopes)
 
-	// Create a reusable token source that auto-refreshes
-	ts := cfg.TokenSource(ctx, token)
+	// Create a reusable token source that auto-refreshes and shares the
+	// tokens it refreshes
+	ts := &sharedTokenSource{
+		tm:      tm,
+		account: account,
+		base:    cfg.TokenSource(ctx, token),
+		last:    token.AccessToken,
+	}
 
 	// Wrap in a ReuseTokenSource for efficiency
 	return oauth2.ReuseTokenSource(token, ts), nil
@@ -1053,3 +1053,33 @@ func TestRefreshTokenWhenTokenUnchanged(t *testing.T) {
 		t.Errorf("expected access token 'same-access-token', got %q", newToken.AccessToken)
 	}
 }
+
+func TestDeleteAll(t *testing.T) {
+	store := newMockStore()
+	manager := NewTokenManager(store)
+	_ = store.Set("work", KeyToken, []byte(`{}`))
+	_ = store.Set("work", KeyScopes, []byte(`[]`))
+	_ = store.Set("work", "imap_password", []byte("secret"))
+	_ = store.Set("personal", KeyToken, []byte(`{}`))
+
+	deleted, err := manager.DeleteAll("work")
+	if err != nil {
+		t.Fatalf("DeleteAll failed: %v", err)
+	}
+	want := []string{"imap_password", KeyScopes, KeyToken}
+	if len(deleted) != len(want) {
+		t.Fatalf("deleted %v, want %v", deleted, want)
+	}
+	for i := range want {
+		if deleted[i] != want[i] {
+			t.Errorf("deleted %v, want %v", deleted, want)
+			break
+		}
+	}
+	if keys, _ := store.List("work"); len(keys) != 0 {
+		t.Errorf("entries left for work: %v", keys)
+	}
+	if keys, _ := store.List("personal"); len(keys) != 1 {
+		t.Errorf("other accounts should be kept, got %v", keys)
+	}
+}
@@ -0,0 +1,115 @@
+// Package calwatch keeps what 'goog cal watch' last saw of each watched
+// calendar: the sync token to ask for changes since, and a snapshot of
+// the events to compare the changes with.
+package calwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the watch state file kept next to the config
+// file.
+const FileName = "cal_watch.json"
+
+// Path returns the path of the watch state file.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// State is what was last seen of one watched calendar.
+type State struct {
+	Account    string `json:"account"`
+	CalendarID string `json:"calendar_id"`
+	// SyncToken asks the Calendar API for the changes since the last run.
+	SyncToken string    `json:"sync_token"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Events are snapshots of the calendar's events, keyed by ID.
+	Events map[string]*calendar.Event `json:"events"`
+}
+
+// load reads the state file at path. A missing file holds no state.
+func load(path string) ([]*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar watch state: %w", err)
+	}
+	var states []*State
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar watch state: %w", err)
+	}
+	return states, nil
+}
+
+// Load returns the state of the account's calendar in the file at path,
+// or nil when it has not been watched.
+func Load(path, account, calendarID string) (*State, error) {
+	states, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range states {
+		if s.Account == account && s.CalendarID == calendarID {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// Save stores state in the file at path, replacing the state of the same
+// account and calendar.
+func Save(path string, state *State) error {
+	return update(path, state.Account, state.CalendarID, state)
+}
+
+// Remove drops the state of the account's calendar from the file at path.
+func Remove(path, account, calendarID string) error {
+	return update(path, account, calendarID, nil)
+}
+
+// update replaces the state of the account's calendar in the file at path
+// with state, or removes it when state is nil.
+func update(path, account, calendarID string, state *State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create calendar watch state directory: %w", err)
+	}
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	states, err := load(path)
+	if err != nil {
+		return err
+	}
+	kept := []*State{}
+	for _, s := range states {
+		if s.Account != account || s.CalendarID != calendarID {
+			kept = append(kept, s)
+		}
+	}
+	if state != nil {
+		kept = append(kept, state)
+	}
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return fmt.Errorf("failed to encode calendar watch state: %w", err)
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write calendar watch state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package calwatch
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+func TestSaveLoadRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	if s, err := Load(path, "me@example.com", "primary"); s != nil || err != nil {
+		t.Fatalf("Load() of a missing file = %v, %v; want nothing", s, err)
+	}
+
+	for _, calendarID := range []string{"primary", "team@group.calendar.google.com"} {
+		state := &State{
+			Account:    "me@example.com",
+			CalendarID: calendarID,
+			SyncToken:  "token-" + calendarID,
+			CheckedAt:  start,
+			Events:     map[string]*calendar.Event{"ev1": {ID: "ev1", Title: "Standup", Start: start}},
+		}
+		if err := Save(path, state); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := Save(path, &State{Account: "me@example.com", CalendarID: "primary", SyncToken: "token-2"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := Load(path, "me@example.com", "primary")
+	if err != nil || got == nil || got.SyncToken != "token-2" {
+		t.Fatalf("Load(primary) = %+v, %v; want the replaced state", got, err)
+	}
+	team, err := Load(path, "me@example.com", "team@group.calendar.google.com")
+	if err != nil || team == nil || !team.Events["ev1"].Start.Equal(start) {
+		t.Fatalf("Load(team) = %+v, %v", team, err)
+	}
+
+	if err := Remove(path, "me@example.com", "primary"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if s, _ := Load(path, "me@example.com", "primary"); s != nil {
+		t.Errorf("state still there after Remove: %+v", s)
+	}
+	if s, _ := Load(path, "me@example.com", "team@group.calendar.google.com"); s == nil {
+		t.Error("Remove dropped another calendar's state")
+	}
+}
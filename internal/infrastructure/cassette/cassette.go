@@ -0,0 +1,339 @@
+// Package cassette records Google API interactions to a file and replays
+// them, so scripts that drive goog can be tested without reaching Google.
+//
+// A Recorder is an http.RoundTripper that forwards requests and appends each
+// request/response pair to a cassette file with credentials scrubbed. A
+// Replayer serves responses from a cassette and never touches the network.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// Version is the cassette file format version.
+const Version = 1
+
+// Redacted replaces scrubbed values in recorded interactions.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned by a Replayer for requests that are not in
+// the cassette.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// Cassette is the on-disk format of a recording.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Account      string        `json:"account,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded part of an HTTP request.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// recordedHeaders are the response headers kept in a cassette.
+var recordedHeaders = []string{"Content-Type"}
+
+// secretParams are query parameters and JSON keys whose values are scrubbed.
+var secretParams = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_secret": true,
+	"key":           true,
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s", c.Version, path)
+	}
+	return &c, nil
+}
+
+// Recorder is an http.RoundTripper that records interactions to a cassette
+// file. Several goog processes may record to the same file; each interaction
+// is appended under a file lock.
+type Recorder struct {
+	path    string
+	account string
+	base    http.RoundTripper
+	mu      sync.Mutex
+}
+
+// NewRecorder creates a Recorder that appends to the cassette at path and
+// sends requests with base, or http.DefaultTransport when base is nil.
+// account is stored in the cassette so replays act as the same address.
+func NewRecorder(path, account string, base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{path: path, account: account, base: base}
+}
+
+// RoundTrip sends the request and records it with its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    scrubURL(req.URL),
+			Body:   scrubBody(reqBody),
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: recordHeader(resp.Header),
+			Body:   scrubBody(respBody),
+		},
+	}
+	if err := r.append(interaction); err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+	return resp, nil
+}
+
+// append adds an interaction to the cassette file.
+func (r *Recorder) append(interaction Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, err := filelock.Acquire(r.path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	c := &Cassette{Version: Version}
+	if _, err := os.Stat(r.path); err == nil {
+		if c, err = Load(r.path); err != nil {
+			return err
+		}
+	}
+	if c.Account == "" {
+		c.Account = r.account
+	}
+	c.Interactions = append(c.Interactions, interaction)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return filelock.WriteFile(r.path, data, 0600)
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette.
+type Replayer struct {
+	cassette *Cassette
+	mu       sync.Mutex
+	used     []bool
+}
+
+// NewReplayer loads the cassette at path for replay.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{cassette: c, used: make([]bool, len(c.Interactions))}, nil
+}
+
+// Account returns the email address the cassette was recorded as.
+func (r *Replayer) Account() string {
+	return r.cassette.Account
+}
+
+// RoundTrip returns the recorded response for the request. Interactions are
+// matched by method and URL, in recorded order; when no unused interaction
+// has the exact URL, one for the same path is used so that queries built
+// from the current time still replay.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	target := scrubURL(req.URL)
+	i := r.find(req.Method, func(u string) bool { return u == target })
+	if i < 0 {
+		path := urlPath(target)
+		i = r.find(req.Method, func(u string) bool { return urlPath(u) == path })
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, target)
+	}
+	r.used[i] = true
+
+	recorded := r.cassette.Interactions[i].Response
+	header := make(http.Header)
+	for k, v := range recorded.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// find returns the first unused interaction with the method whose URL
+// satisfies match, or -1.
+func (r *Replayer) find(method string, match func(string) bool) int {
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] && in.Request.Method == method && match(in.Request.URL) {
+			return i
+		}
+	}
+	return -1
+}
+
+// readBody reads and replaces *body so it can be read again.
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+// recordHeader returns the response headers kept in a cassette.
+func recordHeader(h http.Header) map[string]string {
+	out := make(map[string]string)
+	for _, name := range recordedHeaders {
+		if v := h.Get(name); v != "" {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// scrubURL returns u as a string with secret query parameters redacted.
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	query := scrubbed.Query()
+	changed := false
+	for name := range query {
+		if secretParams[name] {
+			query.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if changed {
+		scrubbed.RawQuery = query.Encode()
+	}
+	return scrubbed.String()
+}
+
+// urlPath returns the URL without its query.
+func urlPath(raw string) string {
+	if i := strings.IndexByte(raw, '?'); i >= 0 {
+		return raw[:i]
+	}
+	return raw
+}
+
+// scrubBody redacts secret values in a JSON body. Other bodies are
+// returned unchanged.
+func scrubBody(body string) string {
+	if body == "" {
+		return body
+	}
+	var v any
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	if !scrubValue(v) {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+// scrubValue redacts secret keys in decoded JSON, reporting whether it
+// changed anything.
+func scrubValue(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if secretParams[k] {
+				v[k] = Redacted
+				changed = true
+				continue
+			}
+			if scrubValue(child) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if scrubValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -0,0 +1,226 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newAPIServer returns a server that echoes the request path and sets a
+// cookie and a token in its response.
+func newAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`","access_token":"ya29.secret"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func record(t *testing.T, rec *Recorder, method, url, body string) string {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer ya29.secret")
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func replay(t *testing.T, rep *Replayer, method, url string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rep.RoundTrip(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data), nil
+}
+
+func TestRecorder(t *testing.T) {
+	srv := newAPIServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec := NewRecorder(path, "me@example.com", nil)
+	body := record(t, rec, http.MethodGet, srv.URL+"/gmail/v1/users/me/messages?key=apikey&q=is:unread", "")
+	if !strings.Contains(body, "ya29.secret") {
+		t.Errorf("caller should see the real response, got %s", body)
+	}
+	record(t, rec, http.MethodPost, srv.URL+"/token", `{"refresh_token":"1//secret","grant_type":"refresh_token"}`)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("cassette contains a secret:\n%s", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("cassette permissions = %o, want 600", perm)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c.Account != "me@example.com" || len(c.Interactions) != 2 {
+		t.Fatalf("unexpected cassette: %+v", c)
+	}
+	first := c.Interactions[0]
+	if !strings.Contains(first.Request.URL, "key="+Redacted) || !strings.Contains(first.Request.URL, "q=is%3Aunread") {
+		t.Errorf("unexpected URL %q", first.Request.URL)
+	}
+	if first.Response.Status != http.StatusOK || first.Response.Header["Content-Type"] != "application/json" {
+		t.Errorf("unexpected response: %+v", first.Response)
+	}
+	if _, ok := first.Response.Header["Set-Cookie"]; ok {
+		t.Error("Set-Cookie should not be recorded")
+	}
+	if !strings.Contains(c.Interactions[1].Request.Body, `"grant_type":"refresh_token"`) {
+		t.Errorf("non-secret request fields should be kept: %s", c.Interactions[1].Request.Body)
+	}
+}
+
+func TestRecorderAppendsAcrossRecorders(t *testing.T) {
+	srv := newAPIServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	record(t, NewRecorder(path, "me@example.com", nil), http.MethodGet, srv.URL+"/one", "")
+	record(t, NewRecorder(path, "other@example.com", nil), http.MethodGet, srv.URL+"/two", "")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(c.Interactions) != 2 {
+		t.Fatalf("got %d interactions, want 2", len(c.Interactions))
+	}
+	if c.Account != "me@example.com" {
+		t.Errorf("Account = %q, want the first recorder's", c.Account)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	srv := newAPIServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec := NewRecorder(path, "me@example.com", nil)
+	record(t, rec, http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?timeMin=2026-01-01", "")
+	record(t, rec, http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?timeMin=2026-01-02", "")
+	record(t, rec, http.MethodDelete, srv.URL+"/tasks/v1/lists/1", "")
+	srv.Close()
+
+	rep, err := NewReplayer(path)
+	if err != nil {
+		t.Fatalf("NewReplayer failed: %v", err)
+	}
+	if rep.Account() != "me@example.com" {
+		t.Errorf("Account() = %q", rep.Account())
+	}
+
+	t.Run("exact match is preferred", func(t *testing.T) {
+		resp, body, err := replay(t, rep, http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?timeMin=2026-01-02")
+		if err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected response: %d %v", resp.StatusCode, resp.Header)
+		}
+		if !strings.Contains(body, "/calendar/v3/calendars/primary/events") {
+			t.Errorf("unexpected body %s", body)
+		}
+	})
+
+	t.Run("falls back to the same path", func(t *testing.T) {
+		if _, _, err := replay(t, rep, http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?timeMin=2026-10-16"); err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+	})
+
+	t.Run("interactions are used once", func(t *testing.T) {
+		_, _, err := replay(t, rep, http.MethodGet, srv.URL+"/calendar/v3/calendars/primary/events?timeMin=2026-01-01")
+		if !errors.Is(err, ErrNoInteraction) {
+			t.Errorf("expected ErrNoInteraction, got %v", err)
+		}
+	})
+
+	t.Run("method must match", func(t *testing.T) {
+		_, _, err := replay(t, rep, http.MethodGet, srv.URL+"/tasks/v1/lists/1")
+		if !errors.Is(err, ErrNoInteraction) {
+			t.Errorf("expected ErrNoInteraction, got %v", err)
+		}
+		if _, _, err := replay(t, rep, http.MethodDelete, srv.URL+"/tasks/v1/lists/1"); err != nil {
+			t.Errorf("RoundTrip failed: %v", err)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing cassette")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(bad); err == nil {
+		t.Error("expected error for invalid cassette")
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"version":99,"interactions":[]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(future); err == nil || !strings.Contains(err.Error(), "unsupported cassette version") {
+		t.Errorf("expected version error, got %v", err)
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"empty", "", ""},
+		{"not json", "raw=data", "raw=data"},
+		{"nothing to scrub", `{"id": "1"}`, `{"id": "1"}`},
+		{"nested", `{"items":[{"client_secret":"s","id":"1"}]}`, `{"items":[{"client_secret":"REDACTED","id":"1"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrubBody(tt.in); got != tt.want {
+				t.Errorf("scrubBody(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
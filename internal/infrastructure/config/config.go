@@ -4,21 +4,32 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	netmail "net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration.
 type Config struct {
-	// DefaultAccount is the email of the default Google account to use.
+	// SchemaVersion is the config file format. Older files are migrated
+	// when loaded, so a loaded config always has the current version.
+	SchemaVersion int `yaml:"schema_version" mapstructure:"schema_version"`
+
+	// DefaultAccount is the alias of the default Google account to use.
 	DefaultAccount string `yaml:"default_account" mapstructure:"default_account"`
 
 	// DefaultFormat specifies the default output format (json|plain|table).
@@ -35,6 +46,29 @@ type Config struct {
 
 	// Calendar contains calendar-specific settings.
 	Calendar CalendarConfig `yaml:"calendar" mapstructure:"calendar"`
+
+	// Display contains date, time and size formatting settings.
+	Display DisplayConfig `yaml:"display" mapstructure:"display"`
+
+	// Keyring contains credential storage settings.
+	Keyring KeyringConfig `yaml:"keyring" mapstructure:"keyring"`
+
+	// Metrics contains opt-in local usage metrics settings.
+	Metrics MetricsConfig `yaml:"metrics" mapstructure:"metrics"`
+
+	// History contains command history settings.
+	History HistoryConfig `yaml:"history" mapstructure:"history"`
+
+	// API contains settings for calls to Google's APIs.
+	API APIConfig `yaml:"api" mapstructure:"api"`
+
+	// Aliases maps user-defined command names to the goog command lines
+	// they expand to, e.g. inbox: "mail list --labels INBOX --unread-only".
+	Aliases map[string]string `yaml:"aliases" mapstructure:"aliases"`
+
+	// Settings are per-command flag defaults such as
+	// mail.list.max_results; see Resolver.
+	Settings Settings `yaml:"settings,omitempty" mapstructure:"settings"`
 }
 
 // AccountConfig represents configuration for a single Google account.
@@ -47,6 +81,101 @@ type AccountConfig struct {
 
 	// AddedAt is the timestamp when the account was added.
 	AddedAt time.Time `yaml:"added_at" mapstructure:"added_at"`
+
+	// ReadOnly blocks changes to the account's mail, calendars, tasks and
+	// contacts, as if --read-only were always given.
+	ReadOnly bool `yaml:"read_only,omitempty" mapstructure:"read_only"`
+
+	// Commands lists the commands the account was authorized for with
+	// "goog auth login --for". Granted scopes beyond what they need are
+	// reported by "goog auth status".
+	Commands []string `yaml:"commands,omitempty" mapstructure:"commands"`
+
+	// GmailEndpoint, CalendarEndpoint, TasksEndpoint and PeopleEndpoint
+	// replace Google's base URL for the service, e.g. to use a mock server
+	// or an API gateway. Empty means Google's endpoint.
+	GmailEndpoint    string `yaml:"gmail_endpoint,omitempty" mapstructure:"gmail_endpoint"`
+	CalendarEndpoint string `yaml:"calendar_endpoint,omitempty" mapstructure:"calendar_endpoint"`
+	TasksEndpoint    string `yaml:"tasks_endpoint,omitempty" mapstructure:"tasks_endpoint"`
+	PeopleEndpoint   string `yaml:"people_endpoint,omitempty" mapstructure:"people_endpoint"`
+
+	// LabelMap translates labels on messages copied into this account with
+	// "mail copy". Each entry has the form "Source=Target"; an empty
+	// target drops the label, and "Source/*" entries cover nested labels.
+	LabelMap []string `yaml:"label_map,omitempty" mapstructure:"label_map"`
+
+	// Settings are per-command flag defaults used while this account is
+	// in use. They take precedence over the global settings.
+	Settings Settings `yaml:"settings,omitempty" mapstructure:"settings"`
+
+	// Metadata holds user-defined key/value pairs describing the account,
+	// such as role: work, for templates and rule conditions.
+	Metadata map[string]string `yaml:"metadata,omitempty" mapstructure:"metadata"`
+}
+
+// LabelMapping returns the account's label map keyed by source label name.
+func (a AccountConfig) LabelMapping() map[string]string {
+	mapping := make(map[string]string, len(a.LabelMap))
+	for _, entry := range a.LabelMap {
+		if from, to, ok := strings.Cut(entry, "="); ok {
+			mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+		}
+	}
+	return mapping
+}
+
+// ParseLabelMap splits a comma-separated list of "Source=Target" label
+// mappings, as given to "config set accounts.<alias>.label_map", into
+// entries.
+func ParseLabelMap(value string) ([]string, error) {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(entry, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" {
+			return nil, fmt.Errorf("invalid label mapping %q: use Source=Target", entry)
+		}
+		entries = append(entries, from+"="+to)
+	}
+	return entries, nil
+}
+
+// Endpoints returns the account's endpoint overrides keyed by service name
+// (gmail, calendar, tasks, people). Services without an override are left
+// out.
+func (a AccountConfig) Endpoints() map[string]string {
+	endpoints := make(map[string]string)
+	for service, endpoint := range map[string]string{
+		"gmail":    a.GmailEndpoint,
+		"calendar": a.CalendarEndpoint,
+		"tasks":    a.TasksEndpoint,
+		"people":   a.PeopleEndpoint,
+	} {
+		if endpoint != "" {
+			endpoints[service] = endpoint
+		}
+	}
+	return endpoints
+}
+
+// endpointField returns the endpoint override named by a per-account field
+// such as gmail_endpoint.
+func (a *AccountConfig) endpointField(field string) (*string, bool) {
+	switch field {
+	case "gmail_endpoint":
+		return &a.GmailEndpoint, true
+	case "calendar_endpoint":
+		return &a.CalendarEndpoint, true
+	case "tasks_endpoint":
+		return &a.TasksEndpoint, true
+	case "people_endpoint":
+		return &a.PeopleEndpoint, true
+	}
+	return nil, false
 }
 
 // MailConfig contains mail-specific settings.
@@ -56,6 +185,144 @@ type MailConfig struct {
 
 	// PageSize is the default number of messages to fetch per page.
 	PageSize int `yaml:"page_size" mapstructure:"page_size"`
+
+	// TodoLabel is the label applied by "mail todo add".
+	TodoLabel string `yaml:"todo_label" mapstructure:"todo_label"`
+
+	// DoneLabel is the label applied by "mail todo done". Empty means the
+	// todo label is only removed.
+	DoneLabel string `yaml:"done_label" mapstructure:"done_label"`
+
+	// MuteLabel is the label "mail mute" marks muted threads with.
+	MuteLabel string `yaml:"mute_label" mapstructure:"mute_label"`
+
+	// ReadLaterDir is the directory "mail readlater" saves notes to when
+	// --dest is not given.
+	ReadLaterDir string `yaml:"readlater_dir,omitempty" mapstructure:"readlater_dir"`
+
+	// TranslateAPIKey is the Google Cloud Translation API key used by
+	// "mail show --translate". GOOG_TRANSLATE_API_KEY takes precedence.
+	TranslateAPIKey string `yaml:"translate_api_key,omitempty" mapstructure:"translate_api_key"`
+
+	// SummarizeCommand is the shell command "thread summarize" pipes a
+	// thread to. It takes precedence over SummarizeURL.
+	SummarizeCommand string `yaml:"summarize_command,omitempty" mapstructure:"summarize_command"`
+
+	// SummarizeURL is the HTTP endpoint "thread summarize" posts a thread to.
+	SummarizeURL string `yaml:"summarize_url,omitempty" mapstructure:"summarize_url"`
+
+	// CheckMX makes sending commands look up the mail servers of every
+	// recipient domain before sending.
+	CheckMX bool `yaml:"check_mx,omitempty" mapstructure:"check_mx"`
+
+	// QueueOffline makes sending commands queue a message in the outbox
+	// when it cannot be sent because the network is unavailable.
+	QueueOffline bool `yaml:"queue_offline,omitempty" mapstructure:"queue_offline"`
+
+	// Headers are extra headers, as "Name: value", added to every message
+	// sent with send, reply or forward.
+	Headers []string `yaml:"headers,omitempty" mapstructure:"headers"`
+
+	// Throttle is the minimum time, such as "10m", between two messages
+	// sending commands send to the same recipient. Empty or zero means
+	// mail is not throttled.
+	Throttle string `yaml:"throttle,omitempty" mapstructure:"throttle"`
+
+	// ThrottleOverrides replace Throttle for an address or a domain, as
+	// "address=duration" or "@domain=duration"; zero exempts them.
+	ThrottleOverrides []string `yaml:"throttle_overrides,omitempty" mapstructure:"throttle_overrides"`
+
+	// DuplicateWindow is how far back, such as "1h", mail send looks in
+	// the sent mail for the same message before sending it again. Empty
+	// or zero means the check is off.
+	DuplicateWindow string `yaml:"duplicate_window,omitempty" mapstructure:"duplicate_window"`
+
+	// ReplyQuote places the quoted original in replies: "top" puts the
+	// reply above it, "bottom" below it. Empty or "none" leaves it out.
+	ReplyQuote string `yaml:"reply_quote,omitempty" mapstructure:"reply_quote"`
+
+	// QuotePrefix starts each quoted line of a reply; empty means "> ".
+	QuotePrefix string `yaml:"quote_prefix,omitempty" mapstructure:"quote_prefix"`
+
+	// AutoBcc are addresses, such as a CRM dropbox, blind-copied on every
+	// message the sending commands send.
+	AutoBcc []string `yaml:"auto_bcc,omitempty" mapstructure:"auto_bcc"`
+
+	// SearchCacheTTL is how long, such as "2m", "mail search" reuses the
+	// results of an identical search. Empty or zero turns the cache off.
+	SearchCacheTTL string `yaml:"search_cache_ttl,omitempty" mapstructure:"search_cache_ttl"`
+
+	// Views are named Gmail searches, such as "newsletters", used by
+	// "mail digest --view". They add to or replace the built-in views.
+	Views map[string]string `yaml:"views,omitempty" mapstructure:"views"`
+
+	// Groups are named lists of addresses, such as "team-platform", that
+	// sending commands expand when a recipient is "group:<name>". They take
+	// precedence over Contacts groups of the same name.
+	Groups map[string][]string `yaml:"groups,omitempty" mapstructure:"groups"`
+}
+
+// ParseHeaderList splits a semicolon-separated list of "Name: value"
+// headers, as given to "config set mail.headers", into entries. Only the
+// form is checked here; header names and values are validated when a
+// message is sent.
+func ParseHeaderList(value string) ([]string, error) {
+	var entries []string
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(entry, ":")
+		name, val = strings.TrimSpace(name), strings.TrimSpace(val)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q: use \"Name: value\"", entry)
+		}
+		entries = append(entries, name+": "+val)
+	}
+	return entries, nil
+}
+
+// ParseAddressList splits a comma-separated list of addresses, as given
+// to "config set mail.auto_bcc", dropping display names.
+func ParseAddressList(value string) ([]string, error) {
+	var addrs []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		addr, err := netmail.ParseAddress(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", entry, err)
+		}
+		addrs = append(addrs, addr.Address)
+	}
+	return addrs, nil
+}
+
+// ParseThrottleOverrideList splits a comma-separated list of throttle
+// overrides, as given to "config set mail.throttle_overrides", into
+// entries of the form "target=duration". Targets are checked to be an
+// address or "@domain" and durations to parse; a zero duration is allowed.
+func ParseThrottleOverrideList(value string) ([]string, error) {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		target, val, ok := strings.Cut(entry, "=")
+		target, val = strings.ToLower(strings.TrimSpace(target)), strings.TrimSpace(val)
+		if !ok || strings.TrimPrefix(target, "@") == "" || !strings.Contains(target, "@") {
+			return nil, fmt.Errorf("invalid throttle override %q: use \"address=duration\" or \"@domain=duration\"", entry)
+		}
+		if d, err := time.ParseDuration(val); err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid throttle override %q: duration must be like 10m or 0", entry)
+		}
+		entries = append(entries, target+"="+val)
+	}
+	return entries, nil
 }
 
 // CalendarConfig contains calendar-specific settings.
@@ -65,23 +332,105 @@ type CalendarConfig struct {
 
 	// WeekStart specifies the first day of the week (sunday|monday).
 	WeekStart string `yaml:"week_start" mapstructure:"week_start"`
+
+	// TravelCheck warns when creating or accepting an event leaves too
+	// little time to travel from or to a neighbouring event.
+	TravelCheck bool `yaml:"travel_check,omitempty" mapstructure:"travel_check"`
+
+	// TravelCommand estimates travel time between two locations; see
+	// the travel package. Empty means a fixed back-to-back threshold.
+	TravelCommand string `yaml:"travel_command,omitempty" mapstructure:"travel_command"`
+}
+
+// DisplayConfig controls how dates, times and sizes appear in table and
+// plain output.
+type DisplayConfig struct {
+	// DateFormat is a preset (iso|us|eu|de) or a Go layout.
+	DateFormat string `yaml:"date_format" mapstructure:"date_format"`
+
+	// TimeFormat is 24h, 12h or a Go layout.
+	TimeFormat string `yaml:"time_format" mapstructure:"time_format"`
+
+	// SizeUnits selects binary (iec) or decimal (si) byte units.
+	SizeUnits string `yaml:"size_units" mapstructure:"size_units"`
+
+	// A11y turns on accessible output, as --a11y does.
+	A11y bool `yaml:"a11y,omitempty" mapstructure:"a11y"`
+}
+
+// KeyringConfig contains credential storage settings.
+type KeyringConfig struct {
+	// Backend selects where tokens are stored
+	// (auto|keychain|secret-service|wincred|file).
+	Backend string `yaml:"backend" mapstructure:"backend"`
+
+	// Protector binds the encrypted token files to a hardware key:
+	// ssh-agent, ssh-agent:<fingerprint> or plugin:<name>. Empty for none.
+	Protector string `yaml:"protector" mapstructure:"protector"`
+}
+
+// MetricsConfig contains opt-in local usage metrics settings.
+type MetricsConfig struct {
+	// Enabled records command latency, API calls, retries and cache hits
+	// in a metrics file next to the config file.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+
+	// Textfile is a path the metrics are also written to after each
+	// command, in the Prometheus text format. Empty disables it.
+	Textfile string `yaml:"textfile" mapstructure:"textfile"`
+}
+
+// HistoryConfig contains command history settings.
+type HistoryConfig struct {
+	// Enabled records each command, with content redacted, in a history
+	// file next to the config file.
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// APIConfig contains settings for calls to Google's APIs.
+type APIConfig struct {
+	// RetryBudget is how many retries after rate limits and temporary
+	// errors one command may make in total, across all its API calls.
+	// Zero means no limit.
+	RetryBudget int `yaml:"retry_budget" mapstructure:"retry_budget"`
 }
 
+// DefaultRetryBudget is the default api.retry_budget.
+const DefaultRetryBudget = 10
+
 // NewConfig creates a new Config with default values.
 func NewConfig() *Config {
 	return &Config{
+		SchemaVersion:  SchemaVersion,
 		DefaultAccount: "",
 		DefaultFormat:  "table",
 		Timezone:       "Local",
 		Accounts:       make(map[string]AccountConfig),
+		Aliases:        make(map[string]string),
 		Mail: MailConfig{
 			DefaultLabel: "INBOX",
 			PageSize:     20,
+			TodoLabel:    "todo",
+			MuteLabel:    "Muted",
 		},
 		Calendar: CalendarConfig{
 			DefaultCalendar: "primary",
 			WeekStart:       "sunday",
 		},
+		Display: DisplayConfig{
+			DateFormat: "iso",
+			TimeFormat: "24h",
+			SizeUnits:  "iec",
+		},
+		Keyring: KeyringConfig{
+			Backend: "auto",
+		},
+		History: HistoryConfig{
+			Enabled: true,
+		},
+		API: APIConfig{
+			RetryBudget: DefaultRetryBudget,
+		},
 	}
 }
 
@@ -163,13 +512,26 @@ func Load() (*Config, error) {
 	v.SetDefault("accounts", make(map[string]AccountConfig))
 	v.SetDefault("mail.default_label", "INBOX")
 	v.SetDefault("mail.page_size", 20)
+	v.SetDefault("mail.todo_label", "todo")
+	v.SetDefault("mail.done_label", "")
+	v.SetDefault("mail.mute_label", "Muted")
 	v.SetDefault("calendar.default_calendar", "primary")
 	v.SetDefault("calendar.week_start", "sunday")
+	v.SetDefault("display.date_format", "iso")
+	v.SetDefault("display.time_format", "24h")
+	v.SetDefault("display.size_units", "iec")
+	v.SetDefault("keyring.backend", "auto")
+	v.SetDefault("metrics.enabled", false)
+	v.SetDefault("metrics.textfile", "")
+	v.SetDefault("history.enabled", true)
+	v.SetDefault("api.retry_budget", DefaultRetryBudget)
+	v.SetDefault("aliases", make(map[string]string))
 
-	// Read config file if it exists
+	// Read config file if it exists, upgrading older formats in memory;
+	// the file itself is rewritten by the next Save or "config migrate"
 	if configExists {
-		if err := v.ReadInConfig(); err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+		if err := readMigrated(v, configPath); err != nil {
+			return nil, err
 		}
 	}
 
@@ -192,10 +554,13 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// Ensure accounts map is initialized
+	// Ensure accounts and aliases maps are initialized
 	if cfg.Accounts == nil {
 		cfg.Accounts = make(map[string]AccountConfig)
 	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
 
 	// If config didn't exist, save the default
 	if !configExists {
@@ -207,10 +572,43 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// readMigrated reads the config file at configPath into v after applying
+// any pending schema migrations.
+func readMigrated(v *viper.Viper, configPath string) error {
+	doc, err := readDocument(configPath)
+	if err != nil {
+		// Let viper report unreadable files as before
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		return nil
+	}
+	if _, _, err := migrateDocument(doc); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return nil
+}
+
+// ErrConfigLocked is returned by Save when another goog process holds the
+// config lock for longer than the lock timeout.
+var ErrConfigLocked = errors.New("config is locked by another goog process")
+
+// lockTimeout is how long Save waits for the config lock.
+var lockTimeout = filelock.DefaultTimeout
+
 // Save writes the configuration to the config file.
 // It creates the config directory if it doesn't exist and
 // creates the file with secure permissions (0600) from the start to avoid
 // race conditions where the file could be read before permissions are set.
+// The write holds the config lock and replaces the file atomically, so
+// concurrent goog processes never see or produce a partial file.
 func (c *Config) Save() error {
 	configPath := GetConfigPath()
 	configDir := filepath.Dir(configPath)
@@ -226,12 +624,36 @@ func (c *Config) Save() error {
 	v.SetConfigType("yaml")
 
 	// Set values from config struct
+	v.Set("schema_version", SchemaVersion)
 	v.Set("default_account", c.DefaultAccount)
 	v.Set("default_format", c.DefaultFormat)
 	v.Set("timezone", c.Timezone)
 	v.Set("accounts", c.Accounts)
 	v.Set("mail", c.Mail)
 	v.Set("calendar", c.Calendar)
+	v.Set("display", c.Display)
+	v.Set("keyring", c.Keyring)
+	v.Set("metrics", c.Metrics)
+	v.Set("history", c.History)
+	v.Set("api", c.API)
+	v.Set("aliases", c.Aliases)
+	if len(c.Settings) > 0 {
+		v.Set("settings", c.Settings)
+	}
+
+	lock, err := filelock.Acquire(configPath, lockTimeout)
+	if err != nil {
+		if errors.Is(err, filelock.ErrLocked) {
+			return fmt.Errorf("%w (%s): try again", ErrConfigLocked, filelock.LockPath(configPath))
+		}
+		return err
+	}
+	defer lock.Unlock()
+
+	// Keep a copy of a file in an older format before replacing it
+	if err := backupBeforeSave(configPath); err != nil {
+		return err
+	}
 
 	// Write config securely to avoid race condition
 	if err := writeConfigSecurely(configPath, v); err != nil {
@@ -243,44 +665,20 @@ func (c *Config) Save() error {
 
 // writeConfigSecurely writes the viper configuration to a file with secure
 // permissions (0600) from the start. This avoids the race condition where
-// the file is created with default permissions and then chmod'd.
+// the file is created with default permissions and then chmod'd. The file
+// is written to a temporary file and renamed into place.
 func writeConfigSecurely(configPath string, v *viper.Viper) error {
-	// On Windows, just use viper's default behavior
-	if runtime.GOOS == "windows" {
-		if err := v.WriteConfig(); err != nil {
-			if os.IsNotExist(err) {
-				return v.SafeWriteConfig()
-			}
-			return err
-		}
-		return nil
-	}
-
-	// For Unix-like systems, create file with secure permissions first
-	// Use O_CREATE|O_WRONLY|O_TRUNC to create or truncate the file
-	f, err := os.OpenFile(configPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to create config file: %w", err)
-	}
-
 	// Get the configuration as YAML using viper's AllSettings
 	settings := v.AllSettings()
 	yamlData, err := marshalYAML(settings)
 	if err != nil {
-		f.Close()
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Write to the file
-	if _, err := f.Write(yamlData); err != nil {
-		f.Close()
+	if err := filelock.WriteFile(configPath, yamlData, 0600); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
-	if err := f.Close(); err != nil {
-		return fmt.Errorf("failed to close config file: %w", err)
-	}
-
 	return nil
 }
 
@@ -324,8 +722,133 @@ var validFormats = map[string]bool{
 	"table": true,
 }
 
+// validKeyringBackends lists the valid keyring backend options.
+var validKeyringBackends = map[string]bool{
+	"auto":           true,
+	"keychain":       true,
+	"secret-service": true,
+	"wincred":        true,
+	"file":           true,
+}
+
+// validKeyringProtector reports whether value names a token file
+// protector. The keyring package checks the details when it is used.
+func validKeyringProtector(value string) bool {
+	if value == "" || value == "ssh-agent" {
+		return true
+	}
+	if fingerprint, ok := strings.CutPrefix(value, "ssh-agent:"); ok {
+		return fingerprint != ""
+	}
+	name, ok := strings.CutPrefix(value, "plugin:")
+	return ok && name != ""
+}
+
+// accountKey splits a per-account key of the form accounts.<alias>.<field>.
+func accountKey(key string) (alias, field string, ok bool) {
+	rest, found := strings.CutPrefix(key, "accounts.")
+	if !found {
+		return "", "", false
+	}
+	i := strings.LastIndexByte(rest, '.')
+	if i <= 0 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
+
+// viewKey returns the view name of a key of the form mail.views.<name>.
+func viewKey(key string) (name string, ok bool) {
+	name, ok = strings.CutPrefix(key, "mail.views.")
+	return name, ok
+}
+
+// groupKey returns the group name of a key of the form mail.groups.<name>.
+func groupKey(key string) (name string, ok bool) {
+	name, ok = strings.CutPrefix(key, "mail.groups.")
+	return name, ok
+}
+
+// validViewName reports whether name can be used as a view or group name:
+// lowercase letters, digits, hyphens and underscores.
+func validViewName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// setView sets the query of a named view; an empty query removes it.
+func (c *Config) setView(name, query string) error {
+	if !validViewName(name) {
+		return fmt.Errorf("invalid view name %q: use lowercase letters, digits, hyphens and underscores", name)
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		delete(c.Mail.Views, name)
+		if len(c.Mail.Views) == 0 {
+			c.Mail.Views = nil
+		}
+		return nil
+	}
+	if c.Mail.Views == nil {
+		c.Mail.Views = make(map[string]string)
+	}
+	c.Mail.Views[name] = query
+	return nil
+}
+
+// setGroup sets the addresses of a recipient group from a comma-separated
+// list, dropping display names; an empty list removes the group.
+func (c *Config) setGroup(name, value string) error {
+	if !validViewName(name) {
+		return fmt.Errorf("invalid group name %q: use lowercase letters, digits, hyphens and underscores", name)
+	}
+	var members []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		addr, err := netmail.ParseAddress(entry)
+		if err != nil {
+			return fmt.Errorf("invalid address %q in group %s: %w", entry, name, err)
+		}
+		members = append(members, addr.Address)
+	}
+	if len(members) == 0 {
+		delete(c.Mail.Groups, name)
+		if len(c.Mail.Groups) == 0 {
+			c.Mail.Groups = nil
+		}
+		return nil
+	}
+	if c.Mail.Groups == nil {
+		c.Mail.Groups = make(map[string][]string)
+	}
+	c.Mail.Groups[name] = members
+	return nil
+}
+
 // SetValue sets a configuration value by key path (e.g., "mail.page_size").
 func (c *Config) SetValue(key, value string) error {
+	if alias, name, ok := metadataKey(key); ok {
+		return c.SetAccountMetadata(alias, name, value)
+	}
+	if alias, field, ok := accountKey(key); ok {
+		return c.setAccountValue(alias, field, value)
+	}
+	if name, ok := viewKey(key); ok {
+		return c.setView(name, value)
+	}
+	if name, ok := groupKey(key); ok {
+		return c.setGroup(name, value)
+	}
 	switch key {
 	case "default_account":
 		c.DefaultAccount = value
@@ -350,18 +873,254 @@ func (c *Config) SetValue(key, value string) error {
 			return fmt.Errorf("invalid page_size: %w", err)
 		}
 		c.Mail.PageSize = pageSize
+	case "mail.todo_label":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("todo_label cannot be empty")
+		}
+		c.Mail.TodoLabel = value
+	case "mail.done_label":
+		c.Mail.DoneLabel = value
+	case "mail.mute_label":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("mute_label cannot be empty")
+		}
+		c.Mail.MuteLabel = value
+	case "mail.readlater_dir":
+		c.Mail.ReadLaterDir = value
+	case "mail.translate_api_key":
+		c.Mail.TranslateAPIKey = value
+	case "mail.summarize_command":
+		c.Mail.SummarizeCommand = value
+	case "mail.summarize_url":
+		c.Mail.SummarizeURL = value
+	case "mail.check_mx":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid mail.check_mx %q: must be true or false", value)
+		}
+		c.Mail.CheckMX = enabled
+	case "mail.queue_offline":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid mail.queue_offline %q: must be true or false", value)
+		}
+		c.Mail.QueueOffline = enabled
+	case "mail.headers":
+		entries, err := ParseHeaderList(value)
+		if err != nil {
+			return err
+		}
+		c.Mail.Headers = entries
+	case "mail.throttle":
+		value = strings.TrimSpace(value)
+		if value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("invalid mail.throttle %q: must be a duration like 10m, or 0 to disable", value)
+			}
+		}
+		c.Mail.Throttle = value
+	case "mail.throttle_overrides":
+		entries, err := ParseThrottleOverrideList(value)
+		if err != nil {
+			return err
+		}
+		c.Mail.ThrottleOverrides = entries
+	case "mail.duplicate_window":
+		value = strings.TrimSpace(value)
+		if value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("invalid mail.duplicate_window %q: must be a duration like 1h, or 0 to disable", value)
+			}
+		}
+		c.Mail.DuplicateWindow = value
+	case "mail.reply_quote":
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch value {
+		case "", "none", "top", "bottom":
+		default:
+			return fmt.Errorf("invalid mail.reply_quote %q: must be top, bottom or none", value)
+		}
+		c.Mail.ReplyQuote = value
+	case "mail.quote_prefix":
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid mail.quote_prefix %q: must be a single line", value)
+		}
+		c.Mail.QuotePrefix = value
+	case "mail.auto_bcc":
+		addrs, err := ParseAddressList(value)
+		if err != nil {
+			return fmt.Errorf("invalid mail.auto_bcc: %w", err)
+		}
+		c.Mail.AutoBcc = addrs
+	case "mail.search_cache_ttl":
+		value = strings.TrimSpace(value)
+		if value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return fmt.Errorf("invalid mail.search_cache_ttl %q: must be a duration like 2m, or 0 to disable", value)
+			}
+		}
+		c.Mail.SearchCacheTTL = value
 	case "calendar.default_calendar":
 		c.Calendar.DefaultCalendar = value
 	case "calendar.week_start":
 		c.Calendar.WeekStart = value
+	case "calendar.travel_check":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid calendar.travel_check %q: must be true or false", value)
+		}
+		c.Calendar.TravelCheck = enabled
+	case "calendar.travel_command":
+		c.Calendar.TravelCommand = value
+	case "display.date_format":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("date_format cannot be empty")
+		}
+		c.Display.DateFormat = value
+	case "display.time_format":
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("time_format cannot be empty")
+		}
+		c.Display.TimeFormat = value
+	case "display.size_units":
+		if value != "iec" && value != "si" {
+			return fmt.Errorf("invalid size_units %q: must be iec or si", value)
+		}
+		c.Display.SizeUnits = value
+	case "display.a11y":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid display.a11y %q: must be true or false", value)
+		}
+		c.Display.A11y = enabled
+	case "keyring.backend":
+		if !validKeyringBackends[value] {
+			return fmt.Errorf("invalid keyring backend %q: must be one of auto, keychain, secret-service, wincred, file", value)
+		}
+		c.Keyring.Backend = value
+	case "keyring.protector":
+		if !validKeyringProtector(value) {
+			return fmt.Errorf("invalid keyring protector %q: must be ssh-agent, ssh-agent:<fingerprint>, plugin:<name> or empty", value)
+		}
+		c.Keyring.Protector = value
+	case "metrics.enabled":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid metrics.enabled %q: must be true or false", value)
+		}
+		c.Metrics.Enabled = enabled
+	case "metrics.textfile":
+		c.Metrics.Textfile = value
+	case "history.enabled":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid history.enabled %q: must be true or false", value)
+		}
+		c.History.Enabled = enabled
+	case "api.retry_budget":
+		budget, err := strconv.Atoi(value)
+		if err != nil || budget < 0 {
+			return fmt.Errorf("invalid api.retry_budget %q: must be a number of retries, 0 for no limit", value)
+		}
+		c.API.RetryBudget = budget
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
 	return nil
 }
 
+// setAccountValue sets a per-account value.
+func (c *Config) setAccountValue(alias, field, value string) error {
+	acc, ok := c.Accounts[alias]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, alias)
+	}
+	switch field {
+	case "read_only":
+		readOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid read_only %q: must be true or false", value)
+		}
+		acc.ReadOnly = readOnly
+	case "label_map":
+		entries, err := ParseLabelMap(value)
+		if err != nil {
+			return err
+		}
+		acc.LabelMap = entries
+	default:
+		endpoint, ok := acc.endpointField(field)
+		if !ok {
+			return fmt.Errorf("unknown config key: accounts.%s.%s", alias, field)
+		}
+		if err := validateEndpoint(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", field, err)
+		}
+		*endpoint = value
+	}
+	c.Accounts[alias] = acc
+	return nil
+}
+
+// validateEndpoint checks that an endpoint override is empty or an absolute
+// http or https URL.
+func validateEndpoint(value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q must be an http or https URL, e.g. https://gateway.example.com/gmail/", value)
+	}
+	return nil
+}
+
 // GetValue retrieves a configuration value by key path.
 func (c *Config) GetValue(key string) (string, error) {
+	if alias, name, ok := metadataKey(key); ok {
+		acc, err := c.GetAccount(alias)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, alias)
+		}
+		value, found := acc.Metadata[strings.ToLower(name)]
+		if !found {
+			return "", fmt.Errorf("account %s has no metadata %s", alias, name)
+		}
+		return value, nil
+	}
+	if alias, field, ok := accountKey(key); ok {
+		acc, err := c.GetAccount(alias)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", err, alias)
+		}
+		switch field {
+		case "read_only":
+			return strconv.FormatBool(acc.ReadOnly), nil
+		case "label_map":
+			return strings.Join(acc.LabelMap, ","), nil
+		}
+		if endpoint, ok := acc.endpointField(field); ok {
+			return *endpoint, nil
+		}
+		return "", fmt.Errorf("unknown config key: %s", key)
+	}
+	if name, ok := viewKey(key); ok {
+		query, found := c.Mail.Views[name]
+		if !found {
+			return "", fmt.Errorf("unknown view: %s", name)
+		}
+		return query, nil
+	}
+	if name, ok := groupKey(key); ok {
+		members, found := c.Mail.Groups[name]
+		if !found {
+			return "", fmt.Errorf("unknown group: %s", name)
+		}
+		return strings.Join(members, ","), nil
+	}
 	switch key {
 	case "default_account":
 		return c.DefaultAccount, nil
@@ -373,10 +1132,68 @@ func (c *Config) GetValue(key string) (string, error) {
 		return c.Mail.DefaultLabel, nil
 	case "mail.page_size":
 		return fmt.Sprintf("%d", c.Mail.PageSize), nil
+	case "mail.todo_label":
+		return c.Mail.TodoLabel, nil
+	case "mail.done_label":
+		return c.Mail.DoneLabel, nil
+	case "mail.mute_label":
+		return c.Mail.MuteLabel, nil
+	case "mail.readlater_dir":
+		return c.Mail.ReadLaterDir, nil
+	case "mail.translate_api_key":
+		return c.Mail.TranslateAPIKey, nil
+	case "mail.summarize_command":
+		return c.Mail.SummarizeCommand, nil
+	case "mail.summarize_url":
+		return c.Mail.SummarizeURL, nil
+	case "mail.check_mx":
+		return strconv.FormatBool(c.Mail.CheckMX), nil
+	case "mail.queue_offline":
+		return strconv.FormatBool(c.Mail.QueueOffline), nil
+	case "mail.headers":
+		return strings.Join(c.Mail.Headers, "; "), nil
+	case "mail.throttle":
+		return c.Mail.Throttle, nil
+	case "mail.throttle_overrides":
+		return strings.Join(c.Mail.ThrottleOverrides, ", "), nil
+	case "mail.duplicate_window":
+		return c.Mail.DuplicateWindow, nil
+	case "mail.reply_quote":
+		return c.Mail.ReplyQuote, nil
+	case "mail.quote_prefix":
+		return c.Mail.QuotePrefix, nil
+	case "mail.auto_bcc":
+		return strings.Join(c.Mail.AutoBcc, ", "), nil
+	case "mail.search_cache_ttl":
+		return c.Mail.SearchCacheTTL, nil
 	case "calendar.default_calendar":
 		return c.Calendar.DefaultCalendar, nil
 	case "calendar.week_start":
 		return c.Calendar.WeekStart, nil
+	case "calendar.travel_check":
+		return strconv.FormatBool(c.Calendar.TravelCheck), nil
+	case "calendar.travel_command":
+		return c.Calendar.TravelCommand, nil
+	case "display.date_format":
+		return c.Display.DateFormat, nil
+	case "display.time_format":
+		return c.Display.TimeFormat, nil
+	case "display.size_units":
+		return c.Display.SizeUnits, nil
+	case "display.a11y":
+		return strconv.FormatBool(c.Display.A11y), nil
+	case "keyring.backend":
+		return c.Keyring.Backend, nil
+	case "keyring.protector":
+		return c.Keyring.Protector, nil
+	case "metrics.enabled":
+		return strconv.FormatBool(c.Metrics.Enabled), nil
+	case "metrics.textfile":
+		return c.Metrics.Textfile, nil
+	case "history.enabled":
+		return strconv.FormatBool(c.History.Enabled), nil
+	case "api.retry_budget":
+		return strconv.Itoa(c.API.RetryBudget), nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
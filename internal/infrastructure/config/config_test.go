@@ -3,11 +3,15 @@ package config
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
 )
 
 func TestConfigDefaults(t *testing.T) {
@@ -47,6 +51,15 @@ func TestConfigDefaults(t *testing.T) {
 		if cfg.Mail.PageSize != 20 {
 			t.Errorf("expected mail page_size 20, got %d", cfg.Mail.PageSize)
 		}
+		if cfg.Mail.TodoLabel != "todo" {
+			t.Errorf("expected mail todo_label 'todo', got %q", cfg.Mail.TodoLabel)
+		}
+		if cfg.Mail.DoneLabel != "" {
+			t.Errorf("expected empty mail done_label, got %q", cfg.Mail.DoneLabel)
+		}
+		if cfg.Mail.MuteLabel != "Muted" {
+			t.Errorf("expected mail mute_label 'Muted', got %q", cfg.Mail.MuteLabel)
+		}
 	})
 
 	t.Run("calendar defaults", func(t *testing.T) {
@@ -57,6 +70,30 @@ func TestConfigDefaults(t *testing.T) {
 			t.Errorf("expected calendar week_start 'sunday', got %q", cfg.Calendar.WeekStart)
 		}
 	})
+
+	t.Run("display defaults", func(t *testing.T) {
+		if cfg.Display.DateFormat != "iso" {
+			t.Errorf("expected display date_format 'iso', got %q", cfg.Display.DateFormat)
+		}
+		if cfg.Display.TimeFormat != "24h" {
+			t.Errorf("expected display time_format '24h', got %q", cfg.Display.TimeFormat)
+		}
+		if cfg.Display.SizeUnits != "iec" {
+			t.Errorf("expected display size_units 'iec', got %q", cfg.Display.SizeUnits)
+		}
+	})
+
+	t.Run("history defaults", func(t *testing.T) {
+		if !cfg.History.Enabled {
+			t.Error("expected history to be enabled by default")
+		}
+	})
+
+	t.Run("keyring defaults", func(t *testing.T) {
+		if cfg.Keyring.Backend != "auto" {
+			t.Errorf("expected keyring backend 'auto', got %q", cfg.Keyring.Backend)
+		}
+	})
 }
 
 func TestConfigPlatformPaths(t *testing.T) {
@@ -321,6 +358,24 @@ func TestConfigSave(t *testing.T) {
 		}
 	})
 
+	t.Run("save and load aliases", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+		cfg := NewConfig()
+		cfg.Aliases["inbox"] = "mail list --labels INBOX --unread-only --max-results 50"
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+
+		loaded, err := Load()
+		if err != nil {
+			t.Fatalf("failed to load saved config: %v", err)
+		}
+		if got := loaded.Aliases["inbox"]; got != "mail list --labels INBOX --unread-only --max-results 50" {
+			t.Errorf("expected alias to round-trip, got %q", got)
+		}
+	})
+
 	t.Run("save creates directory if not exists", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "subdir", "goog", "config.yaml")
@@ -462,6 +517,55 @@ func TestSetValueValid(t *testing.T) {
 				return cfg.Mail.PageSize == 50
 			},
 		},
+		{
+			key:   "mail.todo_label",
+			value: "Action",
+			validate: func() bool {
+				return cfg.Mail.TodoLabel == "Action"
+			},
+		},
+		{
+			key:   "mail.done_label",
+			value: "Done",
+			validate: func() bool {
+				return cfg.Mail.DoneLabel == "Done"
+			},
+		},
+		{
+			key:   "mail.mute_label",
+			value: "Quiet",
+			validate: func() bool {
+				return cfg.Mail.MuteLabel == "Quiet"
+			},
+		},
+		{
+			key:   "mail.readlater_dir",
+			value: "~/notes/inbox",
+			validate: func() bool {
+				return cfg.Mail.ReadLaterDir == "~/notes/inbox"
+			},
+		},
+		{
+			key:   "mail.translate_api_key",
+			value: "AIza-test",
+			validate: func() bool {
+				return cfg.Mail.TranslateAPIKey == "AIza-test"
+			},
+		},
+		{
+			key:   "mail.summarize_command",
+			value: "llm -s 'Summarize'",
+			validate: func() bool {
+				return cfg.Mail.SummarizeCommand == "llm -s 'Summarize'"
+			},
+		},
+		{
+			key:   "mail.summarize_url",
+			value: "http://localhost:8080/summarize",
+			validate: func() bool {
+				return cfg.Mail.SummarizeURL == "http://localhost:8080/summarize"
+			},
+		},
 		{
 			key:   "calendar.default_calendar",
 			value: "work",
@@ -476,6 +580,97 @@ func TestSetValueValid(t *testing.T) {
 				return cfg.Calendar.WeekStart == "monday"
 			},
 		},
+		{
+			key:   "mail.check_mx",
+			value: "true",
+			validate: func() bool {
+				return cfg.Mail.CheckMX
+			},
+		},
+		{
+			key:   "mail.queue_offline",
+			value: "true",
+			validate: func() bool {
+				return cfg.Mail.QueueOffline
+			},
+		},
+		{
+			key:   "calendar.travel_check",
+			value: "true",
+			validate: func() bool {
+				return cfg.Calendar.TravelCheck
+			},
+		},
+		{
+			key:   "calendar.travel_command",
+			value: "travel-time --mode transit",
+			validate: func() bool {
+				return cfg.Calendar.TravelCommand == "travel-time --mode transit"
+			},
+		},
+		{
+			key:   "display.date_format",
+			value: "eu",
+			validate: func() bool {
+				return cfg.Display.DateFormat == "eu"
+			},
+		},
+		{
+			key:   "display.time_format",
+			value: "12h",
+			validate: func() bool {
+				return cfg.Display.TimeFormat == "12h"
+			},
+		},
+		{
+			key:   "display.size_units",
+			value: "si",
+			validate: func() bool {
+				return cfg.Display.SizeUnits == "si"
+			},
+		},
+		{
+			key:   "keyring.backend",
+			value: "file",
+			validate: func() bool {
+				return cfg.Keyring.Backend == "file"
+			},
+		},
+		{
+			key:   "keyring.protector",
+			value: "plugin:yubikey",
+			validate: func() bool {
+				return cfg.Keyring.Protector == "plugin:yubikey"
+			},
+		},
+		{
+			key:   "metrics.enabled",
+			value: "true",
+			validate: func() bool {
+				return cfg.Metrics.Enabled
+			},
+		},
+		{
+			key:   "metrics.textfile",
+			value: "/var/lib/node_exporter/goog.prom",
+			validate: func() bool {
+				return cfg.Metrics.Textfile == "/var/lib/node_exporter/goog.prom"
+			},
+		},
+		{
+			key:   "history.enabled",
+			value: "false",
+			validate: func() bool {
+				return !cfg.History.Enabled
+			},
+		},
+		{
+			key:   "api.retry_budget",
+			value: "25",
+			validate: func() bool {
+				return cfg.API.RetryBudget == 25
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -508,6 +703,87 @@ func TestSetValueInvalid(t *testing.T) {
 			t.Error("expected error for invalid page_size")
 		}
 	})
+
+	t.Run("empty todo_label returns error", func(t *testing.T) {
+		err := cfg.SetValue("mail.todo_label", " ")
+		if err == nil {
+			t.Error("expected error for empty todo_label")
+		}
+	})
+
+	t.Run("empty mute_label returns error", func(t *testing.T) {
+		if err := cfg.SetValue("mail.mute_label", ""); err == nil {
+			t.Error("expected error for empty mute_label")
+		}
+	})
+
+	t.Run("empty date_format returns error", func(t *testing.T) {
+		if err := cfg.SetValue("display.date_format", ""); err == nil {
+			t.Error("expected error for empty date_format")
+		}
+	})
+
+	t.Run("empty time_format returns error", func(t *testing.T) {
+		if err := cfg.SetValue("display.time_format", " "); err == nil {
+			t.Error("expected error for empty time_format")
+		}
+	})
+
+	t.Run("invalid size_units returns error", func(t *testing.T) {
+		if err := cfg.SetValue("display.size_units", "metric"); err == nil {
+			t.Error("expected error for invalid size_units")
+		}
+	})
+
+	t.Run("invalid a11y returns error", func(t *testing.T) {
+		if err := cfg.SetValue("display.a11y", "sometimes"); err == nil {
+			t.Error("expected error for invalid a11y")
+		}
+	})
+
+	t.Run("invalid keyring backend returns error", func(t *testing.T) {
+		if err := cfg.SetValue("keyring.backend", "kwallet"); err == nil {
+			t.Error("expected error for invalid keyring backend")
+		}
+	})
+
+	t.Run("invalid keyring protector returns error", func(t *testing.T) {
+		for _, value := range []string{"tpm", "plugin:", "ssh-agent:"} {
+			if err := cfg.SetValue("keyring.protector", value); err == nil {
+				t.Errorf("expected error for keyring protector %q", value)
+			}
+		}
+	})
+
+	t.Run("invalid mail.check_mx returns error", func(t *testing.T) {
+		if err := cfg.SetValue("mail.check_mx", "yes please"); err == nil {
+			t.Error("expected error for invalid mail.check_mx")
+		}
+	})
+
+	t.Run("invalid mail.queue_offline returns error", func(t *testing.T) {
+		if err := cfg.SetValue("mail.queue_offline", "sometimes"); err == nil {
+			t.Error("expected error for invalid mail.queue_offline")
+		}
+	})
+
+	t.Run("invalid calendar.travel_check returns error", func(t *testing.T) {
+		if err := cfg.SetValue("calendar.travel_check", "maybe"); err == nil {
+			t.Error("expected error for invalid calendar.travel_check")
+		}
+	})
+
+	t.Run("invalid metrics.enabled returns error", func(t *testing.T) {
+		if err := cfg.SetValue("metrics.enabled", "sometimes"); err == nil {
+			t.Error("expected error for invalid metrics.enabled")
+		}
+	})
+
+	t.Run("negative api.retry_budget returns error", func(t *testing.T) {
+		if err := cfg.SetValue("api.retry_budget", "-1"); err == nil {
+			t.Error("expected error for negative api.retry_budget")
+		}
+	})
 }
 
 // TestGetValueAll tests GetValue for all config keys.
@@ -518,8 +794,26 @@ func TestGetValueAll(t *testing.T) {
 	cfg.Timezone = "UTC"
 	cfg.Mail.DefaultLabel = "INBOX"
 	cfg.Mail.PageSize = 25
+	cfg.Mail.TodoLabel = "todo"
+	cfg.Mail.DoneLabel = "done"
+	cfg.Mail.ReadLaterDir = "~/notes"
+	cfg.Mail.TranslateAPIKey = "AIza-test"
+	cfg.Mail.SummarizeCommand = "summarize"
+	cfg.Mail.SummarizeURL = "http://localhost/summarize"
+	cfg.Mail.CheckMX = true
+	cfg.Mail.QueueOffline = true
 	cfg.Calendar.DefaultCalendar = "work"
 	cfg.Calendar.WeekStart = "monday"
+	cfg.Calendar.TravelCheck = true
+	cfg.Calendar.TravelCommand = "travel-time"
+	cfg.Display.DateFormat = "us"
+	cfg.Display.TimeFormat = "12h"
+	cfg.Display.SizeUnits = "si"
+	cfg.Display.A11y = true
+	cfg.Keyring.Backend = "file"
+	cfg.Keyring.Protector = "ssh-agent"
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.Textfile = "goog.prom"
 
 	testCases := []struct {
 		key      string
@@ -530,8 +824,29 @@ func TestGetValueAll(t *testing.T) {
 		{"timezone", "UTC"},
 		{"mail.default_label", "INBOX"},
 		{"mail.page_size", "25"},
+		{"mail.todo_label", "todo"},
+		{"mail.done_label", "done"},
+		{"mail.mute_label", "Muted"},
+		{"mail.readlater_dir", "~/notes"},
+		{"mail.translate_api_key", "AIza-test"},
+		{"mail.summarize_command", "summarize"},
+		{"mail.summarize_url", "http://localhost/summarize"},
+		{"mail.check_mx", "true"},
+		{"mail.queue_offline", "true"},
 		{"calendar.default_calendar", "work"},
 		{"calendar.week_start", "monday"},
+		{"calendar.travel_check", "true"},
+		{"calendar.travel_command", "travel-time"},
+		{"display.date_format", "us"},
+		{"display.time_format", "12h"},
+		{"display.size_units", "si"},
+		{"display.a11y", "true"},
+		{"keyring.backend", "file"},
+		{"keyring.protector", "ssh-agent"},
+		{"metrics.enabled", "true"},
+		{"metrics.textfile", "goog.prom"},
+		{"history.enabled", "true"},
+		{"api.retry_budget", "10"},
 	}
 
 	for _, tc := range testCases {
@@ -1074,10 +1389,16 @@ func TestConfigSaveAndReload(t *testing.T) {
 	cfg.Timezone = "America/Chicago"
 	cfg.Mail.PageSize = 75
 	cfg.Calendar.WeekStart = "monday"
+	cfg.Display.DateFormat = "Jan 2, 2006"
+	cfg.Display.TimeFormat = "12h"
+	cfg.API.RetryBudget = 4
 	cfg.Accounts["cycle@example.com"] = AccountConfig{
-		Email:   "cycle@example.com",
-		Scopes:  []string{"gmail.readonly", "calendar"},
-		AddedAt: time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC),
+		Email:    "cycle@example.com",
+		Scopes:   []string{"gmail.readonly", "calendar"},
+		AddedAt:  time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC),
+		ReadOnly: true,
+
+		GmailEndpoint: "https://gateway.example.com/gmail/",
 	}
 
 	// Save
@@ -1104,9 +1425,15 @@ func TestConfigSaveAndReload(t *testing.T) {
 	if loaded.Mail.PageSize != 75 {
 		t.Errorf("Mail.PageSize = %d, want 75", loaded.Mail.PageSize)
 	}
+	if loaded.API.RetryBudget != 4 {
+		t.Errorf("API.RetryBudget = %d, want 4", loaded.API.RetryBudget)
+	}
 	if loaded.Calendar.WeekStart != "monday" {
 		t.Errorf("Calendar.WeekStart = %q, want 'monday'", loaded.Calendar.WeekStart)
 	}
+	if loaded.Display.DateFormat != "Jan 2, 2006" || loaded.Display.TimeFormat != "12h" || loaded.Display.SizeUnits != "iec" {
+		t.Errorf("Display = %+v, want date_format 'Jan 2, 2006', time_format '12h', size_units 'iec'", loaded.Display)
+	}
 
 	acc, ok := loaded.Accounts["cycle@example.com"]
 	if !ok {
@@ -1115,6 +1442,12 @@ func TestConfigSaveAndReload(t *testing.T) {
 	if len(acc.Scopes) != 2 {
 		t.Errorf("len(Scopes) = %d, want 2", len(acc.Scopes))
 	}
+	if !acc.ReadOnly {
+		t.Error("ReadOnly = false, want true")
+	}
+	if acc.GmailEndpoint != "https://gateway.example.com/gmail/" {
+		t.Errorf("GmailEndpoint = %q", acc.GmailEndpoint)
+	}
 }
 
 // TestGetConfigPathDarwin tests GetConfigPath on macOS.
@@ -2949,3 +3282,405 @@ func TestConfigFullRoundTrip(t *testing.T) {
 		t.Errorf("work.Email = %q, want 'work@company.com'", work.Email)
 	}
 }
+
+// TestSaveWhileLocked tests that Save reports a held config lock.
+func TestSaveWhileLocked(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", configPath)
+
+	origTimeout := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = origTimeout })
+
+	lock, err := filelock.Acquire(configPath, time.Second)
+	if err != nil {
+		t.Fatalf("failed to take config lock: %v", err)
+	}
+
+	cfg := NewConfig()
+	err = cfg.Save()
+	if !errors.Is(err, ErrConfigLocked) {
+		t.Fatalf("Save() = %v, want ErrConfigLocked", err)
+	}
+	if !strings.Contains(err.Error(), "config is locked") {
+		t.Errorf("error should say the config is locked, got %q", err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("failed to release config lock: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Errorf("Save() after unlock failed: %v", err)
+	}
+}
+
+// TestSaveReplacesAtomically tests that Save leaves no temporary files.
+func TestSaveReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOOG_CONFIG", filepath.Join(dir, "config.yaml"))
+
+	cfg := NewConfig()
+	for _, format := range []string{"json", "plain"} {
+		cfg.DefaultFormat = format
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read config dir: %v", err)
+	}
+	for _, e := range entries {
+		if e.Name() != "config.yaml" && e.Name() != "config.yaml.lock" {
+			t.Errorf("unexpected file left in config dir: %s", e.Name())
+		}
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.DefaultFormat != "plain" {
+		t.Errorf("DefaultFormat = %q, want plain", loaded.DefaultFormat)
+	}
+}
+
+// TestAccountReadOnlyValue tests the accounts.<alias>.read_only key.
+func TestAccountReadOnlyValue(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Accounts["work"] = AccountConfig{Email: "work@example.com"}
+	cfg.Accounts["my.team"] = AccountConfig{Email: "team@example.com"}
+
+	if err := cfg.SetValue("accounts.work.read_only", "true"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if !cfg.Accounts["work"].ReadOnly || cfg.Accounts["work"].Email != "work@example.com" {
+		t.Errorf("unexpected account: %+v", cfg.Accounts["work"])
+	}
+	if got, err := cfg.GetValue("accounts.work.read_only"); err != nil || got != "true" {
+		t.Errorf("GetValue() = %q, %v; want true", got, err)
+	}
+
+	t.Run("alias containing a dot", func(t *testing.T) {
+		if err := cfg.SetValue("accounts.my.team.read_only", "yes"); err == nil {
+			t.Error("expected error for invalid bool")
+		}
+		if err := cfg.SetValue("accounts.my.team.read_only", "1"); err != nil {
+			t.Fatalf("SetValue failed: %v", err)
+		}
+		if !cfg.Accounts["my.team"].ReadOnly {
+			t.Error("expected my.team to be read-only")
+		}
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		if err := cfg.SetValue("accounts.other.read_only", "true"); !errors.Is(err, ErrAccountNotFound) {
+			t.Errorf("SetValue error = %v, want ErrAccountNotFound", err)
+		}
+		if _, err := cfg.GetValue("accounts.other.read_only"); !errors.Is(err, ErrAccountNotFound) {
+			t.Errorf("GetValue error = %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		if err := cfg.SetValue("accounts.work.email", "x@example.com"); err == nil {
+			t.Error("expected error for unknown field")
+		}
+		if _, err := cfg.GetValue("accounts.work.email"); err == nil {
+			t.Error("expected error for unknown field")
+		}
+		if err := cfg.SetValue("accounts.read_only", "true"); err == nil {
+			t.Error("expected error for key without alias")
+		}
+	})
+}
+
+// TestAccountLabelMapValue tests the accounts.<alias>.label_map key.
+func TestAccountLabelMapValue(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Accounts["personal"] = AccountConfig{Email: "me@example.com"}
+
+	if err := cfg.SetValue("accounts.personal.label_map", " Work = Archive/Work, Clients/*=Archive/Clients/*,INBOX=,"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("accounts.personal.label_map"); err != nil || got != "Work=Archive/Work,Clients/*=Archive/Clients/*,INBOX=" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	mapping := cfg.Accounts["personal"].LabelMapping()
+	if len(mapping) != 3 || mapping["Work"] != "Archive/Work" || mapping["INBOX"] != "" {
+		t.Errorf("LabelMapping() = %v", mapping)
+	}
+
+	for _, bad := range []string{"Work", "=Archive"} {
+		if err := cfg.SetValue("accounts.personal.label_map", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if err := cfg.SetValue("accounts.personal.label_map", ""); err != nil || cfg.Accounts["personal"].LabelMap != nil {
+		t.Errorf("expected an empty value to clear the map, got %v, %v", cfg.Accounts["personal"].LabelMap, err)
+	}
+}
+
+// TestMailHeadersValue tests the mail.headers key.
+func TestMailHeadersValue(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.headers", "X-Mailer:goog ; List-Unsubscribe: <mailto:u@example.com>, <https://example.com/u>;"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	want := "X-Mailer: goog; List-Unsubscribe: <mailto:u@example.com>, <https://example.com/u>"
+	if got, err := cfg.GetValue("mail.headers"); err != nil || got != want {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+
+	for _, bad := range []string{"X-Mailer", ": goog"} {
+		if err := cfg.SetValue("mail.headers", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if err := cfg.SetValue("mail.headers", ""); err != nil || cfg.Mail.Headers != nil {
+		t.Errorf("expected an empty value to clear the headers, got %v, %v", cfg.Mail.Headers, err)
+	}
+}
+
+// TestMailThrottleValues tests the mail.throttle and
+// mail.throttle_overrides keys.
+func TestMailThrottleValues(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.throttle", " 10m "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.throttle"); err != nil || got != "10m" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	for _, bad := range []string{"soon", "-5m"} {
+		if err := cfg.SetValue("mail.throttle", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	if err := cfg.SetValue("mail.throttle_overrides", "OnCall@Example.com=0, @example.org = 1h,"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	want := "oncall@example.com=0, @example.org=1h"
+	if got, err := cfg.GetValue("mail.throttle_overrides"); err != nil || got != want {
+		t.Errorf("GetValue() = %q, %v; want %q", got, err, want)
+	}
+	for _, bad := range []string{"example.org=1h", "@=1h", "a@example.com", "a@example.com=later"} {
+		if err := cfg.SetValue("mail.throttle_overrides", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if err := cfg.SetValue("mail.throttle_overrides", ""); err != nil || cfg.Mail.ThrottleOverrides != nil {
+		t.Errorf("expected an empty value to clear the overrides, got %v, %v", cfg.Mail.ThrottleOverrides, err)
+	}
+}
+
+// TestMailDuplicateWindowValue tests the mail.duplicate_window key.
+func TestMailDuplicateWindowValue(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.duplicate_window", " 1h "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.duplicate_window"); err != nil || got != "1h" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	for _, bad := range []string{"an hour", "-1h"} {
+		if err := cfg.SetValue("mail.duplicate_window", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if err := cfg.SetValue("mail.duplicate_window", ""); err != nil || cfg.Mail.DuplicateWindow != "" {
+		t.Errorf("expected an empty value to turn the check off, got %q, %v", cfg.Mail.DuplicateWindow, err)
+	}
+}
+
+// TestMailSearchCacheTTLValue tests the mail.search_cache_ttl key.
+func TestMailSearchCacheTTLValue(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.search_cache_ttl", " 2m "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.search_cache_ttl"); err != nil || got != "2m" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	for _, bad := range []string{"briefly", "-1m"} {
+		if err := cfg.SetValue("mail.search_cache_ttl", bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+// TestMailReplyQuoteValues tests the mail.reply_quote and
+// mail.quote_prefix keys.
+func TestMailReplyQuoteValues(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.reply_quote", " Bottom "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.reply_quote"); err != nil || got != "bottom" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	if err := cfg.SetValue("mail.reply_quote", "inline"); err == nil {
+		t.Error("expected error for inline")
+	}
+
+	// The prefix keeps its spaces
+	if err := cfg.SetValue("mail.quote_prefix", "| "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.quote_prefix"); err != nil || got != "| " {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	if err := cfg.SetValue("mail.quote_prefix", ">\n"); err == nil {
+		t.Error("expected error for a prefix with a newline")
+	}
+}
+
+// TestMailAutoBccValue tests the mail.auto_bcc key.
+func TestMailAutoBccValue(t *testing.T) {
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.auto_bcc", "CRM <dropbox@crm.example.com>, archive@example.com,"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	want := "dropbox@crm.example.com, archive@example.com"
+	if got, err := cfg.GetValue("mail.auto_bcc"); err != nil || got != want {
+		t.Errorf("GetValue() = %q, %v; want %q", got, err, want)
+	}
+	if err := cfg.SetValue("mail.auto_bcc", "not an address"); err == nil {
+		t.Error("expected error for an invalid address")
+	}
+	if err := cfg.SetValue("mail.auto_bcc", ""); err != nil || cfg.Mail.AutoBcc != nil {
+		t.Errorf("expected an empty value to clear the list, got %v, %v", cfg.Mail.AutoBcc, err)
+	}
+}
+
+// TestMailViewsValue tests the mail.views.<name> keys.
+func TestMailViewsValue(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.views.newsletters", "  list:news.example.com "); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.views.newsletters"); err != nil || got != "list:news.example.com" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	if _, err := cfg.GetValue("mail.views.missing"); err == nil {
+		t.Error("expected an error for an unknown view")
+	}
+	for _, bad := range []string{"mail.views.", "mail.views.News", "mail.views.a.b"} {
+		if err := cfg.SetValue(bad, "from:x"); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Mail.Views["newsletters"] != "list:news.example.com" {
+		t.Errorf("Views after load = %v", loaded.Mail.Views)
+	}
+
+	if err := cfg.SetValue("mail.views.newsletters", ""); err != nil || cfg.Mail.Views != nil {
+		t.Errorf("expected an empty value to remove the view, got %v, %v", cfg.Mail.Views, err)
+	}
+}
+
+// TestMailGroupsValue tests the mail.groups.<name> keys.
+func TestMailGroupsValue(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := NewConfig()
+
+	if err := cfg.SetValue("mail.groups.team-platform", "ana@example.com, Bo <bo@example.com>,"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("mail.groups.team-platform"); err != nil || got != "ana@example.com,bo@example.com" {
+		t.Errorf("GetValue() = %q, %v", got, err)
+	}
+	if _, err := cfg.GetValue("mail.groups.missing"); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+	if err := cfg.SetValue("mail.groups.Team", "ana@example.com"); err == nil {
+		t.Error("expected error for an upper-case group name")
+	}
+	if err := cfg.SetValue("mail.groups.team", "ana@example.com, not an address"); err == nil {
+		t.Error("expected error for an invalid address")
+	}
+
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := loaded.Mail.Groups["team-platform"]; len(got) != 2 || got[1] != "bo@example.com" {
+		t.Errorf("Groups after load = %v", loaded.Mail.Groups)
+	}
+
+	if err := cfg.SetValue("mail.groups.team-platform", ""); err != nil || cfg.Mail.Groups != nil {
+		t.Errorf("expected an empty value to remove the group, got %v, %v", cfg.Mail.Groups, err)
+	}
+}
+
+// TestAccountEndpointValues tests the accounts.<alias>.<service>_endpoint keys.
+func TestAccountEndpointValues(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Accounts["work"] = AccountConfig{Email: "work@example.com"}
+
+	for _, service := range []string{"gmail", "calendar", "tasks", "people"} {
+		key := "accounts.work." + service + "_endpoint"
+		url := "http://localhost:8080/" + service + "/"
+		if err := cfg.SetValue(key, url); err != nil {
+			t.Fatalf("SetValue(%s) failed: %v", key, err)
+		}
+		if got, err := cfg.GetValue(key); err != nil || got != url {
+			t.Errorf("GetValue(%s) = %q, %v", key, got, err)
+		}
+	}
+
+	endpoints := cfg.Accounts["work"].Endpoints()
+	if len(endpoints) != 4 || endpoints["calendar"] != "http://localhost:8080/calendar/" {
+		t.Errorf("Endpoints() = %v", endpoints)
+	}
+
+	t.Run("empty value resets", func(t *testing.T) {
+		if err := cfg.SetValue("accounts.work.gmail_endpoint", ""); err != nil {
+			t.Fatalf("SetValue failed: %v", err)
+		}
+		if _, ok := cfg.Accounts["work"].Endpoints()["gmail"]; ok {
+			t.Error("expected the gmail override to be removed")
+		}
+	})
+
+	t.Run("invalid URLs", func(t *testing.T) {
+		for _, value := range []string{"localhost:8080", "ftp://example.com/", "/gmail/", "https://"} {
+			if err := cfg.SetValue("accounts.work.tasks_endpoint", value); err == nil {
+				t.Errorf("expected error for %q", value)
+			}
+		}
+		if cfg.Accounts["work"].TasksEndpoint != "http://localhost:8080/tasks/" {
+			t.Errorf("invalid values should not be stored, got %q", cfg.Accounts["work"].TasksEndpoint)
+		}
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		if err := cfg.SetValue("accounts.work.drive_endpoint", "https://example.com/"); err == nil {
+			t.Error("expected error for unknown service")
+		}
+		if _, err := cfg.GetValue("accounts.work.drive_endpoint"); err == nil {
+			t.Error("expected error for unknown service")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Context scopes the commands run in a directory to one project: a mail
+// label, a calendar and a saved mail view. It is kept under context: in
+// the directory-local settings file.
+type Context struct {
+	Label    string `yaml:"label,omitempty" json:"label,omitempty"`
+	Calendar string `yaml:"calendar,omitempty" json:"calendar,omitempty"`
+	View     string `yaml:"view,omitempty" json:"view,omitempty"`
+}
+
+// IsZero reports whether the context scopes nothing.
+func (c *Context) IsZero() bool {
+	return c == nil || (c.Label == "" && c.Calendar == "" && c.View == "")
+}
+
+// LoadLocalContext reads the context of a directory-local settings file.
+// It returns nil when the file has none.
+func LoadLocalContext(path string) (*Context, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f struct {
+		Context *Context `yaml:"context"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if f.Context.IsZero() {
+		return nil, nil
+	}
+	return f.Context, nil
+}
+
+// SaveLocalContext writes the context into a directory-local settings
+// file, creating it if needed and keeping its other sections. An empty
+// context removes the section, and the file when nothing else is left.
+func SaveLocalContext(path string, ctx *Context) error {
+	doc := make(map[string]any)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+		if doc == nil {
+			doc = make(map[string]any)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	if ctx.IsZero() {
+		delete(doc, "context")
+	} else {
+		doc["context"] = ctx
+	}
+	if len(doc) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return os.WriteFile(path, out, 0o644)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalContextSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LocalFileName)
+	if err := os.WriteFile(path, []byte("settings:\n  mail:\n    list:\n      max_results: 30\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadLocalContext(path); err != nil || c != nil {
+		t.Fatalf("LoadLocalContext() = %+v, %v; want none", c, err)
+	}
+
+	want := &Context{Label: "Project/Foo", Calendar: "foo@group.calendar.google.com", View: "foo"}
+	if err := SaveLocalContext(path, want); err != nil {
+		t.Fatalf("SaveLocalContext failed: %v", err)
+	}
+	got, err := LoadLocalContext(path)
+	if err != nil || got == nil || *got != *want {
+		t.Fatalf("LoadLocalContext() = %+v, %v; want %+v", got, err, want)
+	}
+	settings, err := LoadLocalSettings(path)
+	if v, ok := settings.Get("mail.list.max_results"); err != nil || !ok || v != "30" {
+		t.Errorf("settings lost: %v, %v", settings, err)
+	}
+
+	if err := SaveLocalContext(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadLocalContext(path); err != nil || c != nil {
+		t.Errorf("expected the context to be removed, got %+v, %v", c, err)
+	}
+
+	only := filepath.Join(t.TempDir(), LocalFileName)
+	if err := SaveLocalContext(only, &Context{Label: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLocalContext(only, &Context{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(only); !os.IsNotExist(err) {
+		t.Errorf("expected a file left empty to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// metadataPlaceholder matches an {account.<key>} placeholder in a template.
+var metadataPlaceholder = regexp.MustCompile(`\{account\.([a-z0-9_-]+)\}`)
+
+// metadataKey returns the alias and metadata key of a config key of the
+// form accounts.<alias>.metadata.<key>.
+func metadataKey(key string) (alias, name string, ok bool) {
+	rest, found := strings.CutPrefix(key, "accounts.")
+	if !found {
+		return "", "", false
+	}
+	alias, name, found = strings.Cut(rest, ".metadata.")
+	if !found || alias == "" {
+		return "", "", false
+	}
+	return alias, name, true
+}
+
+// SetAccountMetadata sets a metadata entry of the account with the given
+// alias; an empty value removes it. Keys are stored in lower case, as the
+// config file's keys are read without regard to case.
+func (c *Config) SetAccountMetadata(alias, key, value string) error {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !validViewName(key) {
+		return fmt.Errorf("invalid metadata key %q: use letters, digits, hyphens and underscores", key)
+	}
+	acc, ok := c.Accounts[alias]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, alias)
+	}
+	if value == "" {
+		delete(acc.Metadata, key)
+		if len(acc.Metadata) == 0 {
+			acc.Metadata = nil
+		}
+	} else {
+		if acc.Metadata == nil {
+			acc.Metadata = make(map[string]string)
+		}
+		acc.Metadata[key] = value
+	}
+	c.Accounts[alias] = acc
+	return nil
+}
+
+// ExpandMetadata replaces the {account.<key>} placeholders in text with
+// the values of metadata. Keys the account has no value for are replaced
+// with nothing, so one template can serve accounts with different keys.
+func ExpandMetadata(text string, metadata map[string]string) string {
+	return metadataPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		return metadata[metadataPlaceholder.FindStringSubmatch(placeholder)[1]]
+	})
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetAccountMetadata(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Accounts["work"] = AccountConfig{Email: "me@work.example"}
+
+	if err := cfg.SetAccountMetadata("work", "Role", "work"); err != nil {
+		t.Fatalf("SetAccountMetadata failed: %v", err)
+	}
+	if err := cfg.SetValue("accounts.work.metadata.cost-centre", "4711"); err != nil {
+		t.Fatalf("SetValue failed: %v", err)
+	}
+	if got, err := cfg.GetValue("accounts.work.metadata.role"); err != nil || got != "work" {
+		t.Errorf("GetValue(role) = %q, %v", got, err)
+	}
+	if got := cfg.Accounts["work"].Metadata["cost-centre"]; got != "4711" {
+		t.Errorf("cost-centre = %q", got)
+	}
+
+	if err := cfg.SetAccountMetadata("work", "role", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetAccountMetadata("work", "cost-centre", ""); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Accounts["work"].Metadata != nil {
+		t.Errorf("expected empty metadata to be dropped, got %v", cfg.Accounts["work"].Metadata)
+	}
+	if _, err := cfg.GetValue("accounts.work.metadata.role"); err == nil {
+		t.Error("expected an error for a removed key")
+	}
+
+	if err := cfg.SetAccountMetadata("work", "bad key", "x"); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+	if err := cfg.SetAccountMetadata("home", "role", "x"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestExpandMetadata(t *testing.T) {
+	metadata := map[string]string{"role": "work", "cost-centre": "4711"}
+	for text, want := range map[string]string{
+		"{account.role}/{account.cost-centre}": "work/4711",
+		"[{account.missing}]":                  "[]",
+		"{account}{subject}":                   "{account}{subject}",
+	} {
+		if got := ExpandMetadata(text, metadata); got != want {
+			t.Errorf("ExpandMetadata(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the config file format written by this version of goog.
+// Files without schema_version are version 0.
+const SchemaVersion = 1
+
+// ErrSchemaTooNew is returned when the config file was written by a newer
+// goog with a format this version does not know.
+var ErrSchemaTooNew = errors.New("config file format is newer than this goog supports")
+
+// migration upgrades a raw config document from version-1 to version.
+type migration struct {
+	version     int
+	description string
+	// apply changes doc in place and returns a description of each change.
+	apply func(doc map[string]any) []string
+}
+
+// migrations lists every format change in version order. A new format
+// adds an entry here and bumps SchemaVersion.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "default_account holds an account alias instead of an email address",
+		apply:       migrateDefaultAccountAlias,
+	},
+}
+
+// migrateDefaultAccountAlias replaces a default_account that is the email
+// address of a configured account with that account's alias.
+func migrateDefaultAccountAlias(doc map[string]any) []string {
+	current, _ := doc["default_account"].(string)
+	accounts, _ := asMap(doc["accounts"])
+	if current == "" {
+		return nil
+	}
+	if _, isAlias := accounts[current]; isAlias {
+		return nil
+	}
+
+	aliases := make([]string, 0, len(accounts))
+	for alias := range accounts {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		acc, _ := asMap(accounts[alias])
+		if email, _ := acc["email"].(string); email != "" && strings.EqualFold(email, current) {
+			doc["default_account"] = alias
+			return []string{fmt.Sprintf("default_account: %q -> %q", current, alias)}
+		}
+	}
+	return nil
+}
+
+// MigrationStep is one migration applied to a config document.
+type MigrationStep struct {
+	Version     int      `json:"version"`
+	Description string   `json:"description"`
+	Changes     []string `json:"changes"`
+}
+
+// MigrationPlan describes upgrading a config file to SchemaVersion.
+type MigrationPlan struct {
+	Path  string          `json:"path"`
+	From  int             `json:"from"`
+	To    int             `json:"to"`
+	Steps []MigrationStep `json:"steps"`
+
+	doc map[string]any
+}
+
+// Pending reports whether the file needs to be rewritten.
+func (p *MigrationPlan) Pending() bool {
+	return p.From < p.To
+}
+
+// schemaVersionOf returns the schema_version of a raw config document.
+func schemaVersionOf(doc map[string]any) (int, error) {
+	switch v := doc["schema_version"].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid schema_version %v", doc["schema_version"])
+}
+
+// migrateDocument upgrades doc in place to SchemaVersion and returns the
+// version it started at and the migrations applied.
+func migrateDocument(doc map[string]any) (int, []MigrationStep, error) {
+	from, err := schemaVersionOf(doc)
+	if err != nil {
+		return 0, nil, err
+	}
+	if from > SchemaVersion {
+		return from, nil, fmt.Errorf("%w: schema_version %d, supported up to %d (upgrade goog)", ErrSchemaTooNew, from, SchemaVersion)
+	}
+	var steps []MigrationStep
+	for _, m := range migrations {
+		if m.version <= from {
+			continue
+		}
+		steps = append(steps, MigrationStep{Version: m.version, Description: m.description, Changes: m.apply(doc)})
+		doc["schema_version"] = m.version
+	}
+	return from, steps, nil
+}
+
+// readDocument parses the config file at path as a raw document. A
+// missing or empty file is an empty document.
+func readDocument(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]any)
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+	return doc, nil
+}
+
+// PlanMigration reads the config file and returns the migrations needed to
+// bring it to SchemaVersion, without changing the file.
+func PlanMigration() (*MigrationPlan, error) {
+	path := GetConfigPath()
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+	from, steps, err := migrateDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationPlan{Path: path, From: from, To: SchemaVersion, Steps: steps, doc: doc}, nil
+}
+
+// BackupPath returns where the config file is copied before a file with
+// the given schema version is rewritten in the current format.
+func BackupPath(configPath string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", configPath, version)
+}
+
+// Migrate rewrites the config file in the current format, keeping a copy
+// of the previous file at BackupPath, and returns the plan it applied and
+// the backup path. It does nothing when the file is already current.
+func Migrate() (*MigrationPlan, string, error) {
+	path := GetConfigPath()
+	lock, err := filelock.Acquire(path, lockTimeout)
+	if err != nil {
+		if errors.Is(err, filelock.ErrLocked) {
+			return nil, "", fmt.Errorf("%w (%s): try again", ErrConfigLocked, filelock.LockPath(path))
+		}
+		return nil, "", err
+	}
+	defer lock.Unlock()
+
+	plan, err := PlanMigration()
+	if err != nil || !plan.Pending() {
+		return plan, "", err
+	}
+	backup, err := backupConfig(path, plan.From)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := yaml.Marshal(plan.doc)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return nil, "", fmt.Errorf("failed to write config: %w", err)
+	}
+	return plan, backup, nil
+}
+
+// backupConfig copies the config file to BackupPath for the given version.
+// An existing backup of that version is kept, since it is the closest to
+// the original file.
+func backupConfig(path string, version int) (string, error) {
+	backup := BackupPath(path, version)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+	f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return backup, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to back up config: %w", err)
+	}
+	return backup, nil
+}
+
+// backupBeforeSave backs up the config file at path if it has an older
+// schema version than the file about to be written. Files that cannot be
+// parsed are left to the write.
+func backupBeforeSave(path string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil
+	}
+	version, err := schemaVersionOf(doc)
+	if err != nil || version >= SchemaVersion {
+		return nil
+	}
+	_, err = backupConfig(path, version)
+	return err
+}
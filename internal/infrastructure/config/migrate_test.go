@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// legacyConfig is a version 0 config file whose default account is an
+// email address.
+const legacyConfig = `default_account: Work@Example.com
+accounts:
+  personal:
+    email: me@example.com
+  work:
+    email: work@example.com
+custom_key: kept
+`
+
+// writeLegacyConfig points GOOG_CONFIG at a version 0 config file.
+func writeLegacyConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", path)
+	if err := os.WriteFile(path, []byte(legacyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPlanMigration(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	plan, err := PlanMigration()
+	if err != nil {
+		t.Fatalf("PlanMigration failed: %v", err)
+	}
+	if !plan.Pending() || plan.From != 0 || plan.To != SchemaVersion || len(plan.Steps) != 1 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if changes := plan.Steps[0].Changes; len(changes) != 1 || changes[0] != `default_account: "Work@Example.com" -> "work"` {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Error("expected planning to leave the file unchanged")
+	}
+}
+
+func TestLoadMigratesInMemory(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DefaultAccount != "work" || cfg.SchemaVersion != SchemaVersion {
+		t.Errorf("DefaultAccount = %q, SchemaVersion = %d", cfg.DefaultAccount, cfg.SchemaVersion)
+	}
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Error("expected Load to leave the file unchanged")
+	}
+
+	// The next save backs up the old file
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if data, err := os.ReadFile(BackupPath(path, 0)); err != nil || string(data) != legacyConfig {
+		t.Errorf("expected a backup of the old file, got %q, %v", data, err)
+	}
+	plan, err := PlanMigration()
+	if err != nil || plan.Pending() {
+		t.Errorf("expected no pending migration after saving, got %+v, %v", plan, err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := writeLegacyConfig(t)
+
+	plan, backup, err := Migrate()
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if !plan.Pending() || backup != BackupPath(path, 0) {
+		t.Errorf("unexpected result: %+v, %q", plan, backup)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"schema_version: 1", "default_account: work", "custom_key: kept"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("migrated file lacks %q:\n%s", want, data)
+		}
+	}
+
+	plan, backup, err = Migrate()
+	if err != nil || plan.Pending() || backup != "" {
+		t.Errorf("expected a second migration to do nothing, got %+v, %q, %v", plan, backup, err)
+	}
+}
+
+func TestLoadRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GOOG_CONFIG", path)
+	if err := os.WriteFile(path, []byte("schema_version: 99\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestMigrateDefaultAccountAlias(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  map[string]any
+		want any
+	}{
+		{"alias kept", map[string]any{"default_account": "work", "accounts": map[string]any{"work": map[string]any{"email": "work@example.com"}}}, "work"},
+		{"unknown email kept", map[string]any{"default_account": "x@example.com", "accounts": map[string]any{}}, "x@example.com"},
+		{"empty", map[string]any{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if changes := migrateDefaultAccountAlias(tt.doc); len(changes) != 0 || tt.doc["default_account"] != tt.want {
+				t.Errorf("changes = %v, default_account = %v", changes, tt.doc["default_account"])
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalFileName is the name of the directory-local settings file. The
+// nearest one in the working directory or its parents is used.
+const LocalFileName = ".goog.yaml"
+
+// Settings layer names, from highest to lowest precedence after flags.
+const (
+	LayerEnv     = "env"
+	LayerLocal   = "local"
+	LayerProfile = "profile"
+	LayerGlobal  = "global"
+)
+
+// Settings holds per-command defaults as a tree keyed by command path and
+// setting name, e.g. mail: {list: {max_results: 50}} for the key
+// mail.list.max_results. Keys are lower case, so viper's lower-casing of
+// map keys does not change them.
+type Settings map[string]any
+
+// ValidSettingKey reports whether key can name a per-command setting: at
+// least a command and a setting name, each segment made of lowercase
+// letters, digits and underscores.
+func ValidSettingKey(key string) bool {
+	parts := strings.Split(key, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Get returns the value of key as a string.
+func (s Settings) Get(key string) (string, bool) {
+	var node any = map[string]any(s)
+	for _, part := range strings.Split(key, ".") {
+		m, ok := asMap(node)
+		if !ok {
+			return "", false
+		}
+		if node, ok = m[part]; !ok {
+			return "", false
+		}
+	}
+	if _, isMap := asMap(node); isMap || node == nil {
+		return "", false
+	}
+	return fmt.Sprint(node), true
+}
+
+// Flatten returns every setting keyed by its dotted key.
+func (s Settings) Flatten() map[string]string {
+	out := make(map[string]string)
+	var walk func(prefix string, node any)
+	walk = func(prefix string, node any) {
+		if m, ok := asMap(node); ok {
+			for k, v := range m {
+				if prefix != "" {
+					k = prefix + "." + k
+				}
+				walk(k, v)
+			}
+			return
+		}
+		if node != nil && prefix != "" {
+			out[prefix] = fmt.Sprint(node)
+		}
+	}
+	walk("", map[string]any(s))
+	return out
+}
+
+// set stores value under key, creating intermediate levels. An empty value
+// removes the key and any levels left empty.
+func (s Settings) set(key, value string) {
+	parts := strings.Split(key, ".")
+	if value == "" {
+		removeSetting(s, parts)
+		return
+	}
+	m := map[string]any(s)
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := asMap(m[part])
+		if !ok {
+			child = make(map[string]any)
+			m[part] = child
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// removeSetting deletes the key at path from m and reports whether m is
+// left empty.
+func removeSetting(m map[string]any, path []string) bool {
+	if len(path) == 1 {
+		delete(m, path[0])
+	} else if child, ok := asMap(m[path[0]]); ok && removeSetting(child, path[1:]) {
+		delete(m, path[0])
+	}
+	return len(m) == 0
+}
+
+// asMap returns node as a map if it is one. YAML and viper decode nested
+// maps as map[string]any; yaml.v3 may also produce map[any]any.
+func asMap(node any) (map[string]any, bool) {
+	switch m := node.(type) {
+	case Settings:
+		return m, true
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[fmt.Sprint(k)] = v
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// SetSetting sets a per-command setting in the global config. An empty
+// value removes it.
+func (c *Config) SetSetting(key, value string) error {
+	if !ValidSettingKey(key) {
+		return fmt.Errorf("invalid setting key %q: use <command>.<setting>, e.g. mail.list.max_results", key)
+	}
+	if c.Settings == nil {
+		c.Settings = make(Settings)
+	}
+	c.Settings.set(key, value)
+	if len(c.Settings) == 0 {
+		c.Settings = nil
+	}
+	return nil
+}
+
+// SetAccountSetting sets a per-command setting in an account's profile.
+// An empty value removes it.
+func (c *Config) SetAccountSetting(alias, key, value string) error {
+	if !ValidSettingKey(key) {
+		return fmt.Errorf("invalid setting key %q: use <command>.<setting>, e.g. mail.list.max_results", key)
+	}
+	acc, ok := c.Accounts[alias]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, alias)
+	}
+	if acc.Settings == nil {
+		acc.Settings = make(Settings)
+	}
+	acc.Settings.set(key, value)
+	if len(acc.Settings) == 0 {
+		acc.Settings = nil
+	}
+	c.Accounts[alias] = acc
+	return nil
+}
+
+// localFile is the content of a directory-local settings file.
+type localFile struct {
+	Settings Settings `yaml:"settings"`
+}
+
+// FindLocalFile returns the path of the nearest directory-local settings
+// file in dir or its parents, or "" if there is none.
+func FindLocalFile(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, LocalFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadLocalSettings reads the settings of a directory-local settings file.
+func LoadLocalSettings(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f localFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return f.Settings, nil
+}
+
+// SettingEnvVar returns the environment variable that overrides a setting:
+// GOOG_ followed by the key in upper case with dots as underscores, e.g.
+// GOOG_MAIL_LIST_MAX_RESULTS.
+func SettingEnvVar(key string) string {
+	return "GOOG_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// settingsLayer is one source of settings.
+type settingsLayer struct {
+	name     string
+	settings Settings
+}
+
+// Resolver looks up per-command settings across layers. From highest to
+// lowest precedence: environment variables, the directory-local file, the
+// profile of the account in use and the global config. Command-line flags
+// take precedence over all of them and are handled by the caller.
+type Resolver struct {
+	env    func(string) (string, bool)
+	layers []settingsLayer
+}
+
+// NewResolver returns a resolver over the environment, the nearest
+// directory-local settings file from dir (if dir is not empty), the
+// profile of the account with the given alias (if any) and cfg.
+func NewResolver(cfg *Config, alias, dir string) (*Resolver, error) {
+	r := &Resolver{env: os.LookupEnv}
+	if dir != "" {
+		if path := FindLocalFile(dir); path != "" {
+			local, err := LoadLocalSettings(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+			r.layers = append(r.layers, settingsLayer{name: LayerLocal, settings: local})
+		}
+	}
+	if cfg != nil {
+		if acc, ok := cfg.Accounts[alias]; ok && alias != "" {
+			r.layers = append(r.layers, settingsLayer{name: LayerProfile, settings: acc.Settings})
+		}
+		r.layers = append(r.layers, settingsLayer{name: LayerGlobal, settings: cfg.Settings})
+	}
+	return r, nil
+}
+
+// Lookup returns the value of a setting and the name of the layer it came
+// from. The first key found in the highest layer wins; later keys are
+// synonyms that are only tried within the same layer.
+func (r *Resolver) Lookup(keys ...string) (value, layer string, ok bool) {
+	for _, key := range keys {
+		if value, ok := r.env(SettingEnvVar(key)); ok {
+			return value, LayerEnv, true
+		}
+	}
+	for _, l := range r.layers {
+		for _, key := range keys {
+			if value, ok := l.settings.Get(key); ok {
+				return value, l.name, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// ResolvedSetting is a setting value and the layer it came from.
+type ResolvedSetting struct {
+	Key   string
+	Value string
+	Layer string
+}
+
+// All returns every setting defined in a file layer, with the value and
+// layer that win, sorted by key. Environment variables are applied to the
+// keys found in files.
+func (r *Resolver) All() []ResolvedSetting {
+	keys := make(map[string]bool)
+	for _, l := range r.layers {
+		for key := range l.settings.Flatten() {
+			keys[key] = true
+		}
+	}
+	out := make([]ResolvedSetting, 0, len(keys))
+	for key := range keys {
+		value, layer, _ := r.Lookup(key)
+		out = append(out, ResolvedSetting{Key: key, Value: value, Layer: layer})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
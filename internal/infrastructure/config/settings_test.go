@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidSettingKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"mail.list.max_results": true,
+		"cal.today.calendar":    true,
+		"history.limit":         true,
+		"mail":                  false,
+		"mail..max_results":     false,
+		"mail.list.max-results": false,
+		"Mail.list.max_results": false,
+	} {
+		if got := ValidSettingKey(key); got != want {
+			t.Errorf("ValidSettingKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestSettingsSetGetFlatten(t *testing.T) {
+	s := Settings{}
+	s.set("mail.list.max_results", "50")
+	s.set("mail.search.format", "json")
+
+	if got, ok := s.Get("mail.list.max_results"); !ok || got != "50" {
+		t.Errorf("Get() = %q, %v", got, ok)
+	}
+	if _, ok := s.Get("mail.list"); ok {
+		t.Error("expected a command level not to be a value")
+	}
+	if got := s.Flatten(); len(got) != 2 || got["mail.search.format"] != "json" {
+		t.Errorf("Flatten() = %v", got)
+	}
+
+	s.set("mail.list.max_results", "")
+	s.set("mail.search.format", "")
+	if len(s) != 0 {
+		t.Errorf("expected removing every key to leave no levels, got %v", s)
+	}
+}
+
+func TestSettingsSaveLoad(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	cfg := NewConfig()
+	cfg.Accounts["work"] = AccountConfig{Email: "work@example.com"}
+	if err := cfg.SetSetting("mail.list.max_results", "50"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetAccountSetting("work", "mail.list.max_results", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetAccountSetting("home", "mail.list.max_results", "5"); err == nil {
+		t.Error("expected an error for an unknown account")
+	}
+	if err := cfg.SetSetting("mail.list.Max", "5"); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := loaded.Settings.Get("mail.list.max_results"); got != "50" {
+		t.Errorf("global setting = %q", got)
+	}
+	if got, _ := loaded.Accounts["work"].Settings.Get("mail.list.max_results"); got != "5" {
+		t.Errorf("profile setting = %q", got)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "project", "docs")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	local := "settings:\n  mail:\n    list:\n      max_results: 30\n    search:\n      format: json\n"
+	if err := os.WriteFile(filepath.Join(root, "project", LocalFileName), []byte(local), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewConfig()
+	cfg.Accounts["work"] = AccountConfig{}
+	_ = cfg.SetSetting("mail.list.max_results", "50")
+	_ = cfg.SetSetting("cal.list.max_results", "10")
+	_ = cfg.SetSetting("mail.triage.page_size", "7")
+	_ = cfg.SetAccountSetting("work", "cal.list.max_results", "15")
+
+	r, err := NewResolver(cfg, "work", sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOG_MAIL_SEARCH_FORMAT", "plain")
+
+	tests := []struct {
+		keys      []string
+		value     string
+		layer     string
+		wantFound bool
+	}{
+		{[]string{"mail.search.format"}, "plain", LayerEnv, true},
+		{[]string{"mail.list.max_results"}, "30", LayerLocal, true},
+		{[]string{"cal.list.max_results"}, "15", LayerProfile, true},
+		{[]string{"mail.triage.max_results", "mail.triage.page_size"}, "7", LayerGlobal, true},
+		{[]string{"tasks.list.max_results"}, "", "", false},
+	}
+	for _, tt := range tests {
+		value, layer, ok := r.Lookup(tt.keys...)
+		if value != tt.value || layer != tt.layer || ok != tt.wantFound {
+			t.Errorf("Lookup(%v) = %q, %q, %v; want %q, %q, %v", tt.keys, value, layer, ok, tt.value, tt.layer, tt.wantFound)
+		}
+	}
+
+	all := r.All()
+	if len(all) != 4 || all[0].Key != "cal.list.max_results" || all[0].Layer != LayerProfile {
+		t.Errorf("All() = %+v", all)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "project", LocalFileName), []byte("settings: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewResolver(cfg, "", sub); err == nil {
+		t.Error("expected an error for an invalid local file")
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// UsageFileName is the name of the file next to the config file that
+// records when each account was last used. It is kept out of config.yaml
+// so that running a command does not rewrite the config.
+const UsageFileName = "account-usage.json"
+
+// usageResolution is how stale a recorded time may get before
+// MarkAccountUsed writes it again.
+const usageResolution = time.Minute
+
+// UsagePath returns the path of the account usage file.
+func UsagePath() string {
+	return filepath.Join(filepath.Dir(GetConfigPath()), UsageFileName)
+}
+
+// LoadLastUsed returns when each account was last used, keyed by alias. A
+// missing or unreadable file means no account has been used.
+func LoadLastUsed() (map[string]time.Time, error) {
+	data, err := os.ReadFile(UsagePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]time.Time{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account usage: %w", err)
+	}
+	used := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &used); err != nil {
+		return map[string]time.Time{}, nil
+	}
+	return used, nil
+}
+
+// MarkAccountUsed records that the account alias was used at t. Times
+// within a minute of the recorded one are not written, so a burst of
+// commands does not rewrite the file each time.
+func MarkAccountUsed(alias string, t time.Time) error {
+	used, err := LoadLastUsed()
+	if err != nil {
+		return err
+	}
+	if last, ok := used[alias]; ok && t.Sub(last) < usageResolution && !t.Before(last) {
+		return nil
+	}
+
+	path := UsagePath()
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	// Re-read under the lock so concurrent commands keep each other's times
+	if used, err = LoadLastUsed(); err != nil {
+		return err
+	}
+	used[alias] = t.UTC().Truncate(time.Second)
+	data, err := json.MarshalIndent(used, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode account usage: %w", err)
+	}
+	if err := filelock.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write account usage: %w", err)
+	}
+	return nil
+}
+
+// ForgetAccountUsage removes the usage record of the account alias. It
+// reports whether there was one.
+func ForgetAccountUsage(alias string) (bool, error) {
+	path := UsagePath()
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	used, err := LoadLastUsed()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := used[alias]; !ok {
+		return false, nil
+	}
+	delete(used, alias)
+	data, err := json.MarshalIndent(used, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to encode account usage: %w", err)
+	}
+	if err := filelock.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return false, fmt.Errorf("failed to write account usage: %w", err)
+	}
+	return true, nil
+}
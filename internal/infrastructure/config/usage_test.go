@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarkAccountUsed(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	used, err := LoadLastUsed()
+	if err != nil {
+		t.Fatalf("LoadLastUsed failed: %v", err)
+	}
+	if len(used) != 0 {
+		t.Errorf("missing file should mean no usage, got %v", used)
+	}
+
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	if err := MarkAccountUsed("work", start); err != nil {
+		t.Fatalf("MarkAccountUsed failed: %v", err)
+	}
+	if err := MarkAccountUsed("personal", start.Add(time.Hour)); err != nil {
+		t.Fatalf("MarkAccountUsed failed: %v", err)
+	}
+
+	used, err = LoadLastUsed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !used["work"].Equal(start) || !used["personal"].Equal(start.Add(time.Hour)) {
+		t.Errorf("usage = %v", used)
+	}
+
+	t.Run("skips writes within a minute", func(t *testing.T) {
+		if err := MarkAccountUsed("work", start.Add(30*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		used, _ := LoadLastUsed()
+		if !used["work"].Equal(start) {
+			t.Errorf("work = %v, want %v", used["work"], start)
+		}
+
+		if err := MarkAccountUsed("work", start.Add(2*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		used, _ = LoadLastUsed()
+		if !used["work"].Equal(start.Add(2 * time.Minute)) {
+			t.Errorf("work = %v, want %v", used["work"], start.Add(2*time.Minute))
+		}
+	})
+
+	t.Run("forgets an account", func(t *testing.T) {
+		forgot, err := ForgetAccountUsage("personal")
+		if err != nil || !forgot {
+			t.Fatalf("ForgetAccountUsage = %v, %v, want true", forgot, err)
+		}
+		used, _ := LoadLastUsed()
+		if _, ok := used["personal"]; ok || used["work"].IsZero() {
+			t.Errorf("usage after forgetting personal = %v", used)
+		}
+		if forgot, _ := ForgetAccountUsage("personal"); forgot {
+			t.Error("forgetting twice should report nothing forgotten")
+		}
+	})
+
+	t.Run("file is private", func(t *testing.T) {
+		info, err := os.Stat(UsagePath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("usage file permissions = %o, want 600", perm)
+		}
+	})
+
+	t.Run("ignores a corrupt file", func(t *testing.T) {
+		if err := os.WriteFile(UsagePath(), []byte("{not json"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		used, err := LoadLastUsed()
+		if err != nil || len(used) != 0 {
+			t.Errorf("LoadLastUsed = %v, %v, want empty", used, err)
+		}
+	})
+}
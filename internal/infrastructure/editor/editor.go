@@ -0,0 +1,43 @@
+// Package editor runs the user's text editor on a file, so messages can be
+// composed in the editor the user already knows.
+package editor
+
+import (
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Command returns the editor to run: VISUAL, else EDITOR, else vi, or
+// notepad on Windows. getenv is usually os.Getenv.
+func Command(getenv func(string) string) string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if v := strings.TrimSpace(getenv(name)); v != "" {
+			return v
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// Start starts the editor command on path, connected to the given
+// terminal streams. The command is run by the shell, so it may carry
+// arguments, such as "code --wait". The caller waits for it to exit.
+func Start(command, path string, stdin io.Reader, stdout, stderr io.Writer) (*exec.Cmd, error) {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/c", command+` "`+path+`"`)
+	} else {
+		c = exec.Command("sh", "-c", command+` "$1"`, "sh", path)
+	}
+	c.Stdin = stdin
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	want := "vi"
+	if runtime.GOOS == "windows" {
+		want = "notepad"
+	}
+	if got := Command(getenv); got != want {
+		t.Errorf("Command() with no editor set = %q, want %q", got, want)
+	}
+
+	env["EDITOR"] = "nano"
+	if got := Command(getenv); got != "nano" {
+		t.Errorf("Command() = %q, want EDITOR", got)
+	}
+
+	env["VISUAL"] = "code --wait"
+	if got := Command(getenv); got != "code --wait" {
+		t.Errorf("Command() = %q, want VISUAL before EDITOR", got)
+	}
+}
+
+func TestStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "message with spaces.txt")
+	if err := os.WriteFile(path, []byte("draft"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Start(`f() { printf ' edited' >> "$1"; }; f`, path, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "draft edited" {
+		t.Errorf("file = %q, want the editor's change", got)
+	}
+}
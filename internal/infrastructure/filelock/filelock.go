@@ -0,0 +1,116 @@
+// Package filelock provides advisory file locks and atomic file writes so
+// that several goog processes (for example a cron job and an interactive
+// session) can safely update the same config and token files.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long Lock waits for another process to release
+	// a lock.
+	DefaultTimeout = 5 * time.Second
+
+	// retryInterval is the delay between lock attempts.
+	retryInterval = 50 * time.Millisecond
+
+	// lockSuffix is appended to a file's path to name its lock file.
+	lockSuffix = ".lock"
+)
+
+// ErrLocked is returned when a lock is still held by another process after
+// the timeout.
+var ErrLocked = errors.New("locked by another process")
+
+// Lock is an exclusive advisory lock on a file.
+type Lock struct {
+	f *os.File
+}
+
+// LockPath returns the path of the lock file guarding path.
+func LockPath(path string) string {
+	return path + lockSuffix
+}
+
+// Acquire takes an exclusive lock guarding path, retrying until timeout.
+// The lock is held on a separate lock file next to path, which is left in
+// place after Unlock so that it is never deleted while another process is
+// waiting on it.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	lockPath := LockPath(path)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		if locked {
+			return &Lock{f: f}, nil
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", lockPath, ErrLocked)
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// Unlock releases the lock.
+func (l *Lock) Unlock() error {
+	if err := unlock(l.f); err != nil {
+		l.f.Close()
+		return fmt.Errorf("failed to unlock: %w", err)
+	}
+	return l.f.Close()
+}
+
+// WriteFile writes data to path atomically: it writes a temporary file in
+// the same directory and renames it over path, so readers see either the
+// old or the new contents and never a partial write. The temporary file is
+// created with perm from the start. Like os.WriteFile, it fails if path
+// exists but is not writable.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		f.Close()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op after a successful rename
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,157 @@
+// Package history keeps a local history of goog commands, like a shell
+// history. Entries hold the command line with content such as message
+// bodies redacted, and are stored one JSON object per line in a file next
+// to the config file.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the history file kept next to the config file.
+const FileName = "history.jsonl"
+
+// MaxEntries is the number of most recent entries kept.
+const MaxEntries = 1000
+
+// Redacted replaces content left out of an entry.
+const Redacted = "REDACTED"
+
+// ErrNotFound is returned by Get when no entry has the requested number.
+var ErrNotFound = errors.New("history entry not found")
+
+// Entry is one command in the history.
+type Entry struct {
+	// ID numbers entries in the order they ran, starting at 1.
+	ID int `json:"id"`
+	// Time is when the command ran.
+	Time time.Time `json:"time"`
+	// Command is the command path without the program name, e.g.
+	// "mail list".
+	Command string `json:"command"`
+	// Args are the arguments after the program name, as they would be
+	// typed to run the command again.
+	Args []string `json:"args"`
+	// Redacted reports whether content was replaced with Redacted.
+	Redacted bool `json:"redacted,omitempty"`
+	// Failed reports whether the command returned an error.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// Path returns the path of the history file.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// Load reads the entries in the history file at path, oldest first. A
+// missing file is an empty history; lines that cannot be parsed are
+// skipped.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.ID == 0 {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns the entry numbered id in the history file at path.
+func Get(path string, id int) (Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// Append adds e to the history file at path, numbering it after the last
+// entry and dropping the oldest entries beyond MaxEntries. It returns the
+// stored entry.
+func Append(path string, e Entry) (Entry, error) {
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := Load(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	entries = append(entries, e)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+
+	if err := write(path, entries); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// Clear removes every entry from the history file at path.
+func Clear(path string) error {
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear history: %w", err)
+	}
+	return nil
+}
+
+// write replaces the history file at path with entries.
+func write(path string, entries []Entry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to encode history: %w", err)
+		}
+	}
+	if err := filelock.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
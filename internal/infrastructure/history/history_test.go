@@ -0,0 +1,138 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	first, err := Append(path, Entry{Command: "mail list", Args: []string{"mail", "list", "--unread"}})
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	second, err := Append(path, Entry{Command: "cal today", Args: []string{"cal", "today"}, Failed: true})
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+	if first.Time.IsZero() {
+		t.Error("Append should set the time")
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Command != "mail list" || entries[0].Args[2] != "--unread" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if !entries[1].Failed {
+		t.Error("second entry should be marked failed")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("history file permissions = %o, want 600", perm)
+	}
+}
+
+func TestAppendTrims(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	for i := 0; i < MaxEntries+5; i++ {
+		if _, err := Append(path, Entry{Command: "tasks list", Args: []string{"tasks", "list"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Fatalf("got %d entries, want %d", len(entries), MaxEntries)
+	}
+	if entries[0].ID != 6 || entries[len(entries)-1].ID != MaxEntries+5 {
+		t.Errorf("kept IDs %d..%d, want 6..%d", entries[0].ID, entries[len(entries)-1].ID, MaxEntries+5)
+	}
+}
+
+func TestLoadSkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	data := `{"id":1,"command":"mail list","args":["mail","list"]}
+not json
+{"id":2,"command":"cal today","args":["cal","today"]}
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2", len(entries))
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	entries, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("missing file should be an empty history, got %d entries", len(entries))
+	}
+}
+
+func TestGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if _, err := Append(path, Entry{Command: "mail list", Args: []string{"mail", "list"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := Get(path, 1)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if e.Command != "mail list" {
+		t.Errorf("Command = %q, want mail list", e.Command)
+	}
+
+	if _, err := Get(path, 7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if _, err := Append(path, Entry{Command: "mail list", Args: []string{"mail", "list"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d entries after Clear, want 0", len(entries))
+	}
+	if err := Clear(path); err != nil {
+		t.Errorf("clearing an empty history should succeed, got %v", err)
+	}
+}
@@ -0,0 +1,445 @@
+// Package ics reads events from iCalendar (RFC 5545) files, such as those
+// exported by other calendar applications, so they can be imported, and
+// the invitations carried by text/calendar parts of mail.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Date and date-time value formats.
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+)
+
+// property is a parsed content line: NAME;PARAM=value:VALUE.
+type property struct {
+	name   string
+	params map[string]string
+	// raw is the line as read, after unfolding, for recurrence rules that
+	// are passed on unchanged.
+	raw   string
+	value string
+}
+
+// Parse reads the events of an iCalendar stream. Cancelled events and
+// changed instances of recurring events (those with a RECURRENCE-ID) are
+// left out, as importing them would add events rather than change the
+// recurring one. Alarms inside events are ignored.
+func Parse(r io.Reader) ([]*calendar.Event, error) {
+	_, components, err := split(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*calendar.Event
+	for _, props := range components {
+		event, err := toEvent(props)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// split reads the properties of the calendar itself and those of each
+// VEVENT. Components nested in events, such as alarms, and other
+// top-level components, such as time zones, are skipped.
+func split(r io.Reader) (calendarProps []property, events [][]property, err error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	var current []property
+	depth, other := 0, 0
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		switch prop.name {
+		case "BEGIN":
+			switch {
+			case depth > 0:
+				depth++
+			case strings.EqualFold(prop.value, "VEVENT"):
+				depth, current = 1, nil
+			case !strings.EqualFold(prop.value, "VCALENDAR"):
+				other++
+			}
+			continue
+		case "END":
+			if depth == 0 {
+				if other > 0 && !strings.EqualFold(prop.value, "VCALENDAR") {
+					other--
+				}
+				continue
+			}
+			depth--
+			if depth == 0 {
+				events = append(events, current)
+			}
+			continue
+		}
+		switch {
+		case depth == 1:
+			current = append(current, prop)
+		case depth == 0 && other == 0:
+			calendarProps = append(calendarProps, prop)
+		}
+	}
+	if depth > 0 {
+		return nil, nil, fmt.Errorf("unterminated VEVENT")
+	}
+	return calendarProps, events, nil
+}
+
+// ParseInvitation reads the invitation in the text/calendar part of a
+// message: the calendar's METHOD and its first event, with the organizer
+// and attendees. Unlike Parse, it keeps cancelled events and single
+// instances of recurring ones, since invitations announce both.
+func ParseInvitation(r io.Reader) (*mail.Invitation, error) {
+	calendarProps, components, err := split(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("calendar has no event")
+	}
+
+	props := components[0]
+	event, err := readEvent(props)
+	if err != nil {
+		return nil, err
+	}
+	inv := &mail.Invitation{
+		Method:    mail.MethodRequest,
+		UID:       event.ICalUID,
+		Summary:   event.Title,
+		Location:  event.Location,
+		Start:     event.Start,
+		End:       event.End,
+		AllDay:    event.AllDay,
+		Recurring: len(event.Recurrence) > 0,
+		Attendees: []*mail.InvitationAttendee{},
+	}
+	for _, prop := range calendarProps {
+		if prop.name == "METHOD" {
+			inv.Method = strings.ToUpper(strings.TrimSpace(prop.value))
+		}
+	}
+	if event.Status == calendar.StatusCancelled {
+		inv.Method = mail.MethodCancel
+	}
+	for _, prop := range props {
+		switch prop.name {
+		case "ORGANIZER":
+			inv.Organizer = calAddress(prop)
+		case "ATTENDEE":
+			inv.Attendees = append(inv.Attendees, &mail.InvitationAttendee{
+				Address:  calAddress(prop),
+				Response: partStat(prop.params["PARTSTAT"]),
+				Optional: strings.EqualFold(prop.params["ROLE"], "OPT-PARTICIPANT"),
+			})
+		case "RECURRENCE-ID":
+			if inv.RecurrenceID, _, err = parseTime(prop); err != nil {
+				return nil, fmt.Errorf("event %q: invalid RECURRENCE-ID: %w", eventName(event), err)
+			}
+		}
+	}
+	return inv, nil
+}
+
+// calAddress reads an ORGANIZER or ATTENDEE value, a mailto: URI with the
+// name in its CN parameter.
+func calAddress(prop property) mail.Address {
+	email := strings.TrimSpace(prop.value)
+	if len(email) > len("mailto:") && strings.EqualFold(email[:len("mailto:")], "mailto:") {
+		email = email[len("mailto:"):]
+	}
+	return mail.Address{Name: prop.params["CN"], Email: email}
+}
+
+// partStat maps an attendee's PARTSTAT to an invitation response.
+func partStat(value string) string {
+	switch strings.ToUpper(value) {
+	case "ACCEPTED":
+		return mail.ResponseAccepted
+	case "DECLINED":
+		return mail.ResponseDeclined
+	case "TENTATIVE":
+		return mail.ResponseTentative
+	default:
+		return mail.ResponseNeedsAction
+	}
+}
+
+// unfold reads the content lines of r, joining folded continuation lines,
+// which start with a space or tab, to the line before.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into its name, parameters and value.
+// Parameter values may be quoted, and a quoted value may hold ':' or ';'.
+func parseLine(line string) (property, error) {
+	prop := property{raw: line, params: make(map[string]string)}
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.value = line[colon+1:]
+
+	parts := splitParams(line[:colon])
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// splitParams splits a property name and its parameters at semicolons
+// outside quotes.
+func splitParams(s string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// toEvent builds an event from the properties of a VEVENT, or returns nil
+// for one that is not imported.
+func toEvent(props []property) (*calendar.Event, error) {
+	for _, prop := range props {
+		if prop.name == "RECURRENCE-ID" {
+			return nil, nil
+		}
+	}
+	event, err := readEvent(props)
+	if err != nil || event.Status == calendar.StatusCancelled {
+		return nil, err
+	}
+	return event, nil
+}
+
+// readEvent builds an event from the properties of a VEVENT.
+func readEvent(props []property) (*calendar.Event, error) {
+	event := &calendar.Event{
+		Status:     calendar.StatusConfirmed,
+		Visibility: calendar.VisibilityPrivate,
+		Attendees:  make([]*calendar.Attendee, 0),
+		Reminders:  make([]*calendar.Reminder, 0),
+	}
+	var start, end *property
+	var duration string
+	for i := range props {
+		prop := &props[i]
+		switch prop.name {
+		case "UID":
+			event.ICalUID = prop.value
+		case "SUMMARY":
+			event.Title = unescape(prop.value)
+		case "DESCRIPTION":
+			event.Description = unescape(prop.value)
+		case "LOCATION":
+			event.Location = unescape(prop.value)
+		case "DTSTART":
+			start = prop
+		case "DTEND":
+			end = prop
+		case "DURATION":
+			duration = prop.value
+		case "RRULE", "EXRULE", "RDATE", "EXDATE":
+			event.Recurrence = append(event.Recurrence, prop.raw)
+		case "STATUS":
+			switch strings.ToUpper(prop.value) {
+			case "CANCELLED":
+				event.Status = calendar.StatusCancelled
+			case "TENTATIVE":
+				event.Status = calendar.StatusTentative
+			}
+		case "CLASS":
+			if strings.EqualFold(prop.value, "PUBLIC") {
+				event.Visibility = calendar.VisibilityPublic
+			}
+		}
+	}
+
+	if start == nil {
+		return nil, fmt.Errorf("event %q has no DTSTART", eventName(event))
+	}
+	var err error
+	if event.Start, event.AllDay, err = parseTime(*start); err != nil {
+		return nil, fmt.Errorf("event %q: invalid DTSTART: %w", eventName(event), err)
+	}
+
+	switch {
+	case end != nil:
+		if event.End, _, err = parseTime(*end); err != nil {
+			return nil, fmt.Errorf("event %q: invalid DTEND: %w", eventName(event), err)
+		}
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: invalid DURATION: %w", eventName(event), err)
+		}
+		event.End = event.Start.Add(d)
+	case event.AllDay:
+		event.End = event.Start.AddDate(0, 0, 1)
+	default:
+		event.End = event.Start
+	}
+	if event.End.Before(event.Start) {
+		return nil, fmt.Errorf("event %q ends before it starts", eventName(event))
+	}
+	return event, nil
+}
+
+// eventName names an event in errors.
+func eventName(event *calendar.Event) string {
+	if event.Title != "" {
+		return event.Title
+	}
+	return event.ICalUID
+}
+
+// parseTime parses a DTSTART or DTEND value, reporting whether it is a
+// date. Dates and floating times are in the local time zone; times with a
+// TZID the system does not know, such as Windows zone names, are too.
+func parseTime(prop property) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLayout, strings.TrimSuffix(value, "Z"))
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, loc)
+	return t, false, err
+}
+
+// parseDuration parses an iCalendar duration such as "PT1H30M", "P1D" or
+// "P2W".
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "+")
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("negative duration %q", s)
+	}
+	rest, ok := strings.CutPrefix(s, "P")
+	if !ok || rest == "" {
+		return 0, fmt.Errorf("malformed duration %q", s)
+	}
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, r := range rest {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("malformed duration %q", s)
+		}
+		num = ""
+		switch {
+		case r == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("malformed duration %q", s)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("malformed duration %q", s)
+	}
+	return total, nil
+}
+
+// unescape undoes the escaping of a TEXT value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
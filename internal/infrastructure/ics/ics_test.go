@@ -0,0 +1,201 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"SUMMARY:Team standup\\, daily\r\n" +
+	"DESCRIPTION:Line one\\nLine \r\n" +
+	" two\r\n" +
+	"DTSTART;TZID=\"America/New_York\":20240304T090000\r\n" +
+	"DURATION:PT15M\r\n" +
+	"RRULE:FREQ=DAILY;COUNT=5\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20240501\r\n" +
+	"STATUS:TENTATIVE\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"RECURRENCE-ID;TZID=America/New_York:20240305T090000\r\n" +
+	"SUMMARY:Moved standup\r\n" +
+	"DTSTART:20240305T150000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:gone@example.com\r\n" +
+	"SUMMARY:Cancelled\r\n" +
+	"DTSTART:20240306T150000Z\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Review\r\n" +
+	"LOCATION:Room 4\\; east wing\r\n" +
+	"DTSTART:20240307T150000Z\r\n" +
+	"DTEND:20240307T160000Z\r\n" +
+	"CLASS:PUBLIC\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Parse() returned %d events, want 3", len(events))
+	}
+
+	standup := events[0]
+	ny, _ := time.LoadLocation("America/New_York")
+	wantStart := time.Date(2024, 3, 4, 9, 0, 0, 0, ny)
+	if standup.ICalUID != "standup@example.com" || standup.Title != "Team standup, daily" {
+		t.Errorf("standup = %q %q", standup.ICalUID, standup.Title)
+	}
+	if standup.Description != "Line one\nLine two" {
+		t.Errorf("description = %q, want the unfolded, unescaped text", standup.Description)
+	}
+	if !standup.Start.Equal(wantStart) || !standup.End.Equal(wantStart.Add(15*time.Minute)) {
+		t.Errorf("standup runs %v to %v", standup.Start, standup.End)
+	}
+	if len(standup.Recurrence) != 1 || standup.Recurrence[0] != "RRULE:FREQ=DAILY;COUNT=5" {
+		t.Errorf("recurrence = %q", standup.Recurrence)
+	}
+
+	holiday := events[1]
+	if !holiday.AllDay || holiday.Start.Format("2006-01-02") != "2024-05-01" || !holiday.End.Equal(holiday.Start.AddDate(0, 0, 1)) {
+		t.Errorf("holiday = %+v, want an all-day event on 2024-05-01", holiday)
+	}
+	if holiday.Status != calendar.StatusTentative {
+		t.Errorf("holiday status = %q, want tentative", holiday.Status)
+	}
+
+	review := events[2]
+	if review.Location != "Room 4; east wing" || review.Visibility != calendar.VisibilityPublic {
+		t.Errorf("review = %q %q", review.Location, review.Visibility)
+	}
+	if review.End.Sub(review.Start) != time.Hour {
+		t.Errorf("review lasts %v, want 1h", review.End.Sub(review.Start))
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no start":     "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n",
+		"bad start":    "BEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\n",
+		"unterminated": "BEGIN:VEVENT\nDTSTART:20240307T150000Z\n",
+		"malformed":    "BEGIN:VEVENT\nDTSTART\nEND:VEVENT\n",
+		"ends early":   "BEGIN:VEVENT\nDTSTART:20240307T150000Z\nDTEND:20240307T140000Z\nEND:VEVENT\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(input)); err == nil {
+				t.Error("Parse() error = nil, want an error")
+			}
+		})
+	}
+}
+
+const invite = "BEGIN:VCALENDAR\r\n" +
+	"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Paris\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701025T030000\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T093000Z\r\n" +
+	"UID:7kukuqrfedlm2f9t0vr42q7ksk@google.com\r\n" +
+	"ORGANIZER;CN=Ana Lima:mailto:ana@example.com\r\n" +
+	"ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;CN=Ana Lima:\r\n" +
+	" mailto:ana@example.com\r\n" +
+	"ATTENDEE;ROLE=OPT-PARTICIPANT;PARTSTAT=NEEDS-ACTION;CN=me@example.com:MAILTO:me@example.com\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"LOCATION:Room 2\r\n" +
+	"RRULE:FREQ=WEEKLY\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseInvitation(t *testing.T) {
+	inv, err := ParseInvitation(strings.NewReader(invite))
+	if err != nil {
+		t.Fatalf("ParseInvitation() error = %v", err)
+	}
+	if inv.Method != mail.MethodRequest || inv.UID != "7kukuqrfedlm2f9t0vr42q7ksk@google.com" || inv.Summary != "Design review" || !inv.Recurring {
+		t.Errorf("invitation = %+v", inv)
+	}
+	if !inv.Start.Equal(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)) || inv.End.Sub(inv.Start) != 30*time.Minute {
+		t.Errorf("invitation runs %v to %v", inv.Start, inv.End)
+	}
+	if inv.Organizer.String() != "Ana Lima <ana@example.com>" {
+		t.Errorf("organizer = %q", inv.Organizer)
+	}
+	if len(inv.Attendees) != 2 || inv.ResponseOf("ana@example.com") != mail.ResponseAccepted {
+		t.Fatalf("attendees = %+v", inv.Attendees)
+	}
+	if me := inv.Attendee("me@example.com"); me == nil || !me.Optional || me.Response != mail.ResponseNeedsAction {
+		t.Errorf("me = %+v", me)
+	}
+}
+
+func TestParseInvitation_Cancelled(t *testing.T) {
+	input := "BEGIN:VCALENDAR\nMETHOD:CANCEL\nBEGIN:VEVENT\nUID:x@example.com\n" +
+		"RECURRENCE-ID:20260309T090000Z\nDTSTART:20260309T090000Z\nSTATUS:CANCELLED\nEND:VEVENT\nEND:VCALENDAR\n"
+	inv, err := ParseInvitation(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseInvitation() error = %v", err)
+	}
+	if !inv.IsCancelled() || !inv.RecurrenceID.Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("invitation = %+v", inv)
+	}
+
+	if _, err := ParseInvitation(strings.NewReader("BEGIN:VCALENDAR\nMETHOD:REQUEST\nEND:VCALENDAR\n")); err == nil {
+		t.Error("expected an error for a calendar without an event")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "PT1H30M", want: 90 * time.Minute},
+		{input: "P1D", want: 24 * time.Hour},
+		{input: "P2W", want: 14 * 24 * time.Hour},
+		{input: "P1DT2H", want: 26 * time.Hour},
+		{input: "PT45S", want: 45 * time.Second},
+		{input: "-PT1H", wantErr: true},
+		{input: "P", wantErr: true},
+		{input: "PT1", wantErr: true},
+		{input: "P1H", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/99designs/keyring"
+)
+
+// Backend names accepted by SetBackend and Diagnose.
+const (
+	BackendAuto          = "auto"
+	BackendKeychain      = "keychain"
+	BackendSecretService = "secret-service"
+	BackendWinCred       = "wincred"
+	BackendFile          = "file"
+)
+
+// backendTypes maps backend names to their keyring library types.
+var backendTypes = map[string]keyring.BackendType{
+	BackendKeychain:      keyring.KeychainBackend,
+	BackendSecretService: keyring.SecretServiceBackend,
+	BackendWinCred:       keyring.WinCredBackend,
+	BackendFile:          keyring.FileBackend,
+}
+
+// ErrUnknownBackend is returned for backend names that are not recognized.
+var ErrUnknownBackend = errors.New("unknown keyring backend")
+
+var (
+	backendMu        sync.RWMutex
+	preferredBackend = BackendAuto
+)
+
+// ValidateBackend checks that name is auto or a known backend.
+func ValidateBackend(name string) error {
+	if name == BackendAuto {
+		return nil
+	}
+	if _, ok := backendTypes[name]; !ok {
+		return fmt.Errorf("%w %q: must be auto, keychain, secret-service, wincred or file", ErrUnknownBackend, name)
+	}
+	return nil
+}
+
+// SetBackend sets the backend used by NewStore. An empty name selects auto.
+func SetBackend(name string) error {
+	if name == "" {
+		name = BackendAuto
+	}
+	if err := ValidateBackend(name); err != nil {
+		return err
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	preferredBackend = name
+	return nil
+}
+
+// CurrentBackend returns the backend used by NewStore.
+func CurrentBackend() string {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return preferredBackend
+}
+
+// platformBackend returns the system keyring backend for the current OS,
+// or an empty string when there is none.
+func platformBackend() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return BackendKeychain
+	case "linux":
+		return BackendSecretService
+	case "windows":
+		return BackendWinCred
+	}
+	return ""
+}
+
+// SupportedBackends returns the backends that can be selected on this
+// platform, system keyring first.
+func SupportedBackends() []string {
+	if b := platformBackend(); b != "" {
+		return []string{b, BackendFile}
+	}
+	return []string{BackendFile}
+}
+
+// openedBackend is a keyring opened by openBackend.
+type openedBackend struct {
+	ring keyring.Keyring
+
+	// name is the backend that opened.
+	name string
+
+	// skipped is why auto passed over the system keyring, if it did.
+	skipped error
+}
+
+// openBackend opens the named backend. For auto it tries the system keyring
+// and then the file backend.
+func openBackend(configDir, name string) (openedBackend, error) {
+	candidates := []string{name}
+	if name == BackendAuto {
+		candidates = SupportedBackends()
+	}
+
+	var skipped error
+	for _, candidate := range candidates {
+		ring, err := keyring.Open(keyringConfig(configDir, backendTypes[candidate]))
+		if err == nil {
+			return openedBackend{ring: ring, name: candidate, skipped: skipped}, nil
+		}
+		if name != BackendAuto {
+			return openedBackend{}, fmt.Errorf("keyring backend %s is unavailable: %w", candidate, err)
+		}
+		if skipped == nil {
+			skipped = fmt.Errorf("%s: %w", candidate, err)
+		}
+	}
+	return openedBackend{}, fmt.Errorf("no keyring backend is available: %w", skipped)
+}
+
+// keyringConfig returns the keyring library configuration for a backend.
+func keyringConfig(configDir string, backend keyring.BackendType) keyring.Config {
+	return keyring.Config{
+		ServiceName:                    ServiceName,
+		AllowedBackends:                []keyring.BackendType{backend},
+		FileDir:                        filepath.Join(configDir, "keyring"),
+		FilePasswordFunc:               keyring.FixedStringPrompt(deriveMachinePassword()),
+		KeychainTrustApplication:       true,
+		KeychainSynchronizable:         false,
+		KeychainAccessibleWhenUnlocked: true,
+	}
+}
+
+// Status describes the keyring backend in use.
+type Status struct {
+	// Requested is the backend asked for: auto or a backend name.
+	Requested string `json:"requested"`
+
+	// Backend is the backend actually in use; empty if none could be opened.
+	Backend string `json:"backend,omitempty"`
+
+	// Fallback is true when auto could not use the system keyring.
+	Fallback bool `json:"fallback"`
+
+	// Unlockable reports whether stored items could be listed.
+	Unlockable bool `json:"unlockable"`
+
+	// Location is the directory holding the items for file storage.
+	Location string `json:"location,omitempty"`
+
+	// Protector is the hardware key new token files are bound to.
+	Protector string `json:"protector,omitempty"`
+
+	// Supported lists the backends that can be selected on this platform.
+	Supported []string `json:"supported"`
+
+	// Reason explains a fallback, an open failure or an unlock failure.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Diagnose opens the named backend, or the one set with SetBackend when name
+// is empty, and reports which backend is active and whether it can be
+// unlocked. Listing the items may prompt to unlock the system keyring.
+func Diagnose(name string) Status {
+	if name == "" {
+		name = CurrentBackend()
+	}
+	status := Status{Requested: name, Supported: SupportedBackends()}
+	if err := ValidateBackend(name); err != nil {
+		status.Reason = err.Error()
+		return status
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		status.Reason = fmt.Sprintf("failed to get config directory: %v", err)
+		return status
+	}
+
+	opened, err := openBackend(configDir, name)
+	if err != nil && name == BackendAuto {
+		// NewStore falls back to encrypted token files
+		status.Backend = BackendFile
+		status.Fallback = true
+		status.Location = filepath.Join(configDir, "tokens")
+		if p := CurrentProtector(); p != nil {
+			status.Protector = p.Spec()
+		}
+		status.Reason = err.Error()
+		if _, err := NewFileStore(configDir); err != nil {
+			status.Reason += fmt.Sprintf("; %v", err)
+			return status
+		}
+		status.Unlockable = true
+		return status
+	}
+	if err != nil {
+		status.Reason = err.Error()
+		return status
+	}
+	status.Backend = opened.name
+	if opened.skipped != nil {
+		status.Fallback = true
+		status.Reason = fmt.Sprintf("system keyring unavailable: %v", opened.skipped)
+	}
+	if opened.name == BackendFile {
+		status.Location = filepath.Join(configDir, "keyring")
+	}
+
+	if _, err := opened.ring.Keys(); err != nil {
+		reason := fmt.Sprintf("failed to unlock: %v", err)
+		if status.Reason != "" {
+			reason = status.Reason + "; " + reason
+		}
+		status.Reason = reason
+		return status
+	}
+	status.Unlockable = true
+	return status
+}
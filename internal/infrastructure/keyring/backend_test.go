@@ -0,0 +1,124 @@
+package keyring
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// setBackendForTest sets the preferred backend and restores it afterwards.
+func setBackendForTest(t *testing.T, name string) {
+	t.Helper()
+	orig := CurrentBackend()
+	if err := SetBackend(name); err != nil {
+		t.Fatalf("SetBackend(%q) failed: %v", name, err)
+	}
+	t.Cleanup(func() { _ = SetBackend(orig) })
+}
+
+func TestValidateBackend(t *testing.T) {
+	for _, name := range []string{BackendAuto, BackendKeychain, BackendSecretService, BackendWinCred, BackendFile} {
+		if err := ValidateBackend(name); err != nil {
+			t.Errorf("ValidateBackend(%q) returned error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "kwallet", "File"} {
+		if err := ValidateBackend(name); !errors.Is(err, ErrUnknownBackend) {
+			t.Errorf("ValidateBackend(%q) = %v, want ErrUnknownBackend", name, err)
+		}
+	}
+}
+
+func TestSetBackend(t *testing.T) {
+	setBackendForTest(t, BackendFile)
+	if got := CurrentBackend(); got != BackendFile {
+		t.Errorf("CurrentBackend() = %q, want file", got)
+	}
+
+	if err := SetBackend(""); err != nil {
+		t.Fatalf("SetBackend(\"\") failed: %v", err)
+	}
+	if got := CurrentBackend(); got != BackendAuto {
+		t.Errorf("CurrentBackend() = %q, want auto", got)
+	}
+
+	if err := SetBackend("kwallet"); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("expected ErrUnknownBackend, got %v", err)
+	}
+	if got := CurrentBackend(); got != BackendAuto {
+		t.Errorf("invalid backend changed CurrentBackend() to %q", got)
+	}
+}
+
+func TestSupportedBackends(t *testing.T) {
+	backends := SupportedBackends()
+	if len(backends) == 0 || backends[len(backends)-1] != BackendFile {
+		t.Errorf("SupportedBackends() = %v, want file last", backends)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	t.Run("file backend", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+
+		status := Diagnose(BackendFile)
+		if status.Requested != BackendFile || status.Backend != BackendFile {
+			t.Fatalf("unexpected status: %+v", status)
+		}
+		if !status.Unlockable {
+			t.Errorf("expected file backend to be unlockable: %s", status.Reason)
+		}
+		if status.Fallback {
+			t.Error("explicit file backend should not be a fallback")
+		}
+		if want := filepath.Join(home, ".config", "goog", "keyring"); status.Location != want {
+			t.Errorf("Location = %q, want %q", status.Location, want)
+		}
+	})
+
+	t.Run("uses preferred backend when name is empty", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		setBackendForTest(t, BackendFile)
+
+		if status := Diagnose(""); status.Requested != BackendFile {
+			t.Errorf("Requested = %q, want file", status.Requested)
+		}
+	})
+
+	t.Run("auto opens a backend", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		status := Diagnose(BackendAuto)
+		if status.Backend == "" {
+			t.Fatalf("expected auto to open a backend: %+v", status)
+		}
+		if status.Backend == BackendFile && !status.Fallback {
+			t.Error("file backend under auto should be reported as a fallback")
+		}
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		status := Diagnose("kwallet")
+		if status.Backend != "" || status.Reason == "" {
+			t.Errorf("unexpected status: %+v", status)
+		}
+	})
+}
+
+func TestNewStoreWithFileBackend(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	setBackendForTest(t, BackendFile)
+
+	store, err := NewStore()
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	if err := store.Set("acct", "token", []byte("value")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	got, err := store.Get("acct", "token")
+	if err != nil || string(got) != "value" {
+		t.Errorf("Get() = %q, %v; want value", got, err)
+	}
+}
@@ -0,0 +1,220 @@
+package keyring
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// ProtectorPluginPrefix is the prefix of the executables that implement
+// plugin protectors: plugin:yubikey runs goog-keyring-yubikey.
+const ProtectorPluginPrefix = "goog-keyring-"
+
+// minProtectorSecret is the shortest secret accepted from a protector.
+const minProtectorSecret = 16
+
+// ErrProtectorUnavailable is returned when the hardware key a token file is
+// bound to cannot be used, e.g. the SSH agent does not hold the key or the
+// plugin is not installed.
+var ErrProtectorUnavailable = errors.New("hardware key for the token files is unavailable")
+
+// Protector binds the key of the encrypted token files to a hardware-backed
+// key, so the files cannot be decrypted without it.
+type Protector interface {
+	// Spec identifies the key in token files, e.g. ssh-agent:SHA256:...
+	// It is only complete after a successful call to Secret.
+	Spec() string
+
+	// Secret returns a secret derived from challenge by the hardware key.
+	// The same challenge must always give the same secret.
+	Secret(challenge []byte) ([]byte, error)
+}
+
+var preferredProtector Protector
+
+// ParseProtector returns the protector for a spec: ssh-agent (the first
+// suitable key in the agent), ssh-agent:<fingerprint> or plugin:<name>. An
+// empty spec or "none" is no protector.
+func ParseProtector(spec string) (Protector, error) {
+	switch {
+	case spec == "" || spec == "none":
+		return nil, nil
+	case spec == "ssh-agent":
+		return &sshAgentProtector{}, nil
+	case strings.HasPrefix(spec, "ssh-agent:"):
+		fingerprint := strings.TrimPrefix(spec, "ssh-agent:")
+		if fingerprint == "" {
+			return nil, fmt.Errorf("invalid protector %q: missing key fingerprint", spec)
+		}
+		return &sshAgentProtector{fingerprint: fingerprint}, nil
+	case strings.HasPrefix(spec, "plugin:"):
+		name := strings.TrimPrefix(spec, "plugin:")
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid protector %q: bad plugin name", spec)
+		}
+		return &pluginProtector{name: name}, nil
+	}
+	return nil, fmt.Errorf("invalid protector %q: must be ssh-agent, ssh-agent:<fingerprint> or plugin:<name>", spec)
+}
+
+// SetProtector sets the protector used for token files written by stores
+// created afterwards. An empty spec turns protection off; files already
+// protected still need their key to be read.
+func SetProtector(spec string) error {
+	p, err := ParseProtector(spec)
+	if err != nil {
+		return err
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	preferredProtector = p
+	return nil
+}
+
+// CurrentProtector returns the protector set with SetProtector, or nil.
+func CurrentProtector() Protector {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return preferredProtector
+}
+
+// protectorChallenge is the challenge a protector answers for a token file.
+// The salt changes with every save, so each file version has its own secret.
+func protectorChallenge(account string, salt []byte) []byte {
+	return []byte(fmt.Sprintf("go-goog-cli-file-store:%s:%x", account, salt))
+}
+
+// dialAgent connects to the SSH agent and returns it with the connection
+// to close. It is a variable so tests can use an in-memory agent.
+var dialAgent = func() (agent.ExtendedAgent, io.Closer, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, nil, fmt.Errorf("%w: SSH_AUTH_SOCK is not set", ErrProtectorUnavailable)
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cannot connect to the SSH agent: %v", ErrProtectorUnavailable, err)
+	}
+	return agent.NewClient(conn), conn, nil
+}
+
+// sshAgentProtector derives secrets from signatures made by a key in the
+// SSH agent. Only key types with deterministic signatures are usable:
+// Ed25519 and RSA. ECDSA and security key (sk-) signatures differ on every
+// call.
+type sshAgentProtector struct {
+	fingerprint string
+}
+
+// Spec returns ssh-agent:<fingerprint>.
+func (p *sshAgentProtector) Spec() string {
+	if p.fingerprint == "" {
+		return "ssh-agent"
+	}
+	return "ssh-agent:" + p.fingerprint
+}
+
+// Secret signs challenge with the agent key and hashes the signature.
+func (p *sshAgentProtector) Secret(challenge []byte) ([]byte, error) {
+	ag, conn, err := dialAgent()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	keys, err := ag.List()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot list SSH agent keys: %v", ErrProtectorUnavailable, err)
+	}
+
+	var key *agent.Key
+	for _, k := range keys {
+		if p.fingerprint != "" && ssh.FingerprintSHA256(k) != p.fingerprint {
+			continue
+		}
+		if k.Type() != ssh.KeyAlgoED25519 && k.Type() != ssh.KeyAlgoRSA {
+			if p.fingerprint != "" {
+				return nil, fmt.Errorf("SSH key %s is %s: only ed25519 and rsa keys sign deterministically", p.fingerprint, k.Type())
+			}
+			continue
+		}
+		key = k
+		break
+	}
+	if key == nil {
+		if p.fingerprint != "" {
+			return nil, fmt.Errorf("%w: the SSH agent does not hold key %s", ErrProtectorUnavailable, p.fingerprint)
+		}
+		return nil, fmt.Errorf("%w: the SSH agent holds no ed25519 or rsa key", ErrProtectorUnavailable)
+	}
+
+	var sig *ssh.Signature
+	if key.Type() == ssh.KeyAlgoRSA {
+		sig, err = ag.SignWithFlags(key, challenge, agent.SignatureFlagRsaSha256)
+	} else {
+		sig, err = ag.Sign(key, challenge)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SSH agent failed to sign: %w", err)
+	}
+
+	p.fingerprint = ssh.FingerprintSHA256(key)
+	sum := sha256.Sum256(sig.Blob)
+	return sum[:], nil
+}
+
+// runPlugin runs a protector plugin. It is a variable so tests can avoid
+// executing programs.
+var runPlugin = func(name string, challenge []byte) ([]byte, error) {
+	path, err := exec.LookPath(ProtectorPluginPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s%s is not installed", ErrProtectorUnavailable, ProtectorPluginPrefix, name)
+	}
+	cmd := exec.Command(path, "derive")
+	cmd.Stdin = strings.NewReader(hex.EncodeToString(challenge) + "\n")
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s%s failed: %w", ProtectorPluginPrefix, name, err)
+	}
+	return out, nil
+}
+
+// pluginProtector derives secrets with an external program, in the style
+// of age plugins, so FIDO2 (hmac-secret) and TPM keys can be supported
+// without goog linking their libraries. goog-keyring-<name> is run with
+// the argument "derive", reads the hex challenge as one line on stdin and
+// writes the hex secret as one line on stdout. Prompts such as "touch
+// your key" go to stderr.
+type pluginProtector struct {
+	name string
+}
+
+// Spec returns plugin:<name>.
+func (p *pluginProtector) Spec() string {
+	return "plugin:" + p.name
+}
+
+// Secret runs the plugin and decodes its answer.
+func (p *pluginProtector) Secret(challenge []byte) ([]byte, error) {
+	out, err := runPlugin(p.name, challenge)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("%s%s returned an invalid secret: %w", ProtectorPluginPrefix, p.name, err)
+	}
+	if len(secret) < minProtectorSecret {
+		return nil, fmt.Errorf("%s%s returned a secret shorter than %d bytes", ProtectorPluginPrefix, p.name, minProtectorSecret)
+	}
+	return secret, nil
+}
@@ -0,0 +1,177 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// nopCloser stands in for the agent connection.
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// useAgent replaces the SSH agent with an in-memory one holding keys.
+func useAgent(t *testing.T, keys ...any) agent.Agent {
+	t.Helper()
+	ag := agent.NewKeyring()
+	for _, key := range keys {
+		if err := ag.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orig := dialAgent
+	dialAgent = func() (agent.ExtendedAgent, io.Closer, error) {
+		return ag.(agent.ExtendedAgent), nopCloser{}, nil
+	}
+	t.Cleanup(func() { dialAgent = orig })
+	return ag
+}
+
+// useProtector sets the protector for stores created in the test.
+func useProtector(t *testing.T, spec string) {
+	t.Helper()
+	if err := SetProtector(spec); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetProtector("") })
+}
+
+func TestParseProtector(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"none", "", false},
+		{"ssh-agent", "ssh-agent", false},
+		{"ssh-agent:SHA256:abc", "ssh-agent:SHA256:abc", false},
+		{"plugin:yubikey", "plugin:yubikey", false},
+		{"ssh-agent:", "", true},
+		{"plugin:../evil", "", true},
+		{"tpm", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, err := ParseProtector(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := ""
+			if p != nil {
+				got = p.Spec()
+			}
+			if got != tt.want {
+				t.Errorf("Spec() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileStoreSSHAgentProtector(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	ag := useAgent(t, key)
+	useProtector(t, "ssh-agent")
+
+	dir := t.TempDir()
+	store, _ := NewFileStore(dir)
+	if err := store.Set("work", "token", []byte("secret")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	signer, _ := ssh.NewSignerFromKey(key)
+	fingerprint := ssh.FingerprintSHA256(signer.PublicKey())
+	fileData, _ := os.ReadFile(store.tokenFilePath("work"))
+	var encFile encryptedFile
+	if err := json.Unmarshal(fileData, &encFile); err != nil || encFile.Protector != "ssh-agent:"+fingerprint {
+		t.Fatalf("expected the file to record the key, got %q, %v", encFile.Protector, err)
+	}
+
+	// Reading works with protection turned off as long as the key is there
+	_ = SetProtector("")
+	reopened, _ := NewFileStore(dir)
+	if value, err := reopened.Get("work", "token"); err != nil || string(value) != "secret" {
+		t.Errorf("Get = %q, %v", value, err)
+	}
+
+	// Without the key the file cannot be read
+	if err := ag.RemoveAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Get("work", "token"); !errors.Is(err, ErrProtectorUnavailable) {
+		t.Errorf("expected ErrProtectorUnavailable, got %v", err)
+	}
+}
+
+func TestSSHAgentProtectorRejectsECDSA(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	useAgent(t, key)
+
+	p, _ := ParseProtector("ssh-agent")
+	if _, err := p.Secret([]byte("challenge")); !errors.Is(err, ErrProtectorUnavailable) {
+		t.Errorf("expected no usable key, got %v", err)
+	}
+
+	signer, _ := ssh.NewSignerFromKey(key)
+	p, _ = ParseProtector("ssh-agent:" + ssh.FingerprintSHA256(signer.PublicKey()))
+	if _, err := p.Secret([]byte("challenge")); err == nil || !strings.Contains(err.Error(), "deterministically") {
+		t.Errorf("expected an ECDSA key to be rejected, got %v", err)
+	}
+}
+
+func TestFileStorePluginProtector(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	present := true
+	var gotName string
+	orig := runPlugin
+	runPlugin = func(name string, challenge []byte) ([]byte, error) {
+		gotName = name
+		if !present {
+			return nil, ErrProtectorUnavailable
+		}
+		sum := sha256.Sum256(append([]byte("hardware:"), challenge...))
+		return []byte(hex.EncodeToString(sum[:]) + "\n"), nil
+	}
+	t.Cleanup(func() { runPlugin = orig })
+	useProtector(t, "plugin:yubikey")
+
+	store, _ := NewFileStore(t.TempDir())
+	if err := store.Set("work", "token", []byte("secret")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, err := store.Get("work", "token"); err != nil || string(value) != "secret" || gotName != "yubikey" {
+		t.Errorf("Get = %q, %v (plugin %q)", value, err, gotName)
+	}
+
+	present = false
+	if _, err := store.Get("work", "token"); !errors.Is(err, ErrProtectorUnavailable) {
+		t.Errorf("expected ErrProtectorUnavailable, got %v", err)
+	}
+}
+
+func TestPluginProtectorRejectsShortSecret(t *testing.T) {
+	orig := runPlugin
+	runPlugin = func(name string, challenge []byte) ([]byte, error) {
+		return []byte("abcd\n"), nil
+	}
+	t.Cleanup(func() { runPlugin = orig })
+
+	p, _ := ParseProtector("plugin:short")
+	if _, err := p.Secret([]byte("challenge")); err == nil {
+		t.Error("expected a short secret to be rejected")
+	}
+}
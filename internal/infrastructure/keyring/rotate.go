@@ -0,0 +1,248 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// rotateSuffix is appended to a token file path for the re-encrypted copy
+// written before it replaces the original.
+const rotateSuffix = ".rotate"
+
+// RotatedAccount describes the token file of one account handled by a
+// rotation.
+type RotatedAccount struct {
+	Account string `json:"account"`
+	Keys    int    `json:"keys"`
+}
+
+// OpenFileStore opens the encrypted token files at ~/.config/goog/tokens/
+// regardless of the configured backend.
+func OpenFileStore() (*FileStore, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return NewFileStore(configDir)
+}
+
+// OpenSystemStore opens the system keyring of this platform and checks
+// that it can be unlocked. It returns the store and the backend name.
+func OpenSystemStore() (Store, string, error) {
+	backend := platformBackend()
+	if backend == "" {
+		return nil, "", errors.New("no system keyring is supported on this platform")
+	}
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	opened, err := openBackend(configDir, backend)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := opened.ring.Keys(); err != nil {
+		return nil, "", fmt.Errorf("keyring backend %s cannot be unlocked: %w", backend, err)
+	}
+	return &KeyringStore{ring: opened.ring}, backend, nil
+}
+
+// Accounts returns the accounts that have a token file, sorted.
+func (s *FileStore) Accounts() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.baseDir, "tokens"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to list token files: %w", err)
+	}
+	accounts := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".enc") {
+			continue
+		}
+		accounts = append(accounts, strings.TrimSuffix(name, ".enc"))
+	}
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// Check reads every token file and returns the accounts and the number of
+// keys in each. It fails on the first file that cannot be read.
+func (s *FileStore) Check() ([]RotatedAccount, error) {
+	accounts, all, err := s.loadAll()
+	if err != nil {
+		return nil, err
+	}
+	return summarize(accounts, all), nil
+}
+
+// Rotate re-encrypts every token file with a new salt and the given
+// passphrase; an empty passphrase leaves the files protected by the
+// machine identity alone. Every file is read first, and each re-encrypted
+// copy is written next to the original, read back and compared before any
+// original is replaced, so a failure leaves all token files as they were.
+// The store uses the new passphrase afterwards.
+func (s *FileStore) Rotate(passphrase string) ([]RotatedAccount, error) {
+	unlock, err := s.lockAll()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, all, err := s.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var staged []string
+	discard := func() {
+		for _, path := range staged {
+			_ = os.Remove(path)
+		}
+	}
+	for _, account := range accounts {
+		fileData, err := s.encodeTokenData(account, all[account], passphrase)
+		if err != nil {
+			discard()
+			return nil, fmt.Errorf("failed to re-encrypt tokens for %s: %w", account, err)
+		}
+		path := s.tokenFilePath(account) + rotateSuffix
+		if err := os.WriteFile(path, fileData, 0600); err != nil {
+			discard()
+			return nil, fmt.Errorf("failed to write tokens for %s: %w", account, err)
+		}
+		staged = append(staged, path)
+		if err := s.verifyFile(account, path, passphrase, all[account]); err != nil {
+			discard()
+			return nil, err
+		}
+	}
+
+	for _, account := range accounts {
+		path := s.tokenFilePath(account)
+		if err := os.Rename(path+rotateSuffix, path); err != nil {
+			discard()
+			return nil, fmt.Errorf("failed to replace token file for %s: %w", account, err)
+		}
+	}
+	s.passphrase = passphrase
+	return summarize(accounts, all), nil
+}
+
+// MoveTo copies every token into dst and reads each one back. The token
+// files are deleted only once every entry has been verified; on failure
+// they are left in place.
+func (s *FileStore) MoveTo(dst Store) ([]RotatedAccount, error) {
+	unlock, err := s.lockAll()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	accounts, all, err := s.loadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		for key, value := range all[account].Tokens {
+			if err := dst.Set(account, key, value); err != nil {
+				return nil, fmt.Errorf("failed to copy %s for %s: %w", key, account, err)
+			}
+			got, err := dst.Get(account, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read back %s for %s: %w", key, account, err)
+			}
+			if !bytes.Equal(got, value) {
+				return nil, fmt.Errorf("%s for %s reads back different from the token file", key, account)
+			}
+		}
+	}
+
+	for _, account := range accounts {
+		if err := os.Remove(s.tokenFilePath(account)); err != nil {
+			return nil, fmt.Errorf("failed to delete token file for %s: %w", account, err)
+		}
+	}
+	return summarize(accounts, all), nil
+}
+
+// lockAll takes the lock of every token file and returns a function that
+// releases them.
+func (s *FileStore) lockAll() (func(), error) {
+	accounts, err := s.Accounts()
+	if err != nil {
+		return nil, err
+	}
+	var locks []*filelock.Lock
+	unlock := func() {
+		for _, lock := range locks {
+			_ = lock.Unlock()
+		}
+	}
+	for _, account := range accounts {
+		lock, err := s.lock(account)
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	return unlock, nil
+}
+
+// loadAll reads the token file of every account.
+func (s *FileStore) loadAll() ([]string, map[string]*tokenData, error) {
+	accounts, err := s.Accounts()
+	if err != nil {
+		return nil, nil, err
+	}
+	all := make(map[string]*tokenData, len(accounts))
+	for _, account := range accounts {
+		data, err := s.loadTokenData(account)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read tokens for %s: %w", account, err)
+		}
+		all[account] = data
+	}
+	return accounts, all, nil
+}
+
+// verifyFile reads the token file at path and checks that it holds want.
+func (s *FileStore) verifyFile(account, path, passphrase string, want *tokenData) error {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read back tokens for %s: %w", account, err)
+	}
+	got, err := s.decodeTokenData(account, fileData, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to read back tokens for %s: %w", account, err)
+	}
+	if len(got.Tokens) != len(want.Tokens) {
+		return fmt.Errorf("tokens for %s read back different from the original", account)
+	}
+	for key, value := range want.Tokens {
+		if !bytes.Equal(got.Tokens[key], value) {
+			return fmt.Errorf("tokens for %s read back different from the original", account)
+		}
+	}
+	return nil
+}
+
+// summarize returns the number of keys of each account.
+func summarize(accounts []string, all map[string]*tokenData) []RotatedAccount {
+	out := make([]RotatedAccount, len(accounts))
+	for i, account := range accounts {
+		out[i] = RotatedAccount{Account: account, Keys: len(all[account].Tokens)}
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// memStore is an in-memory Store standing in for the system keyring.
+type memStore struct {
+	items map[string][]byte
+	// corrupt makes Get return a different value than was set.
+	corrupt bool
+}
+
+func newMemStore() *memStore {
+	return &memStore{items: make(map[string][]byte)}
+}
+
+func (m *memStore) Set(account, key string, value []byte) error {
+	m.items[formatKey(account, key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *memStore) Get(account, key string) ([]byte, error) {
+	value, ok := m.items[formatKey(account, key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if m.corrupt {
+		return append(value, '!'), nil
+	}
+	return value, nil
+}
+
+func (m *memStore) Delete(account, key string) error {
+	delete(m.items, formatKey(account, key))
+	return nil
+}
+
+func (m *memStore) List(account string) ([]string, error) {
+	return nil, nil
+}
+
+// newRotateStore returns a FileStore in a temp dir holding tokens for two
+// accounts.
+func newRotateStore(t *testing.T) (*FileStore, string) {
+	t.Helper()
+	t.Setenv(PassphraseEnv, "")
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	for _, item := range []struct{ account, key, value string }{
+		{"personal", "token", "p-token"},
+		{"work", "token", "w-token"},
+		{"work", "scopes", "mail"},
+	} {
+		if err := store.Set(item.account, item.key, []byte(item.value)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	return store, dir
+}
+
+func TestFileStoreAccounts(t *testing.T) {
+	store, dir := newRotateStore(t)
+	// Lock and temporary files are not accounts
+	for _, name := range []string{".work.enc.tmp-1", "work.enc.lock", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, "tokens", name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accounts, err := store.Accounts()
+	if err != nil {
+		t.Fatalf("Accounts failed: %v", err)
+	}
+	if len(accounts) != 2 || accounts[0] != "personal" || accounts[1] != "work" {
+		t.Errorf("unexpected accounts: %v", accounts)
+	}
+}
+
+func TestFileStoreRotate(t *testing.T) {
+	store, dir := newRotateStore(t)
+	before, _ := os.ReadFile(store.tokenFilePath("work"))
+
+	rotated, err := store.Rotate("s3cret")
+	if err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if len(rotated) != 2 || rotated[1] != (RotatedAccount{Account: "work", Keys: 2}) {
+		t.Errorf("unexpected result: %+v", rotated)
+	}
+	after, _ := os.ReadFile(store.tokenFilePath("work"))
+	if string(after) == string(before) {
+		t.Error("expected the token file to be re-encrypted")
+	}
+	if _, err := os.Stat(store.tokenFilePath("work") + rotateSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected no staged file to remain")
+	}
+
+	// The files now need the passphrase
+	t.Setenv(PassphraseEnv, "")
+	reopened, _ := NewFileStore(dir)
+	if _, err := reopened.Get("work", "token"); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("expected ErrPassphraseRequired, got %v", err)
+	}
+	t.Setenv(PassphraseEnv, "wrong")
+	reopened, _ = NewFileStore(dir)
+	if _, err := reopened.Get("work", "token"); err == nil {
+		t.Error("expected a wrong passphrase to fail")
+	}
+	t.Setenv(PassphraseEnv, "s3cret")
+	reopened, _ = NewFileStore(dir)
+	if value, err := reopened.Get("work", "token"); err != nil || string(value) != "w-token" {
+		t.Errorf("Get = %q, %v", value, err)
+	}
+
+	// Rotating without a passphrase removes it
+	if _, err := reopened.Rotate(""); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	t.Setenv(PassphraseEnv, "")
+	reopened, _ = NewFileStore(dir)
+	if value, err := reopened.Get("personal", "token"); err != nil || string(value) != "p-token" {
+		t.Errorf("Get = %q, %v", value, err)
+	}
+}
+
+func TestFileStoreRotateUnreadable(t *testing.T) {
+	store, _ := newRotateStore(t)
+	if err := os.WriteFile(store.tokenFilePath("broken"), []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(store.tokenFilePath("work"))
+
+	if _, err := store.Rotate("s3cret"); err == nil {
+		t.Fatal("expected an unreadable file to stop the rotation")
+	}
+	if after, _ := os.ReadFile(store.tokenFilePath("work")); string(after) != string(before) {
+		t.Error("expected the other token files to be left unchanged")
+	}
+}
+
+func TestFileStoreMoveTo(t *testing.T) {
+	t.Run("moves verified tokens", func(t *testing.T) {
+		store, _ := newRotateStore(t)
+		dst := newMemStore()
+
+		moved, err := store.MoveTo(dst)
+		if err != nil {
+			t.Fatalf("MoveTo failed: %v", err)
+		}
+		if len(moved) != 2 || len(dst.items) != 3 {
+			t.Errorf("moved %+v, keyring has %d items", moved, len(dst.items))
+		}
+		if value, _ := dst.Get("work", "scopes"); string(value) != "mail" {
+			t.Errorf("unexpected value %q", value)
+		}
+		if accounts, _ := store.Accounts(); len(accounts) != 0 {
+			t.Errorf("expected the token files to be deleted, got %v", accounts)
+		}
+	})
+
+	t.Run("keeps files when verification fails", func(t *testing.T) {
+		store, _ := newRotateStore(t)
+		dst := newMemStore()
+		dst.corrupt = true
+
+		if _, err := store.MoveTo(dst); err == nil {
+			t.Fatal("expected a verification error")
+		}
+		if accounts, _ := store.Accounts(); len(accounts) != 2 {
+			t.Errorf("expected the token files to be kept, got %v", accounts)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 // Package keyring provides secure credential storage using the system keyring.
-// It supports macOS Keychain as the primary backend with an encrypted file
-// fallback for environments where the system keyring is unavailable.
+// It supports the macOS Keychain, Secret Service and Windows Credential
+// Manager with an encrypted file fallback for environments where the system
+// keyring is unavailable.
 package keyring
 
 import (
@@ -15,10 +16,10 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/99designs/keyring"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
 	"golang.org/x/crypto/pbkdf2"
 )
 
@@ -37,9 +38,17 @@ const (
 	saltSize = 32
 )
 
+// PassphraseEnv is the environment variable holding the passphrase of
+// passphrase-protected token files.
+const PassphraseEnv = "GOOG_TOKEN_PASSPHRASE"
+
 // ErrKeyNotFound is returned when a requested key does not exist in the store.
 var ErrKeyNotFound = errors.New("key not found")
 
+// ErrPassphraseRequired is returned when a token file is protected by a
+// passphrase and GOOG_TOKEN_PASSPHRASE is not set.
+var ErrPassphraseRequired = errors.New("token file is protected by a passphrase: set " + PassphraseEnv)
+
 // Store defines the interface for secure credential storage.
 type Store interface {
 	// Set stores a value for the given account and key.
@@ -65,67 +74,61 @@ type KeyringStore struct {
 // FileStore implements Store using encrypted files as a fallback.
 type FileStore struct {
 	baseDir string
+
+	// passphrase is mixed into the key of files written by this store;
+	// empty for files protected by the machine identity alone.
+	passphrase string
+
+	// protector binds files written by this store to a hardware key; nil
+	// for none.
+	protector Protector
 }
 
-// NewStore creates a new Store using the appropriate backend for the platform.
-// On macOS, it uses Keychain. If the system keyring is unavailable, it falls
-// back to encrypted file storage at ~/.config/goog/tokens/.
+// NewStore creates a new Store using the backend set with SetBackend. With
+// the default, auto, it uses the system keyring (Keychain, Secret Service or
+// wincred) when available and otherwise the encrypted file backend; if no
+// keyring can be opened it falls back to encrypted file storage at
+// ~/.config/goog/tokens/. A specific backend that cannot be opened is an
+// error rather than a silent fallback.
 func NewStore() (Store, error) {
 	configDir, err := getConfigDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config directory: %w", err)
 	}
 
-	// Try to open the system keyring
-	ring, err := openKeyring(configDir)
+	backend := CurrentBackend()
+	opened, err := openBackend(configDir, backend)
 	if err != nil {
+		if backend != BackendAuto {
+			return nil, err
+		}
 		// Fall back to file-based storage
 		return NewFileStore(configDir)
 	}
 
-	return &KeyringStore{ring: ring}, nil
+	return &KeyringStore{ring: opened.ring}, nil
 }
 
 // NewFileStore creates a file-based Store at the specified directory.
-// This is used as a fallback when the system keyring is unavailable.
+// This is used as a fallback when the system keyring is unavailable. The
+// passphrase for the token files is read from GOOG_TOKEN_PASSPHRASE and
+// new files are bound to the protector set with SetProtector.
 func NewFileStore(baseDir string) (*FileStore, error) {
 	tokensDir := filepath.Join(baseDir, "tokens")
 	if err := os.MkdirAll(tokensDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create tokens directory: %w", err)
 	}
-	return &FileStore{baseDir: baseDir}, nil
+	return &FileStore{baseDir: baseDir, passphrase: os.Getenv(PassphraseEnv), protector: CurrentProtector()}, nil
 }
 
-// openKeyring attempts to open the system keyring with appropriate configuration.
+// openKeyring opens the system keyring, falling back to the encrypted file
+// backend.
 func openKeyring(configDir string) (keyring.Keyring, error) {
-	backends := []keyring.BackendType{}
-
-	switch runtime.GOOS {
-	case "darwin":
-		backends = append(backends, keyring.KeychainBackend)
-	case "linux":
-		backends = append(backends, keyring.SecretServiceBackend)
-	case "windows":
-		backends = append(backends, keyring.WinCredBackend)
-	}
-
-	// Always add file backend as final fallback
-	backends = append(backends, keyring.FileBackend)
-
-	// Derive machine-specific password for file backend
-	machinePassword := deriveMachinePassword()
-
-	cfg := keyring.Config{
-		ServiceName:                    ServiceName,
-		AllowedBackends:                backends,
-		FileDir:                        filepath.Join(configDir, "keyring"),
-		FilePasswordFunc:               keyring.FixedStringPrompt(machinePassword),
-		KeychainTrustApplication:       true,
-		KeychainSynchronizable:         false,
-		KeychainAccessibleWhenUnlocked: true,
+	opened, err := openBackend(configDir, BackendAuto)
+	if err != nil {
+		return nil, err
 	}
-
-	return keyring.Open(cfg)
+	return opened.ring, nil
 }
 
 // deriveMachinePassword creates a machine-specific password by combining
@@ -255,12 +258,20 @@ type tokenData struct {
 // encryptedFile represents the structure of the encrypted file on disk,
 // including the salt used for key derivation.
 type encryptedFile struct {
-	Salt       []byte `json:"salt"`       // Random salt for PBKDF2
-	Ciphertext []byte `json:"ciphertext"` // Encrypted token data
+	Salt       []byte `json:"salt"`                 // Random salt for PBKDF2
+	Ciphertext []byte `json:"ciphertext"`           // Encrypted token data
+	Passphrase bool   `json:"passphrase,omitempty"` // Key includes a passphrase
+	Protector  string `json:"protector,omitempty"`  // Hardware key the key is bound to
 }
 
 // Set stores a value in an encrypted file.
 func (s *FileStore) Set(account, key string, value []byte) error {
+	lock, err := s.lock(account)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	data, err := s.loadTokenData(account)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("failed to load token data: %w", err)
@@ -292,6 +303,12 @@ func (s *FileStore) Get(account, key string) ([]byte, error) {
 
 // Delete removes a value from an encrypted file.
 func (s *FileStore) Delete(account, key string) error {
+	lock, err := s.lock(account)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	data, err := s.loadTokenData(account)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -327,6 +344,19 @@ func (s *FileStore) List(account string) ([]string, error) {
 	return keys, nil
 }
 
+// lock takes the lock on the token file for the given account, so that
+// concurrent goog processes do not lose each other's updates.
+func (s *FileStore) lock(account string) (*filelock.Lock, error) {
+	lock, err := filelock.Acquire(s.tokenFilePath(account), filelock.DefaultTimeout)
+	if err != nil {
+		if errors.Is(err, filelock.ErrLocked) {
+			return nil, fmt.Errorf("token file for %s is locked by another goog process: %w", account, err)
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
 // tokenFilePath returns the path to the token file for the given account.
 func (s *FileStore) tokenFilePath(account string) string {
 	return filepath.Join(s.baseDir, "tokens", account+".enc")
@@ -339,7 +369,12 @@ func (s *FileStore) loadTokenData(account string) (*tokenData, error) {
 	if err != nil {
 		return nil, err
 	}
+	return s.decodeTokenData(account, fileData, s.passphrase)
+}
 
+// decodeTokenData decrypts the contents of a token file. The passphrase is
+// only used for files written with one.
+func (s *FileStore) decodeTokenData(account string, fileData []byte, passphrase string) (*tokenData, error) {
 	// Parse the encrypted file structure
 	var encFile encryptedFile
 	if err := json.Unmarshal(fileData, &encFile); err != nil {
@@ -353,7 +388,20 @@ func (s *FileStore) loadTokenData(account string) (*tokenData, error) {
 	}
 
 	// Derive key using PBKDF2 with the stored salt
-	key := s.deriveKey(account, encFile.Salt)
+	if encFile.Passphrase && passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	if !encFile.Passphrase {
+		passphrase = ""
+	}
+	protector, err := ParseProtector(encFile.Protector)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.fileKey(account, encFile.Salt, passphrase, protector)
+	if err != nil {
+		return nil, err
+	}
 
 	plaintext, err := decrypt(encFile.Ciphertext, key)
 	if err != nil {
@@ -389,38 +437,55 @@ func (s *FileStore) loadLegacyTokenData(encryptedData []byte, account string) (*
 
 // saveTokenData encrypts and saves token data to a file.
 func (s *FileStore) saveTokenData(account string, data *tokenData) error {
+	fileData, err := s.encodeTokenData(account, data, s.passphrase)
+	if err != nil {
+		return err
+	}
+
+	filePath := s.tokenFilePath(account)
+	return filelock.WriteFile(filePath, fileData, 0600)
+}
+
+// encodeTokenData encrypts token data with a new random salt and, when
+// passphrase is not empty, the passphrase.
+func (s *FileStore) encodeTokenData(account string, data *tokenData, passphrase string) ([]byte, error) {
 	plaintext, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal token data: %w", err)
+		return nil, fmt.Errorf("failed to marshal token data: %w", err)
 	}
 
 	// Generate a new random salt for each save
 	salt := make([]byte, saltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
-		return fmt.Errorf("failed to generate salt: %w", err)
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
 	}
 
 	// Derive key using PBKDF2 with the new salt
-	key := s.deriveKey(account, salt)
+	key, err := s.fileKey(account, salt, passphrase, s.protector)
+	if err != nil {
+		return nil, err
+	}
 
 	ciphertext, err := encrypt(plaintext, key)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt token data: %w", err)
+		return nil, fmt.Errorf("failed to encrypt token data: %w", err)
 	}
 
 	// Create the encrypted file structure
 	encFile := encryptedFile{
 		Salt:       salt,
 		Ciphertext: ciphertext,
+		Passphrase: passphrase != "",
+	}
+	if s.protector != nil {
+		encFile.Protector = s.protector.Spec()
 	}
 
 	fileData, err := json.Marshal(encFile)
 	if err != nil {
-		return fmt.Errorf("failed to marshal encrypted file: %w", err)
+		return nil, fmt.Errorf("failed to marshal encrypted file: %w", err)
 	}
-
-	filePath := s.tokenFilePath(account)
-	return os.WriteFile(filePath, fileData, 0600)
+	return fileData, nil
 }
 
 // deriveKey derives an encryption key using PBKDF2 with machine-specific info.
@@ -436,6 +501,31 @@ func (s *FileStore) deriveKey(account string, salt []byte) []byte {
 	return pbkdf2.Key([]byte(input), salt, pbkdf2Iterations, 32, sha256.New)
 }
 
+// derivePassphraseKey derives an encryption key like deriveKey with a
+// passphrase added, so a copied file needs both the machine identity and
+// the passphrase.
+func (s *FileStore) derivePassphraseKey(account string, salt []byte, passphrase string) []byte {
+	input := fmt.Sprintf("go-goog-cli-file-store:%s:%s:%s", account, getMachineInfo(), passphrase)
+	return pbkdf2.Key([]byte(input), salt, pbkdf2Iterations, 32, sha256.New)
+}
+
+// fileKey derives the key of a token file from the machine identity, the
+// passphrase if any, and the secret of the protector if any.
+func (s *FileStore) fileKey(account string, salt []byte, passphrase string, protector Protector) ([]byte, error) {
+	if protector == nil {
+		if passphrase == "" {
+			return s.deriveKey(account, salt), nil
+		}
+		return s.derivePassphraseKey(account, salt, passphrase), nil
+	}
+	secret, err := protector.Secret(protectorChallenge(account, salt))
+	if err != nil {
+		return nil, err
+	}
+	input := fmt.Sprintf("go-goog-cli-file-store:%s:%s:%s:%x", account, getMachineInfo(), passphrase, secret)
+	return pbkdf2.Key([]byte(input), salt, pbkdf2Iterations, 32, sha256.New), nil
+}
+
 // deriveLegacyKey provides backward compatibility with the old key derivation.
 // This uses simple SHA256 hashing without salt or iterations.
 func (s *FileStore) deriveLegacyKey(account string) []byte {
@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -2447,3 +2449,45 @@ func TestKeyringStoreGetNonexistent(t *testing.T) {
 		t.Error("expected error for non-existent key")
 	}
 }
+
+// TestFileStoreConcurrentSet tests that concurrent writers, such as two goog
+// processes sharing a token file, do not lose each other's keys.
+func TestFileStoreConcurrentSet(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	const writers = 6
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate stores mimic separate processes
+			store, err := NewFileStore(tmpDir)
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- store.Set("shared", fmt.Sprintf("key-%d", i), []byte("value"))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Set failed: %v", err)
+		}
+	}
+
+	store, err := NewFileStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	keys, err := store.List("shared")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(keys) != writers {
+		t.Errorf("expected %d keys after concurrent writes, got %d: %v", writers, len(keys), keys)
+	}
+}
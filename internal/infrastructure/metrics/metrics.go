@@ -0,0 +1,449 @@
+// Package metrics records opt-in local usage metrics: command latency, API
+// call counts by service, retries and cache hit rates. A Recorder collects
+// metrics for one run in memory; Flush merges them into a JSON file so
+// totals accumulate across runs, and WritePrometheus renders them in the
+// Prometheus text exposition format for a node_exporter textfile collector.
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the metrics file kept next to the config file.
+const FileName = "metrics.json"
+
+// Path returns the path of the metrics file.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// Snapshot is a set of metrics, as stored in the metrics file.
+type Snapshot struct {
+	// Since is when collection started.
+	Since time.Time `json:"since"`
+	// Commands holds latency per command path, e.g. "mail list".
+	Commands map[string]*CommandStats `json:"commands,omitempty"`
+	// APICalls holds request counts per service, e.g. "gmail".
+	APICalls map[string]*APIStats `json:"api_calls,omitempty"`
+	// Retries counts API calls retried after a rate limit or temporary error.
+	Retries int64 `json:"retries"`
+	// Caches holds lookups per cache, e.g. "imap.raw".
+	Caches map[string]*CacheStats `json:"caches,omitempty"`
+}
+
+// CommandStats records runs of one command.
+type CommandStats struct {
+	Count       int64 `json:"count"`
+	Errors      int64 `json:"errors"`
+	TotalMillis int64 `json:"total_ms"`
+	MaxMillis   int64 `json:"max_ms"`
+	// Retries counts the API retries made by runs of the command.
+	Retries int64 `json:"retries,omitempty"`
+}
+
+// AverageMillis returns the mean run time in milliseconds.
+func (s *CommandStats) AverageMillis() int64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalMillis / s.Count
+}
+
+// APIStats records requests to one API.
+type APIStats struct {
+	Calls       int64 `json:"calls"`
+	Errors      int64 `json:"errors"`
+	RateLimited int64 `json:"rate_limited"`
+	TotalMillis int64 `json:"total_ms"`
+}
+
+// CacheStats records lookups in one cache.
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// HitRate returns the fraction of lookups that hit, or 0 without lookups.
+func (s *CacheStats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// newSnapshot returns an empty snapshot starting at since.
+func newSnapshot(since time.Time) *Snapshot {
+	return &Snapshot{
+		Since:    since,
+		Commands: make(map[string]*CommandStats),
+		APICalls: make(map[string]*APIStats),
+		Caches:   make(map[string]*CacheStats),
+	}
+}
+
+// merge adds the metrics of other to s.
+func (s *Snapshot) merge(other *Snapshot) {
+	if s.Since.IsZero() || (!other.Since.IsZero() && other.Since.Before(s.Since)) {
+		s.Since = other.Since
+	}
+	for name, o := range other.Commands {
+		c := s.command(name)
+		c.Count += o.Count
+		c.Errors += o.Errors
+		c.TotalMillis += o.TotalMillis
+		c.MaxMillis = max(c.MaxMillis, o.MaxMillis)
+		c.Retries += o.Retries
+	}
+	for name, o := range other.APICalls {
+		a := s.api(name)
+		a.Calls += o.Calls
+		a.Errors += o.Errors
+		a.RateLimited += o.RateLimited
+		a.TotalMillis += o.TotalMillis
+	}
+	s.Retries += other.Retries
+	for name, o := range other.Caches {
+		c := s.cache(name)
+		c.Hits += o.Hits
+		c.Misses += o.Misses
+	}
+}
+
+func (s *Snapshot) command(name string) *CommandStats {
+	if s.Commands == nil {
+		s.Commands = make(map[string]*CommandStats)
+	}
+	c := s.Commands[name]
+	if c == nil {
+		c = &CommandStats{}
+		s.Commands[name] = c
+	}
+	return c
+}
+
+func (s *Snapshot) api(name string) *APIStats {
+	if s.APICalls == nil {
+		s.APICalls = make(map[string]*APIStats)
+	}
+	a := s.APICalls[name]
+	if a == nil {
+		a = &APIStats{}
+		s.APICalls[name] = a
+	}
+	return a
+}
+
+func (s *Snapshot) cache(name string) *CacheStats {
+	if s.Caches == nil {
+		s.Caches = make(map[string]*CacheStats)
+	}
+	c := s.Caches[name]
+	if c == nil {
+		c = &CacheStats{}
+		s.Caches[name] = c
+	}
+	return c
+}
+
+// Recorder collects metrics in memory. It is safe for concurrent use.
+type Recorder struct {
+	mu   sync.Mutex
+	snap *Snapshot
+	// retries counts the retries since the last RecordCommand, which
+	// charges them to its command.
+	retries int64
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{snap: newSnapshot(time.Now())}
+}
+
+// RecordCommand records one run of a command.
+func (r *Recorder) RecordCommand(name string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.snap.command(name)
+	c.Count++
+	if err != nil {
+		c.Errors++
+	}
+	c.TotalMillis += d.Milliseconds()
+	c.MaxMillis = max(c.MaxMillis, d.Milliseconds())
+	c.Retries += r.retries
+	r.retries = 0
+}
+
+// RecordAPICall records one request to service. status is the HTTP status,
+// or 0 when no response was received.
+func (r *Recorder) RecordAPICall(service string, status int, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := r.snap.api(service)
+	a.Calls++
+	if status == 0 || status >= 400 {
+		a.Errors++
+	}
+	if status == http.StatusTooManyRequests {
+		a.RateLimited++
+	}
+	a.TotalMillis += d.Milliseconds()
+}
+
+// RecordRetry records one retried API call, charged to the command
+// recorded next.
+func (r *Recorder) RecordRetry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snap.Retries++
+	r.retries++
+}
+
+// RecordCache records one lookup in the named cache.
+func (r *Recorder) RecordCache(name string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.snap.cache(name)
+	if hit {
+		c.Hits++
+	} else {
+		c.Misses++
+	}
+}
+
+// Snapshot returns a copy of the metrics recorded so far.
+func (r *Recorder) Snapshot() *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snap := newSnapshot(r.snap.Since)
+	snap.merge(r.snap)
+	return snap
+}
+
+// Flush merges the recorded metrics into the file at path and starts a new
+// collection, so a Recorder can be flushed more than once.
+func (r *Recorder) Flush(path string) (*Snapshot, error) {
+	r.mu.Lock()
+	pending := r.snap
+	r.snap = newSnapshot(time.Now())
+	r.mu.Unlock()
+
+	var total *Snapshot
+	err := update(path, func(snap *Snapshot) *Snapshot {
+		snap.merge(pending)
+		total = snap
+		return snap
+	})
+	if err != nil {
+		// Keep the metrics for a later flush
+		r.mu.Lock()
+		r.snap.merge(pending)
+		r.mu.Unlock()
+		return nil, err
+	}
+	return total, nil
+}
+
+// Load reads the metrics file at path. A missing file is an empty snapshot.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newSnapshot(time.Time{}), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	snap := newSnapshot(time.Time{})
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// Reset clears the metrics file at path.
+func Reset(path string) error {
+	return update(path, func(*Snapshot) *Snapshot {
+		return newSnapshot(time.Now())
+	})
+}
+
+// update rewrites the metrics file at path under its lock.
+func update(path string, fn func(*Snapshot) *Snapshot) error {
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	snap, err := Load(path)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(fn(snap), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// Transport returns an http.RoundTripper that records each request to
+// Google in r before passing it to base; a nil base uses
+// http.DefaultTransport.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	return &transport{recorder: r, base: base}
+}
+
+type transport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+// RoundTrip sends req and records its service, status and latency.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.recorder.RecordAPICall(ServiceOf(req), status, time.Since(start))
+	return resp, err
+}
+
+// servicePaths maps API path segments to service names.
+var servicePaths = []struct{ segment, service string }{
+	{"/gmail/v1/", "gmail"},
+	{"/calendar/v3/", "calendar"},
+	{"/tasks/v1/", "tasks"},
+	{"/v1/people", "people"},
+	{"/v1/contactGroups", "people"},
+	{"/v1/otherContacts", "people"},
+}
+
+// ServiceOf returns the Google API a request is for, from its path or
+// host, or "other".
+func ServiceOf(req *http.Request) string {
+	for _, sp := range servicePaths {
+		if strings.Contains(req.URL.Path, sp.segment) {
+			return sp.service
+		}
+	}
+	if service, _, ok := strings.Cut(req.URL.Hostname(), "."); ok && strings.HasSuffix(req.URL.Hostname(), ".googleapis.com") {
+		return service
+	}
+	return "other"
+}
+
+// WritePrometheus writes snap in the Prometheus text exposition format.
+// Durations are in seconds and every metric is a counter, except the
+// per-command maximum which is a gauge.
+func WritePrometheus(w io.Writer, snap *Snapshot) error {
+	var b bytes.Buffer
+
+	writeHeader(&b, "goog_command_runs_total", "counter", "Commands run.")
+	for _, name := range sortedKeys(snap.Commands) {
+		fmt.Fprintf(&b, "goog_command_runs_total{command=%q} %d\n", name, snap.Commands[name].Count)
+	}
+	writeHeader(&b, "goog_command_errors_total", "counter", "Commands that failed.")
+	for _, name := range sortedKeys(snap.Commands) {
+		fmt.Fprintf(&b, "goog_command_errors_total{command=%q} %d\n", name, snap.Commands[name].Errors)
+	}
+	writeHeader(&b, "goog_command_duration_seconds_total", "counter", "Total run time of commands.")
+	for _, name := range sortedKeys(snap.Commands) {
+		fmt.Fprintf(&b, "goog_command_duration_seconds_total{command=%q} %s\n", name, seconds(snap.Commands[name].TotalMillis))
+	}
+	writeHeader(&b, "goog_command_duration_seconds_max", "gauge", "Longest run time of commands.")
+	for _, name := range sortedKeys(snap.Commands) {
+		fmt.Fprintf(&b, "goog_command_duration_seconds_max{command=%q} %s\n", name, seconds(snap.Commands[name].MaxMillis))
+	}
+	writeHeader(&b, "goog_command_retries_total", "counter", "API calls retried by commands.")
+	for _, name := range sortedKeys(snap.Commands) {
+		fmt.Fprintf(&b, "goog_command_retries_total{command=%q} %d\n", name, snap.Commands[name].Retries)
+	}
+
+	writeHeader(&b, "goog_api_calls_total", "counter", "Requests sent to Google APIs.")
+	for _, name := range sortedKeys(snap.APICalls) {
+		fmt.Fprintf(&b, "goog_api_calls_total{service=%q} %d\n", name, snap.APICalls[name].Calls)
+	}
+	writeHeader(&b, "goog_api_errors_total", "counter", "Requests that failed or returned an error status.")
+	for _, name := range sortedKeys(snap.APICalls) {
+		fmt.Fprintf(&b, "goog_api_errors_total{service=%q} %d\n", name, snap.APICalls[name].Errors)
+	}
+	writeHeader(&b, "goog_api_rate_limited_total", "counter", "Requests rejected with 429 Too Many Requests.")
+	for _, name := range sortedKeys(snap.APICalls) {
+		fmt.Fprintf(&b, "goog_api_rate_limited_total{service=%q} %d\n", name, snap.APICalls[name].RateLimited)
+	}
+	writeHeader(&b, "goog_api_duration_seconds_total", "counter", "Total time spent waiting for Google APIs.")
+	for _, name := range sortedKeys(snap.APICalls) {
+		fmt.Fprintf(&b, "goog_api_duration_seconds_total{service=%q} %s\n", name, seconds(snap.APICalls[name].TotalMillis))
+	}
+
+	writeHeader(&b, "goog_api_retries_total", "counter", "API calls retried after a rate limit or temporary error.")
+	fmt.Fprintf(&b, "goog_api_retries_total %d\n", snap.Retries)
+
+	writeHeader(&b, "goog_cache_hits_total", "counter", "Cache lookups that hit.")
+	for _, name := range sortedKeys(snap.Caches) {
+		fmt.Fprintf(&b, "goog_cache_hits_total{cache=%q} %d\n", name, snap.Caches[name].Hits)
+	}
+	writeHeader(&b, "goog_cache_misses_total", "counter", "Cache lookups that missed.")
+	for _, name := range sortedKeys(snap.Caches) {
+		fmt.Fprintf(&b, "goog_cache_misses_total{cache=%q} %d\n", name, snap.Caches[name].Misses)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteTextfile writes snap in the Prometheus text format to path,
+// replacing the file atomically so a textfile collector never reads a
+// partial file. The file name should end in .prom.
+func WriteTextfile(path string, snap *Snapshot) error {
+	var b bytes.Buffer
+	if err := WritePrometheus(&b, snap); err != nil {
+		return err
+	}
+	if err := filelock.WriteFile(path, b.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics textfile: %w", err)
+	}
+	return nil
+}
+
+func writeHeader(b *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// seconds formats a duration in milliseconds as seconds.
+func seconds(millis int64) string {
+	return fmt.Sprintf("%.3f", float64(millis)/1000)
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	r.RecordCommand("mail list", 100*time.Millisecond, nil)
+	r.RecordCommand("mail list", 300*time.Millisecond, errors.New("failed"))
+	r.RecordAPICall("gmail", http.StatusOK, 50*time.Millisecond)
+	r.RecordAPICall("gmail", http.StatusTooManyRequests, 10*time.Millisecond)
+	r.RecordAPICall("tasks", 0, time.Millisecond)
+	r.RecordRetry()
+	r.RecordCache("imap.raw", true)
+	r.RecordCache("imap.raw", true)
+	r.RecordCache("imap.raw", false)
+
+	snap := r.Snapshot()
+	cmd := snap.Commands["mail list"]
+	if cmd.Count != 2 || cmd.Errors != 1 || cmd.MaxMillis != 300 || cmd.AverageMillis() != 200 {
+		t.Errorf("command stats = %+v, want 2 runs, 1 error, max 300, average 200", cmd)
+	}
+	gmail := snap.APICalls["gmail"]
+	if gmail.Calls != 2 || gmail.Errors != 1 || gmail.RateLimited != 1 || gmail.TotalMillis != 60 {
+		t.Errorf("gmail stats = %+v", gmail)
+	}
+	if tasks := snap.APICalls["tasks"]; tasks.Errors != 1 {
+		t.Errorf("a call without a response should count as an error, got %+v", tasks)
+	}
+	if snap.Retries != 1 {
+		t.Errorf("retries = %d, want 1", snap.Retries)
+	}
+	if rate := snap.Caches["imap.raw"].HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("hit rate = %v, want 2/3", rate)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	first := NewRecorder()
+	first.RecordCommand("cal today", time.Second, nil)
+	if _, err := first.Flush(path); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	second := NewRecorder()
+	second.RecordRetry()
+	second.RecordCommand("cal today", 3*time.Second, nil)
+	total, err := second.Flush(path)
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if c := total.Commands["cal today"]; c.Count != 2 || c.MaxMillis != 3000 || c.Retries != 1 {
+		t.Errorf("totals should accumulate across runs, got %+v", c)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Commands["cal today"].Count != 2 || loaded.Retries != 1 {
+		t.Errorf("loaded = %+v, want the flushed totals", loaded)
+	}
+
+	// A flushed recorder starts over
+	if snap := second.Snapshot(); len(snap.Commands) != 0 || snap.Retries != 0 {
+		t.Errorf("recorder should be empty after Flush, got %+v", snap)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	snap, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(snap.Commands) != 0 || snap.Retries != 0 {
+		t.Errorf("missing file should be an empty snapshot, got %+v", snap)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for an invalid metrics file")
+	}
+}
+
+func TestReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	r := NewRecorder()
+	r.RecordCommand("tasks list", time.Second, nil)
+	if _, err := r.Flush(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Reset(path); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	snap, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Commands) != 0 {
+		t.Errorf("Reset should clear the metrics, got %+v", snap.Commands)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	r := NewRecorder()
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "/calendar/") {
+			return nil, errors.New("unreachable")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	})
+	client := &http.Client{Transport: r.Transport(base)}
+
+	if _, err := client.Get("https://gmail.googleapis.com/gmail/v1/users/me/messages"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get("https://www.googleapis.com/calendar/v3/users/me/calendarList"); err == nil {
+		t.Fatal("expected the base transport error")
+	}
+
+	snap := r.Snapshot()
+	if a := snap.APICalls["gmail"]; a == nil || a.Calls != 1 || a.Errors != 0 {
+		t.Errorf("gmail stats = %+v, want one successful call", a)
+	}
+	if a := snap.APICalls["calendar"]; a == nil || a.Errors != 1 {
+		t.Errorf("calendar stats = %+v, want one failed call", a)
+	}
+}
+
+func TestServiceOf(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://gmail.googleapis.com/gmail/v1/users/me/messages", "gmail"},
+		{"https://www.googleapis.com/calendar/v3/calendars/primary/events", "calendar"},
+		{"https://tasks.googleapis.com/tasks/v1/users/@me/lists", "tasks"},
+		{"https://people.googleapis.com/v1/people/me/connections", "people"},
+		{"https://people.googleapis.com/v1/contactGroups", "people"},
+		{"https://gateway.example.com/gmail/v1/users/me/labels", "gmail"},
+		{"https://drive.googleapis.com/drive/v3/files", "drive"},
+		{"https://example.com/other", "other"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ServiceOf(req); got != tt.want {
+			t.Errorf("ServiceOf(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	r := NewRecorder()
+	r.RecordRetry()
+	r.RecordCommand("mail list", 1500*time.Millisecond, nil)
+	r.RecordAPICall("gmail", http.StatusTooManyRequests, 250*time.Millisecond)
+	r.RecordCache("imap.raw", true)
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, r.Snapshot()); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"# TYPE goog_command_runs_total counter",
+		`goog_command_runs_total{command="mail list"} 1`,
+		`goog_command_duration_seconds_total{command="mail list"} 1.500`,
+		"# TYPE goog_command_duration_seconds_max gauge",
+		`goog_api_calls_total{service="gmail"} 1`,
+		`goog_api_rate_limited_total{service="gmail"} 1`,
+		`goog_api_duration_seconds_total{service="gmail"} 0.250`,
+		"goog_api_retries_total 1",
+		`goog_command_retries_total{command="mail list"} 1`,
+		`goog_cache_hits_total{cache="imap.raw"} 1`,
+		`goog_cache_misses_total{cache="imap.raw"} 0`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestWriteTextfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goog.prom")
+	r := NewRecorder()
+	r.RecordRetry()
+
+	if err := WriteTextfile(path, r.Snapshot()); err != nil {
+		t.Fatalf("WriteTextfile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "goog_api_retries_total 1") {
+		t.Errorf("textfile = %q, want the retry counter", data)
+	}
+}
@@ -0,0 +1,242 @@
+// Package outbox keeps messages that could not be sent because the network
+// was unavailable, encrypted on disk, until they are sent or discarded.
+package outbox
+
+import (
+	"cmp"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+)
+
+const (
+	// DirName is the name of the outbox directory kept next to the config
+	// file.
+	DirName = "outbox"
+
+	// keyAccount and keyName locate the outbox encryption key in the
+	// credential store.
+	keyAccount = "outbox"
+	keyName    = "encryption-key"
+
+	// keySize is the AES-256 key size in bytes.
+	keySize = 32
+
+	// fileExt is the extension of queued message files.
+	fileExt = ".enc"
+)
+
+// ErrNotFound is returned for an ID that is not in the outbox.
+var ErrNotFound = errors.New("message not found in outbox")
+
+// Kind says how a queued message is sent.
+type Kind string
+
+// Kinds of queued messages.
+const (
+	KindSend    Kind = "send"
+	KindForward Kind = "forward"
+)
+
+// Entry is a queued message.
+type Entry struct {
+	ID string `json:"id"`
+	// Kind says whether Message is sent as new mail or as a forward of
+	// MessageID.
+	Kind      Kind   `json:"kind"`
+	MessageID string `json:"message_id,omitempty"`
+	// Account is the address of the account the message is sent from.
+	Account   string        `json:"account"`
+	Message   *mail.Message `json:"message"`
+	QueuedAt  time.Time     `json:"queued_at"`
+	Attempts  int           `json:"attempts"`
+	LastError string        `json:"last_error,omitempty"`
+}
+
+// Outbox is a directory of queued messages, each encrypted with AES-GCM
+// under a key held in the credential store.
+type Outbox struct {
+	dir string
+	gcm cipher.AEAD
+}
+
+// Dir returns the path of the outbox directory.
+func Dir() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), DirName)
+}
+
+// Open opens the outbox in dir, creating the directory and the encryption
+// key in store on first use.
+func Open(dir string, store keyring.Store) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	key, err := store.Get(keyAccount, keyName)
+	if errors.Is(err, keyring.ErrKeyNotFound) {
+		key = make([]byte, keySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("failed to generate outbox key: %w", err)
+		}
+		if err := store.Set(keyAccount, keyName, key); err != nil {
+			return nil, fmt.Errorf("failed to store outbox key: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read outbox key: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("outbox key has %d bytes, want %d", len(key), keySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Outbox{dir: dir, gcm: gcm}, nil
+}
+
+// Lock takes the outbox lock, which guards sending and removing queued
+// messages so that two processes do not send the same message.
+func (o *Outbox) Lock() (*filelock.Lock, error) {
+	lock, err := filelock.Acquire(o.dir, filelock.DefaultTimeout)
+	if errors.Is(err, filelock.ErrLocked) {
+		return nil, fmt.Errorf("outbox is in use by another goog process: %w", err)
+	}
+	return lock, err
+}
+
+// Add queues e, assigning its ID and, when unset, its queue time.
+func (o *Outbox) Add(e *Entry) error {
+	if e.QueuedAt.IsZero() {
+		e.QueuedAt = time.Now()
+	}
+	e.QueuedAt = e.QueuedAt.UTC().Truncate(time.Second)
+	suffix := make([]byte, 3)
+	if _, err := io.ReadFull(rand.Reader, suffix); err != nil {
+		return fmt.Errorf("failed to generate outbox ID: %w", err)
+	}
+	e.ID = e.QueuedAt.Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+	return o.Save(e)
+}
+
+// Save writes e, replacing the stored copy.
+func (o *Outbox) Save(e *Entry) error {
+	plaintext, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode queued message: %w", err)
+	}
+	nonce := make([]byte, o.gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to encrypt queued message: %w", err)
+	}
+	data := o.gcm.Seal(nonce, nonce, plaintext, []byte(e.ID))
+	if err := filelock.WriteFile(o.path(e.ID), data, 0600); err != nil {
+		return fmt.Errorf("failed to write queued message: %w", err)
+	}
+	return nil
+}
+
+// Get returns the queued message with the given ID.
+func (o *Outbox) Get(id string) (*Entry, error) {
+	if !validID(id) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	data, err := os.ReadFile(o.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queued message: %w", err)
+	}
+
+	size := o.gcm.NonceSize()
+	if len(data) < size {
+		return nil, fmt.Errorf("queued message %s is corrupt", id)
+	}
+	plaintext, err := o.gcm.Open(nil, data[:size], data[size:], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt queued message %s: %w", id, err)
+	}
+	var e Entry
+	if err := json.Unmarshal(plaintext, &e); err != nil {
+		return nil, fmt.Errorf("failed to parse queued message %s: %w", id, err)
+	}
+	return &e, nil
+}
+
+// List returns the queued messages, oldest first.
+func (o *Outbox) List() ([]*Entry, error) {
+	files, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	var entries []*Entry
+	for _, f := range files {
+		id, ok := strings.CutSuffix(f.Name(), fileExt)
+		if !ok || f.IsDir() || !validID(id) {
+			continue
+		}
+		e, err := o.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		return cmp.Or(a.QueuedAt.Compare(b.QueuedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return entries, nil
+}
+
+// Remove deletes the queued message with the given ID.
+func (o *Outbox) Remove(id string) error {
+	if !validID(id) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	err := os.Remove(o.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove queued message: %w", err)
+	}
+	return nil
+}
+
+// path returns the file holding the message with the given ID.
+func (o *Outbox) path(id string) string {
+	return filepath.Join(o.dir, id+fileExt)
+}
+
+// validID reports whether id can name a file in the outbox, so that IDs
+// given on the command line cannot reach outside it.
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+)
+
+// memStore is an in-memory keyring.Store.
+type memStore map[string][]byte
+
+func (m memStore) Set(account, key string, value []byte) error {
+	m[account+"/"+key] = value
+	return nil
+}
+
+func (m memStore) Get(account, key string) ([]byte, error) {
+	if v, ok := m[account+"/"+key]; ok {
+		return v, nil
+	}
+	return nil, keyring.ErrKeyNotFound
+}
+
+func (m memStore) Delete(account, key string) error {
+	delete(m, account+"/"+key)
+	return nil
+}
+
+func (m memStore) List(account string) ([]string, error) { return nil, nil }
+
+func TestOutbox_AddListRemove(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+	store := memStore{}
+	box, err := Open(dir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := &Entry{
+		Kind:     KindSend,
+		Account:  "me@example.com",
+		Message:  &mail.Message{To: []mail.Address{{Email: "bob@example.com"}}, Subject: "Secret plans", Body: "top secret body"},
+		QueuedAt: time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+	second := &Entry{Kind: KindForward, MessageID: "m1", Account: "me@example.com", Message: &mail.Message{To: []mail.Address{{Email: "c@example.com"}}}}
+	if err := box.Add(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := box.Add(first); err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == "" || first.ID == second.ID {
+		t.Fatalf("unexpected IDs %q and %q", first.ID, second.ID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, first.ID+fileExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("top secret body")) || bytes.Contains(data, []byte("Secret plans")) {
+		t.Error("queued message is stored in plain text")
+	}
+
+	// Reopening with the same store reads the same messages.
+	box, err = Open(dir, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := box.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != first.ID || entries[0].Message.Body != "top secret body" {
+		t.Fatalf("List() = %+v", entries)
+	}
+
+	entries[1].Attempts = 1
+	entries[1].LastError = "dial tcp: no route to host"
+	if err := box.Save(entries[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := box.Get(second.ID); err != nil || got.Attempts != 1 || got.MessageID != "m1" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+
+	if err := box.Remove(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := box.Remove(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Remove() = %v, want ErrNotFound", err)
+	}
+	if _, err := box.Get("../config"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a path = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOutbox_WrongKey(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+	box, err := Open(dir, memStore{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := box.Add(&Entry{Kind: KindSend, Message: &mail.Message{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := Open(dir, memStore{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.List(); err == nil {
+		t.Error("expected a decryption error with a different key")
+	}
+}
@@ -0,0 +1,242 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// The "HELLO WORLD" 1-Q example of ISO/IEC 18004.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236}
+	want := []byte{168, 72, 22, 82, 217, 54, 156, 0, 46, 15, 180, 122, 16}
+	if got := rsRemainder(data, rsDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	// Capacities of level M from ISO/IEC 18004 table 7.
+	for version, want := range map[int]int{1: 16, 2: 28, 5: 86, 7: 124, 10: 216, 40: 2334} {
+		if got := dataCodewords(version); got != want {
+			t.Errorf("dataCodewords(%d) = %d, want %d", version, got, want)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	tests := map[int][]int{1: nil, 2: {6, 18}, 7: {6, 22, 38}, 32: {6, 34, 60, 86, 112, 138}}
+	for version, want := range tests {
+		got := alignmentPositions(version)
+		if len(got) != len(want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", version, got, want)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("alignmentPositions(%d) = %v, want %v", version, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		text    string
+		version int
+	}{
+		{"hello", 1},
+		{"https://mail.google.com/mail/u/me@example.com/#all/18c1234abcd5678e", 5},
+		{"https://www.google.com/calendar/event?authuser=me%40example.com&eid=" + strings.Repeat("QUJD", 30), 10},
+		{strings.Repeat("x", 1000), 0},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(tt.text), err)
+		}
+		if tt.version != 0 && code.Version != tt.version {
+			t.Errorf("Encode(%d bytes) version = %d, want %d", len(tt.text), code.Version, tt.version)
+		}
+		if code.Size != 17+4*code.Version {
+			t.Errorf("size = %d for version %d", code.Size, code.Version)
+		}
+		got, err := decode(code)
+		if err != nil {
+			t.Fatalf("decode version %d: %v", code.Version, err)
+		}
+		if got != tt.text {
+			t.Errorf("decoded %q, want %q", got, tt.text)
+		}
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 2332)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("error = %v, want ErrTooLong", err)
+	}
+	if _, err := Encode(strings.Repeat("x", 2331)); err != nil {
+		t.Errorf("largest text: %v", err)
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	code, err := Encode("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(code.Terminal(), "\n"), "\n")
+	width := code.Size + 2*QuietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("got %d lines, want %d", len(lines), (width+1)/2)
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Errorf("line %d is %d wide, want %d", i, n, width)
+		}
+	}
+	// The quiet zone is light and the top left finder corner is dark.
+	if !strings.HasPrefix(lines[0], strings.Repeat("█", width)) {
+		t.Errorf("first line = %q", lines[0])
+	}
+	if r := []rune(lines[1])[QuietZone]; r != ' ' {
+		t.Errorf("finder corner = %q, want ' '", r)
+	}
+}
+
+func TestDrawVersion(t *testing.T) {
+	// Version 7's information is 0x07C94 in ISO/IEC 18004 annex D.
+	c := newCode(7)
+	c.drawVersion()
+	got := 0
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		if c.modules[b][a] != c.modules[a][b] {
+			t.Fatalf("version copies differ at bit %d", i)
+		}
+		if c.modules[b][a] {
+			got |= 1 << i
+		}
+	}
+	if got != 0x07C94 {
+		t.Errorf("version bits = %#05x, want 0x07c94", got)
+	}
+}
+
+// decode reads a code back: it checks the format information, removes the
+// mask, verifies every block's error correction and parses the byte mode
+// segment.
+func decode(c *Code) (string, error) {
+	bits := 0
+	read := func(i, x, y int) {
+		if c.modules[y][x] {
+			bits |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		read(i, 8, i)
+	}
+	read(6, 8, 7)
+	read(7, 8, 8)
+	read(8, 7, 8)
+	for i := 9; i < 15; i++ {
+		read(i, 14-i, 8)
+	}
+	bits ^= 0x5412
+	data := bits >> 10
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	if rem != bits&0x3FF {
+		return "", errors.New("bad format information")
+	}
+	if data>>3 != formatLevelM {
+		return "", errors.New("level is not M")
+	}
+
+	mask := data & 7
+	fresh := newCode(c.Version)
+	fresh.drawFunctionPatterns()
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if fresh.function[y][x] != c.function[y][x] {
+				return "", errors.New("function modules differ")
+			}
+		}
+	}
+
+	raw := rawModules(c.Version) / 8
+	codewords := make([]byte, raw)
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.function[y][x] || i >= raw*8 {
+					continue
+				}
+				if c.modules[y][x] != maskBit(mask, x, y) {
+					codewords[i/8] |= 1 << (7 - i%8)
+				}
+				i++
+			}
+		}
+	}
+
+	numBlocks, eccLen := eccBlocks[c.Version], eccPerBlock[c.Version]
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for b := range blocks {
+			if i < shortLen || b >= numShort {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var payload []byte
+	for b, block := range blocks {
+		ecc := codewords[k+b : len(codewords) : len(codewords)]
+		var got []byte
+		for j := 0; j < eccLen; j++ {
+			got = append(got, ecc[j*numBlocks])
+		}
+		if !bytes.Equal(rsRemainder(block, rsDivisor(eccLen)), got) {
+			return "", errors.New("error correction mismatch")
+		}
+		payload = append(payload, block...)
+	}
+
+	pos := 0
+	take := func(n int) int {
+		v := 0
+		for j := 0; j < n; j++ {
+			v = v<<1 | int(payload[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	if take(4) != 0x4 {
+		return "", errors.New("not byte mode")
+	}
+	n := take(countBits(c.Version))
+	out := make([]byte, n)
+	for j := range out {
+		out[j] = byte(take(8))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/shell"
+)
+
+// DefaultTimeout bounds how long an exec action or notification may take.
+const DefaultTimeout = 30 * time.Second
+
+// ErrNoNotifier is returned by Notify on systems without a supported
+// desktop notification command.
+var ErrNoNotifier = errors.New("desktop notifications are not supported on this system")
+
+// Expand replaces {rule}, {id}, {from} and {subject} in text with the
+// rule name and the message's fields, and {account.<key>} with the
+// account's metadata.
+func Expand(text, rule string, msg *mail.Message, account map[string]string) string {
+	return config.ExpandMetadata(strings.NewReplacer(
+		"{rule}", rule,
+		"{id}", msg.ID,
+		"{from}", msg.From.String(),
+		"{subject}", msg.Subject,
+	).Replace(text), account)
+}
+
+// Exec runs command with the shell for a message matched by rule. The
+// message's plain-text body is passed on stdin, and its ID, thread ID,
+// sender and subject in GOOG_MESSAGE_ID, GOOG_THREAD_ID, GOOG_MESSAGE_FROM
+// and GOOG_MESSAGE_SUBJECT, with the rule name in GOOG_RULE. Each entry of
+// the account's metadata is passed as GOOG_ACCOUNT_<KEY>, e.g.
+// GOOG_ACCOUNT_ROLE.
+func Exec(ctx context.Context, command, rule string, msg *mail.Message, account map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	c := shell.Command(ctx, command)
+	c.Env = append(os.Environ(),
+		"GOOG_RULE="+rule,
+		"GOOG_MESSAGE_ID="+msg.ID,
+		"GOOG_THREAD_ID="+msg.ThreadID,
+		"GOOG_MESSAGE_FROM="+msg.From.String(),
+		"GOOG_MESSAGE_SUBJECT="+msg.Subject,
+	)
+	for key, value := range account {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		c.Env = append(c.Env, "GOOG_ACCOUNT_"+name+"="+value)
+	}
+	c.Stdin = strings.NewReader(msg.Body)
+	return shell.Run(c, "exec action")
+}
+
+// Notify shows a desktop notification with notify-send on Linux and
+// other Unix systems or osascript on macOS.
+func Notify(ctx context.Context, title, text string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(text), appleScriptString(title))
+		c = exec.CommandContext(ctx, "osascript", "-e", script)
+	case "windows":
+		return ErrNoNotifier
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return ErrNoNotifier
+		}
+		c = exec.CommandContext(ctx, "notify-send", title, text)
+	}
+	return shell.Run(c, "notification")
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+)
+
+// JournalDirName is the name of the directory, next to the config file,
+// holding the journals of 'mail classify' runs.
+const JournalDirName = "classify"
+
+// journalExt ends the file name of a journal; undoneExt replaces it once
+// the run has been undone.
+const (
+	journalExt = ".jsonl"
+	undoneExt  = ".undone"
+)
+
+// JournalDir returns the directory of the classification journals.
+func JournalDir() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), JournalDirName)
+}
+
+// JournalEntry records the label changes a run made to one message: the
+// label IDs it added and those it removed, each of which the message did
+// not have, or had, before.
+type JournalEntry struct {
+	ID     string   `json:"id"`
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Journal records the changes of one run as they are made, one JSON entry
+// per line, so an interrupted run can still be undone.
+type Journal struct {
+	ID   string
+	file *os.File
+}
+
+// CreateJournal creates the journal of a run started at now in dir. The
+// run ID is the time, such as 20260316-140502.
+func CreateJournal(dir string, now time.Time) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	id := now.Format("20060102-150405")
+	f, err := os.OpenFile(filepath.Join(dir, id+journalExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create journal: %w", err)
+	}
+	return &Journal{ID: id, file: f}, nil
+}
+
+// Append records entries and syncs them to disk.
+func (j *Journal) Append(entries ...JournalEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode journal entry: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	if _, err := j.file.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// Close closes the journal. A journal without entries is removed, as
+// there is nothing to undo.
+func (j *Journal) Close() error {
+	info, statErr := j.file.Stat()
+	if err := j.file.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
+	if statErr == nil && info.Size() == 0 {
+		_ = os.Remove(j.file.Name())
+	}
+	return nil
+}
+
+// Journals returns the IDs of the runs in dir that can be undone, oldest
+// first.
+func Journals(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+journalExt))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, strings.TrimSuffix(filepath.Base(m), journalExt))
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// ReadJournal returns the entries of the run with the given ID in dir.
+func ReadJournal(dir, id string) ([]JournalEntry, error) {
+	f, err := os.Open(filepath.Join(dir, id+journalExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no classify run %s to undo", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			// The last line of an interrupted run may be cut short
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// MarkUndone renames the journal of the run with the given ID in dir so
+// that it is no longer offered for undo, keeping it for reference.
+func MarkUndone(dir, id string) error {
+	path := filepath.Join(dir, id+journalExt)
+	if err := os.Rename(path, path+undoneExt); err != nil {
+		return fmt.Errorf("failed to mark run %s undone: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestJournal(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), JournalDirName)
+	start := time.Date(2026, 3, 16, 14, 5, 2, 0, time.UTC)
+
+	j, err := CreateJournal(dir, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.ID != "20260316-140502" {
+		t.Errorf("ID = %q", j.ID)
+	}
+	if err := j.Append(JournalEntry{ID: "m1", Add: []string{"Label_1"}}, JournalEntry{ID: "m2", Remove: []string{"INBOX"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append(JournalEntry{ID: "m3", Add: []string{"Label_1"}, Remove: []string{"INBOX"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A run that changed nothing leaves no journal
+	empty, err := CreateJournal(dir, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := empty.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := Journals(dir)
+	if err != nil || !slices.Equal(ids, []string{"20260316-140502"}) {
+		t.Fatalf("Journals() = %v, %v", ids, err)
+	}
+
+	// A cut-short last line is skipped
+	f, err := os.OpenFile(filepath.Join(dir, j.ID+journalExt), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"id":"m4","ad`)
+	_ = f.Close()
+
+	entries, err := ReadJournal(dir, j.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[2].ID != "m3" || !slices.Equal(entries[2].Remove, []string{"INBOX"}) {
+		t.Errorf("ReadJournal() = %+v", entries)
+	}
+
+	if err := MarkUndone(dir, j.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := Journals(dir); len(ids) != 0 {
+		t.Errorf("an undone run should not be listed, got %v", ids)
+	}
+	if _, err := ReadJournal(dir, j.ID); err == nil {
+		t.Error("expected an undone run to be gone")
+	}
+}
@@ -0,0 +1,216 @@
+// Package rules loads local mail automation rules from a YAML file and
+// keeps track of the messages they have already processed. Rules run on
+// this machine, complementing Gmail's server-side filters with actions
+// such as notifications and shell commands.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the rules file kept next to the config file.
+const FileName = "rules.yaml"
+
+// SeenFileName is the name of the file, next to the config file, that
+// records the messages rules have already run on.
+const SeenFileName = "rules-seen.json"
+
+// MaxSeen is the number of most recently processed message IDs kept.
+const MaxSeen = 5000
+
+// Path returns the path of the default rules file.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// SeenPath returns the path of the processed-messages file.
+func SeenPath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), SeenFileName)
+}
+
+// file is the YAML layout of a rules file.
+type file struct {
+	Rules []fileRule `yaml:"rules"`
+}
+
+type fileRule struct {
+	Name       string          `yaml:"name"`
+	Match      string          `yaml:"match"`
+	Conditions []fileCondition `yaml:"conditions"`
+	Actions    []fileAction    `yaml:"actions"`
+	Stop       bool            `yaml:"stop"`
+}
+
+type fileCondition struct {
+	Field    string  `yaml:"field"`
+	Contains *string `yaml:"contains"`
+	Equals   *string `yaml:"equals"`
+	Matches  *string `yaml:"matches"`
+	Not      bool    `yaml:"not"`
+}
+
+type fileAction struct {
+	Label   string `yaml:"label"`
+	Archive bool   `yaml:"archive"`
+	Forward string `yaml:"forward"`
+	Notify  string `yaml:"notify"`
+	Exec    string `yaml:"exec"`
+}
+
+// Load reads and validates the rules file at path.
+func Load(path string) ([]*mail.Rule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no rules file at %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates rules in the YAML rules file format.
+func Parse(data []byte) ([]*mail.Rule, error) {
+	var f file
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	rules := make([]*mail.Rule, 0, len(f.Rules))
+	names := make(map[string]bool)
+	for i, fr := range f.Rules {
+		rule, err := fr.toDomain()
+		if err == nil {
+			err = rule.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if names[strings.ToLower(rule.Name)] {
+			return nil, fmt.Errorf("rule %d: duplicate rule name %q", i+1, rule.Name)
+		}
+		names[strings.ToLower(rule.Name)] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// toDomain converts a rule from the file into a domain rule.
+func (fr fileRule) toDomain() (*mail.Rule, error) {
+	rule := &mail.Rule{Name: strings.TrimSpace(fr.Name), Stop: fr.Stop}
+	switch strings.ToLower(strings.TrimSpace(fr.Match)) {
+	case "", "all":
+	case "any":
+		rule.MatchAny = true
+	default:
+		return nil, fmt.Errorf("match must be all or any, got %q", fr.Match)
+	}
+
+	for _, fc := range fr.Conditions {
+		cond := &mail.RuleCondition{Field: mail.RuleField(strings.ToLower(strings.TrimSpace(fc.Field))), Negate: fc.Not}
+		set := 0
+		for _, op := range []struct {
+			value *string
+			op    mail.RuleOperator
+		}{{fc.Contains, mail.RuleOpContains}, {fc.Equals, mail.RuleOpEquals}, {fc.Matches, mail.RuleOpMatches}} {
+			if op.value != nil {
+				cond.Operator, cond.Value = op.op, *op.value
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("condition on %q needs exactly one of contains, equals or matches", fc.Field)
+		}
+		rule.Conditions = append(rule.Conditions, cond)
+	}
+
+	for _, fa := range fr.Actions {
+		var actions []*mail.RuleAction
+		if fa.Label != "" {
+			actions = append(actions, &mail.RuleAction{Type: mail.RuleActionLabel, Value: fa.Label})
+		}
+		if fa.Archive {
+			actions = append(actions, &mail.RuleAction{Type: mail.RuleActionArchive})
+		}
+		if fa.Forward != "" {
+			actions = append(actions, &mail.RuleAction{Type: mail.RuleActionForward, Value: fa.Forward})
+		}
+		if fa.Notify != "" {
+			actions = append(actions, &mail.RuleAction{Type: mail.RuleActionNotify, Value: fa.Notify})
+		}
+		if fa.Exec != "" {
+			actions = append(actions, &mail.RuleAction{Type: mail.RuleActionExec, Value: fa.Exec})
+		}
+		if len(actions) != 1 {
+			return nil, fmt.Errorf("each action needs exactly one of label, archive, forward, notify or exec")
+		}
+		rule.Actions = append(rule.Actions, actions[0])
+	}
+	return rule, nil
+}
+
+// Seen records the IDs of messages that rules have already run on, so a
+// scheduled run does not act on the same message twice.
+type Seen struct {
+	path string
+	ids  []string
+}
+
+// LoadSeen reads the processed-messages file at path. A missing file
+// means nothing has been processed yet.
+func LoadSeen(path string) (*Seen, error) {
+	s := &Seen{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processed messages: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.ids); err != nil {
+		return nil, fmt.Errorf("failed to parse processed messages: %w", err)
+	}
+	return s, nil
+}
+
+// Has reports whether the message with the given ID has been processed.
+func (s *Seen) Has(id string) bool {
+	return slices.Contains(s.ids, id)
+}
+
+// Add marks the message with the given ID as processed.
+func (s *Seen) Add(id string) {
+	if !s.Has(id) {
+		s.ids = append(s.ids, id)
+	}
+}
+
+// Save writes the most recent MaxSeen IDs back to the file.
+func (s *Seen) Save() error {
+	if len(s.ids) > MaxSeen {
+		s.ids = s.ids[len(s.ids)-MaxSeen:]
+	}
+	data, err := json.Marshal(s.ids)
+	if err != nil {
+		return fmt.Errorf("failed to encode processed messages: %w", err)
+	}
+	if err := filelock.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save processed messages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+const sampleRules = `
+rules:
+  - name: Receipts
+    match: any
+    conditions:
+      - field: from
+        contains: "@shop.example.com"
+      - field: subject
+        matches: "(?i)order #\\d+"
+    actions:
+      - label: Receipts
+      - archive: true
+    stop: true
+  - name: Pager
+    conditions:
+      - field: label
+        equals: INBOX
+      - field: subject
+        contains: "[alert]"
+        not: true
+    actions:
+      - forward: oncall@example.com
+      - notify: "{subject} from {from}"
+      - exec: ~/bin/page
+`
+
+func TestParse(t *testing.T) {
+	rules, err := Parse([]byte(sampleRules))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+
+	receipts := rules[0]
+	if receipts.Name != "Receipts" || !receipts.MatchAny || !receipts.Stop {
+		t.Errorf("unexpected rule: %+v", receipts)
+	}
+	if c := receipts.Conditions[1]; c.Field != mail.RuleFieldSubject || c.Operator != mail.RuleOpMatches || c.Value != `(?i)order #\d+` {
+		t.Errorf("unexpected condition: %+v", c)
+	}
+	if a := receipts.Actions; len(a) != 2 || a[0].Type != mail.RuleActionLabel || a[0].Value != "Receipts" || a[1].Type != mail.RuleActionArchive {
+		t.Errorf("unexpected actions: %+v %+v", a[0], a[1])
+	}
+
+	pager := rules[1]
+	if pager.MatchAny || !pager.Conditions[1].Negate {
+		t.Errorf("unexpected rule: %+v", pager)
+	}
+	var types []string
+	for _, a := range pager.Actions {
+		types = append(types, string(a.Type))
+	}
+	if got := strings.Join(types, ","); got != "forward,notify,exec" {
+		t.Errorf("action types = %s", got)
+	}
+
+	if !receipts.Matches(&mail.Message{Subject: "Order #42 confirmed"}, nil) {
+		t.Error("expected the parsed pattern to match")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"unknown key", "rules:\n  - name: a\n    when: x\n", "field when not found"},
+		{"two operators", "rules:\n  - name: a\n    conditions:\n      - field: from\n        contains: x\n        equals: y\n    actions:\n      - archive: true\n", "exactly one of contains"},
+		{"two actions in one item", "rules:\n  - name: a\n    conditions:\n      - field: from\n        contains: x\n    actions:\n      - archive: true\n        label: y\n", "exactly one of label"},
+		{"bad match", "rules:\n  - name: a\n    match: some\n", "match must be all or any"},
+		{"invalid rule", "rules:\n  - name: a\n    conditions:\n      - field: date\n        contains: x\n    actions:\n      - archive: true\n", "unknown condition field"},
+		{"duplicate", "rules:\n" + strings.Repeat("  - name: a\n    conditions:\n      - field: from\n        contains: x\n    actions:\n      - archive: true\n", 2), "duplicate rule name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if rules, err := Parse(nil); err != nil || len(rules) != 0 {
+		t.Errorf("Parse(empty) = %v, %v; want no rules", rules, err)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err == nil || !strings.Contains(err.Error(), "no rules file") {
+		t.Errorf("Load() error = %v", err)
+	}
+}
+
+func TestSeen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SeenFileName)
+	seen, err := LoadSeen(path)
+	if err != nil {
+		t.Fatalf("LoadSeen failed: %v", err)
+	}
+	for i := 0; i < MaxSeen+10; i++ {
+		seen.Add(fmt.Sprintf("m%d", i))
+	}
+	seen.Add("m5")
+	if err := seen.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded, err := LoadSeen(path)
+	if err != nil {
+		t.Fatalf("LoadSeen failed: %v", err)
+	}
+	if reloaded.Has("m5") || !reloaded.Has("m10") || !reloaded.Has(fmt.Sprintf("m%d", MaxSeen+9)) {
+		t.Error("expected only the most recent IDs to be kept")
+	}
+	if info, err := os.Stat(path); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0600) {
+		t.Errorf("unexpected file mode: %v %v", info, err)
+	}
+}
+
+func TestExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	msg := &mail.Message{ID: "m1", From: mail.Address{Email: "a@example.com"}, Subject: "Hi", Body: "hello body"}
+	command := fmt.Sprintf(`{ echo "$GOOG_RULE $GOOG_MESSAGE_ID $GOOG_MESSAGE_SUBJECT $GOOG_ACCOUNT_COST_CENTRE"; cat; } > %q`, out)
+	if err := Exec(context.Background(), command, "Save", msg, map[string]string{"cost-centre": "42"}); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "Save m1 Hi 42\nhello body" {
+		t.Errorf("command saw %q", got)
+	}
+
+	err = Exec(context.Background(), "echo broken >&2; exit 3", "Save", msg, nil)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected stderr in the error, got %v", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	msg := &mail.Message{ID: "m1", From: mail.Address{Email: "a@example.com"}, Subject: "Hi"}
+	if got := Expand("{rule}: {subject} from {from} ({id})", "R", msg, nil); got != "R: Hi from a@example.com (m1)" {
+		t.Errorf("Expand() = %q", got)
+	}
+	if got := Expand("[{account.role}{account.missing}] {subject}", "R", msg, map[string]string{"role": "work"}); got != "[work] Hi" {
+		t.Errorf("Expand() with account = %q", got)
+	}
+}
+
+func TestAppleScriptString(t *testing.T) {
+	if got := appleScriptString(`say "hi" \o/`); got != `"say \"hi\" \\o/"` {
+		t.Errorf("appleScriptString() = %s", got)
+	}
+}
@@ -0,0 +1,270 @@
+// Package schedule manages goog jobs in the user's crontab. Each job is a
+// cron entry that runs the goog binary, preceded by a marker comment that
+// holds the job number and the goog command as it was given, so jobs can
+// be listed and removed without touching the user's other entries.
+package schedule
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Marker starts the comment line that precedes each goog job.
+const Marker = "# goog-schedule"
+
+// ErrNotFound is returned by Remove when no job has the requested number.
+var ErrNotFound = errors.New("scheduled job not found")
+
+// Job is a goog command run on a cron schedule.
+type Job struct {
+	// ID numbers jobs in the crontab, starting at 1.
+	ID int `json:"id"`
+	// Spec is the cron schedule, e.g. "0 8 * * 1-5" or "@daily".
+	Spec string `json:"schedule"`
+	// Command is the goog command without the program name, as given to
+	// goog schedule add.
+	Command string `json:"command"`
+	// Entry is the crontab line that runs the command.
+	Entry string `json:"entry"`
+}
+
+// markerPattern matches a marker line and captures the job number and
+// command.
+var markerPattern = regexp.MustCompile(`^` + Marker + ` (\d+): (.*)$`)
+
+// Parse returns the goog jobs in a crontab, in the order they appear.
+// Marker lines without an entry after them are ignored.
+func Parse(crontab string) []Job {
+	lines := strings.Split(crontab, "\n")
+	var jobs []Job
+	for i := 0; i+1 < len(lines); i++ {
+		m := markerPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			continue
+		}
+		entry := strings.TrimSpace(lines[i+1])
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, _ := strconv.Atoi(m[1])
+		jobs = append(jobs, Job{ID: id, Spec: entrySpec(entry), Command: m[2], Entry: entry})
+		i++
+	}
+	return jobs
+}
+
+// entrySpec returns the schedule at the start of a crontab entry.
+func entrySpec(entry string) string {
+	fields := strings.Fields(entry)
+	n := 5
+	if strings.HasPrefix(entry, "@") {
+		n = 1
+	}
+	if len(fields) < n {
+		return strings.Join(fields, " ")
+	}
+	return strings.Join(fields[:n], " ")
+}
+
+// Add appends job to a crontab, numbering it after the existing jobs, and
+// returns the new crontab and the numbered job. Only Command and Entry of
+// job are used; Entry must start with the schedule.
+func Add(crontab string, job Job) (string, Job) {
+	job.ID = 1
+	for _, j := range Parse(crontab) {
+		if j.ID >= job.ID {
+			job.ID = j.ID + 1
+		}
+	}
+	job.Spec = entrySpec(job.Entry)
+
+	var b strings.Builder
+	b.WriteString(crontab)
+	if crontab != "" && !strings.HasSuffix(crontab, "\n") {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%s %d: %s\n%s\n", Marker, job.ID, oneLine(job.Command), job.Entry)
+	return b.String(), job
+}
+
+// Remove deletes the job numbered id, with its marker line, from a crontab.
+func Remove(crontab string, id int) (string, error) {
+	lines := strings.Split(crontab, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		m := markerPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil || m[1] != strconv.Itoa(id) {
+			continue
+		}
+		entry := strings.TrimSpace(lines[i+1])
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		return strings.Join(append(lines[:i:i], lines[i+2:]...), "\n"), nil
+	}
+	return crontab, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
+
+// Entry builds the crontab line that runs args with the program at exe on
+// the schedule spec. env holds NAME=value pairs set for the command, and
+// output is appended to logPath when it is not empty.
+func Entry(spec string, env []string, exe string, args []string, logPath string) string {
+	words := make([]string, 0, len(env)+len(args)+1)
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		words = append(words, name+"="+quote(value))
+	}
+	words = append(words, quote(exe))
+	for _, arg := range args {
+		words = append(words, quote(arg))
+	}
+	command := strings.Join(words, " ")
+	if logPath != "" {
+		command += " >>" + quote(logPath) + " 2>&1"
+	}
+	// cron turns an unescaped % into a newline
+	return spec + " " + strings.ReplaceAll(command, "%", `\%`)
+}
+
+// quote quotes s for sh when it contains characters the shell would split
+// or expand.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`!*?;&|<>()[]{}#~%") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// oneLine keeps a command on a single crontab line.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cronFields are the names and ranges of the five schedule fields.
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// cronMacros are the schedules cron accepts in place of the five fields.
+var cronMacros = map[string]bool{
+	"@reboot": true, "@yearly": true, "@annually": true, "@monthly": true,
+	"@weekly": true, "@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// ValidateSpec checks that spec is a cron schedule: five fields (minute,
+// hour, day of month, month, day of week) or a macro such as @daily.
+func ValidateSpec(spec string) error {
+	fields := strings.Fields(spec)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		if !cronMacros[fields[0]] {
+			return fmt.Errorf("invalid schedule %q: unknown macro", spec)
+		}
+		return nil
+	}
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("invalid schedule %q: want 5 fields (minute hour day-of-month month day-of-week) or a macro such as @daily", spec)
+	}
+	for i, field := range fields {
+		if err := validateField(field, i); err != nil {
+			return fmt.Errorf("invalid schedule %q: %s: %w", spec, cronFields[i].name, err)
+		}
+	}
+	return nil
+}
+
+// validateField checks one schedule field: a comma-separated list of *,
+// values or ranges, each optionally followed by /step.
+func validateField(field string, i int) error {
+	f := cronFields[i]
+	for _, item := range strings.Split(field, ",") {
+		base, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", step)
+			}
+		}
+		if base == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(base, "-")
+		from, err := fieldValue(lo, f.min, f.max, f.names)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		to, err := fieldValue(hi, f.min, f.max, f.names)
+		if err != nil {
+			return err
+		}
+		if to < from {
+			return fmt.Errorf("invalid range %q", base)
+		}
+	}
+	return nil
+}
+
+// fieldValue parses a number or name in a schedule field.
+func fieldValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+	}
+	return n, nil
+}
+
+// Crontab reads and replaces a crontab.
+type Crontab interface {
+	// Read returns the crontab, or an empty string when there is none.
+	Read() (string, error)
+	// Write replaces the crontab.
+	Write(crontab string) error
+}
+
+// UserCrontab is the current user's crontab, managed with the crontab
+// command.
+type UserCrontab struct{}
+
+// Read returns the user's crontab.
+func (UserCrontab) Read() (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("crontab", "-l")
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(strings.ToLower(stderr.String()), "no crontab") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read crontab: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Write replaces the user's crontab.
+func (UserCrontab) Write(crontab string) error {
+	var stderr bytes.Buffer
+	c := exec.Command("crontab", "-")
+	c.Stdin, c.Stderr = strings.NewReader(crontab), &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to write crontab: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package schedule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const userCrontab = `MAILTO=me@example.com
+# nightly backup
+0 2 * * * /usr/local/bin/backup
+`
+
+func TestAddParseRemove(t *testing.T) {
+	crontab, first := Add(userCrontab, Job{
+		Command: "mail list --unread-only",
+		Entry:   Entry("0 8 * * 1-5", nil, "/usr/bin/goog", []string{"mail", "list", "--unread-only"}, ""),
+	})
+	crontab, second := Add(crontab, Job{
+		Command: "cal today",
+		Entry:   Entry("@daily", nil, "/usr/bin/goog", []string{"cal", "today"}, ""),
+	})
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs = %d, %d, want 1, 2", first.ID, second.ID)
+	}
+	if !strings.HasPrefix(crontab, userCrontab) {
+		t.Errorf("Add should keep existing entries, got:\n%s", crontab)
+	}
+
+	jobs := Parse(crontab)
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2:\n%s", len(jobs), crontab)
+	}
+	if jobs[0].Spec != "0 8 * * 1-5" || jobs[0].Command != "mail list --unread-only" {
+		t.Errorf("first job = %+v", jobs[0])
+	}
+	if jobs[1].Spec != "@daily" || jobs[1].Entry != "@daily /usr/bin/goog cal today" {
+		t.Errorf("second job = %+v", jobs[1])
+	}
+
+	crontab, err := Remove(crontab, 1)
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	jobs = Parse(crontab)
+	if len(jobs) != 1 || jobs[0].ID != 2 {
+		t.Errorf("jobs after Remove = %+v, want only job 2", jobs)
+	}
+	if !strings.Contains(crontab, "/usr/local/bin/backup") || strings.Contains(crontab, "unread-only") {
+		t.Errorf("Remove changed the wrong lines:\n%s", crontab)
+	}
+
+	crontab, _ = Add(crontab, Job{Command: "tasks list", Entry: "@hourly /usr/bin/goog tasks list"})
+	if jobs := Parse(crontab); jobs[len(jobs)-1].ID != 3 {
+		t.Errorf("new job ID = %d, want 3", jobs[len(jobs)-1].ID)
+	}
+
+	if _, err := Remove(crontab, 9); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestParseIgnoresDanglingMarker(t *testing.T) {
+	crontab := Marker + " 1: mail list\n# a comment\n" + Marker + " 2: cal today\n"
+	if jobs := Parse(crontab); len(jobs) != 0 {
+		t.Errorf("got %+v, want no jobs", jobs)
+	}
+}
+
+func TestEntry(t *testing.T) {
+	got := Entry("0 8 * * 1-5",
+		[]string{"GOOG_CONFIG=/home/me/my config/config.yaml"},
+		"/usr/bin/goog",
+		[]string{"--account", "work", "mail", "search", "subject:100% off"},
+		"/home/me/.config/goog/schedule.log")
+	want := `0 8 * * 1-5 GOOG_CONFIG='/home/me/my config/config.yaml' /usr/bin/goog --account work mail search 'subject:100\% off' >>/home/me/.config/goog/schedule.log 2>&1`
+	if got != want {
+		t.Errorf("Entry =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	valid := []string{"0 8 * * 1-5", "*/15 * * * *", "0 9,17 1 jan-jun mon", "30 6 * * 7", "@daily", "@reboot"}
+	for _, spec := range valid {
+		if err := ValidateSpec(spec); err != nil {
+			t.Errorf("ValidateSpec(%q) failed: %v", spec, err)
+		}
+	}
+	invalid := []string{"", "0 8 * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "@sometimes", "a b c d e"}
+	for _, spec := range invalid {
+		if err := ValidateSpec(spec); err == nil {
+			t.Errorf("ValidateSpec(%q) should fail", spec)
+		}
+	}
+}
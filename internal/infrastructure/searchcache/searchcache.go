@@ -0,0 +1,115 @@
+// Package searchcache keeps the results of recent mail searches, so a
+// search run again soon after can reuse them instead of listing and
+// fetching every message again.
+package searchcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the search cache kept next to the config file.
+const FileName = "search_cache.json"
+
+// MaxEntries is the number of searches kept; the oldest are dropped first.
+const MaxEntries = 20
+
+// Path returns the path of the search cache.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// Entry is the cached result of one search.
+type Entry struct {
+	Account string `json:"account"`
+	// Key identifies the search: its query and the options that change
+	// which messages are fetched.
+	Key string `json:"key"`
+	// HistoryID is the mailbox history ID taken before the search ran.
+	HistoryID uint64    `json:"history_id"`
+	CachedAt  time.Time `json:"cached_at"`
+	// Total is the API's estimate of matching messages.
+	Total int `json:"total"`
+	// Messages are the messages fetched, in the order they were returned.
+	Messages []*mail.Message `json:"messages"`
+}
+
+// Load reads the cache at path. A missing file is an empty cache.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search cache: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse search cache: %w", err)
+	}
+	return entries, nil
+}
+
+// Lookup returns the entry of the cache at path for the account and key,
+// or nil when there is none cached less than ttl before now.
+func Lookup(path, account, key string, ttl time.Duration, now time.Time) (*Entry, error) {
+	entries, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		e := &entries[i]
+		if e.Account == account && e.Key == key && now.Sub(e.CachedAt) < ttl {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+// Store adds entry to the cache at path, replacing any entry for the same
+// account and key. Entries older than ttl are dropped, and the oldest
+// beyond MaxEntries.
+func Store(path string, entry Entry, ttl time.Duration) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create search cache directory: %w", err)
+	}
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := Load(path)
+	if err != nil {
+		// A damaged cache is only a cache; start again
+		entries = nil
+	}
+	kept := []Entry{}
+	for _, e := range entries {
+		if (e.Account == entry.Account && e.Key == entry.Key) || entry.CachedAt.Sub(e.CachedAt) >= ttl {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	kept = append(kept, entry)
+	if len(kept) > MaxEntries {
+		kept = kept[len(kept)-MaxEntries:]
+	}
+
+	data, err := json.Marshal(kept)
+	if err != nil {
+		return fmt.Errorf("failed to encode search cache: %w", err)
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write search cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package searchcache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+func TestStoreLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	ttl := 5 * time.Minute
+
+	if e, err := Lookup(path, "me@example.com", "is:unread", ttl, now); e != nil || err != nil {
+		t.Fatalf("Lookup() of a missing file = %v, %v; want nothing", e, err)
+	}
+
+	entry := Entry{
+		Account:   "me@example.com",
+		Key:       "is:unread",
+		HistoryID: 42,
+		CachedAt:  now,
+		Total:     7,
+		Messages:  []*mail.Message{{ID: "msg1", Subject: "Hello", Labels: []string{"INBOX"}}},
+	}
+	if err := Store(path, entry, ttl); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	got, err := Lookup(path, "me@example.com", "is:unread", ttl, now.Add(time.Minute))
+	if err != nil || got == nil {
+		t.Fatalf("Lookup() = %v, %v; want the entry", got, err)
+	}
+	if got.HistoryID != 42 || got.Total != 7 || len(got.Messages) != 1 || got.Messages[0].Subject != "Hello" {
+		t.Errorf("entry = %+v", got)
+	}
+	for name, lookup := range map[string]func() (*Entry, error){
+		"other account": func() (*Entry, error) { return Lookup(path, "you@example.com", "is:unread", ttl, now) },
+		"other key":     func() (*Entry, error) { return Lookup(path, "me@example.com", "is:starred", ttl, now) },
+		"expired":       func() (*Entry, error) { return Lookup(path, "me@example.com", "is:unread", ttl, now.Add(ttl)) },
+	} {
+		if e, err := lookup(); e != nil || err != nil {
+			t.Errorf("%s: Lookup() = %v, %v; want nothing", name, e, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("cache file mode = %v, %v; want 0600", info, err)
+	}
+}
+
+func TestStoreReplacesAndTrims(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	ttl := time.Hour
+
+	if err := Store(path, Entry{Account: "a", Key: "old", CachedAt: now.Add(-2 * time.Hour)}, 3*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	for i := range MaxEntries + 2 {
+		if err := Store(path, Entry{Account: "a", Key: fmt.Sprint(i), CachedAt: now}, ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Store(path, Entry{Account: "a", Key: "5", CachedAt: now, Total: 9}, ttl); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Fatalf("kept %d entries, want %d", len(entries), MaxEntries)
+	}
+	seen := map[string]int{}
+	for _, e := range entries {
+		seen[e.Key]++
+	}
+	if seen["old"] != 0 || seen["0"] != 0 || seen["1"] != 0 || seen["2"] != 1 {
+		t.Errorf("expected the expired and oldest entries to be dropped, kept %v", seen)
+	}
+	if seen["5"] != 1 || entries[len(entries)-1].Total != 9 {
+		t.Errorf("expected one, replaced entry for key 5, kept %v", seen)
+	}
+}
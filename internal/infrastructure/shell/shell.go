@@ -0,0 +1,51 @@
+// Package shell runs user-supplied commands, such as summarizers, travel
+// estimators and rule actions, with the system shell.
+package shell
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// waitDelay is how long Run waits for a killed command's output to close.
+const waitDelay = time.Second
+
+// Command returns a command that runs command with sh, or cmd on Windows.
+// It is killed when ctx is done.
+func Command(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/c", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Run runs c. When it fails, the error names it as what and includes its
+// stderr.
+func Run(c *exec.Cmd, what string) error {
+	// Children of the shell may hold the output open after a timeout
+	c.WaitDelay = waitDelay
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s failed: %w: %s", what, err, msg)
+		}
+		return fmt.Errorf("%s failed: %w", what, err)
+	}
+	return nil
+}
+
+// Output runs c like Run and returns its standard output.
+func Output(c *exec.Cmd, what string) ([]byte, error) {
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	if err := Run(c, what); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx := context.Background()
+
+	c := Command(ctx, `read line; echo "$line from $GOOG_TEST"`)
+	c.Env = []string{"GOOG_TEST=shell"}
+	c.Stdin = strings.NewReader("hello\n")
+	out, err := Output(c, "test command")
+	if err != nil || string(out) != "hello from shell\n" {
+		t.Errorf("Output = %q, %v", out, err)
+	}
+
+	_, err = Output(Command(ctx, "echo no route >&2; exit 3"), "test command")
+	var exitErr *exec.ExitError
+	if err == nil || !errors.As(err, &exitErr) || err.Error() != "test command failed: exit status 3: no route" {
+		t.Errorf("error = %v, want the exit status and stderr", err)
+	}
+
+	if err := Run(Command(ctx, "exit 1"), "test command"); err == nil || err.Error() != "test command failed: exit status 1" {
+		t.Errorf("error = %v, want the exit status alone", err)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The backgrounded sleep keeps stdout open after the shell is killed.
+	start := time.Now()
+	c := Command(ctx, "sleep 5 & sleep 5")
+	if _, err := Output(c, "test command"); err == nil {
+		t.Error("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Output returned after %v, want about %v", elapsed, waitDelay)
+	}
+}
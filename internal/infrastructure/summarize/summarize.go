@@ -0,0 +1,242 @@
+// Package summarize hands email threads to a user-supplied command or HTTP
+// endpoint for summarizing, so any language model tool can be plugged in
+// without goog depending on it.
+//
+// Both backends receive a Request encoded as JSON and reply with either a
+// JSON Response or the summary as plain text. The format is versioned:
+// fields may be added within a version but are never renamed or removed.
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/shell"
+)
+
+// Version is the interchange format version sent in every Request.
+const Version = 1
+
+// DefaultTimeout bounds how long a summary may take.
+const DefaultTimeout = 2 * time.Minute
+
+// maxErrorBody is how much of an HTTP error response is included in the
+// returned error.
+const maxErrorBody = 512
+
+// Request is the document sent to a summarizer.
+type Request struct {
+	Version int    `json:"version"`
+	Thread  Thread `json:"thread"`
+	// Text is the whole conversation rendered as Markdown, ready to be
+	// used as a prompt.
+	Text string `json:"text"`
+}
+
+// Thread is a conversation in a Request.
+type Thread struct {
+	ID       string    `json:"id"`
+	Subject  string    `json:"subject"`
+	Messages []Message `json:"messages"`
+}
+
+// Message is one message of a Thread. Body is plain text; HTML-only
+// messages are converted before they are sent.
+type Message struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	To      []string  `json:"to"`
+	Cc      []string  `json:"cc,omitempty"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+}
+
+// Response is the JSON document a summarizer may reply with. A non-empty
+// Error reports a failure.
+type Response struct {
+	Version int    `json:"version,omitempty"`
+	Summary string `json:"summary"`
+	Error   string `json:"error,omitempty"`
+}
+
+// NewRequest builds the request for thread. text is the rendered
+// conversation and body returns a message's plain-text body; nil uses
+// Message.Body.
+func NewRequest(thread *mail.Thread, text string, body func(*mail.Message) string) *Request {
+	if body == nil {
+		body = func(msg *mail.Message) string { return msg.Body }
+	}
+	req := &Request{
+		Version: Version,
+		Thread:  Thread{ID: thread.ID, Subject: threadSubject(thread), Messages: []Message{}},
+		Text:    text,
+	}
+	for _, msg := range thread.Messages {
+		if msg == nil {
+			continue
+		}
+		var cc []string
+		if len(msg.Cc) > 0 {
+			cc = mail.AddressStrings(msg.Cc)
+		}
+		req.Thread.Messages = append(req.Thread.Messages, Message{
+			ID:      msg.ID,
+			From:    msg.From.String(),
+			To:      mail.AddressStrings(msg.To),
+			Cc:      cc,
+			Date:    msg.Date,
+			Subject: msg.Subject,
+			Body:    body(msg),
+		})
+	}
+	return req
+}
+
+// ParseResponse extracts the summary from a summarizer's output. Output
+// that starts with "{" must be a JSON Response; anything else is taken as
+// the summary itself.
+func ParseResponse(output []byte) (string, error) {
+	text := strings.TrimSpace(string(output))
+	if strings.HasPrefix(text, "{") {
+		var resp Response
+		if err := json.Unmarshal([]byte(text), &resp); err != nil {
+			return "", fmt.Errorf("invalid summarizer response: %w", err)
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf("summarizer reported an error: %s", resp.Error)
+		}
+		text = strings.TrimSpace(resp.Summary)
+	}
+	if text == "" {
+		return "", errors.New("summarizer returned an empty summary")
+	}
+	return text, nil
+}
+
+// CommandSummarizer runs a shell command to summarize a thread. The
+// command receives the Request as JSON on stdin and the thread ID in
+// GOOG_THREAD_ID, and prints the summary.
+type CommandSummarizer struct {
+	// Command is the shell command to run.
+	Command string
+	// Timeout bounds each run; zero means DefaultTimeout.
+	Timeout time.Duration
+	// Body returns a message's plain-text body; nil uses Message.Body.
+	Body func(*mail.Message) string
+}
+
+// Compile-time interface compliance check.
+var _ mail.ThreadSummarizer = (*CommandSummarizer)(nil)
+
+// SummarizeThread runs the command for thread.
+func (s *CommandSummarizer) SummarizeThread(ctx context.Context, thread *mail.Thread, text string) (string, error) {
+	payload, err := json.Marshal(NewRequest(thread, text, s.Body))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode summary request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(s.Timeout))
+	defer cancel()
+
+	c := shell.Command(ctx, s.Command)
+	c.Env = append(os.Environ(), "GOOG_THREAD_ID="+thread.ID)
+	c.Stdin = bytes.NewReader(payload)
+	out, err := shell.Output(c, "summarize command")
+	if err != nil {
+		return "", err
+	}
+	return ParseResponse(out)
+}
+
+// HTTPSummarizer posts the Request as JSON to an HTTP endpoint, which
+// replies with the summary.
+type HTTPSummarizer struct {
+	// URL is the endpoint to post to.
+	URL string
+	// Token, when set, is sent as a bearer token.
+	Token string
+	// Client sends the request; nil means http.DefaultClient.
+	Client *http.Client
+	// Timeout bounds each request; zero means DefaultTimeout.
+	Timeout time.Duration
+	// Body returns a message's plain-text body; nil uses Message.Body.
+	Body func(*mail.Message) string
+}
+
+// Compile-time interface compliance check.
+var _ mail.ThreadSummarizer = (*HTTPSummarizer)(nil)
+
+// SummarizeThread posts thread to the endpoint.
+func (s *HTTPSummarizer) SummarizeThread(ctx context.Context, thread *mail.Thread, text string) (string, error) {
+	payload, err := json.Marshal(NewRequest(thread, text, s.Body))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode summary request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(s.Timeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("invalid summarize URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/plain")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("summarize request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read summarize response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(out))
+		if len(msg) > maxErrorBody {
+			msg = msg[:maxErrorBody] + "..."
+		}
+		if msg != "" {
+			return "", fmt.Errorf("summarize endpoint returned %s: %s", resp.Status, msg)
+		}
+		return "", fmt.Errorf("summarize endpoint returned %s", resp.Status)
+	}
+	return ParseResponse(out)
+}
+
+// threadSubject returns the subject of the first message that has one.
+func threadSubject(thread *mail.Thread) string {
+	for _, msg := range thread.Messages {
+		if msg != nil && msg.Subject != "" {
+			return msg.Subject
+		}
+	}
+	return ""
+}
+
+// timeoutOrDefault returns timeout, or DefaultTimeout when it is not set.
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,129 @@
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+func testThread() *mail.Thread {
+	return &mail.Thread{
+		ID: "t1",
+		Messages: []*mail.Message{
+			{ID: "m1", From: mail.Address{Email: "alice@example.com"}, To: []mail.Address{{Email: "bob@example.com"}}, Subject: "Launch plan", Body: "Ship Friday?", Date: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+			nil,
+			{ID: "m2", From: mail.Address{Email: "bob@example.com"}, Subject: "Re: Launch plan", BodyHTML: "<p>Monday</p>"},
+		},
+	}
+}
+
+func TestNewRequest(t *testing.T) {
+	req := NewRequest(testThread(), "# Launch plan", func(msg *mail.Message) string {
+		if msg.Body == "" {
+			return "converted"
+		}
+		return msg.Body
+	})
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":1,"thread":{"id":"t1","subject":"Launch plan","messages":[` +
+		`{"id":"m1","from":"alice@example.com","to":["bob@example.com"],"date":"2026-03-02T09:00:00Z","subject":"Launch plan","body":"Ship Friday?"},` +
+		`{"id":"m2","from":"bob@example.com","to":[],"date":"0001-01-01T00:00:00Z","subject":"Re: Launch plan","body":"converted"}]},` +
+		`"text":"# Launch plan"}`
+	if string(data) != want {
+		t.Errorf("request JSON =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    string
+		wantErr string
+	}{
+		{output: "  Ship on Monday.\n", want: "Ship on Monday."},
+		{output: `{"version":1,"summary":" Ship on Monday. "}`, want: "Ship on Monday."},
+		{output: `{"error":"rate limited"}`, wantErr: "rate limited"},
+		{output: `{"summary":`, wantErr: "invalid summarizer response"},
+		{output: `{"summary":""}`, wantErr: "empty summary"},
+		{output: "\n", wantErr: "empty summary"},
+	}
+	for _, tt := range tests {
+		got, err := ParseResponse([]byte(tt.output))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseResponse(%q) error = %v, want %q", tt.output, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseResponse(%q) = %q, %v; want %q", tt.output, got, err, tt.want)
+		}
+	}
+}
+
+func TestCommandSummarizer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx := context.Background()
+
+	s := &CommandSummarizer{Command: `test "$GOOG_THREAD_ID" = t1 && grep -q '"version":1' && echo "Ship on Monday."`}
+	got, err := s.SummarizeThread(ctx, testThread(), "text")
+	if err != nil || got != "Ship on Monday." {
+		t.Errorf("SummarizeThread = %q, %v", got, err)
+	}
+
+	failing := &CommandSummarizer{Command: "echo no model >&2; exit 2"}
+	if _, err := failing.SummarizeThread(ctx, testThread(), "text"); err == nil || !strings.Contains(err.Error(), "no model") {
+		t.Errorf("expected the command's stderr in the error, got %v", err)
+	}
+
+	slow := &CommandSummarizer{Command: "sleep 5", Timeout: 50 * time.Millisecond}
+	if _, err := slow.SummarizeThread(ctx, testThread(), "text"); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
+
+func TestHTTPSummarizer(t *testing.T) {
+	var got Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(Response{Version: Version, Summary: "Ship on Monday."})
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	s := &HTTPSummarizer{URL: server.URL, Token: "s3cret"}
+	summary, err := s.SummarizeThread(ctx, testThread(), "rendered")
+	if err != nil || summary != "Ship on Monday." {
+		t.Fatalf("SummarizeThread = %q, %v", summary, err)
+	}
+	if got.Version != Version || got.Thread.ID != "t1" || len(got.Thread.Messages) != 2 || got.Text != "rendered" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+
+	s.Token = ""
+	if _, err := s.SummarizeThread(ctx, testThread(), "rendered"); err == nil || !strings.Contains(err.Error(), "401 Unauthorized: unauthorized") {
+		t.Errorf("expected the endpoint's error, got %v", err)
+	}
+}
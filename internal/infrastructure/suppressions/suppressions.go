@@ -0,0 +1,117 @@
+// Package suppressions keeps the local list of addresses mail must not be
+// sent to, fed by detected bounces and by hand.
+package suppressions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/filelock"
+)
+
+// FileName is the name of the suppression list kept next to the config
+// file.
+const FileName = "suppressions.json"
+
+// Sources an address can be suppressed from.
+const (
+	SourceManual = "manual"
+	SourceBounce = "bounce"
+)
+
+// Path returns the path of the suppression list.
+func Path() string {
+	return filepath.Join(filepath.Dir(config.GetConfigPath()), FileName)
+}
+
+// Entry records why and since when an address is suppressed.
+type Entry struct {
+	Source string    `json:"source"`
+	Reason string    `json:"reason,omitempty"`
+	Added  time.Time `json:"added"`
+}
+
+// Load reads the suppression list at path, keyed by lower-cased address.
+// A missing file is an empty list.
+func Load(path string) (map[string]Entry, error) {
+	list := map[string]Entry{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suppression list: %w", err)
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse suppression list: %w", err)
+	}
+	return list, nil
+}
+
+// Add suppresses the addresses of entries, keyed by address, in the list
+// at path. Addresses already suppressed keep their entry. It returns the
+// lower-cased addresses that were added.
+func Add(path string, entries map[string]Entry) ([]string, error) {
+	var added []string
+	err := update(path, func(list map[string]Entry) {
+		for addr, entry := range entries {
+			key := strings.ToLower(strings.TrimSpace(addr))
+			if _, ok := list[key]; ok || key == "" {
+				continue
+			}
+			entry.Added = entry.Added.UTC().Truncate(time.Second)
+			list[key] = entry
+			added = append(added, key)
+		}
+	})
+	slices.Sort(added)
+	return added, err
+}
+
+// Remove deletes addrs from the list at path and returns the lower-cased
+// addresses that were suppressed.
+func Remove(path string, addrs []string) ([]string, error) {
+	var removed []string
+	err := update(path, func(list map[string]Entry) {
+		for _, addr := range addrs {
+			key := strings.ToLower(strings.TrimSpace(addr))
+			if _, ok := list[key]; ok {
+				delete(list, key)
+				removed = append(removed, key)
+			}
+		}
+	})
+	return removed, err
+}
+
+// update applies change to the list at path under the file lock and
+// writes it back.
+func update(path string, change func(map[string]Entry)) error {
+	lock, err := filelock.Acquire(path, filelock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	list, err := Load(path)
+	if err != nil {
+		return err
+	}
+	change(list)
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode suppression list: %w", err)
+	}
+	if err := filelock.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write suppression list: %w", err)
+	}
+	return nil
+}
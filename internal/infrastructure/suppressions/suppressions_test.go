@@ -0,0 +1,49 @@
+package suppressions
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAddLoadRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	day := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	list, err := Load(path)
+	if err != nil || len(list) != 0 {
+		t.Fatalf("Load of a missing file = %v, %v; want an empty list", list, err)
+	}
+
+	added, err := Add(path, map[string]Entry{
+		"Gone@Example.com": {Source: SourceBounce, Reason: "5.1.1", Added: day},
+	})
+	if err != nil || !slices.Equal(added, []string{"gone@example.com"}) {
+		t.Fatalf("Add() = %v, %v", added, err)
+	}
+	// An existing entry is kept
+	added, err = Add(path, map[string]Entry{
+		"gone@example.com": {Source: SourceManual, Added: day.Add(time.Hour)},
+		" ":                {Source: SourceManual},
+	})
+	if err != nil || len(added) != 0 {
+		t.Fatalf("Add() of a suppressed address = %v, %v", added, err)
+	}
+
+	list, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := list["gone@example.com"]; got.Source != SourceBounce || got.Reason != "5.1.1" || !got.Added.Equal(day) {
+		t.Errorf("entry = %+v", got)
+	}
+
+	removed, err := Remove(path, []string{"GONE@example.com", "other@example.com"})
+	if err != nil || !slices.Equal(removed, []string{"gone@example.com"}) {
+		t.Fatalf("Remove() = %v, %v", removed, err)
+	}
+	if list, _ := Load(path); len(list) != 0 {
+		t.Errorf("expected an empty list, got %v", list)
+	}
+}
@@ -0,0 +1,63 @@
+// Package travel estimates travel time between event locations by running
+// a user-supplied command, so any routing service can be plugged in
+// without goog depending on it.
+package travel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/shell"
+)
+
+// DefaultTimeout bounds how long an estimate may take.
+const DefaultTimeout = 10 * time.Second
+
+// CommandEstimator runs a shell command to estimate travel time. The
+// command receives the locations in GOOG_TRAVEL_FROM and GOOG_TRAVEL_TO
+// and prints the travel time, either as a duration such as "25m" or as a
+// number of minutes.
+type CommandEstimator struct {
+	// Command is the shell command to run.
+	Command string
+	// Timeout bounds each run; zero means DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Compile-time interface compliance check.
+var _ calendar.TravelEstimator = (*CommandEstimator)(nil)
+
+// EstimateTravel runs the command for the journey from from to to.
+func (e *CommandEstimator) EstimateTravel(ctx context.Context, from, to string) (time.Duration, error) {
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := shell.Command(ctx, e.Command)
+	c.Env = append(os.Environ(), "GOOG_TRAVEL_FROM="+from, "GOOG_TRAVEL_TO="+to)
+	out, err := shell.Output(c, "travel command")
+	if err != nil {
+		return 0, err
+	}
+	return ParseEstimate(string(out))
+}
+
+// ParseEstimate parses a travel time printed by an estimator command.
+func ParseEstimate(output string) (time.Duration, error) {
+	value := strings.TrimSpace(output)
+	if minutes, err := strconv.ParseFloat(value, 64); err == nil && minutes >= 0 {
+		return time.Duration(minutes * float64(time.Minute)), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("travel command printed %q, want minutes or a duration such as 25m", value)
+}
@@ -0,0 +1,51 @@
+package travel
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{output: "25\n", want: 25 * time.Minute},
+		{output: "7.5", want: 7*time.Minute + 30*time.Second},
+		{output: "1h10m", want: 70 * time.Minute},
+		{output: "soon", wantErr: true},
+		{output: "-5", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEstimate(tt.output)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseEstimate(%q) = %v, %v; want %v, error %v", tt.output, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCommandEstimator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	ctx := context.Background()
+
+	e := &CommandEstimator{Command: `test "$GOOG_TRAVEL_FROM" = "Office" && test "$GOOG_TRAVEL_TO" = "Cafe Luna" && echo 25m`}
+	got, err := e.EstimateTravel(ctx, "Office", "Cafe Luna")
+	if err != nil || got != 25*time.Minute {
+		t.Errorf("EstimateTravel = %v, %v; want 25m", got, err)
+	}
+
+	failing := &CommandEstimator{Command: "echo no route >&2; exit 3"}
+	if _, err := failing.EstimateTravel(ctx, "a", "b"); err == nil {
+		t.Error("expected an error from a failing command")
+	}
+
+	slow := &CommandEstimator{Command: "sleep 5", Timeout: 50 * time.Millisecond}
+	if _, err := slow.EstimateTravel(ctx, "a", "b"); err == nil {
+		t.Error("expected a timeout error")
+	}
+}
@@ -113,17 +113,65 @@ func (s *Service) Add(ctx context.Context, alias string, scopes []string) (*acco
 	return acc, nil
 }
 
+// RemovalReport describes what RemoveAccount cleaned up.
+type RemovalReport struct {
+	// Alias and Email identify the removed account.
+	Alias string
+	Email string
+
+	// Revoked is set when Google revoked the account's token.
+	Revoked bool
+	// RevokeErr is why the token was not revoked, when revocation was
+	// requested and failed. auth.ErrTokenAlreadyInvalid means there was
+	// nothing left to revoke.
+	RevokeErr error
+
+	// DeletedKeys are the keyring entries deleted for the account.
+	DeletedKeys []string
+
+	// UsageForgotten is set when the account's last-used time was removed.
+	UsageForgotten bool
+
+	// NewDefault is the default account after the removal, when the
+	// removed account was the default.
+	NewDefault string
+	// WasDefault is set when the removed account was the default.
+	WasDefault bool
+}
+
 // Remove removes an account and its tokens.
 func (s *Service) Remove(alias string) error {
+	_, err := s.RemoveAccount(context.Background(), alias, false)
+	return err
+}
+
+// RemoveAccount removes an account from the config and deletes every
+// keyring entry stored for it. With revoke, the account's token is first
+// revoked at Google; a failed revocation is recorded in the report and
+// does not stop the local cleanup.
+func (s *Service) RemoveAccount(ctx context.Context, alias string, revoke bool) (*RemovalReport, error) {
 	// Check if account exists
-	_, err := s.cfg.GetAccount(alias)
+	accCfg, err := s.cfg.GetAccount(alias)
 	if err != nil {
-		return account.ErrAccountNotFound
+		return nil, account.ErrAccountNotFound
 	}
+	report := &RemovalReport{Alias: alias, Email: accCfg.Email}
 
-	// Delete tokens from keyring
-	if err := s.tokens.DeleteToken(alias); err != nil {
-		return fmt.Errorf("failed to delete tokens: %w", err)
+	// Revoke before the token is deleted
+	if revoke {
+		token, err := s.tokens.LoadToken(alias)
+		if err == nil {
+			err = auth.RevokeToken(ctx, token)
+		}
+		report.Revoked = err == nil
+		report.RevokeErr = err
+	}
+
+	// Delete every keyring entry for the account
+	deleted, err := s.tokens.DeleteAll(alias)
+	report.DeletedKeys = deleted
+	if err != nil {
+		return report, fmt.Errorf("failed to delete tokens: %w", err)
 	}
 
 	// Remove from config
@@ -131,6 +179,7 @@ func (s *Service) Remove(alias string) error {
 
 	// If this was the default account, clear it
 	if s.cfg.DefaultAccount == alias {
+		report.WasDefault = true
 		s.cfg.DefaultAccount = ""
 		// Set a new default if accounts remain (use sorted order for deterministic behavior)
 		if len(s.cfg.Accounts) > 0 {
@@ -141,14 +190,18 @@ func (s *Service) Remove(alias string) error {
 			sort.Strings(aliases)
 			s.cfg.DefaultAccount = aliases[0]
 		}
+		report.NewDefault = s.cfg.DefaultAccount
 	}
 
 	// Save config
 	if err := s.cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+		return report, fmt.Errorf("failed to save config: %w", err)
 	}
 
-	return nil
+	// The last-used time is informational, so failing to forget it is not an error
+	report.UsageForgotten, _ = config.ForgetAccountUsage(alias)
+
+	return report, nil
 }
 
 // List returns all configured accounts in sorted order by alias.
@@ -160,13 +213,21 @@ func (s *Service) List() ([]*account.Account, error) {
 	}
 	sort.Strings(aliases)
 
+	// Usage is informational, so a missing usage file is not an error
+	lastUsed, _ := config.LoadLastUsed()
+
 	accounts := make([]*account.Account, 0, len(s.cfg.Accounts))
 	for _, alias := range aliases {
 		accCfg := s.cfg.Accounts[alias]
 		acc := account.NewAccount(alias, accCfg.Email)
 		acc.Scopes = accCfg.Scopes
 		acc.Added = accCfg.AddedAt
+		acc.LastUsed = lastUsed[alias]
 		acc.IsDefault = s.cfg.DefaultAccount == alias
+		acc.ReadOnly = accCfg.ReadOnly
+		acc.Commands = accCfg.Commands
+		acc.Endpoints = accCfg.Endpoints()
+		acc.Metadata = accCfg.Metadata
 		accounts = append(accounts, acc)
 	}
 
@@ -310,6 +371,10 @@ func (s *Service) ResolveAccount(flagValue string) (*account.Account, error) {
 	acc.Scopes = accCfg.Scopes
 	acc.Added = accCfg.AddedAt
 	acc.IsDefault = s.cfg.DefaultAccount == alias
+	acc.ReadOnly = accCfg.ReadOnly
+	acc.Commands = accCfg.Commands
+	acc.Endpoints = accCfg.Endpoints()
+	acc.Metadata = accCfg.Metadata
 
 	return acc, nil
 }
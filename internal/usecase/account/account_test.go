@@ -4,11 +4,15 @@ package account
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/auth"
 	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
 	"golang.org/x/oauth2"
 )
@@ -191,6 +195,94 @@ func TestAccountService_List(t *testing.T) {
 	})
 }
 
+func TestAccountService_RemoveAccount(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		revoked = append(revoked, r.FormValue("token"))
+		if r.FormValue("token") == "stale-refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_token"}`))
+		}
+	}))
+	defer server.Close()
+	origURL := auth.RevokeURL
+	auth.RevokeURL = server.URL
+	defer func() { auth.RevokeURL = origURL }()
+
+	setup := func(t *testing.T, refreshToken string) (*Service, *mockStore) {
+		t.Helper()
+		store := newMockStore()
+		authFlow := &mockAuthFlow{email: "work@example.com", token: &oauth2.Token{AccessToken: "access", RefreshToken: refreshToken}}
+		svc := NewService(createTestConfig(t), store, authFlow)
+		if _, err := svc.Add(context.Background(), "work", nil); err != nil {
+			t.Fatal(err)
+		}
+		authFlow.email = "personal@example.com"
+		if _, err := svc.Add(context.Background(), "personal", nil); err != nil {
+			t.Fatal(err)
+		}
+		_ = store.Set("work", "imap_password", []byte("secret"))
+		revoked = nil
+		return svc, store
+	}
+
+	t.Run("revokes and cleans up", func(t *testing.T) {
+		svc, store := setup(t, "refresh")
+		if err := config.MarkAccountUsed("work", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := svc.RemoveAccount(context.Background(), "work", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !report.Revoked || len(revoked) != 1 || revoked[0] != "refresh" {
+			t.Errorf("Revoked = %v, revoked %v, want the refresh token revoked", report.Revoked, revoked)
+		}
+		if len(report.DeletedKeys) != 3 {
+			t.Errorf("DeletedKeys = %v, want 3 entries", report.DeletedKeys)
+		}
+		if keys, _ := store.List("work"); len(keys) != 0 {
+			t.Errorf("keyring entries left: %v", keys)
+		}
+		if report.Email != "work@example.com" || !report.WasDefault || report.NewDefault != "personal" {
+			t.Errorf("report = %+v", report)
+		}
+		if !report.UsageForgotten {
+			t.Error("expected the last-used time to be forgotten")
+		}
+	})
+
+	t.Run("cleans up when the token is already invalid", func(t *testing.T) {
+		svc, store := setup(t, "stale-refresh")
+		report, err := svc.RemoveAccount(context.Background(), "work", true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Revoked || !errors.Is(report.RevokeErr, auth.ErrTokenAlreadyInvalid) {
+			t.Errorf("Revoked = %v, RevokeErr = %v", report.Revoked, report.RevokeErr)
+		}
+		if keys, _ := store.List("work"); len(keys) != 0 {
+			t.Errorf("keyring entries left: %v", keys)
+		}
+	})
+
+	t.Run("skips revocation", func(t *testing.T) {
+		svc, _ := setup(t, "refresh")
+		report, err := svc.RemoveAccount(context.Background(), "personal", false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Revoked || report.RevokeErr != nil || len(revoked) != 0 {
+			t.Errorf("nothing should be revoked, got %+v and %v", report, revoked)
+		}
+		if report.WasDefault {
+			t.Error("personal was not the default")
+		}
+	})
+}
+
 func TestAccountService_Switch(t *testing.T) {
 	store := newMockStore()
 	cfg := createTestConfig(t)
@@ -718,6 +810,35 @@ func TestAccountService_List_WithDefaults(t *testing.T) {
 	}
 }
 
+func TestAccountService_List_LastUsed(t *testing.T) {
+	t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	cfg := createTestConfig(t)
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com"}
+	cfg.Accounts["personal"] = config.AccountConfig{Email: "personal@example.com"}
+	used := time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC)
+	if err := config.MarkAccountUsed("work", used); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(cfg, newMockStore(), &mockAuthFlow{})
+	accounts, err := svc.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, acc := range accounts {
+		switch acc.Alias {
+		case "work":
+			if !acc.LastUsed.Equal(used) {
+				t.Errorf("work LastUsed = %v, want %v", acc.LastUsed, used)
+			}
+		case "personal":
+			if !acc.LastUsed.IsZero() {
+				t.Errorf("personal LastUsed = %v, want zero", acc.LastUsed)
+			}
+		}
+	}
+}
+
 // mockStoreWithErrors implements Store with configurable errors.
 type mockStoreWithErrors struct {
 	*mockStore
@@ -830,3 +951,65 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestAccountService_ResolveAccount_ReadOnly(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com", ReadOnly: true}
+	cfg.Accounts["personal"] = config.AccountConfig{Email: "personal@example.com"}
+	svc := NewService(cfg, newMockStore(), nil)
+
+	acc, err := svc.ResolveAccount("work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !acc.ReadOnly {
+		t.Error("expected work to be read-only")
+	}
+
+	accounts, err := svc.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, a := range accounts {
+		if a.ReadOnly != (a.Alias == "work") {
+			t.Errorf("%s: ReadOnly = %v", a.Alias, a.ReadOnly)
+		}
+	}
+}
+
+func TestAccountService_ResolveAccount_Endpoints(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com", GmailEndpoint: "http://localhost:8080/"}
+	cfg.Accounts["personal"] = config.AccountConfig{Email: "personal@example.com"}
+	svc := NewService(cfg, newMockStore(), nil)
+
+	acc, err := svc.ResolveAccount("work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Endpoints["gmail"] != "http://localhost:8080/" {
+		t.Errorf("Endpoints = %v", acc.Endpoints)
+	}
+
+	acc, err = svc.ResolveAccount("personal")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(acc.Endpoints) != 0 {
+		t.Errorf("expected no endpoints, got %v", acc.Endpoints)
+	}
+}
+
+func TestAccountService_ResolveAccount_Metadata(t *testing.T) {
+	cfg := createTestConfig(t)
+	cfg.Accounts["work"] = config.AccountConfig{Email: "work@example.com", Metadata: map[string]string{"role": "work"}}
+	svc := NewService(cfg, newMockStore(), nil)
+
+	acc, err := svc.ResolveAccount("work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if acc.Metadata["role"] != "work" {
+		t.Errorf("Metadata = %v", acc.Metadata)
+	}
+}
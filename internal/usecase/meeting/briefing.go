@@ -0,0 +1,247 @@
+// Package meeting provides the use case that prepares meeting briefings by
+// joining calendar events with related mail.
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+const (
+	// DefaultLookback is how far back mail is searched by default.
+	DefaultLookback = 30 * 24 * time.Hour
+
+	// DefaultMaxThreads is the default number of threads in a briefing.
+	DefaultMaxThreads = 10
+
+	// maxQueryAttendees caps the addresses put in the mail search, which
+	// Gmail limits in length.
+	maxQueryAttendees = 25
+
+	// messagesPerThread is how many messages are fetched per wanted thread,
+	// since a busy thread returns several matching messages.
+	messagesPerThread = 5
+)
+
+// ErrNothingToSearch is returned when an event has neither other attendees
+// nor a title to search mail for.
+var ErrNothingToSearch = errors.New("event has no other attendees and no title to search mail for")
+
+// Options configures a briefing.
+type Options struct {
+	// CalendarID is the calendar holding the event.
+	CalendarID string
+	// Lookback is how far back mail is searched. Zero means DefaultLookback.
+	Lookback time.Duration
+	// MaxThreads caps the threads in the briefing. Zero means
+	// DefaultMaxThreads.
+	MaxThreads int
+	// Self is the account's address, left out of the attendees searched.
+	Self string
+}
+
+// Briefing is an event with the mail threads related to it.
+type Briefing struct {
+	// Event is the meeting.
+	Event *calendar.Event
+	// Attendees are the addresses mail was searched for.
+	Attendees []string
+	// OmittedAttendees counts attendees left out of the search to keep the
+	// query short.
+	OmittedAttendees int
+	// Since is the start of the searched period.
+	Since time.Time
+	// Query is the Gmail search that was run.
+	Query string
+	// Threads are the related threads, most recent first.
+	Threads []*ThreadSummary
+}
+
+// ThreadSummary describes a mail thread related to a meeting.
+type ThreadSummary struct {
+	// ThreadID identifies the thread.
+	ThreadID string
+	// Subject is the subject of the earliest matching message.
+	Subject string
+	// Latest is the date of the most recent matching message.
+	Latest time.Time
+	// Messages counts the matching messages in the thread.
+	Messages int
+	// Attendees are the meeting attendees who wrote or received the
+	// messages.
+	Attendees []string
+	// Snippet is the snippet of the most recent matching message.
+	Snippet string
+}
+
+// Service prepares meeting briefings.
+type Service struct {
+	events   EventGetter
+	messages MessageLister
+	now      func() time.Time
+}
+
+// NewService creates a new meeting service.
+func NewService(events EventGetter, messages MessageLister) *Service {
+	return &Service{events: events, messages: messages, now: time.Now}
+}
+
+// Brief fetches the event and searches mail for threads with its attendees
+// or its title as subject within the lookback period.
+func (s *Service) Brief(ctx context.Context, eventID string, opts Options) (*Briefing, error) {
+	if opts.Lookback <= 0 {
+		opts.Lookback = DefaultLookback
+	}
+	if opts.MaxThreads <= 0 {
+		opts.MaxThreads = DefaultMaxThreads
+	}
+
+	event, err := s.events.Get(ctx, opts.CalendarID, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	attendees := searchAttendees(event, opts.Self)
+	briefing := &Briefing{
+		Event: event,
+		Since: s.now().Add(-opts.Lookback),
+	}
+	if len(attendees) > maxQueryAttendees {
+		briefing.OmittedAttendees = len(attendees) - maxQueryAttendees
+		attendees = attendees[:maxQueryAttendees]
+	}
+	briefing.Attendees = attendees
+
+	briefing.Query = SearchQuery(attendees, event.Title, briefing.Since)
+	if briefing.Query == "" {
+		return nil, ErrNothingToSearch
+	}
+
+	result, err := s.messages.List(ctx, mail.ListOptions{
+		Query:      briefing.Query,
+		MaxResults: opts.MaxThreads * messagesPerThread,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search mail: %w", err)
+	}
+	briefing.Threads = summarizeThreads(result.Items, attendees, opts.MaxThreads)
+	return briefing, nil
+}
+
+// searchAttendees returns the lower-cased addresses of the organizer and
+// attendees who are people other than self, in event order.
+func searchAttendees(event *calendar.Event, self string) []string {
+	self = strings.ToLower(self)
+	seen := map[string]bool{"": true, self: true}
+	var addrs []string
+	add := func(a *calendar.Attendee) {
+		if a == nil || a.Self || a.IsResource() {
+			return
+		}
+		addr := strings.ToLower(strings.TrimSpace(a.Email))
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	add(event.Organizer)
+	for _, a := range event.Attendees {
+		add(a)
+	}
+	return addrs
+}
+
+// SearchQuery builds the Gmail search for mail after since that involves
+// any of the addresses or has title in its subject. It returns "" when
+// there are neither addresses nor a title.
+func SearchQuery(addresses []string, title string, since time.Time) string {
+	var terms []string
+	for _, addr := range addresses {
+		terms = append(terms, "from:"+addr, "to:"+addr, "cc:"+addr)
+	}
+	if title = strings.TrimSpace(strings.ReplaceAll(title, `"`, "")); title != "" {
+		terms = append(terms, fmt.Sprintf("subject:%q", title))
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("after:%d {%s}", since.Unix(), strings.Join(terms, " "))
+}
+
+// summarizeThreads groups messages by thread and returns at most limit
+// threads, most recent first.
+func summarizeThreads(messages []*mail.Message, attendees []string, limit int) []*ThreadSummary {
+	wanted := make(map[string]bool, len(attendees))
+	for _, a := range attendees {
+		wanted[a] = true
+	}
+
+	byThread := make(map[string]*ThreadSummary)
+	involved := make(map[string]map[string]bool)
+	earliest := make(map[string]time.Time)
+	for _, msg := range messages {
+		id := msg.ThreadID
+		if id == "" {
+			id = msg.ID
+		}
+		summary, ok := byThread[id]
+		if !ok {
+			summary = &ThreadSummary{ThreadID: id}
+			byThread[id] = summary
+			involved[id] = make(map[string]bool)
+		}
+		summary.Messages++
+		if !ok || msg.Date.After(summary.Latest) {
+			summary.Latest = msg.Date
+			summary.Snippet = msg.Snippet
+		}
+		if first, seen := earliest[id]; !seen || msg.Date.Before(first) {
+			earliest[id] = msg.Date
+			summary.Subject = msg.Subject
+		}
+
+		for _, addr := range messageAddresses(msg) {
+			if wanted[addr] && !involved[id][addr] {
+				involved[id][addr] = true
+				summary.Attendees = append(summary.Attendees, addr)
+			}
+		}
+	}
+
+	threads := make([]*ThreadSummary, 0, len(byThread))
+	for _, summary := range byThread {
+		sort.Strings(summary.Attendees)
+		threads = append(threads, summary)
+	}
+	sort.Slice(threads, func(i, j int) bool {
+		if !threads[i].Latest.Equal(threads[j].Latest) {
+			return threads[i].Latest.After(threads[j].Latest)
+		}
+		return threads[i].ThreadID < threads[j].ThreadID
+	})
+	if len(threads) > limit {
+		threads = threads[:limit]
+	}
+	return threads
+}
+
+// messageAddresses returns the lower-cased addresses a message was sent
+// from and to.
+func messageAddresses(msg *mail.Message) []string {
+	var addrs []string
+	for _, addr := range slices.Concat([]mail.Address{msg.From}, msg.To, msg.Cc) {
+		if addr.Email == "" {
+			continue
+		}
+		addrs = append(addrs, strings.ToLower(strings.TrimSpace(addr.Email)))
+	}
+	return addrs
+}
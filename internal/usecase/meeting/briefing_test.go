@@ -0,0 +1,121 @@
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+var testNow = time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+
+func newTestService(event *calendar.Event, messages *MockMessageLister) *Service {
+	s := NewService(&MockEventGetter{Event: event}, messages)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func planningEvent() *calendar.Event {
+	return &calendar.Event{
+		ID:        "ev1",
+		Title:     `Q3 "planning"`,
+		Organizer: &calendar.Attendee{Email: "Lead@Example.com"},
+		Attendees: []*calendar.Attendee{
+			{Email: "me@example.com", Self: true},
+			{Email: "lead@example.com"},
+			{Email: "ana@example.com"},
+			{Email: "c_123@resource.calendar.google.com"},
+		},
+	}
+}
+
+func TestServiceBrief(t *testing.T) {
+	messages := &MockMessageLister{Messages: []*mail.Message{
+		{ID: "m1", ThreadID: "t1", From: mail.Address{Name: "Ana", Email: "ana@example.com"}, To: []mail.Address{{Email: "me@example.com"}}, Subject: "Q3 planning", Date: testNow.Add(-48 * time.Hour), Snippet: "first"},
+		{ID: "m2", ThreadID: "t1", From: mail.Address{Email: "me@example.com"}, To: []mail.Address{{Email: "ana@example.com"}}, Cc: []mail.Address{{Email: "LEAD@example.com"}}, Subject: "Re: Q3 planning", Date: testNow.Add(-24 * time.Hour), Snippet: "latest"},
+		{ID: "m3", ThreadID: "t2", From: mail.Address{Email: "lead@example.com"}, Subject: "Budget", Date: testNow.Add(-72 * time.Hour), Snippet: "budget"},
+	}}
+	s := newTestService(planningEvent(), messages)
+
+	b, err := s.Brief(context.Background(), "ev1", Options{CalendarID: "primary", Self: "me@example.com", MaxThreads: 5, Lookback: 7 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Brief failed: %v", err)
+	}
+
+	if want := []string{"lead@example.com", "ana@example.com"}; !slices.Equal(b.Attendees, want) {
+		t.Errorf("Attendees = %v, want %v", b.Attendees, want)
+	}
+	since := testNow.Add(-7 * 24 * time.Hour)
+	wantQuery := fmt.Sprintf(`after:%d {from:lead@example.com to:lead@example.com cc:lead@example.com from:ana@example.com to:ana@example.com cc:ana@example.com subject:"Q3 planning"}`, since.Unix())
+	if b.Query != wantQuery || messages.Opts.Query != wantQuery {
+		t.Errorf("Query = %q, want %q", b.Query, wantQuery)
+	}
+	if messages.Opts.MaxResults != 5*messagesPerThread {
+		t.Errorf("MaxResults = %d", messages.Opts.MaxResults)
+	}
+
+	if len(b.Threads) != 2 {
+		t.Fatalf("expected 2 threads, got %d", len(b.Threads))
+	}
+	first := b.Threads[0]
+	if first.ThreadID != "t1" || first.Messages != 2 || first.Subject != "Q3 planning" || first.Snippet != "latest" {
+		t.Errorf("unexpected first thread: %+v", first)
+	}
+	if want := []string{"ana@example.com", "lead@example.com"}; !slices.Equal(first.Attendees, want) {
+		t.Errorf("first thread attendees = %v, want %v", first.Attendees, want)
+	}
+	if b.Threads[1].ThreadID != "t2" {
+		t.Errorf("expected t2 second, got %+v", b.Threads[1])
+	}
+}
+
+func TestServiceBrief_LimitsThreadsAndAttendees(t *testing.T) {
+	event := &calendar.Event{ID: "ev1", Title: "All hands"}
+	for i := 0; i < maxQueryAttendees+3; i++ {
+		event.Attendees = append(event.Attendees, &calendar.Attendee{Email: fmt.Sprintf("p%d@example.com", i)})
+	}
+	var msgs []*mail.Message
+	for i := 0; i < 4; i++ {
+		msgs = append(msgs, &mail.Message{ID: fmt.Sprint(i), ThreadID: fmt.Sprintf("t%d", i), Date: testNow.Add(-time.Duration(i) * time.Hour)})
+	}
+	s := newTestService(event, &MockMessageLister{Messages: msgs})
+
+	b, err := s.Brief(context.Background(), "ev1", Options{MaxThreads: 2})
+	if err != nil {
+		t.Fatalf("Brief failed: %v", err)
+	}
+	if len(b.Attendees) != maxQueryAttendees || b.OmittedAttendees != 3 {
+		t.Errorf("attendees = %d, omitted = %d", len(b.Attendees), b.OmittedAttendees)
+	}
+	if len(b.Threads) != 2 || b.Threads[0].ThreadID != "t0" {
+		t.Errorf("unexpected threads: %+v", b.Threads)
+	}
+	if !b.Since.Equal(testNow.Add(-DefaultLookback)) {
+		t.Errorf("Since = %v", b.Since)
+	}
+}
+
+func TestServiceBrief_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	s := NewService(&MockEventGetter{Err: errors.New("not found")}, &MockMessageLister{})
+	if _, err := s.Brief(ctx, "ev1", Options{}); err == nil || !strings.Contains(err.Error(), "failed to get event") {
+		t.Errorf("expected an event error, got %v", err)
+	}
+
+	lonely := &calendar.Event{ID: "ev1", Attendees: []*calendar.Attendee{{Email: "me@example.com", Self: true}}}
+	if _, err := newTestService(lonely, &MockMessageLister{}).Brief(ctx, "ev1", Options{}); !errors.Is(err, ErrNothingToSearch) {
+		t.Errorf("expected ErrNothingToSearch, got %v", err)
+	}
+
+	failing := &MockMessageLister{Err: errors.New("quota")}
+	if _, err := newTestService(planningEvent(), failing).Brief(ctx, "ev1", Options{}); err == nil || !strings.Contains(err.Error(), "failed to search mail") {
+		t.Errorf("expected a search error, got %v", err)
+	}
+}
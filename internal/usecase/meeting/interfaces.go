@@ -0,0 +1,22 @@
+// Package meeting provides the use case that prepares meeting briefings by
+// joining calendar events with related mail.
+package meeting
+
+import (
+	"context"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// EventGetter retrieves calendar events.
+type EventGetter interface {
+	// Get retrieves a single event by ID.
+	Get(ctx context.Context, calendarID, eventID string) (*calendar.Event, error)
+}
+
+// MessageLister searches mail.
+type MessageLister interface {
+	// List retrieves the messages matching the given options.
+	List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error)
+}
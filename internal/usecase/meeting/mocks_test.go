@@ -0,0 +1,41 @@
+// Package meeting provides the use case that prepares meeting briefings by
+// joining calendar events with related mail.
+package meeting
+
+import (
+	"context"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// MockEventGetter is a mock implementation of EventGetter for testing.
+type MockEventGetter struct {
+	Event *calendar.Event
+	Err   error
+}
+
+// Get returns the mock event or error.
+func (m *MockEventGetter) Get(ctx context.Context, calendarID, eventID string) (*calendar.Event, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Event, nil
+}
+
+// MockMessageLister is a mock implementation of MessageLister for testing.
+type MockMessageLister struct {
+	Messages []*mail.Message
+	Err      error
+	// Opts records the options of the last List call.
+	Opts mail.ListOptions
+}
+
+// List records the options and returns the mock messages or error.
+func (m *MockMessageLister) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Opts = opts
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return &mail.ListResult[*mail.Message]{Items: m.Messages}, nil
+}
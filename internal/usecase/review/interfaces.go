@@ -0,0 +1,44 @@
+// Package review provides the use case that builds a weekly review of
+// mail, meetings and task deadlines.
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// MessageLister searches mail.
+type MessageLister interface {
+	// List retrieves the messages matching the given options.
+	List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error)
+}
+
+// ThreadReader lists and retrieves mail threads.
+type ThreadReader interface {
+	// List retrieves the threads matching the given options.
+	List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Thread], error)
+	// Get retrieves a thread with its messages.
+	Get(ctx context.Context, id string) (*mail.Thread, error)
+}
+
+// EventLister lists calendar events.
+type EventLister interface {
+	// List retrieves the events of a calendar between timeMin and timeMax.
+	List(ctx context.Context, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error)
+}
+
+// TaskListLister lists task lists.
+type TaskListLister interface {
+	// List retrieves all task lists.
+	List(ctx context.Context) ([]*tasks.TaskList, error)
+}
+
+// TaskLister lists the tasks of a task list.
+type TaskLister interface {
+	// List retrieves the tasks of a list matching the given options.
+	List(ctx context.Context, taskListID string, opts tasks.ListOptions) (*tasks.ListResult[*tasks.Task], error)
+}
@@ -0,0 +1,150 @@
+// Package review provides the use case that builds a weekly review of
+// mail, meetings and task deadlines.
+package review
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// MockMessageLister is a mock implementation of MessageLister for testing.
+// It returns Counts[q] message IDs, Page at a time, for the first entry of
+// Counts whose key q is contained in the query.
+type MockMessageLister struct {
+	Counts map[string]int
+	Page   int
+	Err    error
+	// Calls counts the List calls.
+	Calls int
+}
+
+// List returns the next page of mock message IDs or the error.
+func (m *MockMessageLister) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Message], error) {
+	m.Calls++
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	total := 0
+	for q, n := range m.Counts {
+		if strings.Contains(opts.Query, q) {
+			total = n
+			break
+		}
+	}
+	page := m.Page
+	if page <= 0 {
+		page = opts.MaxResults
+	}
+	start := len(opts.PageToken)
+	end := min(start+page, total)
+	result := &mail.ListResult[*mail.Message]{}
+	for i := start; i < end; i++ {
+		result.Items = append(result.Items, &mail.Message{ID: "m"})
+	}
+	if end < total {
+		result.NextPageToken = strings.Repeat("x", end)
+	}
+	return result, nil
+}
+
+// MockThreadReader is a mock implementation of ThreadReader for testing.
+type MockThreadReader struct {
+	Threads []*mail.Thread
+	ListErr error
+	GetErr  error
+	// Opts records the options of the last List call, and Got the IDs
+	// passed to Get.
+	Opts mail.ListOptions
+	Got  []string
+}
+
+// List records the options and returns the mock threads without their
+// messages, or the error.
+func (m *MockThreadReader) List(ctx context.Context, opts mail.ListOptions) (*mail.ListResult[*mail.Thread], error) {
+	m.Opts = opts
+	if m.ListErr != nil {
+		return nil, m.ListErr
+	}
+	result := &mail.ListResult[*mail.Thread]{}
+	for _, t := range m.Threads {
+		result.Items = append(result.Items, &mail.Thread{ID: t.ID})
+	}
+	return result, nil
+}
+
+// Get returns the mock thread with the ID, or the error.
+func (m *MockThreadReader) Get(ctx context.Context, id string) (*mail.Thread, error) {
+	m.Got = append(m.Got, id)
+	if m.GetErr != nil {
+		return nil, m.GetErr
+	}
+	for _, t := range m.Threads {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, mail.ErrThreadNotFound
+}
+
+// MockEventLister is a mock implementation of EventLister for testing.
+type MockEventLister struct {
+	Events []*calendar.Event
+	Err    error
+	// TimeMin and TimeMax record the range of the last List call.
+	TimeMin, TimeMax time.Time
+}
+
+// List records the range and returns the mock events or error.
+func (m *MockEventLister) List(ctx context.Context, calendarID string, timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	m.TimeMin, m.TimeMax = timeMin, timeMax
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Events, nil
+}
+
+// MockTaskListLister is a mock implementation of TaskListLister for testing.
+type MockTaskListLister struct {
+	Lists []*tasks.TaskList
+	Err   error
+}
+
+// List returns the mock task lists or error.
+func (m *MockTaskListLister) List(ctx context.Context) ([]*tasks.TaskList, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Lists, nil
+}
+
+// MockTaskLister is a mock implementation of TaskLister for testing. It
+// returns the tasks of each list one per page.
+type MockTaskLister struct {
+	Tasks map[string][]*tasks.Task
+	Err   error
+	// Opts records the options of the last List call.
+	Opts tasks.ListOptions
+}
+
+// List returns the next mock task of the list, or the error.
+func (m *MockTaskLister) List(ctx context.Context, taskListID string, opts tasks.ListOptions) (*tasks.ListResult[*tasks.Task], error) {
+	m.Opts = opts
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	items := m.Tasks[taskListID]
+	i := len(opts.PageToken)
+	result := &tasks.ListResult[*tasks.Task]{}
+	if i < len(items) {
+		result.Items = items[i : i+1]
+	}
+	if i+1 < len(items) {
+		result.NextPageToken = strings.Repeat("x", i+1)
+	}
+	return result, nil
+}
@@ -0,0 +1,328 @@
+// Package review provides the use case that builds a weekly review of
+// mail, meetings and task deadlines.
+package review
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+const (
+	// DefaultPeriod is the period reviewed by default: the last week.
+	DefaultPeriod = 7 * 24 * time.Hour
+
+	// DefaultHorizon is how far ahead deadlines are looked for by default.
+	DefaultHorizon = 7 * 24 * time.Hour
+
+	// DefaultMaxThreads is the default number of threads awaiting a reply.
+	DefaultMaxThreads = 10
+
+	// MaxCount caps the sent and received counts, which take one call per
+	// countPageSize messages.
+	MaxCount = 10000
+
+	// countPageSize is the number of message IDs listed per call when
+	// counting.
+	countPageSize = 500
+
+	// threadsScannedPerWanted is how many inbox threads are read per wanted
+	// thread awaiting a reply, since most threads have been answered.
+	threadsScannedPerWanted = 5
+
+	// taskPageSize is the number of tasks listed per call.
+	taskPageSize = 100
+)
+
+// Options configures a review.
+type Options struct {
+	// CalendarID is the calendar meetings are read from.
+	CalendarID string
+	// Period is how far back the review looks. Zero means DefaultPeriod.
+	Period time.Duration
+	// Horizon is how far ahead deadlines are looked for. Zero means
+	// DefaultHorizon.
+	Horizon time.Duration
+	// MaxThreads caps the threads awaiting a reply. Zero means
+	// DefaultMaxThreads.
+	MaxThreads int
+	// Self is the account's address, which marks the mail sent by you.
+	Self string
+}
+
+// Report is a review of a period.
+type Report struct {
+	// Since and Until bound the reviewed period.
+	Since time.Time
+	Until time.Time
+	// Received and Sent count the messages of the period, up to MaxCount.
+	Received int
+	Sent     int
+	// Awaiting are the inbox threads whose last message is not from you,
+	// most recent first.
+	Awaiting []*AwaitingThread
+	// Meetings are the meetings attended in the period, in start order.
+	Meetings []*calendar.Event
+	// MeetingTime is the total length of the meetings.
+	MeetingTime time.Duration
+	// DeadlinesUntil is the end of the day up to which deadlines are
+	// listed.
+	DeadlinesUntil time.Time
+	// Deadlines are the open tasks due from today to DeadlinesUntil, and
+	// Overdue those due before today, both by due date.
+	Deadlines []*Deadline
+	Overdue   []*Deadline
+}
+
+// AwaitingThread is a thread whose last message is not from you.
+type AwaitingThread struct {
+	// ThreadID identifies the thread.
+	ThreadID string
+	// Subject is the subject of the thread's first message.
+	Subject string
+	// From is the sender of the last message.
+	From mail.Address
+	// Latest is the date of the last message.
+	Latest time.Time
+	// Messages counts the messages in the thread.
+	Messages int
+	// Snippet is the snippet of the last message.
+	Snippet string
+}
+
+// Deadline is an open task with a due date.
+type Deadline struct {
+	// Task is the task.
+	Task *tasks.Task
+	// List is the title of the task's list.
+	List string
+	// Due is the due date, at midnight local time.
+	Due time.Time
+}
+
+// Service builds reviews.
+type Service struct {
+	messages  MessageLister
+	threads   ThreadReader
+	events    EventLister
+	taskLists TaskListLister
+	tasks     TaskLister
+	now       func() time.Time
+}
+
+// NewService creates a new review service.
+func NewService(messages MessageLister, threads ThreadReader, events EventLister, taskLists TaskListLister, tasks TaskLister) *Service {
+	return &Service{
+		messages:  messages,
+		threads:   threads,
+		events:    events,
+		taskLists: taskLists,
+		tasks:     tasks,
+		now:       time.Now,
+	}
+}
+
+// Review counts the mail sent and received in the period, finds the
+// inbox threads awaiting your reply, lists the meetings attended and the
+// open tasks due soon or overdue.
+func (s *Service) Review(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Period <= 0 {
+		opts.Period = DefaultPeriod
+	}
+	if opts.Horizon <= 0 {
+		opts.Horizon = DefaultHorizon
+	}
+	if opts.MaxThreads <= 0 {
+		opts.MaxThreads = DefaultMaxThreads
+	}
+
+	now := s.now()
+	report := &Report{Since: now.Add(-opts.Period), Until: now}
+	after := report.Since.Unix()
+
+	var err error
+	if report.Received, err = s.count(ctx, fmt.Sprintf("after:%d -from:me", after)); err != nil {
+		return nil, fmt.Errorf("failed to count received mail: %w", err)
+	}
+	if report.Sent, err = s.count(ctx, fmt.Sprintf("after:%d in:sent", after)); err != nil {
+		return nil, fmt.Errorf("failed to count sent mail: %w", err)
+	}
+	if report.Awaiting, err = s.awaiting(ctx, AwaitingQuery(report.Since), opts.Self, opts.MaxThreads); err != nil {
+		return nil, err
+	}
+
+	events, err := s.events.List(ctx, opts.CalendarID, report.Since, report.Until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	for _, e := range events {
+		if e.IsMeeting() && e.Start.Before(report.Until) {
+			report.Meetings = append(report.Meetings, e)
+			report.MeetingTime += e.Duration()
+		}
+	}
+	sort.SliceStable(report.Meetings, func(i, j int) bool {
+		return report.Meetings[i].Start.Before(report.Meetings[j].Start)
+	})
+
+	if err := s.deadlines(ctx, report, opts.Horizon); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// AwaitingQuery is the Gmail search for the inbox threads active since
+// since, leaving out promotions and social updates.
+func AwaitingQuery(since time.Time) string {
+	return fmt.Sprintf("in:inbox after:%d -category:promotions -category:social", since.Unix())
+}
+
+// count returns the number of messages matching query, up to MaxCount.
+func (s *Service) count(ctx context.Context, query string) (int, error) {
+	opts := mail.ListOptions{Query: query, MaxResults: countPageSize, IDsOnly: true}
+	n := 0
+	for {
+		result, err := s.messages.List(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		n += len(result.Items)
+		if n >= MaxCount {
+			return MaxCount, nil
+		}
+		if result.NextPageToken == "" {
+			return n, nil
+		}
+		opts.PageToken = result.NextPageToken
+	}
+}
+
+// awaiting reads the threads matching query, most recent first, and
+// returns at most limit of them whose last message is not from self.
+func (s *Service) awaiting(ctx context.Context, query, self string, limit int) ([]*AwaitingThread, error) {
+	result, err := s.threads.List(ctx, mail.ListOptions{
+		Query:      query,
+		MaxResults: limit * threadsScannedPerWanted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list threads: %w", err)
+	}
+
+	var out []*AwaitingThread
+	for _, item := range result.Items {
+		if len(out) == limit {
+			break
+		}
+		thread, err := s.threads.Get(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get thread %s: %w", item.ID, err)
+		}
+		if waiting := awaitingThread(thread, self); waiting != nil {
+			out = append(out, waiting)
+		}
+	}
+	return out, nil
+}
+
+// awaitingThread returns a summary of thread when its last message, drafts
+// aside, is not from self, or else nil.
+func awaitingThread(thread *mail.Thread, self string) *AwaitingThread {
+	var first, last *mail.Message
+	messages := 0
+	for _, msg := range thread.Messages {
+		if msg == nil || slices.Contains(msg.Labels, "DRAFT") {
+			continue
+		}
+		messages++
+		if first == nil || msg.Date.Before(first.Date) {
+			first = msg
+		}
+		if last == nil || !msg.Date.Before(last.Date) {
+			last = msg
+		}
+	}
+	if last == nil || strings.EqualFold(strings.TrimSpace(last.From.Email), strings.TrimSpace(self)) {
+		return nil
+	}
+	return &AwaitingThread{
+		ThreadID: thread.ID,
+		Subject:  first.Subject,
+		From:     last.From,
+		Latest:   last.Date,
+		Messages: messages,
+		Snippet:  last.Snippet,
+	}
+}
+
+// deadlines fills in the report's deadlines: the open tasks of every list
+// due up to the end of the day horizon from now, and those overdue.
+func (s *Service) deadlines(ctx context.Context, report *Report, horizon time.Duration) error {
+	now := report.Until
+	today := startOfDay(now)
+	report.DeadlinesUntil = startOfDay(now.Add(horizon)).AddDate(0, 0, 1).Add(-time.Nanosecond)
+	// Due dates are midnight UTC, so the search reaches a day further to
+	// cover time zones west of UTC.
+	dueMax := report.DeadlinesUntil.AddDate(0, 0, 1)
+
+	lists, err := s.taskLists.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list task lists: %w", err)
+	}
+	for _, list := range lists {
+		opts := tasks.ListOptions{MaxResults: taskPageSize, DueMax: &dueMax}
+		for {
+			result, err := s.tasks.List(ctx, list.ID, opts)
+			if err != nil {
+				return fmt.Errorf("failed to list tasks of %s: %w", list.Title, err)
+			}
+			for _, task := range result.Items {
+				if task.Due == nil || task.IsCompleted() || task.Deleted {
+					continue
+				}
+				d := &Deadline{Task: task, List: list.Title, Due: DueDate(*task.Due, now.Location())}
+				switch {
+				case d.Due.Before(today):
+					report.Overdue = append(report.Overdue, d)
+				case !d.Due.After(report.DeadlinesUntil):
+					report.Deadlines = append(report.Deadlines, d)
+				}
+			}
+			if result.NextPageToken == "" {
+				break
+			}
+			opts.PageToken = result.NextPageToken
+		}
+	}
+	sortDeadlines(report.Deadlines)
+	sortDeadlines(report.Overdue)
+	return nil
+}
+
+// DueDate returns the day of a task due date, which Google Tasks stores
+// as midnight UTC, as midnight in loc.
+func DueDate(due time.Time, loc *time.Location) time.Time {
+	due = due.UTC()
+	return time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc)
+}
+
+// startOfDay returns midnight of t's day in t's location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// sortDeadlines sorts deadlines by due date, then title.
+func sortDeadlines(deadlines []*Deadline) {
+	sort.SliceStable(deadlines, func(i, j int) bool {
+		if !deadlines[i].Due.Equal(deadlines[j].Due) {
+			return deadlines[i].Due.Before(deadlines[j].Due)
+		}
+		return deadlines[i].Task.Title < deadlines[j].Task.Title
+	})
+}
@@ -0,0 +1,225 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+	"github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+var testNow = time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+
+// testMocks holds the mocks behind a test service.
+type testMocks struct {
+	messages  *MockMessageLister
+	threads   *MockThreadReader
+	events    *MockEventLister
+	taskLists *MockTaskListLister
+	tasks     *MockTaskLister
+}
+
+func newTestMocks() *testMocks {
+	return &testMocks{
+		messages:  &MockMessageLister{},
+		threads:   &MockThreadReader{},
+		events:    &MockEventLister{},
+		taskLists: &MockTaskListLister{},
+		tasks:     &MockTaskLister{},
+	}
+}
+
+func (m *testMocks) service() *Service {
+	s := NewService(m.messages, m.threads, m.events, m.taskLists, m.tasks)
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func day(offset int) *time.Time {
+	d := time.Date(2024, 6, 10+offset, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+func TestServiceReview(t *testing.T) {
+	m := newTestMocks()
+	m.messages.Counts = map[string]int{"-from:me": 1203, "in:sent": 42}
+	m.threads.Threads = []*mail.Thread{
+		{ID: "t1", Messages: []*mail.Message{
+			{From: mail.Address{Email: "ana@example.com"}, Subject: "Budget", Date: testNow.Add(-50 * time.Hour)},
+			{From: mail.Address{Email: "Me@Example.com"}, Subject: "Re: Budget", Date: testNow.Add(-48 * time.Hour)},
+		}},
+		{ID: "t2", Messages: []*mail.Message{
+			{From: mail.Address{Email: "me@example.com"}, Subject: "Launch", Date: testNow.Add(-30 * time.Hour)},
+			{From: mail.Address{Name: "Lea", Email: "lea@example.com"}, Subject: "Re: Launch", Date: testNow.Add(-20 * time.Hour), Snippet: "Any news?"},
+			{From: mail.Address{Email: "me@example.com"}, Labels: []string{"DRAFT"}, Date: testNow.Add(-time.Hour)},
+		}},
+	}
+	m.events.Events = []*calendar.Event{
+		{ID: "e2", Title: "Review", Start: testNow.Add(-24 * time.Hour), End: testNow.Add(-23 * time.Hour)},
+		{ID: "e1", Title: "Standup", Start: testNow.Add(-72 * time.Hour), End: testNow.Add(-71*time.Hour - 30*time.Minute)},
+		{ID: "holiday", Title: "Holiday", AllDay: true, Start: testNow.Add(-96 * time.Hour), End: testNow.Add(-72 * time.Hour)},
+		{ID: "declined", Title: "Offsite", Start: testNow.Add(-5 * time.Hour), End: testNow.Add(-4 * time.Hour),
+			Attendees: []*calendar.Attendee{{Email: "me@example.com", Self: true, ResponseStatus: calendar.ResponseDeclined}}},
+	}
+	m.taskLists.Lists = []*tasks.TaskList{{ID: "l1", Title: "Work"}, {ID: "l2", Title: "Home"}}
+	m.tasks.Tasks = map[string][]*tasks.Task{
+		"l1": {
+			{ID: "a", Title: "Ship report", Due: day(2)},
+			{ID: "b", Title: "Expenses", Due: day(-3)},
+			{ID: "c", Title: "Done already", Due: day(1), Status: tasks.StatusCompleted},
+			{ID: "d", Title: "Someday"},
+		},
+		"l2": {
+			{ID: "e", Title: "Call plumber", Due: day(0)},
+			{ID: "f", Title: "Renew passport", Due: day(30)},
+		},
+	}
+
+	r, err := m.service().Review(context.Background(), Options{CalendarID: "primary", Self: "me@example.com"})
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+
+	if !r.Since.Equal(testNow.Add(-DefaultPeriod)) || !r.Until.Equal(testNow) {
+		t.Errorf("period = %v - %v", r.Since, r.Until)
+	}
+	if r.Received != 1203 || r.Sent != 42 {
+		t.Errorf("Received, Sent = %d, %d, want 1203, 42", r.Received, r.Sent)
+	}
+	if want := AwaitingQuery(r.Since); m.threads.Opts.Query != want {
+		t.Errorf("thread query = %q, want %q", m.threads.Opts.Query, want)
+	}
+	if m.threads.Opts.MaxResults != DefaultMaxThreads*threadsScannedPerWanted {
+		t.Errorf("thread MaxResults = %d", m.threads.Opts.MaxResults)
+	}
+
+	if len(r.Awaiting) != 1 {
+		t.Fatalf("expected 1 thread awaiting a reply, got %d", len(r.Awaiting))
+	}
+	if a := r.Awaiting[0]; a.ThreadID != "t2" || a.Subject != "Launch" || a.From.Email != "lea@example.com" || a.Messages != 2 || a.Snippet != "Any news?" {
+		t.Errorf("unexpected awaiting thread: %+v", a)
+	}
+
+	if len(r.Meetings) != 2 || r.Meetings[0].ID != "e1" || r.Meetings[1].ID != "e2" {
+		t.Errorf("unexpected meetings: %v", r.Meetings)
+	}
+	if r.MeetingTime != 90*time.Minute {
+		t.Errorf("MeetingTime = %v, want 1h30m", r.MeetingTime)
+	}
+	if !m.events.TimeMin.Equal(r.Since) || !m.events.TimeMax.Equal(r.Until) {
+		t.Errorf("events listed for %v - %v", m.events.TimeMin, m.events.TimeMax)
+	}
+
+	if got := deadlineTitles(r.Deadlines); got != "Call plumber,Ship report" {
+		t.Errorf("Deadlines = %s", got)
+	}
+	if got := deadlineTitles(r.Overdue); got != "Expenses" {
+		t.Errorf("Overdue = %s", got)
+	}
+	if r.Deadlines[0].List != "Home" {
+		t.Errorf("deadline list = %q, want Home", r.Deadlines[0].List)
+	}
+	if want := time.Date(2024, 6, 17, 23, 59, 59, 999999999, time.UTC); !r.DeadlinesUntil.Equal(want) {
+		t.Errorf("DeadlinesUntil = %v, want %v", r.DeadlinesUntil, want)
+	}
+	if m.tasks.Opts.DueMax == nil || !m.tasks.Opts.DueMax.After(r.DeadlinesUntil) {
+		t.Errorf("DueMax = %v", m.tasks.Opts.DueMax)
+	}
+}
+
+func deadlineTitles(deadlines []*Deadline) string {
+	var titles []string
+	for _, d := range deadlines {
+		titles = append(titles, d.Task.Title)
+	}
+	return strings.Join(titles, ",")
+}
+
+func TestServiceReview_Options(t *testing.T) {
+	m := newTestMocks()
+	for i := 0; i < 6; i++ {
+		m.threads.Threads = append(m.threads.Threads, &mail.Thread{ID: fmt.Sprintf("t%d", i), Messages: []*mail.Message{
+			{From: mail.Address{Email: "ana@example.com"}, Date: testNow.Add(-time.Duration(i) * time.Hour)},
+		}})
+	}
+
+	r, err := m.service().Review(context.Background(), Options{Period: 30 * 24 * time.Hour, Horizon: 24 * time.Hour, MaxThreads: 2, Self: "me@example.com"})
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if !r.Since.Equal(testNow.AddDate(0, 0, -30)) {
+		t.Errorf("Since = %v", r.Since)
+	}
+	if len(r.Awaiting) != 2 || len(m.threads.Got) != 2 {
+		t.Errorf("got %d awaiting threads after %d reads, want 2 after 2", len(r.Awaiting), len(m.threads.Got))
+	}
+	if m.threads.Opts.MaxResults != 2*threadsScannedPerWanted {
+		t.Errorf("thread MaxResults = %d", m.threads.Opts.MaxResults)
+	}
+	if want := time.Date(2024, 6, 11, 23, 59, 59, 999999999, time.UTC); !r.DeadlinesUntil.Equal(want) {
+		t.Errorf("DeadlinesUntil = %v, want %v", r.DeadlinesUntil, want)
+	}
+}
+
+func TestServiceReview_CountPagesAndCap(t *testing.T) {
+	m := newTestMocks()
+	m.messages.Counts = map[string]int{"-from:me": MaxCount + 700, "in:sent": 1250}
+
+	r, err := m.service().Review(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if r.Received != MaxCount || r.Sent != 1250 {
+		t.Errorf("Received, Sent = %d, %d, want %d, 1250", r.Received, r.Sent, MaxCount)
+	}
+	// 20 pages reach the cap and 3 count the sent mail.
+	if m.messages.Calls != 23 {
+		t.Errorf("List calls = %d, want 23", m.messages.Calls)
+	}
+}
+
+func TestServiceReview_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name  string
+		setup func(*testMocks)
+		want  string
+	}{
+		{"messages", func(m *testMocks) { m.messages.Err = boom }, "failed to count received mail"},
+		{"thread list", func(m *testMocks) { m.threads.ListErr = boom }, "failed to list threads"},
+		{"thread get", func(m *testMocks) {
+			m.threads.Threads = []*mail.Thread{{ID: "t1"}}
+			m.threads.GetErr = boom
+		}, "failed to get thread t1"},
+		{"events", func(m *testMocks) { m.events.Err = boom }, "failed to list events"},
+		{"task lists", func(m *testMocks) { m.taskLists.Err = boom }, "failed to list task lists"},
+		{"tasks", func(m *testMocks) {
+			m.taskLists.Lists = []*tasks.TaskList{{ID: "l1", Title: "Work"}}
+			m.tasks.Err = boom
+		}, "failed to list tasks of Work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMocks()
+			tt.setup(m)
+			_, err := m.service().Review(context.Background(), Options{})
+			if !errors.Is(err, boom) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueDate(t *testing.T) {
+	loc := time.FixedZone("PDT", -7*3600)
+	got := DueDate(time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC), loc)
+	if want := time.Date(2024, 6, 12, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("DueDate = %v, want %v", got, want)
+	}
+}
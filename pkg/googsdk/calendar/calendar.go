@@ -0,0 +1,84 @@
+// Package calendar exposes goog's Google Calendar types for use with
+// googsdk.Client.
+//
+// The types are aliases of goog's domain types, so values returned by the
+// client can be used directly.
+package calendar
+
+import (
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/domain/calendar"
+)
+
+// Entities.
+type (
+	Event          = calendar.Event
+	Calendar       = calendar.Calendar
+	Attendee       = calendar.Attendee
+	Reminder       = calendar.Reminder
+	ConferenceData = calendar.ConferenceData
+	ACLRule        = calendar.ACLRule
+	ACLScope       = calendar.ACLScope
+	TimePeriod     = calendar.TimePeriod
+)
+
+// Requests and responses.
+type (
+	FreeBusyRequest  = calendar.FreeBusyRequest
+	FreeBusyResponse = calendar.FreeBusyResponse
+)
+
+// Repositories returned by googsdk.Client.
+type (
+	EventRepository    = calendar.EventRepository
+	CalendarRepository = calendar.CalendarRepository
+	ACLRepository      = calendar.ACLRepository
+	FreeBusyRepository = calendar.FreeBusyRepository
+)
+
+// Errors returned by the repositories.
+var (
+	ErrEventNotFound    = calendar.ErrEventNotFound
+	ErrCalendarNotFound = calendar.ErrCalendarNotFound
+	ErrACLNotFound      = calendar.ErrACLNotFound
+	ErrInvalidTimeRange = calendar.ErrInvalidTimeRange
+)
+
+// Attendee response statuses, as passed to EventRepository.RSVP.
+const (
+	ResponseNeedsAction = calendar.ResponseNeedsAction
+	ResponseDeclined    = calendar.ResponseDeclined
+	ResponseTentative   = calendar.ResponseTentative
+	ResponseAccepted    = calendar.ResponseAccepted
+)
+
+// Event statuses.
+const (
+	StatusConfirmed = calendar.StatusConfirmed
+	StatusTentative = calendar.StatusTentative
+	StatusCancelled = calendar.StatusCancelled
+)
+
+// PrimaryCalendar is the ID of the account's main calendar.
+const PrimaryCalendar = "primary"
+
+// NewEvent creates a timed event.
+func NewEvent(title string, start, end time.Time) *Event {
+	return calendar.NewEvent(title, start, end)
+}
+
+// NewAllDayEvent creates an all-day event on date.
+func NewAllDayEvent(title string, date time.Time) *Event {
+	return calendar.NewAllDayEvent(title, date)
+}
+
+// NewAttendee creates an attendee with the given email.
+func NewAttendee(email string) *Attendee {
+	return calendar.NewAttendee(email)
+}
+
+// NewFreeBusyRequest creates a free/busy query for the given calendars.
+func NewFreeBusyRequest(timeMin, timeMax time.Time, calendarIDs ...string) (*FreeBusyRequest, error) {
+	return calendar.NewFreeBusyRequest(timeMin, timeMax, calendarIDs...)
+}
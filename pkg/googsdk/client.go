@@ -0,0 +1,233 @@
+// Package googsdk lets Go programs use Gmail, Google Calendar, Tasks and
+// Contacts through goog's repositories and account handling instead of
+// shelling out to the goog CLI.
+//
+// Open uses the accounts and tokens created by "goog auth login" or
+// "goog init", refreshing tokens as needed:
+//
+//	client, err := googsdk.Open(ctx, googsdk.Options{Account: "work"})
+//	if err != nil {
+//		return err
+//	}
+//	msgs, err := client.Messages().List(ctx, mail.ListOptions{MaxResults: 10})
+//
+// Programs that manage their own OAuth tokens can use NewClient instead.
+//
+// The entity, option and repository types live in the mail, calendar,
+// tasks and contacts subpackages. This package and its subpackages are the
+// supported API: within a major version, exported names are not removed or
+// changed incompatibly, although fields may be added to structs and methods
+// to repository interfaces. Do not implement the repository interfaces
+// outside tests for that reason.
+package googsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stainedhead/go-goog-cli/internal/adapter/repository"
+	"github.com/stainedhead/go-goog-cli/internal/domain/account"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/keyring"
+	accountuc "github.com/stainedhead/go-goog-cli/internal/usecase/account"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/calendar"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/contacts"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/mail"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/tasks"
+	"golang.org/x/oauth2"
+)
+
+// ErrAccountNotFound is returned by Open when the requested account, or any
+// account when none is requested, has not been signed in with goog.
+var ErrAccountNotFound = account.ErrAccountNotFound
+
+// APIError is returned, possibly wrapped, when a Google API call fails. It
+// carries the HTTP status, Google's error reason and a remediation hint, and
+// wraps one of the errors below or a not-found error from a subpackage:
+//
+//	var apiErr *googsdk.APIError
+//	if errors.As(err, &apiErr) && apiErr.Reason == "userRateLimitExceeded" {
+//		time.Sleep(time.Minute)
+//	}
+type APIError = repository.APIError
+
+// Errors wrapped by APIError; test for them with errors.Is.
+var (
+	ErrBadRequest       = repository.ErrBadRequest
+	ErrRateLimited      = repository.ErrRateLimited
+	ErrQuotaExceeded    = repository.ErrQuotaExceeded
+	ErrTemporary        = repository.ErrTemporary
+	ErrUnauthorized     = repository.ErrUnauthorized
+	ErrPermissionDenied = repository.ErrPermissionDenied
+)
+
+// Options configures Open.
+type Options struct {
+	// Account is the goog account alias to use. When empty, the account is
+	// resolved as the CLI does: GOOG_ACCOUNT, then the default account,
+	// then the first account.
+	Account string
+}
+
+// Account describes the account a Client acts as.
+type Account struct {
+	// Alias is the goog account alias; empty for clients from NewClient.
+	Alias string
+
+	// Email is the account's email address.
+	Email string
+
+	// Scopes lists the OAuth scopes granted to the account, if known.
+	Scopes []string
+}
+
+// Client gives access to the Google APIs for one account. It is safe for
+// concurrent use.
+type Client struct {
+	account Account
+
+	gmail    *repository.GmailRepository
+	calendar *repository.GCalService
+	tasks    *repository.GTasksRepository
+	people   *repository.PeopleRepository
+}
+
+// Open creates a client for an account signed in with goog, using goog's
+// config file and keyring.
+func Open(ctx context.Context, opts Options) (*Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := keyring.SetBackend(cfg.Keyring.Backend); err != nil {
+		return nil, fmt.Errorf("invalid keyring config: %w", err)
+	}
+	store, err := keyring.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize keyring: %w", err)
+	}
+
+	svc := accountuc.NewService(cfg, store, nil)
+	acc, err := svc.ResolveAccount(opts.Account)
+	if err != nil {
+		return nil, fmt.Errorf("no account found: %w (run 'goog auth login' to authenticate)", err)
+	}
+
+	tokenSource, err := svc.GetTokenManager().GetTokenSource(ctx, acc.Alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for %s: %w (run 'goog auth login' to authenticate)", acc.Alias, err)
+	}
+
+	client, err := NewClient(ctx, tokenSource, acc.Email)
+	if err != nil {
+		return nil, err
+	}
+	client.account.Alias = acc.Alias
+	client.account.Scopes = acc.Scopes
+	return client, nil
+}
+
+// NewClient creates a client from an OAuth token source, for programs that
+// manage their own tokens. email is the account's address; it is used as
+// the sender by callers and reported by Account.
+func NewClient(ctx context.Context, tokenSource oauth2.TokenSource, email string) (*Client, error) {
+	if tokenSource == nil {
+		return nil, errors.New("token source is required")
+	}
+
+	gmailRepo, err := repository.NewGmailRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gmail client: %w", err)
+	}
+	calSvc, err := repository.NewGCalService(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Calendar client: %w", err)
+	}
+	tasksRepo, err := repository.NewGTasksRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Tasks client: %w", err)
+	}
+	peopleRepo, err := repository.NewPeopleRepository(ctx, tokenSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create People client: %w", err)
+	}
+
+	return &Client{
+		account:  Account{Email: email},
+		gmail:    gmailRepo,
+		calendar: calSvc,
+		tasks:    tasksRepo,
+		people:   peopleRepo,
+	}, nil
+}
+
+// Account returns the account the client acts as.
+func (c *Client) Account() Account {
+	return c.account
+}
+
+// Messages returns the Gmail message repository.
+func (c *Client) Messages() mail.MessageRepository {
+	return c.gmail
+}
+
+// Drafts returns the Gmail draft repository.
+func (c *Client) Drafts() mail.DraftRepository {
+	return repository.NewGmailDraftRepository(c.gmail)
+}
+
+// Threads returns the Gmail thread repository.
+func (c *Client) Threads() mail.ThreadRepository {
+	return repository.NewGmailThreadRepository(c.gmail)
+}
+
+// Labels returns the Gmail label repository.
+func (c *Client) Labels() mail.LabelRepository {
+	return repository.NewGmailLabelRepository(c.gmail)
+}
+
+// Attachments returns the Gmail attachment repository.
+func (c *Client) Attachments() mail.AttachmentRepository {
+	return repository.NewGmailAttachmentRepository(c.gmail)
+}
+
+// Events returns the Calendar event repository.
+func (c *Client) Events() calendar.EventRepository {
+	return c.calendar.Events()
+}
+
+// Calendars returns the Calendar calendar repository.
+func (c *Client) Calendars() calendar.CalendarRepository {
+	return c.calendar.Calendars()
+}
+
+// ACL returns the Calendar sharing repository.
+func (c *Client) ACL() calendar.ACLRepository {
+	return c.calendar.ACL()
+}
+
+// FreeBusy returns the Calendar free/busy repository.
+func (c *Client) FreeBusy() calendar.FreeBusyRepository {
+	return c.calendar.FreeBusy()
+}
+
+// TaskLists returns the Tasks task list repository.
+func (c *Client) TaskLists() tasks.TaskListRepository {
+	return repository.NewGTaskListRepository(c.tasks)
+}
+
+// Tasks returns the Tasks task repository.
+func (c *Client) Tasks() tasks.TaskRepository {
+	return repository.NewGTaskRepository(c.tasks)
+}
+
+// Contacts returns the People contact repository.
+func (c *Client) Contacts() contacts.ContactRepository {
+	return repository.NewPeopleContactRepository(c.people)
+}
+
+// ContactGroups returns the People contact group repository.
+func (c *Client) ContactGroups() contacts.ContactGroupRepository {
+	return repository.NewPeopleGroupRepository(c.people)
+}
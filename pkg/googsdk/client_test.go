@@ -0,0 +1,140 @@
+package googsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/internal/infrastructure/config"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/calendar"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/mail"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/tasks"
+	"golang.org/x/oauth2"
+)
+
+func staticTokenSource() oauth2.TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token", Expiry: time.Now().Add(time.Hour)})
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(context.Background(), staticTokenSource(), "me@example.com")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if got := client.Account(); got.Email != "me@example.com" || got.Alias != "" {
+		t.Errorf("Account() = %+v, want email only", got)
+	}
+
+	repos := map[string]any{
+		"Messages":      client.Messages(),
+		"Drafts":        client.Drafts(),
+		"Threads":       client.Threads(),
+		"Labels":        client.Labels(),
+		"Attachments":   client.Attachments(),
+		"Events":        client.Events(),
+		"Calendars":     client.Calendars(),
+		"ACL":           client.ACL(),
+		"FreeBusy":      client.FreeBusy(),
+		"TaskLists":     client.TaskLists(),
+		"Tasks":         client.Tasks(),
+		"Contacts":      client.Contacts(),
+		"ContactGroups": client.ContactGroups(),
+	}
+	for name, repo := range repos {
+		if repo == nil {
+			t.Errorf("%s() returned nil", name)
+		}
+	}
+}
+
+func TestNewClientRequiresTokenSource(t *testing.T) {
+	if _, err := NewClient(context.Background(), nil, "me@example.com"); err == nil {
+		t.Error("expected error for nil token source")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("no accounts", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		t.Setenv("GOOG_ACCOUNT", "")
+
+		_, err := Open(context.Background(), Options{})
+		if !errors.Is(err, ErrAccountNotFound) {
+			t.Errorf("Open() = %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("unknown alias", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Accounts["work"] = config.AccountConfig{Email: "me@example.com"}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+
+		_, err := Open(context.Background(), Options{Account: "personal"})
+		if !errors.Is(err, ErrAccountNotFound) {
+			t.Errorf("Open() = %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("invalid keyring backend", func(t *testing.T) {
+		t.Setenv("GOOG_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+		cfg := config.NewConfig()
+		cfg.Keyring.Backend = "kwallet"
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("failed to save config: %v", err)
+		}
+
+		if _, err := Open(context.Background(), Options{}); err == nil {
+			t.Error("expected error for invalid keyring backend")
+		}
+	})
+}
+
+// TestTypeAliases checks that the subpackage types are interchangeable with
+// the values the client returns.
+func TestTypeAliases(t *testing.T) {
+	msg := mail.NewMessage("", "", "me@example.com", "Hello", "Body")
+	msg.To = []mail.Address{{Email: "you@example.com"}}
+	var _ mail.ListOptions = mail.ListOptions{MaxResults: 5, LabelIDs: []string{"INBOX"}}
+
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	event := calendar.NewEvent("Standup", start, start.Add(15*time.Minute))
+	if event.Title != "Standup" {
+		t.Errorf("event title = %q", event.Title)
+	}
+	if _, err := calendar.NewFreeBusyRequest(start, start, calendar.PrimaryCalendar); !errors.Is(err, calendar.ErrInvalidTimeRange) {
+		t.Errorf("expected ErrInvalidTimeRange, got %v", err)
+	}
+
+	task, err := tasks.NewTask("Write report", tasks.DefaultTaskList)
+	if err != nil {
+		t.Fatalf("NewTask failed: %v", err)
+	}
+	if task.Status != tasks.StatusNeedsAction {
+		t.Errorf("task status = %q, want needsAction", task.Status)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	err := fmt.Errorf("failed to list messages: %w", &APIError{
+		Service: "gmail",
+		Status:  429,
+		Reason:  "rateLimitExceeded",
+		Message: "Too many requests",
+		Err:     ErrRateLimited,
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Reason != "rateLimitExceeded" {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if !errors.Is(err, ErrRateLimited) || errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("unexpected sentinel match for %v", err)
+	}
+}
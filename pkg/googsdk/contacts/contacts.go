@@ -0,0 +1,56 @@
+// Package contacts exposes goog's Google Contacts types for use with
+// googsdk.Client.
+//
+// The types are aliases of goog's domain types, so values returned by the
+// client can be used directly.
+package contacts
+
+import (
+	"github.com/stainedhead/go-goog-cli/internal/domain/contacts"
+)
+
+// Entities.
+type (
+	Contact      = contacts.Contact
+	ContactGroup = contacts.ContactGroup
+	Name         = contacts.Name
+	Email        = contacts.Email
+	Phone        = contacts.Phone
+	Address      = contacts.Address
+	Organization = contacts.Organization
+	Birthday     = contacts.Birthday
+	Date         = contacts.Date
+	URL          = contacts.URL
+)
+
+// Options and results.
+type (
+	ListOptions   = contacts.ListOptions
+	SearchOptions = contacts.SearchOptions
+	List          = contacts.ListResult[*contacts.Contact]
+)
+
+// Repositories returned by googsdk.Client.
+type (
+	ContactRepository      = contacts.ContactRepository
+	ContactGroupRepository = contacts.ContactGroupRepository
+)
+
+// Errors returned by the repositories and constructors.
+var (
+	ErrContactNotFound      = contacts.ErrContactNotFound
+	ErrContactGroupNotFound = contacts.ErrContactGroupNotFound
+	ErrInvalidContact       = contacts.ErrInvalidContact
+	ErrInvalidGroup         = contacts.ErrInvalidGroup
+	ErrCannotModifySystem   = contacts.ErrCannotModifySystem
+)
+
+// NewContact creates an empty contact.
+func NewContact() *Contact {
+	return contacts.NewContact()
+}
+
+// NewContactGroup creates a contact group.
+func NewContactGroup(name string) (*ContactGroup, error) {
+	return contacts.NewContactGroup(name)
+}
@@ -0,0 +1,63 @@
+package googsdk_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/calendar"
+	"github.com/stainedhead/go-goog-cli/pkg/googsdk/mail"
+)
+
+func ExampleOpen() {
+	ctx := context.Background()
+
+	// Use the "work" account signed in with "goog auth login --account work".
+	client, err := googsdk.Open(ctx, googsdk.Options{Account: "work"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	msgs, err := client.Messages().List(ctx, mail.ListOptions{MaxResults: 10, LabelIDs: []string{"INBOX"}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, msg := range msgs.Items {
+		fmt.Println(msg.From, msg.Subject)
+	}
+}
+
+func ExampleClient_Events() {
+	ctx := context.Background()
+
+	client, err := googsdk.Open(ctx, googsdk.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	start := time.Now().Truncate(24 * time.Hour)
+	events, err := client.Events().List(ctx, calendar.PrimaryCalendar, start, start.Add(24*time.Hour))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, event := range events {
+		fmt.Println(event.Start.Format("15:04"), event.Title)
+	}
+}
+
+func ExampleClient_Messages_send() {
+	ctx := context.Background()
+
+	client, err := googsdk.Open(ctx, googsdk.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	msg := mail.NewMessage("", "", client.Account().Email, "Build finished", "All tests passed.")
+	msg.AddRecipient("team@example.com")
+	if _, err := client.Messages().Send(ctx, msg); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,81 @@
+// Package mail exposes goog's Gmail types for use with googsdk.Client.
+//
+// The types are aliases of goog's domain types, so values returned by the
+// client can be used directly.
+package mail
+
+import (
+	"github.com/stainedhead/go-goog-cli/internal/domain/mail"
+)
+
+// Entities.
+type (
+	Message        = mail.Message
+	Address        = mail.Address
+	Draft          = mail.Draft
+	Thread         = mail.Thread
+	Label          = mail.Label
+	LabelColor     = mail.LabelColor
+	Attachment     = mail.Attachment
+	Filter         = mail.Filter
+	FilterCriteria = mail.FilterCriteria
+	FilterAction   = mail.FilterAction
+)
+
+// Options and results.
+type (
+	ListOptions   = mail.ListOptions
+	ModifyRequest = mail.ModifyRequest
+	MessageList   = mail.ListResult[*mail.Message]
+	DraftList     = mail.ListResult[*mail.Draft]
+	ThreadList    = mail.ListResult[*mail.Thread]
+)
+
+// Repositories returned by googsdk.Client.
+type (
+	MessageRepository    = mail.MessageRepository
+	DraftRepository      = mail.DraftRepository
+	ThreadRepository     = mail.ThreadRepository
+	LabelRepository      = mail.LabelRepository
+	AttachmentRepository = mail.AttachmentRepository
+)
+
+// Errors returned by the repositories.
+var (
+	ErrMessageNotFound    = mail.ErrMessageNotFound
+	ErrDraftNotFound      = mail.ErrDraftNotFound
+	ErrThreadNotFound     = mail.ErrThreadNotFound
+	ErrLabelNotFound      = mail.ErrLabelNotFound
+	ErrAttachmentNotFound = mail.ErrAttachmentNotFound
+)
+
+// Label types.
+const (
+	LabelTypeSystem = mail.LabelTypeSystem
+	LabelTypeUser   = mail.LabelTypeUser
+)
+
+// NewMessage creates a message for sending.
+func NewMessage(id, threadID, from, subject, body string) *Message {
+	return mail.NewMessage(id, threadID, from, subject, body)
+}
+
+// ParseAddress parses an address such as "Alice <alice@example.com>".
+func ParseAddress(s string) Address {
+	return mail.ParseAddress(s)
+}
+
+// ParseAddressList parses a comma-separated list of addresses.
+func ParseAddressList(s string) []Address {
+	return mail.ParseAddressList(s)
+}
+
+// NewDraft creates a draft wrapping message.
+func NewDraft(id string, message *Message) *Draft {
+	return mail.NewDraft(id, message)
+}
+
+// NewLabel creates a user label.
+func NewLabel(id, name string) *Label {
+	return mail.NewLabel(id, name)
+}
@@ -0,0 +1,56 @@
+// Package tasks exposes goog's Google Tasks types for use with
+// googsdk.Client.
+//
+// The types are aliases of goog's domain types, so values returned by the
+// client can be used directly.
+package tasks
+
+import (
+	"github.com/stainedhead/go-goog-cli/internal/domain/tasks"
+)
+
+// Entities.
+type (
+	Task     = tasks.Task
+	TaskList = tasks.TaskList
+	TaskLink = tasks.TaskLink
+)
+
+// Options and results.
+type (
+	ListOptions = tasks.ListOptions
+	List        = tasks.ListResult[*tasks.Task]
+)
+
+// Repositories returned by googsdk.Client.
+type (
+	TaskRepository     = tasks.TaskRepository
+	TaskListRepository = tasks.TaskListRepository
+)
+
+// Errors returned by the repositories and constructors.
+var (
+	ErrTaskNotFound     = tasks.ErrTaskNotFound
+	ErrTaskListNotFound = tasks.ErrTaskListNotFound
+	ErrInvalidParent    = tasks.ErrInvalidParent
+	ErrInvalidStatus    = tasks.ErrInvalidStatus
+)
+
+// Task statuses.
+const (
+	StatusNeedsAction = tasks.StatusNeedsAction
+	StatusCompleted   = tasks.StatusCompleted
+)
+
+// DefaultTaskList is the ID of the account's default task list.
+const DefaultTaskList = "@default"
+
+// NewTask creates a task in the given list.
+func NewTask(title, listID string) (*Task, error) {
+	return tasks.NewTask(title, listID)
+}
+
+// NewTaskList creates a task list.
+func NewTaskList(title string) (*TaskList, error) {
+	return tasks.NewTaskList(title)
+}